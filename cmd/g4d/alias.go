@@ -0,0 +1,66 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/nvandessel/go4dot/internal/alias"
+	"github.com/nvandessel/go4dot/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+var aliasCmd = &cobra.Command{
+	Use:   "alias",
+	Short: "Link the other name of this binary (g4d or go4dot)",
+	Long: `Create a symlink so go4dot can be run as both g4d and go4dot.
+
+Both names run the same commands. By default the link is named after the name
+this binary was not run as, and is created next to the binary. An existing
+symlink is replaced; use --force to replace a regular file, e.g. an old copy
+left behind by a previous install. 'g4d doctor' warns when either name runs a
+different version.
+
+Examples:
+  g4d alias                          # creates go4dot next to g4d
+  go4dot alias                       # creates g4d next to go4dot
+  g4d alias --dir ~/.local/bin --force`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		name, _ := cmd.Flags().GetString("name")
+		dir, _ := cmd.Flags().GetString("dir")
+		force, _ := cmd.Flags().GetBool("force")
+
+		self, err := os.Executable()
+		if err == nil {
+			self, err = filepath.EvalSymlinks(self)
+		}
+		if err != nil {
+			ui.Error("Cannot locate the running binary: %v", err)
+			os.Exit(1)
+		}
+
+		if name == "" {
+			name = alias.Counterpart(alias.Invoked(os.Args[0]))
+		}
+		if dir == "" {
+			dir = filepath.Dir(self)
+		}
+
+		path, err := alias.Create(self, dir, name, force)
+		if err != nil {
+			ui.Error("%v", err)
+			os.Exit(1)
+		}
+		ui.Success("%s -> %s", path, self)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(aliasCmd)
+
+	aliasCmd.Flags().String("name", "", "Name of the link (default: the other name of this binary)")
+	aliasCmd.Flags().String("dir", "", "Directory to create the link in (default: next to this binary)")
+	aliasCmd.Flags().Bool("force", false, "Replace an existing file that is not a symlink")
+	_ = aliasCmd.RegisterFlagCompletionFunc("name", cobra.FixedCompletions(alias.Names, cobra.ShellCompDirectiveNoFileComp))
+	_ = aliasCmd.MarkFlagDirname("dir")
+}
@@ -0,0 +1,127 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/nvandessel/go4dot/internal/badge"
+	"github.com/nvandessel/go4dot/internal/config"
+	"github.com/nvandessel/go4dot/internal/doctor"
+	"github.com/nvandessel/go4dot/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+var badgeCmd = &cobra.Command{
+	Use:   "badge [name]",
+	Short: "Generate README status badges",
+	Long: `Describe the dotfiles repo as shields.io badges: the number of configs, the
+platforms they support and the result of the last 'g4d doctor --ci' run.
+
+Without flags, prints the shields.io endpoint JSON of every badge (or of the
+named one: configs, platforms or doctor). In CI, write them to a directory you
+publish and point README badges at them:
+
+  g4d doctor --ci
+  g4d badge --out badges
+  g4d badge --markdown --url https://user.github.io/dotfiles/badges
+
+Without --url, --markdown prints static badges of the current values.`,
+	Args:      cobra.MaximumNArgs(1),
+	ValidArgs: badge.Names,
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg, _, err := config.LoadFromDiscovery()
+		if err != nil {
+			ui.Error("Error loading config: %v", err)
+			os.Exit(1)
+		}
+
+		summaryPath, _ := cmd.Flags().GetString("doctor-summary")
+		summary, err := doctor.LoadSummary(summaryPath)
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			ui.Error("%v", err)
+			os.Exit(1)
+		}
+
+		badges := badge.All(cfg, summary)
+		if len(args) > 0 {
+			badges, err = selectBadge(badges, args[0])
+			if err != nil {
+				ui.Error("%v", err)
+				os.Exit(1)
+			}
+		}
+
+		outDir, _ := cmd.Flags().GetString("out")
+		markdown, _ := cmd.Flags().GetBool("markdown")
+		baseURL, _ := cmd.Flags().GetString("url")
+
+		switch {
+		case outDir != "":
+			if err := writeBadges(badges, outDir); err != nil {
+				ui.Error("%v", err)
+				os.Exit(1)
+			}
+			ui.Success("Wrote %d badge file(s) to %s", len(badges), outDir)
+		case markdown:
+			fmt.Print(badge.Markdown(badges, baseURL))
+		case len(badges) == 1:
+			printJSON(badges[0])
+		default:
+			byName := make(map[string]badge.Badge, len(badges))
+			for _, b := range badges {
+				byName[b.Name] = b
+			}
+			printJSON(byName)
+		}
+	},
+}
+
+// selectBadge returns the badge called name
+func selectBadge(badges []badge.Badge, name string) ([]badge.Badge, error) {
+	for _, b := range badges {
+		if b.Name == name {
+			return []badge.Badge{b}, nil
+		}
+	}
+	return nil, fmt.Errorf("unknown badge '%s' (valid: %v)", name, badge.Names)
+}
+
+// writeBadges writes each badge's endpoint JSON to dir as <name>.json
+func writeBadges(badges []badge.Badge, dir string) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create %s: %w", dir, err)
+	}
+	for _, b := range badges {
+		data, err := json.MarshalIndent(b, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal badge %s: %w", b.Name, err)
+		}
+		path := filepath.Join(dir, b.Name+".json")
+		if err := os.WriteFile(path, append(data, '\n'), 0644); err != nil {
+			return fmt.Errorf("failed to write %s: %w", path, err)
+		}
+	}
+	return nil
+}
+
+func printJSON(v interface{}) {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		ui.Error("Failed to marshal JSON: %v", err)
+		os.Exit(1)
+	}
+	fmt.Println(string(data))
+}
+
+func init() {
+	rootCmd.AddCommand(badgeCmd)
+
+	badgeCmd.Flags().String("out", "", "Write each badge's endpoint JSON to <dir>/<name>.json")
+	badgeCmd.Flags().Bool("markdown", false, "Print a markdown snippet instead of JSON")
+	badgeCmd.Flags().String("url", "", "URL the --out directory is published at, for live markdown badges")
+	badgeCmd.Flags().String("doctor-summary", doctor.SummaryFileName, "Summary written by 'g4d doctor --ci'")
+}
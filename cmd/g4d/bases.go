@@ -0,0 +1,55 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/nvandessel/go4dot/internal/config"
+	"github.com/nvandessel/go4dot/internal/deps"
+	"github.com/nvandessel/go4dot/internal/ui"
+)
+
+// fetchBases clones the bases cfg extends that are not cloned yet and
+// reloads the config from configPath so they take effect. With update,
+// bases that are already cloned are pulled too.
+func fetchBases(cfg *config.Config, configPath string, update bool) (*config.Config, error) {
+	if len(cfg.Extends) == 0 || (!update && len(cfg.MissingBases) == 0) {
+		return cfg, nil
+	}
+
+	basesDir, err := config.BasesDir()
+	if err != nil {
+		return nil, err
+	}
+
+	events := ui.DetailEvents("  ")
+	result, err := deps.CloneBases(cfg, basesDir, deps.ExternalOptions{
+		RepoRoot:  filepath.Dir(configPath),
+		Update:    update,
+		EventFunc: events.Print,
+	})
+	events.Done()
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch base repos: %w", err)
+	}
+	failed := make(map[string]bool)
+	for _, f := range result.Failed {
+		failed[f.Dep.URL] = true
+		ui.Warning("Base %s is not available, its entries are ignored: %v", f.Dep.Name, f.Error)
+	}
+
+	cfg, err = config.Load(configPath)
+	if err != nil {
+		return nil, err
+	}
+	// The bases may add to the env
+	if err := applyConfigEnv(cfg, filepath.Dir(configPath)); err != nil {
+		return nil, err
+	}
+	for _, base := range cfg.MissingBases {
+		if !failed[base] {
+			ui.Warning("Base %s is not available, its entries are ignored", base)
+		}
+	}
+	return cfg, nil
+}
@@ -1,6 +1,10 @@
 package main
 
 import (
+	"slices"
+	"strings"
+
+	"github.com/nvandessel/go4dot/internal/config"
 	"github.com/spf13/cobra"
 )
 
@@ -53,6 +57,52 @@ Fish:
 	},
 }
 
+// completeNames returns a completion function offering the names listed by
+// names in the discovered config for the first n arguments, or for every
+// argument when n is 0. Names already given are left out.
+func completeNames(names func(*config.Config) []string, n int) cobra.CompletionFunc {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if n > 0 && len(args) >= n {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		cfg, _, err := config.LoadFromDiscovery()
+		if err != nil {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		var matches []string
+		for _, name := range names(cfg) {
+			if strings.HasPrefix(name, toComplete) && !slices.Contains(args, name) {
+				matches = append(matches, name)
+			}
+		}
+		return matches, cobra.ShellCompDirectiveNoFileComp
+	}
+}
+
+// archivedConfigNames lists the configs 'g4d config restore' can restore
+func archivedConfigNames(cfg *config.Config) []string {
+	var names []string
+	for _, item := range cfg.Archived {
+		names = append(names, item.Name)
+	}
+	return names
+}
+
 func init() {
 	rootCmd.AddCommand(completionCmd)
+
+	// Config names, external IDs and machine prompt IDs
+	syncCmd.ValidArgsFunction = completeNames((*config.Config).ConfigNames, 0)
+	linkCmd.ValidArgsFunction = completeNames((*config.Config).ConfigNames, 0)
+	for _, cmd := range []*cobra.Command{stowAddCmd, stowRemoveCmd, configRenameCmd, configMoveCmd, configSplitCmd, configArchiveCmd} {
+		cmd.ValidArgsFunction = completeNames((*config.Config).ConfigNames, 1)
+	}
+	configRestoreCmd.ValidArgsFunction = completeNames(archivedConfigNames, 1)
+	for _, cmd := range []*cobra.Command{externalCloneCmd, externalUpdateCmd, externalRemoveCmd} {
+		cmd.ValidArgsFunction = completeNames((*config.Config).ExternalIDs, 1)
+	}
+	for _, cmd := range []*cobra.Command{machineConfigureCmd, machineShowCmd, machineRemoveCmd, reconfigureCmd} {
+		cmd.ValidArgsFunction = completeNames((*config.Config).MachinePromptIDs, 1)
+	}
+	_ = grepCmd.RegisterFlagCompletionFunc("config", completeNames((*config.Config).ConfigNames, 0))
 }
@@ -4,16 +4,35 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strconv"
 
+	"github.com/charmbracelet/huh"
 	"github.com/nvandessel/go4dot/internal/config"
+	"github.com/nvandessel/go4dot/internal/feature"
+	"github.com/nvandessel/go4dot/internal/prefs"
+	"github.com/nvandessel/go4dot/internal/setup"
+	"github.com/nvandessel/go4dot/internal/state"
+	"github.com/nvandessel/go4dot/internal/ui"
 	"github.com/spf13/cobra"
 	"gopkg.in/yaml.v3"
 )
 
 var configCmd = &cobra.Command{
-	Use:   "config",
-	Short: "Manage configuration files",
-	Long:  "Commands for working with .go4dot.yaml configuration files",
+	Use:     "config",
+	Aliases: []string{"configs"},
+	Short:   "Manage configuration files",
+	Long:    "Commands for working with .go4dot.yaml configuration files",
+}
+
+var configListCmd = &cobra.Command{
+	Use:   "list [config-path]",
+	Short: "List configs and their status",
+	Long: `List the configs in .go4dot.yaml and their install status.
+
+Same as 'g4d list'. Use --stats to report file counts, sizes and the largest
+files of each config.`,
+	Args: cobra.MaximumNArgs(1),
+	Run:  runList,
 }
 
 var configValidateCmd = &cobra.Command{
@@ -59,20 +78,34 @@ var configValidateCmd = &cobra.Command{
 var configShowCmd = &cobra.Command{
 	Use:   "show [path]",
 	Short: "Display configuration contents",
-	Long:  "Display the full contents of a .go4dot.yaml configuration file",
-	Args:  cobra.MaximumNArgs(1),
+	Long: `Display the full contents of a .go4dot.yaml configuration file.
+
+With --effective, show the config go4dot actually uses: the repos listed
+under 'extends' merged with the file, followed by the base each inherited
+entry comes from.`,
+	Args: cobra.MaximumNArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
-		var cfg *config.Config
 		var configPath string
-		var err error
-
 		if len(args) > 0 {
 			configPath = args[0]
-			cfg, err = config.LoadFromPath(configPath)
+			if stat, err := os.Stat(configPath); err == nil && stat.IsDir() {
+				configPath = filepath.Join(configPath, config.ConfigFileName)
+			}
 		} else {
-			cfg, configPath, err = config.LoadFromDiscovery()
+			path, err := config.FindConfig()
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error loading config: %v\n", err)
+				os.Exit(1)
+			}
+			configPath = path
 		}
 
+		effective, _ := cmd.Flags().GetBool("effective")
+		load := config.LoadFile
+		if effective {
+			load = config.Load
+		}
+		cfg, err := load(configPath)
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "Error loading config: %v\n", err)
 			os.Exit(1)
@@ -89,11 +122,435 @@ var configShowCmd = &cobra.Command{
 		}
 
 		fmt.Println(string(data))
+
+		if effective {
+			printOrigins(cfg)
+		}
+	},
+}
+
+// printOrigins lists the entries of an effective config that come from a
+// base repo, and the bases that are not cloned yet
+func printOrigins(cfg *config.Config) {
+	if len(cfg.Extends) == 0 {
+		return
+	}
+
+	ui.Section("Inherited from bases")
+	if len(cfg.Origins) == 0 {
+		fmt.Println("  (nothing)")
+	}
+	for _, key := range sortedStateKeys(cfg.Origins) {
+		fmt.Printf("  %-30s %s\n", key, cfg.Origins[key])
+	}
+
+	for _, base := range cfg.MissingBases {
+		ui.Warning("Base %s is not cloned yet; run 'g4d sync' to fetch it", base)
+	}
+}
+
+var configDiffCmd = &cobra.Command{
+	Use:   "diff <rev1> [rev2]",
+	Short: "Show how .go4dot.yaml changed between revisions",
+	Long: `Compare .go4dot.yaml between two revisions of the dotfiles repo.
+
+Instead of a text diff, lists the configs, dependencies, externals and other
+entries added, removed or changed, such as an external pointing at a new
+URL or a dependency moving to another tier. Revisions are anything the
+repo's VCS accepts, e.g. HEAD~3 or a tag with git. Without rev2 the file on
+disk is compared.`,
+	Args: cobra.RangeArgs(1, 2),
+	Run: func(cmd *cobra.Command, args []string) {
+		configPath, err := config.FindConfig()
+		if err != nil {
+			ui.Error("Error loading config: %v", err)
+			os.Exit(1)
+		}
+		dotfilesPath := filepath.Dir(configPath)
+
+		to, toLabel := "", "working copy"
+		if len(args) > 1 {
+			to, toLabel = args[1], args[1]
+		}
+		oldCfg, err := config.LoadRevision(dotfilesPath, args[0])
+		if err != nil {
+			ui.Error("%v", err)
+			os.Exit(1)
+		}
+		newCfg, err := config.LoadRevision(dotfilesPath, to)
+		if err != nil {
+			ui.Error("%v", err)
+			os.Exit(1)
+		}
+
+		diff := config.Compare(oldCfg, newCfg)
+		ui.Section(fmt.Sprintf("%s: %s → %s", config.ConfigFileName, args[0], toLabel))
+		printConfigDiff(diff)
+		fmt.Println()
+		fmt.Println(diff.Summary())
+	},
+}
+
+// printConfigDiff lists changes one per line, marked + added, - removed
+// and ~ changed, with what changed indented below
+func printConfigDiff(diff config.ConfigDiff) {
+	for _, c := range diff {
+		switch c.Kind {
+		case config.ChangeAdded:
+			fmt.Printf("  %s %s %s\n", ui.SuccessStyle.Render("+"), c.Section, c.Name)
+		case config.ChangeRemoved:
+			fmt.Printf("  %s %s %s\n", ui.ErrorStyle.Render("-"), c.Section, c.Name)
+		default:
+			fmt.Printf("  %s %s %s\n", ui.WarningStyle.Render("~"), c.Section, c.Name)
+		}
+		for _, detail := range c.Details {
+			fmt.Printf("      %s\n", ui.SubtleStyle.Render(detail))
+		}
+	}
+}
+
+var configRestoreBackupCmd = &cobra.Command{
+	Use:   "restore-backup [n]",
+	Short: "Roll .go4dot.yaml back to a last known good version",
+	Long: `Replace .go4dot.yaml with one of its last known good versions.
+
+Whenever the config loads and validates, go4dot keeps a copy of it under
+~/.config/go4dot/config-backups, skipping unchanged contents; the last 10 good versions are kept. Use this
+to roll back a bad manual edit.
+
+Backups are numbered from 1, the newest, as --list shows them. Without n,
+pick one interactively, or take the newest when not interactive.`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		list, _ := cmd.Flags().GetBool("list")
+
+		configPath, err := config.FindConfig()
+		if err != nil {
+			ui.Error("Error loading config: %v", err)
+			os.Exit(1)
+		}
+		backups, err := config.ListBackups(configPath)
+		if err != nil {
+			ui.Error("Failed to list backups: %v", err)
+			os.Exit(1)
+		}
+		if len(backups) == 0 {
+			ui.Warning("No backups of %s yet", configPath)
+			return
+		}
+
+		if list {
+			ui.Section(fmt.Sprintf("Backups of %s", configPath))
+			for i, b := range backups {
+				fmt.Printf("  %d  %s\n", i+1, describeBackup(configPath, b))
+			}
+			return
+		}
+
+		var backup config.Backup
+		switch {
+		case len(args) == 1:
+			n, err := strconv.Atoi(args[0])
+			if err != nil || n < 1 || n > len(backups) {
+				ui.Error("No backup %s; there are %d (see --list)", args[0], len(backups))
+				os.Exit(1)
+			}
+			backup = backups[n-1]
+		case ui.IsInteractive():
+			var ok bool
+			if backup, ok = selectBackup(configPath, backups); !ok {
+				ui.Println("Restore cancelled.")
+				return
+			}
+		default:
+			backup = backups[0]
+		}
+
+		if ui.IsInteractive() {
+			var proceed bool
+			err := huh.NewForm(
+				huh.NewGroup(
+					huh.NewConfirm().
+						Title(fmt.Sprintf("Replace %s with the backup from %s?", config.ConfigFileName, backup.SavedAt.Local().Format("2006-01-02 15:04"))).
+						Affirmative("Yes").
+						Negative("No").
+						Value(&proceed),
+				),
+			).Run()
+			if err != nil || !proceed {
+				ui.Println("Restore cancelled.")
+				return
+			}
+		}
+
+		if err := config.RestoreBackup(configPath, backup); err != nil {
+			ui.Error("%v", err)
+			os.Exit(1)
+		}
+		ui.Success("Restored %s from the backup of %s", configPath, backup.SavedAt.Local().Format("2006-01-02 15:04"))
 	},
 }
 
+// describeBackup renders when a backup was saved and how the config on
+// disk differs from it
+func describeBackup(configPath string, b config.Backup) string {
+	when := fmt.Sprintf("%s (%s)", b.SavedAt.Local().Format("2006-01-02 15:04"), syncAge(b.SavedAt))
+
+	backupCfg, err := config.LoadFile(b.Path)
+	if err != nil {
+		return when + "  " + ui.ErrorStyle.Render("unreadable")
+	}
+	current, err := config.LoadFile(configPath)
+	if err != nil {
+		return when + "  " + ui.SubtleStyle.Render("current config does not parse")
+	}
+	diff := config.Compare(backupCfg, current)
+	if len(diff) == 0 {
+		return when + "  " + ui.SubtleStyle.Render("same as current")
+	}
+	return when + "  " + ui.SubtleStyle.Render("current has "+diff.Summary())
+}
+
+// selectBackup asks which backup to restore, newest first
+func selectBackup(configPath string, backups []config.Backup) (config.Backup, bool) {
+	options := make([]huh.Option[int], 0, len(backups))
+	for i, b := range backups {
+		options = append(options, huh.NewOption(describeBackup(configPath, b), i))
+	}
+	var picked int
+	err := huh.NewForm(
+		huh.NewGroup(
+			huh.NewSelect[int]().
+				Title("Restore which backup?").
+				Options(options...).
+				Value(&picked),
+		),
+	).Run()
+	if err != nil {
+		return config.Backup{}, false
+	}
+	return backups[picked], true
+}
+
+var configPrefsCmd = &cobra.Command{
+	Use:   "prefs",
+	Short: "Display user preferences",
+	Long: `Display the effective user-level preferences.
+
+Preferences live in ~/.config/go4dot/config.yaml and apply to every dotfiles
+repository. Flags given on the command line always take precedence.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		path, err := prefs.GetPrefsPath()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error locating preferences: %v\n", err)
+			os.Exit(1)
+		}
+
+		fmt.Printf("Preferences from: %s\n", path)
+		if _, err := os.Stat(path); os.IsNotExist(err) {
+			fmt.Println("(file not found, showing defaults)")
+		}
+		fmt.Println("---------------------------------")
+
+		data, err := yaml.Marshal(userPrefs)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error marshaling preferences: %v\n", err)
+			os.Exit(1)
+		}
+
+		fmt.Println(string(data))
+
+		fmt.Println("Features:")
+		for _, f := range feature.Flags {
+			state := "off"
+			if feature.Enabled(f.Name) {
+				state = "on"
+			}
+			fmt.Printf("  %-20s %-3s %s\n", f.Name, state, ui.SubtleStyle.Render(f.Description))
+		}
+	},
+}
+
+var configRenameCmd = &cobra.Command{
+	Use:   "rename <old> <new>",
+	Short: "Rename a config",
+	Long: `Rename a config in .go4dot.yaml and in go4dot state.
+
+If the config's directory has the same name as the config, the directory is
+renamed too and its symlinks are restowed. If any step fails, earlier steps
+are rolled back.`,
+	Args: cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		runConfigRelocate(cmd, func(cfg *config.Config, configPath string, st *state.State, opts setup.RefactorOptions) error {
+			return setup.RenameConfig(cfg, configPath, st, args[0], args[1], opts)
+		}, fmt.Sprintf("Renamed %s to %s", args[0], args[1]))
+	},
+}
+
+var configMoveCmd = &cobra.Command{
+	Use:   "move <name> <new-path>",
+	Short: "Move a config to a new directory",
+	Long: `Move a config's directory within the dotfiles repo.
+
+Updates the path in .go4dot.yaml and go4dot state and restows the config's
+symlinks so they point at the new location. If any step fails, earlier steps
+are rolled back.`,
+	Args: cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		runConfigRelocate(cmd, func(cfg *config.Config, configPath string, st *state.State, opts setup.RefactorOptions) error {
+			return setup.MoveConfig(cfg, configPath, st, args[0], args[1], opts)
+		}, fmt.Sprintf("Moved %s to %s", args[0], args[1]))
+	},
+}
+
+var configSplitCmd = &cobra.Command{
+	Use:   "split <name> <new-name>",
+	Short: "Split part of a config into a new config",
+	Long: `Move some of a config's files and directories into a new config package
+called <new-name>, for a config that has grown to cover several tools.
+
+Without --path, pick what to move from the config's files and directories.
+The new config is added to .go4dot.yaml next to the original, both are
+restowed if the original was linked, and go4dot state gets an entry for the
+new one. If any step fails, earlier steps are rolled back.
+
+Examples:
+  g4d config split shell starship
+  g4d config split shell tmux --path .tmux.conf --path .config/tmux`,
+	Args: cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		paths, _ := cmd.Flags().GetStringArray("path")
+		runConfigRelocate(cmd, func(cfg *config.Config, configPath string, st *state.State, opts setup.RefactorOptions) error {
+			if len(paths) == 0 {
+				picked, err := pickSplitPaths(cfg, filepath.Dir(configPath), args[0], args[1])
+				if err != nil {
+					return err
+				}
+				paths = picked
+			}
+			return setup.SplitConfig(cfg, configPath, st, args[0], args[1], paths, opts)
+		}, fmt.Sprintf("Split %s off %s", args[1], args[0]))
+	},
+}
+
+// pickSplitPaths asks which of a config's files and directories to move
+// into the new config
+func pickSplitPaths(cfg *config.Config, dotfilesPath, name, newName string) ([]string, error) {
+	candidates, err := setup.SplitCandidates(cfg, dotfilesPath, name)
+	if err != nil {
+		return nil, err
+	}
+	if !ui.IsInteractive() {
+		return nil, fmt.Errorf("choose what to move with --path when running non-interactively")
+	}
+
+	options := make([]huh.Option[string], len(candidates))
+	for i, c := range candidates {
+		options[i] = huh.NewOption(c, c)
+	}
+	var picked []string
+	err = huh.NewForm(
+		huh.NewGroup(
+			huh.NewMultiSelect[string]().
+				Title(fmt.Sprintf("Move what from %s into %s?", name, newName)).
+				Description("A directory takes everything in it along").
+				Options(options...).
+				Value(&picked),
+		),
+	).Run()
+	if err != nil {
+		return nil, err
+	}
+	return picked, nil
+}
+
+var configArchiveCmd = &cobra.Command{
+	Use:   "archive <name>",
+	Short: "Archive a config without deleting it",
+	Long: `Mark a config archived: true in .go4dot.yaml.
+
+Archived configs stay in the repo and the YAML but are left out of sync,
+doctor and status. The config's symlinks are removed and its state entry is
+dropped. Use 'g4d config restore' to bring it back.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		runConfigRelocate(cmd, func(cfg *config.Config, configPath string, st *state.State, opts setup.RefactorOptions) error {
+			return setup.ArchiveConfig(cfg, configPath, st, args[0], opts)
+		}, fmt.Sprintf("Archived %s", args[0]))
+	},
+}
+
+var configRestoreCmd = &cobra.Command{
+	Use:   "restore <name>",
+	Short: "Restore an archived config",
+	Long: `Remove the archived mark from a config in .go4dot.yaml.
+
+The config is linked again on the next 'g4d sync' or 'g4d install'.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		runConfigRelocate(cmd, func(cfg *config.Config, configPath string, st *state.State, opts setup.RefactorOptions) error {
+			if opts.DryRun {
+				opts.ProgressFunc(0, 0, fmt.Sprintf("Would restore %s", args[0]))
+				return nil
+			}
+			return setup.RestoreConfig(cfg, configPath, args[0])
+		}, fmt.Sprintf("Restored %s; run 'g4d sync' to link it", args[0]))
+	},
+}
+
+// runConfigRelocate loads config and state and runs a rename or move
+func runConfigRelocate(cmd *cobra.Command, fn func(*config.Config, string, *state.State, setup.RefactorOptions) error, successMsg string) {
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+
+	cfg, configPath, err := config.LoadFromDiscovery()
+	if err != nil {
+		ui.Error("Error loading config: %v", err)
+		os.Exit(1)
+	}
+
+	// Missing state is fine: there is nothing to update
+	st, _ := state.Load()
+
+	err = fn(cfg, configPath, st, setup.RefactorOptions{
+		DryRun: dryRun,
+		ProgressFunc: func(current, total int, msg string) {
+			fmt.Printf("  %s\n", msg)
+		},
+	})
+	if err != nil {
+		ui.Error("%v", err)
+		os.Exit(1)
+	}
+
+	if !dryRun {
+		ui.Success("%s", successMsg)
+	}
+}
+
 func init() {
 	rootCmd.AddCommand(configCmd)
 	configCmd.AddCommand(configValidateCmd)
 	configCmd.AddCommand(configShowCmd)
+	configCmd.AddCommand(configDiffCmd)
+	configCmd.AddCommand(configListCmd)
+	configCmd.AddCommand(configPrefsCmd)
+	configCmd.AddCommand(configRenameCmd)
+	configCmd.AddCommand(configMoveCmd)
+	configCmd.AddCommand(configSplitCmd)
+	configCmd.AddCommand(configArchiveCmd)
+	configCmd.AddCommand(configRestoreCmd)
+	configCmd.AddCommand(configRestoreBackupCmd)
+
+	configListCmd.Flags().BoolP("all", "a", false, "Show all configs including platform-specific and archived")
+	configListCmd.Flags().Bool("stats", false, "Show file counts, sizes and suspiciously large files")
+	configListCmd.Flags().Bool("no-header", false, "Print only the table rows, without titles, column headers or summary")
+	configShowCmd.Flags().Bool("effective", false, "Show the config merged with the bases it extends")
+	configRenameCmd.Flags().Bool("dry-run", false, "Show what would change without changing anything")
+	configMoveCmd.Flags().Bool("dry-run", false, "Show what would change without changing anything")
+	configSplitCmd.Flags().Bool("dry-run", false, "Show what would change without changing anything")
+	configSplitCmd.Flags().StringArray("path", nil, "File or directory of the config to move, relative to its package (repeatable)")
+	configArchiveCmd.Flags().Bool("dry-run", false, "Show what would change without changing anything")
+	configRestoreCmd.Flags().Bool("dry-run", false, "Show what would change without changing anything")
+	configRestoreBackupCmd.Flags().Bool("list", false, "List the backups instead of restoring one")
 }
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"os"
+	"strings"
+
+	"github.com/nvandessel/go4dot/internal/config"
+	"github.com/spf13/cobra"
+)
+
+// Environment variables standing in for dash's --focus and --select, for
+// launchers that can't pass flags
+const (
+	envFocus  = "GO4DOT_FOCUS"
+	envSelect = "GO4DOT_SELECT"
+)
+
+var (
+	dashFocus  string // Panel to focus on launch
+	dashSelect string // Config to put the cursor on at launch
+)
+
+var dashCmd = &cobra.Command{
+	Use:   "dash",
+	Short: "Open the dashboard at a panel or config",
+	Long: `Open the interactive dashboard, as running g4d without a command does,
+optionally focused on a panel and with the cursor on a config. Scripts,
+shell aliases and editor integrations use this to land on the relevant
+item instead of the Configs panel:
+
+  g4d dash --focus=health
+  g4d dash --select=nvim
+
+GO4DOT_FOCUS and GO4DOT_SELECT set the same for plain 'g4d' and 'g4d dash';
+the flags win over them.`,
+	Args: cobra.NoArgs,
+	Run:  runInteractive,
+}
+
+// launchTarget returns the panel and config the dashboard opens at, from
+// dash's flags or else the environment
+func launchTarget() (focus, selectName string) {
+	focus, selectName = dashFocus, dashSelect
+	if focus == "" {
+		focus = os.Getenv(envFocus)
+	}
+	if selectName == "" {
+		selectName = os.Getenv(envSelect)
+	}
+	return focus, selectName
+}
+
+// completePanels completes --focus with the dashboard's panels
+func completePanels(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	return []string{"summary", "health", "overrides", "external", "configs", "details", "output"}, cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeConfigNames completes --select with the discovered config's
+// configs
+func completeConfigNames(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	cfg, _, err := config.LoadFromDiscovery()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	var names []string
+	for _, c := range cfg.GetAllConfigs() {
+		if strings.HasPrefix(c.Name, toComplete) {
+			names = append(names, c.Name)
+		}
+	}
+	return names, cobra.ShellCompDirectiveNoFileComp
+}
+
+func init() {
+	rootCmd.AddCommand(dashCmd)
+
+	dashCmd.Flags().StringVar(&dashFocus, "focus", "", "Panel to focus: health, external, configs, ... (or set GO4DOT_FOCUS)")
+	dashCmd.Flags().StringVar(&dashSelect, "select", "", "Config to put the cursor on (or set GO4DOT_SELECT)")
+	_ = dashCmd.RegisterFlagCompletionFunc("focus", completePanels)
+	_ = dashCmd.RegisterFlagCompletionFunc("select", completeConfigNames)
+}
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/nvandessel/go4dot/internal/ui/dashboard"
+	"github.com/spf13/cobra"
+)
+
+var demoCmd = &cobra.Command{
+	Use:   "demo",
+	Short: "Open the dashboard with sample data",
+	Long: `Launch the dashboard against built-in sample dotfiles instead of a real
+repository. The sample has configs in every state (linked, partially linked,
+drifted, conflicting), failing health checks and missing externals.
+
+Nothing is read from or written to disk: operations are simulated and
+conflict resolution and machine configuration are disabled. The data is
+fixed, which makes the demo suited to screenshots, documentation and visual
+tests.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		var lastFilter, lastSelected string
+		for {
+			state := dashboard.DemoState()
+			state.FilterText = lastFilter
+			state.SelectedConfig = lastSelected
+
+			result, err := dashboard.Run(state)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+
+			// Actions handed back to the CLI would run against the real
+			// system, so anything but quitting reopens the demo
+			if result == nil || result.Action == dashboard.ActionQuit {
+				return
+			}
+			lastFilter = result.FilterText
+			lastSelected = result.SelectedConfig
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(demoCmd)
+}
@@ -1,13 +1,19 @@
 package main
 
 import (
+	"errors"
 	"fmt"
 	"io"
 	"os"
+	"path/filepath"
 
+	"github.com/charmbracelet/huh"
 	"github.com/nvandessel/go4dot/internal/config"
 	"github.com/nvandessel/go4dot/internal/deps"
 	"github.com/nvandessel/go4dot/internal/platform"
+	"github.com/nvandessel/go4dot/internal/prefs"
+	"github.com/nvandessel/go4dot/internal/ui"
+	"github.com/nvandessel/go4dot/internal/ui/cli"
 	"github.com/spf13/cobra"
 )
 
@@ -53,36 +59,18 @@ var depsCheckCmd = &cobra.Command{
 		}
 
 		// Display results
-		fmt.Println("Dependency Status")
-		fmt.Println("-----------------")
-		fmt.Printf("Package Manager: %s\n", p.PackageManager)
-		fmt.Printf("Summary: %s\n\n", result.Summary())
-
-		// Show critical deps
-		if len(result.Critical) > 0 {
-			fmt.Println("Critical Dependencies:")
-			for _, dep := range result.Critical {
-				printDepStatus(dep)
-			}
-			fmt.Println()
+		noHeader, _ := cmd.Flags().GetBool("no-header")
+		if !noHeader {
+			ui.Println("Dependency Status")
+			ui.Println("-----------------")
+			ui.Printf("Package Manager: %s\n", p.PackageManager)
+			ui.Printf("Summary: %s\n\n", result.Summary())
 		}
 
-		// Show core deps
-		if len(result.Core) > 0 {
-			fmt.Println("Core Dependencies:")
-			for _, dep := range result.Core {
-				printDepStatus(dep)
-			}
-			fmt.Println()
-		}
-
-		// Show optional deps
-		if len(result.Optional) > 0 {
-			fmt.Println("Optional Dependencies:")
-			for _, dep := range result.Optional {
-				printDepStatus(dep)
-			}
-		}
+		table := depsTable(result)
+		table.NoHeader = noHeader
+		_ = table.Render(ui.Details())
+		ui.Summary("Dependencies", "%s", result.Summary())
 
 		// Exit with error if critical deps are missing
 		if len(result.GetMissingCritical()) > 0 {
@@ -100,14 +88,23 @@ var depsInstallCmd = &cobra.Command{
 	Run: func(cmd *cobra.Command, args []string) {
 		// Load config
 		var cfg *config.Config
+		var repoRoot string
 		var err error
 
 		if len(args) > 0 {
 			cfg, err = config.LoadFromPath(args[0])
+			if err == nil {
+				repoRoot, _ = config.ResolveRepoRoot(args[0])
+			}
 		} else {
-			cfg, _, err = config.LoadFromDiscovery()
+			var configPath string
+			cfg, configPath, err = config.LoadFromDiscovery()
+			repoRoot = filepath.Dir(configPath)
 		}
 
+		if err == nil {
+			err = applyConfigEnv(cfg, repoRoot)
+		}
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "Error loading config: %v\n", err)
 			os.Exit(1)
@@ -120,18 +117,106 @@ var depsInstallCmd = &cobra.Command{
 			os.Exit(1)
 		}
 
-		if err := runDepsInstall(cfg, p, os.Stdout); err != nil {
+		if _, err := runDepsInstall(cfg, p, ui.Details()); err != nil {
 			fmt.Fprintln(os.Stderr, err)
 			os.Exit(1)
 		}
 	},
 }
 
-func runDepsInstall(cfg *config.Config, p *platform.Platform, stdout io.Writer) error {
+var depsRefreshCmd = &cobra.Command{
+	Use:   "refresh",
+	Short: "Refresh the package manager's package database",
+	Long: `Refresh the package database of this machine's package manager, such as
+pacman -Sy or apt-get update, so installs find the packages the mirrors have now.
+
+On Arch and Manjaro, 'g4d doctor' warns when pacman's databases have not been
+refreshed for a week; this is its fix. You are asked first unless the
+confirmation policy skips it.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg, configPath, err := config.LoadFromDiscovery()
+		if err == nil {
+			err = applyConfigEnv(cfg, filepath.Dir(configPath))
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error loading config: %v\n", err)
+			os.Exit(1)
+		}
+		p, err := platform.Detect()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error detecting platform: %v\n", err)
+			os.Exit(1)
+		}
+
+		if p.PackageManager == "pacman" {
+			if status := platform.CheckPacman(); status.LastSync.IsZero() {
+				ui.Info("pacman's sync databases were never refreshed")
+			} else {
+				ui.Info("pacman's sync databases were last refreshed %s", status.LastSync.Local().Format("2006-01-02 15:04"))
+			}
+		}
+
+		if ui.IsInteractive() && confirmTracker.ShouldConfirm(prefs.OpUpdate) {
+			var proceed bool
+			err := huh.NewForm(
+				huh.NewGroup(
+					huh.NewConfirm().
+						Title(fmt.Sprintf("Refresh the %s package database?", p.PackageManager)).
+						Affirmative("Yes").
+						Negative("No").
+						Value(&proceed),
+				),
+			).Run()
+			if err != nil || !proceed {
+				ui.Println("Refresh cancelled.")
+				return
+			}
+			confirmTracker.Record(prefs.OpUpdate)
+		}
+
+		err = deps.Refresh(cfg, p, deps.InstallOptions{
+			Escalation:  userPrefs.EscalationTool(),
+			AskPassword: sudoPasswordPrompt(),
+		})
+		if err != nil {
+			ui.Error("%v", err)
+			os.Exit(1)
+		}
+		ui.Success("Refreshed the %s package database", p.PackageManager)
+	},
+}
+
+// sudoPasswordPrompt returns how the CLI asks for the sudo password: masked
+// on the terminal, or nil without one so installs fail early instead
+func sudoPasswordPrompt() func(prompt string) (string, error) {
+	if !ui.IsInteractive() {
+		return nil
+	}
+	return func(prompt string) (string, error) {
+		var password string
+		err := huh.NewForm(
+			huh.NewGroup(
+				huh.NewInput().
+					Title(prompt).
+					EchoMode(huh.EchoModePassword).
+					Value(&password),
+			),
+		).Run()
+		if errors.Is(err, huh.ErrUserAborted) {
+			return "", deps.ErrPasswordCanceled
+		}
+		return password, err
+	}
+}
+
+// runDepsInstall installs missing dependencies, writing progress to stdout.
+// It returns how many dependencies are left for manual installation.
+func runDepsInstall(cfg *config.Config, p *platform.Platform, stdout io.Writer) (int, error) {
 	// Check current status
 	checkResult, err := deps.Check(cfg, p)
 	if err != nil {
-		return fmt.Errorf("error checking dependencies: %w", err)
+		return 0, fmt.Errorf("error checking dependencies: %w", err)
 	}
 
 	manualMissing := checkResult.GetManualMissing()
@@ -139,7 +224,8 @@ func runDepsInstall(cfg *config.Config, p *platform.Platform, stdout io.Writer)
 	if len(missing) == 0 {
 		if len(manualMissing) == 0 {
 			_, _ = fmt.Fprintln(stdout, "All dependencies are already installed!")
-			return nil
+			ui.Summary("Dependencies", "all installed")
+			return 0, nil
 		}
 
 		_, _ = fmt.Fprintf(stdout, "Manual (install required): %d packages\n", len(manualMissing))
@@ -147,29 +233,30 @@ func runDepsInstall(cfg *config.Config, p *platform.Platform, stdout io.Writer)
 			_, _ = fmt.Fprintf(stdout, "  - %s (install manually)\n", dep.Item.Name)
 		}
 		_, _ = fmt.Fprintln(stdout, "\nAll auto-installable dependencies are already installed.")
-		return nil
+		ui.Summary("Dependencies", "all installed, %d manual", len(manualMissing))
+		return len(manualMissing), nil
 	}
 
 	_, _ = fmt.Fprintf(stdout, "Installing %d missing dependencies...\n\n", len(missing))
 
 	// Install with progress
+	events := ui.NewEventPrinter(stdout, "")
 	opts := deps.InstallOptions{
 		OnlyMissing: true,
-		ProgressFunc: func(current, total int, msg string) {
-			if total > 0 && current > 0 {
-				_, _ = fmt.Fprintf(stdout, "[%d/%d] %s\n", current, total, msg)
-			} else {
-				_, _ = fmt.Fprintln(stdout, msg)
-			}
-		},
+		Escalation:  userPrefs.EscalationTool(),
+		AskPassword: sudoPasswordPrompt(),
+		EventFunc:   events.Print,
 	}
 
 	result, err := deps.Install(cfg, p, opts)
+	events.Done()
 	if err != nil {
-		return fmt.Errorf("error during installation: %w", err)
+		return 0, fmt.Errorf("error during installation: %w", err)
 	}
 
 	// Show results
+	ui.Summary("Dependencies", "%d installed, %d failed, %d manual",
+		len(result.Installed), len(result.Failed), len(result.ManualSkipped))
 	_, _ = fmt.Fprintln(stdout)
 	_, _ = fmt.Fprintf(stdout, "Installed: %d packages\n", len(result.Installed))
 	if len(result.ManualSkipped) > 0 {
@@ -183,44 +270,68 @@ func runDepsInstall(cfg *config.Config, p *platform.Platform, stdout io.Writer)
 		for _, fail := range result.Failed {
 			_, _ = fmt.Fprintf(stdout, "  - %s: %v\n", fail.Item.Name, fail.Error)
 		}
-		return fmt.Errorf("error during installation: %d packages failed", len(result.Failed))
+		return len(result.ManualSkipped), fmt.Errorf("error during installation: %d packages failed", len(result.Failed))
 	}
 
-	return nil
+	return len(result.ManualSkipped), nil
 }
 
-func printDepStatus(dep deps.DependencyCheck) {
-	status := "x"
-	info := "missing"
+// depsTable lists every checked dependency with its group and status
+func depsTable(result *deps.CheckResult) *cli.Table {
+	table := cli.NewTable(
+		cli.Column{Header: "NAME"},
+		cli.Column{Header: "GROUP"},
+		cli.Column{Header: "STATUS"},
+		cli.Column{Header: "DETAILS", Shrink: true},
+	)
 
+	groups := []struct {
+		name   string
+		checks []deps.DependencyCheck
+	}{
+		{"critical", result.Critical},
+		{"core", result.Core},
+		{"optional", result.Optional},
+	}
+	for _, g := range groups {
+		for _, dep := range g.checks {
+			status, details := depStatusCell(dep)
+			table.AddRow(cli.Text(dep.Item.Name), cli.Text(g.name), status, cli.Text(details))
+		}
+	}
+	return table
+}
+
+// depStatusCell returns the status cell and details for a dependency check
+func depStatusCell(dep deps.DependencyCheck) (cli.Cell, string) {
 	switch dep.Status {
 	case deps.StatusInstalled:
-		status = "+"
-		info = dep.InstalledPath
+		details := dep.InstalledPath
 		if dep.InstalledVersion != "" {
-			info = fmt.Sprintf("%s (v%s)", info, dep.InstalledVersion)
+			details = fmt.Sprintf("%s (v%s)", details, dep.InstalledVersion)
 		}
+		return cli.Styled("installed", ui.SuccessStyle), details
 	case deps.StatusVersionMismatch:
-		status = "!"
-		info = fmt.Sprintf("version mismatch: found v%s, want %s", dep.InstalledVersion, dep.RequiredVersion)
+		return cli.Styled("outdated", ui.WarningStyle),
+			fmt.Sprintf("found v%s, want %s", dep.InstalledVersion, dep.RequiredVersion)
 	case deps.StatusCheckFailed:
-		status = "?"
-		info = fmt.Sprintf("check failed: %v", dep.Error)
+		return cli.Styled("check failed", ui.WarningStyle), fmt.Sprintf("%v", dep.Error)
 	case deps.StatusManualMissing:
-		status = "m"
-		info = "missing (manual install required)"
+		return cli.Styled("missing", ui.ErrorStyle), "manual install required"
+	case deps.StatusUnsupportedArch:
+		return cli.Styled("unsupported", ui.SubtleStyle), fmt.Sprintf("not available on %s", dep.Arch)
 	}
-
-	label := dep.Item.Name
 	if dep.Item.Manual {
-		label += " [manual]"
+		return cli.Styled("missing", ui.ErrorStyle), "manual install required"
 	}
-
-	fmt.Printf("  %s %s (%s)\n", status, label, info)
+	return cli.Styled("missing", ui.ErrorStyle), ""
 }
 
 func init() {
 	rootCmd.AddCommand(depsCmd)
 	depsCmd.AddCommand(depsCheckCmd)
 	depsCmd.AddCommand(depsInstallCmd)
+	depsCmd.AddCommand(depsRefreshCmd)
+
+	depsCheckCmd.Flags().Bool("no-header", false, "Print only the table rows, without titles or column headers")
 }
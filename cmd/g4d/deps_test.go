@@ -6,6 +6,7 @@ import (
 	"testing"
 
 	"github.com/nvandessel/go4dot/internal/config"
+	"github.com/nvandessel/go4dot/internal/deps"
 	"github.com/nvandessel/go4dot/internal/platform"
 )
 
@@ -20,10 +21,13 @@ func TestRunDepsInstall_ManualOnly(t *testing.T) {
 	p := &platform.Platform{}
 
 	var stdout bytes.Buffer
-	err := runDepsInstall(cfg, p, &stdout)
+	manual, err := runDepsInstall(cfg, p, &stdout)
 	if err != nil {
 		t.Fatalf("expected no error, got %v", err)
 	}
+	if manual != 1 {
+		t.Errorf("expected 1 manual dependency, got %d", manual)
+	}
 
 	output := stdout.String()
 	if !strings.Contains(output, "Manual (install required): 1 packages") {
@@ -44,13 +48,45 @@ func TestRunDepsInstall_NoDeps(t *testing.T) {
 	p := &platform.Platform{}
 
 	var stdout bytes.Buffer
-	err := runDepsInstall(cfg, p, &stdout)
+	manual, err := runDepsInstall(cfg, p, &stdout)
 	if err != nil {
 		t.Fatalf("expected no error, got %v", err)
 	}
+	if manual != 0 {
+		t.Errorf("expected no manual dependencies, got %d", manual)
+	}
 
 	output := stdout.String()
 	if !strings.Contains(output, "All dependencies are already installed!") {
 		t.Fatalf("expected all installed message, got %q", output)
 	}
 }
+
+func TestDepsTable(t *testing.T) {
+	result := &deps.CheckResult{
+		Critical: []deps.DependencyCheck{
+			{Item: config.DependencyItem{Name: "git"}, Status: deps.StatusInstalled, InstalledPath: "/usr/bin/git", InstalledVersion: "2.43.0"},
+		},
+		Core: []deps.DependencyCheck{
+			{Item: config.DependencyItem{Name: "neovim"}, Status: deps.StatusVersionMismatch, InstalledVersion: "0.9.5", RequiredVersion: "0.10+"},
+			{Item: config.DependencyItem{Name: "fonts", Manual: true}, Status: deps.StatusManualMissing},
+		},
+		Optional: []deps.DependencyCheck{
+			{Item: config.DependencyItem{Name: "fzf"}, Status: deps.StatusMissing},
+		},
+	}
+
+	table := depsTable(result)
+	table.Color = false
+	table.Width = 0
+
+	want := `NAME    GROUP     STATUS     DETAILS
+git     critical  installed  /usr/bin/git (v2.43.0)
+neovim  core      outdated   found v0.9.5, want 0.10+
+fonts   core      missing    manual install required
+fzf     optional  missing
+`
+	if got := table.String(); got != want {
+		t.Errorf("depsTable() =\n%s\nwant\n%s", got, want)
+	}
+}
@@ -4,17 +4,29 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strconv"
+	"time"
 
+	"github.com/charmbracelet/lipgloss"
 	"github.com/nvandessel/go4dot/internal/config"
 	"github.com/nvandessel/go4dot/internal/doctor"
+	"github.com/nvandessel/go4dot/internal/stow"
 	"github.com/nvandessel/go4dot/internal/ui"
+	"github.com/nvandessel/go4dot/internal/ui/cli"
 	"github.com/spf13/cobra"
 )
 
 var doctorCmd = &cobra.Command{
 	Use:   "doctor",
 	Short: "Check health of dotfiles installation",
-	Long:  "Run health checks on your dotfiles installation and suggest fixes for issues",
+	Long: `Run health checks on your dotfiles installation and suggest fixes for issues.
+
+With --ci, progress lines are left out and a summary of the results is written
+to g4d-doctor.json (see --summary-file), for 'g4d badge' and other CI steps.
+
+Each run is recorded, and compared with the one before it. --since compares
+with an older run instead, listing the checks that changed; 'g4d doctor
+history' lists the recorded runs.`,
 	Run: func(cmd *cobra.Command, args []string) {
 		// Load config
 		var cfg *config.Config
@@ -30,16 +42,36 @@ var doctorCmd = &cobra.Command{
 				dotfilesPath = filepath.Dir(dotfilesPath)
 			}
 		}
+		if err == nil {
+			err = applyConfigEnv(cfg, dotfilesPath)
+		}
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "Error loading config: %v\n", err)
 			os.Exit(1)
 		}
 
 		verbose, _ := cmd.Flags().GetBool("verbose")
+		ci, _ := cmd.Flags().GetBool("ci")
+
+		// Find the run to compare with first, so a bad --since fails fast
+		var baseline *doctor.Summary
+		if since, _ := cmd.Flags().GetString("since"); since != "" {
+			runs, err := doctor.LoadHistory()
+			if err == nil {
+				baseline, err = doctor.FindRun(runs, since, time.Now())
+			}
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+		}
 
 		opts := doctor.CheckOptions{
 			DotfilesPath: dotfilesPath,
 			ProgressFunc: func(current, total int, msg string) {
+				if ci {
+					return
+				}
 				if total > 0 && current > 0 {
 					fmt.Printf("[%d/%d] %s\n", current, total, msg)
 				} else {
@@ -56,16 +88,184 @@ var doctorCmd = &cobra.Command{
 
 		doctor.PrintReport(result, verbose)
 
-		// Exit with error code if unhealthy
-		if !result.IsHealthy() {
+		summary := doctor.NewSummary(result)
+		previous, err := doctor.RecordRun(summary)
+		if err != nil {
+			ui.Warning("Failed to record this run: %v", err)
+		}
+		if baseline != nil {
+			printRunDiff(baseline, summary)
+		} else {
+			printTrend(previous, summary)
+		}
+
+		if ci {
+			summaryPath, _ := cmd.Flags().GetString("summary-file")
+			if err := summary.Save(summaryPath); err != nil {
+				ui.Error("%v", err)
+				os.Exit(1)
+			}
+			ui.Info("Summary written to %s", summaryPath)
+		}
+
+		if ignoreArtifacts, _ := cmd.Flags().GetBool("ignore-artifacts"); ignoreArtifacts && len(result.Artifacts) > 0 {
+			added, err := doctor.AddArtifactIgnores(cfg, dotfilesPath, result.Artifacts)
+			for _, name := range sortedStateKeys(added) {
+				ui.Success("Added %d entries to %s for %s", len(added[name]), stow.LocalIgnoreFile, name)
+			}
+			if err != nil {
+				ui.Error("Failed to update ignore list: %v", err)
+				os.Exit(1)
+			}
+			if len(added) > 0 {
+				ui.Info("Ignored files are no longer linked. Remove them from git with 'git rm -r --cached <path>'")
+			}
+		}
+
+		_, warnings, errs, _ := result.CountByStatus()
+		if code := reportExitCode(errs, warnings, result.ConflictCount(), getFailOn(cmd)); code != exitOK {
+			os.Exit(code)
+		}
+	},
+}
+
+var doctorHistoryCmd = &cobra.Command{
+	Use:   "history",
+	Short: "List recorded doctor runs",
+	Long: `List past doctor runs, newest first, with their counts and how each
+compares with the run before it.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		runs, err := doctor.LoadHistory()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 			os.Exit(1)
 		}
+		if len(runs) == 0 {
+			ui.Info("No doctor runs recorded yet. Run 'g4d doctor' to record one.")
+			return
+		}
+
+		limit, _ := cmd.Flags().GetInt("limit")
+		noHeader, _ := cmd.Flags().GetBool("no-header")
+		table := cli.NewTable(
+			cli.Column{Header: "CHECKED"},
+			cli.Column{Header: "STATUS"},
+			cli.Column{Header: "ERRORS"},
+			cli.Column{Header: "WARNINGS"},
+			cli.Column{Header: "OK"},
+			cli.Column{Header: "TREND"},
+		)
+		table.NoHeader = noHeader
+
+		shown := 0
+		for i := len(runs) - 1; i >= 0 && (limit <= 0 || shown < limit); i-- {
+			run := runs[i]
+			var previous *doctor.Summary
+			if i > 0 {
+				previous = runs[i-1]
+			}
+			table.AddRow(
+				cli.Text(formatRunTime(run.CheckedAt)),
+				cli.Styled(string(run.Status), statusStyle(run.Status)),
+				cli.Text(strconv.Itoa(run.Errors)),
+				cli.Text(strconv.Itoa(run.Warnings)),
+				cli.Text(strconv.Itoa(run.OK)),
+				trendCell(doctor.CompareRuns(previous, run)),
+			)
+			shown++
+		}
+		_ = table.Render(ui.Details())
+
+		ui.Summary("Doctor runs", "%d recorded, %d shown", len(runs), shown)
 	},
 }
 
+// printTrend says how a run compares with the previous one
+func printTrend(previous, current *doctor.Summary) {
+	switch doctor.CompareRuns(previous, current) {
+	case doctor.TrendImproved:
+		ui.Success("Improved since the last run (%s)", formatRunTime(previous.CheckedAt))
+	case doctor.TrendWorsened:
+		ui.Warning("Worsened since the last run (%s)", formatRunTime(previous.CheckedAt))
+	}
+}
+
+// printRunDiff lists the checks whose status changed since an earlier run
+func printRunDiff(from, to *doctor.Summary) {
+	changes := doctor.DiffRuns(from, to)
+	ui.Section(fmt.Sprintf("Changes since %s", formatRunTime(from.CheckedAt)))
+	if len(changes) == 0 {
+		ui.Info("No checks changed")
+		return
+	}
+	for _, c := range changes {
+		line := fmt.Sprintf("%s: %s → %s", c.Name, statusOrNone(c.From), statusOrNone(c.To))
+		if c.Message != "" {
+			line += " (" + c.Message + ")"
+		}
+		switch c.Trend() {
+		case doctor.TrendImproved:
+			ui.Success("%s", line)
+		case doctor.TrendWorsened:
+			ui.Warning("%s", line)
+		default:
+			ui.Info("%s", line)
+		}
+	}
+}
+
+// formatRunTime formats when a doctor run was recorded, in local time
+func formatRunTime(t time.Time) string {
+	return t.Local().Format("2006-01-02 15:04")
+}
+
+// statusOrNone names a check status, or "not run" for a check missing from a run
+func statusOrNone(s doctor.CheckStatus) string {
+	if s == "" {
+		return "not run"
+	}
+	return string(s)
+}
+
+// statusStyle returns the style for an overall run status
+func statusStyle(s doctor.CheckStatus) lipgloss.Style {
+	switch s {
+	case doctor.StatusError:
+		return ui.ErrorStyle
+	case doctor.StatusWarning:
+		return ui.WarningStyle
+	default:
+		return ui.SuccessStyle
+	}
+}
+
+// trendCell renders a trend for the history table
+func trendCell(t doctor.Trend) cli.Cell {
+	switch t {
+	case doctor.TrendImproved:
+		return cli.Styled("improved", ui.SuccessStyle)
+	case doctor.TrendWorsened:
+		return cli.Styled("worsened", ui.WarningStyle)
+	case doctor.TrendUnchanged:
+		return cli.Styled("unchanged", ui.SubtleStyle)
+	default:
+		return cli.Styled("-", ui.SubtleStyle)
+	}
+}
+
 func init() {
 	rootCmd.AddCommand(doctorCmd)
+	doctorCmd.AddCommand(doctorHistoryCmd)
 
 	// Flags for doctor
 	doctorCmd.Flags().BoolP("verbose", "v", false, "Show detailed output including individual items")
+	addFailOnFlag(doctorCmd)
+	doctorCmd.Flags().Bool("ci", false, "Omit progress output and write a summary of the results for CI")
+	doctorCmd.Flags().String("summary-file", doctor.SummaryFileName, "Where --ci writes the summary")
+	doctorCmd.Flags().Bool("ignore-artifacts", false, "Add suggested entries for caches and generated files to each config's .stow-local-ignore")
+	doctorCmd.Flags().String("since", "", "Compare with the last run before this time: a duration (24h, 7d) or a date (2006-01-02)")
+
+	doctorHistoryCmd.Flags().Int("limit", 20, "Show at most this many runs (0 for all)")
+	doctorHistoryCmd.Flags().Bool("no-header", false, "Omit the table header")
 }
@@ -0,0 +1,155 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/nvandessel/go4dot/internal/setup"
+	"github.com/nvandessel/go4dot/internal/stow"
+	"github.com/nvandessel/go4dot/internal/transcript"
+	"github.com/nvandessel/go4dot/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+// addDryRunFlag adds --dry-run, which makes install and sync print what
+// they would change instead of changing it
+func addDryRunFlag(cmd *cobra.Command) {
+	cmd.Flags().Bool("dry-run", false, "Show the symlinks, packages and repos that would change without changing anything")
+}
+
+// printDryRun prints the plan a dry run worked out, one section per kind
+// of change, followed by the counts
+func printDryRun(result *setup.DryRunResult) {
+	ui.Section("Dry Run")
+
+	if len(result.Stow) > 0 || len(result.StowFailed) > 0 {
+		ui.Println("\nSymlinks:")
+		config := ""
+		for _, a := range result.Stow {
+			if a.Config != config {
+				config = a.Config
+				ui.Printf("  %s\n", config)
+			}
+			ui.Printf("    %s\n", dryRunAction(a))
+		}
+		for _, f := range result.StowFailed {
+			ui.Printf("  ✗ %s would fail: %v\n", f.ConfigName, f.Error)
+		}
+	}
+
+	if len(result.Adopt) > 0 {
+		ui.Println("\nFiles to adopt into the repo:")
+		for _, c := range result.Adopt {
+			ui.Printf("  %s → %s\n", c.TargetPath, c.SourcePath)
+		}
+	}
+
+	if l := result.Links; l != nil && l.HasChanges() {
+		ui.Println("\nLinks:")
+		for _, target := range l.Linked {
+			ui.Printf("  + %s\n", target)
+		}
+		for _, target := range l.Copied {
+			ui.Printf("  ~ %s %s\n", target, ui.SubtleStyle.Render("(copy)"))
+		}
+		for _, target := range l.Removed {
+			ui.Printf("  - %s\n", target)
+		}
+		for _, f := range l.Failed {
+			ui.Printf("  ✗ %s: %v\n", f.Target, f.Error)
+		}
+	}
+
+	if len(result.Packages) > 0 || len(result.Manual) > 0 {
+		ui.Printf("\nPackages (%s):\n", result.Platform.PackageManager)
+		for _, p := range result.Packages {
+			name := p.Dep.Name
+			if p.Package != p.Dep.Name {
+				name += " " + ui.SubtleStyle.Render("("+p.Package+")")
+			}
+			if p.Upgrade() {
+				ui.Printf("  ↑ %s %s → %s\n", name, p.Installed, p.Required)
+			} else {
+				ui.Printf("  + %s\n", name)
+			}
+		}
+		for _, dep := range result.Manual {
+			ui.Printf("  ! %s %s\n", dep.Name, ui.SubtleStyle.Render("(install manually)"))
+		}
+	}
+
+	if len(result.Toolchains) > 0 {
+		ui.Println("\nToolchains:")
+		for _, tc := range result.Toolchains {
+			ui.Printf("  + %s\n", tc)
+		}
+	}
+
+	if len(result.Externals) > 0 || len(result.ExternalFailed) > 0 {
+		ui.Println("\nRepos to clone:")
+		for _, e := range result.Externals {
+			ui.Printf("  + %s %s\n", e.Dep.Name, ui.SubtleStyle.Render(e.Dep.URL+" → "+e.Path))
+		}
+		for _, f := range result.ExternalFailed {
+			ui.Printf("  ✗ %s: %v\n", f.Dep.Name, f.Error)
+		}
+	}
+
+	if len(result.MachineConfigs) > 0 {
+		ui.Println("\nMachine configs to generate:")
+		for _, s := range result.MachineConfigs {
+			ui.Printf("  + %s %s\n", s.ID, ui.SubtleStyle.Render("→ "+s.Destination))
+		}
+	}
+
+	if len(result.ShellRCFiles) > 0 {
+		ui.Println("\nShell integration:")
+		for _, path := range result.ShellRCFiles {
+			ui.Printf("  + %s\n", path)
+		}
+	}
+
+	if len(result.Hooks) > 0 {
+		ui.Println("\nHooks to run:")
+		for _, h := range result.Hooks {
+			ui.Printf("  %s\n", h)
+		}
+	}
+
+	ui.Println()
+	summary := result.Summary()
+	transcript.Output("summary", summary)
+	if ui.CurrentVerbosity() != ui.VerbosityQuiet {
+		fmt.Print(summary)
+	}
+	ui.Println(ui.SubtleStyle.Render("Dry run: nothing was changed. Run without --dry-run to apply."))
+}
+
+// dryRunAction describes what stow would do with one file, e.g.
+// "+ .config/nvim/init.lua"
+func dryRunAction(a stow.Action) string {
+	switch a.Kind {
+	case stow.ActionLink:
+		return "+ " + a.Path
+	case stow.ActionUnlink:
+		return "- " + a.Path
+	case stow.ActionWarning, stow.ActionConflict:
+		return "! " + a.Message
+	}
+	return a.String()
+}
+
+// dryRunError returns an error when some of the planned changes would
+// fail: with exitConflicts when existing files would block configs from
+// being linked, as they would in a real run
+func dryRunError(result *setup.DryRunResult) error {
+	if !result.HasErrors() {
+		return nil
+	}
+	code := exitError
+	for _, a := range result.Stow {
+		if a.Kind == stow.ActionConflict {
+			code = exitConflicts
+		}
+	}
+	return withExitCode(code, fmt.Errorf("dry run: some planned changes would fail"))
+}
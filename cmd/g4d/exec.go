@@ -0,0 +1,130 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+
+	"github.com/nvandessel/go4dot/internal/config"
+	"github.com/nvandessel/go4dot/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+var execCmd = &cobra.Command{
+	Use:   "exec <config> -- <command> [args...]",
+	Short: "Run a command in a config's directory",
+	Long: `Run a command from a config's directory in the dotfiles repo, with
+variables describing the config exported:
+
+  G4D_CONFIG_NAME  Name of the config
+  G4D_CONFIG_DIR   The config's directory in the repo (the working directory)
+  G4D_TARGET_DIR   Directory the config is linked into (your home directory)
+  G4D_REPO_ROOT    Root of the dotfiles repo
+
+This makes maintenance scripts and hooks easy to write and try out. g4d exits
+with the command's exit code.
+
+Examples:
+  g4d exec nvim -- git log --oneline -- .
+  g4d exec zsh -- sh -c 'ls -la "$G4D_TARGET_DIR"/.zsh*'
+  g4d exec tmux -- ./scripts/install-plugins.sh`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if len(args) == 0 || len(execArgv(args)) == 0 {
+			return fmt.Errorf("requires a config name and a command")
+		}
+		return nil
+	},
+	ValidArgsFunction: completeExecArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg, configPath, err := config.LoadFromDiscovery()
+		if err == nil {
+			err = applyConfigEnv(cfg, filepath.Dir(configPath))
+		}
+		if err != nil {
+			ui.Error("Error loading config: %v", err)
+			os.Exit(1)
+		}
+
+		home, err := os.UserHomeDir()
+		if err != nil {
+			ui.Error("Cannot determine home directory: %v", err)
+			os.Exit(1)
+		}
+
+		c, err := configCommand(cfg, filepath.Dir(configPath), home, args[0], execArgv(args))
+		if err != nil {
+			ui.Error("%v", err)
+			os.Exit(1)
+		}
+		c.Stdin = os.Stdin
+		c.Stdout = os.Stdout
+		c.Stderr = os.Stderr
+
+		if err := c.Run(); err != nil {
+			var exitErr *exec.ExitError
+			if errors.As(err, &exitErr) {
+				os.Exit(exitErr.ExitCode())
+			}
+			ui.Error("%v", err)
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(execCmd)
+
+	// Everything after the config name belongs to the command, so
+	// 'g4d exec nvim ls -la' works without --
+	execCmd.Flags().SetInterspersed(false)
+}
+
+// execArgv returns the command following the config name in args. Flags
+// are not parsed after the config name, so a "--" separator arrives as an
+// ordinary argument and is dropped here.
+func execArgv(args []string) []string {
+	argv := args[1:]
+	if len(argv) > 0 && argv[0] == "--" {
+		argv = argv[1:]
+	}
+	return argv
+}
+
+// configCommand builds the command for 'g4d exec': argv runs in the
+// config's directory with the config's variables added to the environment
+func configCommand(cfg *config.Config, repoRoot, targetDir, configName string, argv []string) (*exec.Cmd, error) {
+	item := cfg.GetConfigByName(configName)
+	if item == nil {
+		return nil, cfg.UnknownConfigError(configName)
+	}
+
+	dir := item.Dir(repoRoot)
+	if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+		return nil, fmt.Errorf("config directory %s does not exist", dir)
+	}
+
+	c := exec.Command(argv[0], argv[1:]...)
+	c.Dir = dir
+	c.Env = append(os.Environ(), item.Env(repoRoot, targetDir)...)
+	return c, nil
+}
+
+// completeExecArgs completes config names for the first argument and leaves
+// the command to the shell's default completion
+func completeExecArgs(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveDefault
+	}
+
+	cfg, _, err := config.LoadFromDiscovery()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	var names []string
+	for _, c := range cfg.GetAllConfigs() {
+		names = append(names, c.Name)
+	}
+	return names, cobra.ShellCompDirectiveNoFileComp
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/nvandessel/go4dot/internal/config"
+)
+
+func TestConfigCommand(t *testing.T) {
+	repo := t.TempDir()
+	home := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(repo, "nvim-config"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := &config.Config{
+		Configs: config.ConfigGroups{
+			Core: []config.ConfigItem{
+				{Name: "nvim", Path: "nvim-config"},
+				{Name: "ghost", Path: "ghost"},
+			},
+		},
+	}
+
+	tests := []struct {
+		name    string
+		config  string
+		wantErr string
+	}{
+		{name: "runs in config directory", config: "nvim"},
+		{name: "unknown config", config: "emacs", wantErr: "unknown config 'emacs'"},
+		{name: "missing directory", config: "ghost", wantErr: "does not exist"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := configCommand(cfg, repo, home, tt.config, []string{"sh", "-c", "pwd; env"})
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("configCommand() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("configCommand() error = %v", err)
+			}
+
+			out, err := c.Output()
+			if err != nil {
+				t.Fatalf("running command: %v", err)
+			}
+			got := string(out)
+			dir := filepath.Join(repo, "nvim-config")
+			for _, want := range []string{
+				dir + "\n",
+				"G4D_CONFIG_NAME=nvim\n",
+				"G4D_CONFIG_DIR=" + dir + "\n",
+				"G4D_TARGET_DIR=" + home + "\n",
+				"G4D_REPO_ROOT=" + repo + "\n",
+			} {
+				if !strings.Contains(got, want) {
+					t.Errorf("output missing %q:\n%s", want, got)
+				}
+			}
+		})
+	}
+}
+
+func TestExecArgv(t *testing.T) {
+	tests := []struct {
+		args []string
+		want []string
+	}{
+		{[]string{"nvim", "--", "ls", "-la"}, []string{"ls", "-la"}},
+		{[]string{"nvim", "ls", "--", "x"}, []string{"ls", "--", "x"}},
+		{[]string{"nvim", "--"}, []string{}},
+		{[]string{"nvim"}, []string{}},
+	}
+
+	for _, tt := range tests {
+		if got := execArgv(tt.args); strings.Join(got, " ") != strings.Join(tt.want, " ") {
+			t.Errorf("execArgv(%v) = %v, want %v", tt.args, got, tt.want)
+		}
+	}
+}
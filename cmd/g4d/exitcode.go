@@ -0,0 +1,140 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/nvandessel/go4dot/internal/stow"
+	"github.com/spf13/cobra"
+)
+
+// Exit codes are part of the CLI contract documented in docs/commands.md;
+// scripts rely on them, so never renumber them.
+const (
+	exitOK        = 0 // Everything succeeded
+	exitError     = 1 // Errors, or the command could not run
+	exitWarning   = 2 // Warnings, with --fail-on=warning
+	exitConflicts = 3 // Existing files still block configs from being linked
+	exitPartial   = 4 // Some items failed while others succeeded
+)
+
+// failOn selects the least severe outcome that makes a command exit
+// non-zero. It implements pflag.Value so bad values are rejected while
+// parsing flags.
+type failOn string
+
+const (
+	failOnError   failOn = "error"
+	failOnWarning failOn = "warning"
+)
+
+func (f *failOn) String() string { return string(*f) }
+
+func (f *failOn) Set(s string) error {
+	switch failOn(s) {
+	case failOnError, failOnWarning:
+		*f = failOn(s)
+		return nil
+	}
+	return fmt.Errorf("must be %q or %q", failOnWarning, failOnError)
+}
+
+func (f *failOn) Type() string { return "level" }
+
+// addFailOnFlag adds --fail-on to cmd
+func addFailOnFlag(cmd *cobra.Command) {
+	level := failOnError
+	cmd.Flags().Var(&level, "fail-on", "Exit non-zero on 'warning' or only on 'error'")
+	_ = cmd.RegisterFlagCompletionFunc("fail-on", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return []string{string(failOnWarning), string(failOnError)}, cobra.ShellCompDirectiveNoFileComp
+	})
+}
+
+// getFailOn returns the --fail-on level of cmd
+func getFailOn(cmd *cobra.Command) failOn {
+	if f := cmd.Flags().Lookup("fail-on"); f != nil {
+		return failOn(f.Value.String())
+	}
+	return failOnError
+}
+
+// codedError is an error that selects the exit code of the command
+type codedError struct {
+	code int
+	err  error
+}
+
+func (e *codedError) Error() string { return e.err.Error() }
+func (e *codedError) Unwrap() error { return e.err }
+
+// withExitCode attaches an exit code to err. It returns nil when err is nil.
+func withExitCode(code int, err error) error {
+	if err == nil {
+		return nil
+	}
+	return &codedError{code: code, err: err}
+}
+
+// exitCodeFor returns the exit code for the error a command finished with.
+// Errors without an attached code exit with exitError, except unresolved
+// conflicts. Warnings only fail the command with --fail-on=warning.
+func exitCodeFor(err error, level failOn) int {
+	if err == nil {
+		return exitOK
+	}
+
+	code := exitError
+	var coded *codedError
+	switch {
+	case errors.As(err, &coded):
+		code = coded.code
+	case errors.Is(err, stow.ErrUnresolvedConflicts):
+		code = exitConflicts
+	}
+
+	if code == exitWarning && level != failOnWarning {
+		return exitOK
+	}
+	return code
+}
+
+// exitSeverity ranks exit codes for mostSevere: conflicts need the most
+// specific fix, and a partial failure says more than a plain error
+var exitSeverity = map[int]int{
+	exitOK:        0,
+	exitWarning:   1,
+	exitError:     2,
+	exitPartial:   3,
+	exitConflicts: 4,
+}
+
+// mostSevere returns the error of errs with the most severe exit code:
+// conflicts, then partial failures, then errors, then warnings. Of equally
+// severe errors the first wins. It returns nil when every error is nil.
+func mostSevere(errs ...error) error {
+	var worst error
+	for _, err := range errs {
+		if err == nil {
+			continue
+		}
+		if worst == nil || exitSeverity[exitCodeFor(err, failOnWarning)] > exitSeverity[exitCodeFor(worst, failOnWarning)] {
+			worst = err
+		}
+	}
+	return worst
+}
+
+// reportExitCode returns the exit code for a health or status report with
+// the given counts. Conflicts are reported first since they need the most
+// specific fix.
+func reportExitCode(errs, warnings, conflicts int, level failOn) int {
+	switch {
+	case conflicts > 0:
+		return exitConflicts
+	case errs > 0:
+		return exitError
+	case warnings > 0 && level == failOnWarning:
+		return exitWarning
+	}
+	return exitOK
+}
@@ -0,0 +1,128 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/nvandessel/go4dot/internal/status"
+	"github.com/nvandessel/go4dot/internal/stow"
+)
+
+func TestExitCodeFor(t *testing.T) {
+	tests := []struct {
+		name  string
+		err   error
+		level failOn
+		want  int
+	}{
+		{"success", nil, failOnError, exitOK},
+		{"plain error", errors.New("boom"), failOnError, exitError},
+		{"partial failure", withExitCode(exitPartial, errors.New("1 failed")), failOnError, exitPartial},
+		{"unresolved conflicts", fmt.Errorf("link: %w", stow.ErrUnresolvedConflicts), failOnError, exitConflicts},
+		{"coded conflicts", withExitCode(exitConflicts, errors.New("blocked")), failOnError, exitConflicts},
+		{"warning ignored by default", withExitCode(exitWarning, errors.New("manual")), failOnError, exitOK},
+		{"warning with fail-on warning", withExitCode(exitWarning, errors.New("manual")), failOnWarning, exitWarning},
+		{"wrapped coded error", fmt.Errorf("sync: %w", withExitCode(exitPartial, errors.New("x"))), failOnError, exitPartial},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := exitCodeFor(tt.err, tt.level); got != tt.want {
+				t.Errorf("exitCodeFor() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWithExitCode_Nil(t *testing.T) {
+	if err := withExitCode(exitPartial, nil); err != nil {
+		t.Errorf("withExitCode(nil) = %v, want nil", err)
+	}
+}
+
+func TestMostSevere(t *testing.T) {
+	warning := withExitCode(exitWarning, errors.New("manual"))
+	plain := errors.New("boom")
+	partial := withExitCode(exitPartial, errors.New("hook failed"))
+	conflicts := fmt.Errorf("link: %w", stow.ErrUnresolvedConflicts)
+
+	tests := []struct {
+		name string
+		errs []error
+		want error
+	}{
+		{"none", []error{nil, nil}, nil},
+		{"partial over an earlier warning", []error{nil, warning, partial}, partial},
+		{"partial over an error", []error{plain, partial}, partial},
+		{"error over a warning", []error{warning, plain}, plain},
+		{"conflicts over everything", []error{partial, plain, conflicts, warning}, conflicts},
+		{"first of equals", []error{plain, errors.New("later")}, plain},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := mostSevere(tt.errs...); got != tt.want {
+				t.Errorf("mostSevere() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReportExitCode(t *testing.T) {
+	tests := []struct {
+		name                      string
+		errs, warnings, conflicts int
+		level                     failOn
+		want                      int
+	}{
+		{"healthy", 0, 0, 0, failOnWarning, exitOK},
+		{"warnings pass by default", 0, 2, 0, failOnError, exitOK},
+		{"warnings fail when strict", 0, 2, 0, failOnWarning, exitWarning},
+		{"errors", 1, 2, 0, failOnError, exitError},
+		{"conflicts take precedence", 1, 0, 3, failOnError, exitConflicts},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := reportExitCode(tt.errs, tt.warnings, tt.conflicts, tt.level); got != tt.want {
+				t.Errorf("reportExitCode() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFailOn_Set(t *testing.T) {
+	var level failOn
+	for _, v := range []string{"warning", "error"} {
+		if err := level.Set(v); err != nil {
+			t.Errorf("Set(%q) returned error: %v", v, err)
+		}
+		if level.String() != v {
+			t.Errorf("String() = %q, want %q", level.String(), v)
+		}
+	}
+	if err := level.Set("info"); err == nil {
+		t.Error("Set(\"info\") should fail")
+	}
+}
+
+func TestStatusExitCode(t *testing.T) {
+	overview := &status.Overview{
+		Configs: []status.ConfigStatus{
+			{Name: "nvim", Status: status.SyncStatusSynced},
+			{Name: "zsh", Status: status.SyncStatusDrifted, NewFiles: 1},
+		},
+	}
+	if got := statusExitCode(overview, failOnError); got != exitOK {
+		t.Errorf("drift with fail-on error = %d, want %d", got, exitOK)
+	}
+	if got := statusExitCode(overview, failOnWarning); got != exitWarning {
+		t.Errorf("drift with fail-on warning = %d, want %d", got, exitWarning)
+	}
+
+	overview.Configs[1].Conflicts = 2
+	if got := statusExitCode(overview, failOnError); got != exitConflicts {
+		t.Errorf("conflicts = %d, want %d", got, exitConflicts)
+	}
+}
@@ -4,10 +4,13 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/nvandessel/go4dot/internal/config"
 	"github.com/nvandessel/go4dot/internal/deps"
 	"github.com/nvandessel/go4dot/internal/platform"
+	"github.com/nvandessel/go4dot/internal/ui"
+	"github.com/nvandessel/go4dot/internal/ui/cli"
 	"github.com/spf13/cobra"
 )
 
@@ -40,13 +43,16 @@ var externalStatusCmd = &cobra.Command{
 			}
 		}
 
+		if err == nil {
+			err = applyConfigEnv(cfg, repoRoot)
+		}
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "Error loading config: %v\n", err)
 			os.Exit(1)
 		}
 
 		if len(cfg.External) == 0 {
-			fmt.Println("No external dependencies defined in config")
+			ui.Println("No external dependencies defined in config")
 			return
 		}
 
@@ -57,44 +63,110 @@ var externalStatusCmd = &cobra.Command{
 		}
 
 		statuses := deps.CheckExternalStatus(cfg, p, repoRoot)
+		noHeader, _ := cmd.Flags().GetBool("no-header")
 
-		fmt.Println("External Dependencies Status")
-		fmt.Println("----------------------------")
+		var github map[string]*deps.GitHubInfo
+		useGitHub := userPrefs.GitHubMetadata()
+		if cmd.Flags().Changed("github") {
+			useGitHub, _ = cmd.Flags().GetBool("github")
+		}
+		if useGitHub {
+			github, err = deps.FetchGitHubInfo(cmd.Context(), statuses)
+			if err != nil {
+				ui.Warning("GitHub metadata: %v", err)
+			}
+		}
+
+		if !noHeader {
+			ui.Println("External Dependencies Status")
+			ui.Println("----------------------------")
+		}
+
+		table := externalStatusTable(statuses, github)
+		table.NoHeader = noHeader
+		_ = table.Render(ui.Details())
 
 		var installed, missing, skipped int
 		for _, s := range statuses {
-			var statusIcon string
-			var info string
-
 			switch s.Status {
 			case "installed":
-				statusIcon = "+"
-				info = s.Path
 				installed++
 			case "missing":
-				statusIcon = "x"
-				info = "not installed"
 				missing++
 			case "skipped":
-				statusIcon = "o"
-				info = s.Reason
 				skipped++
-			case "error":
-				statusIcon = "!"
-				info = s.Reason
 			}
-
-			fmt.Printf("  %s %s (%s)\n", statusIcon, s.Dep.Name, info)
+		}
+		ui.Summary("External", "%d installed, %d missing, %d skipped", installed, missing, skipped)
+		if noHeader {
+			return
 		}
 
-		fmt.Printf("\nSummary: %d installed, %d missing, %d skipped\n", installed, missing, skipped)
+		ui.Printf("\nSummary: %d installed, %d missing, %d skipped\n", installed, missing, skipped)
 
 		if missing > 0 {
-			fmt.Println("\nRun 'g4d external clone' to install missing dependencies.")
+			ui.Println("\nRun 'g4d external clone' to install missing dependencies.")
 		}
 	},
 }
 
+// externalStatusTable lists external dependencies with their status and
+// install path (or the reason they were skipped). With GitHub metadata, an
+// UPSTREAM column shows stars, the latest release and any hints.
+func externalStatusTable(statuses []deps.ExternalStatus, github map[string]*deps.GitHubInfo) *cli.Table {
+	columns := []cli.Column{
+		{Header: "ID"},
+		{Header: "NAME"},
+		{Header: "STATUS"},
+		{Header: "DETAILS", Shrink: true},
+	}
+	if github != nil {
+		columns = append(columns, cli.Column{Header: "UPSTREAM", Shrink: true})
+	}
+	table := cli.NewTable(columns...)
+
+	for _, s := range statuses {
+		var status cli.Cell
+		details := s.Reason
+		switch s.Status {
+		case "installed":
+			status = cli.Styled(s.Status, ui.SuccessStyle)
+			details = s.Path
+			if s.Reason != "" {
+				details += " (" + s.Reason + ")"
+			}
+		case "missing":
+			status = cli.Styled(s.Status, ui.ErrorStyle)
+			details = s.Path
+		case "skipped":
+			status = cli.Styled(s.Status, ui.SubtleStyle)
+		default:
+			status = cli.Styled(s.Status, ui.WarningStyle)
+		}
+		row := []cli.Cell{cli.Text(s.Dep.ID), cli.Text(s.Dep.Name), status, cli.Text(details)}
+		if github != nil {
+			row = append(row, upstreamCell(github[s.Dep.ID], s))
+		}
+		table.AddRow(row...)
+	}
+	return table
+}
+
+// upstreamCell summarizes an external's GitHub metadata, highlighting hints
+func upstreamCell(info *deps.GitHubInfo, s deps.ExternalStatus) cli.Cell {
+	if info == nil {
+		return cli.Styled("-", ui.SubtleStyle)
+	}
+	text := "★ " + deps.FormatStars(info.Stars)
+	if info.LatestRelease != "" {
+		text += ", latest " + info.LatestRelease
+	}
+	if hints := deps.GitHubHints(info, s); len(hints) > 0 {
+		return cli.Styled(text+" ("+strings.Join(hints, "; ")+")", ui.WarningStyle)
+	}
+	return cli.Text(text)
+}
+
 var externalCloneCmd = &cobra.Command{
 	Use:   "clone [id] [config-path]",
 	Short: "Clone external dependencies",
@@ -137,13 +209,16 @@ With an ID argument, clones only that specific dependency.`,
 			}
 		}
 
+		if err == nil {
+			err = applyConfigEnv(cfg, repoRoot)
+		}
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "Error loading config: %v\n", err)
 			os.Exit(1)
 		}
 
 		if len(cfg.External) == 0 {
-			fmt.Println("No external dependencies defined in config")
+			ui.Println("No external dependencies defined in config")
 			return
 		}
 
@@ -153,50 +228,50 @@ With an ID argument, clones only that specific dependency.`,
 			os.Exit(1)
 		}
 
+		events := ui.DetailEvents("")
 		opts := deps.ExternalOptions{
-			RepoRoot: repoRoot,
-			ProgressFunc: func(current, total int, msg string) {
-				if total > 0 && current > 0 {
-					fmt.Printf("[%d/%d] %s\n", current, total, msg)
-				} else {
-					fmt.Println(msg)
-				}
-			},
+			RepoRoot:  repoRoot,
+			EventFunc: events.Print,
 		}
 
 		if specificID != "" {
 			// Clone single
-			fmt.Printf("Cloning %s...\n\n", specificID)
+			ui.Printf("Cloning %s...\n\n", specificID)
 			err = deps.CloneSingle(cfg, p, specificID, opts)
+			events.Done()
 			if err != nil {
 				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 				os.Exit(1)
 			}
-			fmt.Println("\nDone")
+			ui.Println("\nDone")
+			ui.Summary("External", "%s cloned", specificID)
 		} else {
 			// Clone all
-			fmt.Printf("Cloning %d external dependencies...\n\n", len(cfg.External))
+			ui.Printf("Cloning %d external dependencies...\n\n", len(cfg.External))
 			result, err := deps.CloneExternal(cfg, p, opts)
+			events.Done()
 			if err != nil {
 				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 				os.Exit(1)
 			}
 
 			// Show results
-			fmt.Println()
+			ui.Summary("External", "%d cloned, %d updated, %d skipped, %d failed",
+				len(result.Cloned), len(result.Updated), len(result.Skipped), len(result.Failed))
+			ui.Println()
 			if len(result.Cloned) > 0 {
-				fmt.Printf("Cloned: %d\n", len(result.Cloned))
+				ui.Printf("Cloned: %d\n", len(result.Cloned))
 			}
 			if len(result.Updated) > 0 {
-				fmt.Printf("Updated: %d\n", len(result.Updated))
+				ui.Printf("Updated: %d\n", len(result.Updated))
 			}
 			if len(result.Skipped) > 0 {
-				fmt.Printf("Skipped: %d\n", len(result.Skipped))
+				ui.Printf("Skipped: %d\n", len(result.Skipped))
 			}
 			if len(result.Failed) > 0 {
-				fmt.Printf("Failed: %d\n", len(result.Failed))
+				ui.Printf("Failed: %d\n", len(result.Failed))
 				for _, fail := range result.Failed {
-					fmt.Printf("  - %s: %v\n", fail.Dep.Name, fail.Error)
+					ui.Error("%s: %v", fail.Dep.Name, fail.Error)
 				}
 				os.Exit(1)
 			}
@@ -243,13 +318,16 @@ With an ID argument, updates only that specific dependency.`,
 			}
 		}
 
+		if err == nil {
+			err = applyConfigEnv(cfg, repoRoot)
+		}
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "Error loading config: %v\n", err)
 			os.Exit(1)
 		}
 
 		if len(cfg.External) == 0 {
-			fmt.Println("No external dependencies defined in config")
+			ui.Println("No external dependencies defined in config")
 			return
 		}
 
@@ -259,51 +337,51 @@ With an ID argument, updates only that specific dependency.`,
 			os.Exit(1)
 		}
 
+		events := ui.DetailEvents("")
 		opts := deps.ExternalOptions{
-			Update:   true,
-			RepoRoot: repoRoot,
-			ProgressFunc: func(current, total int, msg string) {
-				if total > 0 && current > 0 {
-					fmt.Printf("[%d/%d] %s\n", current, total, msg)
-				} else {
-					fmt.Println(msg)
-				}
-			},
+			Update:    true,
+			RepoRoot:  repoRoot,
+			EventFunc: events.Print,
 		}
 
 		if specificID != "" {
 			// Update single
-			fmt.Printf("Updating %s...\n\n", specificID)
+			ui.Printf("Updating %s...\n\n", specificID)
 			err = deps.CloneSingle(cfg, p, specificID, opts)
+			events.Done()
 			if err != nil {
 				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 				os.Exit(1)
 			}
-			fmt.Println("\nDone")
+			ui.Println("\nDone")
+			ui.Summary("External", "%s updated", specificID)
 		} else {
 			// Update all
-			fmt.Printf("Updating %d external dependencies...\n\n", len(cfg.External))
+			ui.Printf("Updating %d external dependencies...\n\n", len(cfg.External))
 			result, err := deps.CloneExternal(cfg, p, opts)
+			events.Done()
 			if err != nil {
 				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 				os.Exit(1)
 			}
 
 			// Show results
-			fmt.Println()
+			ui.Summary("External", "%d updated, %d cloned, %d skipped, %d failed",
+				len(result.Updated), len(result.Cloned), len(result.Skipped), len(result.Failed))
+			ui.Println()
 			if len(result.Updated) > 0 {
-				fmt.Printf("Updated: %d\n", len(result.Updated))
+				ui.Printf("Updated: %d\n", len(result.Updated))
 			}
 			if len(result.Cloned) > 0 {
-				fmt.Printf("Cloned (new): %d\n", len(result.Cloned))
+				ui.Printf("Cloned (new): %d\n", len(result.Cloned))
 			}
 			if len(result.Skipped) > 0 {
-				fmt.Printf("Skipped: %d\n", len(result.Skipped))
+				ui.Printf("Skipped: %d\n", len(result.Skipped))
 			}
 			if len(result.Failed) > 0 {
-				fmt.Printf("Failed: %d\n", len(result.Failed))
+				ui.Printf("Failed: %d\n", len(result.Failed))
 				for _, fail := range result.Failed {
-					fmt.Printf("  - %s: %v\n", fail.Dep.Name, fail.Error)
+					ui.Error("%s: %v", fail.Dep.Name, fail.Error)
 				}
 				os.Exit(1)
 			}
@@ -336,23 +414,23 @@ var externalRemoveCmd = &cobra.Command{
 			}
 		}
 
+		if err == nil {
+			err = applyConfigEnv(cfg, repoRoot)
+		}
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "Error loading config: %v\n", err)
 			os.Exit(1)
 		}
 
+		events := ui.DetailEvents("")
 		opts := deps.ExternalOptions{
-			RepoRoot: repoRoot,
-			ProgressFunc: func(current, total int, msg string) {
-				if total > 0 && current > 0 {
-					fmt.Printf("[%d/%d] %s\n", current, total, msg)
-				} else {
-					fmt.Println(msg)
-				}
-			},
+			RepoRoot:  repoRoot,
+			UseTrash:  userPrefs.TrashEnabled(),
+			EventFunc: events.Print,
 		}
 
 		err = deps.RemoveExternal(cfg, id, opts)
+		events.Done()
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 			os.Exit(1)
@@ -366,4 +444,7 @@ func init() {
 	externalCmd.AddCommand(externalCloneCmd)
 	externalCmd.AddCommand(externalUpdateCmd)
 	externalCmd.AddCommand(externalRemoveCmd)
+
+	externalStatusCmd.Flags().Bool("no-header", false, "Print only the table rows, without titles, column headers or summary")
+	externalStatusCmd.Flags().Bool("github", false, "Look up repos hosted on GitHub for stars, new releases and archived status (default from the github preference)")
 }
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/nvandessel/go4dot/internal/config"
+	"github.com/nvandessel/go4dot/internal/deps"
+)
+
+func TestExternalStatusTable(t *testing.T) {
+	statuses := []deps.ExternalStatus{
+		{Dep: config.ExternalDep{ID: "tpm", Name: "Tmux Plugin Manager"}, Status: "installed", Path: "/home/u/.tmux/plugins/tpm"},
+		{Dep: config.ExternalDep{ID: "theme", Name: "Theme"}, Status: "installed", Path: "/home/u/.themes/x", Reason: "copied"},
+		{Dep: config.ExternalDep{ID: "zinit", Name: "Zinit"}, Status: "missing", Path: "/home/u/.zinit"},
+		{Dep: config.ExternalDep{ID: "mac", Name: "Mac Only"}, Status: "skipped", Reason: "condition not met"},
+		{Dep: config.ExternalDep{ID: "bad", Name: "Bad"}, Status: "error", Reason: "invalid path"},
+	}
+
+	table := externalStatusTable(statuses, nil)
+	table.Color = false
+	table.Width = 0
+
+	want := `ID     NAME                 STATUS     DETAILS
+tpm    Tmux Plugin Manager  installed  /home/u/.tmux/plugins/tpm
+theme  Theme                installed  /home/u/.themes/x (copied)
+zinit  Zinit                missing    /home/u/.zinit
+mac    Mac Only             skipped    condition not met
+bad    Bad                  error      invalid path
+`
+	if got := table.String(); got != want {
+		t.Errorf("externalStatusTable() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestExternalStatusTable_GitHub(t *testing.T) {
+	statuses := []deps.ExternalStatus{
+		{Dep: config.ExternalDep{ID: "tpm", Name: "TPM"}, Status: "missing", Path: "/home/u/.tmux/plugins/tpm"},
+		{Dep: config.ExternalDep{ID: "old", Name: "Old"}, Status: "missing", Path: "/home/u/.old"},
+		{Dep: config.ExternalDep{ID: "self", Name: "Self"}, Status: "missing", Path: "/home/u/.self"},
+	}
+	github := map[string]*deps.GitHubInfo{
+		"tpm": {Stars: 12345, LatestRelease: "v3.1.0"},
+		"old": {Stars: 42, Archived: true},
+	}
+
+	table := externalStatusTable(statuses, github)
+	table.Color = false
+	table.Width = 0
+
+	want := `ID    NAME  STATUS   DETAILS                    UPSTREAM
+tpm   TPM   missing  /home/u/.tmux/plugins/tpm  ★ 12k, latest v3.1.0
+old   Old   missing  /home/u/.old               ★ 42 (archived upstream)
+self  Self  missing  /home/u/.self              -
+`
+	if got := table.String(); got != want {
+		t.Errorf("externalStatusTable() =\n%s\nwant\n%s", got, want)
+	}
+}
@@ -0,0 +1,120 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/nvandessel/go4dot/internal/config"
+	"github.com/nvandessel/go4dot/internal/snapshot"
+	"github.com/nvandessel/go4dot/internal/ui"
+	"github.com/nvandessel/go4dot/internal/vcs"
+	"github.com/spf13/cobra"
+)
+
+var githooksCmd = &cobra.Command{
+	Use:   "githooks",
+	Short: "Manage git hooks in the dotfiles repo",
+	Long:  "Commands for the git hooks go4dot installs in your dotfiles repo",
+}
+
+var githooksInstallCmd = &cobra.Command{
+	Use:   "install [config-path]",
+	Short: "Install a pre-commit hook that checks configs before each commit",
+	Long: `Write a pre-commit hook into the dotfiles repo that runs, before each
+commit:
+
+  g4d config validate   .go4dot.yaml must load and validate
+  g4d lint --staged     staged config files must be valid YAML and free of
+                        emails, keys, tokens and home paths
+
+A failing check stops the commit; 'git commit --no-verify' skips them once.
+The hook does nothing where g4d is not installed. core.hooksPath is
+honored. A pre-commit hook go4dot did not write is only replaced with
+--force, after it is snapshotted for 'g4d undo'.`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		force, _ := cmd.Flags().GetBool("force")
+
+		var configPath string
+		var err error
+		if len(args) > 0 {
+			configPath = args[0]
+			if stat, statErr := os.Stat(configPath); statErr == nil && stat.IsDir() {
+				configPath = filepath.Join(configPath, config.ConfigFileName)
+			}
+		} else {
+			configPath, err = config.FindConfig()
+			if err != nil {
+				ui.Error("Error loading config: %v", err)
+				os.Exit(1)
+			}
+		}
+		dotfilesPath, err := filepath.Abs(filepath.Dir(configPath))
+		if err != nil {
+			ui.Error("%v", err)
+			os.Exit(1)
+		}
+
+		root, err := vcs.Git{}.Root(dotfilesPath)
+		if err != nil {
+			ui.Error("%s is not in a git repository", dotfilesPath)
+			os.Exit(1)
+		}
+		if real, err := filepath.EvalSymlinks(dotfilesPath); err == nil {
+			dotfilesPath = real
+		}
+		rel, err := filepath.Rel(root, dotfilesPath)
+		if err != nil {
+			ui.Error("%v", err)
+			os.Exit(1)
+		}
+
+		defer snapshot.Begin("githooks")()
+		path, err := vcs.InstallGitHook(dotfilesPath, "pre-commit", preCommitScript(rel), force)
+		if errors.Is(err, vcs.ErrForeignHook) {
+			ui.Error("%v", err)
+			fmt.Println(ui.SubtleStyle.Render("Replace it with: g4d githooks install --force"))
+			os.Exit(1)
+		}
+		if err != nil {
+			ui.Error("%v", err)
+			os.Exit(1)
+		}
+
+		ui.Success("Installed pre-commit hook at %s", path)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(githooksCmd)
+	githooksCmd.AddCommand(githooksInstallCmd)
+
+	githooksInstallCmd.Flags().Bool("force", false, "Replace a pre-commit hook go4dot did not write")
+}
+
+// preCommitScript returns the body of the pre-commit hook for a dotfiles
+// directory at rel below the repo root
+func preCommitScript(rel string) string {
+	dir := `"$(git rev-parse --show-toplevel)"`
+	if rel != "." {
+		dir += "/" + shellQuote(filepath.ToSlash(rel))
+	}
+	return fmt.Sprintf(`# Checks .go4dot.yaml and the staged config files before each commit.
+# Reinstall with 'g4d githooks install'; skip once with 'git commit --no-verify'.
+if ! command -v g4d >/dev/null 2>&1; then
+	echo "go4dot: g4d not found, skipping the pre-commit checks" >&2
+	exit 0
+fi
+cd %s || exit 1
+g4d config validate . >/dev/null || exit 1
+g4d lint --staged . || exit 1
+`, dir)
+}
+
+// shellQuote quotes s for POSIX sh
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
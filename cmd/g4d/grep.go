@@ -0,0 +1,109 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/nvandessel/go4dot/internal/config"
+	"github.com/nvandessel/go4dot/internal/search"
+	"github.com/nvandessel/go4dot/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+// grepMaxLineWidth truncates long matching lines in human output
+const grepMaxLineWidth = 200
+
+var grepCmd = &cobra.Command{
+	Use:   "grep <pattern>",
+	Short: "Search the content of managed config files",
+	Long: `Search every file managed by your configs for a regular expression and
+report the config, file and line of each match.
+
+Files that stow ignores (.git, README, entries in .stow-local-ignore) are
+skipped, as are binary files. Exits with status 1 if nothing matches.
+
+Examples:
+  g4d grep EDITOR
+  g4d grep -i 'export path'
+  g4d grep -F 'set -g' --config tmux`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		ignoreCase, _ := cmd.Flags().GetBool("ignore-case")
+		literal, _ := cmd.Flags().GetBool("fixed-strings")
+		configs, _ := cmd.Flags().GetStringSlice("config")
+		jsonOutput, _ := cmd.Flags().GetBool("json")
+		configPath, _ := cmd.Flags().GetString("config-file")
+
+		var cfg *config.Config
+		var err error
+		if configPath != "" {
+			cfg, err = config.LoadFromPath(configPath)
+		} else {
+			cfg, configPath, err = config.LoadFromDiscovery()
+		}
+		if err != nil {
+			ui.Error("Error loading config: %v", err)
+			os.Exit(1)
+		}
+
+		matches, err := search.Grep(cfg, filepath.Dir(configPath), args[0], search.Options{
+			IgnoreCase: ignoreCase,
+			Literal:    literal,
+			Configs:    configs,
+		})
+		if err != nil {
+			ui.Error("%v", err)
+			os.Exit(1)
+		}
+
+		if jsonOutput {
+			if matches == nil {
+				matches = []search.Match{}
+			}
+			data, err := json.MarshalIndent(matches, "", "  ")
+			if err != nil {
+				ui.Error("%v", err)
+				os.Exit(1)
+			}
+			fmt.Println(string(data))
+		} else {
+			printGrepMatches(matches)
+		}
+
+		if len(matches) == 0 {
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(grepCmd)
+
+	grepCmd.Flags().BoolP("ignore-case", "i", false, "Case-insensitive search")
+	grepCmd.Flags().BoolP("fixed-strings", "F", false, "Treat the pattern as a literal string")
+	grepCmd.Flags().StringSliceP("config", "c", nil, "Only search these configs")
+	grepCmd.Flags().String("config-file", "", "Path to .go4dot.yaml (default: discovered)")
+	grepCmd.Flags().Bool("json", false, "Output matches as JSON")
+}
+
+func printGrepMatches(matches []search.Match) {
+	lastConfig := ""
+	for _, m := range matches {
+		if m.Config != lastConfig {
+			if lastConfig != "" {
+				fmt.Println()
+			}
+			fmt.Println(ui.TitleStyle.UnsetMarginBottom().Render(m.Config))
+			lastConfig = m.Config
+		}
+
+		text := strings.TrimSpace(m.Text)
+		if runes := []rune(text); len(runes) > grepMaxLineWidth {
+			text = string(runes[:grepMaxLineWidth]) + "…"
+		}
+		fmt.Printf("  %s %s\n", ui.SubtleStyle.Render(fmt.Sprintf("%s:%d:", m.File, m.Line)), text)
+	}
+}
@@ -4,12 +4,15 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"time"
 
 	"github.com/nvandessel/go4dot/internal/config"
 	"github.com/nvandessel/go4dot/internal/machine"
 	"github.com/nvandessel/go4dot/internal/platform"
 	"github.com/nvandessel/go4dot/internal/setup"
+	"github.com/nvandessel/go4dot/internal/state"
 	"github.com/nvandessel/go4dot/internal/stow"
+	"github.com/nvandessel/go4dot/internal/transcript"
 	"github.com/nvandessel/go4dot/internal/ui"
 	"github.com/nvandessel/go4dot/internal/ui/dashboard"
 	"github.com/spf13/cobra"
@@ -22,22 +25,32 @@ var installCmd = &cobra.Command{
 
 This command orchestrates:
 1. Dependency checking and installation
-2. Stowing dotfile configurations
-3. Cloning external dependencies (plugins, themes)
-4. Configuring machine-specific settings
+2. Language toolchains (rustup, nvm, pyenv and their versions)
+3. Stowing dotfile configurations
+4. Cloning external dependencies (plugins, themes)
+5. Configuring machine-specific settings
+
+pre_install hooks set in the config run before the first step, and one
+that fails stops the install; post_install hooks run after the last.
 
 Use flags to customize the installation:
   --auto       Non-interactive mode, use defaults
   --minimal    Only install core configs
   --skip-deps  Skip dependency installation
   --skip-external  Skip external dependency cloning
+  --skip-toolchains  Skip installing language toolchains
   --skip-machine   Skip machine-specific configuration
-  --skip-stow      Skip stowing configs`,
+  --skip-stow      Skip stowing configs
+  --profile <name> Install only what the profile selects, and keep
+                   using it on this machine
+  --dry-run        Print the symlinks, packages and repos an install
+                   would change, without changing anything`,
 	Args: cobra.MaximumNArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
 		var cfg *config.Config
 		var configPath string
 		var err error
+		profile := useProfileFlag(cmd)
 
 		if len(args) > 0 {
 			cfg, err = config.LoadFromPath(args[0])
@@ -46,9 +59,15 @@ Use flags to customize the installation:
 			cfg, configPath, err = config.LoadFromDiscovery()
 		}
 
+		if err == nil {
+			err = applyConfigEnv(cfg, filepath.Dir(configPath))
+		}
+		if err == nil {
+			cfg, err = fetchBases(cfg, configPath, false)
+		}
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "Error loading config: %v\n", err)
-			os.Exit(1)
+			exitLogged(exitError, err)
 		}
 
 		dotfilesPath := filepath.Dir(configPath)
@@ -58,33 +77,81 @@ Use flags to customize the installation:
 		minimal, _ := cmd.Flags().GetBool("minimal")
 		skipDeps, _ := cmd.Flags().GetBool("skip-deps")
 		skipExternal, _ := cmd.Flags().GetBool("skip-external")
+		skipToolchains, _ := cmd.Flags().GetBool("skip-toolchains")
 		skipMachine, _ := cmd.Flags().GetBool("skip-machine")
 		skipStow, _ := cmd.Flags().GetBool("skip-stow")
 		overwrite, _ := cmd.Flags().GetBool("overwrite")
+		adopt, _ := cmd.Flags().GetBool("adopt")
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+
+		if dryRun {
+			result, err := setup.InstallDryRun(cfg, dotfilesPath, setup.InstallOptions{
+				Minimal:        minimal,
+				SkipDeps:       skipDeps,
+				SkipExternal:   skipExternal,
+				SkipToolchains: skipToolchains,
+				SkipMachine:    skipMachine,
+				SkipStow:       skipStow,
+				Adopt:          adopt,
+			})
+			if err != nil {
+				ui.Error("%v", err)
+				exitLogged(exitError, err)
+			}
+			printDryRun(result)
+			if err := dryRunError(result); err != nil {
+				ui.Error("%v", err)
+				exitLogged(exitCodeFor(err, failOnError), err)
+			}
+			return
+		}
+
+		if profile != "" {
+			if err := saveProfile(profile); err != nil {
+				ui.Error("%v", err)
+				exitLogged(exitError, err)
+			}
+		}
 
-		// Use unified dashboard UI for interactive mode
-		if ui.IsInteractive() && !auto {
+		// Use unified dashboard UI for interactive mode. --quiet and --summary
+		// ask for line output, so they use the stdout flow below.
+		if ui.IsInteractive() && !auto && ui.CurrentVerbosity() == ui.VerbosityNormal {
+			// Ask per file before the dashboard takes over the screen
+			if adopt && !skipStow {
+				if !promptAdoptForInstall(cfg, dotfilesPath, minimal) {
+					fmt.Println("Installation cancelled.")
+					return
+				}
+			}
 			runInstallDashboard(cfg, dotfilesPath, dashboard.InstallOptions{
-				Auto:         auto,
-				Minimal:      minimal,
-				SkipDeps:     skipDeps,
-				SkipExternal: skipExternal,
-				SkipMachine:  skipMachine,
-				SkipStow:     skipStow,
-				Overwrite:    overwrite,
+				Auto:           auto,
+				Minimal:        minimal,
+				SkipDeps:       skipDeps,
+				SkipExternal:   skipExternal,
+				SkipToolchains: skipToolchains,
+				SkipMachine:    skipMachine,
+				SkipStow:       skipStow,
+				Overwrite:      overwrite,
+				Adopt:          adopt,
+				Escalation:     userPrefs.EscalationTool(),
 			})
 			return
 		}
 
 		// Non-interactive mode: use legacy stdout-based flow
 		opts := setup.InstallOptions{
-			Auto:         auto,
-			Minimal:      minimal,
-			SkipDeps:     skipDeps,
-			SkipExternal: skipExternal,
-			SkipMachine:  skipMachine,
-			SkipStow:     skipStow,
-			Overwrite:    overwrite,
+			Auto:           auto,
+			Minimal:        minimal,
+			SkipDeps:       skipDeps,
+			SkipExternal:   skipExternal,
+			SkipToolchains: skipToolchains,
+			SkipMachine:    skipMachine,
+			SkipStow:       skipStow,
+			Overwrite:      overwrite,
+			Adopt:          adopt,
+			NoTerminal:     !ui.IsInteractive(),
+			Escalation:     userPrefs.EscalationTool(),
+			AskPassword:    sudoPasswordPrompt(),
 			ProgressFunc: func(current, total int, msg string) {
 				// Simple heuristic to style the output from setup package
 				if len(msg) > 0 && msg[0] == '\n' {
@@ -116,20 +183,20 @@ Use flags to customize the installation:
 						return
 					case "⊘ ":
 						// Skip symbol, print as info/subtle
-						fmt.Printf("  %s%s\n", counterPrefix, msg)
+						ui.Printf("  %s%s\n", counterPrefix, msg)
 						return
 					case "⬇ ", "↻ ":
 						// Download/update in progress
-						fmt.Printf("  %s%s\n", counterPrefix, msg)
+						ui.Printf("  %s%s\n", counterPrefix, msg)
 						return
 					}
 				}
 
 				// Default - include counter if present
 				if counterPrefix != "" {
-					fmt.Printf("%s%s\n", counterPrefix, msg)
+					ui.Printf("%s%s\n", counterPrefix, msg)
 				} else {
-					fmt.Println(msg)
+					ui.Println(msg)
 				}
 			},
 		}
@@ -138,23 +205,25 @@ Use flags to customize the installation:
 		ui.PrintBanner(Version)
 		ui.Section("Installation")
 
-		fmt.Printf("Dotfiles: %s\n", dotfilesPath)
+		ui.Printf("Dotfiles: %s\n", dotfilesPath)
 		if cfg.Metadata.Name != "" {
-			fmt.Printf("Config:   %s\n", cfg.Metadata.Name)
+			ui.Printf("Config:   %s\n", cfg.Metadata.Name)
 		}
 
+		started := time.Now()
 		result, err := setup.Install(cfg, dotfilesPath, opts)
 		if err != nil {
 			ui.Error("%s", err.Error())
-			os.Exit(1)
+			exitLogged(exitError, err)
 		}
+		recordInstall(result, started)
 
 		// Print summary
 		ui.Section("Summary")
 		if result.HasErrors() {
 			ui.Error("Installation completed with errors")
-			fmt.Println()
-			fmt.Print(result.Summary())
+			ui.Println()
+			printInstallSummary(result)
 
 			// Show specific errors
 			for _, e := range result.DepsFailed {
@@ -166,14 +235,17 @@ Use flags to customize the installation:
 			for _, e := range result.ExternalFailed {
 				ui.Error("External %s: %v", e.Dep.Name, e.Error)
 			}
+			for _, e := range result.HooksFailed {
+				ui.Error("Hook %s: %v", e.Hook, e.Err)
+			}
 			for _, e := range result.Errors {
 				ui.Error("%v", e)
 			}
-			os.Exit(1)
+			exitLogged(exitError, fmt.Errorf("installation completed with errors"))
 		} else {
 			ui.Success("Installation complete!")
-			fmt.Println()
-			fmt.Print(result.Summary())
+			ui.Println()
+			printInstallSummary(result)
 
 			// Save state
 			if err := setup.SaveState(cfg, dotfilesPath, result); err != nil {
@@ -183,12 +255,38 @@ Use flags to customize the installation:
 			// Show post-install message if present
 			if cfg.PostInstall != "" {
 				ui.Section("Next Steps")
-				fmt.Println(cfg.PostInstall)
+				ui.Println(cfg.PostInstall)
 			}
 		}
 	},
 }
 
+// recordInstall adds the install and its step timings to the operation
+// history
+func recordInstall(result *setup.InstallResult, started time.Time) {
+	err := state.RecordOperation(state.OperationRecord{
+		Operation: "install",
+		StartedAt: started,
+		Duration:  time.Since(started),
+		Success:   !result.HasErrors(),
+		Steps:     result.Timings,
+	})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to record operation history: %v\n", err)
+	}
+}
+
+// printInstallSummary prints the per-category result lines, which are also
+// the whole output with --summary
+func printInstallSummary(result *setup.InstallResult) {
+	summary := result.Summary()
+	transcript.Output("summary", summary)
+	if ui.CurrentVerbosity() == ui.VerbosityQuiet {
+		return
+	}
+	fmt.Print(summary)
+}
+
 // runInstallDashboard runs the install process within the unified dashboard UI
 func runInstallDashboard(cfg *config.Config, dotfilesPath string, opts dashboard.InstallOptions) {
 	p, _ := platform.Detect()
@@ -223,10 +321,31 @@ func runInstallDashboard(cfg *config.Config, dotfilesPath string, opts dashboard
 
 	if err != nil {
 		ui.Error("Installation failed: %v", err)
-		os.Exit(1)
+		exitLogged(exitError, err)
 	}
 }
 
+// promptAdoptForInstall asks per file whether to adopt existing files that
+// would block the configs being installed. Returns false if cancelled.
+func promptAdoptForInstall(cfg *config.Config, dotfilesPath string, minimal bool) bool {
+	conflicts, err := stow.DetectConflicts(cfg, dotfilesPath)
+	if err != nil {
+		ui.Warning("Failed to check conflicts: %v", err)
+		return true
+	}
+
+	configs := cfg.GetAllConfigs()
+	if minimal {
+		configs = cfg.Configs.Core
+	}
+	conflicts = stow.ConflictsForConfigs(conflicts, configs)
+	if len(conflicts) == 0 {
+		return true
+	}
+
+	return stow.PromptAdoptConflicts(conflicts, dotfilesPath)
+}
+
 func init() {
 	rootCmd.AddCommand(installCmd)
 
@@ -235,7 +354,12 @@ func init() {
 	installCmd.Flags().Bool("minimal", false, "Only install core configs, skip optional")
 	installCmd.Flags().Bool("skip-deps", false, "Skip dependency installation")
 	installCmd.Flags().Bool("skip-external", false, "Skip external dependency cloning")
+	installCmd.Flags().Bool("skip-toolchains", false, "Skip installing language toolchains")
 	installCmd.Flags().Bool("skip-machine", false, "Skip machine-specific configuration")
 	installCmd.Flags().Bool("skip-stow", false, "Skip stowing configs")
 	installCmd.Flags().Bool("overwrite", false, "Overwrite existing files")
+	installCmd.Flags().Bool("adopt", false, "Adopt existing files in home into the repo (like stow --adopt)")
+	addDryRunFlag(installCmd)
+	addProfileFlag(installCmd)
+	addLogFileFlag(installCmd)
 }
@@ -11,7 +11,6 @@ import (
 	"github.com/nvandessel/go4dot/internal/config"
 	"github.com/nvandessel/go4dot/internal/machine"
 	"github.com/nvandessel/go4dot/internal/platform"
-	"github.com/nvandessel/go4dot/internal/setup"
 	"github.com/nvandessel/go4dot/internal/state"
 	"github.com/nvandessel/go4dot/internal/stow"
 	"github.com/nvandessel/go4dot/internal/ui"
@@ -63,6 +62,17 @@ func runInteractive(cmd *cobra.Command, args []string) {
 	// Detect platform once
 	p, _ := platform.Detect()
 
+	offerConfigRollback()
+
+	// Where the first dashboard run opens (see g4d dash)
+	focus, selectName := launchTarget()
+	if focus != "" {
+		if _, err := dashboard.ParsePanel(focus); err != nil {
+			ui.Error("%v", err)
+			os.Exit(exitError)
+		}
+	}
+
 	// State preservation across dashboard runs
 	lastFilter := ""
 	lastSelected := ""
@@ -78,56 +88,23 @@ func runInteractive(cmd *cobra.Command, args []string) {
 		default:
 		}
 
-		// Try to load config to determine context
-		cfg, configPath, err := config.LoadFromDiscovery()
-		hasConfig := err == nil && cfg != nil
-
-		// Build dashboard state - works for both config and no-config cases
-		var dotfilesPath string
-		var driftSummary *stow.DriftSummary
-		var linkStatus map[string]*stow.ConfigLinkStatus
-		var dashStatus []dashboard.MachineStatus
-		var allConfigs []config.ConfigItem
-		hasBaseline := false
-
-		if hasConfig {
-			dotfilesPath = filepath.Dir(configPath)
-			st, _ := state.Load()
-			if st == nil {
-				st = state.New()
-			}
-
-			driftSummary, _ = stow.FullDriftCheck(cfg, dotfilesPath)
-			hasBaseline = len(st.SymlinkCounts) > 0
-			linkStatus, _ = stow.GetAllConfigLinkStatus(cfg, dotfilesPath)
-
-			machineStatus := machine.CheckMachineConfigStatus(cfg)
-			for _, s := range machineStatus {
-				dashStatus = append(dashStatus, dashboard.MachineStatus{
-					ID:          s.ID,
-					Description: s.Description,
-					Status:      s.Status,
-				})
+		cfg, configPath, dashState := loadDashboardState(p)
+		if dashState.HasConfig {
+			if err := applyConfigEnv(cfg, filepath.Dir(configPath)); err != nil {
+				ui.Warning("%v", err)
 			}
-
-			allConfigs = cfg.GetAllConfigs()
 		}
-
-		// Always use the dashboard - it handles no-config case with viewNoConfig
-		dashState := dashboard.State{
-			Platform:       p,
-			DriftSummary:   driftSummary,
-			LinkStatus:     linkStatus,
-			MachineStatus:  dashStatus,
-			Configs:        allConfigs,
-			Config:         cfg,
-			DotfilesPath:   dotfilesPath,
-			UpdateMsg:      updateMsg,
-			HasBaseline:    hasBaseline,
-			HasConfig:      hasConfig,
-			FilterText:     lastFilter,
-			SelectedConfig: lastSelected,
+		dashState.UpdateMsg = updateMsg
+		dashState.ShowTutorial = dashState.HasConfig && !userPrefs.TutorialSeen
+		dashState.FilterText = lastFilter
+		dashState.SelectedConfig = lastSelected
+		dashState.ConfirmTracker = confirmTracker
+		if selectName != "" && dashState.HasConfig && cfg.GetConfigByName(selectName) == nil {
+			ui.Error("%v", cfg.UnknownConfigError(selectName))
+			os.Exit(exitError)
 		}
+		dashState.Focus, dashState.CursorConfig = focus, selectName
+		focus, selectName = "", ""
 		result, err := dashboard.Run(dashState)
 
 		if err != nil {
@@ -152,6 +129,94 @@ func runInteractive(cmd *cobra.Command, args []string) {
 	}
 }
 
+// offerConfigRollback offers to restore the last good .go4dot.yaml when the
+// config on disk no longer loads, rather than opening the dashboard as if
+// there were no config
+func offerConfigRollback() {
+	_, configPath, err := config.LoadFromDiscovery()
+	if err == nil || config.IsNotFound(err) {
+		return
+	}
+	backups, _ := config.ListBackups(configPath)
+	if len(backups) == 0 {
+		return
+	}
+
+	ui.Error("Error loading config: %v", err)
+	var restore bool
+	if err := huh.NewForm(
+		huh.NewGroup(
+			huh.NewConfirm().
+				Title(fmt.Sprintf("Restore the last good %s?", config.ConfigFileName)).
+				Description(describeBackup(configPath, backups[0])).
+				Affirmative("Restore").
+				Negative("Continue without").
+				Value(&restore),
+		),
+	).Run(); err != nil || !restore {
+		return
+	}
+
+	if err := config.RestoreBackup(configPath, backups[0]); err != nil {
+		ui.Error("%v", err)
+		return
+	}
+	ui.Success("Restored %s; 'g4d config restore-backup' lists older versions", configPath)
+}
+
+// loadDashboardState discovers the config and gathers the link, drift and
+// machine status the dashboard shows. Without a config the state leads to
+// the dashboard's no-config view.
+func loadDashboardState(p *platform.Platform) (*config.Config, string, dashboard.State) {
+	cfg, configPath, err := config.LoadFromDiscovery()
+	hasConfig := err == nil && cfg != nil
+
+	// Build dashboard state - works for both config and no-config cases
+	var dotfilesPath string
+	var driftSummary *stow.DriftSummary
+	var linkStatus map[string]*stow.ConfigLinkStatus
+	var dashStatus []dashboard.MachineStatus
+	var allConfigs []config.ConfigItem
+	hasBaseline := false
+
+	if hasConfig {
+		dotfilesPath = filepath.Dir(configPath)
+		st, _ := state.Load()
+		if st == nil {
+			st = state.New()
+		}
+
+		driftSummary, _ = stow.FullDriftCheck(cfg, dotfilesPath)
+		hasBaseline = len(st.SymlinkCounts) > 0
+		linkStatus, _ = stow.GetAllConfigLinkStatus(cfg, dotfilesPath)
+
+		machineStatus := machine.CheckMachineConfigStatus(cfg)
+		for _, s := range machineStatus {
+			dashStatus = append(dashStatus, dashboard.MachineStatus{
+				ID:          s.ID,
+				Description: s.Description,
+				Status:      s.Status,
+			})
+		}
+
+		allConfigs = cfg.GetAllConfigs()
+	}
+
+	return cfg, configPath, dashboard.State{
+		Platform:      p,
+		DriftSummary:  driftSummary,
+		LinkStatus:    linkStatus,
+		MachineStatus: dashStatus,
+		Configs:       allConfigs,
+		Config:        cfg,
+		DotfilesPath:  dotfilesPath,
+		HasBaseline:   hasBaseline,
+		HasConfig:     hasConfig,
+		Preferences:   userPrefs,
+		ReadOnly:      readOnly,
+	}
+}
+
 // handleAction processes the user's action and returns true if we should exit
 func handleAction(result *dashboard.Result, cfg *config.Config, configPath string) bool {
 	switch result.Action {
@@ -174,6 +239,17 @@ func handleAction(result *dashboard.Result, cfg *config.Config, configPath strin
 		machine.RunInteractiveConfig(cfg)
 		waitForEnter()
 
+	case dashboard.ActionUninstall:
+		// Confirmed in the dashboard. Like 'g4d uninstall' without flags,
+		// only symlinks and state are removed.
+		st, _ := state.Load()
+		if err := uninstallDotfiles(cfg, filepath.Dir(configPath), st, false, false); err != nil {
+			ui.Error("%v", err)
+		} else {
+			ui.Success("Uninstall complete")
+		}
+		waitForEnter()
+
 	// ActionInstall and ActionUpdate are now handled inline in dashboard
 	// and no longer trigger handleAction
 
@@ -209,7 +285,7 @@ func runMoreMenu(cfg *config.Config, configPath string) {
 	case "list":
 		st, _ := state.Load()
 		p, _ := platform.Detect()
-		ui.PrintConfigList(cfg, st, p, true)
+		ui.PrintConfigList(cfg, st, p, true, false)
 		waitForEnter()
 
 	case "external":
@@ -229,20 +305,8 @@ func runMoreMenu(cfg *config.Config, configPath string) {
 		}
 
 		if confirm {
-			dotfilesPath := filepath.Dir(configPath)
 			st, _ := state.Load()
-			opts := setup.UninstallOptions{
-				RemoveExternal: true,
-				RemoveMachine:  true,
-				ProgressFunc: func(current, total int, msg string) {
-					if total > 0 && current > 0 {
-						fmt.Printf("  [%d/%d] %s\n", current, total, msg)
-					} else {
-						fmt.Println("  " + msg)
-					}
-				},
-			}
-			if err := setup.Uninstall(cfg, dotfilesPath, st, opts); err != nil {
+			if err := uninstallDotfiles(cfg, filepath.Dir(configPath), st, true, true); err != nil {
 				ui.Error("%v", err)
 			} else {
 				ui.Success("Uninstall complete")
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"github.com/spf13/cobra"
+)
+
+var linkCmd = &cobra.Command{
+	Use:   "link [config-name...]",
+	Short: "Create or refresh symlinks for dotfiles configs",
+	Long: `Restow dotfiles configs, creating symlinks for files that have been
+added to them. Only symlinks are touched: dependencies and external
+dependencies are left alone, which makes link much faster than 'g4d sync'.
+
+Without arguments, links all configs. With config names, links only those
+configs; one that fails to link doesn't stop the others.
+
+Examples:
+  g4d link           # Link all configs
+  g4d link nvim      # Link only the nvim config
+  g4d link nvim tmux # Link the nvim and tmux configs
+  g4d link --adopt   # Move existing files in home into the repo, then link them
+  g4d link --watch   # Link, then keep relinking configs as files are added or removed
+  g4d link --dry-run # Print the symlinks a link would create or remove
+
+Linking all configs only restows those whose files were added, removed or
+renamed since the last link or sync, or whose links are missing or wrong.
+Use --restow-all to restow every config.`,
+	Args: cobra.ArbitraryArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		opts := syncOptions{}
+		opts.adopt, _ = cmd.Flags().GetBool("adopt")
+		opts.restowAll, _ = cmd.Flags().GetBool("restow-all")
+		opts.jobs = readJobsFlag(cmd)
+		opts.strictGit, _ = cmd.Flags().GetBool("strict-git")
+		opts.dryRun, _ = cmd.Flags().GetBool("dry-run")
+		readWatchFlags(cmd, &opts)
+		opts.failOn = getFailOn(cmd)
+		runSyncWithOptions(args, opts)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(linkCmd)
+
+	linkCmd.Flags().Bool("adopt", false, "Adopt existing files in home into the repo (like stow --adopt)")
+	addRestowAllFlag(linkCmd)
+	addJobsFlag(linkCmd)
+	addStrictGitFlag(linkCmd)
+	addWatchFlags(linkCmd)
+	addFailOnFlag(linkCmd)
+	addDryRunFlag(linkCmd)
+}
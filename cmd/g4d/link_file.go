@@ -0,0 +1,109 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/nvandessel/go4dot/internal/config"
+	"github.com/nvandessel/go4dot/internal/stow"
+	"github.com/nvandessel/go4dot/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+var linkFileCmd = &cobra.Command{
+	Use:   "link-file <config-name> <file>",
+	Short: "Create or repair the symlink for a single file",
+	Long: `Link exactly one file of a config, for when a single link was deleted or
+broken and relinking the whole config is more than needed.
+
+The file is given by its path inside the config directory, or by where it is
+linked, e.g. ~/.config/nvim/init.lua. Missing parent directories are created,
+and a parent directory that stow folded into a link to another config is
+unfolded so both configs stay linked. A stale link into the dotfiles repo is
+replaced; any other file in the way is left alone.
+
+Examples:
+  g4d link-file nvim .config/nvim/init.lua
+  g4d link-file nvim ~/.config/nvim/init.lua
+  g4d link-file zsh .zshrc --dry-run`,
+	Args:              cobra.ExactArgs(2),
+	ValidArgsFunction: completeLinkFileArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+
+		cfg, configPath, err := config.LoadFromDiscovery()
+		if err != nil {
+			ui.Error("Failed to load config: %v", err)
+			os.Exit(exitError)
+		}
+		item := cfg.GetConfigByName(args[0])
+		if item == nil {
+			ui.Error("%v", cfg.UnknownConfigError(args[0]))
+			os.Exit(exitError)
+		}
+
+		actions, err := stow.LinkFile(filepath.Dir(configPath), *item, args[1], stow.StowOptions{
+			DryRun:   dryRun,
+			Settings: cfg.Stow,
+		})
+		for _, a := range actions {
+			ui.Printf("  %s\n", a)
+		}
+		if err != nil {
+			ui.Error("%v", err)
+			os.Exit(exitError)
+		}
+
+		switch {
+		case len(actions) == 0:
+			ui.Success("%s is already linked", args[1])
+		case dryRun:
+			ui.Info("Dry run: nothing was changed")
+		default:
+			ui.Success("Linked %s", args[1])
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(linkFileCmd)
+
+	linkFileCmd.Flags().Bool("dry-run", false, "Show what would change without changing anything")
+}
+
+// completeLinkFileArgs completes config names, then the files of the named
+// config relative to its directory
+func completeLinkFileArgs(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	cfg, configPath, err := config.LoadFromDiscovery()
+	if err != nil || len(args) > 1 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	if len(args) == 0 {
+		var names []string
+		for _, c := range cfg.GetAllConfigs() {
+			if strings.HasPrefix(c.Name, toComplete) {
+				names = append(names, c.Name)
+			}
+		}
+		return names, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	item := cfg.GetConfigByName(args[0])
+	if item == nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	dir := item.Dir(filepath.Dir(configPath))
+	var files []string
+	_ = filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() {
+			return nil
+		}
+		if rel, err := filepath.Rel(dir, path); err == nil && strings.HasPrefix(rel, toComplete) {
+			files = append(files, rel)
+		}
+		return nil
+	})
+	return files, cobra.ShellCompDirectiveNoFileComp
+}
@@ -0,0 +1,122 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/nvandessel/go4dot/internal/config"
+	"github.com/nvandessel/go4dot/internal/lint"
+	"github.com/nvandessel/go4dot/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+var lintCmd = &cobra.Command{
+	Use:   "lint [config-path]",
+	Short: "Check config files for broken YAML and personal data",
+	Long: `Check the files of every config for problems worth catching before they
+are committed:
+
+  syntax         YAML files (.yaml, .yml) that don't parse
+  personal-data  Email addresses, GPG key IDs, tokens and home directory
+                 paths, which belong in machine_config templates; values
+                 matching doctor.allow are left out
+
+Files that stow or .go4dotignore ignore are skipped. With --staged, only
+the files staged for the next git commit are checked, as staged; this is
+what the hook 'g4d githooks install' writes runs. Exits with status 1 if
+anything is found.
+
+Examples:
+  g4d lint
+  g4d lint --staged ~/dotfiles`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		staged, _ := cmd.Flags().GetBool("staged")
+		jsonOutput, _ := cmd.Flags().GetBool("json")
+
+		var cfg *config.Config
+		var configPath string
+		var err error
+		if len(args) > 0 {
+			configPath = args[0]
+			if stat, statErr := os.Stat(configPath); statErr == nil && stat.IsDir() {
+				configPath = filepath.Join(configPath, config.ConfigFileName)
+			}
+			cfg, err = config.LoadFromPath(configPath)
+		} else {
+			cfg, configPath, err = config.LoadFromDiscovery()
+		}
+		if err != nil {
+			ui.Error("Error loading config: %v", err)
+			os.Exit(1)
+		}
+		dotfilesPath := filepath.Dir(configPath)
+
+		var files []lint.File
+		if staged {
+			files, err = lint.StagedFiles(cfg, dotfilesPath)
+		} else {
+			files, err = lint.Files(cfg, dotfilesPath)
+		}
+		if err != nil {
+			ui.Error("%v", err)
+			os.Exit(1)
+		}
+
+		findings := lint.Run(cfg, files)
+		if jsonOutput {
+			if findings == nil {
+				findings = []lint.Finding{}
+			}
+			data, err := json.MarshalIndent(findings, "", "  ")
+			if err != nil {
+				ui.Error("%v", err)
+				os.Exit(1)
+			}
+			fmt.Println(string(data))
+		} else {
+			printLintFindings(findings, len(files))
+		}
+
+		if len(findings) > 0 {
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(lintCmd)
+
+	lintCmd.Flags().Bool("staged", false, "Only check files staged for the next git commit")
+	lintCmd.Flags().Bool("json", false, "Output findings as JSON")
+}
+
+func printLintFindings(findings []lint.Finding, fileCount int) {
+	if len(findings) == 0 {
+		ui.Success("No problems in %d file(s)", fileCount)
+		return
+	}
+
+	lastConfig := ""
+	for _, f := range findings {
+		if f.Config != lastConfig {
+			if lastConfig != "" {
+				fmt.Println()
+			}
+			fmt.Println(ui.TitleStyle.UnsetMarginBottom().Render(f.Config))
+			lastConfig = f.Config
+		}
+
+		location := f.Path
+		if f.Line > 0 {
+			location = fmt.Sprintf("%s:%d", f.Path, f.Line)
+		}
+		fmt.Printf("  %s %s %s\n", ui.SubtleStyle.Render(location+":"), f.Message, ui.SubtleStyle.Render("["+f.Check+"]"))
+	}
+
+	fmt.Println()
+	ui.Error("%d problem(s) found", len(findings))
+	fmt.Println(ui.SubtleStyle.Render("Allow a personal value on purpose under doctor.allow in .go4dot.yaml"))
+}
@@ -3,10 +3,12 @@ package main
 import (
 	"fmt"
 	"os"
+	"path/filepath"
 
 	"github.com/nvandessel/go4dot/internal/config"
 	"github.com/nvandessel/go4dot/internal/platform"
 	"github.com/nvandessel/go4dot/internal/state"
+	"github.com/nvandessel/go4dot/internal/stow"
 	"github.com/nvandessel/go4dot/internal/ui"
 	"github.com/spf13/cobra"
 )
@@ -20,45 +22,101 @@ Displays:
 - Installed configs (currently stowed)
 - Available configs (can be installed)
 - Platform-specific configs (not available on this platform)
-- Archived configs (deprecated/old)`,
+- Archived configs (deprecated/old)
+
+With --stats, also reports the number of files, total size and largest
+files of each config, flagging binaries and files over 1 MiB that are
+likely caches or build artifacts.`,
 	Args: cobra.MaximumNArgs(1),
-	Run: func(cmd *cobra.Command, args []string) {
-		// Load config
-		var cfg *config.Config
-		var err error
-
-		if len(args) > 0 {
-			cfg, err = config.LoadFromPath(args[0])
-		} else {
-			cfg, _, err = config.LoadFromDiscovery()
-		}
+	Run:  runList,
+}
+
+func runList(cmd *cobra.Command, args []string) {
+	// Load config
+	var cfg *config.Config
+	var configPath string
+	var err error
+
+	if len(args) > 0 {
+		configPath = args[0]
+		cfg, err = config.LoadFromPath(configPath)
+	} else {
+		cfg, configPath, err = config.LoadFromDiscovery()
+	}
+
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error loading config: %v\n", err)
+		os.Exit(1)
+	}
+
+	// Load state if it exists
+	st, err := state.Load()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to load state: %v\n", err)
+	}
+
+	// Detect platform
+	p, err := platform.Detect()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error detecting platform: %v\n", err)
+		os.Exit(1)
+	}
 
+	showAll, _ := cmd.Flags().GetBool("all")
+	showStats, _ := cmd.Flags().GetBool("stats")
+	noHeader, _ := cmd.Flags().GetBool("no-header")
+
+	ui.PrintConfigList(cfg, st, p, showAll, noHeader)
+
+	if showStats {
+		dotfilesPath, err := config.ResolveRepoRoot(configPath)
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "Error loading config: %v\n", err)
-			os.Exit(1)
+			dotfilesPath = filepath.Dir(configPath)
 		}
+		printConfigStats(cfg, dotfilesPath)
+	}
+}
+
+// listStatsLargest is how many of each config's largest files are listed
+const listStatsLargest = 3
 
-		// Load state if it exists
-		st, err := state.Load()
+// printConfigStats prints the file count, size and largest files of every config
+func printConfigStats(cfg *config.Config, dotfilesPath string) {
+	ui.Section("Size")
+
+	var total int64
+	for _, item := range cfg.GetAllConfigs() {
+		stats, err := stow.GetConfigStats(item, dotfilesPath, listStatsLargest)
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "Warning: failed to load state: %v\n", err)
+			fmt.Printf("  %s %s\n", item.Name, ui.SubtleStyle.Render("("+err.Error()+")"))
+			continue
 		}
+		total += stats.Size
 
-		// Detect platform
-		p, err := platform.Detect()
-		if err != nil {
-			fmt.Fprintf(os.Stderr, "Error detecting platform: %v\n", err)
-			os.Exit(1)
+		files := fmt.Sprintf("%d files", stats.Files)
+		if stats.Files == 1 {
+			files = "1 file"
 		}
+		fmt.Printf("  %-16s %10s  %s\n", item.Name, stow.FormatSize(stats.Size), ui.SubtleStyle.Render(files))
 
-		showAll, _ := cmd.Flags().GetBool("all")
+		for _, f := range stats.Largest {
+			icon, note := ui.SubtleStyle.Render("·"), stow.FormatSize(f.Size)
+			if f.Binary {
+				icon, note = ui.WarningStyle.Render("⚠"), note+", binary"
+			} else if f.Size > stow.LargeFileThreshold {
+				icon, note = ui.WarningStyle.Render("⚠"), note+", large"
+			}
+			fmt.Printf("      %s %s %s\n", icon, f.Path, ui.SubtleStyle.Render("("+note+")"))
+		}
+	}
 
-		ui.PrintConfigList(cfg, st, p, showAll)
-	},
+	fmt.Printf("\n  %-16s %10s\n", "Total", stow.FormatSize(total))
 }
 
 func init() {
 	rootCmd.AddCommand(listCmd)
 
 	listCmd.Flags().BoolP("all", "a", false, "Show all configs including platform-specific and archived")
+	listCmd.Flags().Bool("stats", false, "Show file counts, sizes and suspiciously large files")
+	listCmd.Flags().Bool("no-header", false, "Print only the table rows, without titles, column headers or summary")
 }
@@ -0,0 +1,34 @@
+package main
+
+import (
+	"os"
+
+	"github.com/nvandessel/go4dot/internal/transcript"
+	"github.com/nvandessel/go4dot/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+// addLogFileFlag adds --log-file, which writes a transcript of the run for
+// provisioning pipelines to archive
+func addLogFileFlag(cmd *cobra.Command) {
+	cmd.Flags().String("log-file", "", "Write a JSON Lines transcript of commands run, their output and the results to this file")
+}
+
+// openLogFile starts the transcript when the command was given --log-file
+func openLogFile(cmd *cobra.Command) {
+	path, _ := cmd.Flags().GetString("log-file")
+	if path == "" {
+		return
+	}
+	if err := transcript.Open(path, Version, os.Args); err != nil {
+		ui.Error("%v", err)
+		os.Exit(exitError)
+	}
+}
+
+// exitLogged ends the --log-file transcript, if any, with the exit code and
+// the error the run failed with, then exits
+func exitLogged(code int, err error) {
+	_ = transcript.Finish(code, err)
+	os.Exit(code)
+}
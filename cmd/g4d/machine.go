@@ -177,7 +177,7 @@ var machineShowCmd = &cobra.Command{
 
 		mc := machine.GetMachineConfigByID(cfg, id)
 		if mc == nil {
-			fmt.Fprintf(os.Stderr, "Error: machine config '%s' not found\n", id)
+			fmt.Fprintf(os.Stderr, "Error: %v\n", cfg.UnknownMachinePromptError(id))
 			os.Exit(1)
 		}
 
@@ -224,11 +224,12 @@ var machineRemoveCmd = &cobra.Command{
 
 		mc := machine.GetMachineConfigByID(cfg, id)
 		if mc == nil {
-			fmt.Fprintf(os.Stderr, "Error: machine config '%s' not found\n", id)
+			fmt.Fprintf(os.Stderr, "Error: %v\n", cfg.UnknownMachinePromptError(id))
 			os.Exit(1)
 		}
 
 		opts := machine.RenderOptions{
+			UseTrash: userPrefs.TrashEnabled(),
 			ProgressFunc: func(current, total int, msg string) {
 				if total > 0 && current > 0 {
 					fmt.Printf("[%d/%d] %s\n", current, total, msg)
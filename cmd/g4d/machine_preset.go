@@ -0,0 +1,178 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/nvandessel/go4dot/internal/config"
+	"github.com/nvandessel/go4dot/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+var machinePresetCmd = &cobra.Command{
+	Use:   "preset",
+	Short: "Share machine prompts between repos as presets",
+	Long: `Commands for the local library of machine prompt presets.
+
+A preset is one machine_config entry in a file of its own. Export one from a
+repo, import it into the library, and it is offered next to the built-in
+presets when 'g4d init' or the dashboard's onboarding asks for machine
+configs. Imported presets are kept in ~/.config/go4dot/presets.`,
+}
+
+var machinePresetListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the built-in and imported presets",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		presets, err := config.MachinePresets()
+		if err != nil {
+			ui.Warning("%v", err)
+		}
+
+		for _, p := range presets {
+			source := "built-in"
+			if !p.BuiltIn {
+				source = p.Path
+			}
+			fmt.Printf("  %-20s %s %s\n", p.Prompt.ID, p.Prompt.Description, ui.SubtleStyle.Render("("+source+")"))
+		}
+	},
+}
+
+var machinePresetExportCmd = &cobra.Command{
+	Use:   "export <id> [config-path]",
+	Short: "Print a machine prompt as a preset file",
+	Long: `Print the machine_config entry with the given ID as a preset file, to
+import into another repo or share. The entry is looked up in the repo's
+.go4dot.yaml first, then among the presets.
+
+Examples:
+  g4d machine preset export git-signing > git-signing.yaml`,
+	Args: cobra.RangeArgs(1, 2),
+	Run: func(cmd *cobra.Command, args []string) {
+		id := args[0]
+
+		var cfg *config.Config
+		var err error
+		if len(args) > 1 {
+			cfg, err = config.LoadFromPath(args[1])
+			if err != nil {
+				ui.Error("Error loading config: %v", err)
+				os.Exit(1)
+			}
+		} else {
+			// Outside a dotfiles repo only the presets can be exported
+			cfg, _, _ = config.LoadFromDiscovery()
+		}
+
+		var mp *config.MachinePrompt
+		if cfg != nil {
+			for i := range cfg.MachineConfig {
+				if cfg.MachineConfig[i].ID == id {
+					mp = &cfg.MachineConfig[i]
+					break
+				}
+			}
+		}
+		if mp == nil {
+			preset, err := config.FindMachinePreset(id)
+			if preset == nil {
+				if err != nil {
+					ui.Warning("%v", err)
+				}
+				var ids []string
+				if cfg != nil {
+					ids = cfg.MachinePromptIDs()
+				}
+				presets, _ := config.MachinePresets()
+				for _, p := range presets {
+					ids = append(ids, p.Prompt.ID)
+				}
+				ui.Error("%v", config.UnknownNameError("machine config or preset", id, ids))
+				os.Exit(1)
+			}
+			mp = &preset.Prompt
+		}
+
+		data, err := config.EncodeMachinePreset(*mp)
+		if err != nil {
+			ui.Error("%v", err)
+			os.Exit(1)
+		}
+		_, _ = os.Stdout.Write(data)
+	},
+}
+
+var machinePresetImportCmd = &cobra.Command{
+	Use:   "import <file>",
+	Short: "Add a preset file to the local preset library",
+	Long: `Add a preset file written by 'g4d machine preset export' to the local
+preset library. Use - to read it from stdin. With --add, the machine prompt
+is also appended to machine_config in the current repo's .go4dot.yaml.
+
+Examples:
+  g4d machine preset import git-signing.yaml
+  g4d machine preset import --add git-signing.yaml`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		force, _ := cmd.Flags().GetBool("force")
+		add, _ := cmd.Flags().GetBool("add")
+
+		var data []byte
+		var err error
+		if args[0] == "-" {
+			data, err = io.ReadAll(os.Stdin)
+		} else {
+			data, err = os.ReadFile(args[0])
+		}
+		if err != nil {
+			ui.Error("%v", err)
+			os.Exit(1)
+		}
+
+		// Find the repo before changing anything, so a failed --add leaves
+		// the library as it was
+		var configPath string
+		if add {
+			if configPath, err = config.FindConfig(); err != nil {
+				ui.Error("Error loading config: %v", err)
+				os.Exit(1)
+			}
+		}
+
+		preset, err := config.ImportMachinePreset(data, force)
+		if errors.Is(err, config.ErrPresetExists) {
+			ui.Error("%v", err)
+			fmt.Println(ui.SubtleStyle.Render("Replace it with: g4d machine preset import --force " + args[0]))
+			os.Exit(1)
+		}
+		if err != nil {
+			ui.Error("Invalid preset: %v", err)
+			os.Exit(1)
+		}
+		ui.Success("Imported preset '%s' to %s", preset.Prompt.ID, preset.Path)
+
+		if add {
+			if err := config.AddMachinePrompt(configPath, preset.Prompt); err != nil {
+				ui.Error("%v", err)
+				os.Exit(1)
+			}
+			ui.Success("Added machine config '%s' to %s", preset.Prompt.ID, filepath.Base(configPath))
+			fmt.Println(ui.SubtleStyle.Render("Generate it with: g4d machine configure " + preset.Prompt.ID))
+		}
+	},
+}
+
+func init() {
+	machineCmd.AddCommand(machinePresetCmd)
+	machinePresetCmd.AddCommand(machinePresetListCmd)
+	machinePresetCmd.AddCommand(machinePresetExportCmd)
+	machinePresetCmd.AddCommand(machinePresetImportCmd)
+
+	machinePresetImportCmd.Flags().Bool("force", false, "Replace an imported preset with the same ID")
+	machinePresetImportCmd.Flags().Bool("add", false, "Also add the machine prompt to the current repo's .go4dot.yaml")
+}
@@ -0,0 +1,178 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/nvandessel/go4dot/internal/config"
+	"github.com/nvandessel/go4dot/internal/inventory"
+	"github.com/nvandessel/go4dot/internal/platform"
+	"github.com/nvandessel/go4dot/internal/ui"
+	"github.com/nvandessel/go4dot/internal/ui/cli"
+	"github.com/nvandessel/go4dot/internal/version"
+	"github.com/spf13/cobra"
+)
+
+var machinesCmd = &cobra.Command{
+	Use:   "machines",
+	Short: "Inventory of the machines your dotfiles are synced on",
+	Long: `Show the machines your dotfiles are synced on.
+
+With 'inventory.enabled: true' in .go4dot.yaml, every 'g4d sync' records this
+machine (hostname, platform, profile, go4dot version and sync time) in the
+repo's machines/ directory. Commit and push it like any other change to see
+all your machines from any of them.`,
+}
+
+var machinesListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List recorded machines, marking stale and outdated ones",
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg, configPath, err := config.LoadFromDiscovery()
+		if err != nil {
+			ui.Error("Error loading config: %v", err)
+			os.Exit(1)
+		}
+
+		records, err := inventory.Load(filepath.Dir(configPath))
+		if err != nil {
+			ui.Error("%v", err)
+			os.Exit(1)
+		}
+
+		hostname := ""
+		if p, err := platform.Detect(); err == nil {
+			hostname = p.Hostname
+		}
+		machines := inventory.Assess(records, time.Now(), inventory.StaleAfter(cfg), hostname, version.GetToolVersion())
+
+		if jsonOutput, _ := cmd.Flags().GetBool("json"); jsonOutput {
+			data, err := json.MarshalIndent(machines, "", "  ")
+			if err != nil {
+				ui.Error("Failed to marshal JSON: %v", err)
+				os.Exit(1)
+			}
+			fmt.Println(string(data))
+			return
+		}
+
+		if len(machines) == 0 {
+			if !cfg.Inventory.Enabled {
+				ui.Info("The machine inventory is off. Set 'inventory.enabled: true' in .go4dot.yaml and run 'g4d sync'.")
+			} else {
+				ui.Info("No machines recorded yet. Run 'g4d sync' to record this one.")
+			}
+			return
+		}
+
+		noHeader, _ := cmd.Flags().GetBool("no-header")
+		table := machinesTable(machines)
+		table.NoHeader = noHeader
+		_ = table.Render(ui.Details())
+
+		var stale, outdated int
+		for _, m := range machines {
+			if m.Stale {
+				stale++
+			}
+			if m.Outdated {
+				outdated++
+			}
+		}
+		ui.Summary("Machines", "%d recorded, %d stale, %d outdated", len(machines), stale, outdated)
+		if !noHeader {
+			ui.Printf("\n%d machines, %d stale (no sync in %d days), %d on an older go4dot\n",
+				len(machines), stale, int(inventory.StaleAfter(cfg).Hours()/24), outdated)
+		}
+	},
+}
+
+// machinesTable lists recorded machines with their platform, version and
+// last sync, marking the current, stale and outdated ones
+func machinesTable(machines []inventory.Machine) *cli.Table {
+	table := cli.NewTable(
+		cli.Column{Header: "HOSTNAME"},
+		cli.Column{Header: "PROFILE"},
+		cli.Column{Header: "PLATFORM"},
+		cli.Column{Header: "GO4DOT"},
+		cli.Column{Header: "LAST SYNC"},
+		cli.Column{Header: "STATUS", Shrink: true},
+	)
+
+	for _, m := range machines {
+		host := m.Hostname
+		if m.Current {
+			host += " *"
+		}
+		platformName := m.OS
+		if m.Distro != "" {
+			platformName += "/" + m.Distro
+		}
+		if m.Arch != "" {
+			platformName += " " + m.Arch
+		}
+
+		versionCell := cli.Text(m.Version)
+		if m.Outdated {
+			versionCell = cli.Styled(m.Version, ui.WarningStyle)
+		}
+		syncCell := cli.Text(syncAge(m.LastSync))
+		if m.Stale {
+			syncCell = cli.Styled(syncAge(m.LastSync), ui.WarningStyle)
+		}
+
+		var status cli.Cell
+		switch {
+		case m.Stale && m.Outdated:
+			status = cli.Styled("stale, outdated", ui.WarningStyle)
+		case m.Stale:
+			status = cli.Styled("stale", ui.WarningStyle)
+		case m.Outdated:
+			status = cli.Styled("outdated", ui.WarningStyle)
+		default:
+			status = cli.Styled("ok", ui.SuccessStyle)
+		}
+
+		table.AddRow(cli.Text(host), cli.Text(m.Profile), cli.Text(platformName), versionCell, syncCell, status)
+	}
+	return table
+}
+
+// syncAge renders how long ago a machine was synced
+func syncAge(t time.Time) string {
+	d := time.Since(t)
+	switch {
+	case d < time.Hour:
+		return "just now"
+	case d < 24*time.Hour:
+		return fmt.Sprintf("%dh ago", int(d.Hours()))
+	default:
+		return fmt.Sprintf("%dd ago", int(d.Hours()/24))
+	}
+}
+
+// recordMachine records a sync of this machine in the repo's inventory,
+// when the config enables it
+func recordMachine(cfg *config.Config, dotfilesPath string) {
+	if !cfg.Inventory.Enabled {
+		return
+	}
+	p, err := platform.Detect()
+	if err == nil {
+		err = inventory.Update(cfg, dotfilesPath, p)
+	}
+	if err != nil {
+		ui.Warning("Could not record this machine in %s/: %v", inventory.DirName, err)
+	}
+}
+
+func init() {
+	rootCmd.AddCommand(machinesCmd)
+	machinesCmd.AddCommand(machinesListCmd)
+
+	machinesListCmd.Flags().Bool("json", false, "Output as JSON")
+	machinesListCmd.Flags().Bool("no-header", false, "Print only the table rows, without column headers or summary")
+}
@@ -1,5 +1,8 @@
 package main
 
+import "github.com/nvandessel/go4dot/internal/version"
+
 func main() {
+	version.SetToolVersion(Version)
 	Execute()
 }
@@ -0,0 +1,126 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/nvandessel/go4dot/internal/config"
+	"github.com/nvandessel/go4dot/internal/manifest"
+	"github.com/nvandessel/go4dot/internal/platform"
+	"github.com/nvandessel/go4dot/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+var manifestCmd = &cobra.Command{
+	Use:   "manifest",
+	Short: "List everything go4dot manages on this machine",
+	Long: `Write a manifest of everything go4dot manages on this machine: every
+symlink in place with its source and target, every dependency with the
+version found, every external with the commit or release installed, and a
+SHA-256 hash of each generated machine config, which tells whether two
+machines were given the same answers without revealing them.
+
+Paths under the home directory start with ~/ and paths in the dotfiles repo
+with @repoRoot/, so the manifests of two machines can be compared with
+'g4d manifest diff'.
+
+  g4d manifest -o laptop.yaml
+  g4d manifest --format json > laptop.json`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		output, _ := cmd.Flags().GetString("output")
+		format, _ := cmd.Flags().GetString("format")
+		if format == "" {
+			format = manifestFormat(output)
+		}
+
+		m, err := generateManifest()
+		if err != nil {
+			ui.Error("%v", err)
+			os.Exit(exitError)
+		}
+		data, err := manifest.Encode(m, format)
+		if err != nil {
+			ui.Error("%v", err)
+			os.Exit(exitError)
+		}
+
+		if output == "" {
+			fmt.Print(string(data))
+			return
+		}
+		if err := os.WriteFile(output, data, 0644); err != nil {
+			ui.Error("Failed to write manifest: %v", err)
+			os.Exit(exitError)
+		}
+		ui.Success("Wrote manifest to %s", output)
+	},
+}
+
+var manifestDiffCmd = &cobra.Command{
+	Use:   "diff <manifest> [other]",
+	Short: "Compare two manifests",
+	Long: `Compare a manifest with another, or with this machine when only one is given.
+
+Lists the machine details that differ, then the symlinks, dependencies,
+externals and machine configs added (+), removed (-) or changed (~), such as
+a dependency at another version or an external at another commit.`,
+	Args: cobra.RangeArgs(1, 2),
+	Run: func(cmd *cobra.Command, args []string) {
+		a, err := manifest.Load(args[0])
+		if err != nil {
+			ui.Error("%v", err)
+			os.Exit(exitError)
+		}
+
+		var b *manifest.Manifest
+		otherLabel := "this machine"
+		if len(args) > 1 {
+			b, err = manifest.Load(args[1])
+			otherLabel = args[1]
+		} else {
+			b, err = generateManifest()
+		}
+		if err != nil {
+			ui.Error("%v", err)
+			os.Exit(exitError)
+		}
+
+		diff := manifest.Diff(a, b)
+		ui.Section(fmt.Sprintf("%s → %s", args[0], otherLabel))
+		printConfigDiff(diff)
+		fmt.Println()
+		fmt.Println(diff.Summary())
+	},
+}
+
+// generateManifest writes the manifest of this machine for the discovered
+// config
+func generateManifest() (*manifest.Manifest, error) {
+	cfg, configPath, err := config.LoadFromDiscovery()
+	if err != nil {
+		return nil, fmt.Errorf("error loading config: %w", err)
+	}
+	p, err := platform.Detect()
+	if err != nil {
+		return nil, fmt.Errorf("failed to detect platform: %w", err)
+	}
+	return manifest.Generate(cfg, filepath.Dir(configPath), p)
+}
+
+// manifestFormat picks the manifest format from the output file's extension
+func manifestFormat(output string) string {
+	if strings.EqualFold(filepath.Ext(output), ".json") {
+		return manifest.FormatJSON
+	}
+	return manifest.FormatYAML
+}
+
+func init() {
+	rootCmd.AddCommand(manifestCmd)
+	manifestCmd.AddCommand(manifestDiffCmd)
+	manifestCmd.Flags().StringP("output", "o", "", "Write the manifest to a file instead of printing it")
+	manifestCmd.Flags().String("format", "", "Manifest format: yaml or json (default from the output extension, else yaml)")
+}
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/nvandessel/go4dot/internal/status"
+	"github.com/spf13/cobra"
+)
+
+var motdCmd = &cobra.Command{
+	Use:   "motd",
+	Short: "Print a short dotfiles summary, once a day",
+	Long: `Print a few colorized lines on the state of your dotfiles: configs that
+drifted, missing dependencies and machine prompts not answered yet, with the
+command that fixes each.
+
+Meant to run when a shell starts, it prints at most once a day: later runs
+the same day print nothing and return at once. Set shell_integration.motd in
+.go4dot.yaml to have 'g4d shell-init' call it, or add it to your rc file:
+
+  g4d motd
+
+Use --force to print regardless of when it last did.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		force, _ := cmd.Flags().GetBool("force")
+		now := time.Now()
+
+		cachePath, cacheErr := status.MOTDCachePath()
+		if !force && cacheErr == nil && !status.MOTDDue(cachePath, now) {
+			return
+		}
+
+		overview, err := status.NewGatherer().Gather(status.GatherOptions{})
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(exitError)
+		}
+		fmt.Print(status.RenderMOTD(overview))
+
+		// Failing to record the run only means printing again next shell
+		if cacheErr == nil {
+			_ = status.MarkMOTDShown(cachePath, now)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(motdCmd)
+	motdCmd.Flags().Bool("force", false, "Print even if the summary was already shown today")
+}
@@ -0,0 +1,142 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/charmbracelet/huh"
+	"github.com/nvandessel/go4dot/internal/config"
+	"github.com/nvandessel/go4dot/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+var newCmd = &cobra.Command{
+	Use:   "new <tool>",
+	Short: "Scaffold a starter config for a known tool",
+	Long: `Create a config package for a well-known tool with sensible defaults and
+add it to .go4dot.yaml.
+
+For example 'g4d new alacritty' creates alacritty/.config/alacritty/alacritty.toml
+in your dotfiles repo and adds an 'alacritty' config entry. Existing files are
+never overwritten. Use --add-dep to also declare the tool as a dependency
+(interactively you are asked), then 'g4d link <tool>' to link it.
+
+Examples:
+  g4d new --list            # Show the tools with a starter config
+  g4d new alacritty         # Scaffold an Alacritty config
+  g4d new tmux --add-dep    # Scaffold tmux and declare it as a dependency
+  g4d new kitty --optional  # Add the config to the optional group`,
+	ValidArgs: config.StarterNames(),
+	Args: func(cmd *cobra.Command, args []string) error {
+		if list, _ := cmd.Flags().GetBool("list"); list {
+			return cobra.NoArgs(cmd, args)
+		}
+		return cobra.ExactArgs(1)(cmd, args)
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		if list, _ := cmd.Flags().GetBool("list"); list {
+			for _, name := range config.StarterNames() {
+				fmt.Printf("  %-10s %s\n", name, config.FindStarter(name).Description)
+			}
+			return
+		}
+
+		starter := config.FindStarter(args[0])
+		if starter == nil {
+			ui.Error("No starter config for '%s' (available: %s)", args[0], strings.Join(config.StarterNames(), ", "))
+			os.Exit(1)
+		}
+
+		cfg, configPath, err := config.LoadFromDiscovery()
+		if err != nil {
+			ui.Error("Failed to load config: %v", err)
+			os.Exit(1)
+		}
+
+		opts := newOptions{}
+		opts.optional, _ = cmd.Flags().GetBool("optional")
+		opts.addDep, _ = cmd.Flags().GetBool("add-dep")
+		if !cmd.Flags().Changed("add-dep") && ui.IsInteractive() && !hasDependency(cfg, starter.Dependency) {
+			err := huh.NewForm(
+				huh.NewGroup(
+					huh.NewConfirm().
+						Title(fmt.Sprintf("Add %s to the dependencies?", starter.Dependency.Name)).
+						Affirmative("Yes").
+						Negative("No").
+						Value(&opts.addDep),
+				),
+			).Run()
+			if err != nil {
+				fmt.Println("Cancelled.")
+				return
+			}
+		}
+
+		if err := scaffoldStarter(cfg, configPath, starter, opts); err != nil {
+			ui.Error("%v", err)
+			os.Exit(1)
+		}
+	},
+}
+
+// newOptions controls how 'g4d new' adds a starter to the repo
+type newOptions struct {
+	optional bool // Add the config to configs.optional instead of configs.core
+	addDep   bool // Declare the tool as a core dependency
+}
+
+func init() {
+	rootCmd.AddCommand(newCmd)
+
+	newCmd.Flags().Bool("list", false, "List the tools with a starter config")
+	newCmd.Flags().Bool("optional", false, "Add the config to the optional group")
+	newCmd.Flags().Bool("add-dep", false, "Also declare the tool as a dependency")
+}
+
+// scaffoldStarter writes the starter's files next to the config file at
+// configPath and registers the config (and optionally its dependency) there
+func scaffoldStarter(cfg *config.Config, configPath string, starter *config.Starter, opts newOptions) error {
+	if cfg.GetConfigByName(starter.Name) != nil {
+		return fmt.Errorf("config '%s' already exists in %s", starter.Name, configPath)
+	}
+
+	dotfilesPath := filepath.Dir(configPath)
+	written, err := starter.WriteFiles(dotfilesPath)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", starter.Name, err)
+	}
+	for _, f := range written {
+		fmt.Printf("  + %s\n", f)
+	}
+
+	if err := config.AddConfigItem(configPath, starter.ConfigItem(), opts.optional); err != nil {
+		return err
+	}
+	ui.Success("Added config '%s' to %s", starter.Name, filepath.Base(configPath))
+
+	if opts.addDep {
+		if hasDependency(cfg, starter.Dependency) {
+			fmt.Printf("  %s is already a dependency\n", starter.Dependency.Name)
+		} else {
+			if err := config.AddDependency(configPath, starter.Dependency, "core"); err != nil {
+				return err
+			}
+			ui.Success("Added dependency '%s'", starter.Dependency.Name)
+		}
+	}
+
+	fmt.Printf("\nEdit the files above, then run 'g4d link %s' to link them.\n", starter.Name)
+	return nil
+}
+
+// hasDependency reports whether cfg already declares dep, by name or binary
+func hasDependency(cfg *config.Config, dep config.DependencyItem) bool {
+	for _, d := range cfg.GetAllDependencies() {
+		if d.Name == dep.Name || (d.Binary != "" && d.Binary == dep.Binary) {
+			return true
+		}
+	}
+	return false
+}
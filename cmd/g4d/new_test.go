@@ -0,0 +1,83 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/nvandessel/go4dot/internal/config"
+)
+
+func TestScaffoldStarter(t *testing.T) {
+	const original = `schema_version: "1.0"
+configs:
+  core:
+    - name: zsh
+      path: zsh
+dependencies:
+  critical:
+    - git
+`
+
+	tests := []struct {
+		name     string
+		tool     string
+		opts     newOptions
+		wantErr  bool
+		wantOpt  bool
+		wantDeps int
+	}{
+		{name: "core config without dependency", tool: "alacritty", wantDeps: 1},
+		{name: "optional config with dependency", tool: "kitty", opts: newOptions{optional: true, addDep: true}, wantOpt: true, wantDeps: 2},
+		{name: "dependency already declared", tool: "git", opts: newOptions{addDep: true}, wantDeps: 1},
+		{name: "config already exists", tool: "zsh", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			configPath := filepath.Join(dir, config.ConfigFileName)
+			if err := os.WriteFile(configPath, []byte(original), 0644); err != nil {
+				t.Fatal(err)
+			}
+			cfg, err := config.Load(configPath)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			starter := config.FindStarter(tt.tool)
+			err = scaffoldStarter(cfg, configPath, starter, tt.opts)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("scaffoldStarter() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			for rel := range starter.Files {
+				if _, err := os.Stat(filepath.Join(dir, tt.tool, rel)); err != nil {
+					t.Errorf("starter file %s not created: %v", rel, err)
+				}
+			}
+
+			cfg, err = config.Load(configPath)
+			if err != nil {
+				t.Fatalf("Load() after scaffold: %v", err)
+			}
+			group := cfg.Configs.Core
+			if tt.wantOpt {
+				group = cfg.Configs.Optional
+			}
+			found := false
+			for _, item := range group {
+				found = found || item.Name == tt.tool
+			}
+			if !found {
+				t.Errorf("config %s not added to the expected group", tt.tool)
+			}
+			if got := len(cfg.GetAllDependencies()); got != tt.wantDeps {
+				t.Errorf("got %d dependencies, want %d", got, tt.wantDeps)
+			}
+		})
+	}
+}
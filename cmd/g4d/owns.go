@@ -0,0 +1,163 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/nvandessel/go4dot/internal/config"
+	"github.com/nvandessel/go4dot/internal/platform"
+	"github.com/nvandessel/go4dot/internal/status"
+	"github.com/nvandessel/go4dot/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+var ownsCmd = &cobra.Command{
+	Use:   "owns <path>",
+	Short: "Show which config manages a path",
+	Long: `Look up whether a path in your home directory is managed by go4dot.
+
+Reports the owning config, external dependency or machine config, how the
+path is provided (symlink, folded parent directory, copy, clone or generated
+template) and the source file in the dotfiles repo. Exits with status 1 if
+the path is not managed.
+
+Examples:
+  g4d owns ~/.zshrc
+  g4d owns ~/.config/nvim/init.lua`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeManagedPaths,
+	Run: func(cmd *cobra.Command, args []string) {
+		jsonOutput, _ := cmd.Flags().GetBool("json")
+
+		cfg, configPath, err := config.LoadFromDiscovery()
+		if err != nil {
+			ui.Error("Error loading config: %v", err)
+			os.Exit(1)
+		}
+
+		p, err := platform.Detect()
+		if err != nil {
+			ui.Error("Failed to detect platform: %v", err)
+			os.Exit(1)
+		}
+
+		result, err := status.FindOwners(cfg, filepath.Dir(configPath), p, args[0])
+		if err != nil {
+			ui.Error("%v", err)
+			os.Exit(1)
+		}
+
+		if jsonOutput {
+			data, err := json.MarshalIndent(result, "", "  ")
+			if err != nil {
+				ui.Error("%v", err)
+				os.Exit(1)
+			}
+			fmt.Println(string(data))
+		} else {
+			printOwnership(result)
+		}
+
+		if !result.IsManaged() {
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(ownsCmd)
+
+	ownsCmd.Flags().Bool("json", false, "Output as JSON")
+}
+
+func printOwnership(result *status.Ownership) {
+	if len(result.Owners) == 0 {
+		if result.StrayLink != "" {
+			ui.Warning("%s links into the dotfiles repo (%s) but no config provides it", result.Target, result.StrayLink)
+			return
+		}
+		fmt.Printf("%s is not managed by go4dot\n", result.Target)
+		return
+	}
+
+	fmt.Println(result.Target)
+	for _, o := range result.Owners {
+		fmt.Printf("  %s %s %s\n", ownerIcon(o.Method), ui.TitleStyle.UnsetMarginBottom().Render(o.Name), ui.SubtleStyle.Render("("+string(o.Kind)+")"))
+		fmt.Printf("      %s\n", describeOwner(o))
+		if o.Source != "" && o.Kind == status.OwnerConfig {
+			fmt.Printf("      %s %s\n", ui.SubtleStyle.Render("source:"), o.Source)
+		}
+	}
+}
+
+func ownerIcon(m status.OwnerMethod) string {
+	switch m {
+	case status.MethodConflict:
+		return ui.ErrorStyle.Render("✗")
+	case status.MethodMissing, status.MethodIgnored:
+		return ui.WarningStyle.Render("⚠")
+	default:
+		return ui.SuccessStyle.Render("✓")
+	}
+}
+
+func describeOwner(o status.Owner) string {
+	switch o.Method {
+	case status.MethodSymlink:
+		return "symlinked to the source file"
+	case status.MethodFolded:
+		return fmt.Sprintf("provided by folded directory link %s", o.Link)
+	case status.MethodCopy:
+		if o.Kind == status.OwnerExternal {
+			return fmt.Sprintf("copied from %s into %s", o.Source, o.Link)
+		}
+		return "regular file identical to the source (copy)"
+	case status.MethodDirectory:
+		return "directory containing linked files"
+	case status.MethodConflict:
+		if o.Link != "" {
+			return fmt.Sprintf("conflict: %s is a symlink to somewhere else", o.Link)
+		}
+		return "conflict: a different file is in the way"
+	case status.MethodMissing:
+		if o.Kind == status.OwnerMachine {
+			return "not generated yet (run 'g4d machine configure')"
+		}
+		return "provided by the config but not linked (run 'g4d stow add " + o.Name + "')"
+	case status.MethodIgnored:
+		return "exists in the config but is ignored by stow"
+	case status.MethodClone:
+		return fmt.Sprintf("inside external checkout %s (%s)", o.Link, o.Source)
+	case status.MethodGenerated:
+		return "generated from machine config template"
+	}
+	return string(o.Method)
+}
+
+// completeManagedPaths completes paths under ~ from the files the configs
+// provide, falling back to regular file completion
+func completeManagedPaths(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	if !strings.HasPrefix(toComplete, "~") {
+		return nil, cobra.ShellCompDirectiveDefault
+	}
+
+	cfg, configPath, err := config.LoadFromDiscovery()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveDefault
+	}
+
+	var completions []string
+	for _, rel := range status.ManagedPaths(cfg, filepath.Dir(configPath)) {
+		candidate := "~/" + filepath.ToSlash(rel)
+		if strings.HasPrefix(candidate, toComplete) {
+			completions = append(completions, candidate)
+		}
+	}
+	return completions, cobra.ShellCompDirectiveDefault
+}
@@ -0,0 +1,116 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/nvandessel/go4dot/internal/config"
+	"github.com/nvandessel/go4dot/internal/state"
+	"github.com/nvandessel/go4dot/internal/stow"
+	"github.com/nvandessel/go4dot/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+var popupCmd = &cobra.Command{
+	Use:   "popup",
+	Short: "Compact link picker for tmux and zellij popups",
+	Long: `Show a small picker listing each config's link status, to link one or
+all of them without opening the dashboard. It runs inline at a fixed size
+that fits a terminal multiplexer's popup:
+
+  tmux:    bind g display-popup -E -w 60 -h 18 "g4d popup"
+  zellij:  zellij run --floating --close-on-exit -- g4d popup
+
+Keys: j/k to move, enter to link the selected config, a to link all,
+r to refresh and q to quit. Linking here only restows symlinks, like
+'g4d link'; use 'g4d sync' or the dashboard to also install dependencies
+and externals.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		if !ui.IsInteractive() {
+			ui.Error("g4d popup needs a terminal; use 'g4d status' or 'g4d link' in scripts")
+			os.Exit(exitError)
+		}
+
+		_, configPath, err := config.LoadFromDiscovery()
+		if err != nil {
+			ui.Error("Failed to load config: %v", err)
+			os.Exit(exitError)
+		}
+		dotfilesPath := filepath.Dir(configPath)
+
+		err = ui.RunPopup(ui.PopupOptions{
+			Title: "go4dot",
+			Load: func() ([]ui.PopupConfig, error) {
+				cfg, err := config.Load(configPath)
+				if err != nil {
+					return nil, err
+				}
+				return popupConfigs(cfg, dotfilesPath)
+			},
+			Sync: func(name string) error {
+				return popupLink(configPath, dotfilesPath, name)
+			},
+		})
+		if err != nil {
+			ui.Error("%v", err)
+			os.Exit(exitError)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(popupCmd)
+}
+
+// popupConfigs lists the configs with their link status and drift
+func popupConfigs(cfg *config.Config, dotfilesPath string) ([]ui.PopupConfig, error) {
+	links, err := stow.GetAllConfigLinkStatus(cfg, dotfilesPath)
+	if err != nil {
+		return nil, err
+	}
+	drift, err := stow.FullDriftCheck(cfg, dotfilesPath)
+	if err != nil {
+		return nil, err
+	}
+
+	var rows []ui.PopupConfig
+	for _, c := range cfg.GetAllConfigs() {
+		row := ui.PopupConfig{Name: c.Name}
+		if s := links[c.Name]; s != nil {
+			row.Linked, row.Total = s.LinkedCount, s.TotalCount
+		}
+		if d := drift.ResultByName(c.Name); d != nil {
+			row.NewFiles, row.Conflicts = len(d.NewFiles), len(d.ConflictFiles)
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+// popupLink restows one config, or all of them when name is empty. Nothing
+// is printed, as the popup owns the terminal.
+func popupLink(configPath, dotfilesPath, name string) error {
+	cfg, err := config.Load(configPath)
+	if err != nil {
+		return err
+	}
+	st, _ := state.Load()
+	if st == nil {
+		st = state.New()
+	}
+
+	if name != "" {
+		return stow.SyncSingle(dotfilesPath, name, cfg, st, stow.StowOptions{})
+	}
+	result, err := stow.SyncAll(dotfilesPath, cfg, st, false, stow.StowOptions{})
+	if err != nil {
+		return err
+	}
+	if len(result.Failed) > 0 {
+		first := result.Failed[0]
+		return fmt.Errorf("%d failed, %s: %w", len(result.Failed), first.ConfigName, first.Error)
+	}
+	return nil
+}
@@ -0,0 +1,156 @@
+package main
+
+import (
+	"os"
+
+	"github.com/nvandessel/go4dot/internal/config"
+	"github.com/nvandessel/go4dot/internal/state"
+	"github.com/nvandessel/go4dot/internal/ui"
+	"github.com/nvandessel/go4dot/internal/ui/cli"
+	"github.com/spf13/cobra"
+)
+
+var profileCmd = &cobra.Command{
+	Use:   "profile",
+	Short: "Show the profiles this machine can use",
+	Long: `Show the profiles defined in .go4dot.yaml.
+
+A profile is a machine role, such as work, laptop or server, that selects the
+configs, dependencies and external dependencies installed for it. Choose one
+when installing or syncing; every later command on this machine keeps using it
+until another is chosen:
+
+  g4d install --profile work
+  g4d sync --profile laptop
+  g4d sync --profile all      # back to every config
+
+GO4DOT_PROFILE selects a profile for a single run.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg := loadProfileConfig()
+		if len(cfg.Profiles) == 0 {
+			ui.Info("No profiles defined. Add a 'profiles' section to .go4dot.yaml.")
+			return
+		}
+
+		noHeader, _ := cmd.Flags().GetBool("no-header")
+		table := cli.NewTable(
+			cli.Column{Header: "NAME"},
+			cli.Column{Header: "CONFIGS"},
+			cli.Column{Header: "DEPS"},
+			cli.Column{Header: "EXTERNAL"},
+			cli.Column{Header: "DESCRIPTION", Shrink: true},
+		)
+		table.NoHeader = noHeader
+
+		active := config.ActiveProfileName()
+		for _, p := range cfg.Profiles {
+			name := p.Name
+			if p.Name == active {
+				name += " *"
+			}
+			table.AddRow(cli.Text(name), cli.Text(config.ProfileCount(p.Configs)), cli.Text(config.ProfileCount(p.Dependencies)),
+				cli.Text(config.ProfileCount(p.External)), cli.Text(p.Description))
+		}
+		_ = table.Render(ui.Details())
+
+		if active == "" {
+			ui.Summary("Profiles", "%d defined, none active (every config is used)", len(cfg.Profiles))
+		} else {
+			ui.Summary("Profiles", "%d defined, %s active", len(cfg.Profiles), active)
+		}
+	},
+}
+
+var profileUseCmd = &cobra.Command{
+	Use:   "use <name>",
+	Short: "Switch this machine to a profile without syncing",
+	Long: `Make the named profile the one this machine uses, like --profile does, without
+syncing. Use 'all' to go back to every config. Run g4d sync afterwards to link the
+configs the profile selects.`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeProfiles,
+	Run: func(cmd *cobra.Command, args []string) {
+		useProfile(args[0])
+		cfg := loadProfileConfig()
+		if err := saveProfile(args[0]); err != nil {
+			ui.Error("%v", err)
+			os.Exit(exitError)
+		}
+		if cfg.ActiveProfile == "" {
+			ui.Success("Using every config")
+		} else {
+			ui.Success("Using profile %s: %d configs", cfg.ActiveProfile, len(cfg.GetAllConfigs()))
+		}
+		ui.Println(ui.SubtleStyle.Render("Run 'g4d sync' to link them."))
+	},
+}
+
+// loadProfileConfig loads the discovered config, narrowed to the active
+// profile, exiting when it can't
+func loadProfileConfig() *config.Config {
+	cfg, _, err := config.LoadFromDiscovery()
+	if err != nil {
+		ui.Error("Error loading config: %v", err)
+		os.Exit(exitError)
+	}
+	return cfg
+}
+
+// addProfileFlag adds --profile to a command that installs or links configs
+func addProfileFlag(cmd *cobra.Command) {
+	cmd.Flags().String("profile", "", "Use only what the named profile selects, on this and later runs ('all' for everything)")
+	_ = cmd.RegisterFlagCompletionFunc("profile", completeProfiles)
+}
+
+// useProfileFlag makes the --profile given, if any, the active profile for
+// this run and returns it. It is saved for later runs by saveProfile once
+// the config has accepted it.
+func useProfileFlag(cmd *cobra.Command) string {
+	name, _ := cmd.Flags().GetString("profile")
+	if name != "" {
+		useProfile(name)
+	}
+	return name
+}
+
+// useProfile makes name the active profile for this process and the
+// commands it runs
+func useProfile(name string) {
+	_ = os.Setenv(config.EnvProfile, name)
+}
+
+// applySavedProfile makes the profile saved for this machine the active one,
+// unless GO4DOT_PROFILE already selects one
+func applySavedProfile() {
+	if os.Getenv(config.EnvProfile) != "" {
+		return
+	}
+	if name := state.LoadProfile(); name != "" {
+		useProfile(name)
+	}
+}
+
+// saveProfile records name as the profile this machine uses; 'all' clears it
+func saveProfile(name string) error {
+	if name == config.AllProfiles {
+		name = ""
+	}
+	return state.SaveProfile(name)
+}
+
+// completeProfiles completes profile names from the discovered config
+func completeProfiles(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	cfg, _, err := config.LoadFromDiscovery()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	return append(cfg.ProfileNames(), config.AllProfiles), cobra.ShellCompDirectiveNoFileComp
+}
+
+func init() {
+	rootCmd.AddCommand(profileCmd)
+	profileCmd.AddCommand(profileUseCmd)
+
+	profileCmd.Flags().Bool("no-header", false, "Print only the table rows, without column headers")
+}
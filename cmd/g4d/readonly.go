@@ -0,0 +1,81 @@
+package main
+
+import (
+	"os"
+	"strings"
+
+	"github.com/nvandessel/go4dot/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+// envReadOnly turns on --read-only, e.g. exported in root's shell profile
+const envReadOnly = "GO4DOT_READ_ONLY"
+
+// readOnly refuses every command that changes the machine (--read-only)
+var readOnly bool
+
+// mutatingCommands are the commands refused in read-only mode, by their
+// path below the root command. Everything else only reads.
+var mutatingCommands = map[string]bool{
+	"adopt":                     true,
+	"alias":                     true,
+	"config archive":            true,
+	"config move":               true,
+	"config rename":             true,
+	"config restore":            true,
+	"config restore-backup":     true,
+	"config split":              true,
+	"deps install":              true,
+	"deps refresh":              true,
+	"external clone":            true,
+	"external remove":           true,
+	"external update":           true,
+	"githooks install":          true,
+	"init":                      true,
+	"install":                   true,
+	"link":                      true,
+	"link-file":                 true,
+	"machine configure":         true,
+	"machine keys generate-ssh": true,
+	"machine keys register":     true,
+	"machine preset import":     true,
+	"machine remove":            true,
+	"new":                       true,
+	"popup":                     true,
+	"reconfigure":               true,
+	"state decrypt":             true,
+	"state encrypt":             true,
+	"state repair":              true,
+	"stow add":                  true,
+	"stow refresh":              true,
+	"stow remove":               true,
+	"sync":                      true,
+	"trash restore":             true,
+	"undo":                      true,
+	"undo-conflicts":            true,
+	"uninstall":                 true,
+	"update":                    true,
+}
+
+// isMutating reports whether cmd, or the command it is a subcommand of,
+// changes the machine
+func isMutating(cmd *cobra.Command) bool {
+	path := strings.Fields(cmd.CommandPath())
+	for i := len(path); i > 1; i-- {
+		if mutatingCommands[strings.Join(path[1:i], " ")] {
+			return true
+		}
+	}
+	return false
+}
+
+// checkReadOnly exits when cmd would change the machine in read-only mode
+func checkReadOnly(cmd *cobra.Command) {
+	if !readOnly && os.Getenv(envReadOnly) == "1" {
+		readOnly = true
+	}
+	if readOnly && isMutating(cmd) {
+		ui.Error("'%s' changes the machine and is disabled in read-only mode", strings.TrimPrefix(cmd.CommandPath(), cmd.Root().Name()+" "))
+		os.Exit(exitError)
+	}
+}
@@ -0,0 +1,43 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestIsMutating(t *testing.T) {
+	tests := []struct {
+		args []string
+		want bool
+	}{
+		{[]string{"sync"}, true},
+		{[]string{"config", "restore-backup"}, true},
+		{[]string{"machine", "keys", "generate-ssh"}, true},
+		{[]string{"new", "zsh"}, true},
+		{[]string{"list"}, false},
+		{[]string{"config", "show"}, false},
+		{[]string{"machine", "keys", "list"}, false},
+		{[]string{"dash"}, false},
+	}
+
+	for _, tt := range tests {
+		cmd, _, err := rootCmd.Find(tt.args)
+		if err != nil {
+			t.Fatalf("Find(%v) error = %v", tt.args, err)
+		}
+		if got := isMutating(cmd); got != tt.want {
+			t.Errorf("isMutating(%v) = %v, want %v", tt.args, got, tt.want)
+		}
+	}
+}
+
+// Every mutating command must exist, or a rename would quietly let it run
+// in read-only mode
+func TestMutatingCommandsExist(t *testing.T) {
+	for path := range mutatingCommands {
+		cmd, _, err := rootCmd.Find(strings.Fields(path))
+		if err != nil || !strings.HasSuffix(cmd.CommandPath(), " "+path) {
+			t.Errorf("mutating command %q not found", path)
+		}
+	}
+}
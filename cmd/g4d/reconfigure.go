@@ -93,7 +93,7 @@ This is useful when:
 			// Reconfigure single
 			mc := machine.GetMachineConfigByID(cfg, specificID)
 			if mc == nil {
-				fmt.Fprintf(os.Stderr, "Error: machine config '%s' not found\n", specificID)
+				fmt.Fprintf(os.Stderr, "Error: %v\n", cfg.UnknownMachinePromptError(specificID))
 				os.Exit(1)
 			}
 
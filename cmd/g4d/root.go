@@ -3,8 +3,16 @@ package main
 import (
 	"fmt"
 	"os"
+	"strings"
 
+	"github.com/nvandessel/go4dot/internal/alias"
+	"github.com/nvandessel/go4dot/internal/config"
+	"github.com/nvandessel/go4dot/internal/deps"
+	"github.com/nvandessel/go4dot/internal/feature"
+	"github.com/nvandessel/go4dot/internal/prefs"
+	"github.com/nvandessel/go4dot/internal/transcript"
 	"github.com/nvandessel/go4dot/internal/ui"
+	"github.com/nvandessel/go4dot/internal/workspace"
 	"github.com/spf13/cobra"
 )
 
@@ -16,6 +24,17 @@ var (
 
 	// Global flags
 	nonInteractive bool
+	plainMode      bool
+	quietMode      bool
+	summaryMode    bool
+	noCache        bool
+	envFlags       []string
+
+	// User preferences from ~/.config/go4dot/config.yaml
+	userPrefs = prefs.Default()
+
+	// Confirmation decisions for this process, driven by userPrefs.Confirm
+	confirmTracker = prefs.NewConfirmTracker(userPrefs)
 )
 
 var rootCmd = &cobra.Command{
@@ -48,12 +67,56 @@ var versionCmd = &cobra.Command{
 }
 
 func init() {
+	// Usage, help and completion scripts use the name the binary was run as
+	rootCmd.Use = alias.Invoked(os.Args[0])
+
 	// Global persistent flags
 	rootCmd.PersistentFlags().BoolVar(&nonInteractive, "non-interactive", false, "Run without interactive prompts")
 	rootCmd.PersistentFlags().BoolP("yes", "y", false, "Alias for --non-interactive")
+	rootCmd.PersistentFlags().BoolVar(&plainMode, "plain", false, "Render without alternate screen or mouse, for slow SSH links and terminal capture")
+	rootCmd.PersistentFlags().BoolVarP(&quietMode, "quiet", "q", false, "Print errors only, for cron jobs and scripts")
+	rootCmd.PersistentFlags().BoolVar(&summaryMode, "summary", false, "Print one summary line per category instead of per-item output")
+	rootCmd.MarkFlagsMutuallyExclusive("quiet", "summary")
+	rootCmd.PersistentFlags().StringVar(&workspaceName, "workspace", "", "Link into the named workspace's target, with its own state (or set GO4DOT_WORKSPACE)")
+	_ = rootCmd.RegisterFlagCompletionFunc("workspace", completeWorkspaces)
+	rootCmd.PersistentFlags().BoolVar(&noCache, "no-cache", false, "Look up dependency versions afresh instead of using cached results")
+	rootCmd.PersistentFlags().StringArrayVar(&envFlags, "env", nil, "Set KEY=VALUE for hooks, installers and git, over the config's env (repeatable)")
+	rootCmd.PersistentFlags().BoolVar(&readOnly, "read-only", false, "Refuse every action that changes the machine, for inspecting it safely (or set GO4DOT_READ_ONLY=1)")
 
 	// Set up PersistentPreRun to handle env vars and flag aliases
 	rootCmd.PersistentPreRun = func(cmd *cobra.Command, args []string) {
+		// Set first so that --quiet also covers the warnings below
+		ui.SetVerbosity(verbosityFromFlags(quietMode, summaryMode))
+		checkReadOnly(cmd)
+
+		// Load user preferences; a broken file should not block the CLI
+		if p, err := prefs.Load(); err != nil {
+			ui.Warning("Ignoring user preferences: %v", err)
+		} else {
+			userPrefs = p
+		}
+		applyPrefDefaults(cmd, userPrefs)
+		configureFeatures(userPrefs)
+
+		// Point HOME at the workspace's target before any command looks at it
+		if workspaceName == "" {
+			workspaceName = os.Getenv(workspace.EnvName)
+		}
+		if workspaceName != "" {
+			if err := activateWorkspace(workspaceName); err != nil {
+				ui.Error("%v", err)
+				os.Exit(exitError)
+			}
+		}
+		// The saved profile belongs to the workspace's state
+		applySavedProfile()
+		if err := applyEnvFlags(envFlags); err != nil {
+			ui.Error("%v", err)
+			os.Exit(exitError)
+		}
+		confirmTracker = prefs.NewConfirmTracker(userPrefs)
+		_ = ui.SetTheme(userPrefs.Theme)
+
 		// Check environment variables for non-interactive mode
 		if os.Getenv("GO4DOT_NON_INTERACTIVE") == "1" || os.Getenv("CI") == "true" {
 			nonInteractive = true
@@ -66,14 +129,102 @@ func init() {
 
 		// Propagate to ui package for use throughout the codebase
 		ui.SetNonInteractive(nonInteractive)
+
+		// Plain rendering is auto-detected from TERM unless --plain is given
+		if !cmd.Flags().Changed("plain") {
+			plainMode = ui.DetectPlain()
+		}
+		ui.SetPlain(plainMode)
+		ui.SetReducedMotion(userPrefs.ReducedMotion || ui.DetectReducedMotion())
+
+		deps.SetCacheEnabled(!noCache)
+		openLogFile(cmd)
 	}
 
 	rootCmd.AddCommand(versionCmd)
 }
 
+// applyEnvFlags exports the --env values, which the config's env can't
+// replace
+func applyEnvFlags(flags []string) error {
+	if len(flags) == 0 {
+		return nil
+	}
+	env := make(map[string]string, len(flags))
+	for _, f := range flags {
+		key, value, err := config.ParseEnvFlag(f)
+		if err != nil {
+			return err
+		}
+		env[key] = value
+	}
+	return config.SetEnvOverrides(env)
+}
+
+// applyConfigEnv exports the config's env for commands that run hooks,
+// installers or git. It is only applied once the config validates, so a repo
+// or a base it extends can't set HOME or go4dot's own variables.
+func applyConfigEnv(cfg *config.Config, repoRoot string) error {
+	if len(cfg.Env) == 0 {
+		return nil
+	}
+	if err := cfg.Validate(repoRoot); err != nil {
+		ui.Warning("Ignoring the config's env until the config is valid; run 'g4d config validate'")
+		return nil
+	}
+	return cfg.ApplyEnv()
+}
+
+// applyPrefDefaults sets flag values from user preferences for any flag the
+// command supports that was not given explicitly, so CLI flags always win.
+func applyPrefDefaults(cmd *cobra.Command, p *prefs.Preferences) {
+	defaults := map[string]bool{
+		"dry-run":         p.Defaults.DryRun,
+		"non-interactive": p.Defaults.NonInteractive,
+		"yes":             p.Defaults.Yes,
+		"verbose":         p.Defaults.Verbose,
+	}
+
+	for name, value := range defaults {
+		if !value {
+			continue
+		}
+		flag := cmd.Flags().Lookup(name)
+		if flag == nil || flag.Changed || flag.Value.Type() != "bool" {
+			continue
+		}
+		_ = flag.Value.Set("true")
+	}
+}
+
+// configureFeatures turns on the feature flags set in the preferences and
+// warns about deprecated preferences they use
+func configureFeatures(p *prefs.Preferences) {
+	feature.Configure(p.Features, ui.Deprecation)
+	if unknown := feature.Unknown(p.Features); len(unknown) > 0 {
+		ui.Warning("Ignoring unknown features in preferences: %s", strings.Join(unknown, ", "))
+	}
+	if p.Defaults.Yes {
+		feature.Deprecated(feature.DefaultsYes)
+	}
+}
+
+// verbosityFromFlags maps --quiet and --summary to an output level
+func verbosityFromFlags(quiet, summary bool) ui.Verbosity {
+	switch {
+	case quiet:
+		return ui.VerbosityQuiet
+	case summary:
+		return ui.VerbositySummary
+	default:
+		return ui.VerbosityNormal
+	}
+}
+
 func Execute() {
 	if err := rootCmd.Execute(); err != nil {
 		fmt.Fprintln(os.Stderr, err)
-		os.Exit(1)
+		exitLogged(1, err)
 	}
+	_ = transcript.Finish(exitOK, nil)
 }
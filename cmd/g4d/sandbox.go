@@ -0,0 +1,133 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+
+	"github.com/nvandessel/go4dot/internal/config"
+	"github.com/nvandessel/go4dot/internal/sandbox"
+	"github.com/nvandessel/go4dot/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+var sandboxCmd = &cobra.Command{
+	Use:   "sandbox [config-path]",
+	Short: "Preview an install in a throwaway home directory",
+	Long: `Copy the dotfiles repo into a temporary directory, point HOME at an empty
+directory next to it and run 'g4d install --auto' there. When it finishes,
+every link and file the install created is listed, then the sandbox is
+deleted. Your real home and state are never touched.
+
+System packages are never installed in the sandbox, as they would change the
+real machine: dependencies are skipped. Externals are cloned into the
+sandbox home, and machine configs are generated with their defaults. Hooks
+and installer commands do run, with HOME pointing at the sandbox.
+
+Use --keep to look around the sandbox afterwards.`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		keep, _ := cmd.Flags().GetBool("keep")
+		minimal, _ := cmd.Flags().GetBool("minimal")
+		skipExternal, _ := cmd.Flags().GetBool("skip-external")
+
+		var configPath string
+		var err error
+		if len(args) > 0 {
+			_, err = config.LoadFromPath(args[0])
+			configPath = args[0]
+		} else {
+			_, configPath, err = config.LoadFromDiscovery()
+		}
+		if err != nil {
+			ui.Error("Error loading config: %v", err)
+			os.Exit(exitError)
+		}
+		dotfilesPath, err := filepath.Abs(filepath.Dir(configPath))
+		if err != nil {
+			ui.Error("%v", err)
+			os.Exit(exitError)
+		}
+
+		self, err := os.Executable()
+		if err != nil {
+			ui.Error("Cannot locate the running binary: %v", err)
+			os.Exit(exitError)
+		}
+
+		sb, err := sandbox.New(dotfilesPath)
+		if err != nil {
+			ui.Error("%v", err)
+			os.Exit(exitError)
+		}
+		if !keep {
+			defer func() { _ = sb.Remove() }()
+		}
+		ui.Info("Sandbox home: %s", sb.Home)
+
+		installArgs := []string{"install", "--auto", "--skip-deps"}
+		if minimal {
+			installArgs = append(installArgs, "--minimal")
+		}
+		if skipExternal {
+			installArgs = append(installArgs, "--skip-external")
+		}
+		installArgs = append(installArgs, filepath.Join(sb.Dotfiles, filepath.Base(configPath)))
+
+		install := exec.Command(self, installArgs...)
+		install.Dir = sb.Home
+		install.Env = sb.Env(os.Environ())
+		install.Stdout, install.Stderr = os.Stdout, os.Stderr
+		installErr := install.Run()
+
+		var exitErr *exec.ExitError
+		if installErr != nil && !errors.As(installErr, &exitErr) {
+			ui.Error("Failed to run install: %v", installErr)
+			os.Exit(exitError)
+		}
+
+		changes, err := sb.Changes(dotfilesPath)
+		if err != nil {
+			ui.Warning("Could not list the sandbox home: %v", err)
+		}
+		printSandboxChanges(changes)
+
+		if keep {
+			ui.Info("Sandbox kept at %s", sb.Root)
+		}
+		if installErr != nil {
+			ui.Error("Install failed in the sandbox")
+			os.Exit(exitError)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(sandboxCmd)
+
+	sandboxCmd.Flags().Bool("keep", false, "Keep the sandbox instead of deleting it")
+	sandboxCmd.Flags().Bool("minimal", false, "Only install core configs, skip optional")
+	sandboxCmd.Flags().Bool("skip-external", false, "Skip external dependency cloning")
+}
+
+// printSandboxChanges lists what the install created in the sandbox home
+func printSandboxChanges(changes []sandbox.Change) {
+	ui.Section("Sandbox home")
+	if len(changes) == 0 {
+		ui.Println("  Nothing was created")
+		return
+	}
+	for _, c := range changes {
+		path := filepath.Join("~", c.Path)
+		if c.Dir {
+			path += "/"
+		}
+		if c.Target != "" {
+			ui.Printf("  %s -> %s\n", path, c.Target)
+		} else {
+			ui.Printf("  %s\n", path)
+		}
+	}
+	ui.Printf("\n%d entries\n", len(changes))
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/nvandessel/go4dot/internal/config"
+	"github.com/nvandessel/go4dot/internal/shellinit"
+	"github.com/spf13/cobra"
+)
+
+var shellInitCmd = &cobra.Command{
+	Use:   "shell-init <zsh|bash|fish>",
+	Short: "Print shell aliases and hooks for your dotfiles",
+	Long: `Print a snippet for your shell's startup file with go4dot aliases and hooks.
+
+The snippet sets G4D_DOTFILES to the dotfiles repo and defines:
+
+  cddots   cd into the dotfiles repo
+  g4ds     g4d sync
+  g4dst    g4d status
+  g4dd     g4d doctor
+
+plus any aliases under shell_integration.aliases in .go4dot.yaml. Unless
+shell_integration.dir_hook is off, it also warns when you cd into a
+directory managed by a config, such as ~/.config/nvim, so edits go to the
+repo on purpose rather than through the links.
+
+With shell_integration.enabled, 'g4d install' adds a line sourcing this to
+your rc file. To add it yourself:
+
+  zsh/bash:  eval "$(g4d shell-init zsh)"
+  fish:      g4d shell-init fish | source`,
+	Args:      cobra.MatchAll(cobra.ExactArgs(1), cobra.OnlyValidArgs),
+	ValidArgs: config.Shells,
+	Run: func(cmd *cobra.Command, args []string) {
+		// Everything but the snippet goes to stderr, as stdout is eval'd
+		cfg, configPath, err := config.LoadFromDiscovery()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error loading config: %v\n", err)
+			os.Exit(1)
+		}
+
+		home, err := os.UserHomeDir()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Cannot determine home directory: %v\n", err)
+			os.Exit(1)
+		}
+
+		snippet, err := shellinit.Snippet(args[0], cfg, filepath.Dir(configPath), home)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Print(snippet)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(shellInitCmd)
+}
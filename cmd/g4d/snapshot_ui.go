@@ -0,0 +1,98 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+	"github.com/nvandessel/go4dot/internal/platform"
+	"github.com/nvandessel/go4dot/internal/ui"
+	"github.com/nvandessel/go4dot/internal/ui/dashboard"
+	"github.com/spf13/cobra"
+)
+
+var snapshotUICmd = &cobra.Command{
+	Use:   "snapshot-ui",
+	Short: "Render the dashboard to a text or HTML file",
+	Long: `Render the dashboard once, as it looks after loading, and write it as plain
+text or as an HTML page that keeps the colors. Useful for sharing the state of
+a machine in chat or attaching it to an issue without a screenshot.
+
+The format follows the output file's extension (.html or .htm for HTML) unless
+--format is given. Without --output the snapshot is printed.
+
+  g4d snapshot-ui -o dashboard.html
+  g4d snapshot-ui --width 100 --height 30 | pbcopy
+
+Press ctrl+s in the dashboard to save a snapshot of what is on screen.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		output, _ := cmd.Flags().GetString("output")
+		format, _ := cmd.Flags().GetString("format")
+		width, _ := cmd.Flags().GetInt("width")
+		height, _ := cmd.Flags().GetInt("height")
+		demo, _ := cmd.Flags().GetBool("demo")
+
+		if format == "" {
+			format = snapshotFormat(output)
+		}
+		if width < 40 || height < 10 {
+			ui.Error("Snapshot size must be at least 40x10")
+			os.Exit(exitError)
+		}
+
+		var state dashboard.State
+		if demo {
+			state = dashboard.DemoState()
+		} else {
+			p, _ := platform.Detect()
+			_, _, state = loadDashboardState(p)
+		}
+
+		// Colors are chosen for the terminal the command runs in, which
+		// may be a pipe; the HTML page should look like a modern terminal
+		profile := lipgloss.ColorProfile()
+		if format == ui.SnapshotFormatHTML {
+			lipgloss.SetColorProfile(termenv.TrueColor)
+		}
+		view := dashboard.RenderSnapshot(state, width, height)
+		lipgloss.SetColorProfile(profile)
+
+		content, err := dashboard.FormatSnapshot(view, format)
+		if err != nil {
+			ui.Error("%v", err)
+			os.Exit(exitError)
+		}
+
+		if output == "" {
+			fmt.Print(content)
+			return
+		}
+		if err := os.WriteFile(output, []byte(content), 0644); err != nil {
+			ui.Error("Failed to write snapshot: %v", err)
+			os.Exit(exitError)
+		}
+		ui.Success("Wrote %s snapshot to %s", format, output)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(snapshotUICmd)
+	snapshotUICmd.Flags().StringP("output", "o", "", "Write the snapshot to a file instead of printing it")
+	snapshotUICmd.Flags().String("format", "", "Snapshot format: text or html (default from the output extension, else text)")
+	snapshotUICmd.Flags().Int("width", 120, "Width of the rendered dashboard")
+	snapshotUICmd.Flags().Int("height", 35, "Height of the rendered dashboard")
+	snapshotUICmd.Flags().Bool("demo", false, "Render the sample data of 'g4d demo'")
+}
+
+// snapshotFormat picks the snapshot format from the output file's extension
+func snapshotFormat(output string) string {
+	switch strings.ToLower(filepath.Ext(output)) {
+	case ".html", ".htm":
+		return ui.SnapshotFormatHTML
+	}
+	return ui.SnapshotFormatText
+}
@@ -0,0 +1,349 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/nvandessel/go4dot/internal/config"
+	"github.com/nvandessel/go4dot/internal/doctor"
+	"github.com/nvandessel/go4dot/internal/state"
+	"github.com/nvandessel/go4dot/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+var stateCmd = &cobra.Command{
+	Use:   "state",
+	Short: "Inspect and repair the go4dot state file",
+	Long: `Commands for the state file at ~/.config/go4dot/state.json.
+
+The state file records which configs are installed, symlink counts used for
+drift detection, and external dependencies. Use 'g4d state doctor' to find
+entries that no longer match reality and 'g4d state repair' to fix them.
+
+On shared machines, 'g4d state encrypt' keeps the state encrypted at rest with
+age; it is decrypted transparently whenever go4dot reads it.`,
+}
+
+var stateShowCmd = &cobra.Command{
+	Use:   "show",
+	Short: "Show the contents of the state file",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		jsonOutput, _ := cmd.Flags().GetBool("json")
+
+		st := loadStateOrExit()
+
+		if jsonOutput {
+			data, err := json.MarshalIndent(st, "", "  ")
+			if err != nil {
+				ui.Error("%v", err)
+				os.Exit(1)
+			}
+			fmt.Println(string(data))
+			return
+		}
+
+		printState(st)
+	},
+}
+
+var stateDoctorCmd = &cobra.Command{
+	Use:   "doctor [config-path]",
+	Short: "Check the state file for inconsistencies",
+	Long: `Compare the state file with .go4dot.yaml and the links on disk.
+
+Reports configs marked installed that have no links, configs or symlink counts
+left over from deleted configs, fully linked configs missing from state, and
+external dependencies that no longer exist. Exits with status 1 if any issue
+is found.`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		jsonOutput, _ := cmd.Flags().GetBool("json")
+
+		st := loadStateOrExit()
+		cfg, dotfilesPath := loadStateConfig(args)
+
+		issues := doctor.CheckState(st, cfg, dotfilesPath)
+
+		if jsonOutput {
+			if issues == nil {
+				issues = []doctor.StateIssue{}
+			}
+			data, err := json.MarshalIndent(issues, "", "  ")
+			if err != nil {
+				ui.Error("%v", err)
+				os.Exit(1)
+			}
+			fmt.Println(string(data))
+		} else if len(issues) == 0 {
+			ui.Success("State is consistent")
+		} else {
+			printStateIssues(issues)
+			fmt.Println()
+			fmt.Println(ui.SubtleStyle.Render("Fix with: g4d state repair"))
+		}
+
+		if len(issues) > 0 {
+			os.Exit(1)
+		}
+	},
+}
+
+var stateRepairCmd = &cobra.Command{
+	Use:   "repair [config-path]",
+	Short: "Reconcile the state file with reality",
+	Long:  "Fix the issues reported by 'g4d state doctor' and save the state file",
+	Args:  cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+
+		st := loadStateOrExit()
+		cfg, dotfilesPath := loadStateConfig(args)
+
+		issues := doctor.CheckState(st, cfg, dotfilesPath)
+		if len(issues) == 0 {
+			ui.Success("State is consistent, nothing to repair")
+			return
+		}
+
+		for _, issue := range issues {
+			prefix := "✓"
+			if dryRun {
+				prefix = "Would"
+			}
+			fmt.Printf("  %s %s: %s\n", prefix, issue.Subject, issue.Fix)
+		}
+
+		if dryRun {
+			return
+		}
+
+		doctor.RepairState(st, cfg, dotfilesPath, issues)
+		if err := st.Save(); err != nil {
+			ui.Error("Failed to save state: %v", err)
+			os.Exit(1)
+		}
+
+		fmt.Println()
+		ui.Success("Repaired %d issue(s)", len(issues))
+	},
+}
+
+var stateEncryptCmd = &cobra.Command{
+	Use:   "encrypt",
+	Short: "Encrypt the state file at rest with age",
+	Long: `Encrypt the state file with age, since it can hold sensitive machine
+answers such as emails and key IDs.
+
+The state is encrypted to the identity at $GO4DOT_AGE_IDENTITY, or
+~/.config/go4dot/age-identity.txt, which is generated with age-keygen if it
+doesn't exist. From then on go4dot decrypts the state when reading it and
+never writes it in plain text. Requires age and age-keygen on PATH.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		created, err := state.Encrypt()
+		if err != nil {
+			ui.Error("%v", err)
+			os.Exit(1)
+		}
+
+		identity, _ := state.IdentityPath()
+		if created {
+			ui.Warning("Created age identity %s. Back it up: the state can't be read without it.", identity)
+		}
+		ui.Success("State encrypted with %s", identity)
+	},
+}
+
+var stateDecryptCmd = &cobra.Command{
+	Use:   "decrypt",
+	Short: "Store the state file in plain text again",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := state.Decrypt(); err != nil {
+			if errors.Is(err, state.ErrNotEncrypted) {
+				ui.Info("State is not encrypted")
+				return
+			}
+			ui.Error("%v", err)
+			os.Exit(1)
+		}
+		ui.Success("State decrypted")
+	},
+}
+
+var stateHistoryCmd = &cobra.Command{
+	Use:   "history",
+	Short: "Show recent operations and where their time went",
+	Long: `List the last installs, syncs and other operations, newest first, with how
+long each step took, to find what makes them slow on a machine.
+
+Up to 50 operations are kept in ~/.config/go4dot/operation-history.json.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		jsonOutput, _ := cmd.Flags().GetBool("json")
+		limit, _ := cmd.Flags().GetInt("limit")
+
+		records, err := state.LoadOperationHistory()
+		if err != nil {
+			ui.Error("%v", err)
+			os.Exit(1)
+		}
+		if limit > 0 && len(records) > limit {
+			records = records[len(records)-limit:]
+		}
+
+		if jsonOutput {
+			if records == nil {
+				records = []state.OperationRecord{}
+			}
+			data, err := json.MarshalIndent(records, "", "  ")
+			if err != nil {
+				ui.Error("%v", err)
+				os.Exit(1)
+			}
+			fmt.Println(string(data))
+			return
+		}
+
+		if len(records) == 0 {
+			ui.Info("No operations recorded yet")
+			return
+		}
+		for i := len(records) - 1; i >= 0; i-- {
+			rec := records[i]
+			outcome := ui.SuccessStyle.Render("✓")
+			if !rec.Success {
+				outcome = ui.ErrorStyle.Render("✗")
+			}
+			fmt.Printf("%s %s %-14s %s\n", outcome, rec.StartedAt.Format("2006-01-02 15:04"), rec.Operation, state.FormatDuration(rec.Duration))
+			if len(rec.Steps) > 0 {
+				fmt.Printf("  %s\n", ui.SubtleStyle.Render(state.FormatTimings(rec.Steps)))
+			}
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(stateCmd)
+	stateCmd.AddCommand(stateShowCmd)
+	stateCmd.AddCommand(stateDoctorCmd)
+	stateCmd.AddCommand(stateRepairCmd)
+	stateCmd.AddCommand(stateEncryptCmd)
+	stateCmd.AddCommand(stateDecryptCmd)
+	stateCmd.AddCommand(stateHistoryCmd)
+
+	stateShowCmd.Flags().Bool("json", false, "Output state as JSON")
+	stateDoctorCmd.Flags().Bool("json", false, "Output issues as JSON")
+	stateRepairCmd.Flags().Bool("dry-run", false, "Show what would be repaired without saving")
+	stateHistoryCmd.Flags().Bool("json", false, "Output operations as JSON")
+	stateHistoryCmd.Flags().Int("limit", 10, "Number of operations to show (0 for all)")
+}
+
+// loadStateOrExit loads the state file, exiting if it is missing or unreadable
+func loadStateOrExit() *state.State {
+	st, err := state.Load()
+	if err != nil {
+		ui.Error("%v", err)
+		os.Exit(1)
+	}
+	if st == nil {
+		ui.Error("No state file found. Run 'g4d install' first.")
+		os.Exit(1)
+	}
+	return st
+}
+
+// loadStateConfig loads .go4dot.yaml from an explicit path or discovery
+func loadStateConfig(args []string) (*config.Config, string) {
+	var cfg *config.Config
+	var configPath string
+	var err error
+
+	if len(args) > 0 {
+		cfg, err = config.LoadFromPath(args[0])
+		configPath = args[0]
+	} else {
+		cfg, configPath, err = config.LoadFromDiscovery()
+	}
+	if err != nil {
+		ui.Error("Error loading config: %v", err)
+		os.Exit(1)
+	}
+
+	dotfilesPath, err := filepath.Abs(filepath.Dir(configPath))
+	if err != nil {
+		ui.Error("%v", err)
+		os.Exit(1)
+	}
+	return cfg, dotfilesPath
+}
+
+func printState(st *state.State) {
+	subtle := ui.SubtleStyle
+	timeFormat := "2006-01-02 15:04"
+
+	fmt.Printf("Dotfiles:     %s\n", st.DotfilesPath)
+	fmt.Printf("Version:      %s\n", st.Version)
+	fmt.Printf("Installed:    %s\n", st.InstalledAt.Format(timeFormat))
+	fmt.Printf("Last update:  %s\n", st.LastUpdate.Format(timeFormat))
+	if st.Platform.OS != "" {
+		fmt.Printf("Platform:     %s %s %s (%s)\n", st.Platform.OS, st.Platform.Distro, st.Platform.DistroVersion, st.Platform.PackageManager)
+	}
+
+	fmt.Println()
+	fmt.Printf("Configs (%d):\n", len(st.Configs))
+	for _, c := range st.Configs {
+		kind := "optional"
+		if c.IsCore {
+			kind = "core"
+		}
+		links := ""
+		if count, ok := st.GetSymlinkCount(c.Name); ok {
+			links = fmt.Sprintf(", %d files", count)
+		}
+		fmt.Printf("  %s %s\n", c.Name, subtle.Render(fmt.Sprintf("(%s%s, %s)", kind, links, c.InstalledAt.Format(timeFormat))))
+	}
+
+	if len(st.ExternalDeps) > 0 {
+		fmt.Println()
+		fmt.Printf("External (%d):\n", len(st.ExternalDeps))
+		for _, id := range sortedStateKeys(st.ExternalDeps) {
+			ext := st.ExternalDeps[id]
+			installed := "not installed"
+			if ext.Installed {
+				installed = "installed"
+			}
+			fmt.Printf("  %s %s\n", id, subtle.Render(fmt.Sprintf("(%s, %s)", installed, ext.Path)))
+		}
+	}
+
+	if len(st.MachineConfig) > 0 {
+		fmt.Println()
+		fmt.Printf("Machine configs (%d):\n", len(st.MachineConfig))
+		for _, id := range sortedStateKeys(st.MachineConfig) {
+			fmt.Printf("  %s %s\n", id, subtle.Render(st.MachineConfig[id].ConfigPath))
+		}
+	}
+}
+
+func printStateIssues(issues []doctor.StateIssue) {
+	fmt.Println(ui.WarningStyle.Render(fmt.Sprintf("Found %d issue(s):", len(issues))))
+	for _, issue := range issues {
+		fmt.Printf("  %s %s\n", ui.WarningStyle.Render("⚠"), issue.Message)
+		fmt.Printf("      %s\n", ui.SubtleStyle.Render("Fix: "+issue.Fix))
+	}
+}
+
+func sortedStateKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
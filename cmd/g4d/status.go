@@ -14,9 +14,21 @@ var statusCmd = &cobra.Command{
 	Long: `Display a quick overview of your dotfiles status.
 
 Shows platform info, config sync status, dependency health, and last sync time.
-Suitable for scripting with the --json flag.`,
+Suitable for scripting with the --json flag.
+
+With --repo, inspects a dotfiles repo before installing it: only its .go4dot.yaml
+is fetched (downloaded, or read from a shallow clone in a temp directory), and the
+report lists the configs that apply to this platform, the dependencies that are
+missing and what else install would clone, prompt for and run.`,
+	Example: `  g4d status
+  g4d status --json
+  g4d status --repo https://github.com/me/dotfiles`,
 	Run: func(cmd *cobra.Command, args []string) {
 		jsonOutput, _ := cmd.Flags().GetBool("json")
+		if repo, _ := cmd.Flags().GetString("repo"); repo != "" {
+			runRemoteStatus(repo, jsonOutput)
+			return
+		}
 		skipDeps, _ := cmd.Flags().GetBool("skip-deps")
 		skipDrift, _ := cmd.Flags().GetBool("skip-drift")
 
@@ -39,13 +51,51 @@ Suitable for scripting with the --json flag.`,
 		}
 
 		fmt.Print(output)
+
+		if code := statusExitCode(overview, getFailOn(cmd)); code != exitOK {
+			os.Exit(code)
+		}
 	},
 }
 
+// runRemoteStatus prints what installing the repo at repoURL would entail
+func runRemoteStatus(repoURL string, jsonOutput bool) {
+	overview, err := status.NewGatherer().GatherRemote(repoURL)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	output, err := status.RenderRemote(overview, status.RenderOptions{JSON: jsonOutput})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Print(output)
+}
+
+// statusExitCode returns the exit code for a status overview: conflicts
+// are reported as such and symlink loops as errors, while drift and missing
+// dependencies are warnings
+func statusExitCode(overview *status.Overview, level failOn) int {
+	var errs, warnings, conflicts int
+	for _, c := range overview.Configs {
+		conflicts += c.Conflicts
+		errs += len(c.LinkErrors)
+		if c.Status == status.SyncStatusDrifted {
+			warnings++
+		}
+	}
+	warnings += overview.Dependencies.Missing + overview.Dependencies.VersionMissing
+	return reportExitCode(errs, warnings, conflicts, level)
+}
+
 func init() {
 	rootCmd.AddCommand(statusCmd)
 
 	statusCmd.Flags().Bool("json", false, "Output status as JSON")
 	statusCmd.Flags().Bool("skip-deps", false, "Skip dependency checking (faster)")
 	statusCmd.Flags().Bool("skip-drift", false, "Skip drift detection (faster)")
+	statusCmd.Flags().String("repo", "", "Inspect the repo at this URL before installing it, without cloning it")
+	addFailOnFlag(statusCmd)
 }
@@ -44,7 +44,7 @@ var stowAddCmd = &cobra.Command{
 		// Find the config item
 		cfgItem := cfg.GetConfigByName(configName)
 		if cfgItem == nil {
-			fmt.Fprintf(os.Stderr, "Error: config '%s' not found\n", configName)
+			fmt.Fprintf(os.Stderr, "Error: %v\n", cfg.UnknownConfigError(configName))
 			os.Exit(1)
 		}
 
@@ -53,6 +53,7 @@ var stowAddCmd = &cobra.Command{
 
 		// Stow it
 		opts := stow.StowOptions{
+			Settings: cfg.Stow,
 			ProgressFunc: func(current, total int, msg string) {
 				if total > 0 && current > 0 {
 					fmt.Printf("[%d/%d] %s\n", current, total, msg)
@@ -62,7 +63,7 @@ var stowAddCmd = &cobra.Command{
 			},
 		}
 
-		err = stow.Stow(dotfilesPath, cfgItem.Path, opts)
+		err = stow.Stow(cfgItem.StowDir(dotfilesPath), cfgItem.Path, opts)
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 			os.Exit(1)
@@ -96,13 +97,14 @@ var stowRemoveCmd = &cobra.Command{
 
 		cfgItem := cfg.GetConfigByName(configName)
 		if cfgItem == nil {
-			fmt.Fprintf(os.Stderr, "Error: config '%s' not found\n", configName)
+			fmt.Fprintf(os.Stderr, "Error: %v\n", cfg.UnknownConfigError(configName))
 			os.Exit(1)
 		}
 
 		dotfilesPath := filepath.Dir(configPath)
 
 		opts := stow.StowOptions{
+			Settings: cfg.Stow,
 			ProgressFunc: func(current, total int, msg string) {
 				if total > 0 && current > 0 {
 					fmt.Printf("[%d/%d] %s\n", current, total, msg)
@@ -112,7 +114,7 @@ var stowRemoveCmd = &cobra.Command{
 			},
 		}
 
-		err = stow.Unstow(dotfilesPath, cfgItem.Path, opts)
+		err = stow.Unstow(cfgItem.StowDir(dotfilesPath), cfgItem.Path, opts)
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 			os.Exit(1)
@@ -146,6 +148,7 @@ var stowRefreshCmd = &cobra.Command{
 
 		// Restow all configs
 		opts := stow.StowOptions{
+			Settings: cfg.Stow,
 			ProgressFunc: func(current, total int, msg string) {
 				if total > 0 && current > 0 {
 					fmt.Printf("[%d/%d] %s\n", current, total, msg)
@@ -1,85 +1,323 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/charmbracelet/huh"
 	"github.com/nvandessel/go4dot/internal/config"
+	"github.com/nvandessel/go4dot/internal/deps"
+	"github.com/nvandessel/go4dot/internal/doctor"
+	"github.com/nvandessel/go4dot/internal/hooks"
+	"github.com/nvandessel/go4dot/internal/inventory"
+	"github.com/nvandessel/go4dot/internal/platform"
+	"github.com/nvandessel/go4dot/internal/prefs"
+	"github.com/nvandessel/go4dot/internal/setup"
+	"github.com/nvandessel/go4dot/internal/snapshot"
 	"github.com/nvandessel/go4dot/internal/state"
 	"github.com/nvandessel/go4dot/internal/stow"
 	"github.com/nvandessel/go4dot/internal/ui"
+	"github.com/nvandessel/go4dot/internal/vcs"
 	"github.com/spf13/cobra"
 )
 
 var syncCmd = &cobra.Command{
-	Use:   "sync [config-name]",
-	Short: "Sync symlinks for dotfiles configs",
-	Long: `Sync symlinks for all or specific dotfiles configurations.
+	Use:   "sync [config-name...]",
+	Short: "Link configs and install their dependencies and externals",
+	Long: `Run the full sync pipeline for all or specific dotfiles configurations.
 
-This command restows your dotfiles, creating any new symlinks for files
-that have been added to your configs. It's useful when you've added new
-files to a config (like a new Neovim plugin) and need to create symlinks.
+Sync restows your dotfiles like 'g4d link', then installs missing
+dependencies and clones missing external dependencies (plugins, themes)
+of the synced configs. Use 'g4d link' when only the symlinks need updating.
 
-Without arguments, syncs all configs. With a config name, syncs only that config.
+Dependencies are declared for the whole repo, so they are checked even when
+syncing a single config.
+
+Without arguments, syncs all configs. With config names, syncs only those
+configs; one that fails to link doesn't stop the others.
 
 Examples:
-  g4d sync           # Sync all configs
-  g4d sync nvim      # Sync only the nvim config
-  g4d sync -y        # Sync all without confirmation`,
+  g4d sync                # Sync all configs
+  g4d sync nvim           # Sync only the nvim config
+  g4d sync nvim tmux      # Sync the nvim and tmux configs
+  g4d sync -y             # Sync all without confirmation
+  g4d sync --skip-deps    # Link and clone externals, leave packages alone
+  g4d sync --adopt        # Move existing files in home into the repo, then link them
+  g4d sync --watch        # Sync, then keep relinking configs as files are added or removed
+  g4d sync --restow-all   # Restow every config, not only those that changed
+  g4d sync --dry-run      # Print the symlinks, packages and repos a sync would change
+
+Syncing all configs only restows those whose files were added, removed or
+renamed since the last sync, or whose links are missing or wrong; the
+others are left alone and reported as unchanged.
+
+Hooks set in the config run around linking: pre_sync hooks before it, and
+one that fails stops the sync; post_sync hooks once the configs are linked,
+dependencies installed and externals cloned. 'g4d link' doesn't run them.
+
+With --watch, g4d keeps running after the sync and relinks any config whose
+files are added or removed, logging each change. Edits to existing files
+need no relinking since they already show through their symlinks.`,
 	Run: runSync,
 }
 
+// syncOptions selects what a sync runs in addition to linking
+type syncOptions struct {
+	adopt        bool   // Adopt existing files in home into the repo before linking
+	restowAll    bool   // Restow configs that have not changed since the last sync too
+	jobs         int    // Configs restowed at once when syncing all; 0 for the default
+	full         bool   // Install missing dependencies and clone missing externals after linking
+	skipDeps     bool   // With full, leave dependencies alone
+	skipExternal bool   // With full, leave external dependencies alone
+	failOn       failOn // Least severe outcome that makes the command exit non-zero
+	strictGit    bool   // Refuse to run while the repo has uncommitted changes
+	dryRun       bool   // Print what would change instead of changing it
+	profile      string // Profile given with --profile, saved for later runs
+
+	// Watch mode: keep relinking changed configs after the initial run
+	watch    bool
+	debounce time.Duration
+}
+
+// verb names the operation in prompts and messages
+func (o syncOptions) verb() string {
+	if o.full {
+		return "Sync"
+	}
+	return "Link"
+}
+
 func init() {
 	rootCmd.AddCommand(syncCmd)
+
+	syncCmd.Flags().Bool("adopt", false, "Adopt existing files in home into the repo (like stow --adopt)")
+	addRestowAllFlag(syncCmd)
+	addJobsFlag(syncCmd)
+	syncCmd.Flags().Bool("skip-deps", false, "Skip installing missing dependencies")
+	syncCmd.Flags().Bool("skip-external", false, "Skip cloning missing external dependencies")
+	addStrictGitFlag(syncCmd)
+	addWatchFlags(syncCmd)
+	addFailOnFlag(syncCmd)
+	addDryRunFlag(syncCmd)
+	addProfileFlag(syncCmd)
+	addLogFileFlag(syncCmd)
+}
+
+// addRestowAllFlag adds --restow-all, which makes a sync of all configs
+// restow the ones that did not change too
+func addRestowAllFlag(cmd *cobra.Command) {
+	cmd.Flags().Bool("restow-all", false, "Restow every config, not only those changed since the last sync")
+}
+
+// addJobsFlag adds --jobs, which bounds how many configs a sync of all
+// configs restows at once
+func addJobsFlag(cmd *cobra.Command) {
+	cmd.Flags().IntP("jobs", "j", 0, "Restow up to this many independent configs at once (default: the parallelism preference, else one per CPU, up to 8)")
+}
+
+// readJobsFlag returns --jobs, or the parallelism preference when the flag
+// is not given. 0 leaves the choice to stow.DefaultJobs.
+func readJobsFlag(cmd *cobra.Command) int {
+	if flag := cmd.Flags().Lookup("jobs"); flag != nil && flag.Changed {
+		jobs, _ := cmd.Flags().GetInt("jobs")
+		return jobs
+	}
+	return userPrefs.Workers()
+}
+
+// addWatchFlags adds the flags for watch mode to cmd
+func addWatchFlags(cmd *cobra.Command) {
+	cmd.Flags().Bool("watch", false, "Keep running and relink configs whose files are added or removed")
+	cmd.Flags().Duration("debounce", stow.DefaultWatchDebounce, "With --watch, how long the repo must be quiet before relinking")
+}
+
+// readWatchFlags fills the watch mode options from cmd's flags
+func readWatchFlags(cmd *cobra.Command, opts *syncOptions) {
+	opts.watch, _ = cmd.Flags().GetBool("watch")
+	opts.debounce, _ = cmd.Flags().GetDuration("debounce")
 }
 
 func runSync(cmd *cobra.Command, args []string) {
+	opts := syncOptions{full: true}
+	opts.adopt, _ = cmd.Flags().GetBool("adopt")
+	opts.restowAll, _ = cmd.Flags().GetBool("restow-all")
+	opts.jobs = readJobsFlag(cmd)
+	opts.skipDeps, _ = cmd.Flags().GetBool("skip-deps")
+	opts.skipExternal, _ = cmd.Flags().GetBool("skip-external")
+	opts.strictGit, _ = cmd.Flags().GetBool("strict-git")
+	opts.dryRun, _ = cmd.Flags().GetBool("dry-run")
+	opts.profile = useProfileFlag(cmd)
+	readWatchFlags(cmd, &opts)
+	opts.failOn = getFailOn(cmd)
+	runSyncWithOptions(args, opts)
+}
+
+func runSyncWithOptions(args []string, opts syncOptions) {
 	// Load config
 	cfg, configPath, err := config.LoadFromDiscovery()
+	if err == nil {
+		err = applyConfigEnv(cfg, filepath.Dir(configPath))
+	}
+	if err == nil {
+		cfg, err = fetchBases(cfg, configPath, false)
+	}
 	if err != nil {
 		ui.Error("Failed to load config: %v", err)
-		os.Exit(1)
+		exitLogged(exitError, err)
 	}
 
 	dotfilesPath := filepath.Dir(configPath)
 
+	// A dry run changes nothing, so the repo state and preflight checks
+	// that guard against half-finished changes don't apply
+	if opts.dryRun {
+		if err := syncDryRun(cfg, dotfilesPath, args, opts); err != nil {
+			ui.Error("%v", err)
+			exitLogged(exitCodeFor(err, failOnError), err)
+		}
+		return
+	}
+
+	if opts.profile != "" {
+		if err := saveProfile(opts.profile); err != nil {
+			ui.Error("%v", err)
+			exitLogged(exitError, err)
+		}
+	}
+
+	if err := checkRepoState(dotfilesPath, opts); err != nil {
+		ui.Error("%v", err)
+		exitLogged(exitError, err)
+	}
+
+	if err := syncPreflight(cfg, dotfilesPath, args, opts); err != nil {
+		ui.Error("%v", err)
+		exitLogged(exitError, err)
+	}
+
 	// Load state
 	st, _ := state.Load()
 	if st == nil {
 		st = state.New()
 	}
 
-	// If a specific config is specified, sync just that one
+	// If configs are named, sync just those. Whatever the sync replaces or
+	// removes goes into one snapshot for 'g4d undo'.
+	endSnapshot := snapshot.Begin(strings.ToLower(opts.verb()))
 	if len(args) > 0 {
-		if err := syncSingleConfig(args[0], cfg, dotfilesPath, st); err != nil {
+		err = syncConfigs(args, cfg, dotfilesPath, st, opts)
+	} else {
+		err = syncAllConfigs(cfg, dotfilesPath, st, opts)
+	}
+	endSnapshot()
+	code := exitCodeFor(err, opts.failOn)
+	if code == exitOK || code == exitWarning {
+		recordMachine(cfg, dotfilesPath)
+	}
+	switch code {
+	case exitOK:
+	case exitWarning:
+		ui.Warning("%v", err)
+		exitLogged(code, err)
+	default:
+		ui.Error("%v", err)
+		exitLogged(code, err)
+	}
+
+	if opts.watch {
+		err := watchAndLink(configPath, st, args, stow.WatchOptions{
+			Interval: stow.DefaultWatchInterval,
+			Debounce: opts.debounce,
+		})
+		if err != nil {
 			ui.Error("%v", err)
 			os.Exit(1)
 		}
-		return
 	}
+}
 
-	// Sync all configs
-	if err := syncAllConfigs(cfg, dotfilesPath, st); err != nil {
-		ui.Error("%v", err)
-		os.Exit(1)
+// syncPreflight checks that the sync can finish before anything changes:
+// the directories the configs link into are writable, there is disk space
+// and, for a full sync, missing dependencies can get the root they need
+func syncPreflight(cfg *config.Config, dotfilesPath string, configNames []string, opts syncOptions) error {
+	configs := cfg.GetAllConfigs()
+	if len(configNames) > 0 {
+		configs = nil
+		for _, name := range configNames {
+			item := cfg.GetConfigByName(name)
+			if item == nil {
+				return nil // The sync reports the unknown name
+			}
+			configs = append(configs, *item)
+		}
 	}
+
+	pre := setup.PreflightOptions{Configs: configs, NoTerminal: !ui.IsInteractive(), Escalation: userPrefs.EscalationTool()}
+	if opts.full && !opts.skipDeps {
+		if p, err := platform.Detect(); err == nil {
+			pre.Deps = true
+			pre.Platform = p
+		}
+	}
+	return setup.Preflight(cfg, dotfilesPath, pre)
+}
+
+// addStrictGitFlag adds --strict-git, which makes the repo state check
+// block instead of warn
+func addStrictGitFlag(cmd *cobra.Command) {
+	cmd.Flags().Bool("strict-git", false, "Refuse to run while the dotfiles repo has uncommitted changes or an interrupted rebase or merge")
 }
 
-func syncSingleConfig(configName string, cfg *config.Config, dotfilesPath string, st *state.State) error {
-	// Find the config
-	var configItem *config.ConfigItem
-	for _, c := range cfg.GetAllConfigs() {
-		if c.Name == configName {
-			configItem = &c
-			break
+// checkRepoState warns when the dotfiles repo has uncommitted changes or
+// is mid-rebase or mid-merge, since linking then spreads half-finished
+// edits to the live configs. With --strict-git or the strict git_guard
+// preference it returns an error instead.
+func checkRepoState(dotfilesPath string, opts syncOptions) error {
+	mode := userPrefs.GitGuardMode()
+	if opts.strictGit {
+		mode = prefs.GitGuardStrict
+	}
+	if mode == prefs.GitGuardOff {
+		return nil
+	}
+
+	status, err := vcs.Check(dotfilesPath, inventory.DirName)
+	if err != nil {
+		ui.Warning("Could not check the repo for uncommitted changes: %v", err)
+		return nil
+	}
+	if status.Clean() {
+		return nil
+	}
+
+	if mode == prefs.GitGuardStrict {
+		msg := fmt.Sprintf("refusing to %s, dotfiles repo: %s. Commit or stash first, or finish the rebase or merge.",
+			strings.ToLower(opts.verb()), status.Summary())
+		for _, line := range status.Preview(10) {
+			msg += "\n  " + line
 		}
+		return errors.New(msg)
+	}
+
+	ui.Warning("Dotfiles repo: %s. Its files are linked as they are.", status.Summary())
+	for _, line := range status.Preview(10) {
+		ui.Printf("    %s\n", line)
 	}
+	return nil
+}
 
-	if configItem == nil {
-		return fmt.Errorf("config '%s' not found", configName)
+// syncConfigs links the named configs one after another, like the
+// dashboard's bulk sync, then installs what they need. A config that fails
+// to link does not stop the others.
+func syncConfigs(configNames []string, cfg *config.Config, dotfilesPath string, st *state.State, opts syncOptions) error {
+	items, err := lookupConfigs(cfg, configNames)
+	if err != nil {
+		return err
 	}
 
 	// Check what will be synced
@@ -88,32 +326,37 @@ func syncSingleConfig(configName string, cfg *config.Config, dotfilesPath string
 		return fmt.Errorf("failed to check drift: %w", err)
 	}
 
-	drift := summary.ResultByName(configName)
-
 	// Show what will be synced
-	if drift != nil && drift.HasDrift {
-		fmt.Printf("\nChanges to sync for %s:\n", configName)
-		for _, f := range drift.NewFiles {
-			fmt.Printf("  + %s (new)\n", f)
-		}
-		for _, f := range drift.ConflictFiles {
-			fmt.Printf("  ! %s (conflict)\n", f)
-		}
-		for _, f := range drift.MissingFiles {
-			fmt.Printf("  - %s (missing/orphaned)\n", f)
+	for _, item := range items {
+		drift := summary.ResultByName(item.Name)
+		if drift != nil && drift.HasDrift {
+			ui.Printf("\nChanges to sync for %s:\n", item.Name)
+			for _, f := range drift.NewFiles {
+				ui.Printf("  + %s (new)\n", f)
+			}
+			for _, f := range drift.ConflictFiles {
+				ui.Printf("  ! %s (conflict)\n", f)
+			}
+			for _, f := range drift.MissingFiles {
+				ui.Printf("  - %s (missing/orphaned)\n", f)
+			}
+			ui.Println()
+		} else {
+			ui.Printf("\n%s is already in sync.\n", item.Name)
 		}
-		fmt.Println()
-	} else {
-		fmt.Printf("\n%s is already in sync.\n", configName)
 	}
 
-	// Confirm unless non-interactive
-	if ui.IsInteractive() {
+	// Confirm unless non-interactive or the confirmation policy skips it
+	if ui.IsInteractive() && confirmTracker.ShouldConfirm(prefs.OpSync) {
+		title := fmt.Sprintf("%s %d config(s)?", opts.verb(), len(items))
+		if len(items) == 1 {
+			title = fmt.Sprintf("%s %s?", opts.verb(), items[0].Name)
+		}
 		var proceed bool
 		err := huh.NewForm(
 			huh.NewGroup(
 				huh.NewConfirm().
-					Title(fmt.Sprintf("Sync %s?", configName)).
+					Title(title).
 					Affirmative("Yes").
 					Negative("No").
 					Value(&proceed),
@@ -121,31 +364,74 @@ func syncSingleConfig(configName string, cfg *config.Config, dotfilesPath string
 		).Run()
 
 		if err != nil || !proceed {
-			fmt.Println("Sync cancelled.")
+			ui.Printf("%s cancelled.\n", opts.verb())
 			return nil
 		}
+		confirmTracker.Record(prefs.OpSync)
 	}
 
-	// Do the sync
-	err = stow.SyncSingle(dotfilesPath, configName, cfg, st, stow.StowOptions{
-		ProgressFunc: func(current, total int, msg string) {
-			if total > 0 && current > 0 {
-				fmt.Printf("  [%d/%d] %s\n", current, total, msg)
-			} else {
-				fmt.Printf("  %s\n", msg)
-			}
-		},
-	})
+	var names []string
+	for _, item := range items {
+		names = append(names, item.Name)
+	}
+	return runSyncPipeline(cfg, dotfilesPath, st, names, summary, opts)
+}
 
-	if err != nil {
-		return fmt.Errorf("failed to sync %s: %w", configName, err)
+// lookupConfigs returns the named configs without duplicates, or an error
+// naming every unknown one
+func lookupConfigs(cfg *config.Config, configNames []string) ([]config.ConfigItem, error) {
+	var items []config.ConfigItem
+	var unknown []error
+	seen := make(map[string]bool)
+	for _, name := range configNames {
+		item := cfg.GetConfigByName(name)
+		switch {
+		case item == nil:
+			unknown = append(unknown, cfg.UnknownConfigError(name))
+		case !seen[name]:
+			seen[name] = true
+			items = append(items, *item)
+		}
+	}
+	if len(unknown) > 0 {
+		return nil, errors.Join(unknown...)
 	}
+	return items, nil
+}
 
-	ui.Success("Synced %s", configName)
-	return nil
+// syncDryRun prints what syncing configNames, or all configs, would
+// change: the symlinks stow would create and, for a full sync, the missing
+// packages it would install and the repos it would clone
+func syncDryRun(cfg *config.Config, dotfilesPath string, configNames []string, opts syncOptions) error {
+	dry := setup.DryRunOptions{
+		Configs: cfg.GetAllConfigs(),
+		Adopt:   opts.adopt,
+		Links:   len(configNames) == 0,
+	}
+	if len(configNames) > 0 {
+		items, err := lookupConfigs(cfg, configNames)
+		if err != nil {
+			return err
+		}
+		dry.Configs = items
+	}
+	if opts.full {
+		dry.Deps = !opts.skipDeps
+		dry.HookStages = []string{config.HookPreSync, config.HookPostSync}
+		if !opts.skipExternal {
+			dry.External = cfg.GetExternalForConfigs(configNames)
+		}
+	}
+
+	result, err := setup.DryRun(cfg, dotfilesPath, dry)
+	if err != nil {
+		return err
+	}
+	printDryRun(result)
+	return dryRunError(result)
 }
 
-func syncAllConfigs(cfg *config.Config, dotfilesPath string, st *state.State) error {
+func syncAllConfigs(cfg *config.Config, dotfilesPath string, st *state.State, opts syncOptions) error {
 	// Check what will be synced
 	summary, err := stow.FullDriftCheck(cfg, dotfilesPath)
 	if err != nil {
@@ -157,41 +443,41 @@ func syncAllConfigs(cfg *config.Config, dotfilesPath string, st *state.State) er
 	// Show what will be synced
 	if summary.HasDrift() {
 		if len(drifted) > 0 {
-			fmt.Println("\nConfigs with changes:")
+			ui.Println("\nConfigs with changes:")
 			for _, r := range drifted {
-				fmt.Printf("  %s:\n", r.ConfigName)
+				ui.Printf("  %s:\n", r.ConfigName)
 				for _, f := range r.NewFiles {
-					fmt.Printf("    + %s (new)\n", f)
+					ui.Printf("    + %s (new)\n", f)
 				}
 				for _, f := range r.ConflictFiles {
-					fmt.Printf("    ! %s (conflict)\n", f)
+					ui.Printf("    ! %s (conflict)\n", f)
 				}
 				for _, f := range r.MissingFiles {
-					fmt.Printf("    - %s (missing/orphaned)\n", f)
+					ui.Printf("    - %s (missing/orphaned)\n", f)
 				}
 			}
 		}
 
 		if len(summary.RemovedConfigs) > 0 {
-			fmt.Println("\nRemoved configs still stowed:")
+			ui.Println("\nRemoved configs still stowed:")
 			for _, name := range summary.RemovedConfigs {
-				fmt.Printf("  - %s (removed from YAML)\n", name)
+				ui.Printf("  - %s (removed from YAML)\n", name)
 			}
 		}
-		fmt.Println()
+		ui.Println()
 	} else {
-		fmt.Println("\nAll configs are in sync.")
+		ui.Println("\nAll configs are in sync.")
 	}
 
 	allConfigs := cfg.GetAllConfigs()
 
-	// Confirm unless non-interactive
-	if ui.IsInteractive() {
+	// Confirm unless non-interactive or the confirmation policy skips it
+	if ui.IsInteractive() && confirmTracker.ShouldConfirm(prefs.OpSync) {
 		var proceed bool
 		err := huh.NewForm(
 			huh.NewGroup(
 				huh.NewConfirm().
-					Title(fmt.Sprintf("Sync %d config(s)?", len(allConfigs))).
+					Title(fmt.Sprintf("%s %d config(s)?", opts.verb(), len(allConfigs))).
 					Affirmative("Yes").
 					Negative("No").
 					Value(&proceed),
@@ -199,34 +485,227 @@ func syncAllConfigs(cfg *config.Config, dotfilesPath string, st *state.State) er
 		).Run()
 
 		if err != nil || !proceed {
-			fmt.Println("Sync cancelled.")
+			ui.Printf("%s cancelled.\n", opts.verb())
 			return nil
 		}
+		confirmTracker.Record(prefs.OpSync)
 	}
 
-	// Do the sync
-	result, err := stow.SyncAll(dotfilesPath, cfg, st, ui.IsInteractive(), stow.StowOptions{
+	return runSyncPipeline(cfg, dotfilesPath, st, nil, summary, opts)
+}
+
+// runSyncPipeline links configNames, or all configs when there are none,
+// with setup.Sync, the pipeline the dashboard runs too, printing its
+// progress. For a full sync, the synced configs' dependencies and externals
+// are installed between linking and the post_sync hooks. The error carries
+// the exit code: conflicts, then partial failures, then warnings.
+func runSyncPipeline(cfg *config.Config, dotfilesPath string, st *state.State, configNames []string, drift *stow.DriftSummary, opts syncOptions) error {
+	var depsErr error
+	var stage string
+	result, err := setup.Sync(context.Background(), cfg, dotfilesPath, st, configNames, setup.SyncOptions{
+		Full:        opts.full,
+		Adopt:       opts.adopt,
+		RestowAll:   opts.restowAll,
+		Jobs:        opts.jobs,
+		UseTrash:    userPrefs.TrashEnabled(),
+		Interactive: ui.IsInteractive(),
+		Drift:       drift,
 		ProgressFunc: func(current, total int, msg string) {
 			if total > 0 && current > 0 {
-				fmt.Printf("  [%d/%d] %s\n", current, total, msg)
+				ui.Printf("  [%d/%d] %s\n", current, total, msg)
 			} else {
-				fmt.Printf("  %s\n", msg)
+				ui.Printf("  %s\n", msg)
+			}
+		},
+		LinkedFunc: func(name string, err error) {
+			// Syncing all configs reports the counts instead
+			if err == nil && len(configNames) > 0 {
+				ui.Success("Linked %s", name)
+				ui.Summary("Configs", "%s linked", name)
+			}
+		},
+		HookStart: func(h hooks.Hook) {
+			if h.Stage != stage {
+				stage = h.Stage
+				ui.Printf("\nHooks (%s):\n", stage)
 			}
+			ui.Printf("  $ %s\n", h.Command)
+		},
+		HookOutput: func(h hooks.Hook, line string) {
+			ui.Printf("    %s\n", ui.SubtleStyle.Render(line))
+		},
+		AfterLink: func(result *setup.SyncResult) {
+			synced := result.Synced()
+			if !opts.full || len(synced) == 0 {
+				return
+			}
+			// All configs clone every top-level external
+			if len(configNames) == 0 {
+				synced = nil
+			}
+			depsErr = syncDepsAndExternal(cfg, dotfilesPath, synced, opts)
 		},
 	})
+	for _, f := range result.HooksFailed {
+		ui.Printf("  ✗ %s: %v\n", f.Hook.Command, f.Err)
+	}
+	if len(result.HooksRun) > 0 {
+		ui.Summary("Hooks", "%d run, %d failed", len(result.HooksRun), len(result.HooksFailed))
+	}
+	if err != nil {
+		if errors.Is(err, setup.ErrPreSyncHook) {
+			return fmt.Errorf("%w; nothing was linked", result.HooksErr())
+		}
+		return err
+	}
 
+	for _, name := range result.Skipped {
+		ui.Summary("Configs", "%s skipped", name)
+	}
+	if len(configNames) == 0 {
+		ui.Summary("Configs", "%s", stow.SyncResultSummary(&result.StowResult))
+		if result.Links != nil {
+			ui.Summary("Links", "%s", result.Links.Summary())
+		}
+	}
+
+	err = mostSevere(
+		syncLinkError(result, len(configNames) == 1),
+		depsErr,
+		withExitCode(exitPartial, result.HooksErr()),
+		runVerifyCommands(cfg, dotfilesPath, result.Synced()),
+	)
 	if err != nil {
-		return fmt.Errorf("sync operation failed: %w", err)
+		return err
+	}
+	if len(configNames) == 0 {
+		ui.Success("%s", stow.SyncResultSummary(&result.StowResult))
 	}
+	return nil
+}
 
+// syncLinkError describes the configs and links a sync failed to create,
+// or returns nil. Conflicting files in home make it exit with
+// exitConflicts; failures next to configs that did link are partial.
+func syncLinkError(result *setup.SyncResult, single bool) error {
 	if len(result.Failed) > 0 {
+		code := exitError
+		if len(result.Success) > 0 {
+			code = exitPartial
+		}
+		if len(result.Conflicted) > 0 {
+			code = exitConflicts
+		}
+		if single {
+			f := result.Failed[0]
+			return withExitCode(code, fmt.Errorf("failed to link %s: %w", f.ConfigName, f.Error))
+		}
 		var errs []string
 		for _, f := range result.Failed {
 			errs = append(errs, fmt.Sprintf("%s: %v", f.ConfigName, f.Error))
 		}
-		return fmt.Errorf("failed to sync %d config(s):\n  %s", len(result.Failed), strings.Join(errs, "\n  "))
+		return withExitCode(code, fmt.Errorf("failed to link %d config(s):\n  %s", len(result.Failed), strings.Join(errs, "\n  ")))
+	}
+	if result.Links != nil && len(result.Links.Failed) > 0 {
+		var errs []string
+		for _, f := range result.Links.Failed {
+			errs = append(errs, fmt.Sprintf("%s: %v", f.Target, f.Error))
+		}
+		return withExitCode(exitPartial, fmt.Errorf("failed to create %d link(s):\n  %s", len(result.Links.Failed), strings.Join(errs, "\n  ")))
+	}
+	return nil
+}
+
+// runVerifyCommands runs the verify commands of the linked configs that
+// have one and prints whether each works. Since the configs are linked, a
+// failed command is a warning.
+func runVerifyCommands(cfg *config.Config, dotfilesPath string, configNames []string) error {
+	if len(configNames) == 0 {
+		return nil
+	}
+	checks := doctor.RunFunctionalChecks(cfg, dotfilesPath, configNames)
+	if len(checks) == 0 {
+		return nil
+	}
+
+	ui.Println("\nVerify:")
+	for _, c := range checks {
+		if c.Status == doctor.StatusOK {
+			ui.Printf("  ✓ %s\n", c.Config)
+			continue
+		}
+		ui.Printf("  ✗ %s: %s %s\n", c.Config, c.Command, ui.SubtleStyle.Render("("+c.Message+")"))
+		for _, line := range strings.Split(c.Output, "\n") {
+			if line != "" {
+				ui.Printf("      %s\n", ui.SubtleStyle.Render(line))
+			}
+		}
+	}
+
+	failed := doctor.FailedFunctionalChecks(checks)
+	ui.Summary("Verify", "%d passed, %d failed", len(checks)-len(failed), len(failed))
+	if len(failed) > 0 {
+		var names []string
+		for _, c := range failed {
+			names = append(names, c.Config)
+		}
+		return withExitCode(exitWarning, fmt.Errorf("verify failed for %s", strings.Join(names, ", ")))
+	}
+	return nil
+}
+
+// syncDepsAndExternal runs the steps a full sync adds to linking: it
+// installs missing dependencies and clones the missing external dependencies
+// of configNames (all top-level ones when configNames is empty). Both steps
+// run even if the first fails. Since the configs are already linked, errors
+// are partial failures; dependencies left for manual installation are
+// warnings.
+func syncDepsAndExternal(cfg *config.Config, dotfilesPath string, configNames []string, opts syncOptions) error {
+	p, err := platform.Detect()
+	if err != nil {
+		return fmt.Errorf("failed to detect platform: %w", err)
 	}
 
-	ui.Success("Synced %d config(s)", len(result.Success))
+	var errs []string
+	var manual int
+
+	if !opts.skipDeps {
+		ui.Println("\nDependencies:")
+		manual, err = runDepsInstall(cfg, p, ui.Details())
+		if err != nil {
+			errs = append(errs, err.Error())
+		}
+	}
+
+	if !opts.skipExternal {
+		scoped := *cfg
+		scoped.External = cfg.GetExternalForConfigs(configNames)
+		if len(scoped.External) > 0 {
+			ui.Println("\nExternal dependencies:")
+			result, err := deps.CloneExternal(&scoped, p, deps.ExternalOptions{
+				RepoRoot: dotfilesPath,
+				ProgressFunc: func(current, total int, msg string) {
+					ui.Printf("  [%d/%d] %s\n", current, total, msg)
+				},
+			})
+			if err != nil {
+				errs = append(errs, fmt.Sprintf("external dependencies: %v", err))
+			} else {
+				ui.Summary("External", "%d cloned, %d skipped, %d failed",
+					len(result.Cloned), len(result.Skipped), len(result.Failed))
+				for _, f := range result.Failed {
+					errs = append(errs, fmt.Sprintf("%s: %v", f.Dep.Name, f.Error))
+				}
+			}
+		}
+	}
+
+	if len(errs) > 0 {
+		return withExitCode(exitPartial, fmt.Errorf("sync finished with errors:\n  %s", strings.Join(errs, "\n  ")))
+	}
+	ui.Success("Sync complete")
+	if manual > 0 {
+		return withExitCode(exitWarning, fmt.Errorf("%d dependencies need manual installation", manual))
+	}
 	return nil
 }
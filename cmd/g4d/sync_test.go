@@ -6,9 +6,11 @@ import (
 	"testing"
 
 	"github.com/nvandessel/go4dot/internal/config"
+	"github.com/nvandessel/go4dot/internal/prefs"
 	"github.com/nvandessel/go4dot/internal/state"
 	"github.com/nvandessel/go4dot/internal/stow"
 	"github.com/nvandessel/go4dot/internal/ui"
+	"github.com/spf13/cobra"
 )
 
 func TestSyncCommands(t *testing.T) {
@@ -65,7 +67,7 @@ func TestSyncCommands(t *testing.T) {
 		{
 			name: "syncAllConfigs",
 			fn: func(t *testing.T) {
-				err := syncAllConfigs(cfg, dotfilesPath, st)
+				err := syncAllConfigs(cfg, dotfilesPath, st, syncOptions{})
 				if err != nil {
 					t.Fatalf("syncAllConfigs failed: %v", err)
 				}
@@ -77,16 +79,16 @@ func TestSyncCommands(t *testing.T) {
 			},
 		},
 		{
-			name: "syncSingleConfig",
+			name: "syncConfigs",
 			fn: func(t *testing.T) {
 				// Add another file
 				if err := os.WriteFile(filepath.Join(pkg1Path, "test2.txt"), []byte("content2"), 0644); err != nil {
 					t.Fatal(err)
 				}
 
-				err := syncSingleConfig("pkg1", cfg, dotfilesPath, st)
+				err := syncConfigs([]string{"pkg1"}, cfg, dotfilesPath, st, syncOptions{})
 				if err != nil {
-					t.Fatalf("syncSingleConfig failed: %v", err)
+					t.Fatalf("syncConfigs failed: %v", err)
 				}
 
 				// Verify symlink
@@ -96,17 +98,142 @@ func TestSyncCommands(t *testing.T) {
 			},
 		},
 		{
-			name: "syncSingleConfig NotFound",
+			name: "syncConfigs adopt",
 			fn: func(t *testing.T) {
-				err := syncSingleConfig("nonexistent", cfg, dotfilesPath, st)
+				// A real file in home blocks the link for a new repo file
+				if err := os.WriteFile(filepath.Join(pkg1Path, "test3.txt"), []byte("repo"), 0644); err != nil {
+					t.Fatal(err)
+				}
+				if err := os.WriteFile(filepath.Join(homeDir, "test3.txt"), []byte("local"), 0644); err != nil {
+					t.Fatal(err)
+				}
+
+				if err := syncConfigs([]string{"pkg1"}, cfg, dotfilesPath, st, syncOptions{adopt: true}); err != nil {
+					t.Fatalf("syncConfigs with adopt failed: %v", err)
+				}
+
+				data, err := os.ReadFile(filepath.Join(pkg1Path, "test3.txt"))
+				if err != nil || string(data) != "local" {
+					t.Errorf("repo copy = %q, %v; want adopted content %q", data, err, "local")
+				}
+				info, err := os.Lstat(filepath.Join(homeDir, "test3.txt"))
+				if err != nil || info.Mode()&os.ModeSymlink == 0 {
+					t.Error("test3.txt should be a symlink after adopt")
+				}
+			},
+		},
+		{
+			name: "syncConfigs NotFound",
+			fn: func(t *testing.T) {
+				err := syncConfigs([]string{"pkg1", "nonexistent"}, cfg, dotfilesPath, st, syncOptions{})
 				if err == nil {
 					t.Error("expected error for nonexistent config, got nil")
 				}
 			},
 		},
+		{
+			name: "syncConfigs multiple",
+			fn: func(t *testing.T) {
+				pkg2Path := filepath.Join(dotfilesPath, "pkg2")
+				if err := os.MkdirAll(pkg2Path, 0755); err != nil {
+					t.Fatal(err)
+				}
+				if err := os.WriteFile(filepath.Join(pkg2Path, "other.txt"), []byte("other"), 0644); err != nil {
+					t.Fatal(err)
+				}
+				if err := os.WriteFile(filepath.Join(pkg1Path, "test4.txt"), []byte("content4"), 0644); err != nil {
+					t.Fatal(err)
+				}
+				cfg.Configs.Core = append(cfg.Configs.Core, config.ConfigItem{Name: "pkg2", Path: "pkg2"})
+
+				if err := syncConfigs([]string{"pkg1", "pkg2"}, cfg, dotfilesPath, st, syncOptions{}); err != nil {
+					t.Fatalf("syncConfigs failed: %v", err)
+				}
+
+				for _, f := range []string{"test4.txt", "other.txt"} {
+					if _, err := os.Lstat(filepath.Join(homeDir, f)); err != nil {
+						t.Errorf("%s not symlinked", f)
+					}
+				}
+				if !st.HasConfig("pkg2") {
+					t.Error("pkg2 should be recorded in state")
+				}
+			},
+		},
+		{
+			name: "hooks only for a full sync",
+			fn: func(t *testing.T) {
+				marker := filepath.Join(tmpDir, "hooked")
+				cfg.Hooks = config.Hooks{config.HookPostSync: {"touch " + marker}}
+				defer func() { cfg.Hooks = nil }()
+
+				if err := syncConfigs([]string{"pkg1"}, cfg, dotfilesPath, st, syncOptions{}); err != nil {
+					t.Fatalf("link failed: %v", err)
+				}
+				if _, err := os.Stat(marker); !os.IsNotExist(err) {
+					t.Error("link ran the post_sync hook")
+				}
+
+				full := syncOptions{full: true, skipDeps: true, skipExternal: true}
+				if err := syncConfigs([]string{"pkg1"}, cfg, dotfilesPath, st, full); err != nil {
+					t.Fatalf("sync failed: %v", err)
+				}
+				if _, err := os.Stat(marker); err != nil {
+					t.Error("sync did not run the post_sync hook")
+				}
+			},
+		},
+		{
+			name: "failed hook outranks manual dependencies",
+			fn: func(t *testing.T) {
+				cfg.Hooks = config.Hooks{config.HookPostSync: {"false"}}
+				cfg.Dependencies.Core = []config.DependencyItem{
+					{Name: "g4d-test-manual", Binary: "g4d-test-manual-missing", Manual: true},
+				}
+				defer func() {
+					cfg.Hooks = nil
+					cfg.Dependencies.Core = nil
+				}()
+
+				full := syncOptions{full: true, skipExternal: true}
+				err := syncConfigs([]string{"pkg1"}, cfg, dotfilesPath, st, full)
+				if got := exitCodeFor(err, failOnError); got != exitPartial {
+					t.Errorf("exit code = %d (%v), want %d for the failed post_sync hook", got, err, exitPartial)
+				}
+			},
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, tt.fn)
 	}
 }
+
+func TestReadJobsFlag(t *testing.T) {
+	origPrefs := userPrefs
+	defer func() { userPrefs = origPrefs }()
+
+	newCmd := func() *cobra.Command {
+		cmd := &cobra.Command{Use: "sync"}
+		addJobsFlag(cmd)
+		return cmd
+	}
+
+	userPrefs = &prefs.Preferences{}
+	if got := readJobsFlag(newCmd()); got != 0 {
+		t.Errorf("readJobsFlag() without a preference = %d, want 0 for stow.DefaultJobs", got)
+	}
+
+	userPrefs = &prefs.Preferences{Parallelism: 3}
+	if got := readJobsFlag(newCmd()); got != 3 {
+		t.Errorf("readJobsFlag() = %d, want the parallelism preference 3", got)
+	}
+
+	cmd := newCmd()
+	if err := cmd.Flags().Set("jobs", "1"); err != nil {
+		t.Fatal(err)
+	}
+	if got := readJobsFlag(cmd); got != 1 {
+		t.Errorf("readJobsFlag() with --jobs 1 = %d, want the flag to win", got)
+	}
+}
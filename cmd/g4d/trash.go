@@ -0,0 +1,81 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/nvandessel/go4dot/internal/trash"
+	"github.com/nvandessel/go4dot/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+var trashCmd = &cobra.Command{
+	Use:   "trash",
+	Short: "Inspect and restore files moved to the trash",
+	Long: `Commands for files go4dot moved to the OS trash.
+
+Files only end up here when use_trash is enabled in your preferences
+(~/.config/go4dot/config.yaml).`,
+}
+
+var trashListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List trashed files",
+	Long:  "List items in the trash along with where they were deleted from, newest first",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		t, err := trash.New()
+		if err != nil {
+			ui.Error("%v", err)
+			os.Exit(1)
+		}
+
+		items, err := t.List()
+		if err != nil {
+			ui.Error("%v", err)
+			os.Exit(1)
+		}
+
+		if len(items) == 0 {
+			fmt.Println("Trash is empty")
+			return
+		}
+
+		for _, item := range items {
+			fmt.Printf("  %s\n", item.Name)
+			fmt.Printf("    %s %s\n",
+				ui.SubtleStyle.Render(item.DeletedAt.Format("2006-01-02 15:04")),
+				item.OriginalPath)
+		}
+		fmt.Println()
+		fmt.Println(ui.SubtleStyle.Render("Restore with: g4d trash restore <name>"))
+	},
+}
+
+var trashRestoreCmd = &cobra.Command{
+	Use:   "restore <name>",
+	Short: "Restore a trashed file to its original location",
+	Long:  "Move a trashed item back to where it was deleted from. Existing files are never overwritten.",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		t, err := trash.New()
+		if err != nil {
+			ui.Error("%v", err)
+			os.Exit(1)
+		}
+
+		item, err := t.Restore(args[0])
+		if err != nil {
+			ui.Error("%v", err)
+			os.Exit(1)
+		}
+
+		ui.Success("Restored %s", item.OriginalPath)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(trashCmd)
+	trashCmd.AddCommand(trashListCmd)
+	trashCmd.AddCommand(trashRestoreCmd)
+}
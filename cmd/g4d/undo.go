@@ -0,0 +1,107 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/charmbracelet/huh"
+	"github.com/nvandessel/go4dot/internal/snapshot"
+	"github.com/nvandessel/go4dot/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+var undoCmd = &cobra.Command{
+	Use:   "undo [snapshot]",
+	Short: "Restore what the last sync or uninstall replaced or removed",
+	Long: `Put back the paths a sync, install or uninstall replaced or removed.
+
+Before one of these changes anything, the exact paths it is about to
+replace or remove are snapshotted: symlinks by where they point, rendered
+machine files by a copy, externals and directories by hardlinks. This
+restores the newest snapshot, or the one named, whether or not a conflict
+was detected. Use --list to see the snapshots.
+
+What is in the way of a restored path is snapshotted in turn, so an undo
+can be undone. Snapshots expire after 7 days, and at most 20 are kept.`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		list, _ := cmd.Flags().GetBool("list")
+
+		if list {
+			snapshots, err := snapshot.List()
+			if err != nil {
+				ui.Error("%v", err)
+				os.Exit(1)
+			}
+			if len(snapshots) == 0 {
+				ui.Info("No snapshots")
+				return
+			}
+			for _, s := range snapshots {
+				fmt.Printf("  %s  %s\n", s.ID, ui.SubtleStyle.Render(s.Describe()))
+			}
+			return
+		}
+
+		var id string
+		if len(args) > 0 {
+			id = args[0]
+		}
+		snap, err := snapshot.Get(id)
+		if errors.Is(err, snapshot.ErrNoSnapshot) {
+			ui.Info("Nothing to undo: no snapshot is kept")
+			return
+		}
+		if err != nil {
+			ui.Error("%v", err)
+			os.Exit(1)
+		}
+
+		fmt.Printf("Snapshot of %s:\n", snap.Describe())
+		for _, e := range snap.Entries {
+			fmt.Printf("  %-7s %s\n", e.Kind, e.Path)
+		}
+		fmt.Println()
+
+		if !dryRun && ui.IsInteractive() {
+			var proceed bool
+			err := huh.NewForm(
+				huh.NewGroup(
+					huh.NewConfirm().
+						Title("Restore these paths?").
+						Description("Whatever is at them now is snapshotted first.").
+						Affirmative("Restore").
+						Negative("Cancel").
+						Value(&proceed),
+				),
+			).Run()
+			if err != nil || !proceed {
+				fmt.Println("Undo cancelled.")
+				return
+			}
+		}
+
+		err = snapshot.Restore(snap, snapshot.RestoreOptions{
+			DryRun: dryRun,
+			ProgressFunc: func(current, total int, msg string) {
+				fmt.Printf("  %s\n", msg)
+			},
+		})
+		if err != nil {
+			ui.Error("%v", err)
+			os.Exit(1)
+		}
+
+		if !dryRun {
+			ui.Success("Restored %d path(s)", len(snap.Entries))
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(undoCmd)
+	undoCmd.Flags().Bool("dry-run", false, "Show what would change without changing anything")
+	undoCmd.Flags().Bool("list", false, "List the snapshots instead of restoring one")
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/charmbracelet/huh"
+	"github.com/nvandessel/go4dot/internal/config"
+	"github.com/nvandessel/go4dot/internal/setup"
+	"github.com/nvandessel/go4dot/internal/state"
+	"github.com/nvandessel/go4dot/internal/stow"
+	"github.com/nvandessel/go4dot/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+var undoConflictsCmd = &cobra.Command{
+	Use:   "undo-conflicts",
+	Short: "Undo the last conflict resolution",
+	Long: `Put back the files the last conflict resolution moved out of the way.
+
+Every file a g4d run backed up, trashed or deleted to make room for a link is
+recorded. This unlinks the configs those files belonged to and moves each
+file back where it was. Deleted files are kept for this until the next
+conflict resolution replaces the record.
+
+A file is never put back over anything but a symlink; files that can't be
+restored stay in the record so the command can be run again.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+
+		cfg, configPath, err := config.LoadFromDiscovery()
+		if err != nil {
+			ui.Error("Error loading config: %v", err)
+			os.Exit(1)
+		}
+
+		rec, err := stow.LoadUndoRecord()
+		if errors.Is(err, stow.ErrNothingToUndo) {
+			ui.Info("Nothing to undo: no conflict resolution is recorded")
+			return
+		}
+		if err != nil {
+			ui.Error("%v", err)
+			os.Exit(1)
+		}
+
+		fmt.Printf("Conflict resolution of %s:\n", rec.StartedAt.Format("2006-01-02 15:04"))
+		for _, e := range rec.Entries {
+			fmt.Printf("  %-7s %s %s\n", e.Action, e.TargetPath, ui.SubtleStyle.Render("("+e.ConfigName+")"))
+		}
+		fmt.Println()
+
+		if !dryRun && ui.IsInteractive() {
+			var proceed bool
+			err := huh.NewForm(
+				huh.NewGroup(
+					huh.NewConfirm().
+						Title("Restore these files?").
+						Description(fmt.Sprintf("The links of %d config(s) are removed first.", len(rec.Configs()))).
+						Affirmative("Restore").
+						Negative("Cancel").
+						Value(&proceed),
+				),
+			).Run()
+			if err != nil || !proceed {
+				fmt.Println("Undo cancelled.")
+				return
+			}
+		}
+
+		// Missing state is fine: there is nothing to update
+		st, _ := state.Load()
+
+		_, err = setup.UndoConflicts(cfg, filepath.Dir(configPath), st, setup.RefactorOptions{
+			DryRun: dryRun,
+			ProgressFunc: func(current, total int, msg string) {
+				fmt.Printf("  %s\n", msg)
+			},
+		})
+		if err != nil {
+			ui.Error("%v", err)
+			os.Exit(1)
+		}
+
+		if !dryRun {
+			ui.Success("Restored %d file(s); run 'g4d sync' to link the configs again", len(rec.Entries))
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(undoConflictsCmd)
+	undoConflictsCmd.Flags().Bool("dry-run", false, "Show what would change without changing anything")
+}
@@ -8,6 +8,7 @@ import (
 	"strings"
 
 	"github.com/nvandessel/go4dot/internal/config"
+	"github.com/nvandessel/go4dot/internal/prefs"
 	"github.com/nvandessel/go4dot/internal/setup"
 	"github.com/nvandessel/go4dot/internal/state"
 	"github.com/nvandessel/go4dot/internal/ui"
@@ -64,45 +65,22 @@ Note: This does NOT delete your dotfiles repository, only the symlinks.`,
 		force, _ := cmd.Flags().GetBool("force")
 		removeExternal, _ := cmd.Flags().GetBool("remove-external")
 		removeMachine, _ := cmd.Flags().GetBool("remove-machine")
+		yes, _ := cmd.Flags().GetBool("yes")
 
-		// Confirm unless --force
-		if !force {
-			fmt.Println("This will remove all dotfile symlinks from your home directory.")
-			if removeExternal {
-				fmt.Println("It will also remove external dependencies (plugins, themes, etc.)")
-			}
-			if removeMachine {
-				fmt.Println("It will also remove machine-specific config files.")
-			}
-			fmt.Print("\nAre you sure? [y/N] ")
-
-			reader := bufio.NewReader(os.Stdin)
-			response, _ := reader.ReadString('\n')
-			response = strings.TrimSpace(strings.ToLower(response))
-
-			if response != "y" && response != "yes" {
-				fmt.Println("Aborted.")
-				return
-			}
-			fmt.Println()
+		confirmed, err := confirmUninstall(yes, force, removeExternal, removeMachine)
+		if err != nil {
+			ui.Error("%v", err)
+			os.Exit(1)
+		}
+		if !confirmed {
+			fmt.Println("Aborted.")
+			return
 		}
 
 		fmt.Println("Uninstalling dotfiles...")
 		fmt.Printf("Directory: %s\n\n", dotfilesPath)
 
-		opts := setup.UninstallOptions{
-			RemoveExternal: removeExternal,
-			RemoveMachine:  removeMachine,
-			ProgressFunc: func(current, total int, msg string) {
-				if total > 0 && current > 0 {
-					fmt.Printf("  [%d/%d] %s\n", current, total, msg)
-				} else {
-					fmt.Println("  " + msg)
-				}
-			},
-		}
-
-		if err := setup.Uninstall(cfg, dotfilesPath, st, opts); err != nil {
+		if err := uninstallDotfiles(cfg, dotfilesPath, st, removeExternal, removeMachine); err != nil {
 			ui.Error("%v", err)
 			os.Exit(1)
 		}
@@ -112,10 +90,68 @@ Note: This does NOT delete your dotfiles repository, only the symlinks.`,
 	},
 }
 
+// confirmUninstall asks before uninstalling unless --force, --yes or the
+// confirmation policy skips it, and reports whether to go ahead. --yes only
+// covers removing the symlinks; removing externals or machine files without
+// asking takes --force.
+func confirmUninstall(yes, force, removeExternal, removeMachine bool) (bool, error) {
+	if force || !confirmTracker.ShouldConfirm(prefs.OpUninstall) {
+		return true, nil
+	}
+	if yes {
+		if removeExternal || removeMachine {
+			return false, fmt.Errorf("--remove-external and --remove-machine need --force to run without confirmation")
+		}
+		return true, nil
+	}
+	if !ui.IsInteractive() {
+		return false, fmt.Errorf("refusing to uninstall without confirmation in non-interactive mode; pass --yes, or --force to remove externals and machine files too")
+	}
+
+	fmt.Println("This will remove all dotfile symlinks from your home directory.")
+	if removeExternal {
+		fmt.Println("It will also remove external dependencies (plugins, themes, etc.)")
+	}
+	if removeMachine {
+		fmt.Println("It will also remove machine-specific config files.")
+	}
+	fmt.Print("\nAre you sure? [y/N] ")
+
+	reader := bufio.NewReader(os.Stdin)
+	response, _ := reader.ReadString('\n')
+	response = strings.TrimSpace(strings.ToLower(response))
+
+	if response != "y" && response != "yes" {
+		return false, nil
+	}
+	confirmTracker.Record(prefs.OpUninstall)
+	fmt.Println()
+	return true, nil
+}
+
+// uninstallDotfiles removes the symlinks and state of the dotfiles at
+// dotfilesPath, printing progress. The uninstall command and the
+// dashboard's uninstall action both go through it.
+func uninstallDotfiles(cfg *config.Config, dotfilesPath string, st *state.State, removeExternal, removeMachine bool) error {
+	opts := setup.UninstallOptions{
+		RemoveExternal: removeExternal,
+		RemoveMachine:  removeMachine,
+		UseTrash:       userPrefs.TrashEnabled(),
+		ProgressFunc: func(current, total int, msg string) {
+			if total > 0 && current > 0 {
+				fmt.Printf("  [%d/%d] %s\n", current, total, msg)
+			} else {
+				fmt.Println("  " + msg)
+			}
+		},
+	}
+	return setup.Uninstall(cfg, dotfilesPath, st, opts)
+}
+
 func init() {
 	rootCmd.AddCommand(uninstallCmd)
 
-	uninstallCmd.Flags().BoolP("force", "f", false, "Skip confirmation prompt")
+	uninstallCmd.Flags().BoolP("force", "f", false, "Skip confirmation, including for --remove-external and --remove-machine")
 	uninstallCmd.Flags().Bool("remove-external", false, "Also remove external dependencies")
 	uninstallCmd.Flags().Bool("remove-machine", false, "Also remove machine-specific config files")
 }
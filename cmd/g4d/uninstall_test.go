@@ -0,0 +1,44 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/nvandessel/go4dot/internal/prefs"
+	"github.com/nvandessel/go4dot/internal/ui"
+)
+
+func TestConfirmUninstall_NonInteractive(t *testing.T) {
+	// No TTY: nothing can be asked
+	defer ui.SetNonInteractive(false)
+	ui.SetNonInteractive(true)
+
+	origTracker := confirmTracker
+	confirmTracker = prefs.NewConfirmTracker(&prefs.Preferences{})
+	defer func() { confirmTracker = origTracker }()
+
+	tests := []struct {
+		name                          string
+		yes, force, external, machine bool
+		want                          bool
+		wantErr                       bool
+	}{
+		{name: "yes", yes: true, want: true},
+		{name: "force", force: true, want: true},
+		{name: "neither", wantErr: true},
+		{name: "yes with remove-external", yes: true, external: true, wantErr: true},
+		{name: "yes with remove-machine", yes: true, machine: true, wantErr: true},
+		{name: "force with extras", force: true, external: true, machine: true, want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := confirmUninstall(tt.yes, tt.force, tt.external, tt.machine)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("confirmUninstall() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("confirmUninstall() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -14,11 +14,11 @@ import (
 
 var updateCmd = &cobra.Command{
 	Use:   "update [config-path]",
-	Short: "Update dotfiles from git",
-	Long: `Pull latest changes from git and update dotfiles.
+	Short: "Update dotfiles from their repo",
+	Long: `Pull latest changes and update dotfiles.
 
 This command:
-1. Runs git pull in the dotfiles directory
+1. Pulls the dotfiles repo with git, jj or hg, whichever it uses
 2. Shows what files changed
 3. Restows all configs to apply changes
 4. Updates external dependencies (if --external flag is set)`,
@@ -57,6 +57,18 @@ This command:
 			dotfilesPath = filepath.Dir(dotfilesPath)
 		}
 
+		if err := applyConfigEnv(cfg, dotfilesPath); err != nil {
+			ui.Error("%v", err)
+			os.Exit(1)
+		}
+
+		// Bases are part of the config, so they are always pulled
+		cfg, err = fetchBases(cfg, filepath.Join(dotfilesPath, config.ConfigFileName), true)
+		if err != nil {
+			ui.Error("%v", err)
+			os.Exit(1)
+		}
+
 		updateExternal, _ := cmd.Flags().GetBool("external")
 		skipRestow, _ := cmd.Flags().GetBool("skip-restow")
 
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+	"time"
+
+	"github.com/nvandessel/go4dot/internal/config"
+	"github.com/nvandessel/go4dot/internal/state"
+	"github.com/nvandessel/go4dot/internal/stow"
+)
+
+// watchAndLink relinks configs whose files are added or removed until
+// interrupted, logging each change to stdout. The config file is re-read on
+// every scan so configs added to it are picked up too. With configNames set,
+// changes to other configs are ignored.
+func watchAndLink(configPath string, st *state.State, configNames []string, opts stow.WatchOptions) error {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	cfg, err := config.Load(configPath)
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	dotfilesPath := filepath.Dir(configPath)
+
+	scan := func() stow.PackageFiles {
+		// Keep the last good config while the file is mid-edit or invalid
+		if reloaded, err := config.Load(configPath); err == nil {
+			cfg = reloaded
+		}
+		files := stow.ScanPackages(cfg, dotfilesPath)
+		if len(configNames) > 0 {
+			only := make(stow.PackageFiles, len(configNames))
+			for _, name := range configNames {
+				only[name] = files[name]
+			}
+			return only
+		}
+		return files
+	}
+
+	fmt.Printf("\nWatching %s for changes (Ctrl+C to stop)...\n", dotfilesPath)
+
+	err = stow.Watch(ctx, scan, opts, func(changes []stow.ConfigChange) {
+		relinkChanges(os.Stdout, time.Now(), changes, cfg, dotfilesPath, st)
+	})
+	if err == context.Canceled {
+		fmt.Println("\nStopped watching.")
+		return nil
+	}
+	return err
+}
+
+// relinkChanges logs changes and relinks each changed config that is still
+// declared in cfg
+func relinkChanges(w io.Writer, now time.Time, changes []stow.ConfigChange, cfg *config.Config, dotfilesPath string, st *state.State) {
+	stamp := now.Format("15:04:05")
+	for _, change := range changes {
+		fmt.Fprintf(w, "[%s] %s:\n", stamp, change.ConfigName)
+		for _, f := range change.Added {
+			fmt.Fprintf(w, "  + %s\n", f)
+		}
+		for _, f := range change.Removed {
+			fmt.Fprintf(w, "  - %s\n", f)
+		}
+
+		if cfg.GetConfigByName(change.ConfigName) == nil {
+			fmt.Fprintf(w, "  %s was removed from the config; run 'g4d sync' to unlink it\n", change.ConfigName)
+			continue
+		}
+
+		if err := stow.SyncSingle(dotfilesPath, change.ConfigName, cfg, st, stow.StowOptions{}); err != nil {
+			fmt.Fprintf(w, "  failed to relink %s: %v\n", change.ConfigName, err)
+			continue
+		}
+		fmt.Fprintf(w, "  relinked %s\n", change.ConfigName)
+	}
+}
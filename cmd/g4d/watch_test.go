@@ -0,0 +1,67 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/nvandessel/go4dot/internal/config"
+	"github.com/nvandessel/go4dot/internal/state"
+	"github.com/nvandessel/go4dot/internal/stow"
+)
+
+func TestRelinkChanges(t *testing.T) {
+	origCommander := stow.CurrentCommander
+	stow.CurrentCommander = &stow.MockCommander{}
+	defer func() {
+		stow.CurrentCommander = origCommander
+	}()
+
+	tmpDir := t.TempDir()
+	dotfilesPath := filepath.Join(tmpDir, "dotfiles")
+	homeDir := filepath.Join(tmpDir, "home")
+	t.Setenv("HOME", homeDir)
+
+	pkgPath := filepath.Join(dotfilesPath, "zsh")
+	if err := os.MkdirAll(pkgPath, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(homeDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(pkgPath, ".zshenv"), []byte("export A=1"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := &config.Config{
+		Configs: config.ConfigGroups{
+			Core: []config.ConfigItem{{Name: "zsh", Path: "zsh"}},
+		},
+	}
+
+	changes := []stow.ConfigChange{
+		{ConfigName: "git", Removed: []string{".gitconfig"}},
+		{ConfigName: "zsh", Added: []string{".zshenv"}},
+	}
+
+	var out bytes.Buffer
+	now := time.Date(2024, 1, 2, 15, 4, 5, 0, time.UTC)
+	relinkChanges(&out, now, changes, cfg, dotfilesPath, state.New())
+
+	want := `[15:04:05] git:
+  - .gitconfig
+  git was removed from the config; run 'g4d sync' to unlink it
+[15:04:05] zsh:
+  + .zshenv
+  relinked zsh
+`
+	if got := out.String(); got != want {
+		t.Errorf("output =\n%s\nwant\n%s", got, want)
+	}
+
+	if _, err := os.Lstat(filepath.Join(homeDir, ".zshenv")); err != nil {
+		t.Errorf(".zshenv not symlinked: %v", err)
+	}
+}
@@ -0,0 +1,129 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/nvandessel/go4dot/internal/config"
+	"github.com/nvandessel/go4dot/internal/state"
+	"github.com/nvandessel/go4dot/internal/ui"
+	"github.com/nvandessel/go4dot/internal/ui/cli"
+	"github.com/nvandessel/go4dot/internal/workspace"
+	"github.com/spf13/cobra"
+)
+
+// workspaceName is the --workspace flag
+var workspaceName string
+
+var workspaceCmd = &cobra.Command{
+	Use:   "workspace",
+	Short: "Show the workspaces configs can be linked into",
+	Long: `Show the workspaces defined in .go4dot.yaml.
+
+A workspace links a profile's configs into a target root other than your home,
+e.g. a separate account's home or a ~/work-env prefix used through ZDOTDIR, and
+keeps its own state. Select one for any command with --workspace <name> or
+GO4DOT_WORKSPACE:
+
+  g4d sync --workspace work
+  g4d status --workspace work`,
+}
+
+var workspaceListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List workspaces with their targets and configs",
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg, _, err := config.LoadFromDiscovery()
+		if err != nil {
+			ui.Error("Error loading config: %v", err)
+			os.Exit(1)
+		}
+
+		if len(cfg.Workspaces) == 0 {
+			ui.Info("No workspaces defined. Add a 'workspaces' section to .go4dot.yaml.")
+			return
+		}
+
+		noHeader, _ := cmd.Flags().GetBool("no-header")
+		table := cli.NewTable(
+			cli.Column{Header: "NAME"},
+			cli.Column{Header: "TARGET"},
+			cli.Column{Header: "PROFILE"},
+			cli.Column{Header: "STATE"},
+			cli.Column{Header: "DESCRIPTION", Shrink: true},
+		)
+		table.NoHeader = noHeader
+
+		for _, ws := range cfg.Workspaces {
+			name := ws.Name
+			if ws.Name == workspace.Active() {
+				name += " *"
+			}
+			profile := ws.Profile
+			if profile == "" {
+				profile = "(all configs)"
+			}
+
+			stateCell := cli.Styled("not synced", ui.SubtleStyle)
+			if workspaceStateExists(ws.Name) {
+				stateCell = cli.Styled("synced", ui.SuccessStyle)
+			}
+
+			table.AddRow(cli.Text(name), cli.Text(ws.Target), cli.Text(profile), stateCell, cli.Text(ws.Description))
+		}
+		_ = table.Render(ui.Details())
+
+		ui.Summary("Workspaces", "%d defined", len(cfg.Workspaces))
+	},
+}
+
+// workspaceStateExists reports whether the named workspace has been synced
+func workspaceStateExists(name string) bool {
+	home, err := workspace.UserHome()
+	if err != nil {
+		return false
+	}
+	dir := filepath.Join(home, state.StateDir, state.WorkspacesDir, name)
+	for _, file := range []string{state.StateFileName, state.EncryptedFileName} {
+		if _, err := os.Stat(filepath.Join(dir, file)); err == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// activateWorkspace retargets this process at the named workspace, defined
+// in the discovered config
+func activateWorkspace(name string) error {
+	cfg, _, err := config.LoadFromDiscovery()
+	if err != nil {
+		return fmt.Errorf("workspaces are defined in .go4dot.yaml: %w", err)
+	}
+	if err := cfg.ApplyWorkspace(name); err != nil {
+		return err
+	}
+
+	target, err := workspace.ExpandTarget(cfg.GetWorkspace(name).Target)
+	if err != nil {
+		return err
+	}
+	return workspace.Activate(name, target)
+}
+
+// completeWorkspaces completes --workspace with the discovered config's
+// workspaces
+func completeWorkspaces(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	cfg, _, err := config.LoadFromDiscovery()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	return cfg.WorkspaceNames(), cobra.ShellCompDirectiveNoFileComp
+}
+
+func init() {
+	rootCmd.AddCommand(workspaceCmd)
+	workspaceCmd.AddCommand(workspaceListCmd)
+
+	workspaceListCmd.Flags().Bool("no-header", false, "Print only the table rows, without column headers")
+}
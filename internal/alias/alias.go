@@ -0,0 +1,119 @@
+// Package alias handles the names the go4dot binary answers to: the short
+// g4d and the long go4dot. Both run the same command tree; one is usually a
+// symlink to the other.
+package alias
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	// ShortName is the name go4dot is installed and documented as
+	ShortName = "g4d"
+	// LongName is the project name, kept as an alias
+	LongName = "go4dot"
+)
+
+// Names lists every name the binary answers to
+var Names = []string{ShortName, LongName}
+
+// Invoked returns the name the binary was run as, given os.Args[0]. Unknown
+// names, such as test binaries or renamed copies, fall back to ShortName.
+func Invoked(arg0 string) string {
+	name := strings.TrimSuffix(filepath.Base(arg0), ".exe")
+	for _, n := range Names {
+		if name == n {
+			return n
+		}
+	}
+	return ShortName
+}
+
+// Counterpart returns the other name of the binary
+func Counterpart(name string) string {
+	if name == ShortName {
+		return LongName
+	}
+	return ShortName
+}
+
+// Create links dir/name to target and returns the link's path. An existing
+// symlink is replaced; any other file is left alone unless force is set.
+func Create(target, dir, name string, force bool) (string, error) {
+	path := filepath.Join(dir, name)
+	if same, _ := sameFile(path, target); same {
+		return path, nil
+	}
+
+	if info, err := os.Lstat(path); err == nil {
+		if info.Mode()&os.ModeSymlink == 0 && !force {
+			return path, fmt.Errorf("%s exists and is not a symlink (use --force to replace it)", path)
+		}
+		if err := os.Remove(path); err != nil {
+			return path, fmt.Errorf("failed to replace %s: %w", path, err)
+		}
+	}
+
+	if err := os.Symlink(target, path); err != nil {
+		return path, fmt.Errorf("failed to create %s: %w", path, err)
+	}
+	return path, nil
+}
+
+// Resolution describes what a name runs when typed in a shell
+type Resolution struct {
+	Name    string
+	Path    string // Where the name resolves on PATH; empty if not found
+	Current bool   // The name runs the binary that is running now
+	Version string // Version the name reports, when it is another binary
+}
+
+// RunVersion runs a go4dot binary's version command and returns the version
+// it reports. It is a variable so tests can fake other binaries.
+var RunVersion = func(path string) (string, error) {
+	out, err := exec.Command(path, "version").Output()
+	if err != nil {
+		return "", err
+	}
+	line, _, _ := strings.Cut(string(out), "\n")
+	fields := strings.Fields(line)
+	if len(fields) < 2 {
+		return "", fmt.Errorf("unexpected version output %q", line)
+	}
+	return fields[1], nil
+}
+
+// Resolve looks name up on PATH and reports whether it runs self, the
+// running executable, or which version it runs instead
+func Resolve(name, self string) Resolution {
+	r := Resolution{Name: name}
+	path, err := exec.LookPath(name)
+	if err != nil {
+		return r
+	}
+	r.Path = path
+
+	if same, _ := sameFile(path, self); same {
+		r.Current = true
+		return r
+	}
+	r.Version, _ = RunVersion(path)
+	return r
+}
+
+// sameFile reports whether a and b are the same file once symlinks are followed
+func sameFile(a, b string) (bool, error) {
+	ai, err := os.Stat(a)
+	if err != nil {
+		return false, err
+	}
+	bi, err := os.Stat(b)
+	if err != nil {
+		return false, err
+	}
+	return os.SameFile(ai, bi), nil
+}
@@ -0,0 +1,97 @@
+package alias
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestInvoked(t *testing.T) {
+	tests := []struct {
+		arg0 string
+		want string
+	}{
+		{"g4d", ShortName},
+		{"/usr/local/bin/go4dot", LongName},
+		{"go4dot.exe", LongName},
+		{"./bin/g4d-linux-amd64", ShortName},
+		{"/tmp/go-build/g4d.test", ShortName},
+	}
+
+	for _, tt := range tests {
+		if got := Invoked(tt.arg0); got != tt.want {
+			t.Errorf("Invoked(%q) = %q, want %q", tt.arg0, got, tt.want)
+		}
+	}
+	if Counterpart(ShortName) != LongName || Counterpart(LongName) != ShortName {
+		t.Error("Counterpart should swap the two names")
+	}
+}
+
+// writeBinary writes an executable stand-in for the go4dot binary
+func writeBinary(t *testing.T, path string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte("#!/bin/sh\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestCreate(t *testing.T) {
+	dir := t.TempDir()
+	target := filepath.Join(dir, ShortName)
+	writeBinary(t, target)
+
+	path, err := Create(target, dir, LongName, false)
+	if err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	if dest, err := os.Readlink(path); err != nil || dest != target {
+		t.Errorf("%s -> %q, want %q", path, dest, target)
+	}
+
+	// Linking again is a no-op
+	if _, err := Create(target, dir, LongName, false); err != nil {
+		t.Errorf("Create on an existing alias failed: %v", err)
+	}
+
+	// A stale copy is only replaced with force
+	stale := t.TempDir()
+	writeBinary(t, filepath.Join(stale, LongName))
+	if _, err := Create(target, stale, LongName, false); err == nil {
+		t.Error("expected an error replacing a regular file without force")
+	}
+	if _, err := Create(target, stale, LongName, true); err != nil {
+		t.Errorf("Create with force failed: %v", err)
+	}
+	if info, err := os.Lstat(filepath.Join(stale, LongName)); err != nil || info.Mode()&os.ModeSymlink == 0 {
+		t.Error("expected the stale copy to be replaced by a symlink")
+	}
+}
+
+func TestResolve(t *testing.T) {
+	dir := t.TempDir()
+	self := filepath.Join(dir, ShortName)
+	writeBinary(t, self)
+	if err := os.Symlink(self, filepath.Join(dir, LongName)); err != nil {
+		t.Fatal(err)
+	}
+	other := t.TempDir()
+	writeBinary(t, filepath.Join(other, ShortName))
+
+	orig := RunVersion
+	RunVersion = func(path string) (string, error) { return "0.9.0", nil }
+	defer func() { RunVersion = orig }()
+
+	t.Setenv("PATH", dir)
+	if r := Resolve(LongName, self); !r.Current || r.Path == "" {
+		t.Errorf("Resolve(go4dot) = %+v, want the running binary", r)
+	}
+
+	t.Setenv("PATH", other)
+	if r := Resolve(ShortName, self); r.Current || r.Version != "0.9.0" {
+		t.Errorf("Resolve(g4d) = %+v, want another binary at 0.9.0", r)
+	}
+	if r := Resolve(LongName, self); r.Path != "" {
+		t.Errorf("Resolve(go4dot) = %+v, want not found", r)
+	}
+}
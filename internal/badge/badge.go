@@ -0,0 +1,125 @@
+// Package badge describes a dotfiles repo as shields.io badges, for READMEs.
+package badge
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/nvandessel/go4dot/internal/config"
+	"github.com/nvandessel/go4dot/internal/doctor"
+)
+
+// Badge names, also used as the file names of the endpoint JSON
+const (
+	NameConfigs   = "configs"
+	NamePlatforms = "platforms"
+	NameDoctor    = "doctor"
+)
+
+// Names lists every badge, in display order
+var Names = []string{NameConfigs, NamePlatforms, NameDoctor}
+
+// Badge is a shields.io endpoint response
+// (https://shields.io/badges/endpoint-badge)
+type Badge struct {
+	Name          string `json:"-"`
+	SchemaVersion int    `json:"schemaVersion"`
+	Label         string `json:"label"`
+	Message       string `json:"message"`
+	Color         string `json:"color"`
+}
+
+func newBadge(name, label, message, color string) Badge {
+	return Badge{Name: name, SchemaVersion: 1, Label: label, Message: message, Color: color}
+}
+
+// Configs describes how many configs the repo has
+func Configs(cfg *config.Config) Badge {
+	return newBadge(NameConfigs, "configs", fmt.Sprintf("%d", len(cfg.GetAllConfigs())), "blue")
+}
+
+// Platforms lists the platforms the repo's configs are restricted to, or
+// "any" when none of them is
+func Platforms(cfg *config.Config) Badge {
+	seen := make(map[string]bool)
+	for _, c := range cfg.GetAllConfigs() {
+		for _, p := range c.Platforms {
+			seen[p] = true
+		}
+	}
+	if len(seen) == 0 {
+		return newBadge(NamePlatforms, "platforms", "any", "informational")
+	}
+
+	platforms := make([]string, 0, len(seen))
+	for p := range seen {
+		platforms = append(platforms, p)
+	}
+	sort.Strings(platforms)
+	return newBadge(NamePlatforms, "platforms", strings.Join(platforms, " | "), "informational")
+}
+
+// Doctor describes the result of the last doctor run, or "unknown" when
+// there is none
+func Doctor(s *doctor.Summary) Badge {
+	if s == nil {
+		return newBadge(NameDoctor, "doctor", "unknown", "lightgrey")
+	}
+
+	switch s.Status {
+	case doctor.StatusError:
+		return newBadge(NameDoctor, "doctor", plural(s.Errors, "error"), "red")
+	case doctor.StatusWarning:
+		return newBadge(NameDoctor, "doctor", plural(s.Warnings, "warning"), "yellow")
+	default:
+		return newBadge(NameDoctor, "doctor", "passing", "brightgreen")
+	}
+}
+
+// All returns every badge for cfg, with s as the last doctor run (or nil)
+func All(cfg *config.Config, s *doctor.Summary) []Badge {
+	return []Badge{Configs(cfg), Platforms(cfg), Doctor(s)}
+}
+
+// Markdown returns image links for badges. With a baseURL, where the
+// endpoint JSON files are published as <name>.json, the links use the
+// shields.io endpoint so the badges follow later CI runs. Without one, they
+// are static badges of the current values.
+func Markdown(badges []Badge, baseURL string) string {
+	var sb strings.Builder
+	for i, b := range badges {
+		if i > 0 {
+			sb.WriteString(" ")
+		}
+		fmt.Fprintf(&sb, "![%s](%s)", b.Label, imageURL(b, baseURL))
+	}
+	sb.WriteString("\n")
+	return sb.String()
+}
+
+// imageURL returns the shields.io image URL for b
+func imageURL(b Badge, baseURL string) string {
+	if baseURL != "" {
+		endpoint := strings.TrimSuffix(baseURL, "/") + "/" + b.Name + ".json"
+		return "https://img.shields.io/endpoint?url=" + url.QueryEscape(endpoint)
+	}
+	return fmt.Sprintf("https://img.shields.io/badge/%s-%s-%s",
+		staticPart(b.Label), staticPart(b.Message), url.PathEscape(b.Color))
+}
+
+// staticPart escapes text for a static badge URL, where - and _ separate
+// the parts and must be doubled
+func staticPart(s string) string {
+	s = strings.ReplaceAll(s, "-", "--")
+	s = strings.ReplaceAll(s, "_", "__")
+	return url.PathEscape(s)
+}
+
+func plural(n int, word string) string {
+	if n == 1 {
+		return fmt.Sprintf("1 %s", word)
+	}
+	return fmt.Sprintf("%d %ss", n, word)
+}
@@ -0,0 +1,62 @@
+package badge
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/nvandessel/go4dot/internal/config"
+	"github.com/nvandessel/go4dot/internal/doctor"
+)
+
+func TestConfigsAndPlatforms(t *testing.T) {
+	cfg := &config.Config{}
+	cfg.Configs.Core = []config.ConfigItem{{Name: "git"}, {Name: "hypr", Platforms: []string{"linux"}}}
+	cfg.Configs.Optional = []config.ConfigItem{{Name: "aerospace", Platforms: []string{"macos", "linux"}}}
+
+	if b := Configs(cfg); b.Message != "3" || b.SchemaVersion != 1 {
+		t.Errorf("Configs() = %+v", b)
+	}
+	if b := Platforms(cfg); b.Message != "linux | macos" {
+		t.Errorf("Platforms().Message = %q, want %q", b.Message, "linux | macos")
+	}
+	if b := Platforms(&config.Config{}); b.Message != "any" {
+		t.Errorf("Platforms() without restrictions = %q, want any", b.Message)
+	}
+}
+
+func TestDoctor(t *testing.T) {
+	tests := []struct {
+		name    string
+		summary *doctor.Summary
+		message string
+		color   string
+	}{
+		{"no run", nil, "unknown", "lightgrey"},
+		{"passing", &doctor.Summary{Status: doctor.StatusOK}, "passing", "brightgreen"},
+		{"warning", &doctor.Summary{Status: doctor.StatusWarning, Warnings: 1}, "1 warning", "yellow"},
+		{"errors", &doctor.Summary{Status: doctor.StatusError, Errors: 2, Warnings: 1}, "2 errors", "red"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := Doctor(tt.summary)
+			if b.Message != tt.message || b.Color != tt.color {
+				t.Errorf("Doctor() = %q/%q, want %q/%q", b.Message, b.Color, tt.message, tt.color)
+			}
+		})
+	}
+}
+
+func TestMarkdown(t *testing.T) {
+	badges := []Badge{newBadge(NamePlatforms, "platforms", "linux | mac-os", "informational")}
+
+	static := Markdown(badges, "")
+	if want := "![platforms](https://img.shields.io/badge/platforms-linux%20%7C%20mac--os-informational)"; !strings.Contains(static, want) {
+		t.Errorf("static Markdown = %q, want %q", static, want)
+	}
+
+	endpoint := Markdown(badges, "https://example.com/badges/")
+	if want := "https://img.shields.io/endpoint?url=https%3A%2F%2Fexample.com%2Fbadges%2Fplatforms.json"; !strings.Contains(endpoint, want) {
+		t.Errorf("endpoint Markdown = %q, want %q", endpoint, want)
+	}
+}
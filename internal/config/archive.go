@@ -0,0 +1,30 @@
+package config
+
+// setAsideArchived moves configs marked archived: true out of configs.core
+// and configs.optional into Archived, so sync, doctor and status skip them
+// while they stay in the repo and the YAML
+func (c *Config) setAsideArchived() {
+	split := func(items []ConfigItem) []ConfigItem {
+		var active []ConfigItem
+		for _, item := range items {
+			if item.Archived {
+				c.Archived = append(c.Archived, item)
+				continue
+			}
+			active = append(active, item)
+		}
+		return active
+	}
+	c.Configs.Core = split(c.Configs.Core)
+	c.Configs.Optional = split(c.Configs.Optional)
+}
+
+// GetArchivedConfig returns an archived config by name, or nil
+func (c *Config) GetArchivedConfig(name string) *ConfigItem {
+	for i := range c.Archived {
+		if c.Archived[i].Name == name {
+			return &c.Archived[i]
+		}
+	}
+	return nil
+}
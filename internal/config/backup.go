@@ -0,0 +1,135 @@
+package config
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/nvandessel/go4dot/internal/workspace"
+)
+
+// BackupsDirName is the directory under ~/.config/go4dot that keeps the
+// last good contents of each repo's .go4dot.yaml
+const BackupsDirName = "config-backups"
+
+// MaxBackups is how many distinct good versions of a config are kept
+const MaxBackups = 10
+
+// backupTimeFormat names backup files so they sort oldest first
+const backupTimeFormat = "20060102-150405.000000000"
+
+// Backup is a saved copy of a .go4dot.yaml that loaded and validated
+type Backup struct {
+	Path    string    // The backup file
+	SavedAt time.Time // When the config was last seen good with this content
+}
+
+// BackupDir returns the directory the backups of the config at configPath
+// are kept in; each repo has its own
+func BackupDir(configPath string) (string, error) {
+	home, err := workspace.UserHome()
+	if err != nil {
+		return "", err
+	}
+	abs, err := filepath.Abs(configPath)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256([]byte(abs))
+	return filepath.Join(home, ".config", "go4dot", BackupsDirName, hex.EncodeToString(sum[:6])), nil
+}
+
+// BackupConfig saves the contents of the config at configPath as its newest
+// good version, unless it equals the newest backup, and drops all but the
+// last MaxBackups. Callers back up only configs that loaded and validated.
+func BackupConfig(configPath string) error {
+	data, err := os.ReadFile(configPath)
+	if err != nil {
+		return err
+	}
+	dir, err := BackupDir(configPath)
+	if err != nil {
+		return err
+	}
+
+	backups, err := ListBackups(configPath)
+	if err != nil {
+		return err
+	}
+	if len(backups) > 0 {
+		if newest, err := os.ReadFile(backups[0].Path); err == nil && bytes.Equal(newest, data) {
+			return nil
+		}
+	}
+
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return fmt.Errorf("failed to create backup directory: %w", err)
+	}
+	name := time.Now().UTC().Format(backupTimeFormat) + ".yaml"
+	if err := os.WriteFile(filepath.Join(dir, name), data, 0600); err != nil {
+		return fmt.Errorf("failed to back up config: %w", err)
+	}
+
+	// Keep the newest MaxBackups, counting the one just written
+	for i := MaxBackups - 1; i < len(backups); i++ {
+		_ = os.Remove(backups[i].Path)
+	}
+	return nil
+}
+
+// ListBackups returns the backups of the config at configPath, newest first
+func ListBackups(configPath string) ([]Backup, error) {
+	dir, err := BackupDir(configPath)
+	if err != nil {
+		return nil, err
+	}
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var backups []Backup
+	for _, e := range entries {
+		savedAt, err := time.Parse(backupTimeFormat, strings.TrimSuffix(e.Name(), ".yaml"))
+		if e.IsDir() || err != nil {
+			continue
+		}
+		backups = append(backups, Backup{Path: filepath.Join(dir, e.Name()), SavedAt: savedAt})
+	}
+	sort.Slice(backups, func(i, j int) bool { return backups[i].SavedAt.After(backups[j].SavedAt) })
+	return backups, nil
+}
+
+// RestoreBackup replaces the config at configPath with a backup of it
+func RestoreBackup(configPath string, backup Backup) error {
+	data, err := os.ReadFile(backup.Path)
+	if err != nil {
+		return fmt.Errorf("failed to read backup: %w", err)
+	}
+	if _, err := Parse(data); err != nil {
+		return fmt.Errorf("backup %s is not a valid config: %w", filepath.Base(backup.Path), err)
+	}
+
+	mode := os.FileMode(0644)
+	if info, err := os.Stat(configPath); err == nil {
+		mode = info.Mode().Perm()
+	}
+	tmp := configPath + ".restore"
+	if err := os.WriteFile(tmp, data, mode); err != nil {
+		return fmt.Errorf("failed to restore config: %w", err)
+	}
+	if err := os.Rename(tmp, configPath); err != nil {
+		_ = os.Remove(tmp)
+		return fmt.Errorf("failed to restore config: %w", err)
+	}
+	return nil
+}
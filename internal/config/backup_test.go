@@ -0,0 +1,69 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestBackupConfig(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	configPath := filepath.Join(t.TempDir(), ConfigFileName)
+
+	write := func(content string) {
+		t.Helper()
+		if err := os.WriteFile(configPath, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	backup := func() {
+		t.Helper()
+		if err := BackupConfig(configPath); err != nil {
+			t.Fatalf("BackupConfig() error = %v", err)
+		}
+	}
+
+	write("schema_version: \"1.0\"\nmetadata:\n  name: v0\n")
+	backup()
+	backup() // Unchanged content is not saved twice
+	backups, err := ListBackups(configPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(backups) != 1 {
+		t.Fatalf("got %d backups, want 1", len(backups))
+	}
+
+	// Only the newest MaxBackups are kept
+	for i := 1; i <= MaxBackups+2; i++ {
+		write("schema_version: \"1.0\"\nmetadata:\n  name: v" + string(rune('a'+i)) + "\n")
+		backup()
+	}
+	backups, err = ListBackups(configPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(backups) != MaxBackups {
+		t.Fatalf("got %d backups, want %d", len(backups), MaxBackups)
+	}
+	newest, err := os.ReadFile(backups[0].Path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	current, _ := os.ReadFile(configPath)
+	if string(newest) != string(current) {
+		t.Errorf("newest backup = %q, want the current config", newest)
+	}
+
+	// A bad edit is rolled back to the newest backup
+	write("configs: [\n")
+	if err := RestoreBackup(configPath, backups[0]); err != nil {
+		t.Fatalf("RestoreBackup() error = %v", err)
+	}
+	if _, err := LoadFile(configPath); err != nil {
+		t.Errorf("restored config does not load: %v", err)
+	}
+	if _, err := os.Stat(configPath + ".restore"); !os.IsNotExist(err) {
+		t.Error("RestoreBackup() left its temp file behind")
+	}
+}
@@ -0,0 +1,50 @@
+package config
+
+import "strings"
+
+// Conflict strategies decide what happens to existing files in home that
+// block a config from being linked
+const (
+	ConflictBackup    = "backup"    // Rename the files to .g4d-backup and link
+	ConflictSkip      = "skip"      // Leave the files alone and don't link the config
+	ConflictOverwrite = "overwrite" // Delete the files (to the trash if enabled) and link
+	ConflictAsk       = "ask"       // Ask when interactive; fail the config otherwise
+)
+
+// ConflictStrategies lists the valid on_conflict values
+var ConflictStrategies = []string{ConflictBackup, ConflictSkip, ConflictOverwrite, ConflictAsk}
+
+// IsConflictStrategy reports whether s is a valid on_conflict value. Empty
+// means ask.
+func IsConflictStrategy(s string) bool {
+	if s == "" {
+		return true
+	}
+	for _, strategy := range ConflictStrategies {
+		if strings.EqualFold(strings.TrimSpace(s), strategy) {
+			return true
+		}
+	}
+	return false
+}
+
+// ConflictStrategy returns the config's on_conflict strategy, ConflictAsk
+// when unset or invalid
+func (c *ConfigItem) ConflictStrategy() string {
+	s := strings.ToLower(strings.TrimSpace(c.OnConflict))
+	if s == "" || !IsConflictStrategy(s) {
+		return ConflictAsk
+	}
+	return s
+}
+
+// HasConflictStrategies reports whether any config declares a strategy other
+// than ask
+func (c *Config) HasConflictStrategies() bool {
+	for _, item := range c.GetAllConfigs() {
+		if item.ConflictStrategy() != ConflictAsk {
+			return true
+		}
+	}
+	return false
+}
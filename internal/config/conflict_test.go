@@ -0,0 +1,56 @@
+package config
+
+import "testing"
+
+func TestConfigItem_ConflictStrategy(t *testing.T) {
+	tests := []struct {
+		onConflict string
+		want       string
+	}{
+		{"", ConflictAsk},
+		{"backup", ConflictBackup},
+		{" Overwrite ", ConflictOverwrite},
+		{"skip", ConflictSkip},
+		{"ask", ConflictAsk},
+		{"merge", ConflictAsk},
+	}
+
+	for _, tt := range tests {
+		item := ConfigItem{Name: "nvim", OnConflict: tt.onConflict}
+		if got := item.ConflictStrategy(); got != tt.want {
+			t.Errorf("ConflictStrategy() with on_conflict %q = %q, want %q", tt.onConflict, got, tt.want)
+		}
+	}
+}
+
+func TestValidate_OnConflict(t *testing.T) {
+	dir := t.TempDir()
+
+	tests := []struct {
+		onConflict string
+		wantErr    bool
+	}{
+		{"", false},
+		{"backup", false},
+		{"skip", false},
+		{"overwrite", false},
+		{"ask", false},
+		{"delete", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.onConflict, func(t *testing.T) {
+			cfg := &Config{
+				SchemaVersion: "1.0",
+				Metadata:      Metadata{Name: "test"},
+				Configs: ConfigGroups{
+					Optional: []ConfigItem{{Name: "nvim", Path: dir, OnConflict: tt.onConflict}},
+				},
+			}
+			err := cfg.Validate(dir)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() with on_conflict %q, error = %v, wantErr %v", tt.onConflict, err, tt.wantErr)
+			}
+		})
+	}
+}
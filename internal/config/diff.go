@@ -0,0 +1,357 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/nvandessel/go4dot/internal/vcs"
+)
+
+// ChangeKind says how an entry differs between two configs
+type ChangeKind string
+
+const (
+	ChangeAdded   ChangeKind = "added"
+	ChangeRemoved ChangeKind = "removed"
+	ChangeChanged ChangeKind = "changed"
+)
+
+// Change is one semantic difference between two revisions of a config
+type Change struct {
+	Section string     // config, dependency, external, machine config, toolchain, link or setting
+	Name    string     // The entry's name, ID or target; the YAML key of a setting
+	Kind    ChangeKind // Added, removed or changed
+	Details []string   // For changed entries, each field that differs as "field: old → new"
+}
+
+// String describes the change in one line, e.g.
+// "external tpm changed: url: https://a → https://b"
+func (c Change) String() string {
+	s := fmt.Sprintf("%s %s %s", c.Section, c.Name, c.Kind)
+	if len(c.Details) > 0 {
+		s += ": " + strings.Join(c.Details, "; ")
+	}
+	return s
+}
+
+// ConfigDiff lists the changes between two configs, section by section in
+// the order of the file
+type ConfigDiff []Change
+
+// Summary counts the changes, e.g. "2 added, 1 changed"
+func (d ConfigDiff) Summary() string {
+	if len(d) == 0 {
+		return "no changes"
+	}
+	counts := make(map[ChangeKind]int)
+	for _, c := range d {
+		counts[c.Kind]++
+	}
+	var parts []string
+	for _, kind := range []ChangeKind{ChangeAdded, ChangeRemoved, ChangeChanged} {
+		if counts[kind] > 0 {
+			parts = append(parts, fmt.Sprintf("%d %s", counts[kind], kind))
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// diffSections are the Config fields compared entry by entry; the others
+// are compared as settings
+var diffSections = map[string]bool{
+	"dependencies":   true,
+	"configs":        true,
+	"archived":       true,
+	"external":       true,
+	"machine_config": true,
+	"toolchains":     true,
+	"links":          true,
+}
+
+// Compare returns the semantic differences from old to new: the configs,
+// dependencies, externals, machine configs, toolchains and links added,
+// removed or changed, and the other settings that differ. Configs and
+// dependencies moving between groups are changes, not removals.
+func Compare(old, new *Config) ConfigDiff {
+	var diff ConfigDiff
+	diff = append(diff, compareEntries("config", "group", configEntries(old), configEntries(new))...)
+	diff = append(diff, compareEntries("dependency", "tier", dependencyEntries(old), dependencyEntries(new))...)
+	diff = append(diff, compareEntries("external", "", externalEntries(old), externalEntries(new))...)
+
+	var oldMachine, newMachine, oldTools, newTools, oldLinks, newLinks []entry
+	for _, mc := range old.MachineConfig {
+		oldMachine = append(oldMachine, entry{key: mc.ID, value: mc})
+	}
+	for _, mc := range new.MachineConfig {
+		newMachine = append(newMachine, entry{key: mc.ID, value: mc})
+	}
+	for _, tc := range old.Toolchains {
+		oldTools = append(oldTools, entry{key: tc.Language, value: tc})
+	}
+	for _, tc := range new.Toolchains {
+		newTools = append(newTools, entry{key: tc.Language, value: tc})
+	}
+	for _, l := range old.Links {
+		oldLinks = append(oldLinks, entry{key: l.Target, value: l})
+	}
+	for _, l := range new.Links {
+		newLinks = append(newLinks, entry{key: l.Target, value: l})
+	}
+	diff = append(diff, compareEntries("machine config", "", oldMachine, newMachine)...)
+	diff = append(diff, compareEntries("toolchain", "", oldTools, newTools)...)
+	diff = append(diff, compareEntries("link", "", oldLinks, newLinks)...)
+
+	oldValue, newValue := reflect.ValueOf(*old), reflect.ValueOf(*new)
+	for i := 0; i < oldValue.NumField(); i++ {
+		name := yamlName(oldValue.Type().Field(i))
+		if name == "" || diffSections[name] {
+			continue
+		}
+		details := fieldChanges("", oldValue.Field(i), newValue.Field(i))
+		if len(details) == 0 {
+			continue
+		}
+		change := Change{Section: "setting", Name: name, Kind: ChangeChanged, Details: details}
+		switch {
+		case oldValue.Field(i).IsZero():
+			change.Kind, change.Details = ChangeAdded, nil
+		case newValue.Field(i).IsZero():
+			change.Kind, change.Details = ChangeRemoved, nil
+		}
+		diff = append(diff, change)
+	}
+	return diff
+}
+
+// LoadRevision parses the .go4dot.yaml in dir as written at a revision of
+// the repo dir is in, e.g. "HEAD~3" in git. An empty rev reads the file on
+// disk.
+func LoadRevision(dir, rev string) (*Config, error) {
+	if rev == "" {
+		return LoadFile(filepath.Join(dir, ConfigFileName))
+	}
+
+	repo := vcs.Detect(dir)
+	if repo == nil {
+		return nil, fmt.Errorf("%s is not a git, jj or hg repository", dir)
+	}
+	if !vcs.Installed(repo) {
+		return nil, fmt.Errorf("%s is not installed", repo.Name())
+	}
+	data, err := repo.File(dir, rev, ConfigFileName)
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && len(exitErr.Stderr) > 0 {
+			err = errors.New(strings.TrimSpace(string(exitErr.Stderr)))
+		}
+		return nil, fmt.Errorf("failed to read %s at %s: %w", ConfigFileName, rev, err)
+	}
+	return Parse([]byte(data))
+}
+
+// entry is a keyed item of a config section, such as a dependency
+type entry struct {
+	key   string
+	group string // Group or tier the entry is listed under, if the section has them
+	value interface{}
+}
+
+func configEntries(cfg *Config) []entry {
+	var entries []entry
+	for _, group := range []struct {
+		name  string
+		items []ConfigItem
+	}{{"core", cfg.Configs.Core}, {"optional", cfg.Configs.Optional}, {"archived", cfg.Archived}} {
+		for _, item := range group.items {
+			entries = append(entries, entry{key: item.Name, group: group.name, value: item})
+		}
+	}
+	return entries
+}
+
+func dependencyEntries(cfg *Config) []entry {
+	var entries []entry
+	for _, tier := range []struct {
+		name  string
+		items []DependencyItem
+	}{{"critical", cfg.Dependencies.Critical}, {"core", cfg.Dependencies.Core}, {"optional", cfg.Dependencies.Optional}} {
+		for _, item := range tier.items {
+			entries = append(entries, entry{key: item.Name, group: tier.name, value: item})
+		}
+	}
+	return entries
+}
+
+func externalEntries(cfg *Config) []entry {
+	var entries []entry
+	for _, ext := range cfg.External {
+		key := ext.ID
+		if key == "" {
+			key = ext.Name
+		}
+		entries = append(entries, entry{key: key, value: ext})
+	}
+	return entries
+}
+
+// compareEntries matches old and new entries by key. groupLabel names the
+// entries' group in details, e.g. "tier".
+func compareEntries(section, groupLabel string, old, new []entry) []Change {
+	oldByKey := make(map[string]entry, len(old))
+	for _, e := range old {
+		oldByKey[e.key] = e
+	}
+	inNew := make(map[string]bool, len(new))
+
+	var changes []Change
+	for _, e := range new {
+		inNew[e.key] = true
+		prev, ok := oldByKey[e.key]
+		if !ok {
+			changes = append(changes, Change{Section: section, Name: e.key, Kind: ChangeAdded})
+			continue
+		}
+		var details []string
+		if prev.group != e.group {
+			details = append(details, fmt.Sprintf("%s: %s → %s", groupLabel, prev.group, e.group))
+		}
+		details = append(details, fieldChanges("", reflect.ValueOf(prev.value), reflect.ValueOf(e.value))...)
+		if len(details) > 0 {
+			changes = append(changes, Change{Section: section, Name: e.key, Kind: ChangeChanged, Details: details})
+		}
+	}
+	for _, e := range old {
+		if !inNew[e.key] {
+			changes = append(changes, Change{Section: section, Name: e.key, Kind: ChangeRemoved})
+			inNew[e.key] = true
+		}
+	}
+	return changes
+}
+
+// fieldChanges describes how b differs from a, field by field for structs
+// and key by key for string maps, naming each by its YAML path under
+// prefix
+func fieldChanges(prefix string, a, b reflect.Value) []string {
+	switch a.Kind() {
+	case reflect.Struct:
+		var details []string
+		for i := 0; i < a.NumField(); i++ {
+			name := yamlName(a.Type().Field(i))
+			if name == "" {
+				continue
+			}
+			details = append(details, fieldChanges(joinPath(prefix, name), a.Field(i), b.Field(i))...)
+		}
+		return details
+
+	case reflect.Map:
+		if a.Type().Key().Kind() != reflect.String || a.Type().Elem().Kind() != reflect.String {
+			break
+		}
+		keys := make(map[string]bool)
+		for _, k := range append(a.MapKeys(), b.MapKeys()...) {
+			keys[k.String()] = true
+		}
+		sorted := make([]string, 0, len(keys))
+		for k := range keys {
+			sorted = append(sorted, k)
+		}
+		sort.Strings(sorted)
+
+		var details []string
+		for _, k := range sorted {
+			key := reflect.ValueOf(k)
+			details = append(details, fieldChanges(joinPath(prefix, k), a.MapIndex(key), b.MapIndex(key))...)
+		}
+		return details
+	}
+
+	if sameValue(a, b) {
+		return nil
+	}
+	from, fromOK := formatValue(a)
+	to, toOK := formatValue(b)
+	switch {
+	case !fromOK || !toOK:
+		if prefix == "" {
+			return []string{"changed"}
+		}
+		return []string{prefix + " changed"}
+	case prefix == "":
+		return []string{fmt.Sprintf("%s → %s", from, to)}
+	}
+	return []string{fmt.Sprintf("%s: %s → %s", prefix, from, to)}
+}
+
+// sameValue reports whether a and b are equal, counting a missing map
+// value, nil and empty as the same
+func sameValue(a, b reflect.Value) bool {
+	if !a.IsValid() || !b.IsValid() {
+		return (!a.IsValid() || a.IsZero()) && (!b.IsValid() || b.IsZero())
+	}
+	if (a.Kind() == reflect.Slice || a.Kind() == reflect.Map) && a.Len() == 0 && b.Len() == 0 {
+		return true
+	}
+	return reflect.DeepEqual(a.Interface(), b.Interface())
+}
+
+// formatValue renders short scalars and string lists, reporting false for
+// values too long or too nested to show on one line
+func formatValue(v reflect.Value) (string, bool) {
+	if !v.IsValid() || v.IsZero() {
+		return "(none)", true
+	}
+	var s string
+	switch v.Kind() {
+	case reflect.String:
+		s = v.String()
+	case reflect.Bool:
+		s = strconv.FormatBool(v.Bool())
+	case reflect.Int, reflect.Int64:
+		s = strconv.FormatInt(v.Int(), 10)
+	case reflect.Slice:
+		if v.Type().Elem().Kind() != reflect.String {
+			return "", false
+		}
+		items := make([]string, v.Len())
+		for i := range items {
+			items[i] = v.Index(i).String()
+		}
+		s = "[" + strings.Join(items, ", ") + "]"
+	default:
+		return "", false
+	}
+	if strings.Contains(s, "\n") || len(s) > 60 {
+		return "", false
+	}
+	return s, true
+}
+
+// yamlName returns a field's YAML key, or "" for fields not in the file
+func yamlName(f reflect.StructField) string {
+	if !f.IsExported() {
+		return ""
+	}
+	name := strings.Split(f.Tag.Get("yaml"), ",")[0]
+	switch name {
+	case "-":
+		return ""
+	case "":
+		return strings.ToLower(f.Name)
+	}
+	return name
+}
+
+func joinPath(prefix, name string) string {
+	if prefix == "" {
+		return name
+	}
+	return prefix + "." + name
+}
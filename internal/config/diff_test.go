@@ -0,0 +1,136 @@
+package config
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func mustParse(t *testing.T, yaml string) *Config {
+	t.Helper()
+	cfg, err := Parse([]byte(yaml))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	return cfg
+}
+
+func TestCompare(t *testing.T) {
+	old := mustParse(t, `
+schema_version: "1.0"
+dependencies:
+  core:
+    - git
+    - name: neovim
+      package:
+        apt: neovim
+  optional: [ripgrep]
+configs:
+  core:
+    - name: vim
+      path: vim
+    - name: emacs
+      path: emacs
+external:
+  - id: tpm
+    url: https://github.com/tmux-plugins/tpm
+    destination: ~/.tmux/plugins/tpm
+stow:
+  verbosity: 1
+`)
+	new := mustParse(t, `
+schema_version: "1.0"
+dependencies:
+  core:
+    - git
+    - ripgrep
+    - name: neovim
+      package:
+        apt: neovim-ppa
+configs:
+  core:
+    - name: vim
+      path: editors/vim
+    - name: nvim
+      path: nvim
+external:
+  - id: tpm
+    url: https://github.com/me/tpm
+    destination: ~/.tmux/plugins/tpm
+stow:
+  verbosity: 2
+post_install: echo done
+`)
+
+	var got []string
+	for _, c := range Compare(old, new) {
+		got = append(got, c.String())
+	}
+	want := []string{
+		"config vim changed: path: vim → editors/vim",
+		"config nvim added",
+		"config emacs removed",
+		"dependency ripgrep changed: tier: optional → core",
+		"dependency neovim changed: package.apt: neovim → neovim-ppa",
+		"external tpm changed: url: https://github.com/tmux-plugins/tpm → https://github.com/me/tpm",
+		"setting stow changed: verbosity: 1 → 2",
+		"setting post_install added",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Compare() =\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+
+	if diff := Compare(old, old); len(diff) != 0 || diff.Summary() != "no changes" {
+		t.Errorf("Compare(old, old) = %v, %q", diff, diff.Summary())
+	}
+	if got := Compare(old, new).Summary(); got != "2 added, 1 removed, 5 changed" {
+		t.Errorf("Summary() = %q", got)
+	}
+}
+
+func TestLoadRevision(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	dir := t.TempDir()
+	git := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", append([]string{"-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)...)
+		cmd.Dir = dir
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v failed: %v\n%s", args, err, out)
+		}
+	}
+	write := func(content string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(dir, ConfigFileName), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	git("init", "-q")
+	write("configs:\n  core:\n    - name: vim\n      path: vim\n")
+	git("add", ".")
+	git("commit", "-q", "-m", "vim")
+	write("configs:\n  core:\n    - name: vim\n      path: vim\n    - name: zsh\n      path: zsh\n")
+
+	old, err := LoadRevision(dir, "HEAD")
+	if err != nil {
+		t.Fatalf("LoadRevision(HEAD) error = %v", err)
+	}
+	current, err := LoadRevision(dir, "")
+	if err != nil {
+		t.Fatalf("LoadRevision() error = %v", err)
+	}
+	diff := Compare(old, current)
+	if len(diff) != 1 || diff[0].String() != "config zsh added" {
+		t.Errorf("Compare() = %v, want zsh added", diff)
+	}
+
+	if _, err := LoadRevision(dir, "no-such-rev"); err == nil || !strings.Contains(err.Error(), "no-such-rev") {
+		t.Errorf("LoadRevision(no-such-rev) error = %v", err)
+	}
+}
@@ -0,0 +1,177 @@
+package config
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+const (
+	// DefaultScanBudget is how many directory entries the unmanaged-symlinks
+	// check looks at before it stops, unless doctor.scan_budget says otherwise
+	DefaultScanBudget = 200000
+
+	// maxScanDepth bounds doctor.scan_roots depths
+	maxScanDepth = 32
+)
+
+// ScanRoot is a directory the unmanaged-symlinks check walks. In YAML it is
+// a path, or a mapping with a path and a depth.
+type ScanRoot struct {
+	Path  string `yaml:"path"`            // Absolute, starting with ~/, or relative to the link target
+	Depth int    `yaml:"depth,omitempty"` // Directory levels to look in; 1 (the default) only lists Path
+}
+
+// UnmarshalYAML allows a scan root to be just a path
+func (r *ScanRoot) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var path string
+	if err := unmarshal(&path); err == nil {
+		r.Path = path
+		return nil
+	}
+	type plain ScanRoot
+	return unmarshal((*plain)(r))
+}
+
+// defaultScanRoots are scanned when doctor.scan_roots is empty
+var defaultScanRoots = []ScanRoot{{Path: "."}, {Path: ".config"}}
+
+// DoctorChecks are the IDs of the checks g4d doctor runs, as used in the
+// doctor section of the config
+var DoctorChecks = []string{
+	"platform",
+	"stow",
+	"git",
+	"alias",
+	"dependencies",
+	"pacman-db",
+	"symlinks",
+	"functional",
+	"links",
+	"external",
+	"toolchains",
+	"machine-config",
+	"unmanaged-symlinks",
+	"adoption",
+	"artifacts",
+	"personal-data",
+	"ssh-keys",
+	"github-ssh",
+}
+
+// Ignores reports whether the check's result should not count
+func (d DoctorSettings) Ignores(id string) bool {
+	return containsID(d.Ignore, id)
+}
+
+// RaisesWarning reports whether a warning from the check counts as an error
+func (d DoctorSettings) RaisesWarning(id string) bool {
+	return containsID(d.WarnAsError, id)
+}
+
+// AllowPatterns returns the compiled allow patterns, skipping invalid ones
+// (validation reports those)
+func (d DoctorSettings) AllowPatterns() []*regexp.Regexp {
+	var patterns []*regexp.Regexp
+	for _, p := range d.Allow {
+		if re, err := regexp.Compile(p); err == nil {
+			patterns = append(patterns, re)
+		}
+	}
+	return patterns
+}
+
+// SymlinkScanRoots returns the directories the unmanaged-symlinks check
+// walks, with absolute paths and their depth filled in. target is the
+// directory configs are linked into.
+func (d DoctorSettings) SymlinkScanRoots(target string) []ScanRoot {
+	roots := d.ScanRoots
+	if len(roots) == 0 {
+		roots = defaultScanRoots
+	}
+	resolved := make([]ScanRoot, 0, len(roots))
+	for _, r := range roots {
+		path := expandHome(r.Path)
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(target, path)
+		}
+		depth := r.Depth
+		if depth <= 0 {
+			depth = 1
+		}
+		resolved = append(resolved, ScanRoot{Path: filepath.Clean(path), Depth: depth})
+	}
+	return resolved
+}
+
+// SymlinkScanBudget returns how many directory entries the
+// unmanaged-symlinks check looks at
+func (d DoctorSettings) SymlinkScanBudget() int {
+	if d.ScanBudget > 0 {
+		return d.ScanBudget
+	}
+	return DefaultScanBudget
+}
+
+func (d DoctorSettings) validate() []ValidationError {
+	var errors []ValidationError
+	lists := []struct {
+		field string
+		ids   []string
+	}{
+		{"doctor.ignore", d.Ignore},
+		{"doctor.warn_as_error", d.WarnAsError},
+	}
+	for _, list := range lists {
+		for i, id := range list.ids {
+			if !containsID(DoctorChecks, id) {
+				errors = append(errors, ValidationError{
+					Field:   fmt.Sprintf("%s[%d]", list.field, i),
+					Message: fmt.Sprintf("unknown check %q (known: %s)", id, strings.Join(DoctorChecks, ", ")),
+				})
+			}
+		}
+	}
+	for i, id := range d.WarnAsError {
+		if d.Ignores(id) {
+			errors = append(errors, ValidationError{
+				Field:   fmt.Sprintf("doctor.warn_as_error[%d]", i),
+				Message: fmt.Sprintf("check %q is also ignored", id),
+			})
+		}
+	}
+	for i, p := range d.Allow {
+		if _, err := regexp.Compile(p); err != nil {
+			errors = append(errors, ValidationError{
+				Field:   fmt.Sprintf("doctor.allow[%d]", i),
+				Message: fmt.Sprintf("invalid pattern: %v", err),
+			})
+		}
+	}
+	for i, r := range d.ScanRoots {
+		field := fmt.Sprintf("doctor.scan_roots[%d]", i)
+		switch {
+		case strings.TrimSpace(r.Path) == "":
+			errors = append(errors, ValidationError{Field: field + ".path", Message: "path is required"})
+		case !filepath.IsAbs(r.Path) && r.Path != "~" && !strings.HasPrefix(r.Path, "~/") && strings.HasPrefix(filepath.Clean(r.Path), ".."):
+			errors = append(errors, ValidationError{Field: field + ".path", Message: "relative paths must stay inside the link target; use an absolute path"})
+		}
+		if r.Depth < 0 || r.Depth > maxScanDepth {
+			errors = append(errors, ValidationError{Field: field + ".depth", Message: fmt.Sprintf("depth must be between 1 and %d", maxScanDepth)})
+		}
+	}
+	if d.ScanBudget < 0 {
+		errors = append(errors, ValidationError{Field: "doctor.scan_budget", Message: "scan_budget cannot be negative"})
+	}
+	return errors
+}
+
+func containsID(ids []string, id string) bool {
+	for _, i := range ids {
+		if i == id {
+			return true
+		}
+	}
+	return false
+}
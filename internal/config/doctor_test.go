@@ -0,0 +1,105 @@
+package config
+
+import (
+	"reflect"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestDoctorSettings_Validate(t *testing.T) {
+	tests := []struct {
+		name     string
+		settings DoctorSettings
+		fields   []string
+	}{
+		{
+			name:     "valid",
+			settings: DoctorSettings{Ignore: []string{"unmanaged-symlinks"}, WarnAsError: []string{"symlinks"}},
+		},
+		{
+			name:     "unknown checks",
+			settings: DoctorSettings{Ignore: []string{"ssh-keys", "vibes"}, WarnAsError: []string{"Symlinks"}},
+			fields:   []string{"doctor.ignore[1]", "doctor.warn_as_error[0]"},
+		},
+		{
+			name:     "ignored and raised",
+			settings: DoctorSettings{Ignore: []string{"git"}, WarnAsError: []string{"stow", "git"}},
+			fields:   []string{"doctor.warn_as_error[1]"},
+		},
+		{
+			name:     "invalid allow pattern",
+			settings: DoctorSettings{Allow: []string{`@example\.com$`, "(unclosed"}},
+			fields:   []string{"doctor.allow[1]"},
+		},
+		{
+			name: "invalid scan roots",
+			settings: DoctorSettings{
+				ScanRoots:  []ScanRoot{{Path: ".config", Depth: 3}, {Path: ""}, {Path: "../other"}, {Path: "/srv", Depth: 100}},
+				ScanBudget: -1,
+			},
+			fields: []string{"doctor.scan_roots[1].path", "doctor.scan_roots[2].path", "doctor.scan_roots[3].depth", "doctor.scan_budget"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var fields []string
+			for _, e := range tt.settings.validate() {
+				fields = append(fields, e.Field)
+			}
+			if !reflect.DeepEqual(fields, tt.fields) {
+				t.Errorf("validate() fields = %v, want %v", fields, tt.fields)
+			}
+		})
+	}
+}
+
+func TestDoctorSettings_Load(t *testing.T) {
+	data := []byte(`schema_version: "1.0"
+doctor:
+  ignore: [unmanaged-symlinks]
+  warn_as_error: [symlinks]
+`)
+	var cfg Config
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	if !cfg.Doctor.Ignores("unmanaged-symlinks") || cfg.Doctor.Ignores("symlinks") {
+		t.Errorf("Ignore = %v", cfg.Doctor.Ignore)
+	}
+	if !cfg.Doctor.RaisesWarning("symlinks") {
+		t.Errorf("WarnAsError = %v", cfg.Doctor.WarnAsError)
+	}
+}
+
+func TestDoctorSettings_ScanRoots(t *testing.T) {
+	t.Setenv("HOME", "/home/me")
+	data := []byte(`doctor:
+  scan_roots:
+    - .config
+    - path: ~/.local/share
+      depth: 3
+    - /etc/xdg
+`)
+	var cfg Config
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+
+	got := cfg.Doctor.SymlinkScanRoots("/home/me")
+	want := []ScanRoot{
+		{Path: "/home/me/.config", Depth: 1},
+		{Path: "/home/me/.local/share", Depth: 3},
+		{Path: "/etc/xdg", Depth: 1},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("SymlinkScanRoots() = %v, want %v", got, want)
+	}
+	if len((DoctorSettings{}).SymlinkScanRoots("/home/me")) != 2 {
+		t.Error("expected home and ~/.config by default")
+	}
+	if (DoctorSettings{}).SymlinkScanBudget() != DefaultScanBudget {
+		t.Error("expected the default budget")
+	}
+}
@@ -0,0 +1,409 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+)
+
+// UpdateConfigItem sets fields on the config entry called name in the
+// .go4dot.yaml file at configPath. The file is edited as a YAML node tree so
+// comments and key order elsewhere in the file are kept. The write is atomic.
+func UpdateConfigItem(configPath, name string, fields map[string]string) error {
+	doc, err := readConfigDoc(configPath)
+	if err != nil {
+		return err
+	}
+
+	item := findConfigItemNode(doc, name)
+	if item == nil {
+		return fmt.Errorf("config '%s' not found in %s", name, configPath)
+	}
+
+	for key, value := range fields {
+		setMappingValue(item, key, value)
+	}
+
+	return writeConfigDoc(configPath, doc)
+}
+
+// RenameConfigItem renames the config entry called oldName in the
+// .go4dot.yaml file at configPath to newName with path newPath, and points
+// the depends_on entries of other configs at the new name, in one write
+func RenameConfigItem(configPath, oldName, newName, newPath string) error {
+	doc, err := readConfigDoc(configPath)
+	if err != nil {
+		return err
+	}
+
+	item := findConfigItemNode(doc, oldName)
+	if item == nil {
+		return fmt.Errorf("config '%s' not found in %s", oldName, configPath)
+	}
+	setMappingValue(item, "name", newName)
+	setMappingValue(item, "path", newPath)
+
+	if newName != oldName {
+		for _, node := range configItemNodes(doc) {
+			deps := mappingValue(node, "depends_on")
+			if deps == nil || deps.Kind != yaml.SequenceNode {
+				continue
+			}
+			for _, dep := range deps.Content {
+				if dep.Kind == yaml.ScalarNode && dep.Value == oldName {
+					dep.Value = newName
+				}
+			}
+		}
+	}
+
+	return writeConfigDoc(configPath, doc)
+}
+
+// SetConfigArchived marks the config entry called name in the .go4dot.yaml
+// file at configPath as archived, or removes the mark, keeping the rest of
+// the file as it is
+func SetConfigArchived(configPath, name string, archived bool) error {
+	doc, err := readConfigDoc(configPath)
+	if err != nil {
+		return err
+	}
+
+	item := findConfigItemNode(doc, name)
+	if item == nil {
+		return fmt.Errorf("config '%s' not found in %s", name, configPath)
+	}
+
+	if archived {
+		setMappingValue(item, "archived", "true")
+		mappingValue(item, "archived").Tag = "!!bool"
+	} else {
+		deleteMappingKey(item, "archived")
+	}
+
+	return writeConfigDoc(configPath, doc)
+}
+
+// ConfigMetadata is the part of a config entry that describes it rather
+// than what gets linked
+type ConfigMetadata struct {
+	Description string
+	Tags        []string
+	Platforms   []string
+	DependsOn   []string
+}
+
+// Metadata returns the config's description, tags, platforms and
+// depends_on
+func (c ConfigItem) Metadata() ConfigMetadata {
+	return ConfigMetadata{
+		Description: c.Description,
+		Tags:        c.Tags,
+		Platforms:   c.Platforms,
+		DependsOn:   c.DependsOn,
+	}
+}
+
+// ApplyTo sets the metadata on item
+func (m ConfigMetadata) ApplyTo(item *ConfigItem) {
+	item.Description = m.Description
+	item.Tags = m.Tags
+	item.Platforms = m.Platforms
+	item.DependsOn = m.DependsOn
+}
+
+// UpdateConfigMetadata replaces the description, tags, platforms and
+// depends_on of the config entry called name in the .go4dot.yaml file at
+// configPath, keeping the rest of the file as it is. Empty values remove
+// their key.
+func UpdateConfigMetadata(configPath, name string, meta ConfigMetadata) error {
+	doc, err := readConfigDoc(configPath)
+	if err != nil {
+		return err
+	}
+
+	item := findConfigItemNode(doc, name)
+	if item == nil {
+		return fmt.Errorf("config '%s' not found in %s", name, configPath)
+	}
+
+	if meta.Description == "" {
+		deleteMappingKey(item, "description")
+	} else {
+		setMappingValue(item, "description", meta.Description)
+	}
+	setMappingList(item, "tags", meta.Tags)
+	setMappingList(item, "platforms", meta.Platforms)
+	setMappingList(item, "depends_on", meta.DependsOn)
+
+	return writeConfigDoc(configPath, doc)
+}
+
+// AddConfigItem appends item to configs.core (or configs.optional) in the
+// .go4dot.yaml file at configPath, keeping the rest of the file as it is.
+// Only the name, path and description are written.
+func AddConfigItem(configPath string, item ConfigItem, optional bool) error {
+	doc, err := readConfigDoc(configPath)
+	if err != nil {
+		return err
+	}
+	if findConfigItemNode(doc, item.Name) != nil {
+		return fmt.Errorf("config '%s' already exists in %s", item.Name, configPath)
+	}
+
+	group := "core"
+	if optional {
+		group = "optional"
+	}
+	seq := sequenceValue(mappingChild(doc.Content[0], "configs"), group)
+
+	node := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+	setMappingValue(node, "name", item.Name)
+	setMappingValue(node, "path", item.Path)
+	if item.Description != "" {
+		setMappingValue(node, "description", item.Description)
+	}
+	seq.Content = append(seq.Content, node)
+
+	return writeConfigDoc(configPath, doc)
+}
+
+// AddDependency appends dep to dependencies.<group> in the .go4dot.yaml
+// file at configPath. Only the name and binary are written.
+func AddDependency(configPath string, dep DependencyItem, group string) error {
+	switch group {
+	case "critical", "core", "optional":
+	default:
+		return fmt.Errorf("unknown dependency group '%s'", group)
+	}
+
+	doc, err := readConfigDoc(configPath)
+	if err != nil {
+		return err
+	}
+	seq := sequenceValue(mappingChild(doc.Content[0], "dependencies"), group)
+
+	node := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+	setMappingValue(node, "name", dep.Name)
+	if dep.Binary != "" && dep.Binary != dep.Name {
+		setMappingValue(node, "binary", dep.Binary)
+	}
+	seq.Content = append(seq.Content, node)
+
+	return writeConfigDoc(configPath, doc)
+}
+
+// AddMachinePrompt appends mp to machine_config in the .go4dot.yaml file at
+// configPath, keeping the rest of the file as it is
+func AddMachinePrompt(configPath string, mp MachinePrompt) error {
+	doc, err := readConfigDoc(configPath)
+	if err != nil {
+		return err
+	}
+	seq := sequenceValue(doc.Content[0], "machine_config")
+	for _, node := range seq.Content {
+		if id := mappingValue(node, "id"); id != nil && id.Value == mp.ID {
+			return fmt.Errorf("machine config '%s' already exists in %s", mp.ID, configPath)
+		}
+	}
+
+	var node yaml.Node
+	if err := node.Encode(mp); err != nil {
+		return fmt.Errorf("failed to encode machine config: %w", err)
+	}
+	seq.Content = append(seq.Content, &node)
+
+	return writeConfigDoc(configPath, doc)
+}
+
+// readConfigDoc parses the file at configPath into a YAML node tree whose
+// root is a mapping
+func readConfigDoc(configPath string) (*yaml.Node, error) {
+	data, err := os.ReadFile(configPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse config file: %w", err)
+	}
+	if doc.Kind != yaml.DocumentNode || len(doc.Content) == 0 || doc.Content[0].Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("failed to parse config file: %s is not a YAML mapping", configPath)
+	}
+	return &doc, nil
+}
+
+// writeConfigDoc encodes doc and atomically replaces the file at configPath,
+// keeping the file's layout (see EncodeYAML)
+func writeConfigDoc(configPath string, doc *yaml.Node) error {
+	original, err := os.ReadFile(configPath)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to read config file: %w", err)
+	}
+	data, err := EncodeYAML(doc, original)
+	if err != nil {
+		return fmt.Errorf("failed to encode config file: %w", err)
+	}
+	return writeFileAtomic(configPath, data)
+}
+
+// findConfigItemNode returns the mapping node for a config under
+// configs.core or configs.optional, or nil if not found
+func findConfigItemNode(doc *yaml.Node, name string) *yaml.Node {
+	for _, item := range configItemNodes(doc) {
+		if n := mappingValue(item, "name"); n != nil && n.Value == name {
+			return item
+		}
+	}
+	return nil
+}
+
+// configItemNodes returns the mapping nodes of every config under
+// configs.core and configs.optional
+func configItemNodes(doc *yaml.Node) []*yaml.Node {
+	if doc.Kind != yaml.DocumentNode || len(doc.Content) == 0 {
+		return nil
+	}
+
+	configs := mappingValue(doc.Content[0], "configs")
+	if configs == nil {
+		return nil
+	}
+
+	var items []*yaml.Node
+	for _, group := range []string{"core", "optional"} {
+		seq := mappingValue(configs, group)
+		if seq == nil || seq.Kind != yaml.SequenceNode {
+			continue
+		}
+		items = append(items, seq.Content...)
+	}
+	return items
+}
+
+// mappingValue returns the value node for key in a mapping node
+func mappingValue(node *yaml.Node, key string) *yaml.Node {
+	if node == nil || node.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// mappingChild returns the mapping value for key in node, creating it (or
+// replacing an empty value) when needed
+func mappingChild(node *yaml.Node, key string) *yaml.Node {
+	v := mappingValue(node, key)
+	if v == nil {
+		v = &yaml.Node{}
+		node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key}, v)
+	}
+	if v.Kind != yaml.MappingNode {
+		*v = yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+	}
+	return v
+}
+
+// sequenceValue returns the sequence value for key in node, creating it (or
+// replacing an empty value) when needed. Flow sequences such as "[]" are
+// switched to block style so appended items are readable.
+func sequenceValue(node *yaml.Node, key string) *yaml.Node {
+	v := mappingValue(node, key)
+	if v == nil {
+		v = &yaml.Node{}
+		node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key}, v)
+	}
+	if v.Kind != yaml.SequenceNode {
+		*v = yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq"}
+	}
+	v.Style &^= yaml.FlowStyle
+	return v
+}
+
+// setMappingValue sets a scalar value in a mapping node, appending the key if missing
+func setMappingValue(node *yaml.Node, key, value string) {
+	if v := mappingValue(node, key); v != nil {
+		v.Kind = yaml.ScalarNode
+		v.Tag = "!!str"
+		v.Value = value
+		return
+	}
+	node.Content = append(node.Content,
+		&yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key},
+		&yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: value},
+	)
+}
+
+// setMappingList sets a list of strings in a mapping node, removing the key
+// when values is empty. A new list is written in flow style, e.g.
+// "[linux, darwin]"; an existing one keeps its style.
+func setMappingList(node *yaml.Node, key string, values []string) {
+	if len(values) == 0 {
+		deleteMappingKey(node, key)
+		return
+	}
+
+	v := mappingValue(node, key)
+	if v == nil {
+		v = &yaml.Node{Style: yaml.FlowStyle}
+		node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key}, v)
+	} else if v.Kind != yaml.SequenceNode {
+		v.Style = yaml.FlowStyle
+	}
+	v.Kind, v.Tag, v.Value = yaml.SequenceNode, "!!seq", ""
+	v.Content = nil
+	for _, value := range values {
+		v.Content = append(v.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: value})
+	}
+}
+
+// deleteMappingKey removes key and its value from a mapping node
+func deleteMappingKey(node *yaml.Node, key string) {
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			node.Content = append(node.Content[:i], node.Content[i+2:]...)
+			return
+		}
+	}
+}
+
+// writeFileAtomic writes data to a temp file next to path and renames it into
+// place, keeping the original file mode
+func writeFileAtomic(path string, data []byte) error {
+	mode := os.FileMode(0644)
+	if info, err := os.Stat(path); err == nil {
+		mode = info.Mode().Perm()
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".go4dot-*.yaml")
+	if err != nil {
+		return fmt.Errorf("failed to create temp file: %w", err)
+	}
+	tmpPath := tmp.Name()
+
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		_ = os.Remove(tmpPath)
+		return fmt.Errorf("failed to write config file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		_ = os.Remove(tmpPath)
+		return fmt.Errorf("failed to write config file: %w", err)
+	}
+	if err := os.Chmod(tmpPath, mode); err != nil {
+		_ = os.Remove(tmpPath)
+		return fmt.Errorf("failed to set config file mode: %w", err)
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		_ = os.Remove(tmpPath)
+		return fmt.Errorf("failed to replace config file: %w", err)
+	}
+	return nil
+}
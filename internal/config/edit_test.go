@@ -0,0 +1,426 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestUpdateConfigItem(t *testing.T) {
+	const original = `schema_version: "1.0"
+# My dotfiles
+metadata:
+  name: test
+configs:
+  core:
+    - name: nvim # editor
+      path: nvim
+  optional:
+    - name: tmux
+      path: tmux
+`
+
+	tests := []struct {
+		name      string
+		item      string
+		fields    map[string]string
+		wantErr   bool
+		wantName  string
+		wantPath  string
+		wantInOut []string
+	}{
+		{
+			name:      "rename core config keeps comments",
+			item:      "nvim",
+			fields:    map[string]string{"name": "neovim", "path": "neovim"},
+			wantName:  "neovim",
+			wantPath:  "neovim",
+			wantInOut: []string{"# My dotfiles", "# editor"},
+		},
+		{
+			name:     "move optional config",
+			item:     "tmux",
+			fields:   map[string]string{"path": "terminal-mux"},
+			wantName: "tmux",
+			wantPath: "terminal-mux",
+		},
+		{
+			name:    "unknown config",
+			item:    "missing",
+			fields:  map[string]string{"name": "x"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), ".go4dot.yaml")
+			if err := os.WriteFile(path, []byte(original), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			err := UpdateConfigItem(path, tt.item, tt.fields)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("UpdateConfigItem() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			cfg, err := LoadFromPath(path)
+			if err != nil {
+				t.Fatalf("LoadFromPath() error = %v", err)
+			}
+			item := cfg.GetConfigByName(tt.wantName)
+			if item == nil {
+				t.Fatalf("config %q not found after update", tt.wantName)
+			}
+			if item.Path != tt.wantPath {
+				t.Errorf("path = %q, want %q", item.Path, tt.wantPath)
+			}
+
+			data, _ := os.ReadFile(path)
+			for _, want := range tt.wantInOut {
+				if !strings.Contains(string(data), want) {
+					t.Errorf("output missing %q:\n%s", want, data)
+				}
+			}
+		})
+	}
+}
+
+func TestRenameConfigItem(t *testing.T) {
+	const original = `schema_version: "1.0"
+configs:
+  core:
+    - name: nvim
+      path: nvim
+    - name: lsp
+      path: lsp
+      depends_on: [nvim, git]
+  optional:
+    - name: plugins
+      path: plugins
+      depends_on:
+        - nvim # needs the editor
+`
+	path := filepath.Join(t.TempDir(), ".go4dot.yaml")
+	if err := os.WriteFile(path, []byte(original), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := RenameConfigItem(path, "nvim", "neovim", "neovim"); err != nil {
+		t.Fatalf("RenameConfigItem() error = %v", err)
+	}
+
+	cfg, err := LoadFromPath(path)
+	if err != nil {
+		t.Fatalf("LoadFromPath() error = %v", err)
+	}
+	if item := cfg.GetConfigByName("neovim"); item == nil || item.Path != "neovim" {
+		t.Errorf("neovim = %+v, want it renamed and moved", item)
+	}
+	if deps := cfg.GetConfigByName("lsp").DependsOn; strings.Join(deps, ",") != "neovim,git" {
+		t.Errorf("lsp depends_on = %v, want [neovim git]", deps)
+	}
+	if deps := cfg.GetConfigByName("plugins").DependsOn; strings.Join(deps, ",") != "neovim" {
+		t.Errorf("plugins depends_on = %v, want [neovim]", deps)
+	}
+	data, _ := os.ReadFile(path)
+	if !strings.Contains(string(data), "# needs the editor") {
+		t.Errorf("comment on a depends_on entry was lost:\n%s", data)
+	}
+
+	if err := RenameConfigItem(path, "missing", "x", "x"); err == nil {
+		t.Error("RenameConfigItem() of an unknown config: error = nil")
+	}
+}
+
+func TestAddConfigItem(t *testing.T) {
+	tests := []struct {
+		name      string
+		original  string
+		item      ConfigItem
+		optional  bool
+		wantErr   bool
+		wantCore  []string
+		wantOpt   []string
+		wantInOut []string
+	}{
+		{
+			name: "append to core keeps comments",
+			original: `# My dotfiles
+configs:
+  core:
+    - name: nvim # editor
+      path: nvim
+`,
+			item:      ConfigItem{Name: "tmux", Path: "tmux", Description: "Tmux configuration"},
+			wantCore:  []string{"nvim", "tmux"},
+			wantInOut: []string{"# My dotfiles", "# editor", "description: Tmux configuration"},
+		},
+		{
+			name: "optional group is created",
+			original: `configs:
+  core:
+    - name: nvim
+      path: nvim
+`,
+			item:     ConfigItem{Name: "kitty", Path: "kitty"},
+			optional: true,
+			wantCore: []string{"nvim"},
+			wantOpt:  []string{"kitty"},
+		},
+		{
+			name:     "empty flow sequence and missing configs",
+			original: "metadata:\n  name: test\nconfigs:\n  core: []\n",
+			item:     ConfigItem{Name: "zsh", Path: "zsh"},
+			wantCore: []string{"zsh"},
+		},
+		{
+			name:     "no configs section",
+			original: "metadata:\n  name: test\n",
+			item:     ConfigItem{Name: "zsh", Path: "zsh"},
+			wantCore: []string{"zsh"},
+		},
+		{
+			name: "duplicate name",
+			original: `configs:
+  optional:
+    - name: tmux
+      path: tmux
+`,
+			item:    ConfigItem{Name: "tmux", Path: "tmux"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), ConfigFileName)
+			if err := os.WriteFile(path, []byte(tt.original), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			err := AddConfigItem(path, tt.item, tt.optional)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("AddConfigItem() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			cfg, err := Load(path)
+			if err != nil {
+				t.Fatalf("Load() after add: %v", err)
+			}
+			if got := configNames(cfg.Configs.Core); strings.Join(got, ",") != strings.Join(tt.wantCore, ",") {
+				t.Errorf("core = %v, want %v", got, tt.wantCore)
+			}
+			if got := configNames(cfg.Configs.Optional); strings.Join(got, ",") != strings.Join(tt.wantOpt, ",") {
+				t.Errorf("optional = %v, want %v", got, tt.wantOpt)
+			}
+
+			data, _ := os.ReadFile(path)
+			for _, want := range tt.wantInOut {
+				if !strings.Contains(string(data), want) {
+					t.Errorf("output missing %q:\n%s", want, data)
+				}
+			}
+		})
+	}
+}
+
+func TestAddDependency(t *testing.T) {
+	const original = `dependencies:
+  critical:
+    - git # always needed
+`
+	path := filepath.Join(t.TempDir(), ConfigFileName)
+	if err := os.WriteFile(path, []byte(original), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := AddDependency(path, DependencyItem{Name: "neovim", Binary: "nvim"}, "core"); err != nil {
+		t.Fatalf("AddDependency() error = %v", err)
+	}
+	if err := AddDependency(path, DependencyItem{Name: "tmux", Binary: "tmux"}, "core"); err != nil {
+		t.Fatalf("AddDependency() error = %v", err)
+	}
+	if err := AddDependency(path, DependencyItem{Name: "x"}, "later"); err == nil {
+		t.Error("AddDependency() with unknown group should fail")
+	}
+
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() after add: %v", err)
+	}
+	if len(cfg.Dependencies.Critical) != 1 || cfg.Dependencies.Critical[0].Name != "git" {
+		t.Errorf("critical = %+v, want [git]", cfg.Dependencies.Critical)
+	}
+	core := cfg.Dependencies.Core
+	if len(core) != 2 || core[0].Name != "neovim" || core[0].Binary != "nvim" || core[1].Name != "tmux" {
+		t.Errorf("core = %+v, want [neovim (nvim) tmux]", core)
+	}
+
+	data, _ := os.ReadFile(path)
+	if !strings.Contains(string(data), "# always needed") {
+		t.Errorf("comment lost:\n%s", data)
+	}
+	if strings.Contains(string(data), "binary: tmux") {
+		t.Errorf("binary equal to name should be omitted:\n%s", data)
+	}
+}
+
+func TestAddMachinePrompt(t *testing.T) {
+	const original = `machine_config:
+  - id: git # signing
+    destination: ~/.gitconfig.local
+    template: x
+`
+	path := filepath.Join(t.TempDir(), ConfigFileName)
+	if err := os.WriteFile(path, []byte(original), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	mp := MachinePrompt{
+		ID:          "npmrc",
+		Destination: "~/.npmrc",
+		Prompts:     []PromptField{{ID: "token", Prompt: "Token", Type: "password"}},
+		Template:    "token={{ .token }}\n",
+	}
+	if err := AddMachinePrompt(path, mp); err != nil {
+		t.Fatalf("AddMachinePrompt() error = %v", err)
+	}
+	if err := AddMachinePrompt(path, mp); err == nil {
+		t.Error("AddMachinePrompt() with an existing id should fail")
+	}
+
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() after add: %v", err)
+	}
+	if len(cfg.MachineConfig) != 2 || cfg.MachineConfig[1].ID != "npmrc" || cfg.MachineConfig[1].Template != mp.Template {
+		t.Errorf("machine_config = %+v", cfg.MachineConfig)
+	}
+	data, _ := os.ReadFile(path)
+	if !strings.Contains(string(data), "# signing") {
+		t.Errorf("comment lost:\n%s", data)
+	}
+}
+
+func configNames(items []ConfigItem) []string {
+	var names []string
+	for _, item := range items {
+		names = append(names, item.Name)
+	}
+	return names
+}
+
+func TestSetConfigArchived(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".go4dot.yaml")
+	original := `schema_version: "1.0"
+metadata:
+  name: test
+configs:
+  core:
+    - name: nvim # editor
+      path: nvim
+    - name: zsh
+      path: zsh
+`
+	if err := os.WriteFile(path, []byte(original), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := SetConfigArchived(path, "nvim", true); err != nil {
+		t.Fatalf("SetConfigArchived() error = %v", err)
+	}
+	data, _ := os.ReadFile(path)
+	if !strings.Contains(string(data), "archived: true") || !strings.Contains(string(data), "# editor") {
+		t.Errorf("archived file:\n%s", data)
+	}
+
+	cfg, err := LoadFromPath(path)
+	if err != nil {
+		t.Fatalf("LoadFromPath() error = %v", err)
+	}
+	if cfg.GetConfigByName("nvim") != nil || len(cfg.GetAllConfigs()) != 1 {
+		t.Errorf("archived config still active: %+v", cfg.GetAllConfigs())
+	}
+	if item := cfg.GetArchivedConfig("nvim"); item == nil || !item.Archived {
+		t.Errorf("GetArchivedConfig() = %+v", item)
+	}
+
+	if err := SetConfigArchived(path, "nvim", false); err != nil {
+		t.Fatalf("SetConfigArchived(false) error = %v", err)
+	}
+	data, _ = os.ReadFile(path)
+	if strings.Contains(string(data), "archived") {
+		t.Errorf("restored file still archived:\n%s", data)
+	}
+
+	if err := SetConfigArchived(path, "missing", true); err == nil {
+		t.Error("expected an error for an unknown config")
+	}
+}
+
+func TestUpdateConfigMetadata(t *testing.T) {
+	const original = `schema_version: "1.0"
+metadata:
+  name: test
+configs:
+  core:
+    - name: nvim # editor
+      path: nvim
+      description: Old
+      platforms:
+        - linux
+    - name: zsh
+      path: zsh
+      depends_on: [nvim]
+`
+	path := filepath.Join(t.TempDir(), ".go4dot.yaml")
+	if err := os.WriteFile(path, []byte(original), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	err := UpdateConfigMetadata(path, "nvim", ConfigMetadata{
+		Description: "Neovim",
+		Tags:        []string{"editor", "lua"},
+		Platforms:   []string{"linux", "darwin"},
+	})
+	if err != nil {
+		t.Fatalf("UpdateConfigMetadata() error = %v", err)
+	}
+	if err := UpdateConfigMetadata(path, "zsh", ConfigMetadata{}); err != nil {
+		t.Fatalf("UpdateConfigMetadata() error = %v", err)
+	}
+	if err := UpdateConfigMetadata(path, "missing", ConfigMetadata{}); err == nil {
+		t.Error("expected an error for an unknown config")
+	}
+
+	cfg, err := LoadFromPath(path)
+	if err != nil {
+		t.Fatalf("LoadFromPath() error = %v", err)
+	}
+	nvim := cfg.GetConfigByName("nvim")
+	if nvim.Description != "Neovim" || strings.Join(nvim.Tags, ",") != "editor,lua" || strings.Join(nvim.Platforms, ",") != "linux,darwin" {
+		t.Errorf("nvim metadata = %+v", nvim.Metadata())
+	}
+	zsh := cfg.GetConfigByName("zsh")
+	if zsh.Description != "" || len(zsh.DependsOn) != 0 {
+		t.Errorf("zsh metadata = %+v, want empty", zsh.Metadata())
+	}
+
+	data, _ := os.ReadFile(path)
+	for _, want := range []string{"# editor", "tags: [editor, lua]", "      - darwin"} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("output missing %q:\n%s", want, data)
+		}
+	}
+	if strings.Contains(string(data), "depends_on") {
+		t.Errorf("empty depends_on was kept:\n%s", data)
+	}
+}
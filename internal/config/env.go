@@ -0,0 +1,157 @@
+package config
+
+import (
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// Environment variables exported to commands run for a config
+const (
+	EnvConfigName = "G4D_CONFIG_NAME" // Name of the config
+	EnvConfigDir  = "G4D_CONFIG_DIR"  // The config's directory in the repo
+	EnvTargetDir  = "G4D_TARGET_DIR"  // Directory the config is linked into
+	EnvRepoRoot   = "G4D_REPO_ROOT"   // Root of the dotfiles repo
+)
+
+// StowDir returns the repo the config's package lives in: repoRoot, or the
+// base repo the config was inherited from
+func (c *ConfigItem) StowDir(repoRoot string) string {
+	if c.Root != "" {
+		return c.Root
+	}
+	return repoRoot
+}
+
+// Dir returns the config's directory in the repo at repoRoot, or in the
+// base repo it was inherited from
+func (c *ConfigItem) Dir(repoRoot string) string {
+	return filepath.Join(c.StowDir(repoRoot), c.Path)
+}
+
+// Env returns the environment variables describing the config, as
+// KEY=value pairs to append to a command's environment
+func (c *ConfigItem) Env(repoRoot, targetDir string) []string {
+	return []string{
+		EnvConfigName + "=" + c.Name,
+		EnvConfigDir + "=" + c.Dir(repoRoot),
+		EnvTargetDir + "=" + targetDir,
+		EnvRepoRoot + "=" + repoRoot,
+	}
+}
+
+// envNamePattern matches valid environment variable names
+var envNamePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// envOverrides holds the --env flag values, which win over the config's env
+var (
+	envOverrides   map[string]string
+	envOverridesMu sync.RWMutex
+)
+
+// ParseEnvFlag splits a --env value of the form KEY=VALUE
+func ParseEnvFlag(s string) (string, string, error) {
+	key, value, ok := strings.Cut(s, "=")
+	if !ok {
+		return "", "", fmt.Errorf("invalid --env %q: expected KEY=VALUE", s)
+	}
+	if err := checkEnvName(key); err != nil {
+		return "", "", fmt.Errorf("invalid --env %q: %w", s, err)
+	}
+	return key, value, nil
+}
+
+// SetEnvOverrides exports the --env values for this process, so every
+// operation sees them, and keeps the config's env from replacing them
+func SetEnvOverrides(env map[string]string) error {
+	envOverridesMu.Lock()
+	defer envOverridesMu.Unlock()
+	envOverrides = env
+	for key, value := range env {
+		if err := os.Setenv(key, value); err != nil {
+			return fmt.Errorf("failed to set %s: %w", key, err)
+		}
+	}
+	return nil
+}
+
+// ApplyEnv exports the config's env for this process, so hooks, package
+// installers and git see it. Values may refer to other variables ($HOME,
+// ${USER}); names also given with --env keep the flag's value. Load leaves
+// this to the command, which should only call it once the config validates;
+// an env that sets names go4dot reserves is refused either way.
+func (c *Config) ApplyEnv() error {
+	if errs := c.validateEnv(); len(errs) > 0 {
+		return fmt.Errorf("invalid env: %s: %s", errs[0].Field, errs[0].Message)
+	}
+	envOverridesMu.RLock()
+	defer envOverridesMu.RUnlock()
+	for _, key := range slices.Sorted(maps.Keys(c.Env)) {
+		if _, overridden := envOverrides[key]; overridden {
+			continue
+		}
+		if err := os.Setenv(key, os.ExpandEnv(c.Env[key])); err != nil {
+			return fmt.Errorf("failed to set %s: %w", key, err)
+		}
+	}
+	return nil
+}
+
+// OperationEnv returns the config's env and the --env values as KEY=VALUE
+// pairs, for commands that don't inherit go4dot's environment, such as
+// package installs through sudo
+func (c *Config) OperationEnv() []string {
+	envOverridesMu.RLock()
+	keys := make(map[string]bool, len(c.Env)+len(envOverrides))
+	for key := range envOverrides {
+		keys[key] = true
+	}
+	envOverridesMu.RUnlock()
+	for key := range c.Env {
+		if checkEnvName(key) == nil {
+			keys[key] = true
+		}
+	}
+
+	var env []string
+	for _, key := range slices.Sorted(maps.Keys(keys)) {
+		env = append(env, key+"="+os.Getenv(key))
+	}
+	return env
+}
+
+// checkEnvName rejects names that are not valid or that go4dot sets itself
+func checkEnvName(name string) error {
+	switch {
+	case !envNamePattern.MatchString(name):
+		return fmt.Errorf("%q is not a valid variable name", name)
+	case name == "HOME":
+		return fmt.Errorf("HOME can't be overridden; use workspaces to link elsewhere")
+	case strings.HasPrefix(name, "GO4DOT_") || strings.HasPrefix(name, "G4D_"):
+		return fmt.Errorf("%s is reserved for go4dot", name)
+	}
+	return nil
+}
+
+func (c *Config) validateEnv() []ValidationError {
+	var errors []ValidationError
+	for _, key := range slices.Sorted(maps.Keys(c.Env)) {
+		if err := checkEnvName(key); err != nil {
+			errors = append(errors, ValidationError{
+				Field:   fmt.Sprintf("env.%s", key),
+				Message: err.Error(),
+			})
+		} else if strings.ContainsRune(c.Env[key], 0) {
+			errors = append(errors, ValidationError{
+				Field:   fmt.Sprintf("env.%s", key),
+				Message: "value must not contain NUL characters",
+			})
+		}
+	}
+	return errors
+}
@@ -0,0 +1,101 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestConfigItemEnv(t *testing.T) {
+	item := ConfigItem{Name: "nvim", Path: "editors/nvim"}
+	repo := filepath.Join("/", "dotfiles")
+
+	want := []string{
+		"G4D_CONFIG_NAME=nvim",
+		"G4D_CONFIG_DIR=" + filepath.Join(repo, "editors", "nvim"),
+		"G4D_TARGET_DIR=/home/user",
+		"G4D_REPO_ROOT=" + repo,
+	}
+	if got := item.Env(repo, "/home/user"); !reflect.DeepEqual(got, want) {
+		t.Errorf("Env() = %v, want %v", got, want)
+	}
+}
+
+func TestParseEnvFlag(t *testing.T) {
+	key, value, err := ParseEnvFlag("GIT_SSH_COMMAND=ssh -i ~/.ssh/work=key")
+	if err != nil || key != "GIT_SSH_COMMAND" || value != "ssh -i ~/.ssh/work=key" {
+		t.Errorf("ParseEnvFlag() = %q, %q, %v", key, value, err)
+	}
+	for _, bad := range []string{"NOVALUE", "1BAD=x", "HOME=/tmp", "G4D_REPO_ROOT=x", "=x"} {
+		if _, _, err := ParseEnvFlag(bad); err == nil {
+			t.Errorf("ParseEnvFlag(%q) succeeded, want an error", bad)
+		}
+	}
+}
+
+func TestApplyEnv(t *testing.T) {
+	t.Setenv("ENVTEST_PROXY", "")
+	t.Setenv("LC_G4D_TEST", "")
+	t.Setenv("G4D_TEST_USER", "alice")
+	t.Cleanup(func() { envOverrides = nil })
+
+	if err := SetEnvOverrides(map[string]string{"LC_G4D_TEST": "C"}); err != nil {
+		t.Fatal(err)
+	}
+	cfg := &Config{Env: map[string]string{
+		"ENVTEST_PROXY": "http://${G4D_TEST_USER}@proxy:3128",
+		"LC_G4D_TEST":   "en_US.UTF-8",
+	}}
+	if err := cfg.ApplyEnv(); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := os.Getenv("ENVTEST_PROXY"); got != "http://alice@proxy:3128" {
+		t.Errorf("ENVTEST_PROXY = %q, want variables expanded", got)
+	}
+	if got := os.Getenv("LC_G4D_TEST"); got != "C" {
+		t.Errorf("LC_G4D_TEST = %q, want the --env value to win", got)
+	}
+	want := []string{"ENVTEST_PROXY=http://alice@proxy:3128", "LC_G4D_TEST=C"}
+	if got := cfg.OperationEnv(); !reflect.DeepEqual(got, want) {
+		t.Errorf("OperationEnv() = %v, want %v", got, want)
+	}
+}
+
+func TestApplyEnv_Reserved(t *testing.T) {
+	t.Setenv("G4D_WORKSPACE", "")
+	t.Setenv("ENVTEST_SAFE", "")
+
+	cfg := &Config{Env: map[string]string{"ENVTEST_SAFE": "1", "G4D_WORKSPACE": "hijack", "HOME": "/evil"}}
+	if err := cfg.ApplyEnv(); err == nil {
+		t.Error("ApplyEnv() succeeded, want reserved names refused")
+	}
+	if os.Getenv("G4D_WORKSPACE") != "" || os.Getenv("ENVTEST_SAFE") != "" {
+		t.Error("ApplyEnv() exported an env that failed validation")
+	}
+	for _, kv := range cfg.OperationEnv() {
+		if strings.HasPrefix(kv, "HOME=") || strings.HasPrefix(kv, "G4D_WORKSPACE=") {
+			t.Errorf("OperationEnv() passes %s", kv)
+		}
+	}
+}
+
+func TestValidateEnv(t *testing.T) {
+	cfg := &Config{Env: map[string]string{
+		"GIT_SSH_COMMAND":  "ssh -i ~/.ssh/work",
+		"HOME":             "/tmp",
+		"bad-name":         "x",
+		"GO4DOT_WORKSPACE": "work",
+	}}
+	errs := cfg.validateEnv()
+	var fields []string
+	for _, e := range errs {
+		fields = append(fields, e.Field)
+	}
+	want := []string{"env.GO4DOT_WORKSPACE", "env.HOME", "env.bad-name"}
+	if !reflect.DeepEqual(fields, want) {
+		t.Errorf("validateEnv() fields = %v, want %v", fields, want)
+	}
+}
@@ -0,0 +1,261 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"strings"
+
+	"github.com/nvandessel/go4dot/internal/workspace"
+)
+
+// BasesDirName is the directory under ~/.config/go4dot that base repos are
+// cloned into
+const BasesDirName = "bases"
+
+// Kinds of entries tracked in Config.Origins
+const (
+	OriginConfig        = "config"
+	OriginDependency    = "dependency"
+	OriginExternal      = "external"
+	OriginMachineConfig = "machine_config"
+	OriginMachine       = "machine"
+	OriginToolchain     = "toolchain"
+	OriginLink          = "link"
+)
+
+// Extends lists the go4dot repos a config builds on. It accepts a single
+// URL or a list; local paths are used in place instead of being cloned.
+type Extends []string
+
+// UnmarshalYAML allows extends to be a string or a list of strings
+func (e *Extends) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var url string
+	if err := unmarshal(&url); err == nil {
+		*e = Extends{url}
+		return nil
+	}
+
+	var urls []string
+	if err := unmarshal(&urls); err != nil {
+		return err
+	}
+	*e = urls
+	return nil
+}
+
+// OriginKey returns the Config.Origins key for an entry of the given kind
+func OriginKey(kind, name string) string {
+	return kind + ":" + name
+}
+
+// BasesDir returns the directory base repos are cloned into
+func BasesDir() (string, error) {
+	home, err := workspace.UserHome()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".config", "go4dot", BasesDirName), nil
+}
+
+// IsLocalBase reports whether a base is a path on disk rather than a URL
+func IsLocalBase(base string) bool {
+	return filepath.IsAbs(base) || base == "." || base == ".." ||
+		strings.HasPrefix(base, "./") || strings.HasPrefix(base, "../") || strings.HasPrefix(base, "~/")
+}
+
+// BaseDir returns where the base repo is found: a local base is resolved
+// against repoRoot, a remote one is cloned under basesDir in a directory
+// named after its URL
+func BaseDir(base, repoRoot, basesDir string) string {
+	if IsLocalBase(base) {
+		if strings.HasPrefix(base, "~/") {
+			home, _ := workspace.UserHome()
+			return filepath.Join(home, base[2:])
+		}
+		if filepath.IsAbs(base) {
+			return filepath.Clean(base)
+		}
+		return filepath.Join(repoRoot, base)
+	}
+
+	name := strings.TrimSuffix(base, ".git")
+	if i := strings.Index(name, "://"); i >= 0 {
+		name = name[i+3:]
+	}
+	name = strings.TrimPrefix(name, "git@")
+	name = strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '.', r == '_', r == '-':
+			return r
+		}
+		return '-'
+	}, name)
+	return filepath.Join(basesDir, strings.Trim(name, "-."))
+}
+
+// ApplyBases returns the effective config for local, which was loaded from
+// the repo at repoRoot: the bases it extends are applied in order, each
+// overriding the ones before it, and local overrides them all. Entries are
+// matched by name (configs, dependencies, machines) or id (external,
+// machine_config), and a matching entry replaces the earlier one as a whole.
+// schema_version, metadata and post_install only come from local, and the
+// bases' own extends are not followed. Bases that are not cloned yet are
+// listed in MissingBases.
+func ApplyBases(local *Config, repoRoot, basesDir string) (*Config, error) {
+	effective := &Config{}
+	origins := make(map[string]string)
+	var missing []string
+
+	for _, base := range local.Extends {
+		dir := BaseDir(base, repoRoot, basesDir)
+		baseCfg, err := LoadFile(filepath.Join(dir, ConfigFileName))
+		if errors.Is(err, fs.ErrNotExist) {
+			missing = append(missing, base)
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("base %s: %w", base, err)
+		}
+
+		rootConfigs(baseCfg, dir)
+		for _, key := range originKeys(baseCfg) {
+			origins[key] = base
+		}
+		effective = overlay(effective, baseCfg)
+	}
+
+	for _, key := range originKeys(local) {
+		delete(origins, key)
+	}
+	effective = overlay(effective, local)
+
+	effective.SchemaVersion = local.SchemaVersion
+	effective.Metadata = local.Metadata
+	effective.Extends = local.Extends
+	effective.PostInstall = local.PostInstall
+	effective.Origins = origins
+	effective.MissingBases = missing
+	return effective, nil
+}
+
+// rootConfigs records dir as the repo of each of cfg's configs
+func rootConfigs(cfg *Config, dir string) {
+	for _, group := range [][]ConfigItem{cfg.Configs.Core, cfg.Configs.Optional, cfg.Archived} {
+		for i := range group {
+			if group[i].Root == "" {
+				group[i].Root = dir
+			}
+		}
+	}
+}
+
+// overlay returns the entries of lower that upper does not override,
+// followed by the entries of upper
+func overlay(lower, upper *Config) *Config {
+	out := *upper
+
+	configs := make(map[string]bool)
+	for _, group := range [][]ConfigItem{upper.Configs.Core, upper.Configs.Optional, upper.Archived} {
+		for _, c := range group {
+			configs[c.Name] = true
+		}
+	}
+	configName := func(c ConfigItem) string { return c.Name }
+	out.Configs.Core = overrideBy(lower.Configs.Core, upper.Configs.Core, configs, configName)
+	out.Configs.Optional = overrideBy(lower.Configs.Optional, upper.Configs.Optional, configs, configName)
+	out.Archived = overrideBy(lower.Archived, upper.Archived, configs, configName)
+
+	deps := make(map[string]bool)
+	for _, d := range upper.GetAllDependencies() {
+		deps[d.Name] = true
+	}
+	depName := func(d DependencyItem) string { return d.Name }
+	out.Dependencies.Critical = overrideBy(lower.Dependencies.Critical, upper.Dependencies.Critical, deps, depName)
+	out.Dependencies.Core = overrideBy(lower.Dependencies.Core, upper.Dependencies.Core, deps, depName)
+	out.Dependencies.Optional = overrideBy(lower.Dependencies.Optional, upper.Dependencies.Optional, deps, depName)
+
+	externalID := func(e ExternalDep) string { return e.ID }
+	out.External = overrideBy(lower.External, upper.External, keySet(upper.External, externalID), externalID)
+
+	promptID := func(m MachinePrompt) string { return m.ID }
+	out.MachineConfig = overrideBy(lower.MachineConfig, upper.MachineConfig, keySet(upper.MachineConfig, promptID), promptID)
+
+	machineName := func(m MachineProfile) string { return m.Name }
+	out.Machines = overrideBy(lower.Machines, upper.Machines, keySet(upper.Machines, machineName), machineName)
+
+	profileName := func(p Profile) string { return p.Name }
+	out.Profiles = overrideBy(lower.Profiles, upper.Profiles, keySet(upper.Profiles, profileName), profileName)
+
+	language := func(t Toolchain) string { return t.Language }
+	out.Toolchains = overrideBy(lower.Toolchains, upper.Toolchains, keySet(upper.Toolchains, language), language)
+
+	target := func(l Link) string { return l.Target }
+	out.Links = overrideBy(lower.Links, upper.Links, keySet(upper.Links, target), target)
+
+	// A base's hooks run before the repo's own
+	out.Hooks = lower.Hooks.merge(upper.Hooks)
+
+	if len(lower.Env) > 0 {
+		out.Env = make(map[string]string, len(lower.Env)+len(upper.Env))
+		for key, value := range lower.Env {
+			out.Env[key] = value
+		}
+		for key, value := range upper.Env {
+			out.Env[key] = value
+		}
+	}
+
+	return &out
+}
+
+// overrideBy keeps the entries of lower whose key is not in overridden and
+// appends upper
+func overrideBy[T any](lower, upper []T, overridden map[string]bool, key func(T) string) []T {
+	var out []T
+	for _, item := range lower {
+		if !overridden[key(item)] {
+			out = append(out, item)
+		}
+	}
+	return append(out, upper...)
+}
+
+// keySet returns the keys of items
+func keySet[T any](items []T, key func(T) string) map[string]bool {
+	set := make(map[string]bool, len(items))
+	for _, item := range items {
+		set[key(item)] = true
+	}
+	return set
+}
+
+// originKeys returns the Origins keys of every entry in cfg
+func originKeys(cfg *Config) []string {
+	var keys []string
+	for _, group := range [][]ConfigItem{cfg.Configs.Core, cfg.Configs.Optional, cfg.Archived} {
+		for _, c := range group {
+			keys = append(keys, OriginKey(OriginConfig, c.Name))
+		}
+	}
+	for _, d := range cfg.GetAllDependencies() {
+		keys = append(keys, OriginKey(OriginDependency, d.Name))
+	}
+	for _, e := range cfg.External {
+		keys = append(keys, OriginKey(OriginExternal, e.ID))
+	}
+	for _, m := range cfg.MachineConfig {
+		keys = append(keys, OriginKey(OriginMachineConfig, m.ID))
+	}
+	for _, m := range cfg.Machines {
+		keys = append(keys, OriginKey(OriginMachine, m.Name))
+	}
+	for _, t := range cfg.Toolchains {
+		keys = append(keys, OriginKey(OriginToolchain, t.Language))
+	}
+	for _, l := range cfg.Links {
+		keys = append(keys, OriginKey(OriginLink, l.Target))
+	}
+	return keys
+}
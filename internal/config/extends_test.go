@@ -0,0 +1,181 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestExtendsUnmarshal(t *testing.T) {
+	tests := []struct {
+		name string
+		yaml string
+		want Extends
+	}{
+		{"single", "extends: https://github.com/team/base", Extends{"https://github.com/team/base"}},
+		{"list", "extends:\n  - ../base\n  - https://github.com/team/base", Extends{"../base", "https://github.com/team/base"}},
+		{"absent", "schema_version: \"1.0\"", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var cfg Config
+			if err := yaml.Unmarshal([]byte(tt.yaml), &cfg); err != nil {
+				t.Fatalf("Unmarshal failed: %v", err)
+			}
+			if !reflect.DeepEqual(cfg.Extends, tt.want) {
+				t.Errorf("Extends = %v, want %v", cfg.Extends, tt.want)
+			}
+		})
+	}
+}
+
+func TestBaseDir(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	tests := []struct {
+		base string
+		want string
+	}{
+		{"https://github.com/team/base-dotfiles", "/bases/github.com-team-base-dotfiles"},
+		{"https://github.com/team/base-dotfiles.git", "/bases/github.com-team-base-dotfiles"},
+		{"git@github.com:team/base.git", "/bases/github.com-team-base"},
+		{"../base", "/repo/base"},
+		{"/srv/base", "/srv/base"},
+		{"~/base", filepath.Join(home, "base")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.base, func(t *testing.T) {
+			if got := BaseDir(tt.base, "/repo/dotfiles", "/bases"); got != tt.want {
+				t.Errorf("BaseDir(%q) = %q, want %q", tt.base, got, tt.want)
+			}
+		})
+	}
+}
+
+func writeConfig(t *testing.T, dir, content string) {
+	t.Helper()
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, ConfigFileName), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestApplyBases(t *testing.T) {
+	root := t.TempDir()
+	repo := filepath.Join(root, "dotfiles")
+	base := filepath.Join(root, "base")
+
+	writeConfig(t, base, `schema_version: "1.0"
+metadata:
+  name: base
+extends: ../other
+configs:
+  core:
+    - name: git
+      path: git
+    - name: zsh
+      path: zsh
+dependencies:
+  critical:
+    - git
+external:
+  - id: tpm
+    url: https://github.com/tmux-plugins/tpm
+    destination: ~/.tmux/plugins/tpm
+env:
+  EXTENDS_TEST_PROXY: http://proxy:3128
+  EXTENDS_TEST_LANG: C
+`)
+	writeConfig(t, repo, `schema_version: "1.0"
+metadata:
+  name: mine
+extends:
+  - ../base
+  - https://example.com/missing
+configs:
+  core:
+    - name: zsh
+      path: my-zsh
+    - name: nvim
+      path: nvim
+env:
+  EXTENDS_TEST_LANG: en_US.UTF-8
+`)
+	t.Setenv("EXTENDS_TEST_PROXY", "")
+	t.Setenv("EXTENDS_TEST_LANG", "")
+
+	cfg, err := Load(filepath.Join(repo, ConfigFileName))
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	if cfg.Metadata.Name != "mine" {
+		t.Errorf("Metadata.Name = %q, want metadata from the local config", cfg.Metadata.Name)
+	}
+
+	var names []string
+	for _, c := range cfg.Configs.Core {
+		names = append(names, c.Name)
+	}
+	if want := []string{"git", "zsh", "nvim"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("core configs = %v, want %v", names, want)
+	}
+
+	if git := cfg.GetConfigByName("git"); git == nil || git.Dir(repo) != filepath.Join(base, "git") {
+		t.Errorf("inherited config should live in the base repo, got %+v", git)
+	}
+	if zsh := cfg.GetConfigByName("zsh"); zsh == nil || zsh.Dir(repo) != filepath.Join(repo, "my-zsh") {
+		t.Errorf("local config should override the base, got %+v", zsh)
+	}
+
+	wantOrigins := map[string]string{
+		OriginKey(OriginConfig, "git"):     "../base",
+		OriginKey(OriginDependency, "git"): "../base",
+		OriginKey(OriginExternal, "tpm"):   "../base",
+	}
+	if !reflect.DeepEqual(cfg.Origins, wantOrigins) {
+		t.Errorf("Origins = %v, want %v", cfg.Origins, wantOrigins)
+	}
+
+	if want := []string{"https://example.com/missing"}; !reflect.DeepEqual(cfg.MissingBases, want) {
+		t.Errorf("MissingBases = %v, want %v", cfg.MissingBases, want)
+	}
+
+	wantEnv := map[string]string{"EXTENDS_TEST_PROXY": "http://proxy:3128", "EXTENDS_TEST_LANG": "en_US.UTF-8"}
+	if !reflect.DeepEqual(cfg.Env, wantEnv) {
+		t.Errorf("Env = %v, want the base's env with local values winning", cfg.Env)
+	}
+	// Load leaves exporting the env to the command, after validation
+	if got := os.Getenv("EXTENDS_TEST_PROXY"); got != "" {
+		t.Errorf("EXTENDS_TEST_PROXY = %q after Load, want it unset", got)
+	}
+}
+
+func TestLoadFileIgnoresBases(t *testing.T) {
+	root := t.TempDir()
+	writeConfig(t, filepath.Join(root, "base"), `schema_version: "1.0"
+configs:
+  core:
+    - name: git
+      path: git
+`)
+	writeConfig(t, filepath.Join(root, "dotfiles"), `schema_version: "1.0"
+extends: ../base
+`)
+
+	cfg, err := LoadFile(filepath.Join(root, "dotfiles", ConfigFileName))
+	if err != nil {
+		t.Fatalf("LoadFile failed: %v", err)
+	}
+	if len(cfg.GetAllConfigs()) != 0 {
+		t.Errorf("LoadFile should not merge bases, got %d configs", len(cfg.GetAllConfigs()))
+	}
+}
@@ -0,0 +1,103 @@
+package config
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// Stages hooks run at
+const (
+	HookPreInstall  = "pre_install"  // Before install links anything
+	HookPostInstall = "post_install" // After install has linked the configs
+	HookPreSync     = "pre_sync"     // Before sync links anything
+	HookPostSync    = "post_sync"    // After sync has linked the configs
+)
+
+// HookStages are the stages hooks may run at, in the order they run
+var HookStages = []string{HookPreInstall, HookPostInstall, HookPreSync, HookPostSync}
+
+// Hooks maps a stage to the shell commands run at it, such as reloading
+// tmux or rebuilding the font cache once a config is linked
+type Hooks map[string]HookCommands
+
+// HookCommands are the commands of one stage. It accepts a single command
+// or a list.
+type HookCommands []string
+
+// UnmarshalYAML allows a stage to be a string or a list of strings
+func (h *HookCommands) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var command string
+	if err := unmarshal(&command); err == nil {
+		*h = HookCommands{command}
+		return nil
+	}
+
+	var commands []string
+	if err := unmarshal(&commands); err != nil {
+		return err
+	}
+	*h = commands
+	return nil
+}
+
+// IsPreStage reports whether stage runs before linking, so a failing hook
+// stops the operation
+func IsPreStage(stage string) bool {
+	return strings.HasPrefix(stage, "pre_")
+}
+
+// merge returns the commands of h followed by those of upper, stage by stage
+func (h Hooks) merge(upper Hooks) Hooks {
+	if len(h) == 0 {
+		return upper
+	}
+	out := make(Hooks, len(h)+len(upper))
+	for stage, commands := range h {
+		out[stage] = append(HookCommands{}, commands...)
+	}
+	for stage, commands := range upper {
+		out[stage] = append(out[stage], commands...)
+	}
+	return out
+}
+
+// validateHooks checks the stages and commands of hooks under field
+func validateHooks(field string, hooks Hooks) []ValidationError {
+	var errors []ValidationError
+	for _, stage := range slices.Sorted(maps.Keys(hooks)) {
+		if !isHookStage(stage) {
+			msg := fmt.Sprintf("unknown hook stage %q (known: %s)", stage, strings.Join(HookStages, ", "))
+			if s := Suggest(stage, HookStages); s != "" {
+				msg = fmt.Sprintf("unknown hook stage %q, did you mean %s?", stage, s)
+			}
+			errors = append(errors, ValidationError{Field: field + "." + stage, Message: msg})
+			continue
+		}
+		for i, command := range hooks[stage] {
+			switch {
+			case strings.TrimSpace(command) == "":
+				errors = append(errors, ValidationError{
+					Field:   fmt.Sprintf("%s.%s[%d]", field, stage, i),
+					Message: "command must not be empty",
+				})
+			case strings.ContainsRune(command, 0):
+				errors = append(errors, ValidationError{
+					Field:   fmt.Sprintf("%s.%s[%d]", field, stage, i),
+					Message: "command must not contain NUL characters",
+				})
+			}
+		}
+	}
+	return errors
+}
+
+func isHookStage(stage string) bool {
+	for _, s := range HookStages {
+		if s == stage {
+			return true
+		}
+	}
+	return false
+}
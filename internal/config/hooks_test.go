@@ -0,0 +1,88 @@
+package config
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestHooks_Unmarshal(t *testing.T) {
+	data := `
+hooks:
+  post_sync: tmux source-file ~/.tmux.conf
+  post_install:
+    - fc-cache -f
+    - nvim --headless +PlugInstall +qall
+`
+	var cfg Config
+	if err := yaml.Unmarshal([]byte(data), &cfg); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+
+	want := Hooks{
+		HookPostSync:    {"tmux source-file ~/.tmux.conf"},
+		HookPostInstall: {"fc-cache -f", "nvim --headless +PlugInstall +qall"},
+	}
+	if !reflect.DeepEqual(cfg.Hooks, want) {
+		t.Errorf("Hooks = %v, want %v", cfg.Hooks, want)
+	}
+}
+
+func TestValidateHooks(t *testing.T) {
+	tests := []struct {
+		name    string
+		hooks   Hooks
+		wantErr string
+	}{
+		{name: "valid", hooks: Hooks{HookPreSync: {"echo hi"}, HookPostInstall: {"fc-cache -f"}}},
+		{name: "misspelled stage", hooks: Hooks{"post_snyc": {"echo hi"}}, wantErr: "did you mean post_sync?"},
+		{name: "unknown stage", hooks: Hooks{"on_link": {"echo hi"}}, wantErr: "known: pre_install"},
+		{name: "empty command", hooks: Hooks{HookPostSync: {"echo hi", "  "}}, wantErr: "must not be empty"},
+		{name: "NUL in command", hooks: Hooks{HookPostSync: {"echo \x00"}}, wantErr: "NUL"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := validateHooks("hooks", tt.hooks)
+			if tt.wantErr == "" {
+				if len(errs) > 0 {
+					t.Errorf("validateHooks() = %v, want no errors", errs)
+				}
+				return
+			}
+			if len(errs) == 0 || !strings.Contains(errs[0].Message, tt.wantErr) {
+				t.Errorf("validateHooks() = %v, want %q", errs, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidate_ConfigHooks(t *testing.T) {
+	cfg := &Config{
+		SchemaVersion: "1.0",
+		Metadata:      Metadata{Name: "test"},
+		Configs: ConfigGroups{
+			Core: []ConfigItem{{Name: "tmux", Path: "tmux", Hooks: Hooks{HookPostSync: {""}}}},
+		},
+	}
+	err := cfg.Validate(t.TempDir())
+	if err == nil || !strings.Contains(err.Error(), "configs.core[0].hooks.post_sync[0]") {
+		t.Errorf("Validate() = %v, want an error for the empty hook", err)
+	}
+}
+
+func TestHooks_Merge(t *testing.T) {
+	base := Hooks{HookPostSync: {"base"}, HookPreInstall: {"base-pre"}}
+	local := Hooks{HookPostSync: {"local"}}
+
+	got := base.merge(local)
+	want := Hooks{HookPostSync: {"base", "local"}, HookPreInstall: {"base-pre"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("merge() = %v, want %v", got, want)
+	}
+	if len(base[HookPostSync]) != 1 {
+		t.Errorf("merge() changed the base's hooks: %v", base)
+	}
+}
@@ -9,7 +9,6 @@ import (
 
 	"github.com/charmbracelet/huh"
 	"github.com/charmbracelet/lipgloss"
-	"gopkg.in/yaml.v3"
 )
 
 // subtle uses the same color as ui.SubtleColor to maintain visual consistency.
@@ -327,16 +326,24 @@ func InitConfigWithIO(path string, in io.Reader, out io.Writer) error {
 		return err
 	}
 
+	// Built-in presets and those imported with 'g4d machine preset import'
+	presets, err := MachinePresets()
+	if err != nil {
+		_, _ = fmt.Fprintf(out, "Some imported presets could not be read: %v\n", err)
+	}
+	presetOptions := make([]huh.Option[string], 0, len(presets)+1)
+	for _, p := range presets {
+		presetOptions = append(presetOptions, huh.NewOption(p.Label(), p.Prompt.ID))
+	}
+	presetOptions = append(presetOptions, huh.NewOption("Custom", "custom"))
+
 	for addMachineConfig {
 		var choice string
 		err = huh.NewForm(
 			huh.NewGroup(
 				huh.NewSelect[string]().
 					Title("Select a machine config preset or create custom").
-					Options(
-						huh.NewOption("Git Signing (Name, Email, GPG Key)", "git-signing"),
-						huh.NewOption("Custom", "custom"),
-					).
+					Options(presetOptions...).
 					Value(&choice),
 			),
 		).WithInput(in).WithOutput(out).Run()
@@ -345,18 +352,14 @@ func InitConfigWithIO(path string, in io.Reader, out io.Writer) error {
 			return err
 		}
 
-		if choice == "git-signing" {
-			machineConfigs = append(machineConfigs, MachinePrompt{
-				ID:          "git-local",
-				Description: "Local Git Configuration",
-				Destination: "~/.gitconfig.local",
-				Prompts: []PromptField{
-					{ID: "name", Prompt: "Git User Name", Type: "text", Required: true},
-					{ID: "email", Prompt: "Git User Email", Type: "text", Required: true},
-					{ID: "signingkey", Prompt: "GPG Signing Key ID (optional)", Type: "text"},
-				},
-				Template: "[user]\n  name = {{.name}}\n  email = {{.email}}\n{{if .signingkey}}  signingkey = {{.signingkey}}\n[commit]\n  gpgsign = true{{end}}\n",
-			})
+		var preset *MachinePreset
+		for i := range presets {
+			if presets[i].Prompt.ID == choice {
+				preset = &presets[i]
+			}
+		}
+		if preset != nil {
+			machineConfigs = append(machineConfigs, preset.Prompt)
 		} else {
 			var id, desc, dest, tmpl string
 			err = huh.NewForm(
@@ -412,18 +415,13 @@ func InitConfigWithIO(path string, in io.Reader, out io.Writer) error {
 		External:      externalDeps,
 		MachineConfig: machineConfigs,
 	}
-
-	// Generate YAML
-	data, err := yaml.Marshal(&cfg)
-	if err != nil {
-		return fmt.Errorf("failed to generate YAML: %w", err)
+	if UsesDotPrefix(absPath, selectedConfigs) {
+		cfg.Stow.Flags = []string{"--dotfiles"}
+		_, _ = fmt.Fprintln(out, "Found dot- prefixed files; stow will run with --dotfiles.")
 	}
 
-	// Add comments to the top
-	content := fmt.Sprintf("# Generated by go4dot\n# Edit this file to customize your dotfiles management\n\n%s", string(data))
-
-	if err := os.WriteFile(configFile, []byte(content), 0644); err != nil {
-		return fmt.Errorf("failed to write config file: %w", err)
+	if err := WriteConfig(configFile, &cfg, GeneratedHeader); err != nil {
+		return err
 	}
 
 	_, _ = fmt.Fprintf(out, "\n✅ Successfully created %s\n", configFile)
@@ -0,0 +1,185 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/nvandessel/go4dot/internal/validation"
+)
+
+// RepoRootPrefix starts paths relative to the dotfiles repo
+const RepoRootPrefix = "@repoRoot/"
+
+// How a link is made
+const (
+	LinkModeSymlink = "symlink"
+	LinkModeCopy    = "copy"
+)
+
+// How a copy updates a file already at its target
+const (
+	MergeReplace = "replace" // Write the source over it
+	MergeJSON    = "json"    // Set the source's keys in it and keep the others
+)
+
+// IsCopy reports whether sync copies the link's source rather than linking
+// to it
+func (l Link) IsCopy() bool {
+	return l.Mode == LinkModeCopy || findKnownTarget(l.Target) != nil
+}
+
+// Paths returns where the link is created and what it points to, with ~/
+// expanded to the home directory, @repoRoot/ to repoRoot and a well-known
+// target's prefix to where the application keeps its settings. A
+// well-known target gives no paths when the application isn't on this
+// machine, and @jetbrains/ one per IDE.
+func (l Link) Paths(repoRoot string) (targets []string, source string, err error) {
+	home := os.Getenv("HOME")
+	if home == "" {
+		return nil, "", fmt.Errorf("HOME is not set")
+	}
+	source, err = expandLinkPath(l.Source, home, repoRoot)
+	if err != nil {
+		return nil, "", fmt.Errorf("source: %w", err)
+	}
+
+	known := findKnownTarget(l.Target)
+	if known == nil {
+		target, err := expandLinkPath(l.Target, home, repoRoot)
+		if err != nil {
+			return nil, "", fmt.Errorf("target: %w", err)
+		}
+		return []string{target}, source, nil
+	}
+
+	dirs, err := known.dirs(home)
+	if err != nil {
+		return nil, "", fmt.Errorf("target: %w", err)
+	}
+	for _, dir := range dirs {
+		target, err := joinLinkPath(dir, strings.TrimPrefix(l.Target, known.Prefix), l.Target)
+		if err != nil {
+			return nil, "", fmt.Errorf("target: %w", err)
+		}
+		targets = append(targets, target)
+	}
+	return targets, source, nil
+}
+
+// expandLinkPath expands a path starting with ~/ or @repoRoot/, refusing
+// one that climbs out of its base with ..
+func expandLinkPath(path, home, repoRoot string) (string, error) {
+	switch {
+	case strings.HasPrefix(path, "~/"):
+		return joinLinkPath(home, path[2:], path)
+	case strings.HasPrefix(path, RepoRootPrefix):
+		if repoRoot == "" {
+			return "", fmt.Errorf("repoRoot is not set, cannot expand %s", path)
+		}
+		return joinLinkPath(repoRoot, strings.TrimPrefix(path, RepoRootPrefix), path)
+	}
+	return "", fmt.Errorf("must start with ~/ or %s, got %q", RepoRootPrefix, path)
+}
+
+// joinLinkPath joins rel, the part of path after its prefix, to base,
+// refusing a result outside base or base itself
+func joinLinkPath(base, rel, path string) (string, error) {
+	expanded := filepath.Clean(filepath.Join(base, rel))
+	if err := validation.ValidateDestinationPath(expanded, base); err != nil {
+		return "", err
+	}
+	if expanded == filepath.Clean(base) {
+		return "", fmt.Errorf("%q names its base directory itself", path)
+	}
+	return expanded, nil
+}
+
+func validateLinks(links []Link) []ValidationError {
+	var errors []ValidationError
+	targets := make(map[string]bool)
+	for i, l := range links {
+		field := fmt.Sprintf("links[%d]", i)
+		target := filepath.Clean(l.Target)
+		known := findKnownTarget(l.Target)
+		switch {
+		case known != nil:
+			if !linkPathStaysInside(strings.TrimPrefix(l.Target, known.Prefix)) {
+				errors = append(errors, ValidationError{
+					Field:   field + ".target",
+					Message: fmt.Sprintf("target %q must name a path inside %s", l.Target, known.Prefix),
+				})
+			}
+		case strings.HasPrefix(l.Target, "@"):
+			prefix, _, _ := strings.Cut(l.Target, "/")
+			msg := fmt.Sprintf("unknown well-known target %s/ (known: %s)", prefix, strings.Join(knownTargetPrefixes(), ", "))
+			if s := Suggest(prefix+"/", knownTargetPrefixes()); s != "" {
+				msg = fmt.Sprintf("unknown well-known target %s/, did you mean %s?", prefix, s)
+			}
+			errors = append(errors, ValidationError{Field: field + ".target", Message: msg})
+		case !strings.HasPrefix(l.Target, "~/"):
+			errors = append(errors, ValidationError{
+				Field:   field + ".target",
+				Message: "target must start with ~/ or a well-known target such as @vscode/",
+			})
+		case !linkPathStaysInside(l.Target[2:]):
+			errors = append(errors, ValidationError{
+				Field:   field + ".target",
+				Message: fmt.Sprintf("target %q must name a path inside the home directory", l.Target),
+			})
+		case targets[target]:
+			errors = append(errors, ValidationError{
+				Field:   field + ".target",
+				Message: fmt.Sprintf("duplicate link: %s", l.Target),
+			})
+		}
+		targets[target] = true
+
+		switch {
+		case !strings.HasPrefix(l.Source, "~/") && !strings.HasPrefix(l.Source, RepoRootPrefix):
+			errors = append(errors, ValidationError{
+				Field:   field + ".source",
+				Message: "source must start with ~/ or " + RepoRootPrefix,
+			})
+		case !linkPathStaysInside(l.Source[strings.Index(l.Source, "/")+1:]):
+			errors = append(errors, ValidationError{
+				Field:   field + ".source",
+				Message: fmt.Sprintf("source %q must name a path inside its base directory", l.Source),
+			})
+		}
+
+		switch {
+		case l.Mode != "" && l.Mode != LinkModeSymlink && l.Mode != LinkModeCopy:
+			errors = append(errors, ValidationError{
+				Field:   field + ".mode",
+				Message: fmt.Sprintf("mode must be %s or %s, got %q", LinkModeSymlink, LinkModeCopy, l.Mode),
+			})
+		case l.Mode == LinkModeSymlink && known != nil:
+			errors = append(errors, ValidationError{
+				Field:   field + ".mode",
+				Message: fmt.Sprintf("%s can't be symlinked; links to it are copied", known.Prefix),
+			})
+		}
+		switch {
+		case l.Merge != "" && l.Merge != MergeReplace && l.Merge != MergeJSON:
+			errors = append(errors, ValidationError{
+				Field:   field + ".merge",
+				Message: fmt.Sprintf("merge must be %s or %s, got %q", MergeReplace, MergeJSON, l.Merge),
+			})
+		case l.Merge != "" && !l.IsCopy():
+			errors = append(errors, ValidationError{
+				Field:   field + ".merge",
+				Message: "merge applies to copies only; set mode: copy",
+			})
+		}
+	}
+	return errors
+}
+
+// linkPathStaysInside reports whether rel names a path below its base,
+// not the base itself or anything outside it
+func linkPathStaysInside(rel string) bool {
+	cleaned := filepath.Clean(rel)
+	return cleaned != "." && cleaned != ".." && !strings.HasPrefix(cleaned, ".."+string(filepath.Separator)) && !filepath.IsAbs(cleaned)
+}
@@ -0,0 +1,63 @@
+package config
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestValidateLinks(t *testing.T) {
+	tests := []struct {
+		name    string
+		links   []Link
+		wantErr string
+	}{
+		{"valid", []Link{{Target: "~/bin", Source: "@repoRoot/scripts"}, {Target: "~/notes", Source: "~/Sync/notes"}}, ""},
+		{"absolute target", []Link{{Target: "/usr/local/bin", Source: "@repoRoot/scripts"}}, "target must start with ~/"},
+		{"home as target", []Link{{Target: "~/", Source: "@repoRoot/scripts"}}, "inside the home directory"},
+		{"target outside home", []Link{{Target: "~/../bin", Source: "@repoRoot/scripts"}}, "inside the home directory"},
+		{"duplicate", []Link{{Target: "~/bin", Source: "@repoRoot/scripts"}, {Target: "~/bin/", Source: "@repoRoot/bin"}}, "duplicate link"},
+		{"relative source", []Link{{Target: "~/bin", Source: "scripts"}}, "source must start with"},
+		{"source outside repo", []Link{{Target: "~/bin", Source: "@repoRoot/../scripts"}}, "inside its base directory"},
+		{"copy", []Link{{Target: "~/.config/Code/User/settings.json", Source: "@repoRoot/vscode/settings.json", Mode: "copy", Merge: "json"}}, ""},
+		{"well-known target", []Link{{Target: "@windowsTerminal/settings.json", Source: "@repoRoot/wt.json", Merge: "json"}}, ""},
+		{"unknown well-known target", []Link{{Target: "@vscod/settings.json", Source: "@repoRoot/vscode.json"}}, "did you mean @vscode/?"},
+		{"well-known target escapes", []Link{{Target: "@vscode/../keybindings.json", Source: "@repoRoot/vscode.json"}}, "must name a path inside @vscode/"},
+		{"symlinked well-known target", []Link{{Target: "@vscode/settings.json", Source: "@repoRoot/vscode.json", Mode: "symlink"}}, "can't be symlinked"},
+		{"unknown mode", []Link{{Target: "~/bin", Source: "@repoRoot/scripts", Mode: "hardlink"}}, "mode must be symlink or copy"},
+		{"unknown merge", []Link{{Target: "~/a.json", Source: "@repoRoot/a.json", Mode: "copy", Merge: "yaml"}}, "merge must be replace or json"},
+		{"merge without copy", []Link{{Target: "~/a.json", Source: "@repoRoot/a.json", Merge: "json"}}, "merge applies to copies only"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := validateLinks(tt.links)
+			if tt.wantErr == "" {
+				if len(errs) > 0 {
+					t.Errorf("validateLinks() = %v, want no errors", errs)
+				}
+				return
+			}
+			if len(errs) == 0 || !strings.Contains(errs[0].Message, tt.wantErr) {
+				t.Errorf("validateLinks() = %v, want %q", errs, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestLinkPaths(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	targets, source, err := Link{Target: "~/bin", Source: "@repoRoot/scripts"}.Paths("/repo")
+	if err != nil {
+		t.Fatalf("Paths() error = %v", err)
+	}
+	if len(targets) != 1 || targets[0] != filepath.Join(home, "bin") || source != "/repo/scripts" {
+		t.Errorf("Paths() = %q, %q", targets, source)
+	}
+
+	if _, _, err := (Link{Target: "~/bin", Source: "@repoRoot/scripts"}).Paths(""); err == nil {
+		t.Error("Paths() without a repo root should fail")
+	}
+}
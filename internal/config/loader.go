@@ -6,6 +6,7 @@ import (
 	"os"
 	"path/filepath"
 
+	"github.com/nvandessel/go4dot/internal/workspace"
 	"gopkg.in/yaml.v3"
 )
 
@@ -19,13 +20,50 @@ func IsNotFound(err error) bool {
 	return errors.Is(err, ErrConfigNotFound)
 }
 
-// Load reads and parses a .go4dot.yaml file
+// Load reads and parses a .go4dot.yaml file and returns the effective
+// config, with the bases it extends applied (see ApplyBases), archived
+// configs set aside and narrowed to the active workspace (see ApplyWorkspace)
+// and profile (see ApplyProfile)
 func Load(path string) (*Config, error) {
+	cfg, err := LoadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(cfg.Extends) > 0 {
+		basesDir, err := BasesDir()
+		if err != nil {
+			return nil, err
+		}
+		if cfg, err = ApplyBases(cfg, filepath.Dir(path), basesDir); err != nil {
+			return nil, err
+		}
+	}
+	cfg.setAsideArchived()
+
+	if name := workspace.Active(); name != "" {
+		if err := cfg.ApplyWorkspace(name); err != nil {
+			return nil, err
+		}
+	}
+	if err := cfg.ApplyProfile(ActiveProfileName()); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// LoadFile reads and parses a .go4dot.yaml file as written, without
+// applying the bases it extends
+func LoadFile(path string) (*Config, error) {
 	data, err := os.ReadFile(path)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read config file: %w", err)
 	}
+	return Parse(data)
+}
 
+// Parse parses the contents of a .go4dot.yaml file as written
+func Parse(data []byte) (*Config, error) {
 	var cfg Config
 	if err := yaml.Unmarshal(data, &cfg); err != nil {
 		return nil, fmt.Errorf("failed to parse YAML: %w", err)
@@ -36,14 +74,17 @@ func Load(path string) (*Config, error) {
 
 // FindConfig searches for .go4dot.yaml in common locations
 func FindConfig() (string, error) {
+	// The repo stays in the user's home while a workspace retargets HOME
+	home, _ := workspace.UserHome()
+
 	// Search locations in order of priority
 	searchPaths := []string{
 		// Current directory
 		".",
 		// Home dotfiles directory
-		filepath.Join(os.Getenv("HOME"), "dotfiles"),
+		filepath.Join(home, "dotfiles"),
 		// Hidden dotfiles directory
-		filepath.Join(os.Getenv("HOME"), ".dotfiles"),
+		filepath.Join(home, ".dotfiles"),
 	}
 
 	for _, basePath := range searchPaths {
@@ -73,6 +114,12 @@ func LoadFromDiscovery() (*Config, string, error) {
 		return nil, configPath, err
 	}
 
+	// Keep this version to roll back to after a bad edit; a failed
+	// backup must not stop the command
+	if cfg.Validate(filepath.Dir(configPath)) == nil {
+		_ = BackupConfig(configPath)
+	}
+
 	return cfg, configPath, nil
 }
 
@@ -0,0 +1,57 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/nvandessel/go4dot/internal/validation"
+)
+
+func FuzzLoadFromPath(f *testing.F) {
+	examples, _ := filepath.Glob("../../examples/*/.go4dot.yaml")
+	for _, path := range examples {
+		if data, err := os.ReadFile(path); err == nil {
+			f.Add(data)
+		}
+	}
+	for _, seed := range []string{
+		"invalid: yaml: content:\n  - this is\n wrong",
+		"dependencies:\n  critical:\n    - git\n",
+		"dependencies:\n  core:\n    - name: nvim\n      binary: nvim\n      manual: true\n",
+		"configs:\n  core:\n    - name: ../escape\n      path: ../../etc\n",
+		"external:\n  - id: x\n    url: --upload-pack=evil\n    destination: ~/../../etc\n",
+		"configs:\n  core:\n    - name: a\n      path: a\n      depends_on: [a]\n",
+	} {
+		f.Add([]byte("schema_version: \"1.0\"\nmetadata:\n  name: test\n" + seed))
+	}
+
+	// One directory per fuzzing process; each input overwrites the config
+	dir := f.TempDir()
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		if err := os.WriteFile(filepath.Join(dir, ConfigFileName), data, 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		cfg, err := LoadFromPath(dir)
+		if err != nil {
+			return
+		}
+		if cfg.Validate(dir) != nil {
+			return
+		}
+
+		// A valid config never lets unsafe names or URLs through to stow and git
+		for _, item := range cfg.GetAllConfigs() {
+			if err := validation.ValidateConfigName(item.Name); err != nil {
+				t.Errorf("valid config has unsafe config name: %v", err)
+			}
+		}
+		for _, ext := range cfg.External {
+			if err := validation.ValidateGitURL(ext.URL); err != nil {
+				t.Errorf("valid config has unsafe external URL: %v", err)
+			}
+		}
+	})
+}
@@ -0,0 +1,211 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/nvandessel/go4dot/internal/validation"
+	"github.com/nvandessel/go4dot/internal/workspace"
+	"gopkg.in/yaml.v3"
+)
+
+// PresetsDirName is the directory below ~/.config/go4dot holding the
+// machine prompt presets imported with 'g4d machine preset import'
+const PresetsDirName = "presets"
+
+// ErrPresetExists is returned when importing a preset whose ID is already
+// in the library
+var ErrPresetExists = errors.New("preset already exists")
+
+// MachinePreset is a machine_config entry that can be reused across repos
+type MachinePreset struct {
+	Prompt  MachinePrompt
+	BuiltIn bool   // Shipped with go4dot rather than imported
+	Path    string // File in the preset library, empty for built-ins
+}
+
+// Label describes the preset in pickers
+func (p MachinePreset) Label() string {
+	label := p.Prompt.Description
+	if label == "" {
+		label = p.Prompt.ID
+	}
+	if !p.BuiltIn {
+		label += " (imported)"
+	}
+	return label
+}
+
+var builtinPresets = []MachinePrompt{
+	{
+		ID:          "git-signing",
+		Description: "Git Signing Configuration",
+		Destination: "~/.gitconfig.local",
+		Prompts: []PromptField{
+			{ID: "user_name", Prompt: "Git User Name", Type: "text", Required: true},
+			{ID: "user_email", Prompt: "Git Email Address", Type: "text", Required: true},
+			{ID: "signing_key", Prompt: "GPG Signing Key", Type: "text"},
+		},
+		Template: `[user]
+    name = {{ .user_name }}
+    email = {{ .user_email }}
+{{ if .signing_key }}    signingkey = {{ .signing_key }}
+
+[commit]
+    gpgsign = true
+{{ end }}`,
+	},
+}
+
+// PresetsDir returns the directory imported presets are kept in
+func PresetsDir() (string, error) {
+	home, err := workspace.UserHome()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".config", "go4dot", PresetsDirName), nil
+}
+
+// MachinePresets returns the built-in presets followed by the imported
+// ones, sorted by ID. An imported preset replaces a built-in one with the
+// same ID. Preset files that can't be read are left out and reported in the
+// error, so callers may still use the presets returned.
+func MachinePresets() ([]MachinePreset, error) {
+	presets := make([]MachinePreset, 0, len(builtinPresets))
+	for _, p := range builtinPresets {
+		presets = append(presets, MachinePreset{Prompt: p, BuiltIn: true})
+	}
+
+	dir, err := PresetsDir()
+	if err != nil {
+		return presets, err
+	}
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return presets, nil
+	}
+	if err != nil {
+		return presets, fmt.Errorf("failed to read presets: %w", err)
+	}
+
+	var imported []MachinePreset
+	var errs []error
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".yaml" {
+			continue
+		}
+		path := filepath.Join(dir, entry.Name())
+		data, err := os.ReadFile(path)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to read preset %s: %w", entry.Name(), err))
+			continue
+		}
+		mp, err := ParseMachinePreset(data)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("preset %s: %w", entry.Name(), err))
+			continue
+		}
+		imported = append(imported, MachinePreset{Prompt: mp, Path: path})
+	}
+	sort.Slice(imported, func(i, j int) bool {
+		return imported[i].Prompt.ID < imported[j].Prompt.ID
+	})
+
+	for _, p := range imported {
+		replaced := false
+		for i := range presets {
+			if presets[i].BuiltIn && presets[i].Prompt.ID == p.Prompt.ID {
+				presets[i] = p
+				replaced = true
+			}
+		}
+		if !replaced {
+			presets = append(presets, p)
+		}
+	}
+	return presets, errors.Join(errs...)
+}
+
+// FindMachinePreset returns the preset with the given ID, or nil
+func FindMachinePreset(id string) (*MachinePreset, error) {
+	presets, err := MachinePresets()
+	for i := range presets {
+		if presets[i].Prompt.ID == id {
+			return &presets[i], nil
+		}
+	}
+	return nil, err
+}
+
+// ParseMachinePreset reads a preset file: a single machine_config entry
+func ParseMachinePreset(data []byte) (MachinePrompt, error) {
+	var mp MachinePrompt
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(&mp); err != nil {
+		return MachinePrompt{}, fmt.Errorf("failed to parse preset: %w", err)
+	}
+
+	var errs []error
+	if err := validation.ValidateConfigName(mp.ID); err != nil {
+		errs = append(errs, fmt.Errorf("id: %s", strings.Replace(err.Error(), "config name", "preset id", 1)))
+	}
+	if mp.Destination == "" {
+		errs = append(errs, fmt.Errorf("destination: destination is required"))
+	}
+	if mp.Template == "" {
+		errs = append(errs, fmt.Errorf("template: template is required"))
+	}
+	for _, v := range validateMachineConfig(mp, "") {
+		errs = append(errs, fmt.Errorf("%s: %s", strings.TrimPrefix(v.Field, "."), v.Message))
+	}
+	if len(errs) > 0 {
+		return MachinePrompt{}, errors.Join(errs...)
+	}
+	return mp, nil
+}
+
+// EncodeMachinePreset returns mp as a preset file
+func EncodeMachinePreset(mp MachinePrompt) ([]byte, error) {
+	data, err := yaml.Marshal(mp)
+	if err != nil {
+		return nil, err
+	}
+	header := fmt.Sprintf("# go4dot machine prompt preset '%s'\n# Import with: g4d machine preset import <file>\n", mp.ID)
+	return append([]byte(header), data...), nil
+}
+
+// ImportMachinePreset parses a preset file and adds it to the library,
+// replacing an imported preset with the same ID only when force is set
+func ImportMachinePreset(data []byte, force bool) (MachinePreset, error) {
+	mp, err := ParseMachinePreset(data)
+	if err != nil {
+		return MachinePreset{}, err
+	}
+
+	dir, err := PresetsDir()
+	if err != nil {
+		return MachinePreset{}, err
+	}
+	path := filepath.Join(dir, mp.ID+".yaml")
+	if _, err := os.Stat(path); err == nil && !force {
+		return MachinePreset{}, fmt.Errorf("%w: %s", ErrPresetExists, mp.ID)
+	}
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return MachinePreset{}, fmt.Errorf("failed to create presets directory: %w", err)
+	}
+	encoded, err := EncodeMachinePreset(mp)
+	if err != nil {
+		return MachinePreset{}, err
+	}
+	if err := writeFileAtomic(path, encoded); err != nil {
+		return MachinePreset{}, err
+	}
+	return MachinePreset{Prompt: mp, Path: path}, nil
+}
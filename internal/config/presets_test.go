@@ -0,0 +1,116 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/nvandessel/go4dot/internal/workspace"
+)
+
+func TestMachinePresets_RoundTrip(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv(workspace.EnvUserHome, "")
+
+	presets, err := MachinePresets()
+	if err != nil {
+		t.Fatalf("MachinePresets() error = %v", err)
+	}
+	if len(presets) != 1 || presets[0].Prompt.ID != "git-signing" || !presets[0].BuiltIn {
+		t.Fatalf("MachinePresets() = %+v, want the built-in git-signing", presets)
+	}
+
+	mp := MachinePrompt{
+		ID:          "npmrc",
+		Description: "npm registry token",
+		Destination: "~/.npmrc",
+		Prompts:     []PromptField{{ID: "token", Prompt: "Token", Type: "password", Required: true}},
+		Template:    "//registry.npmjs.org/:_authToken={{ .token }}\n",
+	}
+	data, err := EncodeMachinePreset(mp)
+	if err != nil {
+		t.Fatalf("EncodeMachinePreset() error = %v", err)
+	}
+
+	imported, err := ImportMachinePreset(data, false)
+	if err != nil {
+		t.Fatalf("ImportMachinePreset() error = %v", err)
+	}
+	if imported.BuiltIn || filepath.Base(imported.Path) != "npmrc.yaml" {
+		t.Errorf("ImportMachinePreset() = %+v", imported)
+	}
+	if _, err := ImportMachinePreset(data, false); !errors.Is(err, ErrPresetExists) {
+		t.Errorf("second import error = %v, want ErrPresetExists", err)
+	}
+	if _, err := ImportMachinePreset(data, true); err != nil {
+		t.Errorf("forced import error = %v", err)
+	}
+
+	found, err := FindMachinePreset("npmrc")
+	if err != nil || found == nil {
+		t.Fatalf("FindMachinePreset() = %v, %v", found, err)
+	}
+	if found.Prompt.Template != mp.Template || len(found.Prompt.Prompts) != 1 || found.Prompt.Prompts[0].Type != "password" {
+		t.Errorf("preset did not round-trip: %+v", found.Prompt)
+	}
+	if found.Label() != "npm registry token (imported)" {
+		t.Errorf("Label() = %q", found.Label())
+	}
+}
+
+func TestMachinePresets_ImportedReplacesBuiltIn(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv(workspace.EnvUserHome, "")
+
+	custom := builtinPresets[0]
+	custom.Destination = "~/.config/git/local"
+	data, _ := EncodeMachinePreset(custom)
+	if _, err := ImportMachinePreset(data, false); err != nil {
+		t.Fatal(err)
+	}
+
+	// A broken file is reported without hiding the others
+	dir, _ := PresetsDir()
+	if err := os.WriteFile(filepath.Join(dir, "broken.yaml"), []byte("id: [\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	presets, err := MachinePresets()
+	if err == nil || !strings.Contains(err.Error(), "broken.yaml") {
+		t.Errorf("MachinePresets() error = %v, want one naming broken.yaml", err)
+	}
+	if len(presets) != 1 || presets[0].BuiltIn || presets[0].Prompt.Destination != "~/.config/git/local" {
+		t.Errorf("MachinePresets() = %+v, want the imported git-signing only", presets)
+	}
+}
+
+func TestParseMachinePreset(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		wantErr string
+	}{
+		{name: "valid", data: "id: x\ndestination: ~/.x\ntemplate: x\n"},
+		{name: "missing id", data: "destination: ~/.x\ntemplate: x\n", wantErr: "preset id must not be empty"},
+		{name: "path id", data: "id: ../x\ndestination: ~/.x\ntemplate: x\n", wantErr: "invalid characters"},
+		{name: "absolute destination", data: "id: x\ndestination: /etc/x\ntemplate: x\n", wantErr: "must start with ~/"},
+		{name: "missing template", data: "id: x\ndestination: ~/.x\n", wantErr: "template is required"},
+		{name: "unknown field", data: "id: x\ndestination: ~/.x\ntemplate: x\ntemplates: y\n", wantErr: "templates"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseMachinePreset([]byte(tt.data))
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("ParseMachinePreset() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("ParseMachinePreset() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,183 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/nvandessel/go4dot/internal/workspace"
+)
+
+const (
+	// EnvProfile selects the active profile, as --profile does
+	EnvProfile = "GO4DOT_PROFILE"
+
+	// AllProfiles is the profile name that selects every config again
+	AllProfiles = "all"
+)
+
+// ActiveProfileName returns the name of the active profile, or "" when
+// every config is used
+func ActiveProfileName() string {
+	if name := os.Getenv(EnvProfile); name != AllProfiles {
+		return name
+	}
+	return ""
+}
+
+// GetProfile returns the profile with the given name, or nil
+func (c *Config) GetProfile(name string) *Profile {
+	for i := range c.Profiles {
+		if c.Profiles[i].Name == name {
+			return &c.Profiles[i]
+		}
+	}
+	return nil
+}
+
+// ProfileNames returns the names of the config's profiles, in order
+func (c *Config) ProfileNames() []string {
+	names := make([]string, 0, len(c.Profiles))
+	for _, p := range c.Profiles {
+		names = append(names, p.Name)
+	}
+	return names
+}
+
+// ProfileCount describes how many items of a kind a profile selects from
+// one of its lists: "all" when the list is empty, else its length
+func ProfileCount(names []string) string {
+	if len(names) == 0 {
+		return AllProfiles
+	}
+	return fmt.Sprintf("%d", len(names))
+}
+
+// UnknownProfileError reports that no profile is called name, suggesting
+// the closest profile name
+func (c *Config) UnknownProfileError(name string) error {
+	if len(c.Profiles) == 0 {
+		return fmt.Errorf("unknown profile '%s': the config defines no profiles", name)
+	}
+	if s := Suggest(name, c.ProfileNames()); s != "" {
+		return fmt.Errorf("unknown profile '%s', did you mean '%s'?", name, s)
+	}
+	return fmt.Errorf("unknown profile '%s' (available: %s)", name, strings.Join(c.ProfileNames(), ", "))
+}
+
+// ApplyProfile narrows c to the configs, dependencies and external
+// dependencies the named profile selects. An empty name, or AllProfiles,
+// leaves c as it is.
+func (c *Config) ApplyProfile(name string) error {
+	if name == "" || name == AllProfiles {
+		return nil
+	}
+	p := c.GetProfile(name)
+	if p == nil {
+		return c.UnknownProfileError(name)
+	}
+	c.ActiveProfile = name
+
+	if len(p.Configs) > 0 {
+		keep := nameSet(p.Configs)
+		c.Configs.Core = filterItems(c.Configs.Core, keep, func(item ConfigItem) string { return item.Name })
+		c.Configs.Optional = filterItems(c.Configs.Optional, keep, func(item ConfigItem) string { return item.Name })
+	}
+	if len(p.Dependencies) > 0 {
+		keep := nameSet(p.Dependencies)
+		depName := func(d DependencyItem) string { return d.Name }
+		c.Dependencies.Critical = filterItems(c.Dependencies.Critical, keep, depName)
+		c.Dependencies.Core = filterItems(c.Dependencies.Core, keep, depName)
+		c.Dependencies.Optional = filterItems(c.Dependencies.Optional, keep, depName)
+	}
+	if len(p.External) > 0 {
+		keep := nameSet(p.External)
+		externalID := func(e ExternalDep) string { return e.ID }
+		c.External = filterItems(c.External, keep, externalID)
+		for _, group := range [][]ConfigItem{c.Configs.Core, c.Configs.Optional} {
+			for i := range group {
+				group[i].ExternalDeps = filterItems(group[i].ExternalDeps, keep, externalID)
+			}
+		}
+	}
+	return nil
+}
+
+// nameSet returns names as a set
+func nameSet(names []string) map[string]bool {
+	set := make(map[string]bool, len(names))
+	for _, name := range names {
+		set[name] = true
+	}
+	return set
+}
+
+// filterItems returns the items whose key is in keep
+func filterItems[T any](items []T, keep map[string]bool, key func(T) string) []T {
+	var out []T
+	for _, item := range items {
+		if keep[key(item)] {
+			out = append(out, item)
+		}
+	}
+	return out
+}
+
+// validateProfiles checks profile names and, unless the config was already
+// narrowed to a profile or workspace, that the items each profile selects
+// exist
+func (c *Config) validateProfiles() []ValidationError {
+	var errs []ValidationError
+
+	configs := nameSet(c.ConfigNames())
+	for _, item := range c.Archived {
+		configs[item.Name] = true
+	}
+	deps := make(map[string]bool)
+	for _, d := range c.GetAllDependencies() {
+		deps[d.Name] = true
+	}
+	externals := nameSet(c.ExternalIDs())
+	for _, item := range c.GetAllConfigs() {
+		for _, e := range item.ExternalDeps {
+			externals[e.ID] = true
+		}
+	}
+
+	seen := make(map[string]bool)
+	for i, p := range c.Profiles {
+		field := fmt.Sprintf("profiles[%d]", i)
+		switch {
+		case p.Name == "":
+			errs = append(errs, ValidationError{Field: field + ".name", Message: "name is required"})
+		case p.Name == AllProfiles:
+			errs = append(errs, ValidationError{Field: field + ".name", Message: fmt.Sprintf("'%s' is reserved for selecting every config", AllProfiles)})
+		case seen[p.Name]:
+			errs = append(errs, ValidationError{Field: field + ".name", Message: fmt.Sprintf("duplicate profile name: %s", p.Name)})
+		}
+		seen[p.Name] = true
+
+		if c.ActiveProfile != "" || workspace.Active() != "" {
+			continue
+		}
+		for _, ref := range []struct {
+			field, kind string
+			names       []string
+			known       map[string]bool
+		}{
+			{"configs", "config", p.Configs, configs},
+			{"dependencies", "dependency", p.Dependencies, deps},
+			{"external", "external dependency", p.External, externals},
+		} {
+			for j, name := range ref.names {
+				if !ref.known[name] {
+					errs = append(errs, ValidationError{
+						Field:   fmt.Sprintf("%s.%s[%d]", field, ref.field, j),
+						Message: fmt.Sprintf("unknown %s: %s", ref.kind, name),
+					})
+				}
+			}
+		}
+	}
+	return errs
+}
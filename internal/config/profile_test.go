@@ -0,0 +1,156 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func profileTestConfig() *Config {
+	return &Config{
+		Configs: ConfigGroups{
+			Core: []ConfigItem{
+				{Name: "git", Path: "git"},
+				{Name: "zsh", Path: "zsh", ExternalDeps: []ExternalDep{{ID: "zsh-autosuggestions"}, {ID: "powerlevel10k"}}},
+			},
+			Optional: []ConfigItem{{Name: "games", Path: "games"}},
+		},
+		Dependencies: Dependencies{
+			Critical: []DependencyItem{{Name: "git"}},
+			Core:     []DependencyItem{{Name: "zsh"}, {Name: "docker"}},
+		},
+		External: []ExternalDep{{ID: "tpm"}, {ID: "fonts"}},
+		Profiles: []Profile{
+			{Name: "server", Configs: []string{"git", "zsh"}, Dependencies: []string{"git", "zsh"}, External: []string{"tpm", "zsh-autosuggestions"}},
+			{Name: "laptop", Configs: []string{"zsh", "games"}},
+		},
+	}
+}
+
+func TestApplyProfile(t *testing.T) {
+	tests := []struct {
+		name         string
+		profile      string
+		wantConfigs  string
+		wantDeps     string
+		wantExternal string
+		wantErr      string
+	}{
+		{name: "selects every kind", profile: "server", wantConfigs: "git,zsh", wantDeps: "git,zsh", wantExternal: "tpm,zsh-autosuggestions"},
+		{name: "configs only", profile: "laptop", wantConfigs: "zsh,games", wantDeps: "git,zsh,docker", wantExternal: "tpm,fonts,zsh-autosuggestions,powerlevel10k"},
+		{name: "no profile", profile: "", wantConfigs: "git,zsh,games", wantDeps: "git,zsh,docker", wantExternal: "tpm,fonts,zsh-autosuggestions,powerlevel10k"},
+		{name: "all", profile: AllProfiles, wantConfigs: "git,zsh,games", wantDeps: "git,zsh,docker", wantExternal: "tpm,fonts,zsh-autosuggestions,powerlevel10k"},
+		{name: "unknown profile", profile: "servr", wantErr: "did you mean 'server'?"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := profileTestConfig()
+			err := cfg.ApplyProfile(tt.profile)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ApplyProfile failed: %v", err)
+			}
+
+			var configs, deps, external []string
+			for _, c := range cfg.GetAllConfigs() {
+				configs = append(configs, c.Name)
+			}
+			for _, d := range cfg.GetAllDependencies() {
+				deps = append(deps, d.Name)
+			}
+			external = cfg.ExternalIDs()
+			for _, c := range cfg.GetAllConfigs() {
+				for _, e := range c.ExternalDeps {
+					external = append(external, e.ID)
+				}
+			}
+			if got := strings.Join(configs, ","); got != tt.wantConfigs {
+				t.Errorf("configs = %s, want %s", got, tt.wantConfigs)
+			}
+			if got := strings.Join(deps, ","); got != tt.wantDeps {
+				t.Errorf("dependencies = %s, want %s", got, tt.wantDeps)
+			}
+			if got := strings.Join(external, ","); got != tt.wantExternal {
+				t.Errorf("external = %s, want %s", got, tt.wantExternal)
+			}
+		})
+	}
+}
+
+func TestProfileCount(t *testing.T) {
+	if got := ProfileCount(nil); got != "all" {
+		t.Errorf("ProfileCount(nil) = %q, want all", got)
+	}
+	if got := ProfileCount([]string{"nvim", "git"}); got != "2" {
+		t.Errorf("ProfileCount() = %q, want 2", got)
+	}
+}
+
+func TestLoad_ActiveProfile(t *testing.T) {
+	t.Setenv(EnvProfile, "work")
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, ConfigFileName)
+	data := `schema_version: "1.0"
+configs:
+  core:
+    - name: git
+      path: git
+    - name: games
+      path: games
+profiles:
+  - name: work
+    configs: [git]
+`
+	if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if cfg.ActiveProfile != "work" {
+		t.Errorf("ActiveProfile = %q, want work", cfg.ActiveProfile)
+	}
+	if configs := cfg.GetAllConfigs(); len(configs) != 1 || configs[0].Name != "git" {
+		t.Errorf("expected only the profile's configs, got %v", configs)
+	}
+	if len(cfg.Profiles) != 1 {
+		t.Errorf("expected the profiles to be kept for switching, got %v", cfg.Profiles)
+	}
+}
+
+func TestValidate_Profiles(t *testing.T) {
+	cfg := profileTestConfig()
+	cfg.Profiles = append(cfg.Profiles,
+		Profile{Name: "server"},
+		Profile{Name: AllProfiles},
+		Profile{},
+		Profile{Name: "typos", Configs: []string{"vim"}, Dependencies: []string{"node"}, External: []string{"tmp"}},
+	)
+
+	err := cfg.Validate(t.TempDir())
+	if err == nil {
+		t.Fatal("expected validation errors")
+	}
+	for _, want := range []string{
+		"profiles[2].name: duplicate profile name: server",
+		"profiles[3].name: 'all' is reserved",
+		"profiles[4].name: name is required",
+		"profiles[5].configs[0]: unknown config: vim",
+		"profiles[5].dependencies[0]: unknown dependency: node",
+		"profiles[5].external[0]: unknown external dependency: tmp",
+	} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("expected %q in %v", want, err)
+		}
+	}
+}
@@ -0,0 +1,181 @@
+package config
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// IgnoreFileName is the repo-level ignore file. Paths it matches are hidden
+// from scanning, drift detection and doctor checks; it does not change what
+// stow links (use .stow-local-ignore for that).
+const IgnoreFileName = ".go4dotignore"
+
+// RepoIgnore holds the patterns of a .go4dotignore file. The syntax is that
+// of .gitignore: "#" comments, "!" negation, a trailing "/" to match only
+// directories, a leading or inner "/" to anchor at the repo root, and the
+// wildcards "*", "?", "[...]" and "**".
+type RepoIgnore struct {
+	rules []ignoreRule
+}
+
+type ignoreRule struct {
+	re      *regexp.Regexp
+	negate  bool
+	dirOnly bool
+}
+
+// LoadRepoIgnore reads the .go4dotignore in repoRoot. A missing file yields
+// an empty list.
+func LoadRepoIgnore(repoRoot string) (*RepoIgnore, error) {
+	path := filepath.Join(repoRoot, IgnoreFileName)
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return &RepoIgnore{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", IgnoreFileName, err)
+	}
+	defer func() { _ = f.Close() }()
+
+	var lines []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", IgnoreFileName, err)
+	}
+
+	ignore, err := ParseRepoIgnore(lines)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", IgnoreFileName, err)
+	}
+	return ignore, nil
+}
+
+// ParseRepoIgnore compiles .go4dotignore lines
+func ParseRepoIgnore(lines []string) (*RepoIgnore, error) {
+	ignore := &RepoIgnore{}
+	for i, line := range lines {
+		line = strings.TrimRight(line, " \t\r")
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		var rule ignoreRule
+		if strings.HasPrefix(line, "!") {
+			rule.negate = true
+			line = line[1:]
+		} else if strings.HasPrefix(line, `\!`) || strings.HasPrefix(line, `\#`) {
+			line = line[1:]
+		}
+		if strings.HasSuffix(line, "/") {
+			rule.dirOnly = true
+			line = strings.TrimRight(line, "/")
+		}
+		if line == "" {
+			continue
+		}
+
+		// A slash anywhere but the end anchors the pattern at the root;
+		// otherwise it matches a name at any depth
+		anchored := strings.Contains(line, "/")
+		line = strings.TrimPrefix(line, "/")
+
+		expr := globToRegexp(line)
+		if !anchored {
+			expr = "(?:.*/)?" + expr
+		}
+		re, err := regexp.Compile("^" + expr + "$")
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid pattern %q: %w", i+1, lines[i], err)
+		}
+		rule.re = re
+		ignore.rules = append(ignore.rules, rule)
+	}
+	return ignore, nil
+}
+
+// globToRegexp translates a gitignore glob into a regular expression
+func globToRegexp(glob string) string {
+	var sb strings.Builder
+	for i := 0; i < len(glob); i++ {
+		c := glob[i]
+		switch c {
+		case '*':
+			if strings.HasPrefix(glob[i:], "**") {
+				switch {
+				case strings.HasPrefix(glob[i:], "**/"):
+					sb.WriteString("(?:.*/)?")
+					i += 2
+				default:
+					sb.WriteString(".*")
+					i++
+				}
+				continue
+			}
+			sb.WriteString("[^/]*")
+		case '?':
+			sb.WriteString("[^/]")
+		case '[':
+			end := strings.IndexByte(glob[i+1:], ']')
+			if end < 0 {
+				sb.WriteString(`\[`)
+				continue
+			}
+			class := glob[i+1 : i+1+end]
+			if strings.HasPrefix(class, "!") {
+				class = "^" + class[1:]
+			}
+			sb.WriteString("[" + strings.ReplaceAll(class, `\`, `\\`) + "]")
+			i += end + 1
+		case '\\':
+			if i+1 < len(glob) {
+				i++
+				sb.WriteString(regexp.QuoteMeta(string(glob[i])))
+			}
+		default:
+			sb.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	return sb.String()
+}
+
+// Match reports whether relPath (relative to the repo root) is ignored. As
+// in git, a file inside an ignored directory is ignored and cannot be
+// re-included. A nil list matches nothing.
+func (r *RepoIgnore) Match(relPath string, isDir bool) bool {
+	if r == nil || len(r.rules) == 0 {
+		return false
+	}
+	relPath = filepath.ToSlash(filepath.Clean(relPath))
+	if relPath == "." || relPath == "" || strings.HasPrefix(relPath, "../") {
+		return false
+	}
+
+	parts := strings.Split(relPath, "/")
+	for i := 1; i < len(parts); i++ {
+		if r.matchPath(strings.Join(parts[:i], "/"), true) {
+			return true
+		}
+	}
+	return r.matchPath(relPath, isDir)
+}
+
+// matchPath applies the rules to a single path; the last matching rule wins
+func (r *RepoIgnore) matchPath(path string, isDir bool) bool {
+	ignored := false
+	for _, rule := range r.rules {
+		if rule.dirOnly && !isDir {
+			continue
+		}
+		if rule.re.MatchString(path) {
+			ignored = !rule.negate
+		}
+	}
+	return ignored
+}
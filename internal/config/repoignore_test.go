@@ -0,0 +1,127 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRepoIgnore_Match(t *testing.T) {
+	ignore, err := ParseRepoIgnore([]string{
+		"# scratch space",
+		"",
+		"experiments/",
+		"*.tmp",
+		"/notes.md",
+		"nvim/.config/nvim/spell/",
+		"**/drafts/*.lua",
+		"logs/**",
+		"!logs/keep.log",
+		"build?",
+		"[Bb]ackup",
+		`\#notes#`,
+	})
+	if err != nil {
+		t.Fatalf("ParseRepoIgnore() error = %v", err)
+	}
+
+	tests := []struct {
+		path  string
+		isDir bool
+		want  bool
+	}{
+		{"experiments", true, true},
+		{"experiments", false, false}, // Trailing slash only matches directories
+		{"experiments/a/b.conf", false, true},
+		{"zsh/experiments/x", false, true},
+		{"scratch.tmp", false, true},
+		{"zsh/.cache/x.tmp", false, true},
+		{"notes.md", false, true},
+		{"zsh/notes.md", false, false}, // Leading slash anchors at the root
+		{"nvim/.config/nvim/spell/en.utf-8.add", false, true},
+		{"spell/en.add", false, false},
+		{"nvim/drafts/test.lua", false, true},
+		{"drafts/test.lua", false, true},
+		{"drafts/sub/test.lua", false, false},
+		{"logs/a.log", false, true},
+		{"logs/keep.log", false, false}, // Re-included by negation
+		{"build1", true, true},
+		{"build", true, false},
+		{"Backup", true, true},
+		{"backup", false, true},
+		{"#notes#", false, true},
+		{"zsh/.zshrc", false, false},
+		{".", true, false},
+	}
+
+	for _, tt := range tests {
+		if got := ignore.Match(tt.path, tt.isDir); got != tt.want {
+			t.Errorf("Match(%q, %v) = %v, want %v", tt.path, tt.isDir, got, tt.want)
+		}
+	}
+}
+
+func TestRepoIgnore_IgnoredDirCannotBeReincluded(t *testing.T) {
+	ignore, err := ParseRepoIgnore([]string{"scratch/", "!scratch/keep.conf"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ignore.Match("scratch/keep.conf", false) {
+		t.Error("file in an ignored directory should stay ignored")
+	}
+}
+
+func TestRepoIgnore_Nil(t *testing.T) {
+	var ignore *RepoIgnore
+	if ignore.Match("anything", false) {
+		t.Error("nil RepoIgnore should match nothing")
+	}
+}
+
+func TestLoadRepoIgnore(t *testing.T) {
+	dir := t.TempDir()
+
+	ignore, err := LoadRepoIgnore(dir)
+	if err != nil {
+		t.Fatalf("LoadRepoIgnore() without file error = %v", err)
+	}
+	if ignore.Match("anything", true) {
+		t.Error("missing .go4dotignore should match nothing")
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, IgnoreFileName), []byte("wip/\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	ignore, err = LoadRepoIgnore(dir)
+	if err != nil {
+		t.Fatalf("LoadRepoIgnore() error = %v", err)
+	}
+	if !ignore.Match("wip", true) {
+		t.Error("wip/ should be ignored")
+	}
+}
+
+func TestScanDirectory_RepoIgnore(t *testing.T) {
+	dir := t.TempDir()
+	for _, d := range []string{"nvim", "zsh", "wip-theme", ".config"} {
+		if err := os.MkdirAll(filepath.Join(dir, d), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(dir, IgnoreFileName), []byte("wip-*/\n.config/\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	items, err := scanDirectory(dir)
+	if err != nil {
+		t.Fatalf("scanDirectory() error = %v", err)
+	}
+
+	var names []string
+	for _, item := range items {
+		names = append(names, item.Name)
+	}
+	if len(names) != 2 || names[0] != "nvim" || names[1] != "zsh" {
+		t.Errorf("scanDirectory() = %v, want [nvim zsh]", names)
+	}
+}
@@ -2,7 +2,9 @@ package config
 
 import (
 	"fmt"
+	"io/fs"
 	"os"
+	"path/filepath"
 	"regexp"
 	"strings"
 )
@@ -21,6 +23,8 @@ func scanDirectory(root string) ([]ConfigItem, error) {
 
 	var items []ConfigItem
 
+	repoIgnore, _ := LoadRepoIgnore(root)
+
 	// Directories to always ignore (not dotfiles-related)
 	// Note: .vim and .nvim are NOT in this list - they're valid dotfile configs
 	// handled by the validHiddenDirs allowlist below
@@ -63,8 +67,8 @@ func scanDirectory(root string) ([]ConfigItem, error) {
 	for _, entry := range entries {
 		name := entry.Name()
 
-		// Check explicit ignore list
-		if ignored[name] {
+		// Check explicit ignore list and .go4dotignore
+		if ignored[name] || repoIgnore.Match(name, entry.IsDir()) {
 			continue
 		}
 
@@ -108,6 +112,29 @@ func scanDirectory(root string) ([]ConfigItem, error) {
 	return items, nil
 }
 
+// UsesDotPrefix reports whether any of configs under root holds files or
+// directories named the stow --dotfiles way (dot-bashrc for .bashrc), so
+// the generated config should pass --dotfiles to stow.
+func UsesDotPrefix(root string, configs []ConfigItem) bool {
+	found := false
+	for _, item := range configs {
+		_ = filepath.WalkDir(item.Dir(root), func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return nil
+			}
+			if HasDotPrefix(d.Name()) {
+				found = true
+				return filepath.SkipAll
+			}
+			return nil
+		})
+		if found {
+			return true
+		}
+	}
+	return false
+}
+
 // slugify converts a string to a URL-friendly slug.
 // It lowercases the string and replaces non-alphanumeric characters with hyphens.
 func slugify(s string) string {
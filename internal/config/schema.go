@@ -4,13 +4,31 @@ package config
 type Config struct {
 	SchemaVersion string          `yaml:"schema_version"`
 	Metadata      Metadata        `yaml:"metadata"`
+	Extends       Extends         `yaml:"extends,omitempty"`
 	Dependencies  Dependencies    `yaml:"dependencies"`
 	Configs       ConfigGroups    `yaml:"configs"`
 	External      []ExternalDep   `yaml:"external"`
 	MachineConfig []MachinePrompt `yaml:"machine_config"`
+	Toolchains    []Toolchain     `yaml:"toolchains,omitempty"`
+	Links         []Link          `yaml:"links,omitempty"`
 	Machines      []MachineProfile `yaml:"machines"`
+	Inventory     Inventory       `yaml:"inventory,omitempty"`
+	Workspaces    []Workspace     `yaml:"workspaces,omitempty"`
+	Profiles      []Profile       `yaml:"profiles,omitempty"`
+	ShellIntegration ShellIntegration `yaml:"shell_integration,omitempty"`
+	Doctor        DoctorSettings  `yaml:"doctor,omitempty"`
+	Stow          StowSettings    `yaml:"stow,omitempty"`
+	Env           map[string]string `yaml:"env,omitempty"` // Extra environment for hooks, installers and git
+	Hooks         Hooks           `yaml:"hooks,omitempty"` // Commands run before and after install and sync
 	Archived      []ConfigItem    `yaml:"archived"`
-	PostInstall   string          `yaml:"post_install"`
+	PostInstall   string          `yaml:"post_install"` // Message shown when install finishes
+
+	// Set by Load when the config extends base repos
+	Origins      map[string]string `yaml:"-"` // Entry key (see OriginKey) to the URL of the base it came from
+	MissingBases []string          `yaml:"-"` // URLs of bases that are not cloned yet
+
+	// Set by Load: the profile the config was narrowed to, if any
+	ActiveProfile string `yaml:"-"`
 }
 
 // Metadata contains project information
@@ -34,7 +52,7 @@ type Dependencies struct {
 type DependencyItem struct {
 	Name       string            `yaml:"name"`
 	Binary     string            `yaml:"binary"`      // Binary name to check in PATH
-	Package    map[string]string `yaml:"package"`     // Package name per manager
+	Package    map[string]string `yaml:"package"`     // Package name per manager; a manager/arch key (apt/arm64) overrides it on that architecture
 	Version    string            `yaml:"version"`     // Required version (e.g. "0.11+")
 	VersionCmd string            `yaml:"version_cmd"` // Command to check version (defaults to --version)
 	Manual     bool              `yaml:"manual"`      // If true, skip automated install (user must install manually)
@@ -67,11 +85,17 @@ type ConfigItem struct {
 	Name                  string            `yaml:"name"`
 	Path                  string            `yaml:"path"`
 	Description           string            `yaml:"description"`
+	Tags                  []string          `yaml:"tags,omitempty"` // Free-form labels, shown in the dashboard's Details panel
 	Platforms             []string          `yaml:"platforms"`
 	Condition             map[string]string `yaml:"condition"`  // Platform/machine conditions (more flexible than platforms)
 	DependsOn             []string          `yaml:"depends_on"`
 	ExternalDeps          []ExternalDep     `yaml:"external_deps,omitempty"`
 	RequiresMachineConfig bool              `yaml:"requires_machine_config"`
+	OnConflict            string            `yaml:"on_conflict,omitempty"` // Default for existing files in the way: backup, skip, overwrite or ask (default)
+	Archived              bool              `yaml:"archived,omitempty"`    // Kept in the repo but left out of sync, doctor and status
+	Verify                string            `yaml:"verify,omitempty"`      // Command that exits 0 when the linked config works, run by doctor and after sync
+	Hooks                 Hooks             `yaml:"hooks,omitempty"`       // Commands run before and after the config is installed or synced
+	Root                  string            `yaml:"-"` // Repo the config lives in when inherited from a base; empty for the repo's own configs
 }
 
 // ExternalDep represents an external dependency to clone (plugins, themes, etc.)
@@ -80,11 +104,31 @@ type ExternalDep struct {
 	ID            string            `yaml:"id"`
 	URL           string            `yaml:"url"`
 	Destination   string            `yaml:"destination"`
-	Method        string            `yaml:"method"`         // "clone" or "copy"
+	Method        string            `yaml:"method"`         // "clone", "copy" or "github_release"
 	MergeStrategy string            `yaml:"merge_strategy"` // "overwrite" (default) or "keep_existing"
+	Asset         string            `yaml:"asset"`          // Release asset pattern for github_release, e.g. "tool_*_{os}_{arch}.tar.gz"
 	Condition     map[string]string `yaml:"condition"`
 }
 
+// Link is a symlink that belongs to no config, such as ~/bin pointing to
+// the repo's scripts directory. Sync creates it and uninstall removes it.
+// In copy mode sync copies the source file instead, and leaves it in place
+// once the link is gone.
+type Link struct {
+	Target string `yaml:"target"`          // Where the symlink is created; starts with ~/ or a well-known target's prefix
+	Source string `yaml:"source"`          // What it points to; starts with @repoRoot/ or ~/
+	Mode   string `yaml:"mode,omitempty"`  // "symlink" (default) or "copy"; links to well-known targets are always copied
+	Merge  string `yaml:"merge,omitempty"` // How a copy updates an existing file: "replace" (default) or "json"
+}
+
+// Toolchain is a language's version manager (rustup, nvm or pyenv) and the
+// versions install sets up with it
+type Toolchain struct {
+	Language  string            `yaml:"language"`  // rust, node or python
+	Versions  []string          `yaml:"versions"`  // Versions to install; the first is made the default
+	Condition map[string]string `yaml:"condition"` // Platform/machine conditions for this toolchain
+}
+
 // MachinePrompt represents machine-specific configuration prompts
 type MachinePrompt struct {
 	ID          string        `yaml:"id"`
@@ -103,6 +147,63 @@ type MachineProfile struct {
 	Defaults       map[string]string `yaml:"defaults"`        // Default values for machine_config prompts
 }
 
+// Inventory controls the per-machine records committed to the repo's
+// machines/ directory (opt-in)
+type Inventory struct {
+	Enabled   bool `yaml:"enabled"`              // Record this machine on every sync
+	StaleDays int  `yaml:"stale_days,omitempty"` // Days without a sync before a machine is stale (default 30)
+}
+
+// ShellIntegration controls the snippet printed by g4d shell-init, which
+// install sources from the shells' rc files (opt-in)
+type ShellIntegration struct {
+	Enabled bool              `yaml:"enabled"`            // Source the snippet from the rc files on install
+	Shells  []string          `yaml:"shells,omitempty"`   // Shells to wire up: zsh, bash, fish (default: the login shell)
+	Aliases map[string]string `yaml:"aliases,omitempty"`  // Extra aliases, name to command
+	DirHook string            `yaml:"dir_hook,omitempty"` // On cd into a managed directory: warn (default) or off
+	MOTD    bool              `yaml:"motd,omitempty"`     // Run g4d motd on shell start, which prints at most once a day
+}
+
+// DoctorSettings changes how doctor check results count, for warnings that
+// are intentional on some machines. Entries are check IDs (see DoctorChecks).
+type DoctorSettings struct {
+	Ignore      []string `yaml:"ignore,omitempty"`        // Checks whose result never counts
+	WarnAsError []string `yaml:"warn_as_error,omitempty"` // Checks whose warnings count as errors
+	Allow       []string `yaml:"allow,omitempty"`         // Regular expressions for values the personal-data check accepts
+
+	ScanRoots  []ScanRoot `yaml:"scan_roots,omitempty"`  // Directories the unmanaged-symlinks check walks (default: home and ~/.config, depth 1)
+	ScanBudget int        `yaml:"scan_budget,omitempty"` // Most directory entries that check looks at (default: DefaultScanBudget)
+}
+
+// StowSettings changes how GNU stow is run, for repos laid out for stow
+// flags like --dotfiles or a stow installed outside PATH
+type StowSettings struct {
+	Binary    string   `yaml:"binary,omitempty"`    // stow executable, a name in PATH or a path (default: stow)
+	Flags     []string `yaml:"flags,omitempty"`     // Extra flags, from StowFlags
+	Target    string   `yaml:"target,omitempty"`    // Directory configs are linked into; ~ is $HOME (default)
+	Verbosity int      `yaml:"verbosity,omitempty"` // stow -v level, 1-5 (default 1)
+}
+
+// Workspace links configs into a target root other than the user's home,
+// with its own state. It is selected with --workspace.
+type Workspace struct {
+	Name        string `yaml:"name"`
+	Description string `yaml:"description"`
+	Target      string `yaml:"target"`  // Root configs are linked into; ~ is the user's home
+	Profile     string `yaml:"profile"` // Name of the machine profile whose include/exclude lists select the configs (empty = all)
+}
+
+// Profile is a machine role, such as work, laptop or server, that selects
+// the configs, dependencies and external dependencies installed for it.
+// An empty list keeps every item of that kind.
+type Profile struct {
+	Name         string   `yaml:"name"`
+	Description  string   `yaml:"description"`
+	Configs      []string `yaml:"configs,omitempty"`      // Config names
+	Dependencies []string `yaml:"dependencies,omitempty"` // Dependency names
+	External     []string `yaml:"external,omitempty"`     // External dependency IDs, top-level or a config's
+}
+
 // PromptField represents a single prompt for user input
 type PromptField struct {
 	ID       string   `yaml:"id"`
@@ -0,0 +1,81 @@
+package config
+
+import (
+	"fmt"
+	"maps"
+	"path/filepath"
+	"regexp"
+	"slices"
+)
+
+// Shells shell_integration can generate a snippet for
+var Shells = []string{"zsh", "bash", "fish"}
+
+// shell_integration.dir_hook values
+const (
+	DirHookWarn = "warn"
+	DirHookOff  = "off"
+)
+
+// aliasNamePattern matches names that are valid aliases in every shell
+var aliasNamePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_.-]*$`)
+
+// IsShell reports whether name is a supported shell
+func IsShell(name string) bool {
+	for _, s := range Shells {
+		if s == name {
+			return true
+		}
+	}
+	return false
+}
+
+// ShellsFor returns the shells to wire up: the configured ones, or the
+// login shell (a path such as $SHELL) when none are set and it is supported
+func (s ShellIntegration) ShellsFor(loginShell string) []string {
+	if len(s.Shells) > 0 {
+		return s.Shells
+	}
+	if name := filepath.Base(loginShell); IsShell(name) {
+		return []string{name}
+	}
+	return nil
+}
+
+// WarnsInManagedDirs reports whether the snippet warns on cd into a
+// managed directory
+func (s ShellIntegration) WarnsInManagedDirs() bool {
+	return s.DirHook != DirHookOff
+}
+
+// AliasNames returns the names of the extra aliases, sorted
+func (s ShellIntegration) AliasNames() []string {
+	return slices.Sorted(maps.Keys(s.Aliases))
+}
+
+func (s ShellIntegration) validate() []ValidationError {
+	var errors []ValidationError
+	for i, shell := range s.Shells {
+		if !IsShell(shell) {
+			errors = append(errors, ValidationError{
+				Field:   fmt.Sprintf("shell_integration.shells[%d]", i),
+				Message: fmt.Sprintf("unsupported shell %q (supported: zsh, bash, fish)", shell),
+			})
+		}
+	}
+	if s.DirHook != "" && s.DirHook != DirHookWarn && s.DirHook != DirHookOff {
+		errors = append(errors, ValidationError{
+			Field:   "shell_integration.dir_hook",
+			Message: fmt.Sprintf("dir_hook must be %s or %s", DirHookWarn, DirHookOff),
+		})
+	}
+	for _, name := range s.AliasNames() {
+		field := fmt.Sprintf("shell_integration.aliases.%s", name)
+		if !aliasNamePattern.MatchString(name) {
+			errors = append(errors, ValidationError{Field: field, Message: "alias names may only contain letters, digits, _, . and -"})
+		} else if s.Aliases[name] == "" {
+			errors = append(errors, ValidationError{Field: field, Message: "command is required"})
+		}
+	}
+	return errors
+}
@@ -0,0 +1,74 @@
+package config
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestShellIntegration_Validate(t *testing.T) {
+	tests := []struct {
+		name   string
+		si     ShellIntegration
+		fields []string
+	}{
+		{
+			name: "valid",
+			si: ShellIntegration{
+				Enabled: true,
+				Shells:  []string{"zsh", "fish"},
+				Aliases: map[string]string{"g4du": "g4d update", "dots.edit": "$EDITOR ."},
+				DirHook: DirHookOff,
+			},
+		},
+		{
+			name:   "unsupported shell",
+			si:     ShellIntegration{Shells: []string{"zsh", "tcsh"}},
+			fields: []string{"shell_integration.shells[1]"},
+		},
+		{
+			name:   "unknown dir_hook",
+			si:     ShellIntegration{DirHook: "loud"},
+			fields: []string{"shell_integration.dir_hook"},
+		},
+		{
+			name:   "bad alias name and empty command",
+			si:     ShellIntegration{Aliases: map[string]string{"rm -rf": "x", "empty": ""}},
+			fields: []string{"shell_integration.aliases.empty", "shell_integration.aliases.rm -rf"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var fields []string
+			for _, e := range tt.si.validate() {
+				fields = append(fields, e.Field)
+			}
+			if !reflect.DeepEqual(fields, tt.fields) {
+				t.Errorf("validate() fields = %v, want %v", fields, tt.fields)
+			}
+		})
+	}
+}
+
+func TestShellIntegration_ShellsFor(t *testing.T) {
+	tests := []struct {
+		name       string
+		shells     []string
+		loginShell string
+		want       []string
+	}{
+		{"configured shells win", []string{"bash", "fish"}, "/bin/zsh", []string{"bash", "fish"}},
+		{"login shell", nil, "/usr/bin/zsh", []string{"zsh"}},
+		{"unsupported login shell", nil, "/bin/tcsh", nil},
+		{"no login shell", nil, "", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			si := ShellIntegration{Shells: tt.shells}
+			if got := si.ShellsFor(tt.loginShell); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ShellsFor(%q) = %v, want %v", tt.loginShell, got, tt.want)
+			}
+		})
+	}
+}
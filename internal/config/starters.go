@@ -0,0 +1,260 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// Starter describes a config package that 'g4d new' can scaffold for a
+// well-known tool
+type Starter struct {
+	Name        string            // Config name and package directory
+	Description string            // Description written to .go4dot.yaml
+	Files       map[string]string // Package-relative path -> default content
+	Dependency  DependencyItem    // Dependency the tool needs
+}
+
+var starters = []Starter{
+	{
+		Name:        "alacritty",
+		Description: "Alacritty terminal configuration",
+		Dependency:  DependencyItem{Name: "alacritty", Binary: "alacritty"},
+		Files: map[string]string{
+			".config/alacritty/alacritty.toml": `# Alacritty configuration
+# Reference: https://alacritty.org/config-alacritty.html
+
+[window]
+padding = { x = 8, y = 8 }
+dynamic_padding = true
+
+[font]
+size = 12.0
+
+[scrolling]
+history = 10000
+
+[selection]
+save_to_clipboard = true
+`,
+		},
+	},
+	{
+		Name:        "bash",
+		Description: "Bash shell configuration",
+		Dependency:  DependencyItem{Name: "bash", Binary: "bash"},
+		Files: map[string]string{
+			".bashrc": `# ~/.bashrc
+
+# Stop here for non-interactive shells
+[[ $- != *i* ]] && return
+
+HISTSIZE=10000
+HISTFILESIZE=20000
+HISTCONTROL=ignoreboth
+shopt -s histappend checkwinsize
+
+alias ls='ls --color=auto'
+alias ll='ls -lah'
+`,
+		},
+	},
+	{
+		Name:        "git",
+		Description: "Git configuration",
+		Dependency:  DependencyItem{Name: "git", Binary: "git"},
+		Files: map[string]string{
+			".gitconfig": `[user]
+	# name = Your Name
+	# email = you@example.com
+
+[init]
+	defaultBranch = main
+
+[pull]
+	rebase = true
+
+[push]
+	autoSetupRemote = true
+
+[core]
+	excludesFile = ~/.gitignore_global
+`,
+			".gitignore_global": `.DS_Store
+*.swp
+*~
+`,
+		},
+	},
+	{
+		Name:        "kitty",
+		Description: "Kitty terminal configuration",
+		Dependency:  DependencyItem{Name: "kitty", Binary: "kitty"},
+		Files: map[string]string{
+			".config/kitty/kitty.conf": `# Kitty configuration
+# Reference: https://sw.kovidgoyal.net/kitty/conf/
+
+font_size 12.0
+scrollback_lines 10000
+window_padding_width 8
+enable_audio_bell no
+copy_on_select yes
+`,
+		},
+	},
+	{
+		Name:        "nvim",
+		Description: "Neovim configuration",
+		Dependency:  DependencyItem{Name: "neovim", Binary: "nvim"},
+		Files: map[string]string{
+			".config/nvim/init.lua": `-- Neovim configuration
+
+vim.g.mapleader = " "
+
+vim.opt.number = true
+vim.opt.relativenumber = true
+vim.opt.expandtab = true
+vim.opt.shiftwidth = 4
+vim.opt.tabstop = 4
+vim.opt.ignorecase = true
+vim.opt.smartcase = true
+vim.opt.termguicolors = true
+vim.opt.undofile = true
+`,
+		},
+	},
+	{
+		Name:        "starship",
+		Description: "Starship prompt configuration",
+		Dependency:  DependencyItem{Name: "starship", Binary: "starship"},
+		Files: map[string]string{
+			".config/starship.toml": `# Starship prompt configuration
+# Reference: https://starship.rs/config/
+
+add_newline = true
+
+[character]
+success_symbol = "[❯](bold green)"
+error_symbol = "[❯](bold red)"
+
+[directory]
+truncation_length = 3
+`,
+		},
+	},
+	{
+		Name:        "tmux",
+		Description: "Tmux configuration",
+		Dependency:  DependencyItem{Name: "tmux", Binary: "tmux"},
+		Files: map[string]string{
+			".tmux.conf": `# Tmux configuration
+
+set -g mouse on
+set -g history-limit 10000
+set -g base-index 1
+setw -g pane-base-index 1
+set -g renumber-windows on
+set -sg escape-time 10
+set -g default-terminal "tmux-256color"
+`,
+		},
+	},
+	{
+		Name:        "wezterm",
+		Description: "WezTerm terminal configuration",
+		Dependency:  DependencyItem{Name: "wezterm", Binary: "wezterm"},
+		Files: map[string]string{
+			".config/wezterm/wezterm.lua": `-- WezTerm configuration
+-- Reference: https://wezfurlong.org/wezterm/config/files.html
+
+local wezterm = require("wezterm")
+local config = wezterm.config_builder()
+
+config.font_size = 12.0
+config.scrollback_lines = 10000
+config.window_padding = { left = 8, right = 8, top = 8, bottom = 8 }
+
+return config
+`,
+		},
+	},
+	{
+		Name:        "zsh",
+		Description: "Zsh shell configuration",
+		Dependency:  DependencyItem{Name: "zsh", Binary: "zsh"},
+		Files: map[string]string{
+			".zshrc": `# ~/.zshrc
+
+HISTFILE=~/.zsh_history
+HISTSIZE=10000
+SAVEHIST=10000
+setopt share_history hist_ignore_dups autocd
+
+autoload -Uz compinit && compinit
+
+alias ll='ls -lah'
+`,
+		},
+	},
+}
+
+// StarterNames returns the tools 'g4d new' knows, sorted
+func StarterNames() []string {
+	names := make([]string, 0, len(starters))
+	for _, s := range starters {
+		names = append(names, s.Name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// FindStarter returns the starter for tool, or nil if it is not known
+func FindStarter(tool string) *Starter {
+	for i := range starters {
+		if starters[i].Name == tool {
+			return &starters[i]
+		}
+	}
+	return nil
+}
+
+// ConfigItem returns the entry to add to .go4dot.yaml for the starter
+func (s *Starter) ConfigItem() ConfigItem {
+	return ConfigItem{
+		Name:        s.Name,
+		Path:        s.Name,
+		Description: s.Description,
+	}
+}
+
+// WriteFiles creates the starter's package under repoRoot and returns the
+// files written, relative to repoRoot. Nothing is written if any of the
+// files already exists.
+func (s *Starter) WriteFiles(repoRoot string) ([]string, error) {
+	paths := make([]string, 0, len(s.Files))
+	for rel := range s.Files {
+		paths = append(paths, rel)
+	}
+	sort.Strings(paths)
+
+	for _, rel := range paths {
+		target := filepath.Join(repoRoot, s.Name, rel)
+		if _, err := os.Lstat(target); err == nil {
+			return nil, fmt.Errorf("%s already exists", filepath.Join(s.Name, rel))
+		}
+	}
+
+	var written []string
+	for _, rel := range paths {
+		target := filepath.Join(repoRoot, s.Name, rel)
+		if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+			return written, fmt.Errorf("failed to create directory: %w", err)
+		}
+		if err := os.WriteFile(target, []byte(s.Files[rel]), 0644); err != nil {
+			return written, fmt.Errorf("failed to write %s: %w", rel, err)
+		}
+		written = append(written, filepath.Join(s.Name, rel))
+	}
+	return written, nil
+}
@@ -0,0 +1,63 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+)
+
+func TestStarters(t *testing.T) {
+	names := StarterNames()
+	if !sort.StringsAreSorted(names) {
+		t.Errorf("StarterNames() not sorted: %v", names)
+	}
+
+	for _, name := range names {
+		s := FindStarter(name)
+		if s == nil {
+			t.Fatalf("FindStarter(%q) = nil", name)
+		}
+		if s.Description == "" || len(s.Files) == 0 || s.Dependency.Name == "" {
+			t.Errorf("starter %s is incomplete: %+v", name, s)
+		}
+		item := s.ConfigItem()
+		if item.Name != name || item.Path != name {
+			t.Errorf("starter %s ConfigItem() = %+v", name, item)
+		}
+	}
+
+	if FindStarter("not-a-tool") != nil {
+		t.Error("FindStarter() should return nil for unknown tools")
+	}
+}
+
+func TestStarter_WriteFiles(t *testing.T) {
+	root := t.TempDir()
+	s := FindStarter("alacritty")
+
+	written, err := s.WriteFiles(root)
+	if err != nil {
+		t.Fatalf("WriteFiles() error = %v", err)
+	}
+	want := filepath.Join("alacritty", ".config", "alacritty", "alacritty.toml")
+	if len(written) != 1 || written[0] != want {
+		t.Errorf("WriteFiles() = %v, want [%s]", written, want)
+	}
+	data, err := os.ReadFile(filepath.Join(root, want))
+	if err != nil || len(data) == 0 {
+		t.Errorf("starter file not written: %v", err)
+	}
+
+	// A second run must not overwrite the user's edits
+	if err := os.WriteFile(filepath.Join(root, want), []byte("edited"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.WriteFiles(root); err == nil {
+		t.Error("WriteFiles() should fail when files exist")
+	}
+	data, _ = os.ReadFile(filepath.Join(root, want))
+	if string(data) != "edited" {
+		t.Errorf("existing file was overwritten: %q", data)
+	}
+}
@@ -0,0 +1,152 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// DefaultStowBinary is run when the stow section names no binary
+const DefaultStowBinary = "stow"
+
+// StowFlags are the extra stow flags the stow section may pass. Flags that
+// change what go4dot asks stow to do (-D, -R, -n, -t, -d, --adopt) are left
+// out on purpose. Entries ending in "=" take a value, e.g. "--ignore=\.md$".
+var StowFlags = []string{
+	"--dotfiles",
+	"--no-folding",
+	"--ignore=",
+	"--defer=",
+	"--override=",
+}
+
+// dotfilesPrefix is what stow --dotfiles turns into a leading "."
+const dotfilesPrefix = "dot-"
+
+// IsStowFlag reports whether flag is allowed in stow.flags
+func IsStowFlag(flag string) bool {
+	for _, allowed := range StowFlags {
+		if strings.HasSuffix(allowed, "=") {
+			if strings.HasPrefix(flag, allowed) && len(flag) > len(allowed) {
+				return true
+			}
+		} else if flag == allowed {
+			return true
+		}
+	}
+	return false
+}
+
+// BinaryPath returns the stow executable to run, with ~ expanded
+func (s StowSettings) BinaryPath() string {
+	if s.Binary == "" {
+		return DefaultStowBinary
+	}
+	return expandHome(s.Binary)
+}
+
+// TargetDir returns the directory configs are linked into: target with ~
+// expanded, or $HOME (a workspace's target while one is active)
+func (s StowSettings) TargetDir() string {
+	if s.Target == "" {
+		return os.Getenv("HOME")
+	}
+	return filepath.Clean(expandHome(s.Target))
+}
+
+// LinkPath returns where a file at rel inside a config is linked, relative
+// to TargetDir. With --dotfiles, stow links dot-bashrc as .bashrc.
+func (s StowSettings) LinkPath(rel string) string {
+	if !s.HasFlag("--dotfiles") {
+		return rel
+	}
+	parts := strings.Split(rel, string(filepath.Separator))
+	for i, part := range parts {
+		if HasDotPrefix(part) {
+			parts[i] = "." + strings.TrimPrefix(part, dotfilesPrefix)
+		}
+	}
+	return strings.Join(parts, string(filepath.Separator))
+}
+
+// SourcePath is the reverse of LinkPath: the path inside a config that stow
+// links at rel. With --dotfiles, .bashrc comes from dot-bashrc; a file
+// literally named .bashrc is linked there too, so callers should fall back
+// to rel when SourcePath does not exist.
+func (s StowSettings) SourcePath(rel string) string {
+	if !s.HasFlag("--dotfiles") {
+		return rel
+	}
+	parts := strings.Split(rel, string(filepath.Separator))
+	for i, part := range parts {
+		if len(part) > 1 && part[0] == '.' && part != ".." {
+			parts[i] = dotfilesPrefix + part[1:]
+		}
+	}
+	return strings.Join(parts, string(filepath.Separator))
+}
+
+// HasDotPrefix reports whether a file or directory name follows stow's
+// --dotfiles convention, e.g. dot-bashrc for .bashrc
+func HasDotPrefix(name string) bool {
+	return strings.HasPrefix(name, dotfilesPrefix) && len(name) > len(dotfilesPrefix)
+}
+
+// HasFlag reports whether flag is among the extra stow flags
+func (s StowSettings) HasFlag(flag string) bool {
+	for _, f := range s.Flags {
+		if f == flag {
+			return true
+		}
+	}
+	return false
+}
+
+// VerbosityLevel returns the stow -v level to run with. go4dot reads the
+// links stow reports at level 1, so that is also the lowest.
+func (s StowSettings) VerbosityLevel() int {
+	if s.Verbosity < 1 {
+		return 1
+	}
+	return s.Verbosity
+}
+
+// expandHome replaces a leading ~ with $HOME
+func expandHome(path string) string {
+	if path == "~" || strings.HasPrefix(path, "~/") {
+		return filepath.Join(os.Getenv("HOME"), strings.TrimPrefix(path, "~"))
+	}
+	return path
+}
+
+func (s StowSettings) validate() []ValidationError {
+	var errors []ValidationError
+	if s.Binary != "" && strings.ContainsRune(s.Binary, '/') && !filepath.IsAbs(s.Binary) && !strings.HasPrefix(s.Binary, "~/") {
+		errors = append(errors, ValidationError{
+			Field:   "stow.binary",
+			Message: "binary must be a command name, an absolute path or start with ~/",
+		})
+	}
+	for i, flag := range s.Flags {
+		if !IsStowFlag(flag) {
+			errors = append(errors, ValidationError{
+				Field:   fmt.Sprintf("stow.flags[%d]", i),
+				Message: fmt.Sprintf("flag %q is not allowed (allowed: %s)", flag, strings.Join(StowFlags, ", ")),
+			})
+		}
+	}
+	if s.Target != "" && s.Target != "~" && !strings.HasPrefix(s.Target, "~/") && !filepath.IsAbs(s.Target) {
+		errors = append(errors, ValidationError{
+			Field:   "stow.target",
+			Message: "target must be absolute or start with ~/",
+		})
+	}
+	if s.Verbosity < 0 || s.Verbosity > 5 {
+		errors = append(errors, ValidationError{
+			Field:   "stow.verbosity",
+			Message: "verbosity must be between 1 and 5",
+		})
+	}
+	return errors
+}
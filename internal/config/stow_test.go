@@ -0,0 +1,94 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestStowSettings_Validate(t *testing.T) {
+	tests := []struct {
+		name     string
+		settings StowSettings
+		wantErrs int
+	}{
+		{"empty", StowSettings{}, 0},
+		{"all set", StowSettings{Binary: "/opt/homebrew/bin/stow", Flags: []string{"--dotfiles", "--ignore=\\.md$"}, Target: "~/work", Verbosity: 3}, 0},
+		{"binary in PATH", StowSettings{Binary: "xstow"}, 0},
+		{"relative binary", StowSettings{Binary: "bin/stow"}, 1},
+		{"flag outside allowlist", StowSettings{Flags: []string{"--adopt", "-D"}}, 2},
+		{"flag missing value", StowSettings{Flags: []string{"--ignore="}}, 1},
+		{"relative target", StowSettings{Target: "work"}, 1},
+		{"verbosity too high", StowSettings{Verbosity: 6}, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if errs := tt.settings.validate(); len(errs) != tt.wantErrs {
+				t.Errorf("validate() = %v, want %d errors", errs, tt.wantErrs)
+			}
+		})
+	}
+}
+
+func TestStowSettings_Paths(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	s := StowSettings{}
+	if got := s.TargetDir(); got != home {
+		t.Errorf("TargetDir() = %q, want $HOME", got)
+	}
+	if got := s.BinaryPath(); got != DefaultStowBinary {
+		t.Errorf("BinaryPath() = %q, want %q", got, DefaultStowBinary)
+	}
+	if got := s.LinkPath("dot-config/nvim/init.lua"); got != "dot-config/nvim/init.lua" {
+		t.Errorf("LinkPath() without --dotfiles = %q", got)
+	}
+
+	s = StowSettings{Binary: "~/bin/stow", Target: "~/work", Flags: []string{"--dotfiles"}}
+	if got, want := s.TargetDir(), filepath.Join(home, "work"); got != want {
+		t.Errorf("TargetDir() = %q, want %q", got, want)
+	}
+	if got, want := s.BinaryPath(), filepath.Join(home, "bin", "stow"); got != want {
+		t.Errorf("BinaryPath() = %q, want %q", got, want)
+	}
+	if got, want := s.LinkPath(filepath.Join("dot-config", "nvim", "dot-init")), filepath.Join(".config", "nvim", ".init"); got != want {
+		t.Errorf("LinkPath() = %q, want %q", got, want)
+	}
+}
+
+func TestStowSettings_SourcePath(t *testing.T) {
+	s := StowSettings{Flags: []string{"--dotfiles"}}
+	for _, rel := range []string{
+		filepath.Join("dot-config", "fish", "config.fish"),
+		"dot-bashrc",
+		"README",
+	} {
+		if got := s.SourcePath(s.LinkPath(rel)); got != rel {
+			t.Errorf("SourcePath(LinkPath(%q)) = %q", rel, got)
+		}
+	}
+	if got := (StowSettings{}).SourcePath(".bashrc"); got != ".bashrc" {
+		t.Errorf("SourcePath() without --dotfiles = %q", got)
+	}
+}
+
+func TestUsesDotPrefix(t *testing.T) {
+	root := t.TempDir()
+	for _, f := range []string{"vim/.vimrc", "shell/dot-bashrc"} {
+		path := filepath.Join(root, f)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, nil, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if UsesDotPrefix(root, []ConfigItem{{Name: "vim", Path: "vim"}}) {
+		t.Error("UsesDotPrefix() = true for a config with literal dotfiles")
+	}
+	if !UsesDotPrefix(root, []ConfigItem{{Name: "vim", Path: "vim"}, {Name: "shell", Path: "shell"}}) {
+		t.Error("UsesDotPrefix() = false with dot-bashrc")
+	}
+}
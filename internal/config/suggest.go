@@ -0,0 +1,98 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ConfigNames returns the names of all configs (core + optional)
+func (c *Config) ConfigNames() []string {
+	var names []string
+	for _, item := range c.GetAllConfigs() {
+		names = append(names, item.Name)
+	}
+	return names
+}
+
+// ExternalIDs returns the IDs of the top-level external dependencies
+func (c *Config) ExternalIDs() []string {
+	var ids []string
+	for _, ext := range c.External {
+		ids = append(ids, ext.ID)
+	}
+	return ids
+}
+
+// MachinePromptIDs returns the IDs of the machine_config entries
+func (c *Config) MachinePromptIDs() []string {
+	var ids []string
+	for _, mp := range c.MachineConfig {
+		ids = append(ids, mp.ID)
+	}
+	return ids
+}
+
+// UnknownConfigError reports that no config is called name, suggesting the
+// closest config name
+func (c *Config) UnknownConfigError(name string) error {
+	return UnknownNameError("config", name, c.ConfigNames())
+}
+
+// UnknownExternalError reports that no external dependency has the ID id,
+// suggesting the closest ID
+func (c *Config) UnknownExternalError(id string) error {
+	return UnknownNameError("external dependency", id, c.ExternalIDs())
+}
+
+// UnknownMachinePromptError reports that no machine config has the ID id,
+// suggesting the closest ID
+func (c *Config) UnknownMachinePromptError(id string) error {
+	return UnknownNameError("machine config", id, c.MachinePromptIDs())
+}
+
+// UnknownNameError returns an error for a name of the given kind that isn't
+// one of candidates, such as "unknown config 'nivm', did you mean 'nvim'?"
+func UnknownNameError(kind, name string, candidates []string) error {
+	if s := Suggest(name, candidates); s != "" {
+		return fmt.Errorf("unknown %s '%s', did you mean '%s'?", kind, name, s)
+	}
+	return fmt.Errorf("unknown %s '%s'", kind, name)
+}
+
+// Suggest returns the candidate closest to name by edit distance, ignoring
+// case, or "" when none is close enough to be a likely typo: within a third
+// of the name's length, and at least two edits so swapped letters count.
+func Suggest(name string, candidates []string) string {
+	limit := max(2, len(name)/3)
+	best, bestDist := "", limit+1
+	for _, c := range candidates {
+		d := levenshtein(strings.ToLower(name), strings.ToLower(c))
+		if d < bestDist {
+			best, bestDist = c, d
+		}
+	}
+	return best
+}
+
+// levenshtein returns the number of single-rune insertions, deletions and
+// substitutions that turn a into b
+func levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	cur := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		cur[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(rb)]
+}
@@ -0,0 +1,56 @@
+package config
+
+import "testing"
+
+func TestSuggest(t *testing.T) {
+	candidates := []string{"nvim", "tmux", "zsh", "git", "starship"}
+
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"nivm", "nvim"},
+		{"tmxu", "tmux"},
+		{"NVIM", "nvim"},
+		{"starshp", "starship"},
+		{"zhs", "zsh"},
+		{"emacs", ""},
+		{"", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Suggest(tt.name, candidates); got != tt.want {
+				t.Errorf("Suggest(%q) = %q, want %q", tt.name, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUnknownNameErrors(t *testing.T) {
+	cfg := &Config{
+		Configs:       ConfigGroups{Core: []ConfigItem{{Name: "nvim"}}, Optional: []ConfigItem{{Name: "tmux"}}},
+		External:      []ExternalDep{{ID: "tpm"}},
+		MachineConfig: []MachinePrompt{{ID: "git-signing"}},
+	}
+
+	tests := []struct {
+		name string
+		err  error
+		want string
+	}{
+		{"config", cfg.UnknownConfigError("nivm"), "unknown config 'nivm', did you mean 'nvim'?"},
+		{"optional config", cfg.UnknownConfigError("tumx"), "unknown config 'tumx', did you mean 'tmux'?"},
+		{"no suggestion", cfg.UnknownConfigError("emacs"), "unknown config 'emacs'"},
+		{"external", cfg.UnknownExternalError("tmp"), "unknown external dependency 'tmp', did you mean 'tpm'?"},
+		{"machine", cfg.UnknownMachinePromptError("git-sigining"), "unknown machine config 'git-sigining', did you mean 'git-signing'?"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.err.Error() != tt.want {
+				t.Errorf("error = %q, want %q", tt.err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,158 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strings"
+
+	"github.com/nvandessel/go4dot/internal/platform"
+)
+
+// KnownTarget is a place an application reads its settings from that
+// go4dot finds on each machine, such as Windows Terminal's settings seen
+// from WSL. A link whose target starts with its prefix, e.g.
+// @vscode/settings.json, is always copied: these applications can't follow
+// a symlink into a WSL repo, or replace the file when they save it.
+type KnownTarget struct {
+	Prefix      string // e.g. "@vscode/"
+	Description string
+
+	// dirs returns the directories the prefix stands for on this machine:
+	// none when the application isn't there, several for JetBrains IDEs
+	dirs func(home string) ([]string, error)
+}
+
+// KnownTargets are the well-known targets links may use
+var KnownTargets = []KnownTarget{
+	{Prefix: "@windowsTerminal/", Description: "Windows Terminal's LocalState directory (WSL)", dirs: windowsTerminalDirs},
+	{Prefix: "@vscode/", Description: "VS Code's User settings directory", dirs: vscodeDirs},
+	{Prefix: "@powershell/", Description: "PowerShell's profile directory", dirs: powershellDirs},
+	{Prefix: "@jetbrains/", Description: "The config directory of every JetBrains IDE installed", dirs: jetbrainsDirs},
+}
+
+// Where the well-known targets are looked for; tests replace them
+var (
+	targetOS    = runtime.GOOS
+	inWSL       = platform.InWSL
+	windowsHome = platform.WindowsHome
+)
+
+// jetbrainsProductDir matches the per-version config directories JetBrains
+// IDEs create, e.g. IntelliJIdea2024.1 or PyCharmCE2023.3
+var jetbrainsProductDir = regexp.MustCompile(`^[A-Za-z][A-Za-z-]*\d{4}\.\d+$`)
+
+// findKnownTarget returns the well-known target path starts with, or nil
+func findKnownTarget(path string) *KnownTarget {
+	for i := range KnownTargets {
+		if strings.HasPrefix(path, KnownTargets[i].Prefix) {
+			return &KnownTargets[i]
+		}
+	}
+	return nil
+}
+
+// knownTargetPrefixes returns the prefixes of the well-known targets
+func knownTargetPrefixes() []string {
+	prefixes := make([]string, 0, len(KnownTargets))
+	for _, t := range KnownTargets {
+		prefixes = append(prefixes, t.Prefix)
+	}
+	return prefixes
+}
+
+// windowsUserDir returns the Windows user's profile directory when go4dot
+// runs on Windows or under WSL, and false elsewhere
+func windowsUserDir(home string) (string, bool, error) {
+	switch {
+	case targetOS == "windows":
+		return home, true, nil
+	case targetOS == "linux" && inWSL():
+		dir, err := windowsHome()
+		return dir, err == nil, err
+	}
+	return "", false, nil
+}
+
+func windowsTerminalDirs(home string) ([]string, error) {
+	win, ok, err := windowsUserDir(home)
+	if !ok {
+		return nil, err
+	}
+	return existingDirs(filepath.Join(win, "AppData", "Local", "Packages", "Microsoft.WindowsTerminal_8wekyb3d8bbwe", "LocalState")), nil
+}
+
+// vscodeDirs finds the User directory of VS Code. Under WSL that is the
+// Windows one, which VS Code uses for WSL windows too.
+func vscodeDirs(home string) ([]string, error) {
+	win, ok, err := windowsUserDir(home)
+	switch {
+	case err != nil:
+		return nil, err
+	case ok:
+		return existingDirs(filepath.Join(win, "AppData", "Roaming", "Code", "User")), nil
+	case targetOS == "darwin":
+		return existingDirs(filepath.Join(home, "Library", "Application Support", "Code", "User")), nil
+	}
+	return existingDirs(filepath.Join(home, ".config", "Code", "User")), nil
+}
+
+// powershellDirs finds where PowerShell 7 looks for profiles. The directory
+// itself may not exist yet; it is created along with the first copy.
+func powershellDirs(home string) ([]string, error) {
+	win, ok, err := windowsUserDir(home)
+	switch {
+	case err != nil:
+		return nil, err
+	case ok:
+		return underExistingDir(filepath.Join(win, "Documents"), "PowerShell"), nil
+	}
+	return underExistingDir(filepath.Join(home, ".config"), "powershell"), nil
+}
+
+// jetbrainsDirs finds the config directory of every JetBrains IDE version
+// that has run on this machine
+func jetbrainsDirs(home string) ([]string, error) {
+	root := filepath.Join(home, ".config", "JetBrains")
+	win, ok, err := windowsUserDir(home)
+	switch {
+	case err != nil:
+		return nil, err
+	case ok:
+		root = filepath.Join(win, "AppData", "Roaming", "JetBrains")
+	case targetOS == "darwin":
+		root = filepath.Join(home, "Library", "Application Support", "JetBrains")
+	}
+
+	entries, err := os.ReadDir(root)
+	if err != nil {
+		return nil, nil
+	}
+	var dirs []string
+	for _, e := range entries {
+		if e.IsDir() && jetbrainsProductDir.MatchString(e.Name()) {
+			dirs = append(dirs, filepath.Join(root, e.Name()))
+		}
+	}
+	return dirs, nil
+}
+
+// existingDirs returns the dirs that exist
+func existingDirs(dirs ...string) []string {
+	var found []string
+	for _, dir := range dirs {
+		if info, err := os.Stat(dir); err == nil && info.IsDir() {
+			found = append(found, dir)
+		}
+	}
+	return found
+}
+
+// underExistingDir returns parent/name when parent exists
+func underExistingDir(parent, name string) []string {
+	if len(existingDirs(parent)) == 0 {
+		return nil
+	}
+	return []string{filepath.Join(parent, name)}
+}
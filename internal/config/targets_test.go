@@ -0,0 +1,93 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// fakeWSL makes the well-known targets resolve as under WSL, with the
+// Windows user's profile directory at win
+func fakeWSL(t *testing.T, win string) {
+	t.Helper()
+	origOS, origWSL, origHome := targetOS, inWSL, windowsHome
+	targetOS = "linux"
+	inWSL = func() bool { return true }
+	windowsHome = func() (string, error) { return win, nil }
+	t.Cleanup(func() { targetOS, inWSL, windowsHome = origOS, origWSL, origHome })
+}
+
+func TestLinkPaths_KnownTargets(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	win := t.TempDir()
+	fakeWSL(t, win)
+
+	for _, dir := range []string{
+		"AppData/Local/Packages/Microsoft.WindowsTerminal_8wekyb3d8bbwe/LocalState",
+		"AppData/Roaming/JetBrains/IntelliJIdea2024.1",
+		"AppData/Roaming/JetBrains/PyCharmCE2023.3",
+		"AppData/Roaming/JetBrains/consentOptions",
+		"Documents",
+	} {
+		if err := os.MkdirAll(filepath.Join(win, dir), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		target string
+		want   []string
+	}{
+		{"@windowsTerminal/settings.json", []string{"AppData/Local/Packages/Microsoft.WindowsTerminal_8wekyb3d8bbwe/LocalState/settings.json"}},
+		{"@powershell/Microsoft.PowerShell_profile.ps1", []string{"Documents/PowerShell/Microsoft.PowerShell_profile.ps1"}},
+		{"@jetbrains/options/editor.xml", []string{
+			"AppData/Roaming/JetBrains/IntelliJIdea2024.1/options/editor.xml",
+			"AppData/Roaming/JetBrains/PyCharmCE2023.3/options/editor.xml",
+		}},
+		// VS Code isn't installed
+		{"@vscode/settings.json", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.target, func(t *testing.T) {
+			l := Link{Target: tt.target, Source: "@repoRoot/x"}
+			if !l.IsCopy() {
+				t.Error("IsCopy() = false for a well-known target")
+			}
+			targets, _, err := l.Paths("/repo")
+			if err != nil {
+				t.Fatalf("Paths() error = %v", err)
+			}
+			var got []string
+			for _, target := range targets {
+				rel, _ := filepath.Rel(win, target)
+				got = append(got, filepath.ToSlash(rel))
+			}
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("Paths() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLinkPaths_KnownTargetsOffWindows(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	origOS, origWSL := targetOS, inWSL
+	targetOS = "linux"
+	inWSL = func() bool { return false }
+	t.Cleanup(func() { targetOS, inWSL = origOS, origWSL })
+
+	if err := os.MkdirAll(filepath.Join(home, ".config", "Code", "User"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	targets, _, err := Link{Target: "@vscode/settings.json", Source: "@repoRoot/x"}.Paths("/repo")
+	if err != nil || len(targets) != 1 || targets[0] != filepath.Join(home, ".config", "Code", "User", "settings.json") {
+		t.Errorf("@vscode/ = %v, %v", targets, err)
+	}
+	targets, _, err = Link{Target: "@windowsTerminal/settings.json", Source: "@repoRoot/x"}.Paths("/repo")
+	if err != nil || len(targets) != 0 {
+		t.Errorf("@windowsTerminal/ off Windows = %v, %v, want nothing", targets, err)
+	}
+}
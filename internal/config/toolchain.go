@@ -0,0 +1,69 @@
+package config
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Languages toolchains can set up, each with its version manager
+var Languages = []string{"rust", "node", "python"}
+
+// toolchainVersionPattern matches the versions and aliases the version
+// managers accept, such as stable, 1.78.0, 20, lts/iron or 3.12
+var toolchainVersionPattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._/*+-]*$`)
+
+// IsLanguage reports whether name is a language toolchains support
+func IsLanguage(name string) bool {
+	for _, l := range Languages {
+		if l == name {
+			return true
+		}
+	}
+	return false
+}
+
+// DefaultVersion returns the version made the default, the first one
+func (t Toolchain) DefaultVersion() string {
+	if len(t.Versions) == 0 {
+		return ""
+	}
+	return t.Versions[0]
+}
+
+func validateToolchains(toolchains []Toolchain) []ValidationError {
+	var errors []ValidationError
+	languages := make(map[string]bool)
+	for i, t := range toolchains {
+		field := fmt.Sprintf("toolchains[%d]", i)
+		switch {
+		case !IsLanguage(t.Language):
+			errors = append(errors, ValidationError{
+				Field:   field + ".language",
+				Message: fmt.Sprintf("unsupported language %q (supported: %s)", t.Language, strings.Join(Languages, ", ")),
+			})
+		case languages[t.Language]:
+			errors = append(errors, ValidationError{
+				Field:   field + ".language",
+				Message: fmt.Sprintf("duplicate toolchain: %s", t.Language),
+			})
+		}
+		languages[t.Language] = true
+
+		if len(t.Versions) == 0 {
+			errors = append(errors, ValidationError{
+				Field:   field + ".versions",
+				Message: "at least one version is required",
+			})
+		}
+		for j, v := range t.Versions {
+			if !toolchainVersionPattern.MatchString(v) {
+				errors = append(errors, ValidationError{
+					Field:   fmt.Sprintf("%s.versions[%d]", field, j),
+					Message: fmt.Sprintf("invalid version %q", v),
+				})
+			}
+		}
+	}
+	return errors
+}
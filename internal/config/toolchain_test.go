@@ -0,0 +1,45 @@
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidateToolchains(t *testing.T) {
+	tests := []struct {
+		name       string
+		toolchains []Toolchain
+		wantErr    string
+	}{
+		{"valid", []Toolchain{{Language: "rust", Versions: []string{"stable"}}, {Language: "node", Versions: []string{"20", "lts/iron"}}}, ""},
+		{"unknown language", []Toolchain{{Language: "ruby", Versions: []string{"3.3"}}}, "unsupported language"},
+		{"duplicate", []Toolchain{{Language: "python", Versions: []string{"3.12"}}, {Language: "python", Versions: []string{"3.11"}}}, "duplicate toolchain"},
+		{"no versions", []Toolchain{{Language: "rust"}}, "at least one version"},
+		{"option as version", []Toolchain{{Language: "node", Versions: []string{"--lts"}}}, "invalid version"},
+		{"shell in version", []Toolchain{{Language: "node", Versions: []string{"20; rm -rf ~"}}}, "invalid version"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := validateToolchains(tt.toolchains)
+			if tt.wantErr == "" {
+				if len(errs) > 0 {
+					t.Errorf("validateToolchains() = %v, want no errors", errs)
+				}
+				return
+			}
+			if len(errs) == 0 || !strings.Contains(errs[0].Message, tt.wantErr) {
+				t.Errorf("validateToolchains() = %v, want %q", errs, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestToolchainDefaultVersion(t *testing.T) {
+	if got := (Toolchain{Versions: []string{"3.12", "3.11"}}).DefaultVersion(); got != "3.12" {
+		t.Errorf("DefaultVersion() = %q, want 3.12", got)
+	}
+	if got := (Toolchain{}).DefaultVersion(); got != "" {
+		t.Errorf("DefaultVersion() = %q, want empty", got)
+	}
+}
@@ -86,7 +86,7 @@ func (c *Config) Validate(configDir string) error {
 		configNames[cfg.Name] = true
 
 		// Validate path
-		pathErrors := validateConfigPath(cfg.Path, configDir, fmt.Sprintf("configs.core[%d].path", i))
+		pathErrors := validateConfigPath(cfg.Path, cfg.StowDir(configDir), fmt.Sprintf("configs.core[%d].path", i))
 		errors = append(errors, pathErrors...)
 
 		// Validate per-config external dependencies
@@ -94,6 +94,15 @@ func (c *Config) Validate(configDir string) error {
 			extErrors := validateExternalDep(ext, fmt.Sprintf("configs.core[%d].external_deps[%d]", i, j))
 			errors = append(errors, extErrors...)
 		}
+
+		if !IsConflictStrategy(cfg.OnConflict) {
+			errors = append(errors, ValidationError{
+				Field:   fmt.Sprintf("configs.core[%d].on_conflict", i),
+				Message: fmt.Sprintf("on_conflict must be one of: %s", strings.Join(ConflictStrategies, ", ")),
+			})
+		}
+
+		errors = append(errors, validateHooks(fmt.Sprintf("configs.core[%d].hooks", i), cfg.Hooks)...)
 	}
 
 	// Check optional configs
@@ -126,7 +135,7 @@ func (c *Config) Validate(configDir string) error {
 		configNames[cfg.Name] = true
 
 		// Validate path
-		pathErrors := validateConfigPath(cfg.Path, configDir, fmt.Sprintf("configs.optional[%d].path", i))
+		pathErrors := validateConfigPath(cfg.Path, cfg.StowDir(configDir), fmt.Sprintf("configs.optional[%d].path", i))
 		errors = append(errors, pathErrors...)
 
 		// Validate per-config external dependencies
@@ -134,6 +143,15 @@ func (c *Config) Validate(configDir string) error {
 			extErrors := validateExternalDep(ext, fmt.Sprintf("configs.optional[%d].external_deps[%d]", i, j))
 			errors = append(errors, extErrors...)
 		}
+
+		if !IsConflictStrategy(cfg.OnConflict) {
+			errors = append(errors, ValidationError{
+				Field:   fmt.Sprintf("configs.optional[%d].on_conflict", i),
+				Message: fmt.Sprintf("on_conflict must be one of: %s", strings.Join(ConflictStrategies, ", ")),
+			})
+		}
+
+		errors = append(errors, validateHooks(fmt.Sprintf("configs.optional[%d].hooks", i), cfg.Hooks)...)
 	}
 
 	// Validate external dependencies
@@ -182,6 +200,64 @@ func (c *Config) Validate(configDir string) error {
 		errors = append(errors, mcErrors...)
 	}
 
+	if c.Inventory.StaleDays < 0 {
+		errors = append(errors, ValidationError{
+			Field:   "inventory.stale_days",
+			Message: "stale_days must not be negative",
+		})
+	}
+
+	errors = append(errors, validateToolchains(c.Toolchains)...)
+	errors = append(errors, validateLinks(c.Links)...)
+	errors = append(errors, c.ShellIntegration.validate()...)
+	errors = append(errors, c.Doctor.validate()...)
+	errors = append(errors, c.Stow.validate()...)
+	errors = append(errors, c.validateEnv()...)
+	errors = append(errors, validateHooks("hooks", c.Hooks)...)
+	errors = append(errors, c.validateProfiles()...)
+
+	// Validate workspaces
+	workspaceNames := make(map[string]bool)
+	for i, ws := range c.Workspaces {
+		field := fmt.Sprintf("workspaces[%d]", i)
+		if ws.Name == "" {
+			errors = append(errors, ValidationError{
+				Field:   field + ".name",
+				Message: "name is required",
+			})
+		} else if err := validation.ValidateConfigName(ws.Name); err != nil {
+			errors = append(errors, ValidationError{
+				Field:   field + ".name",
+				Message: err.Error(),
+			})
+		} else if workspaceNames[ws.Name] {
+			errors = append(errors, ValidationError{
+				Field:   field + ".name",
+				Message: fmt.Sprintf("duplicate workspace name: %s", ws.Name),
+			})
+		}
+		workspaceNames[ws.Name] = true
+
+		if ws.Target == "" {
+			errors = append(errors, ValidationError{
+				Field:   field + ".target",
+				Message: "target is required",
+			})
+		} else if ws.Target != "~" && !strings.HasPrefix(ws.Target, "~/") && !filepath.IsAbs(ws.Target) {
+			errors = append(errors, ValidationError{
+				Field:   field + ".target",
+				Message: "target must be absolute or start with ~/",
+			})
+		}
+
+		if ws.Profile != "" && c.GetMachineProfileByName(ws.Profile) == nil {
+			errors = append(errors, ValidationError{
+				Field:   field + ".profile",
+				Message: fmt.Sprintf("unknown machine profile: %s", ws.Profile),
+			})
+		}
+	}
+
 	// PostInstall is a display-only string shown to the user after installation.
 	// It is not executed by go4dot, so no executable-bit validation is needed.
 
@@ -233,6 +309,32 @@ func (c *Config) GetConfigByName(name string) *ConfigItem {
 	return nil
 }
 
+// GetExternalForConfigs returns the external dependencies that belong to the
+// named configs, without duplicates. With no names it returns every
+// top-level external dependency.
+func (c *Config) GetExternalForConfigs(names []string) []ExternalDep {
+	if len(names) == 0 {
+		return c.External
+	}
+
+	var result []ExternalDep
+	seen := make(map[string]bool)
+	for _, name := range names {
+		item := c.GetConfigByName(name)
+		if item == nil {
+			continue
+		}
+		for _, ext := range item.ExternalDeps {
+			if seen[ext.ID] {
+				continue
+			}
+			seen[ext.ID] = true
+			result = append(result, ext)
+		}
+	}
+	return result
+}
+
 // GetConfigsForPlatform returns configs filtered by platform conditions and machine profile.
 // It checks both the legacy Platforms field and the new Condition field.
 func (c *Config) GetConfigsForPlatform(p *platform.Platform) []ConfigItem {
@@ -397,12 +499,32 @@ func validateExternalDep(ext ExternalDep, prefix string) []ValidationError {
 		})
 	}
 	method := strings.ToLower(strings.TrimSpace(ext.Method))
-	if method != "" && method != "clone" && method != "copy" {
+	if method != "" && method != "clone" && method != "copy" && method != "github_release" {
 		errors = append(errors, ValidationError{
 			Field:   prefix + ".method",
-			Message: "method must be \"clone\" or \"copy\"",
+			Message: "method must be \"clone\", \"copy\" or \"github_release\"",
 		})
 	}
+	if method == "github_release" {
+		if ext.Asset == "" {
+			errors = append(errors, ValidationError{
+				Field:   prefix + ".asset",
+				Message: "asset is required with method github_release",
+			})
+		} else if strings.ContainsAny(ext.Asset, "/\\") {
+			errors = append(errors, ValidationError{
+				Field:   prefix + ".asset",
+				Message: "asset is a file name pattern and cannot contain a path separator",
+			})
+		}
+		if ext.URL != "" && !strings.HasPrefix(ext.URL, "https://github.com/") &&
+			!strings.HasPrefix(ext.URL, "ssh://git@github.com/") && !strings.HasPrefix(ext.URL, "git@github.com:") {
+			errors = append(errors, ValidationError{
+				Field:   prefix + ".url",
+				Message: "github_release needs a github.com repository url",
+			})
+		}
+	}
 
 	merge := strings.ToLower(strings.TrimSpace(ext.MergeStrategy))
 	if merge != "" && merge != "overwrite" && merge != "keep_existing" {
@@ -2,6 +2,7 @@ package config
 
 import (
 	"os"
+	"strings"
 	"testing"
 
 	"github.com/nvandessel/go4dot/internal/platform"
@@ -744,3 +745,75 @@ func TestValidate_SecurityDependencyCategories(t *testing.T) {
 		})
 	}
 }
+
+func TestGetExternalForConfigs(t *testing.T) {
+	tpm := ExternalDep{ID: "tpm", Name: "TPM"}
+	theme := ExternalDep{ID: "theme", Name: "Theme"}
+	cfg := &Config{
+		External: []ExternalDep{tpm, theme},
+		Configs: ConfigGroups{
+			Core: []ConfigItem{
+				{Name: "tmux", ExternalDeps: []ExternalDep{tpm}},
+				{Name: "git"},
+			},
+			Optional: []ConfigItem{
+				{Name: "tmux-extra", ExternalDeps: []ExternalDep{tpm, theme}},
+			},
+		},
+	}
+
+	tests := []struct {
+		name  string
+		names []string
+		want  []string
+	}{
+		{"all", nil, []string{"tpm", "theme"}},
+		{"one config", []string{"tmux"}, []string{"tpm"}},
+		{"no externals", []string{"git"}, nil},
+		{"deduplicated", []string{"tmux", "tmux-extra"}, []string{"tpm", "theme"}},
+		{"unknown config", []string{"missing"}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, ext := range cfg.GetExternalForConfigs(tt.names) {
+				got = append(got, ext.ID)
+			}
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("GetExternalForConfigs(%v) = %v, want %v", tt.names, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidate_GitHubReleaseExternal(t *testing.T) {
+	tests := []struct {
+		name    string
+		ext     ExternalDep
+		wantErr bool
+	}{
+		{name: "valid", ext: ExternalDep{URL: "https://github.com/owner/tool", Asset: "tool-{os}-{arch}.tar.gz"}},
+		{name: "missing asset", ext: ExternalDep{URL: "https://github.com/owner/tool"}, wantErr: true},
+		{name: "asset with a path", ext: ExternalDep{URL: "https://github.com/owner/tool", Asset: "../tool.tar.gz"}, wantErr: true},
+		{name: "not on github", ext: ExternalDep{URL: "https://gitlab.com/owner/tool", Asset: "tool.tar.gz"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ext := tt.ext
+			ext.ID = "tool"
+			ext.Destination = "~/.local/opt/tool"
+			ext.Method = "github_release"
+			cfg := &Config{
+				SchemaVersion: "1.0",
+				Metadata:      Metadata{Name: "test"},
+				External:      []ExternalDep{ext},
+			}
+			err := cfg.Validate(t.TempDir())
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
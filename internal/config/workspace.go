@@ -0,0 +1,70 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+// GetWorkspace returns the workspace with the given name, or nil
+func (c *Config) GetWorkspace(name string) *Workspace {
+	for i := range c.Workspaces {
+		if c.Workspaces[i].Name == name {
+			return &c.Workspaces[i]
+		}
+	}
+	return nil
+}
+
+// WorkspaceNames returns the names of the config's workspaces, in order
+func (c *Config) WorkspaceNames() []string {
+	names := make([]string, 0, len(c.Workspaces))
+	for _, ws := range c.Workspaces {
+		names = append(names, ws.Name)
+	}
+	return names
+}
+
+// GetMachineProfileByName returns the machine profile with the given name,
+// or nil
+func (c *Config) GetMachineProfileByName(name string) *MachineProfile {
+	for i := range c.Machines {
+		if c.Machines[i].Name == name {
+			return &c.Machines[i]
+		}
+	}
+	return nil
+}
+
+// ApplyWorkspace narrows c to the configs of the named workspace: those
+// its profile includes, or every config when it has none
+func (c *Config) ApplyWorkspace(name string) error {
+	ws := c.GetWorkspace(name)
+	if ws == nil {
+		if len(c.Workspaces) == 0 {
+			return fmt.Errorf("unknown workspace '%s': the config defines no workspaces", name)
+		}
+		return fmt.Errorf("unknown workspace '%s' (available: %s)", name, strings.Join(c.WorkspaceNames(), ", "))
+	}
+	if ws.Profile == "" {
+		return nil
+	}
+
+	profile := c.GetMachineProfileByName(ws.Profile)
+	if profile == nil {
+		return fmt.Errorf("workspace '%s' uses unknown machine profile '%s'", ws.Name, ws.Profile)
+	}
+	c.Configs.Core = filterByProfile(c.Configs.Core, profile)
+	c.Configs.Optional = filterByProfile(c.Configs.Optional, profile)
+	return nil
+}
+
+// filterByProfile returns the configs the profile includes
+func filterByProfile(configs []ConfigItem, profile *MachineProfile) []ConfigItem {
+	var filtered []ConfigItem
+	for _, cfg := range configs {
+		if profileIncludesConfig(profile, cfg.Name) {
+			filtered = append(filtered, cfg)
+		}
+	}
+	return filtered
+}
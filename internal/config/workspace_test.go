@@ -0,0 +1,125 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/nvandessel/go4dot/internal/workspace"
+)
+
+func workspaceTestConfig() *Config {
+	return &Config{
+		Configs: ConfigGroups{
+			Core:     []ConfigItem{{Name: "git", Path: "git"}, {Name: "zsh", Path: "zsh"}},
+			Optional: []ConfigItem{{Name: "games", Path: "games"}},
+		},
+		Machines: []MachineProfile{
+			{Name: "work", IncludeConfigs: []string{"git", "zsh"}},
+			{Name: "minimal", ExcludeConfigs: []string{"zsh", "games"}},
+		},
+		Workspaces: []Workspace{
+			{Name: "work", Target: "~/work-env", Profile: "work"},
+			{Name: "minimal", Target: "/mnt/other/home", Profile: "minimal"},
+			{Name: "everything", Target: "~/all"},
+			{Name: "broken", Target: "~/broken", Profile: "missing"},
+		},
+	}
+}
+
+func TestApplyWorkspace(t *testing.T) {
+	tests := []struct {
+		name        string
+		workspace   string
+		wantConfigs []string
+		wantErr     string
+	}{
+		{name: "include list", workspace: "work", wantConfigs: []string{"git", "zsh"}},
+		{name: "exclude list", workspace: "minimal", wantConfigs: []string{"git"}},
+		{name: "no profile", workspace: "everything", wantConfigs: []string{"git", "zsh", "games"}},
+		{name: "unknown profile", workspace: "broken", wantErr: "unknown machine profile"},
+		{name: "unknown workspace", workspace: "nope", wantErr: "available: work, minimal, everything, broken"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := workspaceTestConfig()
+			err := cfg.ApplyWorkspace(tt.workspace)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ApplyWorkspace failed: %v", err)
+			}
+
+			var got []string
+			for _, c := range cfg.GetAllConfigs() {
+				got = append(got, c.Name)
+			}
+			if strings.Join(got, ",") != strings.Join(tt.wantConfigs, ",") {
+				t.Errorf("configs = %v, want %v", got, tt.wantConfigs)
+			}
+		})
+	}
+}
+
+func TestLoad_ActiveWorkspace(t *testing.T) {
+	t.Setenv(workspace.EnvName, "work")
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, ConfigFileName)
+	data := `schema_version: "1.0"
+configs:
+  core:
+    - name: git
+      path: git
+    - name: games
+      path: games
+machines:
+  - name: work
+    include_configs: [git]
+workspaces:
+  - name: work
+    target: ~/work-env
+    profile: work
+`
+	if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if configs := cfg.GetAllConfigs(); len(configs) != 1 || configs[0].Name != "git" {
+		t.Errorf("expected only the workspace's configs, got %v", configs)
+	}
+}
+
+func TestValidate_Workspaces(t *testing.T) {
+	cfg := workspaceTestConfig()
+	cfg.Workspaces = append(cfg.Workspaces,
+		Workspace{Name: "work", Target: "~/again"},
+		Workspace{Name: "relative", Target: "work-env"},
+		Workspace{Name: "untargeted"},
+	)
+
+	err := cfg.Validate(t.TempDir())
+	if err == nil {
+		t.Fatal("expected validation errors")
+	}
+	for _, want := range []string{
+		"workspaces[3].profile: unknown machine profile: missing",
+		"workspaces[4].name: duplicate workspace name: work",
+		"workspaces[5].target: target must be absolute or start with ~/",
+		"workspaces[6].target: target is required",
+	} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("expected %q in %v", want, err)
+		}
+	}
+}
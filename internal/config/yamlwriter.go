@@ -0,0 +1,216 @@
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// defaultIndent is the indentation of files that have none to go by
+const defaultIndent = 2
+
+// GeneratedHeader is the comment at the top of a generated .go4dot.yaml
+const GeneratedHeader = "Generated by go4dot\nEdit this file to customize your dotfiles management"
+
+// EncodeYAML encodes doc laid out like original, the file it was parsed
+// from. The node tree already keeps comments, key order and quoting; the
+// encoder drops the rest of the layout, so lines that come out the same as
+// before are written exactly as they were, blank lines between them are
+// put back and new lines are indented like their neighbours.
+func EncodeYAML(doc *yaml.Node, original []byte) ([]byte, error) {
+	origLines := splitLines(string(original))
+
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(detectIndent(origLines))
+	if err := enc.Encode(doc); err != nil {
+		return nil, fmt.Errorf("failed to encode YAML: %w", err)
+	}
+	if err := enc.Close(); err != nil {
+		return nil, fmt.Errorf("failed to encode YAML: %w", err)
+	}
+	if len(origLines) == 0 {
+		return buf.Bytes(), nil
+	}
+
+	newLines := splitLines(buf.String())
+	match := matchLines(origLines, newLines)
+
+	var out []string
+	blanks := 0 // Blank lines at the end of out
+	for i, line := range newLines {
+		o, ok := match[i]
+		switch {
+		case strings.TrimSpace(line) == "":
+			// Inside a block scalar
+			out = append(out, line)
+			blanks++
+			continue
+		case !ok:
+			out = append(out, reindent(line, i, newLines, origLines, match))
+		default:
+			// Blank lines right before the original line come back with it
+			start := o
+			for start > 0 && strings.TrimSpace(origLines[start-1]) == "" {
+				start--
+			}
+			if len(out) > 0 {
+				for n := o - start; n > blanks; n-- {
+					out = append(out, "")
+				}
+			}
+			out = append(out, origLines[o])
+		}
+		blanks = 0
+	}
+	data := []byte(strings.Join(out, "\n") + "\n")
+
+	// The layout must not change what the file says
+	if !sameYAML(data, buf.Bytes()) {
+		return buf.Bytes(), nil
+	}
+	return data, nil
+}
+
+// WriteConfig writes cfg as a new .go4dot.yaml at path, with header as the
+// comment at its top. When path already exists, its layout is kept where
+// the new file has the same lines.
+func WriteConfig(path string, cfg *Config, header string) error {
+	var node yaml.Node
+	if err := node.Encode(cfg); err != nil {
+		return fmt.Errorf("failed to generate YAML: %w", err)
+	}
+	doc := &yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{&node}, HeadComment: header}
+
+	original, _ := os.ReadFile(path)
+	data, err := EncodeYAML(doc, original)
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(path, data)
+}
+
+// sameYAML reports whether a and b hold the same data
+func sameYAML(a, b []byte) bool {
+	var va, vb interface{}
+	if yaml.Unmarshal(a, &va) != nil || yaml.Unmarshal(b, &vb) != nil {
+		return false
+	}
+	return reflect.DeepEqual(va, vb)
+}
+
+// splitLines splits s into lines without their line endings
+func splitLines(s string) []string {
+	s = strings.TrimRight(strings.ReplaceAll(s, "\r\n", "\n"), "\n")
+	if s == "" {
+		return nil
+	}
+	return strings.Split(s, "\n")
+}
+
+// detectIndent returns the indentation step of lines: the smallest indent
+// of a line nested in a mapping
+func detectIndent(lines []string) int {
+	indent := 0
+	for _, line := range lines {
+		trimmed := strings.TrimLeft(line, " ")
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+		if n := len(line) - len(trimmed); n > 0 && (indent == 0 || n < indent) {
+			indent = n
+		}
+	}
+	if indent < 2 || indent > 8 {
+		return defaultIndent
+	}
+	return indent
+}
+
+// normalizeLine reduces a line to its content, so the same line matches
+// whatever its indentation and spacing
+func normalizeLine(line string) string {
+	return strings.Join(strings.Fields(line), " ")
+}
+
+// matchLines pairs each line of newLines with an equal line of origLines,
+// keeping their order (a longest common subsequence). Blank lines are left
+// out: the encoder writes none.
+func matchLines(origLines, newLines []string) map[int]int {
+	var orig []int
+	for i, line := range origLines {
+		if strings.TrimSpace(line) != "" {
+			orig = append(orig, i)
+		}
+	}
+	a := make([]string, len(orig))
+	for i, o := range orig {
+		a[i] = normalizeLine(origLines[o])
+	}
+	b := make([]string, len(newLines))
+	for i, line := range newLines {
+		b[i] = normalizeLine(line)
+	}
+
+	// lcs[i][j] is the length of the common subsequence of a[i:] and b[j:]
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			switch {
+			case a[i] == b[j]:
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			case lcs[i+1][j] >= lcs[i][j+1]:
+				lcs[i][j] = lcs[i+1][j]
+			default:
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	match := make(map[int]int)
+	for i, j := 0, 0; i < len(a) && j < len(b); {
+		switch {
+		case a[i] == b[j]:
+			match[j] = orig[i]
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			i++
+		default:
+			j++
+		}
+	}
+	return match
+}
+
+// reindent shifts a new line, newLines[i], by as much as the closest matched
+// line above it at the same or a shallower depth was shifted, so it lines
+// up with the original layout, e.g. with sequences that aren't indented
+// under their key
+func reindent(line string, i int, newLines, origLines []string, match map[int]int) string {
+	depth := indentOf(line)
+	for j := i - 1; j >= 0; j-- {
+		o, ok := match[j]
+		if !ok || indentOf(newLines[j]) > depth {
+			continue
+		}
+		shifted := depth + indentOf(origLines[o]) - indentOf(newLines[j])
+		if shifted < 0 {
+			shifted = 0
+		}
+		return strings.Repeat(" ", shifted) + strings.TrimLeft(line, " ")
+	}
+	return line
+}
+
+// indentOf returns the number of leading spaces of line
+func indentOf(line string) int {
+	return len(line) - len(strings.TrimLeft(line, " "))
+}
@@ -0,0 +1,120 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestEncodeYAML_KeepsLayout(t *testing.T) {
+	const original = `# My dotfiles
+schema_version: "1.0"
+
+metadata:
+  name: test   # who this is for
+
+configs:
+  core:
+  - name: git
+    path: git
+
+  - name: zsh
+    path: zsh
+    platforms: [linux, darwin]
+
+post_install: |
+  echo one
+
+  echo two
+`
+	var doc yaml.Node
+	if err := yaml.Unmarshal([]byte(original), &doc); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := EncodeYAML(&doc, []byte(original))
+	if err != nil {
+		t.Fatalf("EncodeYAML() error = %v", err)
+	}
+	if string(data) != original {
+		t.Errorf("unchanged document was not written back as it was:\n%s", data)
+	}
+
+	// An appended item lines up with its siblings
+	core := findConfigItemNode(&doc, "git")
+	setMappingValue(core, "description", "Git config")
+	seq := sequenceValue(mappingChild(doc.Content[0], "configs"), "core")
+	item := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+	setMappingValue(item, "name", "nvim")
+	setMappingValue(item, "path", "nvim")
+	seq.Content = append(seq.Content, item)
+
+	data, err = EncodeYAML(&doc, []byte(original))
+	if err != nil {
+		t.Fatalf("EncodeYAML() error = %v", err)
+	}
+	for _, want := range []string{
+		"  name: test   # who this is for\n\nconfigs:",
+		"  - name: git\n    path: git\n    description: Git config\n\n  - name: zsh",
+		"  - name: nvim\n    path: nvim\n",
+		"  echo one\n\n  echo two\n",
+	} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("output missing %q:\n%s", want, data)
+		}
+	}
+
+	cfg, err := Parse(data)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if len(cfg.Configs.Core) != 3 || cfg.PostInstall != "echo one\n\necho two\n" {
+		t.Errorf("edited document parsed as %+v", cfg)
+	}
+}
+
+func TestEncodeYAML_DetectsIndent(t *testing.T) {
+	const original = `metadata:
+    name: test
+`
+	var doc yaml.Node
+	if err := yaml.Unmarshal([]byte(original), &doc); err != nil {
+		t.Fatal(err)
+	}
+	setMappingValue(doc.Content[0].Content[1], "author", "me")
+
+	data, err := EncodeYAML(&doc, []byte(original))
+	if err != nil {
+		t.Fatalf("EncodeYAML() error = %v", err)
+	}
+	if want := "metadata:\n    name: test\n    author: me\n"; string(data) != want {
+		t.Errorf("EncodeYAML() = %q, want %q", data, want)
+	}
+}
+
+func TestWriteConfig(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ConfigFileName)
+	cfg := &Config{
+		SchemaVersion: "1.0",
+		Metadata:      Metadata{Name: "test"},
+		Configs:       ConfigGroups{Core: []ConfigItem{{Name: "git", Path: "git"}}},
+	}
+	if err := WriteConfig(path, cfg, GeneratedHeader); err != nil {
+		t.Fatalf("WriteConfig() error = %v", err)
+	}
+
+	data, _ := os.ReadFile(path)
+	if !strings.HasPrefix(string(data), "# Generated by go4dot\n") {
+		t.Errorf("header missing:\n%s", data)
+	}
+	loaded, err := LoadFile(path)
+	if err != nil {
+		t.Fatalf("LoadFile() error = %v", err)
+	}
+	if loaded.Metadata.Name != "test" || len(loaded.Configs.Core) != 1 {
+		t.Errorf("written config loaded as %+v", loaded)
+	}
+}
@@ -0,0 +1,70 @@
+package deps
+
+import (
+	"fmt"
+	"os/exec"
+	"path/filepath"
+
+	"github.com/nvandessel/go4dot/internal/config"
+	"github.com/nvandessel/go4dot/internal/progress"
+)
+
+// CloneBases clones the base repos cfg extends into basesDir, like external
+// dependencies. With opts.Update, bases that are already cloned are pulled.
+// Local bases are used in place and skipped.
+func CloneBases(cfg *config.Config, basesDir string, opts ExternalOptions) (*ExternalResult, error) {
+	result := &ExternalResult{}
+
+	var remote []config.ExternalDep
+	for _, base := range cfg.Extends {
+		dir := config.BaseDir(base, opts.RepoRoot, basesDir)
+		dep := config.ExternalDep{Name: base, ID: filepath.Base(dir), URL: base, Destination: dir}
+		if config.IsLocalBase(base) {
+			result.Skipped = append(result.Skipped, ExternalSkipped{Dep: dep, Reason: "local path"})
+			continue
+		}
+		remote = append(remote, dep)
+	}
+	if len(remote) == 0 {
+		return result, nil
+	}
+
+	if _, err := exec.LookPath("git"); err != nil {
+		return nil, fmt.Errorf("git is required but not found in PATH")
+	}
+
+	total := len(remote)
+	for i, base := range remote {
+		current := i + 1
+		exists, isGit := checkDestination(base.Destination)
+
+		switch {
+		case exists && opts.Update && isGit:
+			opts.emit(progress.Started(progress.CategoryBase, base.Name, current, total, "Updating base %s...", base.Name))
+			if !opts.DryRun {
+				if err := gitPull(base.Destination); err != nil {
+					result.Failed = append(result.Failed, ExternalError{Dep: base, Error: fmt.Errorf("failed to update: %w", err)})
+					continue
+				}
+			}
+			result.Updated = append(result.Updated, base)
+
+		case exists:
+			result.Skipped = append(result.Skipped, ExternalSkipped{Dep: base, Reason: "already exists"})
+
+		default:
+			opts.emit(progress.Started(progress.CategoryBase, base.Name, current, total, "Cloning base %s...", base.Name))
+			if !opts.DryRun {
+				if err := gitClone(base.URL, base.Destination); err != nil {
+					result.Failed = append(result.Failed, ExternalError{Dep: base, Error: err})
+					opts.emit(progress.Itemf(progress.Error, progress.CategoryBase, base.Name, current, total, "Failed to clone base %s: %v", base.Name, err))
+					continue
+				}
+			}
+			result.Cloned = append(result.Cloned, base)
+			opts.emit(progress.Itemf(progress.Success, progress.CategoryBase, base.Name, current, total, "Cloned base %s", base.Name))
+		}
+	}
+
+	return result, nil
+}
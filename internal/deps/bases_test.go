@@ -0,0 +1,19 @@
+package deps
+
+import (
+	"testing"
+
+	"github.com/nvandessel/go4dot/internal/config"
+)
+
+func TestCloneBasesSkipsLocal(t *testing.T) {
+	cfg := &config.Config{Extends: config.Extends{"../base", "/srv/base"}}
+
+	result, err := CloneBases(cfg, t.TempDir(), ExternalOptions{RepoRoot: t.TempDir()})
+	if err != nil {
+		t.Fatalf("CloneBases failed: %v", err)
+	}
+	if len(result.Skipped) != 2 || len(result.Cloned) != 0 || len(result.Failed) != 0 {
+		t.Errorf("expected both local bases to be skipped, got %+v", result)
+	}
+}
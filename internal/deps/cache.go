@@ -0,0 +1,139 @@
+package deps
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/nvandessel/go4dot/internal/state"
+)
+
+const (
+	// CacheFileName is the version cache, kept in the state directory
+	CacheFileName = "deps-cache.json"
+
+	// CacheTTL is how long a cached version is trusted, even if the binary
+	// looks unchanged
+	CacheTTL = 15 * time.Minute
+)
+
+// cacheEnabled is set from --no-cache; see SetCacheEnabled
+var cacheEnabled = true
+
+// SetCacheEnabled turns the version cache on or off for this process. With
+// it off, every check runs the version command and nothing is written.
+func SetCacheEnabled(enabled bool) {
+	cacheEnabled = enabled
+}
+
+// cacheEntry is one cached version lookup. The binary's mtime and size
+// are kept so an upgrade invalidates the entry straight away.
+type cacheEntry struct {
+	ModTime   time.Time `json:"mod_time"`
+	Size      int64     `json:"size"`
+	Version   string    `json:"version,omitempty"`
+	Error     string    `json:"error,omitempty"`
+	CheckedAt time.Time `json:"checked_at"`
+}
+
+// versionCache holds version lookups keyed by binary path and version command
+type versionCache struct {
+	Entries map[string]cacheEntry `json:"entries"`
+	dirty   bool
+}
+
+// cacheMu serializes reading and writing the cache file, as the dashboard
+// may check dependencies from more than one operation at once
+var cacheMu sync.Mutex
+
+func cachePath() (string, error) {
+	dir, err := state.GetStateDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, CacheFileName), nil
+}
+
+func cacheKey(path, versionCmd string) string {
+	if versionCmd == "" {
+		versionCmd = "--version"
+	}
+	return path + "\x00" + versionCmd
+}
+
+// loadCache reads the cache file. A missing or unreadable cache is empty;
+// it is only ever a shortcut.
+func loadCache() *versionCache {
+	c := &versionCache{Entries: make(map[string]cacheEntry)}
+	path, err := cachePath()
+	if err != nil {
+		return c
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return c
+	}
+	if err := json.Unmarshal(data, c); err != nil || c.Entries == nil {
+		c.Entries = make(map[string]cacheEntry)
+	}
+	return c
+}
+
+// save writes the cache back if a lookup changed it, dropping expired entries
+func (c *versionCache) save() error {
+	if !c.dirty {
+		return nil
+	}
+	path, err := cachePath()
+	if err != nil {
+		return err
+	}
+	for key, entry := range c.Entries {
+		if time.Since(entry.CheckedAt) > CacheTTL {
+			delete(c.Entries, key)
+		}
+	}
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0600)
+}
+
+// get returns the cached entry for a binary if it is fresh and the binary
+// has not changed since
+func (c *versionCache) get(path, versionCmd string) (cacheEntry, bool) {
+	entry, ok := c.Entries[cacheKey(path, versionCmd)]
+	if !ok || time.Since(entry.CheckedAt) > CacheTTL {
+		return cacheEntry{}, false
+	}
+	info, err := os.Stat(path)
+	if err != nil || !info.ModTime().Equal(entry.ModTime) || info.Size() != entry.Size {
+		return cacheEntry{}, false
+	}
+	return entry, true
+}
+
+// put records a version lookup, failed ones included
+func (c *versionCache) put(path, versionCmd, version string, lookupErr error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return
+	}
+	entry := cacheEntry{
+		ModTime:   info.ModTime(),
+		Size:      info.Size(),
+		Version:   version,
+		CheckedAt: time.Now(),
+	}
+	if lookupErr != nil {
+		entry.Error = lookupErr.Error()
+	}
+	c.Entries[cacheKey(path, versionCmd)] = entry
+	c.dirty = true
+}
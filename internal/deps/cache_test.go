@@ -0,0 +1,117 @@
+package deps
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nvandessel/go4dot/internal/config"
+	"github.com/nvandessel/go4dot/internal/workspace"
+)
+
+// fakeTool puts a "fake-tool" script on PATH that counts its runs in a file
+func fakeTool(t *testing.T) (binPath string, runs func() int) {
+	t.Helper()
+	dir := t.TempDir()
+	counter := filepath.Join(dir, "runs")
+	binPath = filepath.Join(dir, "fake-tool")
+	script := "#!/bin/sh\necho x >> '" + counter + "'\necho 'fake-tool v1.2.3'\n"
+	if err := os.WriteFile(binPath, []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+	t.Setenv(workspace.EnvUserHome, t.TempDir())
+
+	return binPath, func() int {
+		data, _ := os.ReadFile(counter)
+		return strings.Count(string(data), "\n")
+	}
+}
+
+func TestCheck_Cache(t *testing.T) {
+	binPath, runs := fakeTool(t)
+	cfg := &config.Config{Dependencies: config.Dependencies{
+		Core: []config.DependencyItem{{Name: "fake-tool", Version: "1.2+"}},
+	}}
+
+	first, _ := Check(cfg, nil)
+	if runs() != 1 || !first.CachedAt.IsZero() {
+		t.Fatalf("first Check: runs = %d, CachedAt = %v", runs(), first.CachedAt)
+	}
+
+	// A second check is answered from the cache
+	second, _ := Check(cfg, nil)
+	if runs() != 1 {
+		t.Errorf("second Check ran the version command, runs = %d", runs())
+	}
+	if second.CachedAt.IsZero() || second.Core[0].InstalledVersion != "1.2.3" || second.Core[0].Status != StatusInstalled {
+		t.Errorf("cached check = %+v, CachedAt = %v", second.Core[0], second.CachedAt)
+	}
+
+	// Upgrading the binary invalidates its entry
+	later := time.Now().Add(time.Minute)
+	if err := os.Chtimes(binPath, later, later); err != nil {
+		t.Fatal(err)
+	}
+	if third, _ := Check(cfg, nil); runs() != 2 || !third.CachedAt.IsZero() {
+		t.Errorf("Check after upgrade: runs = %d, CachedAt = %v", runs(), third.CachedAt)
+	}
+
+	// --no-cache always looks the version up
+	SetCacheEnabled(false)
+	defer SetCacheEnabled(true)
+	if fourth, _ := Check(cfg, nil); runs() != 3 || !fourth.CachedAt.IsZero() {
+		t.Errorf("uncached Check: runs = %d, CachedAt = %v", runs(), fourth.CachedAt)
+	}
+}
+
+func TestVersionCache_Get(t *testing.T) {
+	binPath, _ := fakeTool(t)
+
+	tests := []struct {
+		name  string
+		age   time.Duration
+		cmd   string
+		found bool
+	}{
+		{"fresh", time.Minute, "", true},
+		{"default command", time.Minute, "--version", true},
+		{"other command", time.Minute, "-V", false},
+		{"expired", CacheTTL + time.Minute, "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &versionCache{Entries: make(map[string]cacheEntry)}
+			c.put(binPath, "", "1.2.3", nil)
+			entry := c.Entries[cacheKey(binPath, "")]
+			entry.CheckedAt = time.Now().Add(-tt.age)
+			c.Entries[cacheKey(binPath, "")] = entry
+
+			if _, found := c.get(binPath, tt.cmd); found != tt.found {
+				t.Errorf("get() found = %v, want %v", found, tt.found)
+			}
+		})
+	}
+}
+
+func TestVersionCache_SaveDropsExpired(t *testing.T) {
+	binPath, _ := fakeTool(t)
+
+	c := &versionCache{Entries: make(map[string]cacheEntry)}
+	c.put(binPath, "", "1.2.3", nil)
+	c.Entries["stale"] = cacheEntry{CheckedAt: time.Now().Add(-2 * CacheTTL)}
+	if err := c.save(); err != nil {
+		t.Fatalf("save() error = %v", err)
+	}
+
+	loaded := loadCache()
+	if len(loaded.Entries) != 1 {
+		t.Errorf("loaded %d entries, want 1", len(loaded.Entries))
+	}
+	if _, ok := loaded.get(binPath, ""); !ok {
+		t.Error("saved entry not found after reload")
+	}
+}
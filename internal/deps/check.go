@@ -1,11 +1,13 @@
 package deps
 
 import (
+	"errors"
 	"fmt"
 	"os/exec"
 	"regexp"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/nvandessel/go4dot/internal/config"
 	"github.com/nvandessel/go4dot/internal/platform"
@@ -20,17 +22,20 @@ const (
 	StatusMissing         DepStatus = "missing"
 	StatusCheckFailed     DepStatus = "check_failed"
 	StatusVersionMismatch DepStatus = "version_mismatch"
-	StatusManualMissing   DepStatus = "manual_missing" // Manual dep not found; user must install
+	StatusManualMissing   DepStatus = "manual_missing"   // Manual dep not found; user must install
+	StatusUnsupportedArch DepStatus = "unsupported_arch" // Condition leaves out this machine's architecture; never installed
 )
 
 // DependencyCheck represents the check result for a single dependency
 type DependencyCheck struct {
 	Item             config.DependencyItem
 	Status           DepStatus
-	InstalledPath    string // Path where binary was found
-	InstalledVersion string // Version found
-	RequiredVersion  string // Version required
-	Error            error  // Error if check failed
+	Arch             string    // Architecture the dependency is not available for, with StatusUnsupportedArch
+	InstalledPath    string    // Path where binary was found
+	InstalledVersion string    // Version found
+	RequiredVersion  string    // Version required
+	Error            error     // Error if check failed
+	CachedAt         time.Time // When the version was looked up, if it came from the cache
 }
 
 // CheckResult contains the results of checking all dependencies
@@ -38,35 +43,73 @@ type CheckResult struct {
 	Critical []DependencyCheck
 	Core     []DependencyCheck
 	Optional []DependencyCheck
+	CachedAt time.Time // Oldest cached version used, zero if all were looked up
 }
 
-// Check verifies if dependencies are installed
+// Check verifies if dependencies are installed. Versions are looked up
+// through the cache in the state directory unless it is disabled.
 func Check(cfg *config.Config, p *platform.Platform) (*CheckResult, error) {
 	result := &CheckResult{}
 
+	var cache *versionCache
+	if cacheEnabled {
+		cacheMu.Lock()
+		defer cacheMu.Unlock()
+		cache = loadCache()
+	}
+
 	// Check critical dependencies
 	for _, dep := range cfg.Dependencies.Critical {
-		check := checkDependency(dep)
+		check := checkDependencyOn(dep, p, cache)
 		result.Critical = append(result.Critical, check)
 	}
 
 	// Check core dependencies
 	for _, dep := range cfg.Dependencies.Core {
-		check := checkDependency(dep)
+		check := checkDependencyOn(dep, p, cache)
 		result.Core = append(result.Core, check)
 	}
 
 	// Check optional dependencies
 	for _, dep := range cfg.Dependencies.Optional {
-		check := checkDependency(dep)
+		check := checkDependencyOn(dep, p, cache)
 		result.Optional = append(result.Optional, check)
 	}
 
+	for _, checks := range [][]DependencyCheck{result.Critical, result.Core, result.Optional} {
+		for _, check := range checks {
+			if !check.CachedAt.IsZero() && (result.CachedAt.IsZero() || check.CachedAt.Before(result.CachedAt)) {
+				result.CachedAt = check.CachedAt
+			}
+		}
+	}
+
+	if cache != nil {
+		// A cache that cannot be written only costs speed next time
+		_ = cache.save()
+	}
+
 	return result, nil
 }
 
-// checkDependency checks if a single dependency is installed
-func checkDependency(dep config.DependencyItem) DependencyCheck {
+// checkDependencyOn checks dep on p. A dependency whose condition leaves
+// out p's architecture, such as an x86-only package on a Raspberry Pi, is
+// not looked for.
+func checkDependencyOn(dep config.DependencyItem, p *platform.Platform, cache *versionCache) DependencyCheck {
+	if p != nil && !platform.ArchAllowed(dep.Condition, p.Architecture) {
+		return DependencyCheck{
+			Item:            dep,
+			Status:          StatusUnsupportedArch,
+			Arch:            p.Architecture,
+			RequiredVersion: dep.Version,
+		}
+	}
+	return checkDependency(dep, cache)
+}
+
+// checkDependency checks if a single dependency is installed. The cache
+// may be nil to always run the version command.
+func checkDependency(dep config.DependencyItem, cache *versionCache) DependencyCheck {
 	check := DependencyCheck{
 		Item:            dep,
 		RequiredVersion: dep.Version,
@@ -94,7 +137,8 @@ func checkDependency(dep config.DependencyItem) DependencyCheck {
 
 	// Check version if required
 	if dep.Version != "" {
-		version, err := getVersion(binaryName, dep.VersionCmd)
+		version, cachedAt, err := cachedVersion(cache, path, binaryName, dep.VersionCmd)
+		check.CachedAt = cachedAt
 		if err != nil {
 			check.Status = StatusCheckFailed
 			check.Error = fmt.Errorf("failed to get version: %w", err)
@@ -110,6 +154,24 @@ func checkDependency(dep config.DependencyItem) DependencyCheck {
 	return check
 }
 
+// cachedVersion returns the version of the binary at path, from the cache
+// when it holds a fresh entry, along with when that entry was made
+func cachedVersion(cache *versionCache, path, binary, cmd string) (string, time.Time, error) {
+	if cache == nil {
+		version, err := getVersion(binary, cmd)
+		return version, time.Time{}, err
+	}
+	if entry, ok := cache.get(path, cmd); ok {
+		if entry.Error != "" {
+			return "", entry.CheckedAt, errors.New(entry.Error)
+		}
+		return entry.Version, entry.CheckedAt, nil
+	}
+	version, err := getVersion(binary, cmd)
+	cache.put(path, cmd, version, err)
+	return version, time.Time{}, err
+}
+
 func getVersion(binary, cmd string) (string, error) {
 	if err := validation.ValidateBinaryName(binary); err != nil {
 		return "", fmt.Errorf("invalid binary name: %w", err)
@@ -218,6 +280,22 @@ func (r *CheckResult) GetMissing() []DependencyCheck {
 	return missing
 }
 
+// GetOutdated returns the installed dependencies older than their required
+// version. Manual dependencies are excluded.
+func (r *CheckResult) GetOutdated() []DependencyCheck {
+	var outdated []DependencyCheck
+
+	for _, checks := range [][]DependencyCheck{r.Critical, r.Core, r.Optional} {
+		for _, check := range checks {
+			if !check.Item.Manual && check.Status == StatusVersionMismatch {
+				outdated = append(outdated, check)
+			}
+		}
+	}
+
+	return outdated
+}
+
 // GetMissingCritical returns only missing critical dependencies or those with version mismatch.
 // Manual dependencies are excluded.
 func (r *CheckResult) GetMissingCritical() []DependencyCheck {
@@ -235,6 +313,22 @@ func (r *CheckResult) GetMissingCritical() []DependencyCheck {
 	return missing
 }
 
+// GetUnsupportedArch returns the dependencies left out on this machine's
+// architecture
+func (r *CheckResult) GetUnsupportedArch() []DependencyCheck {
+	var unsupported []DependencyCheck
+
+	for _, checks := range [][]DependencyCheck{r.Critical, r.Core, r.Optional} {
+		for _, check := range checks {
+			if check.Status == StatusUnsupportedArch {
+				unsupported = append(unsupported, check)
+			}
+		}
+	}
+
+	return unsupported
+}
+
 // GetManualMissing returns all manual dependencies that are not installed.
 func (r *CheckResult) GetManualMissing() []DependencyCheck {
 	var missing []DependencyCheck
@@ -261,6 +355,7 @@ func (r *CheckResult) Summary() string {
 	totalInstalled := 0
 	totalMissing := 0
 	totalManualMissing := 0
+	totalUnsupported := 0
 
 	for _, checks := range [][]DependencyCheck{r.Critical, r.Core, r.Optional} {
 		for _, check := range checks {
@@ -271,6 +366,8 @@ func (r *CheckResult) Summary() string {
 				totalMissing++
 			case StatusManualMissing:
 				totalManualMissing++
+			case StatusUnsupportedArch:
+				totalUnsupported++
 			}
 		}
 	}
@@ -279,5 +376,8 @@ func (r *CheckResult) Summary() string {
 	if totalManualMissing > 0 {
 		summary += fmt.Sprintf(", %d manual (not installed)", totalManualMissing)
 	}
+	if totalUnsupported > 0 {
+		summary += fmt.Sprintf(", %d unsupported on this architecture", totalUnsupported)
+	}
 	return summary
 }
@@ -87,7 +87,7 @@ func TestCheckDependency(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			check := checkDependency(tt.dep)
+			check := checkDependency(tt.dep, nil)
 
 			if check.Status != tt.wantStatus {
 				t.Errorf("Status = %v, want %v", check.Status, tt.wantStatus)
@@ -135,6 +135,21 @@ func TestGetMissing(t *testing.T) {
 	}
 }
 
+func TestGetOutdated(t *testing.T) {
+	result := &CheckResult{
+		Core: []DependencyCheck{
+			{Item: config.DependencyItem{Name: "missing"}, Status: StatusMissing},
+			{Item: config.DependencyItem{Name: "old"}, Status: StatusVersionMismatch},
+			{Item: config.DependencyItem{Name: "old-manual", Manual: true}, Status: StatusVersionMismatch},
+		},
+	}
+
+	outdated := result.GetOutdated()
+	if len(outdated) != 1 || outdated[0].Item.Name != "old" {
+		t.Errorf("GetOutdated() = %v, want only old", outdated)
+	}
+}
+
 func TestGetMissingCritical(t *testing.T) {
 	result := &CheckResult{
 		Critical: []DependencyCheck{
@@ -271,7 +286,7 @@ func TestCheckDependencyManual(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			check := checkDependency(tt.dep)
+			check := checkDependency(tt.dep, nil)
 			if check.Status != tt.wantStatus {
 				t.Errorf("Status = %v, want %v", check.Status, tt.wantStatus)
 			}
@@ -290,7 +305,7 @@ func TestCheckDependencyManualVersionMismatch(t *testing.T) {
 		Manual:     true,
 	}
 
-	check := checkDependency(dep)
+	check := checkDependency(dep, nil)
 	// sh --version may either return a parseable version (mismatch) or fail.
 	// Either StatusVersionMismatch or StatusCheckFailed is acceptable here,
 	// as long as it's not StatusInstalled (which would mean version matched).
@@ -307,7 +322,7 @@ func TestCheckDependencyManualVersionError(t *testing.T) {
 		Manual:  true,
 	}
 
-	check := checkDependency(dep)
+	check := checkDependency(dep, nil)
 	if check.Status != StatusCheckFailed {
 		t.Fatalf("expected status %v, got %v", StatusCheckFailed, check.Status)
 	}
@@ -516,3 +531,31 @@ func TestGetVersion_RejectsInjection(t *testing.T) {
 		})
 	}
 }
+
+func TestCheckUnsupportedArch(t *testing.T) {
+	cfg := &config.Config{
+		Dependencies: config.Dependencies{
+			Core: []config.DependencyItem{
+				{Name: "sh", Binary: "sh", Condition: map[string]string{"arch": "aarch64,amd64"}},
+				{Name: "x86-tool", Binary: "sh", Condition: map[string]string{"arch": "x86_64"}},
+			},
+		},
+	}
+
+	result, err := Check(cfg, &platform.Platform{OS: "linux", Architecture: "arm64"})
+	if err != nil {
+		t.Fatalf("Check() failed: %v", err)
+	}
+	if result.Core[0].Status != StatusInstalled {
+		t.Errorf("Core[0].Status = %v, want %v (aarch64 names arm64)", result.Core[0].Status, StatusInstalled)
+	}
+	if got := result.Core[1]; got.Status != StatusUnsupportedArch || got.Arch != "arm64" {
+		t.Errorf("Core[1] = %v on %q, want %v on arm64", got.Status, got.Arch, StatusUnsupportedArch)
+	}
+	if len(result.GetMissing()) != 0 {
+		t.Errorf("GetMissing() = %+v, want unsupported deps left out", result.GetMissing())
+	}
+	if summary := result.Summary(); !strings.Contains(summary, "1 unsupported on this architecture") {
+		t.Errorf("Summary() = %q", summary)
+	}
+}
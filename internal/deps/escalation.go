@@ -0,0 +1,73 @@
+package deps
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+	"os/user"
+	"runtime"
+
+	"github.com/nvandessel/go4dot/internal/platform"
+)
+
+// maxPasswordAttempts is how often a rejected sudo password is asked again
+const maxPasswordAttempts = 3
+
+// ErrPasswordRequired is returned when the escalation tool wants a password
+// and nobody can type one
+var ErrPasswordRequired = errors.New("a password is required to install packages")
+
+// ErrPasswordCanceled is returned when the user dismisses the password prompt
+var ErrPasswordCanceled = errors.New("password prompt canceled")
+
+// PrepareEscalation finds the tool manager's commands get root through and
+// makes sure they can run without stopping at a hidden prompt: a sudo
+// password is asked once through opts.AskPassword and cached, and without a
+// way to ask, or with doas wanting a password, it fails with what to do
+// instead.
+func PrepareEscalation(manager string, opts InstallOptions) (*platform.Escalator, error) {
+	esc, err := platform.FindEscalator(defaultEscalation(opts.Escalation, runtime.GOOS))
+	if err != nil {
+		return nil, fmt.Errorf("%s needs root to install packages: %w", manager, err)
+	}
+	if !esc.NeedsPassword() {
+		return esc, nil
+	}
+
+	if !esc.CanAuthenticate() {
+		return nil, fmt.Errorf("%w: %s asks for it on a terminal go4dot can't answer; run '%s true' first (with 'persist' in doas.conf) or use --skip-deps",
+			ErrPasswordRequired, esc.Tool, esc.Tool)
+	}
+	if opts.AskPassword == nil {
+		return nil, fmt.Errorf("%w: sudo wants a password and nobody can type it here; run 'sudo -v' first or use --skip-deps", ErrPasswordRequired)
+	}
+
+	prompt := fmt.Sprintf("[sudo] password to install packages with %s", manager)
+	if u, err := user.Current(); err == nil {
+		prompt = fmt.Sprintf("[sudo] password for %s to install packages with %s", u.Username, manager)
+	}
+	for attempt := 1; attempt <= maxPasswordAttempts; attempt++ {
+		password, err := opts.AskPassword(prompt)
+		if err != nil {
+			return nil, err
+		}
+		if err := esc.Authenticate(password); err == nil {
+			return esc, nil
+		}
+		prompt = "Sorry, try again. " + prompt
+	}
+	return nil, fmt.Errorf("sudo rejected the password %d times", maxPasswordAttempts)
+}
+
+// defaultEscalation returns the escalation tool to use when none is
+// configured: doas on OpenBSD, where it ships with the base system and sudo
+// is a package that may be installed but not set up
+func defaultEscalation(configured, goos string) string {
+	if configured != "" || goos != "openbsd" {
+		return configured
+	}
+	if _, err := exec.LookPath(platform.EscalateDoas); err == nil {
+		return platform.EscalateDoas
+	}
+	return ""
+}
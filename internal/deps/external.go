@@ -1,6 +1,7 @@
 package deps
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"os/exec"
@@ -9,6 +10,10 @@ import (
 
 	"github.com/nvandessel/go4dot/internal/config"
 	"github.com/nvandessel/go4dot/internal/platform"
+	"github.com/nvandessel/go4dot/internal/progress"
+	"github.com/nvandessel/go4dot/internal/snapshot"
+	"github.com/nvandessel/go4dot/internal/transcript"
+	"github.com/nvandessel/go4dot/internal/trash"
 	"github.com/nvandessel/go4dot/internal/validation"
 )
 
@@ -37,7 +42,14 @@ type ExternalOptions struct {
 	DryRun       bool                                 // Don't actually clone, just report
 	Update       bool                                 // Pull updates for existing repos
 	RepoRoot     string                               // Path to dotfiles root for @repoRoot expansion
+	UseTrash     bool                                 // Move removed repos to the OS trash instead of deleting
 	ProgressFunc func(current, total int, msg string) // Called for progress updates with item counts
+	EventFunc    progress.Func                        // Receives progress as events; when set, ProgressFunc is not called
+}
+
+// emit reports a progress event
+func (o ExternalOptions) emit(e progress.Event) {
+	progress.Emit(o.EventFunc, o.ProgressFunc, e)
 }
 
 // CloneExternal clones all external dependencies from the config
@@ -48,9 +60,15 @@ func CloneExternal(cfg *config.Config, p *platform.Platform, opts ExternalOption
 		return result, nil
 	}
 
-	// Check if git is available
-	if _, err := exec.LookPath("git"); err != nil {
-		return nil, fmt.Errorf("git is required but not found in PATH")
+	// Check if git is available, unless everything comes from releases
+	for _, ext := range cfg.External {
+		if ext.Method == MethodGitHubRelease {
+			continue
+		}
+		if _, err := exec.LookPath("git"); err != nil {
+			return nil, fmt.Errorf("git is required but not found in PATH")
+		}
+		break
 	}
 
 	total := len(cfg.External)
@@ -59,13 +77,12 @@ func CloneExternal(cfg *config.Config, p *platform.Platform, opts ExternalOption
 
 		// Check condition
 		if !platform.CheckCondition(ext.Condition, p) {
+			reason := conditionSkipReason(ext.Condition, p)
 			result.Skipped = append(result.Skipped, ExternalSkipped{
 				Dep:    ext,
-				Reason: "condition not met",
+				Reason: reason,
 			})
-			if opts.ProgressFunc != nil {
-				opts.ProgressFunc(current, total, fmt.Sprintf("⊘ Skipping %s (condition not met)", ext.Name))
-			}
+			opts.emit(progress.Itemf(progress.Skipped, progress.CategoryExternal, ext.Name, current, total, "Skipping %s (%s)", ext.Name, reason))
 			continue
 		}
 
@@ -82,6 +99,11 @@ func CloneExternal(cfg *config.Config, p *platform.Platform, opts ExternalOption
 		// Check if already exists
 		exists, isGit := checkDestination(destPath)
 
+		if ext.Method == MethodGitHubRelease {
+			syncReleaseExternal(ext, p, destPath, exists, current, total, opts, result)
+			continue
+		}
+
 		if exists {
 			if ext.Method == "copy" {
 				goto Execute
@@ -89,9 +111,7 @@ func CloneExternal(cfg *config.Config, p *platform.Platform, opts ExternalOption
 
 			if opts.Update && isGit {
 				// Update existing repo
-				if opts.ProgressFunc != nil {
-					opts.ProgressFunc(current, total, fmt.Sprintf("↻ Updating %s...", ext.Name))
-				}
+				opts.emit(progress.Started(progress.CategoryExternal, ext.Name, current, total, "Updating %s...", ext.Name))
 
 				if !opts.DryRun {
 					if err := gitPull(destPath); err != nil {
@@ -104,33 +124,25 @@ func CloneExternal(cfg *config.Config, p *platform.Platform, opts ExternalOption
 				}
 
 				result.Updated = append(result.Updated, ext)
-				if opts.ProgressFunc != nil {
-					opts.ProgressFunc(current, total, fmt.Sprintf("✓ Updated %s", ext.Name))
-				}
+				opts.emit(progress.Itemf(progress.Success, progress.CategoryExternal, ext.Name, current, total, "Updated %s", ext.Name))
 			} else {
 				// Skip existing
 				result.Skipped = append(result.Skipped, ExternalSkipped{
 					Dep:    ext,
 					Reason: "already exists",
 				})
-				if opts.ProgressFunc != nil {
-					opts.ProgressFunc(current, total, fmt.Sprintf("⊘ Skipping %s (already exists)", ext.Name))
-				}
+				opts.emit(progress.Itemf(progress.Skipped, progress.CategoryExternal, ext.Name, current, total, "Skipping %s (already exists)", ext.Name))
 			}
 			continue
 		}
 
 	Execute:
 		// Clone the repository
-		if opts.ProgressFunc != nil {
-			opts.ProgressFunc(current, total, fmt.Sprintf("⬇ Cloning %s...", ext.Name))
-		}
+		opts.emit(progress.Started(progress.CategoryExternal, ext.Name, current, total, "Cloning %s...", ext.Name))
 
 		if opts.DryRun {
 			result.Cloned = append(result.Cloned, ext)
-			if opts.ProgressFunc != nil {
-				opts.ProgressFunc(current, total, fmt.Sprintf("✓ Would clone %s to %s", ext.Name, destPath))
-			}
+			opts.emit(progress.Itemf(progress.Success, progress.CategoryExternal, ext.Name, current, total, "Would clone %s to %s", ext.Name, destPath))
 			continue
 		}
 
@@ -155,14 +167,10 @@ func CloneExternal(cfg *config.Config, p *platform.Platform, opts ExternalOption
 				Dep:   ext,
 				Error: cloneErr,
 			})
-			if opts.ProgressFunc != nil {
-				opts.ProgressFunc(current, total, fmt.Sprintf("✗ Failed to clone %s: %v", ext.Name, cloneErr))
-			}
+			opts.emit(progress.Itemf(progress.Error, progress.CategoryExternal, ext.Name, current, total, "Failed to clone %s: %v", ext.Name, cloneErr))
 		} else {
 			result.Cloned = append(result.Cloned, ext)
-			if opts.ProgressFunc != nil {
-				opts.ProgressFunc(current, total, fmt.Sprintf("✓ Cloned %s", ext.Name))
-			}
+			opts.emit(progress.Itemf(progress.Success, progress.CategoryExternal, ext.Name, current, total, "Cloned %s", ext.Name))
 		}
 	}
 
@@ -180,12 +188,12 @@ func CloneSingle(cfg *config.Config, p *platform.Platform, id string, opts Exter
 	}
 
 	if found == nil {
-		return fmt.Errorf("external dependency '%s' not found", id)
+		return cfg.UnknownExternalError(id)
 	}
 
 	// Check condition
 	if !platform.CheckCondition(found.Condition, p) {
-		return fmt.Errorf("condition not met for '%s'", id)
+		return fmt.Errorf("%s for '%s'", conditionSkipReason(found.Condition, p), id)
 	}
 
 	destPath, err := expandPath(found.Destination, opts.RepoRoot)
@@ -195,6 +203,21 @@ func CloneSingle(cfg *config.Config, p *platform.Platform, id string, opts Exter
 
 	exists, isGit := checkDestination(destPath)
 
+	if found.Method == MethodGitHubRelease {
+		if exists && !opts.Update {
+			return fmt.Errorf("destination already exists: %s", destPath)
+		}
+		result := &ExternalResult{}
+		syncReleaseExternal(*found, p, destPath, exists, 1, 1, opts, result)
+		if len(result.Failed) > 0 {
+			return result.Failed[0].Error
+		}
+		if len(result.Skipped) > 0 && !exists {
+			return fmt.Errorf("%s for '%s'", result.Skipped[0].Reason, id)
+		}
+		return nil
+	}
+
 	if exists {
 		// Special handling for copy method with merge strategy
 		if found.Method == "copy" {
@@ -202,31 +225,23 @@ func CloneSingle(cfg *config.Config, p *platform.Platform, id string, opts Exter
 		}
 
 		if opts.Update && isGit {
-			if opts.ProgressFunc != nil {
-				opts.ProgressFunc(1, 1, fmt.Sprintf("↻ Updating %s...", found.Name))
-			}
+			opts.emit(progress.Started(progress.CategoryExternal, found.Name, 1, 1, "Updating %s...", found.Name))
 			if !opts.DryRun {
 				if err := gitPull(destPath); err != nil {
 					return fmt.Errorf("failed to update: %w", err)
 				}
 			}
-			if opts.ProgressFunc != nil {
-				opts.ProgressFunc(1, 1, fmt.Sprintf("✓ Updated %s", found.Name))
-			}
+			opts.emit(progress.Itemf(progress.Success, progress.CategoryExternal, found.Name, 1, 1, "Updated %s", found.Name))
 			return nil
 		}
 		return fmt.Errorf("destination already exists: %s", destPath)
 	}
 
 Execute:
-	if opts.ProgressFunc != nil {
-		opts.ProgressFunc(1, 1, fmt.Sprintf("⬇ Cloning %s...", found.Name))
-	}
+	opts.emit(progress.Started(progress.CategoryExternal, found.Name, 1, 1, "Cloning %s...", found.Name))
 
 	if opts.DryRun {
-		if opts.ProgressFunc != nil {
-			opts.ProgressFunc(1, 1, fmt.Sprintf("✓ Would clone %s to %s", found.Name, destPath))
-		}
+		opts.emit(progress.Itemf(progress.Success, progress.CategoryExternal, found.Name, 1, 1, "Would clone %s to %s", found.Name, destPath))
 		return nil
 	}
 
@@ -257,7 +272,7 @@ func CheckExternalStatus(cfg *config.Config, p *platform.Platform, repoRoot stri
 		// Check condition
 		if !platform.CheckCondition(ext.Condition, p) {
 			status.Status = "skipped"
-			status.Reason = "condition not met"
+			status.Reason = conditionSkipReason(ext.Condition, p)
 			statuses = append(statuses, status)
 			continue
 		}
@@ -276,9 +291,12 @@ func CheckExternalStatus(cfg *config.Config, p *platform.Platform, repoRoot stri
 				status.Status = "installed"
 			} else {
 				status.Status = "installed"
-				if ext.Method == "copy" {
+				switch ext.Method {
+				case "copy":
 					status.Reason = "copied"
-				} else {
+				case MethodGitHubRelease:
+					status.Reason = strings.TrimSpace("release " + InstalledRelease(destPath))
+				default:
 					status.Reason = "not a git repo"
 				}
 			}
@@ -293,6 +311,57 @@ func CheckExternalStatus(cfg *config.Config, p *platform.Platform, repoRoot stri
 	return statuses
 }
 
+// conditionSkipReason says why an external whose condition p does not meet
+// is skipped
+func conditionSkipReason(condition map[string]string, p *platform.Platform) string {
+	if !platform.ArchAllowed(condition, p.Architecture) {
+		return fmt.Sprintf("unsupported arch %s", p.Architecture)
+	}
+	return "condition not met"
+}
+
+// syncReleaseExternal installs a github_release external into destPath, or
+// with opts.Update replaces an installed one when a newer release is out,
+// recording the outcome in result. A release without an asset for this
+// machine is skipped as an unsupported arch. It is external number current
+// of total.
+func syncReleaseExternal(ext config.ExternalDep, p *platform.Platform, destPath string, exists bool, current, total int, opts ExternalOptions, result *ExternalResult) {
+	report := func(level progress.Level, format string, a ...interface{}) {
+		opts.emit(progress.Itemf(level, progress.CategoryExternal, ext.Name, current, total, format, a...))
+	}
+	if exists && !opts.Update {
+		result.Skipped = append(result.Skipped, ExternalSkipped{Dep: ext, Reason: "already exists"})
+		report(progress.Skipped, "Skipping %s (already exists)", ext.Name)
+		return
+	}
+
+	opts.emit(progress.Started(progress.CategoryExternal, ext.Name, current, total, "Downloading %s...", ext.Name))
+	if opts.DryRun {
+		result.Cloned = append(result.Cloned, ext)
+		report(progress.Success, "Would download %s to %s", ext.Name, destPath)
+		return
+	}
+
+	tag, err := installRelease(ext, p, destPath)
+	switch {
+	case errors.Is(err, ErrUnsupportedArch):
+		result.Skipped = append(result.Skipped, ExternalSkipped{Dep: ext, Reason: err.Error()})
+		report(progress.Skipped, "Skipping %s (%v)", ext.Name, err)
+	case err != nil:
+		result.Failed = append(result.Failed, ExternalError{Dep: ext, Error: err})
+		report(progress.Error, "Failed to download %s: %v", ext.Name, err)
+	case tag == "":
+		result.Skipped = append(result.Skipped, ExternalSkipped{Dep: ext, Reason: "latest release installed"})
+		report(progress.Skipped, "Skipping %s (latest release installed)", ext.Name)
+	case exists:
+		result.Updated = append(result.Updated, ext)
+		report(progress.Success, "Updated %s to %s", ext.Name, tag)
+	default:
+		result.Cloned = append(result.Cloned, ext)
+		report(progress.Success, "Installed %s %s", ext.Name, tag)
+	}
+}
+
 // ExternalStatus represents the status of an external dependency
 type ExternalStatus struct {
 	Dep    config.ExternalDep
@@ -372,7 +441,7 @@ func gitClone(url, dest string) error {
 	cmd.Stdout = nil // Suppress output
 	cmd.Stderr = nil
 
-	if err := cmd.Run(); err != nil {
+	if err := transcript.Run(cmd); err != nil {
 		return fmt.Errorf("git clone failed: %w", err)
 	}
 
@@ -390,7 +459,7 @@ func gitPull(path string) error {
 	cmd.Stdout = nil
 	cmd.Stderr = nil
 
-	if err := cmd.Run(); err != nil {
+	if err := transcript.Run(cmd); err != nil {
 		return fmt.Errorf("git pull failed: %w", err)
 	}
 
@@ -520,7 +589,7 @@ func RemoveExternal(cfg *config.Config, id string, opts ExternalOptions) error {
 	}
 
 	if found == nil {
-		return fmt.Errorf("external dependency '%s' not found", id)
+		return cfg.UnknownExternalError(id)
 	}
 
 	destPath, err := expandPath(found.Destination, opts.RepoRoot)
@@ -532,24 +601,21 @@ func RemoveExternal(cfg *config.Config, id string, opts ExternalOptions) error {
 		return fmt.Errorf("'%s' is not installed (path does not exist: %s)", id, destPath)
 	}
 
-	if opts.ProgressFunc != nil {
-		opts.ProgressFunc(1, 1, fmt.Sprintf("Removing %s...", found.Name))
-	}
+	opts.emit(progress.Started(progress.CategoryExternal, found.Name, 1, 1, "Removing %s...", found.Name))
 
 	if opts.DryRun {
-		if opts.ProgressFunc != nil {
-			opts.ProgressFunc(1, 1, fmt.Sprintf("✓ Would remove %s from %s", found.Name, destPath))
-		}
+		opts.emit(progress.Itemf(progress.Success, progress.CategoryExternal, found.Name, 1, 1, "Would remove %s from %s", found.Name, destPath))
 		return nil
 	}
 
-	if err := os.RemoveAll(destPath); err != nil {
+	if err := snapshot.Save(destPath); err != nil {
+		return err
+	}
+	if err := trash.Remove(destPath, opts.UseTrash); err != nil {
 		return fmt.Errorf("failed to remove %s: %w", destPath, err)
 	}
 
-	if opts.ProgressFunc != nil {
-		opts.ProgressFunc(1, 1, fmt.Sprintf("✓ Removed %s", found.Name))
-	}
+	opts.emit(progress.Itemf(progress.Success, progress.CategoryExternal, found.Name, 1, 1, "Removed %s", found.Name))
 
 	return nil
 }
@@ -0,0 +1,108 @@
+package deps
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// maxReadmeBytes caps how much of a README is read for previews
+const maxReadmeBytes = 64 * 1024
+
+// readmeNames lists README file names in order of preference
+var readmeNames = []string{"README.md", "README.markdown", "readme.md", "README.rst", "README.txt", "README"}
+
+// ExternalDetails holds information about an installed external dependency
+// that is too expensive to gather for every status check.
+type ExternalDetails struct {
+	Commit     string    // Short hash of HEAD, empty if not a git checkout
+	CommitDate time.Time // Commit time of HEAD
+	UpdatedAt  time.Time // When the checkout was last cloned or pulled
+	ReadmePath string    // Path to the README, empty if none was found
+	Readme     string    // README contents, truncated to maxReadmeBytes
+	Truncated  bool      // True if the README was longer than the preview
+}
+
+// GetExternalDetails reads commit, update time and README information from an
+// installed external dependency. Missing pieces are left empty.
+func GetExternalDetails(status ExternalStatus) (*ExternalDetails, error) {
+	details := &ExternalDetails{}
+	if status.Path == "" || status.Status != "installed" {
+		return details, nil
+	}
+	if !filepath.IsAbs(status.Path) {
+		return nil, fmt.Errorf("external path must be absolute: %q", status.Path)
+	}
+
+	if _, isGit := checkDestination(status.Path); isGit {
+		details.Commit, details.CommitDate = gitHead(status.Path)
+		details.UpdatedAt = lastFetch(status.Path)
+	} else if info, err := os.Stat(status.Path); err == nil {
+		details.UpdatedAt = info.ModTime()
+	}
+
+	for _, name := range readmeNames {
+		path := filepath.Join(status.Path, name)
+		content, truncated, err := readPrefix(path, maxReadmeBytes)
+		if err != nil {
+			continue
+		}
+		details.ReadmePath = path
+		details.Readme = content
+		details.Truncated = truncated
+		break
+	}
+
+	return details, nil
+}
+
+// gitHead returns the short hash and commit time of HEAD
+func gitHead(path string) (string, time.Time) {
+	out, err := exec.Command("git", "-C", path, "log", "-1", "--format=%h %cI").Output()
+	if err != nil {
+		return "", time.Time{}
+	}
+
+	hash, date, _ := strings.Cut(strings.TrimSpace(string(out)), " ")
+	ts, _ := time.Parse(time.RFC3339, date)
+	return hash, ts
+}
+
+// lastFetch approximates when a checkout was last updated: FETCH_HEAD is
+// touched by every pull, HEAD by the initial clone.
+func lastFetch(path string) time.Time {
+	var latest time.Time
+	for _, name := range []string{"FETCH_HEAD", "HEAD"} {
+		if info, err := os.Stat(filepath.Join(path, ".git", name)); err == nil && info.ModTime().After(latest) {
+			latest = info.ModTime()
+		}
+	}
+	return latest
+}
+
+// readPrefix reads at most limit bytes from a regular file
+func readPrefix(path string, limit int64) (string, bool, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", false, err
+	}
+	defer func() { _ = f.Close() }()
+
+	info, err := f.Stat()
+	if err != nil {
+		return "", false, err
+	}
+	if !info.Mode().IsRegular() {
+		return "", false, fmt.Errorf("%s is not a regular file", path)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(f, limit))
+	if err != nil {
+		return "", false, err
+	}
+	return string(data), info.Size() > limit, nil
+}
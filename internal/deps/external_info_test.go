@@ -0,0 +1,107 @@
+package deps
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/nvandessel/go4dot/internal/config"
+)
+
+func TestGetExternalDetails(t *testing.T) {
+	tests := []struct {
+		name        string
+		files       map[string]string
+		status      string
+		wantReadme  string
+		wantContent string
+	}{
+		{
+			name:        "Prefers README.md",
+			files:       map[string]string{"README.md": "# Plugin", "README": "plain"},
+			status:      "installed",
+			wantReadme:  "README.md",
+			wantContent: "# Plugin",
+		},
+		{
+			name:        "Falls back to plain README",
+			files:       map[string]string{"README": "plain"},
+			status:      "installed",
+			wantReadme:  "README",
+			wantContent: "plain",
+		},
+		{
+			name:   "No README",
+			files:  map[string]string{"init.lua": "return {}"},
+			status: "installed",
+		},
+		{
+			name:   "Missing dependency has no details",
+			files:  map[string]string{"README.md": "# Plugin"},
+			status: "missing",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			for name, content := range tt.files {
+				if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			details, err := GetExternalDetails(ExternalStatus{
+				Dep:    config.ExternalDep{ID: "plugin"},
+				Status: tt.status,
+				Path:   dir,
+			})
+			if err != nil {
+				t.Fatalf("GetExternalDetails() error = %v", err)
+			}
+
+			if tt.wantReadme == "" {
+				if details.ReadmePath != "" {
+					t.Errorf("ReadmePath = %q, want empty", details.ReadmePath)
+				}
+				return
+			}
+			if filepath.Base(details.ReadmePath) != tt.wantReadme {
+				t.Errorf("ReadmePath = %q, want %s", details.ReadmePath, tt.wantReadme)
+			}
+			if details.Readme != tt.wantContent {
+				t.Errorf("Readme = %q, want %q", details.Readme, tt.wantContent)
+			}
+			if details.UpdatedAt.IsZero() {
+				t.Error("expected UpdatedAt to be set for a non-git directory")
+			}
+		})
+	}
+}
+
+func TestGetExternalDetailsTruncatesReadme(t *testing.T) {
+	dir := t.TempDir()
+	long := strings.Repeat("x", maxReadmeBytes+10)
+	if err := os.WriteFile(filepath.Join(dir, "README.md"), []byte(long), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	details, err := GetExternalDetails(ExternalStatus{Status: "installed", Path: dir})
+	if err != nil {
+		t.Fatalf("GetExternalDetails() error = %v", err)
+	}
+	if !details.Truncated {
+		t.Error("expected README to be marked truncated")
+	}
+	if len(details.Readme) != maxReadmeBytes {
+		t.Errorf("len(Readme) = %d, want %d", len(details.Readme), maxReadmeBytes)
+	}
+}
+
+func TestGetExternalDetailsRelativePath(t *testing.T) {
+	_, err := GetExternalDetails(ExternalStatus{Status: "installed", Path: "relative/dir"})
+	if err == nil {
+		t.Error("expected error for relative path")
+	}
+}
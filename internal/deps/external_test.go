@@ -528,7 +528,7 @@ func TestCloneSingleNotFound(t *testing.T) {
 		t.Error("Expected error for nonexistent ID")
 	}
 
-	if err.Error() != "external dependency 'nonexistent' not found" {
+	if err.Error() != "unknown external dependency 'nonexistent'" {
 		t.Errorf("Unexpected error message: %v", err)
 	}
 }
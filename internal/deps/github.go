@@ -0,0 +1,298 @@
+package deps
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/nvandessel/go4dot/internal/state"
+)
+
+const (
+	// GitHubCacheFileName holds fetched GitHub metadata, in the state directory
+	GitHubCacheFileName = "github-cache.json"
+
+	// GitHubCacheTTL is how long fetched metadata is used before asking the
+	// API again. Stale entries are still shown while rate limited.
+	GitHubCacheTTL = 6 * time.Hour
+)
+
+// githubAPIURL is the API root; tests point it at a local server
+var githubAPIURL = "https://api.github.com"
+
+// ErrGitHubRateLimited is returned when the API allows no more requests
+// until the rate limit resets
+var ErrGitHubRateLimited = errors.New("GitHub API rate limit reached; set GITHUB_TOKEN for a higher limit")
+
+// GitHubInfo is what the GitHub API says about an external's upstream repo
+type GitHubInfo struct {
+	Repo          string    `json:"repo"` // owner/name
+	Archived      bool      `json:"archived"`
+	Stars         int       `json:"stars"`
+	LatestRelease string    `json:"latest_release,omitempty"` // Tag of the latest release, or the newest tag
+	ReleasedAt    time.Time `json:"released_at,omitempty"`    // Zero when only a tag was found
+	FetchedAt     time.Time `json:"fetched_at"`
+}
+
+// GitHubRepo returns owner/name for clone URLs hosted on github.com, in
+// https, ssh or scp-like (git@github.com:owner/name) form
+func GitHubRepo(url string) (string, bool) {
+	rest := ""
+	for _, prefix := range []string{"https://github.com/", "http://github.com/", "ssh://git@github.com/", "git@github.com:"} {
+		if strings.HasPrefix(url, prefix) {
+			rest = strings.TrimPrefix(url, prefix)
+			break
+		}
+	}
+	rest = strings.TrimSuffix(strings.TrimSuffix(rest, "/"), ".git")
+	parts := strings.Split(rest, "/")
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", false
+	}
+	return parts[0] + "/" + parts[1], true
+}
+
+// GitHubToken returns the token API requests are made with, from
+// GITHUB_TOKEN or GH_TOKEN. Without one the API allows 60 requests an hour.
+func GitHubToken() string {
+	if token := os.Getenv("GITHUB_TOKEN"); token != "" {
+		return token
+	}
+	return os.Getenv("GH_TOKEN")
+}
+
+// githubCache holds fetched metadata keyed by owner/name, and when the
+// rate limit resets if it was hit
+type githubCache struct {
+	Entries          map[string]GitHubInfo `json:"entries"`
+	RateLimitedUntil time.Time             `json:"rate_limited_until,omitempty"`
+	dirty            bool
+}
+
+// githubCacheMu serializes reading and writing the cache file
+var githubCacheMu sync.Mutex
+
+func githubCachePath() (string, error) {
+	dir, err := state.GetStateDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, GitHubCacheFileName), nil
+}
+
+// loadGitHubCache reads the cache file; a missing or unreadable one is empty
+func loadGitHubCache() *githubCache {
+	c := &githubCache{Entries: make(map[string]GitHubInfo)}
+	path, err := githubCachePath()
+	if err != nil {
+		return c
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return c
+	}
+	if err := json.Unmarshal(data, c); err != nil || c.Entries == nil {
+		c.Entries = make(map[string]GitHubInfo)
+	}
+	return c
+}
+
+func (c *githubCache) save() error {
+	if !c.dirty {
+		return nil
+	}
+	path, err := githubCachePath()
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0600)
+}
+
+// FetchGitHubInfo returns GitHub metadata for the externals cloned from
+// github.com, keyed by external ID. Metadata younger than GitHubCacheTTL is
+// reused; the rest is fetched one repo at a time. Once the rate limit is
+// hit, no more requests are made until it resets and the cached metadata is
+// returned with ErrGitHubRateLimited.
+func FetchGitHubInfo(ctx context.Context, statuses []ExternalStatus) (map[string]*GitHubInfo, error) {
+	githubCacheMu.Lock()
+	defer githubCacheMu.Unlock()
+
+	cache := &githubCache{Entries: make(map[string]GitHubInfo)}
+	if cacheEnabled {
+		cache = loadGitHubCache()
+	}
+	client := &http.Client{Timeout: 10 * time.Second}
+	token := GitHubToken()
+
+	result := make(map[string]*GitHubInfo)
+	var fetchErr error
+	for _, s := range statuses {
+		repo, ok := GitHubRepo(s.Dep.URL)
+		if !ok {
+			continue
+		}
+
+		cached, hasCached := cache.Entries[repo]
+		fresh := hasCached && time.Since(cached.FetchedAt) < GitHubCacheTTL
+		if !fresh && fetchErr == nil && time.Now().Before(cache.RateLimitedUntil) {
+			fetchErr = ErrGitHubRateLimited
+		}
+		if !fresh && fetchErr == nil {
+			info, err := fetchGitHubRepo(ctx, client, token, repo)
+			var limited *rateLimitError
+			switch {
+			case errors.As(err, &limited):
+				cache.RateLimitedUntil = limited.reset
+				cache.dirty = true
+				fetchErr = ErrGitHubRateLimited
+			case err != nil:
+				fetchErr = err
+			default:
+				cache.Entries[repo] = *info
+				cache.dirty = true
+				cached, hasCached = *info, true
+			}
+		}
+		if hasCached {
+			info := cached
+			result[s.Dep.ID] = &info
+		}
+	}
+
+	if cacheEnabled {
+		_ = cache.save()
+	}
+	return result, fetchErr
+}
+
+// rateLimitError carries when the exhausted rate limit resets
+type rateLimitError struct {
+	reset time.Time
+}
+
+func (e *rateLimitError) Error() string {
+	return fmt.Sprintf("GitHub API rate limit reached until %s", e.reset.Format(time.Kitchen))
+}
+
+// fetchGitHubRepo asks the API for a repo's metadata and latest release,
+// falling back to its newest tag when it publishes no releases
+func fetchGitHubRepo(ctx context.Context, client *http.Client, token, repo string) (*GitHubInfo, error) {
+	var meta struct {
+		Archived bool `json:"archived"`
+		Stars    int  `json:"stargazers_count"`
+	}
+	if _, err := githubGet(ctx, client, token, "/repos/"+repo, &meta); err != nil {
+		return nil, err
+	}
+	info := &GitHubInfo{Repo: repo, Archived: meta.Archived, Stars: meta.Stars, FetchedAt: time.Now()}
+
+	var release struct {
+		TagName     string    `json:"tag_name"`
+		PublishedAt time.Time `json:"published_at"`
+	}
+	found, err := githubGet(ctx, client, token, "/repos/"+repo+"/releases/latest", &release)
+	if err != nil {
+		return nil, err
+	}
+	if found {
+		info.LatestRelease = release.TagName
+		info.ReleasedAt = release.PublishedAt
+		return info, nil
+	}
+
+	var tags []struct {
+		Name string `json:"name"`
+	}
+	if _, err := githubGet(ctx, client, token, "/repos/"+repo+"/tags?per_page=1", &tags); err != nil {
+		return nil, err
+	}
+	if len(tags) > 0 {
+		info.LatestRelease = tags[0].Name
+	}
+	return info, nil
+}
+
+// githubGet decodes the API response for path into out. It reports false
+// without an error when the API answers 404.
+func githubGet(ctx context.Context, client *http.Client, token, path string, out interface{}) (bool, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, githubAPIURL+path, nil)
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return false, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.Header.Get("X-RateLimit-Remaining") == "0" && resp.StatusCode != http.StatusOK {
+		reset := time.Now().Add(time.Hour)
+		if secs, err := strconv.ParseInt(resp.Header.Get("X-RateLimit-Reset"), 10, 64); err == nil {
+			reset = time.Unix(secs, 0)
+		}
+		return false, &rateLimitError{reset: reset}
+	}
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		return false, nil
+	default:
+		return false, fmt.Errorf("github api returned %d for %s", resp.StatusCode, path)
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return false, fmt.Errorf("failed to parse github api response for %s: %w", path, err)
+	}
+	return true, nil
+}
+
+// GitHubHints returns the notes worth showing for an external: that its
+// upstream is archived, and that a release was published after the
+// installed checkout's commit
+func GitHubHints(info *GitHubInfo, status ExternalStatus) []string {
+	if info == nil {
+		return nil
+	}
+	var hints []string
+	if info.Archived {
+		hints = append(hints, "archived upstream")
+	}
+	if status.Status == "installed" && !info.ReleasedAt.IsZero() && status.Path != "" {
+		if _, commitDate := gitHead(status.Path); !commitDate.IsZero() && info.ReleasedAt.After(commitDate) {
+			hints = append(hints, fmt.Sprintf("new release available: %s", info.LatestRelease))
+		}
+	}
+	return hints
+}
+
+// FormatStars renders a star count compactly: 950, 1.2k or 15k
+func FormatStars(n int) string {
+	switch {
+	case n < 1000:
+		return strconv.Itoa(n)
+	case n < 10000:
+		return fmt.Sprintf("%.1fk", float64(n)/1000)
+	default:
+		return fmt.Sprintf("%dk", n/1000)
+	}
+}
@@ -0,0 +1,191 @@
+package deps
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os/exec"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/nvandessel/go4dot/internal/config"
+	"github.com/nvandessel/go4dot/internal/workspace"
+)
+
+func TestGitHubRepo(t *testing.T) {
+	tests := []struct {
+		url  string
+		want string
+		ok   bool
+	}{
+		{"https://github.com/tmux-plugins/tpm", "tmux-plugins/tpm", true},
+		{"https://github.com/tmux-plugins/tpm.git", "tmux-plugins/tpm", true},
+		{"https://github.com/tmux-plugins/tpm/", "tmux-plugins/tpm", true},
+		{"git@github.com:zdharma/zinit.git", "zdharma/zinit", true},
+		{"ssh://git@github.com/zdharma/zinit", "zdharma/zinit", true},
+		{"https://gitlab.com/owner/repo", "", false},
+		{"https://github.com/owner", "", false},
+		{"https://github.com/owner/repo/tree/main", "", false},
+	}
+	for _, tt := range tests {
+		got, ok := GitHubRepo(tt.url)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("GitHubRepo(%q) = %q, %v; want %q, %v", tt.url, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+// fakeGitHub serves the repo, release and tag endpoints the client uses and
+// counts the requests it gets
+func fakeGitHub(t *testing.T, handler http.HandlerFunc) *int32 {
+	t.Helper()
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		handler(w, r)
+	}))
+	t.Cleanup(server.Close)
+
+	old := githubAPIURL
+	githubAPIURL = server.URL
+	t.Cleanup(func() { githubAPIURL = old })
+	t.Setenv(workspace.EnvUserHome, t.TempDir())
+	t.Setenv("GITHUB_TOKEN", "")
+	t.Setenv("GH_TOKEN", "")
+	return &requests
+}
+
+func githubStatuses() []ExternalStatus {
+	return []ExternalStatus{
+		{Dep: config.ExternalDep{ID: "tpm", URL: "https://github.com/tmux-plugins/tpm.git"}, Status: "missing"},
+		{Dep: config.ExternalDep{ID: "other", URL: "https://gitlab.com/owner/repo.git"}, Status: "missing"},
+	}
+}
+
+func TestFetchGitHubInfo(t *testing.T) {
+	requests := fakeGitHub(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/repos/tmux-plugins/tpm":
+			fmt.Fprint(w, `{"archived": true, "stargazers_count": 1234}`)
+		case "/repos/tmux-plugins/tpm/releases/latest":
+			fmt.Fprint(w, `{"tag_name": "v3.1.0", "published_at": "2024-05-01T00:00:00Z"}`)
+		default:
+			http.NotFound(w, r)
+		}
+	})
+
+	info, err := FetchGitHubInfo(context.Background(), githubStatuses())
+	if err != nil {
+		t.Fatalf("FetchGitHubInfo() error = %v", err)
+	}
+	if _, ok := info["other"]; ok {
+		t.Error("externals not hosted on GitHub should have no metadata")
+	}
+	tpm := info["tpm"]
+	if tpm == nil || !tpm.Archived || tpm.Stars != 1234 || tpm.LatestRelease != "v3.1.0" {
+		t.Fatalf("info[tpm] = %+v", tpm)
+	}
+	if hints := GitHubHints(tpm, githubStatuses()[0]); len(hints) != 1 || hints[0] != "archived upstream" {
+		t.Errorf("GitHubHints() = %v, want [archived upstream]", hints)
+	}
+
+	// The second run is served from the cache
+	before := atomic.LoadInt32(requests)
+	if _, err := FetchGitHubInfo(context.Background(), githubStatuses()); err != nil {
+		t.Fatal(err)
+	}
+	if got := atomic.LoadInt32(requests); got != before {
+		t.Errorf("cached run made %d requests, want 0", got-before)
+	}
+}
+
+func TestFetchGitHubInfo_TagFallbackAndToken(t *testing.T) {
+	var auth atomic.Value
+	fakeGitHub(t, func(w http.ResponseWriter, r *http.Request) {
+		auth.Store(r.Header.Get("Authorization"))
+		switch r.URL.Path {
+		case "/repos/tmux-plugins/tpm":
+			fmt.Fprint(w, `{"stargazers_count": 5}`)
+		case "/repos/tmux-plugins/tpm/tags":
+			fmt.Fprint(w, `[{"name": "v1.0"}]`)
+		default:
+			http.NotFound(w, r)
+		}
+	})
+	t.Setenv("GH_TOKEN", "secret")
+
+	info, err := FetchGitHubInfo(context.Background(), githubStatuses())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tpm := info["tpm"]; tpm == nil || tpm.LatestRelease != "v1.0" || !tpm.ReleasedAt.IsZero() {
+		t.Errorf("info[tpm] = %+v, want the newest tag without a release date", tpm)
+	}
+	if got := auth.Load(); got != "Bearer secret" {
+		t.Errorf("Authorization = %v, want the token from GH_TOKEN", got)
+	}
+}
+
+func TestFetchGitHubInfo_RateLimited(t *testing.T) {
+	reset := time.Now().Add(30 * time.Minute).Unix()
+	requests := fakeGitHub(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-RateLimit-Remaining", "0")
+		w.Header().Set("X-RateLimit-Reset", fmt.Sprint(reset))
+		w.WriteHeader(http.StatusForbidden)
+	})
+
+	_, err := FetchGitHubInfo(context.Background(), githubStatuses())
+	if !errors.Is(err, ErrGitHubRateLimited) {
+		t.Fatalf("error = %v, want ErrGitHubRateLimited", err)
+	}
+
+	// Until the limit resets, nothing more is requested
+	before := atomic.LoadInt32(requests)
+	if _, err := FetchGitHubInfo(context.Background(), githubStatuses()); !errors.Is(err, ErrGitHubRateLimited) {
+		t.Errorf("error = %v, want ErrGitHubRateLimited", err)
+	}
+	if got := atomic.LoadInt32(requests); got != before {
+		t.Errorf("rate limited run made %d requests, want 0", got-before)
+	}
+}
+
+func TestGitHubHints_NewRelease(t *testing.T) {
+	dir := t.TempDir()
+	gitInit(t, dir)
+
+	installed := ExternalStatus{Status: "installed", Path: dir}
+	newer := &GitHubInfo{LatestRelease: "v2.0", ReleasedAt: time.Now().Add(time.Hour)}
+	if hints := GitHubHints(newer, installed); len(hints) != 1 || !strings.Contains(hints[0], "new release available: v2.0") {
+		t.Errorf("GitHubHints() = %v, want a new release hint", hints)
+	}
+
+	older := &GitHubInfo{LatestRelease: "v1.0", ReleasedAt: time.Now().Add(-24 * time.Hour)}
+	if hints := GitHubHints(older, installed); len(hints) != 0 {
+		t.Errorf("GitHubHints() = %v, want none for a release older than the checkout", hints)
+	}
+}
+
+func TestFormatStars(t *testing.T) {
+	for n, want := range map[int]string{950: "950", 1234: "1.2k", 15678: "15k"} {
+		if got := FormatStars(n); got != want {
+			t.Errorf("FormatStars(%d) = %q, want %q", n, got, want)
+		}
+	}
+}
+
+// gitInit makes dir a git repo with one commit
+func gitInit(t *testing.T, dir string) {
+	t.Helper()
+	for _, args := range [][]string{
+		{"init", "-q"},
+		{"-c", "user.name=test", "-c", "user.email=test@example.com", "commit", "-q", "--allow-empty", "-m", "init"},
+	} {
+		if out, err := exec.Command("git", append([]string{"-C", dir}, args...)...).CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+}
@@ -2,9 +2,11 @@ package deps
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/nvandessel/go4dot/internal/config"
 	"github.com/nvandessel/go4dot/internal/platform"
+	"github.com/nvandessel/go4dot/internal/progress"
 )
 
 // InstallResult represents the result of installing dependencies
@@ -25,8 +27,22 @@ type InstallError struct {
 type InstallOptions struct {
 	SkipPrompts  bool                                 // If true, install without asking
 	OnlyMissing  bool                                 // Only install missing deps
+	OnlyOutdated bool                                 // Only upgrade deps older than their required version
 	DryRun       bool                                 // Don't actually install, just report
 	ProgressFunc func(current, total int, msg string) // Called for progress updates with item counts
+	EventFunc    progress.Func                        // Receives progress as events; when set, ProgressFunc is not called
+
+	// Escalation is the tool root commands run through: sudo, doas or
+	// pkexec. Empty uses the first one installed.
+	Escalation string
+	// AskPassword asks for the sudo password once, with masked input. Nil
+	// when nobody can answer, which fails before installing anything.
+	AskPassword func(prompt string) (string, error)
+}
+
+// emit reports a progress event
+func (o InstallOptions) emit(e progress.Event) {
+	progress.Emit(o.EventFunc, o.ProgressFunc, e)
 }
 
 // Install installs missing dependencies
@@ -44,13 +60,20 @@ func Install(cfg *config.Config, p *platform.Platform, opts InstallOptions) (*In
 	for _, depCheck := range manualMissing {
 		dep := depCheck.Item
 		result.ManualSkipped = append(result.ManualSkipped, dep)
-		if opts.ProgressFunc != nil {
-			opts.ProgressFunc(0, 0, fmt.Sprintf("Skipping manual dependency: %s (install manually)", dep.Name))
-		}
+		opts.emit(progress.Itemf(progress.Skipped, progress.CategoryDependency, dep.Name, 0, 0, "Skipping manual dependency: %s (install manually)", dep.Name))
+	}
+
+	// Never try packages that do not exist for this architecture
+	for _, depCheck := range checkResult.GetUnsupportedArch() {
+		result.Skipped = append(result.Skipped, depCheck.Item)
+		opts.emit(progress.Itemf(progress.Skipped, progress.CategoryDependency, depCheck.Item.Name, 0, 0, "Skipping %s (unsupported arch %s)", depCheck.Item.Name, depCheck.Arch))
 	}
 
 	// Get missing dependencies (excludes manual deps)
 	missing := checkResult.GetMissing()
+	if opts.OnlyOutdated {
+		missing = checkResult.GetOutdated()
+	}
 	if len(missing) == 0 {
 		return result, nil // Nothing to do
 	}
@@ -65,18 +88,25 @@ func Install(cfg *config.Config, p *platform.Platform, opts InstallOptions) (*In
 		return nil, fmt.Errorf("package manager %s is not available", pkgMgr.Name())
 	}
 
+	// Settle how to get root before the first package manager command, which
+	// could otherwise wait on a password prompt nobody sees
+	if escalated, ok := pkgMgr.(platform.Escalated); ok && pkgMgr.NeedsSudo() && !opts.DryRun {
+		esc, err := PrepareEscalation(pkgMgr.Name(), opts)
+		if err != nil {
+			return nil, err
+		}
+		esc.Env = cfg.OperationEnv()
+		escalated.SetEscalator(esc)
+	}
+
 	// Update package cache first
 	total := len(missing)
-	if opts.ProgressFunc != nil {
-		opts.ProgressFunc(0, total, "Updating package cache...")
-	}
+	opts.emit(progress.Event{Level: progress.Info, Category: progress.CategoryPackages, Total: total, Message: "Updating package cache..."})
 
 	if !opts.DryRun {
 		if err := pkgMgr.Update(); err != nil {
 			// Don't fail on update errors, just warn
-			if opts.ProgressFunc != nil {
-				opts.ProgressFunc(0, total, fmt.Sprintf("Warning: failed to update package cache: %v", err))
-			}
+			opts.emit(progress.Event{Level: progress.Warning, Category: progress.CategoryPackages, Total: total, Message: fmt.Sprintf("Failed to update package cache: %v", err)})
 		}
 	}
 
@@ -85,8 +115,11 @@ func Install(cfg *config.Config, p *platform.Platform, opts InstallOptions) (*In
 		dep := depCheck.Item
 		current := i + 1
 
-		if opts.ProgressFunc != nil {
-			opts.ProgressFunc(current, total, fmt.Sprintf("Installing %s...", dep.Name))
+		outdated := depCheck.Status == StatusVersionMismatch
+		if outdated {
+			opts.emit(progress.Started(progress.CategoryDependency, dep.Name, current, total, "Upgrading %s (%s, needs %s)...", dep.Name, depCheck.InstalledVersion, depCheck.RequiredVersion))
+		} else {
+			opts.emit(progress.Started(progress.CategoryDependency, dep.Name, current, total, "Installing %s...", dep.Name))
 		}
 
 		if opts.DryRun {
@@ -95,25 +128,30 @@ func Install(cfg *config.Config, p *platform.Platform, opts InstallOptions) (*In
 		}
 
 		// Get package name for this platform
-		pkgName := getPackageNameForPlatform(dep, pkgMgr.Name())
+		pkgName := getPackageNameForPlatform(dep, pkgMgr.Name(), p.Architecture)
 		if pkgName == "" {
 			pkgName = dep.Name
 		}
 
-		// Try to install
-		err := pkgMgr.Install(pkgName)
+		// Try to install; an outdated package may need an upgrade instead
+		var err error
+		if upgrader, ok := pkgMgr.(platform.Upgrader); ok && outdated {
+			err = upgrader.Upgrade(pkgName)
+		} else {
+			err = pkgMgr.Install(pkgName)
+		}
 		if err != nil {
 			result.Failed = append(result.Failed, InstallError{
 				Item:  dep,
 				Error: err,
 			})
-			if opts.ProgressFunc != nil {
-				opts.ProgressFunc(current, total, fmt.Sprintf("Failed to install %s: %v", dep.Name, err))
-			}
+			opts.emit(progress.Itemf(progress.Error, progress.CategoryDependency, dep.Name, current, total, "Failed to install %s: %v", dep.Name, err))
 		} else {
 			result.Installed = append(result.Installed, dep)
-			if opts.ProgressFunc != nil {
-				opts.ProgressFunc(current, total, fmt.Sprintf("Installed %s", dep.Name))
+			if outdated {
+				opts.emit(progress.Itemf(progress.Success, progress.CategoryDependency, dep.Name, current, total, "Upgraded %s", dep.Name))
+			} else {
+				opts.emit(progress.Itemf(progress.Success, progress.CategoryDependency, dep.Name, current, total, "Installed %s", dep.Name))
 			}
 		}
 	}
@@ -121,19 +159,55 @@ func Install(cfg *config.Config, p *platform.Platform, opts InstallOptions) (*In
 	return result, nil
 }
 
-// getPackageNameForPlatform returns the platform-specific package name
-func getPackageNameForPlatform(dep config.DependencyItem, manager string) string {
-	if dep.Package != nil {
-		if pkgName, ok := dep.Package[manager]; ok {
+// getPackageNameForPlatform returns the platform-specific package name: a
+// manager/arch key such as apt/arm64 wins over the manager's own key
+func getPackageNameForPlatform(dep config.DependencyItem, manager, arch string) string {
+	for key, pkgName := range dep.Package {
+		keyManager, keyArch, ok := strings.Cut(key, "/")
+		if ok && keyManager == manager && platform.NormalizeArch(keyArch) == platform.NormalizeArch(arch) {
 			return pkgName
 		}
 	}
+	if pkgName, ok := dep.Package[manager]; ok {
+		return pkgName
+	}
 	return ""
 }
 
+// PackageName returns the name dep is installed under by the package
+// manager of p, falling back to the dependency's own name
+func PackageName(dep config.DependencyItem, p *platform.Platform) string {
+	if name := getPackageNameForPlatform(dep, p.PackageManager, p.Architecture); name != "" {
+		return name
+	}
+	return dep.Name
+}
+
 // InstallMissing is a convenience function that installs only missing dependencies
 func InstallMissing(cfg *config.Config, p *platform.Platform) (*InstallResult, error) {
 	return Install(cfg, p, InstallOptions{
 		OnlyMissing: true,
 	})
 }
+
+// Refresh updates the package manager's package information, as Install
+// does before installing (pacman -Sy, apt-get update, ...)
+func Refresh(cfg *config.Config, p *platform.Platform, opts InstallOptions) error {
+	pkgMgr, err := platform.GetPackageManager(p)
+	if err != nil {
+		return fmt.Errorf("failed to get package manager: %w", err)
+	}
+	if !pkgMgr.IsAvailable() {
+		return fmt.Errorf("package manager %s is not available", pkgMgr.Name())
+	}
+
+	if escalated, ok := pkgMgr.(platform.Escalated); ok && pkgMgr.NeedsSudo() {
+		esc, err := PrepareEscalation(pkgMgr.Name(), opts)
+		if err != nil {
+			return err
+		}
+		esc.Env = cfg.OperationEnv()
+		escalated.SetEscalator(esc)
+	}
+	return pkgMgr.Update()
+}
@@ -53,3 +53,45 @@ func TestInstall_ManualOnly(t *testing.T) {
 		t.Fatal("expected progress message for manual dependency skip")
 	}
 }
+
+func TestGetPackageNameForPlatform(t *testing.T) {
+	dep := config.DependencyItem{
+		Name: "neovim",
+		Package: map[string]string{
+			"apt":         "neovim",
+			"apt/aarch64": "neovim-arm",
+			"dnf/riscv64": "neovim-rv",
+		},
+	}
+	tests := []struct {
+		manager, arch, want string
+	}{
+		{"apt", "amd64", "neovim"},
+		{"apt", "arm64", "neovim-arm"},
+		{"dnf", "riscv64", "neovim-rv"},
+		{"dnf", "amd64", ""},
+	}
+	for _, tt := range tests {
+		if got := getPackageNameForPlatform(dep, tt.manager, tt.arch); got != tt.want {
+			t.Errorf("getPackageNameForPlatform(%s, %s) = %q, want %q", tt.manager, tt.arch, got, tt.want)
+		}
+	}
+}
+
+func TestInstall_SkipsUnsupportedArch(t *testing.T) {
+	cfg := &config.Config{
+		Dependencies: config.Dependencies{
+			Core: []config.DependencyItem{
+				{Name: "x86-tool", Binary: "definitely-not-installed-xyz", Condition: map[string]string{"arch": "amd64"}},
+			},
+		},
+	}
+
+	result, err := Install(cfg, &platform.Platform{Architecture: "riscv64"}, InstallOptions{})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(result.Skipped) != 1 || len(result.Installed) != 0 || len(result.Failed) != 0 {
+		t.Errorf("Install() = %+v, want x86-tool skipped", result)
+	}
+}
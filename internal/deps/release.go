@@ -0,0 +1,292 @@
+package deps
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/nvandessel/go4dot/internal/config"
+	"github.com/nvandessel/go4dot/internal/platform"
+	"github.com/nvandessel/go4dot/internal/validation"
+)
+
+// MethodGitHubRelease installs an external from an asset of its repo's
+// latest GitHub release instead of cloning it
+const MethodGitHubRelease = "github_release"
+
+// releaseMarker is written into the destination of a github_release
+// external and holds the tag installed there
+const releaseMarker = ".go4dot-release"
+
+// releaseTimeout bounds asking for the release and downloading its asset
+const releaseTimeout = 5 * time.Minute
+
+// ErrUnsupportedArch is returned when a release has no asset for this
+// machine's OS and architecture
+var ErrUnsupportedArch = errors.New("unsupported arch")
+
+// ReleaseAsset is a file attached to a GitHub release
+type ReleaseAsset struct {
+	Name string `json:"name"`
+	URL  string `json:"browser_download_url"`
+}
+
+// githubRelease is the part of the API's release object go4dot uses
+type githubRelease struct {
+	TagName string         `json:"tag_name"`
+	Assets  []ReleaseAsset `json:"assets"`
+}
+
+// MatchReleaseAsset returns the asset whose name matches pattern, with
+// {os} and {arch} standing for any name of p's OS and architecture: {arch}
+// is amd64, x86_64 or x64 on amd64 and arm64 or aarch64 on arm64. Names
+// compare case-insensitively and pattern may use * and ? wildcards. Go's
+// names are tried first.
+func MatchReleaseAsset(assets []ReleaseAsset, pattern string, p *platform.Platform) (ReleaseAsset, error) {
+	for _, goos := range platform.OSAliases(p.OS) {
+		for _, arch := range platform.ArchAliases(p.Architecture) {
+			want := strings.ToLower(strings.NewReplacer("{os}", goos, "{arch}", arch).Replace(pattern))
+			for _, asset := range assets {
+				if ok, _ := path.Match(want, strings.ToLower(asset.Name)); ok {
+					return asset, nil
+				}
+			}
+		}
+	}
+	if strings.Contains(pattern, "{arch}") || strings.Contains(pattern, "{os}") {
+		return ReleaseAsset{}, fmt.Errorf("%w: no release asset for %s/%s matches %q", ErrUnsupportedArch, p.OS, p.Architecture, pattern)
+	}
+	return ReleaseAsset{}, fmt.Errorf("no release asset matches %q", pattern)
+}
+
+// InstalledRelease returns the release tag installed at dest by a
+// github_release external, or "" if there is none
+func InstalledRelease(dest string) string {
+	data, err := os.ReadFile(filepath.Join(dest, releaseMarker))
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(data))
+}
+
+// installRelease downloads the asset of ext's latest release matching
+// ext.Asset and unpacks it into dest: .tar.gz, .tgz and .zip archives are
+// extracted, any other file is saved as an executable named after ext's ID.
+// It returns the tag installed, or "" when dest already has it.
+func installRelease(ext config.ExternalDep, p *platform.Platform, dest string) (string, error) {
+	repo, ok := GitHubRepo(ext.URL)
+	if !ok {
+		return "", fmt.Errorf("%s is not a github.com repository", ext.URL)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), releaseTimeout)
+	defer cancel()
+	client := &http.Client{}
+
+	var release githubRelease
+	found, err := githubGet(ctx, client, GitHubToken(), "/repos/"+repo+"/releases/latest", &release)
+	if err != nil {
+		return "", err
+	}
+	if !found {
+		return "", fmt.Errorf("%s publishes no releases", repo)
+	}
+	if release.TagName != "" && release.TagName == InstalledRelease(dest) {
+		return "", nil
+	}
+
+	asset, err := MatchReleaseAsset(release.Assets, ext.Asset, p)
+	if err != nil {
+		return "", err
+	}
+
+	tmpDir, err := os.MkdirTemp("", "go4dot-release-*")
+	if err != nil {
+		return "", fmt.Errorf("failed to create temp directory: %w", err)
+	}
+	defer func() { _ = os.RemoveAll(tmpDir) }()
+
+	download := filepath.Join(tmpDir, "download")
+	if err := downloadFile(ctx, client, asset.URL, download); err != nil {
+		return "", fmt.Errorf("failed to download %s: %w", asset.Name, err)
+	}
+
+	unpacked := filepath.Join(tmpDir, "unpacked")
+	if err := unpackAsset(download, asset.Name, ext.ID, unpacked); err != nil {
+		return "", fmt.Errorf("failed to unpack %s: %w", asset.Name, err)
+	}
+	if err := os.WriteFile(filepath.Join(unpacked, releaseMarker), []byte(release.TagName+"\n"), 0644); err != nil {
+		return "", err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(dest), 0755); err != nil {
+		return "", fmt.Errorf("failed to create parent directory: %w", err)
+	}
+	if err := copyDir(unpacked, dest, ext.MergeStrategy); err != nil {
+		return "", err
+	}
+	return release.TagName, nil
+}
+
+// downloadFile saves what url serves to dest
+func downloadFile(ctx context.Context, client *http.Client, url, dest string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("server returned %d", resp.StatusCode)
+	}
+
+	f, err := os.Create(dest)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(f, resp.Body); err != nil {
+		_ = f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// unpackAsset extracts the archive at src, named name, into dir, or copies
+// a bare file there as an executable called binary
+func unpackAsset(src, name, binary, dir string) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	lower := strings.ToLower(name)
+	switch {
+	case strings.HasSuffix(lower, ".tar.gz") || strings.HasSuffix(lower, ".tgz"):
+		return extractTarGz(src, dir)
+	case strings.HasSuffix(lower, ".zip"):
+		return extractZip(src, dir)
+	}
+
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = in.Close() }()
+	return writeExtracted(filepath.Join(dir, binary), in, 0755)
+}
+
+// extractTarGz extracts the regular files and directories of a .tar.gz
+// archive into dir; links and devices are left out
+func extractTarGz(src, dir string) error {
+	f, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = f.Close() }()
+
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = gz.Close() }()
+
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		target, err := extractPath(dir, hdr.Name)
+		if err != nil {
+			return err
+		}
+		switch hdr.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(target, 0755); err != nil {
+				return err
+			}
+		case tar.TypeReg:
+			if err := writeExtracted(target, tr, os.FileMode(hdr.Mode).Perm()); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// extractZip extracts the files and directories of a .zip archive into dir
+func extractZip(src, dir string) error {
+	zr, err := zip.OpenReader(src)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = zr.Close() }()
+
+	for _, zf := range zr.File {
+		target, err := extractPath(dir, zf.Name)
+		if err != nil {
+			return err
+		}
+		if zf.FileInfo().IsDir() {
+			if err := os.MkdirAll(target, 0755); err != nil {
+				return err
+			}
+			continue
+		}
+		if !zf.Mode().IsRegular() {
+			continue
+		}
+		rc, err := zf.Open()
+		if err != nil {
+			return err
+		}
+		err = writeExtracted(target, rc, zf.Mode().Perm())
+		_ = rc.Close()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// extractPath returns where an archive entry is extracted inside dir,
+// refusing entries that would land outside it
+func extractPath(dir, name string) (string, error) {
+	target := filepath.Join(dir, filepath.FromSlash(name))
+	if err := validation.ValidateDestinationPath(target, dir); err != nil {
+		return "", fmt.Errorf("archive entry %q: %w", name, err)
+	}
+	return target, nil
+}
+
+// writeExtracted writes r to path with perm, creating parent directories
+func writeExtracted(path string, r io.Reader, perm os.FileMode) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	if perm == 0 {
+		perm = 0644
+	}
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, perm)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(f, r); err != nil {
+		_ = f.Close()
+		return err
+	}
+	return f.Close()
+}
@@ -0,0 +1,190 @@
+package deps
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/nvandessel/go4dot/internal/config"
+	"github.com/nvandessel/go4dot/internal/platform"
+)
+
+func TestMatchReleaseAsset(t *testing.T) {
+	assets := []ReleaseAsset{
+		{Name: "lazygit_0.44.1_Darwin_arm64.tar.gz"},
+		{Name: "lazygit_0.44.1_Linux_x86_64.tar.gz"},
+		{Name: "lazygit_0.44.1_Linux_arm64.tar.gz"},
+		{Name: "lazygit_0.44.1_Linux_armv6.tar.gz"},
+		{Name: "checksums.txt"},
+	}
+	pattern := "lazygit_*_{os}_{arch}.tar.gz"
+
+	tests := []struct {
+		name    string
+		p       *platform.Platform
+		want    string
+		wantErr error
+	}{
+		{"amd64 as x86_64", &platform.Platform{OS: "linux", Architecture: "amd64"}, "lazygit_0.44.1_Linux_x86_64.tar.gz", nil},
+		{"raspberry pi", &platform.Platform{OS: "linux", Architecture: "arm64"}, "lazygit_0.44.1_Linux_arm64.tar.gz", nil},
+		{"32-bit arm", &platform.Platform{OS: "linux", Architecture: "arm"}, "lazygit_0.44.1_Linux_armv6.tar.gz", nil},
+		{"macos", &platform.Platform{OS: "darwin", Architecture: "arm64"}, "lazygit_0.44.1_Darwin_arm64.tar.gz", nil},
+		{"riscv64", &platform.Platform{OS: "linux", Architecture: "riscv64"}, "", ErrUnsupportedArch},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := MatchReleaseAsset(assets, pattern, tt.p)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("MatchReleaseAsset() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil || got.Name != tt.want {
+				t.Errorf("MatchReleaseAsset() = %q, %v, want %q", got.Name, err, tt.want)
+			}
+		})
+	}
+}
+
+// tarGz builds a .tar.gz archive of files
+func tarGz(t *testing.T, files map[string]string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	for name, content := range files {
+		if err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0755, Size: int64(len(content)), Typeflag: tar.TypeReg}); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tw.Write([]byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := gz.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+// fakeRelease serves tag with an arm64 and an x86_64 asset holding archive
+func fakeRelease(t *testing.T, tag *string, archive []byte) {
+	t.Helper()
+	var serverURL string
+	fakeGitHub(t, func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/repos/owner/tool/releases/latest":
+			_ = json.NewEncoder(w).Encode(githubRelease{
+				TagName: *tag,
+				Assets: []ReleaseAsset{
+					{Name: "tool-linux-aarch64.tar.gz", URL: serverURL + "/download/arm64"},
+					{Name: "tool-linux-x86_64.tar.gz", URL: serverURL + "/download/amd64"},
+				},
+			})
+		case strings.HasPrefix(r.URL.Path, "/download/"):
+			_, _ = w.Write(archive)
+		default:
+			http.NotFound(w, r)
+		}
+	})
+	serverURL = githubAPIURL
+}
+
+func TestCloneExternal_GitHubRelease(t *testing.T) {
+	tag := "v1.0.0"
+	fakeRelease(t, &tag, tarGz(t, map[string]string{"tool-1.0/bin/tool": "#!/bin/sh\n"}))
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	cfg := &config.Config{External: []config.ExternalDep{{
+		Name:        "Tool",
+		ID:          "tool",
+		URL:         "https://github.com/owner/tool",
+		Destination: "~/.local/opt/tool",
+		Method:      MethodGitHubRelease,
+		Asset:       "tool-{os}-{arch}.tar.gz",
+	}}}
+	dest := filepath.Join(home, ".local", "opt", "tool")
+
+	result, err := CloneExternal(cfg, &platform.Platform{OS: "linux", Architecture: "arm64"}, ExternalOptions{})
+	if err != nil {
+		t.Fatalf("CloneExternal() error = %v", err)
+	}
+	if len(result.Cloned) != 1 {
+		t.Fatalf("CloneExternal() = %+v, want tool installed", result)
+	}
+	info, err := os.Stat(filepath.Join(dest, "tool-1.0", "bin", "tool"))
+	if err != nil || info.Mode().Perm()&0100 == 0 {
+		t.Errorf("extracted binary = %v, %v, want an executable", info, err)
+	}
+	if got := InstalledRelease(dest); got != "v1.0.0" {
+		t.Errorf("InstalledRelease() = %q, want v1.0.0", got)
+	}
+	statuses := CheckExternalStatus(cfg, &platform.Platform{OS: "linux", Architecture: "arm64"}, "")
+	if statuses[0].Status != "installed" || statuses[0].Reason != "release v1.0.0" {
+		t.Errorf("CheckExternalStatus() = %+v", statuses[0])
+	}
+
+	// Updating with the same release changes nothing; a new one replaces it
+	result, _ = CloneExternal(cfg, &platform.Platform{OS: "linux", Architecture: "arm64"}, ExternalOptions{Update: true})
+	if len(result.Skipped) != 1 || result.Skipped[0].Reason != "latest release installed" {
+		t.Errorf("CloneExternal(update) = %+v, want skipped as up to date", result)
+	}
+	tag = "v1.1.0"
+	result, _ = CloneExternal(cfg, &platform.Platform{OS: "linux", Architecture: "arm64"}, ExternalOptions{Update: true})
+	if len(result.Updated) != 1 || InstalledRelease(dest) != "v1.1.0" {
+		t.Errorf("CloneExternal(update) = %+v, installed %q, want v1.1.0", result, InstalledRelease(dest))
+	}
+}
+
+func TestCloneExternal_GitHubReleaseUnsupportedArch(t *testing.T) {
+	tag := "v1.0.0"
+	fakeRelease(t, &tag, tarGz(t, map[string]string{"tool": "x"}))
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	cfg := &config.Config{External: []config.ExternalDep{{
+		Name:        "Tool",
+		ID:          "tool",
+		URL:         "https://github.com/owner/tool",
+		Destination: "~/.local/opt/tool",
+		Method:      MethodGitHubRelease,
+		Asset:       "tool-{os}-{arch}.tar.gz",
+	}}}
+
+	result, err := CloneExternal(cfg, &platform.Platform{OS: "linux", Architecture: "riscv64"}, ExternalOptions{})
+	if err != nil {
+		t.Fatalf("CloneExternal() error = %v", err)
+	}
+	if len(result.Skipped) != 1 || !strings.HasPrefix(result.Skipped[0].Reason, "unsupported arch") {
+		t.Errorf("CloneExternal() = %+v, want skipped as unsupported arch", result)
+	}
+	if _, err := os.Stat(filepath.Join(home, ".local", "opt", "tool")); !os.IsNotExist(err) {
+		t.Error("nothing should be installed for an unsupported arch")
+	}
+}
+
+func TestExtractTarGz_RejectsTraversal(t *testing.T) {
+	dir := t.TempDir()
+	archive := filepath.Join(dir, "evil.tar.gz")
+	if err := os.WriteFile(archive, tarGz(t, map[string]string{"../escape": "x"}), 0644); err != nil {
+		t.Fatal(err)
+	}
+	out := filepath.Join(dir, "out")
+	if err := unpackAsset(archive, "evil.tar.gz", "evil", out); err == nil {
+		t.Error("unpackAsset() should refuse entries outside the destination")
+	}
+	if _, err := os.Stat(filepath.Join(dir, "escape")); !os.IsNotExist(err) {
+		t.Error("entry was written outside the destination")
+	}
+}
@@ -0,0 +1,246 @@
+package deps
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/nvandessel/go4dot/internal/config"
+	"github.com/nvandessel/go4dot/internal/platform"
+	"github.com/nvandessel/go4dot/internal/transcript"
+)
+
+// toolchainManager is how a language's version manager is installed and
+// driven. Each command is a bash script run after Setup, with the version
+// it applies to as $1.
+type toolchainManager struct {
+	Name    string // Version manager, e.g. rustup
+	Setup   string // Puts the manager on PATH or loads it, as a shell rc file would
+	Install string // Installs the manager
+	Has     string // Exits 0 when version $1 is installed
+	Add     string // Installs version $1
+	Use     string // Makes version $1 the default for new shells
+	IsUsed  string // Exits 0 when version $1 is the default
+	Active  string // Prints the default version
+}
+
+// toolchainManagers maps each language to its version manager
+var toolchainManagers = map[string]toolchainManager{
+	"rust": {
+		Name:    "rustup",
+		Setup:   `export PATH="${CARGO_HOME:-$HOME/.cargo}/bin:$PATH"`,
+		Install: `curl --proto '=https' --tlsv1.2 -sSf https://sh.rustup.rs | sh -s -- -y --no-modify-path --default-toolchain none`,
+		Has:     `rustup toolchain list | cut -d' ' -f1 | grep -qx -e "$1" -e "$1-.*"`,
+		Add:     `rustup toolchain install "$1"`,
+		Use:     `rustup default "$1"`,
+		IsUsed:  `rustup default | cut -d' ' -f1 | grep -qx -e "$1" -e "$1-.*"`,
+		Active:  `rustup default | cut -d' ' -f1`,
+	},
+	"node": {
+		Name:    "nvm",
+		Setup:   `export NVM_DIR="${NVM_DIR:-$HOME/.nvm}"; [ -s "$NVM_DIR/nvm.sh" ] && . "$NVM_DIR/nvm.sh"`,
+		Install: `curl -fsSL https://raw.githubusercontent.com/nvm-sh/nvm/v0.40.1/install.sh | PROFILE=/dev/null bash`,
+		Has:     `[ "$(nvm version "$1")" != "N/A" ]`,
+		Add:     `nvm install "$1"`,
+		Use:     `nvm alias default "$1"`,
+		IsUsed:  `[ "$(nvm version default)" = "$(nvm version "$1")" ]`,
+		Active:  `nvm version default`,
+	},
+	"python": {
+		Name:    "pyenv",
+		Setup:   `export PYENV_ROOT="${PYENV_ROOT:-$HOME/.pyenv}"; export PATH="$PYENV_ROOT/bin:$PATH"`,
+		Install: `curl -fsSL https://pyenv.run | bash`,
+		Has:     `pyenv latest "$1" >/dev/null 2>&1`,
+		Add:     `pyenv install --skip-existing "$1"`,
+		Use:     `pyenv global "$(pyenv latest "$1")"`,
+		IsUsed:  `[ "$(pyenv global | head -n 1)" = "$(pyenv latest "$1" 2>/dev/null)" ]`,
+		Active:  `pyenv global | head -n 1`,
+	},
+}
+
+// ToolchainManager returns the name of the version manager for language,
+// e.g. rustup for rust
+func ToolchainManager(language string) string {
+	return toolchainManagers[language].Name
+}
+
+// RunToolchainScript runs script with bash, passing args as $1 and on,
+// and returns its output. It is a variable so tests can fake the managers.
+var RunToolchainScript = func(script string, args ...string) ([]byte, error) {
+	cmd := exec.Command("bash", append([]string{"-c", script, "bash"}, args...)...)
+	if home, err := os.UserHomeDir(); err == nil {
+		cmd.Dir = home // Away from any per-directory version file
+	}
+	var out bytes.Buffer
+	cmd.Stdout = &out
+	cmd.Stderr = &out
+	if err := transcript.Run(cmd); err != nil {
+		if line := lastLine(out.String()); line != "" {
+			return out.Bytes(), fmt.Errorf("%w: %s", err, line)
+		}
+		return out.Bytes(), err
+	}
+	return out.Bytes(), nil
+}
+
+// ToolchainResult represents the result of setting up toolchains
+type ToolchainResult struct {
+	Installed []string // Managers and versions installed, e.g. "rustup" or "node 20"
+	Defaults  []string // Versions made the default, e.g. "python 3.12"
+	Failed    []ToolchainError
+	Skipped   []config.Toolchain // Condition not met
+}
+
+// ToolchainError represents a toolchain that could not be set up
+type ToolchainError struct {
+	Toolchain config.Toolchain
+	Error     error
+}
+
+// ToolchainOptions configures the toolchain setup
+type ToolchainOptions struct {
+	ProgressFunc func(current, total int, msg string) // Called for progress updates with item counts
+}
+
+// ToolchainStatus represents the state of a toolchain on this machine
+type ToolchainStatus struct {
+	Toolchain config.Toolchain
+	Status    string   // "ok", "missing" (manager or versions), "inactive" (another default) or "skipped"
+	Manager   bool     // The version manager is installed
+	Missing   []string // Versions that are not installed
+	Active    string   // The default version, as the manager reports it
+}
+
+// InstallToolchains installs the version manager of each toolchain whose
+// condition is met if it is missing, then the versions that are missing,
+// and makes the first version the default
+func InstallToolchains(cfg *config.Config, p *platform.Platform, opts ToolchainOptions) (*ToolchainResult, error) {
+	result := &ToolchainResult{}
+	if len(cfg.Toolchains) == 0 {
+		return result, nil
+	}
+	if _, err := exec.LookPath("bash"); err != nil {
+		return nil, fmt.Errorf("bash is required for toolchains but not found in PATH")
+	}
+
+	total := len(cfg.Toolchains)
+	for i, tc := range cfg.Toolchains {
+		current := i + 1
+		progress := func(msg string) {
+			if opts.ProgressFunc != nil {
+				opts.ProgressFunc(current, total, msg)
+			}
+		}
+
+		if !platform.CheckCondition(tc.Condition, p) {
+			result.Skipped = append(result.Skipped, tc)
+			progress(fmt.Sprintf("⊘ Skipping %s (condition not met)", tc.Language))
+			continue
+		}
+		if err := installToolchain(tc, result, progress); err != nil {
+			result.Failed = append(result.Failed, ToolchainError{Toolchain: tc, Error: err})
+			progress(fmt.Sprintf("✗ %s: %v", tc.Language, err))
+		}
+	}
+	return result, nil
+}
+
+// installToolchain sets up one toolchain, recording what it installed
+func installToolchain(tc config.Toolchain, result *ToolchainResult, progress func(string)) error {
+	m, ok := toolchainManagers[tc.Language]
+	if !ok {
+		return fmt.Errorf("unsupported language %q", tc.Language)
+	}
+
+	if !m.installed() {
+		progress(fmt.Sprintf("⬇ Installing %s...", m.Name))
+		if _, err := RunToolchainScript(m.Install); err != nil {
+			return fmt.Errorf("failed to install %s: %w", m.Name, err)
+		}
+		if !m.installed() {
+			return fmt.Errorf("%s is still not found after installing it", m.Name)
+		}
+		result.Installed = append(result.Installed, m.Name)
+	}
+
+	for _, v := range tc.Versions {
+		if m.run(m.Has, v) == nil {
+			continue
+		}
+		progress(fmt.Sprintf("⬇ Installing %s %s with %s...", tc.Language, v, m.Name))
+		if err := m.run(m.Add, v); err != nil {
+			return fmt.Errorf("failed to install %s %s: %w", tc.Language, v, err)
+		}
+		result.Installed = append(result.Installed, tc.Language+" "+v)
+	}
+
+	def := tc.DefaultVersion()
+	if m.run(m.IsUsed, def) == nil {
+		progress(fmt.Sprintf("✓ %s %s is the default", tc.Language, def))
+		return nil
+	}
+	if err := m.run(m.Use, def); err != nil {
+		return fmt.Errorf("failed to make %s %s the default: %w", tc.Language, def, err)
+	}
+	result.Defaults = append(result.Defaults, tc.Language+" "+def)
+	progress(fmt.Sprintf("✓ %s %s is now the default", tc.Language, def))
+	return nil
+}
+
+// CheckToolchains reports whether each toolchain's manager and versions are
+// installed and its first version is the default
+func CheckToolchains(cfg *config.Config, p *platform.Platform) []ToolchainStatus {
+	var statuses []ToolchainStatus
+	for _, tc := range cfg.Toolchains {
+		status := ToolchainStatus{Toolchain: tc}
+		m, ok := toolchainManagers[tc.Language]
+		switch {
+		case !platform.CheckCondition(tc.Condition, p):
+			status.Status = "skipped"
+		case !ok || !m.installed():
+			status.Status = "missing"
+			status.Missing = tc.Versions
+		default:
+			status.Manager = true
+			for _, v := range tc.Versions {
+				if m.run(m.Has, v) != nil {
+					status.Missing = append(status.Missing, v)
+				}
+			}
+			if out, err := RunToolchainScript(m.Setup + "\n" + m.Active); err == nil {
+				status.Active = strings.TrimSpace(string(out))
+			}
+			switch {
+			case len(status.Missing) > 0:
+				status.Status = "missing"
+			case m.run(m.IsUsed, tc.DefaultVersion()) != nil:
+				status.Status = "inactive"
+			default:
+				status.Status = "ok"
+			}
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses
+}
+
+// installed reports whether the manager can be run
+func (m toolchainManager) installed() bool {
+	_, err := RunToolchainScript(m.Setup + "\ncommand -v " + m.Name + " >/dev/null")
+	return err == nil
+}
+
+// run runs one of the manager's scripts for version
+func (m toolchainManager) run(script, version string) error {
+	_, err := RunToolchainScript(m.Setup+"\n"+script, version)
+	return err
+}
+
+// lastLine returns the last non-empty line of output, which is where
+// installers put the reason they failed
+func lastLine(output string) string {
+	lines := strings.Split(strings.TrimSpace(output), "\n")
+	return strings.TrimSpace(lines[len(lines)-1])
+}
@@ -0,0 +1,146 @@
+package deps
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/nvandessel/go4dot/internal/config"
+	"github.com/nvandessel/go4dot/internal/platform"
+)
+
+// fakeRustup stands in for rustup and the toolchains it has installed
+type fakeRustup struct {
+	installed bool
+	versions  map[string]bool
+	def       string
+	scripts   []string
+}
+
+func (f *fakeRustup) run(script string, args ...string) ([]byte, error) {
+	f.scripts = append(f.scripts, script)
+	m := toolchainManagers["rust"]
+	fail := errors.New("exit status 1")
+	var arg string
+	if len(args) > 0 {
+		arg = args[0]
+	}
+
+	switch {
+	case strings.HasSuffix(script, "command -v rustup >/dev/null"):
+		if !f.installed {
+			return nil, fail
+		}
+	case script == m.Install:
+		f.installed = true
+	case strings.HasSuffix(script, m.Has):
+		if !f.versions[arg] {
+			return nil, fail
+		}
+	case strings.HasSuffix(script, m.Add):
+		f.versions[arg] = true
+	case strings.HasSuffix(script, m.IsUsed):
+		if f.def != arg {
+			return nil, fail
+		}
+	case strings.HasSuffix(script, m.Use):
+		f.def = arg
+	case strings.HasSuffix(script, m.Active):
+		return []byte(f.def + "\n"), nil
+	default:
+		return nil, errors.New("unexpected script: " + script)
+	}
+	return nil, nil
+}
+
+func useFakeRustup(t *testing.T, f *fakeRustup) {
+	t.Helper()
+	orig := RunToolchainScript
+	RunToolchainScript = f.run
+	t.Cleanup(func() { RunToolchainScript = orig })
+}
+
+func TestInstallToolchains(t *testing.T) {
+	fake := &fakeRustup{versions: map[string]bool{}}
+	useFakeRustup(t, fake)
+
+	cfg := &config.Config{
+		Toolchains: []config.Toolchain{
+			{Language: "rust", Versions: []string{"stable", "1.78.0"}},
+			{Language: "node", Versions: []string{"20"}, Condition: map[string]string{"os": "plan9"}},
+		},
+	}
+	p := &platform.Platform{OS: "linux"}
+
+	result, err := InstallToolchains(cfg, p, ToolchainOptions{})
+	if err != nil {
+		t.Fatalf("InstallToolchains() error = %v", err)
+	}
+	if want := []string{"rustup", "rust stable", "rust 1.78.0"}; !reflect.DeepEqual(result.Installed, want) {
+		t.Errorf("Installed = %v, want %v", result.Installed, want)
+	}
+	if !reflect.DeepEqual(result.Defaults, []string{"rust stable"}) || fake.def != "stable" {
+		t.Errorf("Defaults = %v, default = %q; want stable", result.Defaults, fake.def)
+	}
+	if len(result.Skipped) != 1 || len(result.Failed) != 0 {
+		t.Errorf("Skipped = %v, Failed = %v", result.Skipped, result.Failed)
+	}
+
+	// Everything is in place: nothing is installed again
+	result, err = InstallToolchains(cfg, p, ToolchainOptions{})
+	if err != nil {
+		t.Fatalf("second InstallToolchains() error = %v", err)
+	}
+	if len(result.Installed) != 0 || len(result.Defaults) != 0 {
+		t.Errorf("second run: Installed = %v, Defaults = %v", result.Installed, result.Defaults)
+	}
+}
+
+func TestInstallToolchains_Failure(t *testing.T) {
+	fake := &fakeRustup{versions: map[string]bool{}}
+	useFakeRustup(t, fake)
+	RunToolchainScript = func(script string, args ...string) ([]byte, error) {
+		if strings.HasSuffix(script, toolchainManagers["rust"].Add) {
+			return nil, errors.New("exit status 1: toolchain 'nightly-2001' is not installable")
+		}
+		return fake.run(script, args...)
+	}
+
+	cfg := &config.Config{Toolchains: []config.Toolchain{{Language: "rust", Versions: []string{"nightly-2001"}}}}
+	result, err := InstallToolchains(cfg, &platform.Platform{}, ToolchainOptions{})
+	if err != nil {
+		t.Fatalf("InstallToolchains() error = %v", err)
+	}
+	if len(result.Failed) != 1 || !strings.Contains(result.Failed[0].Error.Error(), "not installable") {
+		t.Errorf("Failed = %v, want the install error", result.Failed)
+	}
+}
+
+func TestCheckToolchains(t *testing.T) {
+	fake := &fakeRustup{installed: true, versions: map[string]bool{"stable": true, "1.78.0": true}, def: "1.78.0"}
+	useFakeRustup(t, fake)
+
+	cfg := &config.Config{Toolchains: []config.Toolchain{{Language: "rust", Versions: []string{"stable", "1.78.0"}}}}
+	p := &platform.Platform{}
+
+	statuses := CheckToolchains(cfg, p)
+	if len(statuses) != 1 || statuses[0].Status != "inactive" || statuses[0].Active != "1.78.0" {
+		t.Fatalf("CheckToolchains() = %+v, want inactive with 1.78.0 active", statuses)
+	}
+
+	fake.def = "stable"
+	if s := CheckToolchains(cfg, p)[0]; s.Status != "ok" {
+		t.Errorf("Status = %q, want ok", s.Status)
+	}
+
+	delete(fake.versions, "1.78.0")
+	if s := CheckToolchains(cfg, p)[0]; s.Status != "missing" || !reflect.DeepEqual(s.Missing, []string{"1.78.0"}) {
+		t.Errorf("Status = %q, Missing = %v; want missing [1.78.0]", s.Status, s.Missing)
+	}
+
+	fake.installed = false
+	if s := CheckToolchains(cfg, p)[0]; s.Status != "missing" || s.Manager {
+		t.Errorf("Status = %q, Manager = %v; want missing without the manager", s.Status, s.Manager)
+	}
+}
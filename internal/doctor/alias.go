@@ -0,0 +1,70 @@
+package doctor
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/nvandessel/go4dot/internal/alias"
+	"github.com/nvandessel/go4dot/internal/version"
+)
+
+// checkAlias verifies that both names of the binary run this version
+func checkAlias() Check {
+	self, err := os.Executable()
+	if err != nil {
+		return Check{
+			ID:          "alias",
+			Name:        "Binary Alias",
+			Description: "g4d and go4dot run the same version",
+			Status:      StatusSkipped,
+			Message:     fmt.Sprintf("Cannot locate the running binary: %v", err),
+		}
+	}
+	return aliasCheck(self, version.GetToolVersion())
+}
+
+// aliasCheck checks how each name resolves on PATH against self, the
+// running binary, which reports want as its version
+func aliasCheck(self, want string) Check {
+	check := Check{
+		ID:          "alias",
+		Name:        "Binary Alias",
+		Description: "g4d and go4dot run the same version",
+	}
+
+	var missing []string
+	for _, name := range alias.Names {
+		r := alias.Resolve(name, self)
+		switch {
+		case r.Path == "":
+			missing = append(missing, name)
+		case r.Current:
+		case r.Version == "":
+			check.Status = StatusWarning
+			check.Message = fmt.Sprintf("%s at %s could not be run", name, r.Path)
+			check.Fix = fmt.Sprintf("Run 'g4d alias --name %s --dir %s --force'", name, filepath.Dir(r.Path))
+			return check
+		case r.Version != want || want == "dev":
+			check.Status = StatusWarning
+			check.Message = fmt.Sprintf("%s at %s runs %s, not %s", name, r.Path, r.Version, want)
+			check.Fix = fmt.Sprintf("Run 'g4d alias --name %s --dir %s --force'", name, filepath.Dir(r.Path))
+			return check
+		}
+	}
+
+	switch len(missing) {
+	case 0:
+		check.Status = StatusOK
+		check.Message = fmt.Sprintf("%s both run %s", strings.Join(alias.Names, " and "), want)
+	case len(alias.Names):
+		check.Status = StatusSkipped
+		check.Message = "go4dot is not on PATH"
+	default:
+		check.Status = StatusWarning
+		check.Message = fmt.Sprintf("%s is not on PATH", strings.Join(missing, ", "))
+		check.Fix = "Run 'g4d alias' to link it next to this binary"
+	}
+	return check
+}
@@ -0,0 +1,83 @@
+package doctor
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/nvandessel/go4dot/internal/alias"
+)
+
+func TestAliasCheck(t *testing.T) {
+	orig := alias.RunVersion
+	alias.RunVersion = func(path string) (string, error) { return "1.0.0", nil }
+	defer func() { alias.RunVersion = orig }()
+
+	tests := []struct {
+		name   string
+		setup  func(t *testing.T, dir, self string)
+		want   string
+		status CheckStatus
+	}{
+		{
+			name:   "both linked",
+			setup:  func(t *testing.T, dir, self string) { symlinkOrFail(t, self, filepath.Join(dir, alias.LongName)) },
+			want:   "1.2.0",
+			status: StatusOK,
+		},
+		{
+			name:   "alias missing",
+			setup:  func(t *testing.T, dir, self string) {},
+			want:   "1.2.0",
+			status: StatusWarning,
+		},
+		{
+			name:   "stale copy",
+			setup:  func(t *testing.T, dir, self string) { writeExecutable(t, filepath.Join(dir, alias.LongName)) },
+			want:   "1.2.0",
+			status: StatusWarning,
+		},
+		{
+			name:   "copy of the same version",
+			setup:  func(t *testing.T, dir, self string) { writeExecutable(t, filepath.Join(dir, alias.LongName)) },
+			want:   "1.0.0",
+			status: StatusOK,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			self := filepath.Join(dir, alias.ShortName)
+			writeExecutable(t, self)
+			tt.setup(t, dir, self)
+			t.Setenv("PATH", dir)
+
+			check := aliasCheck(self, tt.want)
+			if check.Status != tt.status {
+				t.Errorf("aliasCheck() status = %s (%s), want %s", check.Status, check.Message, tt.status)
+			}
+		})
+	}
+
+	t.Run("not on PATH", func(t *testing.T) {
+		t.Setenv("PATH", t.TempDir())
+		if check := aliasCheck("/nonexistent/g4d", "1.2.0"); check.Status != StatusSkipped {
+			t.Errorf("aliasCheck() status = %s, want skipped", check.Status)
+		}
+	})
+}
+
+func writeExecutable(t *testing.T, path string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte("#!/bin/sh\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func symlinkOrFail(t *testing.T, target, path string) {
+	t.Helper()
+	if err := os.Symlink(target, path); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,169 @@
+package doctor
+
+import (
+	"fmt"
+	"io/fs"
+	"maps"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/nvandessel/go4dot/internal/config"
+	"github.com/nvandessel/go4dot/internal/stow"
+)
+
+// artifactRule recognizes generated content that usually should not be
+// versioned in a dotfiles repo
+type artifactRule struct {
+	Reason string         // Human readable description
+	Match  *regexp.Regexp // Matched against the file or directory name
+	Dir    bool           // Only match directories (the whole tree is flagged)
+	Ignore string         // Suggested .stow-local-ignore entry
+}
+
+var artifactRules = []artifactRule{
+	{Reason: "node dependencies", Match: regexp.MustCompile(`^node_modules$`), Dir: true, Ignore: `node_modules`},
+	{Reason: "cache directory", Match: regexp.MustCompile(`^\.?cache$`), Dir: true, Ignore: `\.?cache`},
+	{Reason: "python bytecode", Match: regexp.MustCompile(`^__pycache__$`), Dir: true, Ignore: `__pycache__`},
+	{Reason: "python bytecode", Match: regexp.MustCompile(`\.py[co]$`), Ignore: `.+\.py[co]`},
+	{Reason: "undo history", Match: regexp.MustCompile(`^\.?undo(dir)?$`), Dir: true, Ignore: `\.?undo(dir)?`},
+	{Reason: "undo history", Match: regexp.MustCompile(`\.un~$`), Ignore: `.+\.un~`},
+	{Reason: "swap file", Match: regexp.MustCompile(`^\..+\.sw[a-p]$`), Ignore: `\..+\.sw[a-p]`},
+	{Reason: "plugin state", Match: regexp.MustCompile(`^\.netrwhist$`), Ignore: `\.netrwhist`},
+	{Reason: "plugin state", Match: regexp.MustCompile(`^packer_compiled\.lua$`), Ignore: `packer_compiled\.lua`},
+	{Reason: "plugin state", Match: regexp.MustCompile(`^\.?viminfo$`), Ignore: `\.?viminfo`},
+	{Reason: "compiled zsh files", Match: regexp.MustCompile(`^\.zcompdump.*|.+\.zwc$`), Ignore: `\.zcompdump.*|.+\.zwc`},
+	{Reason: "shell history", Match: regexp.MustCompile(`^\.[a-z_]*_?history$|^\.lesshst$`), Ignore: `\.[a-z_]*_?history|\.lesshst`},
+	{Reason: "macOS metadata", Match: regexp.MustCompile(`^\.DS_Store$`), Ignore: `\.DS_Store`},
+}
+
+// ArtifactFinding is a cache, build artifact or state file found inside a
+// config directory
+type ArtifactFinding struct {
+	Config string `json:"config"`
+	Path   string `json:"path"` // Relative to the config directory
+	Reason string `json:"reason"`
+	Ignore string `json:"ignore"` // Suggested .stow-local-ignore entry
+}
+
+// FindArtifacts scans every config directory for content that looks
+// generated rather than hand-written. Paths stow already ignores and those
+// excluded by .go4dotignore are skipped.
+func FindArtifacts(cfg *config.Config, dotfilesPath string) []ArtifactFinding {
+	repoIgnore, _ := config.LoadRepoIgnore(dotfilesPath)
+
+	var findings []ArtifactFinding
+	for _, item := range cfg.GetAllConfigs() {
+		findings = append(findings, findConfigArtifacts(item, dotfilesPath, repoIgnore)...)
+	}
+	return findings
+}
+
+func findConfigArtifacts(item config.ConfigItem, dotfilesPath string, repoIgnore *config.RepoIgnore) []ArtifactFinding {
+	configDir := item.Dir(dotfilesPath)
+	if _, err := os.Stat(configDir); err != nil {
+		return nil
+	}
+
+	ignore, err := stow.LoadIgnoreList(configDir)
+	if err != nil {
+		return nil
+	}
+
+	var findings []ArtifactFinding
+	_ = filepath.WalkDir(configDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || path == configDir {
+			return nil
+		}
+
+		relPath, _ := filepath.Rel(configDir, path)
+		if ignore.Match(relPath) || repoIgnore.Match(filepath.Join(item.Path, relPath), d.IsDir()) {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		for _, rule := range artifactRules {
+			if rule.Dir != d.IsDir() || !rule.Match.MatchString(d.Name()) {
+				continue
+			}
+			findings = append(findings, ArtifactFinding{
+				Config: item.Name,
+				Path:   relPath,
+				Reason: rule.Reason,
+				Ignore: rule.Ignore,
+			})
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		return nil
+	})
+
+	return findings
+}
+
+// SuggestedIgnores groups the ignore entries suggested by findings per
+// config, without duplicates
+func SuggestedIgnores(findings []ArtifactFinding) map[string][]string {
+	suggested := make(map[string][]string)
+	seen := make(map[string]bool)
+	for _, f := range findings {
+		key := f.Config + "\x00" + f.Ignore
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		suggested[f.Config] = append(suggested[f.Config], f.Ignore)
+	}
+	return suggested
+}
+
+// AddArtifactIgnores appends the suggested ignore entries to each affected
+// config's .stow-local-ignore and returns the entries added per config
+func AddArtifactIgnores(cfg *config.Config, dotfilesPath string, findings []ArtifactFinding) (map[string][]string, error) {
+	suggested := SuggestedIgnores(findings)
+	added := make(map[string][]string)
+
+	for _, name := range slices.Sorted(maps.Keys(suggested)) {
+		item := cfg.GetConfigByName(name)
+		if item == nil {
+			continue
+		}
+		entries, err := stow.AddIgnorePatterns(item.Dir(dotfilesPath), suggested[name])
+		if err != nil {
+			return added, fmt.Errorf("config '%s': %w", name, err)
+		}
+		if len(entries) > 0 {
+			added[name] = entries
+		}
+	}
+
+	return added, nil
+}
+
+// summarizeArtifactCheck creates a check summary from artifact findings
+func summarizeArtifactCheck(findings []ArtifactFinding) Check {
+	check := Check{
+		ID:          "artifacts",
+		Name:        "Repository Artifacts",
+		Description: "Caches and generated files inside configs",
+	}
+
+	if len(findings) == 0 {
+		check.Status = StatusOK
+		check.Message = "No caches or generated files found"
+		return check
+	}
+
+	names := slices.Sorted(maps.Keys(SuggestedIgnores(findings)))
+
+	check.Status = StatusWarning
+	check.Message = fmt.Sprintf("%d likely unwanted item(s) in %s", len(findings), strings.Join(names, ", "))
+	check.Fix = "Run 'g4d doctor --ignore-artifacts' to add them to .stow-local-ignore"
+	return check
+}
@@ -0,0 +1,168 @@
+package doctor
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/nvandessel/go4dot/internal/config"
+	"github.com/nvandessel/go4dot/internal/stow"
+)
+
+func setupArtifactFixture(t *testing.T, files []string) (*config.Config, string) {
+	t.Helper()
+
+	t.Setenv("HOME", t.TempDir())
+	dotfiles := t.TempDir()
+	for _, rel := range files {
+		path := filepath.Join(dotfiles, rel)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte("x"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	cfg := &config.Config{
+		Configs: config.ConfigGroups{
+			Core: []config.ConfigItem{
+				{Name: "nvim", Path: "nvim"},
+				{Name: "zsh", Path: "zsh"},
+			},
+		},
+	}
+	return cfg, dotfiles
+}
+
+func TestFindArtifacts(t *testing.T) {
+	cfg, dotfiles := setupArtifactFixture(t, []string{
+		"nvim/.config/nvim/init.lua",
+		"nvim/.config/nvim/plugin/packer_compiled.lua",
+		"nvim/.config/nvim/node_modules/pkg/index.js",
+		"nvim/.config/nvim/node_modules/other/index.js",
+		"nvim/.config/nvim/undo/%home%user%file",
+		"nvim/.config/nvim/.git/cache/x", // Ignored by stow already
+		"zsh/.zshrc",
+		"zsh/.zcompdump-host-5.9",
+		"zsh/.zsh_history",
+		"zsh/.config/tool/lib/__pycache__/mod.cpython-312.pyc",
+		"zsh/.config/tool/lib/mod.pyc",
+	})
+
+	findings := FindArtifacts(cfg, dotfiles)
+
+	got := make(map[string]string)
+	for _, f := range findings {
+		got[f.Config+":"+filepath.ToSlash(f.Path)] = f.Reason
+	}
+
+	want := map[string]string{
+		"nvim:.config/nvim/plugin/packer_compiled.lua": "plugin state",
+		"nvim:.config/nvim/node_modules":               "node dependencies",
+		"nvim:.config/nvim/undo":                       "undo history",
+		"zsh:.zcompdump-host-5.9":                      "compiled zsh files",
+		"zsh:.zsh_history":                             "shell history",
+		"zsh:.config/tool/lib/__pycache__":             "python bytecode",
+		"zsh:.config/tool/lib/mod.pyc":                 "python bytecode",
+	}
+	if len(got) != len(want) {
+		t.Errorf("got %d findings, want %d: %v", len(got), len(want), got)
+	}
+	for key, reason := range want {
+		if got[key] != reason {
+			t.Errorf("finding %s = %q, want %q", key, got[key], reason)
+		}
+	}
+}
+
+func TestFindArtifacts_RepoIgnore(t *testing.T) {
+	cfg, dotfiles := setupArtifactFixture(t, []string{
+		"nvim/.config/nvim/node_modules/pkg/index.js",
+		"zsh/.zsh_history",
+	})
+	if err := os.WriteFile(filepath.Join(dotfiles, config.IgnoreFileName), []byte("node_modules/\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	findings := FindArtifacts(cfg, dotfiles)
+	if len(findings) != 1 || findings[0].Path != ".zsh_history" {
+		t.Errorf("FindArtifacts() = %+v, want only .zsh_history", findings)
+	}
+}
+
+func TestSuggestedIgnores(t *testing.T) {
+	findings := []ArtifactFinding{
+		{Config: "a", Ignore: "node_modules"},
+		{Config: "a", Ignore: "node_modules"},
+		{Config: "a", Ignore: `\.DS_Store`},
+		{Config: "b", Ignore: "node_modules"},
+	}
+
+	got := SuggestedIgnores(findings)
+	if len(got["a"]) != 2 || len(got["b"]) != 1 {
+		t.Errorf("SuggestedIgnores() = %v, want 2 entries for a and 1 for b", got)
+	}
+}
+
+func TestAddArtifactIgnores(t *testing.T) {
+	cfg, dotfiles := setupArtifactFixture(t, []string{
+		"zsh/.zshrc",
+		"zsh/.zsh_history",
+		"zsh/.DS_Store",
+		"nvim/.config/nvim/init.lua",
+	})
+
+	findings := FindArtifacts(cfg, dotfiles)
+	added, err := AddArtifactIgnores(cfg, dotfiles, findings)
+	if err != nil {
+		t.Fatalf("AddArtifactIgnores() error = %v", err)
+	}
+	if len(added["zsh"]) != 2 || len(added["nvim"]) != 0 {
+		t.Errorf("added = %v, want 2 entries for zsh only", added)
+	}
+
+	if remaining := FindArtifacts(cfg, dotfiles); len(remaining) != 0 {
+		t.Errorf("FindArtifacts() after ignoring = %v, want none", remaining)
+	}
+
+	// The defaults stay in effect once a local ignore file exists
+	ignore, err := stow.LoadIgnoreList(filepath.Join(dotfiles, "zsh"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ignore.Match(".git") || ignore.Match(".zshrc") {
+		t.Error("local ignore file should keep stow's defaults and not ignore .zshrc")
+	}
+
+	// Running again adds nothing
+	added, err = AddArtifactIgnores(cfg, dotfiles, findings)
+	if err != nil {
+		t.Fatalf("AddArtifactIgnores() second run error = %v", err)
+	}
+	if len(added) != 0 {
+		t.Errorf("second run added %v, want nothing", added)
+	}
+}
+
+func TestSummarizeArtifactCheck(t *testing.T) {
+	check := summarizeArtifactCheck(nil)
+	if check.Status != StatusOK {
+		t.Errorf("Status = %s, want ok", check.Status)
+	}
+
+	check = summarizeArtifactCheck([]ArtifactFinding{
+		{Config: "zsh", Ignore: "a"},
+		{Config: "nvim", Ignore: "b"},
+	})
+	if check.Status != StatusWarning {
+		t.Errorf("Status = %s, want warning", check.Status)
+	}
+	if !strings.Contains(check.Message, "nvim, zsh") {
+		t.Errorf("Message = %q, want sorted config names", check.Message)
+	}
+	if check.Fix == "" {
+		t.Error("expected a suggested fix")
+	}
+}
@@ -3,6 +3,7 @@ package doctor
 import (
 	"context"
 	"fmt"
+	"io/fs"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -15,6 +16,7 @@ import (
 	"github.com/nvandessel/go4dot/internal/platform"
 	"github.com/nvandessel/go4dot/internal/state"
 	"github.com/nvandessel/go4dot/internal/stow"
+	"github.com/nvandessel/go4dot/internal/version"
 )
 
 // CheckStatus represents the status of a health check
@@ -29,6 +31,7 @@ const (
 
 // Check represents a single health check result
 type Check struct {
+	ID          string // Stable identifier used in config, e.g. "unmanaged-symlinks"
 	Name        string
 	Description string
 	Status      CheckStatus
@@ -42,10 +45,18 @@ type CheckResult struct {
 	Checks                []Check
 	DepsResult            *deps.CheckResult
 	ExternalStatus        []deps.ExternalStatus
+	ToolchainStatus       []deps.ToolchainStatus
 	MachineStatus         []machine.MachineConfigStatus
 	SymlinkStatus         []SymlinkCheck
+	Functional            []FunctionalCheck
+	LinkStatus            []stow.LinkStatus
 	UnmanagedLinks        []UnmanagedSymlink
+	SymlinkScan           *SymlinkScanCoverage
 	AdoptionOpportunities []AdoptionOpportunity
+	Artifacts             []ArtifactFinding
+	PersonalData          []PersonalDataFinding
+
+	ignoredConflicts bool // The symlinks check is ignored, so conflicts do not count
 }
 
 // SymlinkCheck represents the status of a stowed symlink
@@ -54,6 +65,7 @@ type SymlinkCheck struct {
 	TargetPath string
 	Status     CheckStatus
 	Message    string
+	Conflict   bool // An existing file blocks the symlink
 }
 
 // UnmanagedSymlink represents a symlink pointing to dotfiles but not in config
@@ -88,6 +100,7 @@ func RunChecks(cfg *config.Config, opts CheckOptions) (*CheckResult, error) {
 	}
 	result.Platform = p
 	result.Checks = append(result.Checks, Check{
+		ID:          "platform",
 		Name:        "Platform Detection",
 		Description: "Detect OS and package manager",
 		Status:      StatusOK,
@@ -96,7 +109,10 @@ func RunChecks(cfg *config.Config, opts CheckOptions) (*CheckResult, error) {
 
 	// Step 2: Check stow is installed
 	progress(opts, "Checking GNU stow...")
-	stowCheck := checkStow()
+	stowCheck := checkStow(cfg.Stow.BinaryPath(), p.PackageManager)
+	if stowCheck.Status == StatusOK && opts.DotfilesPath != "" {
+		checkDotPrefix(&stowCheck, cfg, opts.DotfilesPath)
+	}
 	result.Checks = append(result.Checks, stowCheck)
 
 	// Step 3: Check git is installed
@@ -104,11 +120,16 @@ func RunChecks(cfg *config.Config, opts CheckOptions) (*CheckResult, error) {
 	gitCheck := checkGit()
 	result.Checks = append(result.Checks, gitCheck)
 
-	// Step 4: Check dependencies
+	// Step 4: Check both names of the binary run this version
+	progress(opts, "Checking binary alias...")
+	result.Checks = append(result.Checks, checkAlias())
+
+	// Step 5: Check dependencies
 	progress(opts, "Checking dependencies...")
 	depsResult, err := deps.Check(cfg, p)
 	if err != nil {
 		result.Checks = append(result.Checks, Check{
+			ID:          "dependencies",
 			Name:        "Dependencies",
 			Description: "Check required packages",
 			Status:      StatusError,
@@ -120,7 +141,13 @@ func RunChecks(cfg *config.Config, opts CheckOptions) (*CheckResult, error) {
 		result.Checks = append(result.Checks, depCheck)
 	}
 
-	// Step 5: Check symlinks
+	// Step 6: Check pacman's package information is fresh enough to install from
+	if p.PackageManager == "pacman" {
+		progress(opts, "Checking pacman database...")
+		result.Checks = append(result.Checks, checkPacman(platform.CheckPacman(), time.Now()))
+	}
+
+	// Step 7: Check symlinks
 	progress(opts, "Checking symlinks...")
 	if opts.DotfilesPath != "" && !stowCheck.Status.isError() {
 		symlinkStatus := checkSymlinks(cfg, opts.DotfilesPath)
@@ -129,6 +156,7 @@ func RunChecks(cfg *config.Config, opts CheckOptions) (*CheckResult, error) {
 		result.Checks = append(result.Checks, symlinkCheck)
 	} else {
 		result.Checks = append(result.Checks, Check{
+			ID:          "symlinks",
 			Name:        "Symlinks",
 			Description: "Check stowed config symlinks",
 			Status:      StatusSkipped,
@@ -136,7 +164,25 @@ func RunChecks(cfg *config.Config, opts CheckOptions) (*CheckResult, error) {
 		})
 	}
 
-	// Step 6: Check external dependencies
+	// Step 8: Run the configs' verify commands
+	progress(opts, "Running verify commands...")
+	if opts.DotfilesPath != "" {
+		functional := RunFunctionalChecks(cfg, opts.DotfilesPath, nil)
+		result.Functional = functional
+		if len(functional) > 0 {
+			result.Checks = append(result.Checks, summarizeFunctionalCheck(functional))
+		}
+	}
+
+	// Step 9: Check the symlinks from the links section
+	progress(opts, "Checking links...")
+	if opts.DotfilesPath != "" && len(cfg.Links) > 0 {
+		linkStatus := stow.CheckLinks(cfg, opts.DotfilesPath)
+		result.LinkStatus = linkStatus
+		result.Checks = append(result.Checks, summarizeLinkCheck(linkStatus))
+	}
+
+	// Step 10: Check external dependencies
 	progress(opts, "Checking external dependencies...")
 	if len(cfg.External) > 0 {
 		extStatus := deps.CheckExternalStatus(cfg, p, opts.DotfilesPath)
@@ -145,7 +191,15 @@ func RunChecks(cfg *config.Config, opts CheckOptions) (*CheckResult, error) {
 		result.Checks = append(result.Checks, extCheck)
 	}
 
-	// Step 7: Check machine configs
+	// Step 11: Check language toolchains
+	progress(opts, "Checking toolchains...")
+	if len(cfg.Toolchains) > 0 {
+		tcStatus := deps.CheckToolchains(cfg, p)
+		result.ToolchainStatus = tcStatus
+		result.Checks = append(result.Checks, summarizeToolchainCheck(tcStatus))
+	}
+
+	// Step 12: Check machine configs
 	progress(opts, "Checking machine configurations...")
 	if len(cfg.MachineConfig) > 0 {
 		machineStatus := machine.CheckMachineConfigStatus(cfg)
@@ -154,30 +208,16 @@ func RunChecks(cfg *config.Config, opts CheckOptions) (*CheckResult, error) {
 		result.Checks = append(result.Checks, machineCheck)
 	}
 
-	// Step 8: Check for unmanaged symlinks
+	// Step 13: Check for unmanaged symlinks
 	progress(opts, "Checking for unmanaged symlinks...")
 	if opts.DotfilesPath != "" {
-		unmanaged := checkUnmanagedSymlinks(cfg, opts.DotfilesPath)
+		unmanaged, coverage := checkUnmanagedSymlinks(cfg, opts.DotfilesPath)
 		result.UnmanagedLinks = unmanaged
-		if len(unmanaged) > 0 {
-			result.Checks = append(result.Checks, Check{
-				Name:        "Unmanaged Symlinks",
-				Description: "Symlinks pointing to dotfiles but not in config",
-				Status:      StatusWarning,
-				Message:     fmt.Sprintf("%d unmanaged symlinks found", len(unmanaged)),
-				Fix:         "Add these to your .go4dot.yaml or remove them",
-			})
-		} else {
-			result.Checks = append(result.Checks, Check{
-				Name:        "Unmanaged Symlinks",
-				Description: "Symlinks pointing to dotfiles but not in config",
-				Status:      StatusOK,
-				Message:     "No unmanaged symlinks found",
-			})
-		}
+		result.SymlinkScan = coverage
+		result.Checks = append(result.Checks, summarizeUnmanagedCheck(unmanaged, coverage))
 	}
 
-	// Step 9: Check for adoption opportunities
+	// Step 14: Check for adoption opportunities
 	progress(opts, "Checking for adoption opportunities...")
 	if opts.DotfilesPath != "" {
 		opportunities := checkAdoptionOpportunities(cfg, opts.DotfilesPath)
@@ -191,6 +231,7 @@ func RunChecks(cfg *config.Config, opts CheckOptions) (*CheckResult, error) {
 			}
 			if fullyLinked > 0 {
 				result.Checks = append(result.Checks, Check{
+					ID:          "adoption",
 					Name:        "Adoption Opportunities",
 					Description: "Configs with existing symlinks not in state",
 					Status:      StatusWarning,
@@ -201,22 +242,93 @@ func RunChecks(cfg *config.Config, opts CheckOptions) (*CheckResult, error) {
 		}
 	}
 
-	// Step 10: Check SSH keys
+	// Step 15: Check for caches and generated files in the repo
+	progress(opts, "Checking for caches and artifacts...")
+	if opts.DotfilesPath != "" {
+		artifacts := FindArtifacts(cfg, opts.DotfilesPath)
+		result.Artifacts = artifacts
+		result.Checks = append(result.Checks, summarizeArtifactCheck(artifacts))
+	}
+
+	// Step 16: Check for emails, keys and tokens that belong in machine configs
+	progress(opts, "Checking for hard-coded personal data...")
+	if opts.DotfilesPath != "" {
+		personal := FindPersonalData(cfg, opts.DotfilesPath)
+		result.PersonalData = personal
+		result.Checks = append(result.Checks, summarizePersonalDataCheck(personal))
+	}
+
+	// Step 17: Check SSH keys
 	progress(opts, "Checking SSH keys...")
 	sshKeyCheck := checkSSHKeys()
 	result.Checks = append(result.Checks, sshKeyCheck)
 
-	// Step 11: Check GitHub SSH
+	// Step 18: Check GitHub SSH
 	progress(opts, "Checking GitHub SSH access...")
 	githubSSHCheck := checkGitHubSSH()
 	result.Checks = append(result.Checks, githubSSHCheck)
 
+	result.applySettings(cfg.Doctor)
+
 	return result, nil
 }
 
+// summarizeLinkCheck creates a check summary from the links section
+func summarizeLinkCheck(statuses []stow.LinkStatus) Check {
+	check := Check{
+		ID:          "links",
+		Name:        "Links",
+		Description: "Symlinks and copies from the links section",
+	}
+
+	var notLinked, blocked []string
+	for _, s := range statuses {
+		switch s.Status {
+		case stow.LinkOK:
+		case stow.LinkMissing, stow.LinkWrong:
+			notLinked = append(notLinked, s.Link.Target)
+		default:
+			blocked = append(blocked, s.Link.Target)
+		}
+	}
+
+	switch {
+	case len(blocked) > 0:
+		check.Status = StatusError
+		check.Message = fmt.Sprintf("%d of %d cannot be linked: %s", len(blocked), len(statuses), strings.Join(blocked, ", "))
+		check.Fix = "Move what is in the way or fix the paths in .go4dot.yaml, then run 'g4d sync'"
+	case len(notLinked) > 0:
+		check.Status = StatusWarning
+		check.Message = fmt.Sprintf("%d of %d not linked: %s", len(notLinked), len(statuses), strings.Join(notLinked, ", "))
+		check.Fix = "Run 'g4d sync' to create them"
+	default:
+		check.Status = StatusOK
+		check.Message = fmt.Sprintf("%d links in place", len(statuses))
+	}
+	return check
+}
+
+// applySettings ignores or raises check results as the doctor section of
+// the config asks, noting the change in the message
+func (r *CheckResult) applySettings(settings config.DoctorSettings) {
+	for i := range r.Checks {
+		check := &r.Checks[i]
+		switch {
+		case settings.Ignores(check.ID) && check.Status != StatusOK && check.Status != StatusSkipped:
+			check.Status = StatusSkipped
+			check.Message += " (ignored by config)"
+		case settings.RaisesWarning(check.ID) && check.Status == StatusWarning:
+			check.Status = StatusError
+			check.Message += " (warning counts as error by config)"
+		}
+	}
+	r.ignoredConflicts = settings.Ignores("symlinks")
+}
+
 // checkSSHKeys verifies SSH keys are available
 func checkSSHKeys() Check {
 	check := Check{
+		ID:          "ssh-keys",
 		Name:        "SSH Keys",
 		Description: "Available SSH keys",
 	}
@@ -266,6 +378,7 @@ func checkSSHKeys() Check {
 // checkGitHubSSH tests SSH connectivity to GitHub
 func checkGitHubSSH() Check {
 	check := Check{
+		ID:          "github-ssh",
 		Name:        "GitHub SSH",
 		Description: "SSH authentication to GitHub",
 	}
@@ -291,21 +404,30 @@ func checkGitHubSSH() Check {
 	return check
 }
 
-// checkStow verifies GNU stow is installed
-func checkStow() Check {
+// checkStow verifies GNU stow is installed as binary, suggesting how to
+// install it with manager
+func checkStow(binary, manager string) Check {
 	check := Check{
+		ID:          "stow",
 		Name:        "GNU Stow",
 		Description: "Symlink farm manager",
 	}
 
-	if !stow.IsStowInstalled() {
+	if !stow.IsStowBinaryInstalled(binary) {
 		check.Status = StatusError
 		check.Message = "GNU stow is not installed"
-		check.Fix = "Install with your package manager (e.g., dnf install stow, apt install stow, brew install stow)"
+		check.Fix = "Install with your package manager (e.g., dnf install stow, apt install stow, brew install stow, pkg install stow)"
+		if install := platform.InstallCommand(manager, "stow"); install != "" {
+			check.Fix = fmt.Sprintf("Install with `%s`", install)
+		}
+		if binary != config.DefaultStowBinary {
+			check.Message = fmt.Sprintf("GNU stow is not installed at %s", binary)
+			check.Fix = "Fix stow.binary in .go4dot.yaml or remove it to use stow from PATH"
+		}
 		return check
 	}
 
-	if err := stow.ValidateStow(); err != nil {
+	if err := stow.ValidateStowBinary(binary); err != nil {
 		check.Status = StatusWarning
 		check.Message = fmt.Sprintf("stow validation failed: %v", err)
 		return check
@@ -316,9 +438,63 @@ func checkStow() Check {
 	return check
 }
 
+// dotfilesMinStow is the first GNU Stow whose --dotfiles also renames
+// directories; older versions only rename files
+var dotfilesMinStow = version.SemVer{Major: 2, Minor: 4}
+
+// checkDotPrefix warns on the stow check when configs name files the
+// --dotfiles way (dot-bashrc for .bashrc) but stow would not link them so:
+// the flag is missing, or the installed stow is too old for dot- directories.
+func checkDotPrefix(check *Check, cfg *config.Config, dotfilesPath string) {
+	if !cfg.Stow.HasFlag("--dotfiles") {
+		if config.UsesDotPrefix(dotfilesPath, cfg.GetAllConfigs()) {
+			check.Status = StatusWarning
+			check.Message = "Configs have dot- files, but stow runs without --dotfiles and links them as dot-*"
+			check.Fix = "Add --dotfiles to stow.flags in .go4dot.yaml"
+		}
+		return
+	}
+
+	raw, err := stow.StowVersion(cfg.Stow.BinaryPath())
+	if err != nil {
+		return
+	}
+	v, err := version.ParseSemVer(raw)
+	if err != nil || !v.IsOlderThan(dotfilesMinStow) || !hasDotPrefixDir(cfg, dotfilesPath) {
+		return
+	}
+	check.Status = StatusWarning
+	check.Message = fmt.Sprintf("GNU stow %s applies --dotfiles to files only; dot- directories are linked as dot-*", raw)
+	check.Fix = fmt.Sprintf("Upgrade GNU stow to %s or newer", dotfilesMinStow)
+}
+
+// hasDotPrefixDir reports whether a config holds a directory named the
+// --dotfiles way, e.g. dot-config
+func hasDotPrefixDir(cfg *config.Config, dotfilesPath string) bool {
+	found := false
+	for _, item := range cfg.GetAllConfigs() {
+		root := item.Dir(dotfilesPath)
+		_ = filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return nil
+			}
+			if path != root && d.IsDir() && config.HasDotPrefix(d.Name()) {
+				found = true
+				return filepath.SkipAll
+			}
+			return nil
+		})
+		if found {
+			return true
+		}
+	}
+	return false
+}
+
 // checkGit verifies git is installed
 func checkGit() Check {
 	check := Check{
+		ID:          "git",
 		Name:        "Git",
 		Description: "Version control system",
 	}
@@ -339,6 +515,7 @@ func checkGit() Check {
 // summarizeDepsCheck creates a check summary from deps check result
 func summarizeDepsCheck(result *deps.CheckResult) Check {
 	check := Check{
+		ID:          "dependencies",
 		Name:        "Dependencies",
 		Description: "Required packages",
 	}
@@ -349,7 +526,7 @@ func summarizeDepsCheck(result *deps.CheckResult) Check {
 
 	if len(missingCritical) > 0 {
 		check.Status = StatusError
-		check.Message = fmt.Sprintf("%d critical dependencies missing", len(missingCritical))
+		check.Message = describeMissing(missingCritical, "critical")
 		check.Fix = "Run 'g4d deps install' to install missing dependencies"
 		return check
 	}
@@ -357,7 +534,7 @@ func summarizeDepsCheck(result *deps.CheckResult) Check {
 	if len(missing) > 0 && len(manualMissing) > 0 {
 		check.Status = StatusWarning
 		var parts []string
-		parts = append(parts, fmt.Sprintf("%d optional dependencies missing", len(missing)))
+		parts = append(parts, describeMissing(missing, "optional"))
 		parts = append(parts, fmt.Sprintf("%d manual (install separately)", len(manualMissing)))
 		check.Message = strings.Join(parts, ", ")
 		check.Fix = "Run 'g4d deps install' for auto-installable deps; install manual deps yourself"
@@ -366,7 +543,7 @@ func summarizeDepsCheck(result *deps.CheckResult) Check {
 
 	if len(missing) > 0 {
 		check.Status = StatusWarning
-		check.Message = fmt.Sprintf("%d optional dependencies missing", len(missing))
+		check.Message = describeMissing(missing, "optional")
 		check.Fix = "Run 'g4d deps install' to install missing dependencies"
 		return check
 	}
@@ -382,113 +559,182 @@ func summarizeDepsCheck(result *deps.CheckResult) Check {
 	return check
 }
 
+// describeMissing counts dependencies that are not installed apart from
+// those installed at too old a version, e.g. "1 optional dependencies
+// missing, 2 outdated"
+func describeMissing(checks []deps.DependencyCheck, kind string) string {
+	outdated := 0
+	for _, c := range checks {
+		if c.Status == deps.StatusVersionMismatch {
+			outdated++
+		}
+	}
+	switch {
+	case outdated == 0:
+		return fmt.Sprintf("%d %s dependencies missing", len(checks), kind)
+	case outdated == len(checks):
+		return fmt.Sprintf("%d %s dependencies outdated", outdated, kind)
+	default:
+		return fmt.Sprintf("%d %s dependencies missing, %d outdated", len(checks)-outdated, kind, outdated)
+	}
+}
+
 // checkSymlinks verifies all stowed symlinks are valid
 func checkSymlinks(cfg *config.Config, dotfilesPath string) []SymlinkCheck {
 	var checks []SymlinkCheck
-	home := os.Getenv("HOME")
-
-	allConfigs := cfg.GetAllConfigs()
-	for _, configItem := range allConfigs {
-		configPath := filepath.Join(dotfilesPath, configItem.Path)
-
-		// Check if config directory exists in dotfiles
-		if _, err := os.Stat(configPath); os.IsNotExist(err) {
-			checks = append(checks, SymlinkCheck{
-				Config:  configItem.Name,
-				Status:  StatusSkipped,
-				Message: "Config directory not found in dotfiles",
-			})
-			continue
+
+	for _, configItem := range cfg.GetAllConfigs() {
+		checks = append(checks, checkConfigSymlinks(configItem, dotfilesPath, cfg.Stow)...)
+	}
+
+	return checks
+}
+
+// checkConfigSymlinks verifies the symlinks of one config, linked into the
+// target of the config's stow settings. Files stow ignores, such as a README,
+// are never linked and are not checked.
+func checkConfigSymlinks(configItem config.ConfigItem, dotfilesPath string, settings config.StowSettings) []SymlinkCheck {
+	var checks []SymlinkCheck
+	home := settings.TargetDir()
+	configPath := configItem.Dir(dotfilesPath)
+
+	// Check if config directory exists in dotfiles
+	if _, err := os.Stat(configPath); os.IsNotExist(err) {
+		return []SymlinkCheck{{
+			Config:  configItem.Name,
+			Status:  StatusSkipped,
+			Message: "Config directory not found in dotfiles",
+		}}
+	}
+
+	ignore, _ := stow.LoadIgnoreList(configPath)
+
+	// Walk the config directory and check each file's symlink
+	err := filepath.Walk(configPath, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return nil // Skip on error
 		}
 
-		// Walk the config directory and check each file's symlink
-		err := filepath.Walk(configPath, func(path string, info os.FileInfo, err error) error {
-			if err != nil {
-				return nil // Skip on error
-			}
+		// Calculate expected target path
+		relPath, _ := filepath.Rel(configPath, path)
+		if relPath != "." && ignore != nil && ignore.Match(relPath) {
 			if info.IsDir() {
-				return nil // Skip directories
+				return filepath.SkipDir
 			}
+			return nil
+		}
+		if info.IsDir() {
+			return nil // Skip directories
+		}
+		targetPath := filepath.Join(home, settings.LinkPath(relPath))
 
-			// Calculate expected target path
-			relPath, _ := filepath.Rel(configPath, path)
-			targetPath := filepath.Join(home, relPath)
+		check := SymlinkCheck{
+			Config:     configItem.Name,
+			TargetPath: targetPath,
+		}
 
-			check := SymlinkCheck{
-				Config:     configItem.Name,
-				TargetPath: targetPath,
+		// Check if target exists
+		targetInfo, err := os.Lstat(targetPath)
+		if os.IsNotExist(err) {
+			check.Status = StatusWarning
+			check.Message = "Symlink missing"
+			checks = append(checks, check)
+			return nil
+		}
+		if err != nil {
+			check.Status = StatusError
+			check.Message = fmt.Sprintf("Cannot check: %v", err)
+			if linkErr := stow.CheckLinkChain(targetPath); linkErr != nil {
+				check.Message = linkErr.Error()
 			}
+			checks = append(checks, check)
+			return nil
+		}
 
-			// Check if target exists
-			targetInfo, err := os.Lstat(targetPath)
-			if os.IsNotExist(err) {
-				check.Status = StatusWarning
-				check.Message = "Symlink missing"
+		// Check if it's a symlink
+		if targetInfo.Mode()&os.ModeSymlink == 0 {
+			// If not a symlink, check if it's the same file (handles directory folding)
+			sourceInfo, err := os.Stat(path)
+			if err == nil && os.SameFile(sourceInfo, targetInfo) {
+				// It's the same file (synced via parent directory symlink) - OK
+				check.Status = StatusOK
+				check.Message = "Valid (via directory fold)"
 				checks = append(checks, check)
 				return nil
 			}
 
-			// Check if it's a symlink
-			if targetInfo.Mode()&os.ModeSymlink == 0 {
-				// If not a symlink, check if it's the same file (handles directory folding)
-				sourceInfo, err := os.Stat(path)
-				if err == nil && os.SameFile(sourceInfo, targetInfo) {
-					// It's the same file (synced via parent directory symlink) - OK
-					check.Status = StatusOK
-					check.Message = "Valid (via directory fold)"
-					checks = append(checks, check)
-					return nil
-				}
-
-				check.Status = StatusWarning
-				check.Message = "Not a symlink (conflict)"
-				checks = append(checks, check)
-				return nil
-			}
+			check.Status = StatusWarning
+			check.Message = "Not a symlink (conflict)"
+			check.Conflict = true
+			checks = append(checks, check)
+			return nil
+		}
 
-			// Check if symlink points to correct location
-			linkDest, err := os.Readlink(targetPath)
-			if err != nil {
-				check.Status = StatusError
-				check.Message = fmt.Sprintf("Cannot read symlink: %v", err)
-				checks = append(checks, check)
-				return nil
-			}
+		// Check if symlink points to correct location
+		linkDest, err := os.Readlink(targetPath)
+		if err != nil {
+			check.Status = StatusError
+			check.Message = fmt.Sprintf("Cannot read symlink: %v", err)
+			checks = append(checks, check)
+			return nil
+		}
 
-			// Resolve to absolute path
-			if !filepath.IsAbs(linkDest) {
-				linkDest = filepath.Join(filepath.Dir(targetPath), linkDest)
-			}
-			linkDest = filepath.Clean(linkDest)
+		// Resolve to absolute path
+		if !filepath.IsAbs(linkDest) {
+			linkDest = filepath.Join(filepath.Dir(targetPath), linkDest)
+		}
+		linkDest = filepath.Clean(linkDest)
 
-			if linkDest != path {
-				check.Status = StatusWarning
-				check.Message = fmt.Sprintf("Points to wrong location: %s", linkDest)
+		if !samePath(linkDest, path) {
+			if linkErr := stow.CheckLinkChain(targetPath); linkErr != nil {
+				check.Status = StatusError
+				check.Message = linkErr.Error()
 				checks = append(checks, check)
 				return nil
 			}
-
-			check.Status = StatusOK
-			check.Message = "Valid symlink"
+			check.Status = StatusWarning
+			check.Message = fmt.Sprintf("Points to wrong location: %s", linkDest)
 			checks = append(checks, check)
 			return nil
-		})
-
-		if err != nil {
-			checks = append(checks, SymlinkCheck{
-				Config:  configItem.Name,
-				Status:  StatusError,
-				Message: fmt.Sprintf("Error checking: %v", err),
-			})
 		}
+
+		check.Status = StatusOK
+		check.Message = "Valid symlink"
+		checks = append(checks, check)
+		return nil
+	})
+
+	if err != nil {
+		checks = append(checks, SymlinkCheck{
+			Config:  configItem.Name,
+			Status:  StatusError,
+			Message: fmt.Sprintf("Error checking: %v", err),
+		})
 	}
 
 	return checks
 }
 
+// samePath reports whether a and b name the same file, also when their
+// directories are spelled through different symlinks, as on FreeBSD where
+// /home links to /usr/home and a repo found through one links through the
+// other
+func samePath(a, b string) bool {
+	if a == b {
+		return true
+	}
+	if filepath.Base(a) != filepath.Base(b) {
+		return false
+	}
+	dirA, errA := filepath.EvalSymlinks(filepath.Dir(a))
+	dirB, errB := filepath.EvalSymlinks(filepath.Dir(b))
+	return errA == nil && errB == nil && dirA == dirB
+}
+
 // summarizeSymlinkCheck creates a check summary from symlink results
 func summarizeSymlinkCheck(checks []SymlinkCheck) Check {
 	check := Check{
+		ID:          "symlinks",
 		Name:        "Symlinks",
 		Description: "Stowed config symlinks",
 	}
@@ -527,6 +773,7 @@ func summarizeSymlinkCheck(checks []SymlinkCheck) Check {
 // summarizeExternalCheck creates a check summary from external status
 func summarizeExternalCheck(statuses []deps.ExternalStatus) Check {
 	check := Check{
+		ID:          "external",
 		Name:        "External Dependencies",
 		Description: "Cloned repos (themes, plugins)",
 	}
@@ -555,9 +802,54 @@ func summarizeExternalCheck(statuses []deps.ExternalStatus) Check {
 	return check
 }
 
+// summarizeToolchainCheck creates a check summary from toolchain status
+func summarizeToolchainCheck(statuses []deps.ToolchainStatus) Check {
+	check := Check{
+		ID:          "toolchains",
+		Name:        "Toolchains",
+		Description: "Language version managers and versions",
+	}
+
+	var ok, skipped int
+	var problems []string
+	for _, s := range statuses {
+		tc := s.Toolchain
+		switch s.Status {
+		case "ok":
+			ok++
+		case "skipped":
+			skipped++
+		case "inactive":
+			active := s.Active
+			if active == "" {
+				active = "none"
+			}
+			problems = append(problems, fmt.Sprintf("%s default is %s, want %s", tc.Language, active, tc.DefaultVersion()))
+		case "missing":
+			if !s.Manager {
+				problems = append(problems, fmt.Sprintf("%s not installed", deps.ToolchainManager(tc.Language)))
+			} else {
+				problems = append(problems, fmt.Sprintf("%s %s not installed", tc.Language, strings.Join(s.Missing, ", ")))
+			}
+		}
+	}
+
+	if len(problems) > 0 {
+		check.Status = StatusWarning
+		check.Message = strings.Join(problems, "; ")
+		check.Fix = "Run 'g4d install' to install them and set the defaults"
+		return check
+	}
+
+	check.Status = StatusOK
+	check.Message = fmt.Sprintf("%d ready, %d skipped", ok, skipped)
+	return check
+}
+
 // summarizeMachineCheck creates a check summary from machine config status
 func summarizeMachineCheck(statuses []machine.MachineConfigStatus) Check {
 	check := Check{
+		ID:          "machine-config",
 		Name:        "Machine Configuration",
 		Description: "Machine-specific config files",
 	}
@@ -617,6 +909,20 @@ func (r *CheckResult) HasWarnings() bool {
 	return false
 }
 
+// ConflictCount returns the number of symlinks blocked by existing files
+func (r *CheckResult) ConflictCount() int {
+	if r.ignoredConflicts {
+		return 0
+	}
+	count := 0
+	for _, s := range r.SymlinkStatus {
+		if s.Conflict {
+			count++
+		}
+	}
+	return count
+}
+
 // CountByStatus returns the count of checks by status
 func (r *CheckResult) CountByStatus() (ok, warnings, errors, skipped int) {
 	for _, check := range r.Checks {
@@ -641,76 +947,6 @@ func progress(opts CheckOptions, msg string) {
 	}
 }
 
-// checkUnmanagedSymlinks finds symlinks in home pointing to dotfiles but not in config
-func checkUnmanagedSymlinks(cfg *config.Config, dotfilesPath string) []UnmanagedSymlink {
-	var unmanaged []UnmanagedSymlink
-	home, err := os.UserHomeDir()
-	if err != nil {
-		return nil
-	}
-
-	absDotfiles, err := filepath.Abs(dotfilesPath)
-	if err != nil {
-		absDotfiles = dotfilesPath
-	}
-
-	// Map of managed target paths for quick lookup
-	managedTargets := make(map[string]bool)
-	allConfigs := cfg.GetAllConfigs()
-	for _, configItem := range allConfigs {
-		configPath := filepath.Join(absDotfiles, configItem.Path)
-		_ = filepath.Walk(configPath, func(path string, info os.FileInfo, err error) error {
-			if err == nil && !info.IsDir() {
-				relPath, _ := filepath.Rel(configPath, path)
-				targetPath := filepath.Join(home, relPath)
-				managedTargets[filepath.Clean(targetPath)] = true
-			}
-			return nil
-		})
-	}
-
-	// Scan home and ~/.config
-	scanDirs := []string{home, filepath.Join(home, ".config")}
-	for _, dir := range scanDirs {
-		entries, err := os.ReadDir(dir)
-		if err != nil {
-			continue
-		}
-
-		for _, entry := range entries {
-			path := filepath.Join(dir, entry.Name())
-			info, err := os.Lstat(path)
-			if err != nil || info.Mode()&os.ModeSymlink == 0 {
-				continue
-			}
-
-			// It's a symlink, check where it points
-			linkDest, err := os.Readlink(path)
-			if err != nil {
-				continue
-			}
-
-			// Resolve to absolute path
-			if !filepath.IsAbs(linkDest) {
-				linkDest = filepath.Join(filepath.Dir(path), linkDest)
-			}
-			linkDest = filepath.Clean(linkDest)
-
-			// Check if it points into dotfiles
-			if strings.HasPrefix(linkDest, absDotfiles) {
-				if !managedTargets[filepath.Clean(path)] {
-					unmanaged = append(unmanaged, UnmanagedSymlink{
-						TargetPath: path,
-						SourcePath: linkDest,
-					})
-				}
-			}
-		}
-	}
-
-	return unmanaged
-}
-
 // checkAdoptionOpportunities finds configs with existing symlinks that aren't in state
 func checkAdoptionOpportunities(cfg *config.Config, dotfilesPath string) []AdoptionOpportunity {
 	var opportunities []AdoptionOpportunity
@@ -140,8 +140,74 @@ func TestCheckResultCountByStatus(t *testing.T) {
 	}
 }
 
+func TestCheckResultConflictCount(t *testing.T) {
+	result := &CheckResult{
+		SymlinkStatus: []SymlinkCheck{
+			{Status: StatusOK},
+			{Status: StatusWarning, Message: "Symlink missing"},
+			{Status: StatusWarning, Message: "Not a symlink (conflict)", Conflict: true},
+		},
+	}
+
+	if got := result.ConflictCount(); got != 1 {
+		t.Errorf("ConflictCount() = %d, want 1", got)
+	}
+}
+
+func TestCheckResultApplySettings(t *testing.T) {
+	newResult := func() *CheckResult {
+		return &CheckResult{
+			Checks: []Check{
+				{ID: "unmanaged-symlinks", Status: StatusWarning, Message: "2 unmanaged symlinks found"},
+				{ID: "symlinks", Status: StatusWarning, Message: "1 missing"},
+				{ID: "ssh-keys", Status: StatusOK},
+				{ID: "github-ssh", Status: StatusError},
+			},
+			SymlinkStatus: []SymlinkCheck{{Status: StatusWarning, Conflict: true}},
+		}
+	}
+
+	tests := []struct {
+		name                          string
+		settings                      config.DoctorSettings
+		ok, warnings, errors, skipped int
+		conflicts                     int
+	}{
+		{"no settings", config.DoctorSettings{}, 1, 2, 1, 0, 1},
+		{"ignore warning and error", config.DoctorSettings{Ignore: []string{"unmanaged-symlinks", "github-ssh"}}, 1, 1, 0, 2, 1},
+		{"ignore passing check", config.DoctorSettings{Ignore: []string{"ssh-keys"}}, 1, 2, 1, 0, 1},
+		{"warn as error", config.DoctorSettings{WarnAsError: []string{"symlinks"}}, 1, 1, 2, 0, 1},
+		{"ignored symlinks drop conflicts", config.DoctorSettings{Ignore: []string{"symlinks"}}, 1, 1, 1, 1, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := newResult()
+			result.applySettings(tt.settings)
+
+			ok, warnings, errors, skipped := result.CountByStatus()
+			if ok != tt.ok || warnings != tt.warnings || errors != tt.errors || skipped != tt.skipped {
+				t.Errorf("CountByStatus() = %d, %d, %d, %d, want %d, %d, %d, %d",
+					ok, warnings, errors, skipped, tt.ok, tt.warnings, tt.errors, tt.skipped)
+			}
+			if got := result.ConflictCount(); got != tt.conflicts {
+				t.Errorf("ConflictCount() = %d, want %d", got, tt.conflicts)
+			}
+		})
+	}
+
+	result := newResult()
+	result.applySettings(config.DoctorSettings{Ignore: []string{"unmanaged-symlinks"}, WarnAsError: []string{"symlinks"}})
+	if !strings.HasSuffix(result.Checks[0].Message, "(ignored by config)") {
+		t.Errorf("ignored message = %q", result.Checks[0].Message)
+	}
+	if result.IsHealthy() {
+		t.Error("IsHealthy() = true with a warning raised to an error")
+	}
+}
+
 func TestCheckStow(t *testing.T) {
-	check := checkStow()
+	check := checkStow(config.DefaultStowBinary, "dnf")
 
 	// The check should complete without error
 	if check.Name != "GNU Stow" {
@@ -261,6 +327,50 @@ func TestSummarizeExternalCheck(t *testing.T) {
 	}
 }
 
+func TestSummarizeToolchainCheck(t *testing.T) {
+	rust := config.Toolchain{Language: "rust", Versions: []string{"stable", "1.78.0"}}
+	tests := []struct {
+		name            string
+		statuses        []deps.ToolchainStatus
+		expectedStatus  CheckStatus
+		expectedMessage string
+	}{
+		{
+			name:            "Ready",
+			statuses:        []deps.ToolchainStatus{{Toolchain: rust, Status: "ok", Manager: true}, {Status: "skipped"}},
+			expectedStatus:  StatusOK,
+			expectedMessage: "1 ready, 1 skipped",
+		},
+		{
+			name:            "Manager missing",
+			statuses:        []deps.ToolchainStatus{{Toolchain: rust, Status: "missing"}},
+			expectedStatus:  StatusWarning,
+			expectedMessage: "rustup not installed",
+		},
+		{
+			name:            "Version missing",
+			statuses:        []deps.ToolchainStatus{{Toolchain: rust, Status: "missing", Manager: true, Missing: []string{"1.78.0"}}},
+			expectedStatus:  StatusWarning,
+			expectedMessage: "rust 1.78.0 not installed",
+		},
+		{
+			name:            "Other default",
+			statuses:        []deps.ToolchainStatus{{Toolchain: rust, Status: "inactive", Manager: true, Active: "1.78.0-x86_64-unknown-linux-gnu"}},
+			expectedStatus:  StatusWarning,
+			expectedMessage: "rust default is 1.78.0-x86_64-unknown-linux-gnu, want stable",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			check := summarizeToolchainCheck(tt.statuses)
+			if check.Status != tt.expectedStatus || check.Message != tt.expectedMessage {
+				t.Errorf("got %v %q, want %v %q", check.Status, check.Message, tt.expectedStatus, tt.expectedMessage)
+			}
+		})
+	}
+}
+
 func TestSummarizeMachineCheck(t *testing.T) {
 	tests := []struct {
 		name           string
@@ -436,9 +546,17 @@ func TestRunChecks(t *testing.T) {
 		t.Error("Expected progress messages")
 	}
 
+	known := make(map[string]bool)
+	for _, id := range config.DoctorChecks {
+		known[id] = true
+	}
+
 	// Log the checks
 	for _, check := range result.Checks {
 		t.Logf("Check: %s - %v - %s", check.Name, check.Status, check.Message)
+		if !known[check.ID] {
+			t.Errorf("check %q has ID %q, which is not in config.DoctorChecks", check.Name, check.ID)
+		}
 	}
 }
 
@@ -527,3 +645,120 @@ func TestSummarizeDepsCheckWithManual(t *testing.T) {
 		})
 	}
 }
+
+func TestCheckUnmanagedSymlinks_RepoIgnore(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	dotfiles := t.TempDir()
+
+	for _, f := range []string{"wip/.wiprc", "old/.oldrc"} {
+		path := filepath.Join(dotfiles, f)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte("x"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Symlink(path, filepath.Join(home, filepath.Base(f))); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(dotfiles, config.IgnoreFileName), []byte("wip/\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	unmanaged, _ := checkUnmanagedSymlinks(&config.Config{}, dotfiles)
+	if len(unmanaged) != 1 || filepath.Base(unmanaged[0].TargetPath) != ".oldrc" {
+		t.Errorf("checkUnmanagedSymlinks() = %+v, want only .oldrc", unmanaged)
+	}
+}
+
+// On FreeBSD /home links to /usr/home, so a link may reach the repo through
+// the real path while the config names it through HOME, or the other way
+func TestCheckSymlinks_SymlinkedHome(t *testing.T) {
+	root := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(root, "usr", "home", "me"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(filepath.Join(root, "usr", "home"), filepath.Join(root, "home")); err != nil {
+		t.Fatal(err)
+	}
+	home := filepath.Join(root, "home", "me")
+	realHome := filepath.Join(root, "usr", "home", "me")
+	t.Setenv("HOME", home)
+
+	dotfiles := filepath.Join(home, "dotfiles")
+	for _, f := range []string{"zsh/.zshrc", "extra/.extrarc"} {
+		path := filepath.Join(dotfiles, f)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte("x"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Symlink(filepath.Join(realHome, "dotfiles", f), filepath.Join(home, filepath.Base(f))); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	cfg := &config.Config{
+		Configs: config.ConfigGroups{
+			Core: []config.ConfigItem{{Name: "zsh", Path: "zsh"}},
+		},
+	}
+	checks := checkSymlinks(cfg, dotfiles)
+	if len(checks) != 1 || checks[0].Status != StatusOK {
+		t.Errorf("checkSymlinks() = %+v, want .zshrc valid", checks)
+	}
+
+	unmanaged, _ := checkUnmanagedSymlinks(cfg, dotfiles)
+	if len(unmanaged) != 1 || filepath.Base(unmanaged[0].TargetPath) != ".extrarc" {
+		t.Errorf("checkUnmanagedSymlinks() = %+v, want only .extrarc", unmanaged)
+	}
+}
+
+func TestCheckDotPrefix_MissingFlag(t *testing.T) {
+	dotfiles := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dotfiles, "bash"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dotfiles, "bash", "dot-bashrc"), nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	cfg := &config.Config{Configs: config.ConfigGroups{Core: []config.ConfigItem{{Name: "bash", Path: "bash"}}}}
+
+	check := Check{ID: "stow", Status: StatusOK}
+	checkDotPrefix(&check, cfg, dotfiles)
+	if check.Status != StatusWarning || !strings.Contains(check.Fix, "--dotfiles") {
+		t.Errorf("check = %+v, want a warning suggesting --dotfiles", check)
+	}
+
+	cfg.Stow.Flags = []string{"--dotfiles"}
+	check = Check{ID: "stow", Status: StatusOK}
+	checkDotPrefix(&check, cfg, dotfiles)
+	if check.Status != StatusOK {
+		t.Errorf("check = %+v, want OK with --dotfiles and no dot- directories", check)
+	}
+}
+
+func TestCheckConfigSymlinks_Loop(t *testing.T) {
+	tmpDir := t.TempDir()
+	dotfiles, home := filepath.Join(tmpDir, "dotfiles"), filepath.Join(tmpDir, "home")
+	if err := os.MkdirAll(filepath.Join(dotfiles, "nvim", ".config", "nvim"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dotfiles, "nvim", ".config", "nvim", "init.lua"), nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(home, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(".config", filepath.Join(home, ".config")); err != nil {
+		t.Fatal(err)
+	}
+
+	checks := checkConfigSymlinks(config.ConfigItem{Name: "nvim", Path: "nvim"}, dotfiles, config.StowSettings{Target: home})
+	if len(checks) != 1 || checks[0].Status != StatusError || !strings.HasPrefix(checks[0].Message, "symlink loop") {
+		t.Errorf("checks = %+v, want a symlink loop error", checks)
+	}
+}
@@ -0,0 +1,144 @@
+package doctor
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/nvandessel/go4dot/internal/config"
+	"github.com/nvandessel/go4dot/internal/transcript"
+)
+
+// VerifyTimeout is how long a config's verify command may run before it
+// counts as failed
+const VerifyTimeout = 30 * time.Second
+
+// verifyOutputLines is how many of the last output lines of a failed verify
+// command are kept
+const verifyOutputLines = 20
+
+// FunctionalCheck is the result of running a config's verify command,
+// which tells whether the linked config actually works
+type FunctionalCheck struct {
+	Config   string      `json:"config"`
+	Command  string      `json:"command"`
+	Status   CheckStatus `json:"status"`
+	ExitCode int         `json:"exit_code"`
+	Output   string      `json:"output,omitempty"` // Last lines of output, kept when the command fails
+	Message  string      `json:"message"`
+}
+
+// RunFunctionalChecks runs the verify command of each named config that
+// has one, or of every config with one when configNames is empty
+func RunFunctionalChecks(cfg *config.Config, dotfilesPath string, configNames []string) []FunctionalCheck {
+	var items []config.ConfigItem
+	if len(configNames) == 0 {
+		items = cfg.GetAllConfigs()
+	} else {
+		for _, name := range configNames {
+			if item := cfg.GetConfigByName(name); item != nil {
+				items = append(items, *item)
+			}
+		}
+	}
+
+	var checks []FunctionalCheck
+	for _, item := range items {
+		if item.Verify == "" {
+			continue
+		}
+		checks = append(checks, RunFunctionalCheck(item, dotfilesPath, cfg.Stow.TargetDir()))
+	}
+	return checks
+}
+
+// RunFunctionalCheck runs item's verify command with sh in the config's
+// directory, with the config's variables in its environment. The command
+// passes when it exits 0 within VerifyTimeout.
+func RunFunctionalCheck(item config.ConfigItem, dotfilesPath, targetDir string) FunctionalCheck {
+	check := FunctionalCheck{Config: item.Name, Command: item.Verify}
+
+	ctx, cancel := context.WithTimeout(context.Background(), VerifyTimeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, "sh", "-c", item.Verify)
+	cmd.Dir = item.Dir(dotfilesPath)
+	cmd.Env = append(os.Environ(), item.Env(dotfilesPath, targetDir)...)
+	var out bytes.Buffer
+	cmd.Stdout = &out
+	cmd.Stderr = &out
+	err := transcript.Run(cmd)
+
+	var exitErr *exec.ExitError
+	switch {
+	case ctx.Err() == context.DeadlineExceeded:
+		check.Status = StatusError
+		check.ExitCode = -1
+		check.Message = fmt.Sprintf("timed out after %s", VerifyTimeout)
+	case errors.As(err, &exitErr):
+		check.Status = StatusError
+		check.ExitCode = exitErr.ExitCode()
+		check.Message = fmt.Sprintf("exited with %d", check.ExitCode)
+	case err != nil:
+		check.Status = StatusError
+		check.ExitCode = -1
+		check.Message = err.Error()
+	default:
+		check.Status = StatusOK
+		check.Message = "works"
+		return check
+	}
+
+	check.Output = tailLines(out.String(), verifyOutputLines)
+	return check
+}
+
+// FailedFunctionalChecks returns the checks whose verify command failed
+func FailedFunctionalChecks(checks []FunctionalCheck) []FunctionalCheck {
+	var failed []FunctionalCheck
+	for _, c := range checks {
+		if c.Status != StatusOK {
+			failed = append(failed, c)
+		}
+	}
+	return failed
+}
+
+// summarizeFunctionalCheck creates a check summary from the verify commands
+func summarizeFunctionalCheck(checks []FunctionalCheck) Check {
+	check := Check{
+		ID:          "functional",
+		Name:        "Functional Checks",
+		Description: "Configs' verify commands",
+	}
+
+	var failed []string
+	for _, c := range FailedFunctionalChecks(checks) {
+		failed = append(failed, fmt.Sprintf("%s (%s)", c.Config, c.Message))
+	}
+
+	if len(failed) > 0 {
+		check.Status = StatusError
+		check.Message = fmt.Sprintf("%d of %d failed: %s", len(failed), len(checks), strings.Join(failed, ", "))
+		check.Fix = "Run 'g4d doctor -v' to see the output of the verify commands"
+		return check
+	}
+
+	check.Status = StatusOK
+	check.Message = fmt.Sprintf("%d configs work", len(checks))
+	return check
+}
+
+// tailLines returns the last n lines of output
+func tailLines(output string, n int) string {
+	lines := strings.Split(strings.TrimRight(output, "\n"), "\n")
+	if len(lines) > n {
+		lines = lines[len(lines)-n:]
+	}
+	return strings.Join(lines, "\n")
+}
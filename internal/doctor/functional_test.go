@@ -0,0 +1,70 @@
+package doctor
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/nvandessel/go4dot/internal/config"
+)
+
+func TestRunFunctionalChecks(t *testing.T) {
+	dotfiles := t.TempDir()
+	for _, dir := range []string{"nvim", "zsh", "git"} {
+		if err := os.MkdirAll(filepath.Join(dotfiles, dir), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	cfg := &config.Config{
+		Configs: config.ConfigGroups{
+			Core: []config.ConfigItem{
+				{Name: "nvim", Path: "nvim", Verify: `echo "checking $G4D_CONFIG_NAME"; echo "E5113: init.lua:3" >&2; exit 3`},
+				{Name: "zsh", Path: "zsh", Verify: `[ "$(basename "$PWD")" = zsh ]`},
+				{Name: "git", Path: "git"},
+			},
+		},
+	}
+
+	checks := RunFunctionalChecks(cfg, dotfiles, nil)
+	if len(checks) != 2 {
+		t.Fatalf("RunFunctionalChecks() = %+v, want checks for nvim and zsh", checks)
+	}
+
+	nvim := checks[0]
+	if nvim.Config != "nvim" || nvim.Status != StatusError || nvim.ExitCode != 3 {
+		t.Errorf("nvim check = %+v, want an error with exit code 3", nvim)
+	}
+	if nvim.Output != "checking nvim\nE5113: init.lua:3" {
+		t.Errorf("nvim output = %q", nvim.Output)
+	}
+	if zsh := checks[1]; zsh.Status != StatusOK || zsh.Output != "" {
+		t.Errorf("zsh check = %+v, want ok run in its directory", zsh)
+	}
+
+	check := summarizeFunctionalCheck(checks)
+	if check.Status != StatusError || !strings.Contains(check.Message, "nvim (exited with 3)") {
+		t.Errorf("summarizeFunctionalCheck() = %v %q", check.Status, check.Message)
+	}
+
+	// Only the named configs run
+	checks = RunFunctionalChecks(cfg, dotfiles, []string{"zsh", "git"})
+	if len(checks) != 1 || checks[0].Config != "zsh" {
+		t.Errorf("RunFunctionalChecks(zsh, git) = %+v, want only zsh", checks)
+	}
+	if check := summarizeFunctionalCheck(checks); check.Status != StatusOK {
+		t.Errorf("summarizeFunctionalCheck() = %v %q, want ok", check.Status, check.Message)
+	}
+}
+
+func TestTailLines(t *testing.T) {
+	var lines []string
+	for i := 1; i <= 25; i++ {
+		lines = append(lines, strings.Repeat("x", i))
+	}
+	got := tailLines(strings.Join(lines, "\n")+"\n", 20)
+	if want := strings.Join(lines[5:], "\n"); got != want {
+		t.Errorf("tailLines() kept %d lines, want the last 20", strings.Count(got, "\n")+1)
+	}
+}
@@ -0,0 +1,224 @@
+package doctor
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/nvandessel/go4dot/internal/state"
+)
+
+const (
+	// HistoryFileName holds past doctor runs, in the state directory
+	HistoryFileName = "doctor-history.json"
+
+	// MaxHistory is the number of runs kept; older ones are dropped
+	MaxHistory = 100
+)
+
+// Trend compares a doctor run with the one before it
+type Trend string
+
+const (
+	TrendNone      Trend = ""          // No earlier run to compare with
+	TrendImproved  Trend = "improved"  // Fewer errors, or as many with fewer warnings
+	TrendWorsened  Trend = "worsened"  // More errors, or as many with more warnings
+	TrendUnchanged Trend = "unchanged" // Same counts
+)
+
+// HistoryPath returns the path of the doctor history file
+func HistoryPath() (string, error) {
+	dir, err := state.GetStateDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, HistoryFileName), nil
+}
+
+// LoadHistory returns the recorded doctor runs, oldest first. No history
+// yet is not an error.
+func LoadHistory() ([]*Summary, error) {
+	path, err := HistoryPath()
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read doctor history: %w", err)
+	}
+
+	var runs []*Summary
+	if err := json.Unmarshal(data, &runs); err != nil {
+		return nil, fmt.Errorf("failed to parse doctor history %s: %w", path, err)
+	}
+	return runs, nil
+}
+
+// RecordRun adds a run to the history and returns the run before it, or
+// nil if this is the first
+func RecordRun(s *Summary) (*Summary, error) {
+	runs, err := LoadHistory()
+	if err != nil {
+		return nil, err
+	}
+	var previous *Summary
+	if len(runs) > 0 {
+		previous = runs[len(runs)-1]
+	}
+
+	runs = append(runs, s)
+	if len(runs) > MaxHistory {
+		runs = runs[len(runs)-MaxHistory:]
+	}
+
+	path, err := HistoryPath()
+	if err != nil {
+		return previous, err
+	}
+	data, err := json.MarshalIndent(runs, "", "  ")
+	if err != nil {
+		return previous, fmt.Errorf("failed to marshal doctor history: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return previous, fmt.Errorf("failed to create state directory: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		return previous, fmt.Errorf("failed to write doctor history: %w", err)
+	}
+	return previous, nil
+}
+
+// CompareRuns returns how the current run compares with an earlier one.
+// Errors weigh more than warnings; conflicts count as warnings.
+func CompareRuns(previous, current *Summary) Trend {
+	if previous == nil || current == nil {
+		return TrendNone
+	}
+	switch {
+	case current.Errors < previous.Errors:
+		return TrendImproved
+	case current.Errors > previous.Errors:
+		return TrendWorsened
+	}
+	prevWarnings := previous.Warnings + previous.Conflicts
+	curWarnings := current.Warnings + current.Conflicts
+	switch {
+	case curWarnings < prevWarnings:
+		return TrendImproved
+	case curWarnings > prevWarnings:
+		return TrendWorsened
+	}
+	return TrendUnchanged
+}
+
+// CheckChange is a check whose status differs between two runs. A status
+// is empty when the check did not run.
+type CheckChange struct {
+	Name    string
+	From    CheckStatus
+	To      CheckStatus
+	Message string // Message of the later run
+}
+
+// Trend reports whether the check got better or worse
+func (c CheckChange) Trend() Trend {
+	from, to := severity(c.From), severity(c.To)
+	switch {
+	case to < from:
+		return TrendImproved
+	case to > from:
+		return TrendWorsened
+	}
+	return TrendUnchanged
+}
+
+// severity orders statuses for comparing runs; a check that did not run
+// counts as passing
+func severity(s CheckStatus) int {
+	switch s {
+	case StatusError:
+		return 2
+	case StatusWarning:
+		return 1
+	}
+	return 0
+}
+
+// DiffRuns lists the checks whose status changed from one run to another,
+// in the order of the later run followed by checks it no longer has. Checks
+// are matched by ID, or by name for runs recorded before checks had IDs.
+func DiffRuns(from, to *Summary) []CheckChange {
+	byID := make(map[string]int)
+	byName := make(map[string]int)
+	for i, check := range from.Checks {
+		if check.ID != "" {
+			byID[check.ID] = i
+		}
+		byName[check.Name] = i
+	}
+
+	var changes []CheckChange
+	matched := make(map[int]bool)
+	for _, check := range to.Checks {
+		var old SummaryCheck
+		i, ok := byID[check.ID]
+		if !ok {
+			i, ok = byName[check.Name]
+		}
+		if ok && !matched[i] {
+			matched[i] = true
+			old = from.Checks[i]
+		}
+		if old.Status != check.Status {
+			changes = append(changes, CheckChange{Name: check.Name, From: old.Status, To: check.Status, Message: check.Message})
+		}
+	}
+	for i, check := range from.Checks {
+		if !matched[i] {
+			changes = append(changes, CheckChange{Name: check.Name, From: check.Status})
+		}
+	}
+	return changes
+}
+
+// FindRun returns the latest run recorded at or before the time given by
+// since: a duration such as "24h" or "7d" ago, or a date (2006-01-02) or
+// RFC 3339 timestamp
+func FindRun(runs []*Summary, since string, now time.Time) (*Summary, error) {
+	at, err := parseSince(since, now)
+	if err != nil {
+		return nil, err
+	}
+	for i := len(runs) - 1; i >= 0; i-- {
+		if !runs[i].CheckedAt.After(at) {
+			return runs[i], nil
+		}
+	}
+	return nil, fmt.Errorf("no doctor run recorded before %s", at.Format("2006-01-02 15:04"))
+}
+
+func parseSince(since string, now time.Time) (time.Time, error) {
+	if days, ok := strings.CutSuffix(since, "d"); ok {
+		if n, err := strconv.Atoi(days); err == nil && n >= 0 {
+			return now.AddDate(0, 0, -n), nil
+		}
+	}
+	if d, err := time.ParseDuration(since); err == nil && d >= 0 {
+		return now.Add(-d), nil
+	}
+	if t, err := time.ParseInLocation("2006-01-02", since, now.Location()); err == nil {
+		// A date means any run that day
+		return t.AddDate(0, 0, 1).Add(-time.Nanosecond), nil
+	}
+	if t, err := time.Parse(time.RFC3339, since); err == nil {
+		return t, nil
+	}
+	return time.Time{}, fmt.Errorf("invalid --since %q: use a duration (24h, 7d), a date (2006-01-02) or an RFC 3339 time", since)
+}
@@ -0,0 +1,131 @@
+package doctor
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/nvandessel/go4dot/internal/workspace"
+)
+
+func TestRecordRun(t *testing.T) {
+	t.Setenv(workspace.EnvUserHome, t.TempDir())
+
+	if runs, err := LoadHistory(); err != nil || len(runs) != 0 {
+		t.Fatalf("LoadHistory() with no file = %v, %v", runs, err)
+	}
+
+	first := &Summary{Status: StatusWarning, Warnings: 1, CheckedAt: time.Now().Add(-time.Hour)}
+	if previous, err := RecordRun(first); err != nil || previous != nil {
+		t.Fatalf("first RecordRun() = %v, %v", previous, err)
+	}
+	previous, err := RecordRun(&Summary{Status: StatusOK, OK: 3, CheckedAt: time.Now()})
+	if err != nil {
+		t.Fatalf("RecordRun() error = %v", err)
+	}
+	if previous == nil || previous.Warnings != 1 {
+		t.Errorf("RecordRun() previous = %+v, want the first run", previous)
+	}
+
+	// Only the latest MaxHistory runs are kept
+	for i := 0; i < MaxHistory; i++ {
+		if _, err := RecordRun(&Summary{OK: i}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	runs, err := LoadHistory()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(runs) != MaxHistory || runs[0].OK != 0 || runs[len(runs)-1].OK != MaxHistory-1 {
+		t.Errorf("history has %d runs, first OK %d", len(runs), runs[0].OK)
+	}
+}
+
+func TestCompareRuns(t *testing.T) {
+	tests := []struct {
+		name     string
+		previous *Summary
+		current  *Summary
+		want     Trend
+	}{
+		{"first run", nil, &Summary{}, TrendNone},
+		{"fewer errors", &Summary{Errors: 2}, &Summary{Errors: 1, Warnings: 4}, TrendImproved},
+		{"more errors", &Summary{Errors: 0, Warnings: 5}, &Summary{Errors: 1}, TrendWorsened},
+		{"fewer warnings", &Summary{Warnings: 2}, &Summary{Warnings: 1}, TrendImproved},
+		{"new conflict", &Summary{Warnings: 1}, &Summary{Warnings: 1, Conflicts: 1}, TrendWorsened},
+		{"same", &Summary{Errors: 1, Warnings: 1}, &Summary{Errors: 1, Warnings: 1}, TrendUnchanged},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := CompareRuns(tt.previous, tt.current); got != tt.want {
+				t.Errorf("CompareRuns() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDiffRuns(t *testing.T) {
+	from := &Summary{Checks: []SummaryCheck{
+		{ID: "stow", Name: "GNU Stow", Status: StatusOK},
+		{ID: "symlinks", Name: "Symlinks", Status: StatusOK},
+		{Name: "SSH Keys", Status: StatusWarning}, // Recorded before checks had IDs
+		{ID: "machine-config", Name: "Machine Configuration", Status: StatusOK},
+	}}
+	to := &Summary{Checks: []SummaryCheck{
+		{ID: "stow", Name: "GNU Stow", Status: StatusOK},
+		{ID: "symlinks", Name: "Symlinks", Status: StatusWarning, Message: "2 missing"},
+		{ID: "ssh-keys", Name: "SSH Keys", Status: StatusOK},
+		{ID: "artifacts", Name: "Repository Artifacts", Status: StatusOK},
+	}}
+
+	got := DiffRuns(from, to)
+	want := []CheckChange{
+		{Name: "Symlinks", From: StatusOK, To: StatusWarning, Message: "2 missing"},
+		{Name: "SSH Keys", From: StatusWarning, To: StatusOK},
+		{Name: "Repository Artifacts", From: "", To: StatusOK},
+		{Name: "Machine Configuration", From: StatusOK},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("DiffRuns() = %+v\nwant %+v", got, want)
+	}
+	if got[0].Trend() != TrendWorsened || got[1].Trend() != TrendImproved || got[3].Trend() != TrendUnchanged {
+		t.Errorf("trends = %q, %q, %q", got[0].Trend(), got[1].Trend(), got[3].Trend())
+	}
+}
+
+func TestFindRun(t *testing.T) {
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	runs := []*Summary{
+		{OK: 1, CheckedAt: now.AddDate(0, 0, -10)},
+		{OK: 2, CheckedAt: now.AddDate(0, 0, -3)},
+		{OK: 3, CheckedAt: now.Add(-2 * time.Hour)},
+	}
+
+	tests := []struct {
+		since   string
+		wantOK  int
+		wantErr bool
+	}{
+		{"1h", 3, false},
+		{"24h", 2, false},
+		{"7d", 1, false},
+		{"2026-03-07", 2, false},
+		{"2026-03-01T00:00:00Z", 1, false},
+		{"30d", 0, true},
+		{"last tuesday", 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.since, func(t *testing.T) {
+			got, err := FindRun(runs, tt.since, now)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("FindRun() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && got.OK != tt.wantOK {
+				t.Errorf("FindRun() = run %d, want run %d", got.OK, tt.wantOK)
+			}
+		})
+	}
+}
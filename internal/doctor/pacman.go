@@ -0,0 +1,58 @@
+package doctor
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/nvandessel/go4dot/internal/platform"
+)
+
+// checkPacman warns on Arch and Manjaro when pacman's sync databases or
+// mirrorlist are old enough that installing dependencies is likely to fail
+// on packages the mirrors no longer carry
+func checkPacman(status platform.PacmanStatus, now time.Time) Check {
+	check := Check{
+		ID:          "pacman-db",
+		Name:        "Pacman Database",
+		Description: "Freshness of pacman's sync databases and mirrorlist",
+	}
+
+	var problems, fixes []string
+	switch {
+	case status.LastSync.IsZero():
+		problems = append(problems, "sync databases were never refreshed")
+	case status.SyncStale(now):
+		problems = append(problems, fmt.Sprintf("sync databases last refreshed %s", daysAgo(status.LastSync, now)))
+	}
+	if len(problems) > 0 {
+		fixes = append(fixes, "Run 'g4d deps refresh' to refresh them with pacman -Sy")
+	}
+	if status.MirrorlistStale(now) {
+		problems = append(problems, fmt.Sprintf("mirrorlist last changed %s", daysAgo(status.Mirrorlist, now)))
+		fixes = append(fixes, "update /etc/pacman.d/mirrorlist, e.g. with reflector")
+	}
+
+	if len(problems) > 0 {
+		check.Status = StatusWarning
+		check.Message = strings.Join(problems, "; ") + ", so installs may fail"
+		check.Fix = strings.Join(fixes, ", and ")
+		return check
+	}
+
+	check.Status = StatusOK
+	check.Message = fmt.Sprintf("Refreshed %s", daysAgo(status.LastSync, now))
+	return check
+}
+
+// daysAgo describes how long before now t was, e.g. "12 days ago"
+func daysAgo(t, now time.Time) string {
+	switch days := int(now.Sub(t).Hours() / 24); days {
+	case 0:
+		return "today"
+	case 1:
+		return "yesterday"
+	default:
+		return fmt.Sprintf("%d days ago", days)
+	}
+}
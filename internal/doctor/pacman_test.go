@@ -0,0 +1,65 @@
+package doctor
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nvandessel/go4dot/internal/platform"
+)
+
+func TestCheckPacman(t *testing.T) {
+	now := time.Date(2026, 3, 20, 12, 0, 0, 0, time.UTC)
+	day := 24 * time.Hour
+
+	tests := []struct {
+		name        string
+		status      platform.PacmanStatus
+		wantStatus  CheckStatus
+		wantMessage string
+		wantFix     string
+	}{
+		{
+			name:        "fresh",
+			status:      platform.PacmanStatus{LastSync: now.Add(-day), Mirrorlist: now.Add(-30 * day)},
+			wantStatus:  StatusOK,
+			wantMessage: "Refreshed yesterday",
+		},
+		{
+			name:        "never refreshed",
+			status:      platform.PacmanStatus{},
+			wantStatus:  StatusWarning,
+			wantMessage: "never refreshed",
+			wantFix:     "g4d deps refresh",
+		},
+		{
+			name:        "stale databases",
+			status:      platform.PacmanStatus{LastSync: now.Add(-12 * day)},
+			wantStatus:  StatusWarning,
+			wantMessage: "last refreshed 12 days ago",
+			wantFix:     "g4d deps refresh",
+		},
+		{
+			name:        "stale mirrorlist",
+			status:      platform.PacmanStatus{LastSync: now, Mirrorlist: now.Add(-200 * day)},
+			wantStatus:  StatusWarning,
+			wantMessage: "mirrorlist last changed 200 days ago",
+			wantFix:     "reflector",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			check := checkPacman(tt.status, now)
+			if check.Status != tt.wantStatus {
+				t.Errorf("Status = %v, want %v", check.Status, tt.wantStatus)
+			}
+			if !strings.Contains(check.Message, tt.wantMessage) {
+				t.Errorf("Message = %q, want it to contain %q", check.Message, tt.wantMessage)
+			}
+			if !strings.Contains(check.Fix, tt.wantFix) {
+				t.Errorf("Fix = %q, want it to contain %q", check.Fix, tt.wantFix)
+			}
+		})
+	}
+}
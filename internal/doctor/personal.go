@@ -0,0 +1,209 @@
+package doctor
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io/fs"
+	"maps"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/nvandessel/go4dot/internal/config"
+	"github.com/nvandessel/go4dot/internal/stow"
+)
+
+// maxScannedFileSize is the size above which a file is not scanned for
+// personal data; configs that big are rarely hand-written
+const maxScannedFileSize = 1 << 20
+
+// personalRule recognizes a value that differs per person or machine and
+// belongs in a machine_config template rather than in a config file
+type personalRule struct {
+	Kind   string         // Human readable description
+	Match  *regexp.Regexp // Matched against each line
+	Group  int            // Submatch holding the value; 0 for the whole match
+	Secret bool           // Mask the value when showing it
+}
+
+var personalRules = []personalRule{
+	{Kind: "email address", Match: regexp.MustCompile(`[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`)},
+	{Kind: "GPG key ID", Match: regexp.MustCompile(`(?i)(?:signing_?key|default-key|gpg_?key(?:_?id)?)["']?\s*[=:\s]\s*["']?((?:0x)?(?:[0-9a-f]{40}|[0-9a-f]{16}))\b`), Group: 1},
+	{Kind: "GitHub token", Match: regexp.MustCompile(`\b(?:gh[pousr]_[A-Za-z0-9]{36,}|github_pat_[A-Za-z0-9_]{22,})`), Secret: true},
+	{Kind: "AWS access key", Match: regexp.MustCompile(`\bAKIA[0-9A-Z]{16}\b`), Secret: true},
+	{Kind: "Slack token", Match: regexp.MustCompile(`\bxox[abposr]-[A-Za-z0-9-]{10,}`), Secret: true},
+	{Kind: "API token", Match: regexp.MustCompile(`(?i)(?:api[_-]?key|access[_-]?token|auth[_-]?token|secret)["']?\s*[=:]\s*["']?([A-Za-z0-9_\-/+]{20,})`), Group: 1, Secret: true},
+	{Kind: "home directory path", Match: regexp.MustCompile(`(?:/home|/Users)/[A-Za-z0-9._-]+`)},
+}
+
+// defaultAllowed are values that look personal but never are: placeholders
+// and addresses shared by everyone
+var defaultAllowed = []*regexp.Regexp{
+	regexp.MustCompile(`(?i)@(?:[a-z0-9-]+\.)*example\.(?:com|org|net)$`),
+	regexp.MustCompile(`(?i)noreply`),
+	regexp.MustCompile(`^git@`),
+	regexp.MustCompile(`^(?:/home|/Users)/(?:user|username|you|me|runner|linuxbrew|Shared)$`),
+}
+
+// PersonalDataFinding is a value in a config file that looks specific to
+// one person or machine, such as an email address or a token
+type PersonalDataFinding struct {
+	Config string `json:"config"`
+	Path   string `json:"path"` // Relative to the config directory
+	Line   int    `json:"line"`
+	Kind   string `json:"kind"`
+	Value  string `json:"value"` // Masked for secrets
+}
+
+// FindPersonalData scans every config directory for values that should be
+// machine_config prompts instead. Values matching a doctor.allow pattern are
+// left out, as are the paths stow or .go4dotignore ignore.
+func FindPersonalData(cfg *config.Config, dotfilesPath string) []PersonalDataFinding {
+	repoIgnore, _ := config.LoadRepoIgnore(dotfilesPath)
+	allowed := append(cfg.Doctor.AllowPatterns(), defaultAllowed...)
+
+	var findings []PersonalDataFinding
+	for _, item := range cfg.GetAllConfigs() {
+		findings = append(findings, findConfigPersonalData(item, dotfilesPath, repoIgnore, allowed)...)
+	}
+	return findings
+}
+
+func findConfigPersonalData(item config.ConfigItem, dotfilesPath string, repoIgnore *config.RepoIgnore, allowed []*regexp.Regexp) []PersonalDataFinding {
+	configDir := item.Dir(dotfilesPath)
+	if _, err := os.Stat(configDir); err != nil {
+		return nil
+	}
+
+	ignore, err := stow.LoadIgnoreList(configDir)
+	if err != nil {
+		return nil
+	}
+
+	var findings []PersonalDataFinding
+	_ = filepath.WalkDir(configDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || path == configDir {
+			return nil
+		}
+
+		relPath, _ := filepath.Rel(configDir, path)
+		if ignore.Match(relPath) || repoIgnore.Match(filepath.Join(item.Path, relPath), d.IsDir()) {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if d.IsDir() && d.Name() == ".git" {
+			return filepath.SkipDir
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+
+		for _, f := range scanPersonalData(path, allowed) {
+			f.Config, f.Path = item.Name, relPath
+			findings = append(findings, f)
+		}
+		return nil
+	})
+
+	return findings
+}
+
+// scanPersonalData returns the personal values in the text file at path,
+// one per value and line
+func scanPersonalData(path string, allowed []*regexp.Regexp) []PersonalDataFinding {
+	info, err := os.Stat(path)
+	if err != nil || info.Size() > maxScannedFileSize {
+		return nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+	return scanPersonalDataBytes(data, allowed)
+}
+
+// ScanPersonalData returns the personal values in data, the contents of a
+// config file, leaving out those cfg's doctor.allow patterns allow. The
+// findings have no Config or Path set.
+func ScanPersonalData(cfg *config.Config, data []byte) []PersonalDataFinding {
+	if len(data) > maxScannedFileSize {
+		return nil
+	}
+	return scanPersonalDataBytes(data, append(cfg.Doctor.AllowPatterns(), defaultAllowed...))
+}
+
+func scanPersonalDataBytes(data []byte, allowed []*regexp.Regexp) []PersonalDataFinding {
+	if bytes.IndexByte(data, 0) >= 0 {
+		return nil // Binary
+	}
+
+	var findings []PersonalDataFinding
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), maxScannedFileSize)
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		line := scanner.Text()
+		seen := make(map[string]bool)
+		for _, rule := range personalRules {
+			for _, m := range rule.Match.FindAllStringSubmatch(line, -1) {
+				value := m[rule.Group]
+				if seen[value] || isAllowed(value, allowed) {
+					continue
+				}
+				seen[value] = true
+				if rule.Secret {
+					value = maskSecret(value)
+				}
+				findings = append(findings, PersonalDataFinding{Line: lineNo, Kind: rule.Kind, Value: value})
+			}
+		}
+	}
+	return findings
+}
+
+func isAllowed(value string, allowed []*regexp.Regexp) bool {
+	for _, re := range allowed {
+		if re.MatchString(value) {
+			return true
+		}
+	}
+	return false
+}
+
+// maskSecret keeps the first characters of a token, enough to recognize it
+func maskSecret(value string) string {
+	if len(value) <= 8 {
+		return strings.Repeat("*", len(value))
+	}
+	return value[:4] + strings.Repeat("*", 8)
+}
+
+// summarizePersonalDataCheck creates a check summary from personal data
+// findings
+func summarizePersonalDataCheck(findings []PersonalDataFinding) Check {
+	check := Check{
+		ID:          "personal-data",
+		Name:        "Personal Data",
+		Description: "Emails, keys, tokens and home paths hard-coded in configs",
+	}
+
+	if len(findings) == 0 {
+		check.Status = StatusOK
+		check.Message = "No hard-coded personal data found"
+		return check
+	}
+
+	configs := make(map[string]bool)
+	for _, f := range findings {
+		configs[f.Config] = true
+	}
+
+	check.Status = StatusWarning
+	check.Message = fmt.Sprintf("%d machine-specific value(s) in %s", len(findings), strings.Join(slices.Sorted(maps.Keys(configs)), ", "))
+	check.Fix = "Move them into machine_config templates that prompt for them, or allow them under doctor.allow"
+	return check
+}
@@ -2,7 +2,12 @@ package doctor
 
 import (
 	"fmt"
+	"maps"
+	"slices"
 	"strings"
+
+	"github.com/nvandessel/go4dot/internal/deps"
+	"github.com/nvandessel/go4dot/internal/stow"
 )
 
 // Report generates a human-readable health report
@@ -29,7 +34,7 @@ func (r *CheckResult) Report() string {
 		icon := statusIcon(check.Status)
 		fmt.Fprintf(&sb, "%s %s\n", icon, check.Name)
 		fmt.Fprintf(&sb, "  %s\n", check.Message)
-		if check.Fix != "" && check.Status != StatusOK {
+		if check.Fix != "" && (check.Status == StatusWarning || check.Status == StatusError) {
 			fmt.Fprintf(&sb, "  → %s\n", check.Fix)
 		}
 		sb.WriteString("\n")
@@ -95,6 +100,34 @@ func (r *CheckResult) DetailedReport() string {
 		}
 	}
 
+	// Add the output of failed verify commands
+	if failed := FailedFunctionalChecks(r.Functional); len(failed) > 0 {
+		sb.WriteString("\n── Functional Checks ──\n\n")
+		for _, f := range failed {
+			fmt.Fprintf(&sb, "%s [%s] %s\n", statusIcon(f.Status), f.Config, f.Command)
+			fmt.Fprintf(&sb, "  %s\n", f.Message)
+			for _, line := range strings.Split(f.Output, "\n") {
+				if line != "" {
+					fmt.Fprintf(&sb, "  | %s\n", line)
+				}
+			}
+		}
+	}
+
+	// Add the links that are not in place
+	var links []string
+	for _, s := range r.LinkStatus {
+		if s.Status != stow.LinkOK {
+			links = append(links, fmt.Sprintf("• %s → %s\n  %s\n", s.Link.Target, s.Link.Source, s.Message))
+		}
+	}
+	if len(links) > 0 {
+		sb.WriteString("\n── Links ──\n\n")
+		for _, line := range links {
+			sb.WriteString(line)
+		}
+	}
+
 	// Add detailed external status if any are missing
 	if len(r.ExternalStatus) > 0 {
 		hasMissing := false
@@ -117,6 +150,26 @@ func (r *CheckResult) DetailedReport() string {
 		}
 	}
 
+	// Add detailed toolchain status if any need setting up
+	var toolchains []string
+	for _, s := range r.ToolchainStatus {
+		tc := s.Toolchain
+		switch {
+		case s.Status == "missing" && !s.Manager:
+			toolchains = append(toolchains, fmt.Sprintf("• %s: %s not installed\n", tc.Language, deps.ToolchainManager(tc.Language)))
+		case s.Status == "missing":
+			toolchains = append(toolchains, fmt.Sprintf("• %s: %s not installed\n", tc.Language, strings.Join(s.Missing, ", ")))
+		case s.Status == "inactive":
+			toolchains = append(toolchains, fmt.Sprintf("• %s: default is %s, want %s\n", tc.Language, s.Active, tc.DefaultVersion()))
+		}
+	}
+	if len(toolchains) > 0 {
+		sb.WriteString("\n── Toolchains ──\n\n")
+		for _, line := range toolchains {
+			sb.WriteString(line)
+		}
+	}
+
 	// Add detailed machine config status if any are missing
 	if len(r.MachineStatus) > 0 {
 		hasMissing := false
@@ -148,6 +201,41 @@ func (r *CheckResult) DetailedReport() string {
 			fmt.Fprintf(&sb, "  Points to: %s\n", l.SourcePath)
 		}
 	}
+	if c := r.SymlinkScan; c != nil && (len(r.UnmanagedLinks) > 0 || c.Truncated) {
+		fmt.Fprintf(&sb, "\nScanned %s:\n", c.Summary())
+		for _, root := range c.Roots {
+			status := fmt.Sprintf("depth %d", root.Depth)
+			if root.Missing {
+				status = "not found"
+			}
+			fmt.Fprintf(&sb, "• %s (%s)\n", root.Path, status)
+		}
+	}
+
+	// Add caches and generated files with suggested ignore entries
+	if len(r.Artifacts) > 0 {
+		sb.WriteString("\n── Repository Artifacts ──\n\n")
+		for _, a := range r.Artifacts {
+			fmt.Fprintf(&sb, "• [%s] %s (%s)\n", a.Config, a.Path, a.Reason)
+		}
+		sb.WriteString("\nSuggested .stow-local-ignore entries:\n\n")
+		suggested := SuggestedIgnores(r.Artifacts)
+		for _, name := range slices.Sorted(maps.Keys(suggested)) {
+			fmt.Fprintf(&sb, "%s:\n", name)
+			for _, entry := range suggested[name] {
+				fmt.Fprintf(&sb, "  %s\n", entry)
+			}
+		}
+	}
+
+	// Add where hard-coded personal data is, leaving the values out of
+	// the report
+	if len(r.PersonalData) > 0 {
+		sb.WriteString("\n── Personal Data ──\n\n")
+		for _, f := range r.PersonalData {
+			fmt.Fprintf(&sb, "• [%s] %s:%d (%s)\n", f.Config, f.Path, f.Line, f.Kind)
+		}
+	}
 
 	// Add detailed missing deps if any
 	if r.DepsResult != nil {
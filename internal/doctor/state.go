@@ -0,0 +1,207 @@
+package doctor
+
+import (
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/nvandessel/go4dot/internal/config"
+	"github.com/nvandessel/go4dot/internal/state"
+	"github.com/nvandessel/go4dot/internal/stow"
+)
+
+// StateIssueKind identifies a type of inconsistency between state and reality
+type StateIssueKind string
+
+const (
+	// IssueDotfilesPath means the recorded dotfiles path is missing or differs
+	IssueDotfilesPath StateIssueKind = "dotfiles_path"
+	// IssueDuplicateConfig means a config is recorded more than once
+	IssueDuplicateConfig StateIssueKind = "duplicate_config"
+	// IssueUnknownConfig means a config in state no longer exists in .go4dot.yaml
+	IssueUnknownConfig StateIssueKind = "unknown_config"
+	// IssueUnlinkedConfig means a config is marked installed but has no links
+	IssueUnlinkedConfig StateIssueKind = "unlinked_config"
+	// IssueUntrackedConfig means a config is fully linked but not in state
+	IssueUntrackedConfig StateIssueKind = "untracked_config"
+	// IssueStaleBaseline means a symlink count exists for a deleted config
+	IssueStaleBaseline StateIssueKind = "stale_baseline"
+	// IssueUnknownExternal means an external dep in state is not in .go4dot.yaml
+	IssueUnknownExternal StateIssueKind = "unknown_external"
+	// IssueMissingExternal means an external dep is marked installed but is gone
+	IssueMissingExternal StateIssueKind = "missing_external"
+)
+
+// StateIssue describes one inconsistency found in the state file
+type StateIssue struct {
+	Kind    StateIssueKind `json:"kind"`
+	Subject string         `json:"subject"` // Config name, external ID or path
+	Message string         `json:"message"`
+	Fix     string         `json:"fix"` // What repair will do
+}
+
+// CheckState compares the state file against .go4dot.yaml and the links on
+// disk. Issues are returned in a stable order.
+func CheckState(st *state.State, cfg *config.Config, dotfilesPath string) []StateIssue {
+	var issues []StateIssue
+	if st == nil || cfg == nil {
+		return issues
+	}
+
+	if dotfilesPath != "" && filepath.Clean(st.DotfilesPath) != filepath.Clean(dotfilesPath) {
+		recorded := st.DotfilesPath
+		if recorded == "" {
+			recorded = "(empty)"
+		}
+		issues = append(issues, StateIssue{
+			Kind:    IssueDotfilesPath,
+			Subject: st.DotfilesPath,
+			Message: fmt.Sprintf("Dotfiles path %s does not match %s", recorded, dotfilesPath),
+			Fix:     fmt.Sprintf("Set dotfiles path to %s", dotfilesPath),
+		})
+	}
+
+	seen := make(map[string]bool)
+	for _, c := range st.Configs {
+		if seen[c.Name] {
+			issues = append(issues, StateIssue{
+				Kind:    IssueDuplicateConfig,
+				Subject: c.Name,
+				Message: fmt.Sprintf("Config %s is recorded more than once", c.Name),
+				Fix:     "Keep the first entry",
+			})
+			continue
+		}
+		seen[c.Name] = true
+
+		item := cfg.GetConfigByName(c.Name)
+		if item == nil {
+			issues = append(issues, StateIssue{
+				Kind:    IssueUnknownConfig,
+				Subject: c.Name,
+				Message: fmt.Sprintf("Config %s is installed in state but not defined in .go4dot.yaml", c.Name),
+				Fix:     "Remove it from state",
+			})
+			continue
+		}
+
+		if dotfilesPath == "" {
+			continue
+		}
+		status, err := stow.GetConfigLinkStatus(*item, dotfilesPath, cfg.Stow)
+		if err == nil && len(status.LinkedFiles) == 0 {
+			issues = append(issues, StateIssue{
+				Kind:    IssueUnlinkedConfig,
+				Subject: c.Name,
+				Message: fmt.Sprintf("Config %s is marked installed but has no links", c.Name),
+				Fix:     "Remove it from state",
+			})
+		}
+	}
+
+	if dotfilesPath != "" {
+		for _, item := range cfg.GetAllConfigs() {
+			if seen[item.Name] {
+				continue
+			}
+			status, err := stow.GetConfigLinkStatus(item, dotfilesPath, cfg.Stow)
+			if err == nil && status.IsFullyLinked() {
+				issues = append(issues, StateIssue{
+					Kind:    IssueUntrackedConfig,
+					Subject: item.Name,
+					Message: fmt.Sprintf("Config %s is fully linked but not recorded in state", item.Name),
+					Fix:     "Add it to state",
+				})
+			}
+		}
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(st.SymlinkCounts)) {
+		if cfg.GetConfigByName(name) == nil {
+			issues = append(issues, StateIssue{
+				Kind:    IssueStaleBaseline,
+				Subject: name,
+				Message: fmt.Sprintf("Symlink count recorded for deleted config %s", name),
+				Fix:     "Remove the symlink count",
+			})
+		}
+	}
+
+	externals := make(map[string]bool, len(cfg.External))
+	for _, ext := range cfg.External {
+		externals[ext.ID] = true
+	}
+	for _, id := range slices.Sorted(maps.Keys(st.ExternalDeps)) {
+		ext := st.ExternalDeps[id]
+		switch {
+		case !externals[id]:
+			issues = append(issues, StateIssue{
+				Kind:    IssueUnknownExternal,
+				Subject: id,
+				Message: fmt.Sprintf("External dependency %s is not defined in .go4dot.yaml", id),
+				Fix:     "Remove it from state",
+			})
+		case ext.Installed && ext.Path != "":
+			if _, err := os.Stat(ext.Path); os.IsNotExist(err) {
+				issues = append(issues, StateIssue{
+					Kind:    IssueMissingExternal,
+					Subject: id,
+					Message: fmt.Sprintf("External dependency %s is marked installed but %s is missing", id, ext.Path),
+					Fix:     "Remove it from state",
+				})
+			}
+		}
+	}
+
+	return issues
+}
+
+// RepairState applies the fix for each issue to st. The caller is responsible
+// for saving the state afterwards.
+func RepairState(st *state.State, cfg *config.Config, dotfilesPath string, issues []StateIssue) {
+	for _, issue := range issues {
+		switch issue.Kind {
+		case IssueDotfilesPath:
+			st.DotfilesPath = dotfilesPath
+		case IssueDuplicateConfig:
+			dedupeConfigs(st)
+		case IssueUnknownConfig, IssueUnlinkedConfig:
+			// Duplicates may still be present, remove them all
+			for st.HasConfig(issue.Subject) {
+				st.RemoveConfig(issue.Subject)
+			}
+		case IssueUntrackedConfig:
+			isCore := false
+			for _, c := range cfg.Configs.Core {
+				if c.Name == issue.Subject {
+					isCore = true
+					break
+				}
+			}
+			if item := cfg.GetConfigByName(issue.Subject); item != nil {
+				st.AddConfig(item.Name, item.Path, isCore)
+			}
+		case IssueStaleBaseline:
+			st.RemoveSymlinkCount(issue.Subject)
+			st.RemoveFingerprint(issue.Subject)
+		case IssueUnknownExternal, IssueMissingExternal:
+			st.RemoveExternalDep(issue.Subject)
+		}
+	}
+}
+
+// dedupeConfigs keeps only the first entry for each config name
+func dedupeConfigs(st *state.State) {
+	seen := make(map[string]bool)
+	configs := st.Configs[:0]
+	for _, c := range st.Configs {
+		if seen[c.Name] {
+			continue
+		}
+		seen[c.Name] = true
+		configs = append(configs, c)
+	}
+	st.Configs = configs
+}
@@ -0,0 +1,122 @@
+package doctor
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/nvandessel/go4dot/internal/config"
+	"github.com/nvandessel/go4dot/internal/state"
+)
+
+// setupStateFixture creates a dotfiles repo with "linked" fully stowed into a
+// temporary HOME and "unlinked" present but not stowed.
+func setupStateFixture(t *testing.T) (*config.Config, string) {
+	t.Helper()
+
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	dotfiles := t.TempDir()
+
+	for _, name := range []string{"linked", "unlinked"} {
+		dir := filepath.Join(dotfiles, name)
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, "."+name+"rc"), []byte("x"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.Symlink(filepath.Join(dotfiles, "linked", ".linkedrc"), filepath.Join(home, ".linkedrc")); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := &config.Config{
+		Configs: config.ConfigGroups{
+			Core: []config.ConfigItem{
+				{Name: "linked", Path: "linked"},
+				{Name: "unlinked", Path: "unlinked"},
+			},
+		},
+		External: []config.ExternalDep{{ID: "plugin"}},
+	}
+	return cfg, dotfiles
+}
+
+func TestCheckState(t *testing.T) {
+	cfg, dotfiles := setupStateFixture(t)
+	missing := filepath.Join(t.TempDir(), "gone")
+
+	st := state.New()
+	st.DotfilesPath = "/old/dotfiles"
+	st.AddConfig("unlinked", "unlinked", true)
+	st.AddConfig("deleted", "deleted", false)
+	st.Configs = append(st.Configs, state.ConfigState{Name: "unlinked", Path: "unlinked"})
+	st.SetSymlinkCount("deleted", 3)
+	st.SetSymlinkCount("linked", 1)
+	st.SetExternalDep("plugin", missing, true)
+	st.SetExternalDep("old-plugin", missing, true)
+
+	issues := CheckState(st, cfg, dotfiles)
+
+	want := map[StateIssueKind]string{
+		IssueDotfilesPath:    "/old/dotfiles",
+		IssueUnlinkedConfig:  "unlinked",
+		IssueUnknownConfig:   "deleted",
+		IssueDuplicateConfig: "unlinked",
+		IssueUntrackedConfig: "linked",
+		IssueStaleBaseline:   "deleted",
+		IssueMissingExternal: "plugin",
+		IssueUnknownExternal: "old-plugin",
+	}
+	got := make(map[StateIssueKind]string)
+	for _, issue := range issues {
+		got[issue.Kind] = issue.Subject
+	}
+	for kind, subject := range want {
+		if got[kind] != subject {
+			t.Errorf("issue %s: subject = %q, want %q", kind, got[kind], subject)
+		}
+	}
+	if len(issues) != len(want) {
+		t.Errorf("got %d issues, want %d: %+v", len(issues), len(want), issues)
+	}
+
+	RepairState(st, cfg, dotfiles, issues)
+
+	if remaining := CheckState(st, cfg, dotfiles); len(remaining) != 0 {
+		t.Errorf("expected no issues after repair, got %+v", remaining)
+	}
+	if st.DotfilesPath != dotfiles {
+		t.Errorf("DotfilesPath = %q, want %q", st.DotfilesPath, dotfiles)
+	}
+	if names := st.GetConfigNames(); len(names) != 1 || names[0] != "linked" {
+		t.Errorf("configs after repair = %v, want [linked]", names)
+	}
+	if _, ok := st.GetSymlinkCount("deleted"); ok {
+		t.Error("expected stale symlink count to be removed")
+	}
+	if len(st.ExternalDeps) != 0 {
+		t.Errorf("expected external deps to be removed, got %v", st.ExternalDeps)
+	}
+}
+
+func TestCheckStateConsistent(t *testing.T) {
+	cfg, dotfiles := setupStateFixture(t)
+
+	st := state.New()
+	st.DotfilesPath = dotfiles
+	st.AddConfig("linked", "linked", true)
+	st.SetSymlinkCount("linked", 1)
+	st.SetSymlinkCount("unlinked", 1)
+
+	if issues := CheckState(st, cfg, dotfiles); len(issues) != 0 {
+		t.Errorf("expected no issues, got %+v", issues)
+	}
+}
+
+func TestCheckStateNil(t *testing.T) {
+	if issues := CheckState(nil, &config.Config{}, ""); len(issues) != 0 {
+		t.Errorf("expected no issues for nil state, got %+v", issues)
+	}
+}
@@ -0,0 +1,88 @@
+package doctor
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+)
+
+// SummaryFileName is the default file 'g4d doctor --ci' writes its summary to
+const SummaryFileName = "g4d-doctor.json"
+
+// Summary is a compact record of a doctor run that can be saved and read
+// back by other tools, such as 'g4d badge' in a later CI step
+type Summary struct {
+	Status    CheckStatus    `json:"status"` // Worst status of any check: ok, warning or error
+	OK        int            `json:"ok"`
+	Warnings  int            `json:"warnings"`
+	Errors    int            `json:"errors"`
+	Skipped   int            `json:"skipped"`
+	Conflicts int            `json:"conflicts"`
+	Platform  string         `json:"platform,omitempty"`
+	CheckedAt time.Time      `json:"checked_at"`
+	Checks    []SummaryCheck `json:"checks"`
+}
+
+// SummaryCheck is a single check in a Summary
+type SummaryCheck struct {
+	ID      string      `json:"id,omitempty"`
+	Name    string      `json:"name"`
+	Status  CheckStatus `json:"status"`
+	Message string      `json:"message,omitempty"`
+}
+
+// NewSummary summarizes a check result
+func NewSummary(r *CheckResult) *Summary {
+	summary := &Summary{
+		Status:    StatusOK,
+		Conflicts: r.ConflictCount(),
+		CheckedAt: time.Now(),
+	}
+	summary.OK, summary.Warnings, summary.Errors, summary.Skipped = r.CountByStatus()
+	switch {
+	case summary.Errors > 0:
+		summary.Status = StatusError
+	case summary.Warnings > 0:
+		summary.Status = StatusWarning
+	}
+	if r.Platform != nil {
+		summary.Platform = r.Platform.OS
+	}
+
+	for _, check := range r.Checks {
+		summary.Checks = append(summary.Checks, SummaryCheck{
+			ID:      check.ID,
+			Name:    check.Name,
+			Status:  check.Status,
+			Message: check.Message,
+		})
+	}
+	return summary
+}
+
+// Save writes the summary to path as JSON
+func (s *Summary) Save(path string) error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal summary: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write summary: %w", err)
+	}
+	return nil
+}
+
+// LoadSummary reads a summary written by Save
+func LoadSummary(path string) (*Summary, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var s Summary
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, fmt.Errorf("failed to parse summary %s: %w", path, err)
+	}
+	return &s, nil
+}
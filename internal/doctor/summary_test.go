@@ -0,0 +1,54 @@
+package doctor
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/nvandessel/go4dot/internal/platform"
+)
+
+func TestNewSummary(t *testing.T) {
+	tests := []struct {
+		name   string
+		checks []Check
+		want   CheckStatus
+	}{
+		{"all ok", []Check{{Status: StatusOK}, {Status: StatusSkipped}}, StatusOK},
+		{"warning", []Check{{Status: StatusOK}, {Status: StatusWarning}}, StatusWarning},
+		{"error wins", []Check{{Status: StatusWarning}, {Status: StatusError}}, StatusError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewSummary(&CheckResult{Checks: tt.checks})
+			if s.Status != tt.want {
+				t.Errorf("Status = %q, want %q", s.Status, tt.want)
+			}
+			if len(s.Checks) != len(tt.checks) {
+				t.Errorf("got %d checks, want %d", len(s.Checks), len(tt.checks))
+			}
+		})
+	}
+}
+
+func TestSummarySaveLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), SummaryFileName)
+	s := NewSummary(&CheckResult{
+		Platform: &platform.Platform{OS: "linux"},
+		Checks:   []Check{{Name: "Git", Status: StatusOK, Message: "git 2.43"}, {Name: "Stow", Status: StatusWarning}},
+	})
+	if err := s.Save(path); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	loaded, err := LoadSummary(path)
+	if err != nil {
+		t.Fatalf("LoadSummary failed: %v", err)
+	}
+	if loaded.Status != StatusWarning || loaded.OK != 1 || loaded.Warnings != 1 || loaded.Platform != "linux" {
+		t.Errorf("loaded summary = %+v", loaded)
+	}
+	if len(loaded.Checks) != 2 || loaded.Checks[0].Message != "git 2.43" {
+		t.Errorf("loaded checks = %+v", loaded.Checks)
+	}
+}
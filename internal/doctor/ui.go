@@ -2,6 +2,9 @@ package doctor
 
 import (
 	"fmt"
+	"maps"
+	"slices"
+	"strings"
 
 	"github.com/nvandessel/go4dot/internal/ui"
 )
@@ -37,6 +40,22 @@ func PrintReport(result *CheckResult, verbose bool) {
 		}
 	}
 
+	if failed := FailedFunctionalChecks(result.Functional); len(failed) > 0 {
+		printFunctional(failed, verbose)
+	}
+
+	if verbose && result.SymlinkScan != nil {
+		printSymlinkScan(result.SymlinkScan)
+	}
+
+	if len(result.Artifacts) > 0 {
+		printArtifacts(result.Artifacts, verbose)
+	}
+
+	if len(result.PersonalData) > 0 {
+		printPersonalData(result.PersonalData, verbose)
+	}
+
 	fmt.Println()
 	ui.Section("Summary")
 
@@ -65,3 +84,77 @@ func PrintReport(result *CheckResult, verbose bool) {
 		}
 	}
 }
+
+// printSymlinkScan lists the roots the unmanaged-symlinks check walked
+func printSymlinkScan(coverage *SymlinkScanCoverage) {
+	fmt.Println()
+	fmt.Printf("Symlink scan: %s\n", coverage.Summary())
+	for _, root := range coverage.Roots {
+		if root.Missing {
+			fmt.Printf("  %s %s\n", root.Path, ui.SubtleStyle.Render("(not found)"))
+			continue
+		}
+		fmt.Printf("  %s %s\n", root.Path, ui.SubtleStyle.Render(fmt.Sprintf("(depth %d)", root.Depth)))
+	}
+}
+
+// printArtifacts lists the suggested ignore entries per config, and with
+// verbose the individual files that triggered them
+func printArtifacts(artifacts []ArtifactFinding, verbose bool) {
+	ui.Section("Repository Artifacts")
+	if verbose {
+		for _, a := range artifacts {
+			fmt.Printf("  • [%s] %s %s\n", a.Config, a.Path, ui.SubtleStyle.Render("("+a.Reason+")"))
+		}
+		fmt.Println()
+	}
+
+	fmt.Println("Suggested .stow-local-ignore entries:")
+	suggested := SuggestedIgnores(artifacts)
+	for _, name := range slices.Sorted(maps.Keys(suggested)) {
+		fmt.Printf("  %s\n", name)
+		for _, entry := range suggested[name] {
+			fmt.Printf("    %s\n", entry)
+		}
+	}
+}
+
+// maxPersonalDataShown is how many personal data findings are listed
+// without verbose
+const maxPersonalDataShown = 10
+
+// printPersonalData lists the hard-coded personal values, all of them with
+// verbose
+func printPersonalData(findings []PersonalDataFinding, verbose bool) {
+	ui.Section("Personal Data")
+	shown := findings
+	if !verbose && len(shown) > maxPersonalDataShown {
+		shown = shown[:maxPersonalDataShown]
+	}
+	for _, f := range shown {
+		fmt.Printf("  • [%s] %s:%d %s %s\n", f.Config, f.Path, f.Line, f.Value, ui.SubtleStyle.Render("("+f.Kind+")"))
+	}
+	if len(shown) < len(findings) {
+		fmt.Printf("  %s\n", ui.SubtleStyle.Render(fmt.Sprintf("... and %d more (-v lists them)", len(findings)-len(shown))))
+	}
+	fmt.Println()
+	fmt.Println("Turn them into machine_config prompts rendered from a template, or")
+	fmt.Println("add a pattern matching them to doctor.allow in .go4dot.yaml.")
+}
+
+// printFunctional lists the configs whose verify command failed, and with
+// verbose the end of each command's output
+func printFunctional(failed []FunctionalCheck, verbose bool) {
+	ui.Section("Functional Checks")
+	for _, f := range failed {
+		ui.Error("%s: %s %s", f.Config, f.Command, ui.SubtleStyle.Render("("+f.Message+")"))
+		if !verbose {
+			continue
+		}
+		for _, line := range strings.Split(f.Output, "\n") {
+			if line != "" {
+				fmt.Printf("    %s\n", ui.SubtleStyle.Render(line))
+			}
+		}
+	}
+}
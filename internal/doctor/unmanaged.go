@@ -0,0 +1,258 @@
+package doctor
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/nvandessel/go4dot/internal/config"
+)
+
+// SymlinkScanCoverage describes how much of the filesystem the
+// unmanaged-symlinks check looked at
+type SymlinkScanCoverage struct {
+	Roots     []ScannedRoot
+	Dirs      int  // Directories listed
+	Entries   int  // Directory entries looked at
+	Budget    int  // Entries the scan could look at
+	Truncated bool // The budget ran out before every root was walked
+}
+
+// ScannedRoot is one of the roots the unmanaged-symlinks check walked
+type ScannedRoot struct {
+	Path    string
+	Depth   int
+	Missing bool // Path does not exist
+}
+
+// Summary describes the coverage briefly, e.g. "4210 entries in 312 directories"
+func (c *SymlinkScanCoverage) Summary() string {
+	if c == nil {
+		return ""
+	}
+	summary := fmt.Sprintf("%d entries in %d directories", c.Entries, c.Dirs)
+	if c.Truncated {
+		summary += fmt.Sprintf(", stopped at the budget of %d", c.Budget)
+	}
+	return summary
+}
+
+// symlinkScan walks the scan roots concurrently, counting the entries it
+// looks at against a shared budget
+type symlinkScan struct {
+	skip   []string // Directories never entered: the dotfiles repo
+	budget int
+	found  func(path string)
+
+	mu        sync.Mutex
+	entries   int
+	truncated bool
+	dirs      int
+	visited   map[string]int // Deepest remaining depth each directory was listed with
+	slots     chan struct{}
+	wg        sync.WaitGroup
+}
+
+// checkUnmanagedSymlinks finds symlinks below the doctor.scan_roots that
+// point into the dotfiles repo but are not created by any config or link
+func checkUnmanagedSymlinks(cfg *config.Config, dotfilesPath string) ([]UnmanagedSymlink, *SymlinkScanCoverage) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return nil, nil
+	}
+	if cfg.Stow.Target != "" {
+		home = cfg.Stow.TargetDir()
+	}
+
+	absDotfiles, err := filepath.Abs(dotfilesPath)
+	if err != nil {
+		absDotfiles = dotfilesPath
+	}
+	repoIgnore, _ := config.LoadRepoIgnore(absDotfiles)
+	// Links may reach the repo through either spelling of a symlinked home,
+	// such as /home and /usr/home on FreeBSD
+	dotfilesRoots := []string{absDotfiles}
+	if real, err := filepath.EvalSymlinks(absDotfiles); err == nil && real != absDotfiles {
+		dotfilesRoots = append(dotfilesRoots, real)
+	}
+
+	// Map of managed target paths for quick lookup
+	managedTargets := make(map[string]bool)
+	allConfigs := cfg.GetAllConfigs()
+	for _, configItem := range allConfigs {
+		configPath := configItem.Dir(absDotfiles)
+		_ = filepath.Walk(configPath, func(path string, info os.FileInfo, err error) error {
+			if err == nil && !info.IsDir() {
+				relPath, _ := filepath.Rel(configPath, path)
+				targetPath := filepath.Join(home, cfg.Stow.LinkPath(relPath))
+				managedTargets[filepath.Clean(targetPath)] = true
+			}
+			return nil
+		})
+	}
+	for _, l := range cfg.Links {
+		if l.IsCopy() {
+			continue
+		}
+		if targets, _, err := l.Paths(absDotfiles); err == nil {
+			for _, target := range targets {
+				managedTargets[target] = true
+			}
+		}
+	}
+
+	var mu sync.Mutex
+	var unmanaged []UnmanagedSymlink
+	isUnmanaged := func(path string) {
+		// It's a symlink, check where it points
+		linkDest, err := os.Readlink(path)
+		if err != nil {
+			return
+		}
+
+		// Resolve to absolute path
+		if !filepath.IsAbs(linkDest) {
+			linkDest = filepath.Join(filepath.Dir(path), linkDest)
+		}
+		linkDest = filepath.Clean(linkDest)
+
+		// Check if it points into dotfiles
+		for _, root := range dotfilesRoots {
+			if !strings.HasPrefix(linkDest, root) {
+				continue
+			}
+			relDest, _ := filepath.Rel(root, linkDest)
+			destInfo, _ := os.Stat(linkDest)
+			ignored := repoIgnore.Match(relDest, destInfo != nil && destInfo.IsDir())
+			if !ignored && !managedTargets[filepath.Clean(path)] {
+				mu.Lock()
+				unmanaged = append(unmanaged, UnmanagedSymlink{
+					TargetPath: path,
+					SourcePath: linkDest,
+				})
+				mu.Unlock()
+			}
+			return
+		}
+	}
+
+	roots := cfg.Doctor.SymlinkScanRoots(home)
+	scan := &symlinkScan{
+		skip:    dotfilesRoots,
+		budget:  cfg.Doctor.SymlinkScanBudget(),
+		found:   isUnmanaged,
+		visited: make(map[string]int),
+		slots:   make(chan struct{}, runtime.NumCPU()),
+	}
+	coverage := &SymlinkScanCoverage{Budget: scan.budget}
+	for _, root := range roots {
+		scanned := ScannedRoot{Path: root.Path, Depth: root.Depth}
+		if info, err := os.Stat(root.Path); err != nil || !info.IsDir() {
+			scanned.Missing = true
+		} else {
+			scan.walk(root.Path, root.Depth)
+		}
+		coverage.Roots = append(coverage.Roots, scanned)
+	}
+	scan.wg.Wait()
+
+	coverage.Dirs = scan.dirs
+	coverage.Entries = scan.entries
+	coverage.Truncated = scan.truncated
+
+	// The walk finishes in any order
+	sort.Slice(unmanaged, func(i, j int) bool {
+		return unmanaged[i].TargetPath < unmanaged[j].TargetPath
+	})
+	return unmanaged, coverage
+}
+
+// summarizeUnmanagedCheck creates a check summary from the unmanaged
+// symlinks found and how much the scan covered
+func summarizeUnmanagedCheck(unmanaged []UnmanagedSymlink, coverage *SymlinkScanCoverage) Check {
+	check := Check{
+		ID:          "unmanaged-symlinks",
+		Name:        "Unmanaged Symlinks",
+		Description: "Symlinks pointing to dotfiles but not in config",
+	}
+
+	switch {
+	case len(unmanaged) > 0:
+		check.Status = StatusWarning
+		check.Message = fmt.Sprintf("%d unmanaged symlinks found", len(unmanaged))
+		check.Fix = "Add these to your .go4dot.yaml or remove them"
+	case coverage != nil && coverage.Truncated:
+		check.Status = StatusWarning
+		check.Message = "No unmanaged symlinks found, but the scan stopped early"
+		check.Fix = "Raise doctor.scan_budget or scan fewer or shallower doctor.scan_roots"
+	default:
+		check.Status = StatusOK
+		check.Message = "No unmanaged symlinks found"
+	}
+	if coverage != nil {
+		check.Message += fmt.Sprintf(" (%s)", coverage.Summary())
+	}
+	return check
+}
+
+// walk lists dir in the background, then each subdirectory while depth
+// allows. Symlinked directories are reported, never entered.
+func (s *symlinkScan) walk(dir string, depth int) {
+	s.mu.Lock()
+	if s.truncated || s.visited[dir] >= depth {
+		// Overlapping roots list a directory once, as deep as any asks
+		s.mu.Unlock()
+		return
+	}
+	s.visited[dir] = depth
+	s.mu.Unlock()
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		s.slots <- struct{}{}
+		entries, err := os.ReadDir(dir)
+		<-s.slots
+		if err != nil {
+			return
+		}
+
+		s.mu.Lock()
+		if s.truncated {
+			s.mu.Unlock()
+			return
+		}
+		if s.entries+len(entries) > s.budget {
+			s.truncated = true
+			entries = entries[:s.budget-s.entries]
+		}
+		s.entries += len(entries)
+		s.dirs++
+		s.mu.Unlock()
+
+		for _, entry := range entries {
+			path := filepath.Join(dir, entry.Name())
+			switch {
+			case entry.Type()&os.ModeSymlink != 0:
+				s.found(path)
+			case entry.IsDir() && depth > 1 && !s.skipped(path):
+				s.walk(path, depth-1)
+			}
+		}
+	}()
+}
+
+// skipped reports whether path is the dotfiles repo, whose own symlinks
+// are not links into it from outside
+func (s *symlinkScan) skipped(path string) bool {
+	for _, skip := range s.skip {
+		if path == skip {
+			return true
+		}
+	}
+	return false
+}
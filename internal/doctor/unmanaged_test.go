@@ -0,0 +1,67 @@
+package doctor
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/nvandessel/go4dot/internal/config"
+)
+
+func TestCheckUnmanagedSymlinks_ScanRoots(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	dotfiles := filepath.Join(home, "dotfiles")
+	mkfile := func(path string) {
+		t.Helper()
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte("x"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	link := func(src, dst string) {
+		t.Helper()
+		if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Symlink(src, dst); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	mkfile(filepath.Join(dotfiles, "fish", "conf.d", "abbr.fish"))
+	mkfile(filepath.Join(dotfiles, "vim", ".vimrc"))
+	// Deep below ~/.config, out of reach of the default depth
+	link(filepath.Join(dotfiles, "fish", "conf.d", "abbr.fish"), filepath.Join(home, ".config", "fish", "conf.d", "abbr.fish"))
+	// A link inside the repo itself is not a link into it
+	link(filepath.Join(dotfiles, "vim", ".vimrc"), filepath.Join(dotfiles, "vim", "vimrc"))
+
+	cfg := &config.Config{}
+	unmanaged, coverage := checkUnmanagedSymlinks(cfg, dotfiles)
+	if len(unmanaged) != 0 {
+		t.Errorf("default roots found %+v", unmanaged)
+	}
+	if len(coverage.Roots) != 2 || coverage.Truncated {
+		t.Errorf("coverage = %+v", coverage)
+	}
+
+	cfg.Doctor.ScanRoots = []config.ScanRoot{{Path: "~", Depth: 4}, {Path: ".config", Depth: 3}}
+	unmanaged, coverage = checkUnmanagedSymlinks(cfg, dotfiles)
+	if len(unmanaged) != 1 || filepath.Base(unmanaged[0].TargetPath) != "abbr.fish" {
+		t.Errorf("deep roots found %+v, want abbr.fish only", unmanaged)
+	}
+	if coverage.Dirs != 4 || coverage.Truncated {
+		t.Errorf("coverage = %+v", coverage)
+	}
+
+	cfg.Doctor.ScanBudget = 2
+	unmanaged, coverage = checkUnmanagedSymlinks(cfg, dotfiles)
+	if !coverage.Truncated || coverage.Entries != 2 {
+		t.Errorf("coverage with a budget of 2 = %+v", coverage)
+	}
+	if check := summarizeUnmanagedCheck(unmanaged, coverage); check.Status != StatusWarning || check.Fix == "" {
+		t.Errorf("check = %+v, want a warning about the budget", check)
+	}
+}
@@ -0,0 +1,51 @@
+package doctor
+
+import (
+	"fmt"
+
+	"github.com/nvandessel/go4dot/internal/config"
+)
+
+// Verification is the result of checking the links of configs that were
+// just synced or installed
+type Verification struct {
+	Verified int
+	Failed   []SymlinkCheck // Links that are missing, blocked or point elsewhere
+}
+
+// VerifySymlinks runs the symlink checks of doctor for the named configs
+// only, as a quick check after linking them
+func VerifySymlinks(cfg *config.Config, dotfilesPath string, configNames []string) *Verification {
+	v := &Verification{}
+
+	for _, name := range configNames {
+		configItem := cfg.GetConfigByName(name)
+		if configItem == nil {
+			continue
+		}
+		for _, check := range checkConfigSymlinks(*configItem, dotfilesPath, cfg.Stow) {
+			switch check.Status {
+			case StatusOK:
+				v.Verified++
+			case StatusWarning, StatusError:
+				v.Failed = append(v.Failed, check)
+			}
+		}
+	}
+
+	return v
+}
+
+// OK reports whether every checked link is in place
+func (v *Verification) OK() bool {
+	return len(v.Failed) == 0
+}
+
+// Summary returns the counts, e.g. "12 links verified, 1 failed"
+func (v *Verification) Summary() string {
+	summary := fmt.Sprintf("%d links verified", v.Verified)
+	if len(v.Failed) > 0 {
+		summary += fmt.Sprintf(", %d failed", len(v.Failed))
+	}
+	return summary
+}
@@ -0,0 +1,70 @@
+package doctor
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/nvandessel/go4dot/internal/config"
+)
+
+func TestVerifySymlinks(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	dotfiles := t.TempDir()
+
+	write := func(rel string) string {
+		t.Helper()
+		path := filepath.Join(dotfiles, rel)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte("x"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+	link := func(source, rel string) {
+		t.Helper()
+		target := filepath.Join(home, rel)
+		if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Symlink(source, target); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	link(write("zsh/.zshrc"), ".zshrc")
+	link(write("zsh/.zprofile"), ".zprofile")
+	write("zsh/README.md") // Ignored by stow, never linked
+	write("git/.gitconfig")
+	link(filepath.Join(dotfiles, "elsewhere"), ".gitconfig")
+	write("nvim/.config/nvim/init.lua")
+
+	cfg := &config.Config{
+		Configs: config.ConfigGroups{
+			Core: []config.ConfigItem{
+				{Name: "zsh", Path: "zsh"},
+				{Name: "git", Path: "git"},
+				{Name: "nvim", Path: "nvim"},
+			},
+		},
+	}
+
+	v := VerifySymlinks(cfg, dotfiles, []string{"zsh", "git"})
+	if v.Verified != 2 || len(v.Failed) != 1 || v.Failed[0].Config != "git" {
+		t.Errorf("VerifySymlinks() = %+v, want 2 verified and git failed", v)
+	}
+	if v.OK() {
+		t.Error("OK() = true with a failed link")
+	}
+	if got := v.Summary(); got != "2 links verified, 1 failed" {
+		t.Errorf("Summary() = %q", got)
+	}
+
+	// Only the named configs are checked
+	if v := VerifySymlinks(cfg, dotfiles, []string{"zsh", "unknown"}); !v.OK() || v.Verified != 2 {
+		t.Errorf("VerifySymlinks(zsh) = %+v, want 2 verified", v)
+	}
+}
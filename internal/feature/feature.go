@@ -0,0 +1,140 @@
+// Package feature lets larger behavior changes ship progressively. A flag
+// turns a change on or off ahead of its default flipping, from the features
+// map in the user preferences file. A deprecation announces that something
+// goes away, once per run, with a pointer to how to migrate.
+package feature
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// docsBase is where migration notes are linked from
+const docsBase = "https://github.com/nvandessel/go4dot/blob/main/docs/"
+
+// Flag names
+const (
+	// ParallelRestow restows independent configs concurrently when syncing
+	// every config
+	ParallelRestow = "parallel-restow"
+)
+
+// Deprecation IDs
+const (
+	// DefaultsYes is the defaults.yes preference, which duplicates
+	// defaults.non_interactive
+	DefaultsYes = "prefs-defaults-yes"
+)
+
+// Flag is a behavior change that can be turned on or off
+type Flag struct {
+	Name        string
+	Description string
+	Default     bool   // Whether the change is on when the preferences don't say
+	Docs        string // Where the change is explained
+}
+
+// Deprecation is something that still works but is going away
+type Deprecation struct {
+	ID      string
+	Message string // What is deprecated and what to use instead
+	Docs    string // Where migrating is explained
+}
+
+// Flags lists every known flag
+var Flags = []Flag{
+	{
+		Name:        ParallelRestow,
+		Description: "Restow independent configs concurrently when syncing all configs",
+		Default:     true,
+		Docs:        docsBase + "commands.md#g4d-sync",
+	},
+}
+
+// Deprecations lists everything currently deprecated
+var Deprecations = []Deprecation{
+	{
+		ID:      DefaultsYes,
+		Message: "the defaults.yes preference is deprecated; set defaults.non_interactive instead",
+		Docs:    docsBase + "commands.md#user-preferences",
+	},
+}
+
+var (
+	mu       sync.Mutex
+	settings map[string]bool
+	warnFunc func(msg string)
+	warned   = make(map[string]bool)
+)
+
+// Configure sets the flags given in the user preferences and where
+// deprecation warnings are written. Names that aren't known flags are
+// ignored, so preferences naming a flag since removed keep working.
+func Configure(flags map[string]bool, warn func(msg string)) {
+	mu.Lock()
+	defer mu.Unlock()
+	settings = flags
+	warnFunc = warn
+	warned = make(map[string]bool)
+}
+
+// Find returns the flag called name, or nil
+func Find(name string) *Flag {
+	for i := range Flags {
+		if Flags[i].Name == name {
+			return &Flags[i]
+		}
+	}
+	return nil
+}
+
+// Enabled reports whether the flag called name is on: as set in the
+// preferences, else its default. Unknown flags are off.
+func Enabled(name string) bool {
+	f := Find(name)
+	if f == nil {
+		return false
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if on, ok := settings[name]; ok {
+		return on
+	}
+	return f.Default
+}
+
+// Unknown returns the names in flags that aren't known flags, sorted
+func Unknown(flags map[string]bool) []string {
+	var names []string
+	for name := range flags {
+		if Find(name) == nil {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Deprecated warns that the deprecation with the given ID applies, the
+// first time it is called for that ID. Nothing is written until Configure
+// sets where warnings go.
+func Deprecated(id string) {
+	var d *Deprecation
+	for i := range Deprecations {
+		if Deprecations[i].ID == id {
+			d = &Deprecations[i]
+		}
+	}
+	if d == nil {
+		return
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if warned[id] || warnFunc == nil {
+		return
+	}
+	warned[id] = true
+	warnFunc(fmt.Sprintf("%s (see %s)", d.Message, d.Docs))
+}
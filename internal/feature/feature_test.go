@@ -0,0 +1,70 @@
+package feature
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestEnabled(t *testing.T) {
+	t.Cleanup(func() { Configure(nil, nil) })
+
+	Configure(nil, nil)
+	if !Enabled(ParallelRestow) {
+		t.Error("parallel-restow should follow its default when unset")
+	}
+	if Enabled("no-such-flag") {
+		t.Error("unknown flags should be off")
+	}
+
+	Configure(map[string]bool{ParallelRestow: false, "no-such-flag": true}, nil)
+	if Enabled(ParallelRestow) {
+		t.Error("parallel-restow should be off when the preferences turn it off")
+	}
+	if Enabled("no-such-flag") {
+		t.Error("unknown flags should stay off when set")
+	}
+}
+
+func TestUnknown(t *testing.T) {
+	got := Unknown(map[string]bool{"zeta": true, ParallelRestow: true, "alpha": false})
+	if want := []string{"alpha", "zeta"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Unknown() = %v, want %v", got, want)
+	}
+}
+
+func TestDeprecated_WarnsOnce(t *testing.T) {
+	t.Cleanup(func() { Configure(nil, nil) })
+
+	// Before Configure there is nowhere to warn
+	Deprecated(DefaultsYes)
+
+	var warnings []string
+	Configure(nil, func(msg string) { warnings = append(warnings, msg) })
+	Deprecated(DefaultsYes)
+	Deprecated(DefaultsYes)
+	Deprecated("no-such-deprecation")
+
+	if len(warnings) != 1 {
+		t.Fatalf("got %d warnings, want 1: %v", len(warnings), warnings)
+	}
+	if !strings.Contains(warnings[0], "defaults.non_interactive") || !strings.Contains(warnings[0], "https://") {
+		t.Errorf("warning should name the replacement and link the docs: %q", warnings[0])
+	}
+}
+
+func TestRegistries(t *testing.T) {
+	seen := make(map[string]bool)
+	for _, f := range Flags {
+		if f.Name == "" || f.Description == "" || f.Docs == "" || seen[f.Name] {
+			t.Errorf("flag %+v is incomplete or duplicated", f)
+		}
+		seen[f.Name] = true
+	}
+	for _, d := range Deprecations {
+		if d.ID == "" || d.Message == "" || d.Docs == "" || seen[d.ID] {
+			t.Errorf("deprecation %+v is incomplete or duplicated", d)
+		}
+		seen[d.ID] = true
+	}
+}
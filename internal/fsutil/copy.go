@@ -0,0 +1,111 @@
+// Package fsutil copies files and directory trees the same way everywhere
+// go4dot does: regular files keep their permission bits exactly, whatever
+// the umask, and symlinks are copied as links, not followed.
+package fsutil
+
+import (
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// CopyFile copies the regular file src to dst, which must not exist yet,
+// with mode
+func CopyFile(src, dst string, mode fs.FileMode) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = in.Close() }()
+
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_EXCL, mode)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		_ = out.Close()
+		_ = os.Remove(dst)
+		return err
+	}
+	if err := out.Close(); err != nil {
+		_ = os.Remove(dst)
+		return err
+	}
+	// OpenFile's mode is masked by the umask
+	return os.Chmod(dst, mode)
+}
+
+// ReplaceFile copies src over dst atomically, keeping the mode of src.
+// dst may exist; it is only replaced once the copy is complete.
+func ReplaceFile(src, dst string) error {
+	info, err := os.Stat(src)
+	if err != nil {
+		return err
+	}
+
+	tmp := dst + ".g4d-tmp"
+	_ = os.Remove(tmp)
+	if err := CopyFile(src, tmp, info.Mode().Perm()); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, dst); err != nil {
+		_ = os.Remove(tmp)
+		return err
+	}
+	return nil
+}
+
+// CopyTree copies src, a file, symlink or directory, to dst, which must
+// not exist yet. Directories and files keep their modes and symlinks are
+// copied as they are. skipDir, when set, leaves out the directories below
+// src it returns true for. Sockets, pipes and devices are left out.
+func CopyTree(src, dst string, skipDir func(d fs.DirEntry) bool) error {
+	// Directories get their modes last, so read-only ones can be filled
+	type dir struct {
+		path string
+		mode fs.FileMode
+	}
+	var dirs []dir
+	err := filepath.WalkDir(src, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dst, rel)
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		switch {
+		case d.IsDir():
+			if rel != "." && skipDir != nil && skipDir(d) {
+				return filepath.SkipDir
+			}
+			dirs = append(dirs, dir{target, info.Mode().Perm()})
+			return os.MkdirAll(target, 0700)
+		case d.Type()&fs.ModeSymlink != 0:
+			link, err := os.Readlink(path)
+			if err != nil {
+				return err
+			}
+			return os.Symlink(link, target)
+		case d.Type().IsRegular():
+			return CopyFile(path, target, info.Mode().Perm())
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	for i := len(dirs) - 1; i >= 0; i-- {
+		if err := os.Chmod(dirs[i].path, dirs[i].mode); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,102 @@
+package fsutil
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestCopyFile_KeepsMode(t *testing.T) {
+	dir := t.TempDir()
+	src := filepath.Join(dir, "script.sh")
+	if err := os.WriteFile(src, []byte("#!/bin/sh\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	// The usual umask of 022 would drop the group and other write bits
+	dst := filepath.Join(dir, "copy.sh")
+	if err := CopyFile(src, dst, 0777); err != nil {
+		t.Fatalf("CopyFile() error = %v", err)
+	}
+	info, err := os.Stat(dst)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != 0777 {
+		t.Errorf("mode = %v, want 0777 whatever the umask", info.Mode().Perm())
+	}
+
+	if err := CopyFile(src, dst, 0755); err == nil {
+		t.Error("CopyFile() over an existing file: error = nil")
+	}
+}
+
+func TestReplaceFile(t *testing.T) {
+	dir := t.TempDir()
+	src := filepath.Join(dir, "src")
+	dst := filepath.Join(dir, "dst")
+	if err := os.WriteFile(src, []byte("new"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(dst, []byte("old"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := ReplaceFile(src, dst); err != nil {
+		t.Fatalf("ReplaceFile() error = %v", err)
+	}
+	data, err := os.ReadFile(dst)
+	if err != nil || string(data) != "new" {
+		t.Errorf("dst = %q, %v; want %q", data, err, "new")
+	}
+	if info, err := os.Stat(dst); err != nil || info.Mode().Perm() != 0600 {
+		t.Errorf("dst mode = %v, %v; want the source's 0600", info.Mode().Perm(), err)
+	}
+	if _, err := os.Stat(dst + ".g4d-tmp"); !os.IsNotExist(err) {
+		t.Error("the temporary copy was left behind")
+	}
+}
+
+func TestCopyTree(t *testing.T) {
+	src := filepath.Join(t.TempDir(), "src")
+	if err := os.MkdirAll(filepath.Join(src, "bin"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Join(src, ".git"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(src, "bin", "run"), []byte("#!/bin/sh\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(src, ".git", "HEAD"), []byte("ref"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink("bin/run", filepath.Join(src, "run")); err != nil {
+		t.Fatal(err)
+	}
+	// A read-only directory still gets its files
+	if err := os.Chmod(filepath.Join(src, "bin"), 0555); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = os.Chmod(filepath.Join(src, "bin"), 0755) })
+
+	dst := filepath.Join(t.TempDir(), "dst")
+	skipGit := func(d os.DirEntry) bool { return d.Name() == ".git" }
+	if err := CopyTree(src, dst, skipGit); err != nil {
+		t.Fatalf("CopyTree() error = %v", err)
+	}
+	t.Cleanup(func() { _ = os.Chmod(filepath.Join(dst, "bin"), 0755) })
+
+	if info, err := os.Stat(filepath.Join(dst, "bin", "run")); err != nil || info.Mode().Perm() != 0755 {
+		t.Errorf("bin/run = %v, %v; want it copied with 0755", info, err)
+	}
+	if info, err := os.Stat(filepath.Join(dst, "bin")); err != nil || info.Mode().Perm() != 0555 {
+		t.Errorf("bin = %v, %v; want it read-only like the source", info, err)
+	}
+	if link, err := os.Readlink(filepath.Join(dst, "run")); err != nil || link != "bin/run" {
+		t.Errorf("run = %q, %v; want the symlink copied as is", link, err)
+	}
+	if _, err := os.Stat(filepath.Join(dst, ".git")); !os.IsNotExist(err) {
+		t.Error(".git was copied")
+	}
+}
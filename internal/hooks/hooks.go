@@ -0,0 +1,188 @@
+// Package hooks runs the shell commands a repo asks for before and after
+// install and sync, such as reloading tmux or rebuilding the font cache
+// once a config is linked
+package hooks
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+
+	"github.com/nvandessel/go4dot/internal/config"
+	"github.com/nvandessel/go4dot/internal/transcript"
+)
+
+// Hook is one command to run at a stage
+type Hook struct {
+	Config  string // Config the hook belongs to; empty for the repo's top-level hooks
+	Stage   string // One of config.HookStages
+	Command string
+}
+
+// String describes the hook, e.g. "post_sync (tmux): tmux source ~/.tmux.conf"
+func (h Hook) String() string {
+	if h.Config == "" {
+		return fmt.Sprintf("%s: %s", h.Stage, h.Command)
+	}
+	return fmt.Sprintf("%s (%s): %s", h.Stage, h.Config, h.Command)
+}
+
+// Error is a hook that failed
+type Error struct {
+	Hook     Hook
+	ExitCode int // -1 when the command did not run or was stopped
+	Err      error
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("%s hook %q failed: %v", e.Hook.Stage, e.Hook.Command, e.Err)
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Options configures how hooks run
+type Options struct {
+	RepoRoot   string                    // Root of the dotfiles repo; top-level hooks run in it
+	TargetDir  string                    // Directory the configs are linked into
+	DryRun     bool                      // Report each hook to StartFunc without running it
+	StartFunc  func(h Hook)              // Called before each hook runs
+	OutputFunc func(h Hook, line string) // Called for each line of output as the hook writes it
+}
+
+// For returns the hooks of stage for the named configs in the order they
+// run. Before linking the top-level hooks run first, then each config's;
+// after linking each config's run first and the top-level hooks last.
+// Names of configs that don't exist are ignored.
+func For(cfg *config.Config, stage string, configNames []string) []Hook {
+	var top, perConfig []Hook
+	for _, command := range cfg.Hooks[stage] {
+		top = append(top, Hook{Stage: stage, Command: command})
+	}
+	for _, name := range configNames {
+		item := cfg.GetConfigByName(name)
+		if item == nil {
+			continue
+		}
+		for _, command := range item.Hooks[stage] {
+			perConfig = append(perConfig, Hook{Config: name, Stage: stage, Command: command})
+		}
+	}
+
+	if config.IsPreStage(stage) {
+		return append(top, perConfig...)
+	}
+	return append(perConfig, top...)
+}
+
+// Result is what running the hooks of a stage did
+type Result struct {
+	Ran    []Hook
+	Failed []*Error
+}
+
+// Err returns the failed hooks as one error, or nil
+func (r *Result) Err() error {
+	var errs []error
+	for _, f := range r.Failed {
+		errs = append(errs, f)
+	}
+	return errors.Join(errs...)
+}
+
+// Run runs the hooks of stage for the named configs with sh, one after
+// another. A config's hooks run in its directory with its variables in the
+// environment, top-level hooks in the repo root. Before linking, the first
+// hook to fail stops the rest, so the operation can stop too; after
+// linking every hook runs.
+func Run(ctx context.Context, cfg *config.Config, stage string, configNames []string, opts Options) *Result {
+	result := &Result{}
+	for _, h := range For(cfg, stage, configNames) {
+		if opts.StartFunc != nil {
+			opts.StartFunc(h)
+		}
+		if opts.DryRun {
+			continue
+		}
+		result.Ran = append(result.Ran, h)
+		if err := run(ctx, cfg, h, opts); err != nil {
+			result.Failed = append(result.Failed, err)
+			if config.IsPreStage(stage) {
+				break
+			}
+		}
+	}
+	return result
+}
+
+// run runs one hook, reporting its output as it is written
+func run(ctx context.Context, cfg *config.Config, h Hook, opts Options) *Error {
+	if err := ctx.Err(); err != nil {
+		return &Error{Hook: h, ExitCode: -1, Err: err}
+	}
+
+	cmd := exec.CommandContext(ctx, "sh", "-c", h.Command)
+	cmd.Dir = opts.RepoRoot
+	cmd.Env = append(os.Environ(),
+		config.EnvTargetDir+"="+opts.TargetDir,
+		config.EnvRepoRoot+"="+opts.RepoRoot,
+	)
+	if item := cfg.GetConfigByName(h.Config); item != nil {
+		cmd.Dir = item.Dir(opts.RepoRoot)
+		cmd.Env = append(os.Environ(), item.Env(opts.RepoRoot, opts.TargetDir)...)
+	}
+
+	w := &lineWriter{onLine: func(line string) {
+		if opts.OutputFunc != nil {
+			opts.OutputFunc(h, line)
+		}
+	}}
+	// One writer for both, so exec writes from a single goroutine at a time
+	cmd.Stdout = w
+	cmd.Stderr = w
+	err := transcript.Run(cmd)
+	w.flush()
+
+	var exitErr *exec.ExitError
+	switch {
+	case ctx.Err() != nil:
+		return &Error{Hook: h, ExitCode: -1, Err: ctx.Err()}
+	case errors.As(err, &exitErr):
+		return &Error{Hook: h, ExitCode: exitErr.ExitCode(), Err: fmt.Errorf("exited with %d", exitErr.ExitCode())}
+	case err != nil:
+		return &Error{Hook: h, ExitCode: -1, Err: err}
+	}
+	return nil
+}
+
+// lineWriter calls onLine for each line written to it
+type lineWriter struct {
+	line   []byte
+	onLine func(string)
+}
+
+func (w *lineWriter) Write(b []byte) (int, error) {
+	n := len(b)
+	for len(b) > 0 {
+		i := bytes.IndexByte(b, '\n')
+		if i < 0 {
+			w.line = append(w.line, b...)
+			break
+		}
+		w.onLine(string(append(w.line, b[:i]...)))
+		w.line = w.line[:0]
+		b = b[i+1:]
+	}
+	return n, nil
+}
+
+func (w *lineWriter) flush() {
+	if len(w.line) > 0 {
+		w.onLine(string(w.line))
+		w.line = w.line[:0]
+	}
+}
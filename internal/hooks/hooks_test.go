@@ -0,0 +1,126 @@
+package hooks
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/nvandessel/go4dot/internal/config"
+)
+
+func hooksTestConfig() *config.Config {
+	return &config.Config{
+		Hooks: config.Hooks{
+			config.HookPreSync:  {"echo top-pre"},
+			config.HookPostSync: {"echo top-post"},
+		},
+		Configs: config.ConfigGroups{
+			Core: []config.ConfigItem{
+				{Name: "tmux", Path: "tmux", Hooks: config.Hooks{
+					config.HookPreSync:  {"echo tmux-pre"},
+					config.HookPostSync: {"echo reloaded $G4D_CONFIG_NAME from $(basename $PWD)"},
+				}},
+				{Name: "git", Path: "git"},
+			},
+		},
+	}
+}
+
+func commands(hooks []Hook) []string {
+	var out []string
+	for _, h := range hooks {
+		out = append(out, h.Command)
+	}
+	return out
+}
+
+func TestFor(t *testing.T) {
+	cfg := hooksTestConfig()
+
+	tests := []struct {
+		name    string
+		stage   string
+		configs []string
+		want    []string
+	}{
+		{"pre runs top-level first", config.HookPreSync, []string{"tmux", "git"}, []string{"echo top-pre", "echo tmux-pre"}},
+		{"post runs top-level last", config.HookPostSync, []string{"tmux"}, []string{"echo reloaded $G4D_CONFIG_NAME from $(basename $PWD)", "echo top-post"}},
+		{"configs not covered", config.HookPreSync, []string{"git", "missing"}, []string{"echo top-pre"}},
+		{"stage without hooks", config.HookPreInstall, []string{"tmux"}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := commands(For(cfg, tt.stage, tt.configs)); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("For() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRun(t *testing.T) {
+	repo := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(repo, "tmux"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	var started []string
+	var output []string
+	result := Run(context.Background(), hooksTestConfig(), config.HookPostSync, []string{"tmux"}, Options{
+		RepoRoot:   repo,
+		TargetDir:  t.TempDir(),
+		StartFunc:  func(h Hook) { started = append(started, h.Config) },
+		OutputFunc: func(h Hook, line string) { output = append(output, line) },
+	})
+
+	if len(result.Failed) > 0 {
+		t.Fatalf("Run() failed: %v", result.Err())
+	}
+	if want := []string{"tmux", ""}; !reflect.DeepEqual(started, want) {
+		t.Errorf("started = %q, want %q", started, want)
+	}
+	// A config's hooks run in its directory with its variables set
+	if want := []string{"reloaded tmux from tmux", "top-post"}; !reflect.DeepEqual(output, want) {
+		t.Errorf("output = %q, want %q", output, want)
+	}
+}
+
+func TestRun_Failures(t *testing.T) {
+	cfg := &config.Config{Hooks: config.Hooks{
+		config.HookPreSync:  {"exit 3", "echo not reached"},
+		config.HookPostSync: {"exit 3", "echo still runs"},
+	}}
+	opts := Options{RepoRoot: t.TempDir()}
+
+	pre := Run(context.Background(), cfg, config.HookPreSync, nil, opts)
+	if len(pre.Ran) != 1 || len(pre.Failed) != 1 || pre.Failed[0].ExitCode != 3 {
+		t.Errorf("pre_sync: ran %d, failed %v; want the first hook to fail and stop the rest", len(pre.Ran), pre.Failed)
+	}
+
+	post := Run(context.Background(), cfg, config.HookPostSync, nil, opts)
+	if len(post.Ran) != 2 || len(post.Failed) != 1 {
+		t.Errorf("post_sync: ran %d, failed %d; want both hooks to run", len(post.Ran), len(post.Failed))
+	}
+	if post.Err() == nil {
+		t.Error("Err() = nil, want the failed hook")
+	}
+}
+
+func TestRun_DryRun(t *testing.T) {
+	cfg := &config.Config{Hooks: config.Hooks{config.HookPostSync: {"touch ran"}}}
+	repo := t.TempDir()
+
+	var started int
+	result := Run(context.Background(), cfg, config.HookPostSync, nil, Options{
+		RepoRoot:  repo,
+		DryRun:    true,
+		StartFunc: func(Hook) { started++ },
+	})
+	if started != 1 || len(result.Ran) != 0 {
+		t.Errorf("dry run reported %d hooks and ran %d, want 1 and 0", started, len(result.Ran))
+	}
+	if _, err := os.Stat(filepath.Join(repo, "ran")); !os.IsNotExist(err) {
+		t.Error("dry run ran the hook")
+	}
+}
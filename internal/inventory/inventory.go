@@ -0,0 +1,174 @@
+// Package inventory keeps a record of every machine a dotfiles repo is synced
+// on, committed to the repo's machines/ directory.
+package inventory
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/nvandessel/go4dot/internal/config"
+	"github.com/nvandessel/go4dot/internal/platform"
+	"github.com/nvandessel/go4dot/internal/version"
+	"gopkg.in/yaml.v3"
+)
+
+// DirName is the directory in the repo that machine records are written to
+const DirName = "machines"
+
+// DefaultStaleDays is how long a machine may go without a sync before it
+// is stale, unless the config sets inventory.stale_days
+const DefaultStaleDays = 30
+
+// Record describes a machine the repo is synced on
+type Record struct {
+	Hostname       string    `yaml:"hostname" json:"hostname"`
+	Profile        string    `yaml:"profile,omitempty" json:"profile,omitempty"` // Matching entry of the config's machines
+	OS             string    `yaml:"os" json:"os"`
+	Distro         string    `yaml:"distro,omitempty" json:"distro,omitempty"`
+	Arch           string    `yaml:"arch,omitempty" json:"arch,omitempty"`
+	PackageManager string    `yaml:"package_manager,omitempty" json:"package_manager,omitempty"`
+	Version        string    `yaml:"go4dot_version" json:"go4dot_version"`
+	LastSync       time.Time `yaml:"last_sync" json:"last_sync"`
+}
+
+// NewRecord returns the record for the machine described by p, synced now
+func NewRecord(cfg *config.Config, p *platform.Platform, now time.Time) Record {
+	r := Record{
+		Hostname:       p.Hostname,
+		OS:             p.OS,
+		Distro:         p.Distro,
+		Arch:           p.Architecture,
+		PackageManager: p.PackageManager,
+		Version:        version.GetToolVersion(),
+		LastSync:       now.UTC().Truncate(time.Second),
+	}
+	if profile := cfg.GetMachineProfile(p.Hostname); profile != nil {
+		r.Profile = profile.Name
+	}
+	return r
+}
+
+// Update records a sync of this machine in the repo at repoRoot, when the
+// config enables the inventory
+func Update(cfg *config.Config, repoRoot string, p *platform.Platform) error {
+	if !cfg.Inventory.Enabled {
+		return nil
+	}
+	if p == nil || p.Hostname == "" {
+		return fmt.Errorf("cannot record machine: hostname unknown")
+	}
+	return Save(repoRoot, NewRecord(cfg, p, time.Now()))
+}
+
+// Path returns the file a machine's record is stored in
+func Path(repoRoot, hostname string) string {
+	name := strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '.', r == '_', r == '-':
+			return r
+		}
+		return '_'
+	}, hostname)
+	return filepath.Join(repoRoot, DirName, name+".yaml")
+}
+
+// Save writes r to the repo at repoRoot, replacing the machine's previous
+// record
+func Save(repoRoot string, r Record) error {
+	path := Path(repoRoot, r.Hostname)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create %s: %w", filepath.Dir(path), err)
+	}
+
+	data, err := yaml.Marshal(r)
+	if err != nil {
+		return fmt.Errorf("failed to marshal machine record: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write machine record: %w", err)
+	}
+	return nil
+}
+
+// Load reads every machine record in the repo at repoRoot, sorted by
+// hostname. A repo without records yields none.
+func Load(repoRoot string) ([]Record, error) {
+	paths, err := filepath.Glob(filepath.Join(repoRoot, DirName, "*.yaml"))
+	if err != nil {
+		return nil, err
+	}
+
+	var records []Record
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read machine record: %w", err)
+		}
+		var r Record
+		if err := yaml.Unmarshal(data, &r); err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+		}
+		records = append(records, r)
+	}
+
+	sort.Slice(records, func(i, j int) bool {
+		return records[i].Hostname < records[j].Hostname
+	})
+	return records, nil
+}
+
+// Machine is a record with its assessment
+type Machine struct {
+	Record
+	Current  bool `json:"current"`  // The machine go4dot is running on
+	Stale    bool `json:"stale"`    // Not synced within the stale period
+	Outdated bool `json:"outdated"` // Running an older go4dot than the newest known version
+}
+
+// Assess marks which records are stale at now and which run an older
+// go4dot than the newest version among them and toolVersion, the running
+// one. hostname is the machine go4dot is running on. Development builds are
+// never outdated.
+func Assess(records []Record, now time.Time, staleAfter time.Duration, hostname, toolVersion string) []Machine {
+	var newest *version.SemVer
+	consider := func(v string) {
+		sv, err := version.ParseSemVer(v)
+		if err != nil {
+			return
+		}
+		if newest == nil || sv.IsNewerThan(*newest) {
+			newest = &sv
+		}
+	}
+	consider(toolVersion)
+	for _, r := range records {
+		consider(r.Version)
+	}
+
+	machines := make([]Machine, 0, len(records))
+	for _, r := range records {
+		m := Machine{
+			Record:  r,
+			Current: r.Hostname == hostname,
+			Stale:   now.Sub(r.LastSync) > staleAfter,
+		}
+		if sv, err := version.ParseSemVer(r.Version); err == nil && newest != nil {
+			m.Outdated = sv.IsOlderThan(*newest)
+		}
+		machines = append(machines, m)
+	}
+	return machines
+}
+
+// StaleAfter returns how long a machine may go without a sync under cfg
+func StaleAfter(cfg *config.Config) time.Duration {
+	days := cfg.Inventory.StaleDays
+	if days <= 0 {
+		days = DefaultStaleDays
+	}
+	return time.Duration(days) * 24 * time.Hour
+}
@@ -0,0 +1,108 @@
+package inventory
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/nvandessel/go4dot/internal/config"
+	"github.com/nvandessel/go4dot/internal/platform"
+)
+
+func TestSaveLoad(t *testing.T) {
+	repo := t.TempDir()
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	cfg := &config.Config{Machines: []config.MachineProfile{{Name: "work", Hostname: "laptop,laptop-2"}}}
+
+	for _, host := range []string{"laptop", "desktop"} {
+		r := NewRecord(cfg, &platform.Platform{OS: "linux", Distro: "fedora", Hostname: host}, now)
+		if err := Save(repo, r); err != nil {
+			t.Fatalf("Save(%s) failed: %v", host, err)
+		}
+	}
+
+	records, err := Load(repo)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if len(records) != 2 {
+		t.Fatalf("got %d records, want 2", len(records))
+	}
+	if records[0].Hostname != "desktop" || records[1].Hostname != "laptop" {
+		t.Errorf("records not sorted by hostname: %+v", records)
+	}
+	if records[1].Profile != "work" || records[0].Profile != "" {
+		t.Errorf("profiles = %q, %q; want work for laptop only", records[1].Profile, records[0].Profile)
+	}
+	if !records[1].LastSync.Equal(now) {
+		t.Errorf("LastSync = %v, want %v", records[1].LastSync, now)
+	}
+}
+
+func TestLoadEmpty(t *testing.T) {
+	records, err := Load(t.TempDir())
+	if err != nil || len(records) != 0 {
+		t.Errorf("Load() = %v, %v; want no records", records, err)
+	}
+}
+
+func TestPath(t *testing.T) {
+	if got, want := Path("/repo", "my host/x"), filepath.Join("/repo", "machines", "my_host_x.yaml"); got != want {
+		t.Errorf("Path() = %q, want %q", got, want)
+	}
+}
+
+func TestUpdate(t *testing.T) {
+	repo := t.TempDir()
+	p := &platform.Platform{OS: "linux", Hostname: "box"}
+
+	if err := Update(&config.Config{}, repo, p); err != nil {
+		t.Fatalf("Update failed: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(repo, DirName)); !os.IsNotExist(err) {
+		t.Error("expected nothing to be written when the inventory is disabled")
+	}
+
+	cfg := &config.Config{Inventory: config.Inventory{Enabled: true}}
+	if err := Update(cfg, repo, p); err != nil {
+		t.Fatalf("Update failed: %v", err)
+	}
+	if _, err := os.Stat(Path(repo, "box")); err != nil {
+		t.Errorf("expected a record for box: %v", err)
+	}
+}
+
+func TestAssess(t *testing.T) {
+	now := time.Date(2026, 3, 31, 0, 0, 0, 0, time.UTC)
+	records := []Record{
+		{Hostname: "a", Version: "1.4.0", LastSync: now.Add(-24 * time.Hour)},
+		{Hostname: "b", Version: "1.2.0", LastSync: now.Add(-40 * 24 * time.Hour)},
+		{Hostname: "c", Version: "dev", LastSync: now},
+	}
+
+	machines := Assess(records, now, 30*24*time.Hour, "a", "1.3.0")
+
+	want := []struct{ current, stale, outdated bool }{
+		{true, false, false},
+		{false, true, true},
+		{false, false, false},
+	}
+	for i, w := range want {
+		m := machines[i]
+		if m.Current != w.current || m.Stale != w.stale || m.Outdated != w.outdated {
+			t.Errorf("%s: current=%v stale=%v outdated=%v, want %v %v %v",
+				m.Hostname, m.Current, m.Stale, m.Outdated, w.current, w.stale, w.outdated)
+		}
+	}
+}
+
+func TestStaleAfter(t *testing.T) {
+	if got := StaleAfter(&config.Config{}); got != DefaultStaleDays*24*time.Hour {
+		t.Errorf("default StaleAfter = %v", got)
+	}
+	cfg := &config.Config{Inventory: config.Inventory{StaleDays: 7}}
+	if got := StaleAfter(cfg); got != 7*24*time.Hour {
+		t.Errorf("StaleAfter = %v, want 7 days", got)
+	}
+}
@@ -0,0 +1,206 @@
+// Package lint checks the files of a dotfiles repo's configs for problems
+// worth catching before they are committed: YAML that doesn't parse and
+// personal data such as emails, tokens and home paths.
+package lint
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/nvandessel/go4dot/internal/config"
+	"github.com/nvandessel/go4dot/internal/doctor"
+	"github.com/nvandessel/go4dot/internal/stow"
+	"github.com/nvandessel/go4dot/internal/vcs"
+	"gopkg.in/yaml.v3"
+)
+
+// Checks that can produce a Finding
+const (
+	CheckSyntax       = "syntax"
+	CheckPersonalData = "personal-data"
+)
+
+// File is a config file to lint
+type File struct {
+	Config string // Name of the config owning the file
+	Path   string // Relative to the config directory
+	Data   []byte
+}
+
+// Finding is a problem in a config file
+type Finding struct {
+	Config  string `json:"config"`
+	Path    string `json:"path"` // Relative to the config directory
+	Line    int    `json:"line"` // 0 when not known
+	Check   string `json:"check"`
+	Message string `json:"message"`
+}
+
+// yamlLine extracts the line number from a yaml.v3 error message
+var yamlLine = regexp.MustCompile(`^yaml: line (\d+): `)
+
+// Files returns every file of cfg's configs, skipping the paths stow or
+// .go4dotignore ignore
+func Files(cfg *config.Config, dotfilesPath string) ([]File, error) {
+	repoIgnore, _ := config.LoadRepoIgnore(dotfilesPath)
+
+	var files []File
+	for _, item := range cfg.GetAllConfigs() {
+		configDir := item.Dir(dotfilesPath)
+		if _, err := os.Stat(configDir); err != nil {
+			continue
+		}
+		ignore, err := stow.LoadIgnoreList(configDir)
+		if err != nil {
+			return nil, fmt.Errorf("config '%s': %w", item.Name, err)
+		}
+
+		_ = filepath.WalkDir(configDir, func(path string, d fs.DirEntry, err error) error {
+			if err != nil || path == configDir {
+				return nil
+			}
+			relPath, _ := filepath.Rel(configDir, path)
+			if ignore.Match(relPath) || repoIgnore.Match(filepath.Join(item.Path, relPath), d.IsDir()) {
+				if d.IsDir() {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			if !d.Type().IsRegular() {
+				return nil
+			}
+			data, err := os.ReadFile(path)
+			if err != nil {
+				return nil // Skip unreadable files
+			}
+			files = append(files, File{Config: item.Name, Path: relPath, Data: data})
+			return nil
+		})
+	}
+	return files, nil
+}
+
+// StagedFiles returns the files of cfg's configs staged for the next git
+// commit, with their staged contents. Files outside every config, and
+// those stow or .go4dotignore ignore, are left out.
+func StagedFiles(cfg *config.Config, dotfilesPath string) ([]File, error) {
+	var git vcs.Git
+	staged, err := git.Staged(dotfilesPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list staged files: %w", err)
+	}
+	repoIgnore, _ := config.LoadRepoIgnore(dotfilesPath)
+
+	ignoreLists := make(map[string]*stow.IgnoreList)
+	var files []File
+	for _, path := range staged {
+		item, relPath := owningConfig(cfg, path)
+		if item == nil || repoIgnore.Match(path, false) {
+			continue
+		}
+		ignore, ok := ignoreLists[item.Name]
+		if !ok {
+			if ignore, err = stow.LoadIgnoreList(item.Dir(dotfilesPath)); err != nil {
+				return nil, fmt.Errorf("config '%s': %w", item.Name, err)
+			}
+			ignoreLists[item.Name] = ignore
+		}
+		if ignoredPath(ignore, relPath) {
+			continue
+		}
+
+		data, err := git.StagedFile(dotfilesPath, path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read staged %s: %w", path, err)
+		}
+		files = append(files, File{Config: item.Name, Path: relPath, Data: data})
+	}
+	return files, nil
+}
+
+// owningConfig returns the config whose directory contains path, relative
+// to the dotfiles directory, and path relative to that directory
+func owningConfig(cfg *config.Config, path string) (*config.ConfigItem, string) {
+	configs := cfg.GetAllConfigs()
+	for i := range configs {
+		prefix := filepath.Clean(configs[i].Path) + string(filepath.Separator)
+		if strings.HasPrefix(path, prefix) {
+			return &configs[i], strings.TrimPrefix(path, prefix)
+		}
+	}
+	return nil, ""
+}
+
+// ignoredPath reports whether stow ignores relPath or any directory above it
+func ignoredPath(ignore *stow.IgnoreList, relPath string) bool {
+	for p := relPath; p != "." && p != string(filepath.Separator); p = filepath.Dir(p) {
+		if ignore.Match(p) {
+			return true
+		}
+	}
+	return false
+}
+
+// Run lints files and returns the findings ordered by config, path and line
+func Run(cfg *config.Config, files []File) []Finding {
+	var findings []Finding
+	for _, f := range files {
+		if ext := strings.ToLower(filepath.Ext(f.Path)); ext == ".yaml" || ext == ".yml" {
+			if finding, ok := checkYAML(f); !ok {
+				findings = append(findings, finding)
+			}
+		}
+		for _, p := range doctor.ScanPersonalData(cfg, f.Data) {
+			findings = append(findings, Finding{
+				Config:  f.Config,
+				Path:    f.Path,
+				Line:    p.Line,
+				Check:   CheckPersonalData,
+				Message: fmt.Sprintf("%s %s", p.Kind, p.Value),
+			})
+		}
+	}
+
+	sort.SliceStable(findings, func(i, j int) bool {
+		a, b := findings[i], findings[j]
+		if a.Config != b.Config {
+			return a.Config < b.Config
+		}
+		if a.Path != b.Path {
+			return a.Path < b.Path
+		}
+		return a.Line < b.Line
+	})
+	return findings
+}
+
+// checkYAML parses every document in a YAML file
+func checkYAML(f File) (Finding, bool) {
+	dec := yaml.NewDecoder(bytes.NewReader(f.Data))
+	for {
+		var node yaml.Node
+		err := dec.Decode(&node)
+		if err == nil {
+			continue
+		}
+		if errors.Is(err, io.EOF) {
+			return Finding{}, true
+		}
+
+		finding := Finding{Config: f.Config, Path: f.Path, Check: CheckSyntax, Message: err.Error()}
+		if m := yamlLine.FindStringSubmatch(err.Error()); m != nil {
+			finding.Line, _ = strconv.Atoi(m[1])
+			finding.Message = "invalid YAML: " + strings.TrimPrefix(err.Error(), m[0])
+		}
+		return finding, false
+	}
+}
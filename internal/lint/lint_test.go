@@ -0,0 +1,95 @@
+package lint
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/nvandessel/go4dot/internal/config"
+)
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func testConfig() *config.Config {
+	return &config.Config{
+		Configs: config.ConfigGroups{Core: []config.ConfigItem{
+			{Name: "git", Path: "git"},
+			{Name: "tool", Path: "tool"},
+		}},
+	}
+}
+
+func TestRun(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	dotfiles := t.TempDir()
+	writeFile(t, filepath.Join(dotfiles, "git", ".gitconfig"), "[user]\n\temail = jane@corp.io\n")
+	writeFile(t, filepath.Join(dotfiles, "tool", ".config", "tool", "config.yaml"), "theme: dark\nkeys:\n  - a\n - b\n")
+	writeFile(t, filepath.Join(dotfiles, "tool", ".config", "tool", "ok.yml"), "a: 1\n---\nb: 2\n")
+	writeFile(t, filepath.Join(dotfiles, "tool", "README.md"), "Mail bugs to jane@corp.io\n")
+
+	cfg := testConfig()
+	files, err := Files(cfg, dotfiles)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 3 {
+		t.Errorf("Files() = %d files, want 3 without the README", len(files))
+	}
+
+	findings := Run(cfg, files)
+	if len(findings) != 2 {
+		t.Fatalf("Run() = %+v, want 2 findings", findings)
+	}
+	if f := findings[0]; f.Config != "git" || f.Check != CheckPersonalData || f.Line != 2 {
+		t.Errorf("findings[0] = %+v", f)
+	}
+	if f := findings[1]; f.Config != "tool" || f.Check != CheckSyntax || f.Line == 0 {
+		t.Errorf("findings[1] = %+v", f)
+	}
+}
+
+func TestStagedFiles(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	t.Setenv("HOME", t.TempDir())
+	dotfiles := t.TempDir()
+	git := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dotfiles
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v failed: %v\n%s", args, err, out)
+		}
+	}
+
+	git("init", "-q")
+	writeFile(t, filepath.Join(dotfiles, "git", ".gitconfig"), "[user]\n\temail = jane@corp.io\n")
+	writeFile(t, filepath.Join(dotfiles, "tool", "config.yaml"), "a: 1\n")
+	writeFile(t, filepath.Join(dotfiles, "notes.txt"), "jane@corp.io\n")
+	git("add", "git", "notes.txt")
+
+	files, err := StagedFiles(testConfig(), dotfiles)
+	if err != nil {
+		t.Fatalf("StagedFiles() error = %v", err)
+	}
+	if len(files) != 1 || files[0].Config != "git" || files[0].Path != ".gitconfig" {
+		t.Fatalf("StagedFiles() = %+v, want git's .gitconfig only", files)
+	}
+
+	// The staged contents are linted, not the working tree's
+	writeFile(t, filepath.Join(dotfiles, "git", ".gitconfig"), "[user]\n\temail = jane@example.com\n")
+	files, _ = StagedFiles(testConfig(), dotfiles)
+	if findings := Run(testConfig(), files); len(findings) != 1 {
+		t.Errorf("Run() = %+v, want the staged email", findings)
+	}
+}
@@ -88,7 +88,7 @@ func CollectSingleConfig(cfg *config.Config, id string, opts PromptOptions) (*Pr
 	}
 
 	if found == nil {
-		return nil, fmt.Errorf("machine config '%s' not found", id)
+		return nil, cfg.UnknownMachinePromptError(id)
 	}
 
 	result, err := collectPrompts(*found, opts)
@@ -9,6 +9,8 @@ import (
 	"text/template"
 
 	"github.com/nvandessel/go4dot/internal/config"
+	"github.com/nvandessel/go4dot/internal/snapshot"
+	"github.com/nvandessel/go4dot/internal/trash"
 	"github.com/nvandessel/go4dot/internal/validation"
 )
 
@@ -23,6 +25,7 @@ type RenderResult struct {
 type RenderOptions struct {
 	DryRun       bool                                 // Don't write files, just return content
 	Overwrite    bool                                 // Overwrite existing files
+	UseTrash     bool                                 // Move removed files to the OS trash instead of deleting
 	ProgressFunc func(current, total int, msg string) // Called for progress updates with item counts
 }
 
@@ -83,7 +86,10 @@ func RenderAndWrite(mc *config.MachinePrompt, values map[string]string, opts Ren
 		return nil, fmt.Errorf("failed to create directory %s: %w", parentDir, err)
 	}
 
-	// Write the file
+	// Write the file, keeping the one it replaces in the snapshot being taken
+	if err := snapshot.Save(result.Destination); err != nil {
+		return nil, err
+	}
 	if err := os.WriteFile(result.Destination, []byte(result.Content), 0600); err != nil {
 		return nil, fmt.Errorf("failed to write file: %w", err)
 	}
@@ -102,7 +108,7 @@ func RenderAll(cfg *config.Config, results []PromptResult, opts RenderOptions) (
 	for _, pr := range results {
 		mc := GetMachineConfigByID(cfg, pr.ID)
 		if mc == nil {
-			return nil, fmt.Errorf("machine config '%s' not found", pr.ID)
+			return nil, cfg.UnknownMachinePromptError(pr.ID)
 		}
 
 		result, err := RenderAndWrite(mc, pr.Values, opts)
@@ -182,7 +188,15 @@ func RemoveMachineConfig(mc *config.MachinePrompt, opts RenderOptions) error {
 		return nil
 	}
 
-	if err := os.Remove(dest); err != nil {
+	if err := snapshot.Save(dest); err != nil {
+		return err
+	}
+	if opts.UseTrash {
+		err = trash.Remove(dest, true)
+	} else {
+		err = os.Remove(dest)
+	}
+	if err != nil {
 		return fmt.Errorf("failed to remove file: %w", err)
 	}
 
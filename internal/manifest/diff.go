@@ -0,0 +1,135 @@
+package manifest
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/nvandessel/go4dot/internal/config"
+)
+
+// Diff lists how b differs from a, in the form of a config diff: the
+// machine and repo fields that differ, then the symlinks (by target),
+// dependencies, externals and machine configs added, removed or changed.
+// The time a manifest was generated is ignored.
+func Diff(a, b *Manifest) config.ConfigDiff {
+	var diff config.ConfigDiff
+	for _, f := range []struct{ name, from, to string }{
+		{"hostname", a.Machine.Hostname, b.Machine.Hostname},
+		{"os", a.Machine.OS, b.Machine.OS},
+		{"distro", a.Machine.Distro, b.Machine.Distro},
+		{"distro_version", a.Machine.DistroVersion, b.Machine.DistroVersion},
+		{"arch", a.Machine.Arch, b.Machine.Arch},
+		{"package_manager", a.Machine.PackageManager, b.Machine.PackageManager},
+		{"go4dot_version", a.Go4dotVersion, b.Go4dotVersion},
+		{"repo_revision", a.Repo.Revision, b.Repo.Revision},
+	} {
+		if f.from != f.to {
+			diff = append(diff, config.Change{
+				Section: "machine",
+				Name:    f.name,
+				Kind:    config.ChangeChanged,
+				Details: []string{fmt.Sprintf("%s → %s", orNone(abbreviate(f.name, f.from)), orNone(abbreviate(f.name, f.to)))},
+			})
+		}
+	}
+
+	var oldLinks, newLinks []keyed
+	for _, s := range a.Symlinks {
+		oldLinks = append(oldLinks, keyed{s.Target, []field{{"source", s.Source}, {"config", s.Config}}})
+	}
+	for _, s := range b.Symlinks {
+		newLinks = append(newLinks, keyed{s.Target, []field{{"source", s.Source}, {"config", s.Config}}})
+	}
+	diff = append(diff, compare("symlink", oldLinks, newLinks)...)
+
+	var oldDeps, newDeps []keyed
+	for _, d := range a.Dependencies {
+		oldDeps = append(oldDeps, keyed{d.Name, []field{{"status", d.Status}, {"version", d.Version}}})
+	}
+	for _, d := range b.Dependencies {
+		newDeps = append(newDeps, keyed{d.Name, []field{{"status", d.Status}, {"version", d.Version}}})
+	}
+	diff = append(diff, compare("dependency", oldDeps, newDeps)...)
+
+	var oldExt, newExt []keyed
+	for _, e := range a.External {
+		oldExt = append(oldExt, keyed{e.ID, []field{{"url", e.URL}, {"status", e.Status}, {"revision", e.Revision}}})
+	}
+	for _, e := range b.External {
+		newExt = append(newExt, keyed{e.ID, []field{{"url", e.URL}, {"status", e.Status}, {"revision", e.Revision}}})
+	}
+	diff = append(diff, compare("external", oldExt, newExt)...)
+
+	var oldMachine, newMachine []keyed
+	for _, mc := range a.MachineConfigs {
+		oldMachine = append(oldMachine, keyed{mc.ID, []field{{"status", mc.Status}, {"sha256", mc.SHA256}}})
+	}
+	for _, mc := range b.MachineConfigs {
+		newMachine = append(newMachine, keyed{mc.ID, []field{{"status", mc.Status}, {"sha256", mc.SHA256}}})
+	}
+	diff = append(diff, compare("machine config", oldMachine, newMachine)...)
+
+	return diff
+}
+
+// keyed is a manifest entry reduced to its key and compared fields
+type keyed struct {
+	key    string
+	fields []field
+}
+
+type field struct {
+	name, value string
+}
+
+// compare matches entries by key, reporting changed fields as
+// "field: old → new"
+func compare(section string, old, new []keyed) []config.Change {
+	oldByKey := make(map[string]keyed, len(old))
+	for _, e := range old {
+		oldByKey[e.key] = e
+	}
+	inNew := make(map[string]bool, len(new))
+
+	var changes []config.Change
+	for _, e := range new {
+		inNew[e.key] = true
+		prev, ok := oldByKey[e.key]
+		if !ok {
+			changes = append(changes, config.Change{Section: section, Name: e.key, Kind: config.ChangeAdded})
+			continue
+		}
+		var details []string
+		for i, f := range e.fields {
+			if from := prev.fields[i].value; from != f.value {
+				details = append(details, fmt.Sprintf("%s: %s → %s", f.name, orNone(abbreviate(f.name, from)), orNone(abbreviate(f.name, f.value))))
+			}
+		}
+		if len(details) > 0 {
+			changes = append(changes, config.Change{Section: section, Name: e.key, Kind: config.ChangeChanged, Details: details})
+		}
+	}
+	for _, e := range old {
+		if !inNew[e.key] {
+			changes = append(changes, config.Change{Section: section, Name: e.key, Kind: config.ChangeRemoved})
+			inNew[e.key] = true
+		}
+	}
+	return changes
+}
+
+// abbreviate shortens hashes to 12 characters, as commit IDs are usually
+// shown
+func abbreviate(name, value string) string {
+	if (name == "sha256" || strings.HasSuffix(name, "revision")) && len(value) >= 40 {
+		return value[:12]
+	}
+	return value
+}
+
+func orNone(s string) string {
+	if s == "" {
+		return "(none)"
+	}
+	return s
+}
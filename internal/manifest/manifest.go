@@ -0,0 +1,248 @@
+// Package manifest describes everything go4dot manages on a machine, from
+// each symlink to the revision of each external, in one document that can
+// be archived or compared with another machine's.
+package manifest
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/nvandessel/go4dot/internal/config"
+	"github.com/nvandessel/go4dot/internal/deps"
+	"github.com/nvandessel/go4dot/internal/machine"
+	"github.com/nvandessel/go4dot/internal/platform"
+	"github.com/nvandessel/go4dot/internal/stow"
+	"github.com/nvandessel/go4dot/internal/vcs"
+	"github.com/nvandessel/go4dot/internal/version"
+	"gopkg.in/yaml.v3"
+)
+
+// FormatVersion is the version of the manifest format
+const FormatVersion = "1"
+
+// Output formats of Encode
+const (
+	FormatYAML = "yaml"
+	FormatJSON = "json"
+)
+
+// Manifest is everything go4dot manages on one machine. Paths under the
+// home directory start with ~/ and paths in the dotfiles repo with
+// @repoRoot/, so manifests of two machines compare.
+type Manifest struct {
+	Version        string          `yaml:"version" json:"version"`
+	GeneratedAt    time.Time       `yaml:"generated_at" json:"generated_at"`
+	Go4dotVersion  string          `yaml:"go4dot_version" json:"go4dot_version"`
+	Machine        Machine         `yaml:"machine" json:"machine"`
+	Repo           Repo            `yaml:"repo" json:"repo"`
+	Symlinks       []Symlink       `yaml:"symlinks" json:"symlinks"`
+	Dependencies   []Dependency    `yaml:"dependencies" json:"dependencies"`
+	External       []External      `yaml:"external" json:"external"`
+	MachineConfigs []MachineConfig `yaml:"machine_configs" json:"machine_configs"`
+}
+
+// Machine is the platform the manifest was generated on
+type Machine struct {
+	Hostname       string `yaml:"hostname" json:"hostname"`
+	OS             string `yaml:"os" json:"os"`
+	Distro         string `yaml:"distro,omitempty" json:"distro,omitempty"`
+	DistroVersion  string `yaml:"distro_version,omitempty" json:"distro_version,omitempty"`
+	Arch           string `yaml:"arch" json:"arch"`
+	PackageManager string `yaml:"package_manager,omitempty" json:"package_manager,omitempty"`
+}
+
+// Repo is the dotfiles repo and the revision checked out
+type Repo struct {
+	Path     string `yaml:"path" json:"path"`
+	Revision string `yaml:"revision,omitempty" json:"revision,omitempty"`
+}
+
+// Symlink is a link in place, of a config's file or from the links section
+type Symlink struct {
+	Config string `yaml:"config,omitempty" json:"config,omitempty"` // Empty for the links section
+	Source string `yaml:"source" json:"source"`
+	Target string `yaml:"target" json:"target"`
+}
+
+// Dependency is a system package and what was found of it
+type Dependency struct {
+	Name    string `yaml:"name" json:"name"`
+	Status  string `yaml:"status" json:"status"` // As deps.Check reports it, e.g. installed or missing
+	Version string `yaml:"version,omitempty" json:"version,omitempty"`
+	Path    string `yaml:"path,omitempty" json:"path,omitempty"`
+}
+
+// External is an external dependency and the revision installed
+type External struct {
+	ID       string `yaml:"id" json:"id"`
+	URL      string `yaml:"url" json:"url"`
+	Path     string `yaml:"path,omitempty" json:"path,omitempty"`
+	Status   string `yaml:"status" json:"status"`                         // installed, missing, skipped or error
+	Revision string `yaml:"revision,omitempty" json:"revision,omitempty"` // Commit SHA of a clone, or the tag of a release
+}
+
+// MachineConfig is a generated machine-specific file. Its hash tells
+// whether two machines were given the same answers without revealing them.
+type MachineConfig struct {
+	ID     string `yaml:"id" json:"id"`
+	Path   string `yaml:"path" json:"path"`
+	Status string `yaml:"status" json:"status"` // configured, missing or error
+	SHA256 string `yaml:"sha256,omitempty" json:"sha256,omitempty"`
+}
+
+// Generate describes this machine's go4dot setup: symlinks of every config
+// that are in place, dependencies, externals and machine configs
+func Generate(cfg *config.Config, dotfilesPath string, p *platform.Platform) (*Manifest, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return nil, err
+	}
+
+	m := &Manifest{
+		Version:       FormatVersion,
+		GeneratedAt:   time.Now().UTC().Truncate(time.Second),
+		Go4dotVersion: version.GetToolVersion(),
+		Machine: Machine{
+			Hostname:       p.Hostname,
+			OS:             p.OS,
+			Distro:         p.Distro,
+			DistroVersion:  p.DistroVersion,
+			Arch:           p.Architecture,
+			PackageManager: p.PackageManager,
+		},
+		Repo: Repo{Path: tilde(dotfilesPath, home)},
+	}
+	if repo := vcs.Detect(dotfilesPath); repo != nil && vcs.Installed(repo) {
+		m.Repo.Revision, _ = repo.Head(dotfilesPath)
+	}
+
+	for _, item := range cfg.GetAllConfigs() {
+		status, err := stow.GetConfigLinkStatus(item, dotfilesPath, cfg.Stow)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan %s: %w", item.Name, err)
+		}
+		for _, rel := range status.LinkedFiles {
+			m.Symlinks = append(m.Symlinks, Symlink{
+				Config: item.Name,
+				Source: repoPath(filepath.Join(item.Dir(dotfilesPath), rel), dotfilesPath),
+				Target: tilde(filepath.Join(cfg.Stow.TargetDir(), cfg.Stow.LinkPath(rel)), home),
+			})
+		}
+	}
+	for _, link := range stow.CheckLinks(cfg, dotfilesPath) {
+		if link.Status == stow.LinkOK && !link.Link.IsCopy() {
+			m.Symlinks = append(m.Symlinks, Symlink{Source: link.Link.Source, Target: link.Link.Target})
+		}
+	}
+	sort.Slice(m.Symlinks, func(i, j int) bool { return m.Symlinks[i].Target < m.Symlinks[j].Target })
+
+	checks, err := deps.Check(cfg, p)
+	if err != nil {
+		return nil, err
+	}
+	for _, tier := range [][]deps.DependencyCheck{checks.Critical, checks.Core, checks.Optional} {
+		for _, check := range tier {
+			m.Dependencies = append(m.Dependencies, Dependency{
+				Name:    check.Item.Name,
+				Status:  string(check.Status),
+				Version: check.InstalledVersion,
+				Path:    tilde(check.InstalledPath, home),
+			})
+		}
+	}
+
+	for _, status := range deps.CheckExternalStatus(cfg, p, dotfilesPath) {
+		ext := External{ID: status.Dep.ID, URL: status.Dep.URL, Status: status.Status}
+		if status.Path != "" {
+			ext.Path = tilde(status.Path, home)
+		}
+		if status.Status == "installed" {
+			ext.Revision = externalRevision(status.Path)
+		}
+		m.External = append(m.External, ext)
+	}
+
+	for _, status := range machine.CheckMachineConfigStatus(cfg) {
+		mc := MachineConfig{ID: status.ID, Path: tilde(status.Destination, home), Status: status.Status}
+		if status.Status == "configured" {
+			if data, err := os.ReadFile(status.Destination); err == nil {
+				sum := sha256.Sum256(data)
+				mc.SHA256 = hex.EncodeToString(sum[:])
+			}
+		}
+		m.MachineConfigs = append(m.MachineConfigs, mc)
+	}
+
+	return m, nil
+}
+
+// externalRevision returns the commit checked out in a cloned external or
+// the release tag of a github_release one, or "" for a copy
+func externalRevision(path string) string {
+	if tag := deps.InstalledRelease(path); tag != "" {
+		return tag
+	}
+	if _, err := os.Stat(filepath.Join(path, ".git")); err != nil {
+		return ""
+	}
+	head, _ := vcs.Git{}.Head(path)
+	return head
+}
+
+// Encode renders m as YAML or JSON
+func Encode(m *Manifest, format string) ([]byte, error) {
+	switch format {
+	case FormatYAML, "":
+		return yaml.Marshal(m)
+	case FormatJSON:
+		data, err := json.MarshalIndent(m, "", "  ")
+		if err != nil {
+			return nil, err
+		}
+		return append(data, '\n'), nil
+	}
+	return nil, fmt.Errorf("unknown manifest format %q (use yaml or json)", format)
+}
+
+// Load reads a manifest written by Encode, in either format
+func Load(path string) (*Manifest, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read manifest: %w", err)
+	}
+	// JSON is YAML too
+	var m Manifest
+	if err := yaml.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("failed to parse manifest %s: %w", path, err)
+	}
+	if m.Version == "" {
+		return nil, fmt.Errorf("%s is not a go4dot manifest", path)
+	}
+	return &m, nil
+}
+
+// tilde abbreviates path under home with ~
+func tilde(path, home string) string {
+	if rel, err := filepath.Rel(home, path); err == nil && rel != ".." && !strings.HasPrefix(rel, "../") {
+		if rel == "." {
+			return "~"
+		}
+		return "~/" + filepath.ToSlash(rel)
+	}
+	return path
+}
+
+// repoPath writes path in the dotfiles repo as @repoRoot/...
+func repoPath(path, dotfilesPath string) string {
+	if rel, err := filepath.Rel(dotfilesPath, path); err == nil && !strings.HasPrefix(rel, "..") {
+		return "@repoRoot/" + filepath.ToSlash(rel)
+	}
+	return path
+}
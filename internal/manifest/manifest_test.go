@@ -0,0 +1,148 @@
+package manifest
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/nvandessel/go4dot/internal/config"
+	"github.com/nvandessel/go4dot/internal/platform"
+)
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	repo := t.TempDir()
+
+	writeFile(t, filepath.Join(repo, "vim", ".vimrc"), "set number\n")
+	writeFile(t, filepath.Join(repo, "vim", ".vim", "colors.vim"), "colorscheme default\n")
+	if err := os.Symlink(filepath.Join(repo, "vim", ".vimrc"), filepath.Join(home, ".vimrc")); err != nil {
+		t.Fatal(err)
+	}
+	writeFile(t, filepath.Join(home, ".gitconfig.local"), "[user]\n\temail = me@example.com\n")
+
+	cfg := &config.Config{
+		Configs: config.ConfigGroups{Core: []config.ConfigItem{{Name: "vim", Path: "vim"}}},
+		MachineConfig: []config.MachinePrompt{
+			{ID: "git", Destination: "~/.gitconfig.local"},
+			{ID: "ssh", Destination: "~/.ssh/config.local"},
+		},
+	}
+	p := &platform.Platform{OS: "linux", Distro: "fedora", Architecture: "arm64", Hostname: "pi"}
+
+	m, err := Generate(cfg, repo, p)
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+
+	// Only the link in place is listed, with portable paths
+	want := []Symlink{{Config: "vim", Source: "@repoRoot/vim/.vimrc", Target: "~/.vimrc"}}
+	if !reflect.DeepEqual(m.Symlinks, want) {
+		t.Errorf("Symlinks = %+v, want %+v", m.Symlinks, want)
+	}
+	if m.Machine.Hostname != "pi" || m.Machine.Arch != "arm64" {
+		t.Errorf("Machine = %+v", m.Machine)
+	}
+	if len(m.MachineConfigs) != 2 || len(m.MachineConfigs[0].SHA256) != 64 || m.MachineConfigs[1].Status != "missing" {
+		t.Errorf("MachineConfigs = %+v", m.MachineConfigs)
+	}
+
+	// The answers are hashed, not copied
+	data, err := Encode(m, FormatYAML)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "me@example.com") {
+		t.Error("manifest should not contain machine config contents")
+	}
+}
+
+func TestEncodeLoad(t *testing.T) {
+	m := &Manifest{
+		Version:      FormatVersion,
+		Machine:      Machine{Hostname: "laptop", OS: "darwin", Arch: "arm64"},
+		Dependencies: []Dependency{{Name: "git", Status: "installed", Version: "2.45.0"}},
+	}
+	dir := t.TempDir()
+	for _, format := range []string{FormatYAML, FormatJSON} {
+		data, err := Encode(m, format)
+		if err != nil {
+			t.Fatalf("Encode(%s) error = %v", format, err)
+		}
+		path := filepath.Join(dir, "manifest."+format)
+		if err := os.WriteFile(path, data, 0644); err != nil {
+			t.Fatal(err)
+		}
+		got, err := Load(path)
+		if err != nil {
+			t.Fatalf("Load(%s) error = %v", format, err)
+		}
+		if !reflect.DeepEqual(got.Dependencies, m.Dependencies) || got.Machine != m.Machine {
+			t.Errorf("Load(%s) = %+v, want %+v", format, got, m)
+		}
+	}
+
+	if _, err := Encode(m, "toml"); err == nil {
+		t.Error("Encode() should reject unknown formats")
+	}
+	notManifest := filepath.Join(dir, "other.yaml")
+	if err := os.WriteFile(notManifest, []byte("name: vim\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Load(notManifest); err == nil {
+		t.Error("Load() should reject files that are not manifests")
+	}
+}
+
+func TestDiff(t *testing.T) {
+	a := &Manifest{
+		Machine:  Machine{Hostname: "laptop", OS: "linux", Arch: "amd64"},
+		Symlinks: []Symlink{{Config: "vim", Source: "@repoRoot/vim/.vimrc", Target: "~/.vimrc"}},
+		Dependencies: []Dependency{
+			{Name: "git", Status: "installed", Version: "2.43.0"},
+			{Name: "fzf", Status: "installed", Version: "0.44.1"},
+		},
+		External: []External{{ID: "tpm", URL: "https://github.com/tmux-plugins/tpm", Status: "installed", Revision: strings.Repeat("a", 40)}},
+	}
+	b := &Manifest{
+		Machine: Machine{Hostname: "pi", OS: "linux", Arch: "arm64"},
+		Symlinks: []Symlink{
+			{Config: "vim", Source: "@repoRoot/vim/.vimrc", Target: "~/.vimrc"},
+			{Config: "zsh", Source: "@repoRoot/zsh/.zshrc", Target: "~/.zshrc"},
+		},
+		Dependencies: []Dependency{{Name: "git", Status: "installed", Version: "2.45.0"}},
+		External:     []External{{ID: "tpm", URL: "https://github.com/tmux-plugins/tpm", Status: "installed", Revision: strings.Repeat("b", 40)}},
+	}
+
+	var got []string
+	for _, c := range Diff(a, b) {
+		got = append(got, c.String())
+	}
+	want := []string{
+		"machine hostname changed: laptop → pi",
+		"machine arch changed: amd64 → arm64",
+		"symlink ~/.zshrc added",
+		"dependency git changed: version: 2.43.0 → 2.45.0",
+		"dependency fzf removed",
+		"external tpm changed: revision: aaaaaaaaaaaa → bbbbbbbbbbbb",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Diff() =\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+
+	if diff := Diff(a, a); len(diff) != 0 {
+		t.Errorf("Diff(a, a) = %v, want no changes", diff)
+	}
+}
@@ -0,0 +1,79 @@
+package platform
+
+import "strings"
+
+// archAliases are the names package repos and release assets use for each
+// architecture, Go's name (what Detect reports) first
+var archAliases = map[string][]string{
+	"amd64":   {"amd64", "x86_64", "x64"},
+	"386":     {"386", "i386", "i686", "x86"},
+	"arm64":   {"arm64", "aarch64"},
+	"arm":     {"arm", "armv7", "armv7l", "armhf", "armv6"},
+	"riscv64": {"riscv64"},
+}
+
+// osAliases are the names release assets use for each OS, Go's name first
+var osAliases = map[string][]string{
+	"linux":   {"linux"},
+	"darwin":  {"darwin", "macos", "apple-darwin", "osx", "mac"},
+	"windows": {"windows", "win"},
+	"freebsd": {"freebsd"},
+	"openbsd": {"openbsd"},
+}
+
+// NormalizeArch returns Go's name for an architecture, so aarch64 and
+// x86_64 in a config match the arm64 and amd64 Detect reports. Unknown
+// names are returned lowercased.
+func NormalizeArch(arch string) string {
+	arch = strings.ToLower(strings.TrimSpace(arch))
+	for name, aliases := range archAliases {
+		for _, alias := range aliases {
+			if arch == alias {
+				return name
+			}
+		}
+	}
+	return arch
+}
+
+// ArchAliases returns the names arch goes by, Go's name first
+func ArchAliases(arch string) []string {
+	arch = NormalizeArch(arch)
+	if aliases, ok := archAliases[arch]; ok {
+		return aliases
+	}
+	return []string{arch}
+}
+
+// OSAliases returns the names goos goes by in release assets, Go's name
+// first
+func OSAliases(goos string) []string {
+	goos = strings.ToLower(goos)
+	if aliases, ok := osAliases[goos]; ok {
+		return aliases
+	}
+	return []string{goos}
+}
+
+// ArchAllowed reports whether condition's arch (or architecture) key, if
+// it has one, lists arch. Configs mark x86-only dependencies this way.
+func ArchAllowed(condition map[string]string, arch string) bool {
+	for _, key := range []string{"arch", "architecture"} {
+		if value, ok := condition[key]; ok && !matchesArch(arch, value) {
+			return false
+		}
+	}
+	return true
+}
+
+// matchesArch checks if actual is one of the comma-separated architectures
+// in expected, by any of their names
+func matchesArch(actual, expected string) bool {
+	actual = NormalizeArch(actual)
+	for _, v := range strings.Split(expected, ",") {
+		if NormalizeArch(v) == actual {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,51 @@
+package platform
+
+import "testing"
+
+func TestNormalizeArch(t *testing.T) {
+	tests := map[string]string{
+		"x86_64":  "amd64",
+		"AARCH64": "arm64",
+		"arm64":   "arm64",
+		"armv7l":  "arm",
+		"riscv64": "riscv64",
+		"mips":    "mips",
+	}
+	for in, want := range tests {
+		if got := NormalizeArch(in); got != want {
+			t.Errorf("NormalizeArch(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestArchAllowed(t *testing.T) {
+	tests := []struct {
+		name      string
+		condition map[string]string
+		arch      string
+		want      bool
+	}{
+		{"no condition", nil, "riscv64", true},
+		{"other keys only", map[string]string{"os": "linux"}, "arm64", true},
+		{"alias", map[string]string{"arch": "aarch64"}, "arm64", true},
+		{"list", map[string]string{"architecture": "x86_64, arm64"}, "arm64", true},
+		{"left out", map[string]string{"arch": "amd64"}, "riscv64", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ArchAllowed(tt.condition, tt.arch); got != tt.want {
+				t.Errorf("ArchAllowed() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckCondition_ArchAliases(t *testing.T) {
+	p := &Platform{OS: "linux", Architecture: "arm64"}
+	if !CheckCondition(map[string]string{"arch": "aarch64"}, p) {
+		t.Error("CheckCondition(arch: aarch64) = false on arm64")
+	}
+	if CheckCondition(map[string]string{"arch": "x86_64"}, p) {
+		t.Error("CheckCondition(arch: x86_64) = true on arm64")
+	}
+}
@@ -6,11 +6,11 @@ import (
 
 // CheckCondition evaluates if a condition is met based on platform information.
 // Conditions are a map of key-value pairs where keys can be:
-// - platform, os: linux, darwin, windows
+// - platform, os: linux, darwin, freebsd, openbsd, windows
 // - distro: fedora, ubuntu, debian, arch, etc.
-// - package_manager: dnf, apt, brew, pacman, etc.
+// - package_manager: dnf, apt, brew, pacman, pkg, pkg_add, etc.
 // - wsl: true, false
-// - arch, architecture: amd64, arm64, etc.
+// - arch, architecture: amd64, arm64, riscv64, etc.; aarch64 and x86_64 work too
 // - hostname: machine hostname (supports comma-separated list)
 func CheckCondition(condition map[string]string, p *Platform) bool {
 	if len(condition) == 0 {
@@ -39,7 +39,7 @@ func CheckCondition(condition map[string]string, p *Platform) bool {
 				return false
 			}
 		case "arch", "architecture":
-			if !matchesValue(p.Architecture, value) {
+			if !matchesArch(p.Architecture, value) {
 				return false
 			}
 		case "hostname":
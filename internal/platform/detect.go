@@ -11,11 +11,11 @@ import (
 
 // Platform represents the detected platform information
 type Platform struct {
-	OS             string // linux, darwin, windows
+	OS             string // linux, darwin, freebsd, openbsd, windows
 	Distro         string // fedora, ubuntu, debian, arch, etc. (Linux only)
-	DistroVersion  string // version number
+	DistroVersion  string // version number; the release on the BSDs
 	IsWSL          bool   // true if running under WSL
-	PackageManager string // dnf, apt, brew, pacman, etc.
+	PackageManager string // dnf, apt, brew, pacman, pkg, pkg_add, etc.
 	Architecture   string // amd64, arm64, etc.
 	Hostname       string // machine hostname
 }
@@ -38,6 +38,9 @@ func Detect() (*Platform, error) {
 		detectLinuxPackageManager(p)
 	case "darwin":
 		detectMacOSPackageManager(p)
+	case "freebsd", "openbsd":
+		detectBSDRelease(p)
+		detectBSDPackageManager(p)
 	case "windows":
 		detectWindowsPackageManager(p)
 	}
@@ -151,6 +154,27 @@ func detectMacOSPackageManager(p *Platform) {
 	}
 }
 
+// detectBSDRelease reads the release, e.g. 14.1-RELEASE or 7.5, from uname
+func detectBSDRelease(p *Platform) {
+	if out, err := runCommand("uname", "-r"); err == nil {
+		p.DistroVersion = out
+	}
+}
+
+// detectBSDPackageManager picks pkg on FreeBSD and pkg_add on OpenBSD.
+// FreeBSD installs pkg on first use, so it may be missing.
+func detectBSDPackageManager(p *Platform) {
+	binary := "pkg"
+	if p.OS == "openbsd" {
+		binary = "pkg_add"
+	}
+	if _, err := exec.LookPath(binary); err == nil {
+		p.PackageManager = binary
+	} else {
+		p.PackageManager = "none"
+	}
+}
+
 // String returns a human-readable representation of the platform
 func (p *Platform) String() string {
 	var sb strings.Builder
@@ -166,6 +190,9 @@ func (p *Platform) String() string {
 			sb.WriteString(" (WSL)")
 		}
 	}
+	if p.IsBSD() && p.DistroVersion != "" {
+		fmt.Fprintf(&sb, "\nRelease: %s", p.DistroVersion)
+	}
 
 	fmt.Fprintf(&sb, "\nArchitecture: %s", p.Architecture)
 	fmt.Fprintf(&sb, "\nPackage Manager: %s", p.PackageManager)
@@ -186,6 +213,11 @@ func (p *Platform) IsMacOS() bool {
 	return p.OS == "darwin"
 }
 
+// IsBSD returns true if the platform is FreeBSD or OpenBSD
+func (p *Platform) IsBSD() bool {
+	return p.OS == "freebsd" || p.OS == "openbsd"
+}
+
 // IsWindows returns true if the platform is Windows
 func (p *Platform) IsWindows() bool {
 	return p.OS == "windows"
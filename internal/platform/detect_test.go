@@ -104,6 +104,47 @@ func TestPlatformStringMacOS(t *testing.T) {
 	}
 }
 
+func TestPlatformStringFreeBSD(t *testing.T) {
+	p := &Platform{
+		OS:             "freebsd",
+		DistroVersion:  "14.1-RELEASE",
+		PackageManager: "pkg",
+		Architecture:   "amd64",
+	}
+
+	s := p.String()
+	for _, expected := range []string{"freebsd", "Release: 14.1-RELEASE", "pkg"} {
+		if !strings.Contains(s, expected) {
+			t.Errorf("String() output missing '%s': %s", expected, s)
+		}
+	}
+	if strings.Contains(s, "Distro:") {
+		t.Errorf("String() should not contain distro info for FreeBSD: %s", s)
+	}
+}
+
+func TestIsBSD(t *testing.T) {
+	tests := []struct {
+		name string
+		os   string
+		want bool
+	}{
+		{"FreeBSD", "freebsd", true},
+		{"OpenBSD", "openbsd", true},
+		{"Linux", "linux", false},
+		{"macOS", "darwin", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &Platform{OS: tt.os}
+			if got := p.IsBSD(); got != tt.want {
+				t.Errorf("IsBSD() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestIsLinux(t *testing.T) {
 	tests := []struct {
 		name string
@@ -0,0 +1,161 @@
+package platform
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// Privilege escalation tools package managers run through
+const (
+	EscalateSudo   = "sudo"
+	EscalateDoas   = "doas"
+	EscalatePkexec = "pkexec" // Asks through the desktop's polkit agent
+)
+
+// EscalationTools lists the supported tools, in the order they are looked for
+var EscalationTools = []string{EscalateSudo, EscalateDoas, EscalatePkexec}
+
+// ErrNoEscalation is returned when none of the escalation tools is installed
+var ErrNoEscalation = errors.New("none of sudo, doas or pkexec is installed")
+
+// geteuid is swapped in tests, which may run as root
+var geteuid = os.Geteuid
+
+// IsValidEscalationTool reports whether tool is a supported escalation tool
+func IsValidEscalationTool(tool string) bool {
+	for _, t := range EscalationTools {
+		if t == tool {
+			return true
+		}
+	}
+	return false
+}
+
+// Escalator runs commands as root through sudo, doas or pkexec. Commands
+// never wait on a password prompt nobody can see: sudo and doas run
+// non-interactively, so a missing password fails at once, and go4dot asks
+// for the sudo password itself up front (see Authenticate).
+type Escalator struct {
+	Tool string   // Empty when go4dot already runs as root
+	Env  []string // KEY=VALUE pairs passed through, as sudo drops most of the environment; see rootSafeEnv
+}
+
+// FindEscalator returns an escalator using preferred, or the first of
+// EscalationTools that is installed when preferred is empty. Running as root
+// needs none.
+func FindEscalator(preferred string) (*Escalator, error) {
+	if geteuid() == 0 {
+		return &Escalator{}, nil
+	}
+	if preferred != "" {
+		if !IsValidEscalationTool(preferred) {
+			return nil, fmt.Errorf("unknown escalation tool %q (valid: %v)", preferred, EscalationTools)
+		}
+		if !commandExists(preferred) {
+			return nil, fmt.Errorf("%s is not installed", preferred)
+		}
+		return &Escalator{Tool: preferred}, nil
+	}
+	for _, tool := range EscalationTools {
+		if commandExists(tool) {
+			return &Escalator{Tool: tool}, nil
+		}
+	}
+	return nil, ErrNoEscalation
+}
+
+// Command returns a command running name as root
+func (e *Escalator) Command(name string, args ...string) *exec.Cmd {
+	if env := rootSafeEnv(e.Env); len(env) > 0 && e.Tool != "" {
+		args = append(append(env, name), args...)
+		name = "env"
+	}
+	switch e.Tool {
+	case "":
+		return exec.Command(name, args...)
+	case EscalateSudo, EscalateDoas:
+		return exec.Command(e.Tool, append([]string{"-n", name}, args...)...)
+	default:
+		return exec.Command(e.Tool, append([]string{name}, args...)...)
+	}
+}
+
+// rootSafeEnv returns the pairs of env that may reach a command running as
+// root, leaving out the search paths, preloads and shell startup variables
+// that would let the config choose which code root runs
+func rootSafeEnv(env []string) []string {
+	var safe []string
+	for _, kv := range env {
+		name, _, _ := strings.Cut(kv, "=")
+		if !loadsCode(name) {
+			safe = append(safe, kv)
+		}
+	}
+	return safe
+}
+
+// loadsCode reports whether the variable name changes which programs,
+// libraries or startup scripts a command loads
+func loadsCode(name string) bool {
+	switch name {
+	case "PATH", "IFS", "ENV", "BASH_ENV", "SHELLOPTS", "BASHOPTS", "PS4",
+		"GCONV_PATH", "NLSPATH", "LOCPATH", "HOSTALIASES",
+		"PYTHONPATH", "PYTHONHOME", "PYTHONSTARTUP", "PERL5LIB", "PERL5OPT", "PERLLIB",
+		"RUBYLIB", "RUBYOPT", "NODE_OPTIONS", "NODE_PATH":
+		return true
+	}
+	return strings.HasPrefix(name, "LD_") || strings.HasPrefix(name, "DYLD_") ||
+		strings.HasPrefix(name, "BASH_FUNC_")
+}
+
+// NeedsPassword reports whether commands would fail until a password is
+// given. pkexec asks through the polkit agent itself, so it never does.
+func (e *Escalator) NeedsPassword() bool {
+	switch e.Tool {
+	case EscalateSudo, EscalateDoas:
+		return exec.Command(e.Tool, "-n", "true").Run() != nil
+	default:
+		return false
+	}
+}
+
+// CanAuthenticate reports whether go4dot can pass a password to the tool.
+// doas only reads one from a terminal it opens itself.
+func (e *Escalator) CanAuthenticate() bool {
+	return e.Tool == EscalateSudo
+}
+
+// Authenticate checks password with sudo and caches it, so the commands
+// that follow run without asking for the rest of sudo's timeout
+func (e *Escalator) Authenticate(password string) error {
+	if !e.CanAuthenticate() {
+		return fmt.Errorf("%s can't take a password from go4dot", e.Tool)
+	}
+	cmd := exec.Command(EscalateSudo, "-S", "-p", "", "-v")
+	cmd.Stdin = strings.NewReader(password + "\n")
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("sudo rejected the password")
+	}
+	return nil
+}
+
+// privileged is embedded by package managers whose commands run as root
+type privileged struct {
+	escalator *Escalator // nil runs through sudo as is, which may prompt
+}
+
+// SetEscalator makes the package manager's root commands run through e
+func (p *privileged) SetEscalator(e *Escalator) {
+	p.escalator = e
+}
+
+// command returns a command running name as root
+func (p *privileged) command(name string, args ...string) *exec.Cmd {
+	if p.escalator == nil {
+		return exec.Command(EscalateSudo, append([]string{name}, args...)...)
+	}
+	return p.escalator.Command(name, args...)
+}
@@ -0,0 +1,123 @@
+package platform
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+// fakeSudo puts a sudo on PATH that accepts "-n" only once validated and
+// validates when given password on stdin
+func fakeSudo(t *testing.T, password string) {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("fake sudo is a shell script")
+	}
+	dir := t.TempDir()
+	stamp := filepath.Join(dir, "validated")
+	script := `#!/bin/sh
+case "$1" in
+-n) [ -f "` + stamp + `" ] || exit 1; shift; exec "$@" ;;
+-S) read pw; [ "$pw" = "` + password + `" ] || exit 1; touch "` + stamp + `" ;;
+esac
+`
+	if err := os.WriteFile(filepath.Join(dir, "sudo"), []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	orig := geteuid
+	geteuid = func() int { return 1000 }
+	t.Cleanup(func() { geteuid = orig })
+}
+
+func TestFindEscalator(t *testing.T) {
+	fakeSudo(t, "hunter2")
+
+	esc, err := FindEscalator("")
+	if err != nil {
+		t.Fatalf("FindEscalator() error = %v", err)
+	}
+	if esc.Tool != EscalateSudo {
+		t.Errorf("Tool = %q, want sudo", esc.Tool)
+	}
+
+	if _, err := FindEscalator("su"); err == nil {
+		t.Error("FindEscalator(\"su\") should reject an unknown tool")
+	}
+
+	geteuid = func() int { return 0 }
+	esc, err = FindEscalator(EscalateSudo)
+	if err != nil || esc.Tool != "" {
+		t.Errorf("FindEscalator() as root = %+v, %v, want no tool", esc, err)
+	}
+}
+
+func TestEscalator_Authenticate(t *testing.T) {
+	fakeSudo(t, "hunter2")
+	esc := &Escalator{Tool: EscalateSudo}
+
+	if !esc.NeedsPassword() {
+		t.Fatal("NeedsPassword() = false before authenticating")
+	}
+	if err := esc.Authenticate("wrong"); err == nil {
+		t.Error("Authenticate() accepted a wrong password")
+	}
+	if err := esc.Authenticate("hunter2"); err != nil {
+		t.Fatalf("Authenticate() error = %v", err)
+	}
+	if esc.NeedsPassword() {
+		t.Error("NeedsPassword() = true after authenticating")
+	}
+	if err := esc.Command("true").Run(); err != nil {
+		t.Errorf("Command() after authenticating failed: %v", err)
+	}
+}
+
+func TestEscalator_Command(t *testing.T) {
+	tests := []struct {
+		tool string
+		want []string
+	}{
+		{"", []string{"apt-get", "install", "-y"}},
+		{EscalateSudo, []string{"sudo", "-n", "apt-get", "install", "-y"}},
+		{EscalateDoas, []string{"doas", "-n", "apt-get", "install", "-y"}},
+		{EscalatePkexec, []string{"pkexec", "apt-get", "install", "-y"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.tool, func(t *testing.T) {
+			cmd := (&Escalator{Tool: tt.tool}).Command("apt-get", "install", "-y")
+			if len(cmd.Args) != len(tt.want) {
+				t.Fatalf("Args = %v, want %v", cmd.Args, tt.want)
+			}
+			for i := range tt.want {
+				if cmd.Args[i] != tt.want[i] {
+					t.Fatalf("Args = %v, want %v", cmd.Args, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestEscalator_CommandEnv(t *testing.T) {
+	esc := &Escalator{Tool: EscalateSudo, Env: []string{"http_proxy=http://proxy:3128"}}
+	got := esc.Command("apt-get", "update").Args
+	want := []string{"sudo", "-n", "env", "http_proxy=http://proxy:3128", "apt-get", "update"}
+	if strings.Join(got, " ") != strings.Join(want, " ") {
+		t.Errorf("Args = %v, want %v", got, want)
+	}
+
+	// Variables choosing the code root runs are never passed
+	esc.Env = append(esc.Env, "PATH=/tmp/evil", "LD_PRELOAD=/tmp/evil.so", "DYLD_INSERT_LIBRARIES=x", "BASH_ENV=/tmp/rc")
+	if got := esc.Command("apt-get", "update").Args; strings.Join(got, " ") != strings.Join(want, " ") {
+		t.Errorf("Args = %v, want loader variables left out", got)
+	}
+
+	// Running as root, the command inherits go4dot's environment as is
+	root := &Escalator{Env: esc.Env}
+	if got := root.Command("apt-get", "update").Args; got[0] != "apt-get" {
+		t.Errorf("Args = %v, want apt-get to run directly", got)
+	}
+}
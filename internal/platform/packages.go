@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"os/exec"
 	"strings"
+
+	"github.com/nvandessel/go4dot/internal/validation"
 )
 
 // PackageManager defines the interface for package management operations
@@ -30,6 +32,19 @@ type PackageManager interface {
 	NeedsSudo() bool
 }
 
+// Escalated is implemented by package managers that install as root, so
+// their root commands can run through the chosen Escalator
+type Escalated interface {
+	SetEscalator(e *Escalator)
+}
+
+// Upgrader is implemented by package managers whose Install leaves an
+// already installed package at its version
+type Upgrader interface {
+	// Upgrade upgrades installed packages to the newest available version
+	Upgrade(packages ...string) error
+}
+
 // GetPackageManager returns the appropriate package manager for the platform
 func GetPackageManager(p *Platform) (PackageManager, error) {
 	switch p.PackageManager {
@@ -43,12 +58,54 @@ func GetPackageManager(p *Platform) (PackageManager, error) {
 		return &BrewManager{}, nil
 	case "pacman":
 		return &PacmanManager{}, nil
+	case "pkg":
+		return &PkgManager{}, nil
+	case "pkg_add":
+		return &PkgAddManager{}, nil
 	default:
+		if p.OS == "freebsd" && p.PackageManager == "none" {
+			return nil, fmt.Errorf("pkg is not installed yet; run 'pkg bootstrap' as root first")
+		}
 		return nil, fmt.Errorf("unsupported package manager: %s", p.PackageManager)
 	}
 }
 
+// installCommands are how each package manager installs a package by hand
+var installCommands = map[string]string{
+	"dnf":     "sudo dnf install",
+	"yum":     "sudo yum install",
+	"apt":     "sudo apt install",
+	"brew":    "brew install",
+	"pacman":  "sudo pacman -S",
+	"pkg":     "sudo pkg install",
+	"pkg_add": "doas pkg_add",
+}
+
+// InstallCommand returns the command that installs pkg with manager, for
+// fix hints, e.g. "sudo pkg install stow". It is empty for managers go4dot
+// does not support.
+func InstallCommand(manager, pkg string) string {
+	cmd, ok := installCommands[manager]
+	if !ok {
+		return ""
+	}
+	return cmd + " " + MapPackageName(pkg, manager)
+}
+
 // runCommand executes a command and returns the output
+// mapPackages maps generic package names to the manager's and validates
+// them, so none can pass as a flag
+func mapPackages(manager string, packages []string) ([]string, error) {
+	mapped := make([]string, len(packages))
+	for i, pkg := range packages {
+		mapped[i] = MapPackageName(pkg, manager)
+		if err := validation.ValidatePackageName(mapped[i]); err != nil {
+			return nil, fmt.Errorf("invalid package name %q: %w", mapped[i], err)
+		}
+	}
+	return mapped, nil
+}
+
 func runCommand(name string, args ...string) (string, error) {
 	cmd := exec.Command(name, args...)
 	output, err := cmd.CombinedOutput()
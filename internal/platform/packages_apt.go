@@ -2,14 +2,16 @@ package platform
 
 import (
 	"fmt"
-	"os/exec"
 	"strings"
 
+	"github.com/nvandessel/go4dot/internal/transcript"
 	"github.com/nvandessel/go4dot/internal/validation"
 )
 
 // APTManager implements PackageManager for APT (Debian, Ubuntu)
-type APTManager struct{}
+type APTManager struct {
+	privileged
+}
 
 func (a *APTManager) Name() string {
 	return "apt"
@@ -38,15 +40,15 @@ func (a *APTManager) Install(packages ...string) error {
 	}
 
 	// Set DEBIAN_FRONTEND=noninteractive to avoid prompts
-	args := []string{"apt-get", "install", "-y"}
+	args := []string{"install", "-y"}
 	args = append(args, mapped...)
 
-	cmd := exec.Command("sudo", args...)
+	cmd := a.command("apt-get", args...)
 	cmd.Env = append(cmd.Env, "DEBIAN_FRONTEND=noninteractive")
 	cmd.Stdout = nil
 	cmd.Stderr = nil
 
-	if err := cmd.Run(); err != nil {
+	if err := transcript.Run(cmd); err != nil {
 		return fmt.Errorf("failed to install packages: %w", err)
 	}
 
@@ -64,9 +66,9 @@ func (a *APTManager) IsInstalled(pkg string) bool {
 }
 
 func (a *APTManager) Update() error {
-	cmd := exec.Command("sudo", "apt-get", "update")
+	cmd := a.command("apt-get", "update")
 	cmd.Env = append(cmd.Env, "DEBIAN_FRONTEND=noninteractive")
-	if err := cmd.Run(); err != nil {
+	if err := transcript.Run(cmd); err != nil {
 		return fmt.Errorf("failed to update package cache: %w", err)
 	}
 	return nil
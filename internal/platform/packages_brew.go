@@ -5,6 +5,7 @@ import (
 	"os/exec"
 	"strings"
 
+	"github.com/nvandessel/go4dot/internal/transcript"
 	"github.com/nvandessel/go4dot/internal/validation"
 )
 
@@ -44,13 +45,34 @@ func (b *BrewManager) Install(packages ...string) error {
 	cmd.Stdout = nil
 	cmd.Stderr = nil
 
-	if err := cmd.Run(); err != nil {
+	if err := transcript.Run(cmd); err != nil {
 		return fmt.Errorf("failed to install packages: %w", err)
 	}
 
 	return nil
 }
 
+// Upgrade upgrades installed packages, which Install leaves alone
+func (b *BrewManager) Upgrade(packages ...string) error {
+	if len(packages) == 0 {
+		return nil
+	}
+	mapped, err := mapPackages("brew", packages)
+	if err != nil {
+		return err
+	}
+
+	cmd := exec.Command("brew", append([]string{"upgrade"}, mapped...)...)
+	cmd.Stdout = nil
+	cmd.Stderr = nil
+
+	if err := transcript.Run(cmd); err != nil {
+		return fmt.Errorf("failed to upgrade packages: %w", err)
+	}
+
+	return nil
+}
+
 func (b *BrewManager) IsInstalled(pkg string) bool {
 	pkg = MapPackageName(pkg, "brew")
 	// brew list --formula returns list of installed formula packages
@@ -72,7 +94,7 @@ func (b *BrewManager) IsInstalled(pkg string) bool {
 
 func (b *BrewManager) Update() error {
 	cmd := exec.Command("brew", "update")
-	if err := cmd.Run(); err != nil {
+	if err := transcript.Run(cmd); err != nil {
 		return fmt.Errorf("failed to update brew: %w", err)
 	}
 	return nil
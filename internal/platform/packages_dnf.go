@@ -2,14 +2,16 @@ package platform
 
 import (
 	"fmt"
-	"os/exec"
 	"strings"
 
+	"github.com/nvandessel/go4dot/internal/transcript"
 	"github.com/nvandessel/go4dot/internal/validation"
 )
 
 // DNFManager implements PackageManager for DNF (Fedora, RHEL 8+)
-type DNFManager struct{}
+type DNFManager struct {
+	privileged
+}
 
 func (d *DNFManager) Name() string {
 	return "dnf"
@@ -40,17 +42,38 @@ func (d *DNFManager) Install(packages ...string) error {
 	args := []string{"install", "-y"}
 	args = append(args, mapped...)
 
-	cmd := exec.Command("sudo", append([]string{"dnf"}, args...)...)
+	cmd := d.command("dnf", args...)
 	cmd.Stdout = nil // Could pipe to UI later
 	cmd.Stderr = nil
 
-	if err := cmd.Run(); err != nil {
+	if err := transcript.Run(cmd); err != nil {
 		return fmt.Errorf("failed to install packages: %w", err)
 	}
 
 	return nil
 }
 
+// Upgrade upgrades installed packages, which Install leaves alone
+func (d *DNFManager) Upgrade(packages ...string) error {
+	if len(packages) == 0 {
+		return nil
+	}
+	mapped, err := mapPackages("dnf", packages)
+	if err != nil {
+		return err
+	}
+
+	cmd := d.command("dnf", append([]string{"upgrade", "-y"}, mapped...)...)
+	cmd.Stdout = nil
+	cmd.Stderr = nil
+
+	if err := transcript.Run(cmd); err != nil {
+		return fmt.Errorf("failed to upgrade packages: %w", err)
+	}
+
+	return nil
+}
+
 func (d *DNFManager) IsInstalled(pkg string) bool {
 	pkg = MapPackageName(pkg, "dnf")
 	output, err := runCommand("rpm", "-q", pkg)
@@ -61,10 +84,10 @@ func (d *DNFManager) IsInstalled(pkg string) bool {
 }
 
 func (d *DNFManager) Update() error {
-	cmd := exec.Command("sudo", "dnf", "check-update", "-y")
+	cmd := d.command("dnf", "check-update", "-y")
 	// check-update returns 100 if updates are available, 0 if not
 	// We just want to refresh the cache, so we ignore the exit code
-	_ = cmd.Run()
+	_ = transcript.Run(cmd)
 	return nil
 }
 
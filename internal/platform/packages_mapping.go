@@ -15,6 +15,7 @@ type PackageMapping struct {
 
 	// Managers maps package manager names to their specific package names.
 	// Keys are manager names (e.g., "apt", "dnf", "brew", "pacman", "yum").
+	// The BSDs' pkg and pkg_add are only listed where their name differs.
 	Managers map[string]string
 }
 
@@ -179,7 +180,7 @@ func defaultMappings() []PackageMapping {
 		{
 			Canonical:   "python3",
 			Description: "Python 3 interpreter",
-			Managers:    map[string]string{"apt": "python3", "dnf": "python3", "yum": "python3", "pacman": "python", "brew": "python@3"},
+			Managers:    map[string]string{"apt": "python3", "dnf": "python3", "yum": "python3", "pacman": "python", "brew": "python@3", "pkg_add": "python"},
 		},
 		{
 			Canonical:   "python3-pip",
@@ -189,12 +190,12 @@ func defaultMappings() []PackageMapping {
 		{
 			Canonical:   "nodejs",
 			Description: "JavaScript runtime built on V8",
-			Managers:    map[string]string{"apt": "nodejs", "dnf": "nodejs", "yum": "nodejs", "pacman": "nodejs", "brew": "node"},
+			Managers:    map[string]string{"apt": "nodejs", "dnf": "nodejs", "yum": "nodejs", "pacman": "nodejs", "brew": "node", "pkg": "node", "pkg_add": "node"},
 		},
 		{
 			Canonical:   "golang",
 			Description: "Go programming language",
-			Managers:    map[string]string{"apt": "golang", "dnf": "golang", "yum": "golang", "pacman": "go", "brew": "go"},
+			Managers:    map[string]string{"apt": "golang", "dnf": "golang", "yum": "golang", "pacman": "go", "brew": "go", "pkg": "go", "pkg_add": "go"},
 		},
 		{
 			Canonical:   "rust",
@@ -209,14 +210,14 @@ func defaultMappings() []PackageMapping {
 		{
 			Canonical:   "lua",
 			Description: "Lightweight scripting language",
-			Managers:    map[string]string{"apt": "lua5.4", "dnf": "lua", "yum": "lua", "pacman": "lua", "brew": "lua"},
+			Managers:    map[string]string{"apt": "lua5.4", "dnf": "lua", "yum": "lua", "pacman": "lua", "brew": "lua", "pkg": "lua54"},
 		},
 
 		// --- Build Tools ---
 		{
 			Canonical:   "make",
 			Description: "GNU Make build automation tool",
-			Managers:    map[string]string{"apt": "make", "dnf": "make", "yum": "make", "pacman": "make", "brew": "make"},
+			Managers:    map[string]string{"apt": "make", "dnf": "make", "yum": "make", "pacman": "make", "brew": "make", "pkg": "gmake", "pkg_add": "gmake"},
 		},
 		{
 			Canonical:   "cmake",
@@ -238,7 +239,7 @@ func defaultMappings() []PackageMapping {
 		{
 			Canonical:   "fd",
 			Description: "Fast and user-friendly alternative to find",
-			Managers:    map[string]string{"apt": "fd-find", "dnf": "fd-find", "yum": "fd-find", "pacman": "fd", "brew": "fd"},
+			Managers:    map[string]string{"apt": "fd-find", "dnf": "fd-find", "yum": "fd-find", "pacman": "fd", "brew": "fd", "pkg": "fd-find"},
 		},
 		{
 			Canonical:   "ripgrep",
@@ -324,7 +325,7 @@ func defaultMappings() []PackageMapping {
 		{
 			Canonical:   "openssh",
 			Description: "OpenSSH client and server",
-			Managers:    map[string]string{"apt": "openssh-client", "dnf": "openssh-clients", "yum": "openssh-clients", "pacman": "openssh", "brew": "openssh"},
+			Managers:    map[string]string{"apt": "openssh-client", "dnf": "openssh-clients", "yum": "openssh-clients", "pacman": "openssh", "brew": "openssh", "pkg": "openssh-portable"},
 		},
 		{
 			Canonical:   "nmap",
@@ -336,7 +337,7 @@ func defaultMappings() []PackageMapping {
 		{
 			Canonical:   "httpd",
 			Description: "Apache HTTP Server",
-			Managers:    map[string]string{"apt": "apache2", "dnf": "httpd", "yum": "httpd", "pacman": "apache", "brew": "httpd"},
+			Managers:    map[string]string{"apt": "apache2", "dnf": "httpd", "yum": "httpd", "pacman": "apache", "brew": "httpd", "pkg": "apache24", "pkg_add": "apache-httpd"},
 		},
 		{
 			Canonical:   "nginx",
@@ -367,7 +368,7 @@ func defaultMappings() []PackageMapping {
 		{
 			Canonical:   "shellcheck",
 			Description: "Static analysis tool for shell scripts",
-			Managers:    map[string]string{"apt": "shellcheck", "dnf": "ShellCheck", "yum": "ShellCheck", "pacman": "shellcheck", "brew": "shellcheck"},
+			Managers:    map[string]string{"apt": "shellcheck", "dnf": "ShellCheck", "yum": "ShellCheck", "pacman": "shellcheck", "brew": "shellcheck", "pkg": "hs-ShellCheck"},
 		},
 		{
 			Canonical:   "the_silver_searcher",
@@ -377,7 +378,7 @@ func defaultMappings() []PackageMapping {
 		{
 			Canonical:   "delta",
 			Description: "Syntax-highlighting pager for git diffs",
-			Managers:    map[string]string{"apt": "git-delta", "dnf": "git-delta", "yum": "git-delta", "pacman": "git-delta", "brew": "git-delta"},
+			Managers:    map[string]string{"apt": "git-delta", "dnf": "git-delta", "yum": "git-delta", "pacman": "git-delta", "brew": "git-delta", "pkg": "git-delta"},
 		},
 	}
 }
@@ -2,14 +2,16 @@ package platform
 
 import (
 	"fmt"
-	"os/exec"
 	"strings"
 
+	"github.com/nvandessel/go4dot/internal/transcript"
 	"github.com/nvandessel/go4dot/internal/validation"
 )
 
 // PacmanManager implements PackageManager for Pacman (Arch Linux, Manjaro)
-type PacmanManager struct{}
+type PacmanManager struct {
+	privileged
+}
 
 func (p *PacmanManager) Name() string {
 	return "pacman"
@@ -40,11 +42,11 @@ func (p *PacmanManager) Install(packages ...string) error {
 	args := []string{"-S", "--noconfirm"}
 	args = append(args, mapped...)
 
-	cmd := exec.Command("sudo", append([]string{"pacman"}, args...)...)
+	cmd := p.command("pacman", args...)
 	cmd.Stdout = nil
 	cmd.Stderr = nil
 
-	if err := cmd.Run(); err != nil {
+	if err := transcript.Run(cmd); err != nil {
 		return fmt.Errorf("failed to install packages: %w", err)
 	}
 
@@ -59,8 +61,8 @@ func (p *PacmanManager) IsInstalled(pkg string) bool {
 }
 
 func (p *PacmanManager) Update() error {
-	cmd := exec.Command("sudo", "pacman", "-Sy")
-	if err := cmd.Run(); err != nil {
+	cmd := p.command("pacman", "-Sy")
+	if err := transcript.Run(cmd); err != nil {
 		return fmt.Errorf("failed to update package database: %w", err)
 	}
 	return nil
@@ -0,0 +1,101 @@
+package platform
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/nvandessel/go4dot/internal/transcript"
+	"github.com/nvandessel/go4dot/internal/validation"
+)
+
+// PkgManager implements PackageManager for pkg (FreeBSD)
+type PkgManager struct {
+	privileged
+}
+
+func (p *PkgManager) Name() string {
+	return "pkg"
+}
+
+func (p *PkgManager) IsAvailable() bool {
+	return commandExists("pkg")
+}
+
+func (p *PkgManager) Install(packages ...string) error {
+	if len(packages) == 0 {
+		return nil
+	}
+
+	// Map package names
+	mapped := make([]string, len(packages))
+	for i, pkg := range packages {
+		mapped[i] = MapPackageName(pkg, "pkg")
+	}
+
+	// Validate package names after mapping to prevent flag injection
+	for _, m := range mapped {
+		if err := validation.ValidatePackageName(m); err != nil {
+			return fmt.Errorf("invalid package name %q: %w", m, err)
+		}
+	}
+
+	args := []string{"install", "-y"}
+	args = append(args, mapped...)
+
+	cmd := p.command("pkg", args...)
+	cmd.Stdout = nil
+	cmd.Stderr = nil
+
+	if err := transcript.Run(cmd); err != nil {
+		return fmt.Errorf("failed to install packages: %w", err)
+	}
+
+	return nil
+}
+
+func (p *PkgManager) IsInstalled(pkg string) bool {
+	pkg = MapPackageName(pkg, "pkg")
+	// pkg info -e exits 0 if the package is installed
+	_, err := runCommand("pkg", "info", "-e", pkg)
+	return err == nil
+}
+
+func (p *PkgManager) Update() error {
+	cmd := p.command("pkg", "update")
+	if err := transcript.Run(cmd); err != nil {
+		return fmt.Errorf("failed to update package catalogue: %w", err)
+	}
+	return nil
+}
+
+func (p *PkgManager) Search(query string) ([]string, error) {
+	output, err := runCommand("pkg", "search", "-q", query)
+	if err != nil {
+		return nil, err
+	}
+
+	var results []string
+	for _, line := range strings.Split(output, "\n") {
+		// pkg search -q format: "package-version"
+		if line = strings.TrimSpace(line); line != "" {
+			results = append(results, trimPackageVersion(line))
+		}
+	}
+
+	return results, nil
+}
+
+func (p *PkgManager) NeedsSudo() bool {
+	return true
+}
+
+// trimPackageVersion strips the version the BSD package tools append to
+// package names, e.g. "ripgrep-14.1.0_1" becomes "ripgrep"
+func trimPackageVersion(name string) string {
+	for i := len(name) - 1; i > 0; i-- {
+		if name[i] == '-' && i+1 < len(name) && name[i+1] >= '0' && name[i+1] <= '9' {
+			return name[:i]
+		}
+	}
+	return name
+}
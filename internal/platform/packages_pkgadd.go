@@ -0,0 +1,111 @@
+package platform
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/nvandessel/go4dot/internal/transcript"
+	"github.com/nvandessel/go4dot/internal/validation"
+)
+
+// PkgAddManager implements PackageManager for pkg_add (OpenBSD)
+type PkgAddManager struct {
+	privileged
+}
+
+func (p *PkgAddManager) Name() string {
+	return "pkg_add"
+}
+
+func (p *PkgAddManager) IsAvailable() bool {
+	return commandExists("pkg_add")
+}
+
+func (p *PkgAddManager) Install(packages ...string) error {
+	if len(packages) == 0 {
+		return nil
+	}
+
+	// Map package names
+	mapped := make([]string, len(packages))
+	for i, pkg := range packages {
+		mapped[i] = MapPackageName(pkg, "pkg_add")
+	}
+
+	// Validate package names after mapping to prevent flag injection
+	for _, m := range mapped {
+		if err := validation.ValidatePackageName(m); err != nil {
+			return fmt.Errorf("invalid package name %q: %w", m, err)
+		}
+	}
+
+	// -I never asks, e.g. which flavor to pick; it fails instead
+	args := []string{"-I"}
+	args = append(args, mapped...)
+
+	cmd := p.command("pkg_add", args...)
+	cmd.Stdout = nil
+	cmd.Stderr = nil
+
+	if err := transcript.Run(cmd); err != nil {
+		return fmt.Errorf("failed to install packages: %w", err)
+	}
+
+	return nil
+}
+
+// Upgrade upgrades installed packages, which Install leaves alone
+func (p *PkgAddManager) Upgrade(packages ...string) error {
+	if len(packages) == 0 {
+		return nil
+	}
+	mapped, err := mapPackages("pkg_add", packages)
+	if err != nil {
+		return err
+	}
+
+	cmd := p.command("pkg_add", append([]string{"-u", "-I"}, mapped...)...)
+	cmd.Stdout = nil
+	cmd.Stderr = nil
+
+	if err := transcript.Run(cmd); err != nil {
+		return fmt.Errorf("failed to upgrade packages: %w", err)
+	}
+
+	return nil
+}
+
+func (p *PkgAddManager) IsInstalled(pkg string) bool {
+	pkg = MapPackageName(pkg, "pkg_add")
+	// pkg_info -e exits 0 if a package matching the spec is installed
+	_, err := runCommand("pkg_info", "-q", "-e", pkg+"-*")
+	return err == nil
+}
+
+// Update does nothing: pkg_add reads the mirror's index on every run, so
+// there is no local catalogue to refresh
+func (p *PkgAddManager) Update() error {
+	return nil
+}
+
+func (p *PkgAddManager) Search(query string) ([]string, error) {
+	output, err := runCommand("pkg_info", "-Q", query)
+	if err != nil {
+		return nil, err
+	}
+
+	var results []string
+	for _, line := range strings.Split(output, "\n") {
+		// pkg_info -Q format: "package-version" or "package-version (installed)"
+		fields := strings.Fields(line)
+		if len(fields) > 0 {
+			results = append(results, trimPackageVersion(fields[0]))
+		}
+	}
+
+	return results, nil
+}
+
+func (p *PkgAddManager) NeedsSudo() bool {
+	return true
+}
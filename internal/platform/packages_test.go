@@ -44,6 +44,24 @@ func TestGetPackageManager(t *testing.T) {
 			wantName: "pacman",
 			wantErr:  false,
 		},
+		{
+			name:     "Pkg",
+			platform: &Platform{PackageManager: "pkg"},
+			wantName: "pkg",
+			wantErr:  false,
+		},
+		{
+			name:     "PkgAdd",
+			platform: &Platform{PackageManager: "pkg_add"},
+			wantName: "pkg_add",
+			wantErr:  false,
+		},
+		{
+			name:     "FreeBSD without pkg",
+			platform: &Platform{OS: "freebsd", PackageManager: "none"},
+			wantName: "",
+			wantErr:  true,
+		},
 		{
 			name:     "Unsupported",
 			platform: &Platform{PackageManager: "unsupported"},
@@ -193,6 +211,54 @@ func TestPacmanManager(t *testing.T) {
 	}
 }
 
+func TestBSDManagers(t *testing.T) {
+	for _, mgr := range []PackageManager{&PkgManager{}, &PkgAddManager{}} {
+		if !mgr.NeedsSudo() {
+			t.Errorf("NeedsSudo() should return true for %s", mgr.Name())
+		}
+		if _, ok := mgr.(Escalated); !ok {
+			t.Errorf("%s should run its root commands through an Escalator", mgr.Name())
+		}
+	}
+
+	// pkg_add has no catalogue to refresh
+	if err := (&PkgAddManager{}).Update(); err != nil {
+		t.Errorf("PkgAddManager.Update() = %v, want nil", err)
+	}
+}
+
+func TestTrimPackageVersion(t *testing.T) {
+	tests := map[string]string{
+		"ripgrep-14.1.0_1":      "ripgrep",
+		"py311-pip-23.3.2":      "py311-pip",
+		"the_silver_searcher-2": "the_silver_searcher",
+		"git-delta-0.17.0p0":    "git-delta",
+		"hs-ShellCheck":         "hs-ShellCheck",
+	}
+	for in, want := range tests {
+		if got := trimPackageVersion(in); got != want {
+			t.Errorf("trimPackageVersion(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestInstallCommand(t *testing.T) {
+	tests := []struct {
+		manager, pkg, want string
+	}{
+		{"pkg", "stow", "sudo pkg install stow"},
+		{"pkg_add", "make", "doas pkg_add gmake"},
+		{"apt", "fd", "sudo apt install fd-find"},
+		{"brew", "stow", "brew install stow"},
+		{"none", "stow", ""},
+	}
+	for _, tt := range tests {
+		if got := InstallCommand(tt.manager, tt.pkg); got != tt.want {
+			t.Errorf("InstallCommand(%q, %q) = %q, want %q", tt.manager, tt.pkg, got, tt.want)
+		}
+	}
+}
+
 func TestCommandExists(t *testing.T) {
 	// Test with a command that should exist on all systems
 	if !commandExists("sh") {
@@ -2,14 +2,16 @@ package platform
 
 import (
 	"fmt"
-	"os/exec"
 	"strings"
 
+	"github.com/nvandessel/go4dot/internal/transcript"
 	"github.com/nvandessel/go4dot/internal/validation"
 )
 
 // YumManager implements PackageManager for YUM (RHEL 7, CentOS 7)
-type YumManager struct{}
+type YumManager struct {
+	privileged
+}
 
 func (y *YumManager) Name() string {
 	return "yum"
@@ -40,17 +42,38 @@ func (y *YumManager) Install(packages ...string) error {
 	args := []string{"install", "-y"}
 	args = append(args, mapped...)
 
-	cmd := exec.Command("sudo", append([]string{"yum"}, args...)...)
+	cmd := y.command("yum", args...)
 	cmd.Stdout = nil
 	cmd.Stderr = nil
 
-	if err := cmd.Run(); err != nil {
+	if err := transcript.Run(cmd); err != nil {
 		return fmt.Errorf("failed to install packages: %w", err)
 	}
 
 	return nil
 }
 
+// Upgrade upgrades installed packages, which Install leaves alone
+func (y *YumManager) Upgrade(packages ...string) error {
+	if len(packages) == 0 {
+		return nil
+	}
+	mapped, err := mapPackages("yum", packages)
+	if err != nil {
+		return err
+	}
+
+	cmd := y.command("yum", append([]string{"update", "-y"}, mapped...)...)
+	cmd.Stdout = nil
+	cmd.Stderr = nil
+
+	if err := transcript.Run(cmd); err != nil {
+		return fmt.Errorf("failed to upgrade packages: %w", err)
+	}
+
+	return nil
+}
+
 func (y *YumManager) IsInstalled(pkg string) bool {
 	pkg = MapPackageName(pkg, "yum")
 	output, err := runCommand("rpm", "-q", pkg)
@@ -61,8 +84,8 @@ func (y *YumManager) IsInstalled(pkg string) bool {
 }
 
 func (y *YumManager) Update() error {
-	cmd := exec.Command("sudo", "yum", "check-update", "-y")
-	_ = cmd.Run()
+	cmd := y.command("yum", "check-update", "-y")
+	_ = transcript.Run(cmd)
 	return nil
 }
 
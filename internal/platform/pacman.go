@@ -0,0 +1,56 @@
+package platform
+
+import (
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Where pacman keeps what decides whether installs find their packages.
+// They are variables so tests can point them at a temp dir.
+var (
+	PacmanSyncDir    = "/var/lib/pacman/sync"
+	PacmanMirrorlist = "/etc/pacman.d/mirrorlist"
+)
+
+// PacmanSyncStaleAfter is how old the sync databases may get before an
+// install is likely to ask mirrors for package versions they no longer have
+const PacmanSyncStaleAfter = 7 * 24 * time.Hour
+
+// PacmanMirrorlistStaleAfter is how old the mirrorlist may get before its
+// mirrors are likely to be out of date or gone
+const PacmanMirrorlistStaleAfter = 90 * 24 * time.Hour
+
+// PacmanStatus is how fresh pacman's package information is
+type PacmanStatus struct {
+	LastSync   time.Time // When the sync databases were last refreshed (pacman -Sy); zero if never
+	Mirrorlist time.Time // When the mirrorlist was last changed; zero if there is none
+}
+
+// CheckPacman reads when pacman's sync databases were last refreshed, from
+// the newest *.db file in PacmanSyncDir, and when its mirrorlist changed
+func CheckPacman() PacmanStatus {
+	var status PacmanStatus
+	dbs, _ := filepath.Glob(filepath.Join(PacmanSyncDir, "*.db"))
+	for _, db := range dbs {
+		if info, err := os.Stat(db); err == nil && info.ModTime().After(status.LastSync) {
+			status.LastSync = info.ModTime()
+		}
+	}
+	if info, err := os.Stat(PacmanMirrorlist); err == nil {
+		status.Mirrorlist = info.ModTime()
+	}
+	return status
+}
+
+// SyncStale reports whether the sync databases were never refreshed or
+// not within PacmanSyncStaleAfter of now
+func (s PacmanStatus) SyncStale(now time.Time) bool {
+	return s.LastSync.IsZero() || now.Sub(s.LastSync) > PacmanSyncStaleAfter
+}
+
+// MirrorlistStale reports whether the mirrorlist exists and was not
+// changed within PacmanMirrorlistStaleAfter of now
+func (s PacmanStatus) MirrorlistStale(now time.Time) bool {
+	return !s.Mirrorlist.IsZero() && now.Sub(s.Mirrorlist) > PacmanMirrorlistStaleAfter
+}
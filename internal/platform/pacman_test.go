@@ -0,0 +1,54 @@
+package platform
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestCheckPacman(t *testing.T) {
+	dir := t.TempDir()
+	oldSync, oldMirrorlist := PacmanSyncDir, PacmanMirrorlist
+	PacmanSyncDir = filepath.Join(dir, "sync")
+	PacmanMirrorlist = filepath.Join(dir, "mirrorlist")
+	t.Cleanup(func() { PacmanSyncDir, PacmanMirrorlist = oldSync, oldMirrorlist })
+
+	now := time.Now()
+	if status := CheckPacman(); !status.LastSync.IsZero() || !status.SyncStale(now) || status.MirrorlistStale(now) {
+		t.Errorf("CheckPacman() without databases = %+v, want never synced and no mirrorlist", status)
+	}
+
+	if err := os.MkdirAll(PacmanSyncDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	touch := func(path string, at time.Time) {
+		t.Helper()
+		if err := os.WriteFile(path, nil, 0644); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Chtimes(path, at, at); err != nil {
+			t.Fatal(err)
+		}
+	}
+	core := now.Add(-10 * 24 * time.Hour).Truncate(time.Second)
+	extra := now.Add(-2 * 24 * time.Hour).Truncate(time.Second)
+	touch(filepath.Join(PacmanSyncDir, "core.db"), core)
+	touch(filepath.Join(PacmanSyncDir, "extra.db"), extra)
+	touch(filepath.Join(PacmanSyncDir, "extra.files"), now)
+	touch(PacmanMirrorlist, now.Add(-120*24*time.Hour))
+
+	status := CheckPacman()
+	if !status.LastSync.Equal(extra) {
+		t.Errorf("LastSync = %v, want the newest database %v", status.LastSync, extra)
+	}
+	if status.SyncStale(now) {
+		t.Error("SyncStale() = true for databases refreshed 2 days ago")
+	}
+	if !status.SyncStale(now.Add(6 * 24 * time.Hour)) {
+		t.Error("SyncStale() = false for databases refreshed 8 days ago")
+	}
+	if !status.MirrorlistStale(now) {
+		t.Error("MirrorlistStale() = false for a mirrorlist changed 120 days ago")
+	}
+}
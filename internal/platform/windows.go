@@ -0,0 +1,56 @@
+package platform
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+)
+
+// EnvWindowsHome names the Windows user's profile directory as seen from
+// WSL, e.g. /mnt/c/Users/me, for when go4dot can't ask Windows for it
+const EnvWindowsHome = "GO4DOT_WINDOWS_HOME"
+
+// InWSL reports whether go4dot runs under Windows Subsystem for Linux
+func InWSL() bool {
+	return detectWSL()
+}
+
+var (
+	windowsHomeOnce sync.Once
+	windowsHome     string
+	windowsHomeErr  error
+)
+
+// WindowsHome returns the Windows user's profile directory as seen from
+// WSL, e.g. /mnt/c/Users/me. GO4DOT_WINDOWS_HOME wins; otherwise Windows is
+// asked for %USERPROFILE% through cmd.exe, once per run.
+func WindowsHome() (string, error) {
+	if dir := os.Getenv(EnvWindowsHome); dir != "" {
+		return dir, nil
+	}
+	windowsHomeOnce.Do(func() {
+		windowsHome, windowsHomeErr = askWindowsHome()
+	})
+	return windowsHome, windowsHomeErr
+}
+
+// askWindowsHome asks cmd.exe for %USERPROFILE% and converts it to a WSL
+// path with wslpath
+func askWindowsHome() (string, error) {
+	cmd := exec.Command("cmd.exe", "/c", "echo %USERPROFILE%")
+	// cmd.exe warns about and falls back from a Linux working directory
+	cmd.Dir = "/mnt/c"
+	out, err := cmd.Output()
+	profile := strings.TrimSpace(string(out))
+	if err != nil || profile == "" || strings.Contains(profile, "%") {
+		return "", fmt.Errorf("could not ask Windows for the user's profile directory; set %s", EnvWindowsHome)
+	}
+
+	out, err = exec.Command("wslpath", "-u", profile).Output()
+	if err != nil {
+		return "", fmt.Errorf("wslpath failed for %s: %w", profile, err)
+	}
+	return strings.TrimSpace(string(out)), nil
+}
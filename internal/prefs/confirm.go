@@ -0,0 +1,134 @@
+package prefs
+
+import (
+	"fmt"
+	"sync"
+)
+
+// DangerLevel classifies how risky an operation is
+type DangerLevel string
+
+const (
+	// DangerSafe operations only create or refresh symlinks
+	DangerSafe DangerLevel = "safe"
+	// DangerDestructive operations delete files or state
+	DangerDestructive DangerLevel = "destructive"
+)
+
+// ConfirmMode controls when an operation asks for confirmation
+type ConfirmMode string
+
+const (
+	// ConfirmAlways asks every time (default)
+	ConfirmAlways ConfirmMode = "always"
+	// ConfirmSession asks once, then remembers the answer until the process exits
+	ConfirmSession ConfirmMode = "session"
+	// ConfirmNever never asks
+	ConfirmNever ConfirmMode = "never"
+)
+
+// Operation names used for confirmation decisions
+const (
+	OpSync            = "sync"
+	OpInstall         = "install"
+	OpUpdate          = "update"
+	OpDeleteConflicts = "delete-conflicts"
+	OpUninstall       = "uninstall"
+	OpPrune           = "prune"
+)
+
+// operationLevels maps known operations to their danger level.
+// Anything not listed is treated as destructive.
+var operationLevels = map[string]DangerLevel{
+	OpSync:            DangerSafe,
+	OpInstall:         DangerSafe,
+	OpUpdate:          DangerSafe,
+	OpDeleteConflicts: DangerDestructive,
+	OpUninstall:       DangerDestructive,
+	OpPrune:           DangerDestructive,
+}
+
+// ConfirmPolicy holds the confirmation mode for each danger level
+type ConfirmPolicy struct {
+	Safe        ConfirmMode `yaml:"safe"`
+	Destructive ConfirmMode `yaml:"destructive"`
+}
+
+// ClassifyOperation returns the danger level of an operation
+func ClassifyOperation(op string) DangerLevel {
+	if level, ok := operationLevels[op]; ok {
+		return level
+	}
+	return DangerDestructive
+}
+
+// ModeFor returns the confirmation mode for a danger level, defaulting to always
+func (c ConfirmPolicy) ModeFor(level DangerLevel) ConfirmMode {
+	mode := c.Destructive
+	if level == DangerSafe {
+		mode = c.Safe
+	}
+	if mode == "" {
+		return ConfirmAlways
+	}
+	return mode
+}
+
+// Validate checks that both modes are known values
+func (c ConfirmPolicy) Validate() error {
+	for _, mode := range []ConfirmMode{c.Safe, c.Destructive} {
+		switch mode {
+		case "", ConfirmAlways, ConfirmSession, ConfirmNever:
+		default:
+			return fmt.Errorf("unknown confirm mode %q (valid: always, session, never)", mode)
+		}
+	}
+	return nil
+}
+
+// ConfirmTracker tracks which operations were already confirmed so that
+// "session" mode only asks once. It is safe for concurrent use.
+type ConfirmTracker struct {
+	mu        sync.Mutex
+	policy    ConfirmPolicy
+	confirmed map[string]bool
+}
+
+// NewConfirmTracker creates a tracker using the policy from the given preferences
+func NewConfirmTracker(p *Preferences) *ConfirmTracker {
+	s := &ConfirmTracker{confirmed: make(map[string]bool)}
+	if p != nil {
+		s.policy = p.Confirm
+	}
+	return s
+}
+
+// ShouldConfirm reports whether the operation needs an explicit confirmation
+func (s *ConfirmTracker) ShouldConfirm(op string) bool {
+	if s == nil {
+		return true
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	switch s.policy.ModeFor(ClassifyOperation(op)) {
+	case ConfirmNever:
+		return false
+	case ConfirmSession:
+		return !s.confirmed[op]
+	default:
+		return true
+	}
+}
+
+// Record marks an operation as confirmed for the rest of the session
+func (s *ConfirmTracker) Record(op string) {
+	if s == nil {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.confirmed[op] = true
+}
@@ -0,0 +1,97 @@
+package prefs
+
+import "testing"
+
+func TestClassifyOperation(t *testing.T) {
+	tests := []struct {
+		op   string
+		want DangerLevel
+	}{
+		{OpSync, DangerSafe},
+		{OpInstall, DangerSafe},
+		{OpUninstall, DangerDestructive},
+		{OpDeleteConflicts, DangerDestructive},
+		{"something-new", DangerDestructive},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.op, func(t *testing.T) {
+			if got := ClassifyOperation(tt.op); got != tt.want {
+				t.Errorf("ClassifyOperation(%q) = %q, want %q", tt.op, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConfirmTracker_ShouldConfirm(t *testing.T) {
+	tests := []struct {
+		name         string
+		policy       ConfirmPolicy
+		op           string
+		record       bool
+		wantBefore   bool
+		wantAfterRec bool
+	}{
+		{
+			name:         "default always asks",
+			op:           OpUninstall,
+			record:       true,
+			wantBefore:   true,
+			wantAfterRec: true,
+		},
+		{
+			name:         "session asks once",
+			policy:       ConfirmPolicy{Destructive: ConfirmSession},
+			op:           OpDeleteConflicts,
+			record:       true,
+			wantBefore:   true,
+			wantAfterRec: false,
+		},
+		{
+			name:         "never skips safe ops",
+			policy:       ConfirmPolicy{Safe: ConfirmNever},
+			op:           OpSync,
+			wantBefore:   false,
+			wantAfterRec: false,
+		},
+		{
+			name:         "safe policy does not affect destructive ops",
+			policy:       ConfirmPolicy{Safe: ConfirmNever},
+			op:           OpUninstall,
+			wantBefore:   true,
+			wantAfterRec: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tr := NewConfirmTracker(&Preferences{Confirm: tt.policy})
+			if got := tr.ShouldConfirm(tt.op); got != tt.wantBefore {
+				t.Errorf("ShouldConfirm() before = %v, want %v", got, tt.wantBefore)
+			}
+			if tt.record {
+				tr.Record(tt.op)
+			}
+			if got := tr.ShouldConfirm(tt.op); got != tt.wantAfterRec {
+				t.Errorf("ShouldConfirm() after = %v, want %v", got, tt.wantAfterRec)
+			}
+		})
+	}
+}
+
+func TestConfirmTracker_Nil(t *testing.T) {
+	var tr *ConfirmTracker
+	if !tr.ShouldConfirm(OpSync) {
+		t.Error("nil tracker should always confirm")
+	}
+	tr.Record(OpSync) // must not panic
+}
+
+func TestConfirmPolicy_Validate(t *testing.T) {
+	if err := (ConfirmPolicy{Safe: ConfirmNever, Destructive: ConfirmSession}).Validate(); err != nil {
+		t.Errorf("Validate() error = %v", err)
+	}
+	if err := (ConfirmPolicy{Destructive: "sometimes"}).Validate(); err == nil {
+		t.Error("Validate() should reject unknown mode")
+	}
+}
@@ -0,0 +1,77 @@
+package prefs
+
+import (
+	"fmt"
+	"sort"
+)
+
+// MaxMacroKeys caps how many keystrokes a single macro may replay
+const MaxMacroKeys = 64
+
+// MacroSlots lists the keys dashboard macros can be bound to. They are the
+// number keys not already used to jump between panels.
+var MacroSlots = []string{"7", "8", "9"}
+
+// Macros maps a macro slot to the keystrokes it replays, in the form
+// Bubble Tea prints them ("j", "enter", "ctrl+a", ...).
+type Macros map[string][]string
+
+// IsMacroSlot reports whether key can have a macro bound to it
+func IsMacroSlot(key string) bool {
+	for _, s := range MacroSlots {
+		if s == key {
+			return true
+		}
+	}
+	return false
+}
+
+// Validate checks that every macro is bound to a known slot and has a
+// replayable length
+func (m Macros) Validate() error {
+	slots := make([]string, 0, len(m))
+	for slot := range m {
+		slots = append(slots, slot)
+	}
+	sort.Strings(slots)
+
+	for _, slot := range slots {
+		if !IsMacroSlot(slot) {
+			return fmt.Errorf("unknown slot %q (valid: %v)", slot, MacroSlots)
+		}
+		n := len(m[slot])
+		if n == 0 {
+			return fmt.Errorf("slot %s has no keys", slot)
+		}
+		if n > MaxMacroKeys {
+			return fmt.Errorf("slot %s has %d keys, at most %d allowed", slot, n, MaxMacroKeys)
+		}
+	}
+	return nil
+}
+
+// Macro returns the keystrokes bound to slot, or nil
+func (p *Preferences) Macro(slot string) []string {
+	if p == nil {
+		return nil
+	}
+	return p.Macros[slot]
+}
+
+// SetMacro binds keys to slot, replacing any previous macro there
+func (p *Preferences) SetMacro(slot string, keys []string) error {
+	if !IsMacroSlot(slot) {
+		return fmt.Errorf("unknown macro slot %q (valid: %v)", slot, MacroSlots)
+	}
+	if len(keys) == 0 {
+		return fmt.Errorf("macro has no keys")
+	}
+	if len(keys) > MaxMacroKeys {
+		return fmt.Errorf("macro has %d keys, at most %d allowed", len(keys), MaxMacroKeys)
+	}
+	if p.Macros == nil {
+		p.Macros = Macros{}
+	}
+	p.Macros[slot] = append([]string(nil), keys...)
+	return nil
+}
@@ -0,0 +1,87 @@
+package prefs
+
+import (
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestMacros_Validate(t *testing.T) {
+	tooLong := make([]string, MaxMacroKeys+1)
+	for i := range tooLong {
+		tooLong[i] = "j"
+	}
+
+	tests := []struct {
+		name    string
+		macros  Macros
+		wantErr bool
+	}{
+		{name: "none", macros: nil},
+		{name: "valid", macros: Macros{"7": {"/", "w", "enter"}, "9": {"s"}}},
+		{name: "panel key slot", macros: Macros{"1": {"s"}}, wantErr: true},
+		{name: "empty sequence", macros: Macros{"8": {}}, wantErr: true},
+		{name: "too long", macros: Macros{"8": tooLong}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.macros.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestSetMacro(t *testing.T) {
+	p := Default()
+	keys := []string{"/", "w", "enter", "A", "S"}
+	if err := p.SetMacro("7", keys); err != nil {
+		t.Fatalf("SetMacro() error = %v", err)
+	}
+	keys[0] = "changed"
+	if got := p.Macro("7"); got[0] != "/" {
+		t.Errorf("Macro(7) shares the caller's slice: %v", got)
+	}
+	if err := p.SetMacro("0", []string{"s"}); err == nil {
+		t.Error("SetMacro() should reject a panel key")
+	}
+	if err := p.SetMacro("8", nil); err == nil {
+		t.Error("SetMacro() should reject an empty macro")
+	}
+
+	var nilPrefs *Preferences
+	if nilPrefs.Macro("7") != nil {
+		t.Error("nil preferences should have no macros")
+	}
+}
+
+func TestMacros_SaveAndLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), PrefsFileName)
+
+	p := Default()
+	if err := p.SetMacro("9", []string{"/", "w", "o", "r", "k", "enter", "A", "S"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := p.SaveToFile(path); err != nil {
+		t.Fatalf("SaveToFile() error = %v", err)
+	}
+
+	loaded, err := LoadFromFile(path)
+	if err != nil {
+		t.Fatalf("LoadFromFile() error = %v", err)
+	}
+	if !reflect.DeepEqual(loaded.Macros, p.Macros) {
+		t.Errorf("Macros = %v, want %v", loaded.Macros, p.Macros)
+	}
+
+	// A file with a bad macro is rejected like any other invalid preference
+	if err := (&Preferences{Theme: ThemeDefault, Macros: Macros{"5": {"s"}}}).SaveToFile(path); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadFromFile(path); err == nil || !strings.Contains(err.Error(), "macros") {
+		t.Errorf("LoadFromFile() error = %v, want macros error", err)
+	}
+}
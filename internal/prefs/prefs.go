@@ -0,0 +1,240 @@
+package prefs
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/nvandessel/go4dot/internal/workspace"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// PrefsDir is the directory (relative to home) where user preferences live.
+	// It is shared with the state file so all per-user go4dot data sits together.
+	PrefsDir = ".config/go4dot"
+	// PrefsFileName is the name of the user preferences file
+	PrefsFileName = "config.yaml"
+
+	// ThemeDefault is the built-in color theme
+	ThemeDefault = "mocha"
+)
+
+// Preferences holds user-level settings that apply to every dotfiles repo.
+// They are distinct from the repo's .go4dot.yaml and never committed.
+type Preferences struct {
+	Theme         string        `yaml:"theme"`            // Color theme: mocha (default), latte, mono
+	Parallelism   int           `yaml:"parallelism"`      // Configs a sync restows at once (0 = default)
+	Defaults      Defaults      `yaml:"defaults"`         // Default values for CLI flags
+	Confirm       ConfirmPolicy `yaml:"confirm"`          // When to ask before safe/destructive operations
+	UseTrash      bool          `yaml:"use_trash"`        // Move deleted files to the OS trash instead of unlinking
+	GitGuard      string        `yaml:"git_guard"`        // Before a sync, on uncommitted repo changes: warn (default), strict or off
+	Escalation    string        `yaml:"escalation"`       // How package managers get root: sudo, doas or pkexec (default: first installed)
+	GitHub        bool          `yaml:"github"`           // Fetch release and archive info for externals hosted on GitHub
+	ReducedMotion bool          `yaml:"reduced_motion"`   // Static indicators instead of spinners, no blinking, fewer redraws
+	Macros        Macros        `yaml:"macros,omitempty"` // Recorded dashboard key sequences by slot (7-9)
+
+	// Features turns behavior changes on or off ahead of their default
+	// flipping, by flag name (see internal/feature)
+	Features map[string]bool `yaml:"features,omitempty"`
+
+	// TutorialSeen is set once the dashboard tour has been shown, so it only
+	// opens by itself on the first launch
+	TutorialSeen bool `yaml:"tutorial_seen,omitempty"`
+}
+
+// Defaults holds default values for CLI flags. A flag given explicitly on the
+// command line always takes precedence over these.
+type Defaults struct {
+	DryRun         bool `yaml:"dry_run"`         // Default for --dry-run where supported
+	NonInteractive bool `yaml:"non_interactive"` // Default for --non-interactive
+	Yes            bool `yaml:"yes"`             // Deprecated: duplicates NonInteractive
+	Verbose        bool `yaml:"verbose"`         // Default for --verbose where supported
+}
+
+// Default returns preferences with built-in defaults applied.
+func Default() *Preferences {
+	return &Preferences{
+		Theme: ThemeDefault,
+	}
+}
+
+// GetPrefsPath returns the full path to the preferences file, which is
+// shared by every workspace
+func GetPrefsPath() (string, error) {
+	home, err := workspace.UserHome()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, PrefsDir, PrefsFileName), nil
+}
+
+// Load reads the user preferences from the default location.
+// A missing file is not an error; built-in defaults are returned instead.
+func Load() (*Preferences, error) {
+	path, err := GetPrefsPath()
+	if err != nil {
+		return Default(), err
+	}
+	return LoadFromFile(path)
+}
+
+// LoadFromFile reads user preferences from a specific file.
+// A missing file yields the built-in defaults.
+func LoadFromFile(path string) (*Preferences, error) {
+	p := Default()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return p, nil
+		}
+		return p, fmt.Errorf("failed to read preferences file: %w", err)
+	}
+
+	if err := yaml.Unmarshal(data, p); err != nil {
+		return Default(), fmt.Errorf("failed to parse preferences file: %w", err)
+	}
+
+	if p.Theme == "" {
+		p.Theme = ThemeDefault
+	}
+
+	if err := p.Validate(); err != nil {
+		return Default(), fmt.Errorf("invalid preferences in %s: %w", path, err)
+	}
+
+	return p, nil
+}
+
+// Save writes the preferences to the default location.
+func (p *Preferences) Save() error {
+	path, err := GetPrefsPath()
+	if err != nil {
+		return err
+	}
+	return p.SaveToFile(path)
+}
+
+// SaveToFile writes the preferences to a specific file.
+func (p *Preferences) SaveToFile(path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return fmt.Errorf("failed to create preferences directory: %w", err)
+	}
+
+	data, err := yaml.Marshal(p)
+	if err != nil {
+		return fmt.Errorf("failed to marshal preferences: %w", err)
+	}
+
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		return fmt.Errorf("failed to write preferences file: %w", err)
+	}
+
+	return nil
+}
+
+// Validate checks that preference values are within supported ranges.
+func (p *Preferences) Validate() error {
+	if !IsValidTheme(p.Theme) {
+		return fmt.Errorf("unknown theme %q (valid: %v)", p.Theme, Themes)
+	}
+	if p.Parallelism < 0 {
+		return fmt.Errorf("parallelism must not be negative, got %d", p.Parallelism)
+	}
+	if p.GitGuard != "" && !isValidGitGuard(p.GitGuard) {
+		return fmt.Errorf("unknown git_guard %q (valid: %v)", p.GitGuard, GitGuards)
+	}
+	if p.Escalation != "" && !isValidEscalation(p.Escalation) {
+		return fmt.Errorf("unknown escalation %q (valid: %v)", p.Escalation, Escalations)
+	}
+	if err := p.Confirm.Validate(); err != nil {
+		return fmt.Errorf("confirm: %w", err)
+	}
+	if err := p.Macros.Validate(); err != nil {
+		return fmt.Errorf("macros: %w", err)
+	}
+	return nil
+}
+
+// Themes lists the supported color themes
+var Themes = []string{"mocha", "latte", "mono"}
+
+// IsValidTheme reports whether name is a supported theme
+func IsValidTheme(name string) bool {
+	for _, t := range Themes {
+		if t == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Git guard modes: what a sync does when the dotfiles repo has uncommitted
+// changes or an interrupted rebase or merge
+const (
+	GitGuardWarn   = "warn"   // Warn, and list the changes before confirming
+	GitGuardStrict = "strict" // Refuse to sync
+	GitGuardOff    = "off"    // Don't check
+)
+
+// GitGuards lists the supported git guard modes
+var GitGuards = []string{GitGuardWarn, GitGuardStrict, GitGuardOff}
+
+func isValidGitGuard(mode string) bool {
+	for _, m := range GitGuards {
+		if m == mode {
+			return true
+		}
+	}
+	return false
+}
+
+// GitGuardMode returns the git guard mode, warn unless set
+func (p *Preferences) GitGuardMode() string {
+	if p == nil || p.GitGuard == "" {
+		return GitGuardWarn
+	}
+	return p.GitGuard
+}
+
+// Escalations lists the supported privilege escalation tools
+var Escalations = []string{"sudo", "doas", "pkexec"}
+
+func isValidEscalation(tool string) bool {
+	for _, t := range Escalations {
+		if t == tool {
+			return true
+		}
+	}
+	return false
+}
+
+// EscalationTool returns the configured escalation tool, or "" to use the
+// first one installed
+func (p *Preferences) EscalationTool() string {
+	if p == nil {
+		return ""
+	}
+	return p.Escalation
+}
+
+// GitHubMetadata reports whether externals hosted on GitHub are looked up
+// through the GitHub API
+func (p *Preferences) GitHubMetadata() bool {
+	return p != nil && p.GitHub
+}
+
+// TrashEnabled reports whether deletions should go to the OS trash
+func (p *Preferences) TrashEnabled() bool {
+	return p != nil && p.UseTrash
+}
+
+// Workers returns how many configs to restow at once, or 0 when the
+// preference is unset and the caller's default applies
+func (p *Preferences) Workers() int {
+	if p == nil || p.Parallelism <= 0 {
+		return 0
+	}
+	return p.Parallelism
+}
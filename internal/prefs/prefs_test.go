@@ -0,0 +1,149 @@
+package prefs
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadFromFile_Missing(t *testing.T) {
+	p, err := LoadFromFile(filepath.Join(t.TempDir(), "nope.yaml"))
+	if err != nil {
+		t.Fatalf("LoadFromFile() error = %v, want nil", err)
+	}
+	if p.Theme != ThemeDefault {
+		t.Errorf("Theme = %q, want %q", p.Theme, ThemeDefault)
+	}
+	if p.Workers() != 0 {
+		t.Errorf("Workers() = %d, want 0 for the caller's default", p.Workers())
+	}
+}
+
+func TestLoadFromFile(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		wantErr bool
+		check   func(t *testing.T, p *Preferences)
+	}{
+		{
+			name: "full file",
+			content: `theme: latte
+parallelism: 4
+defaults:
+  dry_run: true
+  yes: true
+`,
+			check: func(t *testing.T, p *Preferences) {
+				if p.Theme != "latte" {
+					t.Errorf("Theme = %q, want latte", p.Theme)
+				}
+				if p.Workers() != 4 {
+					t.Errorf("Workers() = %d, want 4", p.Workers())
+				}
+				if !p.Defaults.DryRun || !p.Defaults.Yes {
+					t.Errorf("Defaults = %+v, want dry_run and yes set", p.Defaults)
+				}
+			},
+		},
+		{
+			name:    "empty theme uses default",
+			content: "parallelism: 2\n",
+			check: func(t *testing.T, p *Preferences) {
+				if p.Theme != ThemeDefault {
+					t.Errorf("Theme = %q, want %q", p.Theme, ThemeDefault)
+				}
+				if p.GitGuardMode() != GitGuardWarn {
+					t.Errorf("GitGuardMode() = %q, want %q", p.GitGuardMode(), GitGuardWarn)
+				}
+			},
+		},
+		{
+			name:    "unknown theme",
+			content: "theme: neon\n",
+			wantErr: true,
+		},
+		{
+			name:    "strict git guard",
+			content: "git_guard: strict\n",
+			check: func(t *testing.T, p *Preferences) {
+				if p.GitGuardMode() != GitGuardStrict {
+					t.Errorf("GitGuardMode() = %q, want %q", p.GitGuardMode(), GitGuardStrict)
+				}
+			},
+		},
+		{
+			name:    "unknown git guard",
+			content: "git_guard: sometimes\n",
+			wantErr: true,
+		},
+		{
+			name:    "doas escalation",
+			content: "escalation: doas\n",
+			check: func(t *testing.T, p *Preferences) {
+				if p.EscalationTool() != "doas" {
+					t.Errorf("EscalationTool() = %q, want doas", p.EscalationTool())
+				}
+			},
+		},
+		{
+			name:    "unknown escalation",
+			content: "escalation: su\n",
+			wantErr: true,
+		},
+		{
+			name:    "negative parallelism",
+			content: "parallelism: -2\n",
+			wantErr: true,
+		},
+		{
+			name:    "invalid yaml",
+			content: "theme: [\n",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), PrefsFileName)
+			if err := os.WriteFile(path, []byte(tt.content), 0600); err != nil {
+				t.Fatal(err)
+			}
+
+			p, err := LoadFromFile(path)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("LoadFromFile() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if p == nil {
+				t.Fatal("LoadFromFile() returned nil preferences")
+			}
+			if tt.check != nil {
+				tt.check(t, p)
+			}
+		})
+	}
+}
+
+func TestSaveAndLoad(t *testing.T) {
+	tmpDir := t.TempDir()
+	t.Setenv("HOME", tmpDir)
+
+	p := Default()
+	p.Parallelism = 3
+	p.Defaults.Verbose = true
+	if err := p.Save(); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	if _, err := os.Stat(filepath.Join(tmpDir, PrefsDir, PrefsFileName)); err != nil {
+		t.Fatalf("preferences file not written: %v", err)
+	}
+
+	loaded, err := Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if loaded.Parallelism != 3 || !loaded.Defaults.Verbose {
+		t.Errorf("Load() = %+v, want parallelism 3 and verbose", loaded)
+	}
+}
@@ -0,0 +1,104 @@
+// Package progress describes what long-running operations report as they
+// go, such as installing dependencies or cloning externals, so that the
+// dashboard and the CLI render the same events each in their own way.
+package progress
+
+import "fmt"
+
+// Level is how an event turned out
+type Level string
+
+const (
+	Info    Level = "info"    // Work starting or a note
+	Success Level = "success" // An item is done
+	Skipped Level = "skipped" // An item was left alone, e.g. already present
+	Warning Level = "warning" // Something went wrong without failing the item
+	Error   Level = "error"   // An item failed
+)
+
+// Category names what kind of item an event is about
+const (
+	CategoryDependency = "dependency"
+	CategoryExternal   = "external"
+	CategoryBase       = "base"
+	CategoryPackages   = "packages" // The package manager itself, e.g. refreshing its cache
+)
+
+// Event is one progress report
+type Event struct {
+	Level    Level
+	Category string // What kind of item, e.g. CategoryExternal
+	Item     string // The item's name; empty for events about the whole operation
+	Current  int    // Position of the item among Total, from 1; 0 when not counting
+	Total    int
+	Message  string // What happened, without a status icon
+	Running  bool   // The item is being worked on; a later event tells how it went
+}
+
+// Percent returns how far the operation is through its items, from 0 to
+// 100, or -1 when it is not counting
+func (e Event) Percent() int {
+	if e.Total <= 0 || e.Current <= 0 {
+		return -1
+	}
+	return e.Current * 100 / e.Total
+}
+
+// Icon returns the status icon of the event's level
+func (e Event) Icon() string {
+	switch e.Level {
+	case Success:
+		return "✓"
+	case Skipped:
+		return "⊘"
+	case Warning:
+		return "⚠"
+	case Error:
+		return "✗"
+	}
+	return ""
+}
+
+// String renders the event as a line of plain text, its message after its
+// status icon
+func (e Event) String() string {
+	if icon := e.Icon(); icon != "" {
+		return icon + " " + e.Message
+	}
+	return e.Message
+}
+
+// Func receives progress events
+type Func func(Event)
+
+// Emit sends e to onEvent, or when that is nil, its text to onProgress, the
+// older callback that takes plain strings
+func Emit(onEvent Func, onProgress func(current, total int, msg string), e Event) {
+	if onEvent != nil {
+		onEvent(e)
+		return
+	}
+	if onProgress != nil {
+		onProgress(e.Current, e.Total, e.String())
+	}
+}
+
+// Itemf returns an event about item number current of total
+func Itemf(level Level, category, item string, current, total int, format string, a ...interface{}) Event {
+	return Event{
+		Level:    level,
+		Category: category,
+		Item:     item,
+		Current:  current,
+		Total:    total,
+		Message:  fmt.Sprintf(format, a...),
+	}
+}
+
+// Started returns an event that work on item number current of total has
+// begun
+func Started(category, item string, current, total int, format string, a ...interface{}) Event {
+	e := Itemf(Info, category, item, current, total, format, a...)
+	e.Running = true
+	return e
+}
@@ -0,0 +1,56 @@
+package progress
+
+import "testing"
+
+func TestEvent_Percent(t *testing.T) {
+	tests := []struct {
+		current, total, want int
+	}{
+		{1, 4, 25},
+		{4, 4, 100},
+		{0, 4, -1},
+		{0, 0, -1},
+	}
+	for _, tt := range tests {
+		e := Event{Current: tt.current, Total: tt.total}
+		if got := e.Percent(); got != tt.want {
+			t.Errorf("Percent(%d/%d) = %d, want %d", tt.current, tt.total, got, tt.want)
+		}
+	}
+}
+
+func TestEvent_String(t *testing.T) {
+	if got := Itemf(Success, CategoryExternal, "tpm", 1, 2, "Cloned %s", "tpm").String(); got != "✓ Cloned tpm" {
+		t.Errorf("String() = %q", got)
+	}
+	if got := Started(CategoryExternal, "tpm", 1, 2, "Cloning %s...", "tpm").String(); got != "Cloning tpm..." {
+		t.Errorf("String() = %q", got)
+	}
+}
+
+func TestEmit(t *testing.T) {
+	e := Itemf(Skipped, CategoryDependency, "git", 1, 3, "Skipping %s", "git")
+
+	var got []string
+	Emit(nil, func(current, total int, msg string) {
+		if current != 1 || total != 3 {
+			t.Errorf("counts = %d/%d, want 1/3", current, total)
+		}
+		got = append(got, msg)
+	}, e)
+	if len(got) != 1 || got[0] != "⊘ Skipping git" {
+		t.Errorf("onProgress got %v", got)
+	}
+
+	// The event callback wins over the string one
+	var events []Event
+	Emit(func(e Event) { events = append(events, e) }, func(int, int, string) {
+		t.Error("onProgress should not be called when onEvent is set")
+	}, e)
+	if len(events) != 1 || events[0].Item != "git" {
+		t.Errorf("onEvent got %v", events)
+	}
+
+	// No callbacks is fine
+	Emit(nil, nil, e)
+}
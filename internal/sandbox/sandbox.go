@@ -0,0 +1,138 @@
+// Package sandbox prepares a throwaway home directory and a copy of the
+// dotfiles repo, so an install can run against them and show what it
+// would do without touching the real home.
+package sandbox
+
+import (
+	"fmt"
+	"io/fs"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/nvandessel/go4dot/internal/fsutil"
+	"github.com/nvandessel/go4dot/internal/state"
+	"github.com/nvandessel/go4dot/internal/workspace"
+)
+
+// Sandbox is a temporary directory holding a home and a copy of the repo
+type Sandbox struct {
+	Root     string // Temporary directory holding everything below
+	Home     string // Stands in for $HOME
+	Dotfiles string // Copy of the dotfiles repo
+}
+
+// skipDirs are not copied into the sandbox: VCS metadata is large and no
+// install step reads it
+var skipDirs = map[string]bool{".git": true, ".hg": true, ".jj": true}
+
+// New copies the repo at dotfilesPath into a new sandbox with an empty home
+func New(dotfilesPath string) (*Sandbox, error) {
+	root, err := os.MkdirTemp("", "g4d-sandbox-")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create sandbox: %w", err)
+	}
+	s := &Sandbox{
+		Root:     root,
+		Home:     filepath.Join(root, "home"),
+		Dotfiles: filepath.Join(root, "dotfiles"),
+	}
+	if err := os.Mkdir(s.Home, 0755); err != nil {
+		_ = s.Remove()
+		return nil, fmt.Errorf("failed to create sandbox home: %w", err)
+	}
+	if err := fsutil.CopyTree(dotfilesPath, s.Dotfiles, func(d fs.DirEntry) bool { return skipDirs[d.Name()] }); err != nil {
+		_ = s.Remove()
+		return nil, fmt.Errorf("failed to copy dotfiles into sandbox: %w", err)
+	}
+	return s, nil
+}
+
+// Remove deletes the sandbox
+func (s *Sandbox) Remove() error {
+	return os.RemoveAll(s.Root)
+}
+
+// Env returns environ with HOME pointing into the sandbox. The XDG base
+// directories follow it, and workspace variables, which would point back
+// at the real home, are dropped.
+func (s *Sandbox) Env(environ []string) []string {
+	override := map[string]string{
+		"HOME":            s.Home,
+		"XDG_CONFIG_HOME": filepath.Join(s.Home, ".config"),
+		"XDG_DATA_HOME":   filepath.Join(s.Home, ".local", "share"),
+		"XDG_STATE_HOME":  filepath.Join(s.Home, ".local", "state"),
+		"XDG_CACHE_HOME":  filepath.Join(s.Home, ".cache"),
+	}
+	drop := map[string]bool{workspace.EnvUserHome: true, workspace.EnvName: true}
+
+	var env []string
+	for _, kv := range environ {
+		name, _, _ := strings.Cut(kv, "=")
+		if _, ok := override[name]; ok || drop[name] {
+			continue
+		}
+		env = append(env, kv)
+	}
+	for _, name := range slices.Sorted(maps.Keys(override)) {
+		env = append(env, name+"="+override[name])
+	}
+	return env
+}
+
+// Change is a file the install left in the sandbox home
+type Change struct {
+	Path   string // Relative to the home
+	Target string // Where a symlink points, with the sandbox repo shown as the real one
+	Dir    bool   // A symlinked directory, or a directory created empty
+}
+
+// Changes lists what the install created in the home: symlinks, files and
+// empty directories, sorted by path. go4dot's own state directory is left
+// out. Link targets into the sandbox copy are rewritten to dotfilesPath,
+// the repo the sandbox was made from.
+func (s *Sandbox) Changes(dotfilesPath string) ([]Change, error) {
+	var changes []Change
+	err := filepath.WalkDir(s.Home, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(s.Home, path)
+		if err != nil || rel == "." {
+			return err
+		}
+		if rel == filepath.FromSlash(state.StateDir) {
+			return filepath.SkipDir
+		}
+
+		switch {
+		case d.Type()&fs.ModeSymlink != 0:
+			target, err := os.Readlink(path)
+			if err != nil {
+				return err
+			}
+			abs := target
+			if !filepath.IsAbs(abs) {
+				abs = filepath.Join(filepath.Dir(path), abs)
+			}
+			if inRepo, err := filepath.Rel(s.Dotfiles, abs); err == nil && !strings.HasPrefix(inRepo, "..") {
+				target = filepath.Join(dotfilesPath, inRepo)
+			}
+			info, statErr := os.Stat(path)
+			changes = append(changes, Change{Path: rel, Target: target, Dir: statErr == nil && info.IsDir()})
+		case d.IsDir():
+			entries, err := os.ReadDir(path)
+			if err == nil && len(entries) == 0 {
+				changes = append(changes, Change{Path: rel, Dir: true})
+			}
+		default:
+			changes = append(changes, Change{Path: rel})
+		}
+		return nil
+	})
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Path < changes[j].Path })
+	return changes, err
+}
@@ -0,0 +1,116 @@
+package sandbox
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/nvandessel/go4dot/internal/workspace"
+)
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestNew(t *testing.T) {
+	repo := t.TempDir()
+	writeFile(t, filepath.Join(repo, ".go4dot.yaml"), "schema_version: \"1.0\"\n")
+	writeFile(t, filepath.Join(repo, "zsh", ".zshrc"), "export EDITOR=vim\n")
+	writeFile(t, filepath.Join(repo, ".git", "HEAD"), "ref: refs/heads/main\n")
+	if err := os.Symlink(".zshrc", filepath.Join(repo, "zsh", ".zprofile")); err != nil {
+		t.Fatal(err)
+	}
+
+	sb, err := New(repo)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	defer func() { _ = sb.Remove() }()
+
+	if data, err := os.ReadFile(filepath.Join(sb.Dotfiles, "zsh", ".zshrc")); err != nil || string(data) != "export EDITOR=vim\n" {
+		t.Errorf("repo file not copied: %q, %v", data, err)
+	}
+	if link, err := os.Readlink(filepath.Join(sb.Dotfiles, "zsh", ".zprofile")); err != nil || link != ".zshrc" {
+		t.Errorf("symlink not kept: %q, %v", link, err)
+	}
+	if _, err := os.Stat(filepath.Join(sb.Dotfiles, ".git")); !os.IsNotExist(err) {
+		t.Error(".git should not be copied")
+	}
+	if entries, err := os.ReadDir(sb.Home); err != nil || len(entries) != 0 {
+		t.Errorf("expected an empty home, got %v, %v", entries, err)
+	}
+
+	if err := sb.Remove(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(sb.Root); !os.IsNotExist(err) {
+		t.Error("Remove should delete the sandbox")
+	}
+}
+
+func TestEnv(t *testing.T) {
+	sb := &Sandbox{Home: "/tmp/sb/home"}
+	env := sb.Env([]string{
+		"HOME=/home/me",
+		"PATH=/usr/bin",
+		"XDG_CONFIG_HOME=/home/me/.config",
+		workspace.EnvUserHome + "=/home/me",
+		workspace.EnvName + "=work",
+	})
+
+	want := []string{
+		"PATH=/usr/bin",
+		"HOME=/tmp/sb/home",
+		"XDG_CACHE_HOME=/tmp/sb/home/.cache",
+		"XDG_CONFIG_HOME=/tmp/sb/home/.config",
+		"XDG_DATA_HOME=/tmp/sb/home/.local/share",
+		"XDG_STATE_HOME=/tmp/sb/home/.local/state",
+	}
+	if !reflect.DeepEqual(env, want) {
+		t.Errorf("Env() = %q, want %q", env, want)
+	}
+}
+
+func TestChanges(t *testing.T) {
+	repo := t.TempDir()
+	writeFile(t, filepath.Join(repo, "nvim", ".config", "nvim", "init.lua"), "")
+	sb, err := New(repo)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	defer func() { _ = sb.Remove() }()
+
+	// What an install might leave behind
+	if err := os.MkdirAll(filepath.Join(sb.Home, ".config"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	rel, _ := filepath.Rel(filepath.Join(sb.Home, ".config"), filepath.Join(sb.Dotfiles, "nvim", ".config", "nvim"))
+	if err := os.Symlink(rel, filepath.Join(sb.Home, ".config", "nvim")); err != nil {
+		t.Fatal(err)
+	}
+	writeFile(t, filepath.Join(sb.Home, ".gitconfig.local"), "[user]\n")
+	writeFile(t, filepath.Join(sb.Home, ".config", "go4dot", "state.json"), "{}")
+	if err := os.MkdirAll(filepath.Join(sb.Home, ".zsh", "plugins"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	changes, err := sb.Changes("/home/me/dotfiles")
+	if err != nil {
+		t.Fatalf("Changes failed: %v", err)
+	}
+	want := []Change{
+		{Path: ".config/nvim", Target: "/home/me/dotfiles/nvim/.config/nvim", Dir: true},
+		{Path: ".gitconfig.local"},
+		{Path: ".zsh/plugins", Dir: true},
+	}
+	if !reflect.DeepEqual(changes, want) {
+		t.Errorf("Changes() = %+v, want %+v", changes, want)
+	}
+}
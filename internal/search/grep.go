@@ -0,0 +1,177 @@
+package search
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+
+	"github.com/nvandessel/go4dot/internal/config"
+	"github.com/nvandessel/go4dot/internal/stow"
+)
+
+// maxFileSize skips files that are unlikely to be hand-written config
+const maxFileSize = 1 << 20
+
+// Match is a single line matching a search pattern
+type Match struct {
+	Config string `json:"config"` // Name of the config owning the file
+	File   string `json:"file"`   // Path relative to the config directory
+	Path   string `json:"path"`   // Absolute path of the file in the dotfiles repo
+	Line   int    `json:"line"`   // 1-based line number
+	Text   string `json:"text"`   // Content of the matching line
+}
+
+// Options configures a content search
+type Options struct {
+	IgnoreCase bool     // Case-insensitive matching
+	Literal    bool     // Treat the pattern as a fixed string instead of a regex
+	Configs    []string // Only search these configs (empty = all)
+	MaxMatches int      // Stop after this many matches (0 = unlimited)
+}
+
+// Grep searches the content of every managed config file for pattern.
+// Files stow would ignore, binary files and files over 1MB are skipped.
+// Matches are returned in config order, then by file path and line.
+func Grep(cfg *config.Config, dotfilesPath, pattern string, opts Options) ([]Match, error) {
+	if pattern == "" {
+		return nil, fmt.Errorf("pattern cannot be empty")
+	}
+
+	re, err := compile(pattern, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	only := make(map[string]bool, len(opts.Configs))
+	for _, name := range opts.Configs {
+		if cfg.GetConfigByName(name) == nil {
+			return nil, cfg.UnknownConfigError(name)
+		}
+		only[name] = true
+	}
+
+	var matches []Match
+	for _, item := range cfg.GetAllConfigs() {
+		if len(only) > 0 && !only[item.Name] {
+			continue
+		}
+
+		found, err := grepConfig(item, dotfilesPath, re, opts.MaxMatches-len(matches))
+		if err != nil {
+			return matches, err
+		}
+		matches = append(matches, found...)
+
+		if opts.MaxMatches > 0 && len(matches) >= opts.MaxMatches {
+			break
+		}
+	}
+
+	return matches, nil
+}
+
+func compile(pattern string, opts Options) (*regexp.Regexp, error) {
+	if opts.Literal {
+		pattern = regexp.QuoteMeta(pattern)
+	}
+	if opts.IgnoreCase {
+		pattern = "(?i)" + pattern
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("invalid pattern: %w", err)
+	}
+	return re, nil
+}
+
+// grepConfig searches one config directory. limit <= 0 means unlimited.
+func grepConfig(item config.ConfigItem, dotfilesPath string, re *regexp.Regexp, limit int) ([]Match, error) {
+	configDir := item.Dir(dotfilesPath)
+	if _, err := os.Stat(configDir); os.IsNotExist(err) {
+		return nil, nil
+	}
+
+	ignore, err := stow.LoadIgnoreList(configDir)
+	if err != nil {
+		return nil, fmt.Errorf("config '%s': %w", item.Name, err)
+	}
+
+	var matches []Match
+	err = filepath.WalkDir(configDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil // Skip unreadable entries
+		}
+		if path == configDir {
+			return nil
+		}
+
+		relPath, _ := filepath.Rel(configDir, path)
+		if ignore.Match(relPath) {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+
+		found, err := grepFile(path, re)
+		if err != nil {
+			return nil // Skip unreadable files
+		}
+		for _, m := range found {
+			m.Config = item.Name
+			m.File = relPath
+			matches = append(matches, m)
+			if limit > 0 && len(matches) >= limit {
+				return filepath.SkipAll
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return matches, err
+	}
+
+	return matches, nil
+}
+
+// grepFile returns the matching lines of a text file
+func grepFile(path string, re *regexp.Regexp) ([]Match, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if info.Size() > maxFileSize {
+		return nil, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if bytes.IndexByte(data, 0) >= 0 {
+		return nil, nil // Binary
+	}
+
+	var matches []Match
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), maxFileSize)
+	line := 0
+	for scanner.Scan() {
+		line++
+		if re.Match(scanner.Bytes()) {
+			matches = append(matches, Match{
+				Path: path,
+				Line: line,
+				Text: scanner.Text(),
+			})
+		}
+	}
+	return matches, scanner.Err()
+}
@@ -0,0 +1,137 @@
+package search
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/nvandessel/go4dot/internal/config"
+)
+
+func setupRepo(t *testing.T) (*config.Config, string) {
+	t.Helper()
+	t.Setenv("HOME", t.TempDir())
+	dotfiles := t.TempDir()
+
+	files := map[string]string{
+		"zsh/.zshrc":              "export EDITOR=nvim\nalias ll='ls -l'\n",
+		"zsh/README.md":           "Set EDITOR here\n",
+		"zsh/.git/config":         "EDITOR in git dir\n",
+		"git/.gitconfig":          "[core]\n\teditor = nvim\n",
+		"bin/.local/bin/tool":     "EDITOR\x00binary",
+		"nvim/.config/nvim/a.lua": "vim.opt.number = true\n",
+	}
+	for rel, content := range files {
+		path := filepath.Join(dotfiles, rel)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	cfg := &config.Config{
+		Configs: config.ConfigGroups{
+			Core: []config.ConfigItem{
+				{Name: "zsh", Path: "zsh"},
+				{Name: "git", Path: "git"},
+				{Name: "bin", Path: "bin"},
+			},
+			Optional: []config.ConfigItem{
+				{Name: "nvim", Path: "nvim"},
+				{Name: "missing", Path: "missing"},
+			},
+		},
+	}
+	return cfg, dotfiles
+}
+
+func TestGrep(t *testing.T) {
+	cfg, dotfiles := setupRepo(t)
+
+	tests := []struct {
+		name    string
+		pattern string
+		opts    Options
+		want    []string // config:file:line
+		wantErr bool
+	}{
+		{
+			name:    "Skips ignored and binary files",
+			pattern: "EDITOR",
+			want:    []string{"zsh:.zshrc:1"},
+		},
+		{
+			name:    "Ignore case",
+			pattern: "editor",
+			opts:    Options{IgnoreCase: true},
+			want:    []string{"zsh:.zshrc:1", "git:.gitconfig:2"},
+		},
+		{
+			name:    "Regex",
+			pattern: `^alias \w+=`,
+			want:    []string{"zsh:.zshrc:2"},
+		},
+		{
+			name:    "Literal",
+			pattern: "vim.opt.",
+			opts:    Options{Literal: true},
+			want:    []string{"nvim:.config/nvim/a.lua:1"},
+		},
+		{
+			name:    "Limit to configs",
+			pattern: "nvim",
+			opts:    Options{Configs: []string{"git"}},
+			want:    []string{"git:.gitconfig:2"},
+		},
+		{
+			name:    "Max matches",
+			pattern: "nvim",
+			opts:    Options{MaxMatches: 1},
+			want:    []string{"zsh:.zshrc:1"},
+		},
+		{
+			name:    "Unknown config",
+			pattern: "x",
+			opts:    Options{Configs: []string{"nope"}},
+			wantErr: true,
+		},
+		{
+			name:    "Invalid regex",
+			pattern: "(",
+			wantErr: true,
+		},
+		{
+			name:    "Empty pattern",
+			pattern: "",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			matches, err := Grep(cfg, dotfiles, tt.pattern, tt.opts)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Grep() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			var got []string
+			for _, m := range matches {
+				got = append(got, fmt.Sprintf("%s:%s:%d", m.Config, filepath.ToSlash(m.File), m.Line))
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("Grep() = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("match %d = %s, want %s", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,94 @@
+package setup
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/nvandessel/go4dot/internal/config"
+	"github.com/nvandessel/go4dot/internal/state"
+	"github.com/nvandessel/go4dot/internal/stow"
+)
+
+// ArchiveConfig marks a config archived: true in .go4dot.yaml, so sync,
+// doctor and status leave it out while its files stay in the repo. Its links
+// are removed first, along with its state entry.
+func ArchiveConfig(cfg *config.Config, configPath string, st *state.State, name string, opts RefactorOptions) error {
+	item := cfg.GetConfigByName(name)
+	if item == nil {
+		if cfg.GetArchivedConfig(name) != nil {
+			return fmt.Errorf("config '%s' is already archived", name)
+		}
+		return cfg.UnknownConfigError(name)
+	}
+	if item.Root != "" {
+		return fmt.Errorf("config '%s' comes from a base repo; archive it there", name)
+	}
+
+	report := func(msg string) {
+		if opts.ProgressFunc != nil {
+			opts.ProgressFunc(0, 0, msg)
+		}
+	}
+
+	dotfilesPath := filepath.Dir(configPath)
+	linked := st != nil && st.HasConfig(name)
+	if status, err := stow.GetConfigLinkStatus(*item, dotfilesPath, cfg.Stow); err == nil && len(status.LinkedFiles) > 0 {
+		linked = true
+	}
+
+	if opts.DryRun {
+		if linked {
+			report(fmt.Sprintf("Would unlink %s", name))
+		}
+		report(fmt.Sprintf("Would archive %s", name))
+		return nil
+	}
+
+	stowOpts := stow.StowOptions{ProgressFunc: opts.ProgressFunc, Settings: cfg.Stow}
+	if linked {
+		if err := stow.Unstow(item.StowDir(dotfilesPath), item.Path, stowOpts); err != nil {
+			return fmt.Errorf("failed to unlink %s: %w", name, err)
+		}
+		report(fmt.Sprintf("✓ Unlinked %s", name))
+	}
+
+	if err := config.SetConfigArchived(configPath, name, true); err != nil {
+		if linked {
+			_ = stow.Stow(item.StowDir(dotfilesPath), item.Path, stowOpts)
+		}
+		return err
+	}
+	report(fmt.Sprintf("✓ Archived %s in %s", name, filepath.Base(configPath)))
+
+	if st != nil && st.HasConfig(name) {
+		st.RemoveConfig(name)
+		if err := st.Save(); err != nil {
+			return fmt.Errorf("config archived but failed to save state: %w", err)
+		}
+	}
+	return nil
+}
+
+// RestoreConfig removes the archived mark from a config in .go4dot.yaml.
+// It is not linked again until the next sync or install.
+func RestoreConfig(cfg *config.Config, configPath string, name string) error {
+	item := cfg.GetArchivedConfig(name)
+	if item == nil {
+		if cfg.GetConfigByName(name) != nil {
+			return fmt.Errorf("config '%s' is not archived", name)
+		}
+		var archived []string
+		for _, a := range cfg.Archived {
+			archived = append(archived, a.Name)
+		}
+		return config.UnknownNameError("archived config", name, archived)
+	}
+	if !item.Archived {
+		// Listed under the top-level archived section rather than marked
+		return fmt.Errorf("config '%s' is in the archived section; move it back to configs in %s", name, filepath.Base(configPath))
+	}
+	if item.Root != "" {
+		return fmt.Errorf("config '%s' comes from a base repo; restore it there", name)
+	}
+	return config.SetConfigArchived(configPath, name, false)
+}
@@ -0,0 +1,84 @@
+package setup
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/nvandessel/go4dot/internal/config"
+	"github.com/nvandessel/go4dot/internal/state"
+)
+
+func TestArchiveConfig(t *testing.T) {
+	configPath, homeDir := setupRefactorRepo(t)
+
+	cfg, err := config.LoadFromPath(configPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	st := state.New()
+	st.AddConfig("nvim", "nvim", true)
+
+	if err := ArchiveConfig(cfg, configPath, st, "nvim", RefactorOptions{}); err != nil {
+		t.Fatalf("ArchiveConfig() error = %v", err)
+	}
+	if _, err := os.Lstat(filepath.Join(homeDir, ".vimrc")); !os.IsNotExist(err) {
+		t.Errorf("link should be removed, Lstat error = %v", err)
+	}
+	if st.HasConfig("nvim") {
+		t.Error("state still lists nvim")
+	}
+
+	cfg, err = config.LoadFromPath(configPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.GetConfigByName("nvim") != nil || cfg.GetArchivedConfig("nvim") == nil {
+		t.Fatalf("nvim not archived: active %+v, archived %+v", cfg.GetAllConfigs(), cfg.Archived)
+	}
+	if _, err := os.Stat(filepath.Join(filepath.Dir(configPath), "nvim", ".vimrc")); err != nil {
+		t.Errorf("archived files should stay in the repo: %v", err)
+	}
+	if err := ArchiveConfig(cfg, configPath, st, "nvim", RefactorOptions{}); err == nil {
+		t.Error("expected an error archiving twice")
+	}
+
+	if err := RestoreConfig(cfg, configPath, "nvim"); err != nil {
+		t.Fatalf("RestoreConfig() error = %v", err)
+	}
+	cfg, err = config.LoadFromPath(configPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.GetConfigByName("nvim") == nil {
+		t.Error("nvim not restored")
+	}
+	if err := RestoreConfig(cfg, configPath, "nvim"); err == nil {
+		t.Error("expected an error restoring an active config")
+	}
+}
+
+func TestArchiveConfig_DryRun(t *testing.T) {
+	configPath, homeDir := setupRefactorRepo(t)
+	original, _ := os.ReadFile(configPath)
+
+	cfg, err := config.LoadFromPath(configPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var msgs []string
+	opts := RefactorOptions{DryRun: true, ProgressFunc: func(_, _ int, msg string) { msgs = append(msgs, msg) }}
+	if err := ArchiveConfig(cfg, configPath, nil, "nvim", opts); err != nil {
+		t.Fatalf("ArchiveConfig() error = %v", err)
+	}
+	if len(msgs) != 2 {
+		t.Errorf("messages = %v, want unlink and archive", msgs)
+	}
+	if data, _ := os.ReadFile(configPath); string(data) != string(original) {
+		t.Errorf("dry run changed the config:\n%s", data)
+	}
+	if _, err := os.Lstat(filepath.Join(homeDir, ".vimrc")); err != nil {
+		t.Errorf("dry run removed the link: %v", err)
+	}
+}
@@ -0,0 +1,302 @@
+package setup
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/nvandessel/go4dot/internal/config"
+	"github.com/nvandessel/go4dot/internal/deps"
+	"github.com/nvandessel/go4dot/internal/hooks"
+	"github.com/nvandessel/go4dot/internal/machine"
+	"github.com/nvandessel/go4dot/internal/platform"
+	"github.com/nvandessel/go4dot/internal/shellinit"
+	"github.com/nvandessel/go4dot/internal/state"
+	"github.com/nvandessel/go4dot/internal/stow"
+)
+
+// DryRunOptions selects what DryRun plans
+type DryRunOptions struct {
+	Configs    []config.ConfigItem  // Configs to link
+	Adopt      bool                 // Files in home that block the configs are adopted into the repo
+	Links      bool                 // Plan the links section too, as a sync of all configs does
+	Deps       bool                 // Plan installing missing dependencies
+	Toolchains bool                 // Plan installing missing toolchains
+	External   []config.ExternalDep // External dependencies to plan cloning
+	Machine    bool                 // Plan generating missing machine configs
+	Shell      bool                 // Plan wiring shell integration into rc files
+	HookStages []string             // Stages whose hooks for Configs to list
+	Platform   *platform.Platform   // Detected when nil
+}
+
+// PlannedPackage is a dependency a dry run would install
+type PlannedPackage struct {
+	Dep       config.DependencyItem
+	Package   string // Name given to the package manager
+	Installed string // Version found, when it is too old
+	Required  string // Version required, when the installed one is too old
+}
+
+// Upgrade reports whether the package is installed but too old
+func (p PlannedPackage) Upgrade() bool {
+	return p.Installed != ""
+}
+
+// PlannedExternal is an external dependency a dry run would clone
+type PlannedExternal struct {
+	Dep  config.ExternalDep
+	Path string // Where it would be cloned
+}
+
+// DryRunResult is what an install or sync would change
+type DryRunResult struct {
+	Platform       *platform.Platform
+	Stow           []stow.Action       // What stow would do per config, with Config set
+	StowFailed     []stow.StowError    // Configs stow would refuse to link, e.g. over existing files
+	Adopt          []stow.ConflictFile // Files in home that would be moved into the repo
+	Links          *stow.LinksResult   // The links section; nil unless planned
+	Packages       []PlannedPackage
+	Manual         []config.DependencyItem // Missing dependencies only the user can install
+	Toolchains     []string                // Version managers and versions, e.g. "rustup" or "node 20"
+	Externals      []PlannedExternal
+	ExternalFailed []deps.ExternalError // External dependencies whose destination is invalid
+	MachineConfigs []machine.MachineConfigStatus
+	ShellRCFiles   []string
+	Hooks          []hooks.Hook // Hooks that would run, in order
+}
+
+// Empty reports whether nothing would change
+func (r *DryRunResult) Empty() bool {
+	links := r.Links != nil && r.Links.HasChanges()
+	return len(r.Stow) == 0 && len(r.StowFailed) == 0 && len(r.Adopt) == 0 && !links &&
+		len(r.Packages) == 0 && len(r.Manual) == 0 && len(r.Toolchains) == 0 &&
+		len(r.Externals) == 0 && len(r.ExternalFailed) == 0 &&
+		len(r.MachineConfigs) == 0 && len(r.ShellRCFiles) == 0 && len(r.Hooks) == 0
+}
+
+// HasErrors reports whether some of the planned changes would fail
+func (r *DryRunResult) HasErrors() bool {
+	return len(r.StowFailed) > 0 || len(r.ExternalFailed) > 0 ||
+		(r.Links != nil && len(r.Links.Failed) > 0)
+}
+
+// Summary counts the planned changes, one line per kind
+func (r *DryRunResult) Summary() string {
+	var summary string
+	if len(r.Stow) > 0 || len(r.StowFailed) > 0 {
+		changes := 0
+		for _, a := range r.Stow {
+			if !a.IsProblem() {
+				changes++
+			}
+		}
+		summary += fmt.Sprintf("Symlinks: %d changes, %d configs would fail\n", changes, len(r.StowFailed))
+	}
+	if len(r.Adopt) > 0 {
+		summary += fmt.Sprintf("Adopt: %d files\n", len(r.Adopt))
+	}
+	if r.Links != nil && r.Links.HasChanges() {
+		summary += fmt.Sprintf("Links: %s\n", r.Links.Summary())
+	}
+	if len(r.Packages) > 0 || len(r.Manual) > 0 {
+		summary += fmt.Sprintf("Packages: %d to install, %d manual\n", len(r.Packages), len(r.Manual))
+	}
+	if len(r.Toolchains) > 0 {
+		summary += fmt.Sprintf("Toolchains: %d to install\n", len(r.Toolchains))
+	}
+	if len(r.Externals) > 0 || len(r.ExternalFailed) > 0 {
+		summary += fmt.Sprintf("External: %d to clone, %d invalid\n", len(r.Externals), len(r.ExternalFailed))
+	}
+	if len(r.MachineConfigs) > 0 {
+		summary += fmt.Sprintf("Machine configs: %d to generate\n", len(r.MachineConfigs))
+	}
+	if len(r.ShellRCFiles) > 0 {
+		summary += fmt.Sprintf("Shell integration: %d rc files to update\n", len(r.ShellRCFiles))
+	}
+	if len(r.Hooks) > 0 {
+		summary += fmt.Sprintf("Hooks: %d to run\n", len(r.Hooks))
+	}
+	if summary == "" {
+		summary = "Nothing to change\n"
+	}
+	return summary
+}
+
+// InstallDryRun works out what Install with opts would change, without
+// changing anything
+func InstallDryRun(cfg *config.Config, dotfilesPath string, opts InstallOptions) (*DryRunResult, error) {
+	p, err := platform.Detect()
+	if err != nil {
+		return nil, fmt.Errorf("failed to detect platform: %w", err)
+	}
+	filtered := filterConfigForPlatform(cfg, p)
+
+	dry := DryRunOptions{
+		Configs:    installPreflightOptions(filtered, p, opts).Configs,
+		Adopt:      opts.Adopt,
+		Deps:       !opts.SkipDeps,
+		Toolchains: !opts.SkipToolchains,
+		Machine:    !opts.SkipMachine,
+		Shell:      true,
+		HookStages: []string{config.HookPreInstall, config.HookPostInstall},
+		Platform:   p,
+	}
+	if !opts.SkipExternal {
+		dry.External = filtered.External
+	}
+	return DryRun(filtered, dotfilesPath, dry)
+}
+
+// DryRun works out what an install or sync would change without changing
+// anything: stow plans the links with -n, dependencies are checked and the
+// destinations of external dependencies, machine configs and rc files are
+// looked at.
+func DryRun(cfg *config.Config, dotfilesPath string, opts DryRunOptions) (*DryRunResult, error) {
+	p := opts.Platform
+	if p == nil {
+		var err error
+		if p, err = platform.Detect(); err != nil {
+			return nil, fmt.Errorf("failed to detect platform: %w", err)
+		}
+	}
+	result := &DryRunResult{Platform: p}
+
+	if err := dryRunStow(cfg, dotfilesPath, opts, result); err != nil {
+		return nil, err
+	}
+	if opts.Links {
+		st, _ := state.Load()
+		result.Links = stow.SyncLinks(cfg, dotfilesPath, st, stow.StowOptions{DryRun: true})
+	}
+
+	if opts.Deps {
+		checkResult, err := deps.Check(cfg, p)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check dependencies: %w", err)
+		}
+		for _, c := range checkResult.GetMissing() {
+			planned := PlannedPackage{Dep: c.Item, Package: deps.PackageName(c.Item, p)}
+			if c.Status == deps.StatusVersionMismatch {
+				planned.Installed, planned.Required = c.InstalledVersion, c.RequiredVersion
+			}
+			result.Packages = append(result.Packages, planned)
+		}
+		for _, c := range checkResult.GetManualMissing() {
+			result.Manual = append(result.Manual, c.Item)
+		}
+	}
+
+	if opts.Toolchains {
+		for _, s := range deps.CheckToolchains(cfg, p) {
+			if s.Status != "missing" {
+				continue
+			}
+			if !s.Manager {
+				result.Toolchains = append(result.Toolchains, deps.ToolchainManager(s.Toolchain.Language))
+			}
+			for _, v := range s.Missing {
+				result.Toolchains = append(result.Toolchains, s.Toolchain.Language+" "+v)
+			}
+		}
+	}
+
+	if len(opts.External) > 0 {
+		scoped := *cfg
+		scoped.External = opts.External
+		for _, s := range deps.CheckExternalStatus(&scoped, p, dotfilesPath) {
+			switch s.Status {
+			case "missing":
+				result.Externals = append(result.Externals, PlannedExternal{Dep: s.Dep, Path: s.Path})
+			case "error":
+				result.ExternalFailed = append(result.ExternalFailed, deps.ExternalError{Dep: s.Dep, Error: fmt.Errorf("%s", s.Reason)})
+			}
+		}
+	}
+
+	if opts.Machine {
+		for _, s := range machine.CheckMachineConfigStatus(cfg) {
+			if s.Status == "missing" {
+				result.MachineConfigs = append(result.MachineConfigs, s)
+			}
+		}
+	}
+
+	if opts.Shell && cfg.ShellIntegration.Enabled {
+		files, err := unwiredRCFiles(cfg)
+		if err != nil {
+			return nil, err
+		}
+		result.ShellRCFiles = files
+	}
+
+	for _, stage := range opts.HookStages {
+		result.Hooks = append(result.Hooks, hooks.For(cfg, stage, configNames(opts.Configs))...)
+	}
+
+	return result, nil
+}
+
+// dryRunStow has stow plan linking each config with -n, recording what it
+// would do. With opts.Adopt the files in the way are recorded as adopted
+// and stow plans as if they were already in the repo.
+func dryRunStow(cfg *config.Config, dotfilesPath string, opts DryRunOptions, result *DryRunResult) error {
+	if len(opts.Configs) == 0 {
+		return nil
+	}
+	if opts.Adopt {
+		conflicts, err := stow.DetectConflicts(cfg, dotfilesPath)
+		if err != nil {
+			return fmt.Errorf("failed to check conflicts: %w", err)
+		}
+		result.Adopt = stow.ConflictsForConfigs(conflicts, opts.Configs)
+	}
+
+	for _, item := range opts.Configs {
+		if _, err := os.Stat(item.Dir(dotfilesPath)); err != nil {
+			result.StowFailed = append(result.StowFailed, stow.StowError{ConfigName: item.Name, Error: fmt.Errorf("directory not found")})
+			continue
+		}
+		name := item.Name
+		err := stow.Stow(item.StowDir(dotfilesPath), item.Path, stow.StowOptions{
+			DryRun:   true,
+			Force:    opts.Adopt,
+			Settings: cfg.Stow,
+			ActionFunc: func(a stow.Action) {
+				a.Config = name
+				result.Stow = append(result.Stow, a)
+			},
+		})
+		if err != nil {
+			result.StowFailed = append(result.StowFailed, stow.StowError{ConfigName: name, Error: firstLine(err)})
+		}
+	}
+	return nil
+}
+
+// unwiredRCFiles returns the rc files that don't source the shell
+// integration snippet yet
+func unwiredRCFiles(cfg *config.Config) ([]string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get home directory: %w", err)
+	}
+	var files []string
+	for _, shell := range cfg.ShellIntegration.ShellsFor(os.Getenv("SHELL")) {
+		path := shellinit.RCFile(shell, home)
+		data, err := os.ReadFile(path)
+		if err != nil && !os.IsNotExist(err) {
+			return nil, fmt.Errorf("failed to read %s: %w", path, err)
+		}
+		if !strings.Contains(string(data), shellinit.Block(shell)) {
+			files = append(files, path)
+		}
+	}
+	return files, nil
+}
+
+// firstLine drops the stow output that errors from stow carry after their
+// first line; the actions already report it
+func firstLine(err error) error {
+	msg, _, _ := strings.Cut(err.Error(), "\n")
+	return fmt.Errorf("%s", msg)
+}
@@ -0,0 +1,83 @@
+package setup
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/nvandessel/go4dot/internal/config"
+	"github.com/nvandessel/go4dot/internal/platform"
+	"github.com/nvandessel/go4dot/internal/stow"
+)
+
+// planningCommander answers stow -n with the actions stow would print
+type planningCommander struct {
+	output string
+	ran    []string
+}
+
+func (c *planningCommander) Run(name string, args ...string) ([]byte, error) {
+	c.ran = append(c.ran, strings.Join(args, " "))
+	return []byte(c.output), nil
+}
+
+func TestDryRun(t *testing.T) {
+	dotfilesDir, homeDir, item := setupPreflightRepo(t)
+
+	commander := &planningCommander{output: "LINK: .config/nvim/init.lua => ../dotfiles/nvim/.config/nvim/init.lua\n" +
+		"WARNING: in simulation mode so not modifying filesystem."}
+	origCommander := stow.CurrentCommander
+	stow.CurrentCommander = commander
+	t.Cleanup(func() { stow.CurrentCommander = origCommander })
+
+	missing := config.ConfigItem{Name: "zsh", Path: "zsh"}
+	opts := DryRunOptions{
+		Configs:  []config.ConfigItem{item, missing},
+		Platform: &platform.Platform{OS: "linux", PackageManager: "apt"},
+	}
+	result, err := DryRun(&config.Config{}, dotfilesDir, opts)
+	if err != nil {
+		t.Fatalf("DryRun() error = %v", err)
+	}
+
+	for _, args := range commander.ran {
+		if !strings.Contains(" "+args+" ", " -n ") {
+			t.Errorf("stow ran without -n: %s", args)
+		}
+	}
+	if len(result.Stow) == 0 || result.Stow[0].Kind != stow.ActionLink || result.Stow[0].Config != "nvim" {
+		t.Errorf("Stow = %+v, want a LINK for nvim", result.Stow)
+	}
+	if len(result.StowFailed) != 1 || result.StowFailed[0].ConfigName != "zsh" {
+		t.Errorf("StowFailed = %+v, want zsh", result.StowFailed)
+	}
+	if !result.HasErrors() {
+		t.Error("HasErrors() = false, want true for a missing config directory")
+	}
+	if got := result.Summary(); got != "Symlinks: 1 changes, 1 configs would fail\n" {
+		t.Errorf("Summary() = %q", got)
+	}
+
+	if _, err := os.Lstat(filepath.Join(homeDir, ".config", "nvim", "init.lua")); !os.IsNotExist(err) {
+		t.Errorf("dry run changed home: %v", err)
+	}
+}
+
+func TestDryRunResult_Empty(t *testing.T) {
+	result := &DryRunResult{}
+	if !result.Empty() || result.HasErrors() {
+		t.Errorf("Empty() = %v, HasErrors() = %v, want true, false", result.Empty(), result.HasErrors())
+	}
+	if got := result.Summary(); got != "Nothing to change\n" {
+		t.Errorf("Summary() = %q", got)
+	}
+
+	result.Packages = []PlannedPackage{{Dep: config.DependencyItem{Name: "ripgrep"}, Package: "ripgrep"}}
+	if result.Empty() {
+		t.Error("Empty() = true with a package to install")
+	}
+	if got := result.Summary(); got != "Packages: 1 to install, 0 manual\n" {
+		t.Errorf("Summary() = %q", got)
+	}
+}
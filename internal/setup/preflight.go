@@ -0,0 +1,260 @@
+package setup
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/nvandessel/go4dot/internal/config"
+	"github.com/nvandessel/go4dot/internal/deps"
+	"github.com/nvandessel/go4dot/internal/platform"
+	"github.com/nvandessel/go4dot/internal/state"
+	"github.com/nvandessel/go4dot/internal/stow"
+)
+
+// PreflightMinFree is the free space Preflight asks for on the filesystems
+// go4dot writes to: enough for state, backups and small external clones
+const PreflightMinFree = 50 << 20
+
+// PreflightOptions selects what Preflight checks
+type PreflightOptions struct {
+	Configs    []config.ConfigItem // Configs about to be linked; the directories their links go in must be writable
+	Deps       bool                // Missing dependencies will be installed, so the package manager must be usable
+	Platform   *platform.Platform  // Platform the dependencies are installed on; required with Deps
+	NoTerminal bool                // Nobody can answer a sudo password prompt
+	Escalation string              // sudo, doas or pkexec; empty uses the first installed
+	MinFree    uint64              // Free bytes required; 0 uses PreflightMinFree
+}
+
+// PreflightProblem is one thing that would make an operation fail partway
+type PreflightProblem struct {
+	Check  string // "permissions", "ownership", "disk space" or "privileges"
+	Path   string // Directory or file the problem is about, if any
+	Detail string
+}
+
+// PreflightError reports every problem Preflight found
+type PreflightError struct {
+	Problems []PreflightProblem
+}
+
+func (e *PreflightError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "preflight found %d problem(s), nothing was changed:", len(e.Problems))
+	for _, p := range e.Problems {
+		if p.Path != "" {
+			fmt.Fprintf(&b, "\n  %s: %s: %s", p.Check, p.Path, p.Detail)
+		} else {
+			fmt.Fprintf(&b, "\n  %s: %s", p.Check, p.Detail)
+		}
+	}
+	return b.String()
+}
+
+// Preflight checks, before anything is changed, that the directories links
+// and state are written to are writable, that their filesystems have room
+// and, when dependencies will be installed, that the package manager can get
+// root. It returns a *PreflightError listing every problem, or nil.
+func Preflight(cfg *config.Config, dotfilesPath string, opts PreflightOptions) error {
+	var problems []PreflightProblem
+
+	home := cfg.Stow.TargetDir()
+	dirs := map[string]bool{}
+	var targets []string
+	for i := range opts.Configs {
+		configDirs, configTargets := linkTargets(&opts.Configs[i], dotfilesPath, home, cfg.Stow)
+		for _, dir := range configDirs {
+			dirs[dir] = true
+		}
+		targets = append(targets, configTargets...)
+	}
+	if stateDir, err := state.GetStateDir(); err == nil {
+		dirs[stateDir] = true
+	}
+
+	sorted := make([]string, 0, len(dirs))
+	for dir := range dirs {
+		sorted = append(sorted, dir)
+	}
+	sort.Strings(sorted)
+
+	// Only the nearest existing directory can be tested; go4dot creates the rest
+	checked := map[string]bool{}
+	for _, dir := range sorted {
+		existing := existingAncestor(dir)
+		if checked[existing] {
+			continue
+		}
+		checked[existing] = true
+		if err := checkWritable(existing); err != nil {
+			problems = append(problems, PreflightProblem{Check: "permissions", Path: existing, Detail: err.Error()})
+		}
+	}
+	if p := checkOwnership(home, sorted, targets); p != nil {
+		problems = append(problems, *p)
+	}
+
+	minFree := opts.MinFree
+	if minFree == 0 {
+		minFree = PreflightMinFree
+	}
+	for _, dir := range []string{home, dotfilesPath} {
+		if dir == "" {
+			continue
+		}
+		free, ok := freeSpace(existingAncestor(dir))
+		if ok && free < minFree {
+			problems = append(problems, PreflightProblem{
+				Check:  "disk space",
+				Path:   dir,
+				Detail: fmt.Sprintf("%s free, need at least %s", stow.FormatSize(int64(free)), stow.FormatSize(int64(minFree))),
+			})
+		}
+	}
+
+	if opts.Deps && opts.Platform != nil {
+		if p := checkPrivileges(cfg, opts); p != nil {
+			problems = append(problems, *p)
+		}
+	}
+
+	if len(problems) > 0 {
+		return &PreflightError{Problems: problems}
+	}
+	return nil
+}
+
+// linkTargets returns the directories under home that item's files link
+// into, and the paths the links themselves go at
+func linkTargets(item *config.ConfigItem, dotfilesPath, home string, settings config.StowSettings) (dirs, targets []string) {
+	root := item.Dir(dotfilesPath)
+	seen := map[string]bool{home: true}
+	dirs = []string{home}
+	_ = filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return nil
+		}
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return nil
+		}
+		target := filepath.Join(home, settings.LinkPath(rel))
+		targets = append(targets, target)
+		if dir := filepath.Dir(target); !seen[dir] {
+			seen[dir] = true
+			dirs = append(dirs, dir)
+		}
+		return nil
+	})
+	return dirs, targets
+}
+
+// existingAncestor returns dir or its nearest parent that exists
+func existingAncestor(dir string) string {
+	for {
+		if _, err := os.Stat(dir); err == nil {
+			return dir
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return dir
+		}
+		dir = parent
+	}
+}
+
+// checkWritable creates and removes a file in dir, which is the only check
+// that also catches read-only mounts and ACLs
+func checkWritable(dir string) error {
+	f, err := os.CreateTemp(dir, ".g4d-preflight-*")
+	if err != nil {
+		if os.IsPermission(err) {
+			return fmt.Errorf("not writable")
+		}
+		return err
+	}
+	name := f.Name()
+	_ = f.Close()
+	return os.Remove(name)
+}
+
+// checkOwnership reports a problem when the directories links or state go
+// in, or the files already at the link targets, belong to another user, as
+// when sudo keeps HOME pointing at the invoking user's home: linking there
+// would clobber that user's files. Root-owned paths are left to the
+// permission check.
+func checkOwnership(home string, dirs, targets []string) *PreflightProblem {
+	current := state.CurrentOwner()
+	paths := append([]string{home}, dirs...)
+	for _, path := range append(paths, targets...) {
+		owner, ok := state.OwnerOf(path)
+		if !ok || owner.UID == current.UID || owner.UID == 0 {
+			continue
+		}
+		return &PreflightProblem{
+			Check:  "ownership",
+			Path:   path,
+			Detail: fmt.Sprintf("belongs to %s, not %s; run go4dot as that user, or with HOME set to your own home", owner, current),
+		}
+	}
+	return nil
+}
+
+// checkPrivileges reports a problem when missing dependencies need a
+// package manager that runs as root and go4dot can't get root for it
+func checkPrivileges(cfg *config.Config, opts PreflightOptions) *PreflightProblem {
+	if isRoot() {
+		return nil
+	}
+	pm, err := platform.GetPackageManager(opts.Platform)
+	if err != nil || !pm.NeedsSudo() {
+		return nil
+	}
+
+	result, err := deps.Check(cfg, opts.Platform)
+	if err != nil {
+		return nil
+	}
+	var installable []string
+	for _, check := range result.GetMissing() {
+		if check.Status != deps.StatusManualMissing {
+			installable = append(installable, check.Item.Name)
+		}
+	}
+	if len(installable) == 0 {
+		return nil
+	}
+	missing := strings.Join(installable, ", ")
+
+	esc, err := platform.FindEscalator(opts.Escalation)
+	if err != nil {
+		return &PreflightProblem{
+			Check:  "privileges",
+			Detail: fmt.Sprintf("%s needs root to install %s, but %v; run as root or use --skip-deps", pm.Name(), missing, err),
+		}
+	}
+	if !esc.NeedsPassword() {
+		return nil
+	}
+	if !esc.CanAuthenticate() {
+		return &PreflightProblem{
+			Check:  "privileges",
+			Detail: fmt.Sprintf("%s needs root to install %s, and %s wants a password go4dot can't pass on; run '%s true' first or use --skip-deps", pm.Name(), missing, esc.Tool, esc.Tool),
+		}
+	}
+	if opts.NoTerminal {
+		return &PreflightProblem{
+			Check:  "privileges",
+			Detail: fmt.Sprintf("%s needs sudo to install %s, and sudo wants a password nobody can type here; run 'sudo -v' first or use --skip-deps", pm.Name(), missing),
+		}
+	}
+	return nil
+}
+
+// isRoot reports whether go4dot runs as root, which needs no sudo
+func isRoot() bool {
+	return os.Geteuid() == 0
+}
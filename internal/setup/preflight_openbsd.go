@@ -0,0 +1,15 @@
+//go:build openbsd
+
+package setup
+
+import "syscall"
+
+// freeSpace returns the bytes available to unprivileged users on the
+// filesystem holding dir. OpenBSD names the statfs fields differently.
+func freeSpace(dir string) (uint64, bool) {
+	var st syscall.Statfs_t
+	if err := syscall.Statfs(dir, &st); err != nil {
+		return 0, false
+	}
+	return uint64(st.F_bavail) * uint64(st.F_bsize), true
+}
@@ -0,0 +1,136 @@
+package setup
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/nvandessel/go4dot/internal/config"
+)
+
+func setupPreflightRepo(t *testing.T) (dotfilesDir, homeDir string, item config.ConfigItem) {
+	t.Helper()
+
+	tmpDir := t.TempDir()
+	homeDir = filepath.Join(tmpDir, "home")
+	dotfilesDir = filepath.Join(tmpDir, "dotfiles")
+	t.Setenv("HOME", homeDir)
+
+	if err := os.MkdirAll(filepath.Join(dotfilesDir, "nvim", ".config", "nvim"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dotfilesDir, "nvim", ".config", "nvim", "init.lua"), []byte("-- nvim"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(homeDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	return dotfilesDir, homeDir, config.ConfigItem{Name: "nvim", Path: "nvim"}
+}
+
+func TestPreflight(t *testing.T) {
+	dotfilesDir, _, item := setupPreflightRepo(t)
+
+	opts := PreflightOptions{Configs: []config.ConfigItem{item}, MinFree: 1}
+	if err := Preflight(&config.Config{}, dotfilesDir, opts); err != nil {
+		t.Errorf("Preflight() error = %v", err)
+	}
+}
+
+func TestPreflight_ReadOnlyTarget(t *testing.T) {
+	if isRoot() {
+		t.Skip("root can write to read-only directories")
+	}
+	dotfilesDir, homeDir, item := setupPreflightRepo(t)
+
+	configDir := filepath.Join(homeDir, ".config")
+	if err := os.Mkdir(configDir, 0555); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = os.Chmod(configDir, 0755) })
+
+	err := Preflight(&config.Config{}, dotfilesDir, PreflightOptions{Configs: []config.ConfigItem{item}, MinFree: 1})
+	var pre *PreflightError
+	if !errors.As(err, &pre) {
+		t.Fatalf("Preflight() error = %v, want a PreflightError", err)
+	}
+	if len(pre.Problems) != 1 || pre.Problems[0].Check != "permissions" || pre.Problems[0].Path != configDir {
+		t.Errorf("problems = %+v, want %s not writable", pre.Problems, configDir)
+	}
+}
+
+func TestPreflight_DiskSpace(t *testing.T) {
+	dotfilesDir, _, _ := setupPreflightRepo(t)
+	if _, ok := freeSpace(dotfilesDir); !ok {
+		t.Skip("free space is not checked on this platform")
+	}
+
+	err := Preflight(&config.Config{}, dotfilesDir, PreflightOptions{MinFree: 1 << 62})
+	if err == nil || !strings.Contains(err.Error(), "disk space") {
+		t.Errorf("Preflight() error = %v, want a disk space problem", err)
+	}
+}
+
+func TestInstallPreflightOptions(t *testing.T) {
+	cfg := &config.Config{Configs: config.ConfigGroups{
+		Core:     []config.ConfigItem{{Name: "git"}},
+		Optional: []config.ConfigItem{{Name: "i3"}},
+	}}
+
+	if got := installPreflightOptions(cfg, nil, InstallOptions{}); len(got.Configs) != 2 || !got.Deps {
+		t.Errorf("full install checks %+v", got)
+	}
+	if got := installPreflightOptions(cfg, nil, InstallOptions{Minimal: true, SkipDeps: true}); len(got.Configs) != 1 || got.Deps {
+		t.Errorf("minimal install without deps checks %+v", got)
+	}
+	if got := installPreflightOptions(cfg, nil, InstallOptions{SkipStow: true}); len(got.Configs) != 0 {
+		t.Errorf("install without stow checks configs %+v", got.Configs)
+	}
+}
+
+func TestPreflight_ForeignHome(t *testing.T) {
+	if !isRoot() {
+		t.Skip("changing a directory's owner needs root")
+	}
+	dotfilesDir, homeDir, item := setupPreflightRepo(t)
+	if err := os.Chown(homeDir, 4242, 4242); err != nil {
+		t.Fatal(err)
+	}
+
+	err := Preflight(&config.Config{}, dotfilesDir, PreflightOptions{Configs: []config.ConfigItem{item}, MinFree: 1})
+	var pre *PreflightError
+	if !errors.As(err, &pre) {
+		t.Fatalf("Preflight() error = %v, want a PreflightError", err)
+	}
+	if len(pre.Problems) != 1 || pre.Problems[0].Check != "ownership" || pre.Problems[0].Path != homeDir {
+		t.Errorf("problems = %+v, want %s owned by another user", pre.Problems, homeDir)
+	}
+}
+
+func TestPreflight_ForeignTarget(t *testing.T) {
+	if !isRoot() {
+		t.Skip("changing a file's owner needs root")
+	}
+	dotfilesDir, homeDir, item := setupPreflightRepo(t)
+	target := filepath.Join(homeDir, ".config", "nvim", "init.lua")
+	if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(target, []byte("-- theirs"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Lchown(target, 4242, 4242); err != nil {
+		t.Fatal(err)
+	}
+
+	err := Preflight(&config.Config{}, dotfilesDir, PreflightOptions{Configs: []config.ConfigItem{item}, MinFree: 1})
+	var pre *PreflightError
+	if !errors.As(err, &pre) {
+		t.Fatalf("Preflight() error = %v, want a PreflightError", err)
+	}
+	if len(pre.Problems) != 1 || pre.Problems[0].Check != "ownership" || pre.Problems[0].Path != target {
+		t.Errorf("problems = %+v, want %s owned by another user", pre.Problems, target)
+	}
+}
@@ -0,0 +1,15 @@
+//go:build !windows && !openbsd
+
+package setup
+
+import "syscall"
+
+// freeSpace returns the bytes available to unprivileged users on the
+// filesystem holding dir
+func freeSpace(dir string) (uint64, bool) {
+	var st syscall.Statfs_t
+	if err := syscall.Statfs(dir, &st); err != nil {
+		return 0, false
+	}
+	return uint64(st.Bavail) * uint64(st.Bsize), true
+}
@@ -0,0 +1,8 @@
+//go:build windows
+
+package setup
+
+// freeSpace is not checked on Windows
+func freeSpace(dir string) (uint64, bool) {
+	return 0, false
+}
@@ -0,0 +1,193 @@
+package setup
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/nvandessel/go4dot/internal/config"
+	"github.com/nvandessel/go4dot/internal/state"
+	"github.com/nvandessel/go4dot/internal/stow"
+	"github.com/nvandessel/go4dot/internal/validation"
+)
+
+// RefactorOptions configures config rename and move operations.
+type RefactorOptions struct {
+	DryRun       bool                                 // Only report what would change
+	ProgressFunc func(current, total int, msg string) // Called for progress updates
+}
+
+// RenameConfig renames a config in .go4dot.yaml and state, along with the
+// depends_on entries naming it. When the config's directory has the same name
+// as the config, the directory is renamed too and its links are restowed.
+func RenameConfig(cfg *config.Config, configPath string, st *state.State, oldName, newName string, opts RefactorOptions) error {
+	item := cfg.GetConfigByName(oldName)
+	if item == nil {
+		return cfg.UnknownConfigError(oldName)
+	}
+	if err := validation.ValidateConfigName(newName); err != nil {
+		return fmt.Errorf("invalid config name: %w", err)
+	}
+	if oldName == newName {
+		return fmt.Errorf("config is already named '%s'", newName)
+	}
+	if cfg.GetConfigByName(newName) != nil {
+		return fmt.Errorf("config '%s' already exists", newName)
+	}
+
+	newPath := item.Path
+	if item.Path == oldName {
+		newPath = newName
+	}
+
+	return relocateConfig(cfg, configPath, st, *item, newName, newPath, opts)
+}
+
+// MoveConfig moves a config's directory within the dotfiles repo, updating
+// .go4dot.yaml and state and restowing its links.
+func MoveConfig(cfg *config.Config, configPath string, st *state.State, name, newPath string, opts RefactorOptions) error {
+	item := cfg.GetConfigByName(name)
+	if item == nil {
+		return cfg.UnknownConfigError(name)
+	}
+	if err := validation.ValidateConfigName(newPath); err != nil {
+		return fmt.Errorf("invalid config path: %w", err)
+	}
+	if item.Path == newPath {
+		return fmt.Errorf("config '%s' is already at '%s'", name, newPath)
+	}
+
+	return relocateConfig(cfg, configPath, st, *item, item.Name, newPath, opts)
+}
+
+// relocateConfig applies a name and/or path change. Each completed step
+// registers an undo action so a failure leaves the repo, links and state as
+// they were.
+func relocateConfig(cfg *config.Config, configPath string, st *state.State, item config.ConfigItem, newName, newPath string, opts RefactorOptions) error {
+	if item.Root != "" {
+		return fmt.Errorf("config '%s' comes from a base repo; rename or move it there", item.Name)
+	}
+
+	report := func(msg string) {
+		if opts.ProgressFunc != nil {
+			opts.ProgressFunc(0, 0, msg)
+		}
+	}
+
+	dotfilesPath := filepath.Dir(configPath)
+	oldDir := filepath.Join(dotfilesPath, item.Path)
+	newDir := filepath.Join(dotfilesPath, newPath)
+	pathChanged := newPath != item.Path
+
+	if pathChanged {
+		if _, err := os.Stat(oldDir); err != nil {
+			return fmt.Errorf("config directory %s not found: %w", oldDir, err)
+		}
+		if _, err := os.Lstat(newDir); err == nil {
+			return fmt.Errorf("%s already exists", newDir)
+		}
+		for _, c := range cfg.GetAllConfigs() {
+			if c.Path == newPath {
+				return fmt.Errorf("path '%s' is already used by config '%s'", newPath, c.Name)
+			}
+		}
+	}
+
+	// Only restow when something is actually linked
+	linked := st != nil && st.HasConfig(item.Name)
+	if status, err := stow.GetConfigLinkStatus(item, dotfilesPath, cfg.Stow); err == nil && len(status.LinkedFiles) > 0 {
+		linked = true
+	}
+
+	if opts.DryRun {
+		if newName != item.Name {
+			report(fmt.Sprintf("Would rename config %s → %s", item.Name, newName))
+			for _, c := range cfg.GetAllConfigs() {
+				if slices.Contains(c.DependsOn, item.Name) {
+					report(fmt.Sprintf("Would update depends_on of %s", c.Name))
+				}
+			}
+		}
+		if pathChanged {
+			report(fmt.Sprintf("Would move %s → %s", item.Path, newPath))
+			if linked {
+				report(fmt.Sprintf("Would restow links for %s", newName))
+			}
+		}
+		return nil
+	}
+
+	var undo []func()
+	rollback := func() {
+		for i := len(undo) - 1; i >= 0; i-- {
+			undo[i]()
+		}
+	}
+	stowOpts := stow.StowOptions{ProgressFunc: opts.ProgressFunc, Settings: cfg.Stow}
+
+	if pathChanged && linked {
+		if err := stow.Unstow(dotfilesPath, item.Path, stowOpts); err != nil {
+			return fmt.Errorf("failed to unstow %s: %w", item.Name, err)
+		}
+		undo = append(undo, func() { _ = stow.Stow(dotfilesPath, item.Path, stowOpts) })
+	}
+
+	if pathChanged {
+		if err := os.Rename(oldDir, newDir); err != nil {
+			rollback()
+			return fmt.Errorf("failed to move %s: %w", oldDir, err)
+		}
+		undo = append(undo, func() { _ = os.Rename(newDir, oldDir) })
+		report(fmt.Sprintf("✓ Moved %s → %s", item.Path, newPath))
+	}
+
+	original, err := os.ReadFile(configPath)
+	if err != nil {
+		rollback()
+		return fmt.Errorf("failed to read config file: %w", err)
+	}
+	if err := config.RenameConfigItem(configPath, item.Name, newName, newPath); err != nil {
+		rollback()
+		return err
+	}
+	undo = append(undo, func() { _ = os.WriteFile(configPath, original, 0644) })
+	report(fmt.Sprintf("✓ Updated %s", filepath.Base(configPath)))
+
+	if pathChanged && linked {
+		if err := stow.Stow(dotfilesPath, newPath, stowOpts); err != nil {
+			rollback()
+			return fmt.Errorf("failed to stow %s: %w", newName, err)
+		}
+	}
+
+	if st != nil {
+		renameStateConfig(st, item.Name, newName, newPath)
+		if err := st.Save(); err != nil {
+			return fmt.Errorf("config relocated but failed to save state: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// renameStateConfig updates the state entry, symlink count and fingerprint
+// for a config
+func renameStateConfig(st *state.State, oldName, newName, newPath string) {
+	for i, c := range st.Configs {
+		if c.Name == oldName {
+			st.Configs[i].Name = newName
+			st.Configs[i].Path = newPath
+			break
+		}
+	}
+
+	if count, ok := st.GetSymlinkCount(oldName); ok {
+		st.RemoveSymlinkCount(oldName)
+		st.SetSymlinkCount(newName, count)
+	}
+	if fingerprint, ok := st.GetFingerprint(oldName); ok {
+		st.RemoveFingerprint(oldName)
+		st.SetFingerprint(newName, fingerprint)
+	}
+}
@@ -0,0 +1,167 @@
+package setup
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/nvandessel/go4dot/internal/config"
+	"github.com/nvandessel/go4dot/internal/state"
+	"github.com/nvandessel/go4dot/internal/stow"
+)
+
+func setupRefactorRepo(t *testing.T) (configPath, homeDir string) {
+	t.Helper()
+
+	origCommander := stow.CurrentCommander
+	stow.CurrentCommander = &stow.MockCommander{}
+	t.Cleanup(func() { stow.CurrentCommander = origCommander })
+
+	tmpDir := t.TempDir()
+	homeDir = filepath.Join(tmpDir, "home")
+	dotfilesDir := filepath.Join(tmpDir, "dotfiles")
+	t.Setenv("HOME", homeDir)
+
+	if err := os.MkdirAll(filepath.Join(dotfilesDir, "nvim"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dotfilesDir, "nvim", ".vimrc"), []byte("set nu"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(homeDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	configPath = filepath.Join(dotfilesDir, ".go4dot.yaml")
+	yaml := "schema_version: \"1.0\"\nconfigs:\n  core:\n    - name: nvim\n      path: nvim\n    - name: git\n      path: git\n      depends_on: [nvim]\n"
+	if err := os.WriteFile(configPath, []byte(yaml), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := stow.Stow(dotfilesDir, "nvim", stow.StowOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	return configPath, homeDir
+}
+
+func TestRenameConfig(t *testing.T) {
+	configPath, homeDir := setupRefactorRepo(t)
+	dotfilesDir := filepath.Dir(configPath)
+
+	cfg, err := config.LoadFromPath(configPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	st := state.New()
+	st.AddConfig("nvim", "nvim", true)
+	st.SetSymlinkCount("nvim", 1)
+
+	if err := RenameConfig(cfg, configPath, st, "nvim", "neovim", RefactorOptions{}); err != nil {
+		t.Fatalf("RenameConfig() error = %v", err)
+	}
+
+	if _, err := os.Stat(filepath.Join(dotfilesDir, "neovim", ".vimrc")); err != nil {
+		t.Errorf("directory was not renamed: %v", err)
+	}
+	target, err := os.Readlink(filepath.Join(homeDir, ".vimrc"))
+	if err != nil {
+		t.Fatalf("link missing after rename: %v", err)
+	}
+	if filepath.Base(filepath.Dir(target)) != "neovim" {
+		t.Errorf("link points to %s, want the renamed directory", target)
+	}
+
+	updated, err := config.LoadFromPath(configPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if item := updated.GetConfigByName("neovim"); item == nil || item.Path != "neovim" {
+		t.Errorf("config not renamed in YAML: %+v", item)
+	}
+	if deps := updated.GetConfigByName("git").DependsOn; len(deps) != 1 || deps[0] != "neovim" {
+		t.Errorf("git depends_on = %v, want it to follow the rename", deps)
+	}
+	if st.HasConfig("nvim") || !st.HasConfig("neovim") {
+		t.Error("state was not updated")
+	}
+	if count, ok := st.GetSymlinkCount("neovim"); !ok || count != 1 {
+		t.Errorf("symlink count not carried over: %d, %v", count, ok)
+	}
+}
+
+func TestRenameConfig_Inherited(t *testing.T) {
+	configPath, _ := setupRefactorRepo(t)
+
+	cfg, err := config.LoadFromPath(configPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg.Configs.Core[0].Root = filepath.Join(t.TempDir(), "base")
+
+	if err := RenameConfig(cfg, configPath, nil, "nvim", "neovim", RefactorOptions{}); err == nil {
+		t.Error("RenameConfig() of an inherited config: error = nil")
+	}
+	if err := MoveConfig(cfg, configPath, nil, "nvim", "editor", RefactorOptions{}); err == nil {
+		t.Error("MoveConfig() of an inherited config: error = nil")
+	}
+	if _, err := os.Stat(filepath.Join(filepath.Dir(configPath), "nvim")); err != nil {
+		t.Errorf("nvim directory should be untouched: %v", err)
+	}
+}
+
+func TestMoveConfig_Errors(t *testing.T) {
+	configPath, _ := setupRefactorRepo(t)
+
+	cfg, err := config.LoadFromPath(configPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		config  string
+		newPath string
+	}{
+		{"unknown config", "missing", "x"},
+		{"same path", "nvim", "nvim"},
+		{"path used by another config", "nvim", "git"},
+		{"path traversal", "nvim", "../outside"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := MoveConfig(cfg, configPath, nil, tt.config, tt.newPath, RefactorOptions{}); err == nil {
+				t.Error("MoveConfig() expected error")
+			}
+		})
+	}
+
+	// Nothing should have changed
+	if _, err := os.Stat(filepath.Join(filepath.Dir(configPath), "nvim")); err != nil {
+		t.Errorf("nvim directory should be untouched: %v", err)
+	}
+}
+
+func TestMoveConfig_DryRun(t *testing.T) {
+	configPath, _ := setupRefactorRepo(t)
+
+	cfg, err := config.LoadFromPath(configPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var msgs []string
+	opts := RefactorOptions{
+		DryRun:       true,
+		ProgressFunc: func(_, _ int, msg string) { msgs = append(msgs, msg) },
+	}
+	if err := MoveConfig(cfg, configPath, nil, "nvim", "editor", opts); err != nil {
+		t.Fatalf("MoveConfig() error = %v", err)
+	}
+	if len(msgs) == 0 {
+		t.Error("dry run should report planned changes")
+	}
+	if _, err := os.Stat(filepath.Join(filepath.Dir(configPath), "editor")); !os.IsNotExist(err) {
+		t.Error("dry run must not move the directory")
+	}
+}
@@ -1,60 +1,80 @@
 package setup
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
+	"time"
 
 	"github.com/nvandessel/go4dot/internal/config"
 	"github.com/nvandessel/go4dot/internal/deps"
+	"github.com/nvandessel/go4dot/internal/hooks"
 	"github.com/nvandessel/go4dot/internal/machine"
 	"github.com/nvandessel/go4dot/internal/platform"
+	"github.com/nvandessel/go4dot/internal/shellinit"
+	"github.com/nvandessel/go4dot/internal/snapshot"
 	"github.com/nvandessel/go4dot/internal/state"
 	"github.com/nvandessel/go4dot/internal/stow"
 )
 
 // InstallOptions configures the installation behavior
 type InstallOptions struct {
-	Auto         bool                                 // Non-interactive, use defaults
-	Minimal      bool                                 // Only core configs, skip optional
-	SkipDeps     bool                                 // Skip dependency installation
-	SkipExternal bool                                 // Skip external dependency cloning
-	SkipMachine  bool                                 // Skip machine-specific configuration
-	SkipStow     bool                                 // Skip stowing configs
-	SkipKeys     bool                                 // Skip SSH key setup
-	Overwrite    bool                                 // Overwrite existing files
-	ProgressFunc func(current, total int, msg string) // Called for progress updates with item counts
+	Auto           bool                                 // Non-interactive, use defaults
+	Minimal        bool                                 // Only core configs, skip optional
+	SkipDeps       bool                                 // Skip dependency installation
+	SkipExternal   bool                                 // Skip external dependency cloning
+	SkipToolchains bool                                 // Skip installing language toolchains
+	SkipMachine    bool                                 // Skip machine-specific configuration
+	SkipStow       bool                                 // Skip stowing configs
+	SkipKeys       bool                                 // Skip SSH key setup
+	Overwrite      bool                                 // Overwrite existing files
+	Adopt          bool                                 // Move conflicting files in home into the repo before stowing
+	NoTerminal     bool                                 // Nobody can answer prompts, such as sudo asking for a password
+	Escalation     string                               // How package managers get root: sudo, doas or pkexec; empty uses the first installed
+	AskPassword    func(prompt string) (string, error)  // Asks once for the sudo password, masked; nil when nobody can answer
+	ProgressFunc   func(current, total int, msg string) // Called for progress updates with item counts
 }
 
 // InstallResult tracks the result of the installation
 type InstallResult struct {
-	Platform       *platform.Platform
-	DepsInstalled  []config.DependencyItem
-	DepsFailed     []deps.InstallError
-	ConfigsStowed  []string
-	ConfigsAdopted []string // Configs that were already linked and adopted
-	ConfigsFailed  []stow.StowError
-	ExternalCloned []config.ExternalDep
-	ExternalFailed []deps.ExternalError
-	MachineConfigs []machine.RenderResult
-	KeysGenerated  []string // paths of generated SSH keys
-	KeysRegistered []string // descriptions of registered keys
-	Errors         []error
+	Platform            *platform.Platform
+	DepsInstalled       []config.DependencyItem
+	DepsFailed          []deps.InstallError
+	ToolchainsInstalled []string // Version managers and versions installed, e.g. "rustup" or "node 20"
+	ToolchainsFailed    []deps.ToolchainError
+	ConfigsStowed       []string
+	ConfigsAdopted      []string // Configs that were already linked and adopted
+	ConfigsFailed       []stow.StowError
+	ExternalCloned      []config.ExternalDep
+	ExternalFailed      []deps.ExternalError
+	MachineConfigs      []machine.RenderResult
+	KeysGenerated       []string     // paths of generated SSH keys
+	KeysRegistered      []string     // descriptions of registered keys
+	ShellRCWired        []string     // rc files changed to source g4d shell-init
+	HooksRun            []hooks.Hook // pre_install and post_install hooks that ran
+	HooksFailed         []*hooks.Error
+	Timings             []state.StepTiming // How long each step that ran took, in order
+	Errors              []error
 }
 
 // HasErrors returns true if any errors occurred during installation
 func (r *InstallResult) HasErrors() bool {
 	return len(r.DepsFailed) > 0 || len(r.ConfigsFailed) > 0 ||
-		len(r.ExternalFailed) > 0 || len(r.Errors) > 0
+		len(r.ExternalFailed) > 0 || len(r.ToolchainsFailed) > 0 || len(r.HooksFailed) > 0 || len(r.Errors) > 0
 }
 
 // Install runs the full installation flow
 func Install(cfg *config.Config, dotfilesPath string, opts InstallOptions) (*InstallResult, error) {
+	defer snapshot.Begin("install")()
 	result := &InstallResult{}
 
 	// Step 1: Detect platform
 	progress(opts, "Detecting platform...")
-	p, err := platform.Detect()
+	var p *platform.Platform
+	var err error
+	result.timed("platform", func() { p, err = platform.Detect() })
 	if err != nil {
 		return nil, fmt.Errorf("failed to detect platform: %w", err)
 	}
@@ -64,58 +84,163 @@ func Install(cfg *config.Config, dotfilesPath string, opts InstallOptions) (*Ins
 	// Filter config and dependencies for this machine
 	filteredCfg := filterConfigForPlatform(cfg, p)
 
+	// Fail before changing anything rather than halfway through linking
+	result.timed("preflight", func() {
+		err = Preflight(filteredCfg, dotfilesPath, installPreflightOptions(filteredCfg, p, opts))
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// pre_install hooks run before anything changes; one that fails stops
+	// the install
+	toLink := configNames(installPreflightOptions(filteredCfg, p, opts).Configs)
+	if err := runHooks(filteredCfg, dotfilesPath, config.HookPreInstall, toLink, opts, result); err != nil {
+		return nil, err
+	}
+
 	// Step 2: Check and install dependencies
 	if !opts.SkipDeps {
-		if err := installDependencies(filteredCfg, p, opts, result); err != nil {
-			result.Errors = append(result.Errors, err)
-			// Don't return - continue with other steps
-		}
+		result.timed("deps", func() {
+			if err := installDependencies(filteredCfg, p, opts, result); err != nil {
+				result.Errors = append(result.Errors, err)
+				// Don't return - continue with other steps
+			}
+		})
 	} else {
 		progress(opts, "⊘ Skipping dependency installation")
 	}
 
-	// Step 3: Stow configs
+	// Step 3: Install language toolchains, which may need the dependencies
+	if !opts.SkipToolchains {
+		result.timed("toolchains", func() {
+			if err := installToolchains(filteredCfg, p, opts, result); err != nil {
+				result.Errors = append(result.Errors, err)
+			}
+		})
+	} else if len(cfg.Toolchains) > 0 {
+		progress(opts, "⊘ Skipping toolchains")
+	}
+
+	// Step 4: Stow configs
 	if !opts.SkipStow {
-		if err := stowConfigs(filteredCfg, dotfilesPath, opts, result); err != nil {
-			result.Errors = append(result.Errors, err)
-		}
+		result.timed("stow", func() {
+			if err := stowConfigs(filteredCfg, dotfilesPath, opts, result); err != nil {
+				result.Errors = append(result.Errors, err)
+			}
+		})
 	} else {
 		progress(opts, "⊘ Skipping config stowing")
 	}
 
-	// Step 4: Clone external dependencies
+	// Step 5: Clone external dependencies
 	if !opts.SkipExternal {
-		if err := cloneExternal(filteredCfg, dotfilesPath, p, opts, result); err != nil {
-			result.Errors = append(result.Errors, err)
-		}
+		result.timed("externals", func() {
+			if err := cloneExternal(filteredCfg, dotfilesPath, p, opts, result); err != nil {
+				result.Errors = append(result.Errors, err)
+			}
+		})
 	} else {
 		progress(opts, "⊘ Skipping external dependencies")
 	}
 
-	// Step 5: Key setup — before machine config so newly created keys
+	// Step 6: Key setup — before machine config so newly created keys
 	// are detected by smart prompt defaults
 	if !opts.SkipKeys && !opts.Auto {
-		if err := setupKeys(opts, result); err != nil {
-			result.Errors = append(result.Errors, err)
-		}
+		result.timed("keys", func() {
+			if err := setupKeys(opts, result); err != nil {
+				result.Errors = append(result.Errors, err)
+			}
+		})
 	} else if opts.Auto {
 		progress(opts, "⊘ Skipping key setup (non-interactive mode)")
 	} else {
 		progress(opts, "⊘ Skipping key setup")
 	}
 
-	// Step 6: Configure machine-specific settings
+	// Step 7: Configure machine-specific settings
 	if !opts.SkipMachine {
-		if err := configureMachine(filteredCfg, p, opts, result); err != nil {
-			result.Errors = append(result.Errors, err)
-		}
+		result.timed("machine", func() {
+			if err := configureMachine(filteredCfg, p, opts, result); err != nil {
+				result.Errors = append(result.Errors, err)
+			}
+		})
 	} else {
 		progress(opts, "⊘ Skipping machine configuration")
 	}
 
+	// Step 8: Source the shell integration snippet from rc files
+	if cfg.ShellIntegration.Enabled {
+		result.timed("shell", func() {
+			if err := wireShellIntegration(cfg, opts, result); err != nil {
+				result.Errors = append(result.Errors, err)
+			}
+		})
+	}
+
+	// post_install hooks of the configs that are linked now
+	linked := append(append([]string{}, result.ConfigsStowed...), result.ConfigsAdopted...)
+	_ = runHooks(filteredCfg, dotfilesPath, config.HookPostInstall, linked, opts, result)
+
 	return result, nil
 }
 
+// timed runs step and records how long it took under name
+func (r *InstallResult) timed(name string, step func()) {
+	start := time.Now()
+	step()
+	r.Timings = append(r.Timings, state.StepTiming{Name: name, Duration: time.Since(start)})
+}
+
+// installPreflightOptions returns what Preflight checks before an install
+// with opts: the configs it will stow and, unless skipped, dependencies
+func installPreflightOptions(cfg *config.Config, p *platform.Platform, opts InstallOptions) PreflightOptions {
+	pre := PreflightOptions{
+		Deps:       !opts.SkipDeps,
+		Platform:   p,
+		NoTerminal: opts.NoTerminal,
+		Escalation: opts.Escalation,
+	}
+	if !opts.SkipStow {
+		pre.Configs = cfg.Configs.Core
+		if !opts.Minimal {
+			pre.Configs = cfg.GetAllConfigs()
+		}
+	}
+	return pre
+}
+
+// wireShellIntegration adds the g4d shell-init line to the rc file of each
+// configured shell, or of the login shell
+func wireShellIntegration(cfg *config.Config, opts InstallOptions, result *InstallResult) error {
+	progress(opts, "\n── Shell Integration ──")
+
+	shells := cfg.ShellIntegration.ShellsFor(os.Getenv("SHELL"))
+	if len(shells) == 0 {
+		progress(opts, "⊘ No supported login shell; set shell_integration.shells")
+		return nil
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return fmt.Errorf("failed to get home directory: %w", err)
+	}
+
+	for _, shell := range shells {
+		changed, path, err := shellinit.Wire(shell, home)
+		if err != nil {
+			return err
+		}
+		if changed {
+			result.ShellRCWired = append(result.ShellRCWired, path)
+			progress(opts, fmt.Sprintf("✓ %s sources g4d shell-init %s", path, shell))
+		} else {
+			progress(opts, fmt.Sprintf("✓ %s already set up", path))
+		}
+	}
+	return nil
+}
+
 // installDependencies checks and installs missing dependencies
 func installDependencies(cfg *config.Config, p *platform.Platform, opts InstallOptions, result *InstallResult) error {
 	progress(opts, "\n── Dependencies ──")
@@ -136,6 +261,8 @@ func installDependencies(cfg *config.Config, p *platform.Platform, opts InstallO
 
 	installOpts := deps.InstallOptions{
 		OnlyMissing: true,
+		Escalation:  opts.Escalation,
+		AskPassword: opts.AskPassword,
 		ProgressFunc: func(current, total int, msg string) {
 			progressWithCount(opts, current, total, "  "+msg)
 		},
@@ -158,6 +285,39 @@ func installDependencies(cfg *config.Config, p *platform.Platform, opts InstallO
 	return nil
 }
 
+// installToolchains installs the version managers and versions of the
+// toolchains whose condition is met
+func installToolchains(cfg *config.Config, p *platform.Platform, opts InstallOptions, result *InstallResult) error {
+	if len(cfg.Toolchains) == 0 {
+		return nil
+	}
+
+	progress(opts, "\n── Toolchains ──")
+
+	tcResult, err := deps.InstallToolchains(cfg, p, deps.ToolchainOptions{
+		ProgressFunc: func(current, total int, msg string) {
+			progressWithCount(opts, current, total, "  "+msg)
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to install toolchains: %w", err)
+	}
+
+	result.ToolchainsInstalled = tcResult.Installed
+	result.ToolchainsFailed = tcResult.Failed
+
+	if len(tcResult.Failed) > 0 {
+		progress(opts, fmt.Sprintf("⚠ %d toolchains failed", len(tcResult.Failed)))
+	}
+	if len(tcResult.Installed) > 0 {
+		progress(opts, fmt.Sprintf("✓ Installed %s", strings.Join(tcResult.Installed, ", ")))
+	} else if len(tcResult.Failed) == 0 {
+		progress(opts, "✓ All toolchains are installed")
+	}
+
+	return nil
+}
+
 // stowConfigs stows all or selected configs, adopting existing symlinks where possible
 func stowConfigs(cfg *config.Config, dotfilesPath string, opts InstallOptions, result *InstallResult) error {
 	progress(opts, "\n── Configs ──")
@@ -204,14 +364,28 @@ func stowConfigs(cfg *config.Config, dotfilesPath string, opts InstallOptions, r
 		return nil
 	}
 
-	progress(opts, fmt.Sprintf("Stowing %d configs...", len(configsToStow)))
-
 	stowOpts := stow.StowOptions{
+		Settings: cfg.Stow,
 		ProgressFunc: func(current, total int, msg string) {
 			progressWithCount(opts, current, total, "  "+msg)
 		},
 	}
 
+	if opts.Adopt {
+		conflicts, err := stow.DetectConflicts(cfg, dotfilesPath)
+		if err != nil {
+			return fmt.Errorf("failed to check conflicts: %w", err)
+		}
+		if conflicts = stow.ConflictsForConfigs(conflicts, configsToStow); len(conflicts) > 0 {
+			progress(opts, fmt.Sprintf("Adopting %d existing file(s) into the repo...", len(conflicts)))
+			if err := stow.AdoptConflicts(conflicts, stowOpts); err != nil {
+				return err
+			}
+		}
+	}
+
+	progress(opts, fmt.Sprintf("Stowing %d configs...", len(configsToStow)))
+
 	stowResult := stow.StowConfigs(dotfilesPath, configsToStow, stowOpts)
 
 	result.ConfigsStowed = stowResult.Success
@@ -385,6 +559,45 @@ func configureMachine(cfg *config.Config, p *platform.Platform, opts InstallOpti
 	return nil
 }
 
+// runHooks runs the hooks of stage for configNames, reporting each hook
+// and its output as progress, and returns the failures. The time they take
+// is recorded under the stage's name.
+func runHooks(cfg *config.Config, dotfilesPath, stage string, configNames []string, opts InstallOptions, result *InstallResult) error {
+	if len(hooks.For(cfg, stage, configNames)) == 0 {
+		return nil
+	}
+	progress(opts, fmt.Sprintf("\n── Hooks (%s) ──", stage))
+
+	var run *hooks.Result
+	result.timed(stage, func() {
+		run = hooks.Run(context.Background(), cfg, stage, configNames, hooks.Options{
+			RepoRoot:  dotfilesPath,
+			TargetDir: cfg.Stow.TargetDir(),
+			StartFunc: func(h hooks.Hook) {
+				progress(opts, "$ "+h.Command)
+			},
+			OutputFunc: func(h hooks.Hook, line string) {
+				progress(opts, "  "+line)
+			},
+		})
+	})
+	result.HooksRun = append(result.HooksRun, run.Ran...)
+	result.HooksFailed = append(result.HooksFailed, run.Failed...)
+	for _, f := range run.Failed {
+		progress(opts, "✗ "+f.Error())
+	}
+	return run.Err()
+}
+
+// configNames returns the names of configs
+func configNames(configs []config.ConfigItem) []string {
+	names := make([]string, 0, len(configs))
+	for _, c := range configs {
+		names = append(names, c.Name)
+	}
+	return names
+}
+
 // progress sends a progress message if the callback is set
 func progress(opts InstallOptions, msg string) {
 	if opts.ProgressFunc != nil {
@@ -446,6 +659,11 @@ func (r *InstallResult) Summary() string {
 		}
 	}
 
+	if len(r.ToolchainsInstalled) > 0 || len(r.ToolchainsFailed) > 0 {
+		summary += fmt.Sprintf("Toolchains: %d installed, %d failed\n",
+			len(r.ToolchainsInstalled), len(r.ToolchainsFailed))
+	}
+
 	if len(r.ExternalCloned) > 0 || len(r.ExternalFailed) > 0 {
 		summary += fmt.Sprintf("External: %d cloned, %d failed\n",
 			len(r.ExternalCloned), len(r.ExternalFailed))
@@ -455,6 +673,18 @@ func (r *InstallResult) Summary() string {
 		summary += fmt.Sprintf("Machine configs: %d configured\n", len(r.MachineConfigs))
 	}
 
+	if len(r.ShellRCWired) > 0 {
+		summary += fmt.Sprintf("Shell integration: %d rc files updated\n", len(r.ShellRCWired))
+	}
+
+	if len(r.HooksRun) > 0 {
+		summary += fmt.Sprintf("Hooks: %d run, %d failed\n", len(r.HooksRun), len(r.HooksFailed))
+	}
+
+	if len(r.Timings) > 0 {
+		summary += fmt.Sprintf("Timing: %s\n", state.FormatTimings(r.Timings))
+	}
+
 	return summary
 }
 
@@ -492,6 +722,7 @@ func SaveState(cfg *config.Config, dotfilesPath string, result *InstallResult) e
 		}
 		st.AddConfig(configName, configName, isCore)
 	}
+	stow.RecordFingerprints(cfg, dotfilesPath, st, allConfigs)
 
 	// Save external deps
 	for _, ext := range result.ExternalCloned {
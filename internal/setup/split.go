@@ -0,0 +1,286 @@
+package setup
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/nvandessel/go4dot/internal/config"
+	"github.com/nvandessel/go4dot/internal/state"
+	"github.com/nvandessel/go4dot/internal/stow"
+	"github.com/nvandessel/go4dot/internal/validation"
+)
+
+// splitCandidateDepth is how deep SplitCandidates looks into a package:
+// deep enough to reach .config/<tool>
+const splitCandidateDepth = 2
+
+// SplitCandidates returns the files and directories of a config's package
+// that SplitConfig can move, as slash-separated paths relative to the
+// package, up to two levels deep. Directories end in a slash.
+func SplitCandidates(cfg *config.Config, dotfilesPath, name string) ([]string, error) {
+	item := cfg.GetConfigByName(name)
+	if item == nil {
+		return nil, cfg.UnknownConfigError(name)
+	}
+	dir := item.Dir(dotfilesPath)
+
+	var candidates []string
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if path == dir {
+			return nil
+		}
+		rel, _ := filepath.Rel(dir, path)
+		rel = filepath.ToSlash(rel)
+		if d.IsDir() {
+			candidates = append(candidates, rel+"/")
+			if strings.Count(rel, "/")+1 >= splitCandidateDepth {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		candidates = append(candidates, rel)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list %s: %w", dir, err)
+	}
+	sort.Strings(candidates)
+	return candidates, nil
+}
+
+// SplitConfig moves paths, relative to the package of the config called
+// name, into a new config package called newName. The new config is added
+// to .go4dot.yaml next to the original, both are restowed when the original
+// was linked, and state gets an entry for the new config. If any step
+// fails, earlier steps are rolled back.
+func SplitConfig(cfg *config.Config, configPath string, st *state.State, name, newName string, paths []string, opts RefactorOptions) error {
+	report := func(msg string) {
+		if opts.ProgressFunc != nil {
+			opts.ProgressFunc(0, 0, msg)
+		}
+	}
+
+	item := cfg.GetConfigByName(name)
+	if item == nil {
+		return cfg.UnknownConfigError(name)
+	}
+	if item.Root != "" {
+		return fmt.Errorf("config '%s' comes from a base repo and can only be split there", name)
+	}
+	if err := validation.ValidateConfigName(newName); err != nil {
+		return fmt.Errorf("invalid config name: %w", err)
+	}
+	if cfg.GetConfigByName(newName) != nil {
+		return fmt.Errorf("config '%s' already exists", newName)
+	}
+	for _, c := range cfg.GetAllConfigs() {
+		if c.Path == newName {
+			return fmt.Errorf("path '%s' is already used by config '%s'", newName, c.Name)
+		}
+	}
+
+	dotfilesPath := filepath.Dir(configPath)
+	oldDir := item.Dir(dotfilesPath)
+	newDir := filepath.Join(dotfilesPath, newName)
+	if _, err := os.Lstat(newDir); err == nil {
+		return fmt.Errorf("%s already exists", newDir)
+	}
+
+	moves, err := splitPaths(oldDir, paths)
+	if err != nil {
+		return err
+	}
+
+	linked := st != nil && st.HasConfig(item.Name)
+	if status, err := stow.GetConfigLinkStatus(*item, dotfilesPath, cfg.Stow); err == nil && len(status.LinkedFiles) > 0 {
+		linked = true
+	}
+
+	if opts.DryRun {
+		for _, p := range moves {
+			report(fmt.Sprintf("Would move %s → %s", filepath.Join(item.Path, p), filepath.Join(newName, p)))
+		}
+		report(fmt.Sprintf("Would add config %s to %s", newName, filepath.Base(configPath)))
+		if linked {
+			report(fmt.Sprintf("Would restow links for %s and %s", item.Name, newName))
+		}
+		return nil
+	}
+
+	var undo []func()
+	rollback := func() {
+		for i := len(undo) - 1; i >= 0; i-- {
+			undo[i]()
+		}
+	}
+	stowOpts := stow.StowOptions{ProgressFunc: opts.ProgressFunc, Settings: cfg.Stow}
+
+	if linked {
+		if err := stow.Unstow(dotfilesPath, item.Path, stowOpts); err != nil {
+			return fmt.Errorf("failed to unstow %s: %w", item.Name, err)
+		}
+		undo = append(undo, func() { _ = stow.Stow(dotfilesPath, item.Path, stowOpts) })
+	}
+
+	if err := os.Mkdir(newDir, 0755); err != nil {
+		rollback()
+		return fmt.Errorf("failed to create %s: %w", newDir, err)
+	}
+	undo = append(undo, func() { _ = os.RemoveAll(newDir) })
+	for _, p := range moves {
+		from, to := filepath.Join(oldDir, p), filepath.Join(newDir, p)
+		if err := os.MkdirAll(filepath.Dir(to), 0755); err != nil {
+			rollback()
+			return fmt.Errorf("failed to create %s: %w", filepath.Dir(to), err)
+		}
+		if err := os.Rename(from, to); err != nil {
+			rollback()
+			return fmt.Errorf("failed to move %s: %w", from, err)
+		}
+		undo = append(undo, func() { _ = os.Rename(to, from) })
+		report(fmt.Sprintf("✓ Moved %s → %s", filepath.Join(item.Path, p), filepath.Join(newName, p)))
+	}
+
+	original, err := os.ReadFile(configPath)
+	if err != nil {
+		rollback()
+		return fmt.Errorf("failed to read config file: %w", err)
+	}
+	newItem := config.ConfigItem{
+		Name:        newName,
+		Path:        newName,
+		Description: fmt.Sprintf("Split from %s", item.Name),
+	}
+	if err := config.AddConfigItem(configPath, newItem, isOptionalConfig(cfg, item.Name)); err != nil {
+		rollback()
+		return err
+	}
+	undo = append(undo, func() { _ = os.WriteFile(configPath, original, 0644) })
+	report(fmt.Sprintf("✓ Added %s to %s", newName, filepath.Base(configPath)))
+
+	if linked {
+		if err := stow.Stow(dotfilesPath, item.Path, stowOpts); err != nil {
+			rollback()
+			return fmt.Errorf("failed to stow %s: %w", item.Name, err)
+		}
+		undo = append(undo, func() { _ = stow.Unstow(dotfilesPath, item.Path, stowOpts) })
+		if err := stow.Stow(dotfilesPath, newName, stowOpts); err != nil {
+			rollback()
+			return fmt.Errorf("failed to stow %s: %w", newName, err)
+		}
+	}
+
+	if st != nil && st.HasConfig(item.Name) {
+		splitStateConfig(st, configPath, item.Name, newName)
+		if err := st.Save(); err != nil {
+			return fmt.Errorf("config split but failed to save state: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// splitPaths checks the paths to split off the package at dir and returns
+// them cleaned, sorted and without paths inside other selected directories
+func splitPaths(dir string, paths []string) ([]string, error) {
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("no paths selected to split off")
+	}
+
+	var cleaned []string
+	for _, p := range paths {
+		c := filepath.Clean(filepath.FromSlash(strings.TrimSuffix(p, "/")))
+		if c == "." || filepath.IsAbs(c) || c == ".." || strings.HasPrefix(c, ".."+string(filepath.Separator)) {
+			return nil, fmt.Errorf("path '%s' is not inside the config", p)
+		}
+		if _, err := os.Lstat(filepath.Join(dir, c)); err != nil {
+			return nil, fmt.Errorf("path '%s' not found in the config", p)
+		}
+		cleaned = append(cleaned, c)
+	}
+	sort.Strings(cleaned)
+
+	// A selected directory carries everything below it
+	var moves []string
+	for _, c := range cleaned {
+		if n := len(moves); n > 0 && (c == moves[n-1] || strings.HasPrefix(c, moves[n-1]+string(filepath.Separator))) {
+			continue
+		}
+		moves = append(moves, c)
+	}
+
+	// Splitting off everything is a move
+	remaining := 0
+	_ = filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return nil
+		}
+		rel, _ := filepath.Rel(dir, path)
+		for _, m := range moves {
+			if rel == m || strings.HasPrefix(rel, m+string(filepath.Separator)) {
+				return nil
+			}
+		}
+		remaining++
+		return nil
+	})
+	if remaining == 0 {
+		return nil, fmt.Errorf("every file would be split off; use 'g4d config move' or 'g4d config rename' instead")
+	}
+	return moves, nil
+}
+
+// isOptionalConfig reports whether the config called name is listed under
+// configs.optional
+func isOptionalConfig(cfg *config.Config, name string) bool {
+	for _, c := range cfg.Configs.Optional {
+		if c.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// splitStateConfig adds a state entry for the config split off oldName and
+// records both configs' symlink counts and fingerprints as linked now
+func splitStateConfig(st *state.State, configPath, oldName, newName string) {
+	isCore := true
+	for _, c := range st.Configs {
+		if c.Name == oldName {
+			isCore = c.IsCore
+		}
+	}
+	st.AddConfig(newName, newName, isCore)
+
+	cfg, err := config.LoadFile(configPath)
+	if err != nil {
+		// Let the next sync work out what changed
+		st.RemoveFingerprint(oldName)
+		st.RemoveSymlinkCount(oldName)
+		return
+	}
+	dotfilesPath := filepath.Dir(configPath)
+	fingerprints := stow.Fingerprints(cfg, dotfilesPath)
+	for _, name := range []string{oldName, newName} {
+		st.SetFingerprint(name, fingerprints[name])
+		item := cfg.GetConfigByName(name)
+		if item == nil {
+			continue
+		}
+		count := 0
+		_ = filepath.WalkDir(item.Dir(dotfilesPath), func(path string, d fs.DirEntry, err error) error {
+			if err == nil && !d.IsDir() {
+				count++
+			}
+			return nil
+		})
+		st.SetSymlinkCount(name, count)
+	}
+}
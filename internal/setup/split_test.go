@@ -0,0 +1,164 @@
+package setup
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/nvandessel/go4dot/internal/config"
+	"github.com/nvandessel/go4dot/internal/state"
+	"github.com/nvandessel/go4dot/internal/stow"
+)
+
+// setupSplitRepo adds a second tool to the nvim package of
+// setupRefactorRepo and links the package again
+func setupSplitRepo(t *testing.T) (configPath, homeDir string) {
+	t.Helper()
+	configPath, homeDir = setupRefactorRepo(t)
+	dotfilesDir := filepath.Dir(configPath)
+
+	starship := filepath.Join(dotfilesDir, "nvim", ".config", "starship.toml")
+	if err := os.MkdirAll(filepath.Dir(starship), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(starship, []byte("add_newline = false"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := stow.Restow(dotfilesDir, "nvim", stow.StowOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	return configPath, homeDir
+}
+
+func TestSplitCandidates(t *testing.T) {
+	configPath, _ := setupSplitRepo(t)
+	cfg, err := config.LoadFromPath(configPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := SplitCandidates(cfg, filepath.Dir(configPath), "nvim")
+	if err != nil {
+		t.Fatalf("SplitCandidates() error = %v", err)
+	}
+	want := []string{".config/", ".config/starship.toml", ".vimrc"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("SplitCandidates() = %v, want %v", got, want)
+	}
+}
+
+func TestSplitConfig(t *testing.T) {
+	configPath, homeDir := setupSplitRepo(t)
+	dotfilesDir := filepath.Dir(configPath)
+
+	cfg, err := config.LoadFromPath(configPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	st := state.New()
+	st.AddConfig("nvim", "nvim", true)
+
+	if err := SplitConfig(cfg, configPath, st, "nvim", "starship", []string{".config/starship.toml"}, RefactorOptions{}); err != nil {
+		t.Fatalf("SplitConfig() error = %v", err)
+	}
+
+	if _, err := os.Stat(filepath.Join(dotfilesDir, "starship", ".config", "starship.toml")); err != nil {
+		t.Errorf("file was not moved: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dotfilesDir, "nvim", ".vimrc")); err != nil {
+		t.Errorf("file left behind was moved: %v", err)
+	}
+	target, err := os.Readlink(filepath.Join(homeDir, ".config", "starship.toml"))
+	if err != nil {
+		t.Fatalf("link missing after split: %v", err)
+	}
+	if filepath.Base(filepath.Dir(filepath.Dir(target))) != "starship" {
+		t.Errorf("link points to %s, want the new package", target)
+	}
+	if _, err := os.Readlink(filepath.Join(homeDir, ".vimrc")); err != nil {
+		t.Errorf("original config's link missing after split: %v", err)
+	}
+
+	updated, err := config.LoadFromPath(configPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if item := updated.GetConfigByName("starship"); item == nil || item.Path != "starship" {
+		t.Errorf("new config not added to YAML: %+v", item)
+	}
+	if !st.HasConfig("starship") || !st.HasConfig("nvim") {
+		t.Error("state should list both configs")
+	}
+	for name, want := range map[string]int{"nvim": 1, "starship": 1} {
+		if count, ok := st.GetSymlinkCount(name); !ok || count != want {
+			t.Errorf("symlink count for %s = %d, %v; want %d", name, count, ok, want)
+		}
+		if _, ok := st.GetFingerprint(name); !ok {
+			t.Errorf("fingerprint for %s not recorded", name)
+		}
+	}
+}
+
+func TestSplitConfig_Errors(t *testing.T) {
+	configPath, _ := setupSplitRepo(t)
+	dotfilesDir := filepath.Dir(configPath)
+
+	cfg, err := config.LoadFromPath(configPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		config  string
+		newName string
+		paths   []string
+	}{
+		{"unknown config", "missing", "x", []string{".vimrc"}},
+		{"existing config", "nvim", "git", []string{".vimrc"}},
+		{"nothing selected", "nvim", "x", nil},
+		{"missing path", "nvim", "x", []string{".zshrc"}},
+		{"path outside", "nvim", "x", []string{"../git"}},
+		{"everything", "nvim", "x", []string{".vimrc", ".config/"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := SplitConfig(cfg, configPath, nil, tt.config, tt.newName, tt.paths, RefactorOptions{}); err == nil {
+				t.Error("SplitConfig() expected error")
+			}
+		})
+	}
+
+	if _, err := os.Stat(filepath.Join(dotfilesDir, "x")); !os.IsNotExist(err) {
+		t.Error("a failed split must not leave the new package behind")
+	}
+}
+
+func TestSplitConfig_DryRun(t *testing.T) {
+	configPath, _ := setupSplitRepo(t)
+	original, _ := os.ReadFile(configPath)
+
+	cfg, err := config.LoadFromPath(configPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var msgs []string
+	opts := RefactorOptions{
+		DryRun:       true,
+		ProgressFunc: func(_, _ int, msg string) { msgs = append(msgs, msg) },
+	}
+	if err := SplitConfig(cfg, configPath, nil, "nvim", "starship", []string{".config"}, opts); err != nil {
+		t.Fatalf("SplitConfig() error = %v", err)
+	}
+	if len(msgs) == 0 {
+		t.Error("dry run should report planned changes")
+	}
+	if _, err := os.Stat(filepath.Join(filepath.Dir(configPath), "starship")); !os.IsNotExist(err) {
+		t.Error("dry run must not create the new package")
+	}
+	if data, _ := os.ReadFile(configPath); string(data) != string(original) {
+		t.Error("dry run must not change .go4dot.yaml")
+	}
+}
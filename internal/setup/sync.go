@@ -0,0 +1,214 @@
+package setup
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/nvandessel/go4dot/internal/config"
+	"github.com/nvandessel/go4dot/internal/hooks"
+	"github.com/nvandessel/go4dot/internal/state"
+	"github.com/nvandessel/go4dot/internal/stow"
+)
+
+// SyncOptions configures Sync. The CLI and the dashboard run the same
+// pipeline and differ only in how they report it, through the callbacks.
+type SyncOptions struct {
+	Full        bool               // Run pre_sync and post_sync hooks around linking; otherwise only link
+	Adopt       bool               // Move conflicting files in home into the repo before linking
+	RestowAll   bool               // When syncing all configs, restow those that have not changed too
+	Jobs        int                // Configs restowed at once when syncing all; 0 for the default
+	Force       bool               // Restow even if no drift was detected
+	UseTrash    bool               // Move replaced files to the OS trash
+	Interactive bool               // Ask in the terminal how to resolve conflicts
+	Drift       *stow.DriftSummary // Drift from before the sync, to tell conflicts apart; nil checks it
+
+	ProgressFunc func(current, total int, msg string) // Called for progress updates with item counts
+	ActionFunc   func(stow.Action)                    // Called for each file stow changes
+	LinkedFunc   func(name string, err error)         // Called as each config is linked, or fails to be
+	HookStart    func(h hooks.Hook)                   // Called before each hook runs
+	HookOutput   func(h hooks.Hook, line string)      // Called for each line of a hook's output
+
+	// BeforeLink is called once the pre_sync hooks succeeded, right before
+	// linking starts
+	BeforeLink func()
+	// AfterLink is called once linking finished, before the post_sync hooks
+	// run; for a full sync frontends install dependencies and clone
+	// externals in it
+	AfterLink func(result *SyncResult)
+}
+
+// SyncResult is what Sync did
+type SyncResult struct {
+	stow.StowResult
+	Conflicted  []string // Failed configs that existing files in home kept from being linked
+	HooksRun    []hooks.Hook
+	HooksFailed []*hooks.Error
+}
+
+// HooksErr returns the failed hooks as one error, or nil
+func (r *SyncResult) HooksErr() error {
+	return (&hooks.Result{Failed: r.HooksFailed}).Err()
+}
+
+// ErrPreSyncHook is returned, wrapped with the failed hooks, when a pre_sync
+// hook fails and Sync stops before linking anything
+var ErrPreSyncHook = errors.New("pre_sync hook failed")
+
+// Sync links the named configs, or every config when names is nil, the way
+// both frontends sync and link. For a full sync the pre_sync hooks run
+// first and stop the sync if one fails, and after linking and AfterLink the
+// post_sync hooks run for the synced configs (see stow.StowResult.Synced).
+//
+// Named configs are linked one after another, each resolving its conflicts
+// with Adopt or its on_conflict strategy; one that fails doesn't stop the
+// others. All configs go through stow.SyncAll, which only restows those that
+// changed. The error is only set when nothing could be synced.
+func Sync(ctx context.Context, cfg *config.Config, dotfilesPath string, st *state.State, names []string, opts SyncOptions) (*SyncResult, error) {
+	result := &SyncResult{}
+
+	hookNames := names
+	if names == nil {
+		hookNames = configNames(cfg.GetAllConfigs())
+	}
+	if opts.Full {
+		if err := runSyncHooks(ctx, cfg, dotfilesPath, config.HookPreSync, hookNames, opts, result); err != nil {
+			return result, fmt.Errorf("%w: %w", ErrPreSyncHook, err)
+		}
+	}
+	if err := ctx.Err(); err != nil {
+		return result, err
+	}
+
+	drift := opts.Drift
+	if drift == nil {
+		drift, _ = stow.FullDriftCheck(cfg, dotfilesPath)
+	}
+	if opts.BeforeLink != nil {
+		opts.BeforeLink()
+	}
+
+	stowOpts := stow.StowOptions{
+		Force:        opts.Force,
+		UseTrash:     opts.UseTrash,
+		Adopt:        opts.Adopt,
+		RestowAll:    opts.RestowAll,
+		Jobs:         opts.Jobs,
+		Settings:     cfg.Stow,
+		ProgressFunc: opts.ProgressFunc,
+		ActionFunc:   opts.ActionFunc,
+	}
+	if names == nil {
+		res, err := stow.SyncAll(dotfilesPath, cfg, st, opts.Interactive, stowOpts)
+		if err != nil {
+			return result, fmt.Errorf("sync operation failed: %w", err)
+		}
+		result.StowResult = *res
+		if opts.LinkedFunc != nil {
+			for _, name := range res.Success {
+				opts.LinkedFunc(name, nil)
+			}
+			for _, f := range res.Failed {
+				opts.LinkedFunc(f.ConfigName, f.Error)
+			}
+		}
+	} else {
+		for _, name := range names {
+			if ctx.Err() != nil {
+				// Canceled: keep what was synced
+				break
+			}
+			item := cfg.GetConfigByName(name)
+			if item == nil {
+				return result, cfg.UnknownConfigError(name)
+			}
+			linked, err := syncConfig(cfg, dotfilesPath, st, *item, stowOpts, opts)
+			switch {
+			case err != nil:
+				result.Failed = append(result.Failed, stow.StowError{ConfigName: name, Error: err})
+			case linked:
+				result.Success = append(result.Success, name)
+			default:
+				result.Skipped = append(result.Skipped, name)
+				continue
+			}
+			if opts.LinkedFunc != nil {
+				opts.LinkedFunc(name, err)
+			}
+		}
+	}
+
+	// Without adopting, stow refuses to link over the conflicting files
+	// found by the drift check
+	for _, f := range result.Failed {
+		if r := drift.ResultByName(f.ConfigName); !opts.Adopt && r != nil && len(r.ConflictFiles) > 0 {
+			result.Conflicted = append(result.Conflicted, f.ConfigName)
+		}
+	}
+
+	if opts.AfterLink != nil {
+		opts.AfterLink(result)
+	}
+	synced := result.Synced()
+	if opts.Full && len(synced) > 0 && ctx.Err() == nil {
+		// Failures are recorded in the result; the configs are linked
+		_ = runSyncHooks(ctx, cfg, dotfilesPath, config.HookPostSync, synced, opts, result)
+	}
+	return result, nil
+}
+
+// syncConfig links one config, resolving its conflicts with opts.Adopt or
+// its on_conflict strategy. It reports false when on_conflict: skip left the
+// config alone.
+func syncConfig(cfg *config.Config, dotfilesPath string, st *state.State, item config.ConfigItem, stowOpts stow.StowOptions, opts SyncOptions) (bool, error) {
+	if opts.Adopt || item.ConflictStrategy() != config.ConflictAsk {
+		all, err := stow.DetectConflicts(cfg, dotfilesPath)
+		if err != nil {
+			return false, fmt.Errorf("failed to check conflicts: %w", err)
+		}
+		conflicts := stow.ConflictsForConfigs(all, []config.ConfigItem{item})
+		switch {
+		case len(conflicts) == 0:
+		case opts.Adopt && opts.Interactive:
+			if !stow.PromptAdoptConflicts(conflicts, dotfilesPath) {
+				return false, stow.ErrUnresolvedConflicts
+			}
+		case opts.Adopt:
+			if err := stow.AdoptConflicts(conflicts, stowOpts); err != nil {
+				return false, err
+			}
+		default:
+			res, err := stow.ApplyConflictStrategies(conflicts, []config.ConfigItem{item}, stowOpts)
+			if err != nil {
+				return false, err
+			}
+			if len(res.Skipped) > 0 {
+				if opts.ProgressFunc != nil {
+					opts.ProgressFunc(0, 0, fmt.Sprintf("Skipping %s: existing files left in place (on_conflict: skip)", item.Name))
+				}
+				return false, nil
+			}
+		}
+	}
+
+	// SyncSingle links whatever the adopt step left in place
+	stowOpts.Adopt = false
+	if err := stow.SyncSingle(dotfilesPath, item.Name, cfg, st, stowOpts); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// runSyncHooks runs the hooks of stage for configNames, reporting them
+// through opts and recording them in result
+func runSyncHooks(ctx context.Context, cfg *config.Config, dotfilesPath, stage string, configNames []string, opts SyncOptions, result *SyncResult) error {
+	run := hooks.Run(ctx, cfg, stage, configNames, hooks.Options{
+		RepoRoot:   dotfilesPath,
+		TargetDir:  cfg.Stow.TargetDir(),
+		StartFunc:  opts.HookStart,
+		OutputFunc: opts.HookOutput,
+	})
+	result.HooksRun = append(result.HooksRun, run.Ran...)
+	result.HooksFailed = append(result.HooksFailed, run.Failed...)
+	return run.Err()
+}
@@ -0,0 +1,165 @@
+package setup
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/nvandessel/go4dot/internal/config"
+	"github.com/nvandessel/go4dot/internal/hooks"
+	"github.com/nvandessel/go4dot/internal/state"
+	"github.com/nvandessel/go4dot/internal/stow"
+)
+
+func setupSyncRepo(t *testing.T) (dotfilesDir, homeDir string, cfg *config.Config) {
+	t.Helper()
+
+	origCommander := stow.CurrentCommander
+	stow.CurrentCommander = &stow.MockCommander{}
+	t.Cleanup(func() { stow.CurrentCommander = origCommander })
+
+	tmpDir := t.TempDir()
+	homeDir = filepath.Join(tmpDir, "home")
+	dotfilesDir = filepath.Join(tmpDir, "dotfiles")
+	t.Setenv("HOME", homeDir)
+
+	for _, pkg := range []string{"nvim", "git"} {
+		if err := os.MkdirAll(filepath.Join(dotfilesDir, pkg), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dotfilesDir, pkg, "."+pkg+"rc"), []byte(pkg), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.MkdirAll(homeDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg = &config.Config{
+		Configs: config.ConfigGroups{
+			Core: []config.ConfigItem{
+				{Name: "nvim", Path: "nvim"},
+				{Name: "git", Path: "git"},
+			},
+		},
+	}
+	return dotfilesDir, homeDir, cfg
+}
+
+func TestSync(t *testing.T) {
+	dotfilesDir, homeDir, cfg := setupSyncRepo(t)
+	cfg.Hooks = config.Hooks{
+		config.HookPreSync:  {"true"},
+		config.HookPostSync: {"true"},
+	}
+
+	var order []string
+	var linked []string
+	result, err := Sync(context.Background(), cfg, dotfilesDir, state.New(), []string{"nvim", "git"}, SyncOptions{
+		Full:       true,
+		HookStart:  func(h hooks.Hook) { order = append(order, h.Stage) },
+		BeforeLink: func() { order = append(order, "link") },
+		AfterLink:  func(*SyncResult) { order = append(order, "linked") },
+		LinkedFunc: func(name string, err error) {
+			if err == nil {
+				linked = append(linked, name)
+			}
+		},
+	})
+	if err != nil {
+		t.Fatalf("Sync() error = %v", err)
+	}
+
+	want := []string{config.HookPreSync, "link", "linked", config.HookPostSync}
+	if !reflect.DeepEqual(order, want) {
+		t.Errorf("order = %v, want %v", order, want)
+	}
+	if !reflect.DeepEqual(linked, []string{"nvim", "git"}) {
+		t.Errorf("linked = %v, want [nvim git]", linked)
+	}
+	if len(result.HooksRun) != 2 {
+		t.Errorf("HooksRun = %v, want both hooks", result.HooksRun)
+	}
+	for _, f := range []string{".nvimrc", ".gitrc"} {
+		if _, err := os.Lstat(filepath.Join(homeDir, f)); err != nil {
+			t.Errorf("%s not linked", f)
+		}
+	}
+}
+
+func TestSync_LinkOnly(t *testing.T) {
+	dotfilesDir, _, cfg := setupSyncRepo(t)
+	cfg.Hooks = config.Hooks{config.HookPostSync: {"true"}}
+
+	result, err := Sync(context.Background(), cfg, dotfilesDir, state.New(), []string{"nvim"}, SyncOptions{})
+	if err != nil {
+		t.Fatalf("Sync() error = %v", err)
+	}
+	if len(result.HooksRun) != 0 {
+		t.Errorf("linking ran hooks: %v", result.HooksRun)
+	}
+	if !reflect.DeepEqual(result.Synced(), []string{"nvim"}) {
+		t.Errorf("Synced() = %v, want [nvim]", result.Synced())
+	}
+}
+
+func TestSync_PreSyncHookFails(t *testing.T) {
+	dotfilesDir, homeDir, cfg := setupSyncRepo(t)
+	cfg.Hooks = config.Hooks{config.HookPreSync: {"false"}}
+
+	linking := false
+	_, err := Sync(context.Background(), cfg, dotfilesDir, state.New(), nil, SyncOptions{
+		Full:       true,
+		BeforeLink: func() { linking = true },
+	})
+	if !errors.Is(err, ErrPreSyncHook) {
+		t.Fatalf("Sync() error = %v, want ErrPreSyncHook", err)
+	}
+	if linking {
+		t.Error("Sync linked after a failed pre_sync hook")
+	}
+	if _, err := os.Lstat(filepath.Join(homeDir, ".nvimrc")); !os.IsNotExist(err) {
+		t.Error(".nvimrc was linked")
+	}
+}
+
+func TestSync_OnConflictSkip(t *testing.T) {
+	dotfilesDir, homeDir, cfg := setupSyncRepo(t)
+	cfg.Configs.Core[0].OnConflict = config.ConflictSkip
+	if err := os.WriteFile(filepath.Join(homeDir, ".nvimrc"), []byte("local"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := Sync(context.Background(), cfg, dotfilesDir, state.New(), []string{"nvim", "git"}, SyncOptions{})
+	if err != nil {
+		t.Fatalf("Sync() error = %v", err)
+	}
+	if !reflect.DeepEqual(result.Skipped, []string{"nvim"}) {
+		t.Errorf("Skipped = %v, want [nvim]", result.Skipped)
+	}
+	if !reflect.DeepEqual(result.Success, []string{"git"}) {
+		t.Errorf("Success = %v, want [git]", result.Success)
+	}
+	data, err := os.ReadFile(filepath.Join(homeDir, ".nvimrc"))
+	if err != nil || string(data) != "local" {
+		t.Errorf(".nvimrc = %q, %v; want it left in place", data, err)
+	}
+}
+
+func TestSync_NoConfigs(t *testing.T) {
+	dotfilesDir, homeDir, cfg := setupSyncRepo(t)
+
+	result, err := Sync(context.Background(), cfg, dotfilesDir, state.New(), []string{}, SyncOptions{})
+	if err != nil {
+		t.Fatalf("Sync() error = %v", err)
+	}
+	if len(result.Synced()) != 0 {
+		t.Errorf("Synced() = %v, want none", result.Synced())
+	}
+	if _, err := os.Lstat(filepath.Join(homeDir, ".nvimrc")); !os.IsNotExist(err) {
+		t.Error("an empty list linked every config")
+	}
+}
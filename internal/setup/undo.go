@@ -0,0 +1,38 @@
+package setup
+
+import (
+	"fmt"
+
+	"github.com/nvandessel/go4dot/internal/config"
+	"github.com/nvandessel/go4dot/internal/state"
+	"github.com/nvandessel/go4dot/internal/stow"
+)
+
+// UndoConflicts reverts the last conflict resolution (see
+// stow.UndoConflictResolution) and drops the unlinked configs from state.
+// With a partial failure the record is returned along with the error.
+func UndoConflicts(cfg *config.Config, dotfilesPath string, st *state.State, opts RefactorOptions) (*stow.UndoRecord, error) {
+	rec, err := stow.UndoConflictResolution(cfg, dotfilesPath, stow.UndoOptions{
+		DryRun:       opts.DryRun,
+		ProgressFunc: opts.ProgressFunc,
+	})
+	if rec == nil || opts.DryRun || st == nil {
+		return rec, err
+	}
+
+	changed := false
+	for _, name := range rec.Configs() {
+		if cfg.GetConfigByName(name) != nil && st.HasConfig(name) {
+			st.RemoveConfig(name)
+			st.RemoveSymlinkCount(name)
+			st.RemoveFingerprint(name)
+			changed = true
+		}
+	}
+	if changed {
+		if saveErr := st.Save(); saveErr != nil && err == nil {
+			err = fmt.Errorf("files restored but failed to save state: %w", saveErr)
+		}
+	}
+	return rec, err
+}
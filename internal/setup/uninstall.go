@@ -6,6 +6,7 @@ import (
 	"github.com/nvandessel/go4dot/internal/config"
 	"github.com/nvandessel/go4dot/internal/deps"
 	"github.com/nvandessel/go4dot/internal/machine"
+	"github.com/nvandessel/go4dot/internal/snapshot"
 	"github.com/nvandessel/go4dot/internal/state"
 	"github.com/nvandessel/go4dot/internal/stow"
 )
@@ -14,11 +15,14 @@ import (
 type UninstallOptions struct {
 	RemoveExternal bool
 	RemoveMachine  bool
+	UseTrash       bool // Move removed files to the OS trash instead of deleting
 	ProgressFunc   func(current, total int, msg string)
 }
 
 // Uninstall removes the dotfiles installation.
 func Uninstall(cfg *config.Config, dotfilesPath string, st *state.State, opts UninstallOptions) error {
+	defer snapshot.Begin("uninstall")()
+
 	if opts.ProgressFunc != nil {
 		opts.ProgressFunc(0, 0, fmt.Sprintf("Uninstalling dotfiles from %s...", dotfilesPath))
 	}
@@ -45,6 +49,7 @@ func Uninstall(cfg *config.Config, dotfilesPath string, st *state.State, opts Un
 
 		stowOpts := stow.StowOptions{
 			ProgressFunc: opts.ProgressFunc,
+			Settings:     cfg.Stow,
 		}
 
 		result := stow.UnstowConfigs(dotfilesPath, configsToUnstow, stowOpts)
@@ -56,6 +61,17 @@ func Uninstall(cfg *config.Config, dotfilesPath string, st *state.State, opts Un
 		}
 	}
 
+	// Remove the symlinks from the links section
+	if st != nil && len(st.Links) > 0 {
+		if opts.ProgressFunc != nil {
+			opts.ProgressFunc(0, 0, fmt.Sprintf("Removing %d links...", len(st.Links)))
+		}
+		result := stow.RemoveLinks(st, stow.StowOptions{ProgressFunc: opts.ProgressFunc})
+		if len(result.Failed) > 0 && opts.ProgressFunc != nil {
+			opts.ProgressFunc(0, 0, fmt.Sprintf("⚠ %d links failed to remove", len(result.Failed)))
+		}
+	}
+
 	// Remove external deps if requested
 	if opts.RemoveExternal && len(cfg.External) > 0 {
 		if opts.ProgressFunc != nil {
@@ -64,6 +80,7 @@ func Uninstall(cfg *config.Config, dotfilesPath string, st *state.State, opts Un
 
 		for _, ext := range cfg.External {
 			extOpts := deps.ExternalOptions{
+				UseTrash:     opts.UseTrash,
 				ProgressFunc: opts.ProgressFunc,
 			}
 
@@ -83,6 +100,7 @@ func Uninstall(cfg *config.Config, dotfilesPath string, st *state.State, opts Un
 
 		for _, mc := range cfg.MachineConfig {
 			renderOpts := machine.RenderOptions{
+				UseTrash:     opts.UseTrash,
 				ProgressFunc: opts.ProgressFunc,
 			}
 
@@ -94,7 +112,12 @@ func Uninstall(cfg *config.Config, dotfilesPath string, st *state.State, opts Un
 		}
 	}
 
-	// Remove state file
+	// Remove state file, keeping it for 'g4d undo'
+	if statePath, err := state.GetStatePath(); err == nil {
+		if err := snapshot.Save(statePath); err != nil {
+			return err
+		}
+	}
 	if err := state.Delete(); err != nil {
 		return fmt.Errorf("failed to remove state file: %w", err)
 	}
@@ -2,16 +2,13 @@ package setup
 
 import (
 	"fmt"
-	"os"
-	"os/exec"
-	"path/filepath"
-	"strings"
 
 	"github.com/nvandessel/go4dot/internal/config"
 	"github.com/nvandessel/go4dot/internal/deps"
 	"github.com/nvandessel/go4dot/internal/platform"
 	"github.com/nvandessel/go4dot/internal/state"
 	"github.com/nvandessel/go4dot/internal/stow"
+	"github.com/nvandessel/go4dot/internal/vcs"
 )
 
 // UpdateOptions configures the update behavior.
@@ -21,38 +18,38 @@ type UpdateOptions struct {
 	ProgressFunc   func(current, total int, msg string)
 }
 
-// Update pulls latest changes from git and updates dotfiles.
+// Update pulls latest changes with the repo's VCS and updates dotfiles.
 func Update(cfg *config.Config, dotfilesPath string, st *state.State, opts UpdateOptions) error {
 	if opts.ProgressFunc != nil {
 		opts.ProgressFunc(0, 0, fmt.Sprintf("Updating dotfiles in %s...", dotfilesPath))
 	}
 
-	// Check if it's a git repo
-	gitDir := filepath.Join(dotfilesPath, ".git")
-	if _, err := os.Stat(gitDir); os.IsNotExist(err) {
-		return fmt.Errorf("%s is not a git repository", dotfilesPath)
+	repo := vcs.Detect(dotfilesPath)
+	if repo == nil {
+		return fmt.Errorf("%s is not a git, jj or hg repository", dotfilesPath)
+	}
+	if !vcs.Installed(repo) {
+		return fmt.Errorf("%s is not installed", repo.Name())
 	}
 
 	// Get current HEAD
-	oldHead, err := gitHead(dotfilesPath)
+	oldHead, err := repo.Head(dotfilesPath)
 	if err != nil {
 		if opts.ProgressFunc != nil {
 			opts.ProgressFunc(0, 0, fmt.Sprintf("  ⚠ Warning: could not get current HEAD: %v", err))
 		}
 	}
 
-	// Run git pull
+	// Pull upstream changes
 	if opts.ProgressFunc != nil {
 		opts.ProgressFunc(0, 0, "Pulling latest changes...")
 	}
-	pullCmd := exec.Command("git", "pull", "--rebase")
-	pullCmd.Dir = dotfilesPath
-	if output, err := pullCmd.CombinedOutput(); err != nil {
-		return fmt.Errorf("git pull failed: %w\nOutput: %s", err, string(output))
+	if err := repo.Pull(dotfilesPath); err != nil {
+		return err
 	}
 
 	// Get new HEAD
-	newHead, err := gitHead(dotfilesPath)
+	newHead, err := repo.Head(dotfilesPath)
 	if err != nil {
 		if opts.ProgressFunc != nil {
 			opts.ProgressFunc(0, 0, fmt.Sprintf("  ⚠ Warning: could not get new HEAD: %v", err))
@@ -66,10 +63,11 @@ func Update(cfg *config.Config, dotfilesPath string, st *state.State, opts Updat
 		}
 
 		// Check if config file changed
-		configChanged, _ := gitFileChanged(dotfilesPath, oldHead, newHead, config.ConfigFileName)
+		configChanged, _ := repo.Changed(dotfilesPath, oldHead, newHead, config.ConfigFileName)
 		if configChanged {
 			if opts.ProgressFunc != nil {
 				opts.ProgressFunc(0, 0, fmt.Sprintf("  Note: %s was updated. Reloading config...", config.ConfigFileName))
+				reportConfigChanges(dotfilesPath, oldHead, newHead, opts.ProgressFunc)
 			}
 			newCfg, err := config.LoadFromPath(dotfilesPath)
 			if err == nil {
@@ -94,6 +92,7 @@ func Update(cfg *config.Config, dotfilesPath string, st *state.State, opts Updat
 
 		stowOpts := stow.StowOptions{
 			ProgressFunc: opts.ProgressFunc,
+			Settings:     cfg.Stow,
 		}
 
 		// Get configs to restow (from state or all from config)
@@ -176,24 +175,18 @@ func Update(cfg *config.Config, dotfilesPath string, st *state.State, opts Updat
 	return nil
 }
 
-// gitHead returns the current HEAD commit hash
-func gitHead(dir string) (string, error) {
-	cmd := exec.Command("git", "rev-parse", "HEAD")
-	cmd.Dir = dir
-	out, err := cmd.Output()
+// reportConfigChanges lists what changed in .go4dot.yaml between two
+// revisions, entry by entry
+func reportConfigChanges(dotfilesPath, from, to string, progress func(current, total int, msg string)) {
+	oldCfg, err := config.LoadRevision(dotfilesPath, from)
 	if err != nil {
-		return "", err
+		return
 	}
-	return strings.TrimSpace(string(out)), nil
-}
-
-// gitFileChanged checks if a file changed between two commits
-func gitFileChanged(dir, oldCommit, newCommit, filename string) (bool, error) {
-	cmd := exec.Command("git", "diff", "--name-only", oldCommit, newCommit, "--", filename)
-	cmd.Dir = dir
-	out, err := cmd.Output()
+	newCfg, err := config.LoadRevision(dotfilesPath, to)
 	if err != nil {
-		return false, err
+		return
+	}
+	for _, c := range config.Compare(oldCfg, newCfg) {
+		progress(0, 0, "    "+c.String())
 	}
-	return strings.TrimSpace(string(out)) != "", nil
 }
@@ -0,0 +1,96 @@
+package shellinit
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Markers around the block Wire adds to an rc file
+const (
+	BeginMarker = "# >>> go4dot shell integration >>>"
+	EndMarker   = "# <<< go4dot shell integration <<<"
+)
+
+// RCFile returns the startup file for shell
+func RCFile(shell, home string) string {
+	switch shell {
+	case "zsh":
+		return filepath.Join(home, ".zshrc")
+	case "bash":
+		return filepath.Join(home, ".bashrc")
+	case "fish":
+		return filepath.Join(home, ".config", "fish", "config.fish")
+	}
+	return ""
+}
+
+// sourceLine loads the snippet at shell startup, and does nothing once g4d
+// is uninstalled
+func sourceLine(shell string) string {
+	if shell == "fish" {
+		return "command -q g4d; and g4d shell-init fish | source"
+	}
+	return fmt.Sprintf(`command -v g4d >/dev/null 2>&1 && eval "$(g4d shell-init %s)"`, shell)
+}
+
+// Block returns the marked block Wire adds to shell's rc file
+func Block(shell string) string {
+	return BeginMarker + "\n" + sourceLine(shell) + "\n" + EndMarker + "\n"
+}
+
+// Wire makes shell's rc file source the snippet. An existing go4dot block
+// is replaced in place, so running it again changes nothing. The rc file is
+// followed if it is a symlink, e.g. one managed by go4dot itself.
+func Wire(shell, home string) (changed bool, path string, err error) {
+	path = RCFile(shell, home)
+	if path == "" {
+		return false, "", fmt.Errorf("unsupported shell %q", shell)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return false, path, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	content := string(data)
+	updated := replaceBlock(content, Block(shell))
+	if updated == content {
+		return false, path, nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return false, path, fmt.Errorf("failed to create %s: %w", filepath.Dir(path), err)
+	}
+	mode := os.FileMode(0644)
+	if info, err := os.Stat(path); err == nil {
+		mode = info.Mode().Perm()
+	}
+	if err := os.WriteFile(path, []byte(updated), mode); err != nil {
+		return false, path, fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return true, path, nil
+}
+
+// replaceBlock swaps the marked block in content for block, or appends
+// block if there is none
+func replaceBlock(content, block string) string {
+	start := strings.Index(content, BeginMarker)
+	if start >= 0 {
+		if end := strings.Index(content[start:], EndMarker); end >= 0 {
+			end += start + len(EndMarker)
+			if end < len(content) && content[end] == '\n' {
+				end++
+			}
+			return content[:start] + block + content[end:]
+		}
+	}
+
+	if content != "" && !strings.HasSuffix(content, "\n") {
+		content += "\n"
+	}
+	if content != "" {
+		content += "\n"
+	}
+	return content + block
+}
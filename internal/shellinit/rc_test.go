@@ -0,0 +1,67 @@
+package shellinit
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestWire(t *testing.T) {
+	home := t.TempDir()
+	zshrc := filepath.Join(home, ".zshrc")
+	if err := os.WriteFile(zshrc, []byte("export EDITOR=nvim"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	changed, path, err := Wire("zsh", home)
+	if err != nil || !changed || path != zshrc {
+		t.Fatalf("Wire() = %v, %q, %v", changed, path, err)
+	}
+	data, _ := os.ReadFile(zshrc)
+	want := "export EDITOR=nvim\n\n" + Block("zsh")
+	if string(data) != want {
+		t.Errorf(".zshrc = %q, want %q", data, want)
+	}
+	if info, _ := os.Stat(zshrc); info.Mode().Perm() != 0600 {
+		t.Errorf(".zshrc mode = %v, want 0600", info.Mode().Perm())
+	}
+
+	// Running again changes nothing
+	if changed, _, err := Wire("zsh", home); err != nil || changed {
+		t.Errorf("second Wire() = %v, %v, want unchanged", changed, err)
+	}
+
+	// An outdated block is replaced in place
+	old := "a\n" + BeginMarker + "\neval \"$(go4dot init)\"\n" + EndMarker + "\nb\n"
+	if err := os.WriteFile(zshrc, []byte(old), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if changed, _, err := Wire("zsh", home); err != nil || !changed {
+		t.Fatalf("Wire() on an old block = %v, %v", changed, err)
+	}
+	data, _ = os.ReadFile(zshrc)
+	if string(data) != "a\n"+Block("zsh")+"b\n" {
+		t.Errorf(".zshrc = %q", data)
+	}
+}
+
+func TestWire_Fish(t *testing.T) {
+	home := t.TempDir()
+
+	changed, path, err := Wire("fish", home)
+	if err != nil || !changed {
+		t.Fatalf("Wire() = %v, %v", changed, err)
+	}
+	if path != filepath.Join(home, ".config", "fish", "config.fish") {
+		t.Errorf("path = %q", path)
+	}
+	data, _ := os.ReadFile(path)
+	if !strings.Contains(string(data), "g4d shell-init fish | source") {
+		t.Errorf("config.fish = %q", data)
+	}
+
+	if _, _, err := Wire("tcsh", home); err == nil {
+		t.Error("expected an error for an unsupported shell")
+	}
+}
@@ -0,0 +1,219 @@
+// Package shellinit generates the shell snippet printed by g4d shell-init:
+// a few aliases for the dotfiles repo and a cd hook that warns when you are
+// about to edit managed files through their links instead of in the repo.
+package shellinit
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/nvandessel/go4dot/internal/config"
+)
+
+// ManagedDir is a directory under home whose files all come from one config
+type ManagedDir struct {
+	Target string // Directory under home
+	Source string // The same directory in the dotfiles repo
+	Config string
+}
+
+// ManagedDirs returns the directories under home that belong to a single
+// config, outermost first. Home itself and directories shared by several
+// configs, such as ~/.config, are left out.
+func ManagedDirs(cfg *config.Config, dotfilesPath, home string) []ManagedDir {
+	repoIgnore, _ := config.LoadRepoIgnore(dotfilesPath)
+
+	// Deepest directory holding all of each config's files, relative to home
+	roots := make(map[string]string)
+	var names []string
+	for _, item := range cfg.GetAllConfigs() {
+		configDir := item.Dir(dotfilesPath)
+		var root string
+		found := false
+		_ = filepath.Walk(configDir, func(path string, info os.FileInfo, err error) error {
+			if err != nil {
+				return nil
+			}
+			rel, err := filepath.Rel(configDir, path)
+			if err != nil || rel == "." {
+				return nil
+			}
+			if repoIgnore.Match(filepath.Join(item.Path, rel), info.IsDir()) {
+				if info.IsDir() {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			if info.IsDir() {
+				return nil
+			}
+			dir := filepath.Dir(rel)
+			if !found {
+				root, found = dir, true
+			} else {
+				root = commonDir(root, dir)
+			}
+			return nil
+		})
+		if found && root != "." {
+			roots[item.Name] = root
+			names = append(names, item.Name)
+		}
+	}
+
+	var dirs []ManagedDir
+	for _, name := range names {
+		root := roots[name]
+		shared := false
+		for _, other := range names {
+			if other != name && isWithin(roots[other], root) {
+				shared = true
+				break
+			}
+		}
+		if shared {
+			continue
+		}
+		item := cfg.GetConfigByName(name)
+		dirs = append(dirs, ManagedDir{
+			Target: filepath.Join(home, root),
+			Source: filepath.Join(item.Dir(dotfilesPath), root),
+			Config: name,
+		})
+	}
+	sort.Slice(dirs, func(i, j int) bool { return dirs[i].Target < dirs[j].Target })
+	return dirs
+}
+
+// commonDir returns the deepest directory containing both relative paths
+func commonDir(a, b string) string {
+	as := strings.Split(a, string(filepath.Separator))
+	bs := strings.Split(b, string(filepath.Separator))
+	n := 0
+	for n < len(as) && n < len(bs) && as[n] == bs[n] {
+		n++
+	}
+	if n == 0 {
+		return "."
+	}
+	return filepath.Join(as[:n]...)
+}
+
+// isWithin reports whether path is dir or inside it
+func isWithin(path, dir string) bool {
+	return path == dir || strings.HasPrefix(path, dir+string(filepath.Separator))
+}
+
+// defaultAliases are included in every snippet, before the config's own
+var defaultAliases = [][2]string{
+	{"g4ds", "g4d sync"},
+	{"g4dst", "g4d status"},
+	{"g4dd", "g4d doctor"},
+}
+
+// Snippet returns the shell integration code for shell
+func Snippet(shell string, cfg *config.Config, dotfilesPath, home string) (string, error) {
+	if !config.IsShell(shell) {
+		return "", fmt.Errorf("unsupported shell %q (supported: %s)", shell, strings.Join(config.Shells, ", "))
+	}
+
+	si := cfg.ShellIntegration
+	aliases := append([][2]string{}, defaultAliases...)
+	for _, name := range si.AliasNames() {
+		aliases = append(aliases, [2]string{name, si.Aliases[name]})
+	}
+	var dirs []ManagedDir
+	if si.WarnsInManagedDirs() {
+		dirs = ManagedDirs(cfg, dotfilesPath, home)
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "# go4dot shell integration, generated by `g4d shell-init %s`\n", shell)
+	if shell == "fish" {
+		writeFish(&b, dotfilesPath, home, aliases, dirs)
+	} else {
+		writePOSIX(&b, shell, dotfilesPath, home, aliases, dirs)
+	}
+	if si.MOTD {
+		// Same syntax in every supported shell
+		b.WriteString("\ng4d motd\n")
+	}
+	return b.String(), nil
+}
+
+func writePOSIX(b *strings.Builder, shell, dotfilesPath, home string, aliases [][2]string, dirs []ManagedDir) {
+	fmt.Fprintf(b, "export G4D_DOTFILES=%s\n", shQuote(dotfilesPath))
+	b.WriteString("alias cddots='cd \"$G4D_DOTFILES\"'\n")
+	for _, a := range aliases {
+		fmt.Fprintf(b, "alias %s=%s\n", a[0], shQuote(a[1]))
+	}
+	if len(dirs) == 0 {
+		return
+	}
+
+	b.WriteString("\n_g4d_warn_managed() {\n  case \"$PWD/\" in\n")
+	for _, d := range dirs {
+		fmt.Fprintf(b, "    %s*) echo %s >&2 ;;\n", shQuote(d.Target+"/"), shQuote(warning(d, home)))
+	}
+	b.WriteString("  esac\n}\n")
+
+	if shell == "zsh" {
+		b.WriteString("autoload -Uz add-zsh-hook\nadd-zsh-hook chpwd _g4d_warn_managed\n")
+		return
+	}
+	// bash has no chpwd hook; check for a new directory before each prompt
+	b.WriteString(`_g4d_chpwd() {
+  [ "$PWD" = "${_g4d_last_pwd-}" ] && return
+  _g4d_last_pwd=$PWD
+  _g4d_warn_managed
+}
+case ";${PROMPT_COMMAND-};" in
+  *";_g4d_chpwd;"*) ;;
+  *) PROMPT_COMMAND="_g4d_chpwd${PROMPT_COMMAND:+;$PROMPT_COMMAND}" ;;
+esac
+`)
+}
+
+func writeFish(b *strings.Builder, dotfilesPath, home string, aliases [][2]string, dirs []ManagedDir) {
+	fmt.Fprintf(b, "set -gx G4D_DOTFILES %s\n", fishQuote(dotfilesPath))
+	b.WriteString("alias cddots 'cd $G4D_DOTFILES'\n")
+	for _, a := range aliases {
+		fmt.Fprintf(b, "alias %s %s\n", a[0], fishQuote(a[1]))
+	}
+	if len(dirs) == 0 {
+		return
+	}
+
+	b.WriteString("\nfunction _g4d_warn_managed --on-variable PWD\n    switch \"$PWD/\"\n")
+	for _, d := range dirs {
+		fmt.Fprintf(b, "        case %s\n            echo %s >&2\n", fishQuote(d.Target+"/*"), fishQuote(warning(d, home)))
+	}
+	b.WriteString("    end\nend\n")
+}
+
+// warning is the message shown on cd into d
+func warning(d ManagedDir, home string) string {
+	return fmt.Sprintf("go4dot: %s is managed by %s; edit it in %s", tilde(d.Target, home), d.Config, tilde(d.Source, home))
+}
+
+// tilde abbreviates path under home with ~
+func tilde(path, home string) string {
+	if rel, err := filepath.Rel(home, path); err == nil && !strings.HasPrefix(rel, "..") {
+		return filepath.Join("~", rel)
+	}
+	return path
+}
+
+// shQuote quotes s for sh, bash and zsh
+func shQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// fishQuote quotes s for fish, where only \ and ' are special in single quotes
+func fishQuote(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	return "'" + strings.ReplaceAll(s, "'", `\'`) + "'"
+}
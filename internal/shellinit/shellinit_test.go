@@ -0,0 +1,174 @@
+package shellinit
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/nvandessel/go4dot/internal/config"
+)
+
+// writeFiles creates empty files at the given paths under root
+func writeFiles(t *testing.T, root string, paths ...string) {
+	t.Helper()
+	for _, p := range paths {
+		full := filepath.Join(root, p)
+		if err := os.MkdirAll(filepath.Dir(full), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(full, nil, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func testConfig(names ...string) *config.Config {
+	cfg := &config.Config{}
+	for _, name := range names {
+		cfg.Configs.Core = append(cfg.Configs.Core, config.ConfigItem{Name: name, Path: name})
+	}
+	return cfg
+}
+
+func TestManagedDirs(t *testing.T) {
+	repo := t.TempDir()
+	home := "/home/alice"
+	writeFiles(t, repo,
+		"nvim/.config/nvim/init.lua",
+		"nvim/.config/nvim/lua/plugins.lua",
+		"zsh/.zshrc",
+		"git/.gitconfig",
+		"git/.config/git/ignore",
+		"kitty/.config/kitty/kitty.conf",
+		"kitty/.config/kitty/wip/theme.conf",
+		"tmux/.config/tmux/tmux.conf",
+		"tmux/.config/tmux-extra/conf",
+	)
+	if err := os.WriteFile(filepath.Join(repo, config.IgnoreFileName), []byte("kitty/.config/kitty/wip/\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	dirs := ManagedDirs(testConfig("nvim", "zsh", "git", "kitty", "tmux"), repo, home)
+
+	var got []string
+	for _, d := range dirs {
+		got = append(got, d.Config+":"+d.Target)
+	}
+	// zsh and git link straight into home, and tmux shares ~/.config
+	want := []string{
+		"kitty:" + filepath.Join(home, ".config/kitty"),
+		"nvim:" + filepath.Join(home, ".config/nvim"),
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ManagedDirs() = %v, want %v", got, want)
+	}
+	if len(dirs) > 1 && dirs[1].Source != filepath.Join(repo, "nvim/.config/nvim") {
+		t.Errorf("nvim source = %q", dirs[1].Source)
+	}
+}
+
+func TestManagedDirs_Nested(t *testing.T) {
+	repo := t.TempDir()
+	writeFiles(t, repo,
+		"nvim/.config/nvim/init.lua",
+		"nvim-local/.config/nvim/lua/local.lua",
+	)
+
+	// A directory holding another config's files is not one config's
+	dirs := ManagedDirs(testConfig("nvim", "nvim-local"), repo, "/home/alice")
+	if len(dirs) != 1 || dirs[0].Config != "nvim-local" {
+		t.Errorf("ManagedDirs() = %+v, want only nvim-local", dirs)
+	}
+}
+
+func TestSnippet(t *testing.T) {
+	repo := t.TempDir()
+	home := t.TempDir()
+	writeFiles(t, repo, "nvim/.config/nvim/init.lua")
+	cfg := testConfig("nvim")
+	cfg.ShellIntegration.Aliases = map[string]string{"g4du": "g4d update", "quote": "echo 'hi'"}
+
+	tests := []struct {
+		shell string
+		want  []string
+	}{
+		{"zsh", []string{
+			"export G4D_DOTFILES='" + repo + "'",
+			"alias g4ds='g4d sync'",
+			`alias quote='echo '\''hi'\'''`,
+			"'" + home + "/.config/nvim/'*) echo 'go4dot: ~/.config/nvim is managed by nvim; edit it in ",
+			"add-zsh-hook chpwd _g4d_warn_managed",
+		}},
+		{"bash", []string{
+			"alias cddots='cd \"$G4D_DOTFILES\"'",
+			"alias g4du='g4d update'",
+			`PROMPT_COMMAND="_g4d_chpwd${PROMPT_COMMAND:+;$PROMPT_COMMAND}"`,
+		}},
+		{"fish", []string{
+			"set -gx G4D_DOTFILES '" + repo + "'",
+			"alias g4dst 'g4d status'",
+			`alias quote 'echo \'hi\''`,
+			"function _g4d_warn_managed --on-variable PWD",
+			"case '" + home + "/.config/nvim/*'",
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.shell, func(t *testing.T) {
+			got, err := Snippet(tt.shell, cfg, repo, home)
+			if err != nil {
+				t.Fatalf("Snippet() error = %v", err)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(got, want) {
+					t.Errorf("Snippet(%s) missing %q:\n%s", tt.shell, want, got)
+				}
+			}
+		})
+	}
+
+	if _, err := Snippet("tcsh", cfg, repo, home); err == nil {
+		t.Error("expected an error for an unsupported shell")
+	}
+
+	cfg.ShellIntegration.DirHook = config.DirHookOff
+	if got, _ := Snippet("zsh", cfg, repo, home); strings.Contains(got, "_g4d_warn_managed") {
+		t.Errorf("dir_hook off should drop the hook:\n%s", got)
+	}
+
+	if got, _ := Snippet("zsh", cfg, repo, home); strings.Contains(got, "g4d motd") {
+		t.Errorf("motd should be off by default:\n%s", got)
+	}
+	cfg.ShellIntegration.MOTD = true
+	if got, _ := Snippet("fish", cfg, repo, home); !strings.HasSuffix(got, "\ng4d motd\n") {
+		t.Errorf("motd should end the snippet:\n%s", got)
+	}
+}
+
+// TestSnippet_Syntax checks the snippets parse in the shells that are installed
+func TestSnippet_Syntax(t *testing.T) {
+	repo := t.TempDir()
+	writeFiles(t, repo, "nvim/.config/nvim/init.lua")
+	cfg := testConfig("nvim")
+	cfg.ShellIntegration.Aliases = map[string]string{"quote": `echo 'it''s' "$HOME"`}
+	cfg.ShellIntegration.MOTD = true
+
+	for _, shell := range config.Shells {
+		bin, err := exec.LookPath(shell)
+		if err != nil {
+			continue
+		}
+		t.Run(shell, func(t *testing.T) {
+			snippet, err := Snippet(shell, cfg, repo, t.TempDir())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if out, err := exec.Command(bin, "-n", "-c", snippet).CombinedOutput(); err != nil {
+				t.Errorf("%s -n failed: %v\n%s\n%s", shell, err, out, snippet)
+			}
+		})
+	}
+}
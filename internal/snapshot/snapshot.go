@@ -0,0 +1,461 @@
+// Package snapshot keeps what a destructive operation is about to replace
+// or remove, so `g4d undo` can put the affected paths back the way they
+// were. Unlike the conflict undo record (see stow.UndoConflictResolution),
+// it covers everything a sync or uninstall touches: symlinks it removes,
+// rendered files it rewrites, externals and the state file it deletes.
+//
+// An operation calls Begin, and the code that removes or rewrites a path
+// calls Save first; Save does nothing while no snapshot is being taken.
+// Symlinks are recorded by where they point, files are copied and
+// directories are hardlinked, so snapshots stay cheap. They expire after
+// Retention.
+package snapshot
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/nvandessel/go4dot/internal/fsutil"
+	"github.com/nvandessel/go4dot/internal/state"
+)
+
+const (
+	// Dir holds one directory per snapshot, in the state directory
+	Dir = "snapshots"
+	// ManifestName lists a snapshot's entries, in its directory
+	ManifestName = "manifest.json"
+	// Retention is how long a snapshot is kept
+	Retention = 7 * 24 * time.Hour
+	// MaxKept is how many snapshots are kept at most, newest first
+	MaxKept = 20
+
+	// filesDir holds the saved files, in a snapshot's directory
+	filesDir = "files"
+)
+
+// ErrNoSnapshot is returned when there is no snapshot to restore
+var ErrNoSnapshot = errors.New("no snapshot to restore")
+
+// Kind is what was at a path when it was saved
+type Kind string
+
+const (
+	KindFile    Kind = "file"
+	KindSymlink Kind = "symlink"
+	KindDir     Kind = "dir"
+)
+
+// Entry is one saved path
+type Entry struct {
+	Path  string      `json:"path"`
+	Kind  Kind        `json:"kind"`
+	Mode  fs.FileMode `json:"mode"`
+	Link  string      `json:"link,omitempty"`  // Where a symlink pointed
+	Saved string      `json:"saved,omitempty"` // The copy of a file or directory, relative to the snapshot
+}
+
+// Snapshot is what one operation replaced or removed
+type Snapshot struct {
+	ID        string    `json:"id"`
+	Operation string    `json:"operation"` // The g4d command, e.g. "sync"
+	CreatedAt time.Time `json:"created_at"`
+	Entries   []Entry   `json:"entries"`
+
+	dir string // Where the snapshot is kept
+}
+
+// Paths returns the saved paths
+func (s *Snapshot) Paths() []string {
+	paths := make([]string, 0, len(s.Entries))
+	for _, e := range s.Entries {
+		paths = append(paths, e.Path)
+	}
+	return paths
+}
+
+var (
+	mu sync.Mutex
+	// active is the snapshot being taken; nil when none is
+	active *Snapshot
+	// saved are the paths already in the active snapshot
+	saved map[string]bool
+)
+
+// Begin starts a snapshot for operation, after removing expired ones. The
+// returned function ends it and must be called when the operation is done.
+// When a snapshot is already being taken, as when an install runs a sync,
+// paths go into that one and ending is left to its caller.
+func Begin(operation string) func() {
+	return begin(operation, true)
+}
+
+// begin is Begin, removing expired snapshots only when prune is set
+func begin(operation string, prune bool) func() {
+	mu.Lock()
+	defer mu.Unlock()
+
+	if active != nil {
+		return func() {}
+	}
+	if prune {
+		_ = pruneLocked(time.Now())
+	}
+	active = &Snapshot{Operation: operation, CreatedAt: time.Now()}
+	saved = make(map[string]bool)
+	return end
+}
+
+// end stops the active snapshot. Entries were saved as they were added.
+func end() {
+	mu.Lock()
+	defer mu.Unlock()
+	active = nil
+	saved = nil
+}
+
+// Save adds what is at path to the snapshot being taken, before it is
+// replaced or removed. It does nothing when no snapshot is being taken, when
+// nothing is at path or when path is already saved.
+func Save(path string) error {
+	mu.Lock()
+	defer mu.Unlock()
+
+	if active == nil {
+		return nil
+	}
+	path = filepath.Clean(path)
+	if saved[path] {
+		return nil
+	}
+	info, err := os.Lstat(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to snapshot %s: %w", path, err)
+	}
+
+	if active.dir == "" {
+		if err := createLocked(active); err != nil {
+			return err
+		}
+	}
+
+	entry := Entry{Path: path, Mode: info.Mode().Perm()}
+	rel := filepath.Join(filesDir, fmt.Sprintf("%d", len(active.Entries)), filepath.Base(path))
+	switch {
+	case info.Mode()&os.ModeSymlink != 0:
+		entry.Kind = KindSymlink
+		if entry.Link, err = os.Readlink(path); err != nil {
+			return fmt.Errorf("failed to snapshot %s: %w", path, err)
+		}
+	case info.IsDir():
+		entry.Kind, entry.Saved = KindDir, rel
+		if err := linkTree(path, filepath.Join(active.dir, rel)); err != nil {
+			return fmt.Errorf("failed to snapshot %s: %w", path, err)
+		}
+	default:
+		// Copied rather than hardlinked: a file rewritten in place would
+		// change the snapshot along with it
+		entry.Kind, entry.Saved = KindFile, rel
+		saveTo := filepath.Join(active.dir, rel)
+		if err := os.MkdirAll(filepath.Dir(saveTo), 0700); err != nil {
+			return fmt.Errorf("failed to snapshot %s: %w", path, err)
+		}
+		if err := fsutil.CopyFile(path, saveTo, info.Mode().Perm()); err != nil {
+			return fmt.Errorf("failed to snapshot %s: %w", path, err)
+		}
+	}
+
+	active.Entries = append(active.Entries, entry)
+	saved[path] = true
+	return writeManifest(active)
+}
+
+// createLocked creates the directory of s, named after when it was taken
+// and its operation
+func createLocked(s *Snapshot) error {
+	root, err := snapshotsDir()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(root, 0700); err != nil {
+		return fmt.Errorf("failed to create snapshot directory: %w", err)
+	}
+
+	base := s.CreatedAt.Format("20060102-150405") + "-" + s.Operation
+	id := base
+	for n := 2; ; n++ {
+		err := os.Mkdir(filepath.Join(root, id), 0700)
+		if err == nil {
+			break
+		}
+		if !os.IsExist(err) {
+			return fmt.Errorf("failed to create snapshot directory: %w", err)
+		}
+		id = fmt.Sprintf("%s-%d", base, n)
+	}
+	s.ID, s.dir = id, filepath.Join(root, id)
+	return nil
+}
+
+// snapshotsDir returns the directory holding the snapshots
+func snapshotsDir() (string, error) {
+	dir, err := state.GetStateDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, Dir), nil
+}
+
+func writeManifest(s *Snapshot) error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal snapshot: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(s.dir, ManifestName), data, 0600); err != nil {
+		return fmt.Errorf("failed to write snapshot: %w", err)
+	}
+	return nil
+}
+
+// List returns the snapshots, newest first
+func List() ([]*Snapshot, error) {
+	root, err := snapshotsDir()
+	if err != nil {
+		return nil, err
+	}
+	dirs, err := os.ReadDir(root)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read snapshots: %w", err)
+	}
+
+	var snaps []*Snapshot
+	for _, d := range dirs {
+		if !d.IsDir() {
+			continue
+		}
+		s, err := load(filepath.Join(root, d.Name()))
+		if err != nil || len(s.Entries) == 0 {
+			continue
+		}
+		snaps = append(snaps, s)
+	}
+	sort.SliceStable(snaps, func(i, j int) bool {
+		if snaps[i].CreatedAt.Equal(snaps[j].CreatedAt) {
+			return snaps[i].ID > snaps[j].ID
+		}
+		return snaps[i].CreatedAt.After(snaps[j].CreatedAt)
+	})
+	return snaps, nil
+}
+
+// Get returns the snapshot with the given ID, or the newest one when id is
+// empty
+func Get(id string) (*Snapshot, error) {
+	snaps, err := List()
+	if err != nil {
+		return nil, err
+	}
+	for _, s := range snaps {
+		if id == "" || s.ID == id {
+			return s, nil
+		}
+	}
+	if id != "" {
+		return nil, fmt.Errorf("snapshot %q not found", id)
+	}
+	return nil, ErrNoSnapshot
+}
+
+func load(dir string) (*Snapshot, error) {
+	data, err := os.ReadFile(filepath.Join(dir, ManifestName))
+	if err != nil {
+		return nil, err
+	}
+	var s Snapshot
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, fmt.Errorf("failed to parse snapshot %s: %w", dir, err)
+	}
+	s.ID, s.dir = filepath.Base(dir), dir
+	return &s, nil
+}
+
+// pruneLocked removes the snapshots older than Retention and all but the
+// MaxKept newest, along with leftovers that never got an entry
+func pruneLocked(now time.Time) error {
+	root, err := snapshotsDir()
+	if err != nil {
+		return err
+	}
+	dirs, err := os.ReadDir(root)
+	if err != nil {
+		return nil
+	}
+	for _, d := range dirs {
+		dir := filepath.Join(root, d.Name())
+		if s, err := load(dir); err != nil || len(s.Entries) == 0 {
+			_ = os.RemoveAll(dir)
+		}
+	}
+
+	snaps, err := List()
+	if err != nil {
+		return err
+	}
+	for i, s := range snaps {
+		if i >= MaxKept || now.Sub(s.CreatedAt) > Retention {
+			_ = os.RemoveAll(s.dir)
+		}
+	}
+	return nil
+}
+
+// RestoreOptions configures Restore
+type RestoreOptions struct {
+	DryRun       bool
+	ProgressFunc func(current, total int, msg string)
+}
+
+// Restore puts every path in s back the way it was when it was saved,
+// newest first. A symlink or file in the way is replaced, after being saved
+// in an "undo" snapshot of its own so the restore can be undone in turn; a
+// directory in the way is never replaced. The snapshot is removed once all
+// its paths are back; paths that could not be restored stay in it for
+// another try.
+func Restore(s *Snapshot, opts RestoreOptions) error {
+	report := func(current int, msg string) {
+		if opts.ProgressFunc != nil {
+			opts.ProgressFunc(current, len(s.Entries), msg)
+		}
+	}
+
+	if !opts.DryRun {
+		// Not pruning, which could remove s itself
+		defer begin("undo", false)()
+	}
+
+	var failed []Entry
+	var errs []error
+	for i := len(s.Entries) - 1; i >= 0; i-- {
+		e := s.Entries[i]
+		current := len(s.Entries) - i
+		if opts.DryRun {
+			report(current, fmt.Sprintf("Would restore %s (%s)", e.Path, e.Kind))
+			continue
+		}
+		if err := restoreEntry(s, e); err != nil {
+			failed = append([]Entry{e}, failed...)
+			errs = append(errs, fmt.Errorf("%s: %w", e.Path, err))
+			report(current, fmt.Sprintf("✗ %s: %v", e.Path, err))
+			continue
+		}
+		report(current, fmt.Sprintf("✓ Restored %s", e.Path))
+	}
+	if opts.DryRun {
+		return nil
+	}
+
+	if len(failed) > 0 {
+		s.Entries = failed
+		if err := writeManifest(s); err != nil {
+			errs = append(errs, err)
+		}
+		return errors.Join(errs...)
+	}
+	if err := os.RemoveAll(s.dir); err != nil {
+		return fmt.Errorf("failed to remove snapshot: %w", err)
+	}
+	return nil
+}
+
+// restoreEntry puts one path back
+func restoreEntry(s *Snapshot, e Entry) error {
+	if info, err := os.Lstat(e.Path); err == nil {
+		if info.IsDir() {
+			return fmt.Errorf("a directory is in the way")
+		}
+		if err := Save(e.Path); err != nil {
+			return err
+		}
+		if err := os.Remove(e.Path); err != nil {
+			return err
+		}
+	}
+	if err := os.MkdirAll(filepath.Dir(e.Path), 0755); err != nil {
+		return err
+	}
+
+	from := filepath.Join(s.dir, e.Saved)
+	switch e.Kind {
+	case KindSymlink:
+		return os.Symlink(e.Link, e.Path)
+	case KindFile:
+		if os.Rename(from, e.Path) != nil {
+			if err := fsutil.CopyFile(from, e.Path, e.Mode); err != nil {
+				return err
+			}
+		}
+	case KindDir:
+		if os.Rename(from, e.Path) != nil {
+			if err := linkTree(from, e.Path); err != nil {
+				return err
+			}
+		}
+	default:
+		return fmt.Errorf("unknown kind %q", e.Kind)
+	}
+	return os.Chmod(e.Path, e.Mode)
+}
+
+// linkTree recreates the directory src at dst, hardlinking its files, or
+// copying them where hardlinks can't be made
+func linkTree(src, dst string) error {
+	return filepath.WalkDir(src, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dst, rel)
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+
+		switch {
+		case d.IsDir():
+			return os.MkdirAll(target, info.Mode().Perm()|0700)
+		case info.Mode()&os.ModeSymlink != 0:
+			link, err := os.Readlink(path)
+			if err != nil {
+				return err
+			}
+			return os.Symlink(link, target)
+		case info.Mode().IsRegular():
+			if os.Link(path, target) == nil {
+				return nil
+			}
+			return fsutil.CopyFile(path, target, info.Mode().Perm())
+		}
+		return nil // Sockets, devices and the like are not saved
+	})
+}
+
+// Describe returns a one-line description of s, e.g.
+// "sync, 2024-05-01 14:03, 3 path(s)"
+func (s *Snapshot) Describe() string {
+	return fmt.Sprintf("%s, %s, %d path(s)", s.Operation, s.CreatedAt.Format("2006-01-02 15:04"), len(s.Entries))
+}
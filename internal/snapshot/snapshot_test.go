@@ -0,0 +1,189 @@
+package snapshot
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// setupHome points the state directory at a temporary home
+func setupHome(t *testing.T) string {
+	t.Helper()
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	return home
+}
+
+func TestSave_NoSnapshotActive(t *testing.T) {
+	home := setupHome(t)
+	file := filepath.Join(home, "file")
+	if err := os.WriteFile(file, []byte("x"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := Save(file); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	if _, err := Get(""); !errors.Is(err, ErrNoSnapshot) {
+		t.Errorf("Get = %v, want ErrNoSnapshot", err)
+	}
+}
+
+func TestSaveAndRestore(t *testing.T) {
+	home := setupHome(t)
+	file := filepath.Join(home, ".gitconfig")
+	link := filepath.Join(home, ".bashrc")
+	dir := filepath.Join(home, ".config", "nvim")
+	if err := os.WriteFile(file, []byte("rendered"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink("dotfiles/bash/.bashrc", link); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "init.lua"), []byte("vim"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	end := Begin("sync")
+	for _, path := range []string{file, link, dir, link, filepath.Join(home, "missing")} {
+		if err := Save(path); err != nil {
+			t.Fatalf("Save(%s): %v", path, err)
+		}
+	}
+	end()
+
+	// The operation goes ahead
+	if err := os.WriteFile(file, []byte("changed"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Remove(link); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.RemoveAll(dir); err != nil {
+		t.Fatal(err)
+	}
+
+	s, err := Get("")
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if s.Operation != "sync" || len(s.Entries) != 3 {
+		t.Fatalf("snapshot = %s with %d entries, want sync with 3", s.Operation, len(s.Entries))
+	}
+
+	if err := Restore(s, RestoreOptions{}); err != nil {
+		t.Fatalf("Restore: %v", err)
+	}
+	if data, _ := os.ReadFile(file); string(data) != "rendered" {
+		t.Errorf("file = %q, want %q", data, "rendered")
+	}
+	if target, _ := os.Readlink(link); target != "dotfiles/bash/.bashrc" {
+		t.Errorf("link points at %q", target)
+	}
+	if data, _ := os.ReadFile(filepath.Join(dir, "init.lua")); string(data) != "vim" {
+		t.Errorf("dir file = %q, want %q", data, "vim")
+	}
+
+	// The restored snapshot is gone; the changed file is kept by the undo
+	s, err = Get("")
+	if err != nil {
+		t.Fatalf("Get after restore: %v", err)
+	}
+	if s.Operation != "undo" || len(s.Entries) != 1 || s.Entries[0].Path != file {
+		t.Errorf("undo snapshot = %s %v, want undo of %s", s.Operation, s.Paths(), file)
+	}
+}
+
+func TestRestore_DirectoryInTheWay(t *testing.T) {
+	home := setupHome(t)
+	link := filepath.Join(home, ".bashrc")
+	if err := os.Symlink("somewhere", link); err != nil {
+		t.Fatal(err)
+	}
+
+	end := Begin("sync")
+	if err := Save(link); err != nil {
+		t.Fatal(err)
+	}
+	end()
+	if err := os.Remove(link); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Mkdir(link, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	s, err := Get("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := Restore(s, RestoreOptions{}); err == nil {
+		t.Fatal("expected an error restoring over a directory")
+	}
+	if _, err := Get(s.ID); err != nil {
+		t.Errorf("the snapshot should be kept for another try: %v", err)
+	}
+}
+
+func TestBegin_Nested(t *testing.T) {
+	home := setupHome(t)
+	file := filepath.Join(home, "file")
+	if err := os.WriteFile(file, []byte("x"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	endOuter := Begin("install")
+	endInner := Begin("sync")
+	endInner()
+	if err := Save(file); err != nil {
+		t.Fatal(err)
+	}
+	endOuter()
+
+	snaps, err := List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(snaps) != 1 || snaps[0].Operation != "install" {
+		t.Errorf("snapshots = %v, want one install snapshot", snaps)
+	}
+}
+
+func TestPrune(t *testing.T) {
+	home := setupHome(t)
+	file := filepath.Join(home, "file")
+	if err := os.WriteFile(file, []byte("x"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	end := Begin("sync")
+	if err := Save(file); err != nil {
+		t.Fatal(err)
+	}
+	end()
+
+	mu.Lock()
+	err := pruneLocked(time.Now().Add(Retention - time.Hour))
+	mu.Unlock()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if snaps, _ := List(); len(snaps) != 1 {
+		t.Fatalf("a fresh snapshot was pruned")
+	}
+
+	mu.Lock()
+	err = pruneLocked(time.Now().Add(Retention + time.Hour))
+	mu.Unlock()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if snaps, _ := List(); len(snaps) != 0 {
+		t.Errorf("an expired snapshot was kept")
+	}
+}
@@ -0,0 +1,198 @@
+package state
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+
+	"github.com/nvandessel/go4dot/internal/workspace"
+)
+
+const (
+	// EncryptedFileName is the name of the state file once encrypted with
+	// age. While it exists, the state is only ever written encrypted.
+	EncryptedFileName = StateFileName + ".age"
+	// IdentityFileName is the default age identity, in StateDir
+	IdentityFileName = "age-identity.txt"
+	// IdentityEnv overrides the path of the age identity
+	IdentityEnv = "GO4DOT_AGE_IDENTITY"
+)
+
+// ErrNotEncrypted is returned by Decrypt when the state is stored in plain
+// text already
+var ErrNotEncrypted = errors.New("state is not encrypted")
+
+// RunAge runs an age command with stdin and returns its output. It can be
+// replaced in tests.
+var RunAge = func(stdin []byte, name string, args ...string) ([]byte, error) {
+	cmd := exec.Command(name, args...)
+	cmd.Stdin = bytes.NewReader(stdin)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		if msg := bytes.TrimSpace(stderr.Bytes()); len(msg) > 0 {
+			return nil, fmt.Errorf("%s: %w: %s", name, err, msg)
+		}
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+	return out, nil
+}
+
+// GetEncryptedStatePath returns the full path to the encrypted state file
+func GetEncryptedStatePath() (string, error) {
+	stateDir, err := GetStateDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(stateDir, EncryptedFileName), nil
+}
+
+// IdentityPath returns the age identity the state is encrypted to:
+// $GO4DOT_AGE_IDENTITY, or age-identity.txt next to the user's state. All
+// workspaces share it.
+func IdentityPath() (string, error) {
+	if path := os.Getenv(IdentityEnv); path != "" {
+		return path, nil
+	}
+	home, err := workspace.UserHome()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, StateDir, IdentityFileName), nil
+}
+
+// IsEncrypted reports whether the state is stored encrypted
+func IsEncrypted() bool {
+	path, err := GetEncryptedStatePath()
+	if err != nil {
+		return false
+	}
+	_, err = os.Stat(path)
+	return err == nil
+}
+
+// Encrypt moves the plain-text state to an age-encrypted file, creating the
+// identity with age-keygen if it doesn't exist yet. It reports whether an
+// identity was created, which the user must back up.
+func Encrypt() (created bool, err error) {
+	if IsEncrypted() {
+		return false, fmt.Errorf("state is already encrypted")
+	}
+
+	identity, err := IdentityPath()
+	if err != nil {
+		return false, err
+	}
+	if _, err := os.Stat(identity); os.IsNotExist(err) {
+		if err := os.MkdirAll(filepath.Dir(identity), 0700); err != nil {
+			return false, fmt.Errorf("failed to create identity directory: %w", err)
+		}
+		if _, err := RunAge(nil, "age-keygen", "-o", identity); err != nil {
+			return false, fmt.Errorf("failed to create age identity: %w", err)
+		}
+		created = true
+	}
+
+	st, err := Load()
+	if err != nil {
+		return created, err
+	}
+	if st == nil {
+		st = New()
+	}
+
+	data, err := encryptState(st)
+	if err != nil {
+		return created, err
+	}
+	encPath, err := GetEncryptedStatePath()
+	if err != nil {
+		return created, err
+	}
+	if err := os.MkdirAll(filepath.Dir(encPath), 0700); err != nil {
+		return created, fmt.Errorf("failed to create state directory: %w", err)
+	}
+	if err := os.WriteFile(encPath, data, 0600); err != nil {
+		return created, fmt.Errorf("failed to write encrypted state: %w", err)
+	}
+
+	plainPath, err := GetStatePath()
+	if err != nil {
+		return created, err
+	}
+	if err := os.Remove(plainPath); err != nil && !os.IsNotExist(err) {
+		return created, fmt.Errorf("failed to remove plain-text state: %w", err)
+	}
+	return created, nil
+}
+
+// Decrypt moves the encrypted state back to a plain-text file
+func Decrypt() error {
+	if !IsEncrypted() {
+		return ErrNotEncrypted
+	}
+
+	st, err := Load()
+	if err != nil {
+		return err
+	}
+
+	encPath, err := GetEncryptedStatePath()
+	if err != nil {
+		return err
+	}
+	// Save writes encrypted while the encrypted file exists, so write the
+	// plain file first and remove the encrypted one last
+	data, err := marshalState(st)
+	if err != nil {
+		return err
+	}
+	plainPath, err := GetStatePath()
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(plainPath, data, 0600); err != nil {
+		return fmt.Errorf("failed to write state file: %w", err)
+	}
+	if err := os.Remove(encPath); err != nil {
+		return fmt.Errorf("failed to remove encrypted state: %w", err)
+	}
+	return nil
+}
+
+// encryptState marshals s and encrypts it to the identity's recipient
+func encryptState(s *State) ([]byte, error) {
+	data, err := marshalState(s)
+	if err != nil {
+		return nil, err
+	}
+	identity, err := IdentityPath()
+	if err != nil {
+		return nil, err
+	}
+	out, err := RunAge(data, "age", "--encrypt", "--armor", "-i", identity)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encrypt state: %w", err)
+	}
+	return out, nil
+}
+
+// decryptFile decrypts the state file at path with the identity
+func decryptFile(path string) ([]byte, error) {
+	identity, err := IdentityPath()
+	if err != nil {
+		return nil, err
+	}
+	if _, err := os.Stat(identity); err != nil {
+		return nil, fmt.Errorf("state is encrypted but the age identity %s is missing (set %s to use another)", identity, IdentityEnv)
+	}
+	out, err := RunAge(nil, "age", "--decrypt", "-i", identity, path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt state: %w", err)
+	}
+	return out, nil
+}
@@ -0,0 +1,123 @@
+package state
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const fakeArmor = "-----BEGIN AGE ENCRYPTED FILE-----\n"
+
+// fakeAge replaces the age commands with a reversible stand-in for the
+// duration of the test
+func fakeAge(t *testing.T) {
+	t.Helper()
+	orig := RunAge
+	t.Cleanup(func() { RunAge = orig })
+
+	RunAge = func(stdin []byte, name string, args ...string) ([]byte, error) {
+		switch {
+		case name == "age-keygen":
+			return nil, os.WriteFile(args[1], []byte("AGE-SECRET-KEY-1TEST\n"), 0600)
+		case args[0] == "--encrypt":
+			return append([]byte(fakeArmor), stdin...), nil
+		case args[0] == "--decrypt":
+			data, err := os.ReadFile(args[len(args)-1])
+			if err != nil {
+				return nil, err
+			}
+			if !bytes.HasPrefix(data, []byte(fakeArmor)) {
+				return nil, errors.New("not an age file")
+			}
+			return bytes.TrimPrefix(data, []byte(fakeArmor)), nil
+		}
+		return nil, errors.New("unexpected command " + name)
+	}
+}
+
+func TestEncryptDecrypt(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv(IdentityEnv, "")
+	fakeAge(t)
+
+	st := New()
+	st.SetMachineConfig("git", "/home/me/.gitconfig.local", true, false)
+	if err := st.Save(); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	created, err := Encrypt()
+	if err != nil {
+		t.Fatalf("Encrypt failed: %v", err)
+	}
+	if !created {
+		t.Error("expected an identity to be created")
+	}
+	if _, err := os.Stat(filepath.Join(home, StateDir, IdentityFileName)); err != nil {
+		t.Errorf("expected the identity file: %v", err)
+	}
+	if !IsEncrypted() || !Exists() {
+		t.Fatal("expected the state to be encrypted")
+	}
+	if _, err := os.Stat(filepath.Join(home, StateDir, StateFileName)); !os.IsNotExist(err) {
+		t.Error("expected the plain-text state to be removed")
+	}
+	if _, err := Encrypt(); err == nil {
+		t.Error("expected encrypting twice to fail")
+	}
+
+	// Load and Save go through age transparently
+	loaded, err := Load()
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if !loaded.MachineConfig["git"].HasGPG {
+		t.Errorf("unexpected machine config after decrypting: %+v", loaded.MachineConfig)
+	}
+	loaded.AddConfig("vim", "vim", true)
+	if err := loaded.Save(); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(home, StateDir, StateFileName)); !os.IsNotExist(err) {
+		t.Error("expected Save not to write plain text while encrypted")
+	}
+
+	if err := Decrypt(); err != nil {
+		t.Fatalf("Decrypt failed: %v", err)
+	}
+	if IsEncrypted() {
+		t.Error("expected the encrypted state to be removed")
+	}
+	loaded, err = Load()
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if !loaded.HasConfig("vim") {
+		t.Error("expected changes saved while encrypted to survive decryption")
+	}
+	if err := Decrypt(); !errors.Is(err, ErrNotEncrypted) {
+		t.Errorf("Decrypt() = %v, want ErrNotEncrypted", err)
+	}
+}
+
+func TestLoad_MissingIdentity(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv(IdentityEnv, filepath.Join(home, "missing.txt"))
+	fakeAge(t)
+
+	dir := filepath.Join(home, StateDir)
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, EncryptedFileName), []byte(fakeArmor+"{}"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := Load(); err == nil {
+		t.Error("expected Load to fail without the identity")
+	}
+}
@@ -0,0 +1,114 @@
+package state
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const (
+	// OperationHistoryFileName holds past install, sync and link runs, in the
+	// state directory
+	OperationHistoryFileName = "operation-history.json"
+
+	// MaxOperationHistory is the number of runs kept; older ones are dropped
+	MaxOperationHistory = 50
+)
+
+// StepTiming is the wall-clock time one step of an operation took
+type StepTiming struct {
+	Name     string        `json:"name"`
+	Duration time.Duration `json:"duration"`
+}
+
+// OperationRecord is one finished operation and where its time went
+type OperationRecord struct {
+	Operation string        `json:"operation"`
+	StartedAt time.Time     `json:"started_at"`
+	Duration  time.Duration `json:"duration"`
+	Success   bool          `json:"success"`
+	Steps     []StepTiming  `json:"steps,omitempty"`
+}
+
+// FormatTimings renders step timings as "deps 42s, externals 1m03s, stow 2s"
+func FormatTimings(timings []StepTiming) string {
+	parts := make([]string, 0, len(timings))
+	for _, t := range timings {
+		parts = append(parts, fmt.Sprintf("%s %s", t.Name, FormatDuration(t.Duration)))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// FormatDuration renders d compactly: 350ms, 42s or 1m03s
+func FormatDuration(d time.Duration) string {
+	switch {
+	case d < time.Second:
+		return fmt.Sprintf("%dms", d.Milliseconds())
+	case d < time.Minute:
+		return fmt.Sprintf("%ds", int(d.Round(time.Second).Seconds()))
+	}
+	d = d.Round(time.Second)
+	return fmt.Sprintf("%dm%02ds", int(d.Minutes()), int(d.Seconds())%60)
+}
+
+// OperationHistoryPath returns the path of the operation history file
+func OperationHistoryPath() (string, error) {
+	dir, err := GetStateDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, OperationHistoryFileName), nil
+}
+
+// LoadOperationHistory returns the recorded operations, oldest first. No
+// history yet is not an error.
+func LoadOperationHistory() ([]OperationRecord, error) {
+	path, err := OperationHistoryPath()
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read operation history: %w", err)
+	}
+
+	var records []OperationRecord
+	if err := json.Unmarshal(data, &records); err != nil {
+		return nil, fmt.Errorf("failed to parse operation history %s: %w", path, err)
+	}
+	return records, nil
+}
+
+// RecordOperation adds a finished operation to the history
+func RecordOperation(rec OperationRecord) error {
+	records, err := LoadOperationHistory()
+	if err != nil {
+		return err
+	}
+	records = append(records, rec)
+	if len(records) > MaxOperationHistory {
+		records = records[len(records)-MaxOperationHistory:]
+	}
+
+	path, err := OperationHistoryPath()
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(records, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal operation history: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create state directory: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		return fmt.Errorf("failed to write operation history: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,49 @@
+package state
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFormatTimings(t *testing.T) {
+	got := FormatTimings([]StepTiming{
+		{Name: "deps", Duration: 42 * time.Second},
+		{Name: "externals", Duration: 63 * time.Second},
+		{Name: "stow", Duration: 1600 * time.Millisecond},
+		{Name: "machine", Duration: 350 * time.Millisecond},
+	})
+	want := "deps 42s, externals 1m03s, stow 2s, machine 350ms"
+	if got != want {
+		t.Errorf("FormatTimings() = %q, want %q", got, want)
+	}
+}
+
+func TestRecordOperation(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	for i := 0; i < MaxOperationHistory+2; i++ {
+		rec := OperationRecord{
+			Operation: "install",
+			StartedAt: time.Unix(int64(i), 0),
+			Success:   true,
+			Steps:     []StepTiming{{Name: "stow", Duration: time.Second}},
+		}
+		if err := RecordOperation(rec); err != nil {
+			t.Fatalf("RecordOperation() error = %v", err)
+		}
+	}
+
+	records, err := LoadOperationHistory()
+	if err != nil {
+		t.Fatalf("LoadOperationHistory() error = %v", err)
+	}
+	if len(records) != MaxOperationHistory {
+		t.Fatalf("kept %d records, want %d", len(records), MaxOperationHistory)
+	}
+	if records[0].StartedAt.Unix() != 2 {
+		t.Errorf("oldest record started at %d, want the oldest ones dropped", records[0].StartedAt.Unix())
+	}
+	if got := records[len(records)-1].Steps; len(got) != 1 || got[0].Duration != time.Second {
+		t.Errorf("steps = %+v, want the recorded timing", got)
+	}
+}
@@ -0,0 +1,80 @@
+package state
+
+import (
+	"fmt"
+	"os"
+	"os/user"
+	"strconv"
+)
+
+// Owner is the user a state file belongs to. State is kept per user, so
+// two users linking from one shared dotfiles checkout never read or write
+// each other's; the owner is what catches it when they would, e.g. under
+// sudo with HOME left pointing at another user's home.
+type Owner struct {
+	UID  int    `json:"uid"`
+	User string `json:"user,omitempty"`
+	Repo string `json:"repo,omitempty"` // Dotfiles repo the owner linked from, when the state was saved
+}
+
+// CurrentOwner returns the user go4dot runs as
+func CurrentOwner() Owner {
+	uid := os.Geteuid()
+	return Owner{UID: uid, User: userName(uid)}
+}
+
+// String describes the owner as "alice (uid 1000)"
+func (o Owner) String() string {
+	if o.User == "" {
+		return fmt.Sprintf("uid %d", o.UID)
+	}
+	return fmt.Sprintf("%s (uid %d)", o.User, o.UID)
+}
+
+// OwnerOf returns the user owning path, and false when it does not exist
+// or ownership is not known on this platform
+func OwnerOf(path string) (Owner, bool) {
+	uid, ok := fileUID(path)
+	if !ok {
+		return Owner{}, false
+	}
+	return Owner{UID: uid, User: userName(uid)}, true
+}
+
+// userName returns the login name of uid, or "" when it has none
+func userName(uid int) string {
+	u, err := user.LookupId(strconv.Itoa(uid))
+	if err != nil {
+		return ""
+	}
+	return u.Username
+}
+
+// ForeignStateError reports a state file that belongs to another user
+type ForeignStateError struct {
+	Path  string // The state file
+	Owner Owner
+}
+
+func (e *ForeignStateError) Error() string {
+	owner := e.Owner.String()
+	if e.Owner.Repo != "" {
+		owner += " linking from " + e.Owner.Repo
+	}
+	return fmt.Sprintf("go4dot state at %s belongs to %s, not %s; run go4dot as that user, or with HOME set to your own home",
+		e.Path, owner, CurrentOwner())
+}
+
+// checkOwner returns a *ForeignStateError when the state file at path, or
+// the state read from it, belongs to another user. Root-owned files are
+// left to the permission checks: they are not another user's dotfiles.
+func checkOwner(s *State, path string) error {
+	current := os.Geteuid()
+	if s != nil && s.Owner != nil && s.Owner.UID != current {
+		return &ForeignStateError{Path: path, Owner: *s.Owner}
+	}
+	if o, ok := OwnerOf(path); ok && o.UID != current && o.UID != 0 {
+		return &ForeignStateError{Path: path, Owner: o}
+	}
+	return nil
+}
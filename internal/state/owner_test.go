@@ -0,0 +1,73 @@
+package state
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSave_RecordsOwner(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	s := New()
+	s.DotfilesPath = "/srv/dotfiles"
+	if err := s.Save(); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	st, err := Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if st.Owner == nil || st.Owner.UID != os.Geteuid() || st.Owner.Repo != "/srv/dotfiles" {
+		t.Errorf("Owner = %+v, want uid %d linking from /srv/dotfiles", st.Owner, os.Geteuid())
+	}
+}
+
+func TestLoad_ForeignOwner(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	s := New()
+	s.Owner = &Owner{UID: os.Geteuid() + 1, User: "alice", Repo: "/home/alice/dotfiles"}
+	data, err := marshalState(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	path, _ := GetStatePath()
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = Load()
+	var foreign *ForeignStateError
+	if !errors.As(err, &foreign) || foreign.Owner.User != "alice" {
+		t.Errorf("Load() error = %v, want alice's state refused", err)
+	}
+	if err != nil && !strings.Contains(err.Error(), "/home/alice/dotfiles") {
+		t.Errorf("Load() error = %v, want the repo alice linked from", err)
+	}
+}
+
+func TestSave_ForeignStateFile(t *testing.T) {
+	if os.Geteuid() != 0 {
+		t.Skip("changing a file's owner needs root")
+	}
+	t.Setenv("HOME", t.TempDir())
+
+	if err := New().Save(); err != nil {
+		t.Fatal(err)
+	}
+	path, _ := GetStatePath()
+	if err := os.Chown(path, 4242, 4242); err != nil {
+		t.Fatal(err)
+	}
+
+	var foreign *ForeignStateError
+	if err := New().Save(); !errors.As(err, &foreign) || foreign.Owner.UID != 4242 {
+		t.Errorf("Save() error = %v, want uid 4242's state refused", err)
+	}
+}
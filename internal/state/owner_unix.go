@@ -0,0 +1,21 @@
+//go:build !windows
+
+package state
+
+import (
+	"os"
+	"syscall"
+)
+
+// fileUID returns the uid owning path, without following a final symlink
+func fileUID(path string) (int, bool) {
+	info, err := os.Lstat(path)
+	if err != nil {
+		return 0, false
+	}
+	st, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return 0, false
+	}
+	return int(st.Uid), true
+}
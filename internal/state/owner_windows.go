@@ -0,0 +1,8 @@
+//go:build windows
+
+package state
+
+// fileUID is not known on Windows, where home directories are per user
+func fileUID(path string) (int, bool) {
+	return 0, false
+}
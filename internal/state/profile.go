@@ -0,0 +1,50 @@
+package state
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ProfileFileName holds the profile this machine uses, under the state
+// directory. It is kept apart from the state file, which may be encrypted,
+// so every command can read it cheaply.
+const ProfileFileName = "profile"
+
+// LoadProfile returns the profile saved for this machine, or "" when none
+// is saved
+func LoadProfile() string {
+	dir, err := GetStateDir()
+	if err != nil {
+		return ""
+	}
+	data, err := os.ReadFile(filepath.Join(dir, ProfileFileName))
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(data))
+}
+
+// SaveProfile saves the profile this machine uses; "" removes it so every
+// config is used again
+func SaveProfile(name string) error {
+	dir, err := GetStateDir()
+	if err != nil {
+		return err
+	}
+	path := filepath.Join(dir, ProfileFileName)
+	if name == "" {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to remove profile: %w", err)
+		}
+		return nil
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create state directory: %w", err)
+	}
+	if err := os.WriteFile(path, []byte(name+"\n"), 0644); err != nil {
+		return fmt.Errorf("failed to save profile: %w", err)
+	}
+	return nil
+}
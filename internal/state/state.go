@@ -6,6 +6,8 @@ import (
 	"os"
 	"path/filepath"
 	"time"
+
+	"github.com/nvandessel/go4dot/internal/workspace"
 )
 
 const (
@@ -13,6 +15,8 @@ const (
 	StateDir = ".config/go4dot"
 	// StateFileName is the name of the state file
 	StateFileName = "state.json"
+	// WorkspacesDir holds the state of each workspace, under StateDir
+	WorkspacesDir = "workspaces"
 	// StateVersion is the current state file format version
 	StateVersion = "1.0"
 )
@@ -28,6 +32,23 @@ type State struct {
 	MachineConfig map[string]MachineState  `json:"machine_config"`
 	ExternalDeps  map[string]ExternalState `json:"external_deps"`
 	SymlinkCounts map[string]int           `json:"symlink_counts,omitempty"` // File count per config for quick drift detection
+
+	// Fingerprint of each config's files when it was last linked, so a sync
+	// can leave configs that did not change alone
+	Fingerprints map[string]string `json:"fingerprints,omitempty"`
+
+	// Symlinks from the links section that go4dot created, by where they
+	// are to what they point to, so they can be removed again
+	Links map[string]string `json:"links,omitempty"`
+
+	// Revision the dotfiles repo had checked out when configs were last
+	// linked, as its VCS's Head returns it, so changes pulled since can be
+	// shown
+	Revision string `json:"revision,omitempty"`
+
+	// User the state belongs to and the repo they linked from, set on save
+	// (see Owner)
+	Owner *Owner `json:"owner,omitempty"`
 }
 
 // PlatformState stores detected platform information
@@ -74,31 +95,44 @@ func New() *State {
 
 // GetStatePath returns the full path to the state file
 func GetStatePath() (string, error) {
-	home, err := os.UserHomeDir()
+	stateDir, err := GetStateDir()
 	if err != nil {
-		return "", fmt.Errorf("failed to get home directory: %w", err)
+		return "", err
 	}
-	return filepath.Join(home, StateDir, StateFileName), nil
+	return filepath.Join(stateDir, StateFileName), nil
 }
 
-// GetStateDir returns the state directory path
+// GetStateDir returns the state directory path. Each workspace keeps its
+// own state in the user's home, apart from the default one.
 func GetStateDir() (string, error) {
-	home, err := os.UserHomeDir()
+	home, err := workspace.UserHome()
 	if err != nil {
-		return "", fmt.Errorf("failed to get home directory: %w", err)
+		return "", err
+	}
+	if name := workspace.Active(); name != "" {
+		return filepath.Join(home, StateDir, WorkspacesDir, name), nil
 	}
 	return filepath.Join(home, StateDir), nil
 }
 
-// Load reads the state from disk
+// Load reads the state from disk, decrypting it if it is stored encrypted
 func Load() (*State, error) {
 	statePath, err := GetStatePath()
 	if err != nil {
 		return nil, err
 	}
 
-	data, err := os.ReadFile(statePath)
-	if err != nil {
+	var data []byte
+	if IsEncrypted() {
+		encPath, err := GetEncryptedStatePath()
+		if err != nil {
+			return nil, err
+		}
+		statePath = encPath
+		if data, err = decryptFile(encPath); err != nil {
+			return nil, err
+		}
+	} else if data, err = os.ReadFile(statePath); err != nil {
 		if os.IsNotExist(err) {
 			return nil, nil // No state file exists yet
 		}
@@ -109,16 +143,24 @@ func Load() (*State, error) {
 	if err := json.Unmarshal(data, &state); err != nil {
 		return nil, fmt.Errorf("failed to parse state file: %w", err)
 	}
+	if err := checkOwner(&state, statePath); err != nil {
+		return nil, err
+	}
 
 	return &state, nil
 }
 
-// Save writes the state to disk
+// Save writes the state to disk. Once the state is encrypted, it is only
+// ever written encrypted. It refuses, with a *ForeignStateError, to write
+// over another user's state.
 func (s *State) Save() error {
 	stateDir, err := GetStateDir()
 	if err != nil {
 		return err
 	}
+	if err := checkOwner(nil, stateDir); err != nil {
+		return err
+	}
 
 	// Ensure directory exists
 	if err := os.MkdirAll(stateDir, 0700); err != nil {
@@ -132,10 +174,34 @@ func (s *State) Save() error {
 
 	// Update last update time
 	s.LastUpdate = time.Now()
+	owner := CurrentOwner()
+	owner.Repo = s.DotfilesPath
+	s.Owner = &owner
+
+	if IsEncrypted() {
+		encPath, err := GetEncryptedStatePath()
+		if err != nil {
+			return err
+		}
+		if err := checkOwner(nil, encPath); err != nil {
+			return err
+		}
+		data, err := encryptState(s)
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(encPath, data, 0600); err != nil {
+			return fmt.Errorf("failed to write encrypted state: %w", err)
+		}
+		return nil
+	}
 
-	data, err := json.MarshalIndent(s, "", "  ")
+	if err := checkOwner(nil, statePath); err != nil {
+		return err
+	}
+	data, err := marshalState(s)
 	if err != nil {
-		return fmt.Errorf("failed to marshal state: %w", err)
+		return err
 	}
 
 	if err := os.WriteFile(statePath, data, 0600); err != nil {
@@ -145,15 +211,30 @@ func (s *State) Save() error {
 	return nil
 }
 
-// Delete removes the state file
+// marshalState encodes the state as written to the state file
+func marshalState(s *State) ([]byte, error) {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal state: %w", err)
+	}
+	return data, nil
+}
+
+// Delete removes the state file, plain or encrypted
 func Delete() error {
 	statePath, err := GetStatePath()
 	if err != nil {
 		return err
 	}
+	encPath, err := GetEncryptedStatePath()
+	if err != nil {
+		return err
+	}
 
-	if err := os.Remove(statePath); err != nil && !os.IsNotExist(err) {
-		return fmt.Errorf("failed to delete state file: %w", err)
+	for _, path := range []string{statePath, encPath} {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to delete state file: %w", err)
+		}
 	}
 
 	return nil
@@ -167,7 +248,7 @@ func Exists() bool {
 	}
 
 	_, err = os.Stat(statePath)
-	return err == nil
+	return err == nil || IsEncrypted()
 }
 
 // AddConfig adds a config to the installed list
@@ -277,6 +358,40 @@ func (s *State) RemoveSymlinkCount(configName string) {
 	}
 }
 
+// SetFingerprint records the fingerprint of a config's files as linked
+func (s *State) SetFingerprint(configName, fingerprint string) {
+	if s.Fingerprints == nil {
+		s.Fingerprints = make(map[string]string)
+	}
+	s.Fingerprints[configName] = fingerprint
+}
+
+// GetFingerprint returns the fingerprint of a config's files when it was
+// last linked
+func (s *State) GetFingerprint(configName string) (string, bool) {
+	fingerprint, ok := s.Fingerprints[configName]
+	return fingerprint, ok
+}
+
+// RemoveFingerprint forgets a config's fingerprint, so the next sync
+// relinks it
+func (s *State) RemoveFingerprint(configName string) {
+	delete(s.Fingerprints, configName)
+}
+
+// SetLink records a symlink from the links section that was created
+func (s *State) SetLink(target, source string) {
+	if s.Links == nil {
+		s.Links = make(map[string]string)
+	}
+	s.Links[target] = source
+}
+
+// RemoveLink forgets a symlink from the links section
+func (s *State) RemoveLink(target string) {
+	delete(s.Links, target)
+}
+
 // AdoptConfigs adds multiple configs to state at once (for adoption)
 func (s *State) AdoptConfigs(configs []ConfigState) {
 	for _, cfg := range configs {
@@ -4,6 +4,8 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+
+	"github.com/nvandessel/go4dot/internal/workspace"
 )
 
 func TestNew(t *testing.T) {
@@ -308,3 +310,39 @@ func TestExists(t *testing.T) {
 		t.Error("Exists() should be false after Delete()")
 	}
 }
+
+func TestGetStatePath_Workspace(t *testing.T) {
+	userHome := t.TempDir()
+	t.Setenv("HOME", filepath.Join(userHome, "work-env"))
+	t.Setenv(workspace.EnvUserHome, userHome)
+	t.Setenv(workspace.EnvName, "work")
+
+	path, err := GetStatePath()
+	if err != nil {
+		t.Fatalf("GetStatePath() failed: %v", err)
+	}
+	want := filepath.Join(userHome, StateDir, WorkspacesDir, "work", StateFileName)
+	if path != want {
+		t.Errorf("GetStatePath() = %q, want %q", path, want)
+	}
+}
+
+func TestSaveProfile(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	if got := LoadProfile(); got != "" {
+		t.Errorf("LoadProfile() = %q before saving, want \"\"", got)
+	}
+	if err := SaveProfile("work"); err != nil {
+		t.Fatalf("SaveProfile() error = %v", err)
+	}
+	if got := LoadProfile(); got != "work" {
+		t.Errorf("LoadProfile() = %q, want work", got)
+	}
+	if err := SaveProfile(""); err != nil {
+		t.Fatalf("SaveProfile(\"\") error = %v", err)
+	}
+	if got := LoadProfile(); got != "" {
+		t.Errorf("LoadProfile() = %q after clearing, want \"\"", got)
+	}
+}
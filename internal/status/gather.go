@@ -7,6 +7,7 @@ import (
 
 	"github.com/nvandessel/go4dot/internal/config"
 	"github.com/nvandessel/go4dot/internal/deps"
+	"github.com/nvandessel/go4dot/internal/machine"
 	"github.com/nvandessel/go4dot/internal/platform"
 	"github.com/nvandessel/go4dot/internal/state"
 	"github.com/nvandessel/go4dot/internal/stow"
@@ -31,6 +32,7 @@ type ConfigStatus struct {
 	Conflicts    int        `json:"conflicts,omitempty"`
 	ContentDrift int        `json:"content_drift,omitempty"`
 	Orphans      int        `json:"orphans,omitempty"`
+	LinkErrors   []string   `json:"link_errors,omitempty"` // Symlink loops and chains too deep to follow
 }
 
 // DependencyStatus holds a summary of dependency checking.
@@ -58,6 +60,7 @@ type Overview struct {
 	ConfigCount  int                `json:"config_count"`
 	Configs      []ConfigStatus     `json:"configs"`
 	Dependencies DependencyStatus   `json:"dependencies"`
+	PendingMachine []string         `json:"pending_machine,omitempty"` // Machine configs not generated yet
 	LastSync     *time.Time         `json:"last_sync,omitempty"`
 	Initialized  bool               `json:"initialized"`
 }
@@ -78,6 +81,8 @@ type Gatherer struct {
 	StateLoader      func() (*state.State, error)
 	DriftChecker     func(cfg *config.Config, dotfilesPath string) (*stow.DriftSummary, error)
 	DepsChecker      func(cfg *config.Config, p *platform.Platform) (*deps.CheckResult, error)
+	MachineChecker   func(cfg *config.Config) []machine.MachineConfigStatus
+	RemoteFetcher    func(repoURL string) ([]byte, string, error) // Fetches a repo's .go4dot.yaml for GatherRemote
 }
 
 // NewGatherer creates a Gatherer with production implementations.
@@ -88,6 +93,8 @@ func NewGatherer() *Gatherer {
 		StateLoader:      state.Load,
 		DriftChecker:     stow.FullDriftCheck,
 		DepsChecker:      deps.Check,
+		MachineChecker:   machine.CheckMachineConfigStatus,
+		RemoteFetcher:    FetchRemoteConfig,
 	}
 }
 
@@ -170,6 +177,7 @@ func (g *Gatherer) Gather(opts GatherOptions) (*Overview, error) {
 			cs.MissingFiles = len(dr.MissingFiles)
 			cs.Conflicts = len(dr.ConflictFiles)
 			cs.ContentDrift = len(dr.ContentDriftFiles)
+			cs.LinkErrors = dr.LinkErrors
 		} else {
 			cs.Status = SyncStatusSynced
 		}
@@ -190,6 +198,15 @@ func (g *Gatherer) Gather(opts GatherOptions) (*Overview, error) {
 		}
 	}
 
+	// Machine configs still to be answered
+	if g.MachineChecker != nil {
+		for _, mc := range g.MachineChecker(cfg) {
+			if mc.Status == "missing" {
+				overview.PendingMachine = append(overview.PendingMachine, mc.ID)
+			}
+		}
+	}
+
 	return overview, nil
 }
 
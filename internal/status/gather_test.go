@@ -7,6 +7,7 @@ import (
 
 	"github.com/nvandessel/go4dot/internal/config"
 	"github.com/nvandessel/go4dot/internal/deps"
+	"github.com/nvandessel/go4dot/internal/machine"
 	"github.com/nvandessel/go4dot/internal/platform"
 	"github.com/nvandessel/go4dot/internal/state"
 	"github.com/nvandessel/go4dot/internal/stow"
@@ -286,6 +287,26 @@ func TestGather_SkipDeps(t *testing.T) {
 	}
 }
 
+func TestGather_PendingMachine(t *testing.T) {
+	p := &platform.Platform{OS: "linux"}
+	cfg := &config.Config{}
+	g := newTestGatherer(p, cfg, "/tmp/.go4dot.yaml", nil, &stow.DriftSummary{}, nil)
+	g.MachineChecker = func(_ *config.Config) []machine.MachineConfigStatus {
+		return []machine.MachineConfigStatus{
+			{ID: "git", Status: "missing"},
+			{ID: "gpg", Status: "configured"},
+		}
+	}
+
+	overview, err := g.Gather(GatherOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(overview.PendingMachine) != 1 || overview.PendingMachine[0] != "git" {
+		t.Errorf("PendingMachine = %v, want [git]", overview.PendingMachine)
+	}
+}
+
 func TestGather_WSLPlatform(t *testing.T) {
 	p := &platform.Platform{
 		OS:             "linux",
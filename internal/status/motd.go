@@ -0,0 +1,130 @@
+package status
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/nvandessel/go4dot/internal/state"
+	"github.com/nvandessel/go4dot/internal/ui"
+)
+
+// MOTDCacheFileName records in the state directory when g4d motd last
+// printed, so shells started later the same day stay quiet
+const MOTDCacheFileName = "motd.json"
+
+// motdMaxConfigs caps the drifted configs listed by name, keeping the
+// summary to one screen
+const motdMaxConfigs = 5
+
+type motdCache struct {
+	ShownAt time.Time `json:"shown_at"`
+}
+
+// MOTDCachePath returns where the motd cache is kept
+func MOTDCachePath() (string, error) {
+	dir, err := state.GetStateDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, MOTDCacheFileName), nil
+}
+
+// MOTDDue reports whether the motd has not been shown yet on now's day. A
+// missing or unreadable cache counts as never shown.
+func MOTDDue(path string, now time.Time) bool {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return true
+	}
+	var c motdCache
+	if err := json.Unmarshal(data, &c); err != nil {
+		return true
+	}
+	y1, m1, d1 := c.ShownAt.In(now.Location()).Date()
+	y2, m2, d2 := now.Date()
+	return y1 != y2 || m1 != m2 || d1 != d2
+}
+
+// MarkMOTDShown records that the motd was shown at now
+func MarkMOTDShown(path string, now time.Time) error {
+	data, err := json.Marshal(motdCache{ShownAt: now})
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+// RenderMOTD returns the short summary printed on shell start: how many
+// configs drifted, dependencies are missing and machine prompts are
+// pending, with the command fixing each. An overview with nothing to fix
+// gets a single line.
+func RenderMOTD(o *Overview) string {
+	var sb strings.Builder
+	title := lipgloss.NewStyle().Foreground(ui.PrimaryColor).Bold(true).Render("go4dot")
+
+	if !o.Initialized {
+		fmt.Fprintf(&sb, "%s %s\n", title, ui.SubtleStyle.Render("no dotfiles config found, run 'g4d init' or 'g4d install'"))
+		return sb.String()
+	}
+
+	var drifted []string
+	for _, c := range o.Configs {
+		if c.Status == SyncStatusDrifted {
+			drifted = append(drifted, c.Name)
+		}
+	}
+	missing := o.Dependencies.Missing
+
+	if len(drifted) == 0 && missing == 0 && len(o.PendingMachine) == 0 {
+		fmt.Fprintf(&sb, "%s %s\n", title, ui.SuccessStyle.Render(fmt.Sprintf("✓ %d configs in sync", o.ConfigCount)))
+		return sb.String()
+	}
+
+	var counts []string
+	if len(drifted) > 0 {
+		counts = append(counts, ui.WarningStyle.Render(plural(len(drifted), "config drifted", "configs drifted")))
+	}
+	if missing > 0 {
+		counts = append(counts, ui.ErrorStyle.Render(plural(missing, "missing dependency", "missing dependencies")))
+	}
+	if n := len(o.PendingMachine); n > 0 {
+		counts = append(counts, ui.WarningStyle.Render(plural(n, "machine prompt pending", "machine prompts pending")))
+	}
+	fmt.Fprintf(&sb, "%s %s\n", title, strings.Join(counts, ui.SubtleStyle.Render(", ")))
+
+	if len(drifted) > 0 {
+		names := drifted
+		if len(names) > motdMaxConfigs {
+			names = append(names[:motdMaxConfigs:motdMaxConfigs], fmt.Sprintf("+%d more", len(drifted)-motdMaxConfigs))
+		}
+		fmt.Fprintf(&sb, "  %s %s\n", ui.WarningStyle.Render("~"), strings.Join(names, ", "))
+		motdHint(&sb, "g4d sync", "to relink them")
+	}
+	if missing > 0 {
+		motdHint(&sb, "g4d install", "to install dependencies")
+	}
+	if len(o.PendingMachine) > 0 {
+		motdHint(&sb, "g4d machine configure", "to answer "+strings.Join(o.PendingMachine, ", "))
+	}
+	return sb.String()
+}
+
+func motdHint(sb *strings.Builder, command, purpose string) {
+	fmt.Fprintf(sb, "  %s %s\n", lipgloss.NewStyle().Foreground(ui.PrimaryColor).Render(command), ui.SubtleStyle.Render(purpose))
+}
+
+// plural formats n with the singular or plural noun
+func plural(n int, one, many string) string {
+	if n == 1 {
+		return "1 " + one
+	}
+	return fmt.Sprintf("%d %s", n, many)
+}
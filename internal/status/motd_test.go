@@ -0,0 +1,66 @@
+package status
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMOTDDue(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state", MOTDCacheFileName)
+	morning := time.Date(2026, 3, 2, 8, 0, 0, 0, time.Local)
+
+	if !MOTDDue(path, morning) {
+		t.Error("expected the motd to be due without a cache")
+	}
+	if err := MarkMOTDShown(path, morning); err != nil {
+		t.Fatalf("MarkMOTDShown failed: %v", err)
+	}
+	if MOTDDue(path, morning.Add(10*time.Hour)) {
+		t.Error("expected the motd to be shown once a day")
+	}
+	if !MOTDDue(path, morning.Add(20*time.Hour)) {
+		t.Error("expected the motd to be due the next day")
+	}
+}
+
+func TestRenderMOTD(t *testing.T) {
+	o := &Overview{
+		Initialized: true,
+		ConfigCount: 8,
+		Configs: []ConfigStatus{
+			{Name: "vim", Status: SyncStatusSynced},
+			{Name: "zsh", Status: SyncStatusDrifted},
+			{Name: "tmux", Status: SyncStatusNotInstalled},
+		},
+		Dependencies:   DependencyStatus{Total: 3, Installed: 1, Missing: 2},
+		PendingMachine: []string{"git"},
+	}
+
+	got := RenderMOTD(o)
+	for _, want := range []string{
+		"1 config drifted", "2 missing dependencies", "1 machine prompt pending",
+		"zsh", "g4d sync", "g4d install", "g4d machine configure",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("RenderMOTD() missing %q:\n%s", want, got)
+		}
+	}
+	if strings.Contains(got, "tmux") {
+		t.Errorf("RenderMOTD() should not list configs that are not installed:\n%s", got)
+	}
+
+	var many []ConfigStatus
+	for _, name := range []string{"a", "b", "c", "d", "e", "f", "g"} {
+		many = append(many, ConfigStatus{Name: name, Status: SyncStatusDrifted})
+	}
+	if got := RenderMOTD(&Overview{Initialized: true, Configs: many}); !strings.Contains(got, "e, +2 more") {
+		t.Errorf("RenderMOTD() should cap the configs listed:\n%s", got)
+	}
+
+	clean := &Overview{Initialized: true, ConfigCount: 2, Configs: o.Configs[:1]}
+	if got := RenderMOTD(clean); strings.Count(got, "\n") != 1 || !strings.Contains(got, "2 configs in sync") {
+		t.Errorf("RenderMOTD() of a clean overview = %q", got)
+	}
+}
@@ -0,0 +1,294 @@
+package status
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/nvandessel/go4dot/internal/config"
+	"github.com/nvandessel/go4dot/internal/deps"
+	"github.com/nvandessel/go4dot/internal/machine"
+	"github.com/nvandessel/go4dot/internal/platform"
+	"github.com/nvandessel/go4dot/internal/stow"
+)
+
+// OwnerKind identifies what manages a path.
+type OwnerKind string
+
+const (
+	OwnerConfig   OwnerKind = "config"
+	OwnerExternal OwnerKind = "external"
+	OwnerMachine  OwnerKind = "machine"
+)
+
+// OwnerMethod describes how a managed path is put in place.
+type OwnerMethod string
+
+const (
+	MethodSymlink   OwnerMethod = "symlink"   // The path itself links to the source
+	MethodFolded    OwnerMethod = "folded"    // A parent directory links into the config
+	MethodCopy      OwnerMethod = "copy"      // A regular file identical to the source
+	MethodDirectory OwnerMethod = "directory" // A real directory stow links files into
+	MethodConflict  OwnerMethod = "conflict"  // Something else is in the way
+	MethodMissing   OwnerMethod = "missing"   // The config provides it but it is not linked
+	MethodIgnored   OwnerMethod = "ignored"   // The source exists but stow ignores it
+	MethodClone     OwnerMethod = "clone"     // Inside a cloned external dependency
+	MethodGenerated OwnerMethod = "generated" // Rendered from a machine config template
+)
+
+// Owner describes one config, external dependency or machine config that
+// provides a path.
+type Owner struct {
+	Kind   OwnerKind   `json:"kind"`
+	Name   string      `json:"name"`
+	Method OwnerMethod `json:"method"`
+	Source string      `json:"source,omitempty"` // Source file, repo URL or template ID
+	Link   string      `json:"link,omitempty"`   // The symlink or destination that provides the path
+}
+
+// Ownership is the result of looking up who manages a path.
+type Ownership struct {
+	Target string  `json:"target"`
+	Owners []Owner `json:"owners"`
+	// StrayLink is set when the target is a symlink into the dotfiles repo
+	// that no config accounts for.
+	StrayLink string `json:"stray_link,omitempty"`
+}
+
+// IsManaged reports whether any owner currently provides the path.
+func (o *Ownership) IsManaged() bool {
+	for _, owner := range o.Owners {
+		switch owner.Method {
+		case MethodSymlink, MethodFolded, MethodCopy, MethodDirectory, MethodClone, MethodGenerated:
+			return true
+		}
+	}
+	return false
+}
+
+// FindOwners resolves which configs, external dependencies and machine
+// configs manage target. It is the inverse of the per-config link views.
+func FindOwners(cfg *config.Config, dotfilesPath string, p *platform.Platform, target string) (*Ownership, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get home directory: %w", err)
+	}
+
+	target, err = expandTarget(target, home)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &Ownership{Target: target, Owners: []Owner{}}
+
+	linkRoot := home
+	if cfg.Stow.Target != "" {
+		linkRoot = cfg.Stow.TargetDir()
+	}
+	if rel, ok := relativeTo(linkRoot, target); ok && rel != "." {
+		for _, item := range cfg.GetAllConfigs() {
+			if owner, ok := configOwner(item, dotfilesPath, linkRoot, rel, cfg.Stow); ok {
+				result.Owners = append(result.Owners, owner)
+			}
+		}
+	}
+
+	for _, s := range deps.CheckExternalStatus(cfg, p, dotfilesPath) {
+		if s.Path == "" {
+			continue
+		}
+		if _, ok := relativeTo(s.Path, target); !ok {
+			continue
+		}
+		method := MethodClone
+		if s.Dep.Method == "copy" {
+			method = MethodCopy
+		}
+		result.Owners = append(result.Owners, Owner{
+			Kind:   OwnerExternal,
+			Name:   s.Dep.ID,
+			Method: method,
+			Source: s.Dep.URL,
+			Link:   s.Path,
+		})
+	}
+
+	for _, s := range machine.CheckMachineConfigStatus(cfg) {
+		if s.Destination != target {
+			continue
+		}
+		method := MethodGenerated
+		if s.Status != "configured" {
+			method = MethodMissing
+		}
+		result.Owners = append(result.Owners, Owner{
+			Kind:   OwnerMachine,
+			Name:   s.ID,
+			Method: method,
+			Source: s.ID,
+			Link:   s.Destination,
+		})
+	}
+
+	if len(result.Owners) == 0 {
+		if dest, ok := readLink(target); ok {
+			if _, inRepo := relativeTo(dotfilesPath, dest); inRepo {
+				result.StrayLink = dest
+			}
+		}
+	}
+
+	return result, nil
+}
+
+// configOwner checks whether a config provides the home-relative path rel.
+// With stow --dotfiles, .bashrc may come from dot-bashrc.
+func configOwner(item config.ConfigItem, dotfilesPath, home, rel string, settings config.StowSettings) (Owner, bool) {
+	configDir := item.Dir(dotfilesPath)
+	sourceRel := settings.SourcePath(rel)
+	if _, err := os.Lstat(filepath.Join(configDir, sourceRel)); err != nil {
+		sourceRel = rel
+	}
+	source := filepath.Join(configDir, sourceRel)
+	if _, err := os.Lstat(source); err != nil {
+		return Owner{}, false
+	}
+
+	owner := Owner{Kind: OwnerConfig, Name: item.Name, Source: source}
+	target := filepath.Join(home, rel)
+
+	if ignore, err := stow.LoadIgnoreList(configDir); err == nil {
+		for dir := sourceRel; dir != "." && dir != string(filepath.Separator); dir = filepath.Dir(dir) {
+			if ignore.Match(dir) {
+				owner.Method = MethodIgnored
+				return owner, true
+			}
+		}
+	}
+
+	// Find the nearest symlink at or above the target. Stow folds whole
+	// directories into a single link when it can.
+	for path := target; path != home && path != filepath.Dir(path); path = filepath.Dir(path) {
+		dest, ok := readLink(path)
+		if !ok {
+			continue
+		}
+		pathRel, _ := filepath.Rel(home, path)
+		if dest == filepath.Join(configDir, pathRel) || dest == filepath.Join(configDir, settings.SourcePath(pathRel)) {
+			owner.Link = path
+			owner.Method = MethodFolded
+			if path == target {
+				owner.Method = MethodSymlink
+			}
+			return owner, true
+		}
+		if path == target {
+			owner.Link = path
+			owner.Method = MethodConflict
+			return owner, true
+		}
+		break // A parent links elsewhere, the target is not ours
+	}
+
+	targetInfo, err := os.Lstat(target)
+	if err != nil {
+		owner.Method = MethodMissing
+		return owner, true
+	}
+
+	owner.Method = MethodConflict
+	if targetInfo.Mode().IsRegular() && sameContent(source, target) {
+		owner.Method = MethodCopy
+	} else if targetInfo.IsDir() {
+		// Directories can be shared by configs; stow links the files inside
+		owner.Method = MethodDirectory
+	}
+	return owner, true
+}
+
+// ManagedPaths lists the home-relative paths of all files provided by the
+// configs, as linked, for shell completion.
+func ManagedPaths(cfg *config.Config, dotfilesPath string) []string {
+	var paths []string
+	for _, item := range cfg.GetAllConfigs() {
+		configDir := item.Dir(dotfilesPath)
+		ignore, err := stow.LoadIgnoreList(configDir)
+		if err != nil {
+			continue
+		}
+		_ = filepath.Walk(configDir, func(path string, info os.FileInfo, err error) error {
+			if err != nil || path == configDir {
+				return nil
+			}
+			rel, _ := filepath.Rel(configDir, path)
+			if ignore.Match(rel) {
+				if info.IsDir() {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			if !info.IsDir() {
+				paths = append(paths, cfg.Stow.LinkPath(rel))
+			}
+			return nil
+		})
+	}
+	return paths
+}
+
+// expandTarget makes target absolute, expanding a leading ~/
+func expandTarget(target, home string) (string, error) {
+	if target == "~" {
+		return home, nil
+	}
+	if strings.HasPrefix(target, "~/") {
+		return filepath.Join(home, target[2:]), nil
+	}
+	abs, err := filepath.Abs(target)
+	if err != nil {
+		return "", fmt.Errorf("invalid path %q: %w", target, err)
+	}
+	return abs, nil
+}
+
+// relativeTo returns path relative to base if path is base or inside it
+func relativeTo(base, path string) (string, bool) {
+	if base == "" {
+		return "", false
+	}
+	rel, err := filepath.Rel(base, path)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", false
+	}
+	return rel, true
+}
+
+// readLink returns the absolute, cleaned destination of a symlink
+func readLink(path string) (string, bool) {
+	info, err := os.Lstat(path)
+	if err != nil || info.Mode()&os.ModeSymlink == 0 {
+		return "", false
+	}
+	dest, err := os.Readlink(path)
+	if err != nil {
+		return "", false
+	}
+	if !filepath.IsAbs(dest) {
+		dest = filepath.Join(filepath.Dir(path), dest)
+	}
+	return filepath.Clean(dest), true
+}
+
+func sameContent(a, b string) bool {
+	da, err := os.ReadFile(a)
+	if err != nil {
+		return false
+	}
+	db, err := os.ReadFile(b)
+	if err != nil {
+		return false
+	}
+	return bytes.Equal(da, db)
+}
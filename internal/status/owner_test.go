@@ -0,0 +1,169 @@
+package status
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/nvandessel/go4dot/internal/config"
+	"github.com/nvandessel/go4dot/internal/platform"
+)
+
+func writeTestFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestFindOwners(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	dotfiles := t.TempDir()
+
+	// zsh: file symlink
+	writeTestFile(t, filepath.Join(dotfiles, "zsh", ".zshrc"), "zsh")
+	if err := os.Symlink(filepath.Join(dotfiles, "zsh", ".zshrc"), filepath.Join(home, ".zshrc")); err != nil {
+		t.Fatal(err)
+	}
+	// nvim: folded directory link
+	writeTestFile(t, filepath.Join(dotfiles, "nvim", ".config", "nvim", "init.lua"), "lua")
+	writeTestFile(t, filepath.Join(home, ".config", "placeholder"), "")
+	if err := os.Symlink(filepath.Join(dotfiles, "nvim", ".config", "nvim"), filepath.Join(home, ".config", "nvim")); err != nil {
+		t.Fatal(err)
+	}
+	// git: identical copy, and a not-linked file
+	writeTestFile(t, filepath.Join(dotfiles, "git", ".gitconfig"), "[user]")
+	writeTestFile(t, filepath.Join(home, ".gitconfig"), "[user]")
+	writeTestFile(t, filepath.Join(dotfiles, "git", ".gitignore_global"), "*.swp")
+	// bash: conflicting file, and a stow-ignored README
+	writeTestFile(t, filepath.Join(dotfiles, "bash", ".bashrc"), "repo")
+	writeTestFile(t, filepath.Join(home, ".bashrc"), "local")
+	writeTestFile(t, filepath.Join(dotfiles, "bash", "README.md"), "docs")
+	// stray link into the repo
+	if err := os.Symlink(filepath.Join(dotfiles, "old", ".oldrc"), filepath.Join(home, ".oldrc")); err != nil {
+		t.Fatal(err)
+	}
+	// external checkout
+	writeTestFile(t, filepath.Join(home, ".tmux", "plugins", "tpm", "tpm"), "#!/bin/sh")
+
+	cfg := &config.Config{
+		Configs: config.ConfigGroups{
+			Core: []config.ConfigItem{
+				{Name: "zsh", Path: "zsh"},
+				{Name: "nvim", Path: "nvim"},
+				{Name: "git", Path: "git"},
+				{Name: "bash", Path: "bash"},
+			},
+		},
+		External: []config.ExternalDep{
+			{ID: "tpm", URL: "https://github.com/tmux-plugins/tpm", Destination: "~/.tmux/plugins/tpm"},
+		},
+		MachineConfig: []config.MachinePrompt{
+			{ID: "git-local", Destination: "~/.gitconfig.local"},
+		},
+	}
+	p := &platform.Platform{OS: "linux"}
+
+	tests := []struct {
+		target     string
+		wantName   string
+		wantMethod OwnerMethod
+		managed    bool
+	}{
+		{"~/.zshrc", "zsh", MethodSymlink, true},
+		{filepath.Join(home, ".config", "nvim", "init.lua"), "nvim", MethodFolded, true},
+		{"~/.gitconfig", "git", MethodCopy, true},
+		{"~/.gitignore_global", "git", MethodMissing, false},
+		{"~/.bashrc", "bash", MethodConflict, false},
+		{"~/README.md", "bash", MethodIgnored, false},
+		{"~/.tmux/plugins/tpm/tpm", "tpm", MethodClone, true},
+		{"~/.gitconfig.local", "git-local", MethodMissing, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.target, func(t *testing.T) {
+			result, err := FindOwners(cfg, dotfiles, p, tt.target)
+			if err != nil {
+				t.Fatalf("FindOwners() error = %v", err)
+			}
+			if len(result.Owners) != 1 {
+				t.Fatalf("expected 1 owner, got %+v", result.Owners)
+			}
+			o := result.Owners[0]
+			if o.Name != tt.wantName || o.Method != tt.wantMethod {
+				t.Errorf("owner = %s/%s, want %s/%s", o.Name, o.Method, tt.wantName, tt.wantMethod)
+			}
+			if result.IsManaged() != tt.managed {
+				t.Errorf("IsManaged() = %v, want %v", result.IsManaged(), tt.managed)
+			}
+		})
+	}
+
+	t.Run("stray link", func(t *testing.T) {
+		result, err := FindOwners(cfg, dotfiles, p, "~/.oldrc")
+		if err != nil {
+			t.Fatalf("FindOwners() error = %v", err)
+		}
+		if len(result.Owners) != 0 || result.StrayLink == "" {
+			t.Errorf("expected stray link with no owners, got %+v", result)
+		}
+	})
+
+	t.Run("unmanaged", func(t *testing.T) {
+		result, err := FindOwners(cfg, dotfiles, p, "/etc/hosts")
+		if err != nil {
+			t.Fatalf("FindOwners() error = %v", err)
+		}
+		if len(result.Owners) != 0 || result.IsManaged() {
+			t.Errorf("expected no owners, got %+v", result)
+		}
+	})
+}
+
+func TestFindOwners_DotfilesPrefix(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	dotfiles := t.TempDir()
+
+	writeTestFile(t, filepath.Join(dotfiles, "fish", "dot-config", "fish", "config.fish"), "fish")
+	writeTestFile(t, filepath.Join(home, ".config", "placeholder"), "")
+	if err := os.Symlink(filepath.Join(dotfiles, "fish", "dot-config", "fish"), filepath.Join(home, ".config", "fish")); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := &config.Config{
+		Configs: config.ConfigGroups{Core: []config.ConfigItem{{Name: "fish", Path: "fish"}}},
+		Stow:    config.StowSettings{Flags: []string{"--dotfiles"}},
+	}
+	got, err := FindOwners(cfg, dotfiles, &platform.Platform{OS: "linux"}, "~/.config/fish/config.fish")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got.Owners) != 1 || got.Owners[0].Name != "fish" || got.Owners[0].Method != MethodFolded {
+		t.Errorf("Owners = %+v, want fish through the folded dot-config/fish link", got.Owners)
+	}
+
+	paths := ManagedPaths(cfg, dotfiles)
+	if len(paths) != 1 || paths[0] != filepath.Join(".config", "fish", "config.fish") {
+		t.Errorf("ManagedPaths() = %v, want the linked name", paths)
+	}
+}
+
+func TestManagedPaths(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	dotfiles := t.TempDir()
+	writeTestFile(t, filepath.Join(dotfiles, "zsh", ".zshrc"), "")
+	writeTestFile(t, filepath.Join(dotfiles, "zsh", "README.md"), "")
+	writeTestFile(t, filepath.Join(dotfiles, "zsh", ".git", "HEAD"), "")
+
+	cfg := &config.Config{Configs: config.ConfigGroups{Core: []config.ConfigItem{{Name: "zsh", Path: "zsh"}}}}
+
+	paths := ManagedPaths(cfg, dotfiles)
+	if len(paths) != 1 || paths[0] != ".zshrc" {
+		t.Errorf("ManagedPaths() = %v, want [.zshrc]", paths)
+	}
+}
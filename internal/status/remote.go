@@ -0,0 +1,257 @@
+package status
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/nvandessel/go4dot/internal/config"
+	"github.com/nvandessel/go4dot/internal/deps"
+	"github.com/nvandessel/go4dot/internal/hooks"
+	"github.com/nvandessel/go4dot/internal/platform"
+	"github.com/nvandessel/go4dot/internal/transcript"
+	"github.com/nvandessel/go4dot/internal/validation"
+)
+
+// How a remote .go4dot.yaml was fetched
+const (
+	RemoteSourceRaw   = "raw"   // Downloaded on its own
+	RemoteSourceClone = "clone" // Read from a shallow clone in a temp directory
+)
+
+// rawGitHubURL serves single files of GitHub repos; tests point it at a
+// local server
+var rawGitHubURL = "https://raw.githubusercontent.com"
+
+// RemoteConfigStatus is a config of a repo that isn't installed
+type RemoteConfigStatus struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	IsCore      bool   `json:"is_core"`
+	Applies     bool   `json:"applies"` // Installed on this platform; false when its platforms or condition leave it out
+}
+
+// RemoteDependency is a dependency of a repo that isn't installed
+type RemoteDependency struct {
+	Name     string `json:"name"`
+	Package  string `json:"package"` // Name given to this platform's package manager
+	Version  string `json:"version,omitempty"`
+	Manual   bool   `json:"manual,omitempty"`
+	Optional bool   `json:"optional,omitempty"`
+	Found    bool   `json:"found"` // Already on PATH; the version is not checked
+}
+
+// RemoteOverview is what installing a dotfiles repo would entail, worked
+// out from its .go4dot.yaml alone
+type RemoteOverview struct {
+	Repo           string               `json:"repo"`
+	Source         string               `json:"source"`
+	Name           string               `json:"name,omitempty"`
+	Description    string               `json:"description,omitempty"`
+	Platform       PlatformInfo         `json:"platform"`
+	Configs        []RemoteConfigStatus `json:"configs"`
+	Dependencies   []RemoteDependency   `json:"dependencies"`
+	External       []string             `json:"external,omitempty"` // URLs install would clone on this platform
+	Toolchains     []string             `json:"toolchains,omitempty"`
+	MachineConfigs []string             `json:"machine_configs,omitempty"` // Prompted for during install
+	Extends        []string             `json:"extends,omitempty"`         // Base repos, not fetched
+	Hooks          []string             `json:"hooks,omitempty"`           // Commands install runs before and after linking
+	PostInstall    string               `json:"post_install,omitempty"`    // Message install shows at the end
+}
+
+// FetchRemoteConfig fetches the .go4dot.yaml of a repo without cloning it
+// into place. A URL of the file itself and GitHub repos are downloaded
+// directly; any other repo, or a GitHub one whose file can't be downloaded
+// (e.g. a private repo), is cloned with --depth 1 into a temp directory
+// that is removed again.
+func FetchRemoteConfig(repoURL string) ([]byte, string, error) {
+	if strings.HasSuffix(repoURL, "/"+config.ConfigFileName) && strings.HasPrefix(repoURL, "https://") {
+		data, err := httpGet(repoURL)
+		if err != nil {
+			return nil, "", err
+		}
+		return data, RemoteSourceRaw, nil
+	}
+
+	if err := validation.ValidateGitURL(repoURL); err != nil {
+		return nil, "", err
+	}
+	if repo, ok := deps.GitHubRepo(repoURL); ok {
+		if data, err := httpGet(rawGitHubURL + "/" + repo + "/HEAD/" + config.ConfigFileName); err == nil {
+			return data, RemoteSourceRaw, nil
+		}
+	}
+
+	data, err := shallowCloneConfig(repoURL)
+	if err != nil {
+		return nil, "", err
+	}
+	return data, RemoteSourceClone, nil
+}
+
+// httpGet downloads url, failing on any status but 200
+func httpGet(url string) ([]byte, error) {
+	client := &http.Client{Timeout: 10 * time.Second}
+	resp, err := client.Get(url)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch %s: %w", url, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to fetch %s: %s", url, resp.Status)
+	}
+	return io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+}
+
+// shallowCloneConfig clones repoURL into a temp directory and reads its
+// .go4dot.yaml. git never prompts, so a repo needing credentials fails
+// rather than waits.
+func shallowCloneConfig(repoURL string) ([]byte, error) {
+	tmpDir, err := os.MkdirTemp("", "g4d-remote-*")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temp directory: %w", err)
+	}
+	defer func() { _ = os.RemoveAll(tmpDir) }()
+
+	cmd := exec.Command("git", "clone", "--depth", "1", "--", repoURL, tmpDir)
+	cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0")
+	if out, err := transcript.CombinedOutput(cmd); err != nil {
+		return nil, fmt.Errorf("git clone failed: %s", strings.TrimSpace(string(out)))
+	}
+
+	data, err := os.ReadFile(filepath.Join(tmpDir, config.ConfigFileName))
+	if os.IsNotExist(err) {
+		return nil, fmt.Errorf("%s has no %s", repoURL, config.ConfigFileName)
+	}
+	return data, err
+}
+
+// GatherRemote reports what installing the repo at repoURL would entail on
+// this platform: which configs apply, which dependencies are missing and
+// what else install would clone, prompt for and run. Nothing from the repo
+// is run, so dependencies are only looked up on PATH.
+func (g *Gatherer) GatherRemote(repoURL string) (*RemoteOverview, error) {
+	p, err := g.PlatformDetector()
+	if err != nil {
+		return nil, fmt.Errorf("detecting platform: %w", err)
+	}
+
+	fetch := g.RemoteFetcher
+	if fetch == nil {
+		fetch = FetchRemoteConfig
+	}
+	data, source, err := fetch(repoURL)
+	if err != nil {
+		return nil, err
+	}
+	cfg, err := config.Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s in %s: %w", config.ConfigFileName, repoURL, err)
+	}
+
+	overview := &RemoteOverview{
+		Repo:        repoURL,
+		Source:      source,
+		Name:        cfg.Metadata.Name,
+		Description: cfg.Metadata.Description,
+		Platform: PlatformInfo{
+			OS:             p.OS,
+			Distro:         p.Distro,
+			DistroVersion:  p.DistroVersion,
+			PackageManager: p.PackageManager,
+			Architecture:   p.Architecture,
+			IsWSL:          p.IsWSL,
+		},
+		Configs:      []RemoteConfigStatus{},
+		Dependencies: []RemoteDependency{},
+		Extends:      cfg.Extends,
+		PostInstall:  cfg.PostInstall,
+	}
+
+	applies := make(map[string]bool)
+	for _, c := range cfg.GetConfigsForPlatform(p) {
+		applies[c.Name] = true
+	}
+	for _, group := range []struct {
+		items []config.ConfigItem
+		core  bool
+	}{{cfg.Configs.Core, true}, {cfg.Configs.Optional, false}} {
+		for _, c := range group.items {
+			if c.Archived {
+				continue
+			}
+			overview.Configs = append(overview.Configs, RemoteConfigStatus{
+				Name:        c.Name,
+				Description: c.Description,
+				IsCore:      group.core,
+				Applies:     applies[c.Name],
+			})
+		}
+	}
+
+	overview.Dependencies = remoteDependencies(cfg.GetDepsForPlatform(p), p)
+
+	for _, e := range cfg.External {
+		if len(e.Condition) == 0 || platform.CheckCondition(e.Condition, p) {
+			overview.External = append(overview.External, e.URL)
+		}
+	}
+	for _, tc := range cfg.Toolchains {
+		if len(tc.Condition) == 0 || platform.CheckCondition(tc.Condition, p) {
+			overview.Toolchains = append(overview.Toolchains, strings.TrimSpace(tc.Language+" "+strings.Join(tc.Versions, ", ")))
+		}
+	}
+	for _, mc := range cfg.MachineConfig {
+		overview.MachineConfigs = append(overview.MachineConfigs, mc.ID)
+	}
+
+	var linked []string
+	for _, c := range overview.Configs {
+		if c.Applies {
+			linked = append(linked, c.Name)
+		}
+	}
+	for _, stage := range []string{config.HookPreInstall, config.HookPostInstall} {
+		for _, h := range hooks.For(cfg, stage, linked) {
+			overview.Hooks = append(overview.Hooks, h.String())
+		}
+	}
+
+	return overview, nil
+}
+
+// remoteDependencies lists the dependencies for p, looking each one's
+// binary up on PATH without running it
+func remoteDependencies(d config.Dependencies, p *platform.Platform) []RemoteDependency {
+	var result []RemoteDependency
+	for _, group := range []struct {
+		items    []config.DependencyItem
+		optional bool
+	}{{d.Critical, false}, {d.Core, false}, {d.Optional, true}} {
+		for _, dep := range group.items {
+			binary := dep.Binary
+			if binary == "" {
+				binary = dep.Name
+			}
+			_, err := exec.LookPath(binary)
+			result = append(result, RemoteDependency{
+				Name:     dep.Name,
+				Package:  deps.PackageName(dep, p),
+				Version:  dep.Version,
+				Manual:   dep.Manual,
+				Optional: group.optional,
+				Found:    err == nil,
+			})
+		}
+	}
+	if result == nil {
+		return []RemoteDependency{}
+	}
+	return result
+}
@@ -0,0 +1,121 @@
+package status
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/nvandessel/go4dot/internal/platform"
+)
+
+const remoteConfig = `schema_version: "1.0"
+metadata:
+  name: me-dotfiles
+dependencies:
+  core:
+    - name: ripgrep
+      binary: rg
+      package:
+        apt: ripgrep
+    - name: aerospace
+      condition:
+        os: macos
+configs:
+  core:
+    - name: git
+      path: git
+      hooks:
+        post_install: git config --global include.path ~/.gitconfig.local
+  optional:
+    - name: hammerspoon
+      path: hammerspoon
+      platforms: [macos]
+      hooks:
+        post_install: hs -c 'hs.reload()'
+    - name: old
+      path: old
+      archived: true
+external:
+  - name: tpm
+    url: https://github.com/tmux-plugins/tpm
+machine_config:
+  - id: git
+    destination: ~/.gitconfig.local
+`
+
+func TestGatherRemote(t *testing.T) {
+	p := &platform.Platform{OS: "linux", Distro: "ubuntu", PackageManager: "apt", Architecture: "amd64"}
+	g := &Gatherer{
+		PlatformDetector: func() (*platform.Platform, error) { return p, nil },
+		RemoteFetcher: func(repoURL string) ([]byte, string, error) {
+			return []byte(remoteConfig), RemoteSourceRaw, nil
+		},
+	}
+
+	overview, err := g.GatherRemote("https://github.com/me/dotfiles")
+	if err != nil {
+		t.Fatalf("GatherRemote() error = %v", err)
+	}
+
+	if overview.Name != "me-dotfiles" || overview.Source != RemoteSourceRaw {
+		t.Errorf("name, source = %q, %q", overview.Name, overview.Source)
+	}
+	if len(overview.Configs) != 2 {
+		t.Fatalf("configs = %+v, want git and hammerspoon without the archived one", overview.Configs)
+	}
+	if !overview.Configs[0].Applies || !overview.Configs[0].IsCore {
+		t.Errorf("git = %+v, want a core config that applies", overview.Configs[0])
+	}
+	if overview.Configs[1].Applies {
+		t.Errorf("hammerspoon = %+v, want it left out on linux", overview.Configs[1])
+	}
+	if len(overview.Dependencies) != 1 || overview.Dependencies[0].Package != "ripgrep" {
+		t.Errorf("dependencies = %+v, want only ripgrep", overview.Dependencies)
+	}
+	if len(overview.External) != 1 || len(overview.MachineConfigs) != 1 {
+		t.Errorf("external = %v, machine configs = %v", overview.External, overview.MachineConfigs)
+	}
+	if len(overview.Hooks) != 1 || !strings.HasPrefix(overview.Hooks[0], "post_install (git): ") {
+		t.Errorf("hooks = %q, want only git's, as hammerspoon is not linked on linux", overview.Hooks)
+	}
+
+	out, err := RenderRemote(overview, RenderOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"1 of 2 apply", "hammerspoon (not for this platform)", "https://github.com/tmux-plugins/tpm", "git config --global include.path"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestFetchRemoteConfig_GitHubRaw(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/me/dotfiles/HEAD/.go4dot.yaml" {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write([]byte(remoteConfig))
+	}))
+	defer server.Close()
+
+	orig := rawGitHubURL
+	rawGitHubURL = server.URL
+	defer func() { rawGitHubURL = orig }()
+
+	data, source, err := FetchRemoteConfig("https://github.com/me/dotfiles.git")
+	if err != nil {
+		t.Fatalf("FetchRemoteConfig() error = %v", err)
+	}
+	if source != RemoteSourceRaw || string(data) != remoteConfig {
+		t.Errorf("source = %q, data = %q", source, data)
+	}
+}
+
+func TestFetchRemoteConfig_InvalidURL(t *testing.T) {
+	if _, _, err := FetchRemoteConfig("file:///tmp/dotfiles"); err == nil {
+		t.Error("expected file:// URLs to be refused")
+	}
+}
@@ -71,6 +71,10 @@ func renderText(o *Overview) string {
 	if o.LastSync != nil {
 		writeField(&sb, "Last sync", formatTimeAgo(*o.LastSync))
 	}
+	if len(o.PendingMachine) > 0 {
+		writeField(&sb, "Machine setup", ui.WarningStyle.Render(
+			fmt.Sprintf("%d pending (%s)", len(o.PendingMachine), strings.Join(o.PendingMachine, ", "))))
+	}
 	sb.WriteString("\n")
 
 	// Config status section
@@ -89,6 +93,9 @@ func renderText(o *Overview) string {
 	for _, cs := range o.Configs {
 		sb.WriteString(renderConfigLine(cs))
 		sb.WriteString("\n")
+		for _, linkErr := range cs.LinkErrors {
+			fmt.Fprintf(&sb, "      %s\n", ui.ErrorStyle.Render(linkErr))
+		}
 	}
 	sb.WriteString("\n")
 
@@ -190,6 +197,9 @@ func driftDetails(cs ConfigStatus) string {
 	if cs.ContentDrift > 0 {
 		parts = append(parts, fmt.Sprintf("≠%d content drift", cs.ContentDrift))
 	}
+	if len(cs.LinkErrors) > 0 {
+		parts = append(parts, fmt.Sprintf("✗%d symlink loops", len(cs.LinkErrors)))
+	}
 	if cs.Orphans > 0 {
 		parts = append(parts, fmt.Sprintf("?%d untracked", cs.Orphans))
 	}
@@ -246,3 +256,114 @@ func formatTimeAgo(t time.Time) string {
 		return fmt.Sprintf("%d days ago", days)
 	}
 }
+
+// RenderRemote formats a RemoteOverview for display, as JSON or as a
+// summary of what installing the repo would entail
+func RenderRemote(o *RemoteOverview, opts RenderOptions) (string, error) {
+	if opts.JSON {
+		data, err := json.MarshalIndent(o, "", "  ")
+		if err != nil {
+			return "", fmt.Errorf("marshaling status to JSON: %w", err)
+		}
+		return string(data), nil
+	}
+
+	var sb strings.Builder
+	header := lipgloss.NewStyle().
+		Foreground(ui.PrimaryColor).
+		Bold(true).
+		Render("go4dot status: " + o.Repo)
+	sb.WriteString(header)
+	sb.WriteString("\n\n")
+
+	sectionHeader(&sb, "Repo")
+	if o.Name != "" {
+		writeField(&sb, "Name", o.Name)
+	}
+	if o.Description != "" {
+		writeField(&sb, "Description", o.Description)
+	}
+	writeField(&sb, "Fetched", o.Source)
+	if len(o.Extends) > 0 {
+		writeField(&sb, "Extends", strings.Join(o.Extends, ", ")+" "+ui.SubtleStyle.Render("(not fetched)"))
+	}
+	sb.WriteString("\n")
+
+	sectionHeader(&sb, "Platform")
+	writeField(&sb, "OS", formatOS(o.Platform))
+	writeField(&sb, "Arch", o.Platform.Architecture)
+	writeField(&sb, "Package Manager", o.Platform.PackageManager)
+	sb.WriteString("\n")
+
+	sectionHeader(&sb, "Configs")
+	applicable := 0
+	for _, c := range o.Configs {
+		if c.Applies {
+			applicable++
+		}
+	}
+	fmt.Fprintf(&sb, "  %s of %d apply to this platform\n",
+		ui.SuccessStyle.Render(fmt.Sprintf("%d", applicable)), len(o.Configs))
+	for _, c := range o.Configs {
+		icon, label := ui.SuccessStyle.Render("+"), c.Name
+		if !c.Applies {
+			icon, label = ui.SubtleStyle.Render("-"), ui.SubtleStyle.Render(c.Name+" (not for this platform)")
+		}
+		if c.IsCore {
+			label += lipgloss.NewStyle().Foreground(ui.PrimaryColor).Render(" [core]")
+		}
+		fmt.Fprintf(&sb, "  %s %s\n", icon, label)
+	}
+	sb.WriteString("\n")
+
+	sectionHeader(&sb, "Dependencies")
+	if len(o.Dependencies) == 0 {
+		sb.WriteString("  ")
+		sb.WriteString(ui.SubtleStyle.Render("none defined"))
+		sb.WriteString("\n")
+	}
+	for _, d := range o.Dependencies {
+		label := d.Name
+		if d.Package != d.Name {
+			label += " " + ui.SubtleStyle.Render("("+d.Package+")")
+		}
+		if d.Version != "" {
+			label += " " + ui.SubtleStyle.Render(d.Version)
+		}
+		switch {
+		case d.Found:
+			fmt.Fprintf(&sb, "  %s %s\n", ui.SuccessStyle.Render("*"), label)
+		case d.Manual:
+			fmt.Fprintf(&sb, "  %s %s %s\n", ui.WarningStyle.Render("!"), label, ui.SubtleStyle.Render("(install manually)"))
+		case d.Optional:
+			fmt.Fprintf(&sb, "  %s %s %s\n", ui.SubtleStyle.Render("+"), label, ui.SubtleStyle.Render("(optional)"))
+		default:
+			fmt.Fprintf(&sb, "  %s %s\n", ui.WarningStyle.Render("+"), label)
+		}
+	}
+
+	writeRemoteList(&sb, "External", o.External)
+	writeRemoteList(&sb, "Toolchains", o.Toolchains)
+	writeRemoteList(&sb, "Machine configs", o.MachineConfigs)
+	writeRemoteList(&sb, "Hooks", o.Hooks)
+	if o.PostInstall != "" {
+		sb.WriteString("\n")
+		sectionHeader(&sb, "Post-install message")
+		for _, line := range strings.Split(strings.TrimRight(o.PostInstall, "\n"), "\n") {
+			fmt.Fprintf(&sb, "  %s\n", ui.SubtleStyle.Render(line))
+		}
+	}
+	return sb.String(), nil
+}
+
+// writeRemoteList writes a section listing items, or nothing without any
+func writeRemoteList(sb *strings.Builder, title string, items []string) {
+	if len(items) == 0 {
+		return
+	}
+	sb.WriteString("\n")
+	sectionHeader(sb, title)
+	for _, item := range items {
+		fmt.Fprintf(sb, "  + %s\n", item)
+	}
+}
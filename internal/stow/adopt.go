@@ -83,11 +83,11 @@ func (s *AdoptSummary) GetNotLinked() []AdoptResult {
 // This is used to detect pre-existing stow setups that should be adopted into go4dot state.
 func ScanExistingSymlinks(cfg *config.Config, dotfilesPath string) (*AdoptSummary, error) {
 	summary := &AdoptSummary{}
-	home := os.Getenv("HOME")
+	home := cfg.Stow.TargetDir()
 
 	// Process core configs
 	for _, configItem := range cfg.Configs.Core {
-		result, err := scanConfigSymlinks(configItem, dotfilesPath, home, true)
+		result, err := scanConfigSymlinks(configItem, dotfilesPath, home, cfg.Stow, true)
 		if err != nil {
 			continue
 		}
@@ -96,7 +96,7 @@ func ScanExistingSymlinks(cfg *config.Config, dotfilesPath string) (*AdoptSummar
 
 	// Process optional configs
 	for _, configItem := range cfg.Configs.Optional {
-		result, err := scanConfigSymlinks(configItem, dotfilesPath, home, false)
+		result, err := scanConfigSymlinks(configItem, dotfilesPath, home, cfg.Stow, false)
 		if err != nil {
 			continue
 		}
@@ -119,8 +119,8 @@ func ScanExistingSymlinks(cfg *config.Config, dotfilesPath string) (*AdoptSummar
 }
 
 // scanConfigSymlinks checks a single config for existing symlinks
-func scanConfigSymlinks(configItem config.ConfigItem, dotfilesPath, home string, isCore bool) (*AdoptResult, error) {
-	configPath := filepath.Join(dotfilesPath, configItem.Path)
+func scanConfigSymlinks(configItem config.ConfigItem, dotfilesPath, home string, settings config.StowSettings, isCore bool) (*AdoptResult, error) {
+	configPath := configItem.Dir(dotfilesPath)
 
 	result := &AdoptResult{
 		ConfigName: configItem.Name,
@@ -146,7 +146,7 @@ func scanConfigSymlinks(configItem config.ConfigItem, dotfilesPath, home string,
 
 		// Calculate expected target path in home
 		relPath, _ := filepath.Rel(configPath, path)
-		targetPath := filepath.Join(home, relPath)
+		targetPath := filepath.Join(home, settings.LinkPath(relPath))
 
 		// Check if the symlink exists and is correct
 		if isCorrectlyLinked(path, targetPath) {
@@ -225,8 +225,8 @@ func AdoptExistingSymlinks(cfg *config.Config, dotfilesPath string, st *state.St
 	return summary, nil
 }
 
-// GetConfigLinkStatus returns the link status for a single config
-func GetConfigLinkStatus(configItem config.ConfigItem, dotfilesPath string) (*AdoptResult, error) {
-	home := os.Getenv("HOME")
-	return scanConfigSymlinks(configItem, dotfilesPath, home, false)
+// GetConfigLinkStatus returns the link status for a single config, linked
+// as the config's stow settings say
+func GetConfigLinkStatus(configItem config.ConfigItem, dotfilesPath string, settings config.StowSettings) (*AdoptResult, error) {
+	return scanConfigSymlinks(configItem, dotfilesPath, settings.TargetDir(), settings, false)
 }
@@ -0,0 +1,215 @@
+package stow
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/nvandessel/go4dot/internal/config"
+	"github.com/nvandessel/go4dot/internal/fsutil"
+)
+
+// VariantsDir is the directory (relative to the dotfiles repo) where adopted
+// files kept as host-specific variants are stored. It sits outside every stow
+// package so variants are never linked into home.
+const VariantsDir = ".g4d-variants"
+
+// maxDiffLines caps the number of lines compared when building a diff preview
+const maxDiffLines = 2000
+
+// AdoptConflict moves a conflicting file from home into the dotfiles repo,
+// replacing the repo copy, so stow can link it back in place.
+// This mirrors `stow --adopt` for a single file.
+func AdoptConflict(conflict ConflictFile) error {
+	if conflict.IsDir {
+		return fmt.Errorf("cannot adopt directory %s", conflict.TargetPath)
+	}
+
+	if err := fsutil.ReplaceFile(conflict.TargetPath, conflict.SourcePath); err != nil {
+		return fmt.Errorf("failed to adopt %s: %w", conflict.TargetPath, err)
+	}
+
+	return os.Remove(conflict.TargetPath)
+}
+
+// AdoptConflictAsVariant stores a conflicting file in the repo under
+// VariantsDir/<variant>/ instead of replacing the repo copy, then removes it
+// from home so the repo version can be linked. Returns the stored path.
+func AdoptConflictAsVariant(conflict ConflictFile, dotfilesPath, variant string) (string, error) {
+	if conflict.IsDir {
+		return "", fmt.Errorf("cannot adopt directory %s", conflict.TargetPath)
+	}
+	if variant == "" || variant != filepath.Base(variant) || variant == "." || variant == ".." {
+		return "", fmt.Errorf("invalid variant name %q", variant)
+	}
+
+	relPath, err := filepath.Rel(dotfilesPath, conflict.SourcePath)
+	if err != nil || strings.HasPrefix(relPath, "..") {
+		return "", fmt.Errorf("source %s is outside the dotfiles directory", conflict.SourcePath)
+	}
+
+	dest := filepath.Join(dotfilesPath, VariantsDir, variant, relPath)
+	if err := os.MkdirAll(filepath.Dir(dest), 0755); err != nil {
+		return "", fmt.Errorf("failed to create variant directory: %w", err)
+	}
+
+	if err := fsutil.ReplaceFile(conflict.TargetPath, dest); err != nil {
+		return "", fmt.Errorf("failed to store variant of %s: %w", conflict.TargetPath, err)
+	}
+
+	if err := os.Remove(conflict.TargetPath); err != nil {
+		return dest, err
+	}
+
+	return dest, nil
+}
+
+// AdoptConflicts adopts every conflict into the repo without prompting.
+// Directories are skipped since stow cannot link over them file by file.
+func AdoptConflicts(conflicts []ConflictFile, opts StowOptions) error {
+	for _, conflict := range conflicts {
+		if conflict.IsDir {
+			if opts.ProgressFunc != nil {
+				opts.ProgressFunc(0, 0, fmt.Sprintf("⊘ Skipped %s (directory)", conflict.TargetPath))
+			}
+			continue
+		}
+
+		if opts.DryRun {
+			if opts.ProgressFunc != nil {
+				opts.ProgressFunc(0, 0, fmt.Sprintf("Would adopt %s", conflict.TargetPath))
+			}
+			continue
+		}
+
+		if err := AdoptConflict(conflict); err != nil {
+			return err
+		}
+		if opts.ProgressFunc != nil {
+			opts.ProgressFunc(0, 0, fmt.Sprintf("Adopted %s", conflict.TargetPath))
+		}
+	}
+	return nil
+}
+
+// ConflictsForConfigs returns only the conflicts belonging to the given configs
+func ConflictsForConfigs(conflicts []ConflictFile, configs []config.ConfigItem) []ConflictFile {
+	names := make(map[string]bool, len(configs))
+	for _, c := range configs {
+		names[c.Name] = true
+	}
+
+	var filtered []ConflictFile
+	for _, c := range conflicts {
+		if names[c.ConfigName] {
+			filtered = append(filtered, c)
+		}
+	}
+	return filtered
+}
+
+// ConflictIdentical reports whether the file in home has the same content as
+// the repo copy, in which case adopting it changes nothing in the repo.
+func ConflictIdentical(conflict ConflictFile) bool {
+	if conflict.IsDir {
+		return false
+	}
+	if _, err := os.Stat(conflict.TargetPath); err != nil {
+		return false
+	}
+	if _, err := os.Stat(conflict.SourcePath); err != nil {
+		return false
+	}
+	return !hasContentDrift(conflict.SourcePath, conflict.TargetPath)
+}
+
+// ConflictDiff returns a line diff from the repo copy to the file in home.
+// Lines are prefixed with "-" (only in repo), "+" (only in home) or " "
+// (unchanged). Files that are binary or too large yield a single note line.
+func ConflictDiff(conflict ConflictFile) ([]string, error) {
+	if conflict.IsDir {
+		return []string{"(directory)"}, nil
+	}
+
+	repo, err := os.ReadFile(conflict.SourcePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", conflict.SourcePath, err)
+	}
+	home, err := os.ReadFile(conflict.TargetPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", conflict.TargetPath, err)
+	}
+
+	if isBinary(repo) || isBinary(home) {
+		return []string{"(binary files differ)"}, nil
+	}
+
+	a := splitLines(string(repo))
+	b := splitLines(string(home))
+	if len(a) > maxDiffLines || len(b) > maxDiffLines {
+		return []string{"(files too large to preview)"}, nil
+	}
+
+	return lineDiff(a, b), nil
+}
+
+// lineDiff computes a minimal line diff using the longest common subsequence
+func lineDiff(a, b []string) []string {
+	// lcs[i][j] is the LCS length of a[i:] and b[j:]
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	var out []string
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			out = append(out, " "+a[i])
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			out = append(out, "-"+a[i])
+			i++
+		default:
+			out = append(out, "+"+b[j])
+			j++
+		}
+	}
+	for ; i < len(a); i++ {
+		out = append(out, "-"+a[i])
+	}
+	for ; j < len(b); j++ {
+		out = append(out, "+"+b[j])
+	}
+	return out
+}
+
+func splitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(s, "\n"), "\n")
+}
+
+func isBinary(data []byte) bool {
+	for _, c := range data {
+		if c == 0 {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,130 @@
+package stow
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/nvandessel/go4dot/internal/config"
+)
+
+func TestLineDiff(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b []string
+		want []string
+	}{
+		{
+			name: "identical",
+			a:    []string{"x", "y"},
+			b:    []string{"x", "y"},
+			want: []string{" x", " y"},
+		},
+		{
+			name: "changed line",
+			a:    []string{"x", "y", "z"},
+			b:    []string{"x", "Y", "z"},
+			want: []string{" x", "-y", "+Y", " z"},
+		},
+		{
+			name: "added and removed",
+			a:    []string{"a", "b"},
+			b:    []string{"b", "c"},
+			want: []string{"-a", " b", "+c"},
+		},
+		{
+			name: "empty repo copy",
+			a:    nil,
+			b:    []string{"new"},
+			want: []string{"+new"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := lineDiff(tt.a, tt.b); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("lineDiff() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func setupAdoptConflict(t *testing.T, repo, home string) (ConflictFile, string) {
+	t.Helper()
+	tmpDir := t.TempDir()
+	dotfilesDir := filepath.Join(tmpDir, "dotfiles")
+	src := filepath.Join(dotfilesDir, "zsh", ".zshrc")
+	dst := filepath.Join(tmpDir, "home", ".zshrc")
+
+	for path, content := range map[string]string{src: repo, dst: home} {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return ConflictFile{ConfigName: "zsh", SourcePath: src, TargetPath: dst}, dotfilesDir
+}
+
+func TestAdoptConflict(t *testing.T) {
+	conflict, _ := setupAdoptConflict(t, "repo\n", "home\n")
+
+	if ConflictIdentical(conflict) {
+		t.Error("ConflictIdentical() = true for different content")
+	}
+
+	diff, err := ConflictDiff(conflict)
+	if err != nil {
+		t.Fatalf("ConflictDiff() error = %v", err)
+	}
+	if want := []string{"-repo", "+home"}; !reflect.DeepEqual(diff, want) {
+		t.Errorf("ConflictDiff() = %q, want %q", diff, want)
+	}
+
+	if err := AdoptConflict(conflict); err != nil {
+		t.Fatalf("AdoptConflict() error = %v", err)
+	}
+	if data, _ := os.ReadFile(conflict.SourcePath); string(data) != "home\n" {
+		t.Errorf("repo copy = %q, want adopted content", data)
+	}
+	if _, err := os.Lstat(conflict.TargetPath); !os.IsNotExist(err) {
+		t.Error("AdoptConflict() should remove the file from home")
+	}
+}
+
+func TestAdoptConflictAsVariant(t *testing.T) {
+	conflict, dotfilesDir := setupAdoptConflict(t, "repo\n", "home\n")
+
+	if _, err := AdoptConflictAsVariant(conflict, dotfilesDir, "../escape"); err == nil {
+		t.Error("AdoptConflictAsVariant() should reject variant names with separators")
+	}
+
+	dest, err := AdoptConflictAsVariant(conflict, dotfilesDir, "laptop")
+	if err != nil {
+		t.Fatalf("AdoptConflictAsVariant() error = %v", err)
+	}
+	if want := filepath.Join(dotfilesDir, VariantsDir, "laptop", "zsh", ".zshrc"); dest != want {
+		t.Errorf("dest = %s, want %s", dest, want)
+	}
+	if data, _ := os.ReadFile(dest); string(data) != "home\n" {
+		t.Errorf("variant content = %q", data)
+	}
+	if data, _ := os.ReadFile(conflict.SourcePath); string(data) != "repo\n" {
+		t.Errorf("repo copy should be unchanged, got %q", data)
+	}
+}
+
+func TestConflictsForConfigs(t *testing.T) {
+	conflicts := []ConflictFile{
+		{ConfigName: "zsh", TargetPath: "/h/.zshrc"},
+		{ConfigName: "git", TargetPath: "/h/.gitconfig"},
+	}
+
+	got := ConflictsForConfigs(conflicts, []config.ConfigItem{{Name: "git"}})
+	if len(got) != 1 || got[0].ConfigName != "git" {
+		t.Errorf("ConflictsForConfigs() = %+v", got)
+	}
+}
@@ -0,0 +1,92 @@
+package stow
+
+import (
+	"fmt"
+
+	"github.com/nvandessel/go4dot/internal/config"
+)
+
+// StrategyResult reports what ApplyConflictStrategies did
+type StrategyResult struct {
+	Resolved []ConflictFile // Backed up or overwritten, so the config can be linked
+	Skipped  []string       // Configs whose strategy is skip; their files are left in place
+	Pending  []ConflictFile // Conflicts of configs set to ask, still to be resolved
+}
+
+// ApplyConflictStrategies resolves conflicts with each config's on_conflict
+// strategy. Conflicts of configs set to ask (the default) are returned as
+// pending, as are those of configs not in configs.
+func ApplyConflictStrategies(conflicts []ConflictFile, configs []config.ConfigItem, opts StowOptions) (*StrategyResult, error) {
+	strategies := make(map[string]string, len(configs))
+	for _, c := range configs {
+		strategies[c.Name] = c.ConflictStrategy()
+	}
+
+	result := &StrategyResult{}
+	skipped := make(map[string]bool)
+	for _, conflict := range conflicts {
+		strategy, ok := strategies[conflict.ConfigName]
+		if !ok {
+			strategy = config.ConflictAsk
+		}
+
+		var err error
+		switch strategy {
+		case config.ConflictBackup:
+			err = BackupConflict(conflict)
+		case config.ConflictOverwrite:
+			if opts.UseTrash {
+				err = TrashConflict(conflict)
+			} else {
+				err = RemoveConflict(conflict)
+			}
+		case config.ConflictSkip:
+			if !skipped[conflict.ConfigName] {
+				skipped[conflict.ConfigName] = true
+				result.Skipped = append(result.Skipped, conflict.ConfigName)
+			}
+			continue
+		default:
+			result.Pending = append(result.Pending, conflict)
+			continue
+		}
+		if err != nil {
+			return result, fmt.Errorf("%s %s: %w", strategy, conflict.TargetPath, err)
+		}
+		result.Resolved = append(result.Resolved, conflict)
+
+		if opts.ProgressFunc != nil {
+			verb := "Backed up"
+			if strategy == config.ConflictOverwrite {
+				verb = "Overwrote"
+			}
+			opts.ProgressFunc(0, 0, fmt.Sprintf("%s %s (on_conflict: %s)", verb, conflict.TargetPath, strategy))
+		}
+	}
+	return result, nil
+}
+
+// CommonConflictStrategy returns the strategy shared by the configs of all
+// conflicts, or ConflictAsk when they differ
+func CommonConflictStrategy(conflicts []ConflictFile, configs []config.ConfigItem) string {
+	strategies := make(map[string]string, len(configs))
+	for _, c := range configs {
+		strategies[c.Name] = c.ConflictStrategy()
+	}
+
+	common := ""
+	for _, conflict := range conflicts {
+		strategy, ok := strategies[conflict.ConfigName]
+		if !ok {
+			return config.ConflictAsk
+		}
+		if common != "" && strategy != common {
+			return config.ConflictAsk
+		}
+		common = strategy
+	}
+	if common == "" {
+		return config.ConflictAsk
+	}
+	return common
+}
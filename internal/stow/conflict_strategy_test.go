@@ -0,0 +1,143 @@
+package stow
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/nvandessel/go4dot/internal/config"
+	"github.com/nvandessel/go4dot/internal/state"
+)
+
+// writeTestFile writes content to path, creating its directory
+func writeTestFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestApplyConflictStrategies(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	configs := []config.ConfigItem{
+		{Name: "nvim", OnConflict: "overwrite"},
+		{Name: "ssh", OnConflict: "backup"},
+		{Name: "zsh", OnConflict: "skip"},
+		{Name: "git"},
+	}
+
+	var conflicts []ConflictFile
+	for _, name := range []string{"nvim", "ssh", "zsh", "git", "tmux"} {
+		target := filepath.Join(home, "."+name+"rc")
+		writeTestFile(t, target, "existing\n")
+		conflicts = append(conflicts, ConflictFile{ConfigName: name, TargetPath: target})
+	}
+
+	result, err := ApplyConflictStrategies(conflicts, configs, StowOptions{})
+	if err != nil {
+		t.Fatalf("ApplyConflictStrategies failed: %v", err)
+	}
+
+	if len(result.Resolved) != 2 {
+		t.Errorf("Resolved = %+v, want nvim and ssh", result.Resolved)
+	}
+	if !reflect.DeepEqual(result.Skipped, []string{"zsh"}) {
+		t.Errorf("Skipped = %v, want [zsh]", result.Skipped)
+	}
+	if len(result.Pending) != 2 || result.Pending[0].ConfigName != "git" || result.Pending[1].ConfigName != "tmux" {
+		t.Errorf("Pending = %+v, want git and tmux", result.Pending)
+	}
+
+	for _, tt := range []struct {
+		path   string
+		exists bool
+	}{
+		{".nvimrc", false},
+		{".sshrc", false},
+		{".sshrc.g4d-backup", true},
+		{".zshrc", true},
+		{".gitrc", true},
+	} {
+		_, err := os.Stat(filepath.Join(home, tt.path))
+		if exists := err == nil; exists != tt.exists {
+			t.Errorf("%s exists = %v, want %v", tt.path, exists, tt.exists)
+		}
+	}
+}
+
+func TestCommonConflictStrategy(t *testing.T) {
+	configs := []config.ConfigItem{
+		{Name: "nvim", OnConflict: "overwrite"},
+		{Name: "helix", OnConflict: "Overwrite"},
+		{Name: "ssh", OnConflict: "backup"},
+	}
+
+	tests := []struct {
+		name    string
+		configs []string
+		want    string
+	}{
+		{"shared", []string{"nvim", "helix"}, config.ConflictOverwrite},
+		{"mixed", []string{"nvim", "ssh"}, config.ConflictAsk},
+		{"unknown config", []string{"nvim", "tmux"}, config.ConflictAsk},
+		{"none", nil, config.ConflictAsk},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var conflicts []ConflictFile
+			for _, name := range tt.configs {
+				conflicts = append(conflicts, ConflictFile{ConfigName: name})
+			}
+			if got := CommonConflictStrategy(conflicts, configs); got != tt.want {
+				t.Errorf("CommonConflictStrategy() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSyncAll_ConflictStrategies(t *testing.T) {
+	dotfilesPath, homeDir, cleanup := setupSyncTestEnv(t)
+	defer cleanup()
+
+	writeTestFile(t, filepath.Join(dotfilesPath, "ssh", ".ssh", "config"), "Host *\n")
+	writeTestFile(t, filepath.Join(dotfilesPath, "zsh", ".zshrc"), "export EDITOR=nvim\n")
+	writeTestFile(t, filepath.Join(homeDir, ".ssh", "config"), "Host old\n")
+	writeTestFile(t, filepath.Join(homeDir, ".zshrc"), "# local\n")
+
+	cfg := &config.Config{
+		Configs: config.ConfigGroups{
+			Core: []config.ConfigItem{
+				{Name: "ssh", Path: "ssh", OnConflict: "backup"},
+				{Name: "zsh", Path: "zsh", OnConflict: "skip"},
+			},
+		},
+	}
+	st := state.New()
+
+	result, err := SyncAll(dotfilesPath, cfg, st, false, StowOptions{})
+	if err != nil {
+		t.Fatalf("SyncAll failed: %v", err)
+	}
+
+	if !reflect.DeepEqual(result.Success, []string{"ssh"}) || !reflect.DeepEqual(result.Skipped, []string{"zsh"}) {
+		t.Errorf("Success = %v, Skipped = %v; want [ssh], [zsh]", result.Success, result.Skipped)
+	}
+	if info, err := os.Lstat(filepath.Join(homeDir, ".ssh", "config")); err != nil || info.Mode()&os.ModeSymlink == 0 {
+		t.Error("expected ssh to be linked after backing up the existing file")
+	}
+	if _, err := os.Stat(filepath.Join(homeDir, ".ssh", "config.g4d-backup")); err != nil {
+		t.Errorf("expected a backup of the existing file: %v", err)
+	}
+	if data, err := os.ReadFile(filepath.Join(homeDir, ".zshrc")); err != nil || string(data) != "# local\n" {
+		t.Error("expected the skipped config's file to be left in place")
+	}
+	if st.HasConfig("zsh") {
+		t.Error("expected the skipped config not to be recorded as installed")
+	}
+}
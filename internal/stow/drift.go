@@ -7,7 +7,9 @@ import (
 	"path/filepath"
 
 	"github.com/nvandessel/go4dot/internal/config"
+	"github.com/nvandessel/go4dot/internal/snapshot"
 	"github.com/nvandessel/go4dot/internal/state"
+	"github.com/nvandessel/go4dot/internal/trash"
 )
 
 // DriftResult represents the drift status for a single config.
@@ -22,6 +24,7 @@ type DriftResult struct {
 	ConflictFiles     []string // Files that exist in home but aren't symlinks
 	ContentDriftFiles []string // Conflict files where dest content differs from source
 	OrphanFiles       []string // Files in dest managed dirs not tracked by source
+	LinkErrors        []string // Links on the way to a target that loop or run too deep, with the links involved
 }
 
 // DriftSummary provides an overview of drift across all configs.
@@ -74,7 +77,7 @@ func (s *DriftSummary) ResultByName(configName string) *DriftResult {
 // It identifies exactly which files are new, missing, or in conflict by comparing
 // the dotfiles directory with the user's home directory.
 func FullDriftCheck(cfg *config.Config, dotfilesPath string) (*DriftSummary, error) {
-	home := os.Getenv("HOME")
+	home := cfg.Stow.TargetDir()
 	st, err := state.Load()
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Warning: failed to load state: %v\n", err)
@@ -88,9 +91,11 @@ func FullDriftCheck(cfg *config.Config, dotfilesPath string) (*DriftSummary, err
 func FullDriftCheckWithHome(cfg *config.Config, dotfilesPath, home string, st *state.State) (*DriftSummary, error) {
 	var results []DriftResult
 
+	repoIgnore, _ := config.LoadRepoIgnore(dotfilesPath)
+
 	allConfigs := cfg.GetAllConfigs()
 	for _, configItem := range allConfigs {
-		configPath := filepath.Join(dotfilesPath, configItem.Path)
+		configPath := configItem.Dir(dotfilesPath)
 
 		result := DriftResult{
 			ConfigName: configItem.Name,
@@ -107,18 +112,26 @@ func FullDriftCheckWithHome(cfg *config.Config, dotfilesPath, home string, st *s
 			if err != nil {
 				return nil // Skip on error
 			}
-			if info.IsDir() {
-				return nil // Skip directories
-			}
-
-			result.CurrentCount++
 
 			// Calculate expected target path in home
 			relPath, err := filepath.Rel(configPath, path)
 			if err != nil {
 				return nil // Skip this file if we can't compute relative path
 			}
-			targetPath := filepath.Join(home, relPath)
+
+			// Hide whatever .go4dotignore excludes
+			if relPath != "." && repoIgnore.Match(filepath.Join(configItem.Path, relPath), info.IsDir()) {
+				if info.IsDir() {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			if info.IsDir() {
+				return nil // Skip directories
+			}
+
+			result.CurrentCount++
+			targetPath := filepath.Join(home, cfg.Stow.LinkPath(relPath))
 
 			// Check target status
 			targetInfo, err := os.Lstat(targetPath)
@@ -129,6 +142,9 @@ func FullDriftCheckWithHome(cfg *config.Config, dotfilesPath, home string, st *s
 			}
 
 			if err != nil {
+				if linkErr := CheckLinkChain(targetPath); linkErr != nil {
+					result.LinkErrors = append(result.LinkErrors, linkErr.Error())
+				}
 				return nil // Skip on other errors
 			}
 
@@ -163,6 +179,10 @@ func FullDriftCheckWithHome(cfg *config.Config, dotfilesPath, home string, st *s
 
 			// If symlink points to wrong location, count as conflict
 			if linkDest != path {
+				if linkErr := CheckLinkChain(targetPath); linkErr != nil {
+					result.LinkErrors = append(result.LinkErrors, linkErr.Error())
+					return nil
+				}
 				result.ConflictFiles = append(result.ConflictFiles, relPath)
 				if hasContentDrift(path, targetPath) {
 					result.ContentDriftFiles = append(result.ContentDriftFiles, relPath)
@@ -179,10 +199,11 @@ func FullDriftCheckWithHome(cfg *config.Config, dotfilesPath, home string, st *s
 		// Check for symlinks in home that point to deleted files in dotfiles
 		// We can do this by walking the target directories that we know about
 		// from the current config structure.
-		result.MissingFiles = findOrphanedSymlinks(configPath, home)
-		result.OrphanFiles = findOrphanFiles(configPath, home)
+		result.MissingFiles = findOrphanedSymlinks(configPath, home, cfg.Stow)
+		result.OrphanFiles = findOrphanFiles(configPath, home, cfg.Stow)
 
-		result.HasDrift = len(result.NewFiles) > 0 || len(result.ConflictFiles) > 0 || len(result.MissingFiles) > 0
+		result.HasDrift = len(result.NewFiles) > 0 || len(result.ConflictFiles) > 0 || len(result.MissingFiles) > 0 ||
+			len(result.LinkErrors) > 0
 		results = append(results, result)
 	}
 
@@ -273,11 +294,11 @@ type ConflictFile struct {
 // DetectConflicts checks for existing files in home that would block stow.
 func DetectConflicts(cfg *config.Config, dotfilesPath string) ([]ConflictFile, error) {
 	var conflicts []ConflictFile
-	home := os.Getenv("HOME")
+	home := cfg.Stow.TargetDir()
 
 	allConfigs := cfg.GetAllConfigs()
 	for _, configItem := range allConfigs {
-		configPath := filepath.Join(dotfilesPath, configItem.Path)
+		configPath := configItem.Dir(dotfilesPath)
 
 		// Check if config directory exists
 		if _, err := os.Stat(configPath); os.IsNotExist(err) {
@@ -295,7 +316,7 @@ func DetectConflicts(cfg *config.Config, dotfilesPath string) ([]ConflictFile, e
 
 			// Calculate expected target path in home
 			relPath, _ := filepath.Rel(configPath, path)
-			targetPath := filepath.Join(home, relPath)
+			targetPath := filepath.Join(home, cfg.Stow.LinkPath(relPath))
 
 			// Check if target exists
 			targetInfo, err := os.Lstat(targetPath)
@@ -348,7 +369,8 @@ func DetectConflicts(cfg *config.Config, dotfilesPath string) ([]ConflictFile, e
 	return conflicts, nil
 }
 
-// BackupConflict moves a conflicting file to a backup location.
+// BackupConflict moves a conflicting file to a backup location. It is
+// recorded so UndoConflictResolution can move it back.
 func BackupConflict(conflict ConflictFile) error {
 	backupPath := conflict.TargetPath + ".g4d-backup"
 
@@ -357,11 +379,43 @@ func BackupConflict(conflict ConflictFile) error {
 		backupPath = fmt.Sprintf("%s.g4d-backup-%d", conflict.TargetPath, os.Getpid())
 	}
 
-	return os.Rename(conflict.TargetPath, backupPath)
+	if err := os.Rename(conflict.TargetPath, backupPath); err != nil {
+		return err
+	}
+	return recordUndo(UndoEntry{ConfigName: conflict.ConfigName, TargetPath: conflict.TargetPath, Action: UndoBackup, SavedPath: backupPath})
 }
 
-// RemoveConflict deletes a conflicting file.
+// TrashConflict moves a conflicting file or directory to the OS trash. It is
+// recorded so UndoConflictResolution can restore it.
+func TrashConflict(conflict ConflictFile) error {
+	t, err := trash.New()
+	if err != nil {
+		return err
+	}
+	item, err := t.Put(conflict.TargetPath)
+	if err != nil {
+		return err
+	}
+	return recordUndo(UndoEntry{
+		ConfigName: conflict.ConfigName,
+		TargetPath: conflict.TargetPath,
+		Action:     UndoTrash,
+		SavedPath:  filepath.Join(t.FilesDir, item.Name),
+		TrashName:  item.Name,
+	})
+}
+
+// RemoveConflict deletes a conflicting file. It is kept in the undo stash
+// until the next conflict resolution, so UndoConflictResolution can restore
+// it; when it can't be moved there it is deleted outright.
 func RemoveConflict(conflict ConflictFile) error {
+	if err := snapshot.Save(conflict.TargetPath); err != nil {
+		return err
+	}
+	saved, err := stashConflict(conflict)
+	if err == nil {
+		return recordUndo(UndoEntry{ConfigName: conflict.ConfigName, TargetPath: conflict.TargetPath, Action: UndoDelete, SavedPath: saved})
+	}
 	if conflict.IsDir {
 		return os.RemoveAll(conflict.TargetPath)
 	}
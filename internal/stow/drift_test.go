@@ -108,6 +108,84 @@ func TestFullDriftCheck(t *testing.T) {
 	}
 }
 
+func TestFullDriftCheck_RepoIgnore(t *testing.T) {
+	tmpDir := t.TempDir()
+	home := t.TempDir()
+	for _, f := range []string{"test/.testrc", "test/scratch/notes.txt", "test/.testrc.tmp"} {
+		path := filepath.Join(tmpDir, f)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte("test"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(tmpDir, config.IgnoreFileName), []byte("test/scratch/\n*.tmp\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := &config.Config{
+		Configs: config.ConfigGroups{
+			Core: []config.ConfigItem{
+				{Name: "test", Path: "test"},
+			},
+		},
+	}
+
+	summary, err := FullDriftCheckWithHome(cfg, tmpDir, home, nil)
+	if err != nil {
+		t.Fatalf("FullDriftCheckWithHome failed: %v", err)
+	}
+
+	result := summary.Results[0]
+	if result.CurrentCount != 1 {
+		t.Errorf("Expected 1 file, got %d", result.CurrentCount)
+	}
+	if len(result.NewFiles) != 1 || result.NewFiles[0] != ".testrc" {
+		t.Errorf("Expected only .testrc as new, got %v", result.NewFiles)
+	}
+}
+
+func TestFullDriftCheck_DotfilesPrefix(t *testing.T) {
+	dotfiles := t.TempDir()
+	home := t.TempDir()
+	for _, f := range []string{"shell/dot-bashrc", "shell/dot-config/fish/config.fish"} {
+		path := filepath.Join(dotfiles, f)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte("test"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// dot-bashrc is linked as stow --dotfiles would
+	if err := os.Symlink(filepath.Join(dotfiles, "shell", "dot-bashrc"), filepath.Join(home, ".bashrc")); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := &config.Config{
+		Configs: config.ConfigGroups{Core: []config.ConfigItem{{Name: "shell", Path: "shell"}}},
+		Stow:    config.StowSettings{Flags: []string{"--dotfiles"}},
+	}
+	summary, err := FullDriftCheckWithHome(cfg, dotfiles, home, nil)
+	if err != nil {
+		t.Fatalf("FullDriftCheckWithHome failed: %v", err)
+	}
+	result := summary.Results[0]
+	want := filepath.Join("dot-config", "fish", "config.fish")
+	if len(result.NewFiles) != 1 || result.NewFiles[0] != want {
+		t.Errorf("NewFiles = %v, want only %s (dot-bashrc is linked as .bashrc)", result.NewFiles, want)
+	}
+
+	status, err := GetConfigLinkStatus(cfg.Configs.Core[0], dotfiles, config.StowSettings{Flags: []string{"--dotfiles"}, Target: home})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(status.LinkedFiles) != 1 || status.LinkedFiles[0] != "dot-bashrc" {
+		t.Errorf("LinkedFiles = %v, want [dot-bashrc]", status.LinkedFiles)
+	}
+}
+
 func TestGetDriftedConfigs(t *testing.T) {
 	results := []DriftResult{
 		{ConfigName: "a", HasDrift: true},
@@ -0,0 +1,215 @@
+package stow
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+const (
+	// LocalIgnoreFile is the per-package ignore list read by GNU stow
+	LocalIgnoreFile = ".stow-local-ignore"
+	// GlobalIgnoreFile is the per-user ignore list in $HOME read by GNU stow
+	GlobalIgnoreFile = ".stow-global-ignore"
+)
+
+// defaultIgnorePatterns mirrors the built-in ignore list of GNU stow, used
+// when neither a local nor a global ignore file exists.
+var defaultIgnorePatterns = []string{
+	`RCS`,
+	`.+,v`,
+	`CVS`,
+	`\.\#.+`,
+	`\.cvsignore`,
+	`\.svn`,
+	`_darcs`,
+	`\.hg`,
+	`\.git`,
+	`\.gitignore`,
+	`\.gitmodules`,
+	`.+~`,
+	`\#.*\#`,
+	`^/README.*`,
+	`^/LICENSE.*`,
+	`^/COPYING`,
+}
+
+// IgnoreList decides which files in a stow package are skipped by stow
+type IgnoreList struct {
+	path     *regexp.Regexp // Patterns containing "/", matched against "/"+relPath
+	basename *regexp.Regexp // Other patterns, matched against the file name
+}
+
+// LoadIgnoreList returns the ignore list stow would use for the package at
+// packageDir: .stow-local-ignore if present, then ~/.stow-global-ignore,
+// then the built-in defaults.
+func LoadIgnoreList(packageDir string) (*IgnoreList, error) {
+	candidates := []string{filepath.Join(packageDir, LocalIgnoreFile)}
+	if home, err := os.UserHomeDir(); err == nil {
+		candidates = append(candidates, filepath.Join(home, GlobalIgnoreFile))
+	}
+
+	for _, path := range candidates {
+		patterns, err := readIgnoreFile(path)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		return NewIgnoreList(patterns)
+	}
+
+	return NewIgnoreList(defaultIgnorePatterns)
+}
+
+// AddIgnorePatterns appends patterns to the package's .stow-local-ignore,
+// skipping ones already listed, and returns the patterns added. A local
+// ignore file replaces the global and built-in lists, so when it does not
+// exist yet it is seeded with the list stow currently uses.
+func AddIgnorePatterns(packageDir string, patterns []string) ([]string, error) {
+	path := filepath.Join(packageDir, LocalIgnoreFile)
+
+	existing, err := readIgnoreFile(path)
+	created := os.IsNotExist(err)
+	if err != nil && !created {
+		return nil, err
+	}
+
+	var lines []string
+	if created {
+		existing = defaultIgnorePatterns
+		if home, err := os.UserHomeDir(); err == nil {
+			if global, err := readIgnoreFile(filepath.Join(home, GlobalIgnoreFile)); err == nil {
+				existing = global
+			}
+		}
+		lines = append(lines, "# Patterns stow ignored before this file was created")
+		lines = append(lines, existing...)
+		lines = append(lines, "")
+	}
+
+	seen := make(map[string]bool, len(existing))
+	for _, p := range existing {
+		seen[p] = true
+	}
+
+	var added []string
+	for _, p := range patterns {
+		if seen[p] {
+			continue
+		}
+		if _, err := regexp.Compile(p); err != nil {
+			return nil, fmt.Errorf("invalid ignore pattern %q: %w", p, err)
+		}
+		seen[p] = true
+		added = append(added, p)
+	}
+	if len(added) == 0 {
+		return nil, nil
+	}
+	lines = append(lines, added...)
+
+	content := strings.Join(lines, "\n") + "\n"
+	if !created {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		if len(data) > 0 && data[len(data)-1] != '\n' {
+			content = "\n" + content
+		}
+	}
+
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	if _, err := f.WriteString(content); err != nil {
+		_ = f.Close()
+		return nil, fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	if err := f.Close(); err != nil {
+		return nil, fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return added, nil
+}
+
+// NewIgnoreList compiles stow-style ignore patterns
+func NewIgnoreList(patterns []string) (*IgnoreList, error) {
+	var pathPatterns, basePatterns []string
+	for _, p := range patterns {
+		if _, err := regexp.Compile(p); err != nil {
+			return nil, fmt.Errorf("invalid ignore pattern %q: %w", p, err)
+		}
+		if strings.Contains(p, "/") {
+			pathPatterns = append(pathPatterns, p)
+		} else {
+			basePatterns = append(basePatterns, p)
+		}
+	}
+
+	l := &IgnoreList{}
+	if len(pathPatterns) > 0 {
+		l.path = regexp.MustCompile(`(?:^|/)(?:` + strings.Join(pathPatterns, "|") + `)(?:/|$)`)
+	}
+	if len(basePatterns) > 0 {
+		l.basename = regexp.MustCompile(`^(?:` + strings.Join(basePatterns, "|") + `)$`)
+	}
+	return l, nil
+}
+
+// Match reports whether relPath (relative to the package root) is ignored.
+// An ignored directory hides everything below it.
+func (l *IgnoreList) Match(relPath string) bool {
+	if l == nil {
+		return false
+	}
+	relPath = filepath.ToSlash(relPath)
+	if l.path != nil && l.path.MatchString("/"+relPath) {
+		return true
+	}
+	return l.basename != nil && l.basename.MatchString(filepath.Base(relPath))
+}
+
+// readIgnoreFile reads patterns from a stow ignore file, dropping blank
+// lines and comments
+func readIgnoreFile(path string) ([]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = f.Close() }()
+
+	var patterns []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := stripIgnoreComment(scanner.Text())
+		if line != "" {
+			patterns = append(patterns, line)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	return patterns, nil
+}
+
+// stripIgnoreComment removes a trailing comment. As in stow, "#" starts a
+// comment unless it is escaped as "\#".
+func stripIgnoreComment(line string) string {
+	for i := 0; i < len(line); i++ {
+		if line[i] == '\\' {
+			i++
+			continue
+		}
+		if line[i] == '#' {
+			line = line[:i]
+			break
+		}
+	}
+	return strings.TrimSpace(line)
+}
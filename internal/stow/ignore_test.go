@@ -0,0 +1,145 @@
+package stow
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestIgnoreListDefaults(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	l, err := LoadIgnoreList(t.TempDir())
+	if err != nil {
+		t.Fatalf("LoadIgnoreList() error = %v", err)
+	}
+
+	tests := []struct {
+		path    string
+		ignored bool
+	}{
+		{".git", true},
+		{".git/config", false}, // Walkers skip the directory itself
+		{"README.md", true},
+		{"docs/README.md", false}, // ^/README only matches at the top level
+		{"LICENSE", true},
+		{".vimrc~", true},
+		{"#autosave#", true},
+		{".gitignore", true},
+		{".config/nvim/init.lua", false},
+		{".zshrc", false},
+	}
+
+	for _, tt := range tests {
+		if got := l.Match(tt.path); got != tt.ignored {
+			t.Errorf("Match(%q) = %v, want %v", tt.path, got, tt.ignored)
+		}
+	}
+}
+
+func TestIgnoreListLocalFile(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	dir := t.TempDir()
+
+	content := "# local rules\n\\.cache  # caches\n^/notes\\.txt\n\n\\#literal\n"
+	if err := os.WriteFile(filepath.Join(dir, LocalIgnoreFile), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	l, err := LoadIgnoreList(dir)
+	if err != nil {
+		t.Fatalf("LoadIgnoreList() error = %v", err)
+	}
+
+	tests := []struct {
+		path    string
+		ignored bool
+	}{
+		{".cache", true},
+		{".config/.cache", true},
+		{"notes.txt", true},
+		{"sub/notes.txt", false},
+		{"#literal", true},
+		{"README.md", false}, // Defaults no longer apply
+	}
+
+	for _, tt := range tests {
+		if got := l.Match(tt.path); got != tt.ignored {
+			t.Errorf("Match(%q) = %v, want %v", tt.path, got, tt.ignored)
+		}
+	}
+}
+
+func TestIgnoreListGlobalFile(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	if err := os.WriteFile(filepath.Join(home, GlobalIgnoreFile), []byte("secret.*\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	l, err := LoadIgnoreList(t.TempDir())
+	if err != nil {
+		t.Fatalf("LoadIgnoreList() error = %v", err)
+	}
+	if !l.Match("secret.key") {
+		t.Error("expected global ignore pattern to apply")
+	}
+	if l.Match(".git") {
+		t.Error("expected defaults to be replaced by the global file")
+	}
+}
+
+func TestNewIgnoreListInvalid(t *testing.T) {
+	if _, err := NewIgnoreList([]string{"("}); err == nil {
+		t.Error("expected error for invalid pattern")
+	}
+}
+
+func TestAddIgnorePatterns(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	dir := t.TempDir()
+
+	added, err := AddIgnorePatterns(dir, []string{`node_modules`, `\.git`})
+	if err != nil {
+		t.Fatalf("AddIgnorePatterns() error = %v", err)
+	}
+	// \.git is already part of the seeded defaults
+	if len(added) != 1 || added[0] != "node_modules" {
+		t.Errorf("added = %v, want [node_modules]", added)
+	}
+
+	l, err := LoadIgnoreList(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for path, ignored := range map[string]bool{
+		"node_modules": true,
+		".git":         true,
+		"README.md":    true,
+		".zshrc":       false,
+	} {
+		if got := l.Match(path); got != ignored {
+			t.Errorf("Match(%q) = %v, want %v", path, got, ignored)
+		}
+	}
+
+	// Existing files are appended to, even without a trailing newline
+	path := filepath.Join(dir, LocalIgnoreFile)
+	if err := os.WriteFile(path, []byte("keep"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := AddIgnorePatterns(dir, []string{`\.cache`, `keep`}); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := string(data); got != "keep\n\\.cache\n" {
+		t.Errorf("ignore file = %q, want %q", got, "keep\n\\.cache\n")
+	}
+
+	if _, err := AddIgnorePatterns(dir, []string{`(`}); err == nil {
+		t.Error("expected error for invalid pattern")
+	}
+}
@@ -0,0 +1,256 @@
+package stow
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// ErrJSONCTarget is returned when merging would rewrite a target with
+// comments or trailing commas, which the rewrite can't keep
+var ErrJSONCTarget = errors.New("target has comments or trailing commas that merging would drop; remove them, or copy the whole file by dropping merge: json")
+
+// jsonObject is a JSON object that keeps its keys in order, so merging
+// into a settings file doesn't reorder it
+type jsonObject struct {
+	keys   []string
+	values map[string]any
+}
+
+// mergeJSON sets the keys of managed, a JSON object, in existing: nested
+// objects are merged key by key, anything else (arrays included) replaces
+// what existing has. Keys only existing has are kept, in their order.
+// Both may have comments and trailing commas, as VS Code and Windows
+// Terminal settings do. changed is false when existing already has every
+// managed value, so it needn't be rewritten; a rewrite would drop the
+// comments of existing, so that is refused with ErrJSONCTarget.
+func mergeJSON(existing, managed []byte) (merged []byte, changed bool, err error) {
+	src, err := parseJSONC(managed)
+	if err != nil {
+		return nil, false, fmt.Errorf("source is not valid JSON: %w", err)
+	}
+	if _, ok := src.(*jsonObject); !ok {
+		return nil, false, fmt.Errorf("source must be a JSON object to merge")
+	}
+
+	var dst any = &jsonObject{values: make(map[string]any)}
+	if len(bytes.TrimSpace(stripJSONC(existing))) > 0 {
+		if dst, err = parseJSONC(existing); err != nil {
+			return nil, false, fmt.Errorf("target is not valid JSON: %w", err)
+		}
+		if _, ok := dst.(*jsonObject); !ok {
+			return nil, false, fmt.Errorf("target is not a JSON object")
+		}
+	}
+
+	indent := detectIndent(existing)
+	before := encodeJSON(dst, indent)
+	after := encodeJSON(mergeJSONValue(dst, src), indent)
+	if bytes.Equal(before, after) {
+		return after, false, nil
+	}
+	if !bytes.Equal(stripJSONC(existing), existing) {
+		return nil, false, ErrJSONCTarget
+	}
+	return after, true, nil
+}
+
+// mergeJSONValue merges src into dst, changing dst
+func mergeJSONValue(dst, src any) any {
+	d, dOK := dst.(*jsonObject)
+	s, sOK := src.(*jsonObject)
+	if !dOK || !sOK {
+		return src
+	}
+	for _, key := range s.keys {
+		old, ok := d.values[key]
+		if !ok {
+			d.keys = append(d.keys, key)
+		}
+		d.values[key] = mergeJSONValue(old, s.values[key])
+	}
+	return d
+}
+
+// parseJSONC parses JSON that may have comments and trailing commas
+func parseJSONC(data []byte) (any, error) {
+	dec := json.NewDecoder(bytes.NewReader(stripJSONC(data)))
+	dec.UseNumber()
+	v, err := decodeJSONValue(dec)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := dec.Token(); !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("unexpected data after the top-level value")
+	}
+	return v, nil
+}
+
+// decodeJSONValue decodes the next value, keeping the key order of objects
+func decodeJSONValue(dec *json.Decoder) (any, error) {
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+	delim, ok := tok.(json.Delim)
+	if !ok {
+		return tok, nil
+	}
+
+	switch delim {
+	case '{':
+		obj := &jsonObject{values: make(map[string]any)}
+		for dec.More() {
+			keyTok, err := dec.Token()
+			if err != nil {
+				return nil, err
+			}
+			key, _ := keyTok.(string)
+			value, err := decodeJSONValue(dec)
+			if err != nil {
+				return nil, err
+			}
+			if _, dup := obj.values[key]; !dup {
+				obj.keys = append(obj.keys, key)
+			}
+			obj.values[key] = value
+		}
+		_, err := dec.Token()
+		return obj, err
+	case '[':
+		arr := []any{}
+		for dec.More() {
+			value, err := decodeJSONValue(dec)
+			if err != nil {
+				return nil, err
+			}
+			arr = append(arr, value)
+		}
+		_, err := dec.Token()
+		return arr, err
+	}
+	return nil, fmt.Errorf("unexpected %v", delim)
+}
+
+// stripJSONC removes // and /* */ comments and trailing commas outside of
+// strings
+func stripJSONC(data []byte) []byte {
+	var out []byte
+	inString, escaped := false, false
+	for i := 0; i < len(data); i++ {
+		c := data[i]
+		if inString {
+			out = append(out, c)
+			switch {
+			case escaped:
+				escaped = false
+			case c == '\\':
+				escaped = true
+			case c == '"':
+				inString = false
+			}
+			continue
+		}
+
+		switch {
+		case c == '"':
+			inString = true
+		case c == '/' && i+1 < len(data) && data[i+1] == '/':
+			for i < len(data) && data[i] != '\n' {
+				i++
+			}
+			if i < len(data) {
+				out = append(out, '\n')
+			}
+			continue
+		case c == '/' && i+1 < len(data) && data[i+1] == '*':
+			end := bytes.Index(data[i+2:], []byte("*/"))
+			if end < 0 {
+				return out
+			}
+			i += end + 3
+			continue
+		case c == '}' || c == ']':
+			// Drop a trailing comma before the closing bracket
+			trimmed := bytes.TrimRight(out, " \t\r\n")
+			if len(trimmed) > 0 && trimmed[len(trimmed)-1] == ',' {
+				out = append(trimmed[:len(trimmed)-1], out[len(trimmed):]...)
+			}
+		}
+		out = append(out, c)
+	}
+	return out
+}
+
+// detectIndent returns the indentation of the first indented line of data,
+// or four spaces, the indentation VS Code and Windows Terminal write
+func detectIndent(data []byte) string {
+	for _, line := range strings.Split(string(data), "\n") {
+		trimmed := strings.TrimLeft(line, " \t")
+		if trimmed != "" && len(trimmed) < len(line) {
+			return line[:len(line)-len(trimmed)]
+		}
+	}
+	return "    "
+}
+
+// encodeJSON writes v indented, ending with a newline
+func encodeJSON(v any, indent string) []byte {
+	var buf bytes.Buffer
+	writeJSONValue(&buf, v, indent, 0)
+	buf.WriteByte('\n')
+	return buf.Bytes()
+}
+
+func writeJSONValue(buf *bytes.Buffer, v any, indent string, depth int) {
+	pad := strings.Repeat(indent, depth)
+	switch v := v.(type) {
+	case *jsonObject:
+		if len(v.keys) == 0 {
+			buf.WriteString("{}")
+			return
+		}
+		buf.WriteString("{\n")
+		for i, key := range v.keys {
+			buf.WriteString(pad + indent)
+			writeJSONScalar(buf, key)
+			buf.WriteString(": ")
+			writeJSONValue(buf, v.values[key], indent, depth+1)
+			if i < len(v.keys)-1 {
+				buf.WriteByte(',')
+			}
+			buf.WriteByte('\n')
+		}
+		buf.WriteString(pad + "}")
+	case []any:
+		if len(v) == 0 {
+			buf.WriteString("[]")
+			return
+		}
+		buf.WriteString("[\n")
+		for i, item := range v {
+			buf.WriteString(pad + indent)
+			writeJSONValue(buf, item, indent, depth+1)
+			if i < len(v)-1 {
+				buf.WriteByte(',')
+			}
+			buf.WriteByte('\n')
+		}
+		buf.WriteString(pad + "]")
+	default:
+		writeJSONScalar(buf, v)
+	}
+}
+
+// writeJSONScalar writes a string, number, bool or null without escaping
+// HTML characters, which settings files often hold
+func writeJSONScalar(buf *bytes.Buffer, v any) {
+	enc := json.NewEncoder(buf)
+	enc.SetEscapeHTML(false)
+	_ = enc.Encode(v)
+	// Encode ends with a newline
+	buf.Truncate(buf.Len() - 1)
+}
@@ -0,0 +1,121 @@
+package stow
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestMergeJSON(t *testing.T) {
+	existing := `{
+  "defaultProfile": "{61c54bbd}",
+  "profiles": {
+    "defaults": {"fontSize": 11, "opacity": 90},
+    "list": [{"name": "Ubuntu"}]
+  },
+  "theme": "dark"
+}
+`
+	managed := `{
+  "profiles": {"defaults": {"fontFace": "JetBrains Mono", "fontSize": 12}},
+  "copyOnSelect": true,
+  "keybindings": [{"command": "paste", "keys": "ctrl+v"}]
+}`
+
+	merged, changed, err := mergeJSON([]byte(existing), []byte(managed))
+	if err != nil {
+		t.Fatalf("mergeJSON() error = %v", err)
+	}
+	if !changed {
+		t.Error("changed = false, want true")
+	}
+	want := `{
+  "defaultProfile": "{61c54bbd}",
+  "profiles": {
+    "defaults": {
+      "fontSize": 12,
+      "opacity": 90,
+      "fontFace": "JetBrains Mono"
+    },
+    "list": [
+      {
+        "name": "Ubuntu"
+      }
+    ]
+  },
+  "theme": "dark",
+  "copyOnSelect": true,
+  "keybindings": [
+    {
+      "command": "paste",
+      "keys": "ctrl+v"
+    }
+  ]
+}
+`
+	if string(merged) != want {
+		t.Errorf("mergeJSON() =\n%s\nwant\n%s", merged, want)
+	}
+
+	// Merging again changes nothing, whatever the comments
+	if _, changed, err := mergeJSON(append([]byte("// mine\n"), merged...), []byte(managed)); err != nil || changed {
+		t.Errorf("merging into an up to date file: changed = %v, err = %v", changed, err)
+	}
+}
+
+func TestMergeJSON_Commented(t *testing.T) {
+	existing := `// Settings written by Windows Terminal
+{
+  "profiles": {
+    "defaults": {"fontSize": 11}, // set in the UI
+  },
+  /* picked in the UI */
+  "theme": "dark",
+}
+`
+	if _, _, err := mergeJSON([]byte(existing), []byte(`{"profiles": {"defaults": {"fontSize": 12}}}`)); !errors.Is(err, ErrJSONCTarget) {
+		t.Errorf("merging a change into a commented file: err = %v, want ErrJSONCTarget", err)
+	}
+
+	// A commented file that already has the managed values is left alone
+	if _, changed, err := mergeJSON([]byte(existing), []byte(`{"theme": "dark"}`)); err != nil || changed {
+		t.Errorf("merging into an up to date file: changed = %v, err = %v", changed, err)
+	}
+}
+
+func TestMergeJSON_NewFile(t *testing.T) {
+	merged, changed, err := mergeJSON(nil, []byte(`{"editor.fontSize": 14, "url": "https://a.b/?x=1&y=<2>"}`))
+	if err != nil {
+		t.Fatalf("mergeJSON() error = %v", err)
+	}
+	want := "{\n    \"editor.fontSize\": 14,\n    \"url\": \"https://a.b/?x=1&y=<2>\"\n}\n"
+	if !changed || string(merged) != want {
+		t.Errorf("mergeJSON() = %q, %v, want %q", merged, changed, want)
+	}
+}
+
+func TestMergeJSON_Invalid(t *testing.T) {
+	tests := []struct {
+		name              string
+		existing, managed string
+	}{
+		{"source not an object", `{}`, `[1, 2]`},
+		{"source invalid", `{}`, `{"a": }`},
+		{"target invalid", `{"a": 1`, `{"a": 2}`},
+		{"target not an object", `"text"`, `{"a": 2}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, _, err := mergeJSON([]byte(tt.existing), []byte(tt.managed)); err == nil {
+				t.Error("mergeJSON() error = nil")
+			}
+		})
+	}
+}
+
+func TestStripJSONC(t *testing.T) {
+	in := `{"url": "http://x//y", /* c */ "s": "a /* b */ \" // c", "l": [1, 2,],} // end`
+	want := `{"url": "http://x//y",  "s": "a /* b */ \" // c", "l": [1, 2]} `
+	if got := string(stripJSONC([]byte(in))); got != want {
+		t.Errorf("stripJSONC() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,197 @@
+package stow
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/nvandessel/go4dot/internal/config"
+)
+
+// ErrLinkFileConflict is returned when something that go4dot does not
+// manage is in the way of the link LinkFile would create
+var ErrLinkFileConflict = errors.New("path is in the way")
+
+// LinkFile creates or repairs the link for a single file of a config, the
+// way stow would link it, without restowing the rest of the config. relPath
+// is the file's path inside the config directory; a path as linked in the
+// target directory (e.g. .bashrc for dot-bashrc with --dotfiles), relative
+// or absolute, works too.
+//
+// Missing parent directories are created. A parent directory stow folded
+// into a link to another config's directory is unfolded first: the link is
+// replaced by a directory holding a link for each of its entries, so the
+// other config stays linked. Stale links into the repo at the file's path
+// are replaced; anything else in the way is left alone and
+// ErrLinkFileConflict returned. Returns the changes made, or that would be
+// made with opts.DryRun, none if the file is already linked.
+func LinkFile(dotfilesPath string, item config.ConfigItem, relPath string, opts StowOptions) ([]Action, error) {
+	settings := opts.Settings
+	home := settings.TargetDir()
+	configDir := item.Dir(dotfilesPath)
+
+	rel, err := linkFileSource(configDir, home, settings, relPath)
+	if err != nil {
+		return nil, err
+	}
+	source := filepath.Join(configDir, rel)
+	linkRel := settings.LinkPath(rel)
+	target := filepath.Join(home, linkRel)
+
+	if isCorrectlyLinked(source, target) {
+		return nil, nil
+	}
+
+	repo, err := filepath.Abs(dotfilesPath)
+	if err != nil {
+		return nil, err
+	}
+
+	var actions []Action
+	do := func(a Action, apply func() error) error {
+		if !opts.DryRun {
+			if err := apply(); err != nil {
+				return err
+			}
+		}
+		actions = append(actions, a)
+		if opts.ActionFunc != nil {
+			opts.ActionFunc(a)
+		}
+		return nil
+	}
+
+	// Make each parent a real directory
+	dir := home
+	for _, part := range strings.Split(filepath.Dir(linkRel), string(filepath.Separator)) {
+		if part == "." {
+			continue
+		}
+		dir = filepath.Join(dir, part)
+		dirRel, _ := filepath.Rel(home, dir)
+
+		info, err := os.Lstat(dir)
+		switch {
+		case os.IsNotExist(err):
+			if err := do(Action{Kind: ActionMkdir, Path: dirRel}, func() error { return os.Mkdir(dir, 0755) }); err != nil {
+				return actions, err
+			}
+		case err != nil:
+			return actions, err
+		case info.Mode()&os.ModeSymlink != 0:
+			folded := linkDestination(dir)
+			if !isInside(repo, folded) {
+				return actions, fmt.Errorf("%w: %s is a link to %s", ErrLinkFileConflict, dir, folded)
+			}
+			if err := unfold(dir, dirRel, folded, do); err != nil {
+				return actions, err
+			}
+		case !info.IsDir():
+			return actions, fmt.Errorf("%w: %s is a file, not a directory", ErrLinkFileConflict, dir)
+		}
+	}
+
+	// Clear a stale link at the file's path
+	if info, err := os.Lstat(target); err == nil {
+		dest := linkDestination(target)
+		_, statErr := os.Stat(target)
+		if info.Mode()&os.ModeSymlink == 0 || !isInside(repo, dest) || statErr == nil {
+			return actions, fmt.Errorf("%w: %s already exists; move it away or run 'g4d link %s --adopt'", ErrLinkFileConflict, target, item.Name)
+		}
+		if err := do(Action{Kind: ActionUnlink, Path: linkRel}, func() error { return os.Remove(target) }); err != nil {
+			return actions, err
+		}
+	}
+
+	// Link relative to the link's directory, as stow does
+	dest, err := filepath.Rel(filepath.Dir(target), source)
+	if err != nil {
+		return actions, err
+	}
+	err = do(Action{Kind: ActionLink, Path: linkRel, Dest: dest}, func() error { return os.Symlink(dest, target) })
+	return actions, err
+}
+
+// linkFileSource returns the path inside configDir of the file relPath
+// names, which must be a file
+func linkFileSource(configDir, home string, settings config.StowSettings, relPath string) (string, error) {
+	rel := relPath
+	if rel == "~" || strings.HasPrefix(rel, "~/") {
+		rel = filepath.Join(home, strings.TrimPrefix(rel, "~"))
+	}
+	if filepath.IsAbs(rel) {
+		r, err := filepath.Rel(home, rel)
+		if err != nil || r == ".." || strings.HasPrefix(r, ".."+string(filepath.Separator)) {
+			return "", fmt.Errorf("%s is outside %s", relPath, home)
+		}
+		rel = r
+	}
+	rel = filepath.Clean(rel)
+	if rel == "." || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("invalid file path %q", relPath)
+	}
+
+	// Prefer the file of that name, then the one stow links there
+	for _, candidate := range []string{rel, settings.SourcePath(rel)} {
+		info, err := os.Lstat(filepath.Join(configDir, candidate))
+		if err != nil {
+			continue
+		}
+		if info.IsDir() {
+			return "", fmt.Errorf("%s is a directory; link a file inside it or the whole config", relPath)
+		}
+		return candidate, nil
+	}
+	return "", fmt.Errorf("%s is not in %s", relPath, configDir)
+}
+
+// unfold replaces the link at dir with a directory holding a link for each
+// entry of folded, the directory it pointed at. A link to a directory no
+// longer in the repo just becomes an empty directory.
+func unfold(dir, dirRel, folded string, do func(Action, func() error) error) error {
+	entries, err := os.ReadDir(folded)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to unfold %s: %w", dir, err)
+	}
+	if err := do(Action{Kind: ActionUnlink, Path: dirRel}, func() error { return os.Remove(dir) }); err != nil {
+		return err
+	}
+	if err := do(Action{Kind: ActionMkdir, Path: dirRel}, func() error { return os.Mkdir(dir, 0755) }); err != nil {
+		return err
+	}
+	for _, e := range entries {
+		path := filepath.Join(dir, e.Name())
+		dest, err := filepath.Rel(dir, filepath.Join(folded, e.Name()))
+		if err != nil {
+			return err
+		}
+		if err := do(Action{Kind: ActionLink, Path: filepath.Join(dirRel, e.Name()), Dest: dest}, func() error { return os.Symlink(dest, path) }); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// linkDestination returns the absolute, cleaned destination of the link at
+// path, or "" if it cannot be read
+func linkDestination(path string) string {
+	dest, err := os.Readlink(path)
+	if err != nil {
+		return ""
+	}
+	if !filepath.IsAbs(dest) {
+		dest = filepath.Join(filepath.Dir(path), dest)
+	}
+	return filepath.Clean(dest)
+}
+
+// isInside reports whether path is dir or below it
+func isInside(dir, path string) bool {
+	if path == "" {
+		return false
+	}
+	rel, err := filepath.Rel(dir, path)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
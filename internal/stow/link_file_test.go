@@ -0,0 +1,169 @@
+package stow
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/nvandessel/go4dot/internal/config"
+)
+
+// setupLinkFile creates a repo with nvim/.config/nvim/{init.lua,lua/opts.lua}
+// and an empty home used as the stow target
+func setupLinkFile(t *testing.T) (dotfilesPath, home string, opts StowOptions) {
+	t.Helper()
+	tmpDir := t.TempDir()
+	dotfilesPath = filepath.Join(tmpDir, "dotfiles")
+	home = filepath.Join(tmpDir, "home")
+
+	for _, f := range []string{"nvim/.config/nvim/init.lua", "nvim/.config/nvim/lua/opts.lua", "git/.config/git/config"} {
+		path := filepath.Join(dotfilesPath, f)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(f), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.MkdirAll(home, 0755); err != nil {
+		t.Fatal(err)
+	}
+	return dotfilesPath, home, StowOptions{Settings: config.StowSettings{Target: home}}
+}
+
+func TestLinkFile_CreatesMissingLink(t *testing.T) {
+	dotfilesPath, home, opts := setupLinkFile(t)
+	item := config.ConfigItem{Name: "nvim", Path: "nvim"}
+
+	actions, err := LinkFile(dotfilesPath, item, ".config/nvim/lua/opts.lua", opts)
+	if err != nil {
+		t.Fatalf("LinkFile() error = %v", err)
+	}
+
+	source := filepath.Join(dotfilesPath, "nvim/.config/nvim/lua/opts.lua")
+	if !isCorrectlyLinked(source, filepath.Join(home, ".config/nvim/lua/opts.lua")) {
+		t.Error("opts.lua was not linked")
+	}
+	if _, err := os.Lstat(filepath.Join(home, ".config/nvim/init.lua")); !os.IsNotExist(err) {
+		t.Error("init.lua was linked too, want only the one file")
+	}
+	// .config, .config/nvim and .config/nvim/lua, then the link
+	if len(actions) != 4 || actions[3].Kind != ActionLink {
+		t.Errorf("actions = %v, want 3 MKDIR and a LINK", actions)
+	}
+
+	// Linking again changes nothing
+	actions, err = LinkFile(dotfilesPath, item, ".config/nvim/lua/opts.lua", opts)
+	if err != nil || len(actions) != 0 {
+		t.Errorf("second LinkFile() = %v, %v, want no actions", actions, err)
+	}
+}
+
+func TestLinkFile_HomePath(t *testing.T) {
+	dotfilesPath, home, opts := setupLinkFile(t)
+	item := config.ConfigItem{Name: "nvim", Path: "nvim"}
+
+	if _, err := LinkFile(dotfilesPath, item, filepath.Join(home, ".config/nvim/init.lua"), opts); err != nil {
+		t.Fatalf("LinkFile() error = %v", err)
+	}
+	if !isCorrectlyLinked(filepath.Join(dotfilesPath, "nvim/.config/nvim/init.lua"), filepath.Join(home, ".config/nvim/init.lua")) {
+		t.Error("init.lua was not linked")
+	}
+}
+
+func TestLinkFile_ReplacesStaleLink(t *testing.T) {
+	dotfilesPath, home, opts := setupLinkFile(t)
+	item := config.ConfigItem{Name: "nvim", Path: "nvim"}
+	target := filepath.Join(home, ".config/nvim/init.lua")
+	if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(filepath.Join(dotfilesPath, "nvim/old.lua"), target); err != nil {
+		t.Fatal(err)
+	}
+
+	actions, err := LinkFile(dotfilesPath, item, ".config/nvim/init.lua", opts)
+	if err != nil {
+		t.Fatalf("LinkFile() error = %v", err)
+	}
+	if len(actions) != 2 || actions[0].Kind != ActionUnlink || actions[1].Kind != ActionLink {
+		t.Errorf("actions = %v, want UNLINK then LINK", actions)
+	}
+	if !isCorrectlyLinked(filepath.Join(dotfilesPath, "nvim/.config/nvim/init.lua"), target) {
+		t.Error("init.lua was not relinked")
+	}
+}
+
+func TestLinkFile_UnfoldsOtherConfig(t *testing.T) {
+	dotfilesPath, home, opts := setupLinkFile(t)
+	item := config.ConfigItem{Name: "nvim", Path: "nvim"}
+
+	// Stow folded ~/.config into a link to the git config's directory
+	if err := os.Symlink(filepath.Join(dotfilesPath, "git/.config"), filepath.Join(home, ".config")); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := LinkFile(dotfilesPath, item, ".config/nvim/init.lua", opts); err != nil {
+		t.Fatalf("LinkFile() error = %v", err)
+	}
+
+	info, err := os.Lstat(filepath.Join(home, ".config"))
+	if err != nil || !info.IsDir() {
+		t.Fatalf("~/.config is not a directory after unfolding: %v", err)
+	}
+	if !isCorrectlyLinked(filepath.Join(dotfilesPath, "git/.config/git"), filepath.Join(home, ".config/git")) {
+		t.Error("git config lost its link when unfolding")
+	}
+	if !isCorrectlyLinked(filepath.Join(dotfilesPath, "nvim/.config/nvim/init.lua"), filepath.Join(home, ".config/nvim/init.lua")) {
+		t.Error("init.lua was not linked")
+	}
+}
+
+func TestLinkFile_Conflict(t *testing.T) {
+	dotfilesPath, home, opts := setupLinkFile(t)
+	item := config.ConfigItem{Name: "nvim", Path: "nvim"}
+	target := filepath.Join(home, ".config/nvim/init.lua")
+	if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(target, []byte("mine"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err := LinkFile(dotfilesPath, item, ".config/nvim/init.lua", opts)
+	if !errors.Is(err, ErrLinkFileConflict) {
+		t.Fatalf("LinkFile() error = %v, want ErrLinkFileConflict", err)
+	}
+	if data, _ := os.ReadFile(target); string(data) != "mine" {
+		t.Error("existing file was changed")
+	}
+}
+
+func TestLinkFile_DryRun(t *testing.T) {
+	dotfilesPath, home, opts := setupLinkFile(t)
+	item := config.ConfigItem{Name: "nvim", Path: "nvim"}
+	opts.DryRun = true
+
+	actions, err := LinkFile(dotfilesPath, item, ".config/nvim/init.lua", opts)
+	if err != nil {
+		t.Fatalf("LinkFile() error = %v", err)
+	}
+	if len(actions) == 0 {
+		t.Error("dry run reported no actions")
+	}
+	if _, err := os.Lstat(filepath.Join(home, ".config")); !os.IsNotExist(err) {
+		t.Error("dry run changed the home directory")
+	}
+}
+
+func TestLinkFile_InvalidPaths(t *testing.T) {
+	dotfilesPath, _, opts := setupLinkFile(t)
+	item := config.ConfigItem{Name: "nvim", Path: "nvim"}
+
+	for _, rel := range []string{"../git/.config/git/config", ".config/nvim", ".config/nvim/missing.lua", "/etc/passwd"} {
+		if _, err := LinkFile(dotfilesPath, item, rel, opts); err == nil {
+			t.Errorf("LinkFile(%q) succeeded, want an error", rel)
+		}
+	}
+}
@@ -0,0 +1,260 @@
+package stow
+
+import (
+	"fmt"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/nvandessel/go4dot/internal/config"
+)
+
+// linkPropertySeeds is how many random trees each linker is checked against.
+// Failures name the seed, which reproduces the tree.
+const linkPropertySeeds = 40
+
+// treeNames are the path elements random trees are built from. They avoid
+// names GNU stow ignores by default (README*, .git, ...).
+var treeNames = []string{".a", ".b", "c", "d.conf", ".config", "e", "f.lua"}
+
+// linkers are the commanders the properties are checked against: the mock
+// used by the rest of the tests, and real GNU stow when it is installed.
+func linkers() map[string]Commander {
+	l := map[string]Commander{"mock": &MockCommander{}}
+	if IsStowInstalled() {
+		l["stow"] = &ExecCommander{}
+	}
+	return l
+}
+
+// TestLinkProperties checks, for random package trees and random unrelated
+// files already in the target, that:
+//   - nothing outside the target directory changes, and every new link
+//     points into the package
+//   - the package is reported fully linked
+//   - stowing and restowing again change nothing
+//   - unstowing restores the target exactly
+//   - dry runs change nothing
+func TestLinkProperties(t *testing.T) {
+	for name, commander := range linkers() {
+		t.Run(name, func(t *testing.T) {
+			origCommander := CurrentCommander
+			CurrentCommander = commander
+			defer func() { CurrentCommander = origCommander }()
+
+			for seed := int64(1); seed <= linkPropertySeeds; seed++ {
+				t.Run(fmt.Sprintf("seed=%d", seed), func(t *testing.T) {
+					checkLinkProperties(t, rand.New(rand.NewSource(seed)))
+				})
+			}
+		})
+	}
+}
+
+func checkLinkProperties(t *testing.T, rng *rand.Rand) {
+	root := t.TempDir()
+	dotfilesPath := filepath.Join(root, "dotfiles")
+	home := filepath.Join(root, "home")
+	pkgPath := filepath.Join(dotfilesPath, "pkg")
+	t.Setenv("HOME", home)
+	if err := os.MkdirAll(home, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	pkgFiles := randomTree(t, rng, pkgPath, nil)
+	randomTree(t, rng, home, pkgFiles)
+	writeTreeFile(t, filepath.Join(root, "outside", "file"), "outside")
+
+	item := config.ConfigItem{Name: "pkg", Path: "pkg"}
+	opts := StowOptions{}
+	before := snapshotTree(t, root)
+
+	if err := Stow(dotfilesPath, "pkg", StowOptions{DryRun: true}); err != nil {
+		t.Fatalf("dry-run stow: %v", err)
+	}
+	assertSameTree(t, "dry-run stow", before, snapshotTree(t, root))
+
+	if err := Stow(dotfilesPath, "pkg", opts); err != nil {
+		t.Fatalf("stow: %v", err)
+	}
+	stowed := snapshotTree(t, root)
+
+	for path, entry := range stowed {
+		old, existed := before[path]
+		if existed && old == entry {
+			continue
+		}
+		if !strings.HasPrefix(path, "home/") {
+			t.Errorf("stow changed %s outside the target", path)
+			continue
+		}
+		if dest, ok := strings.CutPrefix(entry, "link:"); ok {
+			abs := filepath.Clean(filepath.Join(root, filepath.Dir(path), dest))
+			if filepath.IsAbs(dest) {
+				abs = filepath.Clean(dest)
+			}
+			if !strings.HasPrefix(abs, pkgPath+string(filepath.Separator)) {
+				t.Errorf("stow linked %s to %s, outside the package", path, dest)
+			}
+		}
+	}
+	for path := range before {
+		if _, ok := stowed[path]; !ok {
+			t.Errorf("stow removed %s", path)
+		}
+	}
+
+	status, err := getConfigLinkStatusInternal(item, dotfilesPath, home, config.StowSettings{})
+	if err != nil {
+		t.Fatalf("link status: %v", err)
+	}
+	if !status.IsFullyLinked() || status.TotalCount != len(pkgFiles) {
+		t.Errorf("link status = %d/%d linked, want %d/%d (missing %v)",
+			status.LinkedCount, status.TotalCount, len(pkgFiles), len(pkgFiles), status.GetMissingFiles())
+	}
+
+	if err := Stow(dotfilesPath, "pkg", opts); err != nil {
+		t.Fatalf("second stow: %v", err)
+	}
+	assertSameTree(t, "second stow", stowed, snapshotTree(t, root))
+
+	if err := Restow(dotfilesPath, "pkg", opts); err != nil {
+		t.Fatalf("restow: %v", err)
+	}
+	assertSameTree(t, "restow", stowed, snapshotTree(t, root))
+
+	if err := Unstow(dotfilesPath, "pkg", StowOptions{DryRun: true}); err != nil {
+		t.Fatalf("dry-run unstow: %v", err)
+	}
+	assertSameTree(t, "dry-run unstow", stowed, snapshotTree(t, root))
+
+	if err := Unstow(dotfilesPath, "pkg", opts); err != nil {
+		t.Fatalf("unstow: %v", err)
+	}
+	assertSameTree(t, "unstow", before, snapshotTree(t, root))
+}
+
+// randomTree writes 1-8 files at random paths up to three levels deep under
+// dir and returns their paths relative to dir. Files whose path collides
+// with avoid, or with a directory or file of it, are not written, so the
+// target never conflicts with the package.
+func randomTree(t *testing.T, rng *rand.Rand, dir string, avoid map[string]bool) map[string]bool {
+	t.Helper()
+
+	files := map[string]bool{}
+	dirs := map[string]bool{}
+	n := 1 + rng.Intn(8)
+	for i := 0; i < n; i++ {
+		parts := make([]string, 1+rng.Intn(3))
+		for j := range parts {
+			parts[j] = treeNames[rng.Intn(len(treeNames))]
+		}
+		rel := filepath.Join(parts...)
+		if !fitsTree(rel, files, dirs) || (avoid != nil && collides(rel, avoid)) {
+			continue
+		}
+
+		writeTreeFile(t, filepath.Join(dir, rel), fmt.Sprintf("%s %d", rel, rng.Int()))
+		files[rel] = true
+		for d := filepath.Dir(rel); d != "."; d = filepath.Dir(d) {
+			dirs[d] = true
+		}
+	}
+	if len(files) == 0 && avoid == nil {
+		writeTreeFile(t, filepath.Join(dir, ".a"), "a")
+		files[".a"] = true
+	}
+	return files
+}
+
+// fitsTree reports whether rel can be a file alongside files and dirs
+func fitsTree(rel string, files, dirs map[string]bool) bool {
+	if files[rel] || dirs[rel] {
+		return false
+	}
+	for d := filepath.Dir(rel); d != "."; d = filepath.Dir(d) {
+		if files[d] {
+			return false
+		}
+	}
+	return true
+}
+
+// collides reports whether a target file at rel would clash with, or sit
+// inside the tree of, a package file. Target files may share the package's
+// directories, but not sit where stow would fold a directory into a link.
+func collides(rel string, pkgFiles map[string]bool) bool {
+	for f := range pkgFiles {
+		if f == rel || strings.HasPrefix(f, rel+string(filepath.Separator)) || strings.HasPrefix(rel, f+string(filepath.Separator)) {
+			return true
+		}
+	}
+	return false
+}
+
+func writeTreeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// snapshotTree describes every entry under root by its path relative to
+// root: "dir", "file:<content>" or "link:<destination>". Links are not
+// followed.
+func snapshotTree(t *testing.T, root string) map[string]string {
+	t.Helper()
+
+	snap := map[string]string{}
+	err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(root, path)
+		if err != nil || rel == "." {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+
+		switch {
+		case info.Mode()&os.ModeSymlink != 0:
+			dest, err := os.Readlink(path)
+			if err != nil {
+				return err
+			}
+			snap[rel] = "link:" + dest
+		case info.IsDir():
+			snap[rel] = "dir"
+		default:
+			data, err := os.ReadFile(path)
+			if err != nil {
+				return err
+			}
+			snap[rel] = "file:" + string(data)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("snapshot %s: %v", root, err)
+	}
+	return snap
+}
+
+func assertSameTree(t *testing.T, step string, want, got map[string]string) {
+	t.Helper()
+	for path, entry := range want {
+		if got[path] != entry {
+			t.Errorf("after %s: %s = %q, want %q", step, path, got[path], entry)
+		}
+	}
+	for path, entry := range got {
+		if _, ok := want[path]; !ok {
+			t.Errorf("after %s: unexpected %s (%s)", step, path, entry)
+		}
+	}
+}
@@ -0,0 +1,273 @@
+package stow
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/nvandessel/go4dot/internal/config"
+	"github.com/nvandessel/go4dot/internal/state"
+)
+
+// Statuses of a symlink from the links section
+const (
+	LinkOK      = "ok"      // The symlink points to its source, or the copy is up to date
+	LinkMissing = "missing" // Nothing is at the target yet
+	LinkWrong   = "wrong"   // A symlink at the target points elsewhere, or the copy differs from the repo
+	LinkBlocked = "blocked" // A file or directory is in the way
+	LinkInvalid = "invalid" // The paths cannot be expanded, or a copy's files can't be read
+)
+
+// LinkStatus is the state of one symlink or copy from the links section
+type LinkStatus struct {
+	Link    config.Link
+	Target  string // Expanded target path
+	Source  string // Expanded source path
+	Status  string
+	Message string
+}
+
+// LinksResult is what SyncLinks or RemoveLinks changed
+type LinksResult struct {
+	Linked    []string // Targets of the symlinks created or repointed, as written in the config
+	Copied    []string // Expanded targets of the copies written
+	Unchanged []string // Targets already pointing to their source
+	Removed   []string // Expanded targets of symlinks removed
+	Failed    []LinkError
+}
+
+// LinkError is a symlink from the links section that could not be created
+// or removed
+type LinkError struct {
+	Target string
+	Error  error
+}
+
+// Summary describes the result, e.g. "2 linked, 1 unchanged"
+func (r *LinksResult) Summary() string {
+	summary := fmt.Sprintf("%d linked", len(r.Linked))
+	if len(r.Copied) > 0 {
+		summary += fmt.Sprintf(", %d copied", len(r.Copied))
+	}
+	summary += fmt.Sprintf(", %d unchanged", len(r.Unchanged))
+	if len(r.Removed) > 0 {
+		summary += fmt.Sprintf(", %d removed", len(r.Removed))
+	}
+	if len(r.Failed) > 0 {
+		summary += fmt.Sprintf(", %d failed", len(r.Failed))
+	}
+	return summary
+}
+
+// HasChanges reports whether any link was created, copied or removed, or
+// failed
+func (r *LinksResult) HasChanges() bool {
+	return len(r.Linked) > 0 || len(r.Copied) > 0 || len(r.Removed) > 0 || len(r.Failed) > 0
+}
+
+// CheckLinks reports whether each symlink in the links section is in place
+// and each copy up to date. A link to a well-known target has a status for
+// each place it stands for on this machine, and none when the application
+// isn't there.
+func CheckLinks(cfg *config.Config, repoRoot string) []LinkStatus {
+	var statuses []LinkStatus
+	for _, l := range cfg.Links {
+		statuses = append(statuses, checkLink(l, repoRoot)...)
+	}
+	return statuses
+}
+
+// checkLink reports whether one link is in place at each of its targets
+func checkLink(l config.Link, repoRoot string) []LinkStatus {
+	targets, source, err := l.Paths(repoRoot)
+	if err != nil {
+		return []LinkStatus{{Link: l, Status: LinkInvalid, Message: err.Error()}}
+	}
+
+	statuses := make([]LinkStatus, 0, len(targets))
+	for _, target := range targets {
+		status := LinkStatus{Link: l, Target: target, Source: source}
+		if l.IsCopy() {
+			checkCopy(&status)
+		} else {
+			checkSymlink(&status)
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses
+}
+
+// checkSymlink reports whether the symlink at status.Target points to
+// status.Source
+func checkSymlink(status *LinkStatus) {
+	target, source := status.Target, status.Source
+	info, err := os.Lstat(target)
+	switch {
+	case os.IsNotExist(err):
+		status.Status = LinkMissing
+		status.Message = "not linked"
+	case err != nil:
+		status.Status = LinkBlocked
+		status.Message = err.Error()
+	case info.Mode()&os.ModeSymlink == 0:
+		status.Status = LinkBlocked
+		status.Message = "an existing file or directory is in the way"
+	case linkDestination(target) == source:
+		status.Status = LinkOK
+		status.Message = "linked"
+	default:
+		status.Status = LinkWrong
+		status.Message = fmt.Sprintf("points to %s", linkDestination(target))
+	}
+}
+
+// SyncLinks creates the symlinks in the links section that are missing and
+// repoints those that point elsewhere, if go4dot made them or they point
+// into the repo. Existing files are left alone and reported as failed.
+// Symlinks recorded in st that were dropped from the config are removed.
+// With opts.DryRun nothing is changed.
+func SyncLinks(cfg *config.Config, repoRoot string, st *state.State, opts StowOptions) *LinksResult {
+	result := &LinksResult{}
+	report := func(msg string) {
+		if opts.ProgressFunc != nil {
+			opts.ProgressFunc(0, 0, msg)
+		}
+	}
+
+	wanted := make(map[string]bool)
+	for _, s := range CheckLinks(cfg, repoRoot) {
+		if s.Link.IsCopy() {
+			syncCopy(s, opts, result, report)
+			continue
+		}
+		if s.Target != "" {
+			wanted[s.Target] = true
+		}
+
+		switch s.Status {
+		case LinkOK:
+			result.Unchanged = append(result.Unchanged, s.Link.Target)
+			if st != nil && !opts.DryRun {
+				st.SetLink(s.Target, s.Source)
+			}
+			continue
+		case LinkInvalid, LinkBlocked:
+			result.Failed = append(result.Failed, LinkError{Target: s.Link.Target, Error: errors.New(s.Message)})
+			report(fmt.Sprintf("✗ %s: %s", s.Link.Target, s.Message))
+			continue
+		case LinkWrong:
+			recorded := false
+			if st != nil {
+				_, recorded = st.Links[s.Target]
+			}
+			if dest := linkDestination(s.Target); !recorded && !isInside(repoRoot, dest) {
+				err := fmt.Errorf("a symlink to %s is in the way", dest)
+				result.Failed = append(result.Failed, LinkError{Target: s.Link.Target, Error: err})
+				report(fmt.Sprintf("✗ %s: %v", s.Link.Target, err))
+				continue
+			}
+		}
+
+		if _, err := os.Stat(s.Source); err != nil {
+			err = fmt.Errorf("source %s does not exist", s.Link.Source)
+			result.Failed = append(result.Failed, LinkError{Target: s.Link.Target, Error: err})
+			report(fmt.Sprintf("✗ %s: %v", s.Link.Target, err))
+			continue
+		}
+
+		report(fmt.Sprintf("Linking %s → %s", s.Link.Target, s.Link.Source))
+		if !opts.DryRun {
+			if err := createLink(s.Target, s.Source, s.Status == LinkWrong); err != nil {
+				result.Failed = append(result.Failed, LinkError{Target: s.Link.Target, Error: err})
+				report(fmt.Sprintf("✗ %s: %v", s.Link.Target, err))
+				continue
+			}
+			if st != nil {
+				st.SetLink(s.Target, s.Source)
+			}
+		}
+		result.Linked = append(result.Linked, s.Link.Target)
+	}
+
+	if st != nil {
+		for _, target := range sortedLinkTargets(st) {
+			if !wanted[target] {
+				removeRecordedLink(target, st, opts, result, report)
+			}
+		}
+	}
+	return result
+}
+
+// RemoveLinks removes every symlink recorded in st that still points where
+// go4dot pointed it, and forgets them all. With opts.DryRun nothing is
+// changed.
+func RemoveLinks(st *state.State, opts StowOptions) *LinksResult {
+	result := &LinksResult{}
+	if st == nil {
+		return result
+	}
+	report := func(msg string) {
+		if opts.ProgressFunc != nil {
+			opts.ProgressFunc(0, 0, msg)
+		}
+	}
+	for _, target := range sortedLinkTargets(st) {
+		removeRecordedLink(target, st, opts, result, report)
+	}
+	return result
+}
+
+// removeRecordedLink removes the symlink at target if it still points to
+// the source recorded in st, then forgets it
+func removeRecordedLink(target string, st *state.State, opts StowOptions, result *LinksResult, report func(string)) {
+	if linkDestination(target) != st.Links[target] {
+		// Gone or replaced by something else: nothing of ours to remove
+		if !opts.DryRun {
+			st.RemoveLink(target)
+		}
+		return
+	}
+
+	report(fmt.Sprintf("Removing link %s", target))
+	if opts.DryRun {
+		result.Removed = append(result.Removed, target)
+		return
+	}
+	if err := removeSaved(target); err != nil {
+		result.Failed = append(result.Failed, LinkError{Target: target, Error: err})
+		report(fmt.Sprintf("✗ %s: %v", target, err))
+		return
+	}
+	st.RemoveLink(target)
+	result.Removed = append(result.Removed, target)
+}
+
+// createLink makes target a symlink to source, creating missing parent
+// directories and replacing the symlink there if replace is set
+func createLink(target, source string, replace bool) error {
+	if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+		return fmt.Errorf("failed to create %s: %w", filepath.Dir(target), err)
+	}
+	if replace {
+		if err := removeSaved(target); err != nil {
+			return fmt.Errorf("failed to remove the old link: %w", err)
+		}
+	}
+	if err := os.Symlink(source, target); err != nil {
+		return fmt.Errorf("failed to create link: %w", err)
+	}
+	return nil
+}
+
+// sortedLinkTargets returns the targets of the symlinks recorded in st
+func sortedLinkTargets(st *state.State) []string {
+	targets := make([]string, 0, len(st.Links))
+	for target := range st.Links {
+		targets = append(targets, target)
+	}
+	sort.Strings(targets)
+	return targets
+}
@@ -0,0 +1,141 @@
+package stow
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/nvandessel/go4dot/internal/config"
+	"github.com/nvandessel/go4dot/internal/snapshot"
+)
+
+// checkCopy reports whether the file at status.Target has what copying
+// status.Source there would write
+func checkCopy(status *LinkStatus) {
+	info, err := os.Lstat(status.Target)
+	switch {
+	case os.IsNotExist(err):
+		status.Status = LinkMissing
+		status.Message = "not copied"
+		return
+	case err != nil:
+		status.Status = LinkBlocked
+		status.Message = err.Error()
+		return
+	case info.Mode()&os.ModeSymlink != 0:
+		status.Status = LinkBlocked
+		status.Message = "a symlink is in the way"
+		return
+	case info.IsDir():
+		status.Status = LinkBlocked
+		status.Message = "a directory is in the way"
+		return
+	}
+
+	_, changed, err := copyContent(status.Link, status.Source, status.Target)
+	switch {
+	case err != nil:
+		status.Status = LinkInvalid
+		status.Message = err.Error()
+	case changed && status.Link.Merge == config.MergeJSON:
+		status.Status = LinkWrong
+		status.Message = "some settings from the repo are missing or differ"
+	case changed:
+		status.Status = LinkWrong
+		status.Message = "differs from the repo"
+	default:
+		status.Status = LinkOK
+		status.Message = "up to date"
+	}
+}
+
+// copyContent returns what copying source to target writes: the source
+// itself, or with merge: json the file at target with the source's keys
+// set in it. changed reports whether that differs from the file at target.
+func copyContent(l config.Link, source, target string) (content []byte, changed bool, err error) {
+	info, err := os.Stat(source)
+	switch {
+	case os.IsNotExist(err):
+		return nil, false, fmt.Errorf("source %s does not exist", l.Source)
+	case err != nil:
+		return nil, false, err
+	case info.IsDir():
+		return nil, false, fmt.Errorf("source %s is a directory; copies are made file by file", l.Source)
+	}
+	data, err := os.ReadFile(source)
+	if err != nil {
+		return nil, false, err
+	}
+
+	existing, err := os.ReadFile(target)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, false, err
+	}
+	missing := os.IsNotExist(err)
+
+	if l.Merge == config.MergeJSON {
+		merged, changed, err := mergeJSON(existing, data)
+		if err != nil {
+			return nil, false, fmt.Errorf("merging %s into %s: %w", l.Source, target, err)
+		}
+		return merged, changed || missing, nil
+	}
+	return data, missing || !bytes.Equal(existing, data), nil
+}
+
+// syncCopy writes the copy status describes when it is missing or out of
+// date. What was at the target is saved to the snapshot for g4d undo.
+func syncCopy(s LinkStatus, opts StowOptions, result *LinksResult, report func(string)) {
+	fail := func(err error) {
+		result.Failed = append(result.Failed, LinkError{Target: s.Link.Target, Error: err})
+		report(fmt.Sprintf("✗ %s: %v", s.Link.Target, err))
+	}
+
+	switch s.Status {
+	case LinkOK:
+		result.Unchanged = append(result.Unchanged, s.Link.Target)
+		return
+	case LinkInvalid, LinkBlocked:
+		if s.Target != "" {
+			fail(fmt.Errorf("%s: %s", s.Target, s.Message))
+		} else {
+			fail(errors.New(s.Message))
+		}
+		return
+	}
+
+	report(fmt.Sprintf("Copying %s → %s", s.Link.Source, s.Target))
+	if !opts.DryRun {
+		if err := writeCopy(s); err != nil {
+			fail(err)
+			return
+		}
+	}
+	result.Copied = append(result.Copied, s.Target)
+}
+
+// writeCopy writes the copy to its target, creating missing parent
+// directories
+func writeCopy(s LinkStatus) error {
+	content, _, err := copyContent(s.Link, s.Source, s.Target)
+	if err != nil {
+		return err
+	}
+	info, err := os.Stat(s.Source)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(s.Target), 0755); err != nil {
+		return fmt.Errorf("failed to create %s: %w", filepath.Dir(s.Target), err)
+	}
+	if err := snapshot.Save(s.Target); err != nil {
+		return err
+	}
+	if err := os.WriteFile(s.Target, content, info.Mode().Perm()); err != nil {
+		return fmt.Errorf("failed to copy to %s: %w", s.Target, err)
+	}
+	return nil
+}
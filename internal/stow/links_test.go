@@ -0,0 +1,170 @@
+package stow
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/nvandessel/go4dot/internal/config"
+	"github.com/nvandessel/go4dot/internal/state"
+)
+
+func TestSyncLinks(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	repo := t.TempDir()
+	writeTestFile(t, filepath.Join(repo, "scripts", "hello"), "#!/bin/sh\n")
+	writeTestFile(t, filepath.Join(repo, "notes", "todo.md"), "- todo\n")
+	writeTestFile(t, filepath.Join(home, ".local", "share", "wallpapers"), "not a link")
+
+	cfg := &config.Config{Links: []config.Link{
+		{Target: "~/bin", Source: "@repoRoot/scripts"},
+		{Target: "~/Documents/notes", Source: "@repoRoot/notes"},
+		{Target: "~/.local/share/wallpapers", Source: "@repoRoot/wallpapers"},
+	}}
+	st := state.New()
+
+	result := SyncLinks(cfg, repo, st, StowOptions{})
+	if !reflect.DeepEqual(result.Linked, []string{"~/bin", "~/Documents/notes"}) {
+		t.Errorf("Linked = %v", result.Linked)
+	}
+	if len(result.Failed) != 1 || result.Failed[0].Target != "~/.local/share/wallpapers" {
+		t.Errorf("Failed = %v, want the blocked wallpapers link", result.Failed)
+	}
+	if got := linkDestination(filepath.Join(home, "Documents", "notes")); got != filepath.Join(repo, "notes") {
+		t.Errorf("~/Documents/notes points to %q", got)
+	}
+	if st.Links[filepath.Join(home, "bin")] != filepath.Join(repo, "scripts") {
+		t.Errorf("state links = %v", st.Links)
+	}
+
+	statuses := CheckLinks(cfg, repo)
+	if statuses[0].Status != LinkOK || statuses[2].Status != LinkBlocked {
+		t.Errorf("CheckLinks() = %+v", statuses)
+	}
+
+	// A link dropped from the config is removed; one left in place is unchanged
+	cfg.Links = cfg.Links[:1]
+	result = SyncLinks(cfg, repo, st, StowOptions{})
+	if !reflect.DeepEqual(result.Unchanged, []string{"~/bin"}) || len(result.Removed) != 1 {
+		t.Errorf("after dropping a link: %+v", result)
+	}
+	if _, err := os.Lstat(filepath.Join(home, "Documents", "notes")); !os.IsNotExist(err) {
+		t.Errorf("dropped link still exists: %v", err)
+	}
+
+	// Uninstall removes the rest
+	result = RemoveLinks(st, StowOptions{})
+	if len(result.Removed) != 1 || len(st.Links) != 0 {
+		t.Errorf("RemoveLinks() = %+v, state links = %v", result, st.Links)
+	}
+	if _, err := os.Lstat(filepath.Join(home, "bin")); !os.IsNotExist(err) {
+		t.Errorf("~/bin still exists: %v", err)
+	}
+}
+
+func TestSyncLinks_LeavesForeignSymlink(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	repo := t.TempDir()
+	writeTestFile(t, filepath.Join(repo, "scripts", "hello"), "#!/bin/sh\n")
+	elsewhere := t.TempDir()
+	if err := os.Symlink(elsewhere, filepath.Join(home, "bin")); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := &config.Config{Links: []config.Link{{Target: "~/bin", Source: "@repoRoot/scripts"}}}
+	result := SyncLinks(cfg, repo, state.New(), StowOptions{})
+	if len(result.Failed) != 1 {
+		t.Fatalf("Failed = %v, want the link to elsewhere left alone", result.Failed)
+	}
+	if got := linkDestination(filepath.Join(home, "bin")); got != elsewhere {
+		t.Errorf("~/bin points to %q, want it untouched", got)
+	}
+}
+
+func TestSyncLinks_Copy(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	repo := t.TempDir()
+	writeTestFile(t, filepath.Join(repo, "vscode", "settings.json"), `{"editor.fontSize": 14}`)
+	writeTestFile(t, filepath.Join(repo, "pwsh", "profile.ps1"), "Set-PSReadLineOption -EditMode Emacs\n")
+	settings := filepath.Join(home, ".config", "Code", "User", "settings.json")
+	writeTestFile(t, settings, "{\n  \"workbench.colorTheme\": \"Solarized\"\n}\n")
+
+	cfg := &config.Config{Links: []config.Link{
+		{Target: "~/.config/Code/User/settings.json", Source: "@repoRoot/vscode/settings.json", Mode: config.LinkModeCopy, Merge: config.MergeJSON},
+		{Target: "~/.config/powershell/profile.ps1", Source: "@repoRoot/pwsh/profile.ps1", Mode: config.LinkModeCopy},
+	}}
+	st := state.New()
+
+	statuses := CheckLinks(cfg, repo)
+	if statuses[0].Status != LinkWrong || statuses[1].Status != LinkMissing {
+		t.Errorf("CheckLinks() before sync = %+v", statuses)
+	}
+
+	result := SyncLinks(cfg, repo, st, StowOptions{DryRun: true})
+	if len(result.Copied) != 2 {
+		t.Errorf("dry run Copied = %v", result.Copied)
+	}
+	if _, err := os.Stat(filepath.Join(home, ".config", "powershell")); !os.IsNotExist(err) {
+		t.Errorf("dry run copied: %v", err)
+	}
+
+	result = SyncLinks(cfg, repo, st, StowOptions{})
+	if len(result.Copied) != 2 || len(result.Failed) != 0 {
+		t.Fatalf("SyncLinks() = %+v", result)
+	}
+	data, err := os.ReadFile(settings)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "{\n  \"workbench.colorTheme\": \"Solarized\",\n  \"editor.fontSize\": 14\n}\n"; string(data) != want {
+		t.Errorf("settings.json = %q, want %q", data, want)
+	}
+	if info, err := os.Lstat(filepath.Join(home, ".config", "powershell", "profile.ps1")); err != nil || !info.Mode().IsRegular() {
+		t.Errorf("profile.ps1 was not copied: %v", err)
+	}
+	if len(st.Links) != 0 {
+		t.Errorf("copies were recorded as links: %v", st.Links)
+	}
+
+	// Up to date copies are left alone; dropping one leaves the file
+	result = SyncLinks(cfg, repo, st, StowOptions{})
+	if len(result.Unchanged) != 2 || len(result.Copied) != 0 {
+		t.Errorf("second sync = %+v", result)
+	}
+	cfg.Links = cfg.Links[:1]
+	SyncLinks(cfg, repo, st, StowOptions{})
+	if _, err := os.Stat(filepath.Join(home, ".config", "powershell", "profile.ps1")); err != nil {
+		t.Errorf("dropping a copy removed it: %v", err)
+	}
+}
+
+func TestSyncLinks_CopyBlocked(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	repo := t.TempDir()
+	writeTestFile(t, filepath.Join(repo, "settings.json"), `{"a": 1}`)
+	if err := os.MkdirAll(filepath.Join(home, "settings.json"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	writeTestFile(t, filepath.Join(home, "broken.json"), `{"a": `)
+
+	cfg := &config.Config{Links: []config.Link{
+		{Target: "~/settings.json", Source: "@repoRoot/settings.json", Mode: config.LinkModeCopy},
+		{Target: "~/broken.json", Source: "@repoRoot/settings.json", Mode: config.LinkModeCopy, Merge: config.MergeJSON},
+	}}
+	statuses := CheckLinks(cfg, repo)
+	if statuses[0].Status != LinkBlocked || statuses[1].Status != LinkInvalid {
+		t.Errorf("CheckLinks() = %+v", statuses)
+	}
+	result := SyncLinks(cfg, repo, state.New(), StowOptions{})
+	if len(result.Failed) != 2 {
+		t.Errorf("Failed = %v, want both copies", result.Failed)
+	}
+	if data, _ := os.ReadFile(filepath.Join(home, "broken.json")); string(data) != `{"a": ` {
+		t.Errorf("invalid JSON was overwritten: %q", data)
+	}
+}
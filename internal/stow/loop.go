@@ -0,0 +1,126 @@
+package stow
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// MaxSymlinkDepth is how many links go4dot follows on the way to one path
+// before giving up on it, as Linux does
+const MaxSymlinkDepth = 40
+
+var (
+	// ErrSymlinkLoop is matched by SymlinkErrors for links that lead back
+	// to themselves
+	ErrSymlinkLoop = errors.New("symlink loop")
+
+	// ErrSymlinkTooDeep is matched by SymlinkErrors for chains of more than
+	// MaxSymlinkDepth links
+	ErrSymlinkTooDeep = errors.New("symlink chain too deep")
+)
+
+// SymlinkError reports the links followed on the way to Path when they
+// loop or go on for more than MaxSymlinkDepth links
+type SymlinkError struct {
+	Path  string   // The path being resolved
+	Chain []string // The links followed, in order; for a loop the last one repeats an earlier one
+	Loop  bool     // False when the chain was only too long
+}
+
+func (e *SymlinkError) Error() string {
+	if e.Loop {
+		return fmt.Sprintf("symlink loop at %s: %s", e.Path, strings.Join(e.Chain, " -> "))
+	}
+	return fmt.Sprintf("more than %d symlinks on the way to %s (stopped at %s)", MaxSymlinkDepth, e.Path, e.Chain[len(e.Chain)-1])
+}
+
+// Is makes errors.Is match ErrSymlinkLoop or ErrSymlinkTooDeep
+func (e *SymlinkError) Is(target error) bool {
+	if e.Loop {
+		return target == ErrSymlinkLoop
+	}
+	return target == ErrSymlinkTooDeep
+}
+
+// ResolveLinks returns path with every link on the way to it followed,
+// like filepath.EvalSymlinks, but reports loops and chains of more than
+// MaxSymlinkDepth links as a *SymlinkError naming the links involved.
+// The part of path that doesn't exist is kept as is.
+func ResolveLinks(path string) (string, error) {
+	path, err := filepath.Abs(path)
+	if err != nil {
+		return "", err
+	}
+
+	volume := filepath.VolumeName(path)
+	resolved := volume + string(filepath.Separator)
+	rest := splitPath(path[len(volume):])
+
+	var chain []string
+	seen := make(map[string]bool)
+	for len(rest) > 0 {
+		next := filepath.Join(resolved, rest[0])
+		rest = rest[1:]
+
+		info, err := os.Lstat(next)
+		if os.IsNotExist(err) {
+			return filepath.Join(append([]string{next}, rest...)...), nil
+		}
+		if err != nil {
+			return "", err
+		}
+		if info.Mode()&os.ModeSymlink == 0 {
+			resolved = next
+			continue
+		}
+
+		// Coming back to a link with the same path left to resolve can
+		// only repeat what happened the first time
+		key := next + "\x00" + strings.Join(rest, "/")
+		chain = append(chain, next)
+		if seen[key] {
+			return "", &SymlinkError{Path: path, Chain: chain, Loop: true}
+		}
+		if len(chain) > MaxSymlinkDepth {
+			return "", &SymlinkError{Path: path, Chain: chain}
+		}
+		seen[key] = true
+
+		dest, err := os.Readlink(next)
+		if err != nil {
+			return "", err
+		}
+		if filepath.IsAbs(dest) {
+			volume = filepath.VolumeName(dest)
+			resolved = volume + string(filepath.Separator)
+			dest = dest[len(volume):]
+		}
+		rest = append(splitPath(dest), rest...)
+	}
+	return resolved, nil
+}
+
+// CheckLinkChain returns the *SymlinkError for the links on the way to
+// path, or nil when they resolve or path simply doesn't exist
+func CheckLinkChain(path string) *SymlinkError {
+	_, err := ResolveLinks(path)
+	var linkErr *SymlinkError
+	if errors.As(err, &linkErr) {
+		return linkErr
+	}
+	return nil
+}
+
+// splitPath splits a path into its elements, dropping empty ones
+func splitPath(path string) []string {
+	var parts []string
+	for _, part := range strings.Split(filepath.ToSlash(path), "/") {
+		if part != "" && part != "." {
+			parts = append(parts, part)
+		}
+	}
+	return parts
+}
@@ -0,0 +1,116 @@
+package stow
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/nvandessel/go4dot/internal/config"
+)
+
+func TestResolveLinks(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.Mkdir(filepath.Join(dir, "real"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink("real", filepath.Join(dir, "dir")); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(filepath.Join(dir, "dir", "file"), filepath.Join(dir, "link")); err != nil {
+		t.Fatal(err)
+	}
+
+	want, err := filepath.EvalSymlinks(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := ResolveLinks(filepath.Join(dir, "link"))
+	if err != nil {
+		t.Fatalf("ResolveLinks() error = %v", err)
+	}
+	if got != filepath.Join(want, "real", "file") {
+		t.Errorf("ResolveLinks() = %q, want %q", got, filepath.Join(want, "real", "file"))
+	}
+}
+
+func TestResolveLinks_Loop(t *testing.T) {
+	dir := t.TempDir()
+	a, b := filepath.Join(dir, "a"), filepath.Join(dir, "b")
+	if err := os.Symlink(b, a); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(a, b); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err := ResolveLinks(filepath.Join(a, ".zshrc"))
+	if !errors.Is(err, ErrSymlinkLoop) {
+		t.Fatalf("ResolveLinks() error = %v, want a loop", err)
+	}
+	var linkErr *SymlinkError
+	if !errors.As(err, &linkErr) || len(linkErr.Chain) != 3 || !strings.Contains(err.Error(), a) {
+		t.Errorf("error = %v, want the chain a -> b -> a", err)
+	}
+}
+
+func TestResolveLinks_TooDeep(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "link0"), nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	for i := 1; i <= MaxSymlinkDepth+1; i++ {
+		if err := os.Symlink(fmt.Sprintf("link%d", i-1), filepath.Join(dir, fmt.Sprintf("link%d", i))); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if _, err := ResolveLinks(filepath.Join(dir, fmt.Sprintf("link%d", MaxSymlinkDepth))); err != nil {
+		t.Errorf("ResolveLinks() error = %v for %d links", err, MaxSymlinkDepth)
+	}
+	_, err := ResolveLinks(filepath.Join(dir, fmt.Sprintf("link%d", MaxSymlinkDepth+1)))
+	if !errors.Is(err, ErrSymlinkTooDeep) {
+		t.Errorf("ResolveLinks() error = %v, want too deep", err)
+	}
+}
+
+func TestFullDriftCheck_SymlinkLoop(t *testing.T) {
+	tmpDir := t.TempDir()
+	dotfiles, home := filepath.Join(tmpDir, "dotfiles"), filepath.Join(tmpDir, "home")
+	if err := os.MkdirAll(filepath.Join(dotfiles, "nvim", ".config", "nvim"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dotfiles, "nvim", ".config", "nvim", "init.lua"), nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(home, 0755); err != nil {
+		t.Fatal(err)
+	}
+	// ~/.config points at itself
+	if err := os.Symlink(".config", filepath.Join(home, ".config")); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := &config.Config{Configs: config.ConfigGroups{Core: []config.ConfigItem{{Name: "nvim", Path: "nvim"}}}}
+	summary, err := FullDriftCheckWithHome(cfg, dotfiles, home, nil)
+	if err != nil {
+		t.Fatalf("FullDriftCheckWithHome() error = %v", err)
+	}
+	result := summary.Results[0]
+	if len(result.LinkErrors) != 1 || !strings.Contains(result.LinkErrors[0], filepath.Join(home, ".config")) {
+		t.Errorf("LinkErrors = %v, want the loop at ~/.config", result.LinkErrors)
+	}
+	if !result.HasDrift {
+		t.Error("expected a symlink loop to count as drift")
+	}
+
+	status, err := GetSingleConfigLinkStatus(cfg.Configs.Core[0], dotfiles, config.StowSettings{Target: home})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(status.Files) != 1 || !strings.HasPrefix(status.Files[0].Issue, "symlink loop") {
+		t.Errorf("files = %+v, want a symlink loop issue", status.Files)
+	}
+}
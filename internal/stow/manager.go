@@ -6,8 +6,10 @@ import (
 	"os/exec"
 	"path/filepath"
 	"strings"
+	"sync"
 
 	"github.com/nvandessel/go4dot/internal/config"
+	"github.com/nvandessel/go4dot/internal/transcript"
 	"github.com/nvandessel/go4dot/internal/validation"
 )
 
@@ -16,6 +18,16 @@ type StowResult struct {
 	Success []string    // List of successfully stowed config names
 	Failed  []StowError // List of configs that failed to stow with their errors
 	Skipped []string    // List of configs that were skipped (e.g., directory not found)
+
+	Unchanged []string     // Configs a sync left alone as they had not changed since the last one
+	Links     *LinksResult // Symlinks from the links section a sync created or removed
+}
+
+// Synced returns the configs a sync leaves linked: those it restowed and
+// those it left alone as unchanged. post_sync hooks and verify commands run
+// for these, as edits to an unchanged config's files can still break it.
+func (r *StowResult) Synced() []string {
+	return append(append([]string{}, r.Success...), r.Unchanged...)
 }
 
 // StowError represents an error that occurred during a stow operation for a specific config.
@@ -28,7 +40,13 @@ type StowError struct {
 type StowOptions struct {
 	DryRun       bool                                 // If true, don't make any changes, just show what would happen
 	Force        bool                                 // If true, use --adopt to take over existing files
+	UseTrash     bool                                 // If true, deleted conflict files are moved to the trash
+	Adopt        bool                                 // If true, conflicting files in home are moved into the repo before linking
 	ProgressFunc func(current, total int, msg string) // Callback for progress updates
+	ActionFunc   func(Action)                         // Called per file stow links, unlinks or warns about
+	Settings     config.StowSettings                  // The config's stow section: binary, extra flags, target
+	RestowAll    bool                                 // If true, SyncAll restows configs that have not changed too
+	Jobs         int                                  // Configs SyncAll restows at once; 0 for DefaultJobs, 1 for one at a time
 }
 
 // Commander defines the interface for executing stow commands.
@@ -42,19 +60,23 @@ type ExecCommander struct{}
 // Run executes a command using os/exec.
 func (e *ExecCommander) Run(name string, args ...string) ([]byte, error) {
 	cmd := exec.Command(name, args...)
-	return cmd.CombinedOutput()
+	return transcript.CombinedOutput(cmd)
 }
 
 // MockCommander simulates GNU Stow behavior for testing.
 type MockCommander struct {
 	LastArgs []string
+
+	mu sync.Mutex // Syncs run stow for several configs at once
 }
 
 // Run parses stow-like arguments and manipulates the filesystem to simulate stow.
 func (m *MockCommander) Run(name string, args ...string) ([]byte, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
 	m.LastArgs = args
 
-	if name != "stow" {
+	if filepath.Base(name) != "stow" {
 		return nil, fmt.Errorf("unexpected command: %s", name)
 	}
 
@@ -67,7 +89,7 @@ func (m *MockCommander) Run(name string, args ...string) ([]byte, error) {
 
 	// Parse arguments
 	var targetDir, dotfilesDir string
-	var deleteMode, restowMode, dryRun bool
+	var deleteMode, restowMode, dryRun, dotfiles bool
 	var packages []string
 
 	for i := 0; i < len(args); i++ {
@@ -91,6 +113,8 @@ func (m *MockCommander) Run(name string, args ...string) ([]byte, error) {
 			dryRun = true
 		case "-v":
 			// ignore
+		case "--dotfiles":
+			dotfiles = true
 		case "--":
 			// Everything after -- is a package name, not a flag
 			packages = append(packages, args[i+1:]...)
@@ -121,7 +145,7 @@ func (m *MockCommander) Run(name string, args ...string) ([]byte, error) {
 					return nil
 				}
 				rel, _ := filepath.Rel(pkgPath, path)
-				targetPath := filepath.Join(targetDir, rel)
+				targetPath := filepath.Join(targetDir, mockLinkPath(rel, dotfiles))
 
 				// Check if it's a symlink pointing to our source
 				if linkInfo, err := os.Lstat(targetPath); err == nil && linkInfo.Mode()&os.ModeSymlink != 0 {
@@ -132,6 +156,7 @@ func (m *MockCommander) Run(name string, args ...string) ([]byte, error) {
 						}
 						if absDest == filepath.Clean(path) {
 							_ = os.Remove(targetPath)
+							removeEmptyParents(filepath.Dir(targetPath), targetDir)
 						}
 					}
 				}
@@ -146,7 +171,7 @@ func (m *MockCommander) Run(name string, args ...string) ([]byte, error) {
 					return nil
 				}
 				rel, _ := filepath.Rel(pkgPath, path)
-				targetPath := filepath.Join(targetDir, rel)
+				targetPath := filepath.Join(targetDir, mockLinkPath(rel, dotfiles))
 
 				// Ensure parent directory exists
 				_ = os.MkdirAll(filepath.Dir(targetPath), 0755)
@@ -162,6 +187,27 @@ func (m *MockCommander) Run(name string, args ...string) ([]byte, error) {
 	return []byte("Mock stow finished successfully"), nil
 }
 
+// mockLinkPath maps rel the way stow --dotfiles does when dotfiles is set
+func mockLinkPath(rel string, dotfiles bool) string {
+	if !dotfiles {
+		return rel
+	}
+	return config.StowSettings{Flags: []string{"--dotfiles"}}.LinkPath(rel)
+}
+
+// removeEmptyParents removes dir and its parents up to, but not including,
+// root while they are empty. Stow folds directories it creates into a single
+// link, so unstowing leaves none of them behind; the mock creates real
+// directories and cleans them up here instead.
+func removeEmptyParents(dir, root string) {
+	root = filepath.Clean(root)
+	for dir = filepath.Clean(dir); dir != root && strings.HasPrefix(dir, root+string(filepath.Separator)); dir = filepath.Dir(dir) {
+		if os.Remove(dir) != nil {
+			return
+		}
+	}
+}
+
 var (
 	// CurrentCommander is the commander instance used for all stow operations.
 	// It can be replaced in tests with a mock implementation.
@@ -185,37 +231,64 @@ func StowWithCount(dotfilesPath string, configName string, current, total int, o
 		opts.ProgressFunc(current, total, fmt.Sprintf("Stowing %s...", configName))
 	}
 
-	homeDir, err := os.UserHomeDir()
+	args, err := stowArgs(dotfilesPath, configName, "", opts)
 	if err != nil {
-		return fmt.Errorf("failed to get home directory: %w", err)
+		return err
 	}
 
-	// Build stow command
-	args := []string{"-v"} // Verbose
+	output, err := runStow(opts, args...)
 
-	if opts.DryRun {
-		args = append(args, "-n") // No-op/dry-run
+	if err != nil {
+		return fmt.Errorf("stow failed: %w\nOutput: %s", err, string(output))
 	}
 
-	if opts.Force {
-		args = append(args, "--adopt") // Adopt existing files
+	if opts.ProgressFunc != nil {
+		opts.ProgressFunc(current, total, fmt.Sprintf("✓ Stowed %s", configName))
 	}
 
-	args = append(args, "-t", homeDir)         // Target home directory
-	args = append(args, "-d", dotfilesPath)    // Directory containing packages
-	args = append(args, "--", configName)      // Package to stow (-- prevents flag injection)
+	return nil
+}
 
-	output, err := CurrentCommander.Run("stow", args...)
+// stowArgs builds the stow arguments for one package. mode is "-D" to
+// unstow, "-R" to restow or empty to stow; opts.Settings adds the config's
+// verbosity, extra flags and target.
+func stowArgs(dotfilesPath, configName, mode string, opts StowOptions) ([]string, error) {
+	target := opts.Settings.TargetDir()
+	if opts.Settings.Target == "" {
+		homeDir, err := os.UserHomeDir()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get home directory: %w", err)
+		}
+		target = homeDir
+	}
 
-	if err != nil {
-		return fmt.Errorf("stow failed: %w\nOutput: %s", err, string(output))
+	args := []string{"-v"} // Verbose, so linked files can be reported
+	if level := opts.Settings.VerbosityLevel(); level > 1 {
+		args = []string{fmt.Sprintf("--verbose=%d", level)}
+	}
+	if mode != "" {
+		args = append(args, mode)
 	}
 
-	if opts.ProgressFunc != nil {
-		opts.ProgressFunc(current, total, fmt.Sprintf("✓ Stowed %s", configName))
+	if opts.DryRun {
+		args = append(args, "-n") // No-op/dry-run
 	}
 
-	return nil
+	if opts.Force {
+		args = append(args, "--adopt") // Adopt existing files
+	}
+
+	for _, flag := range opts.Settings.Flags {
+		if !config.IsStowFlag(flag) {
+			return nil, fmt.Errorf("stow flag %q is not allowed", flag)
+		}
+		args = append(args, flag)
+	}
+
+	args = append(args, "-t", target)       // Directory to link into
+	args = append(args, "-d", dotfilesPath) // Directory containing packages
+	args = append(args, "--", configName)   // Package to stow (-- prevents flag injection)
+	return args, nil
 }
 
 // Unstow removes symlinks for a config using GNU stow.
@@ -235,22 +308,19 @@ func UnstowWithCount(dotfilesPath string, configName string, current, total int,
 		opts.ProgressFunc(current, total, fmt.Sprintf("Unstowing %s...", configName))
 	}
 
-	homeDir, err := os.UserHomeDir()
+	// Unstowing never adopts
+	opts.Force = false
+	args, err := stowArgs(dotfilesPath, configName, "-D", opts)
 	if err != nil {
-		return fmt.Errorf("failed to get home directory: %w", err)
+		return err
 	}
-
-	args := []string{"-v", "-D"} // Delete/unstow
-
-	if opts.DryRun {
-		args = append(args, "-n")
+	if !opts.DryRun {
+		if err := saveStowedLinks(dotfilesPath, configName, opts); err != nil {
+			return err
+		}
 	}
 
-	args = append(args, "-t", homeDir)
-	args = append(args, "-d", dotfilesPath)
-	args = append(args, "--", configName)
-
-	output, err := CurrentCommander.Run("stow", args...)
+	output, err := runStow(opts, args...)
 
 	if err != nil {
 		return fmt.Errorf("unstow failed: %w\nOutput: %s", err, string(output))
@@ -280,26 +350,12 @@ func RestowWithCount(dotfilesPath string, configName string, current, total int,
 		opts.ProgressFunc(current, total, fmt.Sprintf("Restowing %s...", configName))
 	}
 
-	homeDir, err := os.UserHomeDir()
+	args, err := stowArgs(dotfilesPath, configName, "-R", opts)
 	if err != nil {
-		return fmt.Errorf("failed to get home directory: %w", err)
+		return err
 	}
 
-	args := []string{"-v", "-R"} // Restow
-
-	if opts.DryRun {
-		args = append(args, "-n")
-	}
-
-	if opts.Force {
-		args = append(args, "--adopt")
-	}
-
-	args = append(args, "-t", homeDir)
-	args = append(args, "-d", dotfilesPath)
-	args = append(args, "--", configName)
-
-	output, err := CurrentCommander.Run("stow", args...)
+	output, err := runStow(opts, args...)
 
 	if err != nil {
 		return fmt.Errorf("restow failed: %w\nOutput: %s", err, string(output))
@@ -322,7 +378,7 @@ func StowConfigs(dotfilesPath string, configs []config.ConfigItem, opts StowOpti
 		current := i + 1
 
 		// Check if config directory exists
-		configPath := filepath.Join(dotfilesPath, cfg.Path)
+		configPath := cfg.Dir(dotfilesPath)
 		if _, err := os.Stat(configPath); os.IsNotExist(err) {
 			result.Skipped = append(result.Skipped, cfg.Name)
 			if opts.ProgressFunc != nil {
@@ -332,7 +388,7 @@ func StowConfigs(dotfilesPath string, configs []config.ConfigItem, opts StowOpti
 		}
 
 		// Stow it
-		err := StowWithCount(dotfilesPath, cfg.Path, current, total, opts)
+		err := StowWithCount(cfg.StowDir(dotfilesPath), cfg.Path, current, total, opts)
 		if err != nil {
 			result.Failed = append(result.Failed, StowError{
 				ConfigName: cfg.Name,
@@ -356,7 +412,7 @@ func UnstowConfigs(dotfilesPath string, configs []config.ConfigItem, opts StowOp
 		current := i + 1
 
 		// Check if config directory exists
-		configPath := filepath.Join(dotfilesPath, cfg.Path)
+		configPath := cfg.Dir(dotfilesPath)
 		if _, err := os.Stat(configPath); os.IsNotExist(err) {
 			result.Skipped = append(result.Skipped, cfg.Name)
 			if opts.ProgressFunc != nil {
@@ -365,7 +421,7 @@ func UnstowConfigs(dotfilesPath string, configs []config.ConfigItem, opts StowOp
 			continue
 		}
 
-		err := UnstowWithCount(dotfilesPath, cfg.Path, current, total, opts)
+		err := UnstowWithCount(cfg.StowDir(dotfilesPath), cfg.Path, current, total, opts)
 		if err != nil {
 			result.Failed = append(result.Failed, StowError{
 				ConfigName: cfg.Name,
@@ -387,7 +443,7 @@ func RestowConfigs(dotfilesPath string, configs []config.ConfigItem, opts StowOp
 
 	for i, cfg := range configs {
 		current := i + 1
-		configPath := filepath.Join(dotfilesPath, cfg.Path)
+		configPath := cfg.Dir(dotfilesPath)
 		if _, err := os.Stat(configPath); os.IsNotExist(err) {
 			result.Skipped = append(result.Skipped, cfg.Name)
 			if opts.ProgressFunc != nil {
@@ -396,7 +452,7 @@ func RestowConfigs(dotfilesPath string, configs []config.ConfigItem, opts StowOp
 			continue
 		}
 
-		err := RestowWithCount(dotfilesPath, cfg.Path, current, total, opts)
+		err := RestowWithCount(cfg.StowDir(dotfilesPath), cfg.Path, current, total, opts)
 		if err != nil {
 			result.Failed = append(result.Failed, StowError{
 				ConfigName: cfg.Name,
@@ -412,19 +468,33 @@ func RestowConfigs(dotfilesPath string, configs []config.ConfigItem, opts StowOp
 
 // IsStowInstalled checks if the GNU stow executable is available in the system PATH.
 func IsStowInstalled() bool {
-	_, err := exec.LookPath("stow")
+	return IsStowBinaryInstalled(config.DefaultStowBinary)
+}
+
+// IsStowBinaryInstalled checks if binary, a name in PATH or a path as set in
+// the config's stow section, is an executable.
+func IsStowBinaryInstalled(binary string) bool {
+	_, err := exec.LookPath(binary)
 	return err == nil
 }
 
 // ValidateStow checks if GNU stow is installed and correctly reports its version.
 // It ensures that the 'stow' command is available and identifies as GNU Stow.
 func ValidateStow() error {
-	if !IsStowInstalled() {
+	return ValidateStowBinary(config.DefaultStowBinary)
+}
+
+// ValidateStowBinary checks that binary is installed and identifies as GNU Stow.
+func ValidateStowBinary(binary string) error {
+	if !IsStowBinaryInstalled(binary) {
+		if binary != config.DefaultStowBinary {
+			return fmt.Errorf("GNU stow is not installed at %s", binary)
+		}
 		return fmt.Errorf("GNU stow is not installed")
 	}
 
 	// Try to get stow version
-	output, err := CurrentCommander.Run("stow", "--version")
+	output, err := CurrentCommander.Run(binary, "--version")
 	if err != nil {
 		return fmt.Errorf("stow command failed: %w", err)
 	}
@@ -436,3 +506,17 @@ func ValidateStow() error {
 
 	return nil
 }
+
+// StowVersion returns the version binary reports, e.g. "2.3.1".
+func StowVersion(binary string) (string, error) {
+	output, err := CurrentCommander.Run(binary, "--version")
+	if err != nil {
+		return "", fmt.Errorf("stow command failed: %w", err)
+	}
+	line, _, _ := strings.Cut(strings.TrimSpace(string(output)), "\n")
+	fields := strings.Fields(line)
+	if len(fields) == 0 {
+		return "", fmt.Errorf("unexpected stow version output: %s", string(output))
+	}
+	return fields[len(fields)-1], nil
+}
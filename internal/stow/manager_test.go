@@ -340,3 +340,43 @@ func TestRestow_RejectsInjection(t *testing.T) {
 		t.Errorf("Expected 'invalid config name' error, got: %v", err)
 	}
 }
+
+func TestStow_UsesStowSettings(t *testing.T) {
+	origCommander := CurrentCommander
+	defer func() { CurrentCommander = origCommander }()
+
+	mock := &MockCommander{}
+	CurrentCommander = mock
+
+	dotfiles := t.TempDir()
+	target := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dotfiles, "bash"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dotfiles, "bash", "dot-bashrc"), []byte("x"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	opts := StowOptions{Settings: config.StowSettings{
+		Binary:    "/opt/homebrew/bin/stow",
+		Flags:     []string{"--dotfiles"},
+		Target:    target,
+		Verbosity: 2,
+	}}
+	if err := Stow(dotfiles, "bash", opts); err != nil {
+		t.Fatalf("Stow() error = %v", err)
+	}
+
+	want := []string{"--verbose=2", "--dotfiles", "-t", target, "-d", dotfiles, "--", "bash"}
+	if strings.Join(mock.LastArgs, " ") != strings.Join(want, " ") {
+		t.Errorf("args = %v, want %v", mock.LastArgs, want)
+	}
+	if _, err := os.Lstat(filepath.Join(target, ".bashrc")); err != nil {
+		t.Errorf("dot-bashrc should be linked as .bashrc in the target: %v", err)
+	}
+
+	opts.Settings.Flags = []string{"--adopt"}
+	if err := Stow(dotfiles, "bash", opts); err == nil {
+		t.Error("Stow() should reject a flag outside the allowlist")
+	}
+}
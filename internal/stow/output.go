@@ -0,0 +1,210 @@
+package stow
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+	"strings"
+
+	"github.com/nvandessel/go4dot/internal/transcript"
+)
+
+// ActionKind is the kind of change or message in stow's verbose output
+type ActionKind string
+
+const (
+	ActionLink     ActionKind = "LINK"
+	ActionUnlink   ActionKind = "UNLINK"
+	ActionMkdir    ActionKind = "MKDIR"
+	ActionRmdir    ActionKind = "RMDIR"
+	ActionMove     ActionKind = "MV"
+	ActionWarning  ActionKind = "WARNING"
+	ActionConflict ActionKind = "CONFLICT"
+)
+
+// Action is one line of stow -v output that matters to the user
+type Action struct {
+	Kind    ActionKind
+	Path    string // Target path relative to the target directory
+	Dest    string // Link destination (LINK) or new location (MV)
+	Message string // Text of a warning or conflict
+	Reverts bool   // Undoes an earlier action, as restow does for unchanged links
+	Config  string // Config being linked, set when several are linked at once
+}
+
+// IsProblem reports whether the action is a warning or conflict rather than
+// a change
+func (a Action) IsProblem() bool {
+	return a.Kind == ActionWarning || a.Kind == ActionConflict
+}
+
+// String returns a short description, e.g. "LINK .config/nvim/init.lua"
+func (a Action) String() string {
+	if a.IsProblem() {
+		return a.Message
+	}
+	if a.Kind == ActionMove {
+		return fmt.Sprintf("MV %s -> %s", a.Path, a.Dest)
+	}
+	return fmt.Sprintf("%s %s", a.Kind, a.Path)
+}
+
+const revertsSuffix = " (reverts previous action)"
+
+// ParseOutputLine parses a line of stow -v output. Lines that only trace
+// stow's planning return false.
+func ParseOutputLine(line string) (Action, bool) {
+	line = strings.TrimRight(line, "\r\n")
+	trimmed := strings.TrimSpace(line)
+	if trimmed == "" {
+		return Action{}, false
+	}
+
+	// Conflicts are listed as bullets below a "WARNING! ... conflicts" line
+	if strings.HasPrefix(trimmed, "* ") {
+		return Action{Kind: ActionConflict, Message: strings.TrimPrefix(trimmed, "* ")}, true
+	}
+	if strings.HasPrefix(trimmed, "WARNING") || strings.HasPrefix(trimmed, "BUG in ") {
+		return Action{Kind: ActionWarning, Message: trimmed}, true
+	}
+
+	kind, rest, ok := strings.Cut(trimmed, " ")
+	if !ok {
+		return Action{}, false
+	}
+	a := Action{Kind: ActionKind(strings.TrimSuffix(kind, ":"))}
+	if strings.HasSuffix(rest, revertsSuffix) {
+		a.Reverts = true
+		rest = strings.TrimSuffix(rest, revertsSuffix)
+	}
+
+	switch a.Kind {
+	case ActionLink:
+		a.Path, a.Dest, _ = strings.Cut(rest, " => ")
+	case ActionMove:
+		a.Path, a.Dest, _ = strings.Cut(rest, " -> ")
+	case ActionUnlink, ActionMkdir, ActionRmdir:
+		a.Path = rest
+	default:
+		return Action{}, false
+	}
+	return a, true
+}
+
+// outputParser turns stow output into actions as lines arrive. Restowing an
+// unchanged package unlinks and relinks every file; those pairs cancel out
+// and are dropped, so only real changes are reported.
+type outputParser struct {
+	emit    func(Action)
+	pending *Action // An unlink or rmdir that the next line may revert
+}
+
+func (p *outputParser) line(line string) {
+	a, ok := ParseOutputLine(line)
+	if !ok {
+		return
+	}
+	if p.pending != nil {
+		pending := *p.pending
+		p.pending = nil
+		if a.Reverts && a.Path == pending.Path {
+			return
+		}
+		p.emit(pending)
+	}
+	if a.Reverts {
+		return
+	}
+	if a.Kind == ActionUnlink || a.Kind == ActionRmdir {
+		p.pending = &a
+		return
+	}
+	p.emit(a)
+}
+
+func (p *outputParser) flush() {
+	if p.pending != nil {
+		p.emit(*p.pending)
+		p.pending = nil
+	}
+}
+
+// ParseOutput returns the actions in complete stow -v output
+func ParseOutput(output string) []Action {
+	var actions []Action
+	p := &outputParser{emit: func(a Action) { actions = append(actions, a) }}
+	for _, line := range strings.Split(output, "\n") {
+		p.line(line)
+	}
+	p.flush()
+	return actions
+}
+
+// LineCommander is a Commander that can also report output lines as the
+// command writes them
+type LineCommander interface {
+	Commander
+	RunLines(onLine func(string), name string, args ...string) ([]byte, error)
+}
+
+// RunLines executes a command, calling onLine for each line of combined
+// output as it is written.
+func (e *ExecCommander) RunLines(onLine func(string), name string, args ...string) ([]byte, error) {
+	cmd := exec.Command(name, args...)
+	w := &lineWriter{onLine: onLine}
+	// One writer for both, so exec writes from a single goroutine at a time
+	cmd.Stdout = w
+	cmd.Stderr = w
+	err := transcript.Run(cmd)
+	w.flush()
+	return w.buf.Bytes(), err
+}
+
+// lineWriter keeps everything written to it and calls onLine per line
+type lineWriter struct {
+	buf    bytes.Buffer
+	line   []byte
+	onLine func(string)
+}
+
+func (w *lineWriter) Write(b []byte) (int, error) {
+	w.buf.Write(b)
+	for _, c := range b {
+		if c == '\n' {
+			w.onLine(string(w.line))
+			w.line = w.line[:0]
+			continue
+		}
+		w.line = append(w.line, c)
+	}
+	return len(b), nil
+}
+
+func (w *lineWriter) flush() {
+	if len(w.line) > 0 {
+		w.onLine(string(w.line))
+		w.line = w.line[:0]
+	}
+}
+
+// runStow runs stow, reporting its actions to opts.ActionFunc as they
+// happen when set. Commanders that cannot stream, such as test mocks, are
+// parsed once the command exits.
+func runStow(opts StowOptions, args ...string) ([]byte, error) {
+	binary := opts.Settings.BinaryPath()
+	if opts.ActionFunc == nil {
+		return CurrentCommander.Run(binary, args...)
+	}
+
+	p := &outputParser{emit: opts.ActionFunc}
+	defer p.flush()
+
+	if lc, ok := CurrentCommander.(LineCommander); ok {
+		return lc.RunLines(p.line, binary, args...)
+	}
+	output, err := CurrentCommander.Run(binary, args...)
+	for _, line := range strings.Split(string(output), "\n") {
+		p.line(line)
+	}
+	return output, err
+}
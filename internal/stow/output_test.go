@@ -0,0 +1,121 @@
+package stow
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseOutputLine(t *testing.T) {
+	tests := []struct {
+		line string
+		want Action
+		ok   bool
+	}{
+		{"LINK: .config/nvim/init.lua => ../dotfiles/nvim/.config/nvim/init.lua",
+			Action{Kind: ActionLink, Path: ".config/nvim/init.lua", Dest: "../dotfiles/nvim/.config/nvim/init.lua"}, true},
+		{"LINK: .zshrc => dotfiles/zsh/.zshrc (reverts previous action)",
+			Action{Kind: ActionLink, Path: ".zshrc", Dest: "dotfiles/zsh/.zshrc", Reverts: true}, true},
+		{"UNLINK: .zshrc", Action{Kind: ActionUnlink, Path: ".zshrc"}, true},
+		{"MKDIR: .config/nvim", Action{Kind: ActionMkdir, Path: ".config/nvim"}, true},
+		{"RMDIR .config/nvim", Action{Kind: ActionRmdir, Path: ".config/nvim"}, true},
+		{"MV: .bashrc -> dotfiles/bash/.bashrc", Action{Kind: ActionMove, Path: ".bashrc", Dest: "dotfiles/bash/.bashrc"}, true},
+		{"WARNING! stowing zsh would cause conflicts:",
+			Action{Kind: ActionWarning, Message: "WARNING! stowing zsh would cause conflicts:"}, true},
+		{"  * existing target is neither a link nor a directory: .zshrc",
+			Action{Kind: ActionConflict, Message: "existing target is neither a link nor a directory: .zshrc"}, true},
+		{"BUG in find_stowed_path? Absolute/relative mismatch between Stow dir dotfiles and path /home/a/.zshrc",
+			Action{Kind: ActionWarning, Message: "BUG in find_stowed_path? Absolute/relative mismatch between Stow dir dotfiles and path /home/a/.zshrc"}, true},
+		{"Planning stow of package zsh... done", Action{}, false},
+		{"", Action{}, false},
+	}
+
+	for _, tt := range tests {
+		got, ok := ParseOutputLine(tt.line)
+		if ok != tt.ok || got != tt.want {
+			t.Errorf("ParseOutputLine(%q) = %+v, %v, want %+v, %v", tt.line, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestParseOutput_DropsRestowNoOps(t *testing.T) {
+	output := strings.Join([]string{
+		"UNLINK: .zshrc",
+		"LINK: .zshrc => dotfiles/zsh/.zshrc (reverts previous action)",
+		"UNLINK: .zprofile",
+		"LINK: .zsh/aliases.zsh => ../dotfiles/zsh/.zsh/aliases.zsh",
+		"RMDIR .zsh/old",
+	}, "\n")
+
+	var got []string
+	for _, a := range ParseOutput(output) {
+		got = append(got, a.String())
+	}
+	want := []string{"UNLINK .zprofile", "LINK .zsh/aliases.zsh", "RMDIR .zsh/old"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ParseOutput() = %v, want %v", got, want)
+	}
+}
+
+// lineCommander streams canned output line by line
+type lineCommander struct {
+	output string
+}
+
+func (c *lineCommander) Run(name string, args ...string) ([]byte, error) {
+	return []byte(c.output), nil
+}
+
+func (c *lineCommander) RunLines(onLine func(string), name string, args ...string) ([]byte, error) {
+	for _, line := range strings.Split(c.output, "\n") {
+		onLine(line)
+	}
+	return []byte(c.output), nil
+}
+
+func TestRunStow_ActionFunc(t *testing.T) {
+	output := "MKDIR: .config/kitty\nLINK: .config/kitty/kitty.conf => ../../dotfiles/kitty/.config/kitty/kitty.conf\nWARNING: in simulation mode so not modifying filesystem."
+	want := []string{"MKDIR .config/kitty", "LINK .config/kitty/kitty.conf", "WARNING: in simulation mode so not modifying filesystem."}
+
+	orig := CurrentCommander
+	defer func() { CurrentCommander = orig }()
+
+	// Streaming commanders and plain ones report the same actions
+	for _, commander := range []Commander{&lineCommander{output}, &mockOutputCommander{output}} {
+		CurrentCommander = commander
+
+		var got []string
+		opts := StowOptions{ActionFunc: func(a Action) { got = append(got, a.String()) }}
+		if err := Stow("/dotfiles", "kitty", opts); err != nil {
+			t.Fatalf("Stow() error = %v", err)
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("%T: actions = %v, want %v", commander, got, want)
+		}
+	}
+}
+
+// mockOutputCommander returns canned output and cannot stream
+type mockOutputCommander struct {
+	output string
+}
+
+func (c *mockOutputCommander) Run(name string, args ...string) ([]byte, error) {
+	return []byte(c.output), nil
+}
+
+func TestExecCommander_RunLines(t *testing.T) {
+	var lines []string
+	out, err := (&ExecCommander{}).RunLines(func(line string) { lines = append(lines, line) },
+		"sh", "-c", "echo 'LINK: a => b'; echo 'WARNING: w' >&2; printf 'UNLINK: c'")
+	if err != nil {
+		t.Fatalf("RunLines() error = %v", err)
+	}
+	want := []string{"LINK: a => b", "WARNING: w", "UNLINK: c"}
+	if !reflect.DeepEqual(lines, want) {
+		t.Errorf("lines = %q, want %q", lines, want)
+	}
+	if string(out) != "LINK: a => b\nWARNING: w\nUNLINK: c" {
+		t.Errorf("output = %q", out)
+	}
+}
@@ -0,0 +1,203 @@
+package stow
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sync"
+
+	"github.com/nvandessel/go4dot/internal/config"
+	"github.com/nvandessel/go4dot/internal/feature"
+)
+
+// maxJobs caps how many stow processes run at once by default; beyond it
+// they mostly wait on the same disk
+const maxJobs = 8
+
+// DefaultJobs is how many configs are restowed at once when StowOptions
+// leaves Jobs unset: one per CPU, up to maxJobs, or one at a time with the
+// parallel-restow feature turned off
+func DefaultJobs() int {
+	if !feature.Enabled(feature.ParallelRestow) {
+		return 1
+	}
+	if n := runtime.NumCPU(); n < maxJobs {
+		return n
+	}
+	return maxJobs
+}
+
+// RestowConfigsConcurrently restows configs like RestowConfigs, running up
+// to jobs stow processes at once. A config starts only after the configs it
+// depends on, and after earlier configs it shares a target directory with
+// that is not a real directory yet, since stow may fold that directory into
+// a link the other would then have to split. Callbacks in opts are never
+// called concurrently, and the actions passed to ActionFunc name their
+// config. The result lists configs in the order given.
+func RestowConfigsConcurrently(dotfilesPath string, configs []config.ConfigItem, jobs int, opts StowOptions) *StowResult {
+	if jobs <= 0 {
+		jobs = DefaultJobs()
+	}
+	if jobs == 1 || len(configs) < 2 {
+		return RestowConfigs(dotfilesPath, configs, opts)
+	}
+
+	// Callbacks are written for one config at a time
+	var mu sync.Mutex
+	progress := opts.ProgressFunc
+	if progress != nil {
+		opts.ProgressFunc = func(current, total int, msg string) {
+			mu.Lock()
+			defer mu.Unlock()
+			progress(current, total, msg)
+		}
+	}
+	action := opts.ActionFunc
+
+	type outcome struct {
+		skipped bool
+		err     error
+	}
+	outcomes := make([]outcome, len(configs))
+	preds := linkOrder(dotfilesPath, configs, opts.Settings)
+	done := make([]chan struct{}, len(configs))
+	for i := range done {
+		done[i] = make(chan struct{})
+	}
+	slots := make(chan struct{}, jobs)
+	total := len(configs)
+
+	var wg sync.WaitGroup
+	for i, item := range configs {
+		wg.Add(1)
+		go func(i int, item config.ConfigItem) {
+			defer wg.Done()
+			defer close(done[i])
+			for _, p := range preds[i] {
+				<-done[p]
+			}
+			slots <- struct{}{}
+			defer func() { <-slots }()
+
+			if _, err := os.Stat(item.Dir(dotfilesPath)); os.IsNotExist(err) {
+				outcomes[i].skipped = true
+				if opts.ProgressFunc != nil {
+					opts.ProgressFunc(i+1, total, fmt.Sprintf("⊘ Skipped %s (directory not found)", item.Name))
+				}
+				return
+			}
+
+			itemOpts := opts
+			if action != nil {
+				itemOpts.ActionFunc = func(a Action) {
+					a.Config = item.Name
+					mu.Lock()
+					defer mu.Unlock()
+					action(a)
+				}
+			}
+			outcomes[i].err = RestowWithCount(item.StowDir(dotfilesPath), item.Path, i+1, total, itemOpts)
+		}(i, item)
+	}
+	wg.Wait()
+
+	result := &StowResult{}
+	for i, item := range configs {
+		switch o := outcomes[i]; {
+		case o.skipped:
+			result.Skipped = append(result.Skipped, item.Name)
+		case o.err != nil:
+			result.Failed = append(result.Failed, StowError{ConfigName: item.Name, Error: o.err})
+		default:
+			result.Success = append(result.Success, item.Name)
+		}
+	}
+	return result
+}
+
+// linkOrder returns, for each config, the indexes of the configs that must
+// be linked before it: those it depends on, and earlier ones sharing a
+// target directory stow may fold. Configs are taken in dependency order,
+// so the result never has a cycle.
+func linkOrder(dotfilesPath string, configs []config.ConfigItem, settings config.StowSettings) [][]int {
+	index := make(map[string]int, len(configs))
+	for i, item := range configs {
+		index[item.Name] = i
+	}
+
+	// Depth-first over depends_on, keeping the given order otherwise
+	var order []int
+	visited := make([]bool, len(configs))
+	var visit func(i int)
+	visit = func(i int) {
+		if visited[i] {
+			return
+		}
+		visited[i] = true
+		for _, dep := range configs[i].DependsOn {
+			if j, ok := index[dep]; ok {
+				visit(j)
+			}
+		}
+		order = append(order, i)
+	}
+	for i := range configs {
+		visit(i)
+	}
+
+	target := settings.TargetDir()
+	dirs := make([]map[string]bool, len(configs))
+	for i, item := range configs {
+		dirs[i] = unsettledDirs(item.Dir(dotfilesPath), target, settings)
+	}
+
+	preds := make([][]int, len(configs))
+	for k, i := range order {
+		for _, dep := range configs[i].DependsOn {
+			if j, ok := index[dep]; ok && j != i {
+				preds[i] = append(preds[i], j)
+			}
+		}
+		for _, j := range order[:k] {
+			if sharesDir(dirs[i], dirs[j]) {
+				preds[i] = append(preds[i], j)
+			}
+		}
+	}
+	return preds
+}
+
+// unsettledDirs returns the directories of the package at packageDir, as
+// linked below target, that are not real directories in target yet
+func unsettledDirs(packageDir, target string, settings config.StowSettings) map[string]bool {
+	dirs := make(map[string]bool)
+	_ = filepath.WalkDir(packageDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || path == packageDir || !d.IsDir() {
+			return nil
+		}
+		rel, err := filepath.Rel(packageDir, path)
+		if err != nil {
+			return nil
+		}
+		link := settings.LinkPath(rel)
+		if info, err := os.Lstat(filepath.Join(target, link)); err != nil || !info.IsDir() {
+			dirs[link] = true
+		}
+		return nil
+	})
+	return dirs
+}
+
+func sharesDir(a, b map[string]bool) bool {
+	if len(b) < len(a) {
+		a, b = b, a
+	}
+	for dir := range a {
+		if b[dir] {
+			return true
+		}
+	}
+	return false
+}
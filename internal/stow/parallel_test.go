@@ -0,0 +1,147 @@
+package stow
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/nvandessel/go4dot/internal/config"
+	"github.com/nvandessel/go4dot/internal/feature"
+)
+
+// slowCommander pretends each stow run takes a while, recording when each
+// package starts and ends and how many run at once
+type slowCommander struct {
+	mu      sync.Mutex
+	running int
+	peak    int
+	events  []string
+}
+
+func (c *slowCommander) Run(name string, args ...string) ([]byte, error) {
+	pkg := args[len(args)-1]
+	c.mu.Lock()
+	c.events = append(c.events, "start "+pkg)
+	c.running++
+	if c.running > c.peak {
+		c.peak = c.running
+	}
+	c.mu.Unlock()
+
+	time.Sleep(20 * time.Millisecond)
+
+	c.mu.Lock()
+	c.running--
+	c.events = append(c.events, "end "+pkg)
+	c.mu.Unlock()
+	return []byte(fmt.Sprintf("LINK: .%src => %s/.%src\n", pkg, pkg, pkg)), nil
+}
+
+func writePackages(t *testing.T, dotfilesPath string, files ...string) {
+	t.Helper()
+	for _, f := range files {
+		path := filepath.Join(dotfilesPath, f)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte("x"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestLinkOrder(t *testing.T) {
+	dotfilesPath, homeDir, cleanup := setupSyncTestEnv(t)
+	defer cleanup()
+
+	writePackages(t, dotfilesPath,
+		"zsh/.zshrc",
+		"starship/.config/starship.toml",
+		"nvim/.config/nvim/init.lua",
+		"kitty/.config/kitty/kitty.conf",
+		"git/.gitconfig",
+	)
+	configs := []config.ConfigItem{
+		{Name: "starship", Path: "starship", DependsOn: []string{"zsh"}},
+		{Name: "zsh", Path: "zsh"},
+		{Name: "nvim", Path: "nvim"},
+		{Name: "kitty", Path: "kitty"},
+		{Name: "git", Path: "git"},
+	}
+
+	// ~/.config doesn't exist, so stow may fold it for whichever comes first
+	preds := linkOrder(dotfilesPath, configs, config.StowSettings{})
+	want := [][]int{{1}, nil, {0}, {0, 2}, nil}
+	if !reflect.DeepEqual(preds, want) {
+		t.Errorf("linkOrder() = %v, want %v", preds, want)
+	}
+
+	// Once it is a real directory, only the dependency orders them
+	if err := os.MkdirAll(filepath.Join(homeDir, ".config"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	preds = linkOrder(dotfilesPath, configs, config.StowSettings{})
+	want = [][]int{{1}, nil, nil, nil, nil}
+	if !reflect.DeepEqual(preds, want) {
+		t.Errorf("linkOrder() with ~/.config = %v, want %v", preds, want)
+	}
+}
+
+func TestRestowConfigsConcurrently(t *testing.T) {
+	dotfilesPath, _, cleanup := setupSyncTestEnv(t)
+	defer cleanup()
+	commander := &slowCommander{}
+	CurrentCommander = commander
+
+	writePackages(t, dotfilesPath, "a/.arc", "b/.brc", "c/.crc", "d/.drc")
+	configs := []config.ConfigItem{
+		{Name: "a", Path: "a", DependsOn: []string{"d"}},
+		{Name: "b", Path: "b"},
+		{Name: "c", Path: "c"},
+		{Name: "d", Path: "d"},
+		{Name: "missing", Path: "missing"},
+	}
+
+	var actions []Action
+	result := RestowConfigsConcurrently(dotfilesPath, configs, 3, StowOptions{
+		ActionFunc: func(a Action) { actions = append(actions, a) },
+	})
+
+	if !reflect.DeepEqual(result.Success, []string{"a", "b", "c", "d"}) || !reflect.DeepEqual(result.Skipped, []string{"missing"}) {
+		t.Errorf("result = %+v", result)
+	}
+	if commander.peak < 2 || commander.peak > 3 {
+		t.Errorf("peak concurrency = %d, want 2 or 3", commander.peak)
+	}
+	index := make(map[string]int)
+	for i, e := range commander.events {
+		index[e] = i
+	}
+	if index["start a"] < index["end d"] {
+		t.Errorf("a started before its dependency d ended: %v", commander.events)
+	}
+	if len(actions) != 4 {
+		t.Fatalf("actions = %+v, want one per config", actions)
+	}
+	for _, a := range actions {
+		if a.Config == "" || a.Path != "."+a.Config+"rc" {
+			t.Errorf("action %+v does not name its config", a)
+		}
+	}
+}
+
+func TestDefaultJobs_ParallelRestowOff(t *testing.T) {
+	t.Cleanup(func() { feature.Configure(nil, nil) })
+
+	if DefaultJobs() < 1 {
+		t.Errorf("DefaultJobs() = %d, want at least 1", DefaultJobs())
+	}
+	feature.Configure(map[string]bool{feature.ParallelRestow: false}, nil)
+	if got := DefaultJobs(); got != 1 {
+		t.Errorf("DefaultJobs() with parallel-restow off = %d, want 1", got)
+	}
+}
@@ -0,0 +1,120 @@
+package stow
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/nvandessel/go4dot/internal/config"
+	"github.com/nvandessel/go4dot/internal/state"
+)
+
+// Reasons a sync plan restows a config
+const (
+	PlanReasonNew     = "not linked by go4dot before"
+	PlanReasonChanged = "files added or removed"
+	PlanReasonDrift   = "links out of place"
+	PlanReasonAll     = "restow of all configs requested"
+)
+
+// SyncPlan is what a sync of all configs has to restow. Restowing a config
+// rewrites all of its links, which is slow on slow disks and churns their
+// mtimes, so configs whose files and links are as the last sync left them
+// are left alone.
+type SyncPlan struct {
+	Restow       []config.ConfigItem
+	Unchanged    []config.ConfigItem
+	Reasons      map[string]string // Why each config in Restow is restowed
+	Fingerprints map[string]string // Each config's current fingerprint, to record once linked
+}
+
+// PlanSync compares each config's files against the fingerprint recorded in
+// st when it was last linked and checks drift for its links. A config is
+// restowed if it has no fingerprint, its files were added, removed or
+// renamed, or drift shows links missing, wrong or orphaned. With all set,
+// or without state, every config is restowed.
+func PlanSync(cfg *config.Config, dotfilesPath string, st *state.State, drift *DriftSummary, all bool) *SyncPlan {
+	plan := &SyncPlan{
+		Reasons:      make(map[string]string),
+		Fingerprints: Fingerprints(cfg, dotfilesPath),
+	}
+
+	for _, item := range cfg.GetAllConfigs() {
+		var reason string
+		recorded, ok := "", false
+		if st != nil {
+			recorded, ok = st.GetFingerprint(item.Name)
+		}
+
+		switch {
+		case all:
+			reason = PlanReasonAll
+		case !ok:
+			reason = PlanReasonNew
+		case recorded != plan.Fingerprints[item.Name]:
+			reason = PlanReasonChanged
+		case drift != nil && drift.ResultByName(item.Name) != nil && drift.ResultByName(item.Name).HasDrift:
+			reason = PlanReasonDrift
+		}
+
+		if reason == "" {
+			plan.Unchanged = append(plan.Unchanged, item)
+			continue
+		}
+		plan.Restow = append(plan.Restow, item)
+		plan.Reasons[item.Name] = reason
+	}
+	return plan
+}
+
+// Summary describes the plan, e.g. "12 configs unchanged, 2 to restow"
+func (p *SyncPlan) Summary() string {
+	return fmt.Sprintf("%s unchanged, %d to restow", pluralConfigs(len(p.Unchanged)), len(p.Restow))
+}
+
+// Fingerprints returns a fingerprint of the files in each config's package,
+// as ScanPackages lists them, and the stow settings that decide where they
+// are linked. Edits to a file do not change it: they show through the link.
+func Fingerprints(cfg *config.Config, dotfilesPath string) map[string]string {
+	settings := strings.Join(cfg.Stow.Flags, " ") + "\x00" + cfg.Stow.TargetDir()
+	fingerprints := make(map[string]string)
+	for name, files := range ScanPackages(cfg, dotfilesPath) {
+		paths := make([]string, 0, len(files))
+		for path := range files {
+			paths = append(paths, path)
+		}
+		sort.Strings(paths)
+
+		h := sha256.New()
+		h.Write([]byte(settings + "\n"))
+		for _, path := range paths {
+			h.Write([]byte(path + "\n"))
+		}
+		fingerprints[name] = hex.EncodeToString(h.Sum(nil))
+	}
+	return fingerprints
+}
+
+// RecordFingerprints records in st the current fingerprints of the named
+// configs, after they were linked
+func RecordFingerprints(cfg *config.Config, dotfilesPath string, st *state.State, names []string) {
+	if st == nil || len(names) == 0 {
+		return
+	}
+	fingerprints := Fingerprints(cfg, dotfilesPath)
+	for _, name := range names {
+		if fingerprint, ok := fingerprints[name]; ok {
+			st.SetFingerprint(name, fingerprint)
+		}
+	}
+}
+
+// pluralConfigs formats a config count, e.g. "1 config" or "12 configs"
+func pluralConfigs(n int) string {
+	if n == 1 {
+		return "1 config"
+	}
+	return fmt.Sprintf("%d configs", n)
+}
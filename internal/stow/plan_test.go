@@ -0,0 +1,140 @@
+package stow
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/nvandessel/go4dot/internal/config"
+	"github.com/nvandessel/go4dot/internal/state"
+)
+
+// planNames returns the names of items
+func planNames(items []config.ConfigItem) []string {
+	var names []string
+	for _, item := range items {
+		names = append(names, item.Name)
+	}
+	return names
+}
+
+func TestPlanSync(t *testing.T) {
+	dotfilesPath := t.TempDir()
+	writeTestFile(t, filepath.Join(dotfilesPath, "nvim", ".config", "nvim", "init.lua"), "-- nvim\n")
+	writeTestFile(t, filepath.Join(dotfilesPath, "zsh", ".zshrc"), "# zsh\n")
+	writeTestFile(t, filepath.Join(dotfilesPath, "git", ".gitconfig"), "[user]\n")
+
+	cfg := &config.Config{
+		Configs: config.ConfigGroups{
+			Core: []config.ConfigItem{
+				{Name: "nvim", Path: "nvim"},
+				{Name: "zsh", Path: "zsh"},
+				{Name: "git", Path: "git"},
+			},
+		},
+	}
+	st := state.New()
+	RecordFingerprints(cfg, dotfilesPath, st, []string{"nvim", "zsh"})
+
+	// Editing a file changes nothing, adding one does
+	writeTestFile(t, filepath.Join(dotfilesPath, "nvim", ".config", "nvim", "init.lua"), "-- edited\n")
+	writeTestFile(t, filepath.Join(dotfilesPath, "zsh", ".zprofile"), "# new\n")
+
+	plan := PlanSync(cfg, dotfilesPath, st, nil, false)
+	if got := planNames(plan.Unchanged); !reflect.DeepEqual(got, []string{"nvim"}) {
+		t.Errorf("Unchanged = %v, want [nvim]", got)
+	}
+	if got := planNames(plan.Restow); !reflect.DeepEqual(got, []string{"zsh", "git"}) {
+		t.Errorf("Restow = %v, want [zsh git]", got)
+	}
+	if plan.Reasons["zsh"] != PlanReasonChanged || plan.Reasons["git"] != PlanReasonNew {
+		t.Errorf("Reasons = %v", plan.Reasons)
+	}
+	if got := plan.Summary(); got != "1 config unchanged, 2 to restow" {
+		t.Errorf("Summary() = %q", got)
+	}
+
+	// Drift restows a config whose files did not change
+	drift := &DriftSummary{Results: []DriftResult{{ConfigName: "nvim", HasDrift: true}}}
+	plan = PlanSync(cfg, dotfilesPath, st, drift, false)
+	if plan.Reasons["nvim"] != PlanReasonDrift {
+		t.Errorf("Reasons[nvim] = %q, want %q", plan.Reasons["nvim"], PlanReasonDrift)
+	}
+
+	plan = PlanSync(cfg, dotfilesPath, st, nil, true)
+	if len(plan.Restow) != 3 || len(plan.Unchanged) != 0 {
+		t.Errorf("with all: Restow = %v, Unchanged = %v", planNames(plan.Restow), planNames(plan.Unchanged))
+	}
+}
+
+func TestSyncAll_OnlyRestowsChanged(t *testing.T) {
+	dotfilesPath, homeDir, cleanup := setupSyncTestEnv(t)
+	defer cleanup()
+
+	for _, pkg := range []string{"pkg1", "pkg2"} {
+		writeTestFile(t, filepath.Join(dotfilesPath, pkg, pkg+".txt"), pkg)
+	}
+	cfg := &config.Config{
+		Configs: config.ConfigGroups{
+			Core: []config.ConfigItem{
+				{Name: "pkg1", Path: "pkg1"},
+				{Name: "pkg2", Path: "pkg2"},
+			},
+		},
+	}
+	st := state.New()
+
+	result, err := SyncAll(dotfilesPath, cfg, st, false, StowOptions{})
+	if err != nil {
+		t.Fatalf("SyncAll failed: %v", err)
+	}
+	if len(result.Success) != 2 || len(result.Unchanged) != 0 {
+		t.Fatalf("first sync: Success = %v, Unchanged = %v", result.Success, result.Unchanged)
+	}
+
+	// Nothing changed: nothing is restowed
+	result, err = SyncAll(dotfilesPath, cfg, st, false, StowOptions{})
+	if err != nil {
+		t.Fatalf("SyncAll failed: %v", err)
+	}
+	if len(result.Success) != 0 || len(result.Unchanged) != 2 {
+		t.Errorf("second sync: Success = %v, Unchanged = %v", result.Success, result.Unchanged)
+	}
+
+	// A new file in pkg2 restows only pkg2
+	writeTestFile(t, filepath.Join(dotfilesPath, "pkg2", "extra.txt"), "extra")
+	result, err = SyncAll(dotfilesPath, cfg, st, false, StowOptions{})
+	if err != nil {
+		t.Fatalf("SyncAll failed: %v", err)
+	}
+	if !reflect.DeepEqual(result.Success, []string{"pkg2"}) || !reflect.DeepEqual(result.Unchanged, []string{"pkg1"}) {
+		t.Errorf("after adding a file: Success = %v, Unchanged = %v", result.Success, result.Unchanged)
+	}
+	if _, err := os.Lstat(filepath.Join(homeDir, "extra.txt")); err != nil {
+		t.Errorf("extra.txt was not linked: %v", err)
+	}
+	if got := SyncResultSummary(result); got != "1 config unchanged, 1 restowed" {
+		t.Errorf("SyncResultSummary() = %q", got)
+	}
+
+	// A deleted link restows its config
+	if err := os.Remove(filepath.Join(homeDir, "pkg1.txt")); err != nil {
+		t.Fatal(err)
+	}
+	result, err = SyncAll(dotfilesPath, cfg, st, false, StowOptions{})
+	if err != nil {
+		t.Fatalf("SyncAll failed: %v", err)
+	}
+	if !reflect.DeepEqual(result.Success, []string{"pkg1"}) {
+		t.Errorf("after deleting a link: Success = %v, want [pkg1]", result.Success)
+	}
+
+	result, err = SyncAll(dotfilesPath, cfg, st, false, StowOptions{RestowAll: true})
+	if err != nil {
+		t.Fatalf("SyncAll failed: %v", err)
+	}
+	if len(result.Success) != 2 {
+		t.Errorf("with RestowAll: Success = %v, want both configs", result.Success)
+	}
+}
@@ -6,12 +6,16 @@ import (
 	"path/filepath"
 
 	"github.com/charmbracelet/huh"
+	"github.com/nvandessel/go4dot/internal/config"
 	"github.com/nvandessel/go4dot/internal/print"
 )
 
 // ResolveConflicts prompts the user to handle conflicting files.
+// When useTrash is set, deleted files are moved to the OS trash. The
+// delete choice is pre-selected when strategy (the configs' shared
+// on_conflict value) is overwrite.
 // Returns true if conflicts were resolved, false if cancelled.
-func ResolveConflicts(conflicts []ConflictFile) bool {
+func ResolveConflicts(conflicts []ConflictFile, useTrash bool, strategy string) bool {
 	fmt.Printf("\n  Found %d conflicting file(s) that would be overwritten:\n\n", len(conflicts))
 
 	// Group by config
@@ -36,7 +40,10 @@ func ResolveConflicts(conflicts []ConflictFile) bool {
 
 	fmt.Println()
 
-	var action string
+	action := "backup"
+	if strategy == config.ConflictOverwrite {
+		action = "delete"
+	}
 	form := huh.NewForm(
 		huh.NewGroup(
 			huh.NewSelect[string]().
@@ -68,6 +75,13 @@ func ResolveConflicts(conflicts []ConflictFile) bool {
 				relPath, _ := filepath.Rel(home, conflict.TargetPath)
 				fmt.Printf("  Backed up ~/%s\n", relPath)
 			}
+		} else if useTrash {
+			err = TrashConflict(conflict)
+			if err == nil {
+				home := os.Getenv("HOME")
+				relPath, _ := filepath.Rel(home, conflict.TargetPath)
+				fmt.Printf("  Moved ~/%s to trash\n", relPath)
+			}
 		} else {
 			err = RemoveConflict(conflict)
 			if err == nil {
@@ -86,3 +100,100 @@ func ResolveConflicts(conflicts []ConflictFile) bool {
 	fmt.Println()
 	return true
 }
+
+// maxPreviewLines caps how much of a diff is printed per file
+const maxPreviewLines = 20
+
+// PromptAdoptConflicts walks through each conflicting file, shows a diff
+// against the repo copy and asks whether to adopt it into the repo, keep it
+// as a host variant, or back it up. Files identical to the repo copy are
+// adopted without asking. Returns false if the user cancelled.
+func PromptAdoptConflicts(conflicts []ConflictFile, dotfilesPath string) bool {
+	home := os.Getenv("HOME")
+	variant, err := os.Hostname()
+	if err != nil || variant == "" {
+		variant = "local"
+	}
+
+	fmt.Printf("\n  Found %d existing file(s) to adopt:\n", len(conflicts))
+
+	for i, conflict := range conflicts {
+		relPath, _ := filepath.Rel(home, conflict.TargetPath)
+
+		if ConflictIdentical(conflict) {
+			if err := AdoptConflict(conflict); err != nil {
+				print.Error("Failed to adopt %s: %v", conflict.TargetPath, err)
+				return false
+			}
+			fmt.Printf("  Adopted ~/%s (identical to repo)\n", relPath)
+			continue
+		}
+
+		fmt.Printf("\n  [%d/%d] ~/%s (%s)\n", i+1, len(conflicts), relPath, conflict.ConfigName)
+		if diff, err := ConflictDiff(conflict); err == nil {
+			fmt.Println("    --- repo")
+			fmt.Println("    +++ home")
+			for n, line := range diff {
+				if n >= maxPreviewLines {
+					fmt.Printf("    ... %d more line(s)\n", len(diff)-maxPreviewLines)
+					break
+				}
+				fmt.Printf("    %s\n", line)
+			}
+		}
+
+		options := []huh.Option[string]{
+			huh.NewOption("Adopt (replace repo copy with this file)", "adopt"),
+			huh.NewOption(fmt.Sprintf("Keep as variant (%s/%s)", VariantsDir, variant), "variant"),
+			huh.NewOption("Backup (rename to .g4d-backup) and use repo version", "backup"),
+			huh.NewOption("Cancel sync", "cancel"),
+		}
+		if conflict.IsDir {
+			options = []huh.Option[string]{
+				huh.NewOption("Backup (rename to .g4d-backup) and use repo version", "backup"),
+				huh.NewOption("Cancel sync", "cancel"),
+			}
+		}
+
+		var action string
+		form := huh.NewForm(
+			huh.NewGroup(
+				huh.NewSelect[string]().
+					Title("What should happen to this file?").
+					Options(options...).
+					Value(&action),
+			),
+		)
+
+		if err := form.Run(); err != nil || action == "cancel" {
+			return false
+		}
+
+		switch action {
+		case "adopt":
+			err = AdoptConflict(conflict)
+			if err == nil {
+				fmt.Printf("  Adopted ~/%s\n", relPath)
+			}
+		case "variant":
+			var dest string
+			dest, err = AdoptConflictAsVariant(conflict, dotfilesPath, variant)
+			if err == nil {
+				fmt.Printf("  Stored ~/%s as %s\n", relPath, dest)
+			}
+		case "backup":
+			err = BackupConflict(conflict)
+			if err == nil {
+				fmt.Printf("  Backed up ~/%s\n", relPath)
+			}
+		}
+
+		if err != nil {
+			print.Error("Failed to handle %s: %v", conflict.TargetPath, err)
+			return false
+		}
+	}
+
+	fmt.Println()
+	return true
+}
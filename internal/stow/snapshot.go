@@ -0,0 +1,51 @@
+package stow
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/nvandessel/go4dot/internal/snapshot"
+)
+
+// removeSaved removes path after saving it to the snapshot being taken
+func removeSaved(path string) error {
+	if err := snapshot.Save(path); err != nil {
+		return err
+	}
+	return os.Remove(path)
+}
+
+// saveStowedLinks saves the symlinks stow made for a package to the
+// snapshot being taken, before they are unstowed. Stow may have folded a
+// directory into one link, so the topmost symlink on the way to each
+// file's target is the one saved.
+func saveStowedLinks(dotfilesPath, configName string, opts StowOptions) error {
+	target := opts.Settings.TargetDir()
+	root := filepath.Join(dotfilesPath, configName)
+	var saveErr error
+	_ = filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() || saveErr != nil {
+			return nil
+		}
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return nil
+		}
+		dest := target
+		for _, part := range strings.Split(opts.Settings.LinkPath(rel), string(filepath.Separator)) {
+			dest = filepath.Join(dest, part)
+			info, err := os.Lstat(dest)
+			if err != nil {
+				break
+			}
+			if info.Mode()&os.ModeSymlink != 0 {
+				saveErr = snapshot.Save(dest)
+				break
+			}
+		}
+		return nil
+	})
+	return saveErr
+}
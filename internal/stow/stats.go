@@ -0,0 +1,159 @@
+package stow
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/nvandessel/go4dot/internal/config"
+)
+
+const (
+	// LargeFileThreshold is the size above which a file is reported as
+	// unlikely to be a hand-written dotfile.
+	LargeFileThreshold = 1 << 20
+
+	// DefaultLargestFiles is how many of the largest files stats keep.
+	DefaultLargestFiles = 5
+
+	// binarySniffSize is how much of a file is read to detect binaries.
+	binarySniffSize = 8000
+)
+
+// FileStat is the size of a single file inside a config.
+type FileStat struct {
+	Path   string `json:"path"` // Relative to the config directory
+	Size   int64  `json:"size"`
+	Binary bool   `json:"binary"`
+}
+
+// ConfigStats summarizes the files a config provides.
+type ConfigStats struct {
+	Config   string     `json:"config"`
+	Files    int        `json:"files"`
+	Size     int64      `json:"size"`
+	Binaries int        `json:"binaries"`
+	Largest  []FileStat `json:"largest"`
+}
+
+// Suspicious returns the files that look like caches or build artifacts:
+// binaries and anything over LargeFileThreshold.
+func (s *ConfigStats) Suspicious() []FileStat {
+	var out []FileStat
+	for _, f := range s.Largest {
+		if f.Binary || f.Size > LargeFileThreshold {
+			out = append(out, f)
+		}
+	}
+	return out
+}
+
+// GetConfigStats walks a config directory and counts the files stow would
+// link, their total size and the largest ones. Ignored files are skipped.
+// top limits the number of largest files kept (<= 0 uses DefaultLargestFiles).
+func GetConfigStats(item config.ConfigItem, dotfilesPath string, top int) (*ConfigStats, error) {
+	if top <= 0 {
+		top = DefaultLargestFiles
+	}
+
+	configDir := item.Dir(dotfilesPath)
+	if _, err := os.Stat(configDir); err != nil {
+		return nil, fmt.Errorf("config directory not found: %s", configDir)
+	}
+
+	ignore, err := LoadIgnoreList(configDir)
+	if err != nil {
+		return nil, err
+	}
+
+	stats := &ConfigStats{Config: item.Name, Largest: []FileStat{}}
+	err = filepath.WalkDir(configDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || path == configDir {
+			return nil // Skip unreadable entries
+		}
+
+		relPath, _ := filepath.Rel(configDir, path)
+		if ignore.Match(relPath) {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			return nil
+		}
+
+		f := FileStat{Path: relPath, Size: info.Size(), Binary: isBinaryFile(path)}
+		stats.Files++
+		stats.Size += f.Size
+		if f.Binary {
+			stats.Binaries++
+		}
+		stats.Largest = insertLargest(stats.Largest, f, top)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return stats, nil
+}
+
+// insertLargest adds f to the size-sorted list, keeping at most top entries
+func insertLargest(largest []FileStat, f FileStat, top int) []FileStat {
+	i := sort.Search(len(largest), func(i int) bool {
+		if largest[i].Size == f.Size {
+			return largest[i].Path > f.Path
+		}
+		return largest[i].Size < f.Size
+	})
+	if i >= top {
+		return largest
+	}
+	largest = append(largest, FileStat{})
+	copy(largest[i+1:], largest[i:])
+	largest[i] = f
+	if len(largest) > top {
+		largest = largest[:top]
+	}
+	return largest
+}
+
+// isBinaryFile reports whether the start of a file contains a NUL byte
+func isBinaryFile(path string) bool {
+	f, err := os.Open(path)
+	if err != nil {
+		return false
+	}
+	defer f.Close()
+
+	buf := make([]byte, binarySniffSize)
+	n, err := io.ReadFull(f, buf)
+	if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+		return false
+	}
+	return bytes.IndexByte(buf[:n], 0) >= 0
+}
+
+// FormatSize renders a byte count in human readable binary units.
+func FormatSize(size int64) string {
+	const unit = 1024
+	if size < unit {
+		return fmt.Sprintf("%d B", size)
+	}
+	div, exp := int64(unit), 0
+	for n := size / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(size)/float64(div), "KMGTPE"[exp])
+}
@@ -0,0 +1,111 @@
+package stow
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/nvandessel/go4dot/internal/config"
+)
+
+func TestGetConfigStats(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	dotfiles := t.TempDir()
+	configDir := filepath.Join(dotfiles, "nvim")
+
+	files := map[string][]byte{
+		".config/nvim/init.lua":        []byte("vim.o.number = true\n"),
+		".config/nvim/lua/plugins.lua": bytes.Repeat([]byte("x"), 300),
+		".config/nvim/cache.bin":       append([]byte("ELF"), make([]byte, 100)...),
+		"README.md":                    bytes.Repeat([]byte("ignored"), 1000),
+	}
+	for rel, data := range files {
+		path := filepath.Join(configDir, rel)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, data, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	stats, err := GetConfigStats(config.ConfigItem{Name: "nvim", Path: "nvim"}, dotfiles, 2)
+	if err != nil {
+		t.Fatalf("GetConfigStats() error = %v", err)
+	}
+
+	if stats.Files != 3 {
+		t.Errorf("Files = %d, want 3", stats.Files)
+	}
+	if want := int64(20 + 300 + 103); stats.Size != want {
+		t.Errorf("Size = %d, want %d", stats.Size, want)
+	}
+	if stats.Binaries != 1 {
+		t.Errorf("Binaries = %d, want 1", stats.Binaries)
+	}
+	if len(stats.Largest) != 2 {
+		t.Fatalf("Largest has %d entries, want 2", len(stats.Largest))
+	}
+	if stats.Largest[0].Path != filepath.Join(".config", "nvim", "lua", "plugins.lua") {
+		t.Errorf("Largest[0] = %s, want plugins.lua", stats.Largest[0].Path)
+	}
+	if !stats.Largest[1].Binary {
+		t.Errorf("Largest[1] = %+v, want the binary file", stats.Largest[1])
+	}
+
+	suspicious := stats.Suspicious()
+	if len(suspicious) != 1 || suspicious[0].Path != stats.Largest[1].Path {
+		t.Errorf("Suspicious() = %+v, want only the binary file", suspicious)
+	}
+}
+
+func TestGetConfigStatsMissingDir(t *testing.T) {
+	_, err := GetConfigStats(config.ConfigItem{Name: "gone", Path: "gone"}, t.TempDir(), 0)
+	if err == nil {
+		t.Error("GetConfigStats() expected error for missing directory")
+	}
+}
+
+func TestInsertLargest(t *testing.T) {
+	var largest []FileStat
+	for _, f := range []FileStat{
+		{Path: "a", Size: 10},
+		{Path: "b", Size: 30},
+		{Path: "c", Size: 20},
+		{Path: "d", Size: 5},
+		{Path: "e", Size: 30},
+	} {
+		largest = insertLargest(largest, f, 3)
+	}
+
+	want := []string{"b", "e", "c"}
+	if len(largest) != len(want) {
+		t.Fatalf("got %d entries, want %d", len(largest), len(want))
+	}
+	for i, path := range want {
+		if largest[i].Path != path {
+			t.Errorf("largest[%d] = %s, want %s", i, largest[i].Path, path)
+		}
+	}
+}
+
+func TestFormatSize(t *testing.T) {
+	tests := []struct {
+		size int64
+		want string
+	}{
+		{0, "0 B"},
+		{1023, "1023 B"},
+		{1024, "1.0 KiB"},
+		{1536, "1.5 KiB"},
+		{5 << 20, "5.0 MiB"},
+		{3 << 30, "3.0 GiB"},
+	}
+
+	for _, tt := range tests {
+		if got := FormatSize(tt.size); got != tt.want {
+			t.Errorf("FormatSize(%d) = %q, want %q", tt.size, got, tt.want)
+		}
+	}
+}
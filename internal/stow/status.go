@@ -63,11 +63,11 @@ func (s *ConfigLinkStatus) GetMissingFiles() []FileStatus {
 // GetAllConfigLinkStatus returns link status for all configs
 func GetAllConfigLinkStatus(cfg *config.Config, dotfilesPath string) (map[string]*ConfigLinkStatus, error) {
 	result := make(map[string]*ConfigLinkStatus)
-	home := os.Getenv("HOME")
+	home := cfg.Stow.TargetDir()
 
 	allConfigs := cfg.GetAllConfigs()
 	for _, configItem := range allConfigs {
-		status, err := getConfigLinkStatusInternal(configItem, dotfilesPath, home)
+		status, err := getConfigLinkStatusInternal(configItem, dotfilesPath, home, cfg.Stow)
 		if err != nil {
 			continue
 		}
@@ -77,9 +77,15 @@ func GetAllConfigLinkStatus(cfg *config.Config, dotfilesPath string) (map[string
 	return result, nil
 }
 
+// GetSingleConfigLinkStatus returns link status for one config, linked as
+// settings say
+func GetSingleConfigLinkStatus(configItem config.ConfigItem, dotfilesPath string, settings config.StowSettings) (*ConfigLinkStatus, error) {
+	return getConfigLinkStatusInternal(configItem, dotfilesPath, settings.TargetDir(), settings)
+}
+
 // getConfigLinkStatusInternal checks the link status of a single config
-func getConfigLinkStatusInternal(configItem config.ConfigItem, dotfilesPath, home string) (*ConfigLinkStatus, error) {
-	configPath := filepath.Join(dotfilesPath, configItem.Path)
+func getConfigLinkStatusInternal(configItem config.ConfigItem, dotfilesPath, home string, settings config.StowSettings) (*ConfigLinkStatus, error) {
+	configPath := configItem.Dir(dotfilesPath)
 
 	status := &ConfigLinkStatus{
 		ConfigName: configItem.Name,
@@ -104,7 +110,7 @@ func getConfigLinkStatusInternal(configItem config.ConfigItem, dotfilesPath, hom
 
 		// Calculate expected target path in home
 		relPath, _ := filepath.Rel(configPath, path)
-		targetPath := filepath.Join(home, relPath)
+		targetPath := filepath.Join(home, settings.LinkPath(relPath))
 
 		fileStatus := FileStatus{
 			RelPath: relPath,
@@ -137,6 +143,9 @@ func checkLinkStatus(sourcePath, targetPath string, fileStatus *FileStatus) bool
 	}
 	if err != nil {
 		fileStatus.Issue = "error checking"
+		if linkErr := CheckLinkChain(targetPath); linkErr != nil {
+			fileStatus.Issue = linkErr.Error()
+		}
 		return false
 	}
 
@@ -171,6 +180,9 @@ func checkLinkStatus(sourcePath, targetPath string, fileStatus *FileStatus) bool
 
 	if linkDest != sourcePath {
 		fileStatus.Issue = "points elsewhere"
+		if linkErr := CheckLinkChain(targetPath); linkErr != nil {
+			fileStatus.Issue = linkErr.Error()
+		}
 		return false
 	}
 
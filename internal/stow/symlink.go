@@ -9,6 +9,7 @@ import (
 
 	"github.com/nvandessel/go4dot/internal/config"
 	"github.com/nvandessel/go4dot/internal/state"
+	"github.com/nvandessel/go4dot/internal/vcs"
 )
 
 // countFiles counts the number of files (not directories) in a directory tree.
@@ -32,12 +33,13 @@ func countFiles(dir string) (int, error) {
 	return count, nil
 }
 
-// UpdateSymlinkCounts updates the stored file counts for all configs in state.
+// UpdateSymlinkCounts updates the stored file counts for all configs in
+// state, and the repo revision they were linked from.
 func UpdateSymlinkCounts(cfg *config.Config, dotfilesPath string, st *state.State) error {
 	allConfigs := cfg.GetAllConfigs()
 
 	for _, configItem := range allConfigs {
-		configPath := filepath.Join(dotfilesPath, configItem.Path)
+		configPath := configItem.Dir(dotfilesPath)
 
 		count, err := countFiles(configPath)
 		if err != nil {
@@ -53,6 +55,12 @@ func UpdateSymlinkCounts(cfg *config.Config, dotfilesPath string, st *state.Stat
 		st.SetSymlinkCount(configItem.Name, count)
 	}
 
+	if repo := vcs.Detect(dotfilesPath); repo != nil && vcs.Installed(repo) {
+		if head, err := repo.Head(dotfilesPath); err == nil {
+			st.Revision = head
+		}
+	}
+
 	if err := st.Save(); err != nil {
 		return fmt.Errorf("saving state: %w", err)
 	}
@@ -60,8 +68,9 @@ func UpdateSymlinkCounts(cfg *config.Config, dotfilesPath string, st *state.Stat
 }
 
 // findOrphanedSymlinks finds symlinks in home that point to the given config directory
-// but no longer have a corresponding file in that directory.
-func findOrphanedSymlinks(configPath, home string) []string {
+// but no longer have a corresponding file in that directory. settings maps
+// config paths to link paths, as stow --dotfiles does.
+func findOrphanedSymlinks(configPath, home string, settings config.StowSettings) []string {
 	var orphans []string
 
 	// We need to find which directories in home might contain symlinks to configPath.
@@ -80,7 +89,7 @@ func findOrphanedSymlinks(configPath, home string) []string {
 	})
 
 	for relDir := range dirsToCheck {
-		targetDir := filepath.Join(home, relDir)
+		targetDir := filepath.Join(home, settings.LinkPath(relDir))
 		entries, err := os.ReadDir(targetDir)
 		if err != nil {
 			continue
@@ -121,8 +130,8 @@ func findOrphanedSymlinks(configPath, home string) []string {
 // findOrphanFiles finds files in home managed directories that aren't tracked
 // by the config source. Only checks directories where the config has files
 // (not parent traversal directories). Skips root directory to avoid scanning
-// the entire home.
-func findOrphanFiles(configPath, home string) []string {
+// the entire home. Paths are compared as linked, after settings maps them.
+func findOrphanFiles(configPath, home string, settings config.StowSettings) []string {
 	var orphans []string
 
 	// Build set of expected file paths (relative to config root)
@@ -133,7 +142,7 @@ func findOrphanFiles(configPath, home string) []string {
 		}
 		relPath, err := filepath.Rel(configPath, path)
 		if err == nil {
-			expectedFiles[relPath] = true
+			expectedFiles[settings.LinkPath(relPath)] = true
 		}
 		return nil
 	})
@@ -1,18 +1,46 @@
 package stow
 
 import (
+	"errors"
 	"fmt"
-	"os"
 	"path/filepath"
 
 	"github.com/nvandessel/go4dot/internal/config"
 	"github.com/nvandessel/go4dot/internal/state"
 )
 
-// SyncAll restows all configs and updates state.
-// It handles conflict detection and resolution if interactive.
+// ErrUnresolvedConflicts is returned when a sync is cancelled because
+// existing files in home still block some configs
+var ErrUnresolvedConflicts = errors.New("sync cancelled due to unresolved conflicts")
+
+// SyncAll restows the configs that changed since the last sync, syncs the
+// symlinks in the links section and updates state. With state, PlanSync decides which configs to restow; the others
+// are reported in result.Unchanged. opts.RestowAll restows every config.
+// It handles conflict detection and resolution if interactive. Otherwise
+// conflicts are resolved with each config's on_conflict strategy, and
+// configs set to skip are left unlinked. Independent configs are restowed
+// concurrently, up to opts.Jobs at once.
 func SyncAll(dotfilesPath string, cfg *config.Config, st *state.State, interactive bool, opts StowOptions) (*StowResult, error) {
-	if interactive {
+	opts.Settings = cfg.Stow
+	skipped := make(map[string]bool)
+	if !interactive && !opts.Adopt && cfg.HasConflictStrategies() {
+		conflicts, err := DetectConflicts(cfg, dotfilesPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check conflicts: %w", err)
+		}
+		res, err := ApplyConflictStrategies(conflicts, cfg.GetAllConfigs(), opts)
+		if err != nil {
+			return nil, err
+		}
+		for _, name := range res.Skipped {
+			skipped[name] = true
+			if opts.ProgressFunc != nil {
+				opts.ProgressFunc(0, 0, fmt.Sprintf("Skipping %s: existing files left in place (on_conflict: skip)", name))
+			}
+		}
+	}
+
+	if interactive || opts.Adopt {
 		if opts.ProgressFunc != nil {
 			opts.ProgressFunc(0, 0, "Checking for conflicts...")
 		}
@@ -23,69 +51,64 @@ func SyncAll(dotfilesPath string, cfg *config.Config, st *state.State, interacti
 		}
 
 		if len(conflicts) > 0 {
-			if !ResolveConflicts(conflicts) {
-				return nil, fmt.Errorf("sync cancelled due to unresolved conflicts")
+			switch {
+			case opts.Adopt && interactive:
+				if !PromptAdoptConflicts(conflicts, dotfilesPath) {
+					return nil, ErrUnresolvedConflicts
+				}
+			case opts.Adopt:
+				if err := AdoptConflicts(conflicts, opts); err != nil {
+					return nil, err
+				}
+			default:
+				if !ResolveConflicts(conflicts, opts.UseTrash, CommonConflictStrategy(conflicts, cfg.GetAllConfigs())) {
+					return nil, ErrUnresolvedConflicts
+				}
 			}
 		}
 	}
 
+	home := cfg.Stow.TargetDir()
+	var drift *DriftSummary
+	if st != nil {
+		drift, _ = FullDriftCheckWithHome(cfg, dotfilesPath, home, st)
+	}
+	plan := PlanSync(cfg, dotfilesPath, st, drift, opts.RestowAll)
 	if opts.ProgressFunc != nil {
-		opts.ProgressFunc(0, 0, "Syncing all configs...")
+		opts.ProgressFunc(0, 0, plan.Summary())
 	}
 
-	allConfigs := cfg.GetAllConfigs()
-	result := RestowConfigs(dotfilesPath, allConfigs, opts)
+	var allConfigs, restow []config.ConfigItem
+	for _, item := range cfg.GetAllConfigs() {
+		if !skipped[item.Name] {
+			allConfigs = append(allConfigs, item)
+		}
+	}
+	for _, item := range plan.Restow {
+		if !skipped[item.Name] {
+			restow = append(restow, item)
+		}
+	}
+	result := RestowConfigsConcurrently(dotfilesPath, restow, opts.Jobs, opts)
+	for _, item := range plan.Unchanged {
+		result.Unchanged = append(result.Unchanged, item.Name)
+	}
+	for name := range skipped {
+		result.Skipped = append(result.Skipped, name)
+	}
 
-	// Unstow removed configs
 	if st != nil {
-		home := os.Getenv("HOME")
-		summary, err := FullDriftCheckWithHome(cfg, dotfilesPath, home, st)
-		if err == nil {
-			// Unstow removed configs
-			if len(summary.RemovedConfigs) > 0 {
-				for _, name := range summary.RemovedConfigs {
-					if opts.ProgressFunc != nil {
-						opts.ProgressFunc(0, 0, fmt.Sprintf("Unstowing removed config %s...", name))
-					}
-					var removedPath string
-					for _, sc := range st.Configs {
-						if sc.Name == name {
-							removedPath = sc.Path
-							break
-						}
-					}
-
-					if removedPath != "" {
-						err := Unstow(dotfilesPath, removedPath, opts)
-						if err == nil {
-							st.RemoveConfig(name)
-							st.RemoveSymlinkCount(name)
-						} else if opts.ProgressFunc != nil {
-							opts.ProgressFunc(0, 0, fmt.Sprintf("Warning: failed to unstow removed config %s: %v", name, err))
-						}
-					}
-				}
-			}
-
-			// Clean up orphaned symlinks for active configs
-			for _, res := range summary.Results {
-				if len(res.MissingFiles) > 0 {
-					for _, relPath := range res.MissingFiles {
-						if opts.ProgressFunc != nil {
-							opts.ProgressFunc(0, 0, fmt.Sprintf("Removing orphaned symlink %s...", relPath))
-						}
-						if !opts.DryRun {
-							targetPath := filepath.Join(home, relPath)
-							if err := os.Remove(targetPath); err != nil {
-								if opts.ProgressFunc != nil {
-									opts.ProgressFunc(0, 0, fmt.Sprintf("Warning: failed to remove orphaned symlink %s: %v", relPath, err))
-								}
-							}
-						}
-					}
-				}
-			}
+		// Restowing changes the links, so check them again
+		if len(restow) > 0 || drift == nil {
+			drift, _ = FullDriftCheckWithHome(cfg, dotfilesPath, home, st)
 		}
+		if drift != nil {
+			cleanupRemovedAndOrphaned(dotfilesPath, drift, st, home, opts)
+		}
+	}
+
+	if len(cfg.Links) > 0 || (st != nil && len(st.Links) > 0) {
+		result.Links = SyncLinks(cfg, dotfilesPath, st, opts)
 	}
 
 	// Update state
@@ -94,6 +117,14 @@ func SyncAll(dotfilesPath string, cfg *config.Config, st *state.State, interacti
 		for _, cfgItem := range allConfigs {
 			st.AddConfig(cfgItem.Name, cfgItem.Path, true) // Assume core if in main config
 		}
+		if !opts.DryRun {
+			for _, name := range result.Success {
+				st.SetFingerprint(name, plan.Fingerprints[name])
+			}
+			for _, f := range result.Failed {
+				st.RemoveFingerprint(f.ConfigName)
+			}
+		}
 
 		if err := UpdateSymlinkCounts(cfg, dotfilesPath, st); err != nil {
 			return result, fmt.Errorf("failed to update symlink counts: %w", err)
@@ -105,6 +136,7 @@ func SyncAll(dotfilesPath string, cfg *config.Config, st *state.State, interacti
 
 // SyncSingle restows a single config and updates state.
 func SyncSingle(dotfilesPath string, configName string, cfg *config.Config, st *state.State, opts StowOptions) error {
+	opts.Settings = cfg.Stow
 	// Find the config item
 	var configItem *config.ConfigItem
 	for _, c := range cfg.GetAllConfigs() {
@@ -115,20 +147,20 @@ func SyncSingle(dotfilesPath string, configName string, cfg *config.Config, st *
 	}
 
 	if configItem == nil {
-		return fmt.Errorf("config '%s' not found", configName)
+		return cfg.UnknownConfigError(configName)
 	}
 
 	if opts.ProgressFunc != nil {
 		opts.ProgressFunc(0, 0, fmt.Sprintf("Syncing %s...", configName))
 	}
 
-	err := Restow(dotfilesPath, configItem.Path, opts)
+	err := Restow(configItem.StowDir(dotfilesPath), configItem.Path, opts)
 	if err != nil {
 		return err
 	}
 
 	// Clean up orphaned symlinks for this config
-	home := os.Getenv("HOME")
+	home := cfg.Stow.TargetDir()
 	summary, err := FullDriftCheckWithHome(cfg, dotfilesPath, home, st)
 	if err != nil {
 		if opts.ProgressFunc != nil {
@@ -142,7 +174,7 @@ func SyncSingle(dotfilesPath string, configName string, cfg *config.Config, st *
 				}
 				if !opts.DryRun {
 					targetPath := filepath.Join(home, relPath)
-					if err := os.Remove(targetPath); err != nil {
+					if err := removeSaved(targetPath); err != nil {
 						if opts.ProgressFunc != nil {
 							opts.ProgressFunc(0, 0, fmt.Sprintf("Warning: failed to remove orphaned symlink %s: %v", relPath, err))
 						}
@@ -155,6 +187,9 @@ func SyncSingle(dotfilesPath string, configName string, cfg *config.Config, st *
 	// Update state for this config
 	if st != nil {
 		st.AddConfig(configItem.Name, configItem.Path, true)
+		if !opts.DryRun {
+			RecordFingerprints(cfg, dotfilesPath, st, []string{configName})
+		}
 		if err := UpdateSymlinkCounts(cfg, dotfilesPath, st); err != nil {
 			return fmt.Errorf("failed to update symlink counts: %w", err)
 		}
@@ -163,11 +198,58 @@ func SyncSingle(dotfilesPath string, configName string, cfg *config.Config, st *
 	return nil
 }
 
-// SyncResultSummary returns a human-readable summary of the sync result.
-// It displays the number of successfully synced configs or a failure message.
+// SyncResultSummary returns a human-readable summary of the sync result,
+// e.g. "12 configs unchanged, 2 restowed" or, when some failed,
+// "12 configs unchanged, 1 restowed, 1 failed".
 func SyncResultSummary(result *StowResult) string {
+	summary := fmt.Sprintf("%s unchanged, %d restowed", pluralConfigs(len(result.Unchanged)), len(result.Success))
 	if len(result.Failed) > 0 {
-		return fmt.Sprintf("Failed to sync %d config(s)", len(result.Failed))
+		summary += fmt.Sprintf(", %d failed", len(result.Failed))
+	}
+	return summary
+}
+
+// cleanupRemovedAndOrphaned unstows the configs drift found in state but no
+// longer in the config, and removes the orphaned symlinks of active configs
+func cleanupRemovedAndOrphaned(dotfilesPath string, drift *DriftSummary, st *state.State, home string, opts StowOptions) {
+	for _, name := range drift.RemovedConfigs {
+		if opts.ProgressFunc != nil {
+			opts.ProgressFunc(0, 0, fmt.Sprintf("Unstowing removed config %s...", name))
+		}
+		var removedPath string
+		for _, sc := range st.Configs {
+			if sc.Name == name {
+				removedPath = sc.Path
+				break
+			}
+		}
+
+		if removedPath != "" {
+			err := Unstow(dotfilesPath, removedPath, opts)
+			if err == nil {
+				st.RemoveConfig(name)
+				st.RemoveSymlinkCount(name)
+				st.RemoveFingerprint(name)
+			} else if opts.ProgressFunc != nil {
+				opts.ProgressFunc(0, 0, fmt.Sprintf("Warning: failed to unstow removed config %s: %v", name, err))
+			}
+		}
+	}
+
+	// Clean up orphaned symlinks for active configs
+	for _, res := range drift.Results {
+		for _, relPath := range res.MissingFiles {
+			if opts.ProgressFunc != nil {
+				opts.ProgressFunc(0, 0, fmt.Sprintf("Removing orphaned symlink %s...", relPath))
+			}
+			if !opts.DryRun {
+				targetPath := filepath.Join(home, relPath)
+				if err := removeSaved(targetPath); err != nil {
+					if opts.ProgressFunc != nil {
+						opts.ProgressFunc(0, 0, fmt.Sprintf("Warning: failed to remove orphaned symlink %s: %v", relPath, err))
+					}
+				}
+			}
+		}
 	}
-	return fmt.Sprintf("Synced %d config(s)", len(result.Success))
 }
@@ -0,0 +1,317 @@
+package stow
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/nvandessel/go4dot/internal/config"
+	"github.com/nvandessel/go4dot/internal/state"
+	"github.com/nvandessel/go4dot/internal/trash"
+)
+
+const (
+	// UndoFileName records the last conflict resolution, in the state directory
+	UndoFileName = "conflict-undo.json"
+
+	// UndoStashDir keeps files deleted by the last conflict resolution, in
+	// the state directory, so the deletion can be undone
+	UndoStashDir = "conflict-undo"
+)
+
+// Ways a conflicting file was moved out of the way
+const (
+	UndoBackup = "backup" // Renamed to a .g4d-backup file
+	UndoTrash  = "trash"  // Moved to the OS trash
+	UndoDelete = "delete" // Deleted; kept in the undo stash until the next resolution
+)
+
+// ErrNothingToUndo is returned when no conflict resolution is recorded
+var ErrNothingToUndo = errors.New("no conflict resolution to undo")
+
+// UndoEntry records one conflicting file that was moved out of the way
+type UndoEntry struct {
+	ConfigName string `json:"config"`
+	TargetPath string `json:"target"`               // Where the file was, and goes back to
+	Action     string `json:"action"`               // UndoBackup, UndoTrash or UndoDelete
+	SavedPath  string `json:"saved"`                // Where the file is now
+	TrashName  string `json:"trash_name,omitempty"` // Name in the trash, for UndoTrash
+}
+
+// UndoRecord is the last conflict resolution: every file moved out of the
+// way by one g4d run, so one undo puts them all back
+type UndoRecord struct {
+	StartedAt time.Time   `json:"started_at"`
+	Entries   []UndoEntry `json:"entries"`
+}
+
+// Configs returns the names of the configs whose conflicts were resolved
+func (r *UndoRecord) Configs() []string {
+	var names []string
+	seen := make(map[string]bool)
+	for _, e := range r.Entries {
+		if !seen[e.ConfigName] {
+			seen[e.ConfigName] = true
+			names = append(names, e.ConfigName)
+		}
+	}
+	return names
+}
+
+var (
+	undoMu sync.Mutex
+	// sessionUndo is this run's record; nil until the run resolves a conflict
+	sessionUndo *UndoRecord
+)
+
+// UndoPath returns the path of the conflict undo record
+func UndoPath() (string, error) {
+	dir, err := state.GetStateDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, UndoFileName), nil
+}
+
+// undoStashPath returns the undo stash directory
+func undoStashPath() (string, error) {
+	dir, err := state.GetStateDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, UndoStashDir), nil
+}
+
+// LoadUndoRecord returns the last conflict resolution: this run's if it
+// resolved any, otherwise the one saved by an earlier run
+func LoadUndoRecord() (*UndoRecord, error) {
+	undoMu.Lock()
+	defer undoMu.Unlock()
+	return loadUndoRecordLocked()
+}
+
+func loadUndoRecordLocked() (*UndoRecord, error) {
+	if sessionUndo != nil {
+		return sessionUndo, nil
+	}
+
+	path, err := UndoPath()
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, ErrNothingToUndo
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read conflict undo record: %w", err)
+	}
+
+	var rec UndoRecord
+	if err := json.Unmarshal(data, &rec); err != nil {
+		return nil, fmt.Errorf("failed to parse conflict undo record %s: %w", path, err)
+	}
+	if len(rec.Entries) == 0 {
+		return nil, ErrNothingToUndo
+	}
+	return &rec, nil
+}
+
+// beginUndo starts this run's record on its first conflict resolution,
+// replacing the record and stash of the run before
+func beginUndo() error {
+	undoMu.Lock()
+	defer undoMu.Unlock()
+	return beginUndoLocked()
+}
+
+func beginUndoLocked() error {
+	if sessionUndo != nil {
+		return nil
+	}
+	if err := clearUndoLocked(); err != nil {
+		return err
+	}
+	sessionUndo = &UndoRecord{StartedAt: time.Now()}
+	return nil
+}
+
+// recordUndo adds an entry to this run's record and saves it
+func recordUndo(entry UndoEntry) error {
+	undoMu.Lock()
+	defer undoMu.Unlock()
+
+	if err := beginUndoLocked(); err != nil {
+		return err
+	}
+	sessionUndo.Entries = append(sessionUndo.Entries, entry)
+	return saveUndoLocked(sessionUndo)
+}
+
+func saveUndoLocked(rec *UndoRecord) error {
+	path, err := UndoPath()
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(rec, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal conflict undo record: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create state directory: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		return fmt.Errorf("failed to write conflict undo record: %w", err)
+	}
+	return nil
+}
+
+// clearUndoLocked removes the saved record and the files stashed for it
+func clearUndoLocked() error {
+	sessionUndo = nil
+
+	path, err := UndoPath()
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove conflict undo record: %w", err)
+	}
+	stash, err := undoStashPath()
+	if err != nil {
+		return err
+	}
+	return os.RemoveAll(stash)
+}
+
+// stashConflict moves a conflicting file into the undo stash instead of
+// deleting it. It returns where the file went.
+func stashConflict(conflict ConflictFile) (string, error) {
+	if _, err := os.Lstat(conflict.TargetPath); err != nil {
+		return "", err
+	}
+	// Started first so that starting it can't clear the stash of this file
+	if err := beginUndo(); err != nil {
+		return "", err
+	}
+	stash, err := undoStashPath()
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(stash, 0700); err != nil {
+		return "", fmt.Errorf("failed to create undo stash: %w", err)
+	}
+	dir, err := os.MkdirTemp(stash, "item-")
+	if err != nil {
+		return "", fmt.Errorf("failed to create undo stash: %w", err)
+	}
+	saved := filepath.Join(dir, filepath.Base(conflict.TargetPath))
+	if err := os.Rename(conflict.TargetPath, saved); err != nil {
+		_ = os.Remove(dir)
+		return "", err
+	}
+	return saved, nil
+}
+
+// UndoOptions configures UndoConflictResolution
+type UndoOptions struct {
+	DryRun       bool
+	ProgressFunc func(current, total int, msg string)
+}
+
+// UndoConflictResolution reverts the last conflict resolution: it unlinks
+// the configs whose conflicts were resolved and moves every backed up,
+// trashed or deleted file back where it was. Files that could not be put
+// back stay in the record for another try.
+func UndoConflictResolution(cfg *config.Config, dotfilesPath string, opts UndoOptions) (*UndoRecord, error) {
+	undoMu.Lock()
+	defer undoMu.Unlock()
+
+	rec, err := loadUndoRecordLocked()
+	if err != nil {
+		return nil, err
+	}
+
+	report := func(msg string) {
+		if opts.ProgressFunc != nil {
+			opts.ProgressFunc(0, 0, msg)
+		}
+	}
+
+	for _, name := range rec.Configs() {
+		item := cfg.GetConfigByName(name)
+		if item == nil {
+			continue
+		}
+		if opts.DryRun {
+			report(fmt.Sprintf("Would unlink %s", name))
+			continue
+		}
+		if err := Unstow(item.StowDir(dotfilesPath), item.Path, StowOptions{Settings: cfg.Stow}); err != nil {
+			report(fmt.Sprintf("Warning: failed to unlink %s: %v", name, err))
+		} else {
+			report(fmt.Sprintf("✓ Unlinked %s", name))
+		}
+	}
+
+	var failed []UndoEntry
+	var errs []error
+	for i := len(rec.Entries) - 1; i >= 0; i-- {
+		entry := rec.Entries[i]
+		if opts.DryRun {
+			report(fmt.Sprintf("Would restore %s (%s)", entry.TargetPath, entry.Action))
+			continue
+		}
+		if err := restoreUndoEntry(entry); err != nil {
+			failed = append([]UndoEntry{entry}, failed...)
+			errs = append(errs, fmt.Errorf("%s: %w", entry.TargetPath, err))
+			continue
+		}
+		report(fmt.Sprintf("✓ Restored %s", entry.TargetPath))
+	}
+	if opts.DryRun {
+		return rec, nil
+	}
+
+	if len(failed) > 0 {
+		remaining := &UndoRecord{StartedAt: rec.StartedAt, Entries: failed}
+		if sessionUndo != nil {
+			sessionUndo = remaining
+		}
+		if err := saveUndoLocked(remaining); err != nil {
+			errs = append(errs, err)
+		}
+		return rec, errors.Join(errs...)
+	}
+	return rec, clearUndoLocked()
+}
+
+// restoreUndoEntry moves one file back to its original path. A symlink
+// left there is removed; anything else is never overwritten.
+func restoreUndoEntry(entry UndoEntry) error {
+	if info, err := os.Lstat(entry.TargetPath); err == nil {
+		if info.Mode()&os.ModeSymlink == 0 {
+			return fmt.Errorf("something else now exists there")
+		}
+		if err := os.Remove(entry.TargetPath); err != nil {
+			return err
+		}
+	}
+	if err := os.MkdirAll(filepath.Dir(entry.TargetPath), 0755); err != nil {
+		return err
+	}
+
+	if entry.Action == UndoTrash {
+		t, err := trash.New()
+		if err != nil {
+			return err
+		}
+		_, err = t.Restore(entry.TrashName)
+		return err
+	}
+	return os.Rename(entry.SavedPath, entry.TargetPath)
+}
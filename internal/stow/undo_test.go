@@ -0,0 +1,136 @@
+package stow
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/nvandessel/go4dot/internal/config"
+)
+
+// setupUndoTest isolates the undo record in a temporary home
+func setupUndoTest(t *testing.T) string {
+	t.Helper()
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_DATA_HOME", filepath.Join(home, ".local", "share"))
+	sessionUndo = nil
+	t.Cleanup(func() { sessionUndo = nil })
+	return home
+}
+
+func TestUndoConflictResolution(t *testing.T) {
+	home := setupUndoTest(t)
+
+	backedUp := filepath.Join(home, ".zshrc")
+	deleted := filepath.Join(home, ".bashrc")
+	trashed := filepath.Join(home, ".vimrc")
+	for _, path := range []string{backedUp, deleted, trashed} {
+		writeTestFile(t, path, "original "+filepath.Base(path))
+	}
+
+	if err := BackupConflict(ConflictFile{ConfigName: "zsh", TargetPath: backedUp}); err != nil {
+		t.Fatal(err)
+	}
+	if err := RemoveConflict(ConflictFile{ConfigName: "bash", TargetPath: deleted}); err != nil {
+		t.Fatal(err)
+	}
+	if err := TrashConflict(ConflictFile{ConfigName: "vim", TargetPath: trashed}); err != nil {
+		t.Fatal(err)
+	}
+
+	// A link created since the resolution is replaced by the original file
+	if err := os.Symlink("/dotfiles/zsh/.zshrc", backedUp); err != nil {
+		t.Fatal(err)
+	}
+
+	// A later run finds the record on disk
+	sessionUndo = nil
+	rec, err := LoadUndoRecord()
+	if err != nil {
+		t.Fatalf("LoadUndoRecord() error = %v", err)
+	}
+	if len(rec.Entries) != 3 {
+		t.Fatalf("recorded %d entries, want 3", len(rec.Entries))
+	}
+
+	if _, err := UndoConflictResolution(&config.Config{}, t.TempDir(), UndoOptions{}); err != nil {
+		t.Fatalf("UndoConflictResolution() error = %v", err)
+	}
+	for _, path := range []string{backedUp, deleted, trashed} {
+		data, err := os.ReadFile(path)
+		if err != nil || string(data) != "original "+filepath.Base(path) {
+			t.Errorf("%s = %q, %v; want the original file back", path, data, err)
+		}
+	}
+
+	if _, err := LoadUndoRecord(); !errors.Is(err, ErrNothingToUndo) {
+		t.Errorf("LoadUndoRecord() after undo error = %v, want ErrNothingToUndo", err)
+	}
+}
+
+func TestUndoConflictResolution_KeepsFailedEntries(t *testing.T) {
+	home := setupUndoTest(t)
+
+	target := filepath.Join(home, ".zshrc")
+	writeTestFile(t, target, "original")
+	if err := BackupConflict(ConflictFile{ConfigName: "zsh", TargetPath: target}); err != nil {
+		t.Fatal(err)
+	}
+	// A real file now in the way is never overwritten
+	writeTestFile(t, target, "new")
+
+	if _, err := UndoConflictResolution(&config.Config{}, t.TempDir(), UndoOptions{}); err == nil {
+		t.Fatal("expected an error when the original path is taken")
+	}
+	if data, _ := os.ReadFile(target); string(data) != "new" {
+		t.Errorf("file in the way was overwritten: %q", data)
+	}
+	rec, err := LoadUndoRecord()
+	if err != nil || len(rec.Entries) != 1 {
+		t.Errorf("LoadUndoRecord() = %+v, %v; want the failed entry kept", rec, err)
+	}
+}
+
+func TestUndoRecord_NewSessionReplacesOld(t *testing.T) {
+	home := setupUndoTest(t)
+
+	first := filepath.Join(home, ".zshrc")
+	writeTestFile(t, first, "first")
+	if err := RemoveConflict(ConflictFile{ConfigName: "zsh", TargetPath: first}); err != nil {
+		t.Fatal(err)
+	}
+
+	sessionUndo = nil
+	second := filepath.Join(home, ".bashrc")
+	writeTestFile(t, second, "second")
+	if err := BackupConflict(ConflictFile{ConfigName: "bash", TargetPath: second}); err != nil {
+		t.Fatal(err)
+	}
+
+	rec, err := LoadUndoRecord()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := rec.Configs(); len(got) != 1 || got[0] != "bash" {
+		t.Errorf("Configs() = %v, want only the latest run's", got)
+	}
+}
+
+func TestUndoConflictResolution_FirstResolutionIsDelete(t *testing.T) {
+	home := setupUndoTest(t)
+
+	target := filepath.Join(home, ".zshrc")
+	writeTestFile(t, target, "original")
+	if err := RemoveConflict(ConflictFile{ConfigName: "zsh", TargetPath: target}); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := UndoConflictResolution(&config.Config{}, t.TempDir(), UndoOptions{}); err != nil {
+		t.Fatalf("UndoConflictResolution() error = %v", err)
+	}
+	if data, err := os.ReadFile(target); err != nil || string(data) != "original" {
+		t.Errorf("%s = %q, %v; want the deleted file back", target, data, err)
+	}
+}
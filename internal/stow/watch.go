@@ -0,0 +1,152 @@
+package stow
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/nvandessel/go4dot/internal/config"
+)
+
+// Default timings for Watch
+const (
+	DefaultWatchInterval = time.Second
+	DefaultWatchDebounce = 500 * time.Millisecond
+)
+
+// PackageFiles maps a config name to the set of files in its package,
+// relative to the package root
+type PackageFiles map[string]map[string]bool
+
+// ConfigChange lists the files added to or removed from a config's package
+type ConfigChange struct {
+	ConfigName string
+	Added      []string
+	Removed    []string
+}
+
+// WatchOptions configures Watch
+type WatchOptions struct {
+	Interval time.Duration // How often packages are scanned
+	Debounce time.Duration // How long packages must stay unchanged before reporting
+}
+
+// ScanPackages lists the files in every config's package, skipping files
+// stow would ignore and those excluded by .go4dotignore. Only the set of
+// files matters for linking: edits to a file already show through its
+// symlink, so contents are not tracked.
+func ScanPackages(cfg *config.Config, dotfilesPath string) PackageFiles {
+	files := PackageFiles{}
+	repoIgnore, _ := config.LoadRepoIgnore(dotfilesPath)
+	for _, item := range cfg.GetAllConfigs() {
+		configDir := item.Dir(dotfilesPath)
+		ignore, _ := LoadIgnoreList(configDir)
+
+		set := map[string]bool{}
+		_ = filepath.Walk(configDir, func(path string, info os.FileInfo, err error) error {
+			if err != nil {
+				return nil
+			}
+			relPath, err := filepath.Rel(configDir, path)
+			if err != nil || relPath == "." {
+				return nil
+			}
+			if ignore.Match(relPath) || repoIgnore.Match(filepath.Join(item.Path, relPath), info.IsDir()) {
+				if info.IsDir() {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			if !info.IsDir() {
+				set[relPath] = true
+			}
+			return nil
+		})
+		files[item.Name] = set
+	}
+	return files
+}
+
+// DiffPackages returns the configs whose files differ between old and new,
+// sorted by config name. A config missing from one side counts as empty.
+func DiffPackages(old, new PackageFiles) []ConfigChange {
+	names := map[string]bool{}
+	for name := range old {
+		names[name] = true
+	}
+	for name := range new {
+		names[name] = true
+	}
+
+	var changes []ConfigChange
+	for name := range names {
+		change := ConfigChange{ConfigName: name}
+		for f := range new[name] {
+			if !old[name][f] {
+				change.Added = append(change.Added, f)
+			}
+		}
+		for f := range old[name] {
+			if !new[name][f] {
+				change.Removed = append(change.Removed, f)
+			}
+		}
+		if len(change.Added) == 0 && len(change.Removed) == 0 {
+			continue
+		}
+		sort.Strings(change.Added)
+		sort.Strings(change.Removed)
+		changes = append(changes, change)
+	}
+
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].ConfigName < changes[j].ConfigName
+	})
+	return changes
+}
+
+// Watch polls scan every Interval and calls onChange with the configs that
+// changed once scan has returned the same files for at least Debounce, so
+// that a burst of edits (a checkout, an editor's save dance) is reported
+// once. It blocks until ctx is cancelled.
+func Watch(ctx context.Context, scan func() PackageFiles, opts WatchOptions, onChange func([]ConfigChange)) error {
+	if opts.Interval <= 0 {
+		opts.Interval = DefaultWatchInterval
+	}
+	if opts.Debounce < 0 {
+		opts.Debounce = 0
+	}
+
+	reported := scan()
+	latest := reported
+	var changedAt time.Time
+	pending := false
+
+	ticker := time.NewTicker(opts.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case now := <-ticker.C:
+			current := scan()
+			if len(DiffPackages(latest, current)) > 0 {
+				latest = current
+				changedAt = now
+				pending = true
+			}
+			if !pending || now.Sub(changedAt) < opts.Debounce {
+				continue
+			}
+			pending = false
+			changes := DiffPackages(reported, latest)
+			reported = latest
+			if len(changes) > 0 {
+				onChange(changes)
+			}
+		}
+	}
+}
@@ -0,0 +1,172 @@
+package stow
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/nvandessel/go4dot/internal/config"
+)
+
+func TestScanPackages(t *testing.T) {
+	dotfiles := t.TempDir()
+	for _, f := range []string{
+		"nvim/.config/nvim/init.lua",
+		"nvim/README.md",
+		"nvim/.git/HEAD",
+		"zsh/.zshrc",
+	} {
+		path := filepath.Join(dotfiles, f)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte("x"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	cfg := &config.Config{
+		Configs: config.ConfigGroups{
+			Core: []config.ConfigItem{
+				{Name: "nvim", Path: "nvim"},
+				{Name: "zsh", Path: "zsh"},
+				{Name: "missing", Path: "missing"},
+			},
+		},
+	}
+
+	got := ScanPackages(cfg, dotfiles)
+	want := PackageFiles{
+		"nvim":    {filepath.Join(".config", "nvim", "init.lua"): true},
+		"zsh":     {".zshrc": true},
+		"missing": {},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ScanPackages() = %v, want %v", got, want)
+	}
+}
+
+func TestDiffPackages(t *testing.T) {
+	tests := []struct {
+		name string
+		old  PackageFiles
+		new  PackageFiles
+		want []ConfigChange
+	}{
+		{
+			name: "no changes",
+			old:  PackageFiles{"zsh": {".zshrc": true}},
+			new:  PackageFiles{"zsh": {".zshrc": true}},
+			want: nil,
+		},
+		{
+			name: "added and removed files",
+			old:  PackageFiles{"zsh": {".zshrc": true, ".zprofile": true}},
+			new:  PackageFiles{"zsh": {".zshrc": true, ".zshenv": true, ".zlogin": true}},
+			want: []ConfigChange{
+				{ConfigName: "zsh", Added: []string{".zlogin", ".zshenv"}, Removed: []string{".zprofile"}},
+			},
+		},
+		{
+			name: "config added and removed",
+			old:  PackageFiles{"git": {".gitconfig": true}},
+			new:  PackageFiles{"zsh": {".zshrc": true}},
+			want: []ConfigChange{
+				{ConfigName: "git", Removed: []string{".gitconfig"}},
+				{ConfigName: "zsh", Added: []string{".zshrc"}},
+			},
+		},
+		{
+			name: "empty config added",
+			old:  PackageFiles{},
+			new:  PackageFiles{"zsh": {}},
+			want: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := DiffPackages(tt.old, tt.new)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("DiffPackages() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWatch_Debounces(t *testing.T) {
+	var mu sync.Mutex
+	current := PackageFiles{"zsh": {".zshrc": true}}
+	setFiles := func(files map[string]bool) {
+		mu.Lock()
+		defer mu.Unlock()
+		current = PackageFiles{"zsh": files}
+	}
+	scan := func() PackageFiles {
+		mu.Lock()
+		defer mu.Unlock()
+		return current
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	reports := make(chan []ConfigChange, 10)
+	done := make(chan error, 1)
+	go func() {
+		done <- Watch(ctx, scan, WatchOptions{
+			Interval: 5 * time.Millisecond,
+			Debounce: 50 * time.Millisecond,
+		}, func(changes []ConfigChange) {
+			reports <- changes
+		})
+	}()
+
+	// A burst of changes within the debounce window is reported once,
+	// against the files before the burst
+	time.Sleep(20 * time.Millisecond)
+	setFiles(map[string]bool{".zshrc": true, ".zshenv": true})
+	time.Sleep(15 * time.Millisecond)
+	setFiles(map[string]bool{".zshrc": true, ".zshenv": true, ".zlogin": true})
+
+	select {
+	case got := <-reports:
+		want := []ConfigChange{{ConfigName: "zsh", Added: []string{".zlogin", ".zshenv"}}}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("reported %+v, want %+v", got, want)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("changes were not reported")
+	}
+
+	select {
+	case got := <-reports:
+		t.Errorf("unexpected second report: %+v", got)
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	// A change that is reverted before the debounce elapses is not reported
+	setFiles(map[string]bool{".zshrc": true})
+	time.Sleep(15 * time.Millisecond)
+	setFiles(map[string]bool{".zshrc": true, ".zshenv": true, ".zlogin": true})
+
+	select {
+	case got := <-reports:
+		t.Errorf("reverted change was reported: %+v", got)
+	case <-time.After(150 * time.Millisecond):
+	}
+
+	cancel()
+	select {
+	case err := <-done:
+		if err != context.Canceled {
+			t.Errorf("Watch() error = %v, want %v", err, context.Canceled)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Watch did not stop after cancel")
+	}
+}
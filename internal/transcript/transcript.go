@@ -0,0 +1,223 @@
+// Package transcript records a complete account of a run for --log-file:
+// every command executed with its output, duration and exit code, the
+// progress events and messages shown, and the result. Entries are written
+// as JSON Lines as they happen, whatever the output mode, so provisioning
+// pipelines can archive the file as an artifact even when the run is
+// interrupted.
+package transcript
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/nvandessel/go4dot/internal/progress"
+)
+
+// Entry types
+const (
+	TypeStart   = "start"   // The run began
+	TypeCommand = "command" // An external command finished
+	TypeEvent   = "event"   // A progress event
+	TypeOutput  = "output"  // A message printed to the user
+	TypeResult  = "result"  // The run finished
+)
+
+// Entry is one line of the transcript. Fields not used by an entry's type
+// are left out.
+type Entry struct {
+	Time time.Time `json:"time"`
+	Type string    `json:"type"`
+
+	// start and command
+	Args []string `json:"args,omitempty"`
+	Dir  string   `json:"dir,omitempty"`
+
+	// start
+	Version string `json:"version,omitempty"`
+
+	// command; output holds stdout and stderr together when the command
+	// wrote both to one stream
+	Stdout string `json:"stdout,omitempty"`
+	Stderr string `json:"stderr,omitempty"`
+	Output string `json:"output,omitempty"`
+
+	// command and result
+	DurationMS int64  `json:"duration_ms,omitempty"`
+	ExitCode   *int   `json:"exit_code,omitempty"`
+	Error      string `json:"error,omitempty"`
+
+	// event and output
+	Level    string `json:"level,omitempty"`
+	Category string `json:"category,omitempty"`
+	Item     string `json:"item,omitempty"`
+	Message  string `json:"message,omitempty"`
+}
+
+var (
+	mu      sync.Mutex
+	file    *os.File
+	started time.Time
+)
+
+// ansiPattern matches terminal escape sequences in messages
+var ansiPattern = regexp.MustCompile(`\x1b\[[0-9;?]*[ -/]*[@-~]`)
+
+// Open starts a transcript at path, replacing any file there, and records
+// the start of the run
+func Open(path, version string, args []string) error {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open log file: %w", err)
+	}
+
+	mu.Lock()
+	if file != nil {
+		_ = file.Close()
+	}
+	file = f
+	started = time.Now()
+	mu.Unlock()
+
+	dir, _ := os.Getwd()
+	write(Entry{Type: TypeStart, Args: args, Dir: dir, Version: version})
+	return nil
+}
+
+// Active reports whether a transcript is being written
+func Active() bool {
+	mu.Lock()
+	defer mu.Unlock()
+	return file != nil
+}
+
+// Finish records the run's exit code and error and closes the transcript.
+// It does nothing when no transcript is open.
+func Finish(code int, err error) error {
+	mu.Lock()
+	defer mu.Unlock()
+	if file == nil {
+		return nil
+	}
+
+	e := Entry{Type: TypeResult, DurationMS: time.Now().Sub(started).Milliseconds(), ExitCode: &code}
+	if err != nil {
+		e.Error = err.Error()
+	}
+	writeLocked(e)
+	closeErr := file.Close()
+	file = nil
+	return closeErr
+}
+
+// Event records a progress event
+func Event(e progress.Event) {
+	if !Active() {
+		return
+	}
+	write(Entry{
+		Type:     TypeEvent,
+		Level:    string(e.Level),
+		Category: e.Category,
+		Item:     e.Item,
+		Message:  e.Message,
+	})
+}
+
+// Output records a message shown to the user at the given level, e.g.
+// "info" or "error". Terminal styling and surrounding newlines are removed.
+func Output(level, message string) {
+	if !Active() {
+		return
+	}
+	message = strings.Trim(ansiPattern.ReplaceAllString(message, ""), "\n")
+	write(Entry{Type: TypeOutput, Level: level, Message: message})
+}
+
+// Run runs cmd like cmd.Run, recording it with its output, duration and
+// exit code. Output the command writes still goes where cmd sends it.
+func Run(cmd *exec.Cmd) error {
+	if !Active() {
+		return cmd.Run()
+	}
+
+	var stdout, stderr, combined bytes.Buffer
+	switch {
+	case cmd.Stdout != nil && cmd.Stdout == cmd.Stderr:
+		w := io.MultiWriter(cmd.Stdout, &combined)
+		cmd.Stdout, cmd.Stderr = w, w
+	default:
+		cmd.Stdout = tee(cmd.Stdout, &stdout)
+		cmd.Stderr = tee(cmd.Stderr, &stderr)
+	}
+
+	start := time.Now()
+	err := cmd.Run()
+	e := Entry{
+		Type:       TypeCommand,
+		Args:       cmd.Args,
+		Dir:        cmd.Dir,
+		Stdout:     stdout.String(),
+		Stderr:     stderr.String(),
+		Output:     combined.String(),
+		DurationMS: time.Now().Sub(start).Milliseconds(),
+	}
+	if cmd.ProcessState != nil {
+		code := cmd.ProcessState.ExitCode()
+		e.ExitCode = &code
+	}
+	var exitErr *exec.ExitError
+	if err != nil && !errors.As(err, &exitErr) {
+		e.Error = err.Error()
+	}
+	write(e)
+	return err
+}
+
+// CombinedOutput runs cmd like cmd.CombinedOutput, recording it like Run
+func CombinedOutput(cmd *exec.Cmd) ([]byte, error) {
+	if !Active() {
+		return cmd.CombinedOutput()
+	}
+	var out bytes.Buffer
+	cmd.Stdout = &out
+	cmd.Stderr = &out
+	err := Run(cmd)
+	return out.Bytes(), err
+}
+
+// tee returns a writer sending to w, if set, and to buf
+func tee(w io.Writer, buf *bytes.Buffer) io.Writer {
+	if w == nil {
+		return buf
+	}
+	return io.MultiWriter(w, buf)
+}
+
+func write(e Entry) {
+	mu.Lock()
+	defer mu.Unlock()
+	writeLocked(e)
+}
+
+// writeLocked appends e to the transcript; mu must be held. Write errors
+// are ignored so a full disk doesn't fail the run being logged.
+func writeLocked(e Entry) {
+	if file == nil {
+		return
+	}
+	e.Time = time.Now()
+	data, err := json.Marshal(e)
+	if err != nil {
+		return
+	}
+	_, _ = file.Write(append(data, '\n'))
+}
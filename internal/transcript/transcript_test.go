@@ -0,0 +1,115 @@
+package transcript
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/nvandessel/go4dot/internal/progress"
+)
+
+func readEntries(t *testing.T, path string) []Entry {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var entries []Entry
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		var e Entry
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			t.Fatalf("line %q is not JSON: %v", scanner.Text(), err)
+		}
+		entries = append(entries, e)
+	}
+	return entries
+}
+
+func TestTranscript(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "install.jsonl")
+	if err := Open(path, "1.2.3", []string{"g4d", "install", "--log-file", path}); err != nil {
+		t.Fatal(err)
+	}
+
+	var shown bytes.Buffer
+	cmd := exec.Command("sh", "-c", "echo out; echo err >&2; exit 3")
+	cmd.Stdout = &shown
+	if err := Run(cmd); err == nil {
+		t.Error("Run() should return the command's exit error")
+	}
+	if shown.String() != "out\n" {
+		t.Errorf("command stdout = %q, want it still passed through", shown.String())
+	}
+
+	out, err := CombinedOutput(exec.Command("sh", "-c", "echo both"))
+	if err != nil || string(out) != "both\n" {
+		t.Errorf("CombinedOutput() = %q, %v", out, err)
+	}
+
+	Event(progress.Itemf(progress.Success, progress.CategoryExternal, "tpm", 1, 2, "Cloned %s", "tpm"))
+	Output("warning", "\x1b[33mcareful\x1b[0m\n")
+
+	if err := Finish(4, os.ErrNotExist); err != nil {
+		t.Fatal(err)
+	}
+	if Active() {
+		t.Error("Active() = true after Finish")
+	}
+
+	entries := readEntries(t, path)
+	if len(entries) != 6 {
+		t.Fatalf("got %d entries, want 6: %+v", len(entries), entries)
+	}
+
+	start := entries[0]
+	if start.Type != TypeStart || start.Version != "1.2.3" || len(start.Args) != 4 {
+		t.Errorf("start entry = %+v", start)
+	}
+
+	run := entries[1]
+	if run.Type != TypeCommand || run.Args[0] != "sh" || run.Stdout != "out\n" || run.Stderr != "err\n" {
+		t.Errorf("command entry = %+v", run)
+	}
+	if run.ExitCode == nil || *run.ExitCode != 3 || run.Error != "" {
+		t.Errorf("command exit = %v, error %q; want 3 and no error", run.ExitCode, run.Error)
+	}
+
+	combined := entries[2]
+	if combined.Output != "both\n" || combined.Stdout != "" || *combined.ExitCode != 0 {
+		t.Errorf("combined command entry = %+v", combined)
+	}
+
+	if ev := entries[3]; ev.Type != TypeEvent || ev.Level != "success" || ev.Item != "tpm" || ev.Message != "Cloned tpm" {
+		t.Errorf("event entry = %+v", ev)
+	}
+	if o := entries[4]; o.Type != TypeOutput || o.Level != "warning" || o.Message != "careful" {
+		t.Errorf("output entry = %+v", o)
+	}
+
+	result := entries[5]
+	if result.Type != TypeResult || result.ExitCode == nil || *result.ExitCode != 4 || result.Error == "" {
+		t.Errorf("result entry = %+v", result)
+	}
+}
+
+func TestRun_Inactive(t *testing.T) {
+	var out bytes.Buffer
+	cmd := exec.Command("sh", "-c", "echo plain")
+	cmd.Stdout = &out
+	if err := Run(cmd); err != nil {
+		t.Fatal(err)
+	}
+	if out.String() != "plain\n" {
+		t.Errorf("stdout = %q", out.String())
+	}
+
+	// Nothing to finish without a transcript
+	if err := Finish(0, nil); err != nil {
+		t.Errorf("Finish() = %v", err)
+	}
+}
@@ -0,0 +1,285 @@
+package trash
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/nvandessel/go4dot/internal/fsutil"
+)
+
+const (
+	// infoExt is the extension of freedesktop trash metadata files
+	infoExt = ".trashinfo"
+	// dateLayout is the DeletionDate format required by the freedesktop spec
+	dateLayout = "2006-01-02T15:04:05"
+)
+
+// ErrNotFound is returned when a trashed item cannot be found
+var ErrNotFound = errors.New("item not found in trash")
+
+// Item describes a file or directory that was moved to the trash
+type Item struct {
+	Name         string    // Name inside the trash (unique)
+	OriginalPath string    // Absolute path the item was deleted from
+	DeletedAt    time.Time // When the item was trashed
+}
+
+// Trash is a trash can with a files directory and a metadata directory.
+// On Linux this follows the freedesktop.org trash specification. On macOS the
+// files go to ~/.Trash and go4dot keeps the metadata itself so items can be
+// restored later.
+type Trash struct {
+	FilesDir string
+	InfoDir  string
+}
+
+// New returns the home trash for the current platform
+func New() (*Trash, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get home directory: %w", err)
+	}
+
+	if runtime.GOOS == "darwin" {
+		return &Trash{
+			FilesDir: filepath.Join(home, ".Trash"),
+			InfoDir:  filepath.Join(home, ".config", "go4dot", "trash"),
+		}, nil
+	}
+
+	dataHome := os.Getenv("XDG_DATA_HOME")
+	if dataHome == "" {
+		dataHome = filepath.Join(home, ".local", "share")
+	}
+	return NewAt(filepath.Join(dataHome, "Trash")), nil
+}
+
+// NewAt returns a freedesktop-style trash rooted at dir
+func NewAt(dir string) *Trash {
+	return &Trash{
+		FilesDir: filepath.Join(dir, "files"),
+		InfoDir:  filepath.Join(dir, "info"),
+	}
+}
+
+// Put moves path into the trash and records where it came from
+func (t *Trash) Put(path string) (*Item, error) {
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve %s: %w", path, err)
+	}
+	if _, err := os.Lstat(absPath); err != nil {
+		return nil, fmt.Errorf("cannot trash %s: %w", absPath, err)
+	}
+
+	if err := os.MkdirAll(t.FilesDir, 0700); err != nil {
+		return nil, fmt.Errorf("failed to create trash directory: %w", err)
+	}
+	if err := os.MkdirAll(t.InfoDir, 0700); err != nil {
+		return nil, fmt.Errorf("failed to create trash info directory: %w", err)
+	}
+
+	item := &Item{OriginalPath: absPath, DeletedAt: time.Now()}
+
+	// Reserve a unique name by creating the info file exclusively first
+	base := filepath.Base(absPath)
+	var infoFile *os.File
+	for i := 1; ; i++ {
+		item.Name = base
+		if i > 1 {
+			item.Name = fmt.Sprintf("%s.%d", base, i)
+		}
+		if _, err := os.Lstat(filepath.Join(t.FilesDir, item.Name)); err == nil {
+			continue
+		}
+		infoFile, err = os.OpenFile(t.infoPath(item.Name), os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+		if err == nil {
+			break
+		}
+		if !os.IsExist(err) {
+			return nil, fmt.Errorf("failed to write trash info: %w", err)
+		}
+	}
+
+	_, err = fmt.Fprintf(infoFile, "[Trash Info]\nPath=%s\nDeletionDate=%s\n",
+		escapePath(absPath), item.DeletedAt.Format(dateLayout))
+	closeErr := infoFile.Close()
+	if err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		_ = os.Remove(t.infoPath(item.Name))
+		return nil, fmt.Errorf("failed to write trash info: %w", err)
+	}
+
+	if err := move(absPath, filepath.Join(t.FilesDir, item.Name)); err != nil {
+		_ = os.Remove(t.infoPath(item.Name))
+		return nil, fmt.Errorf("failed to move %s to trash: %w", absPath, err)
+	}
+
+	return item, nil
+}
+
+// List returns all items in the trash that have metadata, newest first
+func (t *Trash) List() ([]Item, error) {
+	entries, err := os.ReadDir(t.InfoDir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read trash: %w", err)
+	}
+
+	var items []Item
+	for _, e := range entries {
+		if e.IsDir() || !strings.HasSuffix(e.Name(), infoExt) {
+			continue
+		}
+		item, err := t.readInfo(strings.TrimSuffix(e.Name(), infoExt))
+		if err != nil {
+			continue // Skip malformed entries
+		}
+		items = append(items, *item)
+	}
+
+	sort.Slice(items, func(i, j int) bool {
+		return items[i].DeletedAt.After(items[j].DeletedAt)
+	})
+
+	return items, nil
+}
+
+// Restore moves a trashed item back to its original location.
+// It refuses to overwrite anything that now exists at that path.
+func (t *Trash) Restore(name string) (*Item, error) {
+	if name == "" || name != filepath.Base(name) || name == "." || name == ".." {
+		return nil, fmt.Errorf("%w: %s", ErrNotFound, name)
+	}
+
+	item, err := t.readInfo(name)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := os.Lstat(item.OriginalPath); err == nil {
+		return nil, fmt.Errorf("cannot restore %s: %s already exists", name, item.OriginalPath)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(item.OriginalPath), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create parent directory: %w", err)
+	}
+
+	if err := move(filepath.Join(t.FilesDir, name), item.OriginalPath); err != nil {
+		return nil, fmt.Errorf("failed to restore %s: %w", name, err)
+	}
+
+	if err := os.Remove(t.infoPath(name)); err != nil && !os.IsNotExist(err) {
+		return item, fmt.Errorf("restored %s but failed to remove trash info: %w", name, err)
+	}
+
+	return item, nil
+}
+
+// Remove deletes path, moving it to the trash when useTrash is set.
+// This is the single entry point callers use for user-visible deletions.
+func Remove(path string, useTrash bool) error {
+	if !useTrash {
+		return os.RemoveAll(path)
+	}
+
+	t, err := New()
+	if err != nil {
+		return err
+	}
+	_, err = t.Put(path)
+	return err
+}
+
+// rename is os.Rename, replaced in tests
+var rename = os.Rename
+
+// move renames src to dst. When they are on different filesystems, as a
+// home on its own mount and the trash can be, src is copied and then
+// removed instead.
+func move(src, dst string) error {
+	err := rename(src, dst)
+	if !errors.Is(err, syscall.EXDEV) {
+		return err
+	}
+	if err := fsutil.CopyTree(src, dst, nil); err != nil {
+		_ = os.RemoveAll(dst)
+		return err
+	}
+	return os.RemoveAll(src)
+}
+
+func (t *Trash) infoPath(name string) string {
+	return filepath.Join(t.InfoDir, name+infoExt)
+}
+
+// readInfo parses the .trashinfo file for an item
+func (t *Trash) readInfo(name string) (*Item, error) {
+	f, err := os.Open(t.infoPath(name))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("%w: %s", ErrNotFound, name)
+		}
+		return nil, fmt.Errorf("failed to read trash info: %w", err)
+	}
+	defer func() { _ = f.Close() }()
+
+	item := &Item{Name: name}
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		key, value, ok := strings.Cut(scanner.Text(), "=")
+		if !ok {
+			continue
+		}
+		switch key {
+		case "Path":
+			path, err := url.PathUnescape(value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid path in trash info for %s: %w", name, err)
+			}
+			item.OriginalPath = path
+		case "DeletionDate":
+			if ts, err := time.ParseInLocation(dateLayout, value, time.Local); err == nil {
+				item.DeletedAt = ts
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read trash info: %w", err)
+	}
+
+	if item.OriginalPath == "" {
+		return nil, fmt.Errorf("trash info for %s has no path", name)
+	}
+
+	return item, nil
+}
+
+// escapePath percent-encodes a path as required by the trash spec,
+// keeping path separators readable.
+func escapePath(path string) string {
+	var b strings.Builder
+	for i := 0; i < len(path); i++ {
+		c := path[i]
+		if c == '/' || c == '-' || c == '_' || c == '.' || c == '~' ||
+			('a' <= c && c <= 'z') || ('A' <= c && c <= 'Z') || ('0' <= c && c <= '9') {
+			b.WriteByte(c)
+			continue
+		}
+		fmt.Fprintf(&b, "%%%02X", c)
+	}
+	return b.String()
+}
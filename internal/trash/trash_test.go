@@ -0,0 +1,196 @@
+package trash
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+)
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatalf("Failed to create dir: %v", err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+}
+
+func TestPutAndRestore(t *testing.T) {
+	tmpDir := t.TempDir()
+	tr := NewAt(filepath.Join(tmpDir, "Trash"))
+	target := filepath.Join(tmpDir, "home", ".bashrc")
+	writeFile(t, target, "export A=1")
+
+	item, err := tr.Put(target)
+	if err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+	if item.Name != ".bashrc" {
+		t.Errorf("Put() name = %q, want %q", item.Name, ".bashrc")
+	}
+	if _, err := os.Lstat(target); !os.IsNotExist(err) {
+		t.Error("Put() should remove the original file")
+	}
+	if _, err := os.Stat(filepath.Join(tr.FilesDir, ".bashrc")); err != nil {
+		t.Errorf("trashed file missing: %v", err)
+	}
+
+	items, err := tr.List()
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if len(items) != 1 || items[0].OriginalPath != target {
+		t.Fatalf("List() = %+v, want one item for %s", items, target)
+	}
+
+	if _, err := tr.Restore(".bashrc"); err != nil {
+		t.Fatalf("Restore() error = %v", err)
+	}
+	data, err := os.ReadFile(target)
+	if err != nil || string(data) != "export A=1" {
+		t.Errorf("restored content = %q, %v", data, err)
+	}
+	if items, _ := tr.List(); len(items) != 0 {
+		t.Errorf("List() after restore = %d items, want 0", len(items))
+	}
+}
+
+func TestPut_NameCollision(t *testing.T) {
+	tmpDir := t.TempDir()
+	tr := NewAt(filepath.Join(tmpDir, "Trash"))
+
+	var names []string
+	for _, dir := range []string{"a", "b", "c"} {
+		target := filepath.Join(tmpDir, dir, "config")
+		writeFile(t, target, dir)
+		item, err := tr.Put(target)
+		if err != nil {
+			t.Fatalf("Put(%s) error = %v", target, err)
+		}
+		names = append(names, item.Name)
+	}
+
+	want := []string{"config", "config.2", "config.3"}
+	for i := range want {
+		if names[i] != want[i] {
+			t.Errorf("name[%d] = %q, want %q", i, names[i], want[i])
+		}
+	}
+}
+
+func TestRestore_Errors(t *testing.T) {
+	tmpDir := t.TempDir()
+	tr := NewAt(filepath.Join(tmpDir, "Trash"))
+	target := filepath.Join(tmpDir, "file")
+	writeFile(t, target, "old")
+	if _, err := tr.Put(target); err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		setup    func()
+		item     string
+		notFound bool
+	}{
+		{name: "unknown item", item: "missing", notFound: true},
+		{name: "path traversal", item: "../file", notFound: true},
+		{
+			name:  "original path exists",
+			setup: func() { writeFile(t, target, "new") },
+			item:  "file",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.setup != nil {
+				tt.setup()
+			}
+			_, err := tr.Restore(tt.item)
+			if err == nil {
+				t.Fatal("Restore() expected error")
+			}
+			if got := errors.Is(err, ErrNotFound); got != tt.notFound {
+				t.Errorf("errors.Is(err, ErrNotFound) = %v, want %v (err: %v)", got, tt.notFound, err)
+			}
+		})
+	}
+
+	// The existing file must not have been overwritten
+	if data, _ := os.ReadFile(target); string(data) != "new" {
+		t.Errorf("existing file was overwritten: %q", data)
+	}
+}
+
+func TestPut_CrossDevice(t *testing.T) {
+	// Renames fail as they do between filesystems
+	orig := rename
+	rename = func(oldpath, newpath string) error {
+		return &os.LinkError{Op: "rename", Old: oldpath, New: newpath, Err: syscall.EXDEV}
+	}
+	defer func() { rename = orig }()
+
+	tmpDir := t.TempDir()
+	tr := NewAt(filepath.Join(tmpDir, "Trash"))
+	target := filepath.Join(tmpDir, "home", ".config", "nvim")
+	writeFile(t, filepath.Join(target, "init.lua"), "-- nvim")
+	if err := os.Chmod(filepath.Join(target, "init.lua"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	item, err := tr.Put(target)
+	if err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+	if _, err := os.Lstat(target); !os.IsNotExist(err) {
+		t.Error("Put() should remove the original directory")
+	}
+	info, err := os.Stat(filepath.Join(tr.FilesDir, item.Name, "init.lua"))
+	if err != nil || info.Mode().Perm() != 0600 {
+		t.Errorf("trashed init.lua = %v, %v; want it copied with its mode", info, err)
+	}
+
+	if _, err := tr.Restore(item.Name); err != nil {
+		t.Fatalf("Restore() error = %v", err)
+	}
+	data, err := os.ReadFile(filepath.Join(target, "init.lua"))
+	if err != nil || string(data) != "-- nvim" {
+		t.Errorf("restored init.lua = %q, %v", data, err)
+	}
+	if _, err := os.Lstat(filepath.Join(tr.FilesDir, item.Name)); !os.IsNotExist(err) {
+		t.Error("Restore() should remove the trashed copy")
+	}
+}
+
+func TestEscapePath(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"/home/user/.bashrc", "/home/user/.bashrc"},
+		{"/home/user/my file", "/home/user/my%20file"},
+		{"/tmp/a%b", "/tmp/a%25b"},
+	}
+
+	for _, tt := range tests {
+		if got := escapePath(tt.in); got != tt.want {
+			t.Errorf("escapePath(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestRemove_WithoutTrash(t *testing.T) {
+	target := filepath.Join(t.TempDir(), "dir", "file")
+	writeFile(t, target, "x")
+
+	if err := Remove(filepath.Dir(target), false); err != nil {
+		t.Fatalf("Remove() error = %v", err)
+	}
+	if _, err := os.Lstat(filepath.Dir(target)); !os.IsNotExist(err) {
+		t.Error("Remove() should delete the directory")
+	}
+}
@@ -0,0 +1,241 @@
+// Package triage helps turn a failed operation into a useful bug report: it
+// suggests doctor checks, writes the full log to disk and builds a prefilled
+// GitHub issue URL with personal details redacted.
+package triage
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"os/exec"
+	"os/user"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/nvandessel/go4dot/internal/platform"
+	"github.com/nvandessel/go4dot/internal/workspace"
+)
+
+const (
+	// IssuesURL is where new go4dot issues are filed
+	IssuesURL = "https://github.com/nvandessel/go4dot/issues/new"
+	// LogsDir holds triage logs, under the user's home
+	LogsDir = ".config/go4dot/logs"
+	// maxIssueLogLines caps how much of the log goes into the issue body
+	maxIssueLogLines = 30
+	// maxIssueBody keeps the issue URL under what browsers and GitHub accept
+	maxIssueBody = 6000
+)
+
+// Report describes a failed operation
+type Report struct {
+	Operation string   // e.g. "Sync All"
+	Step      string   // Name of the step that failed, if known
+	Error     string   // Error the operation ended with
+	Log       []string // Full operation log, oldest first
+	Version   string   // go4dot version
+	Platform  *platform.Platform
+}
+
+// checkKeywords maps words found in the error or failing step to the doctor
+// checks that cover them, in the order they are suggested
+var checkKeywords = []struct {
+	words  []string
+	checks []string
+}{
+	{[]string{"stow", "symlink", "link", "conflict"}, []string{"GNU Stow", "Symlinks"}},
+	{[]string{"dependenc", "package", "install"}, []string{"Dependencies"}},
+	{[]string{"clone", "external", "git"}, []string{"Git", "External Dependencies"}},
+	{[]string{"ssh", "publickey", "permission denied"}, []string{"SSH Keys", "GitHub SSH"}},
+	{[]string{"machine", "template"}, []string{"Machine Configuration"}},
+	{[]string{"platform"}, []string{"Platform Detection"}},
+}
+
+// SuggestChecks returns the doctor checks most likely to explain the failure
+func SuggestChecks(r Report) []string {
+	text := strings.ToLower(r.Operation + " " + r.Step + " " + r.Error)
+
+	var checks []string
+	seen := make(map[string]bool)
+	for _, kw := range checkKeywords {
+		for _, w := range kw.words {
+			if !strings.Contains(text, w) {
+				continue
+			}
+			for _, c := range kw.checks {
+				if !seen[c] {
+					seen[c] = true
+					checks = append(checks, c)
+				}
+			}
+			break
+		}
+	}
+	return checks
+}
+
+// Redactor strips personal details from text before it leaves the machine
+type Redactor struct {
+	Home string
+	User string
+	Host string
+}
+
+// NewRedactor returns a redactor for the current user and machine
+func NewRedactor() Redactor {
+	r := Redactor{}
+	r.Home, _ = os.UserHomeDir()
+	if u, err := user.Current(); err == nil {
+		r.User = u.Username
+	}
+	r.Host, _ = os.Hostname()
+	return r
+}
+
+var (
+	emailPattern = regexp.MustCompile(`[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`)
+	tokenPattern = regexp.MustCompile(`\b(ghp_|gho_|ghs_|github_pat_)[A-Za-z0-9_]+`)
+)
+
+// Redact replaces the home directory, user name, host name, email addresses
+// and GitHub tokens in s with placeholders
+func (r Redactor) Redact(s string) string {
+	s = tokenPattern.ReplaceAllString(s, "<token>")
+	s = emailPattern.ReplaceAllString(s, "<email>")
+	if r.Home != "" && r.Home != "/" {
+		s = strings.ReplaceAll(s, r.Home, "~")
+	}
+	// Very short names would clobber unrelated words
+	if len(r.Host) > 2 {
+		s = strings.ReplaceAll(s, r.Host, "<host>")
+	}
+	if len(r.User) > 2 {
+		s = strings.ReplaceAll(s, r.User, "<user>")
+	}
+	return s
+}
+
+// Text renders the report as a plain-text log
+func (r Report) Text() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "operation: %s\n", r.Operation)
+	if r.Step != "" {
+		fmt.Fprintf(&b, "failed step: %s\n", r.Step)
+	}
+	fmt.Fprintf(&b, "error: %s\n", r.Error)
+	fmt.Fprintf(&b, "go4dot: %s\n", r.Version)
+	fmt.Fprintf(&b, "platform: %s\n\n", platformSummary(r.Platform))
+	for _, line := range r.Log {
+		b.WriteString(line)
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// LogDir returns the directory triage logs are written to
+func LogDir() (string, error) {
+	home, err := workspace.UserHome()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, LogsDir), nil
+}
+
+// WriteLog writes the report to a timestamped file in dir and returns its path
+func (r Report) WriteLog(dir string) (string, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return "", fmt.Errorf("failed to create log directory: %w", err)
+	}
+	name := strings.ToLower(strings.Join(strings.Fields(r.Operation), "-"))
+	if name == "" {
+		name = "operation"
+	}
+	path := filepath.Join(dir, fmt.Sprintf("%s-%s.log", name, time.Now().Format("20060102-150405")))
+	if err := os.WriteFile(path, []byte(r.Text()), 0600); err != nil {
+		return "", fmt.Errorf("failed to write log: %w", err)
+	}
+	return path, nil
+}
+
+// IssueURL returns a new-issue URL prefilled with the report, redacted
+func IssueURL(r Report, red Redactor) string {
+	title := fmt.Sprintf("%s failed: %s", r.Operation, r.Error)
+	if len(title) > 120 {
+		title = title[:117] + "..."
+	}
+
+	var b strings.Builder
+	b.WriteString("### What happened\n\n")
+	fmt.Fprintf(&b, "`%s` failed", r.Operation)
+	if r.Step != "" {
+		fmt.Fprintf(&b, " at step **%s**", r.Step)
+	}
+	fmt.Fprintf(&b, ":\n\n```\n%s\n```\n\n", r.Error)
+
+	b.WriteString("### Environment\n\n")
+	fmt.Fprintf(&b, "- go4dot: %s\n", r.Version)
+	fmt.Fprintf(&b, "- platform: %s\n\n", platformSummary(r.Platform))
+
+	if checks := SuggestChecks(r); len(checks) > 0 {
+		b.WriteString("### Suggested doctor checks\n\n")
+		for _, c := range checks {
+			fmt.Fprintf(&b, "- %s\n", c)
+		}
+		b.WriteString("\n")
+	}
+
+	log := r.Log
+	if len(log) > maxIssueLogLines {
+		log = log[len(log)-maxIssueLogLines:]
+	}
+	if len(log) > 0 {
+		fmt.Fprintf(&b, "### Log (last %d lines)\n\n```\n%s\n```\n", len(log), strings.Join(log, "\n"))
+	}
+
+	body := red.Redact(b.String())
+	if len(body) > maxIssueBody {
+		body = body[:maxIssueBody] + "\n...(truncated)\n"
+	}
+
+	q := url.Values{}
+	q.Set("title", red.Redact(title))
+	q.Set("body", body)
+	return IssuesURL + "?" + q.Encode()
+}
+
+// Open opens a file or URL with the system's default handler
+func Open(target string) error {
+	name := "xdg-open"
+	if runtime.GOOS == "darwin" {
+		name = "open"
+	}
+	cmd := exec.Command(name, target)
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("failed to open %s: %w", target, err)
+	}
+	go func() { _ = cmd.Wait() }()
+	return nil
+}
+
+func platformSummary(p *platform.Platform) string {
+	if p == nil {
+		return runtime.GOOS + "/" + runtime.GOARCH
+	}
+	s := p.OS
+	if p.Distro != "" {
+		s += "/" + p.Distro
+		if p.DistroVersion != "" {
+			s += " " + p.DistroVersion
+		}
+	}
+	if p.Architecture != "" {
+		s += " " + p.Architecture
+	}
+	if p.IsWSL {
+		s += " (WSL)"
+	}
+	return s
+}
@@ -0,0 +1,113 @@
+package triage
+
+import (
+	"net/url"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/nvandessel/go4dot/internal/platform"
+)
+
+func TestSuggestChecks(t *testing.T) {
+	tests := []struct {
+		name   string
+		report Report
+		want   []string
+	}{
+		{"stow", Report{Operation: "Sync All", Error: "stow failed for nvim"}, []string{"GNU Stow", "Symlinks"}},
+		{"clone", Report{Operation: "Update External", Error: "git clone exited 128"}, []string{"Git", "External Dependencies"}},
+		{"ssh", Report{Operation: "Sync", Step: "Connecting", Error: "Permission denied (publickey)"}, []string{"SSH Keys", "GitHub SSH"}},
+		{"step only", Report{Operation: "Install", Step: "Machine config", Error: "boom"}, []string{"Dependencies", "Machine Configuration"}},
+		{"nothing", Report{Operation: "Bulk", Error: "boom"}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := SuggestChecks(tt.report); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("SuggestChecks() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRedactor_Redact(t *testing.T) {
+	r := Redactor{Home: "/home/alice", User: "alice", Host: "workbox"}
+
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"linking /home/alice/.zshrc", "linking ~/.zshrc"},
+		{"alice@workbox:~$", "<user>@<host>:~$"},
+		{"user alice on workbox", "user <user> on <host>"},
+		{"token ghp_abcDEF123 rejected", "token <token> rejected"},
+		{"mail alice.smith@example.com", "mail <email>"},
+	}
+
+	for _, tt := range tests {
+		if got := r.Redact(tt.in); got != tt.want {
+			t.Errorf("Redact(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestIssueURL(t *testing.T) {
+	log := make([]string, 40)
+	for i := range log {
+		log[i] = "line"
+	}
+	log[39] = "failed on /home/alice/.config/nvim"
+
+	r := Report{
+		Operation: "Sync All",
+		Step:      "Stowing",
+		Error:     "stow failed",
+		Log:       log,
+		Version:   "1.2.3",
+		Platform:  &platform.Platform{OS: "linux", Distro: "fedora", Architecture: "amd64"},
+	}
+	raw := IssueURL(r, Redactor{Home: "/home/alice"})
+
+	if !strings.HasPrefix(raw, IssuesURL+"?") {
+		t.Fatalf("IssueURL() = %q, want prefix %q", raw, IssuesURL)
+	}
+	u, err := url.Parse(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	q := u.Query()
+	if got := q.Get("title"); got != "Sync All failed: stow failed" {
+		t.Errorf("title = %q", got)
+	}
+	body := q.Get("body")
+	for _, want := range []string{"**Stowing**", "go4dot: 1.2.3", "linux/fedora amd64", "- GNU Stow", "last 30 lines", "~/.config/nvim"} {
+		if !strings.Contains(body, want) {
+			t.Errorf("body missing %q:\n%s", want, body)
+		}
+	}
+	if strings.Contains(body, "/home/alice") {
+		t.Error("body should not contain the home directory")
+	}
+}
+
+func TestReport_WriteLog(t *testing.T) {
+	dir := t.TempDir()
+	r := Report{Operation: "Sync All", Error: "stow failed", Log: []string{"[error] nvim"}}
+
+	path, err := r.WriteLog(dir)
+	if err != nil {
+		t.Fatalf("WriteLog failed: %v", err)
+	}
+	if !strings.HasPrefix(path, dir) || !strings.Contains(path, "sync-all-") {
+		t.Errorf("WriteLog() path = %q", path)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), "error: stow failed") || !strings.Contains(string(data), "[error] nvim") {
+		t.Errorf("log content = %q", data)
+	}
+}
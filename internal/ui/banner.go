@@ -2,7 +2,6 @@ package ui
 
 import (
 	_ "embed"
-	"fmt"
 
 	"github.com/charmbracelet/lipgloss"
 )
@@ -12,12 +11,12 @@ var banner string
 
 // PrintBanner prints the ASCII art banner
 func PrintBanner(version string) {
-	fmt.Println(lipgloss.NewStyle().
+	Println(lipgloss.NewStyle().
 		Foreground(PrimaryColor).
 		Render(banner))
 
-	fmt.Println(lipgloss.NewStyle().
+	Println(lipgloss.NewStyle().
 		Foreground(SubtleColor).
 		Render("           v" + version))
-	fmt.Println()
+	Println()
 }
@@ -0,0 +1,130 @@
+package ui
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Region is a rectangle of the terminal, in cells
+type Region struct {
+	X      int
+	Y      int
+	Width  int
+	Height int
+}
+
+// CalloutOverlayStyle returns a narrow overlay style for callouts that sit
+// next to the region they describe.
+func CalloutOverlayStyle() OverlayStyle {
+	s := DefaultOverlayStyle()
+	s.MaxWidthPct = 0.35
+	return s
+}
+
+// RenderCallout composites a modal next to target on top of a background
+// view. Like RenderOverlay the background is dimmed, except for target,
+// which is drawn in the border color so the callout points at it. The modal
+// goes to the right of target, or to its left when there is no room. An
+// empty target centers the modal like RenderOverlay.
+func RenderCallout(bg, modal string, width, height int, target Region, style OverlayStyle) string {
+	if target.Width <= 0 || target.Height <= 0 {
+		return RenderOverlay(bg, modal, width, height, style)
+	}
+	if width < 10 || height < 5 {
+		return modal
+	}
+
+	dimmedLines := strings.Split(dimContent(bg, width, height, style.DimChar, style.DimColor), "\n")
+	bgLines := strings.Split(bg, "\n")
+
+	modal = fillBackground(modal, style.Background)
+	styledModal := lipgloss.NewStyle().
+		Border(style.BorderStyle).
+		BorderForeground(style.BorderColor).
+		Padding(style.PaddingV, style.PaddingH).
+		Background(style.Background).
+		Render(modal)
+	modalLines := strings.Split(styledModal, "\n")
+	modalWidth := lipgloss.Width(styledModal)
+	pos := calloutPosition(target, modalWidth, len(modalLines), width, height)
+
+	dimStyle := lipgloss.NewStyle().Foreground(style.DimColor)
+	highlightStyle := lipgloss.NewStyle().Foreground(style.BorderColor)
+
+	for y := 0; y < height && y < len(dimmedLines); y++ {
+		inTarget := y >= target.Y && y < target.Y+target.Height
+		modalRow := y - pos.Y
+		inModal := modalRow >= 0 && modalRow < len(modalLines)
+		if !inTarget && !inModal {
+			continue
+		}
+
+		plain := ""
+		if y < len(bgLines) {
+			plain = stripAnsi(bgLines[y])
+		}
+
+		var b strings.Builder
+		for x := 0; x < width; {
+			switch {
+			case inModal && x == pos.X:
+				line := modalLines[modalRow]
+				b.WriteString(line)
+				if pad := modalWidth - lipgloss.Width(line); pad > 0 {
+					b.WriteString(dimStyle.Render(strings.Repeat(" ", pad)))
+				}
+				x += modalWidth
+			case inTarget && x >= target.X && x < target.X+target.Width:
+				end := nextEdge(x, target.X+target.Width, pos.X, inModal)
+				b.WriteString(highlightStyle.Render(sliceByCells(plain, x, end-x)))
+				x = end
+			default:
+				end := width
+				if inTarget && x < target.X {
+					end = target.X
+				}
+				end = nextEdge(x, end, pos.X, inModal)
+				b.WriteString(dimStyle.Render(sliceByCells(plain, x, end-x)))
+				x = end
+			}
+		}
+		dimmedLines[y] = b.String()
+	}
+
+	return strings.Join(dimmedLines[:height], "\n")
+}
+
+// calloutPosition returns where a modal of the given size goes next to
+// target: to its right, else to its left, else centered, top-aligned with
+// target and kept on screen
+func calloutPosition(target Region, modalWidth, modalHeight, width, height int) Region {
+	x := target.X + target.Width + 1
+	if x+modalWidth > width {
+		x = target.X - modalWidth - 1
+	}
+	if x < 0 {
+		x = (width - modalWidth) / 2
+	}
+	if x < 0 {
+		x = 0
+	}
+
+	y := target.Y
+	if y+modalHeight > height {
+		y = height - modalHeight
+	}
+	if y < 0 {
+		y = 0
+	}
+	return Region{X: x, Y: y, Width: modalWidth, Height: modalHeight}
+}
+
+// nextEdge returns end, or the modal's left edge if the modal starts
+// between x and end on this row
+func nextEdge(x, end, modalX int, inModal bool) int {
+	if inModal && modalX > x && modalX < end {
+		return modalX
+	}
+	return end
+}
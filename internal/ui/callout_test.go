@@ -0,0 +1,58 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+func TestCalloutPosition(t *testing.T) {
+	tests := []struct {
+		name   string
+		target Region
+		want   Region
+	}{
+		{"right of target", Region{X: 0, Y: 2, Width: 20, Height: 10}, Region{X: 21, Y: 2, Width: 30, Height: 8}},
+		{"left of target", Region{X: 60, Y: 0, Width: 20, Height: 10}, Region{X: 29, Y: 0, Width: 30, Height: 8}},
+		{"centered when neither fits", Region{X: 10, Y: 0, Width: 60, Height: 10}, Region{X: 25, Y: 0, Width: 30, Height: 8}},
+		{"kept on screen", Region{X: 0, Y: 20, Width: 20, Height: 4}, Region{X: 21, Y: 16, Width: 30, Height: 8}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := calloutPosition(tt.target, 30, 8, 80, 24); got != tt.want {
+				t.Errorf("calloutPosition() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRenderCallout(t *testing.T) {
+	bg := strings.Repeat(strings.Repeat(".", 80)+"\n", 24)
+	result := RenderCallout(bg, "Callout text", 80, 24, Region{X: 0, Y: 0, Width: 20, Height: 10}, DefaultOverlayStyle())
+
+	if !strings.Contains(result, "Callout text") {
+		t.Error("expected callout output to contain modal content")
+	}
+	lines := strings.Split(result, "\n")
+	if len(lines) != 24 {
+		t.Fatalf("expected 24 lines, got %d", len(lines))
+	}
+	for i, line := range lines {
+		if w := lipgloss.Width(line); w != 80 {
+			t.Errorf("line %d is %d cells wide, want 80", i, w)
+		}
+	}
+	if plain := stripAnsi(lines[0]); !strings.HasPrefix(plain, strings.Repeat(".", 21)+"╭") {
+		t.Errorf("expected target to stay visible left of the callout, got %q", plain)
+	}
+}
+
+func TestRenderCallout_NoTarget(t *testing.T) {
+	bg := strings.Repeat(strings.Repeat(".", 80)+"\n", 24)
+	style := DefaultOverlayStyle()
+	if got, want := RenderCallout(bg, "modal", 80, 24, Region{}, style), RenderOverlay(bg, "modal", 80, 24, style); got != want {
+		t.Error("expected an empty target to render like RenderOverlay")
+	}
+}
@@ -0,0 +1,177 @@
+// Package cli renders the tabular output of the non-interactive commands
+package cli
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/term"
+	"github.com/mattn/go-isatty"
+	"github.com/mattn/go-runewidth"
+)
+
+// columnGap separates adjacent columns
+const columnGap = "  "
+
+// minShrinkWidth is the narrowest a shrinkable column is truncated to
+const minShrinkWidth = 12
+
+// Cell is a single table value with an optional style
+type Cell struct {
+	Text   string
+	Style  lipgloss.Style
+	styled bool
+}
+
+// Text returns an unstyled cell
+func Text(s string) Cell {
+	return Cell{Text: s}
+}
+
+// Styled returns a cell rendered with style when the table has color
+func Styled(s string, style lipgloss.Style) Cell {
+	return Cell{Text: s, Style: style, styled: true}
+}
+
+// Column describes a table column
+type Column struct {
+	Header string
+	Shrink bool // May be truncated when the table is wider than Width
+}
+
+// Table renders rows as aligned columns
+type Table struct {
+	Columns  []Column
+	NoHeader bool // Omit the header row
+	Color    bool // Render cell styles
+	Width    int  // Truncate shrinkable columns to fit; 0 disables truncation
+
+	rows [][]Cell
+}
+
+// NewTable returns a table for stdout, with color and width taken from the
+// terminal. When stdout is not a terminal there is no color and no
+// truncation, so the output stays greppable.
+func NewTable(columns ...Column) *Table {
+	return &Table{
+		Columns: columns,
+		Color:   ColorEnabled(os.Stdout),
+		Width:   TerminalWidth(os.Stdout),
+	}
+}
+
+// ColorEnabled reports whether styled output should be written to f: it
+// must be a terminal and NO_COLOR must not be set
+func ColorEnabled(f *os.File) bool {
+	if _, ok := os.LookupEnv("NO_COLOR"); ok {
+		return false
+	}
+	return isatty.IsTerminal(f.Fd()) || isatty.IsCygwinTerminal(f.Fd())
+}
+
+// TerminalWidth returns the width of the terminal f is attached to, or 0
+// when it is not a terminal
+func TerminalWidth(f *os.File) int {
+	if !term.IsTerminal(f.Fd()) {
+		return 0
+	}
+	width, _, err := term.GetSize(f.Fd())
+	if err != nil {
+		return 0
+	}
+	return width
+}
+
+// AddRow appends a row. Missing cells are left empty and extra cells are
+// dropped.
+func (t *Table) AddRow(cells ...Cell) {
+	row := make([]Cell, len(t.Columns))
+	copy(row, cells)
+	t.rows = append(t.rows, row)
+}
+
+// Len returns the number of rows
+func (t *Table) Len() int {
+	return len(t.rows)
+}
+
+// Render writes the table to w
+func (t *Table) Render(w io.Writer) error {
+	_, err := io.WriteString(w, t.String())
+	return err
+}
+
+// String renders the table, one line per row
+func (t *Table) String() string {
+	widths := t.columnWidths()
+
+	var sb strings.Builder
+	if !t.NoHeader {
+		header := make([]Cell, len(t.Columns))
+		for i, col := range t.Columns {
+			header[i] = Styled(col.Header, lipgloss.NewStyle().Bold(true))
+		}
+		t.writeRow(&sb, header, widths)
+	}
+	for _, row := range t.rows {
+		t.writeRow(&sb, row, widths)
+	}
+	return sb.String()
+}
+
+// columnWidths sizes each column to its widest value, then shrinks the
+// shrinkable columns, last first, until the table fits Width
+func (t *Table) columnWidths() []int {
+	widths := make([]int, len(t.Columns))
+	for i, col := range t.Columns {
+		if !t.NoHeader {
+			widths[i] = runewidth.StringWidth(col.Header)
+		}
+		for _, row := range t.rows {
+			widths[i] = max(widths[i], runewidth.StringWidth(row[i].Text))
+		}
+	}
+	if t.Width <= 0 {
+		return widths
+	}
+
+	total := runewidth.StringWidth(columnGap) * (len(widths) - 1)
+	for _, w := range widths {
+		total += w
+	}
+	for i := len(widths) - 1; i >= 0 && total > t.Width; i-- {
+		if !t.Columns[i].Shrink || widths[i] <= minShrinkWidth {
+			continue
+		}
+		cut := min(total-t.Width, widths[i]-minShrinkWidth)
+		widths[i] -= cut
+		total -= cut
+	}
+	return widths
+}
+
+// writeRow writes one padded line. The last column is not padded so lines
+// carry no trailing spaces.
+func (t *Table) writeRow(sb *strings.Builder, row []Cell, widths []int) {
+	var line strings.Builder
+	for i, cell := range row {
+		text := runewidth.Truncate(cell.Text, widths[i], "…")
+		pad := widths[i] - runewidth.StringWidth(text)
+
+		if i > 0 {
+			line.WriteString(columnGap)
+		}
+		if t.Color && cell.styled {
+			line.WriteString(cell.Style.Render(text))
+		} else {
+			line.WriteString(text)
+		}
+		if i < len(row)-1 {
+			line.WriteString(strings.Repeat(" ", pad))
+		}
+	}
+	fmt.Fprintln(sb, strings.TrimRight(line.String(), " "))
+}
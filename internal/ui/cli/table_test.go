@@ -0,0 +1,119 @@
+package cli
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+func TestTable_String(t *testing.T) {
+	tests := []struct {
+		name     string
+		columns  []Column
+		rows     [][]Cell
+		noHeader bool
+		width    int
+		want     string
+	}{
+		{
+			name:    "aligned columns",
+			columns: []Column{{Header: "NAME"}, {Header: "STATUS"}, {Header: "PATH"}},
+			rows: [][]Cell{
+				{Text("git"), Text("installed"), Text("/usr/bin/git")},
+				{Text("neovim"), Text("missing"), Text("")},
+			},
+			want: "" +
+				"NAME    STATUS     PATH\n" +
+				"git     installed  /usr/bin/git\n" +
+				"neovim  missing\n",
+		},
+		{
+			name:     "no header",
+			columns:  []Column{{Header: "NAME"}, {Header: "STATUS"}},
+			rows:     [][]Cell{{Text("zsh"), Text("ok")}},
+			noHeader: true,
+			want:     "zsh  ok\n",
+		},
+		{
+			name:    "short rows are padded",
+			columns: []Column{{Header: "A"}, {Header: "B"}, {Header: "C"}},
+			rows:    [][]Cell{{Text("x")}},
+			want:    "A  B  C\nx\n",
+		},
+		{
+			name:    "shrinkable column truncated to width",
+			columns: []Column{{Header: "NAME"}, {Header: "DESCRIPTION", Shrink: true}},
+			rows: [][]Cell{
+				{Text("nvim"), Text("Neovim with lazy.nvim plugins and LSP setup")},
+			},
+			width: 26,
+			want: "" +
+				"NAME  DESCRIPTION\n" +
+				"nvim  Neovim with lazy.nv…\n",
+		},
+		{
+			name:    "fixed columns are never truncated",
+			columns: []Column{{Header: "NAME"}, {Header: "PATH"}},
+			rows:    [][]Cell{{Text("nvim"), Text("/home/user/.config/nvim")}},
+			width:   10,
+			want: "" +
+				"NAME  PATH\n" +
+				"nvim  /home/user/.config/nvim\n",
+		},
+		{
+			name:    "wide characters",
+			columns: []Column{{Header: "ICON"}, {Header: "NAME"}},
+			rows:    [][]Cell{{Text("✓"), Text("ok")}, {Text("日本"), Text("wide")}},
+			want: "" +
+				"ICON  NAME\n" +
+				"✓     ok\n" +
+				"日本  wide\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			table := &Table{Columns: tt.columns, NoHeader: tt.noHeader, Width: tt.width}
+			for _, row := range tt.rows {
+				table.AddRow(row...)
+			}
+			if got := table.String(); got != tt.want {
+				t.Errorf("String() =\n%q\nwant\n%q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTable_StylesOnlyWithColor(t *testing.T) {
+	style := lipgloss.NewStyle().Bold(true)
+
+	table := &Table{Columns: []Column{{Header: "STATUS"}, {Header: "NAME"}}, NoHeader: true}
+	table.AddRow(Styled("ok", style), Text("zsh"))
+
+	var buf bytes.Buffer
+	if err := table.Render(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if buf.String() != "ok  zsh\n" {
+		t.Errorf("Render() without color = %q", buf.String())
+	}
+
+	// Padding is computed from the plain text, so styled cells stay aligned
+	// whatever escape codes the renderer adds
+	table.Color = true
+	if got := table.String(); !strings.HasSuffix(got, "  zsh\n") || !strings.Contains(got, "ok") {
+		t.Errorf("String() with color = %q", got)
+	}
+	if table.Len() != 1 {
+		t.Errorf("Len() = %d, want 1", table.Len())
+	}
+}
+
+func TestColorEnabled_NoColor(t *testing.T) {
+	t.Setenv("NO_COLOR", "1")
+	if ColorEnabled(nil) {
+		t.Error("ColorEnabled() should be false when NO_COLOR is set")
+	}
+}
@@ -2,125 +2,151 @@ package ui
 
 import (
 	"fmt"
+	"os"
 
 	"github.com/nvandessel/go4dot/internal/config"
 	"github.com/nvandessel/go4dot/internal/platform"
 	"github.com/nvandessel/go4dot/internal/state"
+	"github.com/nvandessel/go4dot/internal/ui/cli"
 )
 
-// PrintConfigList prints the status of all configs using internal/ui styles.
-func PrintConfigList(cfg *config.Config, st *state.State, p *platform.Platform, showAll bool) {
-	// Build installed set from state
-	installed := make(map[string]bool)
-	if st != nil {
-		for _, c := range st.Configs {
-			installed[c.Name] = true
+// PrintConfigList prints the status of all configs as tables. With noHeader
+// only the table rows are printed: no section titles, column headers or
+// summary.
+func PrintConfigList(cfg *config.Config, st *state.State, p *platform.Platform, showAll, noHeader bool) {
+	section := func(title string) {
+		if noHeader {
+			fmt.Println()
+		} else {
+			Section(title)
 		}
 	}
 
-	// Core configs
-	Section("Core Configs")
-	for _, c := range cfg.Configs.Core {
-		printConfigStatus(c, installed, p, showAll)
+	if !noHeader {
+		Section("Configs")
+	}
+	configs := configListTable(cfg, st, p, showAll)
+	configs.NoHeader = noHeader
+	_ = configs.Render(os.Stdout)
+
+	if external := externalListTable(cfg, st, p, showAll); external.Len() > 0 {
+		section("External Dependencies")
+		external.NoHeader = noHeader
+		_ = external.Render(os.Stdout)
 	}
 
-	// Optional configs
-	if len(cfg.Configs.Optional) > 0 {
-		Section("Optional Configs")
-		for _, c := range cfg.Configs.Optional {
-			printConfigStatus(c, installed, p, showAll)
-		}
+	if machine := machineListTable(cfg, st); machine.Len() > 0 {
+		section("Machine Configurations")
+		machine.NoHeader = noHeader
+		_ = machine.Render(os.Stdout)
 	}
 
-	// External deps
-	if len(cfg.External) > 0 {
-		Section("External Dependencies")
-		for _, e := range cfg.External {
-			status := "x"
-			info := "not installed"
-
-			if st != nil {
-				if ext, ok := st.ExternalDeps[e.ID]; ok && ext.Installed {
-					status = "+"
-					info = ext.Path
-				}
-			}
+	if noHeader {
+		return
+	}
 
-			// Check if skipped due to platform
-			if !platform.CheckCondition(e.Condition, p) {
-				if showAll {
-					fmt.Printf("  ⊘ %s (skipped - platform mismatch)\n", e.Name)
-				}
-				continue
-			}
+	Section("Summary")
+	if st != nil {
+		fmt.Printf("Installed: %d configs\n", len(st.Configs))
+		if st.DotfilesPath != "" {
+			fmt.Printf("Dotfiles:  %s\n", st.DotfilesPath)
+		}
+	} else {
+		Warning("No installation state found. Run 'g4d install' to set up.")
+	}
+}
 
-			if status == "+" {
-				Success("%s (%s)", e.Name, info)
-			} else {
-				fmt.Printf("  • %s (%s)\n", e.Name, info)
-			}
+// configListTable lists core and optional configs with their install
+// status. With showAll, configs for other platforms and archived configs
+// are included.
+func configListTable(cfg *config.Config, st *state.State, p *platform.Platform, showAll bool) *cli.Table {
+	installed := make(map[string]bool)
+	if st != nil {
+		for _, c := range st.Configs {
+			installed[c.Name] = true
 		}
 	}
 
-	// Machine configs
-	if len(cfg.MachineConfig) > 0 {
-		Section("Machine Configurations")
-		for _, mc := range cfg.MachineConfig {
-			status := "x"
-			info := "not configured"
-
-			if st != nil {
-				if m, ok := st.MachineConfig[mc.ID]; ok {
-					status = "+"
-					info = m.ConfigPath
+	table := cli.NewTable(
+		cli.Column{Header: "NAME"},
+		cli.Column{Header: "GROUP"},
+		cli.Column{Header: "STATUS"},
+		cli.Column{Header: "DESCRIPTION", Shrink: true},
+	)
+
+	add := func(group string, items []config.ConfigItem) {
+		for _, c := range items {
+			var status cli.Cell
+			switch {
+			case len(c.Platforms) > 0 && !isPlatformMatch(c.Platforms, p):
+				if !showAll {
+					continue
 				}
+				status = cli.Styled("unavailable on "+p.OS, SubtleStyle)
+			case installed[c.Name]:
+				status = cli.Styled("installed", SuccessStyle)
+			default:
+				status = cli.Styled("not installed", SubtleStyle)
 			}
-
-			if status == "+" {
-				Success("%s (%s)", mc.Description, info)
-			} else {
-				fmt.Printf("  • %s (%s)\n", mc.Description, info)
-			}
+			table.AddRow(cli.Text(c.Name), cli.Text(group), status, cli.Text(c.Description))
 		}
 	}
+	add("core", cfg.Configs.Core)
+	add("optional", cfg.Configs.Optional)
 
-	// Archived configs
-	if len(cfg.Archived) > 0 && showAll {
-		Section("Archived Configs (deprecated)")
+	if showAll {
 		for _, c := range cfg.Archived {
-			fmt.Printf("  - %s\n", c.Name)
-			if c.Description != "" {
-				fmt.Printf("    %s\n", c.Description)
-			}
+			table.AddRow(cli.Text(c.Name), cli.Text("archived"), cli.Styled("archived", SubtleStyle), cli.Text(c.Description))
 		}
 	}
+	return table
+}
 
-	// Summary
-	Section("Summary")
-	if st != nil {
-		fmt.Printf("Installed: %d configs\n", len(st.Configs))
-		if st.DotfilesPath != "" {
-			fmt.Printf("Dotfiles:  %s\n", st.DotfilesPath)
+// externalListTable lists external dependencies with their install status
+func externalListTable(cfg *config.Config, st *state.State, p *platform.Platform, showAll bool) *cli.Table {
+	table := cli.NewTable(
+		cli.Column{Header: "NAME"},
+		cli.Column{Header: "STATUS"},
+		cli.Column{Header: "PATH", Shrink: true},
+	)
+
+	for _, e := range cfg.External {
+		if !platform.CheckCondition(e.Condition, p) {
+			if showAll {
+				table.AddRow(cli.Text(e.Name), cli.Styled("skipped (platform mismatch)", SubtleStyle))
+			}
+			continue
 		}
-	} else {
-		Warning("No installation state found. Run 'g4d install' to set up.")
-	}
-}
 
-func printConfigStatus(c config.ConfigItem, installed map[string]bool, p *platform.Platform, showAll bool) {
-	// Check platform compatibility
-	if len(c.Platforms) > 0 && !isPlatformMatch(c.Platforms, p) {
-		if showAll {
-			fmt.Printf("  ⊘ %s (not available on %s)\n", c.Name, p.OS)
+		if st != nil {
+			if ext, ok := st.ExternalDeps[e.ID]; ok && ext.Installed {
+				table.AddRow(cli.Text(e.Name), cli.Styled("installed", SuccessStyle), cli.Text(ext.Path))
+				continue
+			}
 		}
-		return
+		table.AddRow(cli.Text(e.Name), cli.Styled("not installed", SubtleStyle))
 	}
+	return table
+}
 
-	if installed[c.Name] {
-		Success("%s - %s (installed)", c.Name, c.Description)
-	} else {
-		fmt.Printf("  • %s - %s (not installed)\n", c.Name, c.Description)
+// machineListTable lists machine configs and where they were written
+func machineListTable(cfg *config.Config, st *state.State) *cli.Table {
+	table := cli.NewTable(
+		cli.Column{Header: "NAME"},
+		cli.Column{Header: "STATUS"},
+		cli.Column{Header: "PATH", Shrink: true},
+	)
+
+	for _, mc := range cfg.MachineConfig {
+		if st != nil {
+			if m, ok := st.MachineConfig[mc.ID]; ok {
+				table.AddRow(cli.Text(mc.Description), cli.Styled("configured", SuccessStyle), cli.Text(m.ConfigPath))
+				continue
+			}
+		}
+		table.AddRow(cli.Text(mc.Description), cli.Styled("not configured", SubtleStyle))
 	}
+	return table
 }
 
 func isPlatformMatch(platforms []string, p *platform.Platform) bool {
@@ -3,7 +3,9 @@ package ui
 import (
 	"testing"
 
+	"github.com/nvandessel/go4dot/internal/config"
 	"github.com/nvandessel/go4dot/internal/platform"
+	"github.com/nvandessel/go4dot/internal/state"
 )
 
 func TestIsPlatformMatch(t *testing.T) {
@@ -84,3 +86,55 @@ func TestIsPlatformMatch(t *testing.T) {
 		})
 	}
 }
+
+func TestConfigListTable(t *testing.T) {
+	cfg := &config.Config{
+		Configs: config.ConfigGroups{
+			Core: []config.ConfigItem{
+				{Name: "zsh", Description: "Zsh shell"},
+				{Name: "i3", Description: "i3 window manager", Platforms: []string{"linux"}},
+			},
+			Optional: []config.ConfigItem{
+				{Name: "tmux", Description: "Tmux"},
+			},
+		},
+		Archived: []config.ConfigItem{{Name: "vim", Description: "Old vim"}},
+	}
+	st := &state.State{Configs: []state.ConfigState{{Name: "zsh"}}}
+	p := &platform.Platform{OS: "darwin"}
+
+	tests := []struct {
+		name    string
+		showAll bool
+		want    string
+	}{
+		{
+			name: "available configs",
+			want: `NAME  GROUP     STATUS         DESCRIPTION
+zsh   core      installed      Zsh shell
+tmux  optional  not installed  Tmux
+`,
+		},
+		{
+			name:    "all configs",
+			showAll: true,
+			want: `NAME  GROUP     STATUS                 DESCRIPTION
+zsh   core      installed              Zsh shell
+i3    core      unavailable on darwin  i3 window manager
+tmux  optional  not installed          Tmux
+vim   archived  archived               Old vim
+`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			table := configListTable(cfg, st, p, tt.showAll)
+			table.Color = false
+			table.Width = 0
+			if got := table.String(); got != tt.want {
+				t.Errorf("configListTable() =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
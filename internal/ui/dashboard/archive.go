@@ -0,0 +1,77 @@
+package dashboard
+
+import (
+	"fmt"
+	"path/filepath"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/nvandessel/go4dot/internal/config"
+	"github.com/nvandessel/go4dot/internal/setup"
+	"github.com/nvandessel/go4dot/internal/state"
+	"github.com/nvandessel/go4dot/internal/ui"
+)
+
+// toggleArchive restores the selected archived config, or asks before
+// archiving the selected active one since its links are removed
+func (m *Model) toggleArchive() tea.Cmd {
+	if m.state.Config == nil || m.operationActive {
+		return nil
+	}
+
+	if item := m.configsPanel.GetSelectedArchived(); item != nil {
+		return m.setArchived(item.Name, false)
+	}
+
+	item := m.configsPanel.GetSelectedConfig()
+	if item == nil {
+		return nil
+	}
+	m.confirm = NewConfirm(
+		"archive",
+		fmt.Sprintf("Archive %s?", item.Name),
+		"Its links are removed and sync, doctor and status leave it out. Its files stay in the repo and it can be restored with 'a'.",
+	).WithLabels("Archive", "Cancel")
+	contentWidth, contentHeight := overlayContentSize(m.width, m.height, ui.ConfirmOverlayStyle())
+	m.confirm.SetSize(contentWidth, contentHeight)
+	m.pendingConfigName = item.Name
+	m.pushView(viewConfirm)
+	return nil
+}
+
+// setArchived archives or restores the named config in .go4dot.yaml and
+// reloads the dashboard from the edited file
+func (m *Model) setArchived(name string, archived bool) tea.Cmd {
+	if m.state.Demo {
+		m.outputPanel.AddLog("info", fmt.Sprintf("Demo mode: %s was not changed", name))
+		return nil
+	}
+
+	configPath := filepath.Join(m.state.DotfilesPath, config.ConfigFileName)
+	var err error
+	if archived {
+		// Missing state is fine: there is nothing to update
+		st, _ := state.Load()
+		err = setup.ArchiveConfig(m.state.Config, configPath, st, name, setup.RefactorOptions{
+			ProgressFunc: func(_, _ int, msg string) {
+				m.outputPanel.AddLog("info", msg)
+			},
+		})
+	} else {
+		err = setup.RestoreConfig(m.state.Config, configPath, name)
+	}
+	if err != nil {
+		m.outputPanel.AddLog("error", err.Error())
+		return nil
+	}
+
+	if !archived {
+		m.outputPanel.AddLog("success", fmt.Sprintf("Restored %s; sync to link it", name))
+	}
+
+	cfg, err := config.LoadFromPath(configPath)
+	if err != nil {
+		m.outputPanel.AddLog("error", fmt.Sprintf("Failed to reload config: %v", err))
+		return nil
+	}
+	return m.loadConfig(cfg, m.state.DotfilesPath)
+}
@@ -0,0 +1,65 @@
+package dashboard
+
+import (
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/nvandessel/go4dot/internal/config"
+	"github.com/nvandessel/go4dot/internal/state"
+	"github.com/nvandessel/go4dot/internal/vcs"
+)
+
+// configChangesMsg reports how .go4dot.yaml changed since the revision the
+// configs were last synced from
+type configChangesMsg struct {
+	since string
+	diff  config.ConfigDiff
+}
+
+// configChangesCmd compares .go4dot.yaml with the revision recorded at the
+// last sync, once the repo has moved on from it, e.g. after a pull
+func configChangesCmd(dotfilesPath string) tea.Cmd {
+	return func() tea.Msg {
+		st, err := state.Load()
+		if err != nil || st == nil || st.Revision == "" {
+			return nil
+		}
+		repo := vcs.Detect(dotfilesPath)
+		if repo == nil || !vcs.Installed(repo) {
+			return nil
+		}
+		if head, err := repo.Head(dotfilesPath); err != nil || head == st.Revision {
+			return nil
+		}
+
+		synced, err := config.LoadRevision(dotfilesPath, st.Revision)
+		if err != nil {
+			return nil
+		}
+		current, err := config.LoadRevision(dotfilesPath, "")
+		if err != nil {
+			return nil
+		}
+		diff := config.Compare(synced, current)
+		if len(diff) == 0 {
+			return nil
+		}
+		return configChangesMsg{since: st.Revision, diff: diff}
+	}
+}
+
+// logConfigChanges lists the changes in the output panel
+func (m *Model) logConfigChanges(msg configChangesMsg) {
+	since := msg.since
+	if len(since) > 12 {
+		since = since[:12]
+	}
+	entries := []LogEntry{{
+		Level:   "info",
+		Message: fmt.Sprintf("%s changed since the last sync (%s): %s", config.ConfigFileName, since, msg.diff.Summary()),
+	}}
+	for _, c := range msg.diff {
+		entries = append(entries, LogEntry{Level: "info", Message: "  " + c.String()})
+	}
+	m.outputPanel.AddLogs(entries...)
+}
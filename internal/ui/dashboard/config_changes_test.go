@@ -0,0 +1,64 @@
+package dashboard
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/nvandessel/go4dot/internal/config"
+	"github.com/nvandessel/go4dot/internal/state"
+)
+
+func TestConfigChangesCmd(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	t.Setenv("HOME", t.TempDir())
+	dir := t.TempDir()
+	git := func(args ...string) string {
+		t.Helper()
+		cmd := exec.Command("git", append([]string{"-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)...)
+		cmd.Dir = dir
+		out, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("git %v failed: %v\n%s", args, err, out)
+		}
+		return strings.TrimSpace(string(out))
+	}
+	commit := func(content string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(dir, config.ConfigFileName), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		git("add", ".")
+		git("commit", "-q", "-m", "update")
+	}
+
+	git("init", "-q")
+	commit("configs:\n  core:\n    - name: vim\n      path: vim\n")
+	st := state.New()
+	st.Revision = git("rev-parse", "HEAD")
+	if err := st.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Nothing is reported until the repo moves past the synced revision
+	if msg := configChangesCmd(dir)(); msg != nil {
+		t.Fatalf("configChangesCmd() = %v before any pull", msg)
+	}
+
+	commit("configs:\n  core:\n    - name: vim\n      path: vim\n    - name: zsh\n      path: zsh\n")
+	msg, ok := configChangesCmd(dir)().(configChangesMsg)
+	if !ok || len(msg.diff) != 1 || msg.diff[0].String() != "config zsh added" {
+		t.Fatalf("configChangesCmd() = %+v, want zsh added", msg)
+	}
+
+	m := New(State{HasConfig: true, Config: &config.Config{}, DotfilesPath: dir})
+	m.logConfigChanges(msg)
+	logs := m.outputPanel.GetLogs()
+	if len(logs) != 2 || !strings.Contains(logs[0].Message, "changed since the last sync") || logs[1].Message != "  config zsh added" {
+		t.Errorf("logs = %+v", logs)
+	}
+}
@@ -0,0 +1,94 @@
+package dashboard
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/nvandessel/go4dot/internal/ui"
+)
+
+// readmeNames are the file names recognized as a config's README, in order
+// of preference. Matching is case-insensitive.
+var readmeNames = []string{"README.md", "README.markdown", "README"}
+
+// findConfigReadme returns the path of the README in a config directory, or
+// "" if it has none
+func findConfigReadme(dir string) string {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return ""
+	}
+	for _, name := range readmeNames {
+		for _, e := range entries {
+			if !e.IsDir() && strings.EqualFold(e.Name(), name) {
+				return filepath.Join(dir, e.Name())
+			}
+		}
+	}
+	return ""
+}
+
+// ToggleDocs switches the details panel between the selected config's
+// details and its README. It reports whether doc mode is now on.
+func (p *DetailsPanel) ToggleDocs() bool {
+	p.docMode = !p.docMode
+	p.viewport.GotoTop()
+	p.updateContent()
+	return p.docMode
+}
+
+// DocMode reports whether the panel shows the selected config's README
+func (p *DetailsPanel) DocMode() bool {
+	return p.docMode
+}
+
+// selectedConfigReadme returns the README of the selected config, or ""
+func (p *DetailsPanel) selectedConfigReadme() string {
+	if p.configsPanel == nil || p.state.DotfilesPath == "" {
+		return ""
+	}
+	cfg := p.configsPanel.GetSelectedConfig()
+	if cfg == nil || cfg.Path == "" {
+		return ""
+	}
+	return findConfigReadme(cfg.Dir(p.state.DotfilesPath))
+}
+
+// renderConfigDocs renders the selected config's README
+func (p *DetailsPanel) renderConfigDocs() string {
+	if p.configsPanel == nil {
+		return ui.SubtleStyle.Render("No config selected")
+	}
+	cfg := p.configsPanel.GetSelectedConfig()
+	if cfg == nil {
+		return ui.SubtleStyle.Render("No config selected")
+	}
+
+	titleStyle := lipgloss.NewStyle().
+		Foreground(ui.TextColor).
+		Bold(true).
+		Background(ui.PrimaryColor).
+		Padding(0, 1)
+	lines := []string{titleStyle.Render(strings.ToUpper(cfg.Name) + " · README"), ""}
+
+	path := p.selectedConfigReadme()
+	if path == "" {
+		lines = append(lines,
+			ui.SubtleStyle.Render(fmt.Sprintf("No README.md in %s/", cfg.Path)),
+			"",
+			ui.SubtleStyle.Render("Press D to return to details"))
+		return strings.Join(lines, "\n")
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		lines = append(lines, ui.ErrorStyle.Render(fmt.Sprintf("Failed to read %s: %v", filepath.Base(path), err)))
+		return strings.Join(lines, "\n")
+	}
+
+	lines = append(lines, renderMarkdownPreview(string(data), p.ContentWidth()))
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,116 @@
+package dashboard
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/nvandessel/go4dot/internal/config"
+	"github.com/nvandessel/go4dot/internal/platform"
+)
+
+func TestFindConfigReadme(t *testing.T) {
+	tests := []struct {
+		name  string
+		files []string
+		want  string
+	}{
+		{"none", []string{".vimrc"}, ""},
+		{"readme.md", []string{".vimrc", "README.md"}, "README.md"},
+		{"case-insensitive", []string{"Readme.md"}, "Readme.md"},
+		{"prefers markdown", []string{"README", "README.md"}, "README.md"},
+		{"plain readme", []string{"README"}, "README"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			for _, f := range tt.files {
+				if err := os.WriteFile(filepath.Join(dir, f), []byte("x"), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			got := findConfigReadme(dir)
+			if tt.want == "" {
+				if got != "" {
+					t.Errorf("findConfigReadme() = %q, want none", got)
+				}
+				return
+			}
+			if filepath.Base(got) != tt.want {
+				t.Errorf("findConfigReadme() = %q, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDashboard_ConfigDocs(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	dotfiles := t.TempDir()
+	for path, content := range map[string]string{
+		"vim/.vimrc":    "set number\n",
+		"vim/README.md": "# Vim setup\n\nRun `:PlugInstall` after the **first** sync.\n",
+		"zsh/.zshrc":    "export EDITOR=vim\n",
+	} {
+		full := filepath.Join(dotfiles, path)
+		if err := os.MkdirAll(filepath.Dir(full), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(full, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	cfg := &config.Config{Configs: config.ConfigGroups{Core: []config.ConfigItem{
+		{Name: "vim", Path: "vim"},
+		{Name: "zsh", Path: "zsh"},
+	}}}
+	m := New(State{
+		Platform:     &platform.Platform{OS: "linux"},
+		Config:       cfg,
+		Configs:      cfg.GetAllConfigs(),
+		DotfilesPath: dotfiles,
+		HasConfig:    true,
+	})
+	m.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+
+	if !strings.Contains(m.detailsPanel.renderConfigDetails(), "Press D to read README.md") {
+		t.Error("expected the details to point at the README")
+	}
+
+	docs := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("D")}
+	m.Update(docs)
+	if !m.detailsPanel.DocMode() {
+		t.Fatal("expected D to open the README")
+	}
+	if m.focusManager.CurrentFocus() != PanelDetails {
+		t.Error("expected the Details panel to be focused for scrolling")
+	}
+	content := m.detailsPanel.viewport.View()
+	for _, want := range []string{"VIM · README", "Vim setup", "Run :PlugInstall after the first"} {
+		if !strings.Contains(content, want) {
+			t.Errorf("README view missing %q:\n%s", want, content)
+		}
+	}
+
+	m.Update(docs)
+	if m.detailsPanel.DocMode() {
+		t.Error("expected D to return to the details")
+	}
+
+	// A config without a README says so
+	m.detailsPanel.ToggleDocs()
+	m.changeFocus(PanelConfigs)
+	m.Update(tea.KeyMsg{Type: tea.KeyDown})
+	if !strings.Contains(m.detailsPanel.viewport.View(), "No README.md in zsh/") {
+		t.Errorf("expected a missing README note, got:\n%s", m.detailsPanel.viewport.View())
+	}
+
+	// Leaving the configs context ends doc mode
+	m.changeFocus(PanelHealth)
+	if m.detailsPanel.DocMode() {
+		t.Error("expected doc mode to end when another panel drives the details")
+	}
+}
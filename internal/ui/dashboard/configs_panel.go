@@ -21,27 +21,63 @@ type ConfigsPanel struct {
 	state        State
 	selectedIdx  int
 	listOffset   int
-	filteredIdxs []int
+	filteredIdxs []int           // Rows in display order; see archivedHeaderRow
 	selected     map[string]bool // Multi-select state
 }
 
+// archivedHeaderRow marks the row in filteredIdxs that heads the archived
+// section. Rows after it index past state.Configs into the archived configs.
+const archivedHeaderRow = -1
+
 // NewConfigsPanel creates a new configs panel
 func NewConfigsPanel(state State, selected map[string]bool) *ConfigsPanel {
-	filteredIdxs := make([]int, len(state.Configs))
-	for i := range state.Configs {
-		filteredIdxs[i] = i
-	}
-
 	if selected == nil {
 		selected = make(map[string]bool)
 	}
 
-	return &ConfigsPanel{
-		BasePanel:    NewBasePanel(PanelConfigs, "5 Configs"),
-		state:        state,
-		filteredIdxs: filteredIdxs,
-		selected:     selected,
+	p := &ConfigsPanel{
+		BasePanel: NewBasePanel(PanelConfigs, "5 Configs"),
+		state:     state,
+		selected:  selected,
+	}
+	p.filteredIdxs = p.rows("")
+	return p
+}
+
+// archived returns the configs listed in the archived section
+func (p *ConfigsPanel) archived() []config.ConfigItem {
+	if p.state.Config == nil {
+		return nil
 	}
+	return p.state.Config.Archived
+}
+
+// rows lists the configs whose names contain filterText, active ones first
+// and then, under a header row, archived ones
+func (p *ConfigsPanel) rows(filterText string) []int {
+	matches := func(name string) bool {
+		return filterText == "" || strings.Contains(strings.ToLower(name), strings.ToLower(filterText))
+	}
+
+	rows := []int{}
+	for i, cfg := range p.state.Configs {
+		if matches(cfg.Name) {
+			rows = append(rows, i)
+		}
+	}
+
+	headed := false
+	for i, cfg := range p.archived() {
+		if !matches(cfg.Name) {
+			continue
+		}
+		if !headed {
+			rows = append(rows, archivedHeaderRow)
+			headed = true
+		}
+		rows = append(rows, len(p.state.Configs)+i)
+	}
+	return rows
 }
 
 // Init implements Panel interface
@@ -67,15 +103,9 @@ func (p *ConfigsPanel) Update(msg tea.Msg) tea.Cmd {
 
 		switch {
 		case key.Matches(msg, key.NewBinding(key.WithKeys("k", "up"))):
-			if currentPos > 0 {
-				p.selectedIdx = p.filteredIdxs[currentPos-1]
-				p.ensureVisible()
-			}
+			p.moveSelection(currentPos, -1)
 		case key.Matches(msg, key.NewBinding(key.WithKeys("j", "down"))):
-			if currentPos < len(p.filteredIdxs)-1 {
-				p.selectedIdx = p.filteredIdxs[currentPos+1]
-				p.ensureVisible()
-			}
+			p.moveSelection(currentPos, 1)
 		}
 
 	case tea.MouseMsg:
@@ -96,7 +126,7 @@ func (p *ConfigsPanel) Update(msg tea.Msg) tea.Cmd {
 			if msg.Action == tea.MouseActionRelease {
 				clickedLine := msg.Y - 2
 				clickedIdx := p.listOffset + clickedLine
-				if clickedIdx >= 0 && clickedIdx < len(p.filteredIdxs) {
+				if clickedIdx >= 0 && clickedIdx < len(p.filteredIdxs) && p.filteredIdxs[clickedIdx] != archivedHeaderRow {
 					p.selectedIdx = p.filteredIdxs[clickedIdx]
 				}
 			}
@@ -106,6 +136,18 @@ func (p *ConfigsPanel) Update(msg tea.Msg) tea.Cmd {
 	return nil
 }
 
+// moveSelection moves the selection from row pos by step, stepping over the
+// archived section header
+func (p *ConfigsPanel) moveSelection(pos, step int) {
+	for next := pos + step; next >= 0 && next < len(p.filteredIdxs); next += step {
+		if p.filteredIdxs[next] != archivedHeaderRow {
+			p.selectedIdx = p.filteredIdxs[next]
+			p.ensureVisible()
+			return
+		}
+	}
+}
+
 func (p *ConfigsPanel) ensureVisible() {
 	currentPos := -1
 	for i, idx := range p.filteredIdxs {
@@ -150,6 +192,14 @@ func (p *ConfigsPanel) View() string {
 
 	for i := p.listOffset; i < endIdx; i++ {
 		idx := p.filteredIdxs[i]
+		if idx == archivedHeaderRow {
+			lines = append(lines, ui.SubtleStyle.Render(truncateString("── archived ──", p.ContentWidth())))
+			continue
+		}
+		if idx >= len(p.state.Configs) {
+			lines = append(lines, p.archivedRow(idx))
+			continue
+		}
 		cfg := p.state.Configs[idx]
 
 		prefix := "  "
@@ -204,6 +254,21 @@ func (p *ConfigsPanel) View() string {
 	return strings.Join(lines, "\n")
 }
 
+// archivedRow renders an archived config: no checkbox or link status, since
+// archived configs are not synced
+func (p *ConfigsPanel) archivedRow(idx int) string {
+	prefix := "  "
+	if idx == p.selectedIdx && p.focused {
+		prefix = "> "
+	}
+	content := fmt.Sprintf("%-*s", p.ContentWidth(), truncateString(prefix+"    "+p.archived()[idx-len(p.state.Configs)].Name, p.ContentWidth()))
+
+	if idx == p.selectedIdx && p.focused {
+		return ui.SelectedItemStyle.Width(p.ContentWidth()).Render(content)
+	}
+	return ui.SubtleStyle.Render(content)
+}
+
 // configStatusInfo holds detailed status information for a config
 type configStatusInfo struct {
 	icon       string
@@ -315,6 +380,17 @@ func (p *ConfigsPanel) GetSelectedConfig() *config.ConfigItem {
 	return &p.state.Configs[p.selectedIdx]
 }
 
+// GetSelectedArchived returns the selected config when it is in the
+// archived section, or nil
+func (p *ConfigsPanel) GetSelectedArchived() *config.ConfigItem {
+	archived := p.archived()
+	i := p.selectedIdx - len(p.state.Configs)
+	if i < 0 || i >= len(archived) {
+		return nil
+	}
+	return &archived[i]
+}
+
 // GetSelectedIndex returns the selected index
 func (p *ConfigsPanel) GetSelectedIndex() int {
 	return p.selectedIdx
@@ -328,6 +404,24 @@ func (p *ConfigsPanel) SetSelectedIndex(idx int) {
 	}
 }
 
+// SetSize sets the panel size, scrolling to keep the cursor in view
+func (p *ConfigsPanel) SetSize(width, height int) {
+	p.BasePanel.SetSize(width, height)
+	p.ensureVisible()
+}
+
+// SelectByName moves the cursor to the named config, reporting whether it
+// was found
+func (p *ConfigsPanel) SelectByName(name string) bool {
+	for i, c := range p.state.Configs {
+		if c.Name == name {
+			p.SetSelectedIndex(i)
+			return true
+		}
+	}
+	return false
+}
+
 // ToggleSelection toggles selection state for current config
 func (p *ConfigsPanel) ToggleSelection() {
 	if len(p.state.Configs) == 0 || p.selectedIdx >= len(p.state.Configs) {
@@ -344,14 +438,18 @@ func (p *ConfigsPanel) ToggleSelection() {
 // SelectAll selects all filtered configs
 func (p *ConfigsPanel) SelectAll() {
 	for _, idx := range p.filteredIdxs {
-		p.selected[p.state.Configs[idx].Name] = true
+		if idx >= 0 && idx < len(p.state.Configs) {
+			p.selected[p.state.Configs[idx].Name] = true
+		}
 	}
 }
 
 // DeselectAll deselects all configs
 func (p *ConfigsPanel) DeselectAll() {
 	for _, idx := range p.filteredIdxs {
-		delete(p.selected, p.state.Configs[idx].Name)
+		if idx >= 0 && idx < len(p.state.Configs) {
+			delete(p.selected, p.state.Configs[idx].Name)
+		}
 	}
 }
 
@@ -371,28 +469,24 @@ func (p *ConfigsPanel) GetSelectedNames() []string {
 
 // SetFilter applies a filter to the config list
 func (p *ConfigsPanel) SetFilter(filterText string) {
-	filtered := []int{}
-	if filterText == "" {
-		for i := range p.state.Configs {
-			filtered = append(filtered, i)
-		}
-	} else {
-		for i, cfg := range p.state.Configs {
-			if strings.Contains(strings.ToLower(cfg.Name), strings.ToLower(filterText)) {
-				filtered = append(filtered, i)
-			}
-		}
-	}
-	p.filteredIdxs = filtered
+	p.filteredIdxs = p.rows(filterText)
 	p.listOffset = 0
-	if len(filtered) > 0 {
-		p.selectedIdx = filtered[0]
+	if len(p.filteredIdxs) > 0 && p.filteredIdxs[0] != archivedHeaderRow {
+		p.selectedIdx = p.filteredIdxs[0]
+	} else if len(p.filteredIdxs) > 1 {
+		p.selectedIdx = p.filteredIdxs[1]
 	}
 }
 
-// GetFilteredCount returns the number of filtered configs
+// GetFilteredCount returns the number of filtered active configs
 func (p *ConfigsPanel) GetFilteredCount() int {
-	return len(p.filteredIdxs)
+	count := 0
+	for _, idx := range p.filteredIdxs {
+		if idx >= 0 && idx < len(p.state.Configs) {
+			count++
+		}
+	}
+	return count
 }
 
 // GetTotalCount returns the total number of configs
@@ -404,8 +498,16 @@ func (p *ConfigsPanel) GetTotalCount() int {
 func (p *ConfigsPanel) UpdateState(state State) {
 	p.state = state
 	// Rebuild filter indices
-	p.filteredIdxs = make([]int, len(state.Configs))
-	for i := range state.Configs {
-		p.filteredIdxs[i] = i
+	p.filteredIdxs = p.rows("")
+	if total := len(state.Configs) + len(p.archived()); p.selectedIdx >= total && total > 0 {
+		p.selectedIdx = total - 1
+	}
+}
+
+// HandleEvent implements Subscriber
+func (p *ConfigsPanel) HandleEvent(e Event) tea.Cmd {
+	if e, ok := e.(StatusUpdatedEvent); ok {
+		p.UpdateState(e.State)
 	}
+	return nil
 }
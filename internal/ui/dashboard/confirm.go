@@ -18,6 +18,7 @@ type Confirm struct {
 	id          string
 	title       string
 	description string
+	details     []string // Listed below the description, e.g. affected files
 	affirmative string
 	negative    string
 	width       int
@@ -44,6 +45,12 @@ func (c *Confirm) WithLabels(affirmative, negative string) *Confirm {
 	return c
 }
 
+// WithDetails lists lines below the description
+func (c *Confirm) WithDetails(lines []string) *Confirm {
+	c.details = lines
+	return c
+}
+
 // SetSize updates the dialog dimensions
 func (c *Confirm) SetSize(width, height int) {
 	c.width = width
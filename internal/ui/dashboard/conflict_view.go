@@ -10,6 +10,7 @@ import (
 	"github.com/charmbracelet/bubbles/key"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/nvandessel/go4dot/internal/config"
 	"github.com/nvandessel/go4dot/internal/stow"
 	"github.com/nvandessel/go4dot/internal/ui"
 )
@@ -22,6 +23,25 @@ type ConflictView struct {
 	width       int
 	height      int
 	selectedIdx int // 0=Backup, 1=Delete, 2=Cancel
+
+	// confirmDelete makes Delete ask for confirmation before removing files
+	confirmDelete bool
+	// useTrash moves deleted files to the OS trash instead of unlinking them
+	useTrash bool
+	// strategies holds the on_conflict strategy of configs that declare one
+	strategies map[string]string
+
+	// Adopt review: conflicts are walked one by one with a diff preview
+	dotfilesPath string
+	reviewing    bool
+	reviewIdx    int
+	decisions    []AdoptDecision
+	diff         []string
+}
+
+// conflictDeleteRequestMsg asks the parent model to confirm a Delete choice
+type conflictDeleteRequestMsg struct {
+	count int
 }
 
 // NewConflictView creates a new conflict resolution view
@@ -54,10 +74,56 @@ func (v *ConflictView) SetSize(width, height int) {
 	v.height = height
 }
 
+// SetConfirmDelete controls whether choosing Delete requires a confirmation
+func (v *ConflictView) SetConfirmDelete(confirm bool) {
+	v.confirmDelete = confirm
+}
+
+// SetUseTrash controls whether Delete moves files to the OS trash
+func (v *ConflictView) SetUseTrash(useTrash bool) {
+	v.useTrash = useTrash
+}
+
+// SetStrategies records the configs' on_conflict strategies. When all
+// conflicting configs share backup or overwrite, its button is pre-selected.
+func (v *ConflictView) SetStrategies(configs []config.ConfigItem) {
+	v.strategies = make(map[string]string)
+	for _, c := range configs {
+		if strategy := c.ConflictStrategy(); strategy != config.ConflictAsk {
+			v.strategies[c.Name] = strategy
+		}
+	}
+
+	switch stow.CommonConflictStrategy(v.conflicts, configs) {
+	case config.ConflictBackup:
+		v.selectedIdx = int(ConflictChoiceBackup)
+	case config.ConflictOverwrite:
+		v.selectedIdx = int(ConflictChoiceDelete)
+	}
+}
+
+// configLabel renders a config's heading in the file list, with its
+// on_conflict strategy if it declares one
+func (v *ConflictView) configLabel(name string, nameStyle lipgloss.Style) string {
+	label := nameStyle.Render(name + ":")
+	if strategy, ok := v.strategies[name]; ok {
+		label += ui.SubtleStyle.Render(fmt.Sprintf(" (on_conflict: %s)", strategy))
+	}
+	return label
+}
+
+// SetDotfilesPath sets the repo path used to store adopted variants
+func (v *ConflictView) SetDotfilesPath(path string) {
+	v.dotfilesPath = path
+}
+
 // Update handles messages for the conflict view
 func (v *ConflictView) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
+		if v.reviewing {
+			return v, v.updateReview(msg)
+		}
 		switch {
 		case key.Matches(msg, key.NewBinding(key.WithKeys("left", "h"))):
 			if v.selectedIdx > 0 {
@@ -77,6 +143,12 @@ func (v *ConflictView) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		case key.Matches(msg, key.NewBinding(key.WithKeys("d"))):
 			// Shortcut for delete
 			return v, v.resolve(ConflictChoiceDelete)
+		case key.Matches(msg, key.NewBinding(key.WithKeys("a"))):
+			// Review each file for adoption into the repo
+			v.reviewing = true
+			v.reviewIdx = 0
+			v.decisions = nil
+			return v, v.nextReview()
 		case key.Matches(msg, key.NewBinding(key.WithKeys("c", "esc"))):
 			// Shortcut for cancel
 			return v, v.resolve(ConflictChoiceCancel)
@@ -89,6 +161,17 @@ func (v *ConflictView) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 }
 
 func (v *ConflictView) resolve(choice ConflictResolutionChoice) tea.Cmd {
+	if choice == ConflictChoiceDelete && v.confirmDelete {
+		count := len(v.conflicts)
+		return func() tea.Msg {
+			return conflictDeleteRequestMsg{count: count}
+		}
+	}
+	return v.apply(choice)
+}
+
+// apply executes the chosen resolution without further confirmation
+func (v *ConflictView) apply(choice ConflictResolutionChoice) tea.Cmd {
 	return func() tea.Msg {
 		if choice == ConflictChoiceCancel {
 			return ConflictResolvedMsg{
@@ -97,7 +180,7 @@ func (v *ConflictView) resolve(choice ConflictResolutionChoice) tea.Cmd {
 			}
 		}
 
-		err := ResolveConflictsAction(v.conflicts, choice)
+		err := ResolveConflictsAction(v.conflicts, choice, v.useTrash)
 		if err != nil {
 			return ConflictResolvedMsg{
 				Choice:   choice,
@@ -113,6 +196,148 @@ func (v *ConflictView) resolve(choice ConflictResolutionChoice) tea.Cmd {
 	}
 }
 
+// updateReview handles keys while reviewing conflicts for adoption
+func (v *ConflictView) updateReview(msg tea.KeyMsg) tea.Cmd {
+	current := v.conflicts[v.reviewIdx]
+
+	switch {
+	case key.Matches(msg, key.NewBinding(key.WithKeys("a", "enter"))):
+		if current.IsDir {
+			return nil
+		}
+		v.decisions = append(v.decisions, AdoptDecisionAdopt)
+	case key.Matches(msg, key.NewBinding(key.WithKeys("v"))):
+		if current.IsDir {
+			return nil
+		}
+		v.decisions = append(v.decisions, AdoptDecisionVariant)
+	case key.Matches(msg, key.NewBinding(key.WithKeys("b"))):
+		v.decisions = append(v.decisions, AdoptDecisionBackup)
+	case key.Matches(msg, key.NewBinding(key.WithKeys("c", "esc"))):
+		v.reviewing = false
+		return v.resolve(ConflictChoiceCancel)
+	default:
+		return nil
+	}
+
+	v.reviewIdx++
+	return v.nextReview()
+}
+
+// nextReview advances to the next file that needs a decision. Files identical
+// to the repo copy are adopted without asking. Once every file is decided the
+// decisions are applied.
+func (v *ConflictView) nextReview() tea.Cmd {
+	for v.reviewIdx < len(v.conflicts) && stow.ConflictIdentical(v.conflicts[v.reviewIdx]) {
+		v.decisions = append(v.decisions, AdoptDecisionAdopt)
+		v.reviewIdx++
+	}
+
+	if v.reviewIdx < len(v.conflicts) {
+		diff, err := stow.ConflictDiff(v.conflicts[v.reviewIdx])
+		if err != nil {
+			diff = []string{err.Error()}
+		}
+		v.diff = diff
+		return nil
+	}
+
+	conflicts := v.conflicts
+	decisions := v.decisions
+	dotfilesPath := v.dotfilesPath
+	return func() tea.Msg {
+		variant, err := os.Hostname()
+		if err != nil || variant == "" {
+			variant = "local"
+		}
+		if err := ApplyAdoptDecisions(conflicts, decisions, dotfilesPath, variant); err != nil {
+			return ConflictResolvedMsg{Choice: ConflictChoiceAdopt, Resolved: false, Error: err}
+		}
+		return ConflictResolvedMsg{Choice: ConflictChoiceAdopt, Resolved: true}
+	}
+}
+
+// reviewContent renders the per-file adoption review
+func (v *ConflictView) reviewContent(dialogWidth int) string {
+	innerWidth := dialogWidth - 4
+
+	titleStyle := lipgloss.NewStyle().
+		Foreground(ui.WarningColor).
+		Bold(true).
+		Width(innerWidth).
+		Align(lipgloss.Center)
+
+	pathStyle := lipgloss.NewStyle().
+		Foreground(ui.PrimaryColor).
+		Bold(true)
+
+	lineStyle := lipgloss.NewStyle().MaxWidth(innerWidth)
+	addStyle := lineStyle.Foreground(ui.SecondaryColor)
+	delStyle := lineStyle.Foreground(ui.ErrorColor)
+	ctxStyle := lineStyle.Foreground(ui.SubtleColor)
+
+	hintStyle := lipgloss.NewStyle().
+		Foreground(ui.SubtleColor).
+		Width(innerWidth).
+		Align(lipgloss.Center)
+
+	if v.reviewIdx >= len(v.conflicts) {
+		return titleStyle.Render("Adopting files...")
+	}
+	current := v.conflicts[v.reviewIdx]
+
+	displayPath := current.TargetPath
+	if home, _ := os.UserHomeDir(); home != "" {
+		if relPath, err := filepath.Rel(home, current.TargetPath); err == nil && !strings.HasPrefix(relPath, "..") {
+			displayPath = "~/" + relPath
+		}
+	}
+
+	header := pathStyle.Render(fmt.Sprintf("[%d/%d] %s", v.reviewIdx+1, len(v.conflicts), displayPath)) +
+		ctxStyle.Render(fmt.Sprintf(" (%s)", current.ConfigName))
+
+	// Leave room for title, header, legend and hints
+	maxLines := 12
+	if v.height > 0 && v.height-12 < maxLines {
+		maxLines = v.height - 12
+		if maxLines < 3 {
+			maxLines = 3
+		}
+	}
+
+	diffLines := []string{delStyle.Render("--- repo"), addStyle.Render("+++ home")}
+	for i, line := range v.diff {
+		if i >= maxLines {
+			diffLines = append(diffLines, ctxStyle.Render(fmt.Sprintf("... %d more line(s)", len(v.diff)-maxLines)))
+			break
+		}
+		switch {
+		case strings.HasPrefix(line, "+"):
+			diffLines = append(diffLines, addStyle.Render(line))
+		case strings.HasPrefix(line, "-"):
+			diffLines = append(diffLines, delStyle.Render(line))
+		default:
+			diffLines = append(diffLines, ctxStyle.Render(line))
+		}
+	}
+
+	hints := "a Adopt  v Keep as variant  b Backup  esc Cancel"
+	if current.IsDir {
+		hints = "b Backup  esc Cancel"
+	}
+
+	return lipgloss.JoinVertical(
+		lipgloss.Left,
+		titleStyle.Render("Adopt Existing Files"),
+		"",
+		header,
+		"",
+		strings.Join(diffLines, "\n"),
+		"",
+		hintStyle.Render(hints),
+	)
+}
+
 // View renders the conflict view
 func (v *ConflictView) View() string {
 	dialogWidth := 60
@@ -123,6 +348,18 @@ func (v *ConflictView) View() string {
 		}
 	}
 
+	if v.reviewing {
+		dialog := lipgloss.NewStyle().
+			Border(lipgloss.RoundedBorder()).
+			BorderForeground(ui.WarningColor).
+			Padding(1, 2).
+			Width(dialogWidth).
+			Render(v.reviewContent(dialogWidth))
+		return lipgloss.Place(v.width, v.height, lipgloss.Center, lipgloss.Center, dialog,
+			lipgloss.WithWhitespaceChars(" "),
+			lipgloss.WithWhitespaceForeground(lipgloss.Color("#222222")))
+	}
+
 	// Styles
 	titleStyle := lipgloss.NewStyle().
 		Foreground(ui.WarningColor).
@@ -179,7 +416,7 @@ func (v *ConflictView) View() string {
 
 	for _, configName := range v.configNames {
 		files := v.byConfig[configName]
-		fileLines = append(fileLines, configNameStyle.Render(configName+":"))
+		fileLines = append(fileLines, v.configLabel(configName, configNameStyle))
 		displayedConfigs[configName] = true
 
 		showCount := len(files)
@@ -240,7 +477,7 @@ func (v *ConflictView) View() string {
 	buttonsRow := lipgloss.NewStyle().Width(dialogWidth - 4).Align(lipgloss.Center).Render(buttons)
 
 	// Build hints
-	hints := hintStyle.Render("b Backup  d Delete  c Cancel  Enter Select")
+	hints := hintStyle.Render("b Backup  d Delete  a Adopt  c Cancel  Enter Select")
 
 	// Build dialog content
 	content := lipgloss.JoinVertical(
@@ -17,6 +17,20 @@ const (
 	ConflictChoiceDelete
 	// ConflictChoiceCancel cancels the operation
 	ConflictChoiceCancel
+	// ConflictChoiceAdopt moves conflicting files into the repo after a per-file review
+	ConflictChoiceAdopt
+)
+
+// AdoptDecision is the per-file choice made while reviewing conflicts for adoption
+type AdoptDecision int
+
+const (
+	// AdoptDecisionAdopt replaces the repo copy with the file from home
+	AdoptDecisionAdopt AdoptDecision = iota
+	// AdoptDecisionVariant stores the file from home as a host variant in the repo
+	AdoptDecisionVariant
+	// AdoptDecisionBackup backs up the file from home and keeps the repo copy
+	AdoptDecisionBackup
 )
 
 // ConflictResolvedMsg is sent when the conflict resolution modal closes
@@ -26,8 +40,9 @@ type ConflictResolvedMsg struct {
 	Error    error
 }
 
-// ResolveConflictsAction executes the backup or delete action based on user choice
-func ResolveConflictsAction(conflicts []stow.ConflictFile, choice ConflictResolutionChoice) error {
+// ResolveConflictsAction executes the backup or delete action based on user choice.
+// When useTrash is set, deleted files are moved to the OS trash.
+func ResolveConflictsAction(conflicts []stow.ConflictFile, choice ConflictResolutionChoice, useTrash bool) error {
 	switch choice {
 	case ConflictChoiceCancel:
 		return nil
@@ -37,9 +52,15 @@ func ResolveConflictsAction(conflicts []stow.ConflictFile, choice ConflictResolu
 				return fmt.Errorf("backup %s: %w", conflict.TargetPath, err)
 			}
 		}
+	case ConflictChoiceAdopt:
+		return stow.AdoptConflicts(conflicts, stow.StowOptions{})
 	case ConflictChoiceDelete:
 		for _, conflict := range conflicts {
-			if err := stow.RemoveConflict(conflict); err != nil {
+			remove := stow.RemoveConflict
+			if useTrash {
+				remove = stow.TrashConflict
+			}
+			if err := remove(conflict); err != nil {
 				return fmt.Errorf("remove %s: %w", conflict.TargetPath, err)
 			}
 		}
@@ -47,6 +68,39 @@ func ResolveConflictsAction(conflicts []stow.ConflictFile, choice ConflictResolu
 	return nil
 }
 
+// ApplyAdoptDecisions executes one reviewed decision per conflict.
+// Variants are stored under the given variant name in the dotfiles repo.
+func ApplyAdoptDecisions(conflicts []stow.ConflictFile, decisions []AdoptDecision, dotfilesPath, variant string) error {
+	if len(decisions) != len(conflicts) {
+		return fmt.Errorf("expected %d decisions, got %d", len(conflicts), len(decisions))
+	}
+
+	for i, conflict := range conflicts {
+		var err error
+		switch decisions[i] {
+		case AdoptDecisionAdopt:
+			err = stow.AdoptConflict(conflict)
+		case AdoptDecisionVariant:
+			_, err = stow.AdoptConflictAsVariant(conflict, dotfilesPath, variant)
+		case AdoptDecisionBackup:
+			err = stow.BackupConflict(conflict)
+		}
+		if err != nil {
+			return fmt.Errorf("adopt %s: %w", conflict.TargetPath, err)
+		}
+	}
+	return nil
+}
+
+// checkForConflicts checks the dashboard's configs for conflicts. The demo
+// never looks at the real home directory.
+func (m *Model) checkForConflicts(configNames []string) ([]stow.ConflictFile, error) {
+	if m.state.Demo {
+		return nil, nil
+	}
+	return CheckForConflicts(m.state.Config, m.state.DotfilesPath, configNames)
+}
+
 // CheckForConflicts detects files that would conflict with stow operations.
 // If configNames is empty, checks all configs. Otherwise, filters to specified configs.
 func CheckForConflicts(cfg *config.Config, dotfilesPath string, configNames []string) ([]stow.ConflictFile, error) {
@@ -1,15 +1,24 @@
 package dashboard
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"time"
 
 	"github.com/charmbracelet/bubbles/key"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/nvandessel/go4dot/internal/config"
+	"github.com/nvandessel/go4dot/internal/deps"
+	"github.com/nvandessel/go4dot/internal/doctor"
 	"github.com/nvandessel/go4dot/internal/platform"
+	"github.com/nvandessel/go4dot/internal/prefs"
+	"github.com/nvandessel/go4dot/internal/state"
 	"github.com/nvandessel/go4dot/internal/stow"
+	"github.com/nvandessel/go4dot/internal/triage"
 	"github.com/nvandessel/go4dot/internal/ui"
+	"github.com/nvandessel/go4dot/internal/version"
 )
 
 type view int
@@ -25,6 +34,13 @@ const (
 	viewExternal
 	viewMachine
 	viewConflict
+	viewSearch
+	viewInventory
+	viewTriage
+	viewPassword
+	viewPlan
+	viewMetadata
+	viewProfile
 )
 
 // State holds all the shared data for the dashboard.
@@ -41,12 +57,24 @@ type State struct {
 	FilterText     string
 	SelectedConfig string
 	HasConfig      bool
+	ShowTutorial   bool                  // Open the dashboard tour on launch
+	ReadOnly       bool                  // Refuse every action that changes the machine (see --read-only)
+	Focus          string                // Panel focused on launch, by name (see ParsePanel); empty focuses Configs
+	CursorConfig   string                // Config the Configs panel's cursor starts on
+	Preferences    *prefs.Preferences    // User-level preferences (nil = defaults)
+	ConfirmTracker *prefs.ConfirmTracker // Shared across dashboard runs so "session" mode asks once
 
 	// Operation mode - start with an operation instead of dashboard view
 	StartOperation OperationType
 	OperationArg   string   // For single config operations
 	OperationArgs  []string // For bulk operations
 	AutoStart      bool     // Automatically start the operation
+
+	// Demo runs the dashboard against fabricated data (see DemoState):
+	// operations are simulated and nothing on disk is changed.
+	Demo           bool
+	HealthResult   *doctor.CheckResult   // Precomputed health checks, skips running doctor
+	ExternalStatus []deps.ExternalStatus // Precomputed external status, skips scanning
 }
 
 // Model is the main container for the dashboard.
@@ -60,15 +88,30 @@ type Model struct {
 	filterText      string
 	selectedConfigs map[string]bool
 	showHelp        bool
+	tutorial        *Tutorial // Dashboard tour, nil when closed (see tutorial.go)
 	currentView     view
-	viewStack       []view       // Stack for navigation history
-	operationActive bool         // true when an operation is running in the output pane
-	program         *tea.Program // reference for inline operations
+	viewStack       []view // Stack for navigation history
+	operationActive bool   // true when an operation is running in the output pane
+
+	// Inline operations run on their own goroutine and report back through
+	// operationMsgs; messages whose ID is not operationID are stale
+	operationID     int
+	operationMsgs   chan tea.Msg
+	cancelOperation context.CancelFunc
+
+	// Sync, link and install only report what they would change (see dryrun.go)
+	dryRun bool
+
+	// Macro recording (see macro.go)
+	recordingMacro bool
+	macroKeys      []string
+	replayingMacro bool
 
 	// Multi-panel layout
 	focusManager *FocusManager
 	layout       *Layout
 	panels       map[PanelID]Panel
+	bus          EventBus // Delivers model events to the panels
 
 	// Panel references for easy access
 	summaryPanel   *SummaryPanel
@@ -90,10 +133,18 @@ type Model struct {
 
 	// Modal views
 	confirm      *Confirm
+	password     *PasswordPrompt // Open while an operation waits for a password
 	configList   *ConfigListView
 	externalView *ExternalView
 	machineView  *MachineView
 	conflictView *ConflictView
+	searchView   *SearchView
+	inventory    *InventoryView
+	triage       *TriageView
+	planView     *PlanView
+	metadataForm *FormView
+	metadata     *metadataEdit // Values metadataForm edits
+	profileMenu  *Menu
 
 	// Post-onboarding state
 	pendingNewConfigPath string
@@ -119,6 +170,9 @@ func New(s State) Model {
 	if s.SelectedConfig != "" {
 		m.selectedConfigs[s.SelectedConfig] = true
 	}
+	if m.state.ConfirmTracker == nil {
+		m.state.ConfirmTracker = prefs.NewConfirmTracker(s.Preferences)
+	}
 
 	// Determine initial view
 	if s.AutoStart {
@@ -128,13 +182,19 @@ func New(s State) Model {
 		m.currentView = viewNoConfig
 	} else {
 		m.currentView = viewDashboard
+		if s.ShowTutorial {
+			m.tutorial = NewTutorial()
+		}
 	}
 
 	// Initialize multi-panel components
 	m.summaryPanel = NewSummaryPanel(s)
 	m.healthPanel = NewHealthPanel(s.Config, s.DotfilesPath)
+	m.healthPanel.preset = s.HealthResult
 	m.overridesPanel = NewOverridesPanel(s.Config)
 	m.externalPanel = NewExternalPanel(s.Config, s.DotfilesPath, s.Platform)
+	m.externalPanel.preset = s.ExternalStatus
+	m.externalPanel.github = s.Preferences.GitHubMetadata()
 	m.configsPanel = NewConfigsPanel(s, m.selectedConfigs)
 	m.detailsPanel = NewDetailsPanel(s)
 	m.outputPanel = NewOutputPanel()
@@ -151,6 +211,13 @@ func New(s State) Model {
 	m.panels[PanelDetails] = m.detailsPanel
 	m.panels[PanelOutput] = m.outputPanel
 
+	// Subscribe panels to model events, in display order
+	for _, id := range []PanelID{PanelSummary, PanelHealth, PanelOverrides, PanelExternal, PanelConfigs, PanelDetails, PanelOutput} {
+		if s, ok := m.panels[id].(Subscriber); ok {
+			m.bus.Subscribe(s)
+		}
+	}
+
 	// Set initial focus
 	m.configsPanel.SetFocused(true)
 
@@ -159,10 +226,25 @@ func New(s State) Model {
 	m.footer = NewFooter()
 	m.footer.SetPlatform(s.Platform)
 	m.footer.SetUpdateMsg(s.UpdateMsg)
+	m.footer.SetDemo(s.Demo)
+	m.footer.SetReadOnly(s.ReadOnly)
+	if s.Config != nil {
+		m.footer.SetProfile(s.Config.ActiveProfile)
+	}
 	m.help = NewHelp()
+	m.help.readOnly = s.ReadOnly
 	m.menu = &Menu{}
 	*m.menu = NewMenu()
 	m.noconfig = NewNoConfig()
+	m.noconfig.readOnly = s.ReadOnly
+
+	// Open at the requested item; the tour starts from the default focus
+	if s.CursorConfig != "" {
+		m.configsPanel.SelectByName(s.CursorConfig)
+	}
+	if id, err := ParsePanel(s.Focus); err == nil && s.Focus != "" && m.tutorial == nil {
+		m.changeFocus(id)
+	}
 
 	return m
 }
@@ -177,11 +259,17 @@ func (m Model) Init() tea.Cmd {
 	if m.currentView == viewDashboard {
 		cmds = append(cmds, m.healthPanel.Init())
 		cmds = append(cmds, m.externalPanel.Init())
+		cmds = append(cmds, m.detailsPanel.LoadStats())
 
 		// Check for unconfigured machine configs and prompt the user
-		if m.state.Config != nil && len(m.state.Config.MachineConfig) > 0 {
+		if m.state.Config != nil && len(m.state.Config.MachineConfig) > 0 && !m.state.Demo && !m.state.ReadOnly {
 			cmds = append(cmds, checkMachineConfigsCmd(m.state.Config))
 		}
+
+		// Show what a pull changed in .go4dot.yaml since the last sync
+		if m.state.DotfilesPath != "" && !m.state.Demo {
+			cmds = append(cmds, configChangesCmd(m.state.DotfilesPath))
+		}
 	}
 
 	return tea.Batch(cmds...)
@@ -211,17 +299,36 @@ func (m *Model) clearViewStack() {
 }
 
 func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case OperationProgressMsg, OperationStepCompleteMsg, OperationLogMsg, OperationBatchMsg, OperationDoneMsg, passwordRequestMsg:
+		return m, m.handleOperationMsg(msg)
+	case startOperationMsg:
+		return m, m.StartInlineOperation(msg.opType, msg.configName, msg.configNames, msg.run)
+	case tea.KeyMsg:
+		if m.recordingMacro && !m.replayingMacro && m.recordMacroKey(msg) {
+			return m, nil
+		}
+	}
+
 	if m.showHelp {
 		switch msg := msg.(type) {
 		case tea.KeyMsg:
 			switch {
 			case key.Matches(msg, keys.Help), key.Matches(msg, keys.Quit):
 				m.showHelp = false
+			case key.Matches(msg, keys.Tour):
+				m.startTutorial()
 			}
 		}
 		return m, nil
 	}
 
+	if m.tutorial != nil && m.currentView == viewDashboard {
+		if msg, ok := msg.(tea.KeyMsg); ok {
+			return m.updateTutorial(msg)
+		}
+	}
+
 	switch m.currentView {
 	case viewMenu:
 		return m.updateMenu(msg)
@@ -241,6 +348,20 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m.updateMachine(msg)
 	case viewConflict:
 		return m.updateConflict(msg)
+	case viewSearch:
+		return m.updateSearch(msg)
+	case viewInventory:
+		return m.updateInventory(msg)
+	case viewTriage:
+		return m.updateTriage(msg)
+	case viewPassword:
+		return m.updatePassword(msg)
+	case viewPlan:
+		return m.updatePlan(msg)
+	case viewMetadata:
+		return m.updateMetadata(msg)
+	case viewProfile:
+		return m.updateProfile(msg)
 	default:
 		return m.updateDashboard(msg)
 	}
@@ -278,70 +399,205 @@ func stepStatusToLogLevel(status StepStatus) string {
 	}
 }
 
-func (m *Model) handleOperationMsg(msg tea.Msg) (handled bool, cmd tea.Cmd) {
+// handleOperationMsg applies a message from the running operation. It is
+// called for every view, so an operation keeps reporting while a modal is
+// open.
+func (m *Model) handleOperationMsg(msg tea.Msg) tea.Cmd {
+	if operationMsgID(msg) != m.operationID {
+		// Left over from a canceled operation
+		return nil
+	}
+
+	var cmd tea.Cmd
 	switch msg := msg.(type) {
-	case OperationProgressMsg:
-		m.operationActive = true
-		m.operations, cmd = m.operations.Update(msg)
-		return true, cmd
+	case OperationProgressMsg, OperationStepCompleteMsg, OperationLogMsg:
+		return m.applyOperationMsgs([]tea.Msg{msg})
 
-	case OperationStepCompleteMsg:
-		m.operations, cmd = m.operations.Update(msg)
-		if msg.Detail != "" {
-			m.outputPanel.AddLog(stepStatusToLogLevel(msg.Status), msg.Detail)
-		}
-		return true, cmd
+	case OperationBatchMsg:
+		return m.applyOperationMsgs(msg.Msgs)
 
-	case OperationLogMsg:
-		m.operations, cmd = m.operations.Update(msg)
-		m.outputPanel.AddLog(msg.Level, msg.Message)
-		return true, cmd
+	case passwordRequestMsg:
+		m.askPassword(msg)
+		return m.nextOperationMsg()
 
 	case OperationDoneMsg:
 		m.operationActive = false
+		m.stopOperation()
 		opType := m.operations.OperationType()
 		m.operations, cmd = m.operations.Update(msg)
+		timings := m.operations.Timings()
+		eventCmd := m.bus.Publish(OperationFinishedEvent{Type: opType, Summary: msg.Summary, Timings: timings, Err: msg.Error})
 		if msg.Error != nil {
-			m.outputPanel.AddLog("error", fmt.Sprintf("Operation failed: %v", msg.Error))
-		} else if msg.Summary != "" {
-			m.outputPanel.AddLog("success", msg.Summary)
+			m.openTriage(opType, msg.Error)
 		}
-		// Refresh appropriate panel after operation completes
-		var refreshCmd tea.Cmd
-		if opType == OpExternalSingle && msg.Error == nil {
-			refreshCmd = m.externalPanel.Refresh()
+		return tea.Batch(cmd, eventCmd, m.recordOperation(opType, msg, timings))
+	}
+	return nil
+}
+
+// recordOperation returns a command adding the finished operation and its
+// step timings to the operation history. Canceled operations, dry runs and
+// demo mode are not recorded.
+func (m *Model) recordOperation(opType OperationType, msg OperationDoneMsg, timings []state.StepTiming) tea.Cmd {
+	if m.state.Demo || opType == OpDryRun || errors.Is(msg.Error, context.Canceled) {
+		return nil
+	}
+	rec := state.OperationRecord{
+		Operation: opType.HistoryName(),
+		StartedAt: m.operations.StartedAt(),
+		Duration:  time.Since(m.operations.StartedAt()),
+		Success:   msg.Success && msg.Error == nil,
+		Steps:     timings,
+	}
+	return func() tea.Msg {
+		// History is informational; failing to write it must not fail the operation
+		_ = state.RecordOperation(rec)
+		return nil
+	}
+}
+
+// applyOperationMsgs applies an operation's progress, step and log messages
+// in order, updating the output panel once for all of them
+func (m *Model) applyOperationMsgs(msgs []tea.Msg) tea.Cmd {
+	var cmds []tea.Cmd
+	var logs []LogEntry
+	for _, msg := range msgs {
+		switch msg := msg.(type) {
+		case OperationProgressMsg:
+			m.operationActive = true
+		case OperationStepCompleteMsg:
+			if msg.Detail != "" {
+				logs = append(logs, LogEntry{Level: stepStatusToLogLevel(msg.Status), Message: msg.Detail})
+			}
+		case OperationLogMsg:
+			logs = append(logs, LogEntry{Level: msg.Level, Message: msg.Message})
 		}
-		return true, tea.Batch(cmd, refreshCmd)
+		var cmd tea.Cmd
+		m.operations, cmd = m.operations.Update(msg)
+		cmds = append(cmds, cmd)
+	}
+	m.outputPanel.AddLogs(logs...)
+	return tea.Batch(append(cmds, m.nextOperationMsg())...)
+}
+
+// openTriage offers the triage view for a failed operation. Canceled
+// operations and demo mode are left alone, as is any modal the user opened
+// meanwhile.
+func (m *Model) openTriage(opType OperationType, err error) {
+	if errors.Is(err, context.Canceled) || m.state.Demo || m.currentView != viewDashboard {
+		return
+	}
+
+	var log []string
+	for _, entry := range m.outputPanel.GetLogs() {
+		log = append(log, fmt.Sprintf("[%s] %s", entry.Level, entry.Message))
 	}
-	return false, nil
+	m.triage = NewTriageView(triage.Report{
+		Operation: opType.String(),
+		Step:      m.operations.FailedStep(),
+		Error:     err.Error(),
+		Log:       log,
+		Version:   version.GetToolVersion(),
+		Platform:  m.state.Platform,
+	}, triage.NewRedactor())
+	contentWidth, contentHeight := overlayContentSize(m.width, m.height, ui.DefaultOverlayStyle())
+	m.triage.SetSize(contentWidth, contentHeight)
+	m.pushView(viewTriage)
 }
 
+// operationMsgID returns the ID of the operation that sent msg
+func operationMsgID(msg tea.Msg) int {
+	switch msg := msg.(type) {
+	case OperationProgressMsg:
+		return msg.ID
+	case OperationStepCompleteMsg:
+		return msg.ID
+	case OperationLogMsg:
+		return msg.ID
+	case OperationBatchMsg:
+		return msg.ID
+	case OperationDoneMsg:
+		return msg.ID
+	case passwordRequestMsg:
+		return msg.ID
+	}
+	return 0
+}
+
+// nextOperationMsg waits for the running inline operation's next message.
+// Operations started by RunWithOperation send to the program directly.
+func (m *Model) nextOperationMsg() tea.Cmd {
+	if m.operationMsgs == nil {
+		return nil
+	}
+	return waitForOperation(m.operationMsgs)
+}
+
+// startOperationMsg starts an inline operation. Code outside the update
+// loop sends it rather than calling StartInlineOperation.
+type startOperationMsg struct {
+	opType      OperationType
+	configName  string
+	configNames []string
+	run         func(runner *OperationRunner) error
+}
+
+// StartInlineOperation runs operationFunc on its own goroutine, showing its
+// progress in the output panel. Only one operation runs at a time.
+// operationFunc must not touch the model: capture copies of the state it
+// needs and report through the runner.
 func (m *Model) StartInlineOperation(opType OperationType, configName string, configNames []string, operationFunc func(runner *OperationRunner) error) tea.Cmd {
-	if m.program == nil || m.operationActive {
+	if m.operationActive {
+		return nil
+	}
+	if opType != OpDoctor && opType != OpDryRun && m.refuseReadOnly(opType.HistoryName()) {
 		return nil
 	}
 
+	if m.state.Demo {
+		operationFunc = demoOperation(len(getStepsForOperation(opType)))
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	msgs := make(chan tea.Msg)
+
+	m.operationID++
 	m.operationActive = true
+	m.operationMsgs = msgs
+	m.cancelOperation = cancel
 	m.operations = NewOperations(opType, configName, configNames)
-	m.outputPanel.Clear()
-	m.outputPanel.SetTitle(getOperationTitle(opType))
+	eventCmd := m.bus.Publish(OperationStartedEvent{Type: opType})
 
+	runner := newChannelRunner(ctx, m.operationID, msgs)
 	go func() {
-		runner := NewOperationRunner(m.program)
-		defer func() {
-			if r := recover(); r != nil {
-				runner.Done(false, "", fmt.Errorf("operation panicked: %v", r))
-			}
-		}()
-		err := operationFunc(runner)
-		if err != nil {
-			runner.Done(false, "", err)
-		} else {
-			runner.Done(true, "", nil)
-		}
+		defer close(msgs)
+		runner.run(operationFunc)
 	}()
 
-	return m.operations.Init()
+	return tea.Batch(m.operations.Init(), eventCmd, waitForOperation(msgs))
+}
+
+// CancelOperation cancels the running inline operation. Anything it reports
+// afterwards is ignored; the operation stops at its next cancellation check.
+func (m *Model) CancelOperation() {
+	if !m.operationActive {
+		return
+	}
+	m.stopOperation()
+	m.operationID++
+	m.operationActive = false
+	m.operations, _ = m.operations.Update(OperationDoneMsg{Error: context.Canceled})
+	m.outputPanel.AddLog("warning", "Operation canceled")
+}
+
+// stopOperation releases the running inline operation's context and stops
+// listening for its messages
+func (m *Model) stopOperation() {
+	if m.cancelOperation != nil {
+		m.cancelOperation()
+		m.cancelOperation = nil
+	}
+	m.operationMsgs = nil
 }
 
 func getOperationTitle(opType OperationType) string {
@@ -358,8 +614,20 @@ func getOperationTitle(opType OperationType) string {
 		return "Updating"
 	case OpDoctor:
 		return "Health Check"
-	case OpExternalSingle:
+	case OpExternal, OpExternalSingle:
 		return "External"
+	case OpLink:
+		return "Linking All"
+	case OpLinkSingle:
+		return "Linking"
+	case OpBulkLink:
+		return "Linking Selected"
+	case OpUndoConflicts:
+		return "Undo Conflicts"
+	case OpUpgradeDeps:
+		return "Upgrade Dependencies"
+	case OpDryRun:
+		return "Dry Run"
 	default:
 		return "Operation"
 	}
@@ -377,7 +645,7 @@ func (m Model) renderFilterBar() string {
 	if m.filterMode {
 		cursor = lipgloss.NewStyle().
 			Foreground(ui.PrimaryColor).
-			Blink(true).
+			Blink(!ui.IsReducedMotion()).
 			Render("▌")
 	}
 
@@ -410,12 +678,22 @@ func (m Model) View() string {
 		return ui.RenderOverlay(dashboardBg, overlayHelpContent(m.help), m.width, m.height, ui.HelpOverlayStyle())
 	}
 
+	// The tour waits behind any modal that opens while it is shown
+	if m.tutorial != nil && m.currentView == viewDashboard {
+		return m.viewTutorial(dashboardBg)
+	}
+
 	// Handle overlay-based modal views
 	switch m.currentView {
 	case viewOperation:
 		return ui.RenderOverlay(dashboardBg, overlayOperationContent(&m.operations), m.width, m.height, ui.DefaultOverlayStyle())
 	case viewMenu:
 		return ui.RenderOverlay(dashboardBg, overlayMenuContent(m.menu), m.width, m.height, ui.DefaultOverlayStyle())
+	case viewProfile:
+		if m.profileMenu != nil {
+			return ui.RenderOverlay(dashboardBg, overlayMenuContent(m.profileMenu), m.width, m.height, ui.DefaultOverlayStyle())
+		}
+		return ""
 	case viewOnboarding:
 		if m.onboarding != nil {
 			return ui.RenderOverlay(dashboardBg, overlayOnboardingContent(m.onboarding), m.width, m.height, ui.DefaultOverlayStyle())
@@ -431,6 +709,31 @@ func (m Model) View() string {
 			return ui.RenderOverlay(dashboardBg, overlayConfigListContent(m.configList), m.width, m.height, ui.DefaultOverlayStyle())
 		}
 		return ""
+	case viewInventory:
+		if m.inventory != nil {
+			return ui.RenderOverlay(dashboardBg, overlayInventoryContent(m.inventory), m.width, m.height, ui.DefaultOverlayStyle())
+		}
+		return ""
+	case viewTriage:
+		if m.triage != nil {
+			return ui.RenderOverlay(dashboardBg, overlayTriageContent(m.triage), m.width, m.height, ui.DefaultOverlayStyle())
+		}
+		return ""
+	case viewPlan:
+		if m.planView != nil {
+			return ui.RenderOverlay(dashboardBg, overlayPlanContent(m.planView), m.width, m.height, ui.DefaultOverlayStyle())
+		}
+		return ""
+	case viewMetadata:
+		if m.metadataForm != nil {
+			return ui.RenderOverlay(dashboardBg, overlayFormContent(m.metadataForm), m.width, m.height, ui.DefaultOverlayStyle())
+		}
+		return ""
+	case viewPassword:
+		if m.password != nil {
+			return ui.RenderOverlay(dashboardBg, overlayPasswordContent(m.password), m.width, m.height, ui.ConfirmOverlayStyle())
+		}
+		return ""
 	case viewExternal:
 		if m.externalView != nil {
 			return ui.RenderOverlay(dashboardBg, overlayExternalContent(m.externalView), m.width, m.height, ui.DefaultOverlayStyle())
@@ -446,6 +749,11 @@ func (m Model) View() string {
 			return ui.RenderOverlay(dashboardBg, overlayConflictContent(m.conflictView), m.width, m.height, ui.ConflictOverlayStyle())
 		}
 		return ""
+	case viewSearch:
+		if m.searchView != nil {
+			return ui.RenderOverlay(dashboardBg, overlaySearchContent(m.searchView), m.width, m.height, ui.DefaultOverlayStyle())
+		}
+		return ""
 	default:
 		// viewDashboard - return the dashboard directly
 		return dashboardBg
@@ -585,6 +893,8 @@ const (
 	ActionInit
 	ActionQuit
 	ActionBulkSync
+	ActionSearch
+	ActionInventory
 )
 
 // MachineStatus represents the status of a machine config for the dashboard
@@ -597,10 +907,11 @@ type MachineStatus struct {
 // Run starts the dashboard and returns the selected action
 func Run(s State) (*Result, error) {
 	m := New(s)
-	p := tea.NewProgram(&m, tea.WithAltScreen(), tea.WithMouseCellMotion())
-	m.program = p
+	p := tea.NewProgram(&m, ui.ProgramOptions(true, true)...)
 
 	finalModel, err := p.Run()
+	// An operation still running when the dashboard closes is canceled
+	m.stopOperation()
 	if err != nil {
 		return nil, err
 	}
@@ -616,22 +927,12 @@ func RunWithOperation(s State, opType OperationType, configName string, configNa
 	s.OperationArgs = configNames
 
 	m := New(s)
-	p := tea.NewProgram(&m, tea.WithAltScreen(), tea.WithMouseCellMotion())
+	p := tea.NewProgram(&m, ui.ProgramOptions(true, true)...)
 
-	go func() {
-		runner := NewOperationRunner(p)
-		defer func() {
-			if r := recover(); r != nil {
-				runner.Done(false, "", fmt.Errorf("operation panicked: %v", r))
-			}
-		}()
-		err := operationFunc(runner)
-		if err != nil {
-			runner.Done(false, "", err)
-		} else {
-			runner.Done(true, "", nil)
-		}
-	}()
+	// Leaving the dashboard cancels the operation
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go NewOperationRunner(ctx, p).run(operationFunc)
 
 	finalModel, err := p.Run()
 	if err != nil {
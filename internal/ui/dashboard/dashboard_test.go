@@ -1,6 +1,8 @@
 package dashboard
 
 import (
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 
@@ -8,6 +10,7 @@ import (
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/nvandessel/go4dot/internal/config"
 	"github.com/nvandessel/go4dot/internal/platform"
+	"github.com/nvandessel/go4dot/internal/prefs"
 	"github.com/nvandessel/go4dot/internal/stow"
 )
 
@@ -44,6 +47,39 @@ func TestNew_WithConfigs(t *testing.T) {
 	}
 }
 
+func TestNew_LaunchTarget(t *testing.T) {
+	m := New(State{
+		Platform:     &platform.Platform{OS: "linux"},
+		Configs:      []config.ConfigItem{{Name: "git"}, {Name: "nvim"}, {Name: "zsh"}},
+		HasConfig:    true,
+		Focus:        "Health",
+		CursorConfig: "nvim",
+	})
+
+	if m.focusManager.CurrentFocus() != PanelHealth {
+		t.Errorf("expected focus on PanelHealth, got %v", m.focusManager.CurrentFocus())
+	}
+	if selected := m.configsPanel.GetSelectedConfig(); selected == nil || selected.Name != "nvim" {
+		t.Errorf("expected nvim to be selected, got %v", selected)
+	}
+}
+
+func TestParsePanel(t *testing.T) {
+	for _, name := range []string{"health", "External", "CONFIGS"} {
+		id, err := ParsePanel(name)
+		if err != nil {
+			t.Errorf("ParsePanel(%q) error = %v", name, err)
+			continue
+		}
+		if !strings.EqualFold(id.String(), name) {
+			t.Errorf("ParsePanel(%q) = %v", name, id)
+		}
+	}
+	if _, err := ParsePanel("sidebar"); err == nil {
+		t.Error("ParsePanel(\"sidebar\") should fail")
+	}
+}
+
 func TestNew_NoConfig(t *testing.T) {
 	s := State{
 		Platform:  &platform.Platform{OS: "linux"},
@@ -163,11 +199,6 @@ func TestModel_InlineOperation_WithConfig(t *testing.T) {
 	m.width = 100
 	m.height = 40
 
-	// Simulate what Run() does - set the program
-	p := tea.NewProgram(&m, tea.WithAltScreen())
-	m.program = p
-
-	t.Logf("Before 's' - program is nil: %v", m.program == nil)
 	t.Logf("Before 's' - operationActive: %v", m.operationActive)
 
 	msg := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'s'}}
@@ -176,9 +207,9 @@ func TestModel_InlineOperation_WithConfig(t *testing.T) {
 	model := updatedModel.(*Model)
 	t.Logf("After 's' - operationActive: %v", model.operationActive)
 	t.Logf("After 's' - cmd is nil: %v", cmd == nil)
-	t.Logf("After 's' - program is nil: %v", model.program == nil)
+	defer model.stopOperation()
 
-	// With Config and program set, operationActive should be true
+	// With Config set, operationActive should be true
 	if !model.operationActive {
 		t.Error("expected operationActive to be true with Config set")
 	}
@@ -414,6 +445,40 @@ func TestModel_Update_SelectAll(t *testing.T) {
 	}
 }
 
+func TestConfigsPanel_ArchivedSection(t *testing.T) {
+	cfg := &config.Config{Archived: []config.ConfigItem{{Name: "emacs"}}}
+	s := State{
+		Configs: []config.ConfigItem{{Name: "vim"}, {Name: "zsh"}},
+		Config:  cfg,
+	}
+	p := NewConfigsPanel(s, nil)
+	p.SetSize(40, 10)
+	p.SetFocused(true)
+
+	view := p.View()
+	if !strings.Contains(view, "archived") || !strings.Contains(view, "emacs") {
+		t.Errorf("view missing the archived section:\n%s", view)
+	}
+
+	down := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'j'}}
+	p.Update(down)
+	p.Update(down)
+	if item := p.GetSelectedArchived(); item == nil || item.Name != "emacs" {
+		t.Fatalf("GetSelectedArchived() = %v, want emacs after stepping over the header", item)
+	}
+	if p.GetSelectedConfig() != nil {
+		t.Error("an archived row should not count as a selected active config")
+	}
+
+	p.SelectAll()
+	if p.selected["emacs"] || len(p.selected) != 2 {
+		t.Errorf("SelectAll() selected %v, want only active configs", p.selected)
+	}
+	if got := p.GetFilteredCount(); got != 2 {
+		t.Errorf("GetFilteredCount() = %d, want 2", got)
+	}
+}
+
 func TestModel_Update_BulkSync(t *testing.T) {
 	// Without Config, bulk sync does nothing (no inline operation possible)
 	s := State{
@@ -644,6 +709,10 @@ func TestKeys_Bindings(t *testing.T) {
 		{"Select", keys.Select, []string{" "}},
 		{"All", keys.All, []string{"A"}},
 		{"Bulk", keys.Bulk, []string{"S"}},
+		{"Record", keys.Record, []string{"ctrl+r"}},
+		{"Macro", keys.Macro, []string{"7", "8", "9"}},
+		{"Link", keys.Link, []string{"l"}},
+		{"BulkLink", keys.BulkLink, []string{"L"}},
 	}
 
 	for _, tt := range tests {
@@ -679,19 +748,22 @@ func TestModel_SyncOperations_StartInlineOperation(t *testing.T) {
 	}
 
 	testCases := []struct {
-		name  string
-		key   tea.KeyMsg
-		setup func(*Model)
+		name   string
+		key    tea.KeyMsg
+		setup  func(*Model)
+		wantOp OperationType
 	}{
 		{
 			name:  "Sync all starts operation",
-			key:   tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'s'}},
-			setup: func(m *Model) {},
+			key:    tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'s'}},
+			setup:  func(m *Model) {},
+			wantOp: OpSync,
 		},
 		{
 			name:  "Sync single starts operation",
-			key:   tea.KeyMsg{Type: tea.KeyEnter},
-			setup: func(m *Model) {},
+			key:    tea.KeyMsg{Type: tea.KeyEnter},
+			setup:  func(m *Model) {},
+			wantOp: OpSyncSingle,
 		},
 		{
 			name: "Bulk sync starts operation",
@@ -700,6 +772,27 @@ func TestModel_SyncOperations_StartInlineOperation(t *testing.T) {
 				m.selectedConfigs["vim"] = true
 				m.selectedConfigs["zsh"] = true
 			},
+			wantOp: OpBulkSync,
+		},
+		{
+			name:   "Link all starts operation",
+			key:    tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'l'}},
+			setup:  func(m *Model) {},
+			wantOp: OpLink,
+		},
+		{
+			name:   "Link without selection links highlighted config",
+			key:    tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'L'}},
+			setup:  func(m *Model) {},
+			wantOp: OpLinkSingle,
+		},
+		{
+			name: "Link selected starts operation",
+			key:  tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'L'}},
+			setup: func(m *Model) {
+				m.selectedConfigs["vim"] = true
+			},
+			wantOp: OpBulkLink,
 		},
 	}
 
@@ -708,13 +801,11 @@ func TestModel_SyncOperations_StartInlineOperation(t *testing.T) {
 			m := New(s)
 			m.width = 100
 			m.height = 40
-			p := tea.NewProgram(&m, tea.WithAltScreen())
-			m.program = p
-
 			tc.setup(&m)
 
 			updatedModel, cmd := m.Update(tc.key)
 			model := updatedModel.(*Model)
+			defer model.stopOperation()
 
 			if !model.operationActive {
 				t.Error("expected operationActive to be true")
@@ -722,6 +813,9 @@ func TestModel_SyncOperations_StartInlineOperation(t *testing.T) {
 			if cmd == nil {
 				t.Error("expected a command to be returned")
 			}
+			if got := model.operations.OperationType(); got != tc.wantOp {
+				t.Errorf("operation = %v, want %v", got, tc.wantOp)
+			}
 		})
 	}
 }
@@ -1304,6 +1398,79 @@ func TestConflictView_ShortcutKeys(t *testing.T) {
 	}
 }
 
+// TestConflictView_AdoptReview tests the per-file adoption review
+func TestConflictView_AdoptReview(t *testing.T) {
+	tmpDir := t.TempDir()
+	dotfilesDir := filepath.Join(tmpDir, "dotfiles")
+	homeDir := filepath.Join(tmpDir, "home")
+
+	write := func(path, content string) {
+		t.Helper()
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var conflicts []stow.ConflictFile
+	for _, name := range []string{".zshrc", ".zprofile", ".zshenv"} {
+		src := filepath.Join(dotfilesDir, "zsh", name)
+		dst := filepath.Join(homeDir, name)
+		write(src, "repo "+name+"\n")
+		write(dst, "home "+name+"\n")
+		conflicts = append(conflicts, stow.ConflictFile{ConfigName: "zsh", SourcePath: src, TargetPath: dst})
+	}
+	// Identical files are adopted without a prompt
+	write(conflicts[2].TargetPath, "repo .zshenv\n")
+
+	cv := NewConflictView(conflicts)
+	cv.SetSize(80, 40)
+	cv.SetDotfilesPath(dotfilesDir)
+
+	// Start the review, adopt the first file, keep the second as a variant
+	_, cmd := cv.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'a'}})
+	if cmd != nil {
+		t.Fatal("review should wait for a decision")
+	}
+	if view := overlayConflictContent(cv); !strings.Contains(view, "Adopt Existing Files") || !strings.Contains(view, "[1/3]") {
+		t.Fatalf("expected review of the first file, got:\n%s", view)
+	}
+	if _, cmd = cv.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'a'}}); cmd != nil {
+		t.Fatal("review should wait for the second decision")
+	}
+	_, cmd = cv.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'v'}})
+	if cmd == nil {
+		t.Fatal("expected command after the last decision")
+	}
+
+	resolvedMsg, ok := cmd().(ConflictResolvedMsg)
+	if !ok {
+		t.Fatal("expected ConflictResolvedMsg")
+	}
+	if !resolvedMsg.Resolved || resolvedMsg.Choice != ConflictChoiceAdopt {
+		t.Fatalf("unexpected result: %+v", resolvedMsg)
+	}
+
+	if data, _ := os.ReadFile(conflicts[0].SourcePath); string(data) != "home .zshrc\n" {
+		t.Errorf("adopted repo copy = %q", data)
+	}
+	if data, _ := os.ReadFile(conflicts[1].SourcePath); string(data) != "repo .zprofile\n" {
+		t.Errorf("variant should keep repo copy, got %q", data)
+	}
+	for _, c := range conflicts {
+		if _, err := os.Lstat(c.TargetPath); !os.IsNotExist(err) {
+			t.Errorf("%s should be removed from home", c.TargetPath)
+		}
+	}
+
+	variants, _ := filepath.Glob(filepath.Join(dotfilesDir, stow.VariantsDir, "*", "zsh", ".zprofile"))
+	if len(variants) != 1 {
+		t.Errorf("expected one stored variant, got %v", variants)
+	}
+}
+
 // TestConflictView_EscapeCancels tests that Escape key cancels
 func TestConflictView_EscapeCancels(t *testing.T) {
 	conflicts := []stow.ConflictFile{
@@ -1387,6 +1554,81 @@ func TestConflictResolved_Cancel_ClearsPendingState(t *testing.T) {
 	}
 }
 
+// TestConflictDelete_RequiresConfirmationByPolicy tests that Delete goes
+// through the Confirm modal unless the destructive policy is "never"
+func TestConflictDelete_RequiresConfirmationByPolicy(t *testing.T) {
+	tests := []struct {
+		name        string
+		policy      prefs.ConfirmPolicy
+		wantConfirm bool
+	}{
+		{"default asks", prefs.ConfirmPolicy{}, true},
+		{"never skips", prefs.ConfirmPolicy{Destructive: prefs.ConfirmNever}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Resolving a conflict records it for undo under HOME
+			t.Setenv("HOME", t.TempDir())
+			s := State{
+				Platform:    &platform.Platform{OS: "linux"},
+				HasConfig:   true,
+				Preferences: &prefs.Preferences{Confirm: tt.policy},
+			}
+			m := New(s)
+			m.width = 120
+			m.height = 50
+
+			target := filepath.Join(t.TempDir(), ".zshrc")
+			if err := os.WriteFile(target, []byte("x"), 0644); err != nil {
+				t.Fatal(err)
+			}
+			conflicts := []stow.ConflictFile{{ConfigName: "zsh", TargetPath: target}}
+			m.conflictView = NewConflictView(conflicts)
+			m.pendingConflicts = conflicts
+			m.pushView(viewConflict)
+
+			_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'d'}})
+			if cmd == nil {
+				t.Fatal("expected command after pressing d")
+			}
+			msg := cmd()
+
+			if !tt.wantConfirm {
+				if _, ok := msg.(ConflictResolvedMsg); !ok {
+					t.Fatalf("expected ConflictResolvedMsg, got %T", msg)
+				}
+				return
+			}
+
+			if _, ok := msg.(conflictDeleteRequestMsg); !ok {
+				t.Fatalf("expected conflictDeleteRequestMsg, got %T", msg)
+			}
+			m.Update(msg)
+			if m.currentView != viewConfirm || m.confirm == nil || m.confirm.ID() != "delete-conflicts" {
+				t.Fatalf("expected delete-conflicts confirm view, got view %v", m.currentView)
+			}
+			if _, err := os.Stat(target); err != nil {
+				t.Error("file should not be deleted before confirmation")
+			}
+
+			_, cmd = m.Update(ConfirmResult{ID: "delete-conflicts", Confirmed: true})
+			if m.currentView != viewConflict {
+				t.Errorf("expected to return to conflict view, got %v", m.currentView)
+			}
+			if cmd == nil {
+				t.Fatal("expected delete command after confirmation")
+			}
+			if res, ok := cmd().(ConflictResolvedMsg); !ok || !res.Resolved {
+				t.Errorf("expected resolved ConflictResolvedMsg, got %#v", res)
+			}
+			if _, err := os.Stat(target); !os.IsNotExist(err) {
+				t.Error("file should be deleted after confirmation")
+			}
+		})
+	}
+}
+
 // TestCheckForConflicts_FiltersByConfigNames tests that CheckForConflicts
 // correctly filters to specified config names
 func TestCheckForConflicts_FiltersByConfigNames(t *testing.T) {
@@ -1573,3 +1815,86 @@ func TestConflictView_Render(t *testing.T) {
 		t.Error("expected non-empty view")
 	}
 }
+
+func TestConflictView_Strategies(t *testing.T) {
+	configs := []config.ConfigItem{
+		{Name: "nvim", OnConflict: "overwrite"},
+		{Name: "helix", OnConflict: "overwrite"},
+		{Name: "ssh", OnConflict: "backup"},
+	}
+
+	cv := NewConflictView([]stow.ConflictFile{
+		{ConfigName: "nvim", TargetPath: "/home/user/.config/nvim/init.lua"},
+		{ConfigName: "helix", TargetPath: "/home/user/.config/helix/config.toml"},
+	})
+	cv.SetStrategies(configs)
+	cv.SetSize(80, 40)
+	if cv.selectedIdx != int(ConflictChoiceDelete) {
+		t.Errorf("expected Delete to be pre-selected for overwrite configs, got %d", cv.selectedIdx)
+	}
+	if !containsText(overlayConflictContent(cv), "(on_conflict: overwrite)") {
+		t.Error("expected the config's strategy to be shown")
+	}
+
+	cv = NewConflictView([]stow.ConflictFile{
+		{ConfigName: "nvim", TargetPath: "/home/user/.config/nvim/init.lua"},
+		{ConfigName: "ssh", TargetPath: "/home/user/.ssh/config"},
+	})
+	cv.SetStrategies(configs)
+	if cv.selectedIdx != int(ConflictChoiceBackup) {
+		t.Errorf("expected Backup when strategies differ, got %d", cv.selectedIdx)
+	}
+}
+
+func TestSearch_JumpsToOwningConfig(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	dotfiles := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dotfiles, "zsh"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dotfiles, "zsh", ".zshrc"), []byte("export EDITOR=nvim\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	configs := []config.ConfigItem{{Name: "vim", Path: "vim"}, {Name: "zsh", Path: "zsh"}}
+	m := New(State{
+		Platform:     &platform.Platform{OS: "linux"},
+		Configs:      configs,
+		Config:       &config.Config{Configs: config.ConfigGroups{Core: configs}},
+		DotfilesPath: dotfiles,
+		HasConfig:    true,
+	})
+	m.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+
+	m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("F")})
+	if m.currentView != viewSearch {
+		t.Fatalf("expected search view, got %v", m.currentView)
+	}
+
+	m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("EDITOR")})
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if cmd == nil {
+		t.Fatal("expected enter to start a search")
+	}
+	m.Update(m.searchView.runSearch("EDITOR")())
+
+	if !containsText(m.View(), ".zshrc:1:") {
+		t.Errorf("expected results to list .zshrc:1, got:\n%s", m.View())
+	}
+
+	_, cmd = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if cmd == nil {
+		t.Fatal("expected enter on a result to jump")
+	}
+	m.Update(cmd())
+
+	if m.currentView != viewDashboard {
+		t.Errorf("expected dashboard view after jump, got %v", m.currentView)
+	}
+	if selected := m.configsPanel.GetSelectedConfig(); selected == nil || selected.Name != "zsh" {
+		t.Errorf("expected zsh to be selected, got %v", selected)
+	}
+	if m.focusManager.CurrentFocus() != PanelConfigs {
+		t.Errorf("expected configs panel focus, got %v", m.focusManager.CurrentFocus())
+	}
+}
@@ -0,0 +1,171 @@
+package dashboard
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/nvandessel/go4dot/internal/config"
+	"github.com/nvandessel/go4dot/internal/deps"
+	"github.com/nvandessel/go4dot/internal/doctor"
+	"github.com/nvandessel/go4dot/internal/platform"
+	"github.com/nvandessel/go4dot/internal/stow"
+)
+
+// demoStepDelay paces simulated operations so the progress is visible
+const demoStepDelay = 300 * time.Millisecond
+
+// DemoState returns dashboard state built from fabricated data: configs
+// that are linked, partially linked, drifted and conflicting, failing health
+// checks and missing externals. It is fixed, so screenshots and visual tests
+// are reproducible, and touches nothing on disk.
+func DemoState() State {
+	plat := &platform.Platform{
+		OS:             "linux",
+		Distro:         "fedora",
+		DistroVersion:  "41",
+		PackageManager: "dnf",
+		Architecture:   "amd64",
+		Hostname:       "demo",
+	}
+
+	tpm := config.ExternalDep{
+		Name:        "Tmux Plugin Manager",
+		ID:          "tpm",
+		URL:         "https://github.com/tmux-plugins/tpm.git",
+		Destination: "~/.tmux/plugins/tpm",
+		Method:      "clone",
+	}
+	zshAutosuggest := config.ExternalDep{
+		Name:        "zsh-autosuggestions",
+		ID:          "zsh-autosuggestions",
+		URL:         "https://github.com/zsh-users/zsh-autosuggestions.git",
+		Destination: "~/.zsh/zsh-autosuggestions",
+		Method:      "clone",
+	}
+
+	cfg := &config.Config{
+		SchemaVersion: "1.0",
+		Metadata: config.Metadata{
+			Name:        "demo-dotfiles",
+			Description: "Fabricated dotfiles for the go4dot demo",
+		},
+		Configs: config.ConfigGroups{
+			Core: []config.ConfigItem{
+				{Name: "zsh", Path: "zsh", Description: "Zsh shell with plugins and prompt", ExternalDeps: []config.ExternalDep{zshAutosuggest}},
+				{Name: "git", Path: "git", Description: "Git config, aliases and global ignores"},
+				{Name: "nvim", Path: "nvim", Description: "Neovim with lazy.nvim plugins", DependsOn: []string{"git"}},
+				{Name: "tmux", Path: "tmux", Description: "Terminal multiplexer", ExternalDeps: []config.ExternalDep{tpm}},
+			},
+			Optional: []config.ConfigItem{
+				{Name: "alacritty", Path: "alacritty", Description: "GPU accelerated terminal"},
+				{Name: "starship", Path: "starship", Description: "Cross-shell prompt", DependsOn: []string{"zsh"}},
+			},
+		},
+		External: []config.ExternalDep{zshAutosuggest, tpm},
+		MachineConfig: []config.MachinePrompt{
+			{ID: "git", Description: "Git identity", Destination: "~/.gitconfig.local"},
+			{ID: "gpg", Description: "Commit signing key", Destination: "~/.gnupg/gpg-agent.conf"},
+		},
+	}
+
+	linkStatus := map[string]*stow.ConfigLinkStatus{
+		"zsh":       demoLinkStatus("zsh", []string{".zshrc", ".zshenv", ".config/zsh/aliases.zsh", ".config/zsh/prompt.zsh"}, nil),
+		"git":       demoLinkStatus("git", []string{".gitconfig", ".config/git/ignore"}, nil),
+		"nvim":      demoLinkStatus("nvim", []string{".config/nvim/init.lua", ".config/nvim/lua/options.lua", ".config/nvim/lua/keymaps.lua"}, map[string]string{".config/nvim/lua/plugins.lua": "not linked"}),
+		"tmux":      demoLinkStatus("tmux", nil, map[string]string{".tmux.conf": "conflict: file exists"}),
+		"alacritty": demoLinkStatus("alacritty", nil, map[string]string{".config/alacritty/alacritty.toml": "not linked"}),
+		"starship":  demoLinkStatus("starship", []string{".config/starship.toml"}, nil),
+	}
+
+	drift := &stow.DriftSummary{
+		TotalConfigs:      6,
+		DriftedConfigs:    2,
+		TotalNewFiles:     1,
+		TotalContentDrift: 1,
+		TotalOrphans:      1,
+		Results: []stow.DriftResult{
+			{ConfigName: "zsh", ConfigPath: "zsh", CurrentCount: 4, StoredCount: 4},
+			{ConfigName: "git", ConfigPath: "git", CurrentCount: 2, StoredCount: 2},
+			{
+				ConfigName: "nvim", ConfigPath: "nvim", CurrentCount: 4, StoredCount: 3, HasDrift: true,
+				NewFiles:    []string{".config/nvim/lua/plugins.lua"},
+				OrphanFiles: []string{".config/nvim/lazy-lock.json"},
+			},
+			{
+				ConfigName: "tmux", ConfigPath: "tmux", CurrentCount: 1, StoredCount: 1, HasDrift: true,
+				ConflictFiles:     []string{".tmux.conf"},
+				ContentDriftFiles: []string{".tmux.conf"},
+			},
+			{ConfigName: "starship", ConfigPath: "starship", CurrentCount: 1, StoredCount: 1},
+		},
+	}
+
+	health := &doctor.CheckResult{
+		Platform: plat,
+		Checks: []doctor.Check{
+			{Name: "Platform Detection", Description: "Detect OS and package manager", Status: doctor.StatusOK, Message: "linux (dnf)"},
+			{Name: "GNU Stow", Description: "Symlink farm manager", Status: doctor.StatusOK, Message: "Installed and working"},
+			{Name: "Git", Description: "Version control system", Status: doctor.StatusOK, Message: "Found at /usr/bin/git"},
+			{Name: "Dependencies", Description: "Required packages", Status: doctor.StatusError, Message: "1 critical dependencies missing", Fix: "Run 'g4d deps install' to install missing dependencies"},
+			{Name: "Symlinks", Description: "Stowed config symlinks", Status: doctor.StatusWarning, Message: "3 warnings, 10 ok", Fix: "Run 'g4d stow add <config>' to create missing symlinks"},
+			{Name: "External Dependencies", Description: "Cloned repos (themes, plugins)", Status: doctor.StatusWarning, Message: "1 missing, 1 installed, 0 skipped", Fix: "Run 'g4d external clone' to install missing"},
+			{Name: "Machine Configuration", Description: "Machine-specific config files", Status: doctor.StatusWarning, Message: "1 missing, 1 configured", Fix: "Run 'g4d machine configure' to set up"},
+			{Name: "GitHub SSH", Description: "SSH authentication to GitHub", Status: doctor.StatusSkipped, Message: "Skipped in demo"},
+		},
+	}
+
+	externals := []deps.ExternalStatus{
+		{Dep: zshAutosuggest, Status: "installed", Path: "/home/demo/.zsh/zsh-autosuggestions"},
+		{Dep: tpm, Status: "missing", Path: "/home/demo/.tmux/plugins/tpm"},
+	}
+
+	return State{
+		Platform:     plat,
+		DriftSummary: drift,
+		LinkStatus:   linkStatus,
+		MachineStatus: []MachineStatus{
+			{ID: "git", Description: "Git identity", Status: "configured"},
+			{ID: "gpg", Description: "Commit signing key", Status: "missing"},
+		},
+		Configs:        cfg.GetAllConfigs(),
+		Config:         cfg,
+		HasBaseline:    true,
+		HasConfig:      true,
+		Demo:           true,
+		HealthResult:   health,
+		ExternalStatus: externals,
+	}
+}
+
+// demoLinkStatus builds link status from linked files and unlinked files
+// with their issue
+func demoLinkStatus(name string, linked []string, unlinked map[string]string) *stow.ConfigLinkStatus {
+	s := &stow.ConfigLinkStatus{ConfigName: name, ConfigPath: name}
+	for _, f := range linked {
+		s.Files = append(s.Files, stow.FileStatus{RelPath: f, IsLinked: true})
+	}
+	for f, issue := range unlinked {
+		s.Files = append(s.Files, stow.FileStatus{RelPath: f, Issue: issue})
+	}
+	s.LinkedCount = len(linked)
+	s.TotalCount = len(s.Files)
+	return s
+}
+
+// demoOperation returns an operation that walks through the steps of an
+// operation without doing anything
+func demoOperation(steps int) func(runner *OperationRunner) error {
+	return func(runner *OperationRunner) error {
+		for i := 0; i < steps; i++ {
+			runner.Progress(i, "simulated")
+			select {
+			case <-time.After(demoStepDelay):
+			case <-runner.Context().Done():
+				return runner.Err()
+			}
+			runner.StepComplete(i, StepSuccess, "")
+		}
+		runner.Log("info", fmt.Sprintf("Demo mode: %d step(s) simulated, nothing was changed", steps))
+		return nil
+	}
+}
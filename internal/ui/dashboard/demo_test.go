@@ -0,0 +1,60 @@
+package dashboard
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestDemoState(t *testing.T) {
+	s := DemoState()
+
+	if !s.Demo || !s.HasConfig || s.DotfilesPath != "" {
+		t.Fatalf("DemoState() should be a demo with config and no dotfiles path, got Demo=%v HasConfig=%v DotfilesPath=%q",
+			s.Demo, s.HasConfig, s.DotfilesPath)
+	}
+	if len(s.Configs) == 0 {
+		t.Fatal("DemoState() has no configs")
+	}
+	for _, c := range s.Configs {
+		if s.LinkStatus[c.Name] == nil {
+			t.Errorf("config %s has no link status", c.Name)
+		}
+	}
+	if s.DriftSummary == nil || s.DriftSummary.DriftedConfigs == 0 {
+		t.Error("DemoState() should include drift")
+	}
+	if s.HealthResult == nil || s.HealthResult.IsHealthy() {
+		t.Error("DemoState() should include failing health checks")
+	}
+}
+
+func TestDemo_PresetsAndNoConflicts(t *testing.T) {
+	m := New(DemoState())
+
+	msg := m.healthPanel.runChecks()
+	if res, ok := msg.(healthResultMsg); !ok || res.result != m.state.HealthResult {
+		t.Errorf("health panel should return the preset result, got %#v", msg)
+	}
+	msg = m.externalPanel.loadStatus()
+	if res, ok := msg.(externalStatusMsg); !ok || len(res.status) != len(m.state.ExternalStatus) {
+		t.Errorf("external panel should return the preset status, got %#v", msg)
+	}
+
+	if conflicts, err := m.checkForConflicts(nil); err != nil || conflicts != nil {
+		t.Error("checkForConflicts() should be disabled in demo mode")
+	}
+}
+
+func TestDemo_View(t *testing.T) {
+	m := New(DemoState())
+	updated, _ := m.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+	view := updated.(*Model).View()
+
+	for _, want := range []string{"DEMO", "nvim", "tmux"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("demo view should contain %q", want)
+		}
+	}
+}
@@ -7,9 +7,11 @@ import (
 	"sort"
 	"strings"
 
+	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/nvandessel/go4dot/internal/deps"
 	"github.com/nvandessel/go4dot/internal/doctor"
 	"github.com/nvandessel/go4dot/internal/stow"
 	"github.com/nvandessel/go4dot/internal/ui"
@@ -36,13 +38,35 @@ type DetailsPanel struct {
 	// Context determines what to display
 	context DetailsContext
 
+	// docMode shows the selected config's README instead of its details
+	docMode bool
+
 	// Data from various panels
 	configsPanel   *ConfigsPanel
 	healthPanel    *HealthPanel
 	overridesPanel *OverridesPanel
 	externalPanel  *ExternalPanel
+
+	// Size statistics per config, computed lazily when a config is shown
+	stats        map[string]*stow.ConfigStats
+	statsLoading map[string]bool
+
+	// Cursor over the selected config's unlinked files, which enter relinks
+	fileCursor   int
+	cursorConfig string // Config the cursor was placed in
+	cursorLine   int    // Content line of the file under the cursor, -1 if none
+}
+
+// configStatsMsg is sent when size statistics for a config are computed
+type configStatsMsg struct {
+	name  string
+	stats *stow.ConfigStats
+	err   error
 }
 
+// detailsLargestFiles is how many of the largest files the details list
+const detailsLargestFiles = 3
+
 // NewDetailsPanel creates a new details panel
 func NewDetailsPanel(state State) *DetailsPanel {
 	vp := viewport.New(0, 0)
@@ -53,6 +77,10 @@ func NewDetailsPanel(state State) *DetailsPanel {
 		state:     state,
 		viewport:  vp,
 		context:   DetailsContextConfigs,
+
+		stats:        make(map[string]*stow.ConfigStats),
+		statsLoading: make(map[string]bool),
+		cursorLine:   -1,
 	}
 }
 
@@ -89,15 +117,88 @@ func (p *DetailsPanel) Update(msg tea.Msg) tea.Cmd {
 			return cmd
 		}
 	case tea.KeyMsg:
-		if p.focused {
-			p.viewport, cmd = p.viewport.Update(msg)
-			return cmd
+		if !p.focused {
+			return nil
 		}
+		// Up and down pick an unlinked file while there is one to pick
+		if _, _, ok := p.SelectedFile(); ok {
+			switch {
+			case key.Matches(msg, keys.Up):
+				p.moveFileCursor(-1)
+				return nil
+			case key.Matches(msg, keys.Down):
+				p.moveFileCursor(1)
+				return nil
+			}
+		}
+		p.viewport, cmd = p.viewport.Update(msg)
+		return cmd
 	}
 
 	return nil
 }
 
+// SetFocused implements Panel interface. The file cursor only shows while
+// the panel is focused.
+func (p *DetailsPanel) SetFocused(focused bool) {
+	p.BasePanel.SetFocused(focused)
+	p.updateContent()
+}
+
+// SelectedFile returns the config and the path inside it of the unlinked
+// file under the cursor, if the panel shows a config with unlinked files
+func (p *DetailsPanel) SelectedFile() (configName, relPath string, ok bool) {
+	if p.context != DetailsContextConfigs || p.docMode || p.configsPanel == nil {
+		return "", "", false
+	}
+	cfg := p.configsPanel.GetSelectedConfig()
+	if cfg == nil {
+		return "", "", false
+	}
+	files := p.unlinkedFiles(cfg.Name)
+	if len(files) == 0 {
+		return "", "", false
+	}
+	if cfg.Name != p.cursorConfig {
+		p.cursorConfig = cfg.Name
+		p.fileCursor = 0
+	}
+	if p.fileCursor >= len(files) {
+		p.fileCursor = len(files) - 1
+	}
+	return cfg.Name, files[p.fileCursor], true
+}
+
+// unlinkedFiles returns the config's files that are not linked, in the
+// order the file tree lists them
+func (p *DetailsPanel) unlinkedFiles(name string) []string {
+	linkStatus := p.state.LinkStatus[name]
+	if linkStatus == nil {
+		return nil
+	}
+	return unlinkedTreeFiles(buildFileTree(linkStatus.Files))
+}
+
+// moveFileCursor moves the file cursor by delta and scrolls it into view
+func (p *DetailsPanel) moveFileCursor(delta int) {
+	_, _, ok := p.SelectedFile()
+	if !ok {
+		return
+	}
+	n := len(p.unlinkedFiles(p.cursorConfig))
+	p.fileCursor = max(0, min(n-1, p.fileCursor+delta))
+	p.updateContent()
+
+	if p.cursorLine < 0 {
+		return
+	}
+	if p.cursorLine < p.viewport.YOffset {
+		p.viewport.SetYOffset(p.cursorLine)
+	} else if p.cursorLine >= p.viewport.YOffset+p.viewport.Height {
+		p.viewport.SetYOffset(p.cursorLine - p.viewport.Height + 1)
+	}
+}
+
 // View implements Panel interface
 func (p *DetailsPanel) View() string {
 	if !p.ready {
@@ -113,6 +214,9 @@ func (p *DetailsPanel) GetSelectedItem() *SelectedItem {
 
 // SetContext sets what panel's content to display details for
 func (p *DetailsPanel) SetContext(ctx DetailsContext) {
+	if ctx != DetailsContextConfigs {
+		p.docMode = false
+	}
 	p.context = ctx
 	p.updateContent()
 }
@@ -125,6 +229,38 @@ func (p *DetailsPanel) SetPanels(configs *ConfigsPanel, health *HealthPanel, ove
 	p.externalPanel = external
 }
 
+// LoadStats returns a command computing size statistics for the selected
+// config, or nil if they are already known or being computed
+func (p *DetailsPanel) LoadStats() tea.Cmd {
+	if p.context != DetailsContextConfigs || p.configsPanel == nil || p.state.DotfilesPath == "" {
+		return nil
+	}
+	cfg := p.configsPanel.GetSelectedConfig()
+	if cfg == nil || cfg.Path == "" || p.statsLoading[cfg.Name] {
+		return nil
+	}
+	if _, ok := p.stats[cfg.Name]; ok {
+		return nil
+	}
+
+	p.statsLoading[cfg.Name] = true
+	p.updateContent()
+
+	item, dotfilesPath := *cfg, p.state.DotfilesPath
+	return func() tea.Msg {
+		stats, err := stow.GetConfigStats(item, dotfilesPath, detailsLargestFiles)
+		return configStatsMsg{name: item.Name, stats: stats, err: err}
+	}
+}
+
+// SetStats stores computed statistics for a config. A nil result is cached
+// too so failing configs are not walked again.
+func (p *DetailsPanel) SetStats(msg configStatsMsg) {
+	delete(p.statsLoading, msg.name)
+	p.stats[msg.name] = msg.stats
+	p.updateContent()
+}
+
 // RefreshContent updates the content based on current context
 func (p *DetailsPanel) RefreshContent() {
 	p.updateContent()
@@ -132,6 +268,7 @@ func (p *DetailsPanel) RefreshContent() {
 
 func (p *DetailsPanel) updateContent() {
 	var content string
+	p.cursorLine = -1
 
 	switch p.context {
 	case DetailsContextHealth:
@@ -140,6 +277,12 @@ func (p *DetailsPanel) updateContent() {
 		content = p.renderOverridesDetails()
 	case DetailsContextExternal:
 		content = p.renderExternalDetails()
+	case DetailsContextConfigs:
+		if p.docMode {
+			content = p.renderConfigDocs()
+		} else {
+			content = p.renderConfigDetails()
+		}
 	default:
 		content = p.renderConfigDetails()
 	}
@@ -182,6 +325,16 @@ func (p *DetailsPanel) renderConfigDetails() string {
 		lines = append(lines, "")
 	}
 
+	if len(cfg.Tags) > 0 || len(cfg.Platforms) > 0 {
+		if len(cfg.Tags) > 0 {
+			lines = append(lines, fmt.Sprintf("%s %s", subtleStyle.Render("Tags:     "), strings.Join(cfg.Tags, ", ")))
+		}
+		if len(cfg.Platforms) > 0 {
+			lines = append(lines, fmt.Sprintf("%s %s", subtleStyle.Render("Platforms:"), strings.Join(cfg.Platforms, ", ")))
+		}
+		lines = append(lines, "")
+	}
+
 	// Show source and destination paths
 	if linkStatus != nil || cfg.Path != "" {
 		lines = append(lines, headerStyle.Render("PATHS"))
@@ -189,7 +342,7 @@ func (p *DetailsPanel) renderConfigDetails() string {
 		if cfg.Path != "" {
 			lines = append(lines, fmt.Sprintf("%s %s",
 				subtleStyle.Render("Source:"),
-				pathStyle.Render(filepath.Join(p.state.DotfilesPath, cfg.Path))))
+				pathStyle.Render(cfg.Dir(p.state.DotfilesPath))))
 		}
 		home := os.Getenv("HOME")
 		if home != "" {
@@ -200,6 +353,11 @@ func (p *DetailsPanel) renderConfigDetails() string {
 		lines = append(lines, "")
 	}
 
+	if readme := p.selectedConfigReadme(); readme != "" {
+		lines = append(lines, subtleStyle.Render(fmt.Sprintf("Press D to read %s", filepath.Base(readme))))
+		lines = append(lines, "")
+	}
+
 	// Get drift result for enhanced display
 	var driftResult *stow.DriftResult
 	if p.state.DriftSummary != nil {
@@ -223,10 +381,16 @@ func (p *DetailsPanel) renderConfigDetails() string {
 			}
 			driftParts = append(driftParts, warnStyle.Render(conflictText))
 		}
+		if len(driftResult.LinkErrors) > 0 {
+			driftParts = append(driftParts, errStyle.Render(fmt.Sprintf("✗%d symlink loops", len(driftResult.LinkErrors))))
+		}
 		if len(driftResult.OrphanFiles) > 0 {
 			driftParts = append(driftParts, subtleStyle.Render(fmt.Sprintf("?%d untracked", len(driftResult.OrphanFiles))))
 		}
 		lines = append(lines, "  "+strings.Join(driftParts, ", "))
+		for _, linkErr := range driftResult.LinkErrors {
+			lines = append(lines, "  "+errStyle.Render(linkErr))
+		}
 		lines = append(lines, "")
 	}
 
@@ -250,11 +414,24 @@ func (p *DetailsPanel) renderConfigDetails() string {
 			markContentDriftInTree(tree, driftResult.ContentDriftFiles)
 		}
 
-		treeLines := renderFileTree(tree, "", true, okStyle, warnStyle, errStyle, subtleStyle)
+		var selected string
+		if p.focused {
+			_, selected, _ = p.SelectedFile()
+		}
+		treeLines, selectedLine := renderFileTree(tree, "", true, selected, okStyle, warnStyle, errStyle, subtleStyle)
+		if selectedLine >= 0 {
+			// Lines before the tree may wrap, e.g. the description
+			p.cursorLine = strings.Count(strings.Join(lines, "\n"), "\n") + 1 + selectedLine
+		}
 		lines = append(lines, treeLines...)
+		if selected != "" {
+			lines = append(lines, subtleStyle.Render("  ↑/↓ pick a file, enter to relink it"))
+		}
 		lines = append(lines, "")
 	}
 
+	lines = append(lines, p.renderStats(cfg.Name)...)
+
 	if len(cfg.DependsOn) > 0 {
 		lines = append(lines, headerStyle.Render("MODULE DEPENDENCIES"))
 		for _, depName := range cfg.DependsOn {
@@ -293,14 +470,48 @@ func (p *DetailsPanel) renderConfigDetails() string {
 	return strings.Join(lines, "\n")
 }
 
+// renderStats renders the size section for a config
+func (p *DetailsPanel) renderStats(name string) []string {
+	subtleStyle := ui.SubtleStyle
+	if p.statsLoading[name] {
+		return []string{ui.HeaderStyle.Render("SIZE"), subtleStyle.Render("  Calculating..."), ""}
+	}
+	stats := p.stats[name]
+	if stats == nil {
+		return nil
+	}
+
+	files := fmt.Sprintf("%d files", stats.Files)
+	if stats.Files == 1 {
+		files = "1 file"
+	}
+	lines := []string{
+		ui.HeaderStyle.Render("SIZE"),
+		fmt.Sprintf("  %s %s", stow.FormatSize(stats.Size), subtleStyle.Render("in "+files)),
+	}
+	for _, f := range stats.Largest {
+		note := stow.FormatSize(f.Size)
+		switch {
+		case f.Binary:
+			lines = append(lines, ui.WarningStyle.Render(fmt.Sprintf("  ⚠ %s (%s, binary)", f.Path, note)))
+		case f.Size > stow.LargeFileThreshold:
+			lines = append(lines, ui.WarningStyle.Render(fmt.Sprintf("  ⚠ %s (%s, large)", f.Path, note)))
+		default:
+			lines = append(lines, subtleStyle.Render(fmt.Sprintf("  · %s (%s)", f.Path, note)))
+		}
+	}
+	return append(lines, "")
+}
+
 // fileTreeNode represents a node in the file tree (either a directory or file)
 type fileTreeNode struct {
 	name            string
 	isDir           bool
 	isLinked        bool
 	issue           string
-	isOrphan        bool // File in dest not tracked by source
-	hasContentDrift bool // Conflict file with different content from source
+	relPath         string // Path of a file inside the config
+	isOrphan        bool   // File in dest not tracked by source
+	hasContentDrift bool   // Conflict file with different content from source
 	children        map[string]*fileTreeNode
 }
 
@@ -338,6 +549,7 @@ func buildFileTree(files []stow.FileStatus) *fileTreeNode {
 			}
 
 			if isLast {
+				child.relPath = f.RelPath
 				child.isLinked = f.IsLinked
 				child.issue = f.Issue
 				child.isDir = false
@@ -350,11 +562,9 @@ func buildFileTree(files []stow.FileStatus) *fileTreeNode {
 	return root
 }
 
-// renderFileTree renders the tree structure with proper tree connectors (├─, └─, │)
-func renderFileTree(node *fileTreeNode, prefix string, isRoot bool, okStyle, warnStyle, errStyle, subtleStyle lipgloss.Style) []string {
-	var lines []string
-
-	// Sort children: directories first, then files, both alphabetically
+// sortedTreeChildren returns the names of node's children as the tree
+// lists them: directories first, then files, both alphabetically
+func sortedTreeChildren(node *fileTreeNode) []string {
 	var dirs, files []string
 	for name, child := range node.children {
 		if child.isDir {
@@ -365,9 +575,32 @@ func renderFileTree(node *fileTreeNode, prefix string, isRoot bool, okStyle, war
 	}
 	sort.Strings(dirs)
 	sort.Strings(files)
+	return append(dirs, files...)
+}
+
+// unlinkedTreeFiles returns the paths of the files below node that are not
+// linked, in the order renderFileTree lists them
+func unlinkedTreeFiles(node *fileTreeNode) []string {
+	var paths []string
+	for _, name := range sortedTreeChildren(node) {
+		child := node.children[name]
+		if child.isDir {
+			paths = append(paths, unlinkedTreeFiles(child)...)
+		} else if !child.isLinked && !child.isOrphan {
+			paths = append(paths, child.relPath)
+		}
+	}
+	return paths
+}
 
-	// Combine into ordered list for proper connector rendering
-	allNames := append(dirs, files...)
+// renderFileTree renders the tree structure with proper tree connectors (├─, └─, │).
+// The file at selected is highlighted; its line index is returned, or -1.
+func renderFileTree(node *fileTreeNode, prefix string, isRoot bool, selected string, okStyle, warnStyle, errStyle, subtleStyle lipgloss.Style) ([]string, int) {
+	var lines []string
+	selectedLine := -1
+
+	// Directories first, then files, for proper connector rendering
+	allNames := sortedTreeChildren(node)
 	totalChildren := len(allNames)
 
 	for i, name := range allNames {
@@ -397,7 +630,10 @@ func renderFileTree(node *fileTreeNode, prefix string, isRoot bool, okStyle, war
 			// Directory node
 			dirLabel := subtleStyle.Render(connector) + " " + subtleStyle.Render(name+"/")
 			lines = append(lines, linePrefix+dirLabel)
-			childLines := renderFileTree(child, childPrefix, false, okStyle, warnStyle, errStyle, subtleStyle)
+			childLines, childSelected := renderFileTree(child, childPrefix, false, selected, okStyle, warnStyle, errStyle, subtleStyle)
+			if childSelected >= 0 {
+				selectedLine = len(lines) + childSelected
+			}
 			lines = append(lines, childLines...)
 		} else {
 			// File node - choose status icon
@@ -416,7 +652,12 @@ func renderFileTree(node *fileTreeNode, prefix string, isRoot bool, okStyle, war
 				icon = errStyle.Render("✗")
 			}
 
-			lines = append(lines, linePrefix+subtleStyle.Render(connector)+" "+icon+" "+name)
+			label := name
+			if selected != "" && child.relPath == selected && !child.isOrphan {
+				selectedLine = len(lines)
+				label = ui.SelectedItemStyle.Render(name)
+			}
+			lines = append(lines, linePrefix+subtleStyle.Render(connector)+" "+icon+" "+label)
 
 			// Show issue description
 			if child.isOrphan {
@@ -431,7 +672,7 @@ func renderFileTree(node *fileTreeNode, prefix string, isRoot bool, okStyle, war
 		}
 	}
 
-	return lines
+	return lines, selectedLine
 }
 
 func (p *DetailsPanel) renderHealthDetails() string {
@@ -498,9 +739,63 @@ func (p *DetailsPanel) renderHealthDetails() string {
 		lines = append(lines, "")
 	}
 
+	if result := p.healthPanel.GetResult(); check.ID == "dependencies" && result != nil && result.DepsResult != nil {
+		lines = append(lines, renderDependencyVersions(result.DepsResult)...)
+	}
+
 	return strings.Join(lines, "\n")
 }
 
+// renderDependencyVersions lists each dependency with the version found and
+// the version required, marking outdated ones
+func renderDependencyVersions(result *deps.CheckResult) []string {
+	okStyle := lipgloss.NewStyle().Foreground(ui.SecondaryColor)
+	warnStyle := lipgloss.NewStyle().Foreground(ui.WarningColor)
+	errStyle := lipgloss.NewStyle().Foreground(ui.ErrorColor)
+	subtleStyle := ui.SubtleStyle
+
+	lines := []string{ui.HeaderStyle.Render("VERSIONS")}
+	outdated := 0
+	for _, checks := range [][]deps.DependencyCheck{result.Critical, result.Core, result.Optional} {
+		for _, c := range checks {
+			var icon, version string
+			switch c.Status {
+			case deps.StatusInstalled:
+				icon = okStyle.Render("✓")
+				version = c.InstalledVersion
+			case deps.StatusVersionMismatch:
+				outdated++
+				icon = warnStyle.Render("↑")
+				version = warnStyle.Render(c.InstalledVersion + " (outdated)")
+			case deps.StatusCheckFailed:
+				icon = warnStyle.Render("?")
+				version = subtleStyle.Render("version unknown")
+			case deps.StatusUnsupportedArch:
+				icon = subtleStyle.Render("⊘")
+				version = subtleStyle.Render("not for " + c.Arch)
+			default:
+				icon = errStyle.Render("✗")
+				version = subtleStyle.Render("missing")
+			}
+
+			line := fmt.Sprintf("  %s %s", icon, c.Item.Name)
+			if version != "" {
+				line += " " + version
+			}
+			if c.RequiredVersion != "" {
+				line += subtleStyle.Render(" needs " + c.RequiredVersion)
+			}
+			lines = append(lines, line)
+		}
+	}
+	lines = append(lines, "")
+
+	if outdated > 0 {
+		lines = append(lines, subtleStyle.Render(fmt.Sprintf("Press U to upgrade %d outdated", outdated)))
+	}
+	return lines
+}
+
 func (p *DetailsPanel) renderOverridesDetails() string {
 	if p.overridesPanel == nil {
 		return ui.SubtleStyle.Render("No machine config selected")
@@ -575,6 +870,10 @@ func (p *DetailsPanel) renderExternalDetails() string {
 		return ui.SubtleStyle.Render("Loading external dependencies...")
 	}
 
+	if group, ok := p.externalPanel.GetSelectedGroup(); ok {
+		return renderExternalGroupDetails(group)
+	}
+
 	ext := p.externalPanel.GetSelectedExternal()
 	if ext == nil {
 		return ui.SubtleStyle.Render("No external dependency selected")
@@ -623,6 +922,23 @@ func (p *DetailsPanel) renderExternalDetails() string {
 	lines = append(lines, descStyle.Render(ext.Dep.Destination))
 	lines = append(lines, "")
 
+	if info, hints := p.externalPanel.GetGitHubInfo(ext.Dep.ID); info != nil {
+		lines = append(lines, headerStyle.Render("UPSTREAM"))
+		upstream := "★ " + deps.FormatStars(info.Stars)
+		if info.LatestRelease != "" {
+			upstream += ", latest " + info.LatestRelease
+		}
+		lines = append(lines, descStyle.Render(upstream))
+		for _, hint := range hints {
+			lines = append(lines, warnStyle.Render("⚠ "+hint))
+		}
+		lines = append(lines, "")
+	} else if _, ok := deps.GitHubRepo(ext.Dep.URL); ok && p.externalPanel.GitHubError() != nil {
+		lines = append(lines, headerStyle.Render("UPSTREAM"))
+		lines = append(lines, descStyle.Render(p.externalPanel.GitHubError().Error()))
+		lines = append(lines, "")
+	}
+
 	switch ext.Status {
 	case "missing":
 		lines = append(lines, descStyle.Render("Press Enter to clone"))
@@ -633,6 +949,44 @@ func (p *DetailsPanel) renderExternalDetails() string {
 	return strings.Join(lines, "\n")
 }
 
+// renderExternalGroupDetails lists the externals of a group and what Enter
+// does to them
+func renderExternalGroupDetails(g ExternalGroup) string {
+	nameStyle := lipgloss.NewStyle().Foreground(ui.TextColor).Bold(true)
+	descStyle := ui.SubtleStyle
+
+	var lines []string
+	if g.Config == "" {
+		lines = append(lines, nameStyle.Render("Global externals"))
+		lines = append(lines, descStyle.Render("Not referenced by any config"))
+	} else {
+		lines = append(lines, nameStyle.Render("Externals of "+g.Config))
+	}
+	lines = append(lines, "")
+
+	lines = append(lines, ui.HeaderStyle.Render("DEPENDENCIES"))
+	for _, s := range g.Members {
+		name := s.Dep.Name
+		if name == "" {
+			name = s.Dep.ID
+		}
+		lines = append(lines, fmt.Sprintf("  %s %s", externalStatusIcon(s.Status), name))
+	}
+	lines = append(lines, "")
+
+	missing, installed := g.Counts()
+	switch {
+	case missing > 0 && installed > 0:
+		lines = append(lines, descStyle.Render(fmt.Sprintf("Press Enter to clone %d and update %d", missing, installed)))
+	case missing > 0:
+		lines = append(lines, descStyle.Render(fmt.Sprintf("Press Enter to clone %d", missing)))
+	case installed > 0:
+		lines = append(lines, descStyle.Render(fmt.Sprintf("Press Enter to update %d", installed)))
+	}
+
+	return strings.Join(lines, "\n")
+}
+
 // addOrphansToTree adds orphan file nodes to the file tree
 func addOrphansToTree(root *fileTreeNode, orphanFiles []string) {
 	for _, orphanPath := range orphanFiles {
@@ -700,3 +1054,11 @@ func (p *DetailsPanel) UpdateState(state State) {
 	p.state = state
 	p.updateContent()
 }
+
+// HandleEvent implements Subscriber
+func (p *DetailsPanel) HandleEvent(e Event) tea.Cmd {
+	if e, ok := e.(StatusUpdatedEvent); ok {
+		p.UpdateState(e.State)
+	}
+	return nil
+}
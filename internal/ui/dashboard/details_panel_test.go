@@ -0,0 +1,110 @@
+package dashboard
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/nvandessel/go4dot/internal/config"
+	"github.com/nvandessel/go4dot/internal/stow"
+)
+
+func TestDetailsPanel_LoadStats(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	dotfiles := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dotfiles, "zsh"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dotfiles, "zsh", ".zshrc"), []byte("export EDITOR=nvim\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	state := State{
+		Configs:      []config.ConfigItem{{Name: "zsh", Path: "zsh"}},
+		DotfilesPath: dotfiles,
+	}
+	p := NewDetailsPanel(state)
+	p.SetPanels(NewConfigsPanel(state, map[string]bool{}), nil, nil, nil)
+	p.SetSize(60, 40)
+
+	cmd := p.LoadStats()
+	if cmd == nil {
+		t.Fatal("LoadStats() returned nil for a config without stats")
+	}
+	if !strings.Contains(p.renderConfigDetails(), "Calculating") {
+		t.Error("details should show a loading state while stats are computed")
+	}
+	if p.LoadStats() != nil {
+		t.Error("LoadStats() should not start a second load while one is pending")
+	}
+
+	msg, ok := cmd().(configStatsMsg)
+	if !ok {
+		t.Fatalf("LoadStats() command returned %T, want configStatsMsg", cmd())
+	}
+	if msg.err != nil {
+		t.Fatalf("stats error = %v", msg.err)
+	}
+	p.SetStats(msg)
+
+	content := p.renderConfigDetails()
+	for _, want := range []string{"SIZE", "1 file", ".zshrc"} {
+		if !strings.Contains(content, want) {
+			t.Errorf("details missing %q:\n%s", want, content)
+		}
+	}
+	if p.LoadStats() != nil {
+		t.Error("LoadStats() should use cached stats")
+	}
+}
+
+func TestDetailsPanel_FileCursor(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	state := State{
+		Configs: []config.ConfigItem{{Name: "nvim", Path: "nvim"}},
+		LinkStatus: map[string]*stow.ConfigLinkStatus{
+			"nvim": {
+				ConfigName:  "nvim",
+				LinkedCount: 1,
+				TotalCount:  3,
+				Files: []stow.FileStatus{
+					{RelPath: "init.lua", Issue: "not linked"},
+					{RelPath: "lua/opts.lua", IsLinked: true},
+					{RelPath: "lua/keys.lua", Issue: "not linked"},
+				},
+			},
+		},
+	}
+	p := NewDetailsPanel(state)
+	p.SetPanels(NewConfigsPanel(state, map[string]bool{}), nil, nil, nil)
+	p.SetSize(60, 40)
+	p.SetFocused(true)
+
+	// The tree lists directories first, so lua/keys.lua comes before init.lua
+	if name, rel, ok := p.SelectedFile(); !ok || name != "nvim" || rel != "lua/keys.lua" {
+		t.Fatalf("SelectedFile() = %q, %q, %v, want nvim, lua/keys.lua", name, rel, ok)
+	}
+	if p.cursorLine < 0 {
+		t.Error("the file under the cursor has no content line")
+	}
+
+	p.Update(tea.KeyMsg{Type: tea.KeyDown})
+	if _, rel, _ := p.SelectedFile(); rel != "init.lua" {
+		t.Errorf("after down SelectedFile() = %q, want init.lua", rel)
+	}
+	p.Update(tea.KeyMsg{Type: tea.KeyDown})
+	if _, rel, _ := p.SelectedFile(); rel != "init.lua" {
+		t.Errorf("cursor moved past the last file, got %q", rel)
+	}
+
+	// Once everything is linked there is nothing to pick
+	for i := range state.LinkStatus["nvim"].Files {
+		state.LinkStatus["nvim"].Files[i].IsLinked = true
+	}
+	p.UpdateState(state)
+	if _, _, ok := p.SelectedFile(); ok {
+		t.Error("SelectedFile() picked a file while all are linked")
+	}
+}
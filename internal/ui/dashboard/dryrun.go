@@ -0,0 +1,156 @@
+package dashboard
+
+import (
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/nvandessel/go4dot/internal/config"
+	"github.com/nvandessel/go4dot/internal/setup"
+)
+
+// toggleDryRun turns dry-run mode on or off. In dry-run mode sync, link and
+// install only report the symlinks, packages and repos they would change,
+// like g4d sync --dry-run.
+func (m *Model) toggleDryRun() {
+	m.dryRun = !m.dryRun
+	m.footer.SetDryRun(m.dryRun)
+	if m.dryRun {
+		m.outputPanel.AddLog("info", "Dry-run mode on: sync, link and install only report what they would change")
+	} else {
+		m.outputPanel.AddLog("info", "Dry-run mode off")
+	}
+}
+
+// startDryRun reports what opType would change for configName, for
+// configNames, or for every config when neither is given. Conflicts are
+// reported as stow plans them rather than resolved first.
+func (m *Model) startDryRun(opType OperationType, configName string, configNames []string) tea.Cmd {
+	if m.state.Config == nil || m.operationActive {
+		return nil
+	}
+	cfg, dotfilesPath := m.state.Config, m.state.DotfilesPath
+
+	if opType == OpInstall {
+		return m.StartInlineOperation(OpDryRun, "", nil, func(runner *OperationRunner) error {
+			return RunDryRunOperation(runner, func() (*setup.DryRunResult, error) {
+				return setup.InstallDryRun(cfg, dotfilesPath, setup.InstallOptions{})
+			})
+		})
+	}
+
+	if configName != "" {
+		configNames = []string{configName}
+	}
+	full := opType == OpSync || opType == OpSyncSingle || opType == OpBulkSync
+	opts := syncDryRunOptions(cfg, configNames, full)
+	return m.StartInlineOperation(OpDryRun, configName, configNames, func(runner *OperationRunner) error {
+		return RunDryRunOperation(runner, func() (*setup.DryRunResult, error) {
+			return setup.DryRun(cfg, dotfilesPath, opts)
+		})
+	})
+}
+
+// syncDryRunOptions returns what a dry run of syncing configNames, or every
+// config when there are none, plans. A full sync also installs missing
+// dependencies, clones the configs' missing externals and runs hooks.
+func syncDryRunOptions(cfg *config.Config, configNames []string, full bool) setup.DryRunOptions {
+	opts := setup.DryRunOptions{
+		Configs: cfg.GetAllConfigs(),
+		Links:   len(configNames) == 0,
+	}
+	if len(configNames) > 0 {
+		opts.Configs = configItems(cfg, configNames)
+	}
+	if full {
+		opts.Deps = true
+		opts.HookStages = []string{config.HookPreSync, config.HookPostSync}
+		opts.External = cfg.GetExternalForConfigs(configNames)
+	}
+	return opts
+}
+
+// RunDryRunOperation works out what an operation would change with plan
+// and logs every planned change, changing nothing
+func RunDryRunOperation(runner *OperationRunner, plan func() (*setup.DryRunResult, error)) error {
+	runner.Progress(0, "Planning changes...")
+
+	result, err := plan()
+	if err != nil {
+		runner.StepComplete(0, StepError, err.Error())
+		return fmt.Errorf("dry run: %w", err)
+	}
+	logDryRun(runner, result)
+
+	if result.HasErrors() {
+		runner.StepComplete(0, StepWarning, "Some changes would fail")
+		runner.Done(false, "Dry run: "+result.Summary(), nil)
+		return nil
+	}
+	runner.StepComplete(0, StepSuccess, "Nothing was changed")
+	runner.Done(true, "Dry run: "+result.Summary(), nil)
+	return nil
+}
+
+// logDryRun logs each change of a dry run, like the sections printed by
+// g4d sync --dry-run
+func logDryRun(runner *OperationRunner, result *setup.DryRunResult) {
+	for _, a := range result.Stow {
+		msg := fmt.Sprintf("[%s] %s", a.Config, a.String())
+		if a.IsProblem() {
+			runner.Log("warning", msg)
+		} else {
+			runner.Log("info", "Would "+msg)
+		}
+	}
+	for _, f := range result.StowFailed {
+		runner.Log("error", fmt.Sprintf("%s would fail to link: %v", f.ConfigName, f.Error))
+	}
+	for _, c := range result.Adopt {
+		runner.Log("info", fmt.Sprintf("Would adopt %s into %s", c.TargetPath, c.ConfigName))
+	}
+	if l := result.Links; l != nil {
+		for _, target := range l.Linked {
+			runner.Log("info", fmt.Sprintf("Would link %s", target))
+		}
+		for _, target := range l.Copied {
+			runner.Log("info", fmt.Sprintf("Would copy to %s", target))
+		}
+		for _, target := range l.Removed {
+			runner.Log("info", fmt.Sprintf("Would remove link %s", target))
+		}
+		for _, f := range l.Failed {
+			runner.Log("error", fmt.Sprintf("Link %s would fail: %v", f.Target, f.Error))
+		}
+	}
+	for _, p := range result.Packages {
+		if p.Upgrade() {
+			runner.Log("info", fmt.Sprintf("Would upgrade %s %s → %s (%s)", p.Dep.Name, p.Installed, p.Required, p.Package))
+		} else {
+			runner.Log("info", fmt.Sprintf("Would install %s (%s)", p.Dep.Name, p.Package))
+		}
+	}
+	for _, dep := range result.Manual {
+		runner.Log("warning", fmt.Sprintf("%s is missing and must be installed manually", dep.Name))
+	}
+	for _, tc := range result.Toolchains {
+		runner.Log("info", fmt.Sprintf("Would install toolchain %s", tc))
+	}
+	for _, e := range result.Externals {
+		runner.Log("info", fmt.Sprintf("Would clone %s (%s) to %s", e.Dep.Name, e.Dep.URL, e.Path))
+	}
+	for _, f := range result.ExternalFailed {
+		runner.Log("error", fmt.Sprintf("%s would fail: %v", f.Dep.Name, f.Error))
+	}
+	for _, s := range result.MachineConfigs {
+		runner.Log("info", fmt.Sprintf("Would generate %s at %s", s.ID, s.Destination))
+	}
+	for _, path := range result.ShellRCFiles {
+		runner.Log("info", fmt.Sprintf("Would source g4d shell-init in %s", path))
+	}
+	for _, h := range result.Hooks {
+		runner.Log("info", fmt.Sprintf("Would run hook %s", h))
+	}
+	if result.Empty() {
+		runner.Log("success", "Dry run: nothing to change")
+	}
+}
@@ -0,0 +1,44 @@
+package dashboard
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/nvandessel/go4dot/internal/config"
+	"github.com/nvandessel/go4dot/internal/platform"
+)
+
+func TestDryRun_Toggle(t *testing.T) {
+	configs := []config.ConfigItem{{Name: "git", Path: "git"}}
+	m := New(State{
+		Platform:     &platform.Platform{OS: "linux"},
+		Configs:      configs,
+		Config:       &config.Config{Configs: config.ConfigGroups{Core: configs}},
+		DotfilesPath: t.TempDir(),
+		HasConfig:    true,
+		ReadOnly:     true,
+	})
+	m.Update(tea.WindowSizeMsg{Width: 200, Height: 40})
+
+	m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("n")})
+	if !m.dryRun {
+		t.Fatal("expected n to turn dry-run mode on")
+	}
+	if !strings.Contains(m.footer.View(), "DRY-RUN") {
+		t.Errorf("expected the dry-run indicator, got %q", m.footer.View())
+	}
+
+	// Dry runs change nothing, so read-only mode lets them through
+	if reason := m.mutatingAction(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("s")}, PanelConfigs); reason != "" {
+		t.Errorf("expected sync to be allowed as a dry run, got %q", reason)
+	}
+	if reason := m.mutatingAction(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("a")}, PanelConfigs); reason == "" {
+		t.Error("expected archive to stay refused in dry-run mode")
+	}
+
+	m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("n")})
+	if m.dryRun {
+		t.Error("expected n to turn dry-run mode off")
+	}
+}
@@ -0,0 +1,71 @@
+package dashboard
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/nvandessel/go4dot/internal/state"
+)
+
+// Event is a change the model announces to the panels. Panels subscribe to
+// the events they care about instead of the model calling their setters, so
+// a new panel only has to handle the events it needs.
+type Event interface {
+	isEvent()
+}
+
+// StatusUpdatedEvent is published when the shared state changes, e.g. a new
+// config was loaded or a machine config was written
+type StatusUpdatedEvent struct {
+	State State
+}
+
+// ConfigsChangedEvent is published when the set of selected configs changes
+type ConfigsChangedEvent struct {
+	Selected map[string]bool
+}
+
+// OperationStartedEvent is published when an inline operation starts
+type OperationStartedEvent struct {
+	Type OperationType
+}
+
+// OperationFinishedEvent is published when an inline operation ends
+type OperationFinishedEvent struct {
+	Type    OperationType
+	Summary string
+	Timings []state.StepTiming // How long each step that ran took
+	Err     error
+}
+
+func (StatusUpdatedEvent) isEvent()     {}
+func (ConfigsChangedEvent) isEvent()    {}
+func (OperationStartedEvent) isEvent()  {}
+func (OperationFinishedEvent) isEvent() {}
+
+// Subscriber receives events from the bus. Events a subscriber does not
+// handle are ignored; the returned command, if any, is run by the program.
+type Subscriber interface {
+	HandleEvent(e Event) tea.Cmd
+}
+
+// EventBus delivers events to its subscribers in the order they subscribed.
+// Delivery is synchronous, inside the model's Update, so subscribers may
+// change their state directly.
+type EventBus struct {
+	subscribers []Subscriber
+}
+
+// Subscribe adds s to the subscribers
+func (b *EventBus) Subscribe(s Subscriber) {
+	b.subscribers = append(b.subscribers, s)
+}
+
+// Publish delivers e to every subscriber and batches their commands
+func (b *EventBus) Publish(e Event) tea.Cmd {
+	var cmds []tea.Cmd
+	for _, s := range b.subscribers {
+		if cmd := s.HandleEvent(e); cmd != nil {
+			cmds = append(cmds, cmd)
+		}
+	}
+	return tea.Batch(cmds...)
+}
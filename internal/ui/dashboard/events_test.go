@@ -0,0 +1,85 @@
+package dashboard
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/nvandessel/go4dot/internal/config"
+	"github.com/nvandessel/go4dot/internal/platform"
+)
+
+// recordingSubscriber records the events it receives
+type recordingSubscriber struct {
+	name string
+	got  *[]string
+	cmd  tea.Cmd
+}
+
+func (r recordingSubscriber) HandleEvent(e Event) tea.Cmd {
+	*r.got = append(*r.got, r.name)
+	return r.cmd
+}
+
+func TestEventBus_Publish(t *testing.T) {
+	var got []string
+	var bus EventBus
+	bus.Subscribe(recordingSubscriber{name: "first", got: &got})
+	bus.Subscribe(recordingSubscriber{name: "second", got: &got, cmd: func() tea.Msg { return "done" }})
+
+	cmd := bus.Publish(ConfigsChangedEvent{})
+	if !reflect.DeepEqual(got, []string{"first", "second"}) {
+		t.Errorf("delivery order = %v, want [first second]", got)
+	}
+	if cmd == nil || cmd() != "done" {
+		t.Error("expected the subscriber's command to be returned")
+	}
+
+	var empty EventBus
+	if cmd := empty.Publish(ConfigsChangedEvent{}); cmd != nil {
+		t.Error("expected no command without subscribers")
+	}
+}
+
+func TestModel_PanelsFollowEvents(t *testing.T) {
+	m := New(State{
+		Platform:  &platform.Platform{OS: "linux"},
+		Configs:   []config.ConfigItem{{Name: "vim"}, {Name: "zsh"}},
+		HasConfig: true,
+	})
+	m.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+
+	// Selecting a config updates the summary count
+	m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'A'}})
+	if m.summaryPanel.selectedCount != 2 {
+		t.Errorf("summary selected count = %d, want 2", m.summaryPanel.selectedCount)
+	}
+
+	// Operations title and fill the output panel
+	m.bus.Publish(OperationStartedEvent{Type: OpSync})
+	if m.outputPanel.GetTitle() != getOperationTitle(OpSync) {
+		t.Errorf("output title = %q while syncing", m.outputPanel.GetTitle())
+	}
+	m.bus.Publish(OperationFinishedEvent{Type: OpSync, Err: errors.New("stow failed")})
+	logs := m.outputPanel.GetLogs()
+	if m.outputPanel.GetTitle() != "Output" || len(logs) != 1 || !strings.Contains(logs[0].Message, "stow failed") {
+		t.Errorf("output after failure: title %q, logs %v", m.outputPanel.GetTitle(), logs)
+	}
+
+	// A status update points every panel at the new config
+	cfg := &config.Config{
+		Configs:       config.ConfigGroups{Core: []config.ConfigItem{{Name: "nvim"}}},
+		MachineConfig: []config.MachinePrompt{{ID: "git"}},
+	}
+	if cmd := m.loadConfig(cfg, t.TempDir()); cmd == nil {
+		t.Error("expected the health and external panels to reload")
+	}
+	if m.healthPanel.cfg != cfg || m.externalPanel.cfg != cfg || !m.overridesPanel.HasOverrides() {
+		t.Error("expected the panels to use the new config")
+	}
+	if m.configsPanel.GetTotalCount() != 1 || m.summaryPanel.state.Config != cfg {
+		t.Error("expected the configs and summary panels to use the new state")
+	}
+}
@@ -98,6 +98,11 @@ func RunExternalSingleOperation(runner *OperationRunner, cfg *config.Config, dot
 
 	runner.StepComplete(0, StepSuccess, fmt.Sprintf("Status: %s", extStatus.Status))
 
+	if err := runner.Err(); err != nil {
+		result.Error = err
+		return result, err
+	}
+
 	// Step 1: Clone or update
 	if extStatus.Status == "installed" && opts.Update {
 		runner.Progress(1, fmt.Sprintf("Updating %s...", result.Name))
@@ -113,11 +118,9 @@ func RunExternalSingleOperation(runner *OperationRunner, cfg *config.Config, dot
 
 	// Perform the operation
 	extOpts := deps.ExternalOptions{
-		Update:   opts.Update,
-		RepoRoot: dotfilesPath,
-		ProgressFunc: func(current, total int, msg string) {
-			runner.Log("info", msg)
-		},
+		Update:    opts.Update,
+		RepoRoot:  dotfilesPath,
+		EventFunc: runner.LogEvent,
 	}
 
 	err = deps.CloneSingle(cfg, p, extID, extOpts)
@@ -139,3 +142,49 @@ func RunExternalSingleOperation(runner *OperationRunner, cfg *config.Config, dot
 
 	return result, nil
 }
+
+// RunExternalGroupOperation clones the missing externals among extIDs and
+// updates the ones already cloned, as one step
+func RunExternalGroupOperation(runner *OperationRunner, cfg *config.Config, dotfilesPath string, extIDs []string) (*deps.ExternalResult, error) {
+	p, err := platform.Detect()
+	if err != nil {
+		runner.StepComplete(0, StepError, err.Error())
+		return nil, fmt.Errorf("failed to detect platform: %w", err)
+	}
+
+	wanted := make(map[string]bool, len(extIDs))
+	for _, id := range extIDs {
+		wanted[id] = true
+	}
+	group := *cfg
+	group.External = nil
+	for _, ext := range cfg.External {
+		if wanted[ext.ID] {
+			group.External = append(group.External, ext)
+		}
+	}
+	if len(group.External) == 0 {
+		runner.StepComplete(0, StepSkipped, "Nothing to clone or update")
+		return &deps.ExternalResult{}, nil
+	}
+
+	runner.Progress(0, fmt.Sprintf("Cloning and updating %d external dependencies...", len(group.External)))
+
+	result, err := deps.CloneExternal(&group, p, deps.ExternalOptions{
+		Update:    true,
+		RepoRoot:  dotfilesPath,
+		EventFunc: runner.LogEvent,
+	})
+	if err != nil {
+		runner.StepComplete(0, StepError, err.Error())
+		return nil, err
+	}
+
+	summary := fmt.Sprintf("%d cloned, %d updated", len(result.Cloned), len(result.Updated))
+	if len(result.Failed) > 0 {
+		runner.StepComplete(0, StepWarning, fmt.Sprintf("%s, %d failed", summary, len(result.Failed)))
+		return result, fmt.Errorf("%d external dependencies failed", len(result.Failed))
+	}
+	runner.StepComplete(0, StepSuccess, summary)
+	return result, nil
+}
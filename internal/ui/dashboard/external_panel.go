@@ -1,7 +1,9 @@
 package dashboard
 
 import (
+	"context"
 	"fmt"
+	"strings"
 
 	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/spinner"
@@ -19,27 +21,88 @@ type externalStatusMsg struct {
 	err    error
 }
 
-// ExternalPanel displays external dependencies list with status
-// This is a navigable panel - Enter triggers clone/update
+// externalGitHubMsg is sent when GitHub metadata for the externals is loaded
+type externalGitHubMsg struct {
+	info  map[string]*deps.GitHubInfo // By external ID
+	hints map[string][]string         // By external ID
+	err   error
+}
+
+// externalRow is one line of the External panel: a group header or an
+// external dependency
+type externalRow struct {
+	header bool
+	group  string // Owning config of the group; empty for global externals
+	idx    int    // Index into status, for externals
+}
+
+// ExternalGroup is the externals referenced by one config, or the global
+// ones no config references
+type ExternalGroup struct {
+	Config  string // Empty for global externals
+	Members []deps.ExternalStatus
+}
+
+// Label returns the group's heading
+func (g ExternalGroup) Label() string {
+	if g.Config == "" {
+		return "Global"
+	}
+	return g.Config
+}
+
+// Counts returns how many members are missing, and how many are cloned
+func (g ExternalGroup) Counts() (missing, installed int) {
+	for _, s := range g.Members {
+		switch s.Status {
+		case "missing":
+			missing++
+		case "installed":
+			installed++
+		}
+	}
+	return missing, installed
+}
+
+// IDs returns the IDs of the members that can be cloned or updated
+func (g ExternalGroup) IDs() []string {
+	var ids []string
+	for _, s := range g.Members {
+		if s.Status == "missing" || s.Status == "installed" {
+			ids = append(ids, s.Dep.ID)
+		}
+	}
+	return ids
+}
+
+// ExternalPanel displays external dependencies list with status, grouped
+// under the configs that reference them when any do.
+// This is a navigable panel - Enter triggers clone/update of an external
+// or of every external in a group
 type ExternalPanel struct {
 	BasePanel
 	cfg          *config.Config
 	dotfilesPath string
 	platform     *platform.Platform
 
-	status     []deps.ExternalStatus
-	lastError  error
-	spinner    spinner.Model
-	loading    bool
+	status      []deps.ExternalStatus
+	rows        []externalRow
+	preset      []deps.ExternalStatus // Returned instead of scanning
+	lastError   error
+	spinner     spinner.Model
+	loading     bool
 	selectedIdx int
 	listOffset  int
+
+	github     bool // Look up externals hosted on GitHub (github preference)
+	githubInfo map[string]*deps.GitHubInfo
+	hints      map[string][]string
+	githubErr  error
 }
 
 // NewExternalPanel creates a new external dependencies panel
 func NewExternalPanel(cfg *config.Config, dotfilesPath string, plat *platform.Platform) *ExternalPanel {
-	s := spinner.New()
-	s.Spinner = spinner.Dot
-	s.Style = lipgloss.NewStyle().Foreground(ui.PrimaryColor)
+	s := ui.NewSpinner()
 
 	return &ExternalPanel{
 		BasePanel:    NewBasePanel(PanelExternal, "4 External"),
@@ -60,6 +123,9 @@ func (p *ExternalPanel) Init() tea.Cmd {
 }
 
 func (p *ExternalPanel) loadStatus() tea.Msg {
+	if p.preset != nil {
+		return externalStatusMsg{status: p.preset}
+	}
 	if p.cfg == nil {
 		return externalStatusMsg{status: nil, err: nil}
 	}
@@ -67,6 +133,21 @@ func (p *ExternalPanel) loadStatus() tea.Msg {
 	return externalStatusMsg{status: status}
 }
 
+// loadGitHub returns a command fetching GitHub metadata for status. Hints
+// are worked out here too, as they read each checkout's HEAD.
+func loadGitHub(status []deps.ExternalStatus) tea.Cmd {
+	return func() tea.Msg {
+		info, err := deps.FetchGitHubInfo(context.Background(), status)
+		hints := make(map[string][]string)
+		for _, s := range status {
+			if h := deps.GitHubHints(info[s.Dep.ID], s); len(h) > 0 {
+				hints[s.Dep.ID] = h
+			}
+		}
+		return externalGitHubMsg{info: info, hints: hints, err: err}
+	}
+}
+
 // Update implements Panel interface
 func (p *ExternalPanel) Update(msg tea.Msg) tea.Cmd {
 	var cmds []tea.Cmd
@@ -98,24 +179,93 @@ func (p *ExternalPanel) Update(msg tea.Msg) tea.Cmd {
 		} else {
 			p.lastError = nil
 			p.status = msg.status
+			p.rows = groupExternals(p.cfg, p.status)
 			// Clamp selection if results shrunk
-			if len(p.status) > 0 {
-				if p.selectedIdx >= len(p.status) {
-					p.selectedIdx = len(p.status) - 1
+			if len(p.rows) > 0 {
+				if p.selectedIdx >= len(p.rows) {
+					p.selectedIdx = len(p.rows) - 1
 				}
 				p.ensureVisible()
 			} else {
 				p.selectedIdx = 0
 				p.listOffset = 0
 			}
+			if p.github && len(p.status) > 0 {
+				cmds = append(cmds, loadGitHub(p.status))
+			}
 		}
+
+	case externalGitHubMsg:
+		p.githubInfo = msg.info
+		p.hints = msg.hints
+		p.githubErr = msg.err
 	}
 
 	return tea.Batch(cmds...)
 }
 
+// groupExternals lays the externals out under the first config that
+// references each one, then the global ones. Without any config
+// referencing an external the list stays flat.
+func groupExternals(cfg *config.Config, status []deps.ExternalStatus) []externalRow {
+	owners := make(map[string]string)
+	var order []string
+	if cfg != nil {
+		for _, c := range cfg.GetAllConfigs() {
+			for _, ext := range c.ExternalDeps {
+				if _, ok := owners[ext.ID]; !ok {
+					owners[ext.ID] = c.Name
+				}
+			}
+			order = append(order, c.Name)
+		}
+	}
+
+	grouped := false
+	for _, s := range status {
+		if owners[s.Dep.ID] != "" {
+			grouped = true
+			break
+		}
+	}
+
+	var rows []externalRow
+	if !grouped {
+		for i := range status {
+			rows = append(rows, externalRow{idx: i})
+		}
+		return rows
+	}
+
+	for _, group := range append(order, "") {
+		header := false
+		for i, s := range status {
+			if owners[s.Dep.ID] != group {
+				continue
+			}
+			if !header {
+				rows = append(rows, externalRow{header: true, group: group})
+				header = true
+			}
+			rows = append(rows, externalRow{group: group, idx: i})
+		}
+	}
+	return rows
+}
+
+// group returns the group named by a header row
+func (p *ExternalPanel) group(name string) ExternalGroup {
+	g := ExternalGroup{Config: name}
+	for _, r := range p.rows {
+		if !r.header && r.group == name {
+			g.Members = append(g.Members, p.status[r.idx])
+		}
+	}
+	return g
+}
+
 func (p *ExternalPanel) moveDown() {
-	maxIdx := len(p.status) - 1
+	maxIdx := len(p.rows) - 1
 	if p.selectedIdx < maxIdx {
 		p.selectedIdx++
 		p.ensureVisible()
@@ -186,9 +336,7 @@ func (p *ExternalPanel) View() string {
 		lines = append(lines, lipgloss.JoinHorizontal(lipgloss.Left, summaryParts...))
 	}
 
-	okStyle := lipgloss.NewStyle().Foreground(ui.SecondaryColor)
 	warnStyle := lipgloss.NewStyle().Foreground(ui.WarningColor)
-	skipStyle := ui.SubtleStyle
 
 	visibleHeight := p.ContentHeight() - 1 // Account for summary
 	if visibleHeight < 1 {
@@ -196,24 +344,24 @@ func (p *ExternalPanel) View() string {
 	}
 
 	endIdx := p.listOffset + visibleHeight
-	if endIdx > len(p.status) {
-		endIdx = len(p.status)
+	if endIdx > len(p.rows) {
+		endIdx = len(p.rows)
 	}
 
+	grouped := len(p.rows) > 0 && p.rows[0].header
 	for i := p.listOffset; i < endIdx; i++ {
-		s := p.status[i]
-
-		var icon string
-		switch s.Status {
-		case "installed":
-			icon = okStyle.Render("✓")
-		case "missing":
-			icon = warnStyle.Render("○")
-		case "skipped":
-			icon = skipStyle.Render("⊘")
-		default:
-			icon = skipStyle.Render("?")
+		row := p.rows[i]
+		if row.header {
+			line := p.renderGroupHeader(p.group(row.group))
+			if i == p.selectedIdx && p.focused {
+				line = ui.SelectedItemStyle.Width(p.ContentWidth()).Render(line)
+			}
+			lines = append(lines, line)
+			continue
 		}
+		s := p.status[row.idx]
+
+		icon := externalStatusIcon(s.Status)
 
 		// Get name from dep
 		name := s.Dep.Name
@@ -221,8 +369,26 @@ func (p *ExternalPanel) View() string {
 			name = s.Dep.ID
 		}
 
+		// Flag externals with GitHub hints: archived or behind a release
+		marker := ""
+		if info := p.githubInfo[s.Dep.ID]; info != nil && len(p.hints[s.Dep.ID]) > 0 {
+			if info.Archived {
+				marker = " " + warnStyle.Render("⚑")
+			} else {
+				marker = " " + warnStyle.Render("↑")
+			}
+		}
+
+		indent := ""
+		if grouped {
+			indent = "  "
+		}
+
 		// Truncate name to fit
-		maxLen := p.ContentWidth() - 4
+		maxLen := p.ContentWidth() - 4 - len(indent)
+		if marker != "" {
+			maxLen -= 2
+		}
 		if maxLen < 5 {
 			maxLen = 5
 		}
@@ -230,7 +396,7 @@ func (p *ExternalPanel) View() string {
 			name = name[:maxLen-1] + "…"
 		}
 
-		line := fmt.Sprintf("%s %s", icon, name)
+		line := fmt.Sprintf("%s%s %s%s", indent, icon, name, marker)
 
 		if i == p.selectedIdx && p.focused {
 			line = ui.SelectedItemStyle.Width(p.ContentWidth()).Render(line)
@@ -242,12 +408,48 @@ func (p *ExternalPanel) View() string {
 	return lipgloss.JoinVertical(lipgloss.Left, lines...)
 }
 
-// GetSelectedItem implements Panel interface
+// externalStatusIcon returns the icon of an external's status
+func externalStatusIcon(status string) string {
+	switch status {
+	case "installed":
+		return lipgloss.NewStyle().Foreground(ui.SecondaryColor).Render("✓")
+	case "missing":
+		return lipgloss.NewStyle().Foreground(ui.WarningColor).Render("○")
+	case "skipped":
+		return ui.SubtleStyle.Render("⊘")
+	default:
+		return ui.SubtleStyle.Render("?")
+	}
+}
+
+// renderGroupHeader renders a group's heading with what Enter does to it:
+// how many members it clones and how many it updates
+func (p *ExternalPanel) renderGroupHeader(g ExternalGroup) string {
+	header := lipgloss.NewStyle().Foreground(ui.TextColor).Bold(true).Render(g.Label())
+	missing, installed := g.Counts()
+	var actions []string
+	if missing > 0 {
+		actions = append(actions, ui.WarningStyle.Render(fmt.Sprintf("clone %d", missing)))
+	}
+	if installed > 0 {
+		actions = append(actions, ui.SubtleStyle.Render(fmt.Sprintf("update %d", installed)))
+	}
+	if len(actions) > 0 {
+		header += " " + strings.Join(actions, ui.SubtleStyle.Render(", "))
+	}
+	return header
+}
+
+// GetSelectedItem implements Panel interface. A group header has no ID.
 func (p *ExternalPanel) GetSelectedItem() *SelectedItem {
-	if len(p.status) == 0 || p.selectedIdx >= len(p.status) {
+	if p.selectedIdx >= len(p.rows) {
 		return nil
 	}
-	s := p.status[p.selectedIdx]
+	row := p.rows[p.selectedIdx]
+	if row.header {
+		return &SelectedItem{Name: p.group(row.group).Label(), Index: p.selectedIdx}
+	}
+	s := p.status[row.idx]
 	name := s.Dep.Name
 	if name == "" {
 		name = s.Dep.ID
@@ -259,12 +461,32 @@ func (p *ExternalPanel) GetSelectedItem() *SelectedItem {
 	}
 }
 
-// GetSelectedExternal returns the currently selected external dep status
+// GetSelectedExternal returns the currently selected external dep status,
+// or nil when a group header is selected
 func (p *ExternalPanel) GetSelectedExternal() *deps.ExternalStatus {
-	if len(p.status) == 0 || p.selectedIdx >= len(p.status) {
+	if p.selectedIdx >= len(p.rows) || p.rows[p.selectedIdx].header {
 		return nil
 	}
-	return &p.status[p.selectedIdx]
+	return &p.status[p.rows[p.selectedIdx].idx]
+}
+
+// GetSelectedGroup returns the group whose header is selected
+func (p *ExternalPanel) GetSelectedGroup() (ExternalGroup, bool) {
+	if p.selectedIdx >= len(p.rows) || !p.rows[p.selectedIdx].header {
+		return ExternalGroup{}, false
+	}
+	return p.group(p.rows[p.selectedIdx].group), true
+}
+
+// GetGitHubInfo returns the GitHub metadata and hints for an external, or
+// nil when none was fetched
+func (p *ExternalPanel) GetGitHubInfo(id string) (*deps.GitHubInfo, []string) {
+	return p.githubInfo[id], p.hints[id]
+}
+
+// GitHubError returns why GitHub metadata could not be (fully) fetched
+func (p *ExternalPanel) GitHubError() error {
+	return p.githubErr
 }
 
 // GetStatus returns all external dependency statuses
@@ -287,6 +509,26 @@ func (p *ExternalPanel) Refresh() tea.Cmd {
 	)
 }
 
+// HandleEvent implements Subscriber. The status is reloaded when the config
+// changes and after a single external dependency, or a group of them, was
+// cloned or updated.
+func (p *ExternalPanel) HandleEvent(e Event) tea.Cmd {
+	switch e := e.(type) {
+	case StatusUpdatedEvent:
+		p.cfg = e.State.Config
+		p.dotfilesPath = e.State.DotfilesPath
+		p.platform = e.State.Platform
+		p.preset = e.State.ExternalStatus
+		p.github = e.State.Preferences.GitHubMetadata()
+		return p.Refresh()
+	case OperationFinishedEvent:
+		if e.Type == OpExternal || (e.Type == OpExternalSingle && e.Err == nil) {
+			return p.Refresh()
+		}
+	}
+	return nil
+}
+
 // HasExternals returns true if there are any external dependencies
 func (p *ExternalPanel) HasExternals() bool {
 	return len(p.status) > 0
@@ -0,0 +1,79 @@
+package dashboard
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/nvandessel/go4dot/internal/config"
+	"github.com/nvandessel/go4dot/internal/deps"
+)
+
+func groupTestPanel() *ExternalPanel {
+	tpm := config.ExternalDep{ID: "tpm", Name: "tpm"}
+	fzf := config.ExternalDep{ID: "fzf", Name: "fzf"}
+	fonts := config.ExternalDep{ID: "fonts", Name: "fonts"}
+	cfg := &config.Config{
+		Configs: config.ConfigGroups{
+			Core: []config.ConfigItem{
+				{Name: "zsh", ExternalDeps: []config.ExternalDep{fzf}},
+				{Name: "tmux", ExternalDeps: []config.ExternalDep{tpm, fzf}},
+			},
+		},
+		External: []config.ExternalDep{tpm, fzf, fonts},
+	}
+
+	p := NewExternalPanel(cfg, "", nil)
+	p.SetSize(40, 20)
+	p.SetFocused(true)
+	p.Update(externalStatusMsg{status: []deps.ExternalStatus{
+		{Dep: tpm, Status: "missing"},
+		{Dep: fzf, Status: "installed"},
+		{Dep: fonts, Status: "missing"},
+	}})
+	return p
+}
+
+func TestExternalPanel_Groups(t *testing.T) {
+	p := groupTestPanel()
+
+	// fzf is listed once, under the first config referencing it
+	var labels []string
+	for i := range p.rows {
+		p.selectedIdx = i
+		labels = append(labels, p.GetSelectedItem().Name)
+	}
+	want := []string{"zsh", "fzf", "tmux", "tpm", "Global", "fonts"}
+	if !reflect.DeepEqual(labels, want) {
+		t.Errorf("rows = %v, want %v", labels, want)
+	}
+
+	p.selectedIdx = 2
+	group, ok := p.GetSelectedGroup()
+	if !ok || group.Config != "tmux" {
+		t.Fatalf("GetSelectedGroup() = %v, %v", group, ok)
+	}
+	if p.GetSelectedExternal() != nil {
+		t.Error("a group header is not an external")
+	}
+	if ids := group.IDs(); !reflect.DeepEqual(ids, []string{"tpm"}) {
+		t.Errorf("IDs() = %v", ids)
+	}
+	if !strings.Contains(p.View(), "clone 1") {
+		t.Errorf("expected the group's actions in the header, got:\n%s", p.View())
+	}
+}
+
+func TestExternalPanel_Flat(t *testing.T) {
+	p := NewExternalPanel(&config.Config{}, "", nil)
+	p.Update(externalStatusMsg{status: []deps.ExternalStatus{
+		{Dep: config.ExternalDep{ID: "tpm"}, Status: "missing"},
+	}})
+
+	if _, ok := p.GetSelectedGroup(); ok {
+		t.Error("without configs referencing externals there are no groups")
+	}
+	if ext := p.GetSelectedExternal(); ext == nil || ext.Dep.ID != "tpm" {
+		t.Errorf("GetSelectedExternal() = %v", ext)
+	}
+}
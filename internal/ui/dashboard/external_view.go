@@ -2,7 +2,9 @@ package dashboard
 
 import (
 	"fmt"
+	"sort"
 	"strings"
+	"time"
 
 	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/spinner"
@@ -24,6 +26,13 @@ type externalStatusLoadedMsg struct {
 	err    error
 }
 
+// externalDetailsLoadedMsg is sent when details for one dependency are loaded
+type externalDetailsLoadedMsg struct {
+	id      string
+	details *deps.ExternalDetails
+	err     error
+}
+
 // ExternalView displays external dependencies management
 type ExternalView struct {
 	cfg          *config.Config
@@ -39,6 +48,12 @@ type ExternalView struct {
 	ready     bool
 	loading   bool
 	selected  int
+
+	// Details mode shows one dependency with its README
+	showDetails    bool
+	loadingDetails bool
+	details        *deps.ExternalDetails
+	detailsErr     error
 }
 
 // NewExternalView creates a new external dependencies view
@@ -46,9 +61,7 @@ func NewExternalView(cfg *config.Config, dotfilesPath string, plat *platform.Pla
 	vp := viewport.New(0, 0)
 	vp.Style = lipgloss.NewStyle()
 
-	s := spinner.New()
-	s.Spinner = spinner.Dot
-	s.Style = lipgloss.NewStyle().Foreground(ui.PrimaryColor)
+	s := ui.NewSpinner()
 
 	return &ExternalView{
 		cfg:          cfg,
@@ -98,8 +111,28 @@ func (e *ExternalView) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
 		switch {
+		case e.showDetails && key.Matches(msg, key.NewBinding(key.WithKeys("esc", "backspace", "h"))):
+			// Back to the list
+			e.showDetails = false
+			e.updateContent()
+			e.viewport.GotoTop()
 		case key.Matches(msg, key.NewBinding(key.WithKeys("esc", "q"))):
 			return e, func() tea.Msg { return ExternalViewCloseMsg{} }
+		case e.showDetails:
+			// Scroll the details
+			var cmd tea.Cmd
+			e.viewport, cmd = e.viewport.Update(msg)
+			cmds = append(cmds, cmd)
+		case key.Matches(msg, key.NewBinding(key.WithKeys("enter", "l"))):
+			if e.selected < len(e.status) {
+				e.showDetails = true
+				e.loadingDetails = true
+				e.details = nil
+				e.detailsErr = nil
+				e.updateContent()
+				e.viewport.GotoTop()
+				cmds = append(cmds, e.loadDetails(e.status[e.selected]))
+			}
 		case key.Matches(msg, key.NewBinding(key.WithKeys("j", "down"))):
 			if e.selected < len(e.status)-1 {
 				e.selected++
@@ -128,6 +161,14 @@ func (e *ExternalView) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 		e.updateContent()
 
+	case externalDetailsLoadedMsg:
+		if e.showDetails && e.selected < len(e.status) && e.status[e.selected].Dep.ID == msg.id {
+			e.loadingDetails = false
+			e.details = msg.details
+			e.detailsErr = msg.err
+			e.updateContent()
+		}
+
 	case tea.MouseMsg:
 		var cmd tea.Cmd
 		e.viewport, cmd = e.viewport.Update(msg)
@@ -174,7 +215,7 @@ func (e *ExternalView) View() string {
 			"",
 			e.viewport.View(),
 			"",
-			hintStyle.Render("↑/↓ Navigate • ESC Close"),
+			hintStyle.Render(e.hints()),
 		)
 	}
 
@@ -189,7 +230,136 @@ func (e *ExternalView) View() string {
 	)
 }
 
+func (e *ExternalView) loadDetails(s deps.ExternalStatus) tea.Cmd {
+	return func() tea.Msg {
+		details, err := deps.GetExternalDetails(s)
+		return externalDetailsLoadedMsg{id: s.Dep.ID, details: details, err: err}
+	}
+}
+
+// hints returns the key hints for the current mode
+func (e *ExternalView) hints() string {
+	if e.showDetails {
+		return "↑/↓ Scroll • ESC Back"
+	}
+	return "↑/↓ Navigate • Enter Details • ESC Close"
+}
+
+// detailsContent renders the details of the selected dependency
+func (e *ExternalView) detailsContent() string {
+	s := e.status[e.selected]
+
+	labelStyle := lipgloss.NewStyle().Foreground(ui.SubtleColor).Width(11)
+	nameStyle := lipgloss.NewStyle().Foreground(ui.TextColor).Bold(true)
+	okStyle := lipgloss.NewStyle().Foreground(ui.SecondaryColor)
+	errStyle := lipgloss.NewStyle().Foreground(ui.ErrorColor)
+	sectionStyle := lipgloss.NewStyle().Foreground(ui.PrimaryColor).Bold(true)
+	subtleStyle := lipgloss.NewStyle().Foreground(ui.SubtleColor)
+
+	name := s.Dep.Name
+	if name == "" {
+		name = s.Dep.ID
+	}
+
+	row := func(label, value string) string {
+		return labelStyle.Render(label) + value
+	}
+
+	lines := []string{nameStyle.Render(name), ""}
+	lines = append(lines, row("URL", s.Dep.URL))
+	dest := s.Path
+	if dest == "" {
+		dest = s.Dep.Destination
+	}
+	lines = append(lines, row("Destination", dest))
+	status := s.Status
+	if s.Reason != "" {
+		status += " (" + s.Reason + ")"
+	}
+	lines = append(lines, row("Status", status))
+
+	// Condition evaluation, one line per key
+	if len(s.Dep.Condition) == 0 {
+		lines = append(lines, row("Condition", subtleStyle.Render("none (always)")))
+	} else {
+		keys := make([]string, 0, len(s.Dep.Condition))
+		for k := range s.Dep.Condition {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for i, k := range keys {
+			label := ""
+			if i == 0 {
+				label = "Condition"
+			}
+			mark := subtleStyle.Render("?")
+			if e.platform != nil {
+				if platform.CheckCondition(map[string]string{k: s.Dep.Condition[k]}, e.platform) {
+					mark = okStyle.Render("✓")
+				} else {
+					mark = errStyle.Render("✗")
+				}
+			}
+			lines = append(lines, row(label, fmt.Sprintf("%s %s=%s", mark, k, s.Dep.Condition[k])))
+		}
+	}
+
+	if e.loadingDetails {
+		lines = append(lines, "", e.spinner.View()+" Reading checkout...")
+		return strings.Join(lines, "\n")
+	}
+	if e.detailsErr != nil {
+		lines = append(lines, "", errStyle.Render(fmt.Sprintf("Error: %v", e.detailsErr)))
+		return strings.Join(lines, "\n")
+	}
+
+	d := e.details
+	if d == nil {
+		return strings.Join(lines, "\n")
+	}
+
+	if d.Commit != "" {
+		commit := d.Commit
+		if !d.CommitDate.IsZero() {
+			commit += subtleStyle.Render(" (" + d.CommitDate.Format("2006-01-02") + ")")
+		}
+		lines = append(lines, row("Commit", commit))
+	}
+	if !d.UpdatedAt.IsZero() {
+		lines = append(lines, row("Updated", d.UpdatedAt.Format("2006-01-02 15:04")+subtleStyle.Render(" ("+formatAge(d.UpdatedAt)+")")))
+	}
+
+	lines = append(lines, "", sectionStyle.Render("README"))
+	if d.ReadmePath == "" {
+		lines = append(lines, subtleStyle.Render("No README found"))
+	} else {
+		lines = append(lines, renderMarkdownPreview(d.Readme, e.viewport.Width))
+		if d.Truncated {
+			lines = append(lines, subtleStyle.Render("… (truncated)"))
+		}
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// formatAge renders a coarse "N units ago" for a timestamp
+func formatAge(t time.Time) string {
+	d := time.Since(t)
+	switch {
+	case d < time.Hour:
+		return "just now"
+	case d < 24*time.Hour:
+		return fmt.Sprintf("%dh ago", int(d.Hours()))
+	default:
+		return fmt.Sprintf("%dd ago", int(d.Hours()/24))
+	}
+}
+
 func (e *ExternalView) updateContent() {
+	if e.showDetails && e.selected < len(e.status) {
+		e.viewport.SetContent(e.detailsContent())
+		return
+	}
 	if e.lastError != nil {
 		e.viewport.SetContent(fmt.Sprintf("Error loading status: %v", e.lastError))
 		return
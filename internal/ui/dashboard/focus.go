@@ -1,5 +1,10 @@
 package dashboard
 
+import (
+	"fmt"
+	"strings"
+)
+
 // PanelID identifies each panel in the dashboard layout
 type PanelID int
 
@@ -38,6 +43,17 @@ func (p PanelID) String() string {
 	}
 }
 
+// ParsePanel returns the panel with the given name, case-insensitively,
+// e.g. "health" or "External"
+func ParsePanel(name string) (PanelID, error) {
+	for id := PanelID(0); id < panelCount; id++ {
+		if strings.EqualFold(name, id.String()) {
+			return id, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown panel %q (want summary, health, overrides, external, configs, details or output)", name)
+}
+
 // IsNavigable returns true if the panel supports list navigation
 func (p PanelID) IsNavigable() bool {
 	switch p {
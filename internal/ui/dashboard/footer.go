@@ -15,6 +15,11 @@ type Footer struct {
 	focusedPanel PanelID
 	platform     *platform.Platform
 	updateMsg    string
+	demo         bool
+	readOnly     bool
+	dryRun       bool
+	recording    bool
+	profile      string
 }
 
 // NewFooter creates a new footer component.
@@ -34,6 +39,32 @@ func (f *Footer) SetUpdateMsg(msg string) {
 	f.updateMsg = msg
 }
 
+// SetDemo marks the dashboard as running on demo data
+func (f *Footer) SetDemo(demo bool) {
+	f.demo = demo
+}
+
+// SetReadOnly greys out the actions that would change the machine and
+// shows the read-only indicator
+func (f *Footer) SetReadOnly(readOnly bool) {
+	f.readOnly = readOnly
+}
+
+// SetDryRun shows or hides the dry-run mode indicator
+func (f *Footer) SetDryRun(dryRun bool) {
+	f.dryRun = dryRun
+}
+
+// SetProfile shows the active profile, or nothing when every config is used
+func (f *Footer) SetProfile(name string) {
+	f.profile = name
+}
+
+// SetRecording shows or hides the macro recording indicator
+func (f *Footer) SetRecording(recording bool) {
+	f.recording = recording
+}
+
 // SetFocusedPanel updates which panel is focused for context-sensitive hints
 func (f *Footer) SetFocusedPanel(panel PanelID) {
 	f.focusedPanel = panel
@@ -47,55 +78,67 @@ func (f Footer) View() string {
 	type action struct {
 		key      string
 		label    string
-		priority int // Lower is higher priority
+		priority int  // Lower is higher priority
+		mutating bool // Disabled in read-only mode
 	}
 
 	// Base actions always shown
 	allActions := []action{
-		{"?", "Help", 0},
-		{"q", "Quit", 0},
-		{"tab", "Panel", 1},
+		{"?", "Help", 0, false},
+		{"q", "Quit", 0, false},
+		{"tab", "Panel", 1, false},
 	}
 
 	// Context-sensitive actions based on focused panel
 	switch f.focusedPanel {
 	case PanelConfigs:
 		allActions = append(allActions,
-			action{"enter", "Sync", 1},
-			action{"space", "Select", 2},
-			action{"/", "Filter", 2},
-			action{"s", "Sync All", 3},
+			action{"enter", "Sync", 1, true},
+			action{"space", "Select", 2, false},
+			action{"/", "Filter", 2, false},
+			action{"s", "Sync All", 3, true},
+			action{"l", "Link All", 3, true},
+			action{"D", "Readme", 3, false},
+			action{"a", "Archive", 3, true},
+			action{"E", "Edit", 3, true},
 		)
 	case PanelHealth:
 		allActions = append(allActions,
-			action{"enter", "Refresh", 1},
-			action{"↑↓", "Navigate", 2},
+			action{"enter", "Refresh", 1, false},
+			action{"↑↓", "Navigate", 2, false},
+			action{"U", "Upgrade Outdated", 3, true},
 		)
 	case PanelOverrides:
 		allActions = append(allActions,
-			action{"enter", "Configure", 1},
-			action{"↑↓", "Navigate", 2},
+			action{"enter", "Configure", 1, true},
+			action{"↑↓", "Navigate", 2, false},
 		)
 	case PanelExternal:
 		allActions = append(allActions,
-			action{"enter", "Clone/Update", 1},
-			action{"↑↓", "Navigate", 2},
+			action{"enter", "Clone/Update", 1, true},
+			action{"↑↓", "Navigate", 2, false},
+		)
+	case PanelDetails:
+		allActions = append(allActions,
+			action{"enter", "Relink File", 1, true},
+			action{"↑↓", "Scroll", 2, false},
+			action{"E", "Edit", 3, true},
 		)
-	case PanelDetails, PanelOutput:
+	case PanelOutput:
 		allActions = append(allActions,
-			action{"↑↓", "Scroll", 2},
+			action{"↑↓", "Scroll", 2, false},
 		)
 	default:
 		allActions = append(allActions,
-			action{"s", "Sync", 2},
-			action{"i", "Install", 3},
+			action{"s", "Sync", 2, true},
+			action{"i", "Install", 3, true},
 		)
 	}
 
 	// Global shortcuts at lower priority
 	allActions = append(allActions,
-		action{"0-6", "Jump", 4},
-		action{"ctrl+hjkl", "Move", 5},
+		action{"0-6", "Jump", 4, false},
+		action{"ctrl+hjkl", "Move", 5, false},
 	)
 
 	// Build header info for right side
@@ -110,6 +153,41 @@ func (f Footer) View() string {
 		MarginLeft(1)
 
 	headerInfo := titleStyle.Render("GO4DOT DASHBOARD")
+	if f.demo {
+		headerInfo = titleStyle.Render("GO4DOT DEMO")
+	}
+
+	if f.readOnly {
+		readOnlyStyle := lipgloss.NewStyle().
+			Foreground(ui.WarningColor).
+			Bold(true).
+			MarginLeft(1)
+		headerInfo += readOnlyStyle.Render("READ-ONLY")
+	}
+
+	if f.dryRun {
+		dryRunStyle := lipgloss.NewStyle().
+			Foreground(ui.WarningColor).
+			Bold(true).
+			MarginLeft(1)
+		headerInfo += dryRunStyle.Render("DRY-RUN")
+	}
+
+	if f.recording {
+		recStyle := lipgloss.NewStyle().
+			Foreground(ui.ErrorColor).
+			Bold(true).
+			MarginLeft(1)
+		headerInfo += recStyle.Render("● REC")
+	}
+
+	if f.profile != "" {
+		profileStyle := lipgloss.NewStyle().
+			Foreground(ui.SecondaryColor).
+			Bold(true).
+			MarginLeft(1)
+		headerInfo += profileStyle.Render("[" + f.profile + "]")
+	}
 
 	if f.platform != nil {
 		platformInfo := f.platform.OS
@@ -137,7 +215,11 @@ func (f Footer) View() string {
 	margin := 3
 
 	for _, a := range allActions {
-		rendered := keyStyle.Render("["+a.key+"]") + " " + descStyle.Render(a.label)
+		style := keyStyle
+		if a.mutating && f.readOnly {
+			style = descStyle
+		}
+		rendered := style.Render("["+a.key+"]") + " " + descStyle.Render(a.label)
 		width := lipgloss.Width(rendered)
 
 		if currentWidth+width+margin > availableWidth && len(visibleActions) > 0 {
@@ -3,12 +3,14 @@ package dashboard
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/spinner"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/nvandessel/go4dot/internal/config"
+	"github.com/nvandessel/go4dot/internal/deps"
 	"github.com/nvandessel/go4dot/internal/doctor"
 	"github.com/nvandessel/go4dot/internal/ui"
 )
@@ -28,6 +30,7 @@ const healthSummaryLines = 2
 // healthResultMsg is sent when doctor checks complete
 type healthResultMsg struct {
 	result *doctor.CheckResult
+	trend  doctor.Trend // Compared with the previously recorded run
 	err    error
 }
 
@@ -39,6 +42,8 @@ type HealthPanel struct {
 	dotfilesPath string
 
 	result      *doctor.CheckResult
+	trend       doctor.Trend
+	preset      *doctor.CheckResult // Returned instead of running checks
 	lastError   error
 	spinner     spinner.Model
 	loading     bool
@@ -48,9 +53,7 @@ type HealthPanel struct {
 
 // NewHealthPanel creates a new health panel
 func NewHealthPanel(cfg *config.Config, dotfilesPath string) *HealthPanel {
-	s := spinner.New()
-	s.Spinner = spinner.Dot
-	s.Style = lipgloss.NewStyle().Foreground(ui.PrimaryColor)
+	s := ui.NewSpinner()
 
 	return &HealthPanel{
 		BasePanel:    NewBasePanel(PanelHealth, "2 Health"),
@@ -70,6 +73,9 @@ func (p *HealthPanel) Init() tea.Cmd {
 }
 
 func (p *HealthPanel) runChecks() tea.Msg {
+	if p.preset != nil {
+		return healthResultMsg{result: p.preset}
+	}
 	if p.cfg == nil {
 		return healthResultMsg{result: nil, err: fmt.Errorf("no config")}
 	}
@@ -80,7 +86,11 @@ func (p *HealthPanel) runChecks() tea.Msg {
 	if err != nil {
 		return healthResultMsg{result: nil, err: fmt.Errorf("health checks failed: %w", err)}
 	}
-	return healthResultMsg{result: result, err: nil}
+
+	// Failing to record the run only loses the trend
+	summary := doctor.NewSummary(result)
+	previous, _ := doctor.RecordRun(summary)
+	return healthResultMsg{result: result, trend: doctor.CompareRuns(previous, summary), err: nil}
 }
 
 // Update implements Panel interface
@@ -114,6 +124,7 @@ func (p *HealthPanel) Update(msg tea.Msg) tea.Cmd {
 		} else {
 			p.lastError = nil
 			p.result = msg.result
+			p.trend = msg.trend
 			// Clamp selection if results shrunk
 			if p.result != nil && len(p.result.Checks) > 0 {
 				if p.selectedIdx >= len(p.result.Checks) {
@@ -253,9 +264,34 @@ func (p *HealthPanel) renderSummary() string {
 		return ui.SubtleStyle.Render("No checks")
 	}
 
+	switch p.trend {
+	case doctor.TrendImproved:
+		parts = append(parts, ui.SuccessStyle.Render("improved"))
+	case doctor.TrendWorsened:
+		parts = append(parts, ui.WarningStyle.Render("worsened"))
+	}
+
+	// Dependency versions may come from the cache; say how old they are
+	if deps := p.result.DepsResult; deps != nil && !deps.CachedAt.IsZero() {
+		parts = append(parts, ui.SubtleStyle.Render("deps "+formatCacheAge(deps.CachedAt)))
+	}
+
 	return strings.Join(parts, "  ")
 }
 
+// formatCacheAge renders the age of cached results to the minute, e.g.
+// "cached 4m ago"
+func formatCacheAge(t time.Time) string {
+	d := time.Since(t)
+	if d < time.Minute {
+		return "cached <1m ago"
+	}
+	if d < time.Hour {
+		return fmt.Sprintf("cached %dm ago", int(d.Minutes()))
+	}
+	return "cached " + formatAge(t)
+}
+
 // renderCheckItems builds the visible slice of check items, including scroll
 // indicators when the list overflows above or below the visible area.
 func (p *HealthPanel) renderCheckItems() []string {
@@ -313,9 +349,17 @@ func (p *HealthPanel) renderCheckItems() []string {
 			icon = skipStyle.Render(iconSkipped)
 		}
 
+		// Flag the dependencies check when some are outdated
+		marker := ""
+		if check.ID == "dependencies" {
+			if n := len(p.Outdated()); n > 0 {
+				marker = " " + warnStyle.Render(fmt.Sprintf("↑%d", n))
+			}
+		}
+
 		// Truncate name to fit (icon + space + name)
 		name := check.Name
-		maxLen := p.ContentWidth() - 6 // icon width (4) + space (1) + margin (1)
+		maxLen := p.ContentWidth() - 6 - lipgloss.Width(marker) // icon width (4) + space (1) + margin (1)
 		if maxLen < 5 {
 			maxLen = 5
 		}
@@ -323,7 +367,7 @@ func (p *HealthPanel) renderCheckItems() []string {
 			name = name[:maxLen-3] + "..."
 		}
 
-		line := fmt.Sprintf("%s %s", icon, name)
+		line := fmt.Sprintf("%s %s%s", icon, name, marker)
 
 		if i == p.selectedIdx && p.focused {
 			line = ui.SelectedItemStyle.Width(p.ContentWidth()).Render(line)
@@ -366,6 +410,15 @@ func (p *HealthPanel) GetSelectedCheck() *doctor.Check {
 	return &p.result.Checks[p.selectedIdx]
 }
 
+// Outdated returns the dependencies installed at too old a version, which
+// U upgrades
+func (p *HealthPanel) Outdated() []deps.DependencyCheck {
+	if p.result == nil || p.result.DepsResult == nil {
+		return nil
+	}
+	return p.result.DepsResult.GetOutdated()
+}
+
 // GetResult returns the full health check result
 func (p *HealthPanel) GetResult() *doctor.CheckResult {
 	return p.result
@@ -385,3 +438,21 @@ func (p *HealthPanel) Refresh() tea.Cmd {
 		p.runChecks,
 	)
 }
+
+// HandleEvent implements Subscriber. A status update re-runs the checks
+// against the new config, as does upgrading dependencies.
+func (p *HealthPanel) HandleEvent(e Event) tea.Cmd {
+	switch e := e.(type) {
+	case StatusUpdatedEvent:
+		p.cfg = e.State.Config
+		p.dotfilesPath = e.State.DotfilesPath
+		p.preset = e.State.HealthResult
+		return p.Refresh()
+	case OperationFinishedEvent:
+		if e.Type == OpUpgradeDeps {
+			p.preset = nil
+			return p.Refresh()
+		}
+	}
+	return nil
+}
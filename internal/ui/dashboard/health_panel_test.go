@@ -3,7 +3,10 @@ package dashboard
 import (
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/nvandessel/go4dot/internal/config"
+	"github.com/nvandessel/go4dot/internal/deps"
 	"github.com/nvandessel/go4dot/internal/doctor"
 )
 
@@ -104,6 +107,43 @@ func TestHealthPanel_RenderSummary(t *testing.T) {
 	}
 }
 
+func TestHealthPanel_RenderSummary_DepsCacheAge(t *testing.T) {
+	p := newTestHealthPanel([]doctor.Check{{Name: "a", Status: doctor.StatusOK}})
+	p.SetSize(80, 20)
+	if summary := p.renderSummary(); strings.Contains(summary, "cached") {
+		t.Errorf("renderSummary() without deps = %q", summary)
+	}
+
+	p.result.DepsResult = &deps.CheckResult{CachedAt: time.Now().Add(-4 * time.Minute)}
+	if summary := p.renderSummary(); !strings.Contains(summary, "deps cached 4m ago") {
+		t.Errorf("renderSummary() = %q, want the deps cache age", summary)
+	}
+}
+
+func TestHealthPanel_RenderSummary_Trend(t *testing.T) {
+	tests := []struct {
+		trend doctor.Trend
+		want  string
+	}{
+		{doctor.TrendImproved, "improved"},
+		{doctor.TrendWorsened, "worsened"},
+		{doctor.TrendUnchanged, ""},
+		{doctor.TrendNone, ""},
+	}
+	for _, tt := range tests {
+		p := newTestHealthPanel([]doctor.Check{{Name: "a", Status: doctor.StatusWarning}})
+		p.SetSize(80, 20)
+		p.Update(healthResultMsg{result: p.result, trend: tt.trend})
+
+		summary := p.renderSummary()
+		for _, word := range []string{"improved", "worsened"} {
+			if strings.Contains(summary, word) != (word == tt.want) {
+				t.Errorf("trend %q: renderSummary() = %q", tt.trend, summary)
+			}
+		}
+	}
+}
+
 func TestHealthPanel_RenderCheckItems_ASCIIIcons(t *testing.T) {
 	checks := []doctor.Check{
 		{Name: "Platform", Status: doctor.StatusOK},
@@ -488,3 +528,30 @@ func TestHealthPanel_HealthSummaryLinesConstant(t *testing.T) {
 type testError struct{ msg string }
 
 func (e *testError) Error() string { return e.msg }
+
+func TestHealthPanel_OutdatedDependencies(t *testing.T) {
+	p := newTestHealthPanel([]doctor.Check{
+		{ID: "dependencies", Name: "Dependencies", Status: doctor.StatusWarning},
+	})
+	p.result.DepsResult = &deps.CheckResult{
+		Core: []deps.DependencyCheck{
+			{Item: config.DependencyItem{Name: "nvim"}, Status: deps.StatusVersionMismatch, InstalledVersion: "0.9.5", RequiredVersion: "0.11+"},
+			{Item: config.DependencyItem{Name: "git"}, Status: deps.StatusInstalled, InstalledVersion: "2.43.0"},
+		},
+	}
+	p.SetSize(40, 10)
+
+	if got := len(p.Outdated()); got != 1 {
+		t.Fatalf("Outdated() = %d, want 1", got)
+	}
+	if !strings.Contains(p.View(), "↑1") {
+		t.Errorf("expected the dependencies check to be marked, got:\n%s", p.View())
+	}
+
+	versions := strings.Join(renderDependencyVersions(p.result.DepsResult), "\n")
+	for _, want := range []string{"nvim 0.9.5 (outdated) needs 0.11+", "git 2.43.0", "Press U to upgrade 1 outdated"} {
+		if !strings.Contains(versions, want) {
+			t.Errorf("versions missing %q:\n%s", want, versions)
+		}
+	}
+}
@@ -10,8 +10,9 @@ import (
 
 // Help is the model for the help component.
 type Help struct {
-	width  int
-	height int
+	width    int
+	height   int
+	readOnly bool // Grey out the actions that change the machine
 }
 
 // NewHelp creates a new help component.
@@ -55,6 +56,13 @@ func (h Help) View() string {
 		Foreground(ui.TextColor).
 		MarginLeft(2)
 
+	// Actions that change the machine, greyed out in read-only mode
+	mutatingKeyStyle, mutatingDescStyle := keyStyle, descStyle
+	if h.readOnly {
+		mutatingKeyStyle = keyStyle.Foreground(ui.SubtleColor)
+		mutatingDescStyle = descStyle.Foreground(ui.SubtleColor)
+	}
+
 	subtleStyle := lipgloss.NewStyle().
 		Foreground(ui.SubtleColor).
 		Width(boxWidth).
@@ -63,6 +71,10 @@ func (h Help) View() string {
 
 	b.WriteString(titleStyle.Render("go4dot Dashboard - Keyboard Shortcuts"))
 	b.WriteString("\n")
+	if h.readOnly {
+		b.WriteString(lipgloss.NewStyle().Foreground(ui.WarningColor).Width(boxWidth).Align(lipgloss.Center).Render("Read-only: greyed-out actions are disabled"))
+		b.WriteString("\n")
+	}
 
 	b.WriteString(headerStyle.Render("Navigation"))
 	b.WriteString("\n")
@@ -71,22 +83,39 @@ func (h Help) View() string {
 
 	b.WriteString(headerStyle.Render("Actions"))
 	b.WriteString("\n")
-	fmt.Fprintf(&b, "%s%s\n", keyStyle.Render("enter"), descStyle.Render("Sync selected config"))
-	fmt.Fprintf(&b, "%s%s\n", keyStyle.Render("s"), descStyle.Render("Sync all configs"))
-	fmt.Fprintf(&b, "%s%s\n", keyStyle.Render("shift+s"), descStyle.Render("Sync selected configs"))
+	fmt.Fprintf(&b, "%s%s\n", mutatingKeyStyle.Render("enter"), mutatingDescStyle.Render("Sync selected config"))
+	fmt.Fprintf(&b, "%s%s\n", mutatingKeyStyle.Render("enter (6)"), mutatingDescStyle.Render("Relink the file picked in Details"))
+	fmt.Fprintf(&b, "%s%s\n", mutatingKeyStyle.Render("s"), mutatingDescStyle.Render("Sync all: links, deps, externals"))
+	fmt.Fprintf(&b, "%s%s\n", mutatingKeyStyle.Render("shift+s"), mutatingDescStyle.Render("Sync selected configs"))
+	fmt.Fprintf(&b, "%s%s\n", mutatingKeyStyle.Render("l"), mutatingDescStyle.Render("Link all configs (symlinks only)"))
+	fmt.Fprintf(&b, "%s%s\n", mutatingKeyStyle.Render("shift+l"), mutatingDescStyle.Render("Link selected configs"))
+	fmt.Fprintf(&b, "%s%s\n", mutatingKeyStyle.Render("p"), mutatingDescStyle.Render("Edit the plan of an install or bulk sync"))
+	fmt.Fprintf(&b, "%s%s\n", keyStyle.Render("n"), descStyle.Render("Dry-run mode: sync, link, install only report"))
+	fmt.Fprintf(&b, "%s%s\n", mutatingKeyStyle.Render("shift+p"), mutatingDescStyle.Render("Switch profile (the configs this machine uses)"))
+	fmt.Fprintf(&b, "%s%s\n", keyStyle.Render("x"), descStyle.Render("Cancel running operation"))
+	fmt.Fprintf(&b, "%s%s\n", keyStyle.Render("ctrl+r"), descStyle.Render("Record macro, then 7-9 to save"))
+	fmt.Fprintf(&b, "%s%s\n", keyStyle.Render("7-9"), descStyle.Render("Run recorded macro"))
 
 	b.WriteString(headerStyle.Render("Selection & Filter"))
 	b.WriteString("\n")
 	fmt.Fprintf(&b, "%s%s\n", keyStyle.Render("space"), descStyle.Render("Toggle selection"))
 	fmt.Fprintf(&b, "%s%s\n", keyStyle.Render("shift+a"), descStyle.Render("Select/deselect all visible"))
 	fmt.Fprintf(&b, "%s%s\n", keyStyle.Render("/"), descStyle.Render("Enter filter mode"))
+	fmt.Fprintf(&b, "%s%s\n", keyStyle.Render("shift+f"), descStyle.Render("Search file contents"))
 
 	b.WriteString(headerStyle.Render("Other"))
 	b.WriteString("\n")
 	fmt.Fprintf(&b, "%s%s\n", keyStyle.Render("d"), descStyle.Render("Run doctor check"))
 	fmt.Fprintf(&b, "%s%s\n", keyStyle.Render("m"), descStyle.Render("Configure overrides"))
+	fmt.Fprintf(&b, "%s%s\n", keyStyle.Render("shift+d"), descStyle.Render("Read the selected config's README"))
+	fmt.Fprintf(&b, "%s%s\n", mutatingKeyStyle.Render("a"), mutatingDescStyle.Render("Archive or restore the selected config"))
+	fmt.Fprintf(&b, "%s%s\n", mutatingKeyStyle.Render("shift+e"), mutatingDescStyle.Render("Edit the selected config's description, tags, ..."))
+	fmt.Fprintf(&b, "%s%s\n", mutatingKeyStyle.Render("shift+u"), mutatingDescStyle.Render("Undo the last conflict resolution"))
+	fmt.Fprintf(&b, "%s%s\n", mutatingKeyStyle.Render("shift+u"), mutatingDescStyle.Render("Upgrade outdated dependencies (Health panel)"))
+	fmt.Fprintf(&b, "%s%s\n", keyStyle.Render("ctrl+s"), descStyle.Render("Save a text and HTML snapshot"))
 	fmt.Fprintf(&b, "%s%s\n", keyStyle.Render("tab"), descStyle.Render("More commands menu"))
 	fmt.Fprintf(&b, "%s%s\n", keyStyle.Render("?"), descStyle.Render("Toggle help screen"))
+	fmt.Fprintf(&b, "%s%s\n", keyStyle.Render("t"), descStyle.Render("Take the dashboard tour (from this screen)"))
 	fmt.Fprintf(&b, "%s%s\n", keyStyle.Render("q / esc"), descStyle.Render("Quit dashboard"))
 
 	b.WriteString(subtleStyle.Render("Press ?, q, or esc to close"))
@@ -0,0 +1,54 @@
+package dashboard
+
+import (
+	"fmt"
+
+	"github.com/nvandessel/go4dot/internal/config"
+	"github.com/nvandessel/go4dot/internal/hooks"
+)
+
+// runHooks runs the hooks of stage for configNames, streaming each command
+// and its output into the output log. step is the operation step they run
+// in, or -1 for none. A failing pre_ hook fails step, as the operation
+// stops there; after linking the failures are only returned.
+func runHooks(runner *OperationRunner, step int, cfg *config.Config, dotfilesPath, stage string, configNames []string) error {
+	if len(hooks.For(cfg, stage, configNames)) == 0 || runner.Err() != nil {
+		return nil
+	}
+	runner.Progress(step, fmt.Sprintf("Running %s hooks...", stage))
+
+	result := hooks.Run(runner.Context(), cfg, stage, configNames, hooks.Options{
+		RepoRoot:   dotfilesPath,
+		TargetDir:  cfg.Stow.TargetDir(),
+		StartFunc:  logHookStart(runner),
+		OutputFunc: logHookOutput(runner),
+	})
+	logHookFailures(runner, result.Failed)
+
+	err := result.Err()
+	if err != nil && config.IsPreStage(stage) {
+		runner.StepComplete(step, StepError, fmt.Sprintf("%s hook failed", stage))
+	}
+	return err
+}
+
+// logHookStart logs each hook as it starts
+func logHookStart(runner *OperationRunner) func(hooks.Hook) {
+	return func(h hooks.Hook) {
+		runner.Log("info", fmt.Sprintf("Hook %s", h))
+	}
+}
+
+// logHookOutput logs each line a hook writes, indented under the hook
+func logHookOutput(runner *OperationRunner) func(hooks.Hook, string) {
+	return func(h hooks.Hook, line string) {
+		runner.Log("info", "  "+line)
+	}
+}
+
+// logHookFailures logs the hooks that failed
+func logHookFailures(runner *OperationRunner, failed []*hooks.Error) {
+	for _, f := range failed {
+		runner.Log("error", fmt.Sprintf("Failed: %s hook %s - %v", f.Hook.Stage, f.Hook.Command, f.Err))
+	}
+}
@@ -0,0 +1,51 @@
+package dashboard
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/nvandessel/go4dot/internal/config"
+)
+
+func TestRunHooks(t *testing.T) {
+	cfg := &config.Config{Hooks: config.Hooks{
+		config.HookPreSync: {"echo checking; echo broken >&2; exit 1", "echo not reached"},
+	}}
+
+	msgs := make(chan tea.Msg, 16)
+	runner := newChannelRunner(context.Background(), 1, msgs)
+	err := runHooks(runner, 0, cfg, t.TempDir(), config.HookPreSync, nil)
+	runner.flush()
+	close(msgs)
+
+	if err == nil {
+		t.Fatal("runHooks() error = nil, want the failed pre_sync hook")
+	}
+
+	var got []string
+	for batch := range msgs {
+		for _, msg := range batch.(OperationBatchMsg).Msgs {
+			switch msg := msg.(type) {
+			case OperationProgressMsg:
+				got = append(got, "progress:"+msg.Detail)
+			case OperationStepCompleteMsg:
+				got = append(got, "step:"+msg.Detail)
+			case OperationLogMsg:
+				got = append(got, msg.Level+":"+msg.Message)
+			}
+		}
+	}
+	want := []string{
+		"progress:Running pre_sync hooks...",
+		"info:Hook pre_sync: echo checking; echo broken >&2; exit 1",
+		"info:  checking",
+		"info:  broken",
+		"error:Failed: pre_sync hook echo checking; echo broken >&2; exit 1 - exited with 1",
+		"step:pre_sync hook failed",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("messages = %q, want %q", got, want)
+	}
+}
@@ -2,44 +2,58 @@ package dashboard
 
 import (
 	"fmt"
+	"os"
 
 	"github.com/nvandessel/go4dot/internal/config"
 	"github.com/nvandessel/go4dot/internal/deps"
+	"github.com/nvandessel/go4dot/internal/doctor"
 	"github.com/nvandessel/go4dot/internal/machine"
 	"github.com/nvandessel/go4dot/internal/platform"
+	"github.com/nvandessel/go4dot/internal/setup"
+	"github.com/nvandessel/go4dot/internal/shellinit"
+	"github.com/nvandessel/go4dot/internal/snapshot"
 	"github.com/nvandessel/go4dot/internal/state"
 	"github.com/nvandessel/go4dot/internal/stow"
 )
 
 // InstallOptions configures the dashboard installation behavior
 type InstallOptions struct {
-	Auto         bool // Non-interactive, use defaults
-	Minimal      bool // Only core configs, skip optional
-	SkipDeps     bool // Skip dependency installation
-	SkipExternal bool // Skip external dependency cloning
-	SkipMachine  bool // Skip machine-specific configuration
-	SkipStow     bool // Skip stowing configs
-	Overwrite    bool // Overwrite existing files
+	Auto           bool   // Non-interactive, use defaults
+	Minimal        bool   // Only core configs, skip optional
+	SkipDeps       bool   // Skip dependency installation
+	SkipExternal   bool   // Skip external dependency cloning
+	SkipToolchains bool   // Skip installing language toolchains
+	SkipMachine    bool   // Skip machine-specific configuration
+	SkipStow       bool   // Skip stowing configs
+	Overwrite      bool   // Overwrite existing files
+	Adopt          bool   // Move conflicting files in home into the repo before stowing
+	Escalation     string // How package managers get root: sudo, doas or pkexec; empty uses the first installed
+	Plan           *Plan  // Steps as edited in the plan editor; nil runs every stage the options allow
 }
 
 // InstallResult holds the result of an installation
 type InstallResult struct {
-	Platform       *platform.Platform
-	DepsInstalled  []config.DependencyItem
-	DepsFailed     []deps.InstallError
-	ConfigsStowed  []string
-	ConfigsAdopted []string
-	ConfigsFailed  []stow.StowError
-	ExternalCloned []config.ExternalDep
-	ExternalFailed []deps.ExternalError
-	MachineConfigs []machine.RenderResult
-	Errors         []error
+	Platform            *platform.Platform
+	DepsInstalled       []config.DependencyItem
+	DepsFailed          []deps.InstallError
+	ToolchainsInstalled []string
+	ToolchainsFailed    []deps.ToolchainError
+	ConfigsStowed       []string
+	ConfigsAdopted      []string
+	ConfigsFailed       []stow.StowError
+	ExternalCloned      []config.ExternalDep
+	ExternalFailed      []deps.ExternalError
+	MachineConfigs      []machine.RenderResult
+	ShellRCWired        []string
+	Verify              *doctor.Verification // Links of the stowed configs; nil if none were stowed
+	Errors              []error
 }
 
 // HasErrors returns true if any errors occurred
 func (r *InstallResult) HasErrors() bool {
 	return len(r.DepsFailed) > 0 || len(r.ConfigsFailed) > 0 ||
-		len(r.ExternalFailed) > 0 || len(r.Errors) > 0
+		len(r.ExternalFailed) > 0 || len(r.ToolchainsFailed) > 0 || len(r.Errors) > 0 ||
+		(r.Verify != nil && !r.Verify.OK())
 }
 
 // Summary returns a summary string of the installation
@@ -68,6 +82,11 @@ func (r *InstallResult) Summary() string {
 		}
 	}
 
+	if len(r.ToolchainsInstalled) > 0 || len(r.ToolchainsFailed) > 0 {
+		summary += fmt.Sprintf("Toolchains: %d installed, %d failed\n",
+			len(r.ToolchainsInstalled), len(r.ToolchainsFailed))
+	}
+
 	if len(r.ExternalCloned) > 0 || len(r.ExternalFailed) > 0 {
 		summary += fmt.Sprintf("External: %d cloned, %d failed\n",
 			len(r.ExternalCloned), len(r.ExternalFailed))
@@ -77,11 +96,20 @@ func (r *InstallResult) Summary() string {
 		summary += fmt.Sprintf("Machine configs: %d configured\n", len(r.MachineConfigs))
 	}
 
+	if len(r.ShellRCWired) > 0 {
+		summary += fmt.Sprintf("Shell integration: %d rc files updated\n", len(r.ShellRCWired))
+	}
+
+	if r.Verify != nil {
+		summary += fmt.Sprintf("Verify: %s\n", r.Verify.Summary())
+	}
+
 	return summary
 }
 
 // RunInstallOperation runs the install operation within the dashboard
 func RunInstallOperation(runner *OperationRunner, cfg *config.Config, dotfilesPath string, opts InstallOptions) (*InstallResult, error) {
+	defer snapshot.Begin("install")()
 	result := &InstallResult{}
 
 	// Step 0: Detect platform
@@ -92,19 +120,46 @@ func RunInstallOperation(runner *OperationRunner, cfg *config.Config, dotfilesPa
 		return nil, fmt.Errorf("failed to detect platform: %w", err)
 	}
 	result.Platform = p
+
+	// Fail before changing anything rather than halfway through linking
+	pre := setup.PreflightOptions{Deps: !opts.SkipDeps, Platform: p, Escalation: opts.Escalation}
+	if !opts.SkipStow {
+		pre.Configs = installConfigs(cfg, opts)
+	}
+	if err := runPreflight(runner, 0, cfg, dotfilesPath, pre); err != nil {
+		return nil, err
+	}
+	if err := runHooks(runner, 0, cfg, dotfilesPath, config.HookPreInstall, itemNames(pre.Configs)); err != nil {
+		return nil, err
+	}
 	runner.StepComplete(0, StepSuccess, fmt.Sprintf("%s (%s)", p.OS, p.PackageManager))
 
+	// Steps after a cancellation are skipped, but state is still saved for
+	// the ones that ran
+
 	// Step 1: Install dependencies
-	if !opts.SkipDeps {
-		if err := runDependencyInstall(runner, cfg, p, opts, result); err != nil {
+	if action := installStage(opts.SkipDeps, opts.Plan, PlanDependencies); action != PlanSkip && runner.Err() == nil {
+		if err := runDependencyInstall(runner, 1, cfg, p, opts.Escalation, action == PlanDryRun, result); err != nil {
 			result.Errors = append(result.Errors, err)
 		}
 	} else {
 		runner.StepComplete(1, StepSkipped, "Skipped")
 	}
 
+	// Toolchains have no step of their own; they follow the dependencies
+	// they may need to build
+	if action := installStage(opts.SkipToolchains, opts.Plan, PlanToolchains); action != PlanSkip && len(cfg.Toolchains) > 0 && runner.Err() == nil {
+		if action == PlanDryRun {
+			for _, tc := range cfg.Toolchains {
+				runner.Log("info", fmt.Sprintf("Dry run: would set up the %s toolchain", tc.Language))
+			}
+		} else if err := runToolchainInstall(runner, cfg, p, result); err != nil {
+			result.Errors = append(result.Errors, err)
+		}
+	}
+
 	// Step 2: Stow configs
-	if !opts.SkipStow {
+	if !opts.SkipStow && runner.Err() == nil {
 		if err := runStowConfigs(runner, cfg, dotfilesPath, opts, result); err != nil {
 			result.Errors = append(result.Errors, err)
 		}
@@ -113,8 +168,8 @@ func RunInstallOperation(runner *OperationRunner, cfg *config.Config, dotfilesPa
 	}
 
 	// Step 3: Clone external dependencies
-	if !opts.SkipExternal {
-		if err := runCloneExternal(runner, cfg, dotfilesPath, p, opts, result); err != nil {
+	if action := installStage(opts.SkipExternal, opts.Plan, PlanExternal); action != PlanSkip && runner.Err() == nil {
+		if err := runCloneExternal(runner, 3, cfg, dotfilesPath, p, action == PlanDryRun, result); err != nil {
 			result.Errors = append(result.Errors, err)
 		}
 	} else {
@@ -122,19 +177,46 @@ func RunInstallOperation(runner *OperationRunner, cfg *config.Config, dotfilesPa
 	}
 
 	// Step 4: Configure machine settings
-	if !opts.SkipMachine {
-		if err := runMachineConfig(runner, cfg, opts, result); err != nil {
+	if action := installStage(opts.SkipMachine, opts.Plan, PlanMachine); action != PlanSkip && runner.Err() == nil {
+		if action == PlanDryRun {
+			dryRunMachineConfig(runner, cfg)
+		} else if err := runMachineConfig(runner, cfg, opts, result); err != nil {
 			result.Errors = append(result.Errors, err)
 		}
 	} else {
 		runner.StepComplete(4, StepSkipped, "Skipped")
 	}
 
+	// Shell integration has no step of its own; it only touches rc files
+	if action := installStage(false, opts.Plan, PlanShell); action != PlanSkip && cfg.ShellIntegration.Enabled && runner.Err() == nil {
+		if action == PlanDryRun {
+			for _, shell := range cfg.ShellIntegration.ShellsFor(os.Getenv("SHELL")) {
+				runner.Log("info", fmt.Sprintf("Dry run: would source g4d shell-init %s in the %s rc file", shell, shell))
+			}
+		} else if err := runShellIntegration(runner, cfg, result); err != nil {
+			result.Errors = append(result.Errors, err)
+		}
+	}
+
+	// post_install hooks of the linked configs have no step of their own;
+	// then check the new links the way doctor does
+	linked := append(append([]string{}, result.ConfigsStowed...), result.ConfigsAdopted...)
+	if err := runHooks(runner, -1, cfg, dotfilesPath, config.HookPostInstall, linked); err != nil {
+		result.Errors = append(result.Errors, err)
+	}
+	if len(linked) > 0 {
+		result.Verify = verifyLinks(runner, cfg, dotfilesPath, linked)
+	}
+
 	// Save state
 	if err := saveInstallState(cfg, dotfilesPath, result); err != nil {
 		runner.Log("warning", fmt.Sprintf("Failed to save state: %v", err))
 	}
 
+	if err := runner.Err(); err != nil {
+		return result, err
+	}
+
 	// Report completion
 	if result.HasErrors() {
 		runner.Done(false, result.Summary(), fmt.Errorf("installation completed with errors"))
@@ -145,33 +227,52 @@ func RunInstallOperation(runner *OperationRunner, cfg *config.Config, dotfilesPa
 	return result, nil
 }
 
-func runDependencyInstall(runner *OperationRunner, cfg *config.Config, p *platform.Platform, opts InstallOptions, result *InstallResult) error {
-	runner.Progress(1, "Checking dependencies...")
+// installStage returns what to do with an install stage: skipped by its
+// flag, else as the plan says
+func installStage(skip bool, plan *Plan, kind PlanStepKind) PlanAction {
+	if skip {
+		return PlanSkip
+	}
+	return plan.stage(kind)
+}
+
+// runDependencyInstall installs missing dependencies; with dryRun it only
+// reports the ones it would install
+func runDependencyInstall(runner *OperationRunner, step int, cfg *config.Config, p *platform.Platform, escalation string, dryRun bool, result *InstallResult) error {
+	runner.Progress(step, "Checking dependencies...")
 
 	checkResult, err := deps.Check(cfg, p)
 	if err != nil {
-		runner.StepComplete(1, StepError, err.Error())
+		runner.StepComplete(step, StepError, err.Error())
 		return fmt.Errorf("failed to check dependencies: %w", err)
 	}
 
 	missing := checkResult.GetMissing()
 	if len(missing) == 0 {
-		runner.StepComplete(1, StepSuccess, "All dependencies installed")
+		runner.StepComplete(step, StepSuccess, "All dependencies installed")
+		return nil
+	}
+
+	if dryRun {
+		for _, d := range missing {
+			runner.Log("info", fmt.Sprintf("Dry run: would install %s", d.Item.Name))
+		}
+		runner.StepComplete(step, StepSkipped, fmt.Sprintf("Dry run: %d would be installed", len(missing)))
 		return nil
 	}
 
-	runner.Progress(1, fmt.Sprintf("Installing %d dependencies...", len(missing)))
+	runner.Progress(step, fmt.Sprintf("Installing %d dependencies...", len(missing)))
 
 	installOpts := deps.InstallOptions{
 		OnlyMissing: true,
-		ProgressFunc: func(current, total int, msg string) {
-			runner.Log("info", msg)
-		},
+		Escalation:  escalation,
+		AskPassword: runner.AskPassword,
+		EventFunc:   runner.LogEvent,
 	}
 
 	installResult, err := deps.Install(cfg, p, installOpts)
 	if err != nil {
-		runner.StepComplete(1, StepError, err.Error())
+		runner.StepComplete(step, StepError, err.Error())
 		return fmt.Errorf("failed to install dependencies: %w", err)
 	}
 
@@ -179,27 +280,61 @@ func runDependencyInstall(runner *OperationRunner, cfg *config.Config, p *platfo
 	result.DepsFailed = installResult.Failed
 
 	if len(installResult.Failed) > 0 {
-		runner.StepComplete(1, StepWarning, fmt.Sprintf("%d installed, %d failed", len(installResult.Installed), len(installResult.Failed)))
+		runner.StepComplete(step, StepWarning, fmt.Sprintf("%d installed, %d failed", len(installResult.Installed), len(installResult.Failed)))
 		for _, f := range installResult.Failed {
 			runner.Log("error", fmt.Sprintf("Failed: %s - %v", f.Item.Name, f.Error))
 		}
 	} else {
-		runner.StepComplete(1, StepSuccess, fmt.Sprintf("%d dependencies installed", len(installResult.Installed)))
+		runner.StepComplete(step, StepSuccess, fmt.Sprintf("%d dependencies installed", len(installResult.Installed)))
 	}
 
 	return nil
 }
 
+func runToolchainInstall(runner *OperationRunner, cfg *config.Config, p *platform.Platform, result *InstallResult) error {
+	runner.Log("info", "Checking toolchains...")
+
+	tcResult, err := deps.InstallToolchains(cfg, p, deps.ToolchainOptions{
+		ProgressFunc: func(current, total int, msg string) {
+			runner.Log("info", msg)
+		},
+	})
+	if err != nil {
+		runner.Log("error", err.Error())
+		return fmt.Errorf("failed to install toolchains: %w", err)
+	}
+
+	result.ToolchainsInstalled = tcResult.Installed
+	result.ToolchainsFailed = tcResult.Failed
+	for _, f := range tcResult.Failed {
+		runner.Log("error", fmt.Sprintf("Failed: %s toolchain - %v", f.Toolchain.Language, f.Error))
+	}
+	if len(tcResult.Failed) == 0 {
+		runner.Log("success", fmt.Sprintf("Toolchains ready: %d installed", len(tcResult.Installed)))
+	}
+	return nil
+}
+
+// installConfigs returns the configs an install with opts stows, in the
+// plan's order when there is one
+func installConfigs(cfg *config.Config, opts InstallOptions) []config.ConfigItem {
+	if opts.Plan != nil {
+		return configItems(cfg, opts.Plan.configs(PlanRun))
+	}
+	if opts.Minimal {
+		return cfg.Configs.Core
+	}
+	return cfg.GetAllConfigs()
+}
+
 func runStowConfigs(runner *OperationRunner, cfg *config.Config, dotfilesPath string, opts InstallOptions, result *InstallResult) error {
 	runner.Progress(2, "Checking config status...")
 
-	var configs []config.ConfigItem
-	if opts.Minimal {
-		configs = cfg.Configs.Core
-	} else {
-		configs = cfg.GetAllConfigs()
+	if opts.Plan != nil {
+		dryRunStow(runner, cfg, dotfilesPath, opts.Plan.configs(PlanDryRun))
 	}
 
+	configs := installConfigs(cfg, opts)
 	if len(configs) == 0 {
 		runner.StepComplete(2, StepSuccess, "No configs to stow")
 		return nil
@@ -234,14 +369,30 @@ func runStowConfigs(runner *OperationRunner, cfg *config.Config, dotfilesPath st
 		return nil
 	}
 
-	runner.Progress(2, fmt.Sprintf("Stowing %d configs...", len(configsToStow)))
-
 	stowOpts := stow.StowOptions{
+		Settings: cfg.Stow,
 		ProgressFunc: func(current, total int, msg string) {
 			runner.Log("info", msg)
 		},
 	}
 
+	if opts.Adopt {
+		conflicts, err := stow.DetectConflicts(cfg, dotfilesPath)
+		if err != nil {
+			runner.StepComplete(2, StepError, err.Error())
+			return fmt.Errorf("failed to check conflicts: %w", err)
+		}
+		if conflicts = stow.ConflictsForConfigs(conflicts, configsToStow); len(conflicts) > 0 {
+			runner.Progress(2, fmt.Sprintf("Adopting %d existing file(s)...", len(conflicts)))
+			if err := stow.AdoptConflicts(conflicts, stowOpts); err != nil {
+				runner.StepComplete(2, StepError, err.Error())
+				return err
+			}
+		}
+	}
+
+	runner.Progress(2, fmt.Sprintf("Stowing %d configs...", len(configsToStow)))
+
 	stowResult := stow.StowConfigs(dotfilesPath, configsToStow, stowOpts)
 
 	result.ConfigsStowed = stowResult.Success
@@ -259,39 +410,45 @@ func runStowConfigs(runner *OperationRunner, cfg *config.Config, dotfilesPath st
 	return nil
 }
 
-func runCloneExternal(runner *OperationRunner, cfg *config.Config, dotfilesPath string, p *platform.Platform, opts InstallOptions, result *InstallResult) error {
+// runCloneExternal clones missing external dependencies; with dryRun it
+// only reports the ones it would clone
+func runCloneExternal(runner *OperationRunner, step int, cfg *config.Config, dotfilesPath string, p *platform.Platform, dryRun bool, result *InstallResult) error {
 	if len(cfg.External) == 0 {
-		runner.StepComplete(3, StepSuccess, "No external dependencies")
+		runner.StepComplete(step, StepSuccess, "No external dependencies")
 		return nil
 	}
 
-	runner.Progress(3, fmt.Sprintf("Cloning %d external dependencies...", len(cfg.External)))
+	runner.Progress(step, fmt.Sprintf("Cloning %d external dependencies...", len(cfg.External)))
 
 	extOpts := deps.ExternalOptions{
-		RepoRoot: dotfilesPath,
-		ProgressFunc: func(current, total int, msg string) {
-			runner.Log("info", msg)
-		},
+		DryRun:    dryRun,
+		RepoRoot:  dotfilesPath,
+		EventFunc: runner.LogEvent,
 	}
 
 	extResult, err := deps.CloneExternal(cfg, p, extOpts)
 	if err != nil {
-		runner.StepComplete(3, StepError, err.Error())
+		runner.StepComplete(step, StepError, err.Error())
 		return fmt.Errorf("failed to clone external dependencies: %w", err)
 	}
 
+	if dryRun {
+		runner.StepComplete(step, StepSkipped, fmt.Sprintf("Dry run: %d would be cloned", len(extResult.Cloned)))
+		return nil
+	}
+
 	result.ExternalCloned = extResult.Cloned
 	result.ExternalFailed = extResult.Failed
 
 	if len(extResult.Failed) > 0 {
-		runner.StepComplete(3, StepWarning, fmt.Sprintf("%d cloned, %d failed", len(extResult.Cloned), len(extResult.Failed)))
+		runner.StepComplete(step, StepWarning, fmt.Sprintf("%d cloned, %d failed", len(extResult.Cloned), len(extResult.Failed)))
 		for _, f := range extResult.Failed {
 			runner.Log("error", fmt.Sprintf("Failed: %s - %v", f.Dep.Name, f.Error))
 		}
 	} else if len(extResult.Cloned) > 0 || len(extResult.Skipped) > 0 {
-		runner.StepComplete(3, StepSuccess, fmt.Sprintf("%d cloned, %d already present", len(extResult.Cloned), len(extResult.Skipped)))
+		runner.StepComplete(step, StepSuccess, fmt.Sprintf("%d cloned, %d already present", len(extResult.Cloned), len(extResult.Skipped)))
 	} else {
-		runner.StepComplete(3, StepSuccess, "All external dependencies already present")
+		runner.StepComplete(step, StepSuccess, "All external dependencies already present")
 	}
 
 	return nil
@@ -365,6 +522,44 @@ func runMachineConfig(runner *OperationRunner, cfg *config.Config, opts InstallO
 	return nil
 }
 
+// dryRunMachineConfig reports the machine configs an install would ask for
+func dryRunMachineConfig(runner *OperationRunner, cfg *config.Config) {
+	var missing int
+	for _, status := range machine.CheckMachineConfigStatus(cfg) {
+		if status.Status == "missing" {
+			missing++
+			runner.Log("info", fmt.Sprintf("Dry run: would configure %s", status.ID))
+		}
+	}
+	runner.StepComplete(4, StepSkipped, fmt.Sprintf("Dry run: %d would be configured", missing))
+}
+
+func runShellIntegration(runner *OperationRunner, cfg *config.Config, result *InstallResult) error {
+	shells := cfg.ShellIntegration.ShellsFor(os.Getenv("SHELL"))
+	if len(shells) == 0 {
+		runner.Log("warning", "Shell integration: no supported login shell; set shell_integration.shells")
+		return nil
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return fmt.Errorf("failed to get home directory: %w", err)
+	}
+
+	for _, shell := range shells {
+		changed, path, err := shellinit.Wire(shell, home)
+		if err != nil {
+			runner.Log("error", err.Error())
+			return err
+		}
+		if changed {
+			result.ShellRCWired = append(result.ShellRCWired, path)
+			runner.Log("success", fmt.Sprintf("%s sources g4d shell-init %s", path, shell))
+		}
+	}
+	return nil
+}
+
 func saveInstallState(cfg *config.Config, dotfilesPath string, result *InstallResult) error {
 	st, err := state.Load()
 	if err != nil || st == nil {
@@ -395,6 +590,7 @@ func saveInstallState(cfg *config.Config, dotfilesPath string, result *InstallRe
 		}
 		st.AddConfig(configName, configName, isCore)
 	}
+	stow.RecordFingerprints(cfg, dotfilesPath, st, allConfigs)
 
 	for _, ext := range result.ExternalCloned {
 		st.SetExternalDep(ext.ID, ext.Destination, true)
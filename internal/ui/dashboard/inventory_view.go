@@ -0,0 +1,158 @@
+package dashboard
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/nvandessel/go4dot/internal/config"
+	"github.com/nvandessel/go4dot/internal/inventory"
+	"github.com/nvandessel/go4dot/internal/platform"
+	"github.com/nvandessel/go4dot/internal/ui"
+	"github.com/nvandessel/go4dot/internal/version"
+)
+
+// InventoryViewCloseMsg is sent when the inventory view should close
+type InventoryViewCloseMsg struct{}
+
+// InventoryView lists the machines recorded in the repo's inventory
+type InventoryView struct {
+	machines []inventory.Machine
+	enabled  bool
+	err      error
+	viewport viewport.Model
+	width    int
+	height   int
+	ready    bool
+}
+
+// NewInventoryView loads the machine records of the repo at dotfilesPath
+func NewInventoryView(cfg *config.Config, dotfilesPath string, p *platform.Platform) *InventoryView {
+	vp := viewport.New(0, 0)
+	vp.Style = lipgloss.NewStyle()
+	v := &InventoryView{viewport: vp}
+	if cfg == nil {
+		return v
+	}
+	v.enabled = cfg.Inventory.Enabled
+
+	records, err := inventory.Load(dotfilesPath)
+	if err != nil {
+		v.err = err
+		return v
+	}
+	hostname := ""
+	if p != nil {
+		hostname = p.Hostname
+	}
+	v.machines = inventory.Assess(records, time.Now(), inventory.StaleAfter(cfg), hostname, version.GetToolVersion())
+	return v
+}
+
+// Init initializes the inventory view
+func (v *InventoryView) Init() tea.Cmd {
+	return nil
+}
+
+// SetSize updates the view dimensions
+func (v *InventoryView) SetSize(width, height int) {
+	v.width = width
+	v.height = height
+	contentWidth := width - 6
+	contentHeight := height - 6
+	if contentWidth < 10 {
+		contentWidth = 10
+	}
+	if contentHeight < 5 {
+		contentHeight = 5
+	}
+	v.viewport.Width = contentWidth
+	v.viewport.Height = contentHeight
+	v.ready = true
+	v.updateContent()
+}
+
+// Update handles messages
+func (v *InventoryView) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if msg, ok := msg.(tea.KeyMsg); ok && key.Matches(msg, key.NewBinding(key.WithKeys("esc", "q"))) {
+		return v, func() tea.Msg { return InventoryViewCloseMsg{} }
+	}
+
+	var cmd tea.Cmd
+	v.viewport, cmd = v.viewport.Update(msg)
+	return v, cmd
+}
+
+// View renders the inventory content
+func (v *InventoryView) View() string {
+	return overlayInventoryContent(v)
+}
+
+func (v *InventoryView) updateContent() {
+	subtleStyle := lipgloss.NewStyle().Foreground(ui.SubtleColor)
+
+	switch {
+	case v.err != nil:
+		v.viewport.SetContent(ui.ErrorStyle.Render(v.err.Error()))
+		return
+	case len(v.machines) == 0 && !v.enabled:
+		v.viewport.SetContent(subtleStyle.Render("The machine inventory is off.\nSet 'inventory.enabled: true' in .go4dot.yaml and sync to record this machine."))
+		return
+	case len(v.machines) == 0:
+		v.viewport.SetContent(subtleStyle.Render("No machines recorded yet. Sync to record this one."))
+		return
+	}
+
+	nameStyle := lipgloss.NewStyle().Foreground(ui.TextColor).Bold(true)
+	warnStyle := lipgloss.NewStyle().Foreground(ui.WarningColor)
+	okStyle := lipgloss.NewStyle().Foreground(ui.SecondaryColor)
+
+	var lines []string
+	var stale, outdated int
+	for _, m := range v.machines {
+		name := nameStyle.Render(m.Hostname)
+		if m.Current {
+			name += subtleStyle.Render(" (this machine)")
+		}
+		status := okStyle.Render("✓")
+		if m.Stale || m.Outdated {
+			status = warnStyle.Render("⚠")
+		}
+		lines = append(lines, status+" "+name)
+
+		platformName := m.OS
+		if m.Distro != "" {
+			platformName += "/" + m.Distro
+		}
+		if m.Arch != "" {
+			platformName += " " + m.Arch
+		}
+		if m.Profile != "" {
+			platformName += " · profile " + m.Profile
+		}
+		lines = append(lines, "  "+subtleStyle.Render(platformName))
+
+		synced := "synced " + formatAge(m.LastSync)
+		if m.Stale {
+			stale++
+			synced = warnStyle.Render(synced + " (stale)")
+		} else {
+			synced = subtleStyle.Render(synced)
+		}
+		ver := "go4dot " + m.Version
+		if m.Outdated {
+			outdated++
+			ver = warnStyle.Render(ver + " (outdated)")
+		} else {
+			ver = subtleStyle.Render(ver)
+		}
+		lines = append(lines, "  "+synced+subtleStyle.Render(" · ")+ver, "")
+	}
+
+	lines = append(lines, subtleStyle.Render(fmt.Sprintf("%d machines, %d stale, %d outdated", len(v.machines), stale, outdated)))
+	v.viewport.SetContent(strings.Join(lines, "\n"))
+}
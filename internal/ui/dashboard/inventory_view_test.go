@@ -0,0 +1,81 @@
+package dashboard
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/nvandessel/go4dot/internal/config"
+	"github.com/nvandessel/go4dot/internal/inventory"
+	"github.com/nvandessel/go4dot/internal/platform"
+)
+
+func TestInventoryView_Content(t *testing.T) {
+	tests := []struct {
+		name    string
+		enabled bool
+		records []inventory.Record
+		want    []string
+	}{
+		{
+			name: "disabled",
+			want: []string{"inventory is off"},
+		},
+		{
+			name:    "enabled without records",
+			enabled: true,
+			want:    []string{"No machines recorded yet"},
+		},
+		{
+			name:    "stale and outdated",
+			enabled: true,
+			records: []inventory.Record{
+				{Hostname: "laptop", OS: "linux", Version: "2.0.0", LastSync: time.Now()},
+				{Hostname: "server", OS: "linux", Version: "1.0.0", LastSync: time.Now().Add(-60 * 24 * time.Hour)},
+			},
+			want: []string{"laptop", "(this machine)", "server", "(stale)", "(outdated)", "2 machines, 1 stale, 1 outdated"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			for _, r := range tt.records {
+				if err := inventory.Save(dir, r); err != nil {
+					t.Fatal(err)
+				}
+			}
+			cfg := &config.Config{Inventory: config.Inventory{Enabled: tt.enabled}}
+
+			v := NewInventoryView(cfg, dir, &platform.Platform{Hostname: "laptop"})
+			v.SetSize(100, 40)
+			content := v.View()
+			for _, want := range tt.want {
+				if !strings.Contains(content, want) {
+					t.Errorf("expected %q in inventory view, got:\n%s", want, content)
+				}
+			}
+		})
+	}
+}
+
+func TestInventoryView_OpenAndClose(t *testing.T) {
+	m := New(State{
+		Platform:     &platform.Platform{OS: "linux"},
+		Config:       &config.Config{},
+		DotfilesPath: t.TempDir(),
+		HasConfig:    true,
+	})
+	m.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+
+	m.handleMenuAction(ActionInventory)
+	if m.currentView != viewInventory || m.inventory == nil {
+		t.Fatalf("expected the inventory view to open, got view %v", m.currentView)
+	}
+
+	m.Update(InventoryViewCloseMsg{})
+	if m.currentView == viewInventory || m.inventory != nil {
+		t.Error("expected the inventory view to close")
+	}
+}
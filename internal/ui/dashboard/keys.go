@@ -5,20 +5,37 @@ import "github.com/charmbracelet/bubbles/key"
 // keyMap defines the key bindings
 type keyMap struct {
 	// Actions
-	Sync    key.Binding
-	Doctor  key.Binding
-	Install key.Binding
-	Machine key.Binding
-	Update  key.Binding
-	Menu    key.Binding
-	Quit    key.Binding
-	Enter   key.Binding
-	Expand  key.Binding
-	Filter  key.Binding
-	Help    key.Binding
-	Select  key.Binding
-	All     key.Binding
-	Bulk    key.Binding
+	Sync     key.Binding
+	Doctor   key.Binding
+	Install  key.Binding
+	Machine  key.Binding
+	Update   key.Binding
+	Menu     key.Binding
+	Quit     key.Binding
+	Enter    key.Binding
+	Expand   key.Binding
+	Filter   key.Binding
+	Search   key.Binding
+	Help     key.Binding
+	Tour     key.Binding
+	Select   key.Binding
+	All      key.Binding
+	Bulk     key.Binding
+	Cancel   key.Binding
+	Record   key.Binding
+	Macro    key.Binding
+	Docs     key.Binding
+	Archive  key.Binding
+	Undo     key.Binding
+	Snapshot key.Binding
+	Plan     key.Binding
+	Edit     key.Binding
+	DryRun   key.Binding
+	Profile  key.Binding
+
+	// Link-only operations (no dependencies or externals)
+	Link     key.Binding
+	BulkLink key.Binding
 
 	// List navigation (within panel)
 	Up   key.Binding
@@ -84,10 +101,18 @@ var keys = keyMap{
 		key.WithKeys("/"),
 		key.WithHelp("/", "filter"),
 	),
+	Search: key.NewBinding(
+		key.WithKeys("F"),
+		key.WithHelp("F", "search files"),
+	),
 	Help: key.NewBinding(
 		key.WithKeys("?"),
 		key.WithHelp("?", "help"),
 	),
+	Tour: key.NewBinding(
+		key.WithKeys("t"),
+		key.WithHelp("t", "tutorial"),
+	),
 	Select: key.NewBinding(
 		key.WithKeys(" "),
 		key.WithHelp("space", "select"),
@@ -100,6 +125,58 @@ var keys = keyMap{
 		key.WithKeys("S"),
 		key.WithHelp("S", "sync selected"),
 	),
+	Link: key.NewBinding(
+		key.WithKeys("l"),
+		key.WithHelp("l", "link all"),
+	),
+	BulkLink: key.NewBinding(
+		key.WithKeys("L"),
+		key.WithHelp("L", "link selected"),
+	),
+	Cancel: key.NewBinding(
+		key.WithKeys("x"),
+		key.WithHelp("x", "cancel operation"),
+	),
+	Record: key.NewBinding(
+		key.WithKeys("ctrl+r"),
+		key.WithHelp("ctrl+r", "record macro"),
+	),
+	Macro: key.NewBinding(
+		key.WithKeys("7", "8", "9"),
+		key.WithHelp("7-9", "run macro"),
+	),
+	Docs: key.NewBinding(
+		key.WithKeys("D"),
+		key.WithHelp("D", "config readme"),
+	),
+	Archive: key.NewBinding(
+		key.WithKeys("a"),
+		key.WithHelp("a", "archive/restore"),
+	),
+	Undo: key.NewBinding(
+		key.WithKeys("U"),
+		key.WithHelp("U", "undo conflicts"),
+	),
+	Snapshot: key.NewBinding(
+		key.WithKeys("ctrl+s"),
+		key.WithHelp("ctrl+s", "snapshot"),
+	),
+	Plan: key.NewBinding(
+		key.WithKeys("p"),
+		key.WithHelp("p", "plan"),
+	),
+	Edit: key.NewBinding(
+		key.WithKeys("E"),
+		key.WithHelp("E", "edit metadata"),
+	),
+	DryRun: key.NewBinding(
+		key.WithKeys("n"),
+		key.WithHelp("n", "dry-run mode"),
+	),
+	Profile: key.NewBinding(
+		key.WithKeys("P"),
+		key.WithHelp("P", "switch profile"),
+	),
 
 	// List navigation (within panel)
 	Up: key.NewBinding(
@@ -0,0 +1,74 @@
+package dashboard
+
+import (
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/nvandessel/go4dot/internal/state"
+	"github.com/nvandessel/go4dot/internal/stow"
+)
+
+// linkFileMsg is sent when a single file has been relinked
+type linkFileMsg struct {
+	configName string
+	relPath    string
+	actions    []stow.Action
+	err        error
+
+	// Refreshed status, nil if it could not be gathered
+	linkStatus *stow.ConfigLinkStatus
+	drift      *stow.DriftSummary
+}
+
+// linkFile returns a command creating or repairing the link for one file of
+// a config, leaving the config's other links alone
+func (m *Model) linkFile(configName, relPath string) tea.Cmd {
+	if m.state.Config == nil || m.operationActive {
+		return nil
+	}
+	if m.state.Demo {
+		m.outputPanel.AddLog("info", fmt.Sprintf("Demo mode: %s was not linked", relPath))
+		return nil
+	}
+	item := m.state.Config.GetConfigByName(configName)
+	if item == nil {
+		return nil
+	}
+
+	cfg, dotfilesPath := m.state.Config, m.state.DotfilesPath
+	return func() tea.Msg {
+		msg := linkFileMsg{configName: configName, relPath: relPath}
+		msg.actions, msg.err = stow.LinkFile(dotfilesPath, *item, relPath, stow.StowOptions{Settings: cfg.Stow})
+		msg.linkStatus, _ = stow.GetSingleConfigLinkStatus(*item, dotfilesPath, cfg.Stow)
+		st, _ := state.Load()
+		msg.drift, _ = stow.FullDriftCheckWithHome(cfg, dotfilesPath, cfg.Stow.TargetDir(), st)
+		return msg
+	}
+}
+
+// handleLinkFile reports a relinked file and refreshes the panels with the
+// config's new link status
+func (m *Model) handleLinkFile(msg linkFileMsg) tea.Cmd {
+	switch {
+	case msg.err != nil:
+		m.outputPanel.AddLog("error", fmt.Sprintf("Failed to link %s: %v", msg.relPath, msg.err))
+	case len(msg.actions) == 0:
+		m.outputPanel.AddLog("info", fmt.Sprintf("%s is already linked", msg.relPath))
+	default:
+		for _, a := range msg.actions {
+			m.outputPanel.AddLog("info", a.String())
+		}
+		m.outputPanel.AddLog("success", fmt.Sprintf("Linked %s", msg.relPath))
+	}
+
+	if msg.linkStatus != nil {
+		if m.state.LinkStatus == nil {
+			m.state.LinkStatus = make(map[string]*stow.ConfigLinkStatus)
+		}
+		m.state.LinkStatus[msg.configName] = msg.linkStatus
+	}
+	if msg.drift != nil {
+		m.state.DriftSummary = msg.drift
+	}
+	return m.bus.Publish(StatusUpdatedEvent{State: m.state})
+}
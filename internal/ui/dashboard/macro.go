@@ -0,0 +1,162 @@
+package dashboard
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/nvandessel/go4dot/internal/prefs"
+)
+
+// Macros are recorded as the keystrokes the user presses and replayed by
+// feeding the same keys back through Update. Keys are stored in the form
+// tea.KeyMsg prints them so the preferences file stays readable.
+
+// startMacroRecording begins capturing keystrokes for a new macro
+func (m *Model) startMacroRecording() {
+	m.recordingMacro = true
+	m.macroKeys = nil
+	m.footer.SetRecording(true)
+	m.outputPanel.AddLog("info", fmt.Sprintf("Recording macro: press %s to save it, %s to stop",
+		strings.Join(prefs.MacroSlots, "/"), keys.Record.Help().Key))
+}
+
+// stopMacroRecording ends recording without binding anything
+func (m *Model) stopMacroRecording() {
+	m.recordingMacro = false
+	m.macroKeys = nil
+	m.footer.SetRecording(false)
+}
+
+// recordMacroKey handles a key press while recording. It reports whether the
+// key ended the recording; any other key is recorded and then handled as
+// usual.
+func (m *Model) recordMacroKey(msg tea.KeyMsg) bool {
+	// A typed password must never end up in the preferences file
+	if m.currentView == viewPassword {
+		return false
+	}
+
+	// The record and slot keys only control recording on the dashboard
+	// itself, so they can still be typed into filters and forms
+	if m.currentView == viewDashboard && !m.filterMode && !m.showHelp {
+		switch {
+		case key.Matches(msg, keys.Record):
+			m.stopMacroRecording()
+			m.outputPanel.AddLog("info", "Macro recording stopped")
+			return true
+		case key.Matches(msg, keys.Macro):
+			m.bindMacro(msg.String())
+			return true
+		}
+	}
+
+	if len(m.macroKeys) >= prefs.MaxMacroKeys {
+		m.stopMacroRecording()
+		m.outputPanel.AddLog("warning", fmt.Sprintf("Macro recording stopped: more than %d keys", prefs.MaxMacroKeys))
+		return false
+	}
+	m.macroKeys = append(m.macroKeys, msg.String())
+	return false
+}
+
+// bindMacro binds the recorded keys to slot and persists them in the user
+// preferences file
+func (m *Model) bindMacro(slot string) {
+	recorded := m.macroKeys
+	m.stopMacroRecording()
+	if len(recorded) == 0 {
+		m.outputPanel.AddLog("warning", "Macro not saved: no keys were recorded")
+		return
+	}
+
+	if m.state.Preferences == nil {
+		m.state.Preferences = prefs.Default()
+	}
+	if err := m.state.Preferences.SetMacro(slot, recorded); err != nil {
+		m.outputPanel.AddLog("error", fmt.Sprintf("Macro not saved: %v", err))
+		return
+	}
+
+	if m.state.Demo {
+		m.outputPanel.AddLog("info", fmt.Sprintf("Demo mode: macro bound to %s for this session only", slot))
+		return
+	}
+
+	// Reload the file rather than saving the in-memory preferences, which
+	// may be defaults standing in for a file that failed to load
+	onDisk, err := prefs.Load()
+	if err == nil {
+		err = onDisk.SetMacro(slot, recorded)
+	}
+	if err == nil {
+		err = onDisk.Save()
+	}
+	if err != nil {
+		m.outputPanel.AddLog("error", fmt.Sprintf("Macro bound to %s for this session only: %v", slot, err))
+		return
+	}
+	m.outputPanel.AddLog("success", fmt.Sprintf("Macro saved to %s (%d keys)", slot, len(recorded)))
+}
+
+// replayMacro feeds the keys bound to slot through Update in order. Each key
+// sees the state left by the previous one; commands they return (starting
+// operations, loading data) run once the whole macro has been replayed.
+func (m *Model) replayMacro(slot string) tea.Cmd {
+	recorded := m.state.Preferences.Macro(slot)
+	if len(recorded) == 0 {
+		m.outputPanel.AddLog("info", fmt.Sprintf("No macro bound to %s (press %s to record one)", slot, keys.Record.Help().Key))
+		return nil
+	}
+
+	m.replayingMacro = true
+	defer func() { m.replayingMacro = false }()
+
+	var cmds []tea.Cmd
+	for _, k := range recorded {
+		_, cmd := m.Update(parseKeyMsg(k))
+		cmds = append(cmds, cmd)
+		if m.quitting {
+			break
+		}
+	}
+	return tea.Batch(cmds...)
+}
+
+// keyTypes maps the names Bubble Tea prints for special keys back to their
+// key type
+var keyTypes = func() map[string]tea.KeyType {
+	names := map[string]tea.KeyType{}
+	for t := tea.KeyType(-128); t < 128; t++ {
+		if t == tea.KeyRunes {
+			continue
+		}
+		if name := t.String(); name != "" {
+			if _, ok := names[name]; !ok {
+				names[name] = t
+			}
+		}
+	}
+	return names
+}()
+
+// parseKeyMsg turns a key as printed by tea.KeyMsg.String back into the
+// message, so that parseKeyMsg(s).String() == s
+func parseKeyMsg(s string) tea.KeyMsg {
+	var k tea.Key
+	if rest, ok := strings.CutPrefix(s, "alt+"); ok && rest != "" {
+		k.Alt = true
+		s = rest
+	}
+	if t, ok := keyTypes[s]; ok {
+		k.Type = t
+		if t == tea.KeySpace {
+			k.Runes = []rune{' '}
+		}
+		return tea.KeyMsg(k)
+	}
+	k.Type = tea.KeyRunes
+	k.Runes = []rune(s)
+	return tea.KeyMsg(k)
+}
@@ -0,0 +1,150 @@
+package dashboard
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/nvandessel/go4dot/internal/config"
+	"github.com/nvandessel/go4dot/internal/platform"
+	"github.com/nvandessel/go4dot/internal/prefs"
+)
+
+func newMacroTestModel(t *testing.T, demo bool) *Model {
+	t.Helper()
+	t.Setenv("HOME", t.TempDir())
+	m := New(State{
+		Platform: &platform.Platform{OS: "linux"},
+		Configs: []config.ConfigItem{
+			{Name: "vim"},
+			{Name: "zsh-work"},
+			{Name: "git-work"},
+		},
+		HasConfig: true,
+		Demo:      demo,
+	})
+	return &m
+}
+
+func pressKeys(m *Model, ks ...string) {
+	for _, k := range ks {
+		m.Update(parseKeyMsg(k))
+	}
+}
+
+func TestParseKeyMsg_RoundTrip(t *testing.T) {
+	for _, k := range []string{"a", "A", "/", "7", " ", "enter", "esc", "tab", "shift+tab", "up", "backspace", "ctrl+r", "ctrl+c", "alt+x", "alt+enter", "f5"} {
+		if got := parseKeyMsg(k).String(); got != k {
+			t.Errorf("parseKeyMsg(%q).String() = %q", k, got)
+		}
+	}
+}
+
+func TestMacro_RecordBindReplay(t *testing.T) {
+	m := newMacroTestModel(t, false)
+
+	pressKeys(m, "ctrl+r")
+	if !m.recordingMacro || !m.footer.recording {
+		t.Fatal("ctrl+r did not start recording")
+	}
+	pressKeys(m, "/", "w", "o", "r", "k", "enter", "A", "7")
+	if m.recordingMacro {
+		t.Fatal("slot key did not end recording")
+	}
+
+	want := []string{"/", "w", "o", "r", "k", "enter", "A"}
+	if got := m.state.Preferences.Macro("7"); !reflect.DeepEqual(got, want) {
+		t.Errorf("bound macro = %v, want %v", got, want)
+	}
+	saved, err := prefs.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := saved.Macro("7"); !reflect.DeepEqual(got, want) {
+		t.Errorf("saved macro = %v, want %v", got, want)
+	}
+
+	// Undo what recording did, then replay it with one key
+	m.filterText = ""
+	m.configsPanel.SetFilter("")
+	m.selectedConfigs = map[string]bool{}
+
+	pressKeys(m, "7")
+	if m.filterText != "work" || m.filterMode {
+		t.Errorf("after replay filter = %q (mode %v), want work", m.filterText, m.filterMode)
+	}
+	wantSelected := map[string]bool{"zsh-work": true, "git-work": true}
+	if !reflect.DeepEqual(m.selectedConfigs, wantSelected) {
+		t.Errorf("after replay selected = %v, want %v", m.selectedConfigs, wantSelected)
+	}
+}
+
+func TestMacro_SlotKeysTypeIntoFilter(t *testing.T) {
+	m := newMacroTestModel(t, false)
+
+	pressKeys(m, "ctrl+r", "/", "7", "enter", "8")
+	if got := m.state.Preferences.Macro("8"); !reflect.DeepEqual(got, []string{"/", "7", "enter"}) {
+		t.Errorf("bound macro = %v", got)
+	}
+}
+
+func TestMacro_StopWithoutBinding(t *testing.T) {
+	m := newMacroTestModel(t, false)
+
+	pressKeys(m, "ctrl+r", "A", "ctrl+r")
+	if m.recordingMacro || m.footer.recording {
+		t.Error("second ctrl+r did not stop recording")
+	}
+	if m.state.Preferences.Macro("7") != nil {
+		t.Error("stopped recording bound a macro")
+	}
+	if _, err := os.Stat(filepath.Join(os.Getenv("HOME"), prefs.PrefsDir, prefs.PrefsFileName)); !os.IsNotExist(err) {
+		t.Error("stopped recording wrote the preferences file")
+	}
+}
+
+func TestMacro_ReplayUnbound(t *testing.T) {
+	m := newMacroTestModel(t, false)
+
+	pressKeys(m, "9")
+	found := false
+	for _, log := range m.outputPanel.GetLogs() {
+		if strings.Contains(log.Message, "No macro bound to 9") {
+			found = true
+		}
+	}
+	if !found {
+		t.Error("replaying an unbound slot was not reported")
+	}
+}
+
+func TestMacro_DemoIsNotPersisted(t *testing.T) {
+	m := newMacroTestModel(t, true)
+
+	pressKeys(m, "ctrl+r", "A", "9")
+	if m.state.Preferences.Macro("9") == nil {
+		t.Error("demo macro was not bound for the session")
+	}
+	if _, err := os.Stat(filepath.Join(os.Getenv("HOME"), prefs.PrefsDir)); !os.IsNotExist(err) {
+		t.Error("demo mode wrote the preferences file")
+	}
+}
+
+func TestMacro_ReplayStopsOnQuit(t *testing.T) {
+	m := newMacroTestModel(t, true)
+	m.state.Preferences = prefs.Default()
+	if err := m.state.Preferences.SetMacro("7", []string{"q", "A"}); err != nil {
+		t.Fatal(err)
+	}
+
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'7'}})
+	if !m.quitting || cmd == nil {
+		t.Error("quit key in macro did not quit")
+	}
+	if len(m.selectedConfigs) != 0 {
+		t.Error("keys after quit were replayed")
+	}
+}
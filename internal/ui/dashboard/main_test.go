@@ -0,0 +1,20 @@
+package dashboard
+
+import (
+	"os"
+	"testing"
+)
+
+// TestMain points HOME at a temporary directory, so operations the tests
+// run record their history and conflict undo there instead of in the
+// state directory of whoever runs the tests
+func TestMain(m *testing.M) {
+	home, err := os.MkdirTemp("", "g4d-dashboard-test-")
+	if err != nil {
+		panic(err)
+	}
+	_ = os.Setenv("HOME", home)
+	code := m.Run()
+	_ = os.RemoveAll(home)
+	os.Exit(code)
+}
@@ -0,0 +1,86 @@
+package dashboard
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/nvandessel/go4dot/internal/ui"
+)
+
+var (
+	mdImageRe  = regexp.MustCompile(`!\[[^\]]*\]\([^)]*\)`)
+	mdLinkRe   = regexp.MustCompile(`\[([^\]]*)\]\([^)]*\)`)
+	mdEmphRe   = regexp.MustCompile(`(\*\*|__)(.+?)(\*\*|__)`)
+	mdCodeRe   = regexp.MustCompile("`([^`]+)`")
+	mdHTMLRe   = regexp.MustCompile(`<[^>]+>`)
+	mdBulletRe = regexp.MustCompile(`^(\s*)[-*+]\s+`)
+)
+
+// renderMarkdownPreview renders a lightweight terminal preview of markdown.
+// It is not a full renderer: headings are highlighted, code blocks dimmed,
+// and inline markup (links, emphasis, HTML) reduced to plain text so READMEs
+// stay readable inside a small panel.
+func renderMarkdownPreview(text string, width int) string {
+	headingStyle := lipgloss.NewStyle().Foreground(ui.PrimaryColor).Bold(true)
+	codeStyle := lipgloss.NewStyle().Foreground(ui.SubtleColor)
+	textStyle := lipgloss.NewStyle().Foreground(ui.TextColor)
+	if width > 0 {
+		textStyle = textStyle.Width(width)
+	}
+
+	var out []string
+	inCode := false
+	blank := false
+
+	for _, line := range strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n") {
+		trimmed := strings.TrimSpace(line)
+
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			inCode = !inCode
+			continue
+		}
+		if inCode {
+			out = append(out, codeStyle.Render("  "+line))
+			blank = false
+			continue
+		}
+
+		// Collapse runs of blank lines
+		if trimmed == "" {
+			if !blank && len(out) > 0 {
+				out = append(out, "")
+			}
+			blank = true
+			continue
+		}
+		blank = false
+
+		if strings.HasPrefix(trimmed, "#") {
+			heading := strings.TrimSpace(strings.TrimLeft(trimmed, "#"))
+			out = append(out, headingStyle.Render(stripInlineMarkdown(heading)))
+			continue
+		}
+
+		// Skip badge-only lines and horizontal rules
+		if stripped := strings.TrimSpace(stripInlineMarkdown(trimmed)); stripped == "" ||
+			strings.Trim(trimmed, "-*_ ") == "" {
+			continue
+		}
+
+		line = mdBulletRe.ReplaceAllString(line, "$1• ")
+		out = append(out, textStyle.Render(stripInlineMarkdown(line)))
+	}
+
+	return strings.TrimRight(strings.Join(out, "\n"), "\n")
+}
+
+// stripInlineMarkdown reduces inline markdown and HTML to plain text
+func stripInlineMarkdown(s string) string {
+	s = mdImageRe.ReplaceAllString(s, "")
+	s = mdLinkRe.ReplaceAllString(s, "$1")
+	s = mdEmphRe.ReplaceAllString(s, "$2")
+	s = mdCodeRe.ReplaceAllString(s, "$1")
+	s = mdHTMLRe.ReplaceAllString(s, "")
+	return s
+}
@@ -0,0 +1,34 @@
+package dashboard
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRenderMarkdownPreview(t *testing.T) {
+	input := strings.Join([]string{
+		"# My Plugin",
+		"[![CI](https://ci/badge.svg)](https://ci)",
+		"",
+		"",
+		"A **fast** plugin, see [the docs](https://docs) or `:help`.",
+		"---",
+		"- first",
+		"```sh",
+		"make install",
+		"```",
+	}, "\n")
+
+	out := renderMarkdownPreview(input, 0)
+
+	for _, want := range []string{"My Plugin", "A fast plugin, see the docs or :help.", "• first", "make install"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected preview to contain %q, got:\n%s", want, out)
+		}
+	}
+	for _, unwanted := range []string{"badge.svg", "**", "](", "```", "---", "\n\n\n"} {
+		if strings.Contains(out, unwanted) {
+			t.Errorf("expected preview not to contain %q, got:\n%s", unwanted, out)
+		}
+	}
+}
@@ -13,7 +13,7 @@ const (
 	// compact menu panel. The default delegate uses 2 lines per item (title +
 	// description) plus 1 line spacing between items, plus the title header
 	// area. We give a small amount of extra room so the list renders cleanly.
-	menuCompactHeight = 14
+	menuCompactHeight = 20
 )
 
 type menuItem struct {
@@ -34,7 +34,9 @@ type Menu struct {
 func NewMenu() Menu {
 	items := []list.Item{
 		menuItem{title: "List Configs", desc: "View all configurations in a simple list", action: ActionList},
+		menuItem{title: "Search Dotfiles", desc: "Find text in all managed files", action: ActionSearch},
 		menuItem{title: "External Dependencies", desc: "Manage external git repositories", action: ActionExternal},
+		menuItem{title: "Machine Inventory", desc: "See which machines are stale or on an older go4dot", action: ActionInventory},
 		menuItem{title: "Uninstall go4dot", desc: "Remove all symlinks and state", action: ActionUninstall},
 	}
 
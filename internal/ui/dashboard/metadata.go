@@ -0,0 +1,147 @@
+package dashboard
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/huh"
+	"github.com/nvandessel/go4dot/internal/config"
+	"github.com/nvandessel/go4dot/internal/ui"
+)
+
+// metadataFormID identifies the metadata form in FormCompleteMsg
+const metadataFormID = "metadata"
+
+// metadataEdit holds the values the metadata form edits. The form keeps
+// pointers to them, so they live as long as it does.
+type metadataEdit struct {
+	name        string
+	description string
+	tags        string // Comma-separated
+	platforms   string // Comma-separated
+	dependsOn   []string
+}
+
+// Metadata returns the edited values, with the lists split and trimmed
+func (e *metadataEdit) Metadata() config.ConfigMetadata {
+	return config.ConfigMetadata{
+		Description: strings.TrimSpace(e.description),
+		Tags:        splitList(e.tags),
+		Platforms:   splitList(e.platforms),
+		DependsOn:   e.dependsOn,
+	}
+}
+
+// editMetadata opens a form for the selected config's description, tags,
+// platforms and depends_on
+func (m *Model) editMetadata() tea.Cmd {
+	if m.state.Config == nil || m.operationActive {
+		return nil
+	}
+	item := m.configsPanel.GetSelectedConfig()
+	if item == nil {
+		return nil
+	}
+	if item.Root != "" {
+		m.outputPanel.AddLog("warning", fmt.Sprintf("%s comes from a base config; edit it in %s", item.Name, item.Root))
+		return nil
+	}
+
+	edit := &metadataEdit{
+		name:        item.Name,
+		description: item.Description,
+		tags:        strings.Join(item.Tags, ", "),
+		platforms:   strings.Join(item.Platforms, ", "),
+		dependsOn:   append([]string(nil), item.DependsOn...),
+	}
+	fields := []huh.Field{
+		huh.NewInput().Title("Description").Value(&edit.description),
+		huh.NewInput().Title("Tags").Description("Comma-separated").Value(&edit.tags),
+		huh.NewInput().Title("Platforms").Description("Comma-separated OS or distro names; empty for all").Value(&edit.platforms),
+	}
+	var others []huh.Option[string]
+	for _, c := range m.state.Config.GetAllConfigs() {
+		if c.Name != item.Name {
+			others = append(others, huh.NewOption(c.Name, c.Name))
+		}
+	}
+	if len(others) > 0 {
+		fields = append(fields, huh.NewMultiSelect[string]().
+			Title("Depends on").
+			Options(others...).
+			Value(&edit.dependsOn))
+	}
+	form := huh.NewForm(huh.NewGroup(fields...)).WithShowHelp(false)
+
+	m.metadata = edit
+	m.metadataForm = NewFormView(metadataFormID, "Edit "+item.Name, form)
+	contentWidth, contentHeight := overlayContentSize(m.width, m.height, ui.DefaultOverlayStyle())
+	m.metadataForm.SetSize(contentWidth, contentHeight)
+	m.pushView(viewMetadata)
+	return m.metadataForm.Init()
+}
+
+// saveMetadata writes the edited metadata to .go4dot.yaml and reloads the
+// dashboard from the edited file. An edit that would make a valid config
+// invalid is refused.
+func (m *Model) saveMetadata(edit *metadataEdit) tea.Cmd {
+	if m.state.Demo {
+		m.outputPanel.AddLog("info", fmt.Sprintf("Demo mode: %s was not changed", edit.name))
+		return nil
+	}
+
+	meta := edit.Metadata()
+	if err := validateMetadata(m.state.Config, m.state.DotfilesPath, edit.name, meta); err != nil {
+		m.outputPanel.AddLog("error", fmt.Sprintf("%s was not changed: %v", edit.name, err))
+		return nil
+	}
+
+	configPath := filepath.Join(m.state.DotfilesPath, config.ConfigFileName)
+	if err := config.UpdateConfigMetadata(configPath, edit.name, meta); err != nil {
+		m.outputPanel.AddLog("error", err.Error())
+		return nil
+	}
+	m.outputPanel.AddLog("success", fmt.Sprintf("Updated %s in %s", edit.name, config.ConfigFileName))
+
+	cfg, err := config.LoadFromPath(configPath)
+	if err != nil {
+		m.outputPanel.AddLog("error", fmt.Sprintf("Failed to reload config: %v", err))
+		return nil
+	}
+	return m.loadConfig(cfg, m.state.DotfilesPath)
+}
+
+// validateMetadata validates cfg with meta set on the config called name.
+// It only fails when cfg was valid to begin with, so metadata can still be
+// edited in a config that is broken elsewhere.
+func validateMetadata(cfg *config.Config, configDir, name string, meta config.ConfigMetadata) error {
+	edited := *cfg
+	edited.Configs.Core = append([]config.ConfigItem(nil), cfg.Configs.Core...)
+	edited.Configs.Optional = append([]config.ConfigItem(nil), cfg.Configs.Optional...)
+	for _, items := range [][]config.ConfigItem{edited.Configs.Core, edited.Configs.Optional} {
+		for i := range items {
+			if items[i].Name == name {
+				meta.ApplyTo(&items[i])
+			}
+		}
+	}
+
+	err := edited.Validate(configDir)
+	if err != nil && cfg.Validate(configDir) != nil {
+		return nil
+	}
+	return err
+}
+
+// splitList splits a comma-separated list, dropping empty items
+func splitList(s string) []string {
+	var items []string
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
@@ -0,0 +1,98 @@
+package dashboard
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/nvandessel/go4dot/internal/config"
+	"github.com/nvandessel/go4dot/internal/platform"
+)
+
+func TestEditMetadata(t *testing.T) {
+	dir := t.TempDir()
+	const yaml = `schema_version: "1.0"
+metadata:
+  name: test
+configs:
+  core:
+    - name: git # vcs
+      path: git
+    - name: zsh
+      path: zsh
+`
+	configPath := filepath.Join(dir, config.ConfigFileName)
+	if err := os.WriteFile(configPath, []byte(yaml), 0644); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"git", "zsh"} {
+		if err := os.MkdirAll(filepath.Join(dir, name), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	cfg, err := config.LoadFromPath(configPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	m := New(State{
+		Platform:     &platform.Platform{OS: "linux"},
+		Configs:      cfg.GetAllConfigs(),
+		Config:       cfg,
+		DotfilesPath: dir,
+		HasConfig:    true,
+	})
+	m.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+
+	m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("E")})
+	if m.currentView != viewMetadata || m.metadata == nil {
+		t.Fatalf("expected the metadata form, got view %v", m.currentView)
+	}
+	if m.metadata.name != "git" {
+		t.Fatalf("editing %q, want git", m.metadata.name)
+	}
+
+	edit := m.metadata
+	edit.description = " Git config "
+	edit.tags = "vcs, , shell"
+	m.Update(FormCompleteMsg{FormID: metadataFormID})
+	if m.currentView != viewDashboard {
+		t.Errorf("expected the form to close, got view %v", m.currentView)
+	}
+
+	data, _ := os.ReadFile(configPath)
+	for _, want := range []string{"# vcs", "description: Git config", "tags: [vcs, shell]"} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("config missing %q:\n%s", want, data)
+		}
+	}
+}
+
+func TestValidateMetadata(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"git", "zsh"} {
+		if err := os.MkdirAll(filepath.Join(dir, name), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	cfg := &config.Config{
+		SchemaVersion: "1.0",
+		Metadata:      config.Metadata{Name: "test"},
+		Configs: config.ConfigGroups{Core: []config.ConfigItem{
+			{Name: "git", Path: "git", DependsOn: []string{"zsh"}},
+			{Name: "zsh", Path: "zsh"},
+		}},
+	}
+
+	if err := validateMetadata(cfg, dir, "zsh", config.ConfigMetadata{Tags: []string{"shell"}}); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if err := validateMetadata(cfg, dir, "zsh", config.ConfigMetadata{DependsOn: []string{"git"}}); err == nil {
+		t.Error("expected a dependency cycle to be refused")
+	}
+	if len(cfg.Configs.Core[1].DependsOn) != 0 {
+		t.Error("validateMetadata changed the config")
+	}
+}
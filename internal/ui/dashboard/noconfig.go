@@ -11,6 +11,7 @@ import (
 // NoConfig is the model for the no config component.
 type NoConfig struct {
 	selectedIdx int
+	readOnly    bool // Initializing is disabled
 }
 
 // NewNoConfig creates a new no config component.
@@ -52,6 +53,9 @@ func (m NoConfig) View() string {
 		{"Initialize go4dot", "Set up a new .go4dot.yaml config"},
 		{"Quit", "Exit go4dot"},
 	}
+	if m.readOnly {
+		options[0].desc = "Disabled in read-only mode"
+	}
 
 	for i, opt := range options {
 		prefix := "  "
@@ -70,8 +74,12 @@ func (m NoConfig) View() string {
 	b.WriteString("\n")
 
 	keyStyle := lipgloss.NewStyle().Foreground(ui.PrimaryColor).Bold(true)
+	initKeyStyle := keyStyle
+	if m.readOnly {
+		initKeyStyle = subtitleStyle
+	}
 	actions := []string{
-		keyStyle.Render("[i]") + subtitleStyle.Render(" Initialize"),
+		initKeyStyle.Render("[i]") + subtitleStyle.Render(" Initialize"),
 		keyStyle.Render("[q]") + subtitleStyle.Render(" Quit"),
 	}
 	b.WriteString(strings.Join(actions, "   "))
@@ -14,7 +14,6 @@ import (
 	"github.com/charmbracelet/lipgloss"
 	"github.com/nvandessel/go4dot/internal/config"
 	"github.com/nvandessel/go4dot/internal/ui"
-	"gopkg.in/yaml.v3"
 )
 
 // OnboardingStep represents the current step in the onboarding wizard
@@ -45,8 +44,9 @@ type OnboardingCompleteMsg struct {
 
 // scannedConfigsMsg is sent when directory scanning completes
 type scannedConfigsMsg struct {
-	configs []config.ConfigItem
-	err     error
+	configs     []config.ConfigItem
+	dotPrefixed map[string]bool // Configs holding dot-foo files, linked with stow --dotfiles
+	err         error
 }
 
 // configWrittenMsg is sent when config file is written
@@ -67,6 +67,7 @@ type Onboarding struct {
 
 	// Collected data
 	scannedConfigs  []config.ConfigItem
+	dotPrefixed     map[string]bool
 	selectedConfigs []string
 	metadata        config.Metadata
 	externalDeps    []config.ExternalDep
@@ -82,8 +83,9 @@ type Onboarding struct {
 	addMoreDeps     bool
 	addMoreMachine  bool
 
-	// Machine config preset selection
-	machinePreset string
+	// Machine config preset selection, from the built-in and imported presets
+	machinePreset  string
+	machinePresets []config.MachinePreset
 
 	// Custom machine config fields
 	customMachineID          string
@@ -99,9 +101,7 @@ type Onboarding struct {
 
 // NewOnboarding creates a new onboarding wizard
 func NewOnboarding(path string) Onboarding {
-	s := spinner.New()
-	s.Spinner = spinner.Dot
-	s.Style = lipgloss.NewStyle().Foreground(ui.PrimaryColor)
+	s := ui.NewSpinner()
 
 	return Onboarding{
 		path:     path,
@@ -152,6 +152,7 @@ func (o *Onboarding) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 		}
 		o.scannedConfigs = msg.configs
+		o.dotPrefixed = msg.dotPrefixed
 		o.step = stepMetadata
 		o.form = o.createMetadataForm()
 		cmds = append(cmds, o.form.Init())
@@ -287,23 +288,21 @@ func (o *Onboarding) handleFormComplete() (tea.Model, tea.Cmd) {
 		return o, o.form.Init()
 
 	case stepMachineDetails:
-		switch o.machinePreset {
-		case "git-signing":
-			o.machineConfigs = append(o.machineConfigs, createGitSigningPreset())
-			o.addMoreMachine = false
-			o.step = stepMachine
-			o.form = o.createMachinePromptForm()
-			return o, o.form.Init()
-		case "custom":
+		if o.machinePreset == "custom" {
 			o.step = stepMachineCustom
 			o.form = o.createMachineCustomForm()
 			return o, o.form.Init()
-		default:
-			o.addMoreMachine = false
-			o.step = stepMachine
-			o.form = o.createMachinePromptForm()
-			return o, o.form.Init()
 		}
+		for _, p := range o.machinePresets {
+			if p.Prompt.ID == o.machinePreset {
+				o.machineConfigs = append(o.machineConfigs, p.Prompt)
+				break
+			}
+		}
+		o.addMoreMachine = false
+		o.step = stepMachine
+		o.form = o.createMachinePromptForm()
+		return o, o.form.Init()
 
 	case stepMachineCustom:
 		slugifiedID := slugify(o.customMachineID)
@@ -675,14 +674,19 @@ func (o *Onboarding) createMachinePromptForm() *huh.Form {
 
 func (o *Onboarding) createMachineDetailsForm() *huh.Form {
 	o.machinePreset = "" // Reset before displaying form
+	// Unreadable imported presets are left out of the picker
+	o.machinePresets, _ = config.MachinePresets()
+	options := make([]huh.Option[string], 0, len(o.machinePresets)+1)
+	for _, p := range o.machinePresets {
+		options = append(options, huh.NewOption(p.Label(), p.Prompt.ID))
+	}
+	options = append(options, huh.NewOption("Custom", "custom"))
+
 	return huh.NewForm(
 		huh.NewGroup(
 			huh.NewSelect[string]().
 				Title("Select a preset or create custom").
-				Options(
-					huh.NewOption("Git Signing (Name, Email, GPG Key)", "git-signing"),
-					huh.NewOption("Custom", "custom"),
-				).
+				Options(options...).
 				Value(&o.machinePreset),
 		),
 	).WithWidth(60).WithShowHelp(false).WithTheme(huh.ThemeCatppuccin())
@@ -724,42 +728,6 @@ func (o *Onboarding) createMachineCustomForm() *huh.Form {
 	).WithWidth(60).WithShowHelp(false).WithTheme(huh.ThemeCatppuccin())
 }
 
-// createGitSigningPreset returns a MachinePrompt for git signing configuration
-func createGitSigningPreset() config.MachinePrompt {
-	return config.MachinePrompt{
-		ID:          "git-signing",
-		Description: "Git Signing Configuration",
-		Destination: "~/.gitconfig.local",
-		Prompts: []config.PromptField{
-			{
-				ID:       "user_name",
-				Prompt:   "Git User Name",
-				Type:     "text",
-				Required: true,
-			},
-			{
-				ID:       "user_email",
-				Prompt:   "Git Email Address",
-				Type:     "text",
-				Required: true,
-			},
-			{
-				ID:     "signing_key",
-				Prompt: "GPG Signing Key",
-				Type:   "text",
-			},
-		},
-		Template: `[user]
-    name = {{ .user_name }}
-    email = {{ .user_email }}
-{{ if .signing_key }}    signingkey = {{ .signing_key }}
-
-[commit]
-    gpgsign = true
-{{ end }}`,
-	}
-}
-
 func (o *Onboarding) createConfirmForm() *huh.Form {
 	o.confirmWrite = false // Reset before displaying form
 	return huh.NewForm(
@@ -787,22 +755,21 @@ func (o *Onboarding) scanDirectory() tea.Msg {
 		return scannedConfigsMsg{err: err}
 	}
 
-	return scannedConfigsMsg{configs: configs}
+	dotPrefixed := make(map[string]bool)
+	for _, c := range configs {
+		if config.UsesDotPrefix(absPath, []config.ConfigItem{c}) {
+			dotPrefixed[c.Name] = true
+		}
+	}
+	return scannedConfigsMsg{configs: configs, dotPrefixed: dotPrefixed}
 }
 
 func (o *Onboarding) writeConfig() tea.Msg {
 	cfg := o.buildConfig()
 
-	data, err := yaml.Marshal(cfg)
-	if err != nil {
-		return configWrittenMsg{err: fmt.Errorf("failed to generate YAML: %w", err)}
-	}
-
-	content := fmt.Sprintf("# Generated by go4dot\n# Edit this file to customize your dotfiles management\n\n%s", string(data))
-
 	configFile := filepath.Join(o.path, config.ConfigFileName)
-	if err := os.WriteFile(configFile, []byte(content), 0644); err != nil {
-		return configWrittenMsg{err: fmt.Errorf("failed to write config file: %w", err)}
+	if err := config.WriteConfig(configFile, cfg, config.GeneratedHeader); err != nil {
+		return configWrittenMsg{err: err}
 	}
 
 	return configWrittenMsg{path: configFile}
@@ -821,7 +788,7 @@ func (o *Onboarding) buildConfig() *config.Config {
 		}
 	}
 
-	return &config.Config{
+	cfg := &config.Config{
 		SchemaVersion: "1.0",
 		Metadata:      o.metadata,
 		Dependencies: config.Dependencies{
@@ -837,6 +804,21 @@ func (o *Onboarding) buildConfig() *config.Config {
 		External:      o.externalDeps,
 		MachineConfig: o.machineConfigs,
 	}
+	if o.usesDotPrefix() {
+		cfg.Stow.Flags = []string{"--dotfiles"}
+	}
+	return cfg
+}
+
+// usesDotPrefix reports whether a selected config names files the stow
+// --dotfiles way
+func (o *Onboarding) usesDotPrefix() bool {
+	for _, name := range o.selectedConfigs {
+		if o.dotPrefixed[name] {
+			return true
+		}
+	}
+	return false
 }
 
 func (o *Onboarding) renderSummary() string {
@@ -852,6 +834,9 @@ func (o *Onboarding) renderSummary() string {
 	lines = append(lines, labelStyle.Render("External: ")+valueStyle.Render(fmt.Sprintf("%d dependencies", len(o.externalDeps))))
 	lines = append(lines, labelStyle.Render("System deps: ")+valueStyle.Render(fmt.Sprintf("%d packages", len(o.systemDeps))))
 	lines = append(lines, labelStyle.Render("Machine configs: ")+valueStyle.Render(fmt.Sprintf("%d templates", len(o.machineConfigs))))
+	if o.usesDotPrefix() {
+		lines = append(lines, labelStyle.Render("Stow: ")+valueStyle.Render("--dotfiles (dot- files found)"))
+	}
 
 	return strings.Join(lines, "\n")
 }
@@ -913,6 +898,8 @@ func scanDirectoryForConfigs(root string) ([]config.ConfigItem, error) {
 
 	var items []config.ConfigItem
 
+	repoIgnore, _ := config.LoadRepoIgnore(root)
+
 	// Directories to always ignore
 	ignored := map[string]bool{
 		".git": true, ".github": true, ".gitlab": true, ".svn": true,
@@ -935,7 +922,7 @@ func scanDirectoryForConfigs(root string) ([]config.ConfigItem, error) {
 	for _, entry := range entries {
 		name := entry.Name()
 
-		if ignored[name] {
+		if ignored[name] || repoIgnore.Match(name, entry.IsDir()) {
 			continue
 		}
 
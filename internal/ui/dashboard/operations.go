@@ -1,12 +1,18 @@
 package dashboard
 
 import (
+	"context"
 	"fmt"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/charmbracelet/bubbles/spinner"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/nvandessel/go4dot/internal/deps"
+	"github.com/nvandessel/go4dot/internal/progress"
+	"github.com/nvandessel/go4dot/internal/state"
 	"github.com/nvandessel/go4dot/internal/ui"
 )
 
@@ -23,6 +29,12 @@ const (
 	OpUninstall
 	OpExternal
 	OpExternalSingle
+	OpLink
+	OpLinkSingle
+	OpBulkLink
+	OpUndoConflicts
+	OpUpgradeDeps
+	OpDryRun
 )
 
 // String returns a human-readable name for the operation type
@@ -46,16 +58,60 @@ func (op OperationType) String() string {
 		return "External Dependencies"
 	case OpExternalSingle:
 		return "External"
+	case OpLink:
+		return "Linking All"
+	case OpLinkSingle:
+		return "Linking"
+	case OpBulkLink:
+		return "Bulk Linking"
+	case OpUndoConflicts:
+		return "Undoing Conflict Resolution"
+	case OpUpgradeDeps:
+		return "Upgrading Dependencies"
+	case OpDryRun:
+		return "Dry Run"
 	default:
 		return "Processing"
 	}
 }
 
+// HistoryName returns the name the operation is recorded under in the
+// operation history, the same as the matching g4d command
+func (op OperationType) HistoryName() string {
+	switch op {
+	case OpInstall:
+		return "install"
+	case OpSync, OpSyncSingle, OpBulkSync:
+		return "sync"
+	case OpLink, OpLinkSingle, OpBulkLink:
+		return "link"
+	case OpUpdate:
+		return "update"
+	case OpDoctor:
+		return "doctor"
+	case OpUninstall:
+		return "uninstall"
+	case OpExternal, OpExternalSingle:
+		return "external"
+	case OpUndoConflicts:
+		return "undo-conflicts"
+	case OpUpgradeDeps:
+		return "deps"
+	case OpDryRun:
+		return "dry-run"
+	default:
+		return "operation"
+	}
+}
+
 // OperationStep represents a single step in an operation
 type OperationStep struct {
-	Name   string
-	Status StepStatus
-	Detail string
+	Name     string
+	Status   StepStatus
+	Detail   string
+	Duration time.Duration // Wall-clock time the step took, once complete
+
+	started time.Time // First progress report of the step
 }
 
 // StepStatus represents the status of a step
@@ -72,22 +128,27 @@ const (
 
 // OperationProgressMsg is sent to update operation progress
 type OperationProgressMsg struct {
+	ID        int // Operation that sent the message
 	Step      string
 	Current   int
 	Total     int
 	Detail    string
 	StepIndex int
+	At        time.Time // When the operation reported it; batching delays delivery
 }
 
 // OperationStepCompleteMsg is sent when a step completes
 type OperationStepCompleteMsg struct {
+	ID        int // Operation that sent the message
 	StepIndex int
 	Status    StepStatus
 	Detail    string
+	At        time.Time // When the operation reported it; batching delays delivery
 }
 
 // OperationDoneMsg is sent when an operation completes
 type OperationDoneMsg struct {
+	ID      int // Operation that sent the message
 	Success bool
 	Summary string
 	Error   error
@@ -95,10 +156,27 @@ type OperationDoneMsg struct {
 
 // OperationLogMsg adds a log entry
 type OperationLogMsg struct {
-	Level   string // "info", "success", "warning", "error"
+	ID      int    // Operation that sent the message
+	Level   string // "info", "success", "skipped", "warning", "error"
 	Message string
 }
 
+// OperationBatchMsg carries the progress, step and log messages an operation
+// reported since the last batch, in order. The runner batches them so an
+// install printing thousands of lines re-renders the dashboard a few times a
+// second rather than once per line.
+type OperationBatchMsg struct {
+	ID   int // Operation that sent the messages
+	Msgs []tea.Msg
+}
+
+const (
+	// batchInterval is the longest a reported message waits to be sent
+	batchInterval = 50 * time.Millisecond
+	// maxBatchSize sends a batch early once this many messages are waiting
+	maxBatchSize = 100
+)
+
 // Operations is the model for running operations within the dashboard
 type Operations struct {
 	operationType OperationType
@@ -109,6 +187,8 @@ type Operations struct {
 	spinner       spinner.Model
 	steps         []OperationStep
 	currentStep   int
+	started       time.Time
+	lastMark      time.Time // Last step completion; where a step without progress reports starts
 	logs          []logEntry
 	done          bool
 	success       bool
@@ -123,11 +203,10 @@ type logEntry struct {
 
 // NewOperations creates a new operations component
 func NewOperations(opType OperationType, configName string, configNames []string) Operations {
-	s := spinner.New()
-	s.Spinner = spinner.Dot
-	s.Style = lipgloss.NewStyle().Foreground(ui.PrimaryColor)
+	s := ui.NewSpinner()
 
 	steps := getStepsForOperation(opType)
+	now := time.Now()
 
 	return Operations{
 		operationType: opType,
@@ -135,6 +214,8 @@ func NewOperations(opType OperationType, configName string, configNames []string
 		configNames:   configNames,
 		spinner:       s,
 		steps:         steps,
+		started:       now,
+		lastMark:      now,
 		logs:          []logEntry{},
 	}
 }
@@ -153,7 +234,20 @@ func getStepsForOperation(opType OperationType) []OperationStep {
 	case OpSync, OpSyncSingle, OpBulkSync:
 		return []OperationStep{
 			{Name: "Checking symlinks", Status: StepPending},
-			{Name: "Syncing configs", Status: StepPending},
+			{Name: "Linking configs", Status: StepPending},
+			{Name: "Installing dependencies", Status: StepPending},
+			{Name: "Cloning external dependencies", Status: StepPending},
+			{Name: "Updating state", Status: StepPending},
+		}
+	case OpLink, OpLinkSingle, OpBulkLink:
+		return []OperationStep{
+			{Name: "Checking symlinks", Status: StepPending},
+			{Name: "Linking configs", Status: StepPending},
+			{Name: "Updating state", Status: StepPending},
+		}
+	case OpUndoConflicts:
+		return []OperationStep{
+			{Name: "Restoring files", Status: StepPending},
 			{Name: "Updating state", Status: StepPending},
 		}
 	case OpUpdate:
@@ -178,6 +272,10 @@ func getStepsForOperation(opType OperationType) []OperationStep {
 			{Name: "Checking status", Status: StepPending},
 			{Name: "Processing", Status: StepPending},
 		}
+	case OpDryRun:
+		return []OperationStep{
+			{Name: "Planning changes", Status: StepPending},
+		}
 	default:
 		return []OperationStep{
 			{Name: "Processing", Status: StepPending},
@@ -200,16 +298,31 @@ func (o *Operations) Update(msg tea.Msg) (Operations, tea.Cmd) {
 
 	case OperationProgressMsg:
 		if msg.StepIndex >= 0 && msg.StepIndex < len(o.steps) {
-			o.steps[msg.StepIndex].Status = StepRunning
-			o.steps[msg.StepIndex].Detail = msg.Detail
+			step := &o.steps[msg.StepIndex]
+			step.Status = StepRunning
+			step.Detail = msg.Detail
+			if step.started.IsZero() {
+				step.started = msg.At
+			}
 			o.currentStep = msg.StepIndex
 		}
 		return *o, nil
 
 	case OperationStepCompleteMsg:
 		if msg.StepIndex >= 0 && msg.StepIndex < len(o.steps) {
-			o.steps[msg.StepIndex].Status = msg.Status
-			o.steps[msg.StepIndex].Detail = msg.Detail
+			step := &o.steps[msg.StepIndex]
+			step.Status = msg.Status
+			step.Detail = msg.Detail
+			if !msg.At.IsZero() {
+				if msg.Status != StepSkipped {
+					start := step.started
+					if start.IsZero() {
+						start = o.lastMark
+					}
+					step.Duration = msg.At.Sub(start)
+				}
+				o.lastMark = msg.At
+			}
 		}
 		return *o, nil
 
@@ -300,6 +413,9 @@ func (o Operations) View() string {
 		if step.Detail != "" && step.Status == StepRunning {
 			stepLine += ui.SubtleStyle.Render(fmt.Sprintf(" - %s", step.Detail))
 		}
+		if step.Duration > 0 {
+			stepLine += ui.SubtleStyle.Render(" " + state.FormatDuration(step.Duration))
+		}
 
 		b.WriteString(stepLine)
 		b.WriteString("\n")
@@ -378,46 +494,221 @@ func (o Operations) OperationType() OperationType {
 	return o.operationType
 }
 
-// OperationRunner is a helper for running operations and sending progress updates
+// Timings returns how long each step that ran took, in step order
+func (o Operations) Timings() []state.StepTiming {
+	var timings []state.StepTiming
+	for _, step := range o.steps {
+		if step.Duration > 0 {
+			timings = append(timings, state.StepTiming{Name: step.Name, Duration: step.Duration})
+		}
+	}
+	return timings
+}
+
+// StartedAt returns when the operation started
+func (o Operations) StartedAt() time.Time {
+	return o.started
+}
+
+// FailedStep returns the name of the first step that errored, or ""
+func (o Operations) FailedStep() string {
+	for _, step := range o.steps {
+		if step.Status == StepError {
+			return step.Name
+		}
+	}
+	return ""
+}
+
+// OperationRunner is a helper for running operations and sending progress
+// updates. It runs on the operation's goroutine and only talks to the model
+// through messages, each tagged with the operation's ID so the model can drop
+// messages from an operation it has already canceled.
 type OperationRunner struct {
-	program *tea.Program
+	ctx  context.Context
+	id   int
+	send func(tea.Msg)
+	done bool
+
+	mu      sync.Mutex // Guards pending and timer, also used by the flush timer
+	pending []tea.Msg
+	timer   *time.Timer
+}
+
+// NewOperationRunner creates a new operation runner that reports to p. The
+// operation should stop when ctx is canceled; messages sent after that are
+// dropped.
+func NewOperationRunner(ctx context.Context, p *tea.Program) *OperationRunner {
+	return &OperationRunner{ctx: ctx, send: p.Send}
+}
+
+// newChannelRunner creates a runner for operation id that reports on msgs.
+// Sends give up once ctx is canceled, so an abandoned operation never blocks.
+func newChannelRunner(ctx context.Context, id int, msgs chan<- tea.Msg) *OperationRunner {
+	return &OperationRunner{
+		ctx: ctx,
+		id:  id,
+		send: func(msg tea.Msg) {
+			select {
+			case msgs <- msg:
+			case <-ctx.Done():
+			}
+		},
+	}
+}
+
+// Context returns the operation's context, canceled when the user cancels
+// the operation or leaves the dashboard
+func (r *OperationRunner) Context() context.Context {
+	return r.ctx
+}
+
+// Err returns the context's error once the operation has been canceled
+func (r *OperationRunner) Err() error {
+	return r.ctx.Err()
+}
+
+func (r *OperationRunner) emit(msg tea.Msg) {
+	if r.ctx.Err() != nil {
+		return
+	}
+	r.send(msg)
+}
+
+// queue adds msg to the next batch, sending it once maxBatchSize messages
+// are waiting or batchInterval has passed
+func (r *OperationRunner) queue(msg tea.Msg) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.pending = append(r.pending, msg)
+	if len(r.pending) >= maxBatchSize {
+		r.flushLocked()
+		return
+	}
+	if r.timer == nil {
+		r.timer = time.AfterFunc(batchInterval, r.flush)
+	}
 }
 
-// NewOperationRunner creates a new operation runner
-func NewOperationRunner(p *tea.Program) *OperationRunner {
-	return &OperationRunner{program: p}
+// flush sends the waiting messages now
+func (r *OperationRunner) flush() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.flushLocked()
+}
+
+func (r *OperationRunner) flushLocked() {
+	if r.timer != nil {
+		r.timer.Stop()
+		r.timer = nil
+	}
+	if len(r.pending) == 0 {
+		return
+	}
+	msgs := r.pending
+	r.pending = nil
+	r.emit(OperationBatchMsg{ID: r.id, Msgs: msgs})
 }
 
 // Progress sends a progress update
 func (r *OperationRunner) Progress(stepIndex int, detail string) {
-	r.program.Send(OperationProgressMsg{
+	r.queue(OperationProgressMsg{
+		ID:        r.id,
 		StepIndex: stepIndex,
 		Detail:    detail,
+		At:        time.Now(),
 	})
 }
 
 // StepComplete marks a step as complete
 func (r *OperationRunner) StepComplete(stepIndex int, status StepStatus, detail string) {
-	r.program.Send(OperationStepCompleteMsg{
+	r.queue(OperationStepCompleteMsg{
+		ID:        r.id,
 		StepIndex: stepIndex,
 		Status:    status,
 		Detail:    detail,
+		At:        time.Now(),
 	})
 }
 
 // Log adds a log entry
 func (r *OperationRunner) Log(level, message string) {
-	r.program.Send(OperationLogMsg{
+	r.queue(OperationLogMsg{
+		ID:      r.id,
 		Level:   level,
 		Message: message,
 	})
 }
 
-// Done marks the operation as complete
+// LogEvent adds a log entry for a progress event, counting the item when
+// work on it starts
+func (r *OperationRunner) LogEvent(e progress.Event) {
+	msg := e.Message
+	if e.Running && e.Total > 0 && e.Current > 0 {
+		msg = fmt.Sprintf("[%d/%d] %s", e.Current, e.Total, msg)
+	}
+	r.Log(string(e.Level), msg)
+}
+
+// Done marks the operation as complete. Only the first call is reported, so
+// operations may finish with their own summary before the generic one.
 func (r *OperationRunner) Done(success bool, summary string, err error) {
-	r.program.Send(OperationDoneMsg{
+	r.flush()
+	if r.done {
+		return
+	}
+	r.done = true
+	r.emit(OperationDoneMsg{
+		ID:      r.id,
 		Success: success,
 		Summary: summary,
 		Error:   err,
 	})
 }
+
+// AskPassword asks the user for a password with the dashboard's masked
+// prompt and waits for the answer. It fails with deps.ErrPasswordCanceled
+// when the prompt is dismissed, or once the operation is canceled.
+func (r *OperationRunner) AskPassword(prompt string) (string, error) {
+	r.flush()
+	reply := make(chan passwordReply, 1)
+	r.emit(passwordRequestMsg{ID: r.id, Prompt: prompt, reply: reply})
+	select {
+	case res := <-reply:
+		if res.canceled {
+			return "", deps.ErrPasswordCanceled
+		}
+		return res.password, nil
+	case <-r.ctx.Done():
+		return "", r.ctx.Err()
+	}
+}
+
+// run executes operationFunc and reports its outcome, including panics
+func (r *OperationRunner) run(operationFunc func(runner *OperationRunner) error) {
+	defer func() {
+		if rec := recover(); rec != nil {
+			r.Done(false, "", fmt.Errorf("operation panicked: %v", rec))
+		}
+		// Anything logged after Done is sent before the channel closes
+		r.flush()
+	}()
+	if err := operationFunc(r); err != nil {
+		r.Done(false, "", err)
+	} else {
+		r.Done(true, "", nil)
+	}
+}
+
+// waitForOperation returns a command that delivers the next message from a
+// running operation, or nil once it has finished
+func waitForOperation(msgs <-chan tea.Msg) tea.Cmd {
+	return func() tea.Msg {
+		msg, ok := <-msgs
+		if !ok {
+			return nil
+		}
+		return msg
+	}
+}
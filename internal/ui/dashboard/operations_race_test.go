@@ -0,0 +1,301 @@
+package dashboard
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/exp/teatest"
+	"github.com/nvandessel/go4dot/internal/config"
+	"github.com/nvandessel/go4dot/internal/deps"
+	"github.com/nvandessel/go4dot/internal/doctor"
+	"github.com/nvandessel/go4dot/internal/platform"
+)
+
+// These tests drive inline operations through a running program and are
+// meant to be run with -race: operations report from their own goroutine
+// while the model keeps handling input.
+
+func newOperationTestModel(t *testing.T) *teatest.TestModel {
+	t.Helper()
+	m := New(State{
+		Platform:       &platform.Platform{OS: "linux"},
+		Configs:        []config.ConfigItem{{Name: "vim"}},
+		HasConfig:      true,
+		DotfilesPath:   "/tmp/dotfiles",
+		HealthResult:   &doctor.CheckResult{},
+		ExternalStatus: []deps.ExternalStatus{},
+	})
+	tm := teatest.NewTestModel(t, &m, teatest.WithInitialTermSize(160, 40))
+	teatest.WaitFor(t, tm.Output(), func(out []byte) bool {
+		return strings.Contains(string(out), "vim")
+	}, teatest.WithDuration(2*time.Second))
+	return tm
+}
+
+// waitForOutput waits until text is rendered. Tests finish operations with a
+// Done summary and wait for it, so the model has settled before quitting.
+func waitForOutput(t *testing.T, tm *teatest.TestModel, text string) {
+	t.Helper()
+	teatest.WaitFor(t, tm.Output(), func(out []byte) bool {
+		return strings.Contains(string(out), text)
+	}, teatest.WithCheckInterval(20*time.Millisecond), teatest.WithDuration(3*time.Second))
+}
+
+func finalModel(t *testing.T, tm *teatest.TestModel) *Model {
+	t.Helper()
+	tm.Send(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'q'}})
+	return tm.FinalModel(t, teatest.WithFinalTimeout(3*time.Second)).(*Model)
+}
+
+func TestInlineOperation_Complete(t *testing.T) {
+	tm := newOperationTestModel(t)
+
+	tm.Send(startOperationMsg{opType: OpSync, run: func(runner *OperationRunner) error {
+		runner.Progress(0, "checking")
+		runner.StepComplete(0, StepSuccess, "")
+		runner.Done(true, "opdone1", nil)
+		return nil
+	}})
+	waitForOutput(t, tm, "opdone1")
+
+	m := finalModel(t, tm)
+	if m.operationActive {
+		t.Error("operation still active after it finished")
+	}
+	if !m.operations.IsDone() || !m.operations.IsSuccess() {
+		t.Error("operation not recorded as a success")
+	}
+	if m.cancelOperation != nil || m.operationMsgs != nil {
+		t.Error("finished operation was not released")
+	}
+}
+
+func TestInlineOperation_Cancel(t *testing.T) {
+	tm := newOperationTestModel(t)
+
+	started := make(chan struct{})
+	exited := make(chan error, 1)
+	tm.Send(startOperationMsg{opType: OpSync, run: func(runner *OperationRunner) error {
+		close(started)
+		<-runner.Context().Done()
+		// Reported after cancellation: must not reach the model
+		runner.Log("info", "stalelog")
+		runner.StepComplete(0, StepError, "stalestep")
+		exited <- runner.Err()
+		return runner.Err()
+	}})
+	<-started
+
+	tm.Send(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'x'}})
+	select {
+	case err := <-exited:
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("operation context error = %v, want context.Canceled", err)
+		}
+	case <-time.After(3 * time.Second):
+		t.Fatal("operation was not canceled")
+	}
+	waitForOutput(t, tm, "canceled")
+
+	// A new operation starts cleanly after the canceled one
+	tm.Send(startOperationMsg{opType: OpSync, run: func(runner *OperationRunner) error {
+		runner.Done(true, "opdone2", nil)
+		return nil
+	}})
+	waitForOutput(t, tm, "opdone2")
+
+	m := finalModel(t, tm)
+	if m.operationActive {
+		t.Error("operation still active")
+	}
+	for _, log := range m.outputPanel.GetLogs() {
+		if strings.Contains(log.Message, "stale") {
+			t.Errorf("canceled operation reported %q", log.Message)
+		}
+	}
+}
+
+func TestInlineOperation_CancelRacesCompletion(t *testing.T) {
+	tm := newOperationTestModel(t)
+
+	// Each operation finishes as soon as it starts while a cancel is
+	// already on its way; whichever wins, the model must end up idle
+	for i := 0; i < 20; i++ {
+		tm.Send(startOperationMsg{opType: OpSync, run: func(runner *OperationRunner) error {
+			runner.Log("info", "racing")
+			return nil
+		}})
+		tm.Send(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'x'}})
+	}
+	tm.Send(startOperationMsg{opType: OpSync, run: func(runner *OperationRunner) error {
+		runner.Done(true, "opdone3", nil)
+		return nil
+	}})
+	waitForOutput(t, tm, "opdone3")
+
+	m := finalModel(t, tm)
+	if m.operationActive {
+		t.Error("operation still active")
+	}
+}
+
+func TestInlineOperation_OneAtATime(t *testing.T) {
+	tm := newOperationTestModel(t)
+
+	release := make(chan struct{})
+	tm.Send(startOperationMsg{opType: OpSync, run: func(runner *OperationRunner) error {
+		<-release
+		runner.Log("info", "opdone4")
+		return nil
+	}})
+	secondRan := make(chan struct{}, 1)
+	tm.Send(startOperationMsg{opType: OpSync, run: func(runner *OperationRunner) error {
+		secondRan <- struct{}{}
+		return nil
+	}})
+	close(release)
+	waitForOutput(t, tm, "opdone4")
+
+	finalModel(t, tm)
+	select {
+	case <-secondRan:
+		t.Error("second operation started while the first was running")
+	default:
+	}
+}
+
+func TestInlineOperation_PanicIsReported(t *testing.T) {
+	tm := newOperationTestModel(t)
+
+	tm.Send(startOperationMsg{opType: OpSync, run: func(runner *OperationRunner) error {
+		panic("boom")
+	}})
+	waitForOutput(t, tm, "Operation Failed")
+	tm.Send(TriageViewCloseMsg{})
+
+	m := finalModel(t, tm)
+	if m.operationActive || m.operations.GetError() == nil {
+		t.Error("panicking operation not recorded as failed")
+	}
+}
+
+func TestHandleOperationMsg_IgnoresStaleMessages(t *testing.T) {
+	m := New(State{Platform: &platform.Platform{OS: "linux"}, HasConfig: true})
+	m.operationID = 2
+	m.operationActive = true
+
+	m.Update(OperationLogMsg{ID: 1, Level: "info", Message: "stale"})
+	m.Update(OperationDoneMsg{ID: 1, Success: true})
+	if m.outputPanel.GetLogCount() != 0 {
+		t.Errorf("stale log was recorded")
+	}
+	if !m.operationActive {
+		t.Errorf("stale done message ended the running operation")
+	}
+
+	m.Update(OperationDoneMsg{ID: 2, Success: true, Summary: "ok"})
+	if m.operationActive {
+		t.Errorf("done message did not end the running operation")
+	}
+}
+
+func TestOperationRunner_DoneReportedOnce(t *testing.T) {
+	msgs := make(chan tea.Msg, 4)
+	runner := newChannelRunner(context.Background(), 7, msgs)
+	runner.Done(true, "first", nil)
+	runner.Done(true, "", nil)
+	close(msgs)
+
+	var got []tea.Msg
+	for msg := range msgs {
+		got = append(got, msg)
+	}
+	if len(got) != 1 {
+		t.Fatalf("got %d messages, want 1", len(got))
+	}
+	done := got[0].(OperationDoneMsg)
+	if done.ID != 7 || done.Summary != "first" {
+		t.Errorf("done = %+v", done)
+	}
+}
+
+func TestOperationRunner_CanceledSendDoesNotBlock(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	runner := newChannelRunner(ctx, 1, make(chan tea.Msg))
+	cancel()
+
+	finished := make(chan struct{})
+	go func() {
+		runner.Log("info", "nobody listens")
+		runner.Done(false, "", nil)
+		close(finished)
+	}()
+	select {
+	case <-finished:
+	case <-time.After(time.Second):
+		t.Fatal("send blocked after cancellation")
+	}
+}
+
+func TestOperationRunner_BatchesMessages(t *testing.T) {
+	msgs := make(chan tea.Msg, 16)
+	runner := newChannelRunner(context.Background(), 3, msgs)
+
+	// A burst is sent in batches of at most maxBatchSize, in order
+	for i := 0; i < maxBatchSize+5; i++ {
+		runner.Log("info", fmt.Sprintf("line %d", i))
+	}
+	first := (<-msgs).(OperationBatchMsg)
+	if first.ID != 3 || len(first.Msgs) != maxBatchSize {
+		t.Fatalf("first batch = %d messages from %d, want %d from 3", len(first.Msgs), first.ID, maxBatchSize)
+	}
+
+	// The rest follows within the batch interval, without another message
+	select {
+	case msg := <-msgs:
+		rest := msg.(OperationBatchMsg)
+		if len(rest.Msgs) != 5 || rest.Msgs[4].(OperationLogMsg).Message != fmt.Sprintf("line %d", maxBatchSize+4) {
+			t.Errorf("second batch = %+v", rest.Msgs)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("pending messages were not flushed")
+	}
+
+	// Done sends what is waiting first
+	runner.Progress(1, "almost")
+	runner.Done(true, "ok", nil)
+	if _, ok := (<-msgs).(OperationBatchMsg); !ok {
+		t.Error("pending progress was not sent before done")
+	}
+	if _, ok := (<-msgs).(OperationDoneMsg); !ok {
+		t.Error("done was not sent")
+	}
+}
+
+func TestHandleOperationMsg_Batch(t *testing.T) {
+	m := New(State{Platform: &platform.Platform{OS: "linux"}, HasConfig: true})
+	m.operationID = 4
+	m.operations = NewOperations(OpSync, "", nil)
+
+	m.Update(OperationBatchMsg{ID: 4, Msgs: []tea.Msg{
+		OperationProgressMsg{ID: 4, StepIndex: 1, Detail: "LINK .zshrc"},
+		OperationLogMsg{ID: 4, Level: "info", Message: "LINK .zshrc"},
+		OperationStepCompleteMsg{ID: 4, StepIndex: 1, Status: StepSuccess, Detail: "1 configs synced"},
+	}})
+
+	logs := m.outputPanel.GetLogs()
+	if len(logs) != 2 || logs[0].Message != "LINK .zshrc" || logs[1].Level != "success" {
+		t.Errorf("logs = %+v", logs)
+	}
+	if !m.operationActive {
+		t.Error("progress in a batch did not mark the operation active")
+	}
+	if m.operations.steps[1].Status != StepSuccess {
+		t.Errorf("step 1 status = %v, want success", m.operations.steps[1].Status)
+	}
+}
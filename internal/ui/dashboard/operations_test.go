@@ -3,11 +3,13 @@ package dashboard
 import (
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/charmbracelet/bubbles/spinner"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/nvandessel/go4dot/internal/config"
 	"github.com/nvandessel/go4dot/internal/platform"
+	"github.com/nvandessel/go4dot/internal/state"
 	"github.com/nvandessel/go4dot/internal/ui"
 )
 
@@ -27,19 +29,30 @@ func TestNewOperations(t *testing.T) {
 		{
 			name:      "Sync operation",
 			opType:    OpSync,
-			wantSteps: 3,
+			wantSteps: 5,
 		},
 		{
 			name:       "Sync single operation",
 			opType:     OpSyncSingle,
 			configName: "vim",
-			wantSteps:  3,
+			wantSteps:  5,
 		},
 		{
 			name:        "Bulk sync operation",
 			opType:      OpBulkSync,
 			configNames: []string{"vim", "zsh"},
-			wantSteps:   3,
+			wantSteps:   5,
+		},
+		{
+			name:      "Link operation",
+			opType:    OpLink,
+			wantSteps: 3,
+		},
+		{
+			name:       "Link single operation",
+			opType:     OpLinkSingle,
+			configName: "vim",
+			wantSteps:  3,
 		},
 		{
 			name:      "Update operation",
@@ -91,6 +104,9 @@ func TestOperationType_String(t *testing.T) {
 		{OpDoctor, "Running Doctor"},
 		{OpUninstall, "Uninstalling"},
 		{OpExternal, "External Dependencies"},
+		{OpLink, "Linking All"},
+		{OpLinkSingle, "Linking"},
+		{OpBulkLink, "Bulk Linking"},
 		{OperationType(99), "Processing"}, // Unknown type
 	}
 
@@ -152,6 +168,36 @@ func TestOperations_Update_StepComplete(t *testing.T) {
 	}
 }
 
+func TestOperations_Timings(t *testing.T) {
+	op := NewOperations(OpInstall, "", nil)
+	start := op.StartedAt()
+
+	msgs := []tea.Msg{
+		// Completed without a progress report: timed from the operation start
+		OperationStepCompleteMsg{StepIndex: 0, Status: StepSuccess, At: start.Add(2 * time.Second)},
+		// Timed from its first progress report
+		OperationProgressMsg{StepIndex: 1, At: start.Add(5 * time.Second)},
+		OperationProgressMsg{StepIndex: 1, At: start.Add(10 * time.Second)},
+		OperationStepCompleteMsg{StepIndex: 1, Status: StepWarning, At: start.Add(47 * time.Second)},
+		// Skipped steps are left out
+		OperationStepCompleteMsg{StepIndex: 2, Status: StepSkipped, At: start.Add(48 * time.Second)},
+		// Timed from the previous step's completion
+		OperationStepCompleteMsg{StepIndex: 3, Status: StepSuccess, At: start.Add(111 * time.Second)},
+	}
+	for _, msg := range msgs {
+		op, _ = op.Update(msg)
+	}
+
+	got := state.FormatTimings(op.Timings())
+	want := "Detecting platform 2s, Installing dependencies 42s, Cloning external dependencies 1m03s"
+	if got != want {
+		t.Errorf("Timings() = %q, want %q", got, want)
+	}
+	if view := op.View(); !strings.Contains(view, "1m03s") {
+		t.Error("expected the view to show step durations")
+	}
+}
+
 func TestOperations_Update_Log(t *testing.T) {
 	op := NewOperations(OpInstall, "", nil)
 
@@ -426,9 +472,12 @@ func TestGetStepsForOperation(t *testing.T) {
 		wantSteps int
 	}{
 		{OpInstall, 5},
-		{OpSync, 3},
-		{OpSyncSingle, 3},
-		{OpBulkSync, 3},
+		{OpSync, 5},
+		{OpSyncSingle, 5},
+		{OpBulkSync, 5},
+		{OpLink, 3},
+		{OpLinkSingle, 3},
+		{OpBulkLink, 3},
 		{OpUpdate, 2},
 		{OpDoctor, 3},
 		{OpUninstall, 3},
@@ -7,6 +7,7 @@ import (
 	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/nvandessel/go4dot/internal/state"
 	"github.com/nvandessel/go4dot/internal/ui"
 )
 
@@ -16,12 +17,54 @@ type LogEntry struct {
 	Message string
 }
 
+// maxOutputLogs is how many log entries the output panel keeps; older ones
+// are dropped so long installs don't slow the dashboard down
+const maxOutputLogs = 1000
+
+// outputLine is a log entry and its rendered line
+type outputLine struct {
+	entry    LogEntry
+	rendered string
+}
+
+// logRing holds the most recent maxOutputLogs lines, oldest first from start
+type logRing struct {
+	lines   []outputLine
+	start   int
+	dropped int // Lines overwritten since the last reset
+}
+
+func (r *logRing) add(line outputLine) {
+	if len(r.lines) < maxOutputLogs {
+		r.lines = append(r.lines, line)
+		return
+	}
+	r.lines[r.start] = line
+	r.start = (r.start + 1) % len(r.lines)
+	r.dropped++
+}
+
+func (r *logRing) len() int {
+	return len(r.lines)
+}
+
+// each calls fn for every line, oldest first
+func (r *logRing) each(fn func(outputLine)) {
+	for i := range r.lines {
+		fn(r.lines[(r.start+i)%len(r.lines)])
+	}
+}
+
+func (r *logRing) reset() {
+	*r = logRing{}
+}
+
 // OutputPanel displays logs and operation output in a scrollable viewport
 // This is a scrollable panel when focused
 type OutputPanel struct {
 	BasePanel
 	viewport viewport.Model
-	logs     []LogEntry
+	logs     logRing
 	ready    bool
 }
 
@@ -33,7 +76,6 @@ func NewOutputPanel() *OutputPanel {
 	return &OutputPanel{
 		BasePanel: NewBasePanel(PanelOutput, "0 Output"),
 		viewport:  vp,
-		logs:      []LogEntry{},
 	}
 }
 
@@ -83,7 +125,7 @@ func (p *OutputPanel) View() string {
 		return ""
 	}
 
-	if len(p.logs) == 0 {
+	if p.logs.len() == 0 {
 		placeholderStyle := lipgloss.NewStyle().
 			Foreground(ui.SubtleColor).
 			Italic(true)
@@ -100,24 +142,58 @@ func (p *OutputPanel) GetSelectedItem() *SelectedItem {
 
 // AddLog adds a log entry and scrolls to bottom
 func (p *OutputPanel) AddLog(level, message string) {
-	p.logs = append(p.logs, LogEntry{Level: level, Message: message})
+	p.AddLogs(LogEntry{Level: level, Message: message})
+}
+
+// AddLogs adds log entries and scrolls to bottom, redrawing once
+func (p *OutputPanel) AddLogs(entries ...LogEntry) {
+	if len(entries) == 0 {
+		return
+	}
+	for _, entry := range entries {
+		p.logs.add(outputLine{entry: entry, rendered: p.formatLog(entry)})
+	}
 	p.updateContent()
 	p.viewport.GotoBottom()
 }
 
 // Clear removes all logs
 func (p *OutputPanel) Clear() {
-	p.logs = []LogEntry{}
+	p.logs.reset()
 	p.updateContent()
 }
 
+// HandleEvent implements Subscriber. Each operation starts with a clear log
+// titled after it, and ends with its summary or error.
+func (p *OutputPanel) HandleEvent(e Event) tea.Cmd {
+	switch e := e.(type) {
+	case OperationStartedEvent:
+		p.Clear()
+		p.SetTitle(getOperationTitle(e.Type))
+	case OperationFinishedEvent:
+		p.SetTitle("Output")
+		if e.Err != nil {
+			p.AddLog("error", fmt.Sprintf("Operation failed: %v", e.Err))
+		} else if e.Summary != "" {
+			p.AddLog("success", e.Summary)
+		}
+		if len(e.Timings) > 0 {
+			p.AddLog("info", "Timing: "+state.FormatTimings(e.Timings))
+		}
+	}
+	return nil
+}
+
 // updateContent rebuilds the viewport content from logs
 func (p *OutputPanel) updateContent() {
 	var lines []string
-	for _, log := range p.logs {
-		line := p.formatLog(log)
-		lines = append(lines, line)
+	if p.logs.dropped > 0 {
+		lines = append(lines, lipgloss.NewStyle().Foreground(ui.SubtleColor).Italic(true).
+			Render(fmt.Sprintf("… %d earlier lines not shown", p.logs.dropped)))
 	}
+	p.logs.each(func(line outputLine) {
+		lines = append(lines, line.rendered)
+	})
 
 	content := strings.Join(lines, "\n")
 	p.viewport.SetContent(content)
@@ -132,6 +208,9 @@ func (p *OutputPanel) formatLog(log LogEntry) string {
 	case "success":
 		icon = "✓"
 		style = lipgloss.NewStyle().Foreground(ui.SecondaryColor)
+	case "skipped":
+		icon = "⊘"
+		style = lipgloss.NewStyle().Foreground(ui.SubtleColor)
 	case "warning":
 		icon = "⚠"
 		style = lipgloss.NewStyle().Foreground(ui.WarningColor)
@@ -148,13 +227,15 @@ func (p *OutputPanel) formatLog(log LogEntry) string {
 
 // GetLogCount returns the number of log entries
 func (p *OutputPanel) GetLogCount() int {
-	return len(p.logs)
+	return p.logs.len()
 }
 
-// GetLogs returns a copy of all log entries
+// GetLogs returns a copy of the kept log entries, oldest first
 func (p *OutputPanel) GetLogs() []LogEntry {
-	logs := make([]LogEntry, len(p.logs))
-	copy(logs, p.logs)
+	logs := make([]LogEntry, 0, p.logs.len())
+	p.logs.each(func(line outputLine) {
+		logs = append(logs, line.entry)
+	})
 	return logs
 }
 
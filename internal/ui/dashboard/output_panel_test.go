@@ -0,0 +1,45 @@
+package dashboard
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestOutputPanel_KeepsRecentLogs(t *testing.T) {
+	p := NewOutputPanel()
+	p.SetSize(80, 10)
+
+	var entries []LogEntry
+	for i := 0; i < maxOutputLogs+250; i++ {
+		entries = append(entries, LogEntry{Level: "info", Message: fmt.Sprintf("line %d", i)})
+	}
+	p.AddLogs(entries[:10]...)
+	p.AddLogs(entries[10:]...)
+
+	logs := p.GetLogs()
+	if len(logs) != maxOutputLogs || p.GetLogCount() != maxOutputLogs {
+		t.Fatalf("kept %d logs, want %d", len(logs), maxOutputLogs)
+	}
+	if logs[0].Message != "line 250" || logs[len(logs)-1].Message != fmt.Sprintf("line %d", maxOutputLogs+249) {
+		t.Errorf("kept %q .. %q, want the most recent lines in order", logs[0].Message, logs[len(logs)-1].Message)
+	}
+	if !strings.Contains(p.viewport.View(), fmt.Sprintf("line %d", maxOutputLogs+249)) {
+		t.Error("viewport is not scrolled to the newest line")
+	}
+
+	p.ScrollToTop()
+	if !strings.Contains(p.viewport.View(), "250 earlier lines not shown") {
+		t.Errorf("dropped lines are not reported:\n%s", p.viewport.View())
+	}
+
+	p.Clear()
+	p.AddLog("info", "fresh")
+	if logs := p.GetLogs(); len(logs) != 1 || logs[0].Message != "fresh" {
+		t.Errorf("after Clear logs = %+v", logs)
+	}
+	p.ScrollToTop()
+	if strings.Contains(p.viewport.View(), "not shown") {
+		t.Error("Clear did not reset the dropped count")
+	}
+}
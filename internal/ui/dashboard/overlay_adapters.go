@@ -76,6 +76,7 @@ func overlayHelpContent(h Help) string {
 	fmt.Fprintf(&b, "%s%s\n", keyStyle.Render("m"), descStyle.Render("Configure overrides"))
 	fmt.Fprintf(&b, "%s%s\n", keyStyle.Render("tab"), descStyle.Render("More commands menu"))
 	fmt.Fprintf(&b, "%s%s\n", keyStyle.Render("?"), descStyle.Render("Toggle help screen"))
+	fmt.Fprintf(&b, "%s%s\n", keyStyle.Render("t"), descStyle.Render("Take the dashboard tour (from this screen)"))
 	fmt.Fprintf(&b, "%s%s\n", keyStyle.Render("q / esc"), descStyle.Render("Quit dashboard"))
 
 	b.WriteString(subtleStyle.Render("Press ?, q, or esc to close"))
@@ -147,14 +148,17 @@ func overlayConfirmContent(c *Confirm) string {
 	buttons := lipgloss.JoinHorizontal(lipgloss.Center, yesBtn, "  ", noBtn)
 	buttonsRow := lipgloss.NewStyle().Width(dialogWidth - 4).Align(lipgloss.Center).Render(buttons)
 
-	return lipgloss.JoinVertical(
-		lipgloss.Center,
-		titleStyle.Render(c.title),
-		"",
-		dStyle.Render(c.description),
-		"",
-		buttonsRow,
-	)
+	parts := []string{titleStyle.Render(c.title), "", dStyle.Render(c.description), ""}
+	if len(c.details) > 0 {
+		detailStyle := lipgloss.NewStyle().
+			Foreground(ui.SubtleColor).
+			Width(dialogWidth - 4).
+			MaxWidth(dialogWidth - 4)
+		parts = append(parts, detailStyle.Render(strings.Join(c.details, "\n")), "")
+	}
+	parts = append(parts, buttonsRow)
+
+	return lipgloss.JoinVertical(lipgloss.Center, parts...)
 }
 
 // overlayOnboardingContent returns the onboarding content for overlay compositing (without placement).
@@ -292,6 +296,31 @@ func overlayOnboardingContent(o *Onboarding) string {
 	return content
 }
 
+// overlayInventoryContent returns the machine inventory content for overlay compositing (without border/placement).
+func overlayInventoryContent(v *InventoryView) string {
+	if !v.ready {
+		return ""
+	}
+
+	titleStyle := lipgloss.NewStyle().
+		Foreground(ui.PrimaryColor).
+		Bold(true).
+		Padding(0, 1)
+
+	hintStyle := lipgloss.NewStyle().
+		Foreground(ui.SubtleColor).
+		Italic(true)
+
+	return lipgloss.JoinVertical(
+		lipgloss.Left,
+		titleStyle.Render("Machine Inventory"),
+		"",
+		v.viewport.View(),
+		"",
+		hintStyle.Render("Press ESC or q to close"),
+	)
+}
+
 // overlayConfigListContent returns the config list content for overlay compositing (without border/placement).
 func overlayConfigListContent(c *ConfigListView) string {
 	if !c.ready {
@@ -347,7 +376,7 @@ func overlayExternalContent(e *ExternalView) string {
 		"",
 		e.viewport.View(),
 		"",
-		hintStyle.Render("Navigate  ESC Close"),
+		hintStyle.Render(e.hints()),
 	)
 }
 
@@ -408,6 +437,10 @@ func overlayConflictContent(v *ConflictView) string {
 		}
 	}
 
+	if v.reviewing {
+		return v.reviewContent(dialogWidth)
+	}
+
 	titleStyle := lipgloss.NewStyle().
 		Foreground(ui.WarningColor).
 		Bold(true).
@@ -455,7 +488,7 @@ func overlayConflictContent(v *ConflictView) string {
 
 	for _, configName := range v.configNames {
 		files := v.byConfig[configName]
-		fileLines = append(fileLines, configNameStyle.Render(configName+":"))
+		fileLines = append(fileLines, v.configLabel(configName, configNameStyle))
 		displayedConfigs[configName] = true
 
 		showCount := len(files)
@@ -514,7 +547,7 @@ func overlayConflictContent(v *ConflictView) string {
 	buttons := lipgloss.JoinHorizontal(lipgloss.Center, backupBtn, "  ", deleteBtn, "  ", cancelBtn)
 	buttonsRow := lipgloss.NewStyle().Width(dialogWidth - 4).Align(lipgloss.Center).Render(buttons)
 
-	hints := hintStyle.Render("b Backup  d Delete  c Cancel  Enter Select")
+	hints := hintStyle.Render("b Backup  d Delete  a Adopt  c Cancel  Enter Select")
 
 	return lipgloss.JoinVertical(
 		lipgloss.Left,
@@ -529,3 +562,152 @@ func overlayConflictContent(v *ConflictView) string {
 		hints,
 	)
 }
+
+// overlaySearchContent returns the search view content for overlay compositing (without border/placement).
+func overlaySearchContent(v *SearchView) string {
+	if !v.ready {
+		return ""
+	}
+
+	titleStyle := lipgloss.NewStyle().
+		Foreground(ui.PrimaryColor).
+		Bold(true).
+		Padding(0, 1)
+
+	hintStyle := lipgloss.NewStyle().
+		Foreground(ui.SubtleColor).
+		Italic(true)
+
+	return lipgloss.JoinVertical(
+		lipgloss.Left,
+		titleStyle.Render("Search Dotfiles"),
+		"",
+		v.queryLine(),
+		"",
+		v.viewport.View(),
+		"",
+		hintStyle.Render(v.hints()),
+	)
+}
+
+// overlayTriageContent returns the failure triage content for overlay compositing (without border/placement).
+func overlayTriageContent(v *TriageView) string {
+	if !v.ready {
+		return ""
+	}
+
+	titleStyle := lipgloss.NewStyle().
+		Foreground(ui.ErrorColor).
+		Bold(true).
+		Padding(0, 1)
+
+	hintStyle := lipgloss.NewStyle().
+		Foreground(ui.SubtleColor).
+		Italic(true)
+
+	parts := []string{
+		titleStyle.Render("Operation Failed"),
+		"",
+		v.summary(),
+		"",
+		v.viewport.View(),
+		"",
+	}
+	if v.status != "" {
+		parts = append(parts, v.status)
+	}
+	parts = append(parts, hintStyle.Render("d run doctor • o open log • i report issue • ESC close"))
+	return lipgloss.JoinVertical(lipgloss.Left, parts...)
+}
+
+// overlayPlanContent returns the plan editor content for overlay compositing (without border/placement).
+func overlayPlanContent(v *PlanView) string {
+	titleStyle := lipgloss.NewStyle().
+		Foreground(ui.PrimaryColor).
+		Bold(true).
+		Padding(0, 1)
+
+	hintStyle := lipgloss.NewStyle().
+		Foreground(ui.SubtleColor).
+		Italic(true)
+
+	title := "Install Plan"
+	if v.plan.Op == OpBulkSync {
+		title = "Sync Plan"
+	}
+	parts := []string{
+		titleStyle.Render(title),
+		"",
+		v.steps(),
+		"",
+		ui.SubtleStyle.Render(v.plan.Summary()),
+	}
+	if v.status != "" {
+		parts = append(parts, ui.WarningStyle.Render(v.status))
+	}
+	parts = append(parts, "", hintStyle.Render("s skip • d dry-run • r run • K/J reorder • enter run plan • ESC cancel"))
+	return lipgloss.JoinVertical(lipgloss.Left, parts...)
+}
+
+// overlayFormContent returns a form's content for overlay compositing (without border/placement).
+func overlayFormContent(f *FormView) string {
+	titleStyle := lipgloss.NewStyle().
+		Foreground(ui.PrimaryColor).
+		Bold(true).
+		Padding(0, 1)
+
+	hintStyle := lipgloss.NewStyle().
+		Foreground(ui.SubtleColor).
+		Italic(true)
+
+	return lipgloss.JoinVertical(lipgloss.Left,
+		titleStyle.Render(f.title),
+		"",
+		f.form.View(),
+		"",
+		hintStyle.Render("tab/shift+tab move • enter next • ESC cancel"),
+	)
+}
+
+// overlayPasswordContent returns the password prompt content for overlay compositing (without border/placement).
+func overlayPasswordContent(p *PasswordPrompt) string {
+	dialogWidth := 50
+	if p.width > 0 && p.width < dialogWidth+20 {
+		dialogWidth = p.width - 20
+		if dialogWidth < 30 {
+			dialogWidth = 30
+		}
+	}
+
+	titleStyle := lipgloss.NewStyle().
+		Foreground(ui.PrimaryColor).
+		Bold(true).
+		Width(dialogWidth - 4).
+		Align(lipgloss.Center)
+
+	promptStyle := lipgloss.NewStyle().
+		Foreground(ui.TextColor).
+		Width(dialogWidth - 4).
+		Align(lipgloss.Center)
+
+	inputStyle := lipgloss.NewStyle().
+		Border(lipgloss.NormalBorder()).
+		BorderForeground(ui.SubtleColor).
+		Width(dialogWidth - 6)
+
+	hintStyle := lipgloss.NewStyle().
+		Foreground(ui.SubtleColor).
+		Width(dialogWidth - 4).
+		Align(lipgloss.Center)
+
+	return lipgloss.JoinVertical(
+		lipgloss.Center,
+		titleStyle.Render("Password required"),
+		"",
+		promptStyle.Render(p.prompt),
+		"",
+		inputStyle.Render(p.masked()+"█"),
+		"",
+		hintStyle.Render("enter: submit • esc: cancel"),
+	)
+}
@@ -1,14 +1,18 @@
 package dashboard
 
 import (
+	"os"
 	"path/filepath"
 	"strings"
 	"testing"
 
 	"github.com/charmbracelet/bubbles/spinner"
 	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/huh"
 	"github.com/nvandessel/go4dot/internal/config"
+	"github.com/nvandessel/go4dot/internal/deps"
+	"github.com/nvandessel/go4dot/internal/platform"
 	"github.com/nvandessel/go4dot/internal/stow"
 )
 
@@ -303,3 +307,59 @@ func TestOverlayConflictContent_DisplayPath(t *testing.T) {
 		t.Fatalf("expected conflict content to include absolute outside path, got %q", content)
 	}
 }
+
+func TestExternalView_Details(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "README.md"), []byte("# Fancy Plugin\nDoes things."), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	view := NewExternalView(&config.Config{}, t.TempDir(), &platform.Platform{OS: "linux"})
+	view.SetSize(80, 30)
+	view.loading = false
+	view.status = []deps.ExternalStatus{{
+		Dep: config.ExternalDep{
+			ID:        "fancy",
+			Name:      "Fancy",
+			URL:       "https://example.com/fancy.git",
+			Condition: map[string]string{"os": "linux"},
+		},
+		Status: "installed",
+		Path:   dir,
+	}}
+	view.updateContent()
+
+	_, cmd := view.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if !view.showDetails || cmd == nil {
+		t.Fatal("expected enter to open details and load them")
+	}
+
+	msg := cmd()
+	if batch, ok := msg.(tea.BatchMsg); ok {
+		for _, c := range batch {
+			if c == nil {
+				continue
+			}
+			if m, ok := c().(externalDetailsLoadedMsg); ok {
+				msg = m
+			}
+		}
+	}
+	view.Update(msg)
+
+	content := overlayExternalContent(view)
+	for _, want := range []string{"Destination", dir, "✓ os=linux", "Fancy Plugin", "ESC Back"} {
+		if !strings.Contains(content, want) {
+			t.Errorf("expected details to contain %q, got:\n%s", want, content)
+		}
+	}
+
+	// Esc returns to the list instead of closing
+	_, cmd = view.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if view.showDetails {
+		t.Error("expected esc to leave details mode")
+	}
+	if cmd != nil {
+		t.Error("expected esc in details not to close the view")
+	}
+}
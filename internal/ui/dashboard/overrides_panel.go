@@ -204,6 +204,16 @@ func (p *OverridesPanel) RefreshStatus() {
 	}
 }
 
+// HandleEvent implements Subscriber
+func (p *OverridesPanel) HandleEvent(e Event) tea.Cmd {
+	if e, ok := e.(StatusUpdatedEvent); ok {
+		p.cfg = e.State.Config
+		p.machineStatus = nil
+		p.RefreshStatus()
+	}
+	return nil
+}
+
 // HasOverrides returns true if there are any machine configs
 func (p *OverridesPanel) HasOverrides() bool {
 	return p.cfg != nil && len(p.cfg.MachineConfig) > 0
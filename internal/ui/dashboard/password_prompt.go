@@ -0,0 +1,157 @@
+package dashboard
+
+import (
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/nvandessel/go4dot/internal/ui"
+)
+
+// passwordRequestMsg is sent by a running operation that needs a password,
+// e.g. for sudo before installing packages. The operation waits for the
+// answer on reply.
+type passwordRequestMsg struct {
+	ID     int // Operation that sent the message
+	Prompt string
+	reply  chan<- passwordReply
+}
+
+// passwordReply answers a passwordRequestMsg
+type passwordReply struct {
+	password string
+	canceled bool
+}
+
+// passwordDoneMsg is sent when the password prompt is answered or dismissed
+type passwordDoneMsg struct{}
+
+// PasswordPrompt asks for a password with masked input on behalf of a
+// running operation. The password is only handed to the operation; it is
+// never logged, recorded in a macro or kept once answered.
+type PasswordPrompt struct {
+	prompt   string
+	input    []rune
+	reply    chan<- passwordReply
+	answered bool
+	width    int
+	height   int
+}
+
+// NewPasswordPrompt creates a prompt answering on reply
+func NewPasswordPrompt(prompt string, reply chan<- passwordReply) *PasswordPrompt {
+	return &PasswordPrompt{prompt: prompt, reply: reply}
+}
+
+// SetSize updates the prompt dimensions
+func (p *PasswordPrompt) SetSize(width, height int) {
+	p.width = width
+	p.height = height
+}
+
+// Init initializes the prompt
+func (p *PasswordPrompt) Init() tea.Cmd {
+	return nil
+}
+
+// Update handles key input: enter submits, esc cancels the operation's
+// request
+func (p *PasswordPrompt) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return p, nil
+	}
+	switch {
+	case key.Matches(keyMsg, key.NewBinding(key.WithKeys("enter"))):
+		p.answer(passwordReply{password: string(p.input)})
+		return p, func() tea.Msg { return passwordDoneMsg{} }
+	case key.Matches(keyMsg, key.NewBinding(key.WithKeys("esc", "ctrl+c"))):
+		p.Cancel()
+		return p, func() tea.Msg { return passwordDoneMsg{} }
+	case key.Matches(keyMsg, key.NewBinding(key.WithKeys("backspace"))):
+		if len(p.input) > 0 {
+			p.input = p.input[:len(p.input)-1]
+		}
+	case key.Matches(keyMsg, key.NewBinding(key.WithKeys("ctrl+u"))):
+		p.input = nil
+	default:
+		if keyMsg.Type == tea.KeyRunes || keyMsg.Type == tea.KeySpace {
+			if keyMsg.Type == tea.KeySpace && len(keyMsg.Runes) == 0 {
+				p.input = append(p.input, ' ')
+			}
+			p.input = append(p.input, keyMsg.Runes...)
+		}
+	}
+	return p, nil
+}
+
+// Cancel tells the waiting operation the prompt was dismissed
+func (p *PasswordPrompt) Cancel() {
+	p.answer(passwordReply{canceled: true})
+}
+
+// answer hands the reply to the operation once and forgets the input. The
+// reply channel is buffered, so this never blocks even if the operation
+// was canceled meanwhile.
+func (p *PasswordPrompt) answer(r passwordReply) {
+	if p.answered {
+		return
+	}
+	p.answered = true
+	p.input = nil
+	select {
+	case p.reply <- r:
+	default:
+	}
+}
+
+// View renders the prompt for overlay compositing
+func (p *PasswordPrompt) View() string {
+	return overlayPasswordContent(p)
+}
+
+// masked returns one bullet per typed character
+func (p *PasswordPrompt) masked() string {
+	out := make([]rune, len(p.input))
+	for i := range out {
+		out[i] = '•'
+	}
+	return string(out)
+}
+
+// askPassword opens the password prompt for an operation's request
+func (m *Model) askPassword(msg passwordRequestMsg) {
+	if m.password != nil {
+		// Only one operation runs at a time; a stale prompt is dismissed
+		m.password.Cancel()
+		m.popView()
+	}
+	m.password = NewPasswordPrompt(msg.Prompt, msg.reply)
+	contentWidth, contentHeight := overlayContentSize(m.width, m.height, ui.ConfirmOverlayStyle())
+	m.password.SetSize(contentWidth, contentHeight)
+	m.pushView(viewPassword)
+}
+
+// updatePassword handles messages while the password prompt is open
+func (m *Model) updatePassword(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		if m.password != nil {
+			contentWidth, contentHeight := overlayContentSize(msg.Width, msg.Height, ui.ConfirmOverlayStyle())
+			m.password.SetSize(contentWidth, contentHeight)
+		}
+		return m, nil
+
+	case passwordDoneMsg:
+		m.password = nil
+		m.popView()
+		return m, nil
+	}
+
+	if m.password == nil {
+		m.popView()
+		return m, nil
+	}
+	_, cmd := m.password.Update(msg)
+	return m, cmd
+}
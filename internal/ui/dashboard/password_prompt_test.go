@@ -0,0 +1,50 @@
+package dashboard
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestPasswordPrompt_MasksAndSubmits(t *testing.T) {
+	reply := make(chan passwordReply, 1)
+	p := NewPasswordPrompt("[sudo] password", reply)
+	p.SetSize(60, 10)
+
+	p.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("hunter22")})
+	p.Update(tea.KeyMsg{Type: tea.KeyBackspace})
+
+	view := p.View()
+	if strings.Contains(view, "hunter2") {
+		t.Error("View() shows the typed password")
+	}
+	if !strings.Contains(view, strings.Repeat("•", 7)) {
+		t.Error("View() should show one bullet per typed character")
+	}
+
+	_, cmd := p.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if cmd == nil {
+		t.Fatal("enter should close the prompt")
+	}
+	if got := <-reply; got.canceled || got.password != "hunter2" {
+		t.Errorf("reply = %+v, want password hunter2", got)
+	}
+	if len(p.input) != 0 {
+		t.Error("prompt should forget the password once answered")
+	}
+}
+
+func TestPasswordPrompt_EscCancels(t *testing.T) {
+	reply := make(chan passwordReply, 1)
+	p := NewPasswordPrompt("[sudo] password", reply)
+
+	p.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("abc")})
+	p.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	// A second answer must not block or replace the first
+	p.Cancel()
+
+	if got := <-reply; !got.canceled {
+		t.Errorf("reply = %+v, want canceled", got)
+	}
+}
@@ -0,0 +1,250 @@
+package dashboard
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/nvandessel/go4dot/internal/config"
+	"github.com/nvandessel/go4dot/internal/stow"
+)
+
+// PlanStepKind is what a step of a plan does
+type PlanStepKind int
+
+const (
+	PlanDependencies PlanStepKind = iota
+	PlanToolchains
+	PlanConfig
+	PlanExternal
+	PlanMachine
+	PlanShell
+)
+
+// PlanAction is what to do with a step when the plan runs
+type PlanAction int
+
+const (
+	PlanRun    PlanAction = iota
+	PlanSkip              // Leave the step out
+	PlanDryRun            // Report what the step would change without changing it
+)
+
+// String returns the action as the plan editor shows it, like the verbs of
+// git rebase -i
+func (a PlanAction) String() string {
+	switch a {
+	case PlanSkip:
+		return "skip"
+	case PlanDryRun:
+		return "dry-run"
+	default:
+		return "run"
+	}
+}
+
+// PlanStep is one step of an install or bulk sync: a stage, or linking
+// one config
+type PlanStep struct {
+	Kind   PlanStepKind
+	Config string // The config to link, for PlanConfig steps
+	Action PlanAction
+}
+
+// Label describes the step
+func (s PlanStep) Label() string {
+	switch s.Kind {
+	case PlanDependencies:
+		return "Install missing dependencies"
+	case PlanToolchains:
+		return "Install toolchains"
+	case PlanExternal:
+		return "Clone external dependencies"
+	case PlanMachine:
+		return "Configure machine settings"
+	case PlanShell:
+		return "Wire shell integration"
+	default:
+		return "Link " + s.Config
+	}
+}
+
+// Plan is the editable list of steps an install or bulk sync runs. Stages
+// keep their order; configs can be reordered among themselves as long as
+// each stays after the configs it depends on.
+type Plan struct {
+	Op    OperationType // OpInstall or OpBulkSync
+	Steps []PlanStep
+
+	dependsOn map[string][]string
+}
+
+// NewInstallPlan returns the steps an install with opts runs, all set to run
+func NewInstallPlan(cfg *config.Config, opts InstallOptions) *Plan {
+	p := newPlan(OpInstall, cfg)
+	if !opts.SkipDeps {
+		p.Steps = append(p.Steps, PlanStep{Kind: PlanDependencies})
+	}
+	if !opts.SkipToolchains && len(cfg.Toolchains) > 0 {
+		p.Steps = append(p.Steps, PlanStep{Kind: PlanToolchains})
+	}
+	if !opts.SkipStow {
+		for _, c := range installConfigs(cfg, opts) {
+			p.Steps = append(p.Steps, PlanStep{Kind: PlanConfig, Config: c.Name})
+		}
+	}
+	if !opts.SkipExternal && len(cfg.External) > 0 {
+		p.Steps = append(p.Steps, PlanStep{Kind: PlanExternal})
+	}
+	if !opts.SkipMachine && len(cfg.MachineConfig) > 0 {
+		p.Steps = append(p.Steps, PlanStep{Kind: PlanMachine})
+	}
+	if cfg.ShellIntegration.Enabled {
+		p.Steps = append(p.Steps, PlanStep{Kind: PlanShell})
+	}
+	return p
+}
+
+// NewSyncPlan returns the steps a bulk sync of configNames runs, all set to
+// run. A full sync installs dependencies and clones externals after linking.
+func NewSyncPlan(cfg *config.Config, configNames []string, full bool) *Plan {
+	p := newPlan(OpBulkSync, cfg)
+	for _, name := range configNames {
+		p.Steps = append(p.Steps, PlanStep{Kind: PlanConfig, Config: name})
+	}
+	if full {
+		p.Steps = append(p.Steps, PlanStep{Kind: PlanDependencies}, PlanStep{Kind: PlanExternal})
+	}
+	return p
+}
+
+func newPlan(op OperationType, cfg *config.Config) *Plan {
+	p := &Plan{Op: op, dependsOn: make(map[string][]string)}
+	for _, c := range cfg.GetAllConfigs() {
+		p.dependsOn[c.Name] = c.DependsOn
+	}
+	return p
+}
+
+// SetAction sets the action of step i, or sets it back to run when it
+// already has that action
+func (p *Plan) SetAction(i int, action PlanAction) {
+	if i < 0 || i >= len(p.Steps) {
+		return
+	}
+	if p.Steps[i].Action == action {
+		action = PlanRun
+	}
+	p.Steps[i].Action = action
+}
+
+// Move swaps step i with its neighbour delta (-1 or 1) steps away. Only
+// configs move, only past other configs, and never before a config they
+// depend on.
+func (p *Plan) Move(i, delta int) error {
+	j := i + delta
+	if i < 0 || i >= len(p.Steps) || j < 0 || j >= len(p.Steps) {
+		return fmt.Errorf("nothing to swap with")
+	}
+	a, b := p.Steps[i], p.Steps[j]
+	if a.Kind != PlanConfig || b.Kind != PlanConfig {
+		return fmt.Errorf("only configs can be reordered; stages keep their order")
+	}
+	first, second := a, b
+	if delta > 0 {
+		first, second = b, a
+	}
+	// After the swap first comes before second, so second must not be
+	// something first depends on
+	if p.dependsOnConfig(first.Config, second.Config) {
+		return fmt.Errorf("%s depends on %s", first.Config, second.Config)
+	}
+	p.Steps[i], p.Steps[j] = b, a
+	return nil
+}
+
+func (p *Plan) dependsOnConfig(name, dep string) bool {
+	for _, d := range p.dependsOn[name] {
+		if d == dep {
+			return true
+		}
+	}
+	return false
+}
+
+// stage returns what to do with a stage. Without a plan every stage runs;
+// a stage the plan leaves out is skipped.
+func (p *Plan) stage(kind PlanStepKind) PlanAction {
+	if p == nil {
+		return PlanRun
+	}
+	for _, s := range p.Steps {
+		if s.Kind == kind {
+			return s.Action
+		}
+	}
+	return PlanSkip
+}
+
+// configs returns the configs with the given action, in plan order
+func (p *Plan) configs(action PlanAction) []string {
+	var names []string
+	for _, s := range p.Steps {
+		if s.Kind == PlanConfig && s.Action == action {
+			names = append(names, s.Config)
+		}
+	}
+	return names
+}
+
+// Summary counts the steps by action
+func (p *Plan) Summary() string {
+	var run, skipped, dry int
+	for _, s := range p.Steps {
+		switch s.Action {
+		case PlanSkip:
+			skipped++
+		case PlanDryRun:
+			dry++
+		default:
+			run++
+		}
+	}
+	return fmt.Sprintf("%d to run, %d skipped, %d dry-run", run, skipped, dry)
+}
+
+// configItems looks up configs by name, keeping their order
+func configItems(cfg *config.Config, names []string) []config.ConfigItem {
+	var items []config.ConfigItem
+	for _, name := range names {
+		if item := cfg.GetConfigByName(name); item != nil {
+			items = append(items, *item)
+		}
+	}
+	return items
+}
+
+// itemNames returns the names of items
+func itemNames(items []config.ConfigItem) []string {
+	names := make([]string, 0, len(items))
+	for _, item := range items {
+		names = append(names, item.Name)
+	}
+	return names
+}
+
+// dryRunStow logs what linking the configs would change, without changing
+// anything
+func dryRunStow(runner *OperationRunner, cfg *config.Config, dotfilesPath string, names []string) {
+	if len(names) == 0 {
+		return
+	}
+	runner.Log("info", fmt.Sprintf("Dry run: %s", strings.Join(names, ", ")))
+	result := stow.StowConfigs(dotfilesPath, configItems(cfg, names), stow.StowOptions{
+		DryRun:     true,
+		Settings:   cfg.Stow,
+		ActionFunc: logStowAction(runner, 2),
+	})
+	for _, f := range result.Failed {
+		runner.Log("warning", fmt.Sprintf("Dry run: %s would fail - %v", f.ConfigName, f.Error))
+	}
+}
@@ -0,0 +1,124 @@
+package dashboard
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/nvandessel/go4dot/internal/config"
+)
+
+func planTestConfig() *config.Config {
+	return &config.Config{
+		Configs: config.ConfigGroups{
+			Core: []config.ConfigItem{
+				{Name: "git", Path: "git"},
+				{Name: "zsh", Path: "zsh", DependsOn: []string{"git"}},
+			},
+			Optional: []config.ConfigItem{{Name: "tmux", Path: "tmux"}},
+		},
+		External: []config.ExternalDep{{ID: "tpm", URL: "https://github.com/tmux-plugins/tpm"}},
+	}
+}
+
+func TestNewInstallPlan(t *testing.T) {
+	cfg := planTestConfig()
+
+	plan := NewInstallPlan(cfg, InstallOptions{SkipDeps: true})
+	var labels []string
+	for _, s := range plan.Steps {
+		labels = append(labels, s.Label())
+	}
+	want := []string{"Link git", "Link zsh", "Link tmux", "Clone external dependencies"}
+	if !reflect.DeepEqual(labels, want) {
+		t.Errorf("steps = %v, want %v", labels, want)
+	}
+
+	// Stages left out of the plan are skipped; without a plan all run
+	if got := plan.stage(PlanDependencies); got != PlanSkip {
+		t.Errorf("stage(PlanDependencies) = %v, want skip", got)
+	}
+	var none *Plan
+	if got := none.stage(PlanDependencies); got != PlanRun {
+		t.Errorf("nil plan stage = %v, want run", got)
+	}
+}
+
+func TestPlan_Move(t *testing.T) {
+	plan := NewSyncPlan(planTestConfig(), []string{"git", "zsh", "tmux"}, true)
+
+	// zsh depends on git, so it can't move before it
+	if err := plan.Move(1, -1); err == nil || !strings.Contains(err.Error(), "zsh depends on git") {
+		t.Errorf("Move(zsh up) error = %v, want a dependency error", err)
+	}
+	if err := plan.Move(0, 1); err == nil {
+		t.Error("Move(git down) should fail: zsh would come before it")
+	}
+
+	if err := plan.Move(2, -1); err != nil {
+		t.Fatalf("Move(tmux up) error = %v", err)
+	}
+	if got := plan.configs(PlanRun); !reflect.DeepEqual(got, []string{"git", "tmux", "zsh"}) {
+		t.Errorf("configs = %v", got)
+	}
+
+	// Stages keep their place
+	if err := plan.Move(2, 1); err == nil {
+		t.Error("Move() should not swap a config with a stage")
+	}
+}
+
+func TestPlan_SetAction(t *testing.T) {
+	plan := NewSyncPlan(planTestConfig(), []string{"git", "zsh", "tmux"}, false)
+	plan.SetAction(0, PlanDryRun)
+	plan.SetAction(2, PlanSkip)
+
+	if got := plan.configs(PlanRun); !reflect.DeepEqual(got, []string{"zsh"}) {
+		t.Errorf("run = %v", got)
+	}
+	if got := plan.configs(PlanDryRun); !reflect.DeepEqual(got, []string{"git"}) {
+		t.Errorf("dry-run = %v", got)
+	}
+	if got := plan.Summary(); got != "1 to run, 1 skipped, 1 dry-run" {
+		t.Errorf("Summary() = %q", got)
+	}
+
+	// Setting the same action again toggles back to run
+	plan.SetAction(2, PlanSkip)
+	if plan.Steps[2].Action != PlanRun {
+		t.Errorf("action = %v, want run", plan.Steps[2].Action)
+	}
+}
+
+func TestPlanView_Keys(t *testing.T) {
+	v := NewPlanView(NewSyncPlan(planTestConfig(), []string{"git", "zsh", "tmux"}, false))
+	v.SetSize(80, 30)
+
+	press := func(keys ...string) tea.Cmd {
+		var cmd tea.Cmd
+		for _, k := range keys {
+			_, cmd = v.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(k)})
+		}
+		return cmd
+	}
+
+	press("j", "j", "K", "s")
+	if got := v.plan.configs(PlanRun); !reflect.DeepEqual(got, []string{"git", "zsh"}) {
+		t.Errorf("run = %v, want git, zsh", got)
+	}
+	if got := v.plan.configs(PlanSkip); !reflect.DeepEqual(got, []string{"tmux"}) {
+		t.Errorf("skip = %v, want tmux", got)
+	}
+
+	// A blocked move is explained
+	press("j", "K", "K")
+	if !strings.Contains(v.View(), "zsh depends on git") {
+		t.Errorf("expected the blocked move to be explained, got:\n%s", v.View())
+	}
+
+	_, cmd := v.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if msg, ok := cmd().(PlanViewCloseMsg); !ok || !msg.Run {
+		t.Errorf("enter = %#v, want a run", cmd())
+	}
+}
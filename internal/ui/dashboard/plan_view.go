@@ -0,0 +1,176 @@
+package dashboard
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/nvandessel/go4dot/internal/ui"
+)
+
+// PlanViewCloseMsg is sent when the plan editor closes. Run is true when
+// the edited plan should run.
+type PlanViewCloseMsg struct {
+	Run bool
+}
+
+// PlanView edits a plan before it runs, like git rebase -i: each step can
+// be skipped or marked dry-run, and configs can be reordered
+type PlanView struct {
+	plan   *Plan
+	cursor int
+	status string
+	width  int
+	height int
+}
+
+// NewPlanView opens the plan editor on plan
+func NewPlanView(plan *Plan) *PlanView {
+	return &PlanView{plan: plan}
+}
+
+// Init initializes the plan editor
+func (v *PlanView) Init() tea.Cmd {
+	return nil
+}
+
+// SetSize updates the view dimensions
+func (v *PlanView) SetSize(width, height int) {
+	v.width = width
+	v.height = height
+}
+
+// Update handles messages
+func (v *PlanView) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return v, nil
+	}
+
+	v.status = ""
+	switch keyMsg.String() {
+	case "esc", "q":
+		return v, func() tea.Msg { return PlanViewCloseMsg{} }
+	case "enter":
+		return v, func() tea.Msg { return PlanViewCloseMsg{Run: true} }
+	case "up", "k":
+		if v.cursor > 0 {
+			v.cursor--
+		}
+	case "down", "j":
+		if v.cursor < len(v.plan.Steps)-1 {
+			v.cursor++
+		}
+	case "K", "shift+up":
+		v.move(-1)
+	case "J", "shift+down":
+		v.move(1)
+	case "s", " ":
+		v.plan.SetAction(v.cursor, PlanSkip)
+	case "d":
+		v.plan.SetAction(v.cursor, PlanDryRun)
+	case "r":
+		v.plan.SetAction(v.cursor, PlanRun)
+	}
+	return v, nil
+}
+
+// move moves the step under the cursor, following it
+func (v *PlanView) move(delta int) {
+	if err := v.plan.Move(v.cursor, delta); err != nil {
+		v.status = err.Error()
+		return
+	}
+	v.cursor += delta
+}
+
+// View renders the plan editor
+func (v *PlanView) View() string {
+	return overlayPlanContent(v)
+}
+
+// steps renders one line per step: cursor, action and label
+func (v *PlanView) steps() string {
+	actionStyles := map[PlanAction]lipgloss.Style{
+		PlanRun:    lipgloss.NewStyle().Foreground(ui.SecondaryColor),
+		PlanSkip:   lipgloss.NewStyle().Foreground(ui.SubtleColor),
+		PlanDryRun: lipgloss.NewStyle().Foreground(ui.WarningColor),
+	}
+	var lines []string
+	for i, s := range v.plan.Steps {
+		cursor := "  "
+		if i == v.cursor {
+			cursor = lipgloss.NewStyle().Foreground(ui.PrimaryColor).Render("> ")
+		}
+		label := s.Label()
+		if s.Action == PlanSkip {
+			label = lipgloss.NewStyle().Foreground(ui.SubtleColor).Strikethrough(true).Render(label)
+		}
+		lines = append(lines, fmt.Sprintf("%s%s %s", cursor, actionStyles[s.Action].Render(fmt.Sprintf("%-7s", s.Action)), label))
+	}
+	if len(lines) == 0 {
+		return ui.SubtleStyle.Render("Nothing to do.")
+	}
+	return strings.Join(lines, "\n")
+}
+
+// openPlan opens the plan editor on a full sync of the selected configs,
+// or on an install when none are selected
+func (m *Model) openPlan() tea.Cmd {
+	if m.state.Config == nil || m.operationActive {
+		return nil
+	}
+
+	var scope []string
+	plan := NewInstallPlan(m.state.Config, InstallOptions{})
+	rerun := "i"
+	if len(m.selectedConfigs) > 0 {
+		scope = m.selectedConfigNames()
+		plan = NewSyncPlan(m.state.Config, scope, true)
+		rerun = "S"
+	}
+
+	// The plan runs without the conflict dialog, so conflicts are
+	// resolved first
+	conflicts, err := m.checkForConflicts(scope)
+	if err != nil {
+		m.outputPanel.AddLog("error", fmt.Sprintf("Failed to check conflicts: %v", err))
+		return nil
+	}
+	if len(conflicts) > 0 {
+		m.outputPanel.AddLog("warning", fmt.Sprintf("%d file(s) are in the way; press %s to resolve them before planning", len(conflicts), rerun))
+		return nil
+	}
+
+	m.planView = NewPlanView(plan)
+	contentWidth, contentHeight := overlayContentSize(m.width, m.height, ui.DefaultOverlayStyle())
+	m.planView.SetSize(contentWidth, contentHeight)
+	m.pushView(viewPlan)
+	return nil
+}
+
+// runPlan runs an edited plan inline
+func (m *Model) runPlan(plan *Plan) tea.Cmd {
+	opCfg, opPath := m.state.Config, m.state.DotfilesPath
+	escalation := m.state.Preferences.EscalationTool()
+
+	if plan.Op == OpBulkSync {
+		names := plan.configs(PlanRun)
+		opts := SyncOptions{Full: true, UseTrash: m.state.Preferences.TrashEnabled(), Escalation: escalation, Jobs: m.state.Preferences.Workers(), Plan: plan}
+		return m.StartInlineOperation(OpBulkSync, "", names, func(runner *OperationRunner) error {
+			if _, err := RunBulkSyncOperation(runner, opCfg, opPath, names, opts); err != nil {
+				return fmt.Errorf("bulk sync: %w", err)
+			}
+			return nil
+		})
+	}
+
+	opts := InstallOptions{Escalation: escalation, Plan: plan}
+	return m.StartInlineOperation(OpInstall, "", nil, func(runner *OperationRunner) error {
+		if _, err := RunInstallOperation(runner, opCfg, opPath, opts); err != nil {
+			return fmt.Errorf("install: %w", err)
+		}
+		return nil
+	})
+}
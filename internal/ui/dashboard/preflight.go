@@ -0,0 +1,43 @@
+package dashboard
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/nvandessel/go4dot/internal/config"
+	"github.com/nvandessel/go4dot/internal/platform"
+	"github.com/nvandessel/go4dot/internal/setup"
+)
+
+// runPreflight runs setup.Preflight as part of step, logging each problem
+// and failing the step when it finds any
+func runPreflight(runner *OperationRunner, step int, cfg *config.Config, dotfilesPath string, opts setup.PreflightOptions) error {
+	runner.Progress(step, "Checking permissions and disk space...")
+
+	err := setup.Preflight(cfg, dotfilesPath, opts)
+	var pre *setup.PreflightError
+	if errors.As(err, &pre) {
+		for _, p := range pre.Problems {
+			if p.Path != "" {
+				runner.Log("error", fmt.Sprintf("%s: %s: %s", p.Check, p.Path, p.Detail))
+			} else {
+				runner.Log("error", fmt.Sprintf("%s: %s", p.Check, p.Detail))
+			}
+		}
+		runner.StepComplete(step, StepError, fmt.Sprintf("Preflight found %d problem(s)", len(pre.Problems)))
+	}
+	return err
+}
+
+// syncPreflightOptions returns what a sync of configs checks before it
+// starts; a full sync also installs dependencies
+func syncPreflightOptions(configs []config.ConfigItem, opts SyncOptions) setup.PreflightOptions {
+	pre := setup.PreflightOptions{Configs: configs, Escalation: opts.Escalation}
+	if opts.Full {
+		if p, err := platform.Detect(); err == nil {
+			pre.Deps = true
+			pre.Platform = p
+		}
+	}
+	return pre
+}
@@ -0,0 +1,137 @@
+package dashboard
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/nvandessel/go4dot/internal/config"
+	"github.com/nvandessel/go4dot/internal/state"
+)
+
+// profileItem is a profile in the profile switcher; an empty name selects
+// every config
+type profileItem struct {
+	name, title, desc string
+}
+
+func (i profileItem) Title() string       { return i.title }
+func (i profileItem) Description() string { return i.desc }
+func (i profileItem) FilterValue() string { return i.title }
+
+// NewProfileMenu lists the profiles of cfg, with the active one selected
+func NewProfileMenu(cfg *config.Config) Menu {
+	all := profileItem{title: "All configs", desc: "Use every config, dependency and external dependency"}
+	if cfg.ActiveProfile == "" {
+		all.title += " (active)"
+	}
+	items := []list.Item{all}
+	selected := 0
+	for _, p := range cfg.Profiles {
+		item := profileItem{name: p.Name, title: p.Name, desc: p.Description}
+		if item.desc == "" {
+			item.desc = fmt.Sprintf("%s configs, %s deps, %s external", config.ProfileCount(p.Configs), config.ProfileCount(p.Dependencies), config.ProfileCount(p.External))
+		}
+		if p.Name == cfg.ActiveProfile {
+			item.title += " (active)"
+			selected = len(items)
+		}
+		items = append(items, item)
+	}
+
+	l := list.New(items, list.NewDefaultDelegate(), 0, 0)
+	l.Title = "Switch Profile"
+	l.SetShowStatusBar(false)
+	l.SetFilteringEnabled(false)
+	l.SetShowHelp(false)
+	l.Select(selected)
+
+	return Menu{list: l}
+}
+
+// openProfileMenu opens the profile switcher
+func (m *Model) openProfileMenu() tea.Cmd {
+	if m.state.Config == nil || m.operationActive {
+		return nil
+	}
+	if len(m.state.Config.Profiles) == 0 {
+		m.outputPanel.AddLog("info", "No profiles defined; add a 'profiles' section to .go4dot.yaml")
+		return nil
+	}
+	if m.refuseReadOnly("switching profiles") {
+		return nil
+	}
+
+	menu := NewProfileMenu(m.state.Config)
+	menu.SetSize(m.width, m.height)
+	m.profileMenu = &menu
+	m.pushView(viewProfile)
+	return nil
+}
+
+// updateProfile handles messages for the profile switcher
+func (m *Model) updateProfile(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		m.profileMenu.SetSize(msg.Width, msg.Height)
+		return m, nil
+
+	case tea.KeyMsg:
+		switch {
+		case key.Matches(msg, keys.Quit):
+			m.popView()
+			m.profileMenu = nil
+			return m, nil
+		case key.Matches(msg, keys.Enter):
+			item, ok := m.profileMenu.list.SelectedItem().(profileItem)
+			m.popView()
+			m.profileMenu = nil
+			if ok {
+				return m, m.switchProfile(item.name)
+			}
+			return m, nil
+		}
+	}
+
+	var cmd tea.Cmd
+	m.profileMenu.list, cmd = m.profileMenu.list.Update(msg)
+	return m, cmd
+}
+
+// switchProfile makes name the profile this machine uses, as
+// g4d profile use does, and reloads the dashboard narrowed to it. An empty
+// name goes back to every config. Nothing is linked until the next sync.
+func (m *Model) switchProfile(name string) tea.Cmd {
+	if m.state.Demo {
+		m.outputPanel.AddLog("info", "Demo mode: the profile was not changed")
+		return nil
+	}
+
+	previous := os.Getenv(config.EnvProfile)
+	if name == "" {
+		_ = os.Setenv(config.EnvProfile, config.AllProfiles)
+	} else {
+		_ = os.Setenv(config.EnvProfile, name)
+	}
+	cfg, err := config.LoadFromPath(filepath.Join(m.state.DotfilesPath, config.ConfigFileName))
+	if err != nil {
+		_ = os.Setenv(config.EnvProfile, previous)
+		m.outputPanel.AddLog("error", fmt.Sprintf("Failed to reload config: %v", err))
+		return nil
+	}
+	if err := state.SaveProfile(name); err != nil {
+		m.outputPanel.AddLog("warning", fmt.Sprintf("Profile not saved for later runs: %v", err))
+	}
+
+	if cfg.ActiveProfile == "" {
+		m.outputPanel.AddLog("success", "Using every config; sync to link them")
+	} else {
+		m.outputPanel.AddLog("success", fmt.Sprintf("Using profile %s: %d configs; sync to link them", cfg.ActiveProfile, len(cfg.GetAllConfigs())))
+	}
+	return m.loadConfig(cfg, m.state.DotfilesPath)
+}
@@ -0,0 +1,89 @@
+package dashboard
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/nvandessel/go4dot/internal/config"
+	"github.com/nvandessel/go4dot/internal/platform"
+	"github.com/nvandessel/go4dot/internal/state"
+)
+
+func TestSwitchProfile(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv(config.EnvProfile, "")
+
+	dir := t.TempDir()
+	const yaml = `schema_version: "1.0"
+configs:
+  core:
+    - name: git
+      path: git
+    - name: zsh
+      path: zsh
+profiles:
+  - name: server
+    description: Headless boxes
+    configs: [git]
+`
+	configPath := filepath.Join(dir, config.ConfigFileName)
+	if err := os.WriteFile(configPath, []byte(yaml), 0644); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := config.LoadFromPath(configPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	m := New(State{
+		Platform:     &platform.Platform{OS: "linux"},
+		Configs:      cfg.GetAllConfigs(),
+		Config:       cfg,
+		DotfilesPath: dir,
+		HasConfig:    true,
+	})
+	m.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+
+	m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("P")})
+	if m.currentView != viewProfile || m.profileMenu == nil {
+		t.Fatalf("expected the profile switcher, got view %v", m.currentView)
+	}
+	if item := m.profileMenu.list.SelectedItem().(profileItem); item.title != "All configs (active)" {
+		t.Errorf("selected %q, want the active 'All configs'", item.title)
+	}
+
+	m.Update(tea.KeyMsg{Type: tea.KeyDown})
+	m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if m.currentView != viewDashboard {
+		t.Fatalf("expected the dashboard after switching, got view %v", m.currentView)
+	}
+	if m.state.Config.ActiveProfile != "server" || len(m.state.Configs) != 1 {
+		t.Errorf("expected only the server profile's configs, got %q %v", m.state.Config.ActiveProfile, m.state.Configs)
+	}
+	if got := state.LoadProfile(); got != "server" {
+		t.Errorf("saved profile = %q, want server", got)
+	}
+
+	if cmd := m.switchProfile(""); cmd == nil {
+		t.Fatal("expected a reload going back to every config")
+	}
+	if m.state.Config.ActiveProfile != "" || len(m.state.Configs) != 2 {
+		t.Errorf("expected every config again, got %q %v", m.state.Config.ActiveProfile, m.state.Configs)
+	}
+	if got := state.LoadProfile(); got != "" {
+		t.Errorf("saved profile = %q after going back, want \"\"", got)
+	}
+}
+
+func TestOpenProfileMenu_NoProfiles(t *testing.T) {
+	m := New(State{
+		Config:    &config.Config{},
+		HasConfig: true,
+	})
+	m.openProfileMenu()
+	if m.currentView != viewDashboard || m.profileMenu != nil {
+		t.Errorf("expected no switcher without profiles, got view %v", m.currentView)
+	}
+}
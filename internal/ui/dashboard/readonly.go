@@ -0,0 +1,81 @@
+package dashboard
+
+import (
+	"fmt"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// mutatingAction names what a key would change from the focused panel, or
+// returns "" when it only looks around
+func (m *Model) mutatingAction(msg tea.KeyMsg, focused PanelID) string {
+	if m.dryRun && m.dryRunAction(msg, focused) {
+		return ""
+	}
+	switch {
+	case key.Matches(msg, keys.Sync), key.Matches(msg, keys.Bulk):
+		return "sync"
+	case key.Matches(msg, keys.Link), key.Matches(msg, keys.BulkLink):
+		return "link"
+	case key.Matches(msg, keys.Install):
+		return "install"
+	case key.Matches(msg, keys.Update):
+		return "update"
+	case key.Matches(msg, keys.Plan):
+		return "running a plan"
+	case key.Matches(msg, keys.Profile):
+		return "switching profiles"
+	case key.Matches(msg, keys.Undo):
+		if focused == PanelHealth && len(m.healthPanel.Outdated()) > 0 {
+			return "upgrade"
+		}
+		return "undo"
+	case key.Matches(msg, keys.Archive):
+		if focused == PanelConfigs {
+			return "archive"
+		}
+	case key.Matches(msg, keys.Edit):
+		if focused == PanelConfigs || focused == PanelDetails {
+			return "editing metadata"
+		}
+	case key.Matches(msg, keys.Enter):
+		switch focused {
+		case PanelConfigs:
+			return "sync"
+		case PanelOverrides:
+			return "configuring overrides"
+		case PanelExternal:
+			return "clone and update"
+		case PanelDetails:
+			if _, _, ok := m.detailsPanel.SelectedFile(); ok {
+				return "relink"
+			}
+		}
+	}
+	return ""
+}
+
+// dryRunAction reports whether a key starts a sync, link or install, which
+// only report what they would change in dry-run mode
+func (m *Model) dryRunAction(msg tea.KeyMsg, focused PanelID) bool {
+	switch {
+	case key.Matches(msg, keys.Sync), key.Matches(msg, keys.Bulk),
+		key.Matches(msg, keys.Link), key.Matches(msg, keys.BulkLink),
+		key.Matches(msg, keys.Install):
+		return true
+	case key.Matches(msg, keys.Enter):
+		return focused == PanelConfigs
+	}
+	return false
+}
+
+// refuseReadOnly logs that what is disabled and reports true when the
+// dashboard is read-only
+func (m *Model) refuseReadOnly(what string) bool {
+	if !m.state.ReadOnly {
+		return false
+	}
+	m.outputPanel.AddLog("warning", fmt.Sprintf("Read-only mode: %s is disabled", what))
+	return true
+}
@@ -0,0 +1,59 @@
+package dashboard
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/nvandessel/go4dot/internal/config"
+	"github.com/nvandessel/go4dot/internal/platform"
+)
+
+func TestReadOnly_RefusesMutatingKeys(t *testing.T) {
+	configs := []config.ConfigItem{{Name: "git", Path: "git"}}
+	m := New(State{
+		Platform:     &platform.Platform{OS: "linux"},
+		Configs:      configs,
+		Config:       &config.Config{Configs: config.ConfigGroups{Core: configs}},
+		DotfilesPath: t.TempDir(),
+		HasConfig:    true,
+		ReadOnly:     true,
+	})
+	m.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+
+	for _, k := range []string{"s", "l", "i", "u", "U", "a"} {
+		m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(k)})
+	}
+	m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+
+	if m.operationActive {
+		t.Fatal("expected no operation to start in read-only mode")
+	}
+	if m.currentView != viewDashboard {
+		t.Errorf("expected no dialog to open, got view %v", m.currentView)
+	}
+	refused := 0
+	for _, log := range m.outputPanel.GetLogs() {
+		if strings.HasPrefix(log.Message, "Read-only mode:") {
+			refused++
+		}
+	}
+	if refused != 7 {
+		t.Errorf("expected 7 refusals to be logged, got %d", refused)
+	}
+
+	// Looking around still works
+	m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("2")})
+	if m.focusManager.CurrentFocus() != PanelHealth {
+		t.Errorf("expected focus on PanelHealth, got %v", m.focusManager.CurrentFocus())
+	}
+}
+
+func TestFooter_ReadOnly(t *testing.T) {
+	f := NewFooter()
+	f.width = 200
+	f.SetReadOnly(true)
+	if !strings.Contains(f.View(), "READ-ONLY") {
+		t.Errorf("expected the read-only indicator, got %q", f.View())
+	}
+}
@@ -0,0 +1,48 @@
+package dashboard
+
+import (
+	"fmt"
+
+	"github.com/nvandessel/go4dot/internal/inventory"
+	"github.com/nvandessel/go4dot/internal/prefs"
+	"github.com/nvandessel/go4dot/internal/ui"
+	"github.com/nvandessel/go4dot/internal/vcs"
+)
+
+// confirmRepoState checks the dotfiles repo for uncommitted changes or an
+// interrupted rebase or merge, which a sync would spread to the live
+// configs. It asks before going on, listing the changes, or refuses under
+// the strict git_guard preference. It reports whether the sync is held.
+func (m *Model) confirmRepoState(opType OperationType, configName string, configNames []string) bool {
+	mode := m.state.Preferences.GitGuardMode()
+	if m.state.Demo || mode == prefs.GitGuardOff {
+		return false
+	}
+
+	status, err := vcs.Check(m.state.DotfilesPath, inventory.DirName)
+	if err != nil {
+		m.outputPanel.AddLog("warning", fmt.Sprintf("Could not check the repo for uncommitted changes: %v", err))
+		return false
+	}
+	if status.Clean() {
+		return false
+	}
+
+	if mode == prefs.GitGuardStrict {
+		m.outputPanel.AddLog("error", fmt.Sprintf("Sync blocked, dotfiles repo: %s. Commit or stash first.", status.Summary()))
+		return true
+	}
+
+	m.confirm = NewConfirm(
+		"dirty-repo",
+		"Uncommitted changes",
+		fmt.Sprintf("The dotfiles repo has %s. Its files will be linked as they are.", status.Summary()),
+	).WithLabels("Sync anyway", "Cancel").WithDetails(status.Preview(8))
+	contentWidth, contentHeight := overlayContentSize(m.width, m.height, ui.ConfirmOverlayStyle())
+	m.confirm.SetSize(contentWidth, contentHeight)
+	m.pendingOperation = opType
+	m.pendingConfigName = configName
+	m.pendingConfigNames = configNames
+	m.pushView(viewConfirm)
+	return true
+}
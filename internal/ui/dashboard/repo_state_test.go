@@ -0,0 +1,88 @@
+package dashboard
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/nvandessel/go4dot/internal/config"
+	"github.com/nvandessel/go4dot/internal/platform"
+	"github.com/nvandessel/go4dot/internal/prefs"
+)
+
+// newDirtyRepoModel returns a dashboard on a git repo with an uncommitted
+// change
+func newDirtyRepoModel(t *testing.T, p *prefs.Preferences) *Model {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	t.Setenv("HOME", t.TempDir())
+
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, "vim"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "vim", ".vimrc"), []byte("set number\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	cmd := exec.Command("git", "init", "-q")
+	cmd.Dir = dir
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("git init failed: %v\n%s", err, out)
+	}
+
+	cfg := &config.Config{Configs: config.ConfigGroups{Core: []config.ConfigItem{{Name: "vim", Path: "vim"}}}}
+	m := New(State{
+		Platform:     &platform.Platform{OS: "linux"},
+		Config:       cfg,
+		Configs:      cfg.GetAllConfigs(),
+		DotfilesPath: dir,
+		HasConfig:    true,
+		Preferences:  p,
+	})
+	m.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+	return &m
+}
+
+func TestStartSync_ConfirmsDirtyRepo(t *testing.T) {
+	m := newDirtyRepoModel(t, nil)
+
+	if cmd := m.startSync(OpSync, "", nil); cmd != nil {
+		t.Error("expected the sync to wait for confirmation")
+	}
+	if m.currentView != viewConfirm || m.confirm == nil {
+		t.Fatalf("expected the confirmation modal, got view %v", m.currentView)
+	}
+	if view := m.View(); !strings.Contains(view, "?? vim/") {
+		t.Errorf("expected the modal to list the uncommitted files, got:\n%s", view)
+	}
+
+	m.Update(ConfirmResult{ID: "dirty-repo", Confirmed: false})
+	if m.currentView == viewConfirm || m.pendingOperation != 0 {
+		t.Error("expected cancelling to close the modal and drop the pending sync")
+	}
+}
+
+func TestStartSync_StrictGitGuard(t *testing.T) {
+	m := newDirtyRepoModel(t, &prefs.Preferences{GitGuard: prefs.GitGuardStrict})
+
+	m.startSync(OpSync, "", nil)
+	if m.currentView == viewConfirm {
+		t.Error("expected the strict guard to block without asking")
+	}
+	if m.operationActive {
+		t.Error("expected no operation to start")
+	}
+}
+
+func TestStartSync_GitGuardOff(t *testing.T) {
+	m := newDirtyRepoModel(t, &prefs.Preferences{GitGuard: prefs.GitGuardOff})
+
+	if m.confirmRepoState(OpSync, "", nil) {
+		t.Error("expected the guard to be skipped")
+	}
+}
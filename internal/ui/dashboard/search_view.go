@@ -0,0 +1,312 @@
+package dashboard
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/spinner"
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/nvandessel/go4dot/internal/config"
+	"github.com/nvandessel/go4dot/internal/search"
+	"github.com/nvandessel/go4dot/internal/ui"
+)
+
+// searchMaxMatches caps results so huge repos stay responsive
+const searchMaxMatches = 500
+
+// SearchViewCloseMsg is sent when the search view should close
+type SearchViewCloseMsg struct{}
+
+// SearchJumpMsg is sent when a result is chosen, to select its config
+type SearchJumpMsg struct {
+	ConfigName string
+}
+
+// searchResultsMsg is sent when a search finishes
+type searchResultsMsg struct {
+	query   string
+	matches []search.Match
+	err     error
+}
+
+// SearchView searches the content of all managed config files
+type SearchView struct {
+	cfg          *config.Config
+	dotfilesPath string
+	query        string
+	editing      bool // Typing a query rather than browsing results
+	searching    bool
+	searched     string // Query the current results belong to
+	matches      []search.Match
+	lastError    error
+	selected     int
+	viewport     viewport.Model
+	spinner      spinner.Model
+	width        int
+	height       int
+	ready        bool
+}
+
+// NewSearchView creates a new search view
+func NewSearchView(cfg *config.Config, dotfilesPath string) *SearchView {
+	vp := viewport.New(0, 0)
+	vp.Style = lipgloss.NewStyle()
+
+	s := ui.NewSpinner()
+
+	return &SearchView{
+		cfg:          cfg,
+		dotfilesPath: dotfilesPath,
+		editing:      true,
+		viewport:     vp,
+		spinner:      s,
+	}
+}
+
+// Init initializes the search view
+func (v *SearchView) Init() tea.Cmd {
+	return nil
+}
+
+// SetSize updates the view dimensions
+func (v *SearchView) SetSize(width, height int) {
+	v.width = width
+	v.height = height
+	contentWidth := width - 6
+	contentHeight := height - 12 // Account for title, query, hints, borders
+	if contentWidth < 10 {
+		contentWidth = 10
+	}
+	if contentHeight < 5 {
+		contentHeight = 5
+	}
+	v.viewport.Width = contentWidth
+	v.viewport.Height = contentHeight
+	v.ready = true
+	v.updateContent()
+}
+
+// Update handles messages
+func (v *SearchView) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		if key.Matches(msg, key.NewBinding(key.WithKeys("esc", "ctrl+c"))) {
+			return v, func() tea.Msg { return SearchViewCloseMsg{} }
+		}
+		if v.editing {
+			return v, v.updateQuery(msg)
+		}
+		return v, v.updateResults(msg)
+
+	case searchResultsMsg:
+		if msg.query != v.query {
+			return v, nil // Stale result, the query changed meanwhile
+		}
+		v.searching = false
+		v.searched = msg.query
+		v.matches = msg.matches
+		v.lastError = msg.err
+		v.selected = 0
+		if msg.err == nil && len(msg.matches) > 0 {
+			v.editing = false
+		}
+		v.updateContent()
+		v.viewport.GotoTop()
+		return v, nil
+
+	case spinner.TickMsg:
+		if v.searching {
+			var cmd tea.Cmd
+			v.spinner, cmd = v.spinner.Update(msg)
+			return v, cmd
+		}
+
+	case tea.MouseMsg:
+		var cmd tea.Cmd
+		v.viewport, cmd = v.viewport.Update(msg)
+		return v, cmd
+	}
+
+	return v, nil
+}
+
+// updateQuery handles key input while typing the query
+func (v *SearchView) updateQuery(msg tea.KeyMsg) tea.Cmd {
+	switch {
+	case key.Matches(msg, key.NewBinding(key.WithKeys("enter"))):
+		if strings.TrimSpace(v.query) == "" {
+			return nil
+		}
+		v.searching = true
+		v.updateContent()
+		return tea.Batch(v.spinner.Tick, v.runSearch(v.query))
+	case key.Matches(msg, key.NewBinding(key.WithKeys("down", "tab"))):
+		if len(v.matches) > 0 && v.searched == v.query {
+			v.editing = false
+			v.updateContent()
+		}
+	case key.Matches(msg, key.NewBinding(key.WithKeys("backspace"))):
+		if len(v.query) > 0 {
+			runes := []rune(v.query)
+			v.query = string(runes[:len(runes)-1])
+		}
+	default:
+		// Only append printable characters, ignore special keys
+		if msg.Type == tea.KeyRunes || msg.Type == tea.KeySpace {
+			v.query += string(msg.Runes)
+			if msg.Type == tea.KeySpace && len(msg.Runes) == 0 {
+				v.query += " "
+			}
+		}
+	}
+	return nil
+}
+
+// updateResults handles key input while browsing results
+func (v *SearchView) updateResults(msg tea.KeyMsg) tea.Cmd {
+	switch {
+	case key.Matches(msg, key.NewBinding(key.WithKeys("enter"))):
+		if v.selected < len(v.matches) {
+			name := v.matches[v.selected].Config
+			return func() tea.Msg { return SearchJumpMsg{ConfigName: name} }
+		}
+	case key.Matches(msg, key.NewBinding(key.WithKeys("/", "tab"))):
+		v.editing = true
+		v.updateContent()
+	case key.Matches(msg, key.NewBinding(key.WithKeys("k", "up"))):
+		if v.selected > 0 {
+			v.selected--
+			v.updateContent()
+		} else {
+			v.editing = true
+			v.updateContent()
+		}
+	case key.Matches(msg, key.NewBinding(key.WithKeys("j", "down"))):
+		if v.selected < len(v.matches)-1 {
+			v.selected++
+			v.updateContent()
+		}
+	}
+	return nil
+}
+
+func (v *SearchView) runSearch(query string) tea.Cmd {
+	cfg, dotfilesPath := v.cfg, v.dotfilesPath
+	return func() tea.Msg {
+		matches, err := search.Grep(cfg, dotfilesPath, query, search.Options{
+			IgnoreCase: strings.ToLower(query) == query, // Smart case
+			MaxMatches: searchMaxMatches,
+		})
+		return searchResultsMsg{query: query, matches: matches, err: err}
+	}
+}
+
+// hints returns the key hints for the current mode
+func (v *SearchView) hints() string {
+	if v.editing {
+		return "Type a pattern • Enter Search • ↓ Results • ESC Close"
+	}
+	return "↑/↓ Navigate • Enter Go to config • / Edit • ESC Close"
+}
+
+// queryLine renders the query input
+func (v *SearchView) queryLine() string {
+	promptStyle := lipgloss.NewStyle().Foreground(ui.PrimaryColor).Bold(true)
+	textStyle := lipgloss.NewStyle().Foreground(ui.TextColor)
+
+	cursor := ""
+	if v.editing {
+		cursor = "█"
+	}
+	return promptStyle.Render("Search: ") + textStyle.Render(v.query) + cursor
+}
+
+func (v *SearchView) updateContent() {
+	subtleStyle := lipgloss.NewStyle().Foreground(ui.SubtleColor)
+
+	switch {
+	case v.searching:
+		v.viewport.SetContent(v.spinner.View() + " Searching...")
+		return
+	case v.lastError != nil:
+		v.viewport.SetContent(lipgloss.NewStyle().Foreground(ui.ErrorColor).Render(fmt.Sprintf("Error: %v", v.lastError)))
+		return
+	case v.searched == "":
+		v.viewport.SetContent(subtleStyle.Render("Search the content of every managed file, e.g. EDITOR"))
+		return
+	case len(v.matches) == 0:
+		v.viewport.SetContent(subtleStyle.Render(fmt.Sprintf("No matches for %q", v.searched)))
+		return
+	}
+
+	configStyle := lipgloss.NewStyle().Foreground(ui.PrimaryColor).Bold(true)
+	selectedStyle := lipgloss.NewStyle().Foreground(ui.TextColor).Background(ui.PrimaryColor)
+	width := v.viewport.Width
+
+	var lines []string
+	selectedLine := 0
+	lastConfig := ""
+	for i, m := range v.matches {
+		if m.Config != lastConfig {
+			if lastConfig != "" {
+				lines = append(lines, "")
+			}
+			lines = append(lines, configStyle.Render(m.Config))
+			lastConfig = m.Config
+		}
+
+		loc := fmt.Sprintf("%s:%d:", m.File, m.Line)
+		line := "  " + loc + " " + strings.TrimSpace(m.Text)
+		if width > 0 {
+			line = truncateString(line, width)
+		}
+
+		if i == v.selected && !v.editing {
+			selectedLine = len(lines)
+			lines = append(lines, selectedStyle.Render(line))
+		} else if rest, ok := strings.CutPrefix(line, "  "+loc); ok {
+			lines = append(lines, subtleStyle.Render("  "+loc)+rest)
+		} else {
+			lines = append(lines, line)
+		}
+	}
+	if len(v.matches) >= searchMaxMatches {
+		lines = append(lines, "", subtleStyle.Render(fmt.Sprintf("Showing first %d matches", searchMaxMatches)))
+	}
+
+	v.viewport.SetContent(strings.Join(lines, "\n"))
+
+	// Keep the selected line visible
+	if selectedLine < v.viewport.YOffset {
+		v.viewport.SetYOffset(selectedLine)
+	} else if selectedLine >= v.viewport.YOffset+v.viewport.Height {
+		v.viewport.SetYOffset(selectedLine - v.viewport.Height + 1)
+	}
+}
+
+// View renders the search view
+func (v *SearchView) View() string {
+	if !v.ready {
+		return ""
+	}
+
+	borderStyle := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(ui.PrimaryColor).
+		Padding(1, 2).
+		Width(v.width - 4).
+		Height(v.height - 4)
+
+	dialog := borderStyle.Render(overlaySearchContent(v))
+
+	return lipgloss.Place(
+		v.width,
+		v.height,
+		lipgloss.Center,
+		lipgloss.Center,
+		dialog,
+	)
+}
@@ -0,0 +1,71 @@
+package dashboard
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/nvandessel/go4dot/internal/ui"
+)
+
+// snapshotTitle names the HTML page of a snapshot
+const snapshotTitle = "go4dot dashboard"
+
+// RenderSnapshot renders the dashboard for s once, at the given size, and
+// returns the view. Health checks, external status and the selected config's
+// statistics are loaded synchronously first, so the view shows what the
+// dashboard settles on rather than its loading spinners.
+func RenderSnapshot(s State, width, height int) string {
+	s.ShowTutorial = false
+	s.AutoStart = false
+	m := New(s)
+	m.Update(tea.WindowSizeMsg{Width: width, Height: height})
+
+	if m.currentView == viewDashboard {
+		m.Update(m.healthPanel.runChecks())
+		m.Update(m.externalPanel.loadStatus())
+		if cmd := m.detailsPanel.LoadStats(); cmd != nil {
+			m.Update(cmd())
+		}
+	}
+	return m.View()
+}
+
+// FormatSnapshot converts a rendered view to the given snapshot format
+func FormatSnapshot(view, format string) (string, error) {
+	switch format {
+	case ui.SnapshotFormatText:
+		return ui.SnapshotText(view), nil
+	case ui.SnapshotFormatHTML:
+		return ui.SnapshotHTML(view, snapshotTitle), nil
+	}
+	return "", fmt.Errorf("unknown snapshot format %q (want %s or %s)", format, ui.SnapshotFormatText, ui.SnapshotFormatHTML)
+}
+
+// saveSnapshot writes the current view to the working directory as both
+// text and HTML, named after the time, and logs where they went
+func (m *Model) saveSnapshot() {
+	view := m.View()
+	base := "g4d-snapshot-" + time.Now().Format("20060102-150405")
+
+	var saved []string
+	for _, format := range []string{ui.SnapshotFormatText, ui.SnapshotFormatHTML} {
+		ext := ".txt"
+		if format == ui.SnapshotFormatHTML {
+			ext = ".html"
+		}
+		content, _ := FormatSnapshot(view, format)
+		path := base + ext
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			m.outputPanel.AddLog("error", fmt.Sprintf("Failed to save snapshot: %v", err))
+			return
+		}
+		if abs, err := filepath.Abs(path); err == nil {
+			path = abs
+		}
+		saved = append(saved, path)
+	}
+	m.outputPanel.AddLog("success", fmt.Sprintf("Saved snapshot to %s and %s", saved[0], saved[1]))
+}
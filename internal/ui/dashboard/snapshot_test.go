@@ -0,0 +1,31 @@
+package dashboard
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRenderSnapshot_Demo(t *testing.T) {
+	view := RenderSnapshot(DemoState(), 100, 30)
+
+	text, err := FormatSnapshot(view, "text")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"[5] Configs", "zsh", "alacritty"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("snapshot is missing %q:\n%s", want, text)
+		}
+	}
+	// Loading is done before rendering, so no panel is left waiting
+	if strings.Contains(text, "Loading") || strings.Contains(text, "Checking") {
+		t.Errorf("snapshot shows a loading panel:\n%s", text)
+	}
+	if lines := strings.Count(text, "\n"); lines > 30 {
+		t.Errorf("snapshot has %d lines, want at most 30", lines)
+	}
+
+	if _, err := FormatSnapshot(view, "pdf"); err == nil {
+		t.Error("FormatSnapshot() accepted an unknown format")
+	}
+}
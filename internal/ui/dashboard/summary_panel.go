@@ -205,3 +205,14 @@ func (p *SummaryPanel) UpdateState(state State) {
 func (p *SummaryPanel) SetSelectedCount(count int) {
 	p.selectedCount = count
 }
+
+// HandleEvent implements Subscriber
+func (p *SummaryPanel) HandleEvent(e Event) tea.Cmd {
+	switch e := e.(type) {
+	case StatusUpdatedEvent:
+		p.UpdateState(e.State)
+	case ConfigsChangedEvent:
+		p.SetSelectedCount(len(e.Selected))
+	}
+	return nil
+}
@@ -1,49 +1,156 @@
 package dashboard
 
 import (
+	"errors"
 	"fmt"
+	"strings"
 
 	"github.com/nvandessel/go4dot/internal/config"
 	"github.com/nvandessel/go4dot/internal/deps"
+	"github.com/nvandessel/go4dot/internal/doctor"
+	"github.com/nvandessel/go4dot/internal/hooks"
+	"github.com/nvandessel/go4dot/internal/inventory"
 	"github.com/nvandessel/go4dot/internal/platform"
+	"github.com/nvandessel/go4dot/internal/setup"
+	"github.com/nvandessel/go4dot/internal/snapshot"
 	"github.com/nvandessel/go4dot/internal/state"
 	"github.com/nvandessel/go4dot/internal/stow"
 )
 
 // SyncOptions configures the sync operation
 type SyncOptions struct {
-	Force       bool // Force restow even if no drift detected
-	Interactive bool // Enable interactive conflict resolution
+	Force       bool   // Force restow even if no drift detected
+	Interactive bool   // Enable interactive conflict resolution
+	UseTrash    bool   // Move replaced files to the OS trash
+	Full        bool   // Also install missing dependencies and clone missing externals; otherwise only link
+	Escalation  string // How package managers get root: sudo, doas or pkexec; empty uses the first installed
+	Jobs        int    // Configs restowed at once when syncing all; 0 for the default
+	Plan        *Plan  // Steps of a bulk sync as edited in the plan editor; nil runs them all
 }
 
 // SyncResult holds the result of a sync operation
 type SyncResult struct {
-	Success []string
-	Failed  []stow.StowError
-	Skipped []string
+	Success        []string
+	Failed         []stow.StowError
+	Skipped        []string
+	Unchanged      []string // Configs left alone as they had not changed since the last sync
+	DepsFailed     []deps.InstallError
+	ExternalFailed []deps.ExternalError
+	Verify         *doctor.Verification     // Links of the synced configs; nil if none were synced
+	Functional     []doctor.FunctionalCheck // Verify commands of the synced configs that have one
+	Errors         []error
 }
 
 // HasErrors returns true if any errors occurred
 func (r *SyncResult) HasErrors() bool {
-	return len(r.Failed) > 0
+	return len(r.Failed) > 0 || len(r.DepsFailed) > 0 ||
+		len(r.ExternalFailed) > 0 || len(r.Errors) > 0 ||
+		(r.Verify != nil && !r.Verify.OK()) || len(doctor.FailedFunctionalChecks(r.Functional)) > 0
+}
+
+// Err returns an error describing what failed, or nil
+func (r *SyncResult) Err() error {
+	switch {
+	case len(r.Failed) > 0:
+		return collectSyncErrors(r.Failed)
+	case len(r.Errors) > 0:
+		return r.Errors[0]
+	case len(r.DepsFailed) > 0:
+		return fmt.Errorf("%d dependencies failed to install", len(r.DepsFailed))
+	case len(r.ExternalFailed) > 0:
+		return fmt.Errorf("%d external dependencies failed to clone", len(r.ExternalFailed))
+	case r.Verify != nil && !r.Verify.OK():
+		return fmt.Errorf("%d links failed verification", len(r.Verify.Failed))
+	case len(doctor.FailedFunctionalChecks(r.Functional)) > 0:
+		return fmt.Errorf("verify command failed for %d configs", len(doctor.FailedFunctionalChecks(r.Functional)))
+	}
+	return nil
+}
+
+// Synced returns the configs the sync left linked, by the same rule as
+// stow.StowResult.Synced: restowed and unchanged ones
+func (r *SyncResult) Synced() []string {
+	return append(append([]string{}, r.Success...), r.Unchanged...)
 }
 
 // Summary returns a summary string
 func (r *SyncResult) Summary() string {
-	if len(r.Success) == 0 && len(r.Failed) == 0 && len(r.Skipped) == 0 {
+	if len(r.Success) == 0 && len(r.Failed) == 0 && len(r.Skipped) == 0 && len(r.Unchanged) == 0 && !r.HasErrors() {
 		return "No configs to sync"
 	}
 
 	summary := fmt.Sprintf("%d synced", len(r.Success))
+	if len(r.Unchanged) > 0 {
+		summary = fmt.Sprintf("%d unchanged, %s", len(r.Unchanged), summary)
+	}
 	if len(r.Failed) > 0 {
 		summary += fmt.Sprintf(", %d failed", len(r.Failed))
 	}
 	if len(r.Skipped) > 0 {
 		summary += fmt.Sprintf(", %d skipped", len(r.Skipped))
 	}
+	if len(r.DepsFailed) > 0 {
+		summary += fmt.Sprintf(", %d dependencies failed", len(r.DepsFailed))
+	}
+	if len(r.ExternalFailed) > 0 {
+		summary += fmt.Sprintf(", %d externals failed", len(r.ExternalFailed))
+	}
+	if r.Verify != nil {
+		summary += "; " + r.Verify.Summary()
+	}
+	if failed := doctor.FailedFunctionalChecks(r.Functional); len(failed) > 0 {
+		summary += fmt.Sprintf(", %d verify commands failed", len(failed))
+	}
 	return summary
 }
 
+// runFullSyncSteps installs missing dependencies (step 2) and clones missing
+// external dependencies of the synced configs (step 3). These steps are what
+// a full sync adds to linking. It returns the index of the state step that
+// follows.
+func runFullSyncSteps(runner *OperationRunner, cfg *config.Config, dotfilesPath string, configNames []string, opts SyncOptions, result *SyncResult) int {
+	if !opts.Full {
+		return 2
+	}
+	if runner.Err() != nil {
+		runner.StepComplete(2, StepSkipped, "Skipped")
+		runner.StepComplete(3, StepSkipped, "Skipped")
+		return 4
+	}
+
+	p, err := platform.Detect()
+	if err != nil {
+		wrappedErr := fmt.Errorf("platform detection failed: %w", err)
+		runner.StepComplete(2, StepError, wrappedErr.Error())
+		runner.StepComplete(3, StepSkipped, "Skipped")
+		result.Errors = append(result.Errors, wrappedErr)
+		return 4
+	}
+
+	extras := &InstallResult{}
+	if action := opts.Plan.stage(PlanDependencies); action != PlanSkip {
+		if err := runDependencyInstall(runner, 2, cfg, p, opts.Escalation, action == PlanDryRun, extras); err != nil {
+			result.Errors = append(result.Errors, err)
+		}
+	} else {
+		runner.StepComplete(2, StepSkipped, "Skipped")
+	}
+
+	if action := opts.Plan.stage(PlanExternal); action != PlanSkip && runner.Err() == nil {
+		scoped := *cfg
+		scoped.External = cfg.GetExternalForConfigs(configNames)
+		if err := runCloneExternal(runner, 3, &scoped, dotfilesPath, p, action == PlanDryRun, extras); err != nil {
+			result.Errors = append(result.Errors, err)
+		}
+	} else {
+		runner.StepComplete(3, StepSkipped, "Skipped")
+	}
+
+	result.DepsFailed = extras.DepsFailed
+	result.ExternalFailed = extras.ExternalFailed
+	return 4
+}
+
 // loadOrCreateState loads existing state or creates a new one if unavailable
 func loadOrCreateState() *state.State {
 	st, err := state.Load()
@@ -53,52 +160,156 @@ func loadOrCreateState() *state.State {
 	return st
 }
 
-// RunSyncAllOperation runs a sync all operation within the dashboard
+// RunSyncAllOperation runs a sync all operation within the dashboard. With
+// opts.Full it is a full sync, otherwise it only links.
 func RunSyncAllOperation(runner *OperationRunner, cfg *config.Config, dotfilesPath string, opts SyncOptions) (*SyncResult, error) {
+	defer snapshot.Begin("sync")()
 	result := &SyncResult{}
 
 	// Step 0: Check symlinks
 	runner.Progress(0, "Analyzing symlink status...")
 
+	if err := runPreflight(runner, 0, cfg, dotfilesPath, syncPreflightOptions(cfg.GetAllConfigs(), opts)); err != nil {
+		return nil, err
+	}
+
 	st := loadOrCreateState()
 
-	if _, err := stow.FullDriftCheck(cfg, dotfilesPath); err != nil {
+	drift, err := stow.FullDriftCheck(cfg, dotfilesPath)
+	if err != nil {
 		runner.Log("warning", fmt.Sprintf("Drift check failed: %v", err))
 	}
 
-	runner.StepComplete(0, StepSuccess, fmt.Sprintf("%d configs analyzed", len(cfg.GetAllConfigs())))
-
-	// Step 1: Sync configs
-	runner.Progress(1, fmt.Sprintf("Syncing %d configs...", len(cfg.GetAllConfigs())))
+	if err := runner.Err(); err != nil {
+		return nil, err
+	}
 
-	stowOpts := stow.StowOptions{
-		Force: opts.Force,
-		ProgressFunc: func(current, total int, msg string) {
-			runner.Log("info", msg)
+	stateStep, err := runSyncPipeline(runner, cfg, dotfilesPath, st, nil, drift, opts, result, syncSteps{
+		checked: fmt.Sprintf("%d configs analyzed", len(cfg.GetAllConfigs())),
+		linking: "Linking changed configs...",
+		linked: func(res *setup.SyncResult) {
+			if len(res.Failed) > 0 || len(result.Errors) > 0 {
+				runner.StepComplete(1, StepWarning, stow.SyncResultSummary(&res.StowResult))
+			} else {
+				runner.StepComplete(1, StepSuccess, stow.SyncResultSummary(&res.StowResult))
+			}
 		},
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	syncResult, err := stow.SyncAll(dotfilesPath, cfg, st, opts.Interactive, stowOpts)
-	if err != nil {
-		runner.StepComplete(1, StepError, err.Error())
-		return nil, fmt.Errorf("sync all failed: %w", err)
+	if cfg.Inventory.Enabled {
+		p, err := platform.Detect()
+		if err == nil {
+			err = inventory.Update(cfg, dotfilesPath, p)
+		}
+		if err != nil {
+			runner.Log("warning", fmt.Sprintf("Could not record this machine: %v", err))
+		}
 	}
 
-	result.Success = syncResult.Success
-	result.Failed = syncResult.Failed
-	result.Skipped = syncResult.Skipped
+	finishSync(runner, cfg, dotfilesPath, st, stateStep, result)
+	return result, nil
+}
 
-	if len(syncResult.Failed) > 0 {
-		runner.StepComplete(1, StepWarning, fmt.Sprintf("%d synced, %d failed", len(syncResult.Success), len(syncResult.Failed)))
-		for _, f := range syncResult.Failed {
-			runner.Log("error", fmt.Sprintf("Failed: %s - %v", f.ConfigName, f.Error))
-		}
-	} else {
-		runner.StepComplete(1, StepSuccess, fmt.Sprintf("%d configs synced", len(syncResult.Success)))
+// syncSteps is how an operation reports the steps setup.Sync runs through
+type syncSteps struct {
+	checked    string                      // Detail of the finished check step
+	linking    string                      // Detail of the link step as it starts
+	beforeLink func()                      // Runs in the link step before linking, or nil
+	linked     func(res *setup.SyncResult) // Completes the link step
+}
+
+// runSyncPipeline links configNames, or every config when it is nil,
+// with setup.Sync, the pipeline 'g4d sync' and 'g4d link' run too. Its
+// pre_sync hooks run in step 0 and linking in step 1; a full sync installs
+// dependencies and clones externals in steps 2 and 3 and runs the post_sync
+// hooks in the state step, whose index is returned. The error is set when
+// the sync stopped before linking.
+func runSyncPipeline(runner *OperationRunner, cfg *config.Config, dotfilesPath string, st *state.State, configNames []string, drift *stow.DriftSummary, opts SyncOptions, result *SyncResult, steps syncSteps) (int, error) {
+	stateStep := 2
+	stage := ""
+	res, err := setup.Sync(runner.Context(), cfg, dotfilesPath, st, configNames, setup.SyncOptions{
+		Full:        opts.Full,
+		Force:       opts.Force,
+		UseTrash:    opts.UseTrash,
+		Interactive: opts.Interactive,
+		Jobs:        opts.Jobs,
+		Drift:       drift,
+		ProgressFunc: func(current, total int, msg string) {
+			runner.Log("info", msg)
+		},
+		ActionFunc: logStowAction(runner, 1),
+		LinkedFunc: func(name string, err error) {
+			if err != nil {
+				runner.Log("error", fmt.Sprintf("Failed: %s - %v", name, err))
+			} else {
+				runner.Log("success", fmt.Sprintf("Synced: %s", name))
+			}
+		},
+		HookStart: func(h hooks.Hook) {
+			if h.Stage != stage {
+				stage = h.Stage
+				step := stateStep
+				if config.IsPreStage(stage) {
+					step = 0
+				}
+				runner.Progress(step, fmt.Sprintf("Running %s hooks...", stage))
+			}
+			logHookStart(runner)(h)
+		},
+		HookOutput: logHookOutput(runner),
+		BeforeLink: func() {
+			runner.StepComplete(0, StepSuccess, steps.checked)
+			runner.Progress(1, steps.linking)
+			if steps.beforeLink != nil {
+				steps.beforeLink()
+			}
+		},
+		AfterLink: func(res *setup.SyncResult) {
+			result.Success = res.Success
+			result.Failed = res.Failed
+			result.Skipped = append(result.Skipped, res.Skipped...)
+			result.Unchanged = res.Unchanged
+			if links := res.Links; links != nil {
+				runner.Log("info", "Links: "+links.Summary())
+				for _, f := range links.Failed {
+					runner.Log("error", fmt.Sprintf("Failed: link %s - %v", f.Target, f.Error))
+					result.Errors = append(result.Errors, fmt.Errorf("failed to link %s: %w", f.Target, f.Error))
+				}
+			}
+			steps.linked(res)
+
+			// Steps 2-3 (full sync only): dependencies and externals
+			stateStep = runFullSyncSteps(runner, cfg, dotfilesPath, configNames, opts, result)
+		},
+	})
+	logHookFailures(runner, res.HooksFailed)
+	switch {
+	case errors.Is(err, setup.ErrPreSyncHook):
+		runner.StepComplete(0, StepError, fmt.Sprintf("%s hook failed", config.HookPreSync))
+		return 0, err
+	case err != nil && len(res.Success) == 0 && len(res.Failed) == 0:
+		runner.StepComplete(1, StepError, err.Error())
+		return 0, fmt.Errorf("sync failed: %w", err)
+	}
+	if hookErr := res.HooksErr(); hookErr != nil {
+		result.Errors = append(result.Errors, hookErr)
 	}
+	return stateStep, nil
+}
 
-	// Step 2: Update state
-	runner.Progress(2, "Updating state...")
+// finishSync verifies the synced configs, saves state and reports the
+// operation's result
+func finishSync(runner *OperationRunner, cfg *config.Config, dotfilesPath string, st *state.State, stateStep int, result *SyncResult) {
+	// Verify the links, then update state
+	if len(result.Success) > 0 {
+		runner.Progress(stateStep, "Verifying links...")
+		result.Verify = verifyLinks(runner, cfg, dotfilesPath, result.Success)
+	}
+	result.Functional = runVerifyCommands(runner, cfg, dotfilesPath, result.Synced())
+	runner.Progress(stateStep, "Updating state...")
 
 	if err := stow.UpdateSymlinkCounts(cfg, dotfilesPath, st); err != nil {
 		runner.Log("warning", fmt.Sprintf("Failed to update symlink counts: %v", err))
@@ -108,16 +319,64 @@ func RunSyncAllOperation(runner *OperationRunner, cfg *config.Config, dotfilesPa
 		runner.Log("warning", fmt.Sprintf("Failed to save state: %v", err))
 	}
 
-	runner.StepComplete(2, StepSuccess, "State updated")
+	runner.StepComplete(stateStep, StepSuccess, "State updated")
 
 	// Report completion
 	if result.HasErrors() {
-		runner.Done(false, result.Summary(), collectSyncErrors(result.Failed))
+		runner.Done(false, result.Summary(), result.Err())
 	} else {
 		runner.Done(true, result.Summary(), nil)
 	}
+}
 
-	return result, nil
+// verifyLinks checks the links of the synced configs the way doctor does and
+// logs each one that is not in place, so success means the links resolve
+func verifyLinks(runner *OperationRunner, cfg *config.Config, dotfilesPath string, configNames []string) *doctor.Verification {
+	v := doctor.VerifySymlinks(cfg, dotfilesPath, configNames)
+	for _, f := range v.Failed {
+		runner.Log("error", fmt.Sprintf("Verify %s: %s (%s)", f.Config, f.TargetPath, f.Message))
+	}
+	return v
+}
+
+// runVerifyCommands runs the verify commands of the synced configs and logs
+// whether each works, with the end of a failed command's output
+func runVerifyCommands(runner *OperationRunner, cfg *config.Config, dotfilesPath string, configNames []string) []doctor.FunctionalCheck {
+	if len(configNames) == 0 {
+		return nil
+	}
+	checks := doctor.RunFunctionalChecks(cfg, dotfilesPath, configNames)
+	for _, c := range checks {
+		if c.Status == doctor.StatusOK {
+			runner.Log("success", fmt.Sprintf("Verify %s: %s works", c.Config, c.Command))
+			continue
+		}
+		runner.Log("error", fmt.Sprintf("Verify %s: %s %s", c.Config, c.Command, c.Message))
+		for _, line := range strings.Split(c.Output, "\n") {
+			if line != "" {
+				runner.Log("error", "  "+line)
+			}
+		}
+	}
+	return checks
+}
+
+// logStowAction reports each file stow changes as the step's progress and
+// in the output log, and logs stow's warnings and conflicts as warnings
+func logStowAction(runner *OperationRunner, step int) func(stow.Action) {
+	return func(a stow.Action) {
+		msg := a.String()
+		if a.Config != "" {
+			// Configs linked at once interleave their lines
+			msg = fmt.Sprintf("[%s] %s", a.Config, msg)
+		}
+		if a.IsProblem() {
+			runner.Log("warning", msg)
+			return
+		}
+		runner.Progress(step, msg)
+		runner.Log("info", msg)
+	}
 }
 
 // collectSyncErrors combines multiple sync errors into one
@@ -144,117 +403,106 @@ func collectUpdateErrors(failed []deps.ExternalError) error {
 
 // RunSyncSingleOperation runs a sync operation for a single config
 func RunSyncSingleOperation(runner *OperationRunner, cfg *config.Config, dotfilesPath string, configName string, opts SyncOptions) (*SyncResult, error) {
+	defer snapshot.Begin("sync")()
 	result := &SyncResult{}
 
 	// Step 0: Check symlinks
 	runner.Progress(0, fmt.Sprintf("Checking %s...", configName))
 
-	st := loadOrCreateState()
-
-	runner.StepComplete(0, StepSuccess, "Status checked")
+	if item := cfg.GetConfigByName(configName); item != nil {
+		if err := runPreflight(runner, 0, cfg, dotfilesPath, syncPreflightOptions([]config.ConfigItem{*item}, opts)); err != nil {
+			return nil, err
+		}
+	}
 
-	// Step 1: Sync config
-	runner.Progress(1, fmt.Sprintf("Syncing %s...", configName))
+	st := loadOrCreateState()
 
-	stowOpts := stow.StowOptions{
-		Force: opts.Force,
-		ProgressFunc: func(current, total int, msg string) {
-			runner.Log("info", msg)
+	if err := runner.Err(); err != nil {
+		return nil, err
+	}
+
+	stateStep, err := runSyncPipeline(runner, cfg, dotfilesPath, st, []string{configName}, nil, opts, result, syncSteps{
+		checked: "Status checked",
+		linking: fmt.Sprintf("Linking %s...", configName),
+		linked: func(res *setup.SyncResult) {
+			switch {
+			case len(res.Failed) > 0:
+				runner.StepComplete(1, StepError, res.Failed[0].Error.Error())
+			case len(res.Skipped) > 0:
+				runner.StepComplete(1, StepSkipped, fmt.Sprintf("%s skipped (on_conflict: skip)", configName))
+			default:
+				runner.StepComplete(1, StepSuccess, fmt.Sprintf("%s synced", configName))
+			}
 		},
-	}
-
-	err := stow.SyncSingle(dotfilesPath, configName, cfg, st, stowOpts)
+	})
 	if err != nil {
-		runner.StepComplete(1, StepError, err.Error())
-		result.Failed = append(result.Failed, stow.StowError{ConfigName: configName, Error: err})
-	} else {
-		runner.StepComplete(1, StepSuccess, fmt.Sprintf("%s synced", configName))
-		result.Success = append(result.Success, configName)
-	}
-
-	// Step 2: Update state
-	runner.Progress(2, "Updating state...")
-
-	if err := stow.UpdateSymlinkCounts(cfg, dotfilesPath, st); err != nil {
-		runner.Log("warning", fmt.Sprintf("Failed to update symlink counts: %v", err))
-	}
-
-	if err := st.Save(); err != nil {
-		runner.Log("warning", fmt.Sprintf("Failed to save state: %v", err))
-	}
-
-	runner.StepComplete(2, StepSuccess, "State updated")
-
-	// Report completion
-	if result.HasErrors() {
-		runner.Done(false, result.Summary(), collectSyncErrors(result.Failed))
-	} else {
-		runner.Done(true, result.Summary(), nil)
+		return nil, err
 	}
 
+	finishSync(runner, cfg, dotfilesPath, st, stateStep, result)
 	return result, nil
 }
 
-// RunBulkSyncOperation runs a sync operation for multiple configs
+// RunBulkSyncOperation runs a sync operation for multiple configs. With
+// opts.Plan the configs are linked in the plan's order, and those it marks
+// dry-run are only checked.
 func RunBulkSyncOperation(runner *OperationRunner, cfg *config.Config, dotfilesPath string, configNames []string, opts SyncOptions) (*SyncResult, error) {
+	defer snapshot.Begin("sync")()
 	result := &SyncResult{}
 
-	// Step 0: Check symlinks
-	runner.Progress(0, fmt.Sprintf("Checking %d configs...", len(configNames)))
-
-	st := loadOrCreateState()
-
-	runner.StepComplete(0, StepSuccess, fmt.Sprintf("%d configs to sync", len(configNames)))
-
-	// Step 1: Sync configs
-	runner.Progress(1, fmt.Sprintf("Syncing %d configs...", len(configNames)))
-
-	stowOpts := stow.StowOptions{
-		Force: opts.Force,
-		ProgressFunc: func(current, total int, msg string) {
-			runner.Log("info", msg)
-		},
-	}
-
-	for i, name := range configNames {
-		runner.Log("info", fmt.Sprintf("[%d/%d] Syncing %s...", i+1, len(configNames), name))
-
-		err := stow.SyncSingle(dotfilesPath, name, cfg, st, stowOpts)
-		if err != nil {
-			result.Failed = append(result.Failed, stow.StowError{ConfigName: name, Error: err})
-			runner.Log("error", fmt.Sprintf("Failed: %s - %v", name, err))
-		} else {
-			result.Success = append(result.Success, name)
-			runner.Log("success", fmt.Sprintf("Synced: %s", name))
-		}
-	}
-
-	if len(result.Failed) > 0 {
-		runner.StepComplete(1, StepWarning, fmt.Sprintf("%d synced, %d failed", len(result.Success), len(result.Failed)))
-	} else {
-		runner.StepComplete(1, StepSuccess, fmt.Sprintf("%d configs synced", len(result.Success)))
+	var dryRun []string
+	if opts.Plan != nil {
+		configNames, dryRun = opts.Plan.configs(PlanRun), opts.Plan.configs(PlanDryRun)
+		result.Skipped = opts.Plan.configs(PlanSkip)
 	}
 
-	// Step 2: Update state
-	runner.Progress(2, "Updating state...")
-
-	if err := stow.UpdateSymlinkCounts(cfg, dotfilesPath, st); err != nil {
-		runner.Log("warning", fmt.Sprintf("Failed to update symlink counts: %v", err))
-	}
+	// Step 0: Check symlinks
+	runner.Progress(0, fmt.Sprintf("Checking %d configs...", len(configNames)))
 
-	if err := st.Save(); err != nil {
-		runner.Log("warning", fmt.Sprintf("Failed to save state: %v", err))
+	configs := configItems(cfg, configNames)
+	if err := runPreflight(runner, 0, cfg, dotfilesPath, syncPreflightOptions(configs, opts)); err != nil {
+		return nil, err
 	}
 
-	runner.StepComplete(2, StepSuccess, "State updated")
+	st := loadOrCreateState()
 
-	// Report completion
-	if result.HasErrors() {
-		runner.Done(false, result.Summary(), collectSyncErrors(result.Failed))
-	} else {
-		runner.Done(true, result.Summary(), nil)
+	if err := runner.Err(); err != nil {
+		return nil, err
+	}
+
+	stateStep, err := runSyncPipeline(runner, cfg, dotfilesPath, st, itemNames(configs), nil, opts, result, syncSteps{
+		checked: fmt.Sprintf("%d configs to sync", len(configNames)),
+		linking: fmt.Sprintf("Linking %d configs...", len(configNames)),
+		beforeLink: func() {
+			dryOpts := stow.StowOptions{
+				Settings:   cfg.Stow,
+				DryRun:     true,
+				ActionFunc: logStowAction(runner, 1),
+			}
+			for _, name := range dryRun {
+				if runner.Err() != nil {
+					break
+				}
+				if err := stow.SyncSingle(dotfilesPath, name, cfg, nil, dryOpts); err != nil {
+					runner.Log("warning", fmt.Sprintf("Dry run: %s would fail - %v", name, err))
+				} else {
+					runner.Log("info", fmt.Sprintf("Dry run: %s checked", name))
+				}
+			}
+		},
+		linked: func(res *setup.SyncResult) {
+			if len(res.Failed) > 0 {
+				runner.StepComplete(1, StepWarning, fmt.Sprintf("%d synced, %d failed", len(res.Success), len(res.Failed)))
+			} else {
+				runner.StepComplete(1, StepSuccess, fmt.Sprintf("%d configs synced", len(res.Success)))
+			}
+		},
+	})
+	if err != nil {
+		return nil, err
 	}
 
+	finishSync(runner, cfg, dotfilesPath, st, stateStep, result)
 	return result, nil
 }
 
@@ -317,15 +565,17 @@ func RunUpdateOperation(runner *OperationRunner, cfg *config.Config, dotfilesPat
 
 	runner.StepComplete(0, StepSuccess, fmt.Sprintf("%d dependencies found", len(cfg.External)))
 
+	if err := runner.Err(); err != nil {
+		return nil, err
+	}
+
 	// Step 1: Update repositories
 	runner.Progress(1, "Updating repositories...")
 
 	extOpts := deps.ExternalOptions{
-		Update:   true, // Enable update mode
-		RepoRoot: dotfilesPath,
-		ProgressFunc: func(current, total int, msg string) {
-			runner.Log("info", msg)
-		},
+		Update:    true, // Enable update mode
+		RepoRoot:  dotfilesPath,
+		EventFunc: runner.LogEvent,
 	}
 
 	// Use CloneExternal with Update: true to update existing repos
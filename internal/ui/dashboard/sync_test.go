@@ -1,12 +1,15 @@
 package dashboard
 
 import (
+	"context"
 	"errors"
 	"strings"
 	"testing"
 
+	tea "github.com/charmbracelet/bubbletea"
 	"github.com/nvandessel/go4dot/internal/config"
 	"github.com/nvandessel/go4dot/internal/deps"
+	"github.com/nvandessel/go4dot/internal/doctor"
 	"github.com/nvandessel/go4dot/internal/stow"
 )
 
@@ -28,6 +31,37 @@ func TestSyncResult_HasErrors(t *testing.T) {
 			},
 			expected: true,
 		},
+		{
+			name: "Dependency failed during full sync",
+			result: &SyncResult{
+				Success:    []string{"vim"},
+				DepsFailed: []deps.InstallError{{Item: config.DependencyItem{Name: "ripgrep"}, Error: errors.New("test")}},
+			},
+			expected: true,
+		},
+		{
+			name: "External failed during full sync",
+			result: &SyncResult{
+				ExternalFailed: []deps.ExternalError{{Dep: config.ExternalDep{Name: "tpm"}, Error: errors.New("test")}},
+			},
+			expected: true,
+		},
+		{
+			name: "Links failed verification",
+			result: &SyncResult{
+				Success: []string{"vim"},
+				Verify:  &doctor.Verification{Failed: []doctor.SymlinkCheck{{Config: "vim"}}},
+			},
+			expected: true,
+		},
+		{
+			name: "Links verified",
+			result: &SyncResult{
+				Success: []string{"vim"},
+				Verify:  &doctor.Verification{Verified: 2},
+			},
+			expected: false,
+		},
 		{
 			name:     "Empty result",
 			result:   &SyncResult{},
@@ -44,6 +78,29 @@ func TestSyncResult_HasErrors(t *testing.T) {
 	}
 }
 
+func TestSyncResult_Err(t *testing.T) {
+	if err := (&SyncResult{Success: []string{"vim"}}).Err(); err != nil {
+		t.Errorf("Err() = %v, want nil", err)
+	}
+
+	linkErr := errors.New("conflict")
+	r := &SyncResult{
+		Failed:     []stow.StowError{{ConfigName: "vim", Error: linkErr}},
+		DepsFailed: []deps.InstallError{{Item: config.DependencyItem{Name: "ripgrep"}}},
+	}
+	if err := r.Err(); !errors.Is(err, linkErr) {
+		t.Errorf("Err() = %v, want the link error first", err)
+	}
+
+	r = &SyncResult{ExternalFailed: []deps.ExternalError{{Dep: config.ExternalDep{Name: "tpm"}}}}
+	if err := r.Err(); err == nil || !strings.Contains(err.Error(), "external") {
+		t.Errorf("Err() = %v, want external failure", err)
+	}
+	if got := r.Summary(); got != "0 synced, 1 externals failed" {
+		t.Errorf("Summary() = %q", got)
+	}
+}
+
 func TestSyncResult_Summary(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -85,6 +142,14 @@ func TestSyncResult_Summary(t *testing.T) {
 			},
 			expected: "2 synced, 1 failed, 1 skipped",
 		},
+		{
+			name: "With verification",
+			result: &SyncResult{
+				Success: []string{"vim"},
+				Verify:  &doctor.Verification{Verified: 3, Failed: []doctor.SymlinkCheck{{Config: "vim"}}},
+			},
+			expected: "1 synced; 3 links verified, 1 failed",
+		},
 	}
 
 	for _, tt := range tests {
@@ -265,3 +330,36 @@ func TestCollectUpdateErrors(t *testing.T) {
 		})
 	}
 }
+
+func TestLogStowAction(t *testing.T) {
+	msgs := make(chan tea.Msg, 8)
+	runner := newChannelRunner(context.Background(), 1, msgs)
+	log := logStowAction(runner, 1)
+	log(stow.Action{Kind: stow.ActionLink, Path: ".config/nvim/init.lua"})
+	log(stow.Action{Kind: stow.ActionConflict, Message: "existing target is neither a link nor a directory: .zshrc"})
+	log(stow.Action{Kind: stow.ActionLink, Path: ".tmux.conf", Config: "tmux"})
+	runner.flush()
+	close(msgs)
+
+	var got []string
+	for batch := range msgs {
+		for _, msg := range batch.(OperationBatchMsg).Msgs {
+			switch msg := msg.(type) {
+			case OperationProgressMsg:
+				got = append(got, "progress:"+msg.Detail)
+			case OperationLogMsg:
+				got = append(got, msg.Level+":"+msg.Message)
+			}
+		}
+	}
+	want := []string{
+		"progress:LINK .config/nvim/init.lua",
+		"info:LINK .config/nvim/init.lua",
+		"warning:existing target is neither a link nor a directory: .zshrc",
+		"progress:[tmux] LINK .tmux.conf",
+		"info:[tmux] LINK .tmux.conf",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("messages = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,152 @@
+package dashboard
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/nvandessel/go4dot/internal/triage"
+	"github.com/nvandessel/go4dot/internal/ui"
+)
+
+// TriageViewCloseMsg is sent when the triage view should close.
+// RunDoctor asks the dashboard to re-run the health checks.
+type TriageViewCloseMsg struct {
+	RunDoctor bool
+}
+
+// triageOpener opens log files and issue URLs; replaced in tests
+var triageOpener = triage.Open
+
+// TriageView is offered after an inline operation fails. It shows the full
+// log, the doctor checks worth running and ways to report the failure.
+type TriageView struct {
+	report   triage.Report
+	checks   []string
+	issueURL string
+	logPath  string
+	status   string
+	viewport viewport.Model
+	width    int
+	height   int
+	ready    bool
+}
+
+// NewTriageView builds the triage view for a failed operation
+func NewTriageView(report triage.Report, redactor triage.Redactor) *TriageView {
+	vp := viewport.New(0, 0)
+	vp.Style = lipgloss.NewStyle()
+	return &TriageView{
+		report:   report,
+		checks:   triage.SuggestChecks(report),
+		issueURL: triage.IssueURL(report, redactor),
+		viewport: vp,
+	}
+}
+
+// Init initializes the triage view
+func (v *TriageView) Init() tea.Cmd {
+	return nil
+}
+
+// SetSize updates the view dimensions
+func (v *TriageView) SetSize(width, height int) {
+	v.width = width
+	v.height = height
+	contentWidth := width - 6
+	contentHeight := height - 12
+	if contentWidth < 10 {
+		contentWidth = 10
+	}
+	if contentHeight < 5 {
+		contentHeight = 5
+	}
+	v.viewport.Width = contentWidth
+	v.viewport.Height = contentHeight
+	v.ready = true
+	v.updateContent()
+}
+
+// Update handles messages
+func (v *TriageView) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if msg, ok := msg.(tea.KeyMsg); ok {
+		switch msg.String() {
+		case "esc", "q":
+			return v, func() tea.Msg { return TriageViewCloseMsg{} }
+		case "d":
+			return v, func() tea.Msg { return TriageViewCloseMsg{RunDoctor: true} }
+		case "o":
+			v.openLog()
+			return v, nil
+		case "i":
+			if err := triageOpener(v.issueURL); err != nil {
+				v.status = ui.ErrorStyle.Render(err.Error())
+			} else {
+				v.status = ui.SubtleStyle.Render("Opened a prefilled issue in the browser")
+			}
+			return v, nil
+		}
+		if !key.Matches(msg, key.NewBinding(key.WithKeys("up", "down", "k", "j", "pgup", "pgdown"))) {
+			return v, nil
+		}
+	}
+
+	var cmd tea.Cmd
+	v.viewport, cmd = v.viewport.Update(msg)
+	return v, cmd
+}
+
+// openLog writes the report to the log directory, once, and opens it
+func (v *TriageView) openLog() {
+	if v.logPath == "" {
+		dir, err := triage.LogDir()
+		if err == nil {
+			v.logPath, err = v.report.WriteLog(dir)
+		}
+		if err != nil {
+			v.status = ui.ErrorStyle.Render(err.Error())
+			return
+		}
+	}
+	if err := triageOpener(v.logPath); err != nil {
+		v.status = ui.ErrorStyle.Render(fmt.Sprintf("Log saved to %s (%v)", v.logPath, err))
+		return
+	}
+	v.status = ui.SubtleStyle.Render("Opened " + v.logPath)
+}
+
+// View renders the triage content
+func (v *TriageView) View() string {
+	return overlayTriageContent(v)
+}
+
+// summary renders what failed and which doctor checks to run
+func (v *TriageView) summary() string {
+	labelStyle := lipgloss.NewStyle().Foreground(ui.SubtleColor)
+	errStyle := lipgloss.NewStyle().Foreground(ui.ErrorColor)
+
+	failed := v.report.Operation
+	if v.report.Step != "" {
+		failed += " › " + v.report.Step
+	}
+	lines := []string{
+		labelStyle.Render("Failed: ") + errStyle.Render(failed),
+		labelStyle.Render("Error:  ") + v.report.Error,
+	}
+	if len(v.checks) > 0 {
+		lines = append(lines, labelStyle.Render("Check:  ")+strings.Join(v.checks, ", "))
+	}
+	return strings.Join(lines, "\n")
+}
+
+func (v *TriageView) updateContent() {
+	if len(v.report.Log) == 0 {
+		v.viewport.SetContent(ui.SubtleStyle.Render("No output was logged."))
+		return
+	}
+	v.viewport.SetContent(strings.Join(v.report.Log, "\n"))
+	v.viewport.GotoBottom()
+}
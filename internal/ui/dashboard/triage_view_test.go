@@ -0,0 +1,86 @@
+package dashboard
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/nvandessel/go4dot/internal/platform"
+	"github.com/nvandessel/go4dot/internal/triage"
+)
+
+func TestTriageView_Content(t *testing.T) {
+	v := NewTriageView(triage.Report{
+		Operation: "Syncing All",
+		Step:      "Stowing configs",
+		Error:     "stow failed for nvim",
+		Log:       []string{"[info] linking nvim", "[error] stow failed for nvim"},
+	}, triage.Redactor{})
+	v.SetSize(100, 40)
+
+	content := v.View()
+	for _, want := range []string{"Operation Failed", "Syncing All › Stowing configs", "GNU Stow, Symlinks", "[info] linking nvim", "report issue"} {
+		if !strings.Contains(content, want) {
+			t.Errorf("expected %q in triage view, got:\n%s", want, content)
+		}
+	}
+}
+
+func TestTriageView_OpenIssue(t *testing.T) {
+	var opened []string
+	orig := triageOpener
+	triageOpener = func(target string) error {
+		opened = append(opened, target)
+		return nil
+	}
+	defer func() { triageOpener = orig }()
+
+	v := NewTriageView(triage.Report{Operation: "Syncing All", Error: "boom"}, triage.Redactor{})
+	v.SetSize(100, 40)
+	v.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'i'}})
+
+	if len(opened) != 1 || !strings.HasPrefix(opened[0], triage.IssuesURL) {
+		t.Errorf("expected the issue URL to be opened, got %v", opened)
+	}
+}
+
+func TestOperationFailure_OpensTriage(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		demo     bool
+		wantOpen bool
+	}{
+		{"failure", errors.New("stow failed"), false, true},
+		{"success", nil, false, false},
+		{"canceled", context.Canceled, false, false},
+		{"demo", errors.New("stow failed"), true, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := New(State{Platform: &platform.Platform{OS: "linux"}, HasConfig: true, Demo: tt.demo})
+			m.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+			m.operationID = 1
+			m.operationActive = true
+
+			m.Update(OperationDoneMsg{ID: 1, Success: tt.err == nil, Error: tt.err})
+			if opened := m.currentView == viewTriage && m.triage != nil; opened != tt.wantOpen {
+				t.Fatalf("triage opened = %v, want %v", opened, tt.wantOpen)
+			}
+			if !tt.wantOpen {
+				return
+			}
+
+			m.Update(TriageViewCloseMsg{RunDoctor: true})
+			if m.currentView != viewDashboard || m.triage != nil {
+				t.Error("expected the triage view to close")
+			}
+			if m.focusManager.CurrentFocus() != PanelHealth {
+				t.Error("expected running doctor to focus the health panel")
+			}
+		})
+	}
+}
@@ -0,0 +1,194 @@
+package dashboard
+
+import (
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/nvandessel/go4dot/internal/prefs"
+	"github.com/nvandessel/go4dot/internal/ui"
+)
+
+// tutorialStep is one callout of the dashboard tour. Steps without a panel
+// are shown centered.
+type tutorialStep struct {
+	panel    PanelID
+	hasPanel bool
+	title    string
+	body     string
+}
+
+// tutorialSteps is the dashboard tour, in order
+var tutorialSteps = []tutorialStep{
+	{
+		title: "Welcome to go4dot",
+		body:  "This short tour shows what each panel is for and how to run your first sync.\n\nUse → or enter to continue, ← to go back and esc to skip.",
+	},
+	{
+		panel: PanelConfigs, hasPanel: true,
+		title: "Configs",
+		body:  "Every config in your .go4dot.yaml, with its link status.\n\nMove with ↑/↓, select with space, filter with /.",
+	},
+	{
+		panel: PanelDetails, hasPanel: true,
+		title: "Details",
+		body:  "Files, links and conflicts of the selected config, or details about whichever panel has focus.",
+	},
+	{
+		panel: PanelSummary, hasPanel: true,
+		title: "Summary",
+		body:  "Your platform and how many configs are linked, missing or drifted.",
+	},
+	{
+		panel: PanelHealth, hasPanel: true,
+		title: "Health",
+		body:  "Results of the doctor checks. Press d to run them again.",
+	},
+	{
+		panel: PanelOverrides, hasPanel: true,
+		title: "Overrides",
+		body:  "Machine-specific settings such as your git identity. Press m to configure them.",
+	},
+	{
+		panel: PanelExternal, hasPanel: true,
+		title: "External",
+		body:  "Plugins and themes cloned from other repos.",
+	},
+	{
+		panel: PanelOutput, hasPanel: true,
+		title: "Output",
+		body:  "Progress and logs of the running operation. Press x to cancel it.",
+	},
+	{
+		title: "Your first sync",
+		body:  "Press s to sync everything: link your configs, install dependencies and clone externals.\n\nPress enter to sync just the selected config, or l to only create links.\n\nPress ? at any time for all shortcuts, and t there to take this tour again.",
+	},
+}
+
+// Tutorial is the guided tour of the dashboard's panels
+type Tutorial struct {
+	step int
+}
+
+// NewTutorial creates a tour starting at its first step
+func NewTutorial() *Tutorial {
+	return &Tutorial{}
+}
+
+// current returns the current step
+func (t *Tutorial) current() tutorialStep {
+	return tutorialSteps[t.step]
+}
+
+// Next moves to the next step and reports whether there was one
+func (t *Tutorial) Next() bool {
+	if t.step+1 >= len(tutorialSteps) {
+		return false
+	}
+	t.step++
+	return true
+}
+
+// Prev moves to the previous step
+func (t *Tutorial) Prev() {
+	if t.step > 0 {
+		t.step--
+	}
+}
+
+// startTutorial opens the tour over the dashboard
+func (m *Model) startTutorial() {
+	m.showHelp = false
+	m.tutorial = NewTutorial()
+}
+
+// updateTutorial handles keys while the tour is open. Other messages keep
+// going to the dashboard so panels finish loading behind it.
+func (m *Model) updateTutorial(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "right", "l", "enter", " ", "n":
+		if !m.tutorial.Next() {
+			m.endTutorial()
+		}
+	case "left", "h", "p":
+		m.tutorial.Prev()
+	case "esc", "q", "ctrl+c":
+		m.endTutorial()
+	}
+	return m, nil
+}
+
+// endTutorial closes the tour and remembers that it was shown
+func (m *Model) endTutorial() {
+	m.tutorial = nil
+
+	if m.state.Preferences == nil {
+		m.state.Preferences = prefs.Default()
+	}
+	if m.state.Preferences.TutorialSeen {
+		return
+	}
+	m.state.Preferences.TutorialSeen = true
+	if m.state.Demo {
+		return
+	}
+
+	// Reload the file rather than saving the in-memory preferences, which
+	// may be defaults standing in for a file that failed to load
+	onDisk, err := prefs.Load()
+	if err == nil {
+		onDisk.TutorialSeen = true
+		err = onDisk.Save()
+	}
+	if err != nil {
+		m.outputPanel.AddLog("warning", fmt.Sprintf("Could not save that the tour was shown: %v", err))
+	}
+}
+
+// viewTutorial renders the current step as a callout next to its panel
+func (m Model) viewTutorial(bg string) string {
+	step := m.tutorial.current()
+
+	var target ui.Region
+	if step.hasPanel {
+		r := m.layout.GetPanelRegion(step.panel)
+		target = ui.Region{X: r.X, Y: r.Y, Width: r.Width, Height: r.Height}
+	}
+	return ui.RenderCallout(bg, overlayTutorialContent(m.tutorial, m.width, m.height), m.width, m.height, target, ui.CalloutOverlayStyle())
+}
+
+// overlayTutorialContent returns the tour callout content for overlay compositing (without border/placement).
+func overlayTutorialContent(t *Tutorial, width, height int) string {
+	contentWidth, _ := overlayContentSize(width, height, ui.CalloutOverlayStyle())
+	if contentWidth > 40 {
+		contentWidth = 40
+	}
+	step := t.current()
+
+	titleStyle := lipgloss.NewStyle().
+		Foreground(ui.PrimaryColor).
+		Bold(true)
+
+	bodyStyle := lipgloss.NewStyle().
+		Foreground(ui.TextColor).
+		Width(contentWidth)
+
+	hintStyle := lipgloss.NewStyle().
+		Foreground(ui.SubtleColor).
+		Italic(true)
+
+	hint := "→ next · ← back · esc skip"
+	if t.step == len(tutorialSteps)-1 {
+		hint = "enter to finish"
+	}
+
+	content := lipgloss.JoinVertical(
+		lipgloss.Left,
+		titleStyle.Render(step.title)+hintStyle.Render(fmt.Sprintf("  %d/%d", t.step+1, len(tutorialSteps))),
+		"",
+		bodyStyle.Render(step.body),
+		"",
+		hintStyle.Render(hint),
+	)
+	return lipgloss.NewStyle().Width(contentWidth).Render(content)
+}
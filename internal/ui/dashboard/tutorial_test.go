@@ -0,0 +1,103 @@
+package dashboard
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/nvandessel/go4dot/internal/config"
+	"github.com/nvandessel/go4dot/internal/platform"
+	"github.com/nvandessel/go4dot/internal/prefs"
+)
+
+func newTutorialTestModel(t *testing.T, showTutorial bool) *Model {
+	t.Helper()
+	t.Setenv("HOME", t.TempDir())
+	m := New(State{
+		Platform:     &platform.Platform{OS: "linux"},
+		Configs:      []config.ConfigItem{{Name: "vim"}},
+		HasConfig:    true,
+		ShowTutorial: showTutorial,
+	})
+	m.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+	return &m
+}
+
+func TestTutorial_ShownOnFirstLaunch(t *testing.T) {
+	m := newTutorialTestModel(t, true)
+	if m.tutorial == nil {
+		t.Fatal("expected the tour to open on launch")
+	}
+	if view := m.View(); !strings.Contains(view, "Welcome to go4dot") {
+		t.Error("expected the first step to be rendered")
+	}
+
+	if m := newTutorialTestModel(t, false); m.tutorial != nil {
+		t.Error("expected no tour without ShowTutorial")
+	}
+}
+
+func TestTutorial_Navigation(t *testing.T) {
+	m := newTutorialTestModel(t, true)
+
+	pressKeys(m, "right", "right")
+	if m.tutorial.step != 2 {
+		t.Fatalf("step = %d, want 2", m.tutorial.step)
+	}
+	if view := m.View(); !strings.Contains(view, tutorialSteps[2].title) {
+		t.Errorf("expected step %q to be rendered", tutorialSteps[2].title)
+	}
+
+	pressKeys(m, "left", "left", "left")
+	if m.tutorial.step != 0 {
+		t.Errorf("step = %d, want 0", m.tutorial.step)
+	}
+
+	// Keys drive the tour, not the dashboard
+	pressKeys(m, "/")
+	if m.filterMode {
+		t.Error("dashboard keys should not apply while the tour is open")
+	}
+
+	for range tutorialSteps {
+		pressKeys(m, "enter")
+	}
+	if m.tutorial != nil {
+		t.Error("expected the tour to close after its last step")
+	}
+}
+
+func TestTutorial_DismissSavesPreference(t *testing.T) {
+	m := newTutorialTestModel(t, true)
+
+	pressKeys(m, "esc")
+	if m.tutorial != nil {
+		t.Fatal("esc did not close the tour")
+	}
+	if m.quitting {
+		t.Error("esc should close the tour, not quit the dashboard")
+	}
+	if !m.state.Preferences.TutorialSeen {
+		t.Error("expected the tour to be marked as seen")
+	}
+
+	onDisk, err := prefs.Load()
+	if err != nil {
+		t.Fatalf("prefs.Load failed: %v", err)
+	}
+	if !onDisk.TutorialSeen {
+		t.Error("expected tutorial_seen to be saved to the preferences file")
+	}
+}
+
+func TestTutorial_RelaunchFromHelp(t *testing.T) {
+	m := newTutorialTestModel(t, false)
+
+	pressKeys(m, "?", "t")
+	if m.showHelp {
+		t.Error("expected help to close when the tour starts")
+	}
+	if m.tutorial == nil {
+		t.Fatal("expected t in help to start the tour")
+	}
+}
@@ -0,0 +1,79 @@
+package dashboard
+
+import (
+	"errors"
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/nvandessel/go4dot/internal/config"
+	"github.com/nvandessel/go4dot/internal/setup"
+	"github.com/nvandessel/go4dot/internal/stow"
+	"github.com/nvandessel/go4dot/internal/ui"
+)
+
+// confirmUndoConflicts lists the files the last conflict resolution moved
+// out of the way and asks before putting them back
+func (m *Model) confirmUndoConflicts() tea.Cmd {
+	if m.state.Config == nil || m.operationActive {
+		return nil
+	}
+	if m.state.Demo {
+		m.outputPanel.AddLog("info", "Demo mode: there are no conflict resolutions to undo")
+		return nil
+	}
+
+	rec, err := stow.LoadUndoRecord()
+	if errors.Is(err, stow.ErrNothingToUndo) {
+		m.outputPanel.AddLog("info", "Nothing to undo: no conflict resolution is recorded")
+		return nil
+	}
+	if err != nil {
+		m.outputPanel.AddLog("error", err.Error())
+		return nil
+	}
+
+	details := make([]string, 0, len(rec.Entries))
+	for _, e := range rec.Entries {
+		details = append(details, fmt.Sprintf("%s %s", e.Action, e.TargetPath))
+	}
+	m.confirm = NewConfirm(
+		"undo-conflicts",
+		"Undo conflict resolution?",
+		fmt.Sprintf("The links of %d config(s) are removed and these files are put back.", len(rec.Configs())),
+	).WithLabels("Restore", "Cancel").WithDetails(details)
+	contentWidth, contentHeight := overlayContentSize(m.width, m.height, ui.ConfirmOverlayStyle())
+	m.confirm.SetSize(contentWidth, contentHeight)
+	m.pushView(viewConfirm)
+	return nil
+}
+
+// startUndoConflicts runs the undo as an inline operation
+func (m *Model) startUndoConflicts() tea.Cmd {
+	opCfg, opPath := m.state.Config, m.state.DotfilesPath
+	return m.StartInlineOperation(OpUndoConflicts, "", nil, func(runner *OperationRunner) error {
+		return RunUndoConflictsOperation(runner, opCfg, opPath)
+	})
+}
+
+// RunUndoConflictsOperation reverts the last conflict resolution within the
+// dashboard
+func RunUndoConflictsOperation(runner *OperationRunner, cfg *config.Config, dotfilesPath string) error {
+	runner.Progress(0, "Restoring files...")
+
+	st := loadOrCreateState()
+	rec, err := setup.UndoConflicts(cfg, dotfilesPath, st, setup.RefactorOptions{
+		ProgressFunc: func(current, total int, msg string) {
+			runner.Log("info", msg)
+		},
+	})
+	if err != nil {
+		runner.StepComplete(0, StepError, err.Error())
+		runner.StepComplete(1, StepSkipped, "Skipped")
+		return fmt.Errorf("undo conflicts: %w", err)
+	}
+	runner.StepComplete(0, StepSuccess, fmt.Sprintf("%d file(s) restored", len(rec.Entries)))
+	runner.StepComplete(1, StepSuccess, fmt.Sprintf("%d config(s) unlinked", len(rec.Configs())))
+
+	runner.Done(true, fmt.Sprintf("Restored %d file(s); sync to link the configs again", len(rec.Entries)), nil)
+	return nil
+}
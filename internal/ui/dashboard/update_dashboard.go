@@ -27,9 +27,27 @@ func (m *Model) updateDashboard(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.quitting = true
 			m.setResult(ActionQuit)
 			return m, tea.Quit
+		case m.operationActive && key.Matches(msg, keys.Cancel):
+			m.CancelOperation()
+			return m, nil
+		case key.Matches(msg, keys.Record):
+			if !m.replayingMacro {
+				m.startMacroRecording()
+			}
+			return m, nil
+		case key.Matches(msg, keys.Macro):
+			if m.replayingMacro {
+				return m, nil
+			}
+			return m, m.replayMacro(msg.String())
+		case key.Matches(msg, keys.Snapshot):
+			m.saveSnapshot()
+			return m, nil
 		case key.Matches(msg, keys.Filter):
 			m.filterMode = true
 			return m, nil
+		case key.Matches(msg, keys.Search):
+			return m.openSearch()
 		case key.Matches(msg, keys.Menu):
 			// SetSize internally constrains to compact menu panel bounds
 			m.menu.SetSize(m.width, m.height)
@@ -93,12 +111,23 @@ func (m *Model) updateDashboard(msg tea.Msg) (tea.Model, tea.Cmd) {
 			cmds = append(cmds, cmd)
 		}
 
-	case externalStatusMsg:
+	case externalStatusMsg, externalGitHubMsg:
 		cmd := m.externalPanel.Update(msg)
 		if cmd != nil {
 			cmds = append(cmds, cmd)
 		}
 
+	case configStatsMsg:
+		m.detailsPanel.SetStats(msg)
+
+	case configChangesMsg:
+		m.logConfigChanges(msg)
+
+	case linkFileMsg:
+		if cmd := m.handleLinkFile(msg); cmd != nil {
+			cmds = append(cmds, cmd)
+		}
+
 	// Handle unconfigured machine configs detection
 	case machineConfigsUnconfiguredMsg:
 		desc := fmt.Sprintf("%d machine config(s) need setup. Configure now?", len(msg.missing))
@@ -113,15 +142,6 @@ func (m *Model) updateDashboard(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.pushView(viewConfirm)
 		return m, nil
 
-	// Handle operation messages for inline operations
-	case OperationProgressMsg, OperationStepCompleteMsg, OperationLogMsg, OperationDoneMsg:
-		handled, cmd := m.handleOperationMsg(msg)
-		if handled {
-			if _, ok := msg.(OperationDoneMsg); ok {
-				m.outputPanel.SetTitle("Output")
-			}
-			return m, cmd
-		}
 	}
 
 	// Forward spinner tick to loading panels
@@ -140,6 +160,11 @@ func (m *Model) updateDashboard(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 	}
 
+	// Size stats are computed lazily for whichever config is shown
+	if cmd := m.detailsPanel.LoadStats(); cmd != nil {
+		cmds = append(cmds, cmd)
+	}
+
 	return m, tea.Batch(cmds...)
 }
 
@@ -227,41 +252,25 @@ func (m *Model) handlePanelNavigation(msg tea.KeyMsg) tea.Cmd {
 func (m *Model) handlePanelActions(msg tea.KeyMsg) tea.Cmd {
 	focused := m.focusManager.CurrentFocus()
 
+	if what := m.mutatingAction(msg, focused); what != "" && m.refuseReadOnly(what) {
+		return nil
+	}
+
 	switch {
 	// Global operations (s, i, u)
 	case key.Matches(msg, keys.Sync):
-		if m.state.Config != nil && !m.operationActive {
-			// Check for conflicts before syncing
-			conflicts, err := CheckForConflicts(m.state.Config, m.state.DotfilesPath, nil)
-			if err != nil {
-				m.outputPanel.AddLog("error", fmt.Sprintf("Failed to check conflicts: %v", err))
-				return nil
-			}
-			if len(conflicts) > 0 {
-				// Show conflict resolution modal
-				m.conflictView = NewConflictView(conflicts)
-				contentWidth, contentHeight := overlayContentSize(m.width, m.height, ui.ConflictOverlayStyle())
-				m.conflictView.SetSize(contentWidth, contentHeight)
-				m.pendingOperation = OpSync
-				m.pendingConflicts = conflicts
-				m.pushView(viewConflict)
-				return nil
-			}
-			// No conflicts, proceed normally
-			opts := SyncOptions{Force: false, Interactive: false}
-			return m.StartInlineOperation(OpSync, "", nil, func(runner *OperationRunner) error {
-				_, err := RunSyncAllOperation(runner, m.state.Config, m.state.DotfilesPath, opts)
-				if err != nil {
-					return fmt.Errorf("sync all: %w", err)
-				}
-				return nil
-			})
-		}
+		return m.startSync(OpSync, "", nil)
+
+	case key.Matches(msg, keys.Link):
+		return m.startSync(OpLink, "", nil)
 
 	case key.Matches(msg, keys.Install):
+		if m.dryRun {
+			return m.startDryRun(OpInstall, "", nil)
+		}
 		if m.state.Config != nil && !m.operationActive {
 			// Check for conflicts before installing
-			conflicts, err := CheckForConflicts(m.state.Config, m.state.DotfilesPath, nil)
+			conflicts, err := m.checkForConflicts(nil)
 			if err != nil {
 				m.outputPanel.AddLog("error", fmt.Sprintf("Failed to check conflicts: %v", err))
 				return nil
@@ -269,6 +278,7 @@ func (m *Model) handlePanelActions(msg tea.KeyMsg) tea.Cmd {
 			if len(conflicts) > 0 {
 				// Show conflict resolution modal
 				m.conflictView = NewConflictView(conflicts)
+				m.conflictView.SetStrategies(m.state.Config.GetAllConfigs())
 				contentWidth, contentHeight := overlayContentSize(m.width, m.height, ui.ConflictOverlayStyle())
 				m.conflictView.SetSize(contentWidth, contentHeight)
 				m.pendingOperation = OpInstall
@@ -277,9 +287,10 @@ func (m *Model) handlePanelActions(msg tea.KeyMsg) tea.Cmd {
 				return nil
 			}
 			// No conflicts, proceed normally
-			opts := InstallOptions{}
+			opts := InstallOptions{Escalation: m.state.Preferences.EscalationTool()}
+			opCfg, opPath := m.state.Config, m.state.DotfilesPath
 			return m.StartInlineOperation(OpInstall, "", nil, func(runner *OperationRunner) error {
-				_, err := RunInstallOperation(runner, m.state.Config, m.state.DotfilesPath, opts)
+				_, err := RunInstallOperation(runner, opCfg, opPath, opts)
 				if err != nil {
 					return fmt.Errorf("install: %w", err)
 				}
@@ -290,8 +301,9 @@ func (m *Model) handlePanelActions(msg tea.KeyMsg) tea.Cmd {
 	case key.Matches(msg, keys.Update):
 		if m.state.Config != nil && !m.operationActive {
 			opts := UpdateOptions{UpdateExternal: true}
+			opCfg, opPath := m.state.Config, m.state.DotfilesPath
 			return m.StartInlineOperation(OpUpdate, "", nil, func(runner *OperationRunner) error {
-				_, err := RunUpdateOperation(runner, m.state.Config, m.state.DotfilesPath, opts)
+				_, err := RunUpdateOperation(runner, opCfg, opPath, opts)
 				if err != nil {
 					return fmt.Errorf("update: %w", err)
 				}
@@ -313,6 +325,49 @@ func (m *Model) handlePanelActions(msg tea.KeyMsg) tea.Cmd {
 		}
 		return nil
 
+	// Docs (D) - toggle the selected config's README in the Details panel
+	case key.Matches(msg, keys.Docs):
+		if focused != PanelConfigs && focused != PanelDetails {
+			return nil
+		}
+		if m.detailsPanel.ToggleDocs() {
+			m.changeFocus(PanelDetails)
+		}
+		return nil
+
+	// U upgrades outdated dependencies from the Health panel, and undoes
+	// the last conflict resolution elsewhere
+	case key.Matches(msg, keys.Undo):
+		if focused == PanelHealth && len(m.healthPanel.Outdated()) > 0 {
+			return m.startUpgradeOutdated()
+		}
+		return m.confirmUndoConflicts()
+
+	case key.Matches(msg, keys.Plan):
+		return m.openPlan()
+
+	case key.Matches(msg, keys.DryRun):
+		m.toggleDryRun()
+		return nil
+
+	// Profile (P) - narrow the dashboard, sync and install to a profile
+	case key.Matches(msg, keys.Profile):
+		return m.openProfileMenu()
+
+	// Edit (E) - the selected config's description, tags, platforms and
+	// depends_on
+	case key.Matches(msg, keys.Edit):
+		if focused == PanelConfigs || focused == PanelDetails {
+			return m.editMetadata()
+		}
+		return nil
+
+	case key.Matches(msg, keys.Archive):
+		if focused == PanelConfigs {
+			return m.toggleArchive()
+		}
+		return nil
+
 	// Enter - context-specific action
 	case key.Matches(msg, keys.Enter):
 		return m.handleEnterAction(focused)
@@ -322,7 +377,7 @@ func (m *Model) handlePanelActions(msg tea.KeyMsg) tea.Cmd {
 		if focused == PanelConfigs {
 			m.configsPanel.ToggleSelection()
 			m.selectedConfigs = m.configsPanel.GetSelected()
-			m.summaryPanel.SetSelectedCount(len(m.selectedConfigs))
+			return m.bus.Publish(ConfigsChangedEvent{Selected: m.selectedConfigs})
 		}
 
 	// Select All (A)
@@ -336,42 +391,25 @@ func (m *Model) handlePanelActions(msg tea.KeyMsg) tea.Cmd {
 				m.configsPanel.SelectAll()
 			}
 			m.selectedConfigs = m.configsPanel.GetSelected()
-			m.summaryPanel.SetSelectedCount(len(m.selectedConfigs))
+			return m.bus.Publish(ConfigsChangedEvent{Selected: m.selectedConfigs})
 		}
 
 	// Bulk sync (S)
 	case key.Matches(msg, keys.Bulk):
-		if len(m.selectedConfigs) > 0 && m.state.Config != nil && !m.operationActive {
-			names := make([]string, 0, len(m.selectedConfigs))
-			for name := range m.selectedConfigs {
-				names = append(names, name)
-			}
-			// Check for conflicts for selected configs only
-			conflicts, err := CheckForConflicts(m.state.Config, m.state.DotfilesPath, names)
-			if err != nil {
-				m.outputPanel.AddLog("error", fmt.Sprintf("Failed to check conflicts: %v", err))
-				return nil
-			}
-			if len(conflicts) > 0 {
-				// Show conflict resolution modal
-				m.conflictView = NewConflictView(conflicts)
-				contentWidth, contentHeight := overlayContentSize(m.width, m.height, ui.ConflictOverlayStyle())
-				m.conflictView.SetSize(contentWidth, contentHeight)
-				m.pendingOperation = OpBulkSync
-				m.pendingConfigNames = names
-				m.pendingConflicts = conflicts
-				m.pushView(viewConflict)
-				return nil
-			}
-			// No conflicts, proceed normally
-			opts := SyncOptions{Force: false, Interactive: false}
-			return m.StartInlineOperation(OpBulkSync, "", names, func(runner *OperationRunner) error {
-				_, err := RunBulkSyncOperation(runner, m.state.Config, m.state.DotfilesPath, names, opts)
-				if err != nil {
-					return fmt.Errorf("bulk sync: %w", err)
-				}
-				return nil
-			})
+		if len(m.selectedConfigs) > 0 {
+			return m.startSync(OpBulkSync, "", m.selectedConfigNames())
+		}
+
+	// Link selected (L), or the highlighted config when nothing is selected
+	case key.Matches(msg, keys.BulkLink):
+		if focused != PanelConfigs {
+			return nil
+		}
+		if len(m.selectedConfigs) > 0 {
+			return m.startSync(OpBulkLink, "", m.selectedConfigNames())
+		}
+		if cfg := m.configsPanel.GetSelectedConfig(); cfg != nil {
+			return m.startSync(OpLinkSingle, cfg.Name, nil)
 		}
 	}
 
@@ -383,40 +421,20 @@ func (m *Model) handleEnterAction(focused PanelID) tea.Cmd {
 	switch focused {
 	case PanelConfigs:
 		// Sync selected config
-		cfg := m.configsPanel.GetSelectedConfig()
-		if cfg != nil && m.state.Config != nil && !m.operationActive {
-			// Check for conflicts for this specific config
-			conflicts, err := CheckForConflicts(m.state.Config, m.state.DotfilesPath, []string{cfg.Name})
-			if err != nil {
-				m.outputPanel.AddLog("error", fmt.Sprintf("Failed to check conflicts: %v", err))
-				return nil
-			}
-			if len(conflicts) > 0 {
-				// Show conflict resolution modal
-				m.conflictView = NewConflictView(conflicts)
-				contentWidth, contentHeight := overlayContentSize(m.width, m.height, ui.ConflictOverlayStyle())
-				m.conflictView.SetSize(contentWidth, contentHeight)
-				m.pendingOperation = OpSyncSingle
-				m.pendingConfigName = cfg.Name
-				m.pendingConflicts = conflicts
-				m.pushView(viewConflict)
-				return nil
-			}
-			// No conflicts, proceed normally
-			opts := SyncOptions{Force: false, Interactive: false}
-			return m.StartInlineOperation(OpSyncSingle, cfg.Name, nil, func(runner *OperationRunner) error {
-				_, err := RunSyncSingleOperation(runner, m.state.Config, m.state.DotfilesPath, cfg.Name, opts)
-				if err != nil {
-					return fmt.Errorf("sync %s: %w", cfg.Name, err)
-				}
-				return nil
-			})
+		if cfg := m.configsPanel.GetSelectedConfig(); cfg != nil {
+			return m.startSync(OpSyncSingle, cfg.Name, nil)
 		}
 
 	case PanelHealth:
 		// Re-run health checks
 		return m.healthPanel.Refresh()
 
+	case PanelDetails:
+		// Relink the unlinked file under the cursor
+		if name, relPath, ok := m.detailsPanel.SelectedFile(); ok {
+			return m.linkFile(name, relPath)
+		}
+
 	case PanelOverrides:
 		// Open machine config form (modal)
 		mc := m.overridesPanel.GetSelectedConfig()
@@ -429,6 +447,21 @@ func (m *Model) handleEnterAction(focused PanelID) tea.Cmd {
 		}
 
 	case PanelExternal:
+		// Clone/update every external in a group
+		if group, ok := m.externalPanel.GetSelectedGroup(); ok && m.state.Config != nil && !m.operationActive {
+			ids := group.IDs()
+			if len(ids) == 0 {
+				return nil
+			}
+			opCfg, opPath := m.state.Config, m.state.DotfilesPath
+			return m.StartInlineOperation(OpExternal, group.Label(), nil, func(runner *OperationRunner) error {
+				if _, err := RunExternalGroupOperation(runner, opCfg, opPath, ids); err != nil {
+					return fmt.Errorf("externals of %s: %w", group.Label(), err)
+				}
+				return nil
+			})
+		}
+
 		// Clone/update external dep
 		ext := m.externalPanel.GetSelectedExternal()
 		if ext != nil && m.state.Config != nil && !m.operationActive {
@@ -436,8 +469,9 @@ func (m *Model) handleEnterAction(focused PanelID) tea.Cmd {
 			// If already installed, update; if missing, clone
 			shouldUpdate := ext.Status == "installed"
 			opts := ExternalSingleOptions{Update: shouldUpdate}
+			opCfg, opPath := m.state.Config, m.state.DotfilesPath
 			return m.StartInlineOperation(OpExternalSingle, ext.Dep.Name, nil, func(runner *OperationRunner) error {
-				_, err := RunExternalSingleOperation(runner, m.state.Config, m.state.DotfilesPath, extID, opts)
+				_, err := RunExternalSingleOperation(runner, opCfg, opPath, extID, opts)
 				if err != nil {
 					return fmt.Errorf("external %s: %w", ext.Dep.Name, err)
 				}
@@ -482,3 +516,97 @@ func (m *Model) updateDetailsContext() {
 		m.detailsPanel.SetContext(DetailsContextConfigs)
 	}
 }
+
+// selectedConfigNames returns the names of the selected configs
+func (m *Model) selectedConfigNames() []string {
+	names := make([]string, 0, len(m.selectedConfigs))
+	for name := range m.selectedConfigs {
+		names = append(names, name)
+	}
+	return names
+}
+
+// startSync starts a sync or link operation (see startSyncOperation) for all
+// configs, configName or configNames. Uncommitted changes in the repo are
+// confirmed first (see confirmRepoState), then conflicts with existing files
+// are resolved in the conflict view, which starts the operation when done.
+func (m *Model) startSync(opType OperationType, configName string, configNames []string) tea.Cmd {
+	if m.state.Config == nil || m.operationActive {
+		return nil
+	}
+	if m.dryRun {
+		return m.startDryRun(opType, configName, configNames)
+	}
+	if m.confirmRepoState(opType, configName, configNames) {
+		return nil
+	}
+	return m.resolveConflictsAndSync(opType, configName, configNames)
+}
+
+// resolveConflictsAndSync opens the conflict view when existing files block
+// the configs, and starts the operation otherwise
+func (m *Model) resolveConflictsAndSync(opType OperationType, configName string, configNames []string) tea.Cmd {
+	var scope []string
+	switch {
+	case configName != "":
+		scope = []string{configName}
+	case len(configNames) > 0:
+		scope = configNames
+	}
+
+	conflicts, err := m.checkForConflicts(scope)
+	if err != nil {
+		m.outputPanel.AddLog("error", fmt.Sprintf("Failed to check conflicts: %v", err))
+		return nil
+	}
+	if len(conflicts) > 0 {
+		m.conflictView = NewConflictView(conflicts)
+		m.conflictView.SetStrategies(m.state.Config.GetAllConfigs())
+		contentWidth, contentHeight := overlayContentSize(m.width, m.height, ui.ConflictOverlayStyle())
+		m.conflictView.SetSize(contentWidth, contentHeight)
+		m.pendingOperation = opType
+		m.pendingConfigName = configName
+		m.pendingConfigNames = configNames
+		m.pendingConflicts = conflicts
+		m.pushView(viewConflict)
+		return nil
+	}
+
+	return m.startSyncOperation(opType, configName, configNames)
+}
+
+// startSyncOperation runs a sync or link operation inline. Sync operations
+// (OpSync, OpSyncSingle, OpBulkSync) run the full pipeline; link operations
+// (OpLink, OpLinkSingle, OpBulkLink) only create symlinks.
+func (m *Model) startSyncOperation(opType OperationType, configName string, configNames []string) tea.Cmd {
+	opts := SyncOptions{Force: false, Interactive: false, UseTrash: m.state.Preferences.TrashEnabled(), Escalation: m.state.Preferences.EscalationTool(), Jobs: m.state.Preferences.Workers()}
+	verb := "link"
+	switch opType {
+	case OpSync, OpSyncSingle, OpBulkSync:
+		opts.Full = true
+		verb = "sync"
+	}
+
+	opCfg, opPath := m.state.Config, m.state.DotfilesPath
+	return m.StartInlineOperation(opType, configName, configNames, func(runner *OperationRunner) error {
+		var err error
+		switch opType {
+		case OpSync, OpLink:
+			_, err = RunSyncAllOperation(runner, opCfg, opPath, opts)
+			if err != nil {
+				return fmt.Errorf("%s all: %w", verb, err)
+			}
+		case OpSyncSingle, OpLinkSingle:
+			_, err = RunSyncSingleOperation(runner, opCfg, opPath, configName, opts)
+			if err != nil {
+				return fmt.Errorf("%s %s: %w", verb, configName, err)
+			}
+		default:
+			_, err = RunBulkSyncOperation(runner, opCfg, opPath, configNames, opts)
+			if err != nil {
+				return fmt.Errorf("bulk %s: %w", verb, err)
+			}
+		}
+		return nil
+	})
+}
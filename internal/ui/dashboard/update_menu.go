@@ -3,6 +3,7 @@ package dashboard
 import (
 	"github.com/charmbracelet/bubbles/key"
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/nvandessel/go4dot/internal/prefs"
 	"github.com/nvandessel/go4dot/internal/ui"
 )
 
@@ -44,6 +45,16 @@ func (m *Model) handleMenuAction(action Action) (tea.Model, tea.Cmd) {
 		m.pushView(viewConfigList)
 		return m, nil
 
+	case ActionSearch:
+		return m.openSearch()
+
+	case ActionInventory:
+		m.inventory = NewInventoryView(m.state.Config, m.state.DotfilesPath, m.state.Platform)
+		contentWidth, contentHeight := overlayContentSize(m.width, m.height, ui.DefaultOverlayStyle())
+		m.inventory.SetSize(contentWidth, contentHeight)
+		m.pushView(viewInventory)
+		return m, nil
+
 	case ActionExternal:
 		if m.state.Config == nil {
 			return m, nil
@@ -55,6 +66,13 @@ func (m *Model) handleMenuAction(action Action) (tea.Model, tea.Cmd) {
 		return m, m.externalView.Init()
 
 	case ActionUninstall:
+		if m.refuseReadOnly("uninstall") {
+			return m, nil
+		}
+		if !m.state.ConfirmTracker.ShouldConfirm(prefs.OpUninstall) {
+			m.setResult(ActionUninstall)
+			return m, tea.Quit
+		}
 		m.confirm = NewConfirm(
 			"uninstall",
 			"Uninstall go4dot?",
@@ -10,6 +10,7 @@ import (
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/nvandessel/go4dot/internal/config"
 	"github.com/nvandessel/go4dot/internal/machine"
+	"github.com/nvandessel/go4dot/internal/prefs"
 	"github.com/nvandessel/go4dot/internal/ui"
 )
 
@@ -50,6 +51,9 @@ func (m *Model) updateNoConfig(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.setResult(ActionQuit)
 			return m, tea.Quit
 		case key.Matches(msg, key.NewBinding(key.WithKeys("i"), key.WithKeys("enter"))):
+			if m.state.ReadOnly {
+				return m, nil
+			}
 			return m.startOnboarding()
 		}
 	case tea.WindowSizeMsg:
@@ -140,10 +144,59 @@ func (m *Model) updateConfirm(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	case ConfirmResult:
 		if msg.ID == "uninstall" && msg.Confirmed {
+			m.state.ConfirmTracker.Record(prefs.OpUninstall)
 			m.setResult(ActionUninstall)
 			return m, tea.Quit
 		}
 
+		if msg.ID == "dirty-repo" {
+			m.popView()
+			m.confirm = nil
+
+			opType, configName, configNames := m.pendingOperation, m.pendingConfigName, m.pendingConfigNames
+			m.pendingOperation = 0
+			m.pendingConfigName = ""
+			m.pendingConfigNames = nil
+			if !msg.Confirmed {
+				m.outputPanel.AddLog("info", "Operation cancelled")
+				return m, nil
+			}
+			return m, m.resolveConflictsAndSync(opType, configName, configNames)
+		}
+
+		if msg.ID == "delete-conflicts" {
+			// Return to the conflict modal; it stays open if the user cancels
+			m.popView()
+			m.confirm = nil
+
+			if msg.Confirmed && m.conflictView != nil {
+				m.state.ConfirmTracker.Record(prefs.OpDeleteConflicts)
+				return m, m.conflictView.apply(ConflictChoiceDelete)
+			}
+			return m, nil
+		}
+
+		if msg.ID == "undo-conflicts" {
+			m.popView()
+			m.confirm = nil
+			if !msg.Confirmed {
+				return m, nil
+			}
+			return m, m.startUndoConflicts()
+		}
+
+		if msg.ID == "archive" {
+			m.popView()
+			m.confirm = nil
+
+			name := m.pendingConfigName
+			m.pendingConfigName = ""
+			if !msg.Confirmed {
+				return m, nil
+			}
+			return m, m.setArchived(name, true)
+		}
+
 		if msg.ID == "machine-setup-prompt" {
 			m.popView()
 			m.confirm = nil
@@ -167,14 +220,8 @@ func (m *Model) updateConfirm(msg tea.Msg) (tea.Model, tea.Cmd) {
 				// User chose to install - set up dashboard first, then check conflicts
 				dotfilesPath := filepath.Dir(m.pendingNewConfigPath)
 
-				// Update the model state with the new config
-				m.state.Config = m.pendingNewConfig
-				m.state.DotfilesPath = dotfilesPath
-				m.state.HasConfig = true
-				m.state.Configs = m.pendingNewConfig.GetAllConfigs()
-
-				// Reinitialize all panels with the new config
-				m.reinitializePanels()
+				// Point the dashboard at the new config
+				initCmds := []tea.Cmd{m.loadConfig(m.pendingNewConfig, dotfilesPath)}
 
 				// Clear pending onboarding state
 				m.pendingNewConfig = nil
@@ -184,13 +231,8 @@ func (m *Model) updateConfirm(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.clearViewStack()
 				m.currentView = viewDashboard
 
-				// Initialize panels
-				var initCmds []tea.Cmd
-				initCmds = append(initCmds, m.healthPanel.Init())
-				initCmds = append(initCmds, m.externalPanel.Init())
-
 				// Check for conflicts before installing
-				conflicts, err := CheckForConflicts(m.state.Config, m.state.DotfilesPath, nil)
+				conflicts, err := m.checkForConflicts(nil)
 				if err != nil {
 					m.outputPanel.AddLog("error", fmt.Sprintf("Failed to check conflicts: %v", err))
 					return m, tea.Batch(initCmds...)
@@ -199,6 +241,7 @@ func (m *Model) updateConfirm(msg tea.Msg) (tea.Model, tea.Cmd) {
 				if len(conflicts) > 0 {
 					// Show conflict resolution modal
 					m.conflictView = NewConflictView(conflicts)
+					m.conflictView.SetStrategies(m.state.Config.GetAllConfigs())
 					contentWidth, contentHeight := overlayContentSize(m.width, m.height, ui.ConflictOverlayStyle())
 					m.conflictView.SetSize(contentWidth, contentHeight)
 					m.pendingOperation = OpInstall
@@ -208,9 +251,10 @@ func (m *Model) updateConfirm(msg tea.Msg) (tea.Model, tea.Cmd) {
 				}
 
 				// No conflicts, proceed with install
-				opts := InstallOptions{}
+				opts := InstallOptions{Escalation: m.state.Preferences.EscalationTool()}
+				opCfg, opPath := m.state.Config, m.state.DotfilesPath
 				installCmd := m.StartInlineOperation(OpInstall, "", nil, func(runner *OperationRunner) error {
-					_, err := RunInstallOperation(runner, m.state.Config, m.state.DotfilesPath, opts)
+					_, err := RunInstallOperation(runner, opCfg, opPath, opts)
 					if err != nil {
 						return fmt.Errorf("install: %w", err)
 					}
@@ -224,19 +268,11 @@ func (m *Model) updateConfirm(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 
 			// User declined install - return to dashboard with new config loaded
-			if m.pendingNewConfigPath != "" {
-				dotfilesPath := filepath.Dir(m.pendingNewConfigPath)
-
-				// Update the model state with the new config if available
-				if m.pendingNewConfig != nil {
-					m.state.Config = m.pendingNewConfig
-					m.state.DotfilesPath = dotfilesPath
-					m.state.HasConfig = true
-					m.state.Configs = m.pendingNewConfig.GetAllConfigs()
-
-					// Reinitialize all panels with the new config
-					m.reinitializePanels()
-				}
+			var loadCmd tea.Cmd
+			if m.pendingNewConfigPath != "" && m.pendingNewConfig != nil {
+				loadCmd = m.loadConfig(m.pendingNewConfig, filepath.Dir(m.pendingNewConfigPath))
+			} else {
+				loadCmd = m.bus.Publish(StatusUpdatedEvent{State: m.state})
 			}
 
 			// Clear pending state
@@ -247,8 +283,7 @@ func (m *Model) updateConfirm(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.clearViewStack()
 			m.currentView = viewDashboard
 
-			// Initialize panels
-			return m, tea.Batch(m.healthPanel.Init(), m.externalPanel.Init())
+			return m, loadCmd
 		}
 
 		m.popView()
@@ -323,6 +358,68 @@ func (m *Model) updateExternal(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
+// openSearch opens the file content search overlay
+func (m *Model) openSearch() (tea.Model, tea.Cmd) {
+	if m.state.Config == nil {
+		return m, nil
+	}
+	m.searchView = NewSearchView(m.state.Config, m.state.DotfilesPath)
+	contentWidth, contentHeight := overlayContentSize(m.width, m.height, ui.DefaultOverlayStyle())
+	m.searchView.SetSize(contentWidth, contentHeight)
+	m.pushView(viewSearch)
+	return m, m.searchView.Init()
+}
+
+// updateSearch handles messages for the search view
+func (m *Model) updateSearch(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		if m.searchView != nil {
+			contentWidth, contentHeight := overlayContentSize(msg.Width, msg.Height, ui.DefaultOverlayStyle())
+			m.searchView.SetSize(contentWidth, contentHeight)
+		}
+
+	case SearchViewCloseMsg:
+		m.popView()
+		m.searchView = nil
+		return m, nil
+
+	case SearchJumpMsg:
+		m.popView()
+		m.searchView = nil
+		m.selectConfig(msg.ConfigName)
+		return m, nil
+	}
+
+	if m.searchView != nil {
+		model, cmd := m.searchView.Update(msg)
+		if sv, ok := model.(*SearchView); ok {
+			m.searchView = sv
+		}
+		return m, cmd
+	}
+
+	return m, nil
+}
+
+// selectConfig focuses the configs panel on the named config, clearing any
+// filter that would hide it
+func (m *Model) selectConfig(name string) {
+	for i, c := range m.state.Configs {
+		if c.Name != name {
+			continue
+		}
+		m.filterText = ""
+		m.configsPanel.SetFilter("")
+		m.configsPanel.SetSelectedIndex(i)
+		m.changeFocus(PanelConfigs)
+		m.updateDetailsContext()
+		return
+	}
+}
+
 // updateMachine handles messages for the machine configuration view
 func (m *Model) updateMachine(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
@@ -350,25 +447,29 @@ func (m *Model) updateMachine(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, nil
 		}
 
+		if m.state.Demo {
+			m.outputPanel.AddLog("info", fmt.Sprintf("Demo mode: %s was not written to %s", mc.ID, mc.Destination))
+			return m, nil
+		}
+
 		opts := machine.RenderOptions{Overwrite: true}
 		result, err := machine.RenderAndWrite(mc, msg.Values, opts)
 		if err != nil {
 			m.outputPanel.AddLog("error", fmt.Sprintf("Failed to write config: %v", err))
-			m.overridesPanel.RefreshStatus()
-			return m, nil
+			return m, m.bus.Publish(StatusUpdatedEvent{State: m.state})
 		}
 
 		m.outputPanel.AddLog("success", fmt.Sprintf("Wrote %s to %s", result.ID, result.Destination))
-		m.overridesPanel.RefreshStatus()
+		statusCmd := m.bus.Publish(StatusUpdatedEvent{State: m.state})
 
 		// Run verification after successful write
 		gpgKeyID := msg.Values["signing_key"]
-		return m, func() tea.Msg {
+		return m, tea.Batch(statusCmd, func() tea.Msg {
 			ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
 			defer cancel()
 			results := machine.RunAllVerifications(ctx, gpgKeyID)
 			return MachineVerifyCompleteMsg{Results: results}
-		}
+		})
 
 	case MachineVerifyCompleteMsg:
 		for _, r := range msg.Results {
@@ -426,11 +527,29 @@ func (m *Model) updateConflict(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 
 		// Conflicts resolved, execute the pending operation
-		m.outputPanel.AddLog("success", fmt.Sprintf("Resolved %d conflict(s)", len(m.pendingConflicts)))
+		m.outputPanel.AddLog("success", fmt.Sprintf("Resolved %d conflict(s); press U to undo", len(m.pendingConflicts)))
 		return m.executePendingOperation()
+
+	case conflictDeleteRequestMsg:
+		desc := fmt.Sprintf("%d file(s) in your home directory will be deleted. U undoes this until the next conflict resolution.", msg.count)
+		if m.state.Preferences.TrashEnabled() {
+			desc = fmt.Sprintf("%d file(s) in your home directory will be moved to the trash.", msg.count)
+		}
+		m.confirm = NewConfirm(
+			"delete-conflicts",
+			"Delete conflicting files?",
+			desc,
+		).WithLabels("Yes, delete", "Cancel")
+		contentWidth, contentHeight := overlayContentSize(m.width, m.height, ui.ConfirmOverlayStyle())
+		m.confirm.SetSize(contentWidth, contentHeight)
+		m.pushView(viewConfirm)
+		return m, nil
 	}
 
 	if m.conflictView != nil {
+		m.conflictView.SetConfirmDelete(m.state.ConfirmTracker.ShouldConfirm(prefs.OpDeleteConflicts))
+		m.conflictView.SetUseTrash(m.state.Preferences.TrashEnabled())
+		m.conflictView.SetDotfilesPath(m.state.DotfilesPath)
 		model, cmd := m.conflictView.Update(msg)
 		if cv, ok := model.(*ConflictView); ok {
 			m.conflictView = cv
@@ -478,12 +597,6 @@ func (m *Model) updateOperation(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.operations.width = contentWidth
 		m.operations.height = contentHeight
 		return m, nil
-
-	case OperationProgressMsg, OperationStepCompleteMsg, OperationLogMsg, OperationDoneMsg:
-		handled, cmd := m.handleOperationMsg(msg)
-		if handled {
-			return m, cmd
-		}
 	}
 
 	m.operations, cmd = m.operations.Update(msg)
@@ -504,74 +617,168 @@ func (m *Model) executePendingOperation() (tea.Model, tea.Cmd) {
 
 	// Execute the operation based on type
 	switch opType {
-	case OpSync:
-		opts := SyncOptions{Force: false, Interactive: false}
-		return m, m.StartInlineOperation(OpSync, "", nil, func(runner *OperationRunner) error {
-			_, err := RunSyncAllOperation(runner, m.state.Config, m.state.DotfilesPath, opts)
-			if err != nil {
-				return fmt.Errorf("sync all: %w", err)
-			}
-			return nil
-		})
-
 	case OpInstall:
-		opts := InstallOptions{}
+		opts := InstallOptions{Escalation: m.state.Preferences.EscalationTool()}
+		opCfg, opPath := m.state.Config, m.state.DotfilesPath
 		return m, m.StartInlineOperation(OpInstall, "", nil, func(runner *OperationRunner) error {
-			_, err := RunInstallOperation(runner, m.state.Config, m.state.DotfilesPath, opts)
+			_, err := RunInstallOperation(runner, opCfg, opPath, opts)
 			if err != nil {
 				return fmt.Errorf("install: %w", err)
 			}
 			return nil
 		})
 
-	case OpSyncSingle:
-		opts := SyncOptions{Force: false, Interactive: false}
-		return m, m.StartInlineOperation(OpSyncSingle, configName, nil, func(runner *OperationRunner) error {
-			_, err := RunSyncSingleOperation(runner, m.state.Config, m.state.DotfilesPath, configName, opts)
-			if err != nil {
-				return fmt.Errorf("sync %s: %w", configName, err)
-			}
-			return nil
-		})
-
-	case OpBulkSync:
-		opts := SyncOptions{Force: false, Interactive: false}
-		return m, m.StartInlineOperation(OpBulkSync, "", configNames, func(runner *OperationRunner) error {
-			_, err := RunBulkSyncOperation(runner, m.state.Config, m.state.DotfilesPath, configNames, opts)
-			if err != nil {
-				return fmt.Errorf("bulk sync: %w", err)
-			}
-			return nil
-		})
+	case OpSync, OpSyncSingle, OpBulkSync, OpLink, OpLinkSingle, OpBulkLink:
+		return m, m.startSyncOperation(opType, configName, configNames)
 	}
 
 	return m, nil
 }
 
-// reinitializePanels recreates all panels with the current state
-func (m *Model) reinitializePanels() {
-	m.summaryPanel = NewSummaryPanel(m.state)
-	m.configsPanel = NewConfigsPanel(m.state, m.selectedConfigs)
-	m.healthPanel = NewHealthPanel(m.state.Config, m.state.DotfilesPath)
-	m.overridesPanel = NewOverridesPanel(m.state.Config)
-	m.externalPanel = NewExternalPanel(m.state.Config, m.state.DotfilesPath, m.state.Platform)
-	m.detailsPanel = NewDetailsPanel(m.state)
-	m.detailsPanel.SetPanels(m.configsPanel, m.healthPanel, m.overridesPanel, m.externalPanel)
-	m.outputPanel = NewOutputPanel()
-
-	// Re-register all panels
-	m.panels[PanelSummary] = m.summaryPanel
-	m.panels[PanelConfigs] = m.configsPanel
-	m.panels[PanelHealth] = m.healthPanel
-	m.panels[PanelOverrides] = m.overridesPanel
-	m.panels[PanelExternal] = m.externalPanel
-	m.panels[PanelDetails] = m.detailsPanel
-	m.panels[PanelOutput] = m.outputPanel
-
-	// Apply layout to set panel dimensions
+// loadConfig points the dashboard at cfg, loaded from dotfilesPath. The
+// panels pick it up from the published status update.
+func (m *Model) loadConfig(cfg *config.Config, dotfilesPath string) tea.Cmd {
+	m.state.Config = cfg
+	m.state.DotfilesPath = dotfilesPath
+	m.state.HasConfig = true
+	m.state.Configs = cfg.GetAllConfigs()
+	m.footer.SetProfile(cfg.ActiveProfile)
+	cmd := m.bus.Publish(StatusUpdatedEvent{State: m.state})
+
+	// Panels are not laid out while there is no config
 	m.layout.Calculate(m.width, m.height)
 	m.layout.ApplyToPanels(m.panels)
 
 	// Use changeFocus to properly sync FocusManager, footer, and details context
 	m.changeFocus(PanelConfigs)
+	return cmd
+}
+
+// updateInventory handles messages for the machine inventory view
+func (m *Model) updateInventory(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		if m.inventory != nil {
+			contentWidth, contentHeight := overlayContentSize(msg.Width, msg.Height, ui.DefaultOverlayStyle())
+			m.inventory.SetSize(contentWidth, contentHeight)
+		}
+
+	case InventoryViewCloseMsg:
+		m.popView()
+		m.inventory = nil
+		return m, nil
+	}
+
+	if m.inventory != nil {
+		model, cmd := m.inventory.Update(msg)
+		if iv, ok := model.(*InventoryView); ok {
+			m.inventory = iv
+		}
+		return m, cmd
+	}
+
+	return m, nil
+}
+
+// updateTriage handles messages for the failure triage view
+func (m *Model) updateTriage(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		if m.triage != nil {
+			contentWidth, contentHeight := overlayContentSize(msg.Width, msg.Height, ui.DefaultOverlayStyle())
+			m.triage.SetSize(contentWidth, contentHeight)
+		}
+
+	case TriageViewCloseMsg:
+		m.popView()
+		m.triage = nil
+		if msg.RunDoctor {
+			m.changeFocus(PanelHealth)
+			return m, m.healthPanel.Refresh()
+		}
+		return m, nil
+	}
+
+	if m.triage != nil {
+		model, cmd := m.triage.Update(msg)
+		if tv, ok := model.(*TriageView); ok {
+			m.triage = tv
+		}
+		return m, cmd
+	}
+
+	return m, nil
+}
+
+// updatePlan handles messages for the plan editor
+func (m *Model) updatePlan(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		if m.planView != nil {
+			contentWidth, contentHeight := overlayContentSize(msg.Width, msg.Height, ui.DefaultOverlayStyle())
+			m.planView.SetSize(contentWidth, contentHeight)
+		}
+
+	case PlanViewCloseMsg:
+		m.popView()
+		plan := m.planView.plan
+		m.planView = nil
+		if msg.Run {
+			return m, m.runPlan(plan)
+		}
+		return m, nil
+	}
+
+	if m.planView != nil {
+		model, cmd := m.planView.Update(msg)
+		if pv, ok := model.(*PlanView); ok {
+			m.planView = pv
+		}
+		return m, cmd
+	}
+
+	return m, nil
+}
+
+// updateMetadata handles messages for the metadata form
+func (m *Model) updateMetadata(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		if m.metadataForm != nil {
+			contentWidth, contentHeight := overlayContentSize(msg.Width, msg.Height, ui.DefaultOverlayStyle())
+			m.metadataForm.SetSize(contentWidth, contentHeight)
+		}
+
+	case FormCompleteMsg:
+		m.popView()
+		edit := m.metadata
+		m.metadataForm, m.metadata = nil, nil
+		if msg.FormID == metadataFormID && edit != nil {
+			return m, m.saveMetadata(edit)
+		}
+		return m, nil
+
+	case FormCancelMsg:
+		m.popView()
+		m.metadataForm, m.metadata = nil, nil
+		return m, nil
+	}
+
+	if m.metadataForm != nil {
+		model, cmd := m.metadataForm.Update(msg)
+		if fv, ok := model.(*FormView); ok {
+			m.metadataForm = fv
+		}
+		return m, cmd
+	}
+
+	return m, nil
 }
@@ -0,0 +1,54 @@
+package dashboard
+
+import (
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/nvandessel/go4dot/internal/config"
+	"github.com/nvandessel/go4dot/internal/deps"
+	"github.com/nvandessel/go4dot/internal/platform"
+)
+
+// startUpgradeOutdated upgrades the dependencies the Health panel lists as
+// outdated, leaving missing ones alone
+func (m *Model) startUpgradeOutdated() tea.Cmd {
+	if m.state.Config == nil || m.operationActive {
+		return nil
+	}
+
+	opCfg := m.state.Config
+	escalation := m.state.Preferences.EscalationTool()
+	return m.StartInlineOperation(OpUpgradeDeps, "", nil, func(runner *OperationRunner) error {
+		return RunUpgradeOutdatedOperation(runner, opCfg, escalation)
+	})
+}
+
+// RunUpgradeOutdatedOperation upgrades every dependency installed at too
+// old a version within the dashboard
+func RunUpgradeOutdatedOperation(runner *OperationRunner, cfg *config.Config, escalation string) error {
+	runner.Progress(0, "Upgrading outdated dependencies...")
+
+	p, err := platform.Detect()
+	if err != nil {
+		runner.StepComplete(0, StepError, err.Error())
+		return fmt.Errorf("failed to detect platform: %w", err)
+	}
+
+	result, err := deps.Install(cfg, p, deps.InstallOptions{
+		OnlyOutdated: true,
+		Escalation:   escalation,
+		AskPassword:  runner.AskPassword,
+		EventFunc:    runner.LogEvent,
+	})
+	if err != nil {
+		runner.StepComplete(0, StepError, err.Error())
+		return fmt.Errorf("upgrade dependencies: %w", err)
+	}
+
+	if len(result.Failed) > 0 {
+		runner.StepComplete(0, StepWarning, fmt.Sprintf("%d upgraded, %d failed", len(result.Installed), len(result.Failed)))
+		return fmt.Errorf("%d dependencies failed to upgrade", len(result.Failed))
+	}
+	runner.StepComplete(0, StepSuccess, fmt.Sprintf("%d upgraded", len(result.Installed)))
+	return nil
+}
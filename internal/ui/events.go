@@ -0,0 +1,92 @@
+package ui
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sync"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/mattn/go-isatty"
+	"github.com/nvandessel/go4dot/internal/progress"
+	"github.com/nvandessel/go4dot/internal/transcript"
+)
+
+// EventPrinter prints progress events for the non-interactive commands.
+// On a terminal, work in progress is shown on one line that the next event
+// overwrites, so only outcomes stay on screen; elsewhere every event gets
+// its own line.
+type EventPrinter struct {
+	mu     sync.Mutex
+	w      io.Writer
+	live   bool
+	indent string
+	open   bool // A running line is waiting to be overwritten
+}
+
+// NewEventPrinter returns a printer writing to w, indenting each line
+func NewEventPrinter(w io.Writer, indent string) *EventPrinter {
+	live := false
+	if f, ok := w.(*os.File); ok {
+		live = (isatty.IsTerminal(f.Fd()) || isatty.IsCygwinTerminal(f.Fd())) && !IsPlain()
+	}
+	return &EventPrinter{w: w, live: live, indent: indent}
+}
+
+// DetailEvents returns a printer for detailed output, like Printf: it
+// prints nothing with --summary or --quiet
+func DetailEvents(indent string) *EventPrinter {
+	return NewEventPrinter(Details(), indent)
+}
+
+// Print prints an event
+func (p *EventPrinter) Print(e progress.Event) {
+	transcript.Event(e)
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.open {
+		_, _ = fmt.Fprint(p.w, "\r\033[K")
+		p.open = false
+	}
+
+	line := p.indent + formatEvent(e)
+	if e.Running && p.live {
+		_, _ = fmt.Fprint(p.w, line)
+		p.open = true
+		return
+	}
+	_, _ = fmt.Fprintln(p.w, line)
+}
+
+// Done clears a running line left on screen; call it once the operation
+// has finished
+func (p *EventPrinter) Done() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.open {
+		_, _ = fmt.Fprint(p.w, "\r\033[K")
+		p.open = false
+	}
+}
+
+// formatEvent renders an event as its counter, colored icon and message
+func formatEvent(e progress.Event) string {
+	msg := e.Message
+	switch e.Level {
+	case progress.Success:
+		msg = SuccessStyle.Render(e.Icon()) + " " + msg
+	case progress.Skipped:
+		msg = SubtleStyle.Render(e.Icon() + " " + msg)
+	case progress.Warning:
+		msg = lipgloss.NewStyle().Foreground(WarningColor).Render(e.Icon() + " " + msg)
+	case progress.Error:
+		msg = ErrorStyle.Render(e.Icon()) + " " + msg
+	}
+	if e.Percent() >= 0 {
+		msg = SubtleStyle.Render(fmt.Sprintf("[%d/%d]", e.Current, e.Total)) + " " + msg
+	}
+	return msg
+}
@@ -0,0 +1,54 @@
+package ui
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/nvandessel/go4dot/internal/progress"
+)
+
+func TestEventPrinter_Plain(t *testing.T) {
+	var buf bytes.Buffer
+	p := NewEventPrinter(&buf, "  ")
+
+	p.Print(progress.Started(progress.CategoryExternal, "tpm", 1, 2, "Cloning %s...", "tpm"))
+	p.Print(progress.Itemf(progress.Success, progress.CategoryExternal, "tpm", 1, 2, "Cloned %s", "tpm"))
+	p.Done()
+
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected one line per event, got %q", buf.String())
+	}
+	if !strings.Contains(lines[0], "[1/2]") || !strings.Contains(lines[0], "Cloning tpm...") {
+		t.Errorf("running line = %q", lines[0])
+	}
+	if !strings.HasPrefix(lines[1], "  ") || !strings.Contains(lines[1], "✓") {
+		t.Errorf("success line = %q", lines[1])
+	}
+	if strings.Contains(buf.String(), "\r") {
+		t.Errorf("plain output should not rewrite lines: %q", buf.String())
+	}
+}
+
+func TestEventPrinter_Live(t *testing.T) {
+	var buf bytes.Buffer
+	p := &EventPrinter{w: &buf, live: true}
+
+	p.Print(progress.Started(progress.CategoryDependency, "git", 1, 1, "Installing %s...", "git"))
+	if strings.HasSuffix(buf.String(), "\n") {
+		t.Errorf("running line should stay open, got %q", buf.String())
+	}
+
+	p.Print(progress.Itemf(progress.Error, progress.CategoryDependency, "git", 1, 1, "Failed to install %s", "git"))
+	out := buf.String()
+	if !strings.Contains(out, "\r\033[K") || !strings.HasSuffix(out, "\n") {
+		t.Errorf("outcome should overwrite the running line, got %q", out)
+	}
+
+	buf.Reset()
+	p.Done()
+	if buf.Len() != 0 {
+		t.Errorf("Done() with no running line wrote %q", buf.String())
+	}
+}
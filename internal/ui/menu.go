@@ -111,7 +111,7 @@ func RunInteractiveMenu(updateMsg string) (MenuAction, error) {
 	choice := ActionQuit
 	m := model{list: l, choice: &choice, platform: p}
 
-	if _, err := tea.NewProgram(m, tea.WithAltScreen()).Run(); err != nil {
+	if _, err := tea.NewProgram(m, ProgramOptions(true, false)...).Run(); err != nil {
 		return ActionQuit, err
 	}
 
@@ -0,0 +1,83 @@
+package ui
+
+import (
+	"os"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/cursor"
+	"github.com/charmbracelet/bubbles/spinner"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// reducedMotionFPS caps redraws with reduced motion, so terminals that
+// flicker on every frame are spared
+const reducedMotionFPS = 15
+
+var reducedMotion bool
+
+// staticSpinner stands in for the animated spinner with reduced motion. Its
+// single frame never changes, and it ticks rarely enough to cost nothing.
+var staticSpinner = spinner.Spinner{
+	Frames: []string{"… "},
+	FPS:    time.Hour,
+}
+
+// SetReducedMotion turns reduced motion on or off for the TUI programs:
+// static indicators instead of spinners, no blinking cursors and a lower
+// frame rate. This should be called from the CLI layer with the
+// reduced_motion preference or what DetectReducedMotion reports.
+func SetReducedMotion(value bool) {
+	contextMu.Lock()
+	defer contextMu.Unlock()
+	reducedMotion = value
+}
+
+// IsReducedMotion returns true if the TUI should avoid animation
+func IsReducedMotion() bool {
+	contextMu.RLock()
+	defer contextMu.RUnlock()
+	return reducedMotion
+}
+
+// DetectReducedMotion reports whether the environment asks for reduced
+// motion: GO4DOT_REDUCED_MOTION, or the generic REDUCED_MOTION and
+// NO_MOTION hints some desktops and accessibility setups export.
+func DetectReducedMotion() bool {
+	switch strings.ToLower(os.Getenv("GO4DOT_REDUCED_MOTION")) {
+	case "1", "true", "yes":
+		return true
+	case "0", "false", "no":
+		return false
+	}
+	for _, name := range []string{"REDUCED_MOTION", "NO_MOTION"} {
+		switch strings.ToLower(os.Getenv(name)) {
+		case "", "0", "false", "no":
+		default:
+			return true
+		}
+	}
+	return false
+}
+
+// NewSpinner returns the spinner the TUI shows while work is in progress,
+// or a static indicator with reduced motion
+func NewSpinner() spinner.Model {
+	s := spinner.New()
+	s.Spinner = spinner.Dot
+	if IsReducedMotion() {
+		s.Spinner = staticSpinner
+	}
+	s.Style = lipgloss.NewStyle().Foreground(PrimaryColor)
+	return s
+}
+
+// dropCursorBlink keeps text input cursors steady by swallowing the
+// messages that toggle them
+func dropCursorBlink(_ tea.Model, msg tea.Msg) tea.Msg {
+	if _, ok := msg.(cursor.BlinkMsg); ok {
+		return nil
+	}
+	return msg
+}
@@ -0,0 +1,100 @@
+package ui
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/nvandessel/go4dot/internal/transcript"
+)
+
+// Verbosity selects how much the non-interactive commands print
+type Verbosity int
+
+const (
+	// VerbosityNormal prints progress, per-item results and summaries
+	VerbosityNormal Verbosity = iota
+	// VerbositySummary prints one line per category, plus warnings and errors
+	VerbositySummary
+	// VerbosityQuiet prints errors only, for cron jobs and scripts
+	VerbosityQuiet
+)
+
+var (
+	verbosity Verbosity
+	outWriter io.Writer // Overrides os.Stdout in tests
+)
+
+// stdout returns the writer command output goes to
+func stdout() io.Writer {
+	if outWriter != nil {
+		return outWriter
+	}
+	return os.Stdout
+}
+
+// SetVerbosity sets the output level for the printing helpers in this
+// package. This should be called from the CLI layer when --quiet or
+// --summary is used.
+func SetVerbosity(v Verbosity) {
+	contextMu.Lock()
+	defer contextMu.Unlock()
+	verbosity = v
+}
+
+// CurrentVerbosity returns the output level
+func CurrentVerbosity() Verbosity {
+	contextMu.RLock()
+	defer contextMu.RUnlock()
+	return verbosity
+}
+
+// showDetails reports whether detailed output is printed
+func showDetails() bool {
+	return CurrentVerbosity() == VerbosityNormal
+}
+
+// Details returns the writer for detailed output: stdout at normal
+// verbosity, io.Discard otherwise
+func Details() io.Writer {
+	if showDetails() {
+		return stdout()
+	}
+	return io.Discard
+}
+
+// logOutput adds a printed message to the --log-file transcript, which
+// gets every message whatever the verbosity
+func logOutput(level, msg string) {
+	if strings.TrimSpace(msg) != "" {
+		transcript.Output(level, msg)
+	}
+}
+
+// Printf prints detailed output such as progress and per-item results. It
+// is suppressed by --summary and --quiet.
+func Printf(format string, a ...interface{}) {
+	msg := fmt.Sprintf(format, a...)
+	logOutput("info", msg)
+	_, _ = fmt.Fprint(Details(), msg)
+}
+
+// Println prints a line of detailed output, like Printf
+func Println(a ...interface{}) {
+	msg := fmt.Sprintln(a...)
+	logOutput("info", msg)
+	_, _ = fmt.Fprint(Details(), msg)
+}
+
+// Summary prints a one-line summary for a category of work ("Configs: 3
+// linked"). It is only printed at summary verbosity; at normal verbosity the
+// detailed output already covers it.
+func Summary(category, format string, a ...interface{}) {
+	line := fmt.Sprintf("%s: %s", category, fmt.Sprintf(format, a...))
+	logOutput("summary", line)
+	if CurrentVerbosity() != VerbositySummary {
+		return
+	}
+	_, _ = fmt.Fprintln(stdout(), line)
+}
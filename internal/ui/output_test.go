@@ -0,0 +1,79 @@
+package ui
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestVerbosity_Output(t *testing.T) {
+	var buf bytes.Buffer
+	outWriter = &buf
+	defer func() {
+		outWriter = nil
+		SetVerbosity(VerbosityNormal)
+	}()
+
+	tests := []struct {
+		name      string
+		verbosity Verbosity
+		want      []string
+		notWant   []string
+	}{
+		{
+			name:      "normal prints details but no summary lines",
+			verbosity: VerbosityNormal,
+			want:      []string{"linked nvim", "detail line", "careful", "broken"},
+			notWant:   []string{"Configs: 1 linked"},
+		},
+		{
+			name:      "summary prints summary lines, warnings and errors",
+			verbosity: VerbositySummary,
+			want:      []string{"Configs: 1 linked", "careful", "broken"},
+			notWant:   []string{"linked nvim", "detail line", "Section"},
+		},
+		{
+			name:      "quiet prints errors only",
+			verbosity: VerbosityQuiet,
+			want:      []string{"broken"},
+			notWant:   []string{"linked nvim", "detail line", "careful", "Configs: 1 linked", "Section"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf.Reset()
+			SetVerbosity(tt.verbosity)
+
+			Section("Section")
+			Success("linked nvim")
+			Info("info")
+			Printf("detail %s\n", "line")
+			Warning("careful")
+			Error("broken")
+			Summary("Configs", "%d linked", 1)
+
+			got := buf.String()
+			for _, s := range tt.want {
+				if !strings.Contains(got, s) {
+					t.Errorf("output missing %q:\n%s", s, got)
+				}
+			}
+			for _, s := range tt.notWant {
+				if strings.Contains(got, s) {
+					t.Errorf("output should not contain %q:\n%s", s, got)
+				}
+			}
+		})
+	}
+}
+
+func TestDetails_DiscardsBelowNormal(t *testing.T) {
+	defer SetVerbosity(VerbosityNormal)
+
+	SetVerbosity(VerbositySummary)
+	if w := Details(); w != io.Discard {
+		t.Error("Details() should discard output at summary verbosity")
+	}
+}
@@ -0,0 +1,77 @@
+package ui
+
+import (
+	"os"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// plainFPS caps redraws in plain mode so slow links are not flooded
+const plainFPS = 10
+
+var plain bool
+
+// plainTerms are TERM values for terminals that cannot be trusted with the
+// alternate screen or mouse reporting
+var plainTerms = map[string]bool{
+	"":        true,
+	"dumb":    true,
+	"unknown": true,
+	"vt100":   true,
+	"vt102":   true,
+	"vt220":   true,
+}
+
+// SetPlain sets plain rendering mode for the TUI programs: no alternate
+// screen, no mouse and a reduced redraw rate. This should be called from the
+// CLI layer when --plain is used or DetectPlain reports a limited terminal.
+func SetPlain(value bool) {
+	contextMu.Lock()
+	defer contextMu.Unlock()
+	plain = value
+}
+
+// IsPlain returns true if TUI programs should use plain rendering.
+func IsPlain() bool {
+	contextMu.RLock()
+	defer contextMu.RUnlock()
+	return plain
+}
+
+// DetectPlain reports whether plain rendering should be used without an
+// explicit flag, from GO4DOT_PLAIN or a limited TERM.
+func DetectPlain() bool {
+	switch strings.ToLower(os.Getenv("GO4DOT_PLAIN")) {
+	case "1", "true", "yes":
+		return true
+	case "0", "false", "no":
+		return false
+	}
+	return plainTerms[strings.ToLower(os.Getenv("TERM"))]
+}
+
+// ProgramOptions returns the Bubble Tea options for a program. altScreen and
+// mouse are honored unless plain mode is on, in which case the program runs
+// inline with a capped frame rate. Reduced motion also lowers the frame rate
+// and stops cursors from blinking.
+func ProgramOptions(altScreen, mouse bool) []tea.ProgramOption {
+	var opts []tea.ProgramOption
+	if IsReducedMotion() {
+		opts = append(opts, tea.WithFilter(dropCursorBlink))
+		if !IsPlain() {
+			opts = append(opts, tea.WithFPS(reducedMotionFPS))
+		}
+	}
+	if IsPlain() {
+		return append(opts, tea.WithFPS(plainFPS))
+	}
+
+	if altScreen {
+		opts = append(opts, tea.WithAltScreen())
+	}
+	if mouse {
+		opts = append(opts, tea.WithMouseCellMotion())
+	}
+	return opts
+}
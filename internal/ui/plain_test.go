@@ -0,0 +1,112 @@
+package ui
+
+import (
+	"testing"
+
+	"github.com/charmbracelet/bubbles/cursor"
+)
+
+func TestDetectPlain(t *testing.T) {
+	tests := []struct {
+		name  string
+		term  string
+		plain string
+		want  bool
+	}{
+		{"xterm", "xterm-256color", "", false},
+		{"tmux", "tmux-256color", "", false},
+		{"dumb terminal", "dumb", "", true},
+		{"unset TERM", "", "", true},
+		{"vt100", "VT100", "", true},
+		{"forced on", "xterm-256color", "1", true},
+		{"forced off", "dumb", "false", false},
+		{"unrecognized value falls back to TERM", "xterm", "maybe", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("TERM", tt.term)
+			t.Setenv("GO4DOT_PLAIN", tt.plain)
+			if got := DetectPlain(); got != tt.want {
+				t.Errorf("DetectPlain() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestProgramOptions(t *testing.T) {
+	defer SetPlain(false)
+
+	tests := []struct {
+		name      string
+		plain     bool
+		altScreen bool
+		mouse     bool
+		want      int
+	}{
+		{"fullscreen with mouse", false, true, true, 2},
+		{"alt screen only", false, true, false, 1},
+		{"inline", false, false, false, 0},
+		{"plain drops alt screen and mouse", true, true, true, 1},
+		{"plain inline", true, false, false, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			SetPlain(tt.plain)
+			if IsPlain() != tt.plain {
+				t.Fatalf("IsPlain() = %v, want %v", IsPlain(), tt.plain)
+			}
+			if got := len(ProgramOptions(tt.altScreen, tt.mouse)); got != tt.want {
+				t.Errorf("ProgramOptions(%v, %v) returned %d options, want %d", tt.altScreen, tt.mouse, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDetectReducedMotion(t *testing.T) {
+	tests := []struct {
+		name    string
+		g4d     string
+		generic string
+		want    bool
+	}{
+		{"no hints", "", "", false},
+		{"generic hint", "", "1", true},
+		{"generic hint off", "", "false", false},
+		{"forced on", "yes", "", true},
+		{"forced off wins over hint", "0", "1", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("GO4DOT_REDUCED_MOTION", tt.g4d)
+			t.Setenv("REDUCED_MOTION", tt.generic)
+			t.Setenv("NO_MOTION", "")
+			if got := DetectReducedMotion(); got != tt.want {
+				t.Errorf("DetectReducedMotion() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReducedMotion(t *testing.T) {
+	defer SetReducedMotion(false)
+
+	SetReducedMotion(true)
+	if frames := NewSpinner().Spinner.Frames; len(frames) != 1 {
+		t.Errorf("spinner has %d frames with reduced motion, want a static one", len(frames))
+	}
+	// Filter and frame rate on top of alt screen and mouse
+	if got := len(ProgramOptions(true, true)); got != 4 {
+		t.Errorf("ProgramOptions() returned %d options, want 4", got)
+	}
+	if dropCursorBlink(nil, cursor.BlinkMsg{}) != nil {
+		t.Error("cursor blink messages should be dropped")
+	}
+
+	SetReducedMotion(false)
+	if frames := NewSpinner().Spinner.Frames; len(frames) < 2 {
+		t.Errorf("spinner has %d frames, want it animated", len(frames))
+	}
+}
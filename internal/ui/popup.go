@@ -0,0 +1,254 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/spinner"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Popup dimensions: small enough for a tmux display-popup or a zellij
+// floating pane, which rarely get more than a third of the screen
+const (
+	PopupWidth     = 56
+	PopupMaxHeight = 16
+	popupChrome    = 4 // Title, blank line, status and key hints
+)
+
+// PopupConfig is one row of the popup: a config and how its links stand
+type PopupConfig struct {
+	Name      string
+	Linked    int // Files linked
+	Total     int // Files in the config
+	NewFiles  int // Files in the repo not linked yet
+	Conflicts int // Files in the way of a link
+}
+
+// status summarizes the config's links in a few words
+func (c PopupConfig) status() (string, lipgloss.Style) {
+	switch {
+	case c.Conflicts > 0:
+		return fmt.Sprintf("%d conflicts", c.Conflicts), ErrorStyle
+	case c.Total > 0 && c.Linked == 0:
+		return "not linked", SubtleStyle
+	case c.NewFiles > 0:
+		return fmt.Sprintf("+%d new", c.NewFiles), WarningStyle
+	case c.Linked < c.Total:
+		return fmt.Sprintf("%d/%d linked", c.Linked, c.Total), WarningStyle
+	default:
+		return "linked", SuccessStyle
+	}
+}
+
+// PopupOptions supplies the popup's data and actions
+type PopupOptions struct {
+	Title string
+	Load  func() ([]PopupConfig, error)
+	Sync  func(name string) error // Links one config, or all with ""
+}
+
+type popupLoadedMsg struct {
+	configs []PopupConfig
+	err     error
+}
+
+type popupSyncedMsg struct {
+	name string
+	err  error
+}
+
+type popupModel struct {
+	opts     PopupOptions
+	configs  []PopupConfig
+	selected int
+	offset   int
+	height   int
+	busy     string // What is running, shown with the spinner
+	status   string // Outcome of the last action
+	failed   bool
+	spinner  spinner.Model
+}
+
+func newPopupModel(opts PopupOptions) popupModel {
+	return popupModel{
+		opts:    opts,
+		height:  PopupMaxHeight,
+		busy:    "Checking links",
+		spinner: NewSpinner(),
+	}
+}
+
+func (m popupModel) Init() tea.Cmd {
+	return tea.Batch(m.spinner.Tick, m.load())
+}
+
+func (m popupModel) load() tea.Cmd {
+	return func() tea.Msg {
+		configs, err := m.opts.Load()
+		return popupLoadedMsg{configs: configs, err: err}
+	}
+}
+
+func (m popupModel) sync(name string) tea.Cmd {
+	return func() tea.Msg {
+		return popupSyncedMsg{name: name, err: m.opts.Sync(name)}
+	}
+}
+
+func (m popupModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.height = min(msg.Height, PopupMaxHeight)
+		m.ensureVisible()
+
+	case spinner.TickMsg:
+		if m.busy != "" {
+			var cmd tea.Cmd
+			m.spinner, cmd = m.spinner.Update(msg)
+			return m, cmd
+		}
+
+	case popupLoadedMsg:
+		m.busy = ""
+		m.configs = msg.configs
+		if msg.err != nil {
+			m.status, m.failed = msg.err.Error(), true
+		}
+		if m.selected >= len(m.configs) {
+			m.selected = max(len(m.configs)-1, 0)
+		}
+		m.ensureVisible()
+
+	case popupSyncedMsg:
+		name := msg.name
+		if name == "" {
+			name = "all configs"
+		}
+		if msg.err != nil {
+			m.status, m.failed = fmt.Sprintf("%s: %v", name, msg.err), true
+		} else {
+			m.status, m.failed = "Linked "+name, false
+		}
+		m.busy = "Checking links"
+		return m, tea.Batch(m.spinner.Tick, m.load())
+
+	case tea.KeyMsg:
+		if key.Matches(msg, key.NewBinding(key.WithKeys("q", "esc", "ctrl+c"))) {
+			return m, tea.Quit
+		}
+		if m.busy != "" {
+			return m, nil
+		}
+		switch {
+		case key.Matches(msg, key.NewBinding(key.WithKeys("j", "down"))):
+			if m.selected < len(m.configs)-1 {
+				m.selected++
+				m.ensureVisible()
+			}
+		case key.Matches(msg, key.NewBinding(key.WithKeys("k", "up"))):
+			if m.selected > 0 {
+				m.selected--
+				m.ensureVisible()
+			}
+		case key.Matches(msg, key.NewBinding(key.WithKeys("enter", "s"))):
+			if len(m.configs) > 0 {
+				name := m.configs[m.selected].Name
+				m.busy = "Linking " + name
+				return m, tea.Batch(m.spinner.Tick, m.sync(name))
+			}
+		case key.Matches(msg, key.NewBinding(key.WithKeys("a"))):
+			m.busy = "Linking all configs"
+			return m, tea.Batch(m.spinner.Tick, m.sync(""))
+		case key.Matches(msg, key.NewBinding(key.WithKeys("r"))):
+			m.busy = "Checking links"
+			return m, tea.Batch(m.spinner.Tick, m.load())
+		}
+	}
+	return m, nil
+}
+
+// rows is how many configs fit between the title and the footer
+func (m popupModel) rows() int {
+	return max(m.height-popupChrome, 1)
+}
+
+func (m *popupModel) ensureVisible() {
+	rows := m.rows()
+	if m.selected < m.offset {
+		m.offset = m.selected
+	} else if m.selected >= m.offset+rows {
+		m.offset = m.selected - rows + 1
+	}
+}
+
+func (m popupModel) View() string {
+	var lines []string
+
+	drifted := 0
+	for _, c := range m.configs {
+		if text, _ := c.status(); text != "linked" {
+			drifted++
+		}
+	}
+	title := m.opts.Title
+	if title == "" {
+		title = "go4dot"
+	}
+	titleStyle := lipgloss.NewStyle().Foreground(PrimaryColor).Bold(true)
+	lines = append(lines, titleStyle.Render(title)+SubtleStyle.Render(fmt.Sprintf(" · %d configs, %d need linking", len(m.configs), drifted)))
+
+	end := min(m.offset+m.rows(), len(m.configs))
+	for i := m.offset; i < end; i++ {
+		c := m.configs[i]
+		text, style := c.status()
+		name := truncate(c.Name, PopupWidth-len(text)-4)
+		gap := max(PopupWidth-2-lipgloss.Width(name)-lipgloss.Width(text), 1)
+		line := name + strings.Repeat(" ", gap) + style.Render(text)
+		if i == m.selected {
+			line = SelectedItemStyle.Render("▸ ") + line
+		} else {
+			line = "  " + line
+		}
+		lines = append(lines, line)
+	}
+	if len(m.configs) == 0 && m.busy == "" {
+		lines = append(lines, SubtleStyle.Render("  No configs"))
+	}
+
+	lines = append(lines, "")
+	switch {
+	case m.busy != "":
+		lines = append(lines, m.spinner.View()+m.busy+"...")
+	case m.failed:
+		lines = append(lines, ErrorStyle.Render(truncate(m.status, PopupWidth)))
+	case m.status != "":
+		lines = append(lines, SuccessStyle.Render(truncate(m.status, PopupWidth)))
+	default:
+		lines = append(lines, "")
+	}
+	lines = append(lines, SubtleStyle.Render("enter link · a link all · r refresh · q quit"))
+
+	return strings.Join(lines, "\n") + "\n"
+}
+
+// truncate shortens s to width cells, marking the cut with an ellipsis
+func truncate(s string, width int) string {
+	if width < 1 || lipgloss.Width(s) <= width {
+		return s
+	}
+	runes := []rune(s)
+	for len(runes) > 0 && lipgloss.Width(string(runes))+1 > width {
+		runes = runes[:len(runes)-1]
+	}
+	return string(runes) + "…"
+}
+
+// RunPopup runs the compact link picker inline, without the alternate
+// screen, so it fits a tmux popup or zellij floating pane
+func RunPopup(opts PopupOptions) error {
+	_, err := tea.NewProgram(newPopupModel(opts), ProgramOptions(false, false)...).Run()
+	return err
+}
@@ -0,0 +1,108 @@
+package ui
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+func TestPopupConfigStatus(t *testing.T) {
+	tests := []struct {
+		config PopupConfig
+		want   string
+	}{
+		{PopupConfig{Linked: 3, Total: 3}, "linked"},
+		{PopupConfig{Linked: 0, Total: 3}, "not linked"},
+		{PopupConfig{Linked: 2, Total: 3}, "2/3 linked"},
+		{PopupConfig{Linked: 3, Total: 4, NewFiles: 1}, "+1 new"},
+		{PopupConfig{Linked: 1, Total: 3, Conflicts: 2}, "2 conflicts"},
+	}
+	for _, tt := range tests {
+		if got, _ := tt.config.status(); got != tt.want {
+			t.Errorf("status(%+v) = %q, want %q", tt.config, got, tt.want)
+		}
+	}
+}
+
+func TestPopupModel(t *testing.T) {
+	var synced []string
+	configs := []PopupConfig{
+		{Name: "nvim", Linked: 4, Total: 4},
+		{Name: "zsh", Linked: 0, Total: 2},
+	}
+	m := newPopupModel(PopupOptions{
+		Load: func() ([]PopupConfig, error) { return configs, nil },
+		Sync: func(name string) error {
+			synced = append(synced, name)
+			if name == "" {
+				return errors.New("boom")
+			}
+			return nil
+		},
+	})
+
+	update := func(msg tea.Msg) tea.Cmd {
+		t.Helper()
+		model, cmd := m.Update(msg)
+		m = model.(popupModel)
+		return cmd
+	}
+	update(popupLoadedMsg{configs: configs})
+
+	view := m.View()
+	if !strings.Contains(view, "2 configs, 1 need linking") || !strings.Contains(view, "not linked") {
+		t.Errorf("View() =\n%s", view)
+	}
+	for _, line := range strings.Split(view, "\n") {
+		if w := lipgloss.Width(line); w > PopupWidth {
+			t.Errorf("line %q is %d cells wide, want at most %d", line, w, PopupWidth)
+		}
+	}
+
+	// Link the selected config
+	update(tea.KeyMsg{Type: tea.KeyDown})
+	update(tea.KeyMsg{Type: tea.KeyEnter})
+	if m.busy == "" {
+		t.Fatal("expected the popup to be busy linking")
+	}
+	update(popupSyncedMsg{name: "zsh", err: m.opts.Sync("zsh")})
+	if m.status != "Linked zsh" || m.failed {
+		t.Errorf("status = %q (failed %v), want Linked zsh", m.status, m.failed)
+	}
+
+	// Keys other than quit are ignored while busy
+	update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("a")})
+	if len(synced) != 1 {
+		t.Errorf("synced = %v while busy, want only zsh", synced)
+	}
+
+	update(popupLoadedMsg{configs: configs})
+	update(popupSyncedMsg{err: m.opts.Sync("")})
+	if !m.failed || !strings.Contains(m.status, "all configs: boom") {
+		t.Errorf("status = %q, want the failure of linking all", m.status)
+	}
+}
+
+func TestPopupModel_ScrollsInSmallPane(t *testing.T) {
+	var configs []PopupConfig
+	for _, name := range []string{"a", "b", "c", "d", "e", "f"} {
+		configs = append(configs, PopupConfig{Name: name, Linked: 1, Total: 1})
+	}
+	m := newPopupModel(PopupOptions{})
+	model, _ := m.Update(tea.WindowSizeMsg{Width: 40, Height: 7})
+	model, _ = model.Update(popupLoadedMsg{configs: configs})
+	for i := 0; i < 5; i++ {
+		model, _ = model.Update(tea.KeyMsg{Type: tea.KeyDown})
+	}
+	m = model.(popupModel)
+
+	if m.selected != 5 || m.offset != 3 {
+		t.Errorf("selected = %d, offset = %d; want 5 and 3", m.selected, m.offset)
+	}
+	if lines := strings.Count(m.View(), "\n"); lines != 7 {
+		t.Errorf("View() has %d lines, want the 7 the pane has", lines)
+	}
+}
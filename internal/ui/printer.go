@@ -2,40 +2,65 @@ package ui
 
 import (
 	"fmt"
+	"os"
 
 	"github.com/charmbracelet/lipgloss"
 )
 
-// Success prints a success message (green tick)
+// Success prints a success message (green tick). Like the other detailed
+// output it is suppressed by --summary and --quiet.
 func Success(format string, a ...interface{}) {
-	icon := SuccessStyle.Render("✓")
 	msg := fmt.Sprintf(format, a...)
-	fmt.Printf("%s %s\n", icon, msg)
+	logOutput("success", msg)
+	if !showDetails() {
+		return
+	}
+	icon := SuccessStyle.Render("✓")
+	_, _ = fmt.Fprintf(stdout(), "%s %s\n", icon, msg)
 }
 
-// Error prints an error message (red cross)
+// Error prints an error message (red cross) at every verbosity
 func Error(format string, a ...interface{}) {
 	icon := ErrorStyle.Render("✖")
 	msg := fmt.Sprintf(format, a...)
-	fmt.Printf("%s %s\n", icon, msg)
+	logOutput("error", msg)
+	_, _ = fmt.Fprintf(stdout(), "%s %s\n", icon, msg)
 }
 
-// Warning prints a warning message (yellow triangle)
+// Warning prints a warning message (yellow triangle), except with --quiet
 func Warning(format string, a ...interface{}) {
-	icon := lipgloss.NewStyle().Foreground(WarningColor).Bold(true).Render("⚠")
 	msg := fmt.Sprintf(format, a...)
-	fmt.Printf("%s %s\n", icon, msg)
+	logOutput("warning", msg)
+	if CurrentVerbosity() == VerbosityQuiet {
+		return
+	}
+	icon := lipgloss.NewStyle().Foreground(WarningColor).Bold(true).Render("⚠")
+	_, _ = fmt.Fprintf(stdout(), "%s %s\n", icon, msg)
 }
 
-// Info prints an informational message (blue i)
+// Deprecation prints a deprecation warning to stderr, so scripts reading
+// stdout are not affected, except with --quiet
+func Deprecation(msg string) {
+	if CurrentVerbosity() == VerbosityQuiet {
+		return
+	}
+	icon := lipgloss.NewStyle().Foreground(WarningColor).Bold(true).Render("⚠")
+	_, _ = fmt.Fprintf(os.Stderr, "%s %s\n", icon, msg)
+}
+
+// Info prints an informational message (blue i) at normal verbosity
 func Info(format string, a ...interface{}) {
-	icon := lipgloss.NewStyle().Foreground(PrimaryColor).Bold(true).Render("ℹ")
 	msg := fmt.Sprintf(format, a...)
-	fmt.Printf("%s %s\n", icon, msg)
+	logOutput("info", msg)
+	if !showDetails() {
+		return
+	}
+	icon := lipgloss.NewStyle().Foreground(PrimaryColor).Bold(true).Render("ℹ")
+	_, _ = fmt.Fprintf(stdout(), "%s %s\n", icon, msg)
 }
 
-// Section prints a section header
+// Section prints a section header at normal verbosity
 func Section(title string) {
-	fmt.Println()
-	fmt.Println(TitleStyle.Render(title))
+	Println()
+	Println(TitleStyle.Render(title))
 }
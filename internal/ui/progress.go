@@ -8,7 +8,6 @@ import (
 	"github.com/charmbracelet/bubbles/progress"
 	"github.com/charmbracelet/bubbles/spinner"
 	tea "github.com/charmbracelet/bubbletea"
-	"github.com/charmbracelet/lipgloss"
 )
 
 // ProgressTracker tracks progress through a multi-step operation
@@ -206,9 +205,7 @@ func newProgressBarModel(msg string, updateChan chan progressUpdate, doneChan ch
 		progress.WithWidth(40),
 	)
 
-	s := spinner.New()
-	s.Spinner = spinner.Dot
-	s.Style = lipgloss.NewStyle().Foreground(PrimaryColor)
+	s := NewSpinner()
 
 	return progressBarModel{
 		progress:   p,
@@ -323,7 +320,7 @@ func RunWithProgress(msg string, task func(updateChan chan<- progressUpdate) err
 		doneChan <- err
 	}()
 
-	p := tea.NewProgram(newProgressBarModel(msg, updateChan, doneChan))
+	p := tea.NewProgram(newProgressBarModel(msg, updateChan, doneChan), ProgramOptions(false, false)...)
 	m, err := p.Run()
 	if err != nil {
 		return err
@@ -0,0 +1,226 @@
+package ui
+
+import (
+	"fmt"
+	"html"
+	"strconv"
+	"strings"
+)
+
+// Snapshot formats
+const (
+	SnapshotFormatText = "text"
+	SnapshotFormatHTML = "html"
+)
+
+// ansiPalette holds the 16 basic terminal colors, as xterm draws them
+var ansiPalette = [16]string{
+	"#000000", "#cd0000", "#00cd00", "#cdcd00", "#0000ee", "#cd00cd", "#00cdcd", "#e5e5e5",
+	"#7f7f7f", "#ff0000", "#00ff00", "#ffff00", "#5c5cff", "#ff00ff", "#00ffff", "#ffffff",
+}
+
+// SnapshotText returns a rendered TUI view as plain text: escape sequences
+// removed and trailing padding trimmed from each line
+func SnapshotText(view string) string {
+	lines := strings.Split(stripAnsi(view), "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRight(line, " ")
+	}
+	return strings.TrimRight(strings.Join(lines, "\n"), "\n") + "\n"
+}
+
+// SnapshotHTML converts a rendered TUI view to a standalone HTML page,
+// turning SGR colors and attributes into styled spans so the page looks
+// like the terminal did. Other escape sequences are dropped.
+func SnapshotHTML(view, title string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "<!DOCTYPE html>\n<html>\n<head>\n<meta charset=\"utf-8\">\n<title>%s</title>\n", html.EscapeString(title))
+	b.WriteString("<style>body{background:#1e1e2e;color:#cdd6f4;margin:1em}" +
+		"pre{font-family:ui-monospace,Menlo,Consolas,monospace;font-size:13px;line-height:1.2}</style>\n")
+	b.WriteString("</head>\n<body>\n<pre>")
+
+	var sgr sgrState
+	open := false
+	runes := []rune(view)
+	for i := 0; i < len(runes); {
+		if runes[i] == '\x1b' {
+			seq, next := parseEscapeSeq(runes, i)
+			if next == i {
+				i++
+				continue
+			}
+			i = next
+			if !strings.HasSuffix(seq, "m") {
+				continue
+			}
+			sgr.apply(seq[2 : len(seq)-1])
+			if open {
+				b.WriteString("</span>")
+				open = false
+			}
+			if css := sgr.css(); css != "" {
+				fmt.Fprintf(&b, "<span style=\"%s\">", css)
+				open = true
+			}
+			continue
+		}
+		b.WriteString(html.EscapeString(string(runes[i])))
+		i++
+	}
+	if open {
+		b.WriteString("</span>")
+	}
+
+	b.WriteString("</pre>\n</body>\n</html>\n")
+	return b.String()
+}
+
+// sgrState is the text style built up by SGR sequences
+type sgrState struct {
+	fg, bg                         string
+	bold, faint, italic, underline bool
+	reverse, strike                bool
+}
+
+// apply updates the state with the parameters of one SGR sequence
+func (s *sgrState) apply(params string) {
+	if params == "" {
+		*s = sgrState{}
+		return
+	}
+	codes := strings.Split(params, ";")
+	for i := 0; i < len(codes); i++ {
+		n, err := strconv.Atoi(codes[i])
+		if err != nil {
+			continue
+		}
+		switch {
+		case n == 0:
+			*s = sgrState{}
+		case n == 1:
+			s.bold = true
+		case n == 2:
+			s.faint = true
+		case n == 3:
+			s.italic = true
+		case n == 4:
+			s.underline = true
+		case n == 7:
+			s.reverse = true
+		case n == 9:
+			s.strike = true
+		case n == 22:
+			s.bold, s.faint = false, false
+		case n == 23:
+			s.italic = false
+		case n == 24:
+			s.underline = false
+		case n == 27:
+			s.reverse = false
+		case n == 29:
+			s.strike = false
+		case n >= 30 && n <= 37:
+			s.fg = ansiPalette[n-30]
+		case n >= 90 && n <= 97:
+			s.fg = ansiPalette[n-90+8]
+		case n == 39:
+			s.fg = ""
+		case n >= 40 && n <= 47:
+			s.bg = ansiPalette[n-40]
+		case n >= 100 && n <= 107:
+			s.bg = ansiPalette[n-100+8]
+		case n == 49:
+			s.bg = ""
+		case n == 38 || n == 48:
+			color, used := extendedColor(codes[i+1:])
+			i += used
+			if n == 38 {
+				s.fg = color
+			} else {
+				s.bg = color
+			}
+		}
+	}
+}
+
+// extendedColor reads a 256-color (5;N) or true color (2;R;G;B) parameter
+// list, returning the CSS color and how many parameters it used
+func extendedColor(codes []string) (string, int) {
+	nums := make([]int, 0, 4)
+	for _, c := range codes {
+		n, err := strconv.Atoi(c)
+		if err != nil {
+			break
+		}
+		nums = append(nums, n)
+	}
+	switch {
+	case len(nums) >= 2 && nums[0] == 5:
+		return color256(nums[1]), 2
+	case len(nums) >= 4 && nums[0] == 2:
+		return fmt.Sprintf("#%02x%02x%02x", nums[1]&0xff, nums[2]&0xff, nums[3]&0xff), 4
+	}
+	return "", len(nums)
+}
+
+// color256 maps an xterm 256-color index to a CSS color
+func color256(n int) string {
+	switch {
+	case n < 0 || n > 255:
+		return ""
+	case n < 16:
+		return ansiPalette[n]
+	case n < 232:
+		n -= 16
+		level := func(v int) int {
+			if v == 0 {
+				return 0
+			}
+			return 55 + v*40
+		}
+		return fmt.Sprintf("#%02x%02x%02x", level(n/36), level(n/6%6), level(n%6))
+	default:
+		v := 8 + (n-232)*10
+		return fmt.Sprintf("#%02x%02x%02x", v, v, v)
+	}
+}
+
+// css returns the inline style for the state, or "" for the default look
+func (s sgrState) css() string {
+	fg, bg := s.fg, s.bg
+	if s.reverse {
+		fg, bg = bg, fg
+		if fg == "" {
+			fg = "#1e1e2e"
+		}
+		if bg == "" {
+			bg = "#cdd6f4"
+		}
+	}
+
+	var props []string
+	if fg != "" {
+		props = append(props, "color:"+fg)
+	}
+	if bg != "" {
+		props = append(props, "background:"+bg)
+	}
+	if s.bold {
+		props = append(props, "font-weight:bold")
+	}
+	if s.faint {
+		props = append(props, "opacity:0.6")
+	}
+	if s.italic {
+		props = append(props, "font-style:italic")
+	}
+	switch {
+	case s.underline && s.strike:
+		props = append(props, "text-decoration:underline line-through")
+	case s.underline:
+		props = append(props, "text-decoration:underline")
+	case s.strike:
+		props = append(props, "text-decoration:line-through")
+	}
+	return strings.Join(props, ";")
+}
@@ -0,0 +1,58 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSnapshotText(t *testing.T) {
+	view := "\x1b[1;32m✓ linked\x1b[0m   \n\x1b[2Aplain  \n\n"
+	want := "✓ linked\nplain\n"
+	if got := SnapshotText(view); got != want {
+		t.Errorf("SnapshotText() = %q, want %q", got, want)
+	}
+}
+
+func TestSnapshotHTML(t *testing.T) {
+	view := "\x1b[1;38;2;166;227;161mok\x1b[0m <b>&\n\x1b[31;48;5;21mred\x1b[39mbg\x1b[0m"
+	got := SnapshotHTML(view, "<dash>")
+
+	for _, want := range []string{
+		"<title>&lt;dash&gt;</title>",
+		`<span style="color:#a6e3a1;font-weight:bold">ok</span>`,
+		" &lt;b&gt;&amp;\n",
+		`<span style="color:#cd0000;background:#0000ff">red</span>`,
+		`<span style="background:#0000ff">bg</span>`,
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("SnapshotHTML() missing %q in:\n%s", want, got)
+		}
+	}
+	if strings.Contains(got, "\x1b") {
+		t.Error("SnapshotHTML() left escape sequences in the page")
+	}
+	if strings.Count(got, "<span") != strings.Count(got, "</span>") {
+		t.Error("SnapshotHTML() left a span open")
+	}
+}
+
+func TestSGRStateReverse(t *testing.T) {
+	var s sgrState
+	s.apply("7;33")
+	if got := s.css(); got != "color:#1e1e2e;background:#cdcd00" {
+		t.Errorf("css() = %q", got)
+	}
+	s.apply("27")
+	if got := s.css(); got != "color:#cdcd00" {
+		t.Errorf("css() after 27 = %q", got)
+	}
+}
+
+func TestColor256(t *testing.T) {
+	tests := map[int]string{1: "#cd0000", 16: "#000000", 21: "#0000ff", 196: "#ff0000", 232: "#080808", 255: "#eeeeee", 300: ""}
+	for n, want := range tests {
+		if got := color256(n); got != want {
+			t.Errorf("color256(%d) = %q, want %q", n, got, want)
+		}
+	}
+}
@@ -5,7 +5,6 @@ import (
 
 	"github.com/charmbracelet/bubbles/spinner"
 	tea "github.com/charmbracelet/bubbletea"
-	"github.com/charmbracelet/lipgloss"
 )
 
 type errMsg error
@@ -19,9 +18,7 @@ type spinnerModel struct {
 }
 
 func initialSpinnerModel(msg string, action func() error) spinnerModel {
-	s := spinner.New()
-	s.Spinner = spinner.Dot
-	s.Style = lipgloss.NewStyle().Foreground(PrimaryColor)
+	s := NewSpinner()
 	return spinnerModel{
 		spinner: s,
 		message: msg,
@@ -74,7 +71,7 @@ func (m spinnerModel) View() string {
 
 // RunSpinner runs a task with a spinner
 func RunSpinner(msg string, action func() error) error {
-	p := tea.NewProgram(initialSpinnerModel(msg, action))
+	p := tea.NewProgram(initialSpinnerModel(msg, action), ProgramOptions(false, false)...)
 	m, err := p.Run()
 	if err != nil {
 		return err
@@ -12,48 +12,70 @@ var (
 	WarningColor   = lipgloss.Color("#f9e2af") // Yellow (Catppuccin Mocha)
 	SubtleColor    = lipgloss.Color("#9399b2") // Overlay2 (Catppuccin Mocha)
 	TextColor      = lipgloss.Color("#cdd6f4") // Text (Catppuccin Mocha)
+)
+
+// Shared styles. They are derived from the colors above and rebuilt by
+// SetTheme, so always reference them at render time rather than copying.
+var (
+	TitleStyle        lipgloss.Style
+	TextStyle         lipgloss.Style
+	SubtleStyle       lipgloss.Style
+	ErrorStyle        lipgloss.Style
+	SuccessStyle      lipgloss.Style
+	WarningStyle      lipgloss.Style
+	BoxStyle          lipgloss.Style
+	ItemStyle         lipgloss.Style
+	SelectedItemStyle lipgloss.Style
+	HeaderStyle       lipgloss.Style
+)
+
+func init() {
+	buildStyles()
+}
 
+// buildStyles (re)creates the shared styles from the current colors
+func buildStyles() {
 	// Text Styles
 	TitleStyle = lipgloss.NewStyle().
-			Foreground(PrimaryColor).
-			Bold(true).
-			MarginBottom(1)
+		Foreground(PrimaryColor).
+		Bold(true).
+		MarginBottom(1)
 
 	TextStyle = lipgloss.NewStyle().
-			Foreground(TextColor)
+		Foreground(TextColor)
 
 	SubtleStyle = lipgloss.NewStyle().
-			Foreground(SubtleColor)
+		Foreground(SubtleColor)
 
 	ErrorStyle = lipgloss.NewStyle().
-			Foreground(ErrorColor).
-			Bold(true)
+		Foreground(ErrorColor).
+		Bold(true)
 
 	SuccessStyle = lipgloss.NewStyle().
-			Foreground(SecondaryColor).
-			Bold(true)
+		Foreground(SecondaryColor).
+		Bold(true)
 
 	WarningStyle = lipgloss.NewStyle().
-			Foreground(WarningColor).
-			Bold(true)
+		Foreground(WarningColor).
+		Bold(true)
 
 	// Box Styles
 	BoxStyle = lipgloss.NewStyle().
-			Border(lipgloss.RoundedBorder()).
-			BorderForeground(PrimaryColor).
-			Padding(1, 2)
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(PrimaryColor).
+		Padding(1, 2)
 
 	// List Styles
 	ItemStyle = lipgloss.NewStyle().
-			PaddingLeft(2)
+		PaddingLeft(2)
 
 	SelectedItemStyle = lipgloss.NewStyle().
-				Foreground(TextColor).
-				Background(PrimaryColor).
-				Bold(true)
+		Foreground(TextColor).
+		Background(PrimaryColor).
+		Bold(true)
 
 	HeaderStyle = lipgloss.NewStyle().
-			Foreground(PrimaryColor).
-			Bold(true).
-			Underline(true)
-)
+		Foreground(PrimaryColor).
+		Bold(true).
+		Underline(true)
+}
@@ -76,3 +76,21 @@ func TestStylesWithEmptyContent(t *testing.T) {
 		})
 	}
 }
+
+func TestSetTheme(t *testing.T) {
+	defer func() { _ = SetTheme("mocha") }()
+
+	if err := SetTheme("latte"); err != nil {
+		t.Fatalf("SetTheme(latte) error = %v", err)
+	}
+	if PrimaryColor != lipgloss.Color("#7287fd") {
+		t.Errorf("PrimaryColor = %q, want latte lavender", PrimaryColor)
+	}
+	if TitleStyle.GetForeground() != PrimaryColor {
+		t.Error("TitleStyle should be rebuilt with the new primary color")
+	}
+
+	if err := SetTheme("unknown"); err == nil {
+		t.Error("SetTheme(unknown) should return an error")
+	}
+}
@@ -0,0 +1,59 @@
+package ui
+
+import (
+	"fmt"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// palette is the set of colors a theme provides
+type palette struct {
+	primary   lipgloss.Color
+	secondary lipgloss.Color
+	err       lipgloss.Color
+	warning   lipgloss.Color
+	subtle    lipgloss.Color
+	text      lipgloss.Color
+}
+
+var themes = map[string]palette{
+	// Catppuccin Mocha (default, dark)
+	"mocha": {
+		primary:   "#b4befe",
+		secondary: "#a6e3a1",
+		err:       "#f38ba8",
+		warning:   "#f9e2af",
+		subtle:    "#9399b2",
+		text:      "#cdd6f4",
+	},
+	// Catppuccin Latte (light)
+	"latte": {
+		primary:   "#7287fd",
+		secondary: "#40a02b",
+		err:       "#d20f39",
+		warning:   "#df8e1d",
+		subtle:    "#7c7f93",
+		text:      "#4c4f69",
+	},
+	// No colors, only bold/underline attributes
+	"mono": {},
+}
+
+// SetTheme switches the shared colors and styles to the named theme.
+// It should be called once at startup, before any UI is rendered.
+func SetTheme(name string) error {
+	p, ok := themes[name]
+	if !ok {
+		return fmt.Errorf("unknown theme: %s", name)
+	}
+
+	PrimaryColor = p.primary
+	SecondaryColor = p.secondary
+	ErrorColor = p.err
+	WarningColor = p.warning
+	SubtleColor = p.subtle
+	TextColor = p.text
+
+	buildStyles()
+	return nil
+}
@@ -0,0 +1,57 @@
+package validation
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func FuzzValidateGitURL(f *testing.F) {
+	for _, tt := range gitURLTests {
+		f.Add(tt.input)
+	}
+
+	f.Fuzz(func(t *testing.T, url string) {
+		if ValidateGitURL(url) != nil {
+			return
+		}
+
+		// Anything accepted is passed to git clone as an argument
+		if strings.HasPrefix(url, "-") {
+			t.Errorf("accepted flag-like URL %q", url)
+		}
+		if strings.ContainsAny(url, "\n\r\t\x00 ;|&$`<>\\\"'") {
+			t.Errorf("accepted URL with whitespace or shell metacharacters %q", url)
+		}
+		// This also rules out file:// and remote helpers such as ext::
+		if !strings.HasPrefix(url, "https://") && !strings.HasPrefix(url, "git@") {
+			t.Errorf("accepted URL with unexpected scheme %q", url)
+		}
+	})
+}
+
+func FuzzValidateDestinationPath(f *testing.F) {
+	for _, tt := range destinationPathTests {
+		f.Add(tt.expanded, tt.baseDir)
+	}
+
+	f.Fuzz(func(t *testing.T, expanded, baseDir string) {
+		if ValidateDestinationPath(expanded, baseDir) != nil {
+			return
+		}
+
+		// An accepted path must be the base directory or inside it
+		base := filepath.Clean(baseDir)
+		cleaned := filepath.Clean(expanded)
+		if cleaned == base {
+			return
+		}
+		prefix := base
+		if !strings.HasSuffix(prefix, string(filepath.Separator)) {
+			prefix += string(filepath.Separator)
+		}
+		if !strings.HasPrefix(cleaned, prefix) {
+			t.Errorf("accepted %q, which is outside %q", expanded, baseDir)
+		}
+	})
+}
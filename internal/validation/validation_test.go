@@ -112,55 +112,56 @@ func TestValidateVersionCmd(t *testing.T) {
 	}
 }
 
-func TestValidateGitURL(t *testing.T) {
-	tests := []struct {
-		name    string
-		input   string
-		wantErr bool
-	}{
-		// Valid HTTPS URLs
-		{name: "https github", input: "https://github.com/user/repo.git", wantErr: false},
-		{name: "https gitlab", input: "https://gitlab.com/user/repo.git", wantErr: false},
-		{name: "https without .git", input: "https://github.com/user/repo", wantErr: false},
-		{name: "https with path", input: "https://example.com/deep/path/repo.git", wantErr: false},
-
-		// Valid SSH URLs
-		{name: "ssh github", input: "git@github.com:user/repo.git", wantErr: false},
-		{name: "ssh gitlab", input: "git@gitlab.com:user/repo.git", wantErr: false},
-		{name: "ssh custom host", input: "git@my-server.example.com:org/repo.git", wantErr: false},
-
-		// Empty string
-		{name: "empty string", input: "", wantErr: true},
-
-		// Flag injection
-		{name: "starts with hyphen", input: "-victim", wantErr: true},
-		{name: "starts with double hyphen", input: "--upload-pack=evil", wantErr: true},
-		{name: "flag injection upload-pack", input: "--upload-pack=malicious", wantErr: true},
-
-		// file:// scheme
-		{name: "file scheme", input: "file:///etc/passwd", wantErr: true},
-		{name: "file scheme uppercase", input: "FILE:///etc/passwd", wantErr: true},
-		{name: "file scheme mixed case", input: "File:///etc/passwd", wantErr: true},
-
-		// Invalid formats
-		{name: "http not https", input: "http://github.com/user/repo.git", wantErr: true},
-		{name: "ftp scheme", input: "ftp://github.com/user/repo.git", wantErr: true},
-		{name: "just a path", input: "/home/user/repo", wantErr: true},
-		{name: "relative path", input: "../evil/repo", wantErr: true},
-		{name: "plain text", input: "not a url at all", wantErr: true},
-
-		// Security-focused
-		{name: "injection in ssh", input: "git@$(whoami):user/repo.git", wantErr: true},
-		{name: "newline injection", input: "https://github.com/user/repo\n--upload-pack=evil", wantErr: true},
-
-		// Shell metacharacters in URL body
-		{name: "space in https url", input: "https://evil.com/repo --upload-pack=evil", wantErr: true},
-		{name: "semicolon in https url", input: "https://evil.com/repo;rm -rf /", wantErr: true},
-		{name: "pipe in https url", input: "https://evil.com/repo|cat /etc/passwd", wantErr: true},
-		{name: "backtick in https url", input: "https://evil.com/`whoami`/repo", wantErr: true},
-	}
+// gitURLTests also seeds FuzzValidateGitURL
+var gitURLTests = []struct {
+	name    string
+	input   string
+	wantErr bool
+}{
+	// Valid HTTPS URLs
+	{name: "https github", input: "https://github.com/user/repo.git", wantErr: false},
+	{name: "https gitlab", input: "https://gitlab.com/user/repo.git", wantErr: false},
+	{name: "https without .git", input: "https://github.com/user/repo", wantErr: false},
+	{name: "https with path", input: "https://example.com/deep/path/repo.git", wantErr: false},
+
+	// Valid SSH URLs
+	{name: "ssh github", input: "git@github.com:user/repo.git", wantErr: false},
+	{name: "ssh gitlab", input: "git@gitlab.com:user/repo.git", wantErr: false},
+	{name: "ssh custom host", input: "git@my-server.example.com:org/repo.git", wantErr: false},
+
+	// Empty string
+	{name: "empty string", input: "", wantErr: true},
+
+	// Flag injection
+	{name: "starts with hyphen", input: "-victim", wantErr: true},
+	{name: "starts with double hyphen", input: "--upload-pack=evil", wantErr: true},
+	{name: "flag injection upload-pack", input: "--upload-pack=malicious", wantErr: true},
+
+	// file:// scheme
+	{name: "file scheme", input: "file:///etc/passwd", wantErr: true},
+	{name: "file scheme uppercase", input: "FILE:///etc/passwd", wantErr: true},
+	{name: "file scheme mixed case", input: "File:///etc/passwd", wantErr: true},
+
+	// Invalid formats
+	{name: "http not https", input: "http://github.com/user/repo.git", wantErr: true},
+	{name: "ftp scheme", input: "ftp://github.com/user/repo.git", wantErr: true},
+	{name: "just a path", input: "/home/user/repo", wantErr: true},
+	{name: "relative path", input: "../evil/repo", wantErr: true},
+	{name: "plain text", input: "not a url at all", wantErr: true},
+
+	// Security-focused
+	{name: "injection in ssh", input: "git@$(whoami):user/repo.git", wantErr: true},
+	{name: "newline injection", input: "https://github.com/user/repo\n--upload-pack=evil", wantErr: true},
+
+	// Shell metacharacters in URL body
+	{name: "space in https url", input: "https://evil.com/repo --upload-pack=evil", wantErr: true},
+	{name: "semicolon in https url", input: "https://evil.com/repo;rm -rf /", wantErr: true},
+	{name: "pipe in https url", input: "https://evil.com/repo|cat /etc/passwd", wantErr: true},
+	{name: "backtick in https url", input: "https://evil.com/`whoami`/repo", wantErr: true},
+}
 
-	for _, tt := range tests {
+func TestValidateGitURL(t *testing.T) {
+	for _, tt := range gitURLTests {
 		t.Run(tt.name, func(t *testing.T) {
 			err := ValidateGitURL(tt.input)
 			if (err != nil) != tt.wantErr {
@@ -278,47 +279,48 @@ func TestValidateConfigName(t *testing.T) {
 	}
 }
 
-func TestValidateDestinationPath(t *testing.T) {
-	tests := []struct {
-		name     string
-		expanded string
-		baseDir  string
-		wantErr  bool
-	}{
-		// Valid paths
-		{name: "subdir of base", expanded: "/home/user/.config/vim", baseDir: "/home/user", wantErr: false},
-		{name: "exact base dir", expanded: "/home/user", baseDir: "/home/user", wantErr: false},
-		{name: "deep subdir", expanded: "/home/user/.config/nvim/init.vim", baseDir: "/home/user", wantErr: false},
-		{name: "with dots in name", expanded: "/home/user/.dotfiles", baseDir: "/home/user", wantErr: false},
-		{name: "normalized path", expanded: "/home/user/./config", baseDir: "/home/user", wantErr: false},
-
-		// Empty inputs
-		{name: "empty expanded", expanded: "", baseDir: "/home/user", wantErr: true},
-		{name: "empty base dir", expanded: "/home/user/.config", baseDir: "", wantErr: true},
-		{name: "both empty", expanded: "", baseDir: "", wantErr: true},
-
-		// Relative path inputs (must be absolute)
-		{name: "relative expanded path", expanded: "config/vim", baseDir: "/home/user", wantErr: true},
-		{name: "relative base dir", expanded: "/home/user/config", baseDir: "home/user", wantErr: true},
-
-		// Path traversal attacks
-		{name: "parent traversal", expanded: "/home/user/../../etc/shadow", baseDir: "/home/user", wantErr: true},
-		{name: "escape to root", expanded: "/etc/passwd", baseDir: "/home/user", wantErr: true},
-		{name: "escape with dotdot", expanded: "/home/user/../../../tmp/evil", baseDir: "/home/user", wantErr: true},
-		{name: "sibling directory", expanded: "/home/other/config", baseDir: "/home/user", wantErr: true},
-		{name: "escape to system dir", expanded: "/usr/bin/evil", baseDir: "/home/user", wantErr: true},
-
-		// Dotdot-prefixed directory name (should not false-positive)
-		{name: "dotdot-prefixed dir name is allowed", expanded: "/base/..foo", baseDir: "/base", wantErr: false},
-
-		// Security-focused: real attack patterns
-		{name: "shadow file attack", expanded: "/home/user/../../etc/shadow", baseDir: "/home/user", wantErr: true},
-		{name: "crontab injection", expanded: "/var/spool/cron/root", baseDir: "/home/user", wantErr: true},
-		{name: "ssh key overwrite", expanded: "/root/.ssh/authorized_keys", baseDir: "/home/user", wantErr: true},
-		{name: "systemd service injection", expanded: "/etc/systemd/system/evil.service", baseDir: "/home/user", wantErr: true},
-	}
+// destinationPathTests also seeds FuzzValidateDestinationPath
+var destinationPathTests = []struct {
+	name     string
+	expanded string
+	baseDir  string
+	wantErr  bool
+}{
+	// Valid paths
+	{name: "subdir of base", expanded: "/home/user/.config/vim", baseDir: "/home/user", wantErr: false},
+	{name: "exact base dir", expanded: "/home/user", baseDir: "/home/user", wantErr: false},
+	{name: "deep subdir", expanded: "/home/user/.config/nvim/init.vim", baseDir: "/home/user", wantErr: false},
+	{name: "with dots in name", expanded: "/home/user/.dotfiles", baseDir: "/home/user", wantErr: false},
+	{name: "normalized path", expanded: "/home/user/./config", baseDir: "/home/user", wantErr: false},
+
+	// Empty inputs
+	{name: "empty expanded", expanded: "", baseDir: "/home/user", wantErr: true},
+	{name: "empty base dir", expanded: "/home/user/.config", baseDir: "", wantErr: true},
+	{name: "both empty", expanded: "", baseDir: "", wantErr: true},
+
+	// Relative path inputs (must be absolute)
+	{name: "relative expanded path", expanded: "config/vim", baseDir: "/home/user", wantErr: true},
+	{name: "relative base dir", expanded: "/home/user/config", baseDir: "home/user", wantErr: true},
+
+	// Path traversal attacks
+	{name: "parent traversal", expanded: "/home/user/../../etc/shadow", baseDir: "/home/user", wantErr: true},
+	{name: "escape to root", expanded: "/etc/passwd", baseDir: "/home/user", wantErr: true},
+	{name: "escape with dotdot", expanded: "/home/user/../../../tmp/evil", baseDir: "/home/user", wantErr: true},
+	{name: "sibling directory", expanded: "/home/other/config", baseDir: "/home/user", wantErr: true},
+	{name: "escape to system dir", expanded: "/usr/bin/evil", baseDir: "/home/user", wantErr: true},
+
+	// Dotdot-prefixed directory name (should not false-positive)
+	{name: "dotdot-prefixed dir name is allowed", expanded: "/base/..foo", baseDir: "/base", wantErr: false},
+
+	// Security-focused: real attack patterns
+	{name: "shadow file attack", expanded: "/home/user/../../etc/shadow", baseDir: "/home/user", wantErr: true},
+	{name: "crontab injection", expanded: "/var/spool/cron/root", baseDir: "/home/user", wantErr: true},
+	{name: "ssh key overwrite", expanded: "/root/.ssh/authorized_keys", baseDir: "/home/user", wantErr: true},
+	{name: "systemd service injection", expanded: "/etc/systemd/system/evil.service", baseDir: "/home/user", wantErr: true},
+}
 
-	for _, tt := range tests {
+func TestValidateDestinationPath(t *testing.T) {
+	for _, tt := range destinationPathTests {
 		t.Run(tt.name, func(t *testing.T) {
 			err := ValidateDestinationPath(tt.expanded, tt.baseDir)
 			if (err != nil) != tt.wantErr {
@@ -0,0 +1,94 @@
+package vcs
+
+import (
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// Git is the git backend, and the one go4dot has always assumed
+type Git struct{}
+
+// gitMarkers maps files git leaves in its directory during an interrupted
+// operation to the operation's name
+var gitMarkers = []marker{
+	{"rebase-merge", "rebase"},
+	{"rebase-apply", "rebase"},
+	{"MERGE_HEAD", "merge"},
+	{"CHERRY_PICK_HEAD", "cherry-pick"},
+	{"REVERT_HEAD", "revert"},
+}
+
+func (Git) Name() string { return "git" }
+
+func (Git) Root(dir string) (string, error) {
+	return run(dir, nil, "git", "rev-parse", "--show-toplevel")
+}
+
+func (Git) Status(dir string) (*Status, error) {
+	gitDir, err := run(dir, nil, "git", "rev-parse", "--absolute-git-dir")
+	if err != nil {
+		return nil, err
+	}
+	status := &Status{
+		Operation: firstMarker(gitDir, gitMarkers),
+		Locked:    exists(gitDir, "index.lock"),
+	}
+
+	out, err := run(dir, nil, "git", "status", "--porcelain", "--untracked-files=normal", "--", ".")
+	if err != nil {
+		return nil, err
+	}
+	for _, line := range strings.Split(out, "\n") {
+		if len(line) < 4 {
+			continue
+		}
+		status.add(line, line[3:])
+	}
+	return status, nil
+}
+
+func (Git) Head(dir string) (string, error) {
+	return run(dir, nil, "git", "rev-parse", "HEAD")
+}
+
+func (Git) Changed(dir, from, to, path string) (bool, error) {
+	out, err := run(dir, nil, "git", "diff", "--name-only", from, to, "--", filepath.ToSlash(path))
+	return out != "", err
+}
+
+func (Git) File(dir, rev, path string) (string, error) {
+	return run(dir, nil, "git", "show", rev+":./"+filepath.ToSlash(path))
+}
+
+func (Git) Pull(dir string) error {
+	return runCombined(dir, nil, "git", "pull", "--rebase")
+}
+
+func (Git) Push(dir string) error {
+	return runCombined(dir, nil, "git", "push")
+}
+
+// Staged returns the files added, copied, modified or renamed in git's
+// index under dir, relative to dir
+func (Git) Staged(dir string) ([]string, error) {
+	out, err := run(dir, nil, "git", "diff", "--cached", "--name-only", "--diff-filter=ACMR", "--relative", "-z", "--", ".")
+	if err != nil {
+		return nil, err
+	}
+	var paths []string
+	for _, p := range strings.Split(out, "\x00") {
+		if p != "" {
+			paths = append(paths, filepath.FromSlash(p))
+		}
+	}
+	return paths, nil
+}
+
+// StagedFile returns the contents of path, relative to dir, as staged in
+// git's index
+func (Git) StagedFile(dir, path string) ([]byte, error) {
+	cmd := exec.Command("git", "show", ":./"+filepath.ToSlash(path))
+	cmd.Dir = dir
+	return cmd.Output()
+}
@@ -0,0 +1,68 @@
+package vcs
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/nvandessel/go4dot/internal/snapshot"
+)
+
+// gitHookMarker is a line in every hook go4dot writes, so a reinstall can
+// tell its own hooks from the user's
+const gitHookMarker = "# Installed by go4dot"
+
+// ErrForeignHook is returned when a hook go4dot did not write is in the way
+var ErrForeignHook = errors.New("a hook not installed by go4dot already exists")
+
+// GitHookPath returns where git looks for the named hook of the repo
+// containing dir, honoring core.hooksPath and worktrees
+func GitHookPath(dir, name string) (string, error) {
+	path, err := run(dir, nil, "git", "rev-parse", "--git-path", "hooks/"+name)
+	if err != nil {
+		return "", fmt.Errorf("not a git repository: %s", dir)
+	}
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(dir, path)
+	}
+	return filepath.Clean(path), nil
+}
+
+// InstallGitHook writes script as the named hook of the repo containing
+// dir and returns its path. A hook go4dot wrote before is replaced; any
+// other hook only when force is set, after it is snapshotted for g4d undo.
+func InstallGitHook(dir, name, script string, force bool) (string, error) {
+	path, err := GitHookPath(dir, name)
+	if err != nil {
+		return "", err
+	}
+
+	content := []byte("#!/bin/sh\n" + gitHookMarker + "\n" + script)
+	if existing, err := os.ReadFile(path); err == nil {
+		if bytes.Equal(existing, content) {
+			return path, nil
+		}
+		if !bytes.Contains(existing, []byte(gitHookMarker)) {
+			if !force {
+				return path, fmt.Errorf("%w: %s", ErrForeignHook, path)
+			}
+			if err := snapshot.Save(path); err != nil {
+				return path, err
+			}
+		}
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return path, fmt.Errorf("failed to create hooks directory: %w", err)
+	}
+	if err := os.WriteFile(path, content, 0755); err != nil {
+		return path, fmt.Errorf("failed to write hook: %w", err)
+	}
+	// WriteFile keeps the mode of an existing file
+	if err := os.Chmod(path, 0755); err != nil {
+		return path, fmt.Errorf("failed to make hook executable: %w", err)
+	}
+	return path, nil
+}
@@ -0,0 +1,91 @@
+package vcs
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestGit_Staged(t *testing.T) {
+	dir := initRepo(t)
+	writeFile(t, filepath.Join(dir, "vim", ".vimrc"), "set nonumber\n")
+	writeFile(t, filepath.Join(dir, "zsh", ".zshrc"), "export EDITOR=vim\n")
+	writeFile(t, filepath.Join(dir, "tmux", ".tmux.conf"), "set -g mouse on\n")
+	runGit(t, dir, "add", "vim", "zsh")
+	writeFile(t, filepath.Join(dir, "zsh", ".zshrc"), "export EDITOR=nvim\n")
+
+	var g Git
+	staged, err := g.Staged(dir)
+	if err != nil {
+		t.Fatalf("Staged failed: %v", err)
+	}
+	want := []string{filepath.Join("vim", ".vimrc"), filepath.Join("zsh", ".zshrc")}
+	if !reflect.DeepEqual(staged, want) {
+		t.Errorf("Staged() = %v, want %v", staged, want)
+	}
+
+	// Paths are relative to the directory asked about
+	staged, _ = g.Staged(filepath.Join(dir, "zsh"))
+	if !reflect.DeepEqual(staged, []string{".zshrc"}) {
+		t.Errorf("Staged(zsh) = %v", staged)
+	}
+
+	// The staged contents, not the working tree's
+	data, err := g.StagedFile(dir, filepath.Join("zsh", ".zshrc"))
+	if err != nil || string(data) != "export EDITOR=vim\n" {
+		t.Errorf("StagedFile() = %q, %v", data, err)
+	}
+}
+
+func TestInstallGitHook(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	dir := initRepo(t)
+
+	path, err := InstallGitHook(dir, "pre-commit", "exit 0\n", false)
+	if err != nil {
+		t.Fatalf("InstallGitHook failed: %v", err)
+	}
+	if want := filepath.Join(dir, ".git", "hooks", "pre-commit"); path != want {
+		t.Errorf("path = %s, want %s", path, want)
+	}
+	info, err := os.Stat(path)
+	if err != nil || info.Mode().Perm()&0100 == 0 {
+		t.Fatalf("hook not executable: %v %v", info, err)
+	}
+
+	// Our own hook is replaced
+	if _, err := InstallGitHook(dir, "pre-commit", "exit 1\n", false); err != nil {
+		t.Fatalf("reinstall failed: %v", err)
+	}
+	if data, _ := os.ReadFile(path); !strings.HasSuffix(string(data), "exit 1\n") {
+		t.Errorf("hook = %q", data)
+	}
+
+	// Someone else's only with force
+	writeFile(t, path, "#!/bin/sh\nmake lint\n")
+	if _, err := InstallGitHook(dir, "pre-commit", "exit 0\n", false); !errors.Is(err, ErrForeignHook) {
+		t.Fatalf("err = %v, want ErrForeignHook", err)
+	}
+	if data, _ := os.ReadFile(path); string(data) != "#!/bin/sh\nmake lint\n" {
+		t.Errorf("foreign hook was changed: %q", data)
+	}
+	if _, err := InstallGitHook(dir, "pre-commit", "exit 0\n", true); err != nil {
+		t.Fatalf("forced install failed: %v", err)
+	}
+}
+
+func TestGitHookPath_HooksPath(t *testing.T) {
+	dir := initRepo(t)
+	runGit(t, dir, "config", "core.hooksPath", ".githooks")
+
+	path, err := GitHookPath(filepath.Join(dir, "vim"), "pre-commit")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := filepath.Join(dir, ".githooks", "pre-commit"); path != want {
+		t.Errorf("path = %s, want %s", path, want)
+	}
+}
@@ -0,0 +1,76 @@
+package vcs
+
+import (
+	"path/filepath"
+	"strings"
+)
+
+// Mercurial is the hg backend
+type Mercurial struct{}
+
+// hgEnv keeps user configuration such as aliases, relative paths and
+// color out of output that is parsed
+var hgEnv = []string{"HGPLAIN=1"}
+
+// hgMarkers maps files hg leaves in .hg during an interrupted operation to
+// the operation's name
+var hgMarkers = []marker{
+	{"rebasestate", "rebase"},
+	{"histedit-state", "histedit"},
+	{"merge/state", "merge"},
+	{"merge/state2", "merge"},
+	{"graftstate", "graft"},
+	{"shelvedstate", "unshelve"},
+}
+
+func (Mercurial) Name() string { return "hg" }
+
+func (Mercurial) Root(dir string) (string, error) {
+	return run(dir, hgEnv, "hg", "root")
+}
+
+func (h Mercurial) Status(dir string) (*Status, error) {
+	root, err := h.Root(dir)
+	if err != nil {
+		return nil, err
+	}
+	hgDir := filepath.Join(root, ".hg")
+	status := &Status{
+		Operation: firstMarker(hgDir, hgMarkers),
+		Locked:    exists(hgDir, "wlock", "store/lock"),
+	}
+
+	// Lines are "M path", with paths relative to the root under HGPLAIN
+	out, err := run(dir, hgEnv, "hg", "status", "--", ".")
+	if err != nil {
+		return nil, err
+	}
+	for _, line := range strings.Split(out, "\n") {
+		if len(line) < 3 {
+			continue
+		}
+		status.add(line, line[2:])
+	}
+	return status, nil
+}
+
+func (Mercurial) Head(dir string) (string, error) {
+	return run(dir, hgEnv, "hg", "log", "-r", ".", "-T", "{node}")
+}
+
+func (Mercurial) Changed(dir, from, to, path string) (bool, error) {
+	out, err := run(dir, hgEnv, "hg", "status", "--rev", from, "--rev", to, "--", path)
+	return out != "", err
+}
+
+func (Mercurial) File(dir, rev, path string) (string, error) {
+	return run(dir, hgEnv, "hg", "cat", "-r", rev, "--", path)
+}
+
+func (Mercurial) Pull(dir string) error {
+	return runCombined(dir, hgEnv, "hg", "pull", "--update")
+}
+
+func (Mercurial) Push(dir string) error {
+	return runCombined(dir, hgEnv, "hg", "push")
+}
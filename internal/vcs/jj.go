@@ -0,0 +1,78 @@
+package vcs
+
+import (
+	"path/filepath"
+	"strings"
+)
+
+// Jujutsu is the jj backend. In jj the working copy is itself a commit,
+// so its uncommitted changes are those of @ over its parent. jj records
+// conflicts in commits instead of stopping mid-operation, so no operation
+// is ever reported in progress.
+type Jujutsu struct{}
+
+func (Jujutsu) Name() string { return "jj" }
+
+func (Jujutsu) Root(dir string) (string, error) {
+	return run(dir, nil, "jj", "root")
+}
+
+func (j Jujutsu) Status(dir string) (*Status, error) {
+	root, err := j.Root(dir)
+	if err != nil {
+		return nil, err
+	}
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+	if real, err := filepath.EvalSymlinks(abs); err == nil {
+		abs = real
+	}
+	rel, err := filepath.Rel(root, abs)
+	if err != nil {
+		return nil, err
+	}
+
+	// Run from the root so the "M path" lines have root-relative paths
+	out, err := run(root, nil, "jj", "diff", "--summary", "-r", "@", "--", filepath.ToSlash(rel))
+	if err != nil {
+		return nil, err
+	}
+	status := &Status{}
+	for _, line := range strings.Split(out, "\n") {
+		if len(line) < 3 {
+			continue
+		}
+		status.add(line, line[2:])
+	}
+	return status, nil
+}
+
+// Head returns the parent of the working copy commit, which changes only
+// when the checkout moves and not on every edit
+func (Jujutsu) Head(dir string) (string, error) {
+	return run(dir, nil, "jj", "log", "--no-graph", "-r", "@-", "-T", "commit_id")
+}
+
+func (Jujutsu) Changed(dir, from, to, path string) (bool, error) {
+	out, err := run(dir, nil, "jj", "diff", "--summary", "--from", from, "--to", to, "--", path)
+	return out != "", err
+}
+
+func (Jujutsu) File(dir, rev, path string) (string, error) {
+	return run(dir, nil, "jj", "file", "show", "-r", rev, "--", path)
+}
+
+// Pull fetches from the git remote and rebases the working copy onto the
+// trunk
+func (Jujutsu) Pull(dir string) error {
+	if err := runCombined(dir, nil, "jj", "git", "fetch"); err != nil {
+		return err
+	}
+	return runCombined(dir, nil, "jj", "rebase", "-d", "trunk()")
+}
+
+func (Jujutsu) Push(dir string) error {
+	return runCombined(dir, nil, "jj", "git", "push")
+}
@@ -0,0 +1,253 @@
+// Package vcs is go4dot's view of the version control system the dotfiles
+// repo lives in: whether the working tree is safe to sync, what it points
+// at and how to pull. Git is the default; Jujutsu and Mercurial repos are
+// recognized by their .jj and .hg directories.
+package vcs
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/nvandessel/go4dot/internal/transcript"
+)
+
+// VCS is a version control backend. Methods take a directory inside the
+// repo, not necessarily its root.
+type VCS interface {
+	// Name is the backend's name and binary, e.g. "git"
+	Name() string
+
+	// Root returns the absolute path of the repo's root
+	Root(dir string) (string, error)
+
+	// Status lists uncommitted changes under dir, with paths relative to
+	// the root, and any interrupted operation or held lock
+	Status(dir string) (*Status, error)
+
+	// Head returns an identifier of the checked out revision
+	Head(dir string) (string, error)
+
+	// Changed reports whether path, relative to dir, differs between two
+	// revisions returned by Head
+	Changed(dir, from, to, path string) (bool, error)
+
+	// File returns the contents of path, relative to dir, at a revision.
+	// rev is anything the VCS accepts, such as an ID returned by Head.
+	File(dir, rev, path string) (string, error)
+
+	// Pull fetches upstream changes and moves the checkout onto them
+	Pull(dir string) error
+
+	// Push publishes local commits upstream
+	Push(dir string) error
+}
+
+// backends are tried in order at each directory level. Jujutsu comes
+// before git as a colocated jj repo has both .jj and .git.
+var backends = []struct {
+	marker string
+	vcs    VCS
+}{
+	{".jj", Jujutsu{}},
+	{".hg", Mercurial{}},
+	{".git", Git{}},
+}
+
+// Detect returns the backend of the repo containing dir, looking for each
+// backend's directory from dir upwards, or nil if dir is in no repo
+func Detect(dir string) VCS {
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return nil
+	}
+	for {
+		for _, b := range backends {
+			if _, err := os.Stat(filepath.Join(abs, b.marker)); err == nil {
+				return b.vcs
+			}
+		}
+		parent := filepath.Dir(abs)
+		if parent == abs {
+			return nil
+		}
+		abs = parent
+	}
+}
+
+// Installed reports whether the backend's binary is on PATH
+func Installed(v VCS) bool {
+	_, err := exec.LookPath(v.Name())
+	return err == nil
+}
+
+// Status describes the parts of a repo's state that make a sync risky
+type Status struct {
+	Changes   []string // Uncommitted changes, one "status path" line each as the VCS prints them
+	Operation string   // Operation in progress, e.g. rebase or merge; empty if none
+	Locked    bool     // Another VCS command holds the working tree's lock
+
+	paths []string // Root-relative path of each change, for filtering
+}
+
+// Clean reports whether there is nothing to warn about
+func (s *Status) Clean() bool {
+	return s == nil || (len(s.Changes) == 0 && s.Operation == "" && !s.Locked)
+}
+
+// Summary describes the status in a short sentence, e.g.
+// "3 uncommitted changes, rebase in progress"
+func (s *Status) Summary() string {
+	if s.Clean() {
+		return "working tree clean"
+	}
+
+	var parts []string
+	switch len(s.Changes) {
+	case 0:
+	case 1:
+		parts = append(parts, "1 uncommitted change")
+	default:
+		parts = append(parts, fmt.Sprintf("%d uncommitted changes", len(s.Changes)))
+	}
+	if s.Operation != "" {
+		parts = append(parts, s.Operation+" in progress")
+	}
+	if s.Locked {
+		parts = append(parts, "locked by another process")
+	}
+	return strings.Join(parts, ", ")
+}
+
+// Preview returns up to max changes, followed by a line counting the rest
+func (s *Status) Preview(max int) []string {
+	if len(s.Changes) <= max {
+		return s.Changes
+	}
+	lines := append([]string{}, s.Changes[:max]...)
+	return append(lines, fmt.Sprintf("... and %d more", len(s.Changes)-max))
+}
+
+// add records a change line and its root-relative path
+func (s *Status) add(line, path string) {
+	s.Changes = append(s.Changes, line)
+	s.paths = append(s.paths, path)
+}
+
+// Check inspects the repo containing dir. Changes under the ignored paths
+// (relative to dir, e.g. files go4dot writes itself) are left out. It
+// returns nil when dir is in no repo or its VCS is not installed, since
+// there is nothing to guard then.
+func Check(dir string, ignore ...string) (*Status, error) {
+	v := Detect(dir)
+	if v == nil || !Installed(v) {
+		return nil, nil
+	}
+	top, err := v.Root(dir)
+	if err != nil {
+		return nil, nil
+	}
+
+	status, err := v.Status(dir)
+	if err != nil {
+		return nil, fmt.Errorf("%s status failed: %w", v.Name(), err)
+	}
+
+	// Status paths are relative to the root, which dir may be below
+	ignore = append([]string(nil), ignore...)
+	abs, absErr := filepath.Abs(dir)
+	if real, err := filepath.EvalSymlinks(abs); err == nil {
+		abs = real
+	}
+	if absErr == nil {
+		for i, p := range ignore {
+			if rel, err := filepath.Rel(top, filepath.Join(abs, p)); err == nil {
+				ignore[i] = filepath.ToSlash(rel)
+			}
+		}
+	}
+
+	filtered := &Status{Operation: status.Operation, Locked: status.Locked}
+	for i, line := range status.Changes {
+		if !isIgnored(status.paths[i], ignore) {
+			filtered.add(line, status.paths[i])
+		}
+	}
+	return filtered, nil
+}
+
+// isIgnored reports whether path is one of the ignored paths or below one
+func isIgnored(path string, ignore []string) bool {
+	path = strings.Trim(path, `"`)
+	for _, prefix := range ignore {
+		prefix = strings.TrimSuffix(prefix, "/")
+		if path == prefix || strings.HasPrefix(path, prefix+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// marker is a file a VCS leaves behind during an interrupted operation
+type marker struct {
+	path      string
+	operation string
+}
+
+// firstMarker returns the operation of the first marker found in dir
+func firstMarker(dir string, markers []marker) string {
+	for _, m := range markers {
+		if _, err := os.Stat(filepath.Join(dir, m.path)); err == nil {
+			return m.operation
+		}
+	}
+	return ""
+}
+
+// exists reports whether any of the paths under dir exists
+func exists(dir string, paths ...string) bool {
+	for _, p := range paths {
+		if _, err := os.Stat(filepath.Join(dir, p)); err == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// run runs a VCS command in dir and returns its trimmed output
+func run(dir string, env []string, name string, args ...string) (string, error) {
+	cmd := exec.Command(name, args...)
+	cmd.Dir = dir
+	if len(env) > 0 {
+		cmd.Env = append(os.Environ(), env...)
+	}
+	out, err := cmd.Output()
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(string(out), "\n"), nil
+}
+
+// runCombined runs a VCS command that changes the repo, returning an error
+// with its output if it fails
+func runCombined(dir string, env []string, name string, args ...string) error {
+	cmd := exec.Command(name, args...)
+	cmd.Dir = dir
+	if len(env) > 0 {
+		cmd.Env = append(os.Environ(), env...)
+	}
+	if out, err := transcript.CombinedOutput(cmd); err != nil {
+		// Name the command by its subcommands, e.g. "jj git fetch"
+		label := []string{name}
+		for _, arg := range args {
+			if strings.HasPrefix(arg, "-") {
+				break
+			}
+			label = append(label, arg)
+		}
+		return fmt.Errorf("%s failed: %w\nOutput: %s", strings.Join(label, " "), err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
@@ -0,0 +1,206 @@
+package vcs
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// initRepo creates a git repo with one committed file
+func initRepo(t *testing.T) string {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "vim", ".vimrc"), "set number\n")
+	for _, args := range [][]string{
+		{"init", "-q"},
+		{"add", "."},
+		{"-c", "user.name=test", "-c", "user.email=test@example.com", "commit", "-q", "-m", "init"},
+	} {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v failed: %v\n%s", args, err, out)
+		}
+	}
+	return dir
+}
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestCheck(t *testing.T) {
+	dir := initRepo(t)
+
+	status, err := Check(dir)
+	if err != nil {
+		t.Fatalf("Check failed: %v", err)
+	}
+	if !status.Clean() {
+		t.Fatalf("expected a clean repo, got %+v", status)
+	}
+
+	writeFile(t, filepath.Join(dir, "vim", ".vimrc"), "set nonumber\n")
+	writeFile(t, filepath.Join(dir, "zsh", ".zshrc"), "export EDITOR=vim\n")
+	writeFile(t, filepath.Join(dir, "machines", "laptop.yaml"), "hostname: laptop\n")
+
+	status, err = Check(dir, "machines/")
+	if err != nil {
+		t.Fatalf("Check failed: %v", err)
+	}
+	want := []string{" M vim/.vimrc", "?? zsh/"}
+	if !reflect.DeepEqual(status.Changes, want) {
+		t.Errorf("Changes = %q, want %q", status.Changes, want)
+	}
+	if got := status.Summary(); got != "2 uncommitted changes" {
+		t.Errorf("Summary() = %q", got)
+	}
+
+	// An interrupted merge is reported even with a clean tree
+	if err := os.WriteFile(filepath.Join(dir, ".git", "MERGE_HEAD"), []byte("0000\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	status, err = Check(filepath.Join(dir, "vim"))
+	if err != nil {
+		t.Fatalf("Check failed: %v", err)
+	}
+	if status.Operation != "merge" {
+		t.Errorf("Operation = %q, want merge", status.Operation)
+	}
+
+	// So is a lock held by another git command
+	if err := os.WriteFile(filepath.Join(dir, ".git", "index.lock"), nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	status, err = Check(dir)
+	if err != nil {
+		t.Fatalf("Check failed: %v", err)
+	}
+	if !status.Locked || !strings.Contains(status.Summary(), "locked by another process") {
+		t.Errorf("expected a locked repo, got %+v", status)
+	}
+}
+
+func TestCheck_NotARepo(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	t.Setenv("GIT_CEILING_DIRECTORIES", os.TempDir())
+
+	status, err := Check(t.TempDir())
+	if err != nil || status != nil {
+		t.Errorf("expected nil status outside a repo, got %+v, %v", status, err)
+	}
+	if !status.Clean() {
+		t.Error("a nil status should be clean")
+	}
+}
+
+func TestStatus_Preview(t *testing.T) {
+	s := &Status{Changes: []string{" M a", " M b", " M c"}, Operation: "rebase"}
+
+	if got := s.Preview(5); !reflect.DeepEqual(got, s.Changes) {
+		t.Errorf("Preview(5) = %q", got)
+	}
+	want := []string{" M a", " M b", "... and 1 more"}
+	if got := s.Preview(2); !reflect.DeepEqual(got, want) {
+		t.Errorf("Preview(2) = %q, want %q", got, want)
+	}
+	if got := s.Summary(); got != "3 uncommitted changes, rebase in progress" {
+		t.Errorf("Summary() = %q", got)
+	}
+}
+
+// runGit runs a git command in dir, failing the test on error
+func runGit(t *testing.T, dir string, args ...string) {
+	t.Helper()
+	cmd := exec.Command("git", append([]string{"-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)...)
+	cmd.Dir = dir
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("git %v failed: %v\n%s", args, err, out)
+	}
+}
+
+func TestGit_PushPull(t *testing.T) {
+	remote := filepath.Join(t.TempDir(), "remote.git")
+	runGit(t, initRepo(t), "clone", "-q", "--bare", ".", remote)
+	mine, theirs := filepath.Join(t.TempDir(), "mine"), filepath.Join(t.TempDir(), "theirs")
+	runGit(t, remote, "clone", "-q", remote, mine)
+	runGit(t, remote, "clone", "-q", remote, theirs)
+
+	var g Git
+	before, err := g.Head(theirs)
+	if err != nil {
+		t.Fatalf("Head failed: %v", err)
+	}
+
+	writeFile(t, filepath.Join(mine, "g4d.yaml"), "schema_version: \"1.0\"\n")
+	runGit(t, mine, "add", ".")
+	runGit(t, mine, "commit", "-q", "-m", "add config")
+	if err := g.Push(mine); err != nil {
+		t.Fatalf("Push failed: %v", err)
+	}
+
+	if err := g.Pull(theirs); err != nil {
+		t.Fatalf("Pull failed: %v", err)
+	}
+	after, _ := g.Head(theirs)
+	if after == before {
+		t.Fatal("Pull did not move HEAD")
+	}
+	if changed, err := g.Changed(theirs, before, after, "g4d.yaml"); err != nil || !changed {
+		t.Errorf("Changed(g4d.yaml) = %v, %v; want true", changed, err)
+	}
+	if changed, _ := g.Changed(theirs, before, after, "vim/.vimrc"); changed {
+		t.Error("Changed(vim/.vimrc) = true, want false")
+	}
+
+	if got, err := g.File(theirs, after, "g4d.yaml"); err != nil || got != `schema_version: "1.0"` {
+		t.Errorf("File(g4d.yaml) = %q, %v", got, err)
+	}
+	if _, err := g.File(theirs, before, "g4d.yaml"); err == nil {
+		t.Error("File() should fail for a path missing at the revision")
+	}
+	if got, err := g.File(filepath.Join(theirs, "vim"), before, ".vimrc"); err != nil || got != "set number" {
+		t.Errorf("File(.vimrc) from a subdirectory = %q, %v", got, err)
+	}
+}
+
+func TestDetect(t *testing.T) {
+	tests := []struct {
+		markers []string
+		want    string
+	}{
+		{[]string{".git"}, "git"},
+		{[]string{".hg"}, "hg"},
+		{[]string{".jj", ".git"}, "jj"}, // A colocated jj repo is driven with jj
+	}
+	for _, tt := range tests {
+		dir := t.TempDir()
+		for _, m := range tt.markers {
+			if err := os.Mkdir(filepath.Join(dir, m), 0755); err != nil {
+				t.Fatal(err)
+			}
+		}
+		sub := filepath.Join(dir, "vim", ".config")
+		if err := os.MkdirAll(sub, 0755); err != nil {
+			t.Fatal(err)
+		}
+		if v := Detect(sub); v == nil || v.Name() != tt.want {
+			t.Errorf("Detect() with %v = %v, want %s", tt.markers, v, tt.want)
+		}
+	}
+}
@@ -0,0 +1,77 @@
+// Package workspace tracks the active workspace: a named target root that
+// configs are linked into instead of the user's home, with its own state.
+//
+// go4dot treats $HOME as the link target throughout, so activating a
+// workspace points HOME at its target for the rest of the process. The
+// user's real home is kept in the environment for the per-user data that
+// must not move with it: preferences, config discovery and cloned bases.
+package workspace
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	// EnvName selects the active workspace, as the --workspace flag does
+	EnvName = "GO4DOT_WORKSPACE"
+	// EnvUserHome holds the user's real home while HOME points at a
+	// workspace's target
+	EnvUserHome = "GO4DOT_USER_HOME"
+)
+
+// Active returns the name of the active workspace, or "" when configs are
+// linked into the user's home
+func Active() string {
+	return os.Getenv(EnvName)
+}
+
+// UserHome returns the user's home directory, which is not $HOME while a
+// workspace is active
+func UserHome() (string, error) {
+	if home := os.Getenv(EnvUserHome); home != "" {
+		return home, nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get home directory: %w", err)
+	}
+	return home, nil
+}
+
+// ExpandTarget resolves a workspace target, where ~ is the user's home
+func ExpandTarget(target string) (string, error) {
+	if target == "~" || strings.HasPrefix(target, "~/") {
+		home, err := UserHome()
+		if err != nil {
+			return "", err
+		}
+		target = filepath.Join(home, strings.TrimPrefix(target, "~"))
+	}
+	if !filepath.IsAbs(target) {
+		return "", fmt.Errorf("workspace target must be absolute or start with ~/: %s", target)
+	}
+	return filepath.Clean(target), nil
+}
+
+// Activate makes name the active workspace for this process and the
+// commands it runs, linking into target (created if missing) instead of the
+// user's home
+func Activate(name, target string) error {
+	home, err := UserHome()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(target, 0755); err != nil {
+		return fmt.Errorf("failed to create workspace target: %w", err)
+	}
+
+	for key, value := range map[string]string{EnvUserHome: home, EnvName: name, "HOME": target} {
+		if err := os.Setenv(key, value); err != nil {
+			return fmt.Errorf("failed to set %s: %w", key, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,66 @@
+package workspace
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestExpandTarget(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv(EnvUserHome, "")
+
+	tests := []struct {
+		target  string
+		want    string
+		wantErr bool
+	}{
+		{target: "~/work-env", want: filepath.Join(home, "work-env")},
+		{target: "~", want: home},
+		{target: "/mnt/work/home/", want: "/mnt/work/home"},
+		{target: "work-env", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.target, func(t *testing.T) {
+			got, err := ExpandTarget(tt.target)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ExpandTarget(%q) error = %v, wantErr %v", tt.target, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ExpandTarget(%q) = %q, want %q", tt.target, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestActivate(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv(EnvUserHome, "")
+	t.Setenv(EnvName, "")
+
+	target := filepath.Join(home, "work-env")
+	if err := Activate("work", target); err != nil {
+		t.Fatalf("Activate failed: %v", err)
+	}
+
+	if _, err := os.Stat(target); err != nil {
+		t.Errorf("expected target to be created: %v", err)
+	}
+	if got := os.Getenv("HOME"); got != target {
+		t.Errorf("HOME = %q, want %q", got, target)
+	}
+	if got := Active(); got != "work" {
+		t.Errorf("Active() = %q, want work", got)
+	}
+	if got, _ := UserHome(); got != home {
+		t.Errorf("UserHome() = %q, want %q", got, home)
+	}
+
+	// ~ keeps meaning the user's home while the workspace is active
+	if got, _ := ExpandTarget("~/other"); got != filepath.Join(home, "other") {
+		t.Errorf("ExpandTarget after Activate = %q", got)
+	}
+}
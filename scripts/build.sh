@@ -34,7 +34,7 @@ for binary in "$BUILD_DIR"/*; do
         zip -j "$DIST_DIR/${filename%.exe}.zip" "$BUILD_DIR/$APP_NAME.exe"
         rm "$BUILD_DIR/$APP_NAME.exe"
     else
-        # Linux/macOS - tar.gz
+        # Linux/macOS/BSD - tar.gz
         # Create a temporary copy with the generic name for the archive
         cp "$binary" "$BUILD_DIR/$APP_NAME"
         tar -czf "$DIST_DIR/$filename.tar.gz" -C "$BUILD_DIR" "$APP_NAME"
@@ -17,6 +17,8 @@ OS="$(uname -s)"
 case "$OS" in
     Linux*)     OS=linux;;
     Darwin*)    OS=darwin;;
+    FreeBSD*)   OS=freebsd;;
+    OpenBSD*)   OS=openbsd;;
     *)          echo -e "${RED}Unsupported OS: $OS${NC}"; exit 1;;
 esac
 
@@ -24,6 +26,7 @@ esac
 ARCH="$(uname -m)"
 case "$ARCH" in
     x86_64)  ARCH=amd64;;
+    amd64)   ARCH=amd64;;
     aarch64) ARCH=arm64;;
     arm64)   ARCH=arm64;;
     *)       echo -e "${RED}Unsupported architecture: $ARCH${NC}"; exit 1;;
@@ -79,6 +82,9 @@ else
 fi
 chmod +x "$INSTALL_DIR/$BINARY"
 
+# Let go4dot be run by its long name too
+"$INSTALL_DIR/$BINARY" alias --dir "$INSTALL_DIR" || echo -e "${RED}Warning: could not create the go4dot alias.${NC}"
+
 # Cleanup
 rm -rf "$TMP_DIR"
 
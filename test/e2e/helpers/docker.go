@@ -122,6 +122,10 @@ type DockerConfig struct {
 
 	// VHSEnabled installs VHS and its dependencies (ttyd, ffmpeg) in the container
 	VHSEnabled bool
+
+	// PackageManager prepares ImageName with this package manager (apt, dnf,
+	// pacman, apk or zypper) instead of the default apt-based setup
+	PackageManager string
 }
 
 // NewDockerTestContainer creates and starts a test container
@@ -146,7 +150,18 @@ func NewDockerTestContainer(t *testing.T, cfg DockerConfig) *DockerTestContainer
 	tmpDir := t.TempDir()
 	dockerfilePath := filepath.Join(tmpDir, "Dockerfile")
 
-	dockerfile := fmt.Sprintf(`FROM %s
+	var dockerfile string
+	if cfg.PackageManager != "" {
+		if cfg.VHSEnabled && cfg.PackageManager != "apt" {
+			t.Fatalf("VHS containers must use apt, not %s", cfg.PackageManager)
+		}
+		var err error
+		dockerfile, err = distroDockerfile(cfg.ImageName, cfg.PackageManager, cfg.WorkDir)
+		if err != nil {
+			t.Fatalf("failed to generate Dockerfile: %v", err)
+		}
+	} else {
+		dockerfile = fmt.Sprintf(`FROM %s
 
 # Install dependencies
 RUN apt-get update && apt-get install -y --no-install-recommends \
@@ -176,6 +191,7 @@ WORKDIR %s
 # Default command
 CMD ["/bin/zsh", "-i"]
 `, cfg.ImageName, cfg.WorkDir)
+	}
 
 	if err := os.WriteFile(dockerfilePath, []byte(dockerfile), 0644); err != nil {
 		t.Fatalf("failed to write Dockerfile: %v", err)
@@ -0,0 +1,112 @@
+//go:build e2e
+
+package helpers
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+// MatrixEnvVar overrides the matrix file used by the distro matrix tests
+const MatrixEnvVar = "G4D_E2E_MATRIX"
+
+// Distro is one environment of the test matrix
+type Distro struct {
+	Name           string `yaml:"name"`
+	Image          string `yaml:"image"`
+	PackageManager string `yaml:"package_manager"`
+}
+
+// Matrix lists the distros the docker suite runs against
+type Matrix struct {
+	Distros []Distro `yaml:"distros"`
+}
+
+// distroPackages are the base packages every test image needs, per package
+// manager. bash and a useradd implementation are missing from some minimal
+// images.
+var distroPackages = map[string]string{
+	"apt":    "apt-get update && apt-get install -y --no-install-recommends bash git stow curl sudo zsh locales ca-certificates && rm -rf /var/lib/apt/lists/* && sed -i '/en_US.UTF-8/s/^# //g' /etc/locale.gen && locale-gen",
+	"dnf":    "dnf install -y bash git stow curl sudo zsh shadow-utils glibc-langpack-en && dnf clean all",
+	"pacman": "pacman -Syu --noconfirm bash git stow curl sudo zsh && pacman -Scc --noconfirm",
+	"apk":    "apk add --no-cache bash git stow curl sudo zsh shadow",
+	"zypper": "zypper --non-interactive install bash git stow curl sudo zsh shadow && zypper clean --all",
+}
+
+// LoadMatrix reads the matrix from $G4D_E2E_MATRIX, or test/e2e/matrix.yaml
+// in the project.
+func LoadMatrix(t *testing.T) Matrix {
+	t.Helper()
+
+	path := os.Getenv(MatrixEnvVar)
+	if path == "" {
+		path = filepath.Join(GetProjectRoot(t), "test", "e2e", "matrix.yaml")
+	}
+
+	matrix, err := ParseMatrixFile(path)
+	if err != nil {
+		t.Fatalf("failed to load test matrix: %v", err)
+	}
+	return matrix
+}
+
+// ParseMatrixFile reads and validates a matrix file
+func ParseMatrixFile(path string) (Matrix, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Matrix{}, err
+	}
+
+	var matrix Matrix
+	if err := yaml.Unmarshal(data, &matrix); err != nil {
+		return Matrix{}, fmt.Errorf("%s: %w", path, err)
+	}
+	if len(matrix.Distros) == 0 {
+		return Matrix{}, fmt.Errorf("%s: no distros", path)
+	}
+
+	seen := make(map[string]bool)
+	for _, d := range matrix.Distros {
+		switch {
+		case d.Name == "" || d.Image == "":
+			return Matrix{}, fmt.Errorf("%s: every distro needs a name and an image", path)
+		case seen[d.Name]:
+			return Matrix{}, fmt.Errorf("%s: duplicate distro %q", path, d.Name)
+		case distroPackages[d.PackageManager] == "":
+			return Matrix{}, fmt.Errorf("%s: distro %q has unsupported package manager %q", path, d.Name, d.PackageManager)
+		}
+		seen[d.Name] = true
+	}
+	return matrix, nil
+}
+
+// distroDockerfile returns the base Dockerfile for an image prepared with
+// the given package manager: the packages the tests need and a passwordless
+// sudo testuser.
+func distroDockerfile(image, packageManager, workDir string) (string, error) {
+	install, ok := distroPackages[packageManager]
+	if !ok {
+		return "", fmt.Errorf("unsupported package manager %q", packageManager)
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "FROM %s\n\n", image)
+	if packageManager == "apt" {
+		sb.WriteString("ENV DEBIAN_FRONTEND=noninteractive\n")
+	}
+	fmt.Fprintf(&sb, "RUN %s\n\n", install)
+	if packageManager == "apt" {
+		sb.WriteString("ENV LANG=en_US.UTF-8\nENV LANGUAGE=en_US:en\nENV LC_ALL=en_US.UTF-8\n")
+	} else {
+		sb.WriteString("ENV LANG=C.UTF-8\nENV LC_ALL=C.UTF-8\n")
+	}
+	sb.WriteString("ENV TERM=xterm-256color\n\n")
+	sb.WriteString("RUN useradd -m -s /bin/bash testuser && \\\n    echo \"testuser ALL=(ALL) NOPASSWD:ALL\" >> /etc/sudoers\n\n")
+	fmt.Fprintf(&sb, "USER testuser\nWORKDIR %s\n\nCMD [\"/bin/bash\"]\n", workDir)
+	return sb.String(), nil
+}
@@ -4,6 +4,8 @@ package main
 import (
 	"fmt"
 	"os"
+	"path/filepath"
+	"regexp"
 	"runtime"
 
 	"github.com/spf13/cobra"
@@ -20,6 +22,12 @@ var (
 	outputFormat   string
 	verboseFlag    bool
 	timeoutSeconds int
+	runFlag        string
+	retriesFlag    int
+	junitPath      string
+	shardFlag      string
+	matrixPath     string
+	fuzzTime       string
 )
 
 var rootCmd = &cobra.Command{
@@ -40,27 +48,54 @@ Examples:
   # Run only Docker tests
   orchestrator --suite=docker
 
+  # Run install/doctor on a custom set of distros
+  orchestrator --suite=docker --run=TestDistroMatrix --matrix=my-matrix.yaml
+
   # Run visual tests and update golden files
   orchestrator --suite=visual --update-golden
 
+  # Run every fuzz target for a minute each
+  orchestrator --suite=fuzz --fuzztime=1m
+
   # Run all tests with 4 parallel workers
   orchestrator --parallel=4
 
   # Output results as JSON
-  orchestrator --format=json`,
+  orchestrator --format=json
+
+  # Run only the health panel visual test, retrying it up to twice
+  orchestrator --suite=visual --run='^TestVHS_HealthPanel$' --retries=2
+
+  # Write a JUnit report for CI
+  orchestrator --junit=test/e2e/reports/junit.xml
+
+  # Split the suite across three CI workers, then combine their reports
+  orchestrator --shard=1/3 --format=json > shard-1.json
+  orchestrator merge shard-*.json`,
 	RunE: runOrchestrator,
 }
 
 func init() {
-	rootCmd.Flags().StringVarP(&suiteFlag, "suite", "s", "all", "Test suite to run: visual, docker, tui, or all")
+	rootCmd.Flags().StringVarP(&suiteFlag, "suite", "s", "all", "Test suite to run: visual, docker, tui, fuzz, or all")
 	rootCmd.Flags().IntVarP(&parallelFlag, "parallel", "p", runtime.NumCPU(), "Number of parallel test workers")
 	rootCmd.Flags().BoolVar(&updateGolden, "update-golden", false, "Update golden files for visual tests")
 	rootCmd.Flags().StringVarP(&outputFormat, "format", "f", "text", "Output format: text or json")
 	rootCmd.Flags().BoolVarP(&verboseFlag, "verbose", "v", false, "Enable verbose output")
 	rootCmd.Flags().IntVarP(&timeoutSeconds, "timeout", "t", 600, "Timeout in seconds for entire test run")
+	rootCmd.Flags().StringVar(&runFlag, "run", "", "Only run tests whose name matches this regular expression")
+	rootCmd.Flags().IntVar(&retriesFlag, "retries", 0, "Retry failing tests up to N times (for known-flaky visual tests)")
+	rootCmd.Flags().StringVar(&junitPath, "junit", "", "Also write results as a JUnit XML report to this file")
+	rootCmd.Flags().StringVar(&fuzzTime, "fuzztime", defaultFuzzTime, "How long to run each fuzz target (fuzz suite)")
+	rootCmd.Flags().StringVar(&matrixPath, "matrix", "", "Distro matrix file for the docker suite (default: test/e2e/matrix.yaml)")
+	rootCmd.Flags().StringVar(&shardFlag, "shard", "", "Run only shard i of n (\"i/n\"), splitting tests deterministically")
+
+	mergeCmd.Flags().StringVarP(&outputFormat, "format", "f", "text", "Output format: text or json")
+	mergeCmd.Flags().BoolVarP(&verboseFlag, "verbose", "v", false, "Enable verbose output")
+	mergeCmd.Flags().StringVar(&junitPath, "junit", "", "Also write merged results as a JUnit XML report to this file")
 
 	rootCmd.AddCommand(versionCmd)
 	rootCmd.AddCommand(listCmd)
+	rootCmd.AddCommand(mergeCmd)
 }
 
 var versionCmd = &cobra.Command{
@@ -77,17 +112,27 @@ var listCmd = &cobra.Command{
 	RunE:  listSuites,
 }
 
+var mergeCmd = &cobra.Command{
+	Use:   "merge <results.json>...",
+	Short: "Merge JSON results from several shards into one summary",
+	Long: `Combine reports written with --format=json, typically one per --shard
+worker, and print a single summary. Exits with an error if any test failed.`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: mergeResults,
+}
+
 func runOrchestrator(cmd *cobra.Command, args []string) error {
 	// Validate suite flag
 	validSuites := map[string]bool{
 		"visual": true,
 		"docker": true,
 		"tui":    true,
+		"fuzz":   true,
 		"all":    true,
 	}
 
 	if !validSuites[suiteFlag] {
-		return fmt.Errorf("invalid suite: %s (valid options: visual, docker, tui, all)", suiteFlag)
+		return fmt.Errorf("invalid suite: %s (valid options: visual, docker, tui, fuzz, all)", suiteFlag)
 	}
 
 	// Validate output format
@@ -95,6 +140,18 @@ func runOrchestrator(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("invalid format: %s (valid options: text, json)", outputFormat)
 	}
 
+	if retriesFlag < 0 {
+		return fmt.Errorf("invalid retries: %d (must be 0 or more)", retriesFlag)
+	}
+
+	var shardIndex, shardCount int
+	if shardFlag != "" {
+		var err error
+		if shardIndex, shardCount, err = ParseShard(shardFlag); err != nil {
+			return err
+		}
+	}
+
 	// Create runner configuration
 	cfg := RunnerConfig{
 		Suite:        suiteFlag,
@@ -102,6 +159,29 @@ func runOrchestrator(cmd *cobra.Command, args []string) error {
 		UpdateGolden: updateGolden,
 		Verbose:      verboseFlag,
 		Timeout:      timeoutSeconds,
+		Retries:      retriesFlag,
+		ShardIndex:   shardIndex,
+		ShardCount:   shardCount,
+		FuzzTime:     fuzzTime,
+	}
+
+	if matrixPath != "" {
+		abs, err := filepath.Abs(matrixPath)
+		if err != nil {
+			return fmt.Errorf("invalid --matrix path: %w", err)
+		}
+		if _, err := os.Stat(abs); err != nil {
+			return fmt.Errorf("matrix file: %w", err)
+		}
+		cfg.Matrix = abs
+	}
+
+	if runFlag != "" {
+		re, err := regexp.Compile(runFlag)
+		if err != nil {
+			return fmt.Errorf("invalid --run expression: %w", err)
+		}
+		cfg.Run = re
 	}
 
 	// Create runner and execute tests
@@ -111,12 +191,41 @@ func runOrchestrator(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("test execution failed: %w", err)
 	}
 
+	return report(results)
+}
+
+func mergeResults(cmd *cobra.Command, args []string) error {
+	var shards []*TestResults
+	for _, path := range args {
+		results, err := LoadResults(path)
+		if err != nil {
+			return err
+		}
+		shards = append(shards, results)
+	}
+
+	return report(MergeResults(shards))
+}
+
+// report outputs results in the configured format, writes the JUnit report
+// if requested and returns an error if any test failed.
+func report(results *TestResults) error {
+	if outputFormat != "text" && outputFormat != "json" {
+		return fmt.Errorf("invalid format: %s (valid options: text, json)", outputFormat)
+	}
+
 	// Create reporter and output results
 	reporter := NewReporter(outputFormat, verboseFlag)
 	if err := reporter.Report(results); err != nil {
 		return fmt.Errorf("failed to generate report: %w", err)
 	}
 
+	if junitPath != "" {
+		if err := WriteJUnit(junitPath, results); err != nil {
+			return err
+		}
+	}
+
 	// Return error if any tests failed
 	if results.Failed > 0 {
 		return fmt.Errorf("tests failed: %d/%d", results.Failed, results.Total)
@@ -146,6 +255,11 @@ func listSuites(cmd *cobra.Command, args []string) error {
 			Description: "Headless TUI tests using teatest",
 			Tags:        "e2e",
 		},
+		{
+			Name:        "fuzz",
+			Description: "Go fuzz targets (validation, config parsing)",
+			Tags:        "none",
+		},
 	}
 
 	fmt.Println("Available test suites:")
@@ -2,9 +2,11 @@ package main
 
 import (
 	"encoding/json"
+	"encoding/xml"
 	"fmt"
 	"io"
 	"os"
+	"sort"
 	"strings"
 	"time"
 )
@@ -63,7 +65,11 @@ func (r *Reporter) printf(format string, args ...interface{}) {
 func (r *Reporter) printHeader(results *TestResults) {
 	r.printf("\n")
 	r.printf("================================================================================\n")
-	r.printf("  E2E Test Results - Suite: %s\n", results.Suite)
+	if results.Shard != "" {
+		r.printf("  E2E Test Results - Suite: %s (shard %s)\n", results.Suite, results.Shard)
+	} else {
+		r.printf("  E2E Test Results - Suite: %s\n", results.Suite)
+	}
 	r.printf("================================================================================\n")
 	r.printf("\n")
 }
@@ -81,13 +87,18 @@ func (r *Reporter) printTestResult(test TestResult) {
 	statusIcon := r.getStatusIcon(test)
 	statusText := r.getStatusText(test)
 
-	r.printf("%s [%s] %s (%s)\n",
+	r.printf("%s [%s] %s (%s)%s\n",
 		statusIcon,
 		test.Suite,
 		test.Name,
 		formatDuration(test.Duration),
+		attemptsNote(test),
 	)
 
+	for _, sub := range test.Subtests {
+		r.printf("    %s %s (%s)\n", r.getStatusIcon(sub), sub.Name, formatDuration(sub.Duration))
+	}
+
 	// Print details if verbose or if test failed
 	if r.verbose || !test.Passed {
 		if test.Error != "" && test.Error != "skipped" {
@@ -99,6 +110,17 @@ func (r *Reporter) printTestResult(test TestResult) {
 	}
 }
 
+// attemptsNote describes retries of a test, if any.
+func attemptsNote(test TestResult) string {
+	switch {
+	case test.Flaky:
+		return fmt.Sprintf(" - flaky, passed on attempt %d", test.Attempts)
+	case test.Attempts > 1:
+		return fmt.Sprintf(" - failed %d attempts", test.Attempts)
+	}
+	return ""
+}
+
 // getStatusIcon returns the appropriate icon for a test result.
 func (r *Reporter) getStatusIcon(test TestResult) string {
 	if test.Error == "skipped" {
@@ -153,6 +175,9 @@ func (r *Reporter) printSummary(results *TestResults) {
 	r.printf("  Passed:   %d\n", results.Passed)
 	r.printf("  Failed:   %d\n", results.Failed)
 	r.printf("  Skipped:  %d\n", results.Skipped)
+	if flaky := results.FlakyCount(); flaky > 0 {
+		r.printf("  Flaky:    %d\n", flaky)
+	}
 	r.printf("  Duration: %s\n", formatDuration(results.Duration))
 	r.printf("\n")
 
@@ -183,24 +208,28 @@ func formatDuration(d time.Duration) string {
 
 // JSONResult is a simplified result structure for JSON output.
 type JSONResult struct {
-	Suite    string       `json:"suite"`
-	Status   string       `json:"status"`
-	Total    int          `json:"total"`
-	Passed   int          `json:"passed"`
-	Failed   int          `json:"failed"`
-	Skipped  int          `json:"skipped"`
-	Duration string       `json:"duration"`
-	Tests    []JSONTest   `json:"tests"`
+	Suite    string     `json:"suite"`
+	Shard    string     `json:"shard,omitempty"`
+	Status   string     `json:"status"`
+	Total    int        `json:"total"`
+	Passed   int        `json:"passed"`
+	Failed   int        `json:"failed"`
+	Skipped  int        `json:"skipped"`
+	Duration string     `json:"duration"`
+	Tests    []JSONTest `json:"tests"`
 }
 
 // JSONTest represents a single test in JSON output.
 type JSONTest struct {
-	Name     string `json:"name"`
-	Suite    string `json:"suite"`
-	Status   string `json:"status"`
-	Duration string `json:"duration"`
-	Error    string `json:"error,omitempty"`
-	Output   string `json:"output,omitempty"`
+	Name     string     `json:"name"`
+	Suite    string     `json:"suite"`
+	Status   string     `json:"status"`
+	Duration string     `json:"duration"`
+	Error    string     `json:"error,omitempty"`
+	Output   string     `json:"output,omitempty"`
+	Attempts int        `json:"attempts,omitempty"`
+	Flaky    bool       `json:"flaky,omitempty"`
+	Subtests []JSONTest `json:"subtests,omitempty"`
 }
 
 // ToJSON converts TestResults to a JSON-friendly format.
@@ -214,25 +243,12 @@ func (results *TestResults) ToJSON() JSONResult {
 
 	tests := make([]JSONTest, len(results.Tests))
 	for i, t := range results.Tests {
-		testStatus := "passed"
-		if t.Error == "skipped" {
-			testStatus = "skipped"
-		} else if !t.Passed {
-			testStatus = "failed"
-		}
-
-		tests[i] = JSONTest{
-			Name:     t.Name,
-			Suite:    t.Suite,
-			Status:   testStatus,
-			Duration: formatDuration(t.Duration),
-			Error:    t.Error,
-			Output:   t.Output,
-		}
+		tests[i] = t.toJSON()
 	}
 
 	return JSONResult{
 		Suite:    results.Suite,
+		Shard:    results.Shard,
 		Status:   status,
 		Total:    results.Total,
 		Passed:   results.Passed,
@@ -242,3 +258,298 @@ func (results *TestResults) ToJSON() JSONResult {
 		Tests:    tests,
 	}
 }
+
+// toJSON converts a TestResult to its JSON form.
+func (t TestResult) toJSON() JSONTest {
+	status := "passed"
+	if t.Error == "skipped" {
+		status = "skipped"
+	} else if !t.Passed {
+		status = "failed"
+	}
+
+	test := JSONTest{
+		Name:     t.Name,
+		Suite:    t.Suite,
+		Status:   status,
+		Duration: formatDuration(t.Duration),
+		Error:    t.Error,
+		Output:   t.Output,
+		Attempts: t.Attempts,
+		Flaky:    t.Flaky,
+	}
+	for _, sub := range t.Subtests {
+		test.Subtests = append(test.Subtests, sub.toJSON())
+	}
+	return test
+}
+
+// fromJSON converts a JSON test back to a TestResult.
+func (t JSONTest) fromJSON() (TestResult, error) {
+	d, err := parseReportDuration(t.Duration)
+	if err != nil {
+		return TestResult{}, fmt.Errorf("test %s: %w", t.Name, err)
+	}
+	result := TestResult{
+		Name:     t.Name,
+		Suite:    t.Suite,
+		Passed:   t.Status == "passed",
+		Duration: d,
+		Output:   t.Output,
+		Error:    t.Error,
+		Attempts: t.Attempts,
+		Flaky:    t.Flaky,
+	}
+	for _, sub := range t.Subtests {
+		subResult, err := sub.fromJSON()
+		if err != nil {
+			return TestResult{}, err
+		}
+		result.Subtests = append(result.Subtests, subResult)
+	}
+	return result, nil
+}
+
+// FromJSON converts a JSON report, such as the output of one shard, back
+// to TestResults.
+func (j JSONResult) FromJSON() (*TestResults, error) {
+	duration, err := parseReportDuration(j.Duration)
+	if err != nil {
+		return nil, err
+	}
+
+	results := &TestResults{
+		Suite:    j.Suite,
+		Shard:    j.Shard,
+		Total:    j.Total,
+		Passed:   j.Passed,
+		Failed:   j.Failed,
+		Skipped:  j.Skipped,
+		Duration: duration,
+		Tests:    make([]TestResult, len(j.Tests)),
+	}
+	for i, t := range j.Tests {
+		if results.Tests[i], err = t.fromJSON(); err != nil {
+			return nil, err
+		}
+	}
+	return results, nil
+}
+
+// parseReportDuration parses a duration written by formatDuration.
+func parseReportDuration(s string) (time.Duration, error) {
+	if s == "" {
+		return 0, nil
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid duration %q: %w", s, err)
+	}
+	return d, nil
+}
+
+// MergeResults combines the results of several shards into one run. The
+// duration is that of the slowest shard, since shards run side by side. A
+// test reported by more than one shard, such as a suite skipped everywhere
+// for lack of a container runtime, is counted once, keeping a failure over
+// a pass or skip.
+func MergeResults(shards []*TestResults) *TestResults {
+	merged := &TestResults{Tests: []TestResult{}}
+
+	index := make(map[string]int)
+	for _, shard := range shards {
+		switch {
+		case merged.Suite == "":
+			merged.Suite = shard.Suite
+		case merged.Suite != shard.Suite:
+			merged.Suite = "all"
+		}
+		if shard.Duration > merged.Duration {
+			merged.Duration = shard.Duration
+		}
+
+		for _, t := range shard.Tests {
+			key := t.Suite + "\x00" + t.Name
+			i, ok := index[key]
+			if !ok {
+				index[key] = len(merged.Tests)
+				merged.Tests = append(merged.Tests, t)
+				continue
+			}
+			if !t.Passed && t.Error != "skipped" {
+				merged.Tests[i] = t
+			}
+		}
+	}
+
+	sort.SliceStable(merged.Tests, func(i, j int) bool {
+		if merged.Tests[i].Suite != merged.Tests[j].Suite {
+			return merged.Tests[i].Suite < merged.Tests[j].Suite
+		}
+		return merged.Tests[i].Name < merged.Tests[j].Name
+	})
+
+	for _, t := range merged.Tests {
+		merged.Total++
+		if t.Passed {
+			merged.Passed++
+		} else if t.Error == "skipped" {
+			merged.Skipped++
+		} else {
+			merged.Failed++
+		}
+	}
+
+	return merged
+}
+
+// LoadResults reads a JSON report written with --format=json.
+func LoadResults(path string) (*TestResults, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read results: %w", err)
+	}
+	var report JSONResult
+	if err := json.Unmarshal(data, &report); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	results, err := report.FromJSON()
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	return results, nil
+}
+
+// FlakyCount returns the number of tests that passed only after a retry.
+func (results *TestResults) FlakyCount() int {
+	count := 0
+	for _, t := range results.Tests {
+		if t.Flaky {
+			count++
+		}
+	}
+	return count
+}
+
+// junitTestSuites is the root element of a JUnit XML report.
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Skipped  int              `xml:"skipped,attr"`
+	Time     string           `xml:"time,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+// junitTestSuite groups the test cases of one orchestrator suite.
+type junitTestSuite struct {
+	Name     string          `xml:"name,attr"`
+	Tests    int             `xml:"tests,attr"`
+	Failures int             `xml:"failures,attr"`
+	Skipped  int             `xml:"skipped,attr"`
+	Time     string          `xml:"time,attr"`
+	Cases    []junitTestCase `xml:"testcase"`
+}
+
+// junitTestCase is a single test in a JUnit XML report.
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	Classname string        `xml:"classname,attr"`
+	Time      string        `xml:"time,attr"`
+	Failure   *junitMessage `xml:"failure,omitempty"`
+	Skipped   *junitMessage `xml:"skipped,omitempty"`
+	SystemOut string        `xml:"system-out,omitempty"`
+}
+
+// junitMessage is a failure or skip reason with optional details.
+type junitMessage struct {
+	Message string `xml:"message,attr,omitempty"`
+	Body    string `xml:",chardata"`
+}
+
+// ToJUnit converts TestResults to a JUnit XML report, with one testsuite
+// element per orchestrator suite. Flaky tests pass and keep their output.
+func (results *TestResults) ToJUnit() junitTestSuites {
+	report := junitTestSuites{
+		Time: junitSeconds(results.Duration),
+	}
+
+	index := make(map[string]int)
+	var durations []time.Duration
+	for _, t := range results.Tests {
+		i, ok := index[t.Suite]
+		if !ok {
+			i = len(report.Suites)
+			index[t.Suite] = i
+			report.Suites = append(report.Suites, junitTestSuite{Name: t.Suite})
+			durations = append(durations, 0)
+		}
+		suite := &report.Suites[i]
+		durations[i] += t.Duration
+
+		tc := junitTestCase{
+			Name:      t.Name,
+			Classname: t.Suite,
+			Time:      junitSeconds(t.Duration),
+		}
+		switch {
+		case t.Error == "skipped":
+			tc.Skipped = &junitMessage{Message: strings.TrimSpace(t.Output)}
+			suite.Skipped++
+		case !t.Passed:
+			tc.Failure = &junitMessage{Message: t.Error, Body: t.Output}
+			suite.Failures++
+		case t.Flaky:
+			tc.SystemOut = fmt.Sprintf("passed on attempt %d\n%s", t.Attempts, t.Output)
+		}
+		suite.Cases = append(suite.Cases, tc)
+		suite.Tests++
+
+		// Direct subtests (such as one per distro) get their own test cases
+		for _, sub := range t.Subtests {
+			subCase := junitTestCase{
+				Name:      t.Name + "/" + sub.Name,
+				Classname: t.Suite,
+				Time:      junitSeconds(sub.Duration),
+			}
+			switch {
+			case sub.Error == "skipped":
+				subCase.Skipped = &junitMessage{}
+				suite.Skipped++
+			case !sub.Passed:
+				subCase.Failure = &junitMessage{Message: sub.Error}
+				suite.Failures++
+			}
+			suite.Cases = append(suite.Cases, subCase)
+			suite.Tests++
+		}
+	}
+
+	for i := range report.Suites {
+		report.Suites[i].Time = junitSeconds(durations[i])
+		report.Tests += report.Suites[i].Tests
+		report.Failures += report.Suites[i].Failures
+		report.Skipped += report.Suites[i].Skipped
+	}
+
+	return report
+}
+
+// WriteJUnit writes results as a JUnit XML report to path.
+func WriteJUnit(path string, results *TestResults) error {
+	data, err := xml.MarshalIndent(results.ToJUnit(), "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode JUnit report: %w", err)
+	}
+	data = append([]byte(xml.Header), data...)
+	data = append(data, '\n')
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write JUnit report: %w", err)
+	}
+	return nil
+}
+
+// junitSeconds formats a duration as seconds, as JUnit expects.
+func junitSeconds(d time.Duration) string {
+	return fmt.Sprintf("%.3f", d.Seconds())
+}
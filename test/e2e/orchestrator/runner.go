@@ -1,12 +1,18 @@
 package main
 
 import (
+	"bufio"
 	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
 	"sync"
 	"time"
 )
@@ -18,6 +24,12 @@ type RunnerConfig struct {
 	UpdateGolden bool
 	Verbose      bool
 	Timeout      int
+	Run          *regexp.Regexp // Only run tests whose name matches (nil runs all)
+	Matrix       string         // Distro matrix file for the docker suite
+	Retries      int            // Extra attempts for tests that fail
+	ShardIndex   int            // 1-based shard to run (with ShardCount)
+	ShardCount   int            // Number of shards, 0 or 1 runs everything
+	FuzzTime     string         // How long to run each fuzz target (go test -fuzztime)
 }
 
 // Runner executes test suites.
@@ -34,11 +46,15 @@ type TestResult struct {
 	Duration time.Duration `json:"duration"`
 	Output   string        `json:"output,omitempty"`
 	Error    string        `json:"error,omitempty"`
+	Attempts int           `json:"attempts,omitempty"` // Set when the test was retried
+	Flaky    bool          `json:"flaky,omitempty"`    // Failed at first, passed on a retry
+	Subtests []TestResult  `json:"subtests,omitempty"` // Direct subtests, e.g. one per distro
 }
 
 // TestResults aggregates results from all tests.
 type TestResults struct {
 	Suite    string        `json:"suite"`
+	Shard    string        `json:"shard,omitempty"`
 	Total    int           `json:"total"`
 	Passed   int           `json:"passed"`
 	Failed   int           `json:"failed"`
@@ -74,6 +90,9 @@ func (r *Runner) Run() (*TestResults, error) {
 		Suite: r.config.Suite,
 		Tests: []TestResult{},
 	}
+	if r.config.ShardCount > 1 {
+		results.Shard = fmt.Sprintf("%d/%d", r.config.ShardIndex, r.config.ShardCount)
+	}
 
 	suites := r.getSuitesToRun()
 
@@ -132,6 +151,8 @@ func (r *Runner) runSuite(ctx context.Context, suite string) ([]TestResult, erro
 		return r.runDockerTests(ctx)
 	case "tui":
 		return r.runTUITests(ctx)
+	case "fuzz":
+		return r.runFuzzTests(ctx)
 	default:
 		return nil, fmt.Errorf("unknown suite: %s", suite)
 	}
@@ -151,7 +172,7 @@ func (r *Runner) runVisualTests(ctx context.Context) ([]TestResult, error) {
 	}
 
 	// Find VHS test files
-	testFiles, err := r.findTestFiles("scenarios", "cli_test.go")
+	testFiles, err := r.findTestFiles("scenarios", "cli_test.go", "vhs_visual_test.go")
 	if err != nil {
 		return nil, fmt.Errorf("failed to find visual test files: %w", err)
 	}
@@ -193,8 +214,8 @@ func (r *Runner) runDockerTests(ctx context.Context) ([]TestResult, error) {
 		}}, nil
 	}
 
-	// Find Docker test files (doctor_checks_test.go, install_flow_test.go)
-	testFiles, err := r.findTestFiles("scenarios", "doctor_checks_test.go", "install_flow_test.go")
+	// Find Docker test files (doctor_checks_test.go, install_flow_test.go, distro_matrix_test.go)
+	testFiles, err := r.findTestFiles("scenarios", "doctor_checks_test.go", "install_flow_test.go", "distro_matrix_test.go")
 	if err != nil {
 		return nil, fmt.Errorf("failed to find docker test files: %w", err)
 	}
@@ -208,7 +229,12 @@ func (r *Runner) runDockerTests(ctx context.Context) ([]TestResult, error) {
 		}}, nil
 	}
 
-	return r.runGoTests(ctx, testFiles, "docker", os.Environ())
+	env := os.Environ()
+	if r.config.Matrix != "" {
+		env = append(env, "G4D_E2E_MATRIX="+r.config.Matrix)
+	}
+
+	return r.runGoTests(ctx, testFiles, "docker", env)
 }
 
 // runTUITests executes headless TUI tests using teatest.
@@ -231,25 +257,129 @@ func (r *Runner) runTUITests(ctx context.Context) ([]TestResult, error) {
 	return r.runGoTests(ctx, testFiles, "tui", os.Environ())
 }
 
+// runFuzzTests runs every fuzz target in the module for the configured
+// fuzz time. Targets run one after another, since each one already uses
+// all CPUs.
+func (r *Runner) runFuzzTests(ctx context.Context) ([]TestResult, error) {
+	files, err := r.findFuzzFiles()
+	if err != nil {
+		return nil, fmt.Errorf("failed to find fuzz targets: %w", err)
+	}
+
+	selected, err := r.selectFileFuncs(files, listFuzzFuncs)
+	if err != nil {
+		return nil, err
+	}
+
+	results := []TestResult{}
+	for _, file := range files {
+		for _, name := range selected[file] {
+			if ctx.Err() != nil {
+				return results, ctx.Err()
+			}
+			results = append(results, r.fuzz(ctx, file, name))
+		}
+	}
+
+	if len(results) == 0 {
+		return []TestResult{{
+			Name:   "fuzz",
+			Suite:  "fuzz",
+			Passed: true,
+			Output: "No fuzz targets found",
+		}}, nil
+	}
+	return results, nil
+}
+
+// fuzz runs a single fuzz target. A failure leaves the crashing input in
+// the package's testdata/fuzz directory, as go test reports.
+func (r *Runner) fuzz(ctx context.Context, file, name string) TestResult {
+	fuzzTime := r.config.FuzzTime
+	if fuzzTime == "" {
+		fuzzTime = defaultFuzzTime
+	}
+
+	cmd := exec.CommandContext(ctx, "go", "test", "-run=^$", "-fuzz=^"+name+"$", "-fuzztime="+fuzzTime, ".")
+	cmd.Dir = filepath.Dir(file)
+
+	startTime := time.Now()
+	output, err := cmd.CombinedOutput()
+
+	result := TestResult{
+		Name:     name,
+		Suite:    "fuzz",
+		Passed:   err == nil,
+		Duration: time.Since(startTime),
+	}
+	if err != nil {
+		result.Error = err.Error()
+		result.Output = string(output)
+	} else if r.config.Verbose {
+		result.Output = string(output)
+	}
+	return result
+}
+
+// findFuzzFiles returns the test files outside test/e2e that declare fuzz
+// targets.
+func (r *Runner) findFuzzFiles() ([]string, error) {
+	var files []string
+	for _, dir := range []string{"cmd", "internal"} {
+		err := filepath.WalkDir(filepath.Join(r.projectRoot, dir), func(path string, d os.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if d.IsDir() && d.Name() == "testdata" {
+				return filepath.SkipDir
+			}
+			if d.IsDir() || !strings.HasSuffix(path, "_test.go") {
+				return nil
+			}
+			names, err := listFuzzFuncs(path)
+			if err != nil {
+				return err
+			}
+			if len(names) > 0 {
+				files = append(files, path)
+			}
+			return nil
+		})
+		if err != nil && !os.IsNotExist(err) {
+			return nil, err
+		}
+	}
+	return files, nil
+}
+
 // runGoTests executes Go tests for the specified files.
 func (r *Runner) runGoTests(ctx context.Context, files []string, suite string, env []string) ([]TestResult, error) {
 	results := []TestResult{}
-	resultsChan := make(chan TestResult, len(files))
+	resultsChan := make(chan []TestResult, len(files))
 
 	// Create a wait group for parallel execution
 	var wg sync.WaitGroup
 	semaphore := make(chan struct{}, r.config.Parallel)
 
+	selected, err := r.selectFileTests(files)
+	if err != nil {
+		return nil, err
+	}
+
 	for _, file := range files {
+		names := selected[file]
+		if len(names) == 0 {
+			continue
+		}
+
 		wg.Add(1)
-		go func(testFile string) {
+		go func(testFile string, names []string) {
 			defer wg.Done()
 			semaphore <- struct{}{}        // Acquire semaphore
 			defer func() { <-semaphore }() // Release semaphore
 
-			result := r.runSingleTestFile(ctx, testFile, suite, env)
-			resultsChan <- result
-		}(file)
+			resultsChan <- r.runTestFile(ctx, testFile, suite, names, env)
+		}(file, names)
 	}
 
 	// Wait for all tests to complete
@@ -259,66 +389,308 @@ func (r *Runner) runGoTests(ctx context.Context, files []string, suite string, e
 	}()
 
 	// Collect results
-	for result := range resultsChan {
-		results = append(results, result)
+	for fileResults := range resultsChan {
+		results = append(results, fileResults...)
 	}
 
 	return results, nil
 }
 
-// runSingleTestFile executes a single test file.
-func (r *Runner) runSingleTestFile(ctx context.Context, file, suite string, env []string) TestResult {
-	startTime := time.Now()
+// selectFileTests lists the tests declared in each file and keeps the ones
+// selected by --run and --shard. Sharding deals the selected tests of the
+// suite out by name, so every worker computes the same split without
+// coordination and shard sizes differ by at most one.
+func (r *Runner) selectFileTests(files []string) (map[string][]string, error) {
+	return r.selectFileFuncs(files, listTestFuncs)
+}
+
+// selectFileFuncs is selectFileTests for any kind of function the list
+// function finds, such as fuzz targets.
+func (r *Runner) selectFileFuncs(files []string, list func(string) ([]string, error)) (map[string][]string, error) {
+	type fileTest struct {
+		file string
+		name string
+	}
+
+	var all []fileTest
+	for _, file := range files {
+		names, err := list(file)
+		if err != nil {
+			return nil, err
+		}
+		for _, name := range r.selectTests(names) {
+			all = append(all, fileTest{file: file, name: name})
+		}
+	}
+
+	sort.Slice(all, func(i, j int) bool {
+		if all[i].name != all[j].name {
+			return all[i].name < all[j].name
+		}
+		return all[i].file < all[j].file
+	})
+
+	selected := make(map[string][]string)
+	for i, ft := range all {
+		if r.config.ShardCount > 1 && i%r.config.ShardCount != r.config.ShardIndex-1 {
+			continue
+		}
+		selected[ft.file] = append(selected[ft.file], ft.name)
+	}
+	return selected, nil
+}
+
+// selectTests filters test names by the --run expression.
+func (r *Runner) selectTests(names []string) []string {
+	if r.config.Run == nil {
+		return names
+	}
+	var selected []string
+	for _, name := range names {
+		if r.config.Run.MatchString(name) {
+			selected = append(selected, name)
+		}
+	}
+	return selected
+}
+
+// runTestFile runs the named tests of a file, retrying the ones that fail
+// up to the configured number of times.
+func (r *Runner) runTestFile(ctx context.Context, file, suite string, names []string, env []string) []TestResult {
+	results := r.goTest(ctx, file, suite, names, env)
+
+	for attempt := 2; attempt <= r.config.Retries+1; attempt++ {
+		var failed []string
+		for _, result := range results {
+			if !result.Passed && result.Error != "skipped" && result.Name != filepath.Base(file) {
+				failed = append(failed, result.Name)
+			}
+		}
+		if len(failed) == 0 || ctx.Err() != nil {
+			break
+		}
 
-	// Get the directory containing the test file
-	testDir := filepath.Dir(file)
-	testFileName := filepath.Base(file)
+		retried := make(map[string]TestResult)
+		for _, result := range r.goTest(ctx, file, suite, failed, env) {
+			result.Attempts = attempt
+			result.Flaky = result.Passed
+			retried[result.Name] = result
+		}
+		for i, result := range results {
+			if retry, ok := retried[result.Name]; ok {
+				results[i] = retry
+			}
+		}
+	}
+
+	return results
+}
 
+// goTest runs the named tests once and returns a result per test. If the
+// run produces no test results at all (a build failure, for example), a
+// single failing result named after the file is returned instead.
+func (r *Runner) goTest(ctx context.Context, file, suite string, names []string, env []string) []TestResult {
 	// Build the go test command
-	args := []string{"test", "-v", "-tags=e2e", "-timeout", fmt.Sprintf("%ds", r.config.Timeout)}
+	args := []string{"test", "-json", "-tags=e2e", "-timeout", fmt.Sprintf("%ds", r.config.Timeout)}
 
 	// Add parallel flag
 	if r.config.Parallel > 1 {
 		args = append(args, fmt.Sprintf("-parallel=%d", r.config.Parallel))
 	}
 
-	// Run specific test file
-	args = append(args, "-run", testFileToPattern(testFileName), ".")
+	// The TUI tests drive the dashboard while operations report from other
+	// goroutines, so they run under the race detector
+	if suite == "tui" {
+		args = append(args, "-race")
+	}
+
+	// Run exactly the selected tests
+	args = append(args, "-run", testNamesPattern(names), ".")
 
 	cmd := exec.CommandContext(ctx, "go", args...)
-	cmd.Dir = testDir
+	cmd.Dir = filepath.Dir(file)
 	cmd.Env = env
 
 	var stdout, stderr bytes.Buffer
 	cmd.Stdout = &stdout
 	cmd.Stderr = &stderr
 
+	startTime := time.Now()
 	err := cmd.Run()
-	duration := time.Since(startTime)
 
-	result := TestResult{
-		Name:     testFileName,
-		Suite:    suite,
-		Duration: duration,
+	results := parseTestEvents(stdout.Bytes(), suite)
+	if len(results) == 0 {
+		result := TestResult{
+			Name:     filepath.Base(file),
+			Suite:    suite,
+			Passed:   err == nil,
+			Duration: time.Since(startTime),
+			Output:   stdout.String() + stderr.String(),
+		}
+		if err != nil {
+			result.Error = err.Error()
+		}
+		return []TestResult{result}
 	}
 
-	combinedOutput := stdout.String() + stderr.String()
-	if r.config.Verbose {
-		result.Output = combinedOutput
+	// Tests that never reported (a panic or timeout in another test) failed
+	reported := make(map[string]bool)
+	for _, result := range results {
+		reported[result.Name] = true
+	}
+	for _, name := range names {
+		if !reported[name] {
+			results = append(results, TestResult{
+				Name:   name,
+				Suite:  suite,
+				Error:  "no result reported",
+				Output: stderr.String(),
+			})
+		}
 	}
 
-	if err != nil {
-		result.Passed = false
-		result.Error = err.Error()
-		if !r.config.Verbose {
-			// Include output on failure even if not verbose
-			result.Output = combinedOutput
+	for i := range results {
+		if results[i].Passed && !r.config.Verbose {
+			results[i].Output = ""
 		}
-	} else {
-		result.Passed = true
 	}
 
-	return result
+	return results
+}
+
+// testEvent is a line of `go test -json` output.
+type testEvent struct {
+	Action  string
+	Test    string
+	Elapsed float64
+	Output  string
+}
+
+// parseTestEvents turns `go test -json` output into one result per
+// top-level test, in the order the tests started. Subtest output is folded
+// into its parent, and the outcome of each direct subtest is kept in
+// Subtests.
+func parseTestEvents(data []byte, suite string) []TestResult {
+	var results []TestResult
+	index := make(map[string]int)
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var ev testEvent
+		if err := json.Unmarshal(scanner.Bytes(), &ev); err != nil || ev.Test == "" {
+			continue
+		}
+
+		name, _, isSubtest := strings.Cut(ev.Test, "/")
+		i, ok := index[name]
+		if !ok {
+			i = len(results)
+			index[name] = i
+			results = append(results, TestResult{Name: name, Suite: suite})
+		}
+		result := &results[i]
+
+		switch {
+		case ev.Action == "output":
+			result.Output += ev.Output
+		case isSubtest:
+			// The parent's own event decides the outcome
+			recordSubtest(result, ev)
+		case ev.Action == "pass":
+			result.Passed = true
+			result.Duration = time.Duration(ev.Elapsed * float64(time.Second))
+		case ev.Action == "fail":
+			result.Error = "test failed"
+			result.Duration = time.Duration(ev.Elapsed * float64(time.Second))
+		case ev.Action == "skip":
+			result.Error = "skipped"
+			result.Duration = time.Duration(ev.Elapsed * float64(time.Second))
+		}
+	}
+
+	return results
+}
+
+// recordSubtest records the outcome of a direct subtest on its parent.
+func recordSubtest(parent *TestResult, ev testEvent) {
+	_, name, _ := strings.Cut(ev.Test, "/")
+	if strings.Contains(name, "/") {
+		return
+	}
+
+	sub := TestResult{
+		Name:     name,
+		Suite:    parent.Suite,
+		Duration: time.Duration(ev.Elapsed * float64(time.Second)),
+	}
+	switch ev.Action {
+	case "pass":
+		sub.Passed = true
+	case "fail":
+		sub.Error = "test failed"
+	case "skip":
+		sub.Error = "skipped"
+	default:
+		return
+	}
+	parent.Subtests = append(parent.Subtests, sub)
+}
+
+// testFuncPattern matches top-level test function declarations.
+var testFuncPattern = regexp.MustCompile(`(?m)^func (Test\w+)\(t \*testing\.T\)`)
+
+// fuzzFuncPattern matches fuzz target declarations.
+var fuzzFuncPattern = regexp.MustCompile(`(?m)^func (Fuzz\w+)\(f \*testing\.F\)`)
+
+// defaultFuzzTime is how long each fuzz target runs unless configured. It is
+// also the default of `make fuzz`, which only passes --fuzztime when FUZZTIME
+// is set.
+const defaultFuzzTime = "30s"
+
+// listTestFuncs returns the names of the test functions declared in a file.
+func listTestFuncs(file string) ([]string, error) {
+	return listFuncs(file, testFuncPattern)
+}
+
+// listFuzzFuncs returns the names of the fuzz targets declared in a file.
+func listFuzzFuncs(file string) ([]string, error) {
+	return listFuncs(file, fuzzFuncPattern)
+}
+
+// listFuncs returns the first submatch of every match of pattern in file.
+func listFuncs(file string, pattern *regexp.Regexp) ([]string, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("read %s: %w", file, err)
+	}
+	var names []string
+	for _, match := range pattern.FindAllSubmatch(data, -1) {
+		names = append(names, string(match[1]))
+	}
+	return names, nil
+}
+
+// testNamesPattern returns a -run expression matching exactly the given
+// tests. Every test in a package shares one binary, so naming the tests is
+// the only way to scope a run to a single file.
+func testNamesPattern(names []string) string {
+	return "^(" + strings.Join(names, "|") + ")$"
+}
+
+// ParseShard parses a shard spec of the form "i/n" with 1 <= i <= n.
+func ParseShard(spec string) (index, count int, err error) {
+	i, n, ok := strings.Cut(spec, "/")
+	if ok {
+		index, err = strconv.Atoi(i)
+		if err == nil {
+			count, err = strconv.Atoi(n)
+		}
+	}
+	if !ok || err != nil || count < 1 || index < 1 || index > count {
+		return 0, 0, fmt.Errorf("invalid shard %q (expected i/n with 1 <= i <= n)", spec)
+	}
+	return index, count, nil
 }
 
 // findTestFiles finds test files in the e2e test directory.
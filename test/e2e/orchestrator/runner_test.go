@@ -0,0 +1,268 @@
+package main
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseTestEvents(t *testing.T) {
+	data := strings.Join([]string{
+		`{"Action":"run","Test":"TestA"}`,
+		`{"Action":"output","Test":"TestA","Output":"=== RUN   TestA\n"}`,
+		`{"Action":"run","Test":"TestA/sub"}`,
+		`{"Action":"output","Test":"TestA/sub","Output":"sub output\n"}`,
+		`{"Action":"fail","Test":"TestA/sub","Elapsed":0.1}`,
+		`{"Action":"pass","Test":"TestA/sub/nested","Elapsed":0.1}`,
+		`{"Action":"fail","Test":"TestA","Elapsed":0.5}`,
+		`{"Action":"run","Test":"TestB"}`,
+		`{"Action":"pass","Test":"TestB","Elapsed":1.25}`,
+		`{"Action":"run","Test":"TestC"}`,
+		`{"Action":"skip","Test":"TestC"}`,
+		`not json`,
+		`{"Action":"fail","Elapsed":2}`,
+	}, "\n")
+
+	results := parseTestEvents([]byte(data), "visual")
+	if len(results) != 3 {
+		t.Fatalf("got %d results, want 3: %+v", len(results), results)
+	}
+
+	a, b, c := results[0], results[1], results[2]
+	if a.Name != "TestA" || a.Passed || a.Error != "test failed" || !strings.Contains(a.Output, "sub output") {
+		t.Errorf("TestA = %+v, want failed with subtest output", a)
+	}
+	if len(a.Subtests) != 1 || a.Subtests[0].Name != "sub" || a.Subtests[0].Error != "test failed" {
+		t.Errorf("TestA subtests = %+v, want only the failed direct subtest", a.Subtests)
+	}
+	if b.Name != "TestB" || !b.Passed || b.Duration.Seconds() != 1.25 {
+		t.Errorf("TestB = %+v, want passed in 1.25s", b)
+	}
+	if c.Name != "TestC" || c.Error != "skipped" {
+		t.Errorf("TestC = %+v, want skipped", c)
+	}
+}
+
+func TestListTestFuncs(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "x_test.go")
+	src := `package x
+
+func TestOne(t *testing.T) {}
+func helper(t *testing.T) {}
+func TestTwo(t *testing.T) {
+	t.Run("sub", func(t *testing.T) {})
+}
+func BenchmarkThree(b *testing.B) {}
+`
+	if err := os.WriteFile(file, []byte(src), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	names, err := listTestFuncs(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(names, ",") != "TestOne,TestTwo" {
+		t.Errorf("listTestFuncs() = %v, want [TestOne TestTwo]", names)
+	}
+	if got := testNamesPattern(names); got != "^(TestOne|TestTwo)$" {
+		t.Errorf("testNamesPattern() = %q", got)
+	}
+}
+
+func TestListFuzzFuncs(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "x_test.go")
+	src := `package x
+
+func FuzzParse(f *testing.F) {}
+func TestParse(t *testing.T) {}
+func FuzzHelper(t *testing.T) {}
+func FuzzValidate(f *testing.F) {
+	f.Fuzz(func(t *testing.T, s string) {})
+}
+`
+	if err := os.WriteFile(file, []byte(src), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	names, err := listFuzzFuncs(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(names, ",") != "FuzzParse,FuzzValidate" {
+		t.Errorf("listFuzzFuncs() = %v, want [FuzzParse FuzzValidate]", names)
+	}
+}
+
+func TestSelectTests(t *testing.T) {
+	names := []string{"TestVHS_HealthPanel", "TestVHS_Demo", "TestCLIHelp"}
+
+	r := NewRunner(RunnerConfig{})
+	if got := r.selectTests(names); len(got) != 3 {
+		t.Errorf("selectTests() without --run = %v, want all", got)
+	}
+
+	r = NewRunner(RunnerConfig{Run: regexp.MustCompile(`^TestVHS_`)})
+	if got := r.selectTests(names); strings.Join(got, ",") != "TestVHS_HealthPanel,TestVHS_Demo" {
+		t.Errorf("selectTests() = %v, want the VHS tests", got)
+	}
+}
+
+func TestToJUnit(t *testing.T) {
+	results := &TestResults{
+		Tests: []TestResult{
+			{Name: "TestA", Suite: "visual", Passed: true},
+			{Name: "TestB", Suite: "visual", Error: "test failed", Output: "golden mismatch"},
+			{Name: "TestC", Suite: "visual", Passed: true, Attempts: 2, Flaky: true},
+			{Name: "TestD", Suite: "docker", Error: "skipped"},
+			{Name: "TestDistroMatrix", Suite: "docker", Error: "test failed", Subtests: []TestResult{
+				{Name: "ubuntu", Suite: "docker", Passed: true},
+				{Name: "alpine", Suite: "docker", Error: "test failed"},
+			}},
+		},
+	}
+
+	report := results.ToJUnit()
+	if report.Tests != 7 || report.Failures != 3 || report.Skipped != 1 {
+		t.Errorf("totals = %d/%d/%d, want 7 tests, 3 failures, 1 skipped", report.Tests, report.Failures, report.Skipped)
+	}
+	if len(report.Suites) != 2 || report.Suites[0].Name != "visual" || report.Suites[0].Tests != 3 {
+		t.Fatalf("suites = %+v, want visual (3) then docker", report.Suites)
+	}
+
+	data, err := xml.Marshal(report)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := string(data)
+	for _, want := range []string{`<failure message="test failed">golden mismatch</failure>`, `<skipped></skipped>`, `passed on attempt 2`, `name="TestDistroMatrix/alpine"`} {
+		if !strings.Contains(out, want) {
+			t.Errorf("JUnit XML missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestParseShard(t *testing.T) {
+	tests := []struct {
+		spec       string
+		index, cnt int
+		wantErr    bool
+	}{
+		{"1/3", 1, 3, false},
+		{"3/3", 3, 3, false},
+		{"1/1", 1, 1, false},
+		{"0/3", 0, 0, true},
+		{"4/3", 0, 0, true},
+		{"1/0", 0, 0, true},
+		{"2", 0, 0, true},
+		{"a/b", 0, 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			index, count, err := ParseShard(tt.spec)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseShard(%q) error = %v, wantErr %v", tt.spec, err, tt.wantErr)
+			}
+			if index != tt.index || count != tt.cnt {
+				t.Errorf("ParseShard(%q) = %d/%d, want %d/%d", tt.spec, index, count, tt.index, tt.cnt)
+			}
+		})
+	}
+}
+
+func TestSelectFileTests_Shards(t *testing.T) {
+	dir := t.TempDir()
+	files := []string{filepath.Join(dir, "a_test.go"), filepath.Join(dir, "b_test.go")}
+	srcs := []string{
+		"func TestA1(t *testing.T) {}\nfunc TestA2(t *testing.T) {}\nfunc TestA3(t *testing.T) {}\n",
+		"func TestB1(t *testing.T) {}\nfunc TestB2(t *testing.T) {}\n",
+	}
+	for i, file := range files {
+		if err := os.WriteFile(file, []byte(srcs[i]), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	seen := make(map[string]int)
+	for i := 1; i <= 3; i++ {
+		r := NewRunner(RunnerConfig{ShardIndex: i, ShardCount: 3})
+		selected, err := r.selectFileTests(files)
+		if err != nil {
+			t.Fatal(err)
+		}
+		count := 0
+		for _, names := range selected {
+			for _, name := range names {
+				seen[name]++
+				count++
+			}
+		}
+		if count < 1 || count > 2 {
+			t.Errorf("shard %d/3 has %d tests, want 1 or 2", i, count)
+		}
+
+		again, _ := r.selectFileTests(files)
+		if fmt.Sprint(again) != fmt.Sprint(selected) {
+			t.Errorf("shard %d/3 is not deterministic: %v then %v", i, selected, again)
+		}
+	}
+
+	if len(seen) != 5 {
+		t.Errorf("shards cover %d tests, want 5: %v", len(seen), seen)
+	}
+	for name, n := range seen {
+		if n != 1 {
+			t.Errorf("%s ran in %d shards, want 1", name, n)
+		}
+	}
+}
+
+func TestMergeResults(t *testing.T) {
+	shard1 := &TestResults{Suite: "visual", Shard: "1/2", Duration: 3 * time.Second, Tests: []TestResult{
+		{Name: "TestB", Suite: "visual", Passed: true},
+		{Name: "docker", Suite: "docker", Error: "skipped"},
+	}}
+	shard2 := &TestResults{Suite: "visual", Shard: "2/2", Duration: 5 * time.Second, Tests: []TestResult{
+		{Name: "TestA", Suite: "visual", Error: "test failed"},
+		{Name: "docker", Suite: "docker", Error: "skipped"},
+	}}
+
+	// Round-trip through the JSON report, as merge reads shard files
+	var loaded []*TestResults
+	for _, shard := range []*TestResults{shard1, shard2} {
+		data, err := json.Marshal(shard.ToJSON())
+		if err != nil {
+			t.Fatal(err)
+		}
+		path := filepath.Join(t.TempDir(), "shard.json")
+		if err := os.WriteFile(path, data, 0644); err != nil {
+			t.Fatal(err)
+		}
+		results, err := LoadResults(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		loaded = append(loaded, results)
+	}
+
+	merged := MergeResults(loaded)
+	if merged.Total != 3 || merged.Passed != 1 || merged.Failed != 1 || merged.Skipped != 1 {
+		t.Errorf("merged counts = %d total, %d passed, %d failed, %d skipped; want 3/1/1/1",
+			merged.Total, merged.Passed, merged.Failed, merged.Skipped)
+	}
+	if merged.Duration != 5*time.Second {
+		t.Errorf("merged duration = %s, want the slowest shard (5s)", merged.Duration)
+	}
+	if merged.Suite != "visual" || merged.Shard != "" {
+		t.Errorf("merged suite/shard = %q/%q, want visual and no shard", merged.Suite, merged.Shard)
+	}
+	if merged.Tests[0].Suite != "docker" || merged.Tests[1].Name != "TestA" {
+		t.Errorf("merged tests not sorted: %+v", merged.Tests)
+	}
+}
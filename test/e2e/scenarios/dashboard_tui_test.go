@@ -126,6 +126,38 @@ func TestDashboard_NoConfig(t *testing.T) {
 	tm.WaitFinished(1 * time.Second)
 }
 
+// TestDashboard_SyncCancelAndRerun starts a real sync, cancels a second
+// one as it starts and runs a third. The orchestrator runs this suite under
+// -race, which checks the operation goroutine against the model.
+func TestDashboard_SyncCancelAndRerun(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+	state := dashboard.State{
+		Platform:     &platform.Platform{OS: "linux"},
+		Configs:      []config.ConfigItem{},
+		Config:       &config.Config{SchemaVersion: "1.0"},
+		HasConfig:    true,
+		DotfilesPath: t.TempDir(),
+	}
+
+	model := dashboard.New(state)
+	tm := helpers.NewTUITestModel(t, &model, teatest.WithInitialTermSize(160, 40))
+	tm.WaitForText("Output", 2*time.Second)
+
+	tm.SendKeys('s')
+	tm.WaitForText("State updated", 3*time.Second)
+
+	// Cancel may land before or after this sync finishes; either way the
+	// dashboard must be ready for the next one
+	tm.SendKeys('s', 'x')
+	tm.SendKeys('s')
+	tm.WaitForText("State updated", 3*time.Second)
+
+	tm.SendKeys('q')
+	tm.WaitFinished(2 * time.Second)
+}
+
 // TestKeySequenceBuilder demonstrates the fluent API for building key sequences
 func TestKeySequenceBuilder(t *testing.T) {
 	state := dashboard.State{
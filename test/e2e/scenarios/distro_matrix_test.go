@@ -0,0 +1,84 @@
+//go:build e2e
+
+package scenarios
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nvandessel/go4dot/test/e2e/helpers"
+)
+
+// TestDistroMatrix runs detect, install and doctor on every distro of the
+// test matrix (test/e2e/matrix.yaml, or $G4D_E2E_MATRIX). Each distro is a
+// subtest, so results are reported per distro.
+func TestDistroMatrix(t *testing.T) {
+	matrix := helpers.LoadMatrix(t)
+
+	binaryPath := helpers.BuildTestBinary(t)
+	projectRoot := helpers.GetProjectRoot(t)
+	fixturesDir := filepath.Join(projectRoot, "test", "e2e", "fixtures", "dotfiles")
+
+	for _, distro := range matrix.Distros {
+		distro := distro
+		t.Run(distro.Name, func(t *testing.T) {
+			t.Parallel()
+
+			container := helpers.NewDockerTestContainer(t, helpers.DockerConfig{
+				ImageName:      distro.Image,
+				PackageManager: distro.PackageManager,
+				BinaryPath:     binaryPath,
+				FixturesDir:    fixturesDir,
+			})
+
+			setupCommands := []string{
+				"git config --global user.email 'test@example.com'",
+				"git config --global user.name 'Test User'",
+				"mkdir -p ~/dotfiles",
+				"cp -r ~/fixtures/. ~/dotfiles/",
+				"cd ~/dotfiles && git init && git add . && git commit -m 'Initial commit'",
+			}
+			for _, cmd := range setupCommands {
+				if output, err := container.Exec("bash", "-c", cmd); err != nil {
+					t.Fatalf("Failed to set up dotfiles: %v\nCommand: %s\nOutput: %s", err, cmd, output)
+				}
+			}
+
+			// Platform detection must find the distro's package manager
+			output, err := container.Exec("bash", "-c", "g4d detect")
+			if err != nil {
+				t.Fatalf("g4d detect failed: %v\nOutput: %s", err, output)
+			}
+			if !strings.Contains(output, "Package Manager: "+distro.PackageManager) {
+				t.Errorf("Expected package manager %s to be detected, got:\n%s", distro.PackageManager, output)
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+			defer cancel()
+
+			// Dependencies are installed by the image; this checks the
+			// stow and state handling on each distro
+			output, err = container.ExecContext(ctx, "bash", "-c", "cd ~/dotfiles && g4d install --auto --skip-deps --skip-external --skip-machine")
+			if err != nil {
+				t.Fatalf("g4d install failed: %v\nOutput: %s", err, output)
+			}
+			t.Logf("Install output:\n%s", output)
+
+			lsOutput, err := container.Exec("bash", "-c", "ls -la ~/.vimrc")
+			if err != nil || !strings.Contains(lsOutput, "->") {
+				t.Errorf("Expected .vimrc to be a symlink after install: %v\n%s", err, lsOutput)
+			}
+
+			// Doctor may exit non-zero for warnings; the report must render
+			output, _ = container.ExecContext(ctx, "bash", "-c", "cd ~/dotfiles && g4d doctor --non-interactive")
+			for _, section := range []string{"Health Report", "Platform:", "Summary"} {
+				if !strings.Contains(output, section) {
+					t.Errorf("Doctor output missing expected section: %s\n%s", section, output)
+				}
+			}
+		})
+	}
+}
@@ -94,6 +94,16 @@ func TestVHS_OnboardingFlow(t *testing.T) {
 	})
 }
 
+// TestVHS_Demo validates the demo dashboard, which renders fixed sample data
+func TestVHS_Demo(t *testing.T) {
+	runVHSTest(t, vhsTestCase{
+		name:       "demo dashboard",
+		tapePath:   "test/e2e/tapes/demo_dashboard.tape",
+		outputPath: "test/e2e/outputs/demo_dashboard.txt",
+		goldenPath: "test/e2e/golden/demo_dashboard.txt",
+	})
+}
+
 // runVHSTest executes a single VHS test case
 func runVHSTest(t *testing.T, tc vhsTestCase) {
 	t.Helper()